@@ -1,6 +1,10 @@
 package config
 
 import (
+	"fmt"
+	"strings"
+	"time"
+
 	"github.com/caarlos0/env/v10"
 	"github.com/joho/godotenv"
 )
@@ -21,18 +25,320 @@ func LoadEnv(envfile ...string) error {
 	if err := env.Parse(&config.App); err != nil {
 		return err
 	}
+	if config.App.TrustedProxyHops < 0 {
+		return fmt.Errorf("invalid TRUSTED_PROXY_HOPS: %d (must not be negative)", config.App.TrustedProxyHops)
+	}
 
 	if err := env.Parse(&config.Database); err != nil {
 		return err
 	}
+	switch config.Database.MigrationMode {
+	case "up", "check", "off":
+	default:
+		return fmt.Errorf("invalid DB_MIGRATION_MODE: %s (must be up, check or off)", config.Database.MigrationMode)
+	}
 
 	if err := env.Parse(&config.OAuth); err != nil {
 		return err
 	}
 
+	if err := env.Parse(&config.SAML); err != nil {
+		return err
+	}
+
+	if err := env.Parse(&config.SCIM); err != nil {
+		return err
+	}
+	if config.SCIM.Token != "" && len(config.SCIM.Token) < 32 {
+		return fmt.Errorf("invalid SCIM_TOKEN: must be at least 32 characters")
+	}
+
 	if err := env.Parse(&config.Session); err != nil {
 		return err
 	}
+	if config.Session.Mode != "cookie" && config.Session.Mode != "token" {
+		return fmt.Errorf("invalid AUTH_MODE: %s (must be cookie or token)", config.Session.Mode)
+	}
+	switch config.Session.Store {
+	case "postgres", "cookie":
+	default:
+		return fmt.Errorf("invalid SESSION_STORE: %s (must be postgres or cookie)", config.Session.Store)
+	}
+	if config.Session.AccessTokenTTL <= 0 || config.Session.RefreshTokenTTL <= config.Session.AccessTokenTTL {
+		return fmt.Errorf("invalid ACCESS_TOKEN_TTL/REFRESH_TOKEN_TTL: %s/%s (refresh must be longer than access)",
+			config.Session.AccessTokenTTL, config.Session.RefreshTokenTTL)
+	}
+
+	if err := env.Parse(&config.Encryption); err != nil {
+		return err
+	}
+	if len(config.Encryption.Keys) == 0 && config.App.Env != EnvDev {
+		return fmt.Errorf("ENCRYPTION_KEYS is required when APP_ENV is %s", config.App.Env)
+	}
+
+	if err := env.Parse(&config.Captcha); err != nil {
+		return err
+	}
+	if config.Captcha.Provider != "" {
+		if config.Captcha.Provider != "turnstile" && config.Captcha.Provider != "hcaptcha" {
+			return fmt.Errorf("invalid CAPTCHA_PROVIDER: %s (must be turnstile or hcaptcha)", config.Captcha.Provider)
+		}
+		if config.Captcha.SiteKey == "" || config.Captcha.Secret == "" {
+			return fmt.Errorf("CAPTCHA_SITE_KEY and CAPTCHA_SECRET are required when CAPTCHA_PROVIDER is set")
+		}
+		if config.Captcha.Threshold < 0 || config.Captcha.Window <= 0 {
+			return fmt.Errorf("invalid CAPTCHA_THRESHOLD/CAPTCHA_WINDOW: %d/%s", config.Captcha.Threshold, config.Captcha.Window)
+		}
+	}
+
+	if err := env.Parse(&config.Signup); err != nil {
+		return err
+	}
+	if config.Signup.InvitationTTL <= 0 {
+		return fmt.Errorf("invalid SIGNUP_INVITATION_TTL: %s (must be positive)", config.Signup.InvitationTTL)
+	}
+
+	if err := env.Parse(&config.Demo); err != nil {
+		return err
+	}
+	if config.Demo.GuestTTL <= 0 || config.Demo.PurgeInterval <= 0 {
+		return fmt.Errorf("invalid DEMO_GUEST_TTL/DEMO_PURGE_INTERVAL: %s/%s (must be positive)",
+			config.Demo.GuestTTL, config.Demo.PurgeInterval)
+	}
+
+	if err := env.Parse(&config.Task); err != nil {
+		return err
+	}
+
+	if err := env.Parse(&config.Mail); err != nil {
+		return err
+	}
+
+	if err := env.Parse(&config.Push); err != nil {
+		return err
+	}
+	if config.Push.APNsTeamID != "" || config.Push.APNsKeyID != "" || config.Push.APNsPrivateKey != "" {
+		if config.Push.APNsTeamID == "" || config.Push.APNsKeyID == "" || config.Push.APNsPrivateKey == "" || config.Push.APNsTopic == "" {
+			return fmt.Errorf("PUSH_APNS_TEAM_ID, PUSH_APNS_KEY_ID, PUSH_APNS_PRIVATE_KEY and PUSH_APNS_TOPIC are required to enable APNs")
+		}
+	}
+	if config.Push.Timeout <= 0 {
+		return fmt.Errorf("invalid PUSH_TIMEOUT: %s (must be positive)", config.Push.Timeout)
+	}
+
+	if err := env.Parse(&config.Digest); err != nil {
+		return err
+	}
+	if config.Digest.CheckInterval <= 0 {
+		return fmt.Errorf("invalid DIGEST_CHECK_INTERVAL: %s (must be positive)", config.Digest.CheckInterval)
+	}
+	if config.Digest.SendHour < 0 || config.Digest.SendHour > 23 {
+		return fmt.Errorf("invalid DIGEST_SEND_HOUR: %d (must be between 0 and 23)", config.Digest.SendHour)
+	}
+
+	if err := env.Parse(&config.Reminder); err != nil {
+		return err
+	}
+	if config.Reminder.CheckInterval <= 0 {
+		return fmt.Errorf("invalid REMINDER_CHECK_INTERVAL: %s (must be positive)", config.Reminder.CheckInterval)
+	}
+	if config.Reminder.SendHour < 0 || config.Reminder.SendHour > 23 {
+		return fmt.Errorf("invalid REMINDER_SEND_HOUR: %d (must be between 0 and 23)", config.Reminder.SendHour)
+	}
+
+	if err := env.Parse(&config.Trash); err != nil {
+		return err
+	}
+	if config.Trash.Retention <= 0 || config.Trash.PurgeInterval <= 0 {
+		return fmt.Errorf("invalid TRASH_RETENTION/TRASH_PURGE_INTERVAL: %s/%s (must be positive)",
+			config.Trash.Retention, config.Trash.PurgeInterval)
+	}
+
+	if err := env.Parse(&config.GithubAPI); err != nil {
+		return err
+	}
+	if config.GithubAPI.BudgetFloor < 0 {
+		return fmt.Errorf("invalid GITHUB_GRAPHQL_BUDGET_FLOOR: %d (must not be negative)", config.GithubAPI.BudgetFloor)
+	}
+	if config.GithubAPI.MaxRetries < 0 {
+		return fmt.Errorf("invalid GITHUB_API_MAX_RETRIES: %d (must not be negative)", config.GithubAPI.MaxRetries)
+	}
+	if config.GithubAPI.RateLimitMaxWait < 0 {
+		return fmt.Errorf("invalid GITHUB_API_RATE_LIMIT_MAX_WAIT: %s (must not be negative)", config.GithubAPI.RateLimitMaxWait)
+	}
+
+	if err := env.Parse(&config.GithubBranch); err != nil {
+		return err
+	}
+	// ブランチ名がタスクごとに一意になるよう、番号かIDを含める
+	if !strings.Contains(config.GithubBranch.Template, "{number}") && !strings.Contains(config.GithubBranch.Template, "{id}") {
+		return fmt.Errorf("invalid GITHUB_BRANCH_TEMPLATE: %s (must contain {number} or {id})", config.GithubBranch.Template)
+	}
+
+	if err := env.Parse(&config.GithubImport); err != nil {
+		return err
+	}
+	if config.GithubImport.Interval <= 0 {
+		return fmt.Errorf("invalid GITHUB_IMPORT_INTERVAL: %s (must be positive)", config.GithubImport.Interval)
+	}
+
+	if err := env.Parse(&config.GithubSync); err != nil {
+		return err
+	}
+	if config.GithubSync.Delay < 0 {
+		return fmt.Errorf("invalid GITHUB_SYNC_DELAY: %s (must not be negative)", config.GithubSync.Delay)
+	}
+	if config.GithubSync.QueuePollInterval <= 0 {
+		return fmt.Errorf("invalid GITHUB_SYNC_QUEUE_POLL_INTERVAL: %s (must be positive)", config.GithubSync.QueuePollInterval)
+	}
+	if config.GithubSync.QueueRetryMax <= 0 {
+		return fmt.Errorf("invalid GITHUB_SYNC_QUEUE_RETRY_MAX: %s (must be positive)", config.GithubSync.QueueRetryMax)
+	}
+	if config.GithubSync.QuotaPerHour < 0 {
+		return fmt.Errorf("invalid GITHUB_SYNC_QUOTA_PER_HOUR: %d (must not be negative)", config.GithubSync.QuotaPerHour)
+	}
+	if config.GithubSync.PullInterval < 0 {
+		return fmt.Errorf("invalid GITHUB_SYNC_PULL_INTERVAL: %s (must not be negative)", config.GithubSync.PullInterval)
+	}
+
+	if err := env.Parse(&config.Webhook); err != nil {
+		return err
+	}
+	if config.Webhook.MaxAttempts < 1 {
+		return fmt.Errorf("invalid WEBHOOK_MAX_ATTEMPTS: %d (must be positive)", config.Webhook.MaxAttempts)
+	}
+	if config.Webhook.PollInterval <= 0 {
+		return fmt.Errorf("invalid WEBHOOK_POLL_INTERVAL: %s (must be positive)", config.Webhook.PollInterval)
+	}
+
+	if err := env.Parse(&config.OutboundWebhook); err != nil {
+		return err
+	}
+	if config.OutboundWebhook.MaxAttempts < 1 {
+		return fmt.Errorf("invalid OUTBOUND_WEBHOOK_MAX_ATTEMPTS: %d (must be positive)", config.OutboundWebhook.MaxAttempts)
+	}
+	if config.OutboundWebhook.DisableAfter < 1 {
+		return fmt.Errorf("invalid OUTBOUND_WEBHOOK_DISABLE_AFTER: %d (must be positive)", config.OutboundWebhook.DisableAfter)
+	}
+	if config.OutboundWebhook.PollInterval <= 0 {
+		return fmt.Errorf("invalid OUTBOUND_WEBHOOK_POLL_INTERVAL: %s (must be positive)", config.OutboundWebhook.PollInterval)
+	}
+	if config.OutboundWebhook.Timeout <= 0 {
+		return fmt.Errorf("invalid OUTBOUND_WEBHOOK_TIMEOUT: %s (must be positive)", config.OutboundWebhook.Timeout)
+	}
+	if config.OutboundWebhook.Retention <= 0 {
+		return fmt.Errorf("invalid OUTBOUND_WEBHOOK_RETENTION: %s (must be positive)", config.OutboundWebhook.Retention)
+	}
+
+	if err := env.Parse(&config.Outbox); err != nil {
+		return err
+	}
+	if config.Outbox.MaxAttempts < 1 {
+		return fmt.Errorf("invalid OUTBOX_MAX_ATTEMPTS: %d (must be positive)", config.Outbox.MaxAttempts)
+	}
+	if config.Outbox.PollInterval <= 0 {
+		return fmt.Errorf("invalid OUTBOX_POLL_INTERVAL: %s (must be positive)", config.Outbox.PollInterval)
+	}
+	if config.Outbox.Retention <= 0 {
+		return fmt.Errorf("invalid OUTBOX_RETENTION: %s (must be positive)", config.Outbox.Retention)
+	}
+
+	if err := env.Parse(&config.Relay); err != nil {
+		return err
+	}
+	if config.Relay.Broker != "" {
+		if config.Relay.Broker != "nats" && config.Relay.Broker != "kafka" {
+			return fmt.Errorf("invalid RELAY_BROKER: %s (must be nats or kafka)", config.Relay.Broker)
+		}
+		if config.Relay.URL == "" {
+			return fmt.Errorf("RELAY_URL is required when RELAY_BROKER is set")
+		}
+		if config.Relay.Timeout <= 0 {
+			return fmt.Errorf("invalid RELAY_TIMEOUT: %s (must be positive)", config.Relay.Timeout)
+		}
+	}
+
+	if err := env.Parse(&config.Admin); err != nil {
+		return err
+	}
+
+	if err := env.Parse(&config.Status); err != nil {
+		return err
+	}
+	if config.Status.RateLimitPerMinute < 0 {
+		return fmt.Errorf("invalid STATUS_RATE_LIMIT_PER_MINUTE: %d (must not be negative)", config.Status.RateLimitPerMinute)
+	}
+	if config.Status.CacheTTL < 0 {
+		return fmt.Errorf("invalid STATUS_CACHE_TTL: %s (must not be negative)", config.Status.CacheTTL)
+	}
+
+	if err := env.Parse(&config.Storage); err != nil {
+		return err
+	}
+	switch config.Storage.Backend {
+	case "local":
+	case "s3", "gcs":
+		if config.Storage.Bucket == "" || config.Storage.AccessKeyID == "" || config.Storage.SecretAccessKey == "" {
+			return fmt.Errorf("STORAGE_BUCKET, STORAGE_ACCESS_KEY_ID and STORAGE_SECRET_ACCESS_KEY are required when STORAGE_BACKEND is %s", config.Storage.Backend)
+		}
+	default:
+		return fmt.Errorf("invalid STORAGE_BACKEND: %s (must be local, s3 or gcs)", config.Storage.Backend)
+	}
+	if config.Storage.SignedURLTTL < 0 || config.Storage.SignedURLTTL > 7*24*time.Hour {
+		return fmt.Errorf("invalid STORAGE_SIGNED_URL_TTL: %s (must be between 0 and 168h)", config.Storage.SignedURLTTL)
+	}
+	if config.Storage.ExportRetention < 0 {
+		return fmt.Errorf("invalid STORAGE_EXPORT_RETENTION: %s (must not be negative)", config.Storage.ExportRetention)
+	}
+
+	if err := env.Parse(&config.Backup); err != nil {
+		return err
+	}
+	if config.Backup.Interval < 0 {
+		return fmt.Errorf("invalid BACKUP_INTERVAL: %s (must not be negative)", config.Backup.Interval)
+	}
+	if config.Backup.Retention < 1 {
+		return fmt.Errorf("invalid BACKUP_RETENTION: %d (must be positive)", config.Backup.Retention)
+	}
+
+	if err := env.Parse(&config.Scheduler); err != nil {
+		return err
+	}
+	if _, err := time.LoadLocation(config.Scheduler.Timezone); err != nil {
+		return fmt.Errorf("invalid SCHEDULER_TIMEZONE: %s", config.Scheduler.Timezone)
+	}
+	// cron式が未設定のジョブは従来の間隔で実行する（式の検証はジョブの登録時に行う）
+	if config.Scheduler.Digest == "" {
+		config.Scheduler.Digest = "@every " + config.Digest.CheckInterval.String()
+	}
+	if config.Scheduler.Reminder == "" {
+		config.Scheduler.Reminder = "@every " + config.Reminder.CheckInterval.String()
+	}
+	if config.Scheduler.TrashPurge == "" {
+		config.Scheduler.TrashPurge = "@every " + config.Trash.PurgeInterval.String()
+	}
+	if config.Scheduler.GuestPurge == "" {
+		config.Scheduler.GuestPurge = "@every " + config.Demo.PurgeInterval.String()
+	}
+	if config.Scheduler.GithubImport == "" {
+		config.Scheduler.GithubImport = "@every " + config.GithubImport.Interval.String()
+	}
+	if config.Scheduler.GithubProjectSync == "" && config.GithubSync.PullInterval > 0 {
+		config.Scheduler.GithubProjectSync = "@every " + config.GithubSync.PullInterval.String()
+	}
+	if config.Scheduler.Backup == "" && config.Backup.Interval > 0 {
+		config.Scheduler.Backup = "@every " + config.Backup.Interval.String()
+	}
+
+	if err := env.Parse(&config.Override); err != nil {
+		return err
+	}
+
+	profile, err := resolveProfile(&config)
+	if err != nil {
+		return err
+	}
+	config.Profile = *profile
 
 	Config = &config
 
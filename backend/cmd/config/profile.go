@@ -0,0 +1,129 @@
+package config
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+)
+
+// 環境プロファイル名
+const (
+	EnvDev     = "dev"
+	EnvStaging = "staging"
+	EnvProd    = "prod"
+)
+
+// ログ出力形式
+const (
+	LogFormatText = "text"
+	LogFormatJSON = "json"
+)
+
+// Profile は環境プロファイルごとに切り替わる実効設定
+type Profile struct {
+	Name               string
+	CookieSecure       bool
+	LogFormat          string
+	LogLevel           slog.Level
+	CORSAllowedOrigins []string
+	// RateLimitPerMinute はIPごとの1分あたりのリクエスト上限（0の場合は無効）
+	RateLimitPerMinute int
+}
+
+// IsProduction は本番プロファイルかどうかを返す
+func (p Profile) IsProduction() bool {
+	return p.Name == EnvProd
+}
+
+// defaultProfile はプロファイル名に応じたデフォルト値を返す
+func defaultProfile(name, frontendURL string) (*Profile, error) {
+	switch name {
+	case EnvDev:
+		return &Profile{
+			Name: EnvDev,
+			// 開発環境ではフロントエンドがHTTPSの場合のみSecure Cookieを使用する
+			CookieSecure:       strings.HasPrefix(frontendURL, "https://"),
+			LogFormat:          LogFormatText,
+			LogLevel:           slog.LevelDebug,
+			CORSAllowedOrigins: []string{"http://localhost:5173", "http://127.0.0.1:5173"},
+			RateLimitPerMinute: 0,
+		}, nil
+	case EnvStaging:
+		return &Profile{
+			Name:               EnvStaging,
+			CookieSecure:       true,
+			LogFormat:          LogFormatJSON,
+			LogLevel:           slog.LevelDebug,
+			RateLimitPerMinute: 600,
+		}, nil
+	case EnvProd:
+		return &Profile{
+			Name:               EnvProd,
+			CookieSecure:       true,
+			LogFormat:          LogFormatJSON,
+			LogLevel:           slog.LevelInfo,
+			RateLimitPerMinute: 300,
+		}, nil
+	default:
+		return nil, fmt.Errorf("invalid APP_ENV: %s (must be one of dev, staging, prod)", name)
+	}
+}
+
+// resolveProfile はAPP_ENVのデフォルト値に上書き設定を適用した実効プロファイルを作成する
+func resolveProfile(c *config) (*Profile, error) {
+	profile, err := defaultProfile(c.App.Env, c.App.FrontendURL)
+	if err != nil {
+		return nil, err
+	}
+
+	// フロントエンドURLは常にCORSの許可対象とする
+	if c.App.FrontendURL != "" {
+		profile.CORSAllowedOrigins = appendUnique(profile.CORSAllowedOrigins, c.App.FrontendURL)
+	}
+
+	if c.Override.CookieSecure != nil {
+		profile.CookieSecure = *c.Override.CookieSecure
+	}
+
+	if c.Override.LogFormat != "" {
+		switch c.Override.LogFormat {
+		case LogFormatText, LogFormatJSON:
+			profile.LogFormat = c.Override.LogFormat
+		default:
+			return nil, fmt.Errorf("invalid LOG_FORMAT: %s (must be text or json)", c.Override.LogFormat)
+		}
+	}
+
+	if c.Override.LogLevel != "" {
+		var level slog.Level
+		if err := level.UnmarshalText([]byte(c.Override.LogLevel)); err != nil {
+			return nil, fmt.Errorf("invalid LOG_LEVEL: %w", err)
+		}
+		profile.LogLevel = level
+	}
+
+	for _, origin := range c.Override.CORSAllowedOrigins {
+		if origin = strings.TrimSpace(origin); origin != "" {
+			profile.CORSAllowedOrigins = appendUnique(profile.CORSAllowedOrigins, origin)
+		}
+	}
+
+	if c.Override.RateLimitPerMinute != nil {
+		if *c.Override.RateLimitPerMinute < 0 {
+			return nil, fmt.Errorf("invalid RATE_LIMIT_PER_MINUTE: %d", *c.Override.RateLimitPerMinute)
+		}
+		profile.RateLimitPerMinute = *c.Override.RateLimitPerMinute
+	}
+
+	return profile, nil
+}
+
+// appendUnique は重複しない場合のみ値を追加する
+func appendUnique(values []string, v string) []string {
+	for _, existing := range values {
+		if existing == v {
+			return values
+		}
+	}
+	return append(values, v)
+}
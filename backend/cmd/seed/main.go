@@ -0,0 +1,116 @@
+// seed はローカル開発・負荷試験用のデータを生成するコマンド
+//
+// 使い方:
+//
+//	go run ./cmd/seed -users 10 -projects 3 -tasks 200 -days 90
+//
+// 接続先のデータベースはサーバーと同じ環境変数（.env）で指定する。本番プロファイル（APP_ENV=prod）では実行できない
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"log/slog"
+	"os"
+
+	"github.com/sikigasa/github-task-controller/backend/cmd/config"
+	"github.com/sikigasa/github-task-controller/backend/db/migrations"
+	"github.com/sikigasa/github-task-controller/backend/internal/application/usecase"
+	"github.com/sikigasa/github-task-controller/backend/internal/domain/model"
+	"github.com/sikigasa/github-task-controller/backend/internal/infrastructure/persistence"
+)
+
+func main() {
+	os.Exit(run())
+}
+
+func run() int {
+	var opts model.SeedOptions
+	flag.IntVar(&opts.Users, "users", 0, "生成するユーザー数（デフォルト: 3）")
+	flag.IntVar(&opts.ProjectsPerUser, "projects", 0, "ユーザーごとのプロジェクト数（デフォルト: 2）")
+	flag.IntVar(&opts.TasksPerProject, "tasks", 0, "プロジェクトごとのタスク数（デフォルト: 30）")
+	flag.IntVar(&opts.HistoryDays, "days", 0, "作成日・ステータス履歴をさかのぼる日数（デフォルト: 60）")
+	flag.Uint64Var(&opts.RandomSeed, "seed", 0, "乱数のシード（0の場合は毎回異なる内容を生成する）")
+	flag.StringVar(&opts.UserID, "user", "", "指定すると新しいユーザーを作らずにこのユーザーのプロジェクトとして生成する")
+	verbose := flag.Bool("v", false, "リポジトリのログを出力する")
+	flag.Parse()
+
+	ctx := context.Background()
+
+	level := slog.LevelWarn
+	if *verbose {
+		level = slog.LevelInfo
+	}
+	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: level}))
+
+	if err := config.LoadEnv(); err != nil {
+		logger.Error("failed to load config", "error", err)
+		return 1
+	}
+	if config.Config.Profile.IsProduction() {
+		logger.Error("seed cannot run with the production profile (APP_ENV=prod)")
+		return 1
+	}
+
+	dbConfig, err := persistence.ResolveDBConfig(config.Config.Database.URL, persistence.DBConfig{
+		Host:     config.Config.Database.Host,
+		Port:     config.Config.Database.Port,
+		User:     config.Config.Database.User,
+		Password: config.Config.Database.Password,
+		DBName:   config.Config.Database.Name,
+		SSLMode:  config.Config.Database.SSLMode,
+	})
+	if err != nil {
+		logger.Error("failed to parse DATABASE_URL", "error", err)
+		return 1
+	}
+
+	// すべてのユーザーのデータを生成するため、行レベルセキュリティの制限を外して接続する
+	db, err := persistence.NewDB(ctx, dbConfig.WithRowLevelSecurityBypass(), logger)
+	if err != nil {
+		logger.Error("failed to connect to database", "error", err)
+		return 1
+	}
+	defer db.Close()
+
+	// 未適用のマイグレーションを適用してからデータを生成する
+	migrator, err := persistence.NewMigrator(ctx, db, migrations.FS(config.Config.Database.MigrationsDir), logger)
+	if err != nil {
+		logger.Error("failed to load migrations", "error", err)
+		return 1
+	}
+	err = migrator.Up(ctx)
+	if closeErr := migrator.Close(); closeErr != nil {
+		logger.Warn("failed to close migrator", "error", closeErr)
+	}
+	if err != nil {
+		logger.Error("failed to migrate database schema", "error", err)
+		return 1
+	}
+
+	seedUsecase := usecase.NewSeedUsecase(
+		persistence.NewUserRepository(db, logger),
+		persistence.NewProjectRepository(db, logger),
+		persistence.NewMilestoneRepository(db, logger),
+		persistence.NewTaskRepository(db, logger),
+		persistence.NewTaskStatusEventRepository(db, logger),
+		persistence.NewTaskPullRequestRepository(db, logger),
+		persistence.NewTaskCommitRepository(db, logger),
+		logger,
+	)
+
+	result, err := seedUsecase.Seed(ctx, opts)
+	if err != nil {
+		logger.Error("failed to generate seed data", "error", err)
+		return 1
+	}
+
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(result); err != nil {
+		logger.Error("failed to write result", "error", err)
+		return 1
+	}
+	return 0
+}
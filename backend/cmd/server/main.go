@@ -2,21 +2,33 @@ package main
 
 import (
 	"context"
-	"fmt"
+	"errors"
 	"log/slog"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
-	"strings"
+	"runtime/debug"
 	"syscall"
 	"time"
 
 	"github.com/sikigasa/github-task-controller/backend/cmd/config"
 	"github.com/sikigasa/github-task-controller/backend/internal/application/usecase"
+	"github.com/sikigasa/github-task-controller/backend/internal/domain/model"
 	"github.com/sikigasa/github-task-controller/backend/internal/infrastructure/auth"
+	"github.com/sikigasa/github-task-controller/backend/internal/infrastructure/broker"
+	"github.com/sikigasa/github-task-controller/backend/internal/infrastructure/captcha"
+	"github.com/sikigasa/github-task-controller/backend/internal/infrastructure/crypto"
 	"github.com/sikigasa/github-task-controller/backend/internal/infrastructure/github"
+	"github.com/sikigasa/github-task-controller/backend/internal/infrastructure/listener"
+	"github.com/sikigasa/github-task-controller/backend/internal/infrastructure/mail"
 	"github.com/sikigasa/github-task-controller/backend/internal/infrastructure/persistence"
+	"github.com/sikigasa/github-task-controller/backend/internal/infrastructure/push"
+	"github.com/sikigasa/github-task-controller/backend/internal/infrastructure/saml"
 	"github.com/sikigasa/github-task-controller/backend/internal/infrastructure/session"
+	"github.com/sikigasa/github-task-controller/backend/internal/infrastructure/storage"
+	"github.com/sikigasa/github-task-controller/backend/internal/infrastructure/token"
+	"github.com/sikigasa/github-task-controller/backend/internal/infrastructure/webhook"
 	"github.com/sikigasa/github-task-controller/backend/internal/interface/handler"
 	"github.com/sikigasa/github-task-controller/backend/internal/interface/middleware"
 	"github.com/sikigasa/github-task-controller/backend/internal/router"
@@ -26,18 +38,45 @@ func main() {
 	os.Exit(run())
 }
 
-func run() int {
-	// ロガーの初期化
-	logger := slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{
-		Level: slog.LevelInfo,
-	}))
-	slog.SetDefault(logger)
+// version はビルド時に -ldflags "-X main.version=..." で埋め込むバージョン
+var version = "dev"
 
+func run() int {
 	ctx := context.Background()
+	startedAt := time.Now()
 
 	// 環境変数の読み込み
 	if err := config.LoadEnv(); err != nil {
-		logger.Warn("failed to load .env file, using environment variables", "error", err)
+		slog.Error("failed to load config", "error", err)
+		return 1
+	}
+
+	// ロガーの初期化（出力形式とレベルは環境プロファイルに従う）
+	logger := newLogger(config.Config.Profile)
+	slog.SetDefault(logger)
+	logger.Info("config loaded", "app_env", config.Config.Profile.Name)
+
+	// データベース設定
+	// DATABASE_URLが設定されている場合はそれを使用（Railway等のクラウドサービス用）
+	if config.Config.Database.URL != "" {
+		logger.Info("using DATABASE_URL for database connection")
+	}
+	dbConfig, err := persistence.ResolveDBConfig(config.Config.Database.URL, persistence.DBConfig{
+		Host:     config.Config.Database.Host,
+		Port:     config.Config.Database.Port,
+		User:     config.Config.Database.User,
+		Password: config.Config.Database.Password,
+		DBName:   config.Config.Database.Name,
+		SSLMode:  config.Config.Database.SSLMode,
+	})
+	if err != nil {
+		logger.Error("failed to parse DATABASE_URL", "error", err)
+		return 1
+	}
+
+	// migrate サブコマンド: スキーマのマイグレーションだけを実行して終了する
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		return runMigrate(ctx, os.Args[2:], dbConfig, logger)
 	}
 
 	// 設定の検証
@@ -51,36 +90,28 @@ func run() int {
 		return 1
 	}
 
-	// データベース設定
-	// DATABASE_URLが設定されている場合はそれを使用（Railway等のクラウドサービス用）
-	var dbConfig persistence.DBConfig
-	if config.Config.Database.URL != "" {
-		logger.Info("using DATABASE_URL for database connection")
-		parsedConfig, err := persistence.ParseDatabaseURL(config.Config.Database.URL)
-		if err != nil {
-			logger.Error("failed to parse DATABASE_URL", "error", err)
-			return 1
-		}
-		dbConfig = *parsedConfig
-	} else {
-		dbConfig = persistence.DBConfig{
-			Host:     config.Config.Database.Host,
-			Port:     config.Config.Database.Port,
-			User:     config.Config.Database.User,
-			Password: config.Config.Database.Password,
-			DBName:   config.Config.Database.Name,
-			SSLMode:  config.Config.Database.SSLMode,
-		}
+	// 外部サービスのトークンを保存時に暗号化するキー
+	encryptionKeys, err := crypto.ParseKeys(config.Config.Encryption.Keys)
+	if err != nil {
+		logger.Error("invalid ENCRYPTION_KEYS", "error", err)
+		return 1
 	}
-
-	// セッションストアの初期化
-	sessionStore := session.NewCookieStore([]byte(config.Config.Session.Secret))
-	// HTTPS環境（本番）ではSecure=true, SameSite=Noneに設定
-	if strings.HasPrefix(config.Config.App.FrontendURL, "https://") {
-		sessionStore.Secure = true
+	if len(encryptionKeys) == 0 {
+		// 開発環境ではキーを設定しなくても起動できるようにする（staging・prodでは設定の読み込み時に拒否している）
+		logger.Warn("ENCRYPTION_KEYS is not set, deriving a development key from SESSION_SECRET")
+		encryptionKeys = []crypto.Key{crypto.DeriveKey("dev", config.Config.Session.Secret)}
+	}
+	secretCipher, err := crypto.NewCipher(encryptionKeys)
+	if err != nil {
+		logger.Error("invalid ENCRYPTION_KEYS", "error", err)
+		return 1
 	}
 
 	// データベース接続
+	// 行レベルセキュリティを使わない場合は、すべての接続でポリシーの制限を外す（設定のない接続はすべての行が拒否される）
+	if !config.Config.Database.RowLevelSecurity {
+		dbConfig = dbConfig.WithRowLevelSecurityBypass()
+	}
 	db, err := persistence.NewDB(ctx, dbConfig, logger)
 	if err != nil {
 		logger.Error("failed to connect to database", "error", err)
@@ -88,12 +119,44 @@ func run() int {
 	}
 	defer db.Close()
 
-	// スキーマ初期化
-	if err := persistence.InitSchema(ctx, db, logger); err != nil {
-		logger.Error("failed to initialize schema", "error", err)
+	// スキーマのマイグレーション（DB_MIGRATION_MODEに従って適用・検査する）
+	if err := migrateOnStartup(ctx, db, logger); err != nil {
+		logger.Error("failed to migrate database schema", "error", err)
+		return 1
+	}
+
+	// セッションストアの初期化（SESSION_STOREがpostgresの場合はサーバー側に保存して失効できるようにする）
+	// HTTPS環境（staging/prod）ではSecure=true, SameSite=Noneに設定
+	var sessionStore session.Store
+	var serverSessionStore *session.ServerStore
+	if config.Config.Session.Store == "postgres" {
+		serverSessionStore = session.NewServerStore(persistence.NewAuthSessionRepository(db, logger), logger)
+		serverSessionStore.Secure = config.Config.Profile.CookieSecure
+		sessionStore = serverSessionStore
+	} else {
+		cookieStore := session.NewCookieStore([]byte(config.Config.Session.Secret))
+		cookieStore.Secure = config.Config.Profile.CookieSecure
+		sessionStore = cookieStore
+	}
+
+	// 暗号化の導入前に平文で保存されたトークンと、ローテーション前のキーで暗号化されたトークンを暗号化し直す
+	if err := persistence.ReencryptSecrets(ctx, db, secretCipher, logger); err != nil {
+		logger.Error("failed to re-encrypt secrets", "error", err)
 		return 1
 	}
 
+	// 行レベルセキュリティ: 認証済みリクエストのクエリをユーザーが所有するデータに限定する
+	var tenancy middleware.TenantScoper
+	if config.Config.Database.RowLevelSecurity {
+		if err := persistence.CheckRowLevelSecurity(ctx, db); err != nil {
+			logger.Error("failed to enable row level security", "error", err)
+			return 1
+		}
+		tenancy = persistence.NewTenancy()
+		// バックグラウンドジョブと認証前のリクエストは制限を外して実行する（認証済みリクエストはユーザーをテナントとして設定する）
+		ctx = persistence.BypassTenancy(ctx)
+	}
+
 	// OAuth設定の初期化
 	oauthConfig := auth.NewOAuthConfig(
 		config.Config.OAuth.Google.ClientID,
@@ -104,41 +167,381 @@ func run() int {
 		config.Config.OAuth.Github.RedirectURL,
 		logger,
 	)
+	if config.Config.OAuth.Microsoft.ClientID != "" {
+		oauthConfig.EnableMicrosoft(auth.MicrosoftConfig{
+			ClientID:     config.Config.OAuth.Microsoft.ClientID,
+			ClientSecret: config.Config.OAuth.Microsoft.ClientSecret,
+			Tenant:       config.Config.OAuth.Microsoft.Tenant,
+			RedirectURL:  config.Config.OAuth.Microsoft.RedirectURL,
+		})
+	}
+	if config.Config.OAuth.Apple.ClientID != "" {
+		if err := oauthConfig.EnableApple(auth.AppleConfig{
+			ClientID:    config.Config.OAuth.Apple.ClientID,
+			TeamID:      config.Config.OAuth.Apple.TeamID,
+			KeyID:       config.Config.OAuth.Apple.KeyID,
+			PrivateKey:  config.Config.OAuth.Apple.PrivateKey,
+			RedirectURL: config.Config.OAuth.Apple.RedirectURL,
+		}); err != nil {
+			logger.Error("invalid apple sign in config", "error", err)
+			return 1
+		}
+	}
 
 	// 依存性の注入
 	todoRepo := persistence.NewTodoRepository(db, logger)
 	userRepo := persistence.NewUserRepository(db, logger)
-	googleAccountRepo := persistence.NewGoogleAccountRepository(db, logger)
-	githubAccountRepo := persistence.NewGithubAccountRepository(db, logger)
+	googleAccountRepo := persistence.NewGoogleAccountRepository(db, secretCipher, logger)
+	githubAccountRepo := persistence.NewGithubAccountRepository(db, secretCipher, logger)
+	appleAccountRepo := persistence.NewAppleAccountRepository(db, secretCipher, logger)
+	microsoftAccountRepo := persistence.NewMicrosoftAccountRepository(db, secretCipher, logger)
 	projectRepo := persistence.NewProjectRepository(db, logger)
 	taskRepo := persistence.NewTaskRepository(db, logger)
+	taskPullRequestRepo := persistence.NewTaskPullRequestRepository(db, logger)
+	taskCommitRepo := persistence.NewTaskCommitRepository(db, logger)
+	githubFieldMappingRepo := persistence.NewGithubFieldMappingRepository(db, logger)
+	taskStatusEventRepo := persistence.NewTaskStatusEventRepository(db, logger)
+	taskDependencyRepo := persistence.NewTaskDependencyRepository(db, logger)
+	taskRelationRepo := persistence.NewTaskRelationRepository(db, logger)
+	milestoneRepo := persistence.NewMilestoneRepository(db, logger)
+	savedViewRepo := persistence.NewSavedViewRepository(db, logger)
+	projectTokenRepo := persistence.NewProjectTokenRepository(db, logger)
+	webhookEndpointRepo := persistence.NewWebhookEndpointRepository(db, logger)
+	webhookEndpointDeliveryRepo := persistence.NewWebhookEndpointDeliveryRepository(db, logger)
+	githubSyncOperationRepo := persistence.NewGithubSyncOperationRepository(db, logger)
+	githubSyncUsageRepo := persistence.NewGithubSyncUsageRepository(db, logger)
+	githubTaskSyncRepo := persistence.NewGithubTaskSyncRepository(db, logger)
+	pushDeviceRepo := persistence.NewPushDeviceRepository(db, logger)
+	taskReminderRepo := persistence.NewTaskReminderRepository(db, logger)
+	goalRepo := persistence.NewGoalRepository(db, logger)
+	settingsRepo := persistence.NewSettingsRepository(db, logger)
+	reportExportRepo := persistence.NewReportExportRepository(db, logger)
+	userSessionRepo := persistence.NewUserSessionRepository(db, logger)
+	refreshTokenRepo := persistence.NewRefreshTokenRepository(db, logger)
+	samlAccountRepo := persistence.NewSAMLAccountRepository(db, logger)
+	samlRequestRepo := persistence.NewSAMLRequestRepository(db, logger)
+	groupRepo := persistence.NewGroupRepository(db, logger)
+	guestUserRepo := persistence.NewGuestUserRepository(db, logger)
+	invitationRepo := persistence.NewInvitationRepository(db, logger)
+	accountMergeRepo := persistence.NewAccountMergeRepository(db, secretCipher, logger)
+	webhookDeliveryRepo := persistence.NewWebhookDeliveryRepository(db, logger)
+	backupRepo := persistence.NewBackupRepository(db, logger)
+	outboxRepo := persistence.NewOutboxRepository(db, logger)
+	activityRepo := persistence.NewActivityRepository(db, logger)
+	// 変更とドメインイベント（アウトボックス）を同じトランザクションで書き込む
+	transactor := persistence.NewTransactor(db, logger)
+	// 複数のインスタンスで動かす場合に、スケジュールされたジョブとプロジェクトのGitHubとの同期を1つのインスタンスに限定する
+	locker := persistence.NewAdvisoryLocker(db, logger)
+
+	// ファイルの保存先（レポートのエクスポート・バックアップ）
+	fileStorage, err := storage.New(storage.Config{
+		Backend: config.Config.Storage.Backend,
+		Dir:     config.Config.Storage.Dir,
+		S3: storage.S3Config{
+			Bucket:          config.Config.Storage.Bucket,
+			Endpoint:        config.Config.Storage.Endpoint,
+			Region:          config.Config.Storage.Region,
+			AccessKeyID:     config.Config.Storage.AccessKeyID,
+			SecretAccessKey: config.Config.Storage.SecretAccessKey,
+			PathStyle:       config.Config.Storage.PathStyle,
+			SignedURLTTL:    config.Config.Storage.SignedURLTTL,
+		},
+	})
+	if err != nil {
+		logger.Error("failed to initialize storage", "error", err)
+		return 1
+	}
+
+	// メール送信
+	mailSender := mail.NewSender(mail.Config{
+		Host:     config.Config.Mail.SMTPHost,
+		Port:     config.Config.Mail.SMTPPort,
+		Username: config.Config.Mail.SMTPUsername,
+		Password: config.Config.Mail.SMTPPassword,
+		From:     config.Config.Mail.From,
+	}, logger)
+
+	// プッシュ通知（FCM・APNsのどちらも未設定の場合はログへ出力する）
+	pushSender, err := push.NewSender(push.Config{
+		FCMCredentials: config.Config.Push.FCMCredentials,
+		APNs: push.APNsConfig{
+			TeamID:     config.Config.Push.APNsTeamID,
+			KeyID:      config.Config.Push.APNsKeyID,
+			PrivateKey: config.Config.Push.APNsPrivateKey,
+			Topic:      config.Config.Push.APNsTopic,
+			Production: config.Config.Push.APNsProduction,
+		},
+		Timeout: config.Config.Push.Timeout,
+	}, logger)
+	if err != nil {
+		logger.Error("invalid push notification config", "error", err)
+		return 1
+	}
 
 	todoUsecase := usecase.NewTodoUsecase(todoRepo, logger)
-	authUsecase := usecase.NewAuthUsecase(userRepo, googleAccountRepo, githubAccountRepo, oauthConfig, logger)
-	projectUsecase := usecase.NewProjectUsecase(projectRepo, logger)
-	taskUsecase := usecase.NewTaskUsecase(taskRepo, logger)
+	// SIGNUP_ALLOWED_DOMAINS・SIGNUP_INVITE_ONLYで新規登録を制限する（既存ユーザーのログインには影響しない）
+	invitationUsecase := usecase.NewInvitationUsecase(invitationRepo, userRepo, mailSender, usecase.SignupPolicy{
+		AllowedDomains: config.Config.Signup.AllowedDomains,
+		InviteOnly:     config.Config.Signup.InviteOnly,
+		InvitationTTL:  config.Config.Signup.InvitationTTL,
+	}, config.Config.App.FrontendURL, logger)
+	// 同じメールアドレスの既存ユーザーへの別プロバイダーの紐付けは、メールの確認リンクかログイン中の確認を必要とする
+	accountMergeUsecase := usecase.NewAccountMergeUsecase(accountMergeRepo, googleAccountRepo, githubAccountRepo, appleAccountRepo, microsoftAccountRepo, mailSender, config.Config.App.PublicURL, transactor, logger)
+	authUsecase := usecase.NewAuthUsecase(userRepo, googleAccountRepo, githubAccountRepo, appleAccountRepo, microsoftAccountRepo, invitationUsecase, accountMergeUsecase, oauthConfig, transactor, logger)
+	sessionUsecase := usecase.NewSessionUsecase(userSessionRepo, logger)
+
+	// AUTH_MODE=tokenの場合はCookieのセッションの代わりにアクセストークンとリフレッシュトークンで認証する
+	var tokenUsecase *usecase.TokenUsecase
+	var accessTokens middleware.AccessTokenVerifier
+	if config.Config.Session.Mode == "token" {
+		signer := token.NewSigner([]byte(config.Config.Session.Secret))
+		tokenUsecase = usecase.NewTokenUsecase(refreshTokenRepo, userSessionRepo, signer, config.Config.Session.AccessTokenTTL, config.Config.Session.RefreshTokenTTL, logger)
+		accessTokens = tokenUsecase
+	}
+
+	// SAML_IDP_SSO_URLを設定した場合はSAML SSOでのログインを有効にする
+	var samlUsecase *usecase.SAMLUsecase
+	if config.Config.SAML.IdPSSOURL != "" {
+		entityID := config.Config.SAML.SPEntityID
+		if entityID == "" {
+			entityID = config.Config.App.PublicURL + "/auth/saml/metadata"
+		}
+		sp, err := saml.NewServiceProvider(saml.Config{
+			EntityID:       entityID,
+			ACSURL:         config.Config.App.PublicURL + "/auth/saml/acs",
+			IdPEntityID:    config.Config.SAML.IdPEntityID,
+			IdPSSOURL:      config.Config.SAML.IdPSSOURL,
+			IdPCertificate: config.Config.SAML.IdPCertificate,
+		})
+		if err != nil {
+			logger.Error("invalid saml config", "error", err)
+			return 1
+		}
+		samlUsecase = usecase.NewSAMLUsecase(userRepo, samlAccountRepo, samlRequestRepo, sp, config.Config.SAML.EmailAttribute, config.Config.SAML.NameAttribute, transactor, logger)
+	}
+
+	// バックグラウンドジョブの実行状況（/statusで公開する）
+	workerMonitor := usecase.NewWorkerMonitor()
+	// ドメインイベントのイベントバス（アウトボックスに書き込んだイベントを購読者に配信し、配信済みのものはOUTBOX_RETENTIONの後に削除する）
+	eventBus := usecase.NewOutboxUsecase(outboxRepo, config.Config.Outbox.MaxAttempts, config.Config.Outbox.PollInterval, config.Config.Outbox.Retention, workerMonitor, logger)
+
+	// アクティビティログ（操作したユーザーはリクエストの認証情報から取得し、ない場合はシステムの処理として記録する）
+	activityUsecase := usecase.NewActivityUsecase(activityRepo, projectRepo, middleware.GetUserIDFromContext, logger)
+
+	projectUsecase := usecase.NewProjectUsecase(projectRepo, taskRepo, taskDependencyRepo, settingsRepo, eventBus, activityUsecase, transactor, logger)
+	transitionPolicy, err := model.ParseTaskTransitionPolicy(config.Config.Task.StatusTransitions, config.Config.Task.ReopenRequiredFrom)
+	if err != nil {
+		logger.Error("invalid task transition config", "error", err)
+		return 1
+	}
+	taskUsecase := usecase.NewTaskUsecase(taskRepo, projectRepo, taskStatusEventRepo, taskDependencyRepo, taskRelationRepo, milestoneRepo, settingsRepo, eventBus, activityUsecase, transactor, transitionPolicy, logger)
+	milestoneUsecase := usecase.NewMilestoneUsecase(milestoneRepo, projectRepo, activityUsecase, transactor, logger)
+	savedViewUsecase := usecase.NewSavedViewUsecase(savedViewRepo, projectRepo, taskRepo, logger)
+	projectTokenUsecase := usecase.NewProjectTokenUsecase(projectTokenRepo, projectRepo, logger)
+	goalUsecase := usecase.NewGoalUsecase(goalRepo, projectRepo, taskRepo, logger)
+	settingsUsecase := usecase.NewSettingsUsecase(settingsRepo, projectRepo, logger)
+	pushUsecase := usecase.NewPushUsecase(pushDeviceRepo, settingsRepo, projectRepo, pushSender, logger)
+	// DEMO_MODE=trueの場合はサインインせずに試せるゲストユーザーを作成できるようにする
+	var demoUsecase *usecase.DemoUsecase
+	if config.Config.Demo.Enabled {
+		demoUsecase = usecase.NewDemoUsecase(userRepo, guestUserRepo, projectUsecase, taskUsecase, milestoneUsecase, config.Config.Demo.GuestTTL, logger)
+	}
+	reportUsecase := usecase.NewReportUsecase(projectRepo, taskRepo, taskStatusEventRepo, settingsRepo, logger)
+
+	// 週次ダイジェスト
+	digestUsecase := usecase.NewDigestUsecase(settingsRepo, userRepo, projectRepo, taskRepo, taskStatusEventRepo, mailSender, config.Config.Digest.SendHour, logger)
+	exportUsecase := usecase.NewExportUsecase(reportExportRepo, userRepo, reportUsecase, mailSender, fileStorage, config.Config.Storage.ExportRetention, config.Config.App.PublicURL, logger)
 
 	// GitHub連携
-	githubClient := github.NewClient(logger)
+	githubClient := github.NewClient(config.Config.GithubAPI.BudgetFloor, config.Config.GithubAPI.MaxRetries, config.Config.GithubAPI.RateLimitMaxWait, logger)
 	githubService := github.NewProjectService(githubClient, logger)
-	githubUsecase := usecase.NewGithubUsecase(githubAccountRepo, projectRepo, taskRepo, githubService, logger)
+	githubUsecase := usecase.NewGithubUsecase(githubAccountRepo, projectRepo, taskRepo, taskUsecase, taskPullRequestRepo, taskCommitRepo, githubFieldMappingRepo, milestoneRepo, settingsRepo, githubSyncOperationRepo, githubSyncUsageRepo, githubTaskSyncRepo, eventBus, transactor, locker, githubService, oauthConfig, config.Config.GithubBranch.Template, config.Config.GithubSync.QuotaPerHour, logger)
+	// GitHubがトークンを拒否した場合はアカウントを再認証が必要な状態にする
+	githubClient.SetUnauthorizedHandler(githubUsecase.HandleUnauthorized)
+	// GitHubに障害がある間に保留した変更は、接続が戻った後にタスクごとに作成順で再実行する
+	githubSyncQueue := usecase.NewGithubSyncQueue(githubUsecase, githubSyncOperationRepo, config.Config.GithubSync.QueuePollInterval, config.Config.GithubSync.QueueRetryMax, workerMonitor, logger)
+	// 受信したWebhookの配信はキューに保存して非同期に処理し、失敗したものは再試行する
+	statusUsecase := usecase.NewStatusUsecase(db, githubService, workerMonitor, buildVersion(), startedAt, config.Config.Status.CacheTTL, logger)
+	webhookUsecase := usecase.NewWebhookUsecase(webhookDeliveryRepo, config.Config.Webhook.MaxAttempts, config.Config.Webhook.PollInterval, workerMonitor, logger)
+	webhookEndpointUsecase := usecase.NewWebhookEndpointUsecase(webhookEndpointRepo, webhookEndpointDeliveryRepo, projectRepo, webhook.NewSender(config.Config.OutboundWebhook.Timeout, config.Config.OutboundWebhook.AllowPrivateNetworks),
+		config.Config.OutboundWebhook.MaxAttempts, config.Config.OutboundWebhook.DisableAfter, config.Config.OutboundWebhook.PollInterval, config.Config.OutboundWebhook.Retention, workerMonitor, logger)
+	// ローカルの場合はバックアップを従来どおりBACKUP_DIRに保存し、s3・gcsの場合はエクスポートと同じバケットに保存する
+	backupStorage := fileStorage
+	if config.Config.Storage.Backend == "local" {
+		backupStorage, err = storage.NewLocal(config.Config.Backup.Dir)
+		if err != nil {
+			logger.Error("failed to initialize backup storage", "error", err)
+			return 1
+		}
+	}
+	backupUsecase := usecase.NewBackupUsecase(backupRepo, backupStorage, config.Config.Backup.Retention, config.Config.Backup.RestoreEnabled, logger)
+	// GitHub側でIssue・Itemが削除されたら、連携しているタスクをプロジェクトの設定に従って連携切れにするか削除する
+	webhookUsecase.Handle("issues", githubUsecase.HandleIssueWebhook)
+	webhookUsecase.Handle("projects_v2_item", githubUsecase.HandleProjectItemWebhook)
+	webhookUsecase.Handle("issue_comment", githubUsecase.HandleIssueCommentWebhook)
+	// ドメインイベントの購読者（監査ログ・プロジェクトの変更のWebSocket配信・プッシュ通知・同期済みタスクのGitHubへの再同期）
+	eventBroadcaster := usecase.NewEventBroadcaster(logger)
+	eventBus.Subscribe("audit_log", usecase.NewAuditLogSubscriber(logger))
+	eventBus.Subscribe("broadcast", eventBroadcaster.Broadcast)
+	eventBus.Subscribe("webhook_endpoint", webhookEndpointUsecase.Dispatch, model.WebhookEventTypes...)
+	eventBus.Subscribe("push", pushUsecase.NotifyStatusChanged, model.EventTaskStatusChanged)
+	var githubSyncScheduler *usecase.GithubSyncScheduler
+	if config.Config.GithubSync.Delay > 0 {
+		githubSyncScheduler = usecase.NewGithubSyncScheduler(githubUsecase, taskRepo, projectRepo, config.Config.GithubSync.Delay, workerMonitor, logger)
+		eventBus.Subscribe("github_sync", githubSyncScheduler.Schedule, model.EventTaskUpdated, model.EventTaskStatusChanged)
+	}
+	// RELAY_BROKERを設定した場合は、外部のシステムが購読できるようにドメインイベントをメッセージブローカーに中継する
+	if config.Config.Relay.Broker != "" {
+		relayPublisher, err := broker.NewPublisher(broker.Config{
+			Kind:        broker.Kind(config.Config.Relay.Broker),
+			URL:         config.Config.Relay.URL,
+			Destination: config.Config.Relay.Destination,
+			Timeout:     config.Config.Relay.Timeout,
+		}, logger)
+		if err != nil {
+			logger.Error("failed to initialize event relay", "error", err)
+			return 1
+		}
+		defer relayPublisher.Close()
+		eventBus.Subscribe("relay", usecase.NewEventRelaySubscriber(relayPublisher))
+	}
+	// 終了日が近いタスクのリマインダー（メール・プロジェクトのWebhookの送信先・プッシュ通知のうち、ユーザーが設定で有効にした通知先に送る）
+	reminderUsecase := usecase.NewReminderUsecase(taskReminderRepo, taskRepo, projectRepo, settingsRepo, []usecase.ReminderChannel{
+		usecase.NewEmailReminderChannel(userRepo, mailSender),
+		usecase.NewWebhookReminderChannel(eventBus),
+		usecase.NewPushReminderChannel(pushUsecase),
+	}, config.Config.Reminder.SendHour, logger)
+	// 削除したプロジェクト・タスク・TODOは保持期間の間ゴミ箱に残し、過ぎたら完全に削除する
+	trashUsecase := usecase.NewTrashUsecase(projectRepo, taskRepo, todoRepo, config.Config.Trash.Retention, logger)
+	dashboardUsecase := usecase.NewDashboardUsecase(projectRepo, taskRepo, taskStatusEventRepo, settingsRepo, projectUsecase, githubUsecase, logger)
+
+	// 定期実行するジョブ（SCHEDULE_*のcron式で実行し、実行状況は/api/v1/admin/jobsで確認できる）
+	schedulerLocation, err := time.LoadLocation(config.Config.Scheduler.Timezone)
+	if err != nil {
+		logger.Error("failed to load scheduler timezone", "error", err)
+		return 1
+	}
+	scheduler := usecase.NewScheduler(schedulerLocation, locker, workerMonitor, logger)
+	err = errors.Join(
+		scheduler.Register("digest", config.Config.Scheduler.Digest, func(ctx context.Context) error {
+			return digestUsecase.SendDueDigests(ctx, time.Now())
+		}),
+		scheduler.Register("reminder", config.Config.Scheduler.Reminder, func(ctx context.Context) error {
+			return reminderUsecase.SendDueReminders(ctx, time.Now())
+		}),
+		scheduler.Register("trash_purge", config.Config.Scheduler.TrashPurge, func(ctx context.Context) error {
+			return trashUsecase.PurgeExpired(ctx, time.Now())
+		}),
+		scheduler.Register("github_import", config.Config.Scheduler.GithubImport, githubUsecase.ImportAllAssignedIssues),
+	)
+	if demoUsecase != nil {
+		err = errors.Join(err, scheduler.Register("guest_purge", config.Config.Scheduler.GuestPurge, func(ctx context.Context) error {
+			return demoUsecase.PurgeExpired(ctx, time.Now())
+		}))
+	}
+	if config.Config.Scheduler.GithubProjectSync != "" {
+		err = errors.Join(err, scheduler.Register("github_project_sync", config.Config.Scheduler.GithubProjectSync, githubUsecase.SyncAllProjects))
+	}
+	if serverSessionStore != nil {
+		err = errors.Join(err, scheduler.Register("session_purge", config.Config.Scheduler.SessionPurge, func(ctx context.Context) error {
+			return serverSessionStore.PurgeExpired(ctx, time.Now())
+		}))
+	}
+	if config.Config.Scheduler.Backup != "" {
+		err = errors.Join(err, scheduler.Register("backup", config.Config.Scheduler.Backup, backupUsecase.CreateScheduledBackup))
+	}
+	if err != nil {
+		logger.Error("failed to register scheduled jobs", "error", err)
+		return 1
+	}
 
 	todoHandler := handler.NewTodoHandler(todoUsecase, logger)
-	authHandler := handler.NewAuthHandler(authUsecase, sessionStore, config.Config.App.FrontendURL, logger)
-	projectHandler := handler.NewProjectHandler(projectUsecase, logger)
+	authHandler := handler.NewAuthHandler(authUsecase, sessionUsecase, tokenUsecase, samlUsecase, demoUsecase, sessionStore, config.Config.App.FrontendURL, logger)
+	projectHandler := handler.NewProjectHandler(projectUsecase, githubUsecase, logger)
 	taskHandler := handler.NewTaskHandler(taskUsecase, logger)
+	milestoneHandler := handler.NewMilestoneHandler(milestoneUsecase, logger)
+	activityHandler := handler.NewActivityHandler(activityUsecase, logger)
+	savedViewHandler := handler.NewSavedViewHandler(savedViewUsecase, logger)
+	projectTokenHandler := handler.NewProjectTokenHandler(projectTokenUsecase, logger)
+	webhookEndpointHandler := handler.NewWebhookEndpointHandler(webhookEndpointUsecase, logger)
+	goalHandler := handler.NewGoalHandler(goalUsecase, logger)
+	settingsHandler := handler.NewSettingsHandler(settingsUsecase, logger)
+	pushHandler := handler.NewPushHandler(pushUsecase, logger)
+	reportHandler := handler.NewReportHandler(reportUsecase, logger)
+	exportHandler := handler.NewExportHandler(exportUsecase, logger)
 	githubHandler := handler.NewGithubHandler(githubUsecase, logger)
+	dashboardHandler := handler.NewDashboardHandler(dashboardUsecase, logger)
+	trashHandler := handler.NewTrashHandler(trashUsecase, logger)
+	sessionHandler := handler.NewSessionHandler(sessionUsecase, logger)
+	invitationHandler := handler.NewInvitationHandler(invitationUsecase, logger)
+	accountMergeHandler := handler.NewAccountMergeHandler(accountMergeUsecase, config.Config.App.FrontendURL, logger)
+	webhookDeliveryHandler := handler.NewWebhookDeliveryHandler(webhookUsecase, logger)
+	var githubWebhookHandler *handler.GithubWebhookHandler
+	if config.Config.Webhook.GithubSecret != "" {
+		githubWebhookHandler = handler.NewGithubWebhookHandler(webhookUsecase, config.Config.Webhook.GithubSecret, logger)
+	}
+	backupHandler := handler.NewBackupHandler(backupUsecase, logger)
+	statusHandler := handler.NewStatusHandler(statusUsecase, logger)
+	jobHandler := handler.NewJobHandler(scheduler, logger)
+	eventStreamHandler := handler.NewEventStreamHandler(projectUsecase, eventBroadcaster, config.Config.Profile.CORSAllowedOrigins, logger)
+
+	// 開発環境では開発用データの生成エンドポイントを有効にする
+	var seedHandler *handler.SeedHandler
+	if config.Config.Profile.Name == config.EnvDev {
+		seedUsecase := usecase.NewSeedUsecase(userRepo, projectRepo, milestoneRepo, taskRepo, taskStatusEventRepo, taskPullRequestRepo, taskCommitRepo, logger)
+		seedHandler = handler.NewSeedHandler(seedUsecase, logger)
+	}
+
+	// SCIM_TOKENを設定した場合はIdPからのプロビジョニング（/scim/v2）を有効にする
+	var scimHandler *handler.SCIMHandler
+	var provisioningAuth *middleware.ProvisioningAuthMiddleware
+	if config.Config.SCIM.Token != "" {
+		provisioningUsecase := usecase.NewProvisioningUsecase(userRepo, groupRepo, userSessionRepo, logger)
+		scimHandler = handler.NewSCIMHandler(provisioningUsecase, config.Config.App.PublicURL+"/scim/v2", logger)
+		provisioningAuth = middleware.NewProvisioningAuthMiddleware(config.Config.SCIM.Token, logger)
+	}
 
-	authMiddleware := middleware.NewAuthMiddleware(sessionStore, logger)
+	authMiddleware := middleware.NewAuthMiddleware(sessionStore, accessTokens, projectTokenUsecase, sessionUsecase, tenancy, logger)
+	adminAuth := middleware.NewAdminMiddleware(config.Config.Admin.UserIDs, logger)
+	clientIP := middleware.NewClientIPMiddleware(config.Config.App.TrustedProxyHops)
+	rateLimiter := middleware.NewRateLimitMiddleware(config.Config.Profile.RateLimitPerMinute, time.Minute, logger)
+	statusLimiter := middleware.NewRateLimitMiddleware(config.Config.Status.RateLimitPerMinute, time.Minute, logger)
+
+	// CAPTCHA_PROVIDERを設定した場合はログイン開始の試行回数が多いクライアントにCAPTCHAを要求する
+	var authChallenge *middleware.ChallengeMiddleware
+	if config.Config.Captcha.Provider != "" {
+		verifier, err := captcha.NewVerifier(captcha.Provider(config.Config.Captcha.Provider), config.Config.Captcha.SiteKey, config.Config.Captcha.Secret)
+		if err != nil {
+			logger.Error("invalid captcha config", "error", err)
+			return 1
+		}
+		authChallenge = middleware.NewChallengeMiddleware(verifier, config.Config.Captcha.Threshold, config.Config.Captcha.Window, config.Config.App.FrontendURL, logger)
+	}
 
 	// ルーターのセットアップ
-	r := router.NewRouter(todoHandler, projectHandler, taskHandler, authHandler, githubHandler, authMiddleware, config.Config.App.FrontendURL, logger)
+	r := router.NewRouter(todoHandler, projectHandler, taskHandler, milestoneHandler, savedViewHandler, projectTokenHandler, webhookEndpointHandler, goalHandler, settingsHandler, pushHandler, reportHandler, exportHandler, dashboardHandler, trashHandler, activityHandler, sessionHandler, invitationHandler, accountMergeHandler, authHandler, githubHandler, scimHandler, webhookDeliveryHandler, githubWebhookHandler, backupHandler, jobHandler, seedHandler, statusHandler, eventStreamHandler, authMiddleware, provisioningAuth, adminAuth, rateLimiter, statusLimiter, authChallenge, clientIP, config.Config.Profile.CORSAllowedOrigins, logger)
 	httpHandler := r.Setup()
 
+	// リスナーの作成（TCP / Unixドメインソケット / systemdソケットアクティベーション）
+	socketMode, err := listener.ParseFileMode(config.Config.App.SocketMode)
+	if err != nil {
+		logger.Error("invalid LISTEN_SOCKET_MODE", "error", err)
+		return 1
+	}
+	ln, err := listener.New(listener.Config{
+		Port:       config.Config.App.Port,
+		SocketPath: config.Config.App.SocketPath,
+		SocketMode: socketMode,
+	}, logger)
+	if err != nil {
+		logger.Error("failed to create listener", "error", err)
+		return 1
+	}
+
 	// サーバーの設定
 	srv := &http.Server{
-		Addr:         fmt.Sprintf(":%s", config.Config.App.Port),
 		Handler:      httpHandler,
+		BaseContext:  func(net.Listener) context.Context { return ctx },
 		ReadTimeout:  15 * time.Second,
 		WriteTimeout: 15 * time.Second,
 		IdleTimeout:  60 * time.Second,
@@ -146,18 +549,32 @@ func run() int {
 
 	// サーバーの起動
 	go func() {
-		logger.Info("starting server", "port", config.Config.App.Port)
-		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		logger.Info("starting server", "addr", ln.Addr().String())
+		if err := srv.Serve(ln); err != nil && err != http.ErrServerClosed {
 			logger.Error("server error", "error", err)
 		}
 	}()
 
+	// バックグラウンドジョブ（定期実行するジョブ・レポートのエクスポート・Webhook・ドメインイベントの配信）
+	jobCtx, stopJobs := context.WithCancel(ctx)
+	defer stopJobs()
+	go scheduler.Run(jobCtx)
+	go exportUsecase.Run(jobCtx)
+	go webhookUsecase.Run(jobCtx)
+	go webhookEndpointUsecase.Run(jobCtx)
+	go githubSyncQueue.Run(jobCtx)
+	go eventBus.Run(jobCtx)
+	if githubSyncScheduler != nil {
+		go githubSyncScheduler.Run(jobCtx)
+	}
+
 	// シグナル待機
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 	<-quit
 
 	logger.Info("shutting down server...")
+	stopJobs()
 
 	// シャットダウンのタイムアウト設定
 	shutdownCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
@@ -171,3 +588,29 @@ func run() int {
 	logger.Info("server exited gracefully")
 	return 0
 }
+
+// buildVersion はビルド時に埋め込んだバージョンを返す（未指定の場合はVCSのリビジョンを使う）
+func buildVersion() string {
+	if version != "dev" {
+		return version
+	}
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return version
+	}
+	for _, setting := range info.Settings {
+		if setting.Key == "vcs.revision" && len(setting.Value) >= 12 {
+			return version + "-" + setting.Value[:12]
+		}
+	}
+	return version
+}
+
+// newLogger は環境プロファイルに応じたロガーを作成する
+func newLogger(profile config.Profile) *slog.Logger {
+	opts := &slog.HandlerOptions{Level: profile.LogLevel}
+	if profile.LogFormat == config.LogFormatText {
+		return slog.New(slog.NewTextHandler(os.Stdout, opts))
+	}
+	return slog.New(slog.NewJSONHandler(os.Stdout, opts))
+}
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"os"
+	"strconv"
+
+	"github.com/sikigasa/github-task-controller/backend/cmd/config"
+	"github.com/sikigasa/github-task-controller/backend/db/migrations"
+	"github.com/sikigasa/github-task-controller/backend/internal/infrastructure/persistence"
+)
+
+const migrateUsage = `usage: server migrate <command>
+
+commands:
+  up [n]           未適用のマイグレーションをすべて（nを指定した場合はn件）適用する
+  down [n]         適用済みのマイグレーションをn件（デフォルト: 1）取り消す
+  version          適用済みのバージョンを表示する
+  force <version>  マイグレーションを実行せずに適用済みのバージョンを記録する（dirtyの解除）`
+
+// migrateOnStartup は起動時にDB_MIGRATION_MODEに従ってスキーマのマイグレーションを実行・検査する
+func migrateOnStartup(ctx context.Context, db *sql.DB, logger *slog.Logger) error {
+	mode := config.Config.Database.MigrationMode
+	if mode == "off" {
+		logger.Info("skipping database migrations", "mode", mode)
+		return nil
+	}
+
+	migrator, err := persistence.NewMigrator(ctx, db, migrations.FS(config.Config.Database.MigrationsDir), logger)
+	if err != nil {
+		return err
+	}
+	defer closeMigrator(migrator, logger)
+
+	if mode == "check" {
+		return migrator.Check()
+	}
+	return migrator.Up(ctx)
+}
+
+// runMigrate は migrate サブコマンドを実行し、終了コードを返す
+func runMigrate(ctx context.Context, args []string, dbConfig persistence.DBConfig, logger *slog.Logger) int {
+	if len(args) == 0 {
+		fmt.Fprintln(os.Stderr, migrateUsage)
+		return 2
+	}
+
+	db, err := persistence.NewDB(ctx, dbConfig, logger)
+	if err != nil {
+		logger.Error("failed to connect to database", "error", err)
+		return 1
+	}
+	defer db.Close()
+
+	migrator, err := persistence.NewMigrator(ctx, db, migrations.FS(config.Config.Database.MigrationsDir), logger)
+	if err != nil {
+		logger.Error("failed to load migrations", "error", err)
+		return 1
+	}
+	defer closeMigrator(migrator, logger)
+
+	switch args[0] {
+	case "up":
+		n, err := migrateCount(args[1:], 0)
+		if err != nil {
+			break
+		}
+		if n == 0 {
+			err = migrator.Up(ctx)
+		} else {
+			err = migrator.Steps(ctx, n)
+		}
+		return migrateResult(err, logger)
+	case "down":
+		n, err := migrateCount(args[1:], 1)
+		if err != nil || n == 0 {
+			break
+		}
+		return migrateResult(migrator.Steps(ctx, -n), logger)
+	case "version":
+		version, dirty, err := migrator.Version()
+		if err != nil {
+			return migrateResult(err, logger)
+		}
+		if dirty {
+			fmt.Printf("%d (dirty)\n", version)
+		} else {
+			fmt.Println(version)
+		}
+		return 0
+	case "force":
+		if len(args) != 2 {
+			break
+		}
+		version, err := strconv.Atoi(args[1])
+		if err != nil {
+			break
+		}
+		return migrateResult(migrator.Force(version), logger)
+	}
+
+	fmt.Fprintln(os.Stderr, migrateUsage)
+	return 2
+}
+
+// migrateCount は up・down に続く件数の引数を解釈する（省略した場合はdefaultCount）
+func migrateCount(args []string, defaultCount int) (int, error) {
+	switch len(args) {
+	case 0:
+		return defaultCount, nil
+	case 1:
+		n, err := strconv.Atoi(args[0])
+		if err != nil || n < 0 {
+			return 0, fmt.Errorf("invalid migration count: %s", args[0])
+		}
+		return n, nil
+	default:
+		return 0, fmt.Errorf("too many arguments")
+	}
+}
+
+// migrateResult はマイグレーションの結果を終了コードに変換する
+func migrateResult(err error, logger *slog.Logger) int {
+	if err != nil {
+		logger.Error("migration failed", "error", err)
+		return 1
+	}
+	return 0
+}
+
+func closeMigrator(migrator *persistence.Migrator, logger *slog.Logger) {
+	if err := migrator.Close(); err != nil {
+		logger.Warn("failed to close migrator", "error", err)
+	}
+}
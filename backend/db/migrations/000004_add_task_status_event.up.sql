@@ -0,0 +1,14 @@
+-- タスクのステータス遷移イベント（アクティビティログ・GitHub同期・レポートの元データ）
+CREATE TABLE IF NOT EXISTS task_status_event (
+  id uuid PRIMARY KEY DEFAULT uuid_generate_v7(),
+  task_id uuid NOT NULL,
+  project_id uuid NOT NULL,
+  from_status INT,
+  to_status INT NOT NULL,
+  reopened BOOLEAN NOT NULL DEFAULT FALSE,
+  changed_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  CONSTRAINT task_status_event_task_fk FOREIGN KEY (task_id) REFERENCES task(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_task_status_event_task_id ON task_status_event(task_id, changed_at);
+CREATE INDEX IF NOT EXISTS idx_task_status_event_project_id ON task_status_event(project_id, changed_at);
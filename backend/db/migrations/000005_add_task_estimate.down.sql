@@ -0,0 +1,3 @@
+ALTER TABLE project DROP COLUMN IF EXISTS github_estimate_field;
+ALTER TABLE project DROP COLUMN IF EXISTS estimate_unit;
+ALTER TABLE task DROP COLUMN IF EXISTS estimate;
@@ -0,0 +1,6 @@
+-- タスクの見積もり（ストーリーポイントまたは時間）
+ALTER TABLE task ADD COLUMN IF NOT EXISTS estimate NUMERIC(10, 2);
+
+-- プロジェクトごとの見積もり単位とGitHub Projectsの同期先フィールド
+ALTER TABLE project ADD COLUMN IF NOT EXISTS estimate_unit VARCHAR(16) NOT NULL DEFAULT 'points';
+ALTER TABLE project ADD COLUMN IF NOT EXISTS github_estimate_field VARCHAR;
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS task_dependency;
+ALTER TABLE task DROP COLUMN IF EXISTS start_date;
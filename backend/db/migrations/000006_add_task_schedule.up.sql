@@ -0,0 +1,16 @@
+-- タスクの開始日（タイムライン表示用）
+ALTER TABLE task ADD COLUMN IF NOT EXISTS start_date TIMESTAMP;
+
+-- タスク間の依存関係（task_idはdepends_on_task_idの完了後に開始する）
+CREATE TABLE IF NOT EXISTS task_dependency (
+  task_id uuid NOT NULL,
+  depends_on_task_id uuid NOT NULL,
+  project_id uuid NOT NULL,
+  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (task_id, depends_on_task_id),
+  CONSTRAINT task_dependency_task_fk FOREIGN KEY (task_id) REFERENCES task(id) ON DELETE CASCADE,
+  CONSTRAINT task_dependency_depends_on_fk FOREIGN KEY (depends_on_task_id) REFERENCES task(id) ON DELETE CASCADE,
+  CONSTRAINT task_dependency_not_self CHECK (task_id <> depends_on_task_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_task_dependency_project_id ON task_dependency(project_id);
@@ -0,0 +1,2 @@
+ALTER TABLE task DROP COLUMN IF EXISTS milestone_id;
+DROP TABLE IF EXISTS milestone;
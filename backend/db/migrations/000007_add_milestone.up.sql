@@ -0,0 +1,20 @@
+-- プロジェクト内のマイルストーン
+CREATE TABLE IF NOT EXISTS milestone (
+  id uuid PRIMARY KEY DEFAULT uuid_generate_v7(),
+  project_id uuid NOT NULL,
+  title VARCHAR(255) NOT NULL,
+  description TEXT NOT NULL DEFAULT '',
+  due_date TIMESTAMP,
+  github_milestone_number INT,
+  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  CONSTRAINT milestone_project_fk FOREIGN KEY (project_id) REFERENCES project(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_milestone_project_id ON milestone(project_id);
+
+-- タスクのマイルストーン割り当て（マイルストーン削除時は未割り当てに戻す）
+ALTER TABLE task ADD COLUMN IF NOT EXISTS milestone_id uuid
+  REFERENCES milestone(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_task_milestone_id ON task(milestone_id);
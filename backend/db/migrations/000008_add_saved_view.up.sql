@@ -0,0 +1,15 @@
+-- ユーザーがプロジェクトごとに保存したタスクの絞り込み・並び順
+CREATE TABLE IF NOT EXISTS saved_view (
+  id uuid PRIMARY KEY DEFAULT uuid_generate_v7(),
+  project_id uuid NOT NULL,
+  user_id uuid NOT NULL,
+  name VARCHAR(100) NOT NULL,
+  filter JSONB NOT NULL DEFAULT '{}',
+  sort_keys JSONB NOT NULL DEFAULT '[]',
+  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  CONSTRAINT saved_view_project_fk FOREIGN KEY (project_id) REFERENCES project(id) ON DELETE CASCADE,
+  CONSTRAINT saved_view_user_fk FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_saved_view_project_user ON saved_view(project_id, user_id);
@@ -0,0 +1,15 @@
+-- タスク間の関連（プロジェクトをまたいで関連付けできる）
+-- duplicated_byはduplicatesに正規化し、relates_toはtask_id < related_task_idで保存する
+CREATE TABLE IF NOT EXISTS task_relation (
+  task_id uuid NOT NULL,
+  related_task_id uuid NOT NULL,
+  type VARCHAR(32) NOT NULL,
+  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (task_id, related_task_id, type),
+  CONSTRAINT task_relation_task_fk FOREIGN KEY (task_id) REFERENCES task(id) ON DELETE CASCADE,
+  CONSTRAINT task_relation_related_fk FOREIGN KEY (related_task_id) REFERENCES task(id) ON DELETE CASCADE,
+  CONSTRAINT task_relation_not_self CHECK (task_id <> related_task_id),
+  CONSTRAINT task_relation_type_check CHECK (type IN ('relates_to', 'duplicates'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_task_relation_related_task_id ON task_relation(related_task_id);
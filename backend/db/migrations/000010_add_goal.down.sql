@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS goal_task;
+DROP TABLE IF EXISTS goal;
@@ -0,0 +1,25 @@
+-- プロジェクトの目標（OKR）
+CREATE TABLE IF NOT EXISTS goal (
+  id uuid PRIMARY KEY DEFAULT uuid_generate_v7(),
+  project_id uuid NOT NULL,
+  title VARCHAR(255) NOT NULL,
+  description TEXT NOT NULL DEFAULT '',
+  quarter VARCHAR(7) NOT NULL,
+  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  CONSTRAINT goal_project_fk FOREIGN KEY (project_id) REFERENCES project(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_goal_project_quarter ON goal(project_id, quarter);
+
+-- 目標に貢献するタスク
+CREATE TABLE IF NOT EXISTS goal_task (
+  goal_id uuid NOT NULL,
+  task_id uuid NOT NULL,
+  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (goal_id, task_id),
+  CONSTRAINT goal_task_goal_fk FOREIGN KEY (goal_id) REFERENCES goal(id) ON DELETE CASCADE,
+  CONSTRAINT goal_task_task_fk FOREIGN KEY (task_id) REFERENCES task(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_goal_task_task_id ON goal_task(task_id);
@@ -0,0 +1,12 @@
+-- ユーザー（ワークスペース）ごとの設定
+CREATE TABLE IF NOT EXISTS user_settings (
+  user_id uuid PRIMARY KEY,
+  default_task_status INT NOT NULL DEFAULT 0,
+  week_start_day INT NOT NULL DEFAULT 1,
+  timezone VARCHAR(64) NOT NULL DEFAULT 'Asia/Tokyo',
+  default_github_owner VARCHAR(39),
+  default_labels TEXT[] NOT NULL DEFAULT '{}',
+  updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  CONSTRAINT user_settings_user_fk FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+  CONSTRAINT user_settings_week_start_day_check CHECK (week_start_day BETWEEN 0 AND 6)
+);
@@ -0,0 +1,2 @@
+ALTER TABLE user_settings DROP COLUMN IF EXISTS last_digest_sent_at;
+ALTER TABLE user_settings DROP COLUMN IF EXISTS weekly_digest;
@@ -0,0 +1,3 @@
+-- 週次ダイジェストメールの配信設定（オプトイン）と最終配信日時
+ALTER TABLE user_settings ADD COLUMN IF NOT EXISTS weekly_digest BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE user_settings ADD COLUMN IF NOT EXISTS last_digest_sent_at TIMESTAMP;
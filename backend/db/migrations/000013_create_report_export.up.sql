@@ -0,0 +1,19 @@
+-- レポートのエクスポート（非同期に生成したCSV/PDFを保持する）
+CREATE TABLE IF NOT EXISTS report_export (
+  id uuid PRIMARY KEY DEFAULT uuid_generate_v7(),
+  user_id uuid NOT NULL,
+  report VARCHAR(32) NOT NULL,
+  format VARCHAR(16) NOT NULL,
+  params JSONB NOT NULL DEFAULT '{}',
+  status VARCHAR(16) NOT NULL DEFAULT 'pending',
+  file_name VARCHAR(255),
+  content_type VARCHAR(100),
+  content BYTEA,
+  error TEXT,
+  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  completed_at TIMESTAMP,
+  CONSTRAINT report_export_user_fk FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+  CONSTRAINT report_export_status_check CHECK (status IN ('pending', 'completed', 'failed'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_report_export_status ON report_export(status);
@@ -0,0 +1,14 @@
+-- ログインセッションごとの端末情報と最終アクティビティ
+CREATE TABLE IF NOT EXISTS user_session (
+  id uuid PRIMARY KEY DEFAULT uuid_generate_v7(),
+  user_id uuid NOT NULL,
+  user_agent VARCHAR(512) NOT NULL DEFAULT '',
+  ip_address VARCHAR(64) NOT NULL DEFAULT '',
+  location VARCHAR(64),
+  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  last_seen_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  expires_at TIMESTAMP NOT NULL,
+  CONSTRAINT user_session_user_fk FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_session_user_id ON user_session(user_id, expires_at);
@@ -0,0 +1,14 @@
+-- アクセストークンの再発行に使うリフレッシュトークン（ログインセッションの記録を削除すると系列ごと失効する）
+CREATE TABLE IF NOT EXISTS refresh_token (
+  id uuid PRIMARY KEY DEFAULT uuid_generate_v7(),
+  session_id uuid NOT NULL,
+  user_id uuid NOT NULL,
+  token_hash VARCHAR(64) NOT NULL UNIQUE,
+  expires_at TIMESTAMP NOT NULL,
+  used_at TIMESTAMP,
+  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  CONSTRAINT refresh_token_session_fk FOREIGN KEY (session_id) REFERENCES user_session(id) ON DELETE CASCADE,
+  CONSTRAINT refresh_token_user_fk FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_refresh_token_session_id ON refresh_token(session_id);
@@ -0,0 +1,17 @@
+-- Sign in with Appleのアカウント（is_private_emailはメールアドレスがAppleの転送用アドレスかどうか）
+CREATE TABLE IF NOT EXISTS apple_account (
+  user_id uuid NOT NULL,
+  provider VARCHAR NOT NULL,
+  provider_account_id VARCHAR NOT NULL,
+  email VARCHAR(255) NOT NULL DEFAULT '',
+  is_private_email BOOLEAN NOT NULL DEFAULT FALSE,
+  access_token VARCHAR,
+  refresh_token VARCHAR,
+  expires_at BIGINT,
+  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  CONSTRAINT apple_account_pk PRIMARY KEY (provider, provider_account_id),
+  CONSTRAINT apple_account_user_fk FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_apple_account_user_id ON apple_account(user_id);
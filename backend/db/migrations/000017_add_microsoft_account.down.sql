@@ -0,0 +1 @@
+DROP TABLE IF EXISTS microsoft_account;
@@ -0,0 +1,15 @@
+-- Microsoft Entra ID（Azure AD）のアカウント
+CREATE TABLE IF NOT EXISTS microsoft_account (
+  user_id uuid NOT NULL,
+  provider VARCHAR NOT NULL,
+  provider_account_id VARCHAR NOT NULL,
+  access_token VARCHAR,
+  refresh_token VARCHAR,
+  expires_at BIGINT,
+  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  CONSTRAINT microsoft_account_pk PRIMARY KEY (provider, provider_account_id),
+  CONSTRAINT microsoft_account_user_fk FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_microsoft_account_user_id ON microsoft_account(user_id);
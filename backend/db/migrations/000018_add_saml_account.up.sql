@@ -0,0 +1,12 @@
+-- SAML IdPのアカウント（NameID）
+CREATE TABLE IF NOT EXISTS saml_account (
+  user_id uuid NOT NULL,
+  idp_entity_id VARCHAR NOT NULL,
+  name_id VARCHAR NOT NULL,
+  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  CONSTRAINT saml_account_pk PRIMARY KEY (idp_entity_id, name_id),
+  CONSTRAINT saml_account_user_fk FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_saml_account_user_id ON saml_account(user_id);
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS scim_group_member;
+DROP TABLE IF EXISTS scim_group;
+ALTER TABLE users DROP COLUMN IF EXISTS deactivated_at;
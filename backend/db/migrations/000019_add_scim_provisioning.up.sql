@@ -0,0 +1,22 @@
+-- SCIMでプロビジョニングを解除（無効化）したユーザー
+ALTER TABLE users ADD COLUMN IF NOT EXISTS deactivated_at TIMESTAMP;
+
+-- SCIMでプロビジョニングされたグループ
+CREATE TABLE IF NOT EXISTS scim_group (
+  id uuid PRIMARY KEY DEFAULT uuid_generate_v7(),
+  display_name VARCHAR(255) NOT NULL,
+  external_id VARCHAR(255),
+  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  CONSTRAINT scim_group_display_name_unique UNIQUE (display_name)
+);
+
+CREATE TABLE IF NOT EXISTS scim_group_member (
+  group_id uuid NOT NULL,
+  user_id uuid NOT NULL,
+  CONSTRAINT scim_group_member_pk PRIMARY KEY (group_id, user_id),
+  CONSTRAINT scim_group_member_group_fk FOREIGN KEY (group_id) REFERENCES scim_group(id) ON DELETE CASCADE,
+  CONSTRAINT scim_group_member_user_fk FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_scim_group_member_user_id ON scim_group_member(user_id);
@@ -0,0 +1,9 @@
+-- デモモードのゲストユーザー（有効期限を過ぎるとユーザーごと削除する）
+CREATE TABLE IF NOT EXISTS guest_user (
+  user_id uuid PRIMARY KEY,
+  expires_at TIMESTAMP NOT NULL,
+  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  CONSTRAINT guest_user_user_fk FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_guest_user_expires_at ON guest_user(expires_at);
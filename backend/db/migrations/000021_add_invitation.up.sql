@@ -0,0 +1,13 @@
+-- 招待制での新規登録の招待
+CREATE TABLE IF NOT EXISTS invitation (
+  id uuid PRIMARY KEY DEFAULT uuid_generate_v7(),
+  email VARCHAR(255) NOT NULL,
+  invited_by uuid NOT NULL,
+  expires_at TIMESTAMP NOT NULL,
+  accepted_at TIMESTAMP,
+  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  CONSTRAINT invitation_email_unique UNIQUE (email),
+  CONSTRAINT invitation_invited_by_fk FOREIGN KEY (invited_by) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_invitation_invited_by ON invitation(invited_by);
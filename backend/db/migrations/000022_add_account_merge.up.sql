@@ -0,0 +1,21 @@
+-- 別プロバイダーのアカウントを既存ユーザーに紐付ける（統合する）要求と確認の監査記録
+CREATE TABLE IF NOT EXISTS account_merge (
+  id uuid PRIMARY KEY DEFAULT uuid_generate_v7(),
+  user_id uuid NOT NULL,
+  provider VARCHAR(50) NOT NULL,
+  provider_account_id VARCHAR(255) NOT NULL,
+  provider_email VARCHAR(255) NOT NULL,
+  access_token TEXT NOT NULL DEFAULT '',
+  refresh_token TEXT NOT NULL DEFAULT '',
+  token_expires_at TIMESTAMP,
+  token_hash VARCHAR(64) NOT NULL,
+  expires_at TIMESTAMP NOT NULL,
+  confirmed_at TIMESTAMP,
+  confirmed_via VARCHAR(20),
+  confirmed_ip VARCHAR(64),
+  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  CONSTRAINT account_merge_token_hash_unique UNIQUE (token_hash),
+  CONSTRAINT account_merge_user_id_fk FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_account_merge_user_id ON account_merge(user_id);
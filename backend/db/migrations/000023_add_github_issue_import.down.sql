@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_task_github_issue_url;
+ALTER TABLE user_settings DROP COLUMN IF EXISTS last_github_import_at;
+ALTER TABLE user_settings DROP COLUMN IF EXISTS github_import_project_id;
@@ -0,0 +1,7 @@
+-- 担当のGitHub Issueをタスクとして取り込むプロジェクトと最終取り込み日時
+ALTER TABLE user_settings ADD COLUMN IF NOT EXISTS github_import_project_id uuid
+  REFERENCES project(id) ON DELETE SET NULL;
+ALTER TABLE user_settings ADD COLUMN IF NOT EXISTS last_github_import_at TIMESTAMP;
+
+-- 取り込み時にIssueのURLで既存のタスクを検索する
+CREATE INDEX IF NOT EXISTS idx_task_github_issue_url ON task(project_id, github_issue_url);
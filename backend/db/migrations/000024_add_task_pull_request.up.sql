@@ -0,0 +1,15 @@
+-- タスクのGitHub Issueを参照しているPull Request（closes #N・ブランチ名から検出する）
+CREATE TABLE IF NOT EXISTS task_pull_request (
+  task_id uuid NOT NULL,
+  number INT NOT NULL,
+  title VARCHAR(1024) NOT NULL DEFAULT '',
+  url VARCHAR(1024) NOT NULL,
+  state VARCHAR(16) NOT NULL,
+  head_ref VARCHAR(255) NOT NULL DEFAULT '',
+  link_type VARCHAR(16) NOT NULL,
+  merged_at TIMESTAMP,
+  updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (task_id, url),
+  CONSTRAINT task_pull_request_task_fk
+    FOREIGN KEY (task_id) REFERENCES task(id) ON DELETE CASCADE
+);
@@ -0,0 +1,2 @@
+-- タスク用に作成したGitHubの作業ブランチ名
+ALTER TABLE task ADD COLUMN IF NOT EXISTS github_branch VARCHAR(255);
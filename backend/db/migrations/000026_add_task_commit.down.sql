@@ -0,0 +1,2 @@
+ALTER TABLE project DROP COLUMN IF EXISTS github_commit_starts_task;
+DROP TABLE IF EXISTS task_commit;
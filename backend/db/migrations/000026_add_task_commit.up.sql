@@ -0,0 +1,19 @@
+-- コミットメッセージでタスクを参照しているコミット
+CREATE TABLE IF NOT EXISTS task_commit (
+  task_id uuid NOT NULL,
+  project_id uuid NOT NULL,
+  sha VARCHAR(64) NOT NULL,
+  message TEXT NOT NULL DEFAULT '',
+  url VARCHAR(1024) NOT NULL DEFAULT '',
+  author VARCHAR(255) NOT NULL DEFAULT '',
+  committed_at TIMESTAMP NOT NULL,
+  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (task_id, sha),
+  CONSTRAINT task_commit_task_fk
+    FOREIGN KEY (task_id) REFERENCES task(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_task_commit_project_id ON task_commit(project_id, committed_at);
+
+-- 最初の参照コミットでタスクを進行中にするか
+ALTER TABLE project ADD COLUMN IF NOT EXISTS github_commit_starts_task BOOLEAN NOT NULL DEFAULT FALSE;
@@ -0,0 +1,2 @@
+ALTER TABLE task DROP COLUMN IF EXISTS github_checks_status;
+ALTER TABLE task_pull_request DROP COLUMN IF EXISTS checks_status;
@@ -0,0 +1,3 @@
+-- Pull RequestのCI（GitHub Checks・Actions・コミットステータス）の状態
+ALTER TABLE task_pull_request ADD COLUMN IF NOT EXISTS checks_status VARCHAR(16);
+ALTER TABLE task ADD COLUMN IF NOT EXISTS github_checks_status VARCHAR(16);
@@ -0,0 +1,2 @@
+-- 連携先のGitHub Projectをリポジトリに紐づくProject（repository.projectV2）として検索するか
+ALTER TABLE project ADD COLUMN IF NOT EXISTS github_repo_project BOOLEAN NOT NULL DEFAULT FALSE;
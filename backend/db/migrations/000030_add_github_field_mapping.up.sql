@@ -0,0 +1,15 @@
+-- プロジェクトのタスクのステータス・優先度とGitHub Projectsの単一選択フィールドの選択肢の対応付け
+CREATE TABLE IF NOT EXISTS github_field_mapping (
+  project_id uuid PRIMARY KEY,
+  status_field VARCHAR(255) NOT NULL,
+  status_todo VARCHAR(255) NOT NULL,
+  status_in_progress VARCHAR(255) NOT NULL,
+  status_done VARCHAR(255) NOT NULL,
+  priority_field VARCHAR(255),
+  priority_low VARCHAR(255) NOT NULL DEFAULT '',
+  priority_medium VARCHAR(255) NOT NULL DEFAULT '',
+  priority_high VARCHAR(255) NOT NULL DEFAULT '',
+  updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  CONSTRAINT github_field_mapping_project_fk
+    FOREIGN KEY (project_id) REFERENCES project(id) ON DELETE CASCADE
+);
@@ -0,0 +1,2 @@
+ALTER TABLE task DROP COLUMN IF EXISTS github_sync_state;
+ALTER TABLE project DROP COLUMN IF EXISTS github_deletion_policy;
@@ -0,0 +1,5 @@
+-- GitHub側で連携先のItem・Issueが削除された時のタスクの扱い（orphan: 連携切れとして残す、delete: 削除する）
+ALTER TABLE project ADD COLUMN IF NOT EXISTS github_deletion_policy VARCHAR(16) NOT NULL DEFAULT 'orphan';
+
+-- タスクの連携先との同期の状態（orphaned: GitHub上で削除された）
+ALTER TABLE task ADD COLUMN IF NOT EXISTS github_sync_state VARCHAR(16);
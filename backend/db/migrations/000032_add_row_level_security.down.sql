@@ -0,0 +1,43 @@
+DROP POLICY IF EXISTS report_export_tenant ON report_export;
+ALTER TABLE report_export NO FORCE ROW LEVEL SECURITY;
+ALTER TABLE report_export DISABLE ROW LEVEL SECURITY;
+DROP POLICY IF EXISTS user_settings_tenant ON user_settings;
+ALTER TABLE user_settings NO FORCE ROW LEVEL SECURITY;
+ALTER TABLE user_settings DISABLE ROW LEVEL SECURITY;
+DROP POLICY IF EXISTS github_field_mapping_tenant ON github_field_mapping;
+ALTER TABLE github_field_mapping NO FORCE ROW LEVEL SECURITY;
+ALTER TABLE github_field_mapping DISABLE ROW LEVEL SECURITY;
+DROP POLICY IF EXISTS goal_task_tenant ON goal_task;
+ALTER TABLE goal_task NO FORCE ROW LEVEL SECURITY;
+ALTER TABLE goal_task DISABLE ROW LEVEL SECURITY;
+DROP POLICY IF EXISTS goal_tenant ON goal;
+ALTER TABLE goal NO FORCE ROW LEVEL SECURITY;
+ALTER TABLE goal DISABLE ROW LEVEL SECURITY;
+DROP POLICY IF EXISTS saved_view_tenant ON saved_view;
+ALTER TABLE saved_view NO FORCE ROW LEVEL SECURITY;
+ALTER TABLE saved_view DISABLE ROW LEVEL SECURITY;
+DROP POLICY IF EXISTS milestone_tenant ON milestone;
+ALTER TABLE milestone NO FORCE ROW LEVEL SECURITY;
+ALTER TABLE milestone DISABLE ROW LEVEL SECURITY;
+DROP POLICY IF EXISTS task_commit_tenant ON task_commit;
+ALTER TABLE task_commit NO FORCE ROW LEVEL SECURITY;
+ALTER TABLE task_commit DISABLE ROW LEVEL SECURITY;
+DROP POLICY IF EXISTS task_pull_request_tenant ON task_pull_request;
+ALTER TABLE task_pull_request NO FORCE ROW LEVEL SECURITY;
+ALTER TABLE task_pull_request DISABLE ROW LEVEL SECURITY;
+DROP POLICY IF EXISTS task_relation_tenant ON task_relation;
+ALTER TABLE task_relation NO FORCE ROW LEVEL SECURITY;
+ALTER TABLE task_relation DISABLE ROW LEVEL SECURITY;
+DROP POLICY IF EXISTS task_dependency_tenant ON task_dependency;
+ALTER TABLE task_dependency NO FORCE ROW LEVEL SECURITY;
+ALTER TABLE task_dependency DISABLE ROW LEVEL SECURITY;
+DROP POLICY IF EXISTS task_status_event_tenant ON task_status_event;
+ALTER TABLE task_status_event NO FORCE ROW LEVEL SECURITY;
+ALTER TABLE task_status_event DISABLE ROW LEVEL SECURITY;
+DROP POLICY IF EXISTS task_tenant ON task;
+ALTER TABLE task NO FORCE ROW LEVEL SECURITY;
+ALTER TABLE task DISABLE ROW LEVEL SECURITY;
+DROP POLICY IF EXISTS project_tenant ON project;
+ALTER TABLE project NO FORCE ROW LEVEL SECURITY;
+ALTER TABLE project DISABLE ROW LEVEL SECURITY;
+DROP FUNCTION IF EXISTS app_tenant_id();
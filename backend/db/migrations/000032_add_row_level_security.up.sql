@@ -0,0 +1,76 @@
+-- 行レベルセキュリティ: リクエストのユーザー（app.user_id）が所有するデータのみ参照・更新できるようにする
+-- app.user_idが未設定の場合（バックグラウンドジョブ・ログイン処理・DB_ROW_LEVEL_SECURITYが無効の場合）は制限しない
+-- 所有者のロールにも適用するためFORCEを指定する（スーパーユーザーとBYPASSRLSを持つロールには適用されない）
+CREATE OR REPLACE FUNCTION app_tenant_id() RETURNS uuid AS $$
+  SELECT NULLIF(current_setting('app.user_id', true), '')::uuid
+$$ LANGUAGE sql STABLE;
+
+ALTER TABLE project ENABLE ROW LEVEL SECURITY;
+ALTER TABLE project FORCE ROW LEVEL SECURITY;
+DROP POLICY IF EXISTS project_tenant ON project;
+CREATE POLICY project_tenant ON project USING (app_tenant_id() IS NULL OR user_id = app_tenant_id());
+
+ALTER TABLE task ENABLE ROW LEVEL SECURITY;
+ALTER TABLE task FORCE ROW LEVEL SECURITY;
+DROP POLICY IF EXISTS task_tenant ON task;
+CREATE POLICY task_tenant ON task USING (app_tenant_id() IS NULL OR project_id IN (SELECT id FROM project));
+
+ALTER TABLE task_status_event ENABLE ROW LEVEL SECURITY;
+ALTER TABLE task_status_event FORCE ROW LEVEL SECURITY;
+DROP POLICY IF EXISTS task_status_event_tenant ON task_status_event;
+CREATE POLICY task_status_event_tenant ON task_status_event USING (app_tenant_id() IS NULL OR project_id IN (SELECT id FROM project));
+
+ALTER TABLE task_dependency ENABLE ROW LEVEL SECURITY;
+ALTER TABLE task_dependency FORCE ROW LEVEL SECURITY;
+DROP POLICY IF EXISTS task_dependency_tenant ON task_dependency;
+CREATE POLICY task_dependency_tenant ON task_dependency USING (app_tenant_id() IS NULL OR project_id IN (SELECT id FROM project));
+
+ALTER TABLE task_relation ENABLE ROW LEVEL SECURITY;
+ALTER TABLE task_relation FORCE ROW LEVEL SECURITY;
+DROP POLICY IF EXISTS task_relation_tenant ON task_relation;
+CREATE POLICY task_relation_tenant ON task_relation USING (app_tenant_id() IS NULL OR task_id IN (SELECT id FROM task));
+
+ALTER TABLE task_pull_request ENABLE ROW LEVEL SECURITY;
+ALTER TABLE task_pull_request FORCE ROW LEVEL SECURITY;
+DROP POLICY IF EXISTS task_pull_request_tenant ON task_pull_request;
+CREATE POLICY task_pull_request_tenant ON task_pull_request USING (app_tenant_id() IS NULL OR task_id IN (SELECT id FROM task));
+
+ALTER TABLE task_commit ENABLE ROW LEVEL SECURITY;
+ALTER TABLE task_commit FORCE ROW LEVEL SECURITY;
+DROP POLICY IF EXISTS task_commit_tenant ON task_commit;
+CREATE POLICY task_commit_tenant ON task_commit USING (app_tenant_id() IS NULL OR project_id IN (SELECT id FROM project));
+
+ALTER TABLE milestone ENABLE ROW LEVEL SECURITY;
+ALTER TABLE milestone FORCE ROW LEVEL SECURITY;
+DROP POLICY IF EXISTS milestone_tenant ON milestone;
+CREATE POLICY milestone_tenant ON milestone USING (app_tenant_id() IS NULL OR project_id IN (SELECT id FROM project));
+
+ALTER TABLE saved_view ENABLE ROW LEVEL SECURITY;
+ALTER TABLE saved_view FORCE ROW LEVEL SECURITY;
+DROP POLICY IF EXISTS saved_view_tenant ON saved_view;
+CREATE POLICY saved_view_tenant ON saved_view USING (app_tenant_id() IS NULL OR user_id = app_tenant_id());
+
+ALTER TABLE goal ENABLE ROW LEVEL SECURITY;
+ALTER TABLE goal FORCE ROW LEVEL SECURITY;
+DROP POLICY IF EXISTS goal_tenant ON goal;
+CREATE POLICY goal_tenant ON goal USING (app_tenant_id() IS NULL OR project_id IN (SELECT id FROM project));
+
+ALTER TABLE goal_task ENABLE ROW LEVEL SECURITY;
+ALTER TABLE goal_task FORCE ROW LEVEL SECURITY;
+DROP POLICY IF EXISTS goal_task_tenant ON goal_task;
+CREATE POLICY goal_task_tenant ON goal_task USING (app_tenant_id() IS NULL OR goal_id IN (SELECT id FROM goal));
+
+ALTER TABLE github_field_mapping ENABLE ROW LEVEL SECURITY;
+ALTER TABLE github_field_mapping FORCE ROW LEVEL SECURITY;
+DROP POLICY IF EXISTS github_field_mapping_tenant ON github_field_mapping;
+CREATE POLICY github_field_mapping_tenant ON github_field_mapping USING (app_tenant_id() IS NULL OR project_id IN (SELECT id FROM project));
+
+ALTER TABLE user_settings ENABLE ROW LEVEL SECURITY;
+ALTER TABLE user_settings FORCE ROW LEVEL SECURITY;
+DROP POLICY IF EXISTS user_settings_tenant ON user_settings;
+CREATE POLICY user_settings_tenant ON user_settings USING (app_tenant_id() IS NULL OR user_id = app_tenant_id());
+
+ALTER TABLE report_export ENABLE ROW LEVEL SECURITY;
+ALTER TABLE report_export FORCE ROW LEVEL SECURITY;
+DROP POLICY IF EXISTS report_export_tenant ON report_export;
+CREATE POLICY report_export_tenant ON report_export USING (app_tenant_id() IS NULL OR user_id = app_tenant_id());
@@ -0,0 +1,20 @@
+-- 変更と同じトランザクションで書き込むドメインイベントのアウトボックス
+-- seqは書き込んだ順の通し番号で、同じ集約のイベントはこの順に配信する
+CREATE TABLE IF NOT EXISTS outbox_event (
+  id uuid PRIMARY KEY,
+  seq BIGSERIAL NOT NULL UNIQUE,
+  event_type VARCHAR(64) NOT NULL,
+  aggregate_type VARCHAR(32) NOT NULL,
+  aggregate_id VARCHAR(255) NOT NULL,
+  payload JSONB NOT NULL,
+  status VARCHAR(16) NOT NULL DEFAULT 'pending',
+  attempts INT NOT NULL DEFAULT 0,
+  last_error TEXT,
+  next_attempt_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  occurred_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  published_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_outbox_event_due ON outbox_event(status, next_attempt_at);
+CREATE INDEX IF NOT EXISTS idx_outbox_event_aggregate ON outbox_event(aggregate_type, aggregate_id, seq) WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS idx_outbox_event_published ON outbox_event(published_at) WHERE status = 'published';
@@ -0,0 +1,5 @@
+DROP POLICY IF EXISTS todos_tenant ON todos;
+ALTER TABLE todos NO FORCE ROW LEVEL SECURITY;
+ALTER TABLE todos DISABLE ROW LEVEL SECURITY;
+DROP INDEX IF EXISTS idx_todos_user_id;
+ALTER TABLE todos DROP COLUMN IF EXISTS user_id;
@@ -0,0 +1,18 @@
+-- TODOの所有者: TODOは作成したユーザーのみ参照・更新できる
+-- 所有者のない既存のTODO（以前のバージョンで作成したもの）はどのユーザーからも参照できない
+CREATE TABLE IF NOT EXISTS todos (
+  id uuid PRIMARY KEY,
+  title VARCHAR(200) NOT NULL,
+  description TEXT NOT NULL DEFAULT '',
+  completed BOOLEAN NOT NULL DEFAULT FALSE,
+  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+ALTER TABLE todos ADD COLUMN IF NOT EXISTS user_id uuid REFERENCES users(id) ON DELETE CASCADE;
+CREATE INDEX IF NOT EXISTS idx_todos_user_id ON todos(user_id);
+
+ALTER TABLE todos ENABLE ROW LEVEL SECURITY;
+ALTER TABLE todos FORCE ROW LEVEL SECURITY;
+DROP POLICY IF EXISTS todos_tenant ON todos;
+CREATE POLICY todos_tenant ON todos USING (app_tenant_id() IS NULL OR user_id = app_tenant_id());
@@ -0,0 +1 @@
+ALTER TABLE task DROP CONSTRAINT IF EXISTS task_priority_range;
@@ -0,0 +1,6 @@
+-- タスクの優先度を定義済みの値（0: Low, 1: Medium, 2: High）に制限する
+-- 範囲外の値は作成時の既定値（Medium）にする
+UPDATE task SET priority = 1 WHERE priority NOT BETWEEN 0 AND 2;
+ALTER TABLE task ALTER COLUMN priority SET DEFAULT 1;
+ALTER TABLE task DROP CONSTRAINT IF EXISTS task_priority_range;
+ALTER TABLE task ADD CONSTRAINT task_priority_range CHECK (priority BETWEEN 0 AND 2);
@@ -0,0 +1,13 @@
+DO $$
+DECLARE
+  col record;
+BEGIN
+  FOR col IN
+    SELECT table_name, column_name
+    FROM information_schema.columns
+    WHERE table_schema = current_schema() AND data_type = 'timestamp with time zone'
+  LOOP
+    EXECUTE format('ALTER TABLE %I ALTER COLUMN %I TYPE timestamp USING %I AT TIME ZONE ''UTC''',
+      col.table_name, col.column_name, col.column_name);
+  END LOOP;
+END $$;
@@ -0,0 +1,15 @@
+-- 日時の列をタイムゾーン付き（timestamptz）にする
+-- タイムゾーンなしの列はリクエストのオフセットを捨てて保存していたため、既存の値はUTCとして変換する
+DO $$
+DECLARE
+  col record;
+BEGIN
+  FOR col IN
+    SELECT table_name, column_name
+    FROM information_schema.columns
+    WHERE table_schema = current_schema() AND data_type = 'timestamp without time zone'
+  LOOP
+    EXECUTE format('ALTER TABLE %I ALTER COLUMN %I TYPE timestamptz USING %I AT TIME ZONE ''UTC''',
+      col.table_name, col.column_name, col.column_name);
+  END LOOP;
+END $$;
@@ -0,0 +1,2 @@
+ALTER TABLE task DROP CONSTRAINT IF EXISTS task_project_github_item_unique;
+ALTER TABLE project DROP CONSTRAINT IF EXISTS project_user_title_unique;
@@ -0,0 +1,13 @@
+-- プロジェクトのタイトルはユーザーごと、GitHubのItemはプロジェクトごとに一意にする
+-- 既存の重複は、2件目以降のプロジェクトのタイトルに連番を付け、2件目以降のタスクのGitHubのItemとの連携を外す
+UPDATE project p SET title = p.title || ' (' || d.n || ')'
+FROM (SELECT id, ROW_NUMBER() OVER (PARTITION BY user_id, title ORDER BY created_at, id) AS n FROM project) d
+WHERE p.id = d.id AND d.n > 1;
+ALTER TABLE project DROP CONSTRAINT IF EXISTS project_user_title_unique;
+ALTER TABLE project ADD CONSTRAINT project_user_title_unique UNIQUE (user_id, title);
+
+UPDATE task t SET github_item_id = NULL
+FROM (SELECT id, ROW_NUMBER() OVER (PARTITION BY project_id, github_item_id ORDER BY created_at, id) AS n FROM task WHERE github_item_id IS NOT NULL) d
+WHERE t.id = d.id AND d.n > 1;
+ALTER TABLE task DROP CONSTRAINT IF EXISTS task_project_github_item_unique;
+ALTER TABLE task ADD CONSTRAINT task_project_github_item_unique UNIQUE (project_id, github_item_id);
@@ -0,0 +1,10 @@
+-- メールアドレスを大文字・小文字を区別せずに一意にする（異なるプロバイダーの同時の初回ログインで別のユーザーを作らない）
+-- 大文字・小文字違いの重複が既にある場合は統合が必要なため、インデックスを作らずに警告する
+DO $$
+BEGIN
+  IF EXISTS (SELECT 1 FROM users GROUP BY lower(email) HAVING COUNT(*) > 1) THEN
+    RAISE WARNING 'users has emails that differ only in case, skipping users_email_lower_unique';
+  ELSE
+    CREATE UNIQUE INDEX IF NOT EXISTS users_email_lower_unique ON users (lower(email));
+  END IF;
+END $$;
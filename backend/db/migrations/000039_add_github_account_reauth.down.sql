@@ -0,0 +1,2 @@
+ALTER TABLE github_account DROP COLUMN IF EXISTS reauth_reason;
+ALTER TABLE github_account DROP COLUMN IF EXISTS reauth_required_at;
@@ -0,0 +1,3 @@
+-- GitHubのトークンが失効・取り消された（APIが401を返した）ことを記録し、再認証を促す
+ALTER TABLE github_account ADD COLUMN IF NOT EXISTS reauth_required_at TIMESTAMPTZ;
+ALTER TABLE github_account ADD COLUMN IF NOT EXISTS reauth_reason VARCHAR;
@@ -0,0 +1,4 @@
+-- 連携時にGitHubで確認した所有者の種類（user・org・repo）。GitHub Projectを検索するルートのクエリを決める
+-- リポジトリのProjectは種類が明らかなため埋め、それ以外は未確認（NULL）としてユーザーのProjectとして検索する
+ALTER TABLE project ADD COLUMN IF NOT EXISTS github_owner_type VARCHAR(16);
+UPDATE project SET github_owner_type = 'repo' WHERE github_repo_project AND github_owner_type IS NULL;
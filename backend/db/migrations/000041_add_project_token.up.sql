@@ -0,0 +1,21 @@
+-- CIなどの外部の自動化に発行する、1つのプロジェクトに限定したAPIトークン（トークン自体は保存せずハッシュのみ）
+CREATE TABLE IF NOT EXISTS project_token (
+  id uuid PRIMARY KEY,
+  project_id uuid NOT NULL,
+  user_id uuid NOT NULL,
+  name VARCHAR(100) NOT NULL,
+  scope VARCHAR(16) NOT NULL,
+  token_hash VARCHAR(64) NOT NULL UNIQUE,
+  expires_at TIMESTAMPTZ,
+  last_used_at TIMESTAMPTZ,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  CONSTRAINT project_token_project_fk FOREIGN KEY (project_id) REFERENCES project(id) ON DELETE CASCADE,
+  CONSTRAINT project_token_user_fk FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_project_token_project_id ON project_token(project_id);
+
+ALTER TABLE project_token ENABLE ROW LEVEL SECURITY;
+ALTER TABLE project_token FORCE ROW LEVEL SECURITY;
+DROP POLICY IF EXISTS project_token_tenant ON project_token;
+CREATE POLICY project_token_tenant ON project_token USING (app_tenant_id() IS NULL OR user_id = app_tenant_id());
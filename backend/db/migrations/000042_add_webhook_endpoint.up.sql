@@ -0,0 +1,51 @@
+-- プロジェクトのドメインイベントを外部に通知するWebhookの送信先（secretは送信時の署名に使うため平文で保存する）
+CREATE TABLE IF NOT EXISTS webhook_endpoint (
+  id uuid PRIMARY KEY,
+  project_id uuid NOT NULL,
+  user_id uuid NOT NULL,
+  url VARCHAR(2000) NOT NULL,
+  secret VARCHAR(100) NOT NULL,
+  event_types TEXT[] NOT NULL DEFAULT '{}',
+  enabled BOOLEAN NOT NULL DEFAULT TRUE,
+  consecutive_failures INT NOT NULL DEFAULT 0,
+  disabled_at TIMESTAMPTZ,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  CONSTRAINT webhook_endpoint_project_fk FOREIGN KEY (project_id) REFERENCES project(id) ON DELETE CASCADE,
+  CONSTRAINT webhook_endpoint_user_fk FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_webhook_endpoint_project_id ON webhook_endpoint(project_id);
+
+-- 送信先ごとの配信（送信待ちのキューと配信ログを兼ねる）。同じイベントは送信先ごとに1件だけ作る
+CREATE TABLE IF NOT EXISTS webhook_endpoint_delivery (
+  id uuid PRIMARY KEY,
+  endpoint_id uuid NOT NULL,
+  user_id uuid NOT NULL,
+  event_id uuid NOT NULL,
+  event_type VARCHAR(64) NOT NULL,
+  payload JSONB NOT NULL,
+  status VARCHAR(16) NOT NULL,
+  attempts INT NOT NULL DEFAULT 0,
+  response_status INT,
+  last_error TEXT,
+  next_attempt_at TIMESTAMPTZ NOT NULL,
+  delivered_at TIMESTAMPTZ,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  CONSTRAINT webhook_endpoint_delivery_endpoint_fk FOREIGN KEY (endpoint_id) REFERENCES webhook_endpoint(id) ON DELETE CASCADE,
+  CONSTRAINT webhook_endpoint_delivery_event_unique UNIQUE (endpoint_id, event_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_webhook_endpoint_delivery_due ON webhook_endpoint_delivery(next_attempt_at) WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS idx_webhook_endpoint_delivery_endpoint ON webhook_endpoint_delivery(endpoint_id, created_at DESC);
+
+ALTER TABLE webhook_endpoint ENABLE ROW LEVEL SECURITY;
+ALTER TABLE webhook_endpoint FORCE ROW LEVEL SECURITY;
+DROP POLICY IF EXISTS webhook_endpoint_tenant ON webhook_endpoint;
+CREATE POLICY webhook_endpoint_tenant ON webhook_endpoint USING (app_tenant_id() IS NULL OR user_id = app_tenant_id());
+
+ALTER TABLE webhook_endpoint_delivery ENABLE ROW LEVEL SECURITY;
+ALTER TABLE webhook_endpoint_delivery FORCE ROW LEVEL SECURITY;
+DROP POLICY IF EXISTS webhook_endpoint_delivery_tenant ON webhook_endpoint_delivery;
+CREATE POLICY webhook_endpoint_delivery_tenant ON webhook_endpoint_delivery USING (app_tenant_id() IS NULL OR user_id = app_tenant_id());
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_task_github_item_id;
+DROP INDEX IF EXISTS idx_task_github_issue_number;
@@ -0,0 +1,3 @@
+-- GitHubのIssue・ProjectのItemから対応するタスクをプロジェクトをまたいで検索するためのインデックス
+CREATE INDEX IF NOT EXISTS idx_task_github_issue_number ON task(github_issue_number) WHERE github_issue_number IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_task_github_item_id ON task(github_item_id) WHERE github_item_id IS NOT NULL;
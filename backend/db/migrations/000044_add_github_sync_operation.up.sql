@@ -0,0 +1,26 @@
+-- GitHubに障害がある（5xx・接続できない）間に保留したGitHubへの変更（接続が戻った後にタスクごとに作成順で再実行する）
+-- 同期は実行時点のタスクの内容を反映するため、同じタスクの同じ種類の変更は1件にまとめる
+CREATE TABLE IF NOT EXISTS github_sync_operation (
+  id uuid PRIMARY KEY,
+  user_id uuid NOT NULL,
+  project_id uuid NOT NULL,
+  task_id uuid NOT NULL,
+  operation VARCHAR(32) NOT NULL,
+  attempts INT NOT NULL DEFAULT 0,
+  last_error TEXT,
+  next_attempt_at TIMESTAMPTZ NOT NULL,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  CONSTRAINT github_sync_operation_user_fk FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+  CONSTRAINT github_sync_operation_project_fk FOREIGN KEY (project_id) REFERENCES project(id) ON DELETE CASCADE,
+  CONSTRAINT github_sync_operation_task_fk FOREIGN KEY (task_id) REFERENCES task(id) ON DELETE CASCADE,
+  CONSTRAINT github_sync_operation_task_unique UNIQUE (task_id, operation)
+);
+
+CREATE INDEX IF NOT EXISTS idx_github_sync_operation_due ON github_sync_operation(next_attempt_at);
+CREATE INDEX IF NOT EXISTS idx_github_sync_operation_user ON github_sync_operation(user_id);
+
+ALTER TABLE github_sync_operation ENABLE ROW LEVEL SECURITY;
+ALTER TABLE github_sync_operation FORCE ROW LEVEL SECURITY;
+DROP POLICY IF EXISTS github_sync_operation_tenant ON github_sync_operation;
+CREATE POLICY github_sync_operation_tenant ON github_sync_operation USING (app_tenant_id() IS NULL OR user_id = app_tenant_id());
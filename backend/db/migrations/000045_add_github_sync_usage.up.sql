@@ -0,0 +1,14 @@
+-- ユーザーごとの1時間あたりのGitHubとの同期の回数（GITHUB_SYNC_QUOTA_PER_HOURの上限の判定に使う）
+-- window_startは時間の区切り（毎時0分）で、前の時間の行は次の同期の時に削除する
+CREATE TABLE IF NOT EXISTS github_sync_usage (
+  user_id uuid NOT NULL,
+  window_start TIMESTAMPTZ NOT NULL,
+  count INT NOT NULL DEFAULT 0,
+  PRIMARY KEY (user_id, window_start),
+  CONSTRAINT github_sync_usage_user_fk FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+ALTER TABLE github_sync_usage ENABLE ROW LEVEL SECURITY;
+ALTER TABLE github_sync_usage FORCE ROW LEVEL SECURITY;
+DROP POLICY IF EXISTS github_sync_usage_tenant ON github_sync_usage;
+CREATE POLICY github_sync_usage_tenant ON github_sync_usage USING (app_tenant_id() IS NULL OR user_id = app_tenant_id());
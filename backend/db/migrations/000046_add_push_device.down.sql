@@ -0,0 +1,3 @@
+ALTER TABLE user_settings DROP COLUMN IF EXISTS push_reminders;
+ALTER TABLE user_settings DROP COLUMN IF EXISTS push_status_changes;
+DROP TABLE IF EXISTS push_device;
@@ -0,0 +1,24 @@
+-- モバイル・PWAのクライアントがプッシュ通知を受け取るために登録した端末（FCMの登録トークン・APNsのデバイストークン）
+-- 同じ端末で別のユーザーがログインし直した場合は、後から登録したユーザーにだけ通知する
+CREATE TABLE IF NOT EXISTS push_device (
+  id uuid PRIMARY KEY,
+  user_id uuid NOT NULL,
+  platform VARCHAR(16) NOT NULL,
+  token VARCHAR(512) NOT NULL,
+  name VARCHAR(100),
+  created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  CONSTRAINT push_device_user_fk FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+  CONSTRAINT push_device_token_unique UNIQUE (user_id, token)
+);
+
+CREATE INDEX IF NOT EXISTS idx_push_device_token ON push_device(token);
+
+ALTER TABLE push_device ENABLE ROW LEVEL SECURITY;
+ALTER TABLE push_device FORCE ROW LEVEL SECURITY;
+DROP POLICY IF EXISTS push_device_tenant ON push_device;
+CREATE POLICY push_device_tenant ON push_device USING (app_tenant_id() IS NULL OR user_id = app_tenant_id());
+
+-- 通知の種類ごとのプッシュ通知の設定（端末を登録したユーザーにだけ送るため既定で有効にする）
+ALTER TABLE user_settings ADD COLUMN IF NOT EXISTS push_status_changes BOOLEAN NOT NULL DEFAULT TRUE;
+ALTER TABLE user_settings ADD COLUMN IF NOT EXISTS push_reminders BOOLEAN NOT NULL DEFAULT TRUE;
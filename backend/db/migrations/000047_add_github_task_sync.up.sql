@@ -0,0 +1,21 @@
+-- タスクとGitHub ProjectのItemを最後に一致させた時点（双方向の同期で変更があった側を判定する基準）
+-- local_updated_atはその時点のタスクのupdated_at、remote_updated_atはその時点のItemの更新日時（GitHubの時刻）
+-- remote_updated_atがNULLの場合はGitHubから取り込んだことがない（次の取り込みでGitHubの変更として扱う）
+CREATE TABLE IF NOT EXISTS github_task_sync (
+  task_id uuid PRIMARY KEY,
+  user_id uuid NOT NULL,
+  project_id uuid NOT NULL,
+  local_updated_at TIMESTAMPTZ NOT NULL,
+  remote_updated_at TIMESTAMPTZ,
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  CONSTRAINT github_task_sync_task_fk FOREIGN KEY (task_id) REFERENCES task(id) ON DELETE CASCADE,
+  CONSTRAINT github_task_sync_user_fk FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+  CONSTRAINT github_task_sync_project_fk FOREIGN KEY (project_id) REFERENCES project(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_github_task_sync_project ON github_task_sync(project_id);
+
+ALTER TABLE github_task_sync ENABLE ROW LEVEL SECURITY;
+ALTER TABLE github_task_sync FORCE ROW LEVEL SECURITY;
+DROP POLICY IF EXISTS github_task_sync_tenant ON github_task_sync;
+CREATE POLICY github_task_sync_tenant ON github_task_sync USING (app_tenant_id() IS NULL OR user_id = app_tenant_id());
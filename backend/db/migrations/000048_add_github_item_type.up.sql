@@ -0,0 +1,2 @@
+-- 未同期のタスクをGitHub Projectに追加する時のItemの種類（draft: Draft Issue、issue: 連携先のリポジトリに作成したIssue）
+ALTER TABLE project ADD COLUMN IF NOT EXISTS github_item_type VARCHAR(16) NOT NULL DEFAULT 'draft';
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_task_project_created_at;
+DROP INDEX IF EXISTS idx_task_project_end_date;
+DROP INDEX IF EXISTS idx_task_project_status_priority;
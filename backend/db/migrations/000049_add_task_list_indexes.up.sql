@@ -0,0 +1,4 @@
+-- タスク一覧の絞り込み（ステータス・優先度・終了日）と既定の並び順（作成日時の降順）のための索引
+CREATE INDEX IF NOT EXISTS idx_task_project_status_priority ON task(project_id, status, priority);
+CREATE INDEX IF NOT EXISTS idx_task_project_end_date ON task(project_id, end_date);
+CREATE INDEX IF NOT EXISTS idx_task_project_created_at ON task(project_id, created_at DESC, id);
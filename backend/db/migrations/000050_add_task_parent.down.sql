@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_task_parent_task_id;
+ALTER TABLE task DROP COLUMN IF EXISTS parent_task_id;
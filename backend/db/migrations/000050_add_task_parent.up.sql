@@ -0,0 +1,6 @@
+-- サブタスクの親タスク（親タスクを削除した場合は親のないタスクに戻す）
+-- バックアップの復元で親タスクより先にサブタスクを書き込めるよう、外部キーはコミット時に確認する
+ALTER TABLE task ADD COLUMN IF NOT EXISTS parent_task_id uuid
+  REFERENCES task(id) ON DELETE SET NULL DEFERRABLE INITIALLY DEFERRED;
+
+CREATE INDEX IF NOT EXISTS idx_task_parent_task_id ON task(parent_task_id);
@@ -0,0 +1,5 @@
+ALTER TABLE user_settings DROP COLUMN IF EXISTS webhook_reminders;
+ALTER TABLE user_settings DROP COLUMN IF EXISTS email_reminders;
+ALTER TABLE user_settings DROP COLUMN IF EXISTS reminder_days_before;
+DROP INDEX IF EXISTS idx_task_end_date;
+DROP TABLE IF EXISTS task_reminder;
@@ -0,0 +1,26 @@
+-- 期限が近いタスクのリマインダーを通知先ごとに送信した記録（同じ終了日のリマインダーを重複して送らない）
+-- 終了日を変更したタスクは新しい終了日の記録がないため、改めてリマインダーを送る
+CREATE TABLE IF NOT EXISTS task_reminder (
+  task_id uuid NOT NULL,
+  channel VARCHAR(16) NOT NULL,
+  due_date TIMESTAMP NOT NULL,
+  user_id uuid NOT NULL,
+  sent_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  CONSTRAINT task_reminder_pkey PRIMARY KEY (task_id, channel, due_date),
+  CONSTRAINT task_reminder_task_fk FOREIGN KEY (task_id) REFERENCES task(id) ON DELETE CASCADE,
+  CONSTRAINT task_reminder_user_fk FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_task_reminder_user ON task_reminder(user_id);
+-- リマインダーの対象（終了日が近い未完了のタスク）をすべてのプロジェクトから探す
+CREATE INDEX IF NOT EXISTS idx_task_end_date ON task(end_date);
+
+ALTER TABLE task_reminder ENABLE ROW LEVEL SECURITY;
+ALTER TABLE task_reminder FORCE ROW LEVEL SECURITY;
+DROP POLICY IF EXISTS task_reminder_tenant ON task_reminder;
+CREATE POLICY task_reminder_tenant ON task_reminder USING (app_tenant_id() IS NULL OR user_id = app_tenant_id());
+
+-- リマインダーの設定（終了日の何日前から通知するかと、メール・Webhookの通知先。プッシュ通知はpush_remindersで設定する）
+ALTER TABLE user_settings ADD COLUMN IF NOT EXISTS reminder_days_before INTEGER NOT NULL DEFAULT 1;
+ALTER TABLE user_settings ADD COLUMN IF NOT EXISTS email_reminders BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE user_settings ADD COLUMN IF NOT EXISTS webhook_reminders BOOLEAN NOT NULL DEFAULT FALSE;
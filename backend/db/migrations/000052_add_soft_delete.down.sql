@@ -0,0 +1,12 @@
+-- ゴミ箱のものは元に戻せなくなるため、先に完全に削除する
+DELETE FROM project WHERE deleted_at IS NOT NULL;
+DELETE FROM task WHERE deleted_at IS NOT NULL;
+DELETE FROM todos WHERE deleted_at IS NOT NULL;
+
+DROP INDEX IF EXISTS idx_todos_deleted_at;
+DROP INDEX IF EXISTS idx_task_deleted_at;
+DROP INDEX IF EXISTS idx_project_deleted_at;
+
+ALTER TABLE todos DROP COLUMN IF EXISTS deleted_at;
+ALTER TABLE task DROP COLUMN IF EXISTS deleted_at;
+ALTER TABLE project DROP COLUMN IF EXISTS deleted_at;
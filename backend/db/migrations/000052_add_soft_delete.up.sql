@@ -0,0 +1,10 @@
+-- 削除したプロジェクト・タスク・TODOをゴミ箱に移した日時（NULLは削除されていない）
+-- プロジェクトと一緒にゴミ箱に移したタスクにはプロジェクトと同じ日時を記録し、プロジェクトを元に戻す時に一緒に戻す
+ALTER TABLE project ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ;
+ALTER TABLE task ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ;
+ALTER TABLE todos ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ;
+
+-- ゴミ箱の一覧と、保持期間を過ぎたものの完全な削除のための索引
+CREATE INDEX IF NOT EXISTS idx_project_deleted_at ON project(deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_task_deleted_at ON task(deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_todos_deleted_at ON todos(deleted_at) WHERE deleted_at IS NOT NULL;
@@ -0,0 +1,23 @@
+-- プロジェクト・タスク・マイルストーンへの変更の記録（アクティビティログ）
+-- actor_idは変更したユーザーで、GitHubからの反映等のシステムの処理による変更ではNULLにする
+-- changesは変更したフィールドごとの変更前後の値（{"title": {"from": "...", "to": "..."}}）
+CREATE TABLE IF NOT EXISTS activity (
+  id uuid PRIMARY KEY DEFAULT uuid_generate_v7(),
+  project_id uuid NOT NULL,
+  actor_id uuid,
+  entity_type VARCHAR(16) NOT NULL,
+  entity_id uuid NOT NULL,
+  action VARCHAR(16) NOT NULL,
+  changes JSONB NOT NULL DEFAULT '{}',
+  occurred_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  CONSTRAINT activity_project_fk FOREIGN KEY (project_id) REFERENCES project(id) ON DELETE CASCADE,
+  CONSTRAINT activity_actor_fk FOREIGN KEY (actor_id) REFERENCES users(id) ON DELETE SET NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_activity_project_occurred_at ON activity(project_id, occurred_at DESC, id);
+CREATE INDEX IF NOT EXISTS idx_activity_actor_id ON activity(actor_id);
+
+ALTER TABLE activity ENABLE ROW LEVEL SECURITY;
+ALTER TABLE activity FORCE ROW LEVEL SECURITY;
+DROP POLICY IF EXISTS activity_tenant ON activity;
+CREATE POLICY activity_tenant ON activity USING (app_tenant_id() IS NULL OR project_id IN (SELECT id FROM project));
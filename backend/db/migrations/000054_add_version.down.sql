@@ -0,0 +1,2 @@
+ALTER TABLE project DROP COLUMN IF EXISTS version;
+ALTER TABLE task DROP COLUMN IF EXISTS version;
@@ -0,0 +1,3 @@
+-- タスク・プロジェクトの楽観的排他制御のバージョン（更新のたびに1ずつ増やし、更新元と異なる場合は競合として扱う）
+ALTER TABLE task ADD COLUMN IF NOT EXISTS version INTEGER NOT NULL DEFAULT 1;
+ALTER TABLE project ADD COLUMN IF NOT EXISTS version INTEGER NOT NULL DEFAULT 1;
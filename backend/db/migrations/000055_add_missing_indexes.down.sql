@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_task_status;
+DROP INDEX IF EXISTS idx_task_project_id;
+DROP INDEX IF EXISTS idx_project_user_id;
+DROP INDEX IF EXISTS idx_google_account_user_id;
+DROP INDEX IF EXISTS idx_github_account_user_id;
+DROP INDEX IF EXISTS idx_users_email;
@@ -0,0 +1,7 @@
+-- InitSchemaでのみ作成していたインデックス（マイグレーションで作成したデータベースと揃える）
+CREATE INDEX IF NOT EXISTS idx_users_email ON users(email);
+CREATE INDEX IF NOT EXISTS idx_github_account_user_id ON github_account(user_id);
+CREATE INDEX IF NOT EXISTS idx_google_account_user_id ON google_account(user_id);
+CREATE INDEX IF NOT EXISTS idx_project_user_id ON project(user_id);
+CREATE INDEX IF NOT EXISTS idx_task_project_id ON task(project_id);
+CREATE INDEX IF NOT EXISTS idx_task_status ON task(status);
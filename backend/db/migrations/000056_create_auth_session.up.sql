@@ -0,0 +1,17 @@
+-- サーバー側に保存するCookieのセッション（Cookieにはトークンのみを保存し、idはトークンのSHA-256）
+-- ログインセッションの記録（user_session）を削除すると、そのセッションも失効する
+CREATE TABLE IF NOT EXISTS auth_session (
+  id VARCHAR(64) PRIMARY KEY,
+  user_id uuid,
+  user_session_id uuid,
+  data JSONB NOT NULL DEFAULT '{}',
+  expires_at TIMESTAMP NOT NULL,
+  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  CONSTRAINT auth_session_user_fk FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+  CONSTRAINT auth_session_user_session_fk FOREIGN KEY (user_session_id) REFERENCES user_session(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_auth_session_user_id ON auth_session(user_id);
+CREATE INDEX IF NOT EXISTS idx_auth_session_user_session_id ON auth_session(user_session_id);
+CREATE INDEX IF NOT EXISTS idx_auth_session_expires_at ON auth_session(expires_at);
@@ -0,0 +1,11 @@
+-- IdPに送ったAuthnRequest（ResponseのInResponseToの検証に使う）
+-- IdPはクロスサイトのPOSTでACSに遷移しCookieが送られないため、Responseとともに返るRelayStateのハッシュで検索する
+-- 検証に使ったAuthnRequestは削除し、同じResponseを再送できないようにする
+CREATE TABLE IF NOT EXISTS saml_request (
+  relay_state_hash VARCHAR(64) PRIMARY KEY,
+  request_id VARCHAR(64) NOT NULL,
+  expires_at TIMESTAMPTZ NOT NULL,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_saml_request_expires_at ON saml_request(expires_at);
@@ -0,0 +1,73 @@
+DROP POLICY IF EXISTS project_tenant ON project;
+CREATE POLICY project_tenant ON project USING (app_tenant_id() IS NULL OR user_id = app_tenant_id());
+
+DROP POLICY IF EXISTS task_tenant ON task;
+CREATE POLICY task_tenant ON task USING (app_tenant_id() IS NULL OR project_id IN (SELECT id FROM project));
+
+DROP POLICY IF EXISTS task_status_event_tenant ON task_status_event;
+CREATE POLICY task_status_event_tenant ON task_status_event USING (app_tenant_id() IS NULL OR project_id IN (SELECT id FROM project));
+
+DROP POLICY IF EXISTS task_dependency_tenant ON task_dependency;
+CREATE POLICY task_dependency_tenant ON task_dependency USING (app_tenant_id() IS NULL OR project_id IN (SELECT id FROM project));
+
+DROP POLICY IF EXISTS task_relation_tenant ON task_relation;
+CREATE POLICY task_relation_tenant ON task_relation USING (app_tenant_id() IS NULL OR task_id IN (SELECT id FROM task));
+
+DROP POLICY IF EXISTS task_pull_request_tenant ON task_pull_request;
+CREATE POLICY task_pull_request_tenant ON task_pull_request USING (app_tenant_id() IS NULL OR task_id IN (SELECT id FROM task));
+
+DROP POLICY IF EXISTS task_commit_tenant ON task_commit;
+CREATE POLICY task_commit_tenant ON task_commit USING (app_tenant_id() IS NULL OR project_id IN (SELECT id FROM project));
+
+DROP POLICY IF EXISTS milestone_tenant ON milestone;
+CREATE POLICY milestone_tenant ON milestone USING (app_tenant_id() IS NULL OR project_id IN (SELECT id FROM project));
+
+DROP POLICY IF EXISTS saved_view_tenant ON saved_view;
+CREATE POLICY saved_view_tenant ON saved_view USING (app_tenant_id() IS NULL OR user_id = app_tenant_id());
+
+DROP POLICY IF EXISTS goal_tenant ON goal;
+CREATE POLICY goal_tenant ON goal USING (app_tenant_id() IS NULL OR project_id IN (SELECT id FROM project));
+
+DROP POLICY IF EXISTS goal_task_tenant ON goal_task;
+CREATE POLICY goal_task_tenant ON goal_task USING (app_tenant_id() IS NULL OR goal_id IN (SELECT id FROM goal));
+
+DROP POLICY IF EXISTS github_field_mapping_tenant ON github_field_mapping;
+CREATE POLICY github_field_mapping_tenant ON github_field_mapping USING (app_tenant_id() IS NULL OR project_id IN (SELECT id FROM project));
+
+DROP POLICY IF EXISTS user_settings_tenant ON user_settings;
+CREATE POLICY user_settings_tenant ON user_settings USING (app_tenant_id() IS NULL OR user_id = app_tenant_id());
+
+DROP POLICY IF EXISTS report_export_tenant ON report_export;
+CREATE POLICY report_export_tenant ON report_export USING (app_tenant_id() IS NULL OR user_id = app_tenant_id());
+
+DROP POLICY IF EXISTS todos_tenant ON todos;
+CREATE POLICY todos_tenant ON todos USING (app_tenant_id() IS NULL OR user_id = app_tenant_id());
+
+DROP POLICY IF EXISTS project_token_tenant ON project_token;
+CREATE POLICY project_token_tenant ON project_token USING (app_tenant_id() IS NULL OR user_id = app_tenant_id());
+
+DROP POLICY IF EXISTS webhook_endpoint_tenant ON webhook_endpoint;
+CREATE POLICY webhook_endpoint_tenant ON webhook_endpoint USING (app_tenant_id() IS NULL OR user_id = app_tenant_id());
+
+DROP POLICY IF EXISTS webhook_endpoint_delivery_tenant ON webhook_endpoint_delivery;
+CREATE POLICY webhook_endpoint_delivery_tenant ON webhook_endpoint_delivery USING (app_tenant_id() IS NULL OR user_id = app_tenant_id());
+
+DROP POLICY IF EXISTS github_sync_operation_tenant ON github_sync_operation;
+CREATE POLICY github_sync_operation_tenant ON github_sync_operation USING (app_tenant_id() IS NULL OR user_id = app_tenant_id());
+
+DROP POLICY IF EXISTS github_sync_usage_tenant ON github_sync_usage;
+CREATE POLICY github_sync_usage_tenant ON github_sync_usage USING (app_tenant_id() IS NULL OR user_id = app_tenant_id());
+
+DROP POLICY IF EXISTS push_device_tenant ON push_device;
+CREATE POLICY push_device_tenant ON push_device USING (app_tenant_id() IS NULL OR user_id = app_tenant_id());
+
+DROP POLICY IF EXISTS github_task_sync_tenant ON github_task_sync;
+CREATE POLICY github_task_sync_tenant ON github_task_sync USING (app_tenant_id() IS NULL OR user_id = app_tenant_id());
+
+DROP POLICY IF EXISTS task_reminder_tenant ON task_reminder;
+CREATE POLICY task_reminder_tenant ON task_reminder USING (app_tenant_id() IS NULL OR user_id = app_tenant_id());
+
+DROP POLICY IF EXISTS activity_tenant ON activity;
+CREATE POLICY activity_tenant ON activity USING (app_tenant_id() IS NULL OR project_id IN (SELECT id FROM project));
+
+DROP FUNCTION IF EXISTS app_rls_bypass();
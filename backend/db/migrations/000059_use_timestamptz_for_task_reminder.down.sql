@@ -0,0 +1 @@
+ALTER TABLE task_reminder ALTER COLUMN due_date TYPE TIMESTAMP USING due_date AT TIME ZONE 'UTC';
@@ -0,0 +1,3 @@
+-- リマインダーの送信済みの記録の終了日をタイムゾーン付き（timestamptz）にする
+-- 000051はタイムゾーンなしで作成していたため、既存の値はUTCとして変換する
+ALTER TABLE task_reminder ALTER COLUMN due_date TYPE TIMESTAMPTZ USING due_date AT TIME ZONE 'UTC';
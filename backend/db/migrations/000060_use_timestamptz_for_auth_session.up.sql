@@ -0,0 +1,6 @@
+-- Cookieのセッションの日時をタイムゾーン付き（timestamptz）にする
+-- 000056はタイムゾーンなしで作成していたため、既存の値はUTCとして変換する
+ALTER TABLE auth_session
+  ALTER COLUMN expires_at TYPE TIMESTAMPTZ USING expires_at AT TIME ZONE 'UTC',
+  ALTER COLUMN created_at TYPE TIMESTAMPTZ USING created_at AT TIME ZONE 'UTC',
+  ALTER COLUMN updated_at TYPE TIMESTAMPTZ USING updated_at AT TIME ZONE 'UTC';
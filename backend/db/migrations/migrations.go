@@ -0,0 +1,23 @@
+// Package migrations はデータベーススキーマのマイグレーションファイル（golang-migrate形式）を提供する
+package migrations
+
+import (
+	"embed"
+	"io/fs"
+	"os"
+)
+
+// files はバイナリに埋め込んだマイグレーションファイル
+// イメージにファイルを別にコピーしなくてもマイグレーションを適用できる
+//
+//go:embed *.sql
+var files embed.FS
+
+// FS はマイグレーションファイルを返す
+// dirを指定した場合は埋め込んだファイルの代わりにそのディレクトリのファイルを返す
+func FS(dir string) fs.FS {
+	if dir != "" {
+		return os.DirFS(dir)
+	}
+	return files
+}
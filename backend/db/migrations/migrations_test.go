@@ -0,0 +1,54 @@
+package migrations
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestFSEmbedsAllMigrations(t *testing.T) {
+	onDisk, err := filepath.Glob("*.sql")
+	if err != nil {
+		t.Fatalf("failed to list migrations: %v", err)
+	}
+	embedded, err := fs.Glob(FS(""), "*.sql")
+	if err != nil {
+		t.Fatalf("failed to list embedded migrations: %v", err)
+	}
+	if len(embedded) == 0 || !slices.Equal(embedded, onDisk) {
+		t.Errorf("embedded migrations = %v, want %v", embedded, onDisk)
+	}
+
+	// すべてのバージョンにupとdownがある
+	for _, name := range embedded {
+		if up, ok := strings.CutSuffix(name, ".up.sql"); ok {
+			if !slices.Contains(embedded, up+".down.sql") {
+				t.Errorf("%s has no down migration", name)
+			}
+		} else if down, ok := strings.CutSuffix(name, ".down.sql"); ok {
+			if !slices.Contains(embedded, down+".up.sql") {
+				t.Errorf("%s has no up migration", name)
+			}
+		} else {
+			t.Errorf("%s is not an up or down migration", name)
+		}
+	}
+}
+
+func TestFSUsesDirectory(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "000001_test.up.sql"), []byte("SELECT 1;"), 0o600); err != nil {
+		t.Fatalf("failed to write migration: %v", err)
+	}
+
+	names, err := fs.Glob(FS(dir), "*.sql")
+	if err != nil {
+		t.Fatalf("failed to list migrations: %v", err)
+	}
+	if !slices.Equal(names, []string{"000001_test.up.sql"}) {
+		t.Errorf("migrations = %v, want only the file in the directory", names)
+	}
+}
@@ -0,0 +1,231 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sikigasa/github-task-controller/backend/internal/domain/model"
+	"github.com/sikigasa/github-task-controller/backend/internal/domain/repository"
+	"github.com/sikigasa/github-task-controller/backend/internal/infrastructure/mail"
+)
+
+// accountMergeTTL はアカウント統合の確認の有効期間
+const accountMergeTTL = 30 * time.Minute
+
+// AccountMergeUsecase は同じメールアドレスで別プロバイダーからログインした場合のアカウント統合に関するユースケース
+// メールアドレスの一致だけでは紐付けず、既存ユーザーのメールに送った確認リンクか、既存ユーザーとしてログインした状態での確認を必要とする
+type AccountMergeUsecase struct {
+	mergeRepo            repository.AccountMergeRepository
+	googleAccountRepo    repository.GoogleAccountRepository
+	githubAccountRepo    repository.GithubAccountRepository
+	appleAccountRepo     repository.AppleAccountRepository
+	microsoftAccountRepo repository.MicrosoftAccountRepository
+	sender               mail.Sender
+	baseURL              string
+	tx                   repository.Transactor
+	logger               *slog.Logger
+}
+
+// NewAccountMergeUsecase は新しいAccountMergeUsecaseを作成する
+// baseURLは確認リンクの組み立てに使うAPIの公開URL
+func NewAccountMergeUsecase(
+	mergeRepo repository.AccountMergeRepository,
+	googleAccountRepo repository.GoogleAccountRepository,
+	githubAccountRepo repository.GithubAccountRepository,
+	appleAccountRepo repository.AppleAccountRepository,
+	microsoftAccountRepo repository.MicrosoftAccountRepository,
+	sender mail.Sender,
+	baseURL string,
+	tx repository.Transactor,
+	logger *slog.Logger,
+) *AccountMergeUsecase {
+	return &AccountMergeUsecase{
+		mergeRepo:            mergeRepo,
+		googleAccountRepo:    googleAccountRepo,
+		githubAccountRepo:    githubAccountRepo,
+		appleAccountRepo:     appleAccountRepo,
+		microsoftAccountRepo: microsoftAccountRepo,
+		sender:               sender,
+		baseURL:              baseURL,
+		tx:                   tx,
+		logger:               logger,
+	}
+}
+
+// RequestMerge は既存ユーザーへのアカウント統合の要求を保存し、既存ユーザーのメールアドレスに確認リンクを送る
+// 要求を保存できた場合もログインは行わないため、常にエラー（成功時はErrAccountMergeRequired）を返す
+func (u *AccountMergeUsecase) RequestMerge(ctx context.Context, user *model.User, merge *model.AccountMerge) error {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return fmt.Errorf("failed to generate merge token: %w", err)
+	}
+	token := base64.RawURLEncoding.EncodeToString(b)
+
+	now := time.Now()
+	merge.ID = uuid.New().String()
+	merge.UserID = user.ID
+	merge.TokenHash = hashMergeToken(token)
+	merge.ExpiresAt = now.Add(accountMergeTTL)
+	merge.CreatedAt = now
+	if err := u.mergeRepo.Create(ctx, merge); err != nil {
+		return fmt.Errorf("failed to create account merge: %w", err)
+	}
+
+	msg := mail.Message{
+		To:      user.Email,
+		Subject: "アカウントの統合の確認",
+		Body: fmt.Sprintf("%s のアカウント（%s）でログインしようとしました。このアカウントをあなたのアカウントに統合する場合は、%d分以内に以下のリンクを開いてください。\n\n%s/auth/merge/confirm?token=%s\n\n心当たりがない場合はこのメールを無視してください。アカウントは統合されません。\n",
+			merge.Provider, merge.ProviderEmail, int(accountMergeTTL.Minutes()), u.baseURL, url.QueryEscape(token)),
+	}
+	if err := u.sender.Send(ctx, msg); err != nil {
+		u.logger.ErrorContext(ctx, "failed to send account merge confirmation", "error", err, "merge_id", merge.ID)
+	}
+
+	u.logger.WarnContext(ctx, "account merge requires confirmation", "merge_id", merge.ID, "user_id", user.ID, "provider", merge.Provider)
+	return fmt.Errorf("user %s already exists: %w", user.ID, model.ErrAccountMergeRequired)
+}
+
+// ConfirmByToken はメールで送った確認リンクのトークンでアカウント統合を確認する
+// トークンが不正・期限切れの場合はErrNotFoundを返す
+func (u *AccountMergeUsecase) ConfirmByToken(ctx context.Context, token, clientIP string) (*model.AccountMerge, error) {
+	merge, err := u.mergeRepo.FindByTokenHash(ctx, hashMergeToken(token))
+	if err != nil {
+		return nil, fmt.Errorf("failed to find account merge: %w", err)
+	}
+	if err := u.confirm(ctx, merge, model.AccountMergeViaEmail, clientIP); err != nil {
+		return nil, err
+	}
+	return merge, nil
+}
+
+// ConfirmInSession は既存ユーザーとしてログインした状態でアカウント統合を確認する
+func (u *AccountMergeUsecase) ConfirmInSession(ctx context.Context, userID, id, clientIP string) (*model.AccountMerge, error) {
+	if err := validateResourceID(id); err != nil {
+		return nil, err
+	}
+	merge, err := u.mergeRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find account merge: %w", err)
+	}
+	if merge.UserID != userID {
+		return nil, model.ErrForbidden
+	}
+	if err := u.confirm(ctx, merge, model.AccountMergeViaSession, clientIP); err != nil {
+		return nil, err
+	}
+	return merge, nil
+}
+
+// ListMerges はユーザーへのアカウント統合の要求と確認の記録を一覧する
+func (u *AccountMergeUsecase) ListMerges(ctx context.Context, userID string) ([]*model.AccountMerge, error) {
+	merges, err := u.mergeRepo.FindByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find account merges: %w", err)
+	}
+	return merges, nil
+}
+
+// confirm はプロバイダーのアカウントを既存ユーザーに紐付けて、統合を確認済みとして記録する
+// 紐付けと記録は1つのトランザクションで行い、記録に失敗した場合に確認をやり直すとアカウントが重複して作成されないようにする
+func (u *AccountMergeUsecase) confirm(ctx context.Context, merge *model.AccountMerge, via, clientIP string) error {
+	now := time.Now()
+	if !merge.Pending(now) {
+		return fmt.Errorf("account merge is already confirmed or expired: %w", model.ErrNotFound)
+	}
+
+	ip := model.RoughIPAddress(clientIP)
+	err := u.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if err := u.linkAccount(ctx, merge, now); err != nil {
+			return err
+		}
+		if err := u.mergeRepo.Confirm(ctx, merge.ID, via, ip, now); err != nil {
+			return fmt.Errorf("failed to confirm account merge: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	merge.ConfirmedAt = &now
+	merge.ConfirmedVia = via
+	merge.ConfirmedIP = ip
+
+	u.logger.InfoContext(ctx, "accounts merged", "merge_id", merge.ID, "user_id", merge.UserID, "provider", merge.Provider, "via", via)
+	return nil
+}
+
+// linkAccount は統合の要求に保持したトークンでプロバイダーのアカウントを作成する
+func (u *AccountMergeUsecase) linkAccount(ctx context.Context, merge *model.AccountMerge, now time.Time) error {
+	var err error
+	switch merge.Provider {
+	case "google":
+		err = u.googleAccountRepo.Create(ctx, &model.GoogleAccount{
+			ID:                uuid.New().String(),
+			UserID:            merge.UserID,
+			Provider:          merge.Provider,
+			ProviderAccountID: merge.ProviderAccountID,
+			AccessToken:       merge.AccessToken,
+			RefreshToken:      merge.RefreshToken,
+			ExpiresAt:         merge.TokenExpiresAt,
+			CreatedAt:         now,
+			UpdatedAt:         now,
+		})
+	case "github":
+		err = u.githubAccountRepo.Create(ctx, &model.GithubAccount{
+			ID:                uuid.New().String(),
+			UserID:            merge.UserID,
+			Provider:          merge.Provider,
+			ProviderAccountID: merge.ProviderAccountID,
+			AccessToken:       merge.AccessToken,
+			RefreshToken:      merge.RefreshToken,
+			ExpiresAt:         merge.TokenExpiresAt,
+			CreatedAt:         now,
+			UpdatedAt:         now,
+		})
+	case "microsoft":
+		err = u.microsoftAccountRepo.Create(ctx, &model.MicrosoftAccount{
+			UserID:            merge.UserID,
+			Provider:          merge.Provider,
+			ProviderAccountID: merge.ProviderAccountID,
+			AccessToken:       merge.AccessToken,
+			RefreshToken:      merge.RefreshToken,
+			ExpiresAt:         merge.TokenExpiresAt,
+			CreatedAt:         now,
+			UpdatedAt:         now,
+		})
+	case "apple":
+		// 転送用アドレスは統合の対象にしないため、常に実アドレス
+		err = u.appleAccountRepo.Create(ctx, &model.AppleAccount{
+			UserID:            merge.UserID,
+			Provider:          merge.Provider,
+			ProviderAccountID: merge.ProviderAccountID,
+			Email:             merge.ProviderEmail,
+			AccessToken:       merge.AccessToken,
+			RefreshToken:      merge.RefreshToken,
+			ExpiresAt:         merge.TokenExpiresAt,
+			CreatedAt:         now,
+			UpdatedAt:         now,
+		})
+	default:
+		return fmt.Errorf("unsupported provider: %s: %w", merge.Provider, model.ErrInvalidInput)
+	}
+	if err != nil {
+		u.logger.ErrorContext(ctx, "failed to link account", "error", err, "merge_id", merge.ID, "provider", merge.Provider)
+		return fmt.Errorf("failed to create %s account: %w", merge.Provider, err)
+	}
+	return nil
+}
+
+// hashMergeToken は保存・検索に使う確認トークンのハッシュを返す
+func hashMergeToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,107 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sikigasa/github-task-controller/backend/internal/domain/model"
+	"github.com/sikigasa/github-task-controller/backend/internal/domain/repository"
+)
+
+const (
+	// DefaultActivityListLimit はアクティビティ一覧でlimitを省略した場合の件数
+	DefaultActivityListLimit = 50
+	// MaxActivityListLimit はアクティビティ一覧で1回に取得できる最大件数
+	MaxActivityListLimit = 200
+)
+
+// ActorResolver はコンテキストから操作したユーザーのIDを取得する（ユーザーの操作でない場合はfalse）
+type ActorResolver func(ctx context.Context) (string, bool)
+
+// ActivityRecorder はプロジェクト内のリソースへの変更をアクティビティログに記録する
+type ActivityRecorder interface {
+	// Record はentityIDのリソースのbeforeからafterへの変更を記録する（作成・復元ではbefore、削除ではafterをnilにする）
+	// 変更と同じTransactor.WithinTxのトランザクション内で呼び出し、変更したフィールドがない更新は記録しない
+	Record(ctx context.Context, projectID string, entity model.ActivityEntity, entityID string, action model.ActivityAction, before, after any) error
+}
+
+// ActivityUsecase はアクティビティログに関するユースケース
+type ActivityUsecase struct {
+	activityRepo repository.ActivityRepository
+	projectRepo  repository.ProjectRepository
+	actor        ActorResolver
+	logger       *slog.Logger
+}
+
+// NewActivityUsecase は新しいActivityUsecaseを作成する
+// actorは変更を記録する時に操作したユーザーを特定するために使う
+func NewActivityUsecase(activityRepo repository.ActivityRepository, projectRepo repository.ProjectRepository, actor ActorResolver, logger *slog.Logger) *ActivityUsecase {
+	return &ActivityUsecase{
+		activityRepo: activityRepo,
+		projectRepo:  projectRepo,
+		actor:        actor,
+		logger:       logger,
+	}
+}
+
+// Record はリソースへの変更をアクティビティログに記録する
+func (u *ActivityUsecase) Record(ctx context.Context, projectID string, entity model.ActivityEntity, entityID string, action model.ActivityAction, before, after any) error {
+	changes, err := model.ActivityChanges(before, after)
+	if err != nil {
+		return err
+	}
+	if action == model.ActivityActionUpdate && len(changes) == 0 {
+		return nil
+	}
+
+	activity := &model.Activity{
+		ID:         uuid.New().String(),
+		ProjectID:  projectID,
+		EntityType: entity,
+		EntityID:   entityID,
+		Action:     action,
+		Changes:    changes,
+		OccurredAt: time.Now(),
+	}
+	if actorID, ok := u.actor(ctx); ok {
+		activity.ActorID = &actorID
+	}
+
+	return u.activityRepo.Create(ctx, activity)
+}
+
+// List はuserIDが所有するプロジェクトのアクティビティを新しい順に取得する
+// opts.Limitが0の場合はDefaultActivityListLimit件を取得する
+func (u *ActivityUsecase) List(ctx context.Context, userID, projectID string, opts model.ListOptions) ([]*model.Activity, error) {
+	if opts.Limit < 0 || opts.Limit > MaxActivityListLimit {
+		return nil, fmt.Errorf("limit must be between 0 and %d: %w", MaxActivityListLimit, model.ErrInvalidInput)
+	}
+	if opts.Offset < 0 {
+		return nil, fmt.Errorf("offset must not be negative: %w", model.ErrInvalidInput)
+	}
+	if opts.Limit == 0 {
+		opts.Limit = DefaultActivityListLimit
+	}
+	if err := validateResourceID(projectID); err != nil {
+		return nil, err
+	}
+
+	project, err := u.projectRepo.FindByID(ctx, projectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find project: %w", err)
+	}
+	if project.UserID != userID {
+		return nil, model.ErrForbidden
+	}
+
+	activities, err := u.activityRepo.FindByProjectID(ctx, projectID, opts)
+	if err != nil {
+		u.logger.ErrorContext(ctx, "failed to list activities", "error", err, "project_id", projectID)
+		return nil, fmt.Errorf("failed to list activities: %w", err)
+	}
+
+	return activities, nil
+}
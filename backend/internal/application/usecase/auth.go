@@ -7,6 +7,7 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -18,11 +19,16 @@ import (
 
 // AuthUsecase は認証に関するビジネスロジックを実装する
 type AuthUsecase struct {
-	userRepo          repository.UserRepository
-	googleAccountRepo repository.GoogleAccountRepository
-	githubAccountRepo repository.GithubAccountRepository
-	oauthConfig       *auth.OAuthConfig
-	logger            *slog.Logger
+	userRepo             repository.UserRepository
+	googleAccountRepo    repository.GoogleAccountRepository
+	githubAccountRepo    repository.GithubAccountRepository
+	appleAccountRepo     repository.AppleAccountRepository
+	microsoftAccountRepo repository.MicrosoftAccountRepository
+	invitationUsecase    *InvitationUsecase
+	mergeUsecase         *AccountMergeUsecase
+	oauthConfig          *auth.OAuthConfig
+	tx                   repository.Transactor
+	logger               *slog.Logger
 }
 
 // NewAuthUsecase は新しいAuthUsecaseを作成する
@@ -30,18 +36,99 @@ func NewAuthUsecase(
 	userRepo repository.UserRepository,
 	googleAccountRepo repository.GoogleAccountRepository,
 	githubAccountRepo repository.GithubAccountRepository,
+	appleAccountRepo repository.AppleAccountRepository,
+	microsoftAccountRepo repository.MicrosoftAccountRepository,
+	invitationUsecase *InvitationUsecase,
+	mergeUsecase *AccountMergeUsecase,
 	oauthConfig *auth.OAuthConfig,
+	tx repository.Transactor,
 	logger *slog.Logger,
 ) *AuthUsecase {
 	return &AuthUsecase{
-		userRepo:          userRepo,
-		googleAccountRepo: googleAccountRepo,
-		githubAccountRepo: githubAccountRepo,
-		oauthConfig:       oauthConfig,
-		logger:            logger,
+		userRepo:             userRepo,
+		googleAccountRepo:    googleAccountRepo,
+		githubAccountRepo:    githubAccountRepo,
+		appleAccountRepo:     appleAccountRepo,
+		microsoftAccountRepo: microsoftAccountRepo,
+		invitationUsecase:    invitationUsecase,
+		mergeUsecase:         mergeUsecase,
+		oauthConfig:          oauthConfig,
+		tx:                   tx,
+		logger:               logger,
 	}
 }
 
+// createUser は新規登録の制限を確認してユーザーとプロバイダーのアカウント（createAccount）を1つのトランザクションで作成し、招待があれば使用済みにする
+// アカウントの作成に失敗してユーザーだけが残ると、次のログインでメールアドレスが一致する既存ユーザーとして扱われるため、まとめてロールバックする
+//
+// 同じメールアドレス（大文字・小文字は区別しない）で別プロバイダーの初回ログインが同時に進んだ場合は、
+// 先にコミットされたユーザーを正とし、後のログインはそのユーザーへのアカウントの統合（merge）を要求する。
+// 順にログインした場合と同じく、確認されるまでプロバイダーのアカウントは紐付けない
+func (u *AuthUsecase) createUser(ctx context.Context, user *model.User, merge *model.AccountMerge, createAccount func(ctx context.Context) error) error {
+	if err := u.invitationUsecase.AuthorizeSignup(ctx, user.Email); err != nil {
+		return err
+	}
+
+	err := u.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if err := u.userRepo.Create(ctx, user); err != nil {
+			return fmt.Errorf("failed to create user: %w", err)
+		}
+		return createAccount(ctx)
+	})
+	if errors.Is(err, repository.ErrEmailTaken) {
+		winner, findErr := u.userRepo.FindByEmail(ctx, user.Email)
+		if findErr != nil {
+			// 先のユーザーの作成がロールバックされた場合等は、ログインをやり直してもらう
+			return fmt.Errorf("failed to find user created concurrently: %w: %w", findErr, err)
+		}
+		u.logger.InfoContext(ctx, "user with the same email was created concurrently, requesting account merge", "user_id", winner.ID, "provider", merge.Provider)
+		return u.mergeUsecase.RequestMerge(ctx, winner, merge)
+	}
+	if err != nil {
+		u.logger.ErrorContext(ctx, "failed to create user", "error", err)
+		return err
+	}
+
+	// 招待の消し込みに失敗してもユーザーは作成済みのため、ログに記録するのみ
+	if err := u.invitationUsecase.AcceptInvitation(ctx, user.Email); err != nil {
+		u.logger.ErrorContext(ctx, "failed to accept invitation", "error", err, "user_id", user.ID)
+	}
+	return nil
+}
+
+// updateUser は既存ユーザーのログインでユーザー情報（userがnilの場合は更新しない）とプロバイダーのアカウント（updateAccount）を1つのトランザクションで更新する
+// トークンの保存に失敗した場合にユーザー情報だけが更新された状態を残さないよう、まとめてロールバックする
+func (u *AuthUsecase) updateUser(ctx context.Context, user *model.User, updateAccount func(ctx context.Context) error) error {
+	err := u.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if user != nil {
+			if err := u.userRepo.Update(ctx, user); err != nil {
+				return fmt.Errorf("failed to update user: %w", err)
+			}
+		}
+		return updateAccount(ctx)
+	})
+	if err != nil {
+		u.logger.ErrorContext(ctx, "failed to update user", "error", err)
+		return err
+	}
+	return nil
+}
+
+// pendingMerge はプロバイダーのトークンを保持したアカウント統合の要求を作成する
+func pendingMerge(provider, providerAccountID, email string, token *oauth2.Token) *model.AccountMerge {
+	merge := &model.AccountMerge{
+		Provider:          provider,
+		ProviderAccountID: providerAccountID,
+		ProviderEmail:     email,
+		AccessToken:       token.AccessToken,
+		RefreshToken:      token.RefreshToken,
+	}
+	if !token.Expiry.IsZero() {
+		merge.TokenExpiresAt = &token.Expiry
+	}
+	return merge
+}
+
 // GenerateStateToken はCSRF対策用のランダムな状態トークンを生成する
 func (u *AuthUsecase) GenerateStateToken() (string, error) {
 	b := make([]byte, 32)
@@ -60,6 +147,10 @@ func (u *AuthUsecase) GetAuthURL(provider string, state string) string {
 		providerType = auth.ProviderGoogle
 	case "github":
 		providerType = auth.ProviderGithub
+	case "apple":
+		providerType = auth.ProviderApple
+	case "microsoft":
+		providerType = auth.ProviderMicrosoft
 	default:
 		providerType = auth.ProviderGoogle
 	}
@@ -76,6 +167,8 @@ func (u *AuthUsecase) HandleCallback(ctx context.Context, provider string, code
 		providerType = auth.ProviderGoogle
 	case "github":
 		providerType = auth.ProviderGithub
+	case "microsoft":
+		providerType = auth.ProviderMicrosoft
 	default:
 		return nil, nil, fmt.Errorf("unsupported provider: %s", provider)
 	}
@@ -92,6 +185,8 @@ func (u *AuthUsecase) HandleCallback(ctx context.Context, provider string, code
 		return u.handleGoogleCallback(ctx, token)
 	case auth.ProviderGithub:
 		return u.handleGithubCallback(ctx, token)
+	case auth.ProviderMicrosoft:
+		return u.handleMicrosoftCallback(ctx, token)
 	default:
 		return nil, nil, fmt.Errorf("unsupported provider: %s", provider)
 	}
@@ -114,7 +209,7 @@ func (u *AuthUsecase) handleGoogleCallback(ctx context.Context, token *oauth2.To
 
 	// 既存のGoogleアカウントを検索
 	googleAccount, err := u.googleAccountRepo.FindByProviderAccountID(ctx, "google", googleUserInfo.ID)
-	if err != nil && err.Error() != fmt.Sprintf("google account not found: %s", googleUserInfo.ID) {
+	if err != nil && !errors.Is(err, repository.ErrAccountNotFound) {
 		u.logger.ErrorContext(ctx, "failed to find google account", "error", err)
 		return nil, nil, fmt.Errorf("failed to find google account: %w", err)
 	}
@@ -135,11 +230,6 @@ func (u *AuthUsecase) handleGoogleCallback(ctx context.Context, token *oauth2.To
 		domainUser.ImageURL = googleUserInfo.Picture
 		domainUser.UpdatedAt = now
 
-		if err := u.userRepo.Update(ctx, domainUser); err != nil {
-			u.logger.ErrorContext(ctx, "failed to update user", "error", err)
-			return nil, nil, fmt.Errorf("failed to update user: %w", err)
-		}
-
 		// Googleアカウント情報を更新
 		googleAccount.AccessToken = token.AccessToken
 		if token.RefreshToken != "" {
@@ -150,38 +240,39 @@ func (u *AuthUsecase) handleGoogleCallback(ctx context.Context, token *oauth2.To
 		}
 		googleAccount.UpdatedAt = now
 
-		if err := u.googleAccountRepo.Update(ctx, googleAccount); err != nil {
-			u.logger.ErrorContext(ctx, "failed to update google account", "error", err)
-			return nil, nil, fmt.Errorf("failed to update google account: %w", err)
+		err = u.updateUser(ctx, domainUser, func(ctx context.Context) error {
+			if err := u.googleAccountRepo.Update(ctx, googleAccount); err != nil {
+				return fmt.Errorf("failed to update google account: %w", err)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, nil, err
 		}
 	} else {
 		// 新規ユーザーの場合、メールで既存ユーザーを検索
 		domainUser, err = u.userRepo.FindByEmail(ctx, googleUserInfo.Email)
-		if err != nil && err.Error() != fmt.Sprintf("user not found: %s", googleUserInfo.Email) {
+		if err != nil && !errors.Is(err, repository.ErrUserNotFound) {
 			u.logger.ErrorContext(ctx, "failed to find user by email", "error", err)
 			return nil, nil, fmt.Errorf("failed to find user: %w", err)
 		}
 
-		if domainUser == nil {
-			// 新規ユーザーを作成
-			domainUser = &model.User{
-				ID:        uuid.New().String(),
-				Email:     googleUserInfo.Email,
-				Name:      googleUserInfo.Name,
-				ImageURL:  googleUserInfo.Picture,
-				CreatedAt: now,
-				UpdatedAt: now,
-			}
-
-			if err := u.userRepo.Create(ctx, domainUser); err != nil {
-				u.logger.ErrorContext(ctx, "failed to create user", "error", err)
-				return nil, nil, fmt.Errorf("failed to create user: %w", err)
-			}
+		if domainUser != nil {
+			// メールアドレスの一致だけでは紐付けず、既存ユーザーにアカウントの統合を確認してもらう
+			return nil, nil, u.mergeUsecase.RequestMerge(ctx, domainUser, pendingMerge("google", googleUserInfo.ID, googleUserInfo.Email, token))
+		}
 
-			u.logger.InfoContext(ctx, "user created successfully", "user_id", domainUser.ID)
+		// 新規ユーザーを作成
+		domainUser = &model.User{
+			ID:        uuid.New().String(),
+			Email:     googleUserInfo.Email,
+			Name:      googleUserInfo.Name,
+			ImageURL:  googleUserInfo.Picture,
+			CreatedAt: now,
+			UpdatedAt: now,
 		}
 
-		// Googleアカウントを作成
+		// Googleアカウントはユーザーと同じトランザクションで作成する
 		googleAccount = &model.GoogleAccount{
 			ID:                uuid.New().String(),
 			UserID:            domainUser.ID,
@@ -196,12 +287,17 @@ func (u *AuthUsecase) handleGoogleCallback(ctx context.Context, token *oauth2.To
 			googleAccount.ExpiresAt = &token.Expiry
 		}
 
-		if err := u.googleAccountRepo.Create(ctx, googleAccount); err != nil {
-			u.logger.ErrorContext(ctx, "failed to create google account", "error", err)
-			return nil, nil, fmt.Errorf("failed to create google account: %w", err)
+		err = u.createUser(ctx, domainUser, pendingMerge("google", googleUserInfo.ID, googleUserInfo.Email, token), func(ctx context.Context) error {
+			if err := u.googleAccountRepo.Create(ctx, googleAccount); err != nil {
+				return fmt.Errorf("failed to create google account: %w", err)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, nil, err
 		}
 
-		u.logger.InfoContext(ctx, "google account created successfully", "account_id", googleAccount.ID)
+		u.logger.InfoContext(ctx, "user created successfully", "user_id", domainUser.ID, "provider", "google")
 	}
 
 	return domainUser, token, nil
@@ -224,7 +320,7 @@ func (u *AuthUsecase) handleGithubCallback(ctx context.Context, token *oauth2.To
 
 	// 既存のGitHubアカウントを検索
 	githubAccount, err := u.githubAccountRepo.FindByProviderAccountID(ctx, "github", fmt.Sprintf("%d", githubUserInfo.ID))
-	if err != nil && err.Error() != fmt.Sprintf("github account not found: %d", githubUserInfo.ID) {
+	if err != nil && !errors.Is(err, repository.ErrAccountNotFound) {
 		u.logger.ErrorContext(ctx, "failed to find github account", "error", err)
 		return nil, nil, fmt.Errorf("failed to find github account: %w", err)
 	}
@@ -248,58 +344,58 @@ func (u *AuthUsecase) handleGithubCallback(ctx context.Context, token *oauth2.To
 		domainUser.ImageURL = githubUserInfo.AvatarURL
 		domainUser.UpdatedAt = now
 
-		if err := u.userRepo.Update(ctx, domainUser); err != nil {
-			u.logger.ErrorContext(ctx, "failed to update user", "error", err)
-			return nil, nil, fmt.Errorf("failed to update user: %w", err)
-		}
-
 		// GitHubアカウント情報を更新
 		githubAccount.AccessToken = token.AccessToken
 		if token.RefreshToken != "" {
 			githubAccount.RefreshToken = token.RefreshToken
 		}
+		// 期限のないトークン（OAuth App）に切り替わった場合は、以前の期限でリフレッシュしないよう期限を消す
+		githubAccount.ExpiresAt = nil
 		if !token.Expiry.IsZero() {
 			githubAccount.ExpiresAt = &token.Expiry
 		}
+		// 新しいOAuthトークンを取得したため、OAuthのトークンの再認証は不要になる
+		githubAccount.ClearReauth(model.GithubReauthReasonOAuth)
 		githubAccount.UpdatedAt = now
 
-		if err := u.githubAccountRepo.Update(ctx, githubAccount); err != nil {
-			u.logger.ErrorContext(ctx, "failed to update github account", "error", err)
-			return nil, nil, fmt.Errorf("failed to update github account: %w", err)
+		err = u.updateUser(ctx, domainUser, func(ctx context.Context) error {
+			if err := u.githubAccountRepo.Update(ctx, githubAccount); err != nil {
+				return fmt.Errorf("failed to update github account: %w", err)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, nil, err
 		}
 	} else {
 		// 新規ユーザーの場合、メールで既存ユーザーを検索
 		domainUser, err = u.userRepo.FindByEmail(ctx, githubUserInfo.Email)
-		if err != nil && err.Error() != fmt.Sprintf("user not found: %s", githubUserInfo.Email) {
+		if err != nil && !errors.Is(err, repository.ErrUserNotFound) {
 			u.logger.ErrorContext(ctx, "failed to find user by email", "error", err)
 			return nil, nil, fmt.Errorf("failed to find user: %w", err)
 		}
 
-		if domainUser == nil {
-			// 新規ユーザーを作成
-			userName := githubUserInfo.Name
-			if userName == "" {
-				userName = githubUserInfo.Login
-			}
-
-			domainUser = &model.User{
-				ID:        uuid.New().String(),
-				Email:     githubUserInfo.Email,
-				Name:      userName,
-				ImageURL:  githubUserInfo.AvatarURL,
-				CreatedAt: now,
-				UpdatedAt: now,
-			}
+		if domainUser != nil {
+			// メールアドレスの一致だけでは紐付けず、既存ユーザーにアカウントの統合を確認してもらう
+			return nil, nil, u.mergeUsecase.RequestMerge(ctx, domainUser, pendingMerge("github", fmt.Sprintf("%d", githubUserInfo.ID), githubUserInfo.Email, token))
+		}
 
-			if err := u.userRepo.Create(ctx, domainUser); err != nil {
-				u.logger.ErrorContext(ctx, "failed to create user", "error", err)
-				return nil, nil, fmt.Errorf("failed to create user: %w", err)
-			}
+		// 新規ユーザーを作成
+		userName := githubUserInfo.Name
+		if userName == "" {
+			userName = githubUserInfo.Login
+		}
 
-			u.logger.InfoContext(ctx, "user created successfully", "user_id", domainUser.ID)
+		domainUser = &model.User{
+			ID:        uuid.New().String(),
+			Email:     githubUserInfo.Email,
+			Name:      userName,
+			ImageURL:  githubUserInfo.AvatarURL,
+			CreatedAt: now,
+			UpdatedAt: now,
 		}
 
-		// GitHubアカウントを作成
+		// GitHubアカウントはユーザーと同じトランザクションで作成する
 		githubAccount = &model.GithubAccount{
 			ID:                uuid.New().String(),
 			UserID:            domainUser.ID,
@@ -314,12 +410,281 @@ func (u *AuthUsecase) handleGithubCallback(ctx context.Context, token *oauth2.To
 			githubAccount.ExpiresAt = &token.Expiry
 		}
 
-		if err := u.githubAccountRepo.Create(ctx, githubAccount); err != nil {
-			u.logger.ErrorContext(ctx, "failed to create github account", "error", err)
-			return nil, nil, fmt.Errorf("failed to create github account: %w", err)
+		err = u.createUser(ctx, domainUser, pendingMerge("github", fmt.Sprintf("%d", githubUserInfo.ID), githubUserInfo.Email, token), func(ctx context.Context) error {
+			if err := u.githubAccountRepo.Create(ctx, githubAccount); err != nil {
+				return fmt.Errorf("failed to create github account: %w", err)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+
+		u.logger.InfoContext(ctx, "user created successfully", "user_id", domainUser.ID, "provider", "github")
+	}
+
+	return domainUser, token, nil
+}
+
+// handleMicrosoftCallback はMicrosoft Entra IDのOAuthコールバックを処理する
+func (u *AuthUsecase) handleMicrosoftCallback(ctx context.Context, token *oauth2.Token) (*model.User, *oauth2.Token, error) {
+	// ユーザー情報をMicrosoft Graphから取得
+	msUserInfo, err := u.oauthConfig.GetMicrosoftUserInfo(ctx, token)
+	if err != nil {
+		u.logger.ErrorContext(ctx, "failed to get microsoft user info", "error", err)
+		return nil, nil, fmt.Errorf("failed to get user info: %w", err)
+	}
+
+	email := msUserInfo.EmailAddress()
+	if email == "" || !strings.Contains(email, "@") {
+		u.logger.WarnContext(ctx, "email not found", "microsoft_id", msUserInfo.ID)
+		return nil, nil, errors.New("email is not available")
+	}
+
+	// 既存のMicrosoftアカウントを検索
+	msAccount, err := u.microsoftAccountRepo.FindByProviderAccountID(ctx, "microsoft", msUserInfo.ID)
+	if err != nil && !errors.Is(err, repository.ErrAccountNotFound) {
+		u.logger.ErrorContext(ctx, "failed to find microsoft account", "error", err)
+		return nil, nil, fmt.Errorf("failed to find microsoft account: %w", err)
+	}
+
+	now := time.Now()
+	var domainUser *model.User
+
+	if msAccount != nil {
+		// 既存のユーザーを取得
+		domainUser, err = u.userRepo.FindByID(ctx, msAccount.UserID)
+		if err != nil {
+			u.logger.ErrorContext(ctx, "failed to find user", "user_id", msAccount.UserID, "error", err)
+			return nil, nil, fmt.Errorf("failed to find user: %w", err)
+		}
+
+		// ユーザー情報を更新
+		if msUserInfo.DisplayName != "" {
+			domainUser.Name = msUserInfo.DisplayName
+		}
+		domainUser.UpdatedAt = now
+
+		// Microsoftアカウント情報を更新
+		msAccount.AccessToken = token.AccessToken
+		if token.RefreshToken != "" {
+			msAccount.RefreshToken = token.RefreshToken
+		}
+		if !token.Expiry.IsZero() {
+			msAccount.ExpiresAt = &token.Expiry
+		}
+		msAccount.UpdatedAt = now
+
+		err = u.updateUser(ctx, domainUser, func(ctx context.Context) error {
+			if err := u.microsoftAccountRepo.Update(ctx, msAccount); err != nil {
+				return fmt.Errorf("failed to update microsoft account: %w", err)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+	} else {
+		// 複数テナントを受け付ける場合はメールアドレスを他テナントの管理者が設定できるため、既存ユーザーとの紐付けは特定テナントの場合のみ行う
+		if u.oauthConfig.MicrosoftSingleTenant() {
+			domainUser, err = u.userRepo.FindByEmail(ctx, email)
+			if err != nil && !errors.Is(err, repository.ErrUserNotFound) {
+				u.logger.ErrorContext(ctx, "failed to find user by email", "error", err)
+				return nil, nil, fmt.Errorf("failed to find user: %w", err)
+			}
+		}
+
+		if domainUser != nil {
+			// メールアドレスの一致だけでは紐付けず、既存ユーザーにアカウントの統合を確認してもらう
+			return nil, nil, u.mergeUsecase.RequestMerge(ctx, domainUser, pendingMerge("microsoft", msUserInfo.ID, email, token))
+		}
+
+		// 新規ユーザーを作成
+		name := msUserInfo.DisplayName
+		if name == "" {
+			name, _, _ = strings.Cut(email, "@")
+		}
+		domainUser = &model.User{
+			ID:        uuid.New().String(),
+			Email:     email,
+			Name:      name,
+			CreatedAt: now,
+			UpdatedAt: now,
+		}
+
+		// Microsoftアカウントはユーザーと同じトランザクションで作成する
+		msAccount = &model.MicrosoftAccount{
+			UserID:            domainUser.ID,
+			Provider:          "microsoft",
+			ProviderAccountID: msUserInfo.ID,
+			AccessToken:       token.AccessToken,
+			RefreshToken:      token.RefreshToken,
+			CreatedAt:         now,
+			UpdatedAt:         now,
+		}
+		if !token.Expiry.IsZero() {
+			msAccount.ExpiresAt = &token.Expiry
+		}
+
+		err = u.createUser(ctx, domainUser, pendingMerge("microsoft", msUserInfo.ID, email, token), func(ctx context.Context) error {
+			if err := u.microsoftAccountRepo.Create(ctx, msAccount); err != nil {
+				return fmt.Errorf("failed to create microsoft account: %w", err)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+
+		u.logger.InfoContext(ctx, "user created successfully", "user_id", domainUser.ID, "provider", "microsoft")
+	}
+
+	return domainUser, token, nil
+}
+
+// MicrosoftEnabled はMicrosoftのログインが設定されているかどうかを返す
+func (u *AuthUsecase) MicrosoftEnabled() bool {
+	return u.oauthConfig.MicrosoftEnabled()
+}
+
+// AppleEnabled はSign in with Appleが設定されているかどうかを返す
+func (u *AuthUsecase) AppleEnabled() bool {
+	return u.oauthConfig.AppleEnabled()
+}
+
+// HandleAppleCallback はSign in with Appleのコールバックを処理する
+// appleUserはAppleが初回の認可時にのみ送信する名前とメールアドレス（2回目以降はnil）
+func (u *AuthUsecase) HandleAppleCallback(ctx context.Context, code string, appleUser *auth.AppleUser) (*model.User, *oauth2.Token, error) {
+	u.logger.InfoContext(ctx, "handling oauth callback", "provider", "apple")
+
+	token, err := u.oauthConfig.Exchange(ctx, auth.ProviderApple, code)
+	if err != nil {
+		u.logger.ErrorContext(ctx, "failed to exchange token", "provider", "apple", "error", err)
+		return nil, nil, fmt.Errorf("failed to exchange token: %w", err)
+	}
+
+	// ユーザー情報はid_tokenから取得する（Appleにはユーザー情報のAPIがない）
+	idToken, err := u.oauthConfig.VerifyAppleIDToken(ctx, token)
+	if err != nil {
+		u.logger.ErrorContext(ctx, "failed to verify apple id_token", "error", err)
+		return nil, nil, fmt.Errorf("failed to verify id_token: %w", err)
+	}
+
+	if idToken.Email == "" {
+		u.logger.WarnContext(ctx, "email not found", "sub", idToken.Subject)
+		return nil, nil, errors.New("email is not available")
+	}
+	if !idToken.EmailVerified {
+		u.logger.WarnContext(ctx, "email not verified", "email", idToken.Email)
+		return nil, nil, errors.New("email is not verified")
+	}
+
+	var name string
+	if appleUser != nil {
+		name = appleUser.FullName()
+	}
+
+	// 既存のAppleアカウントを検索
+	appleAccount, err := u.appleAccountRepo.FindByProviderAccountID(ctx, "apple", idToken.Subject)
+	if err != nil && !errors.Is(err, repository.ErrAccountNotFound) {
+		u.logger.ErrorContext(ctx, "failed to find apple account", "error", err)
+		return nil, nil, fmt.Errorf("failed to find apple account: %w", err)
+	}
+
+	now := time.Now()
+	var domainUser *model.User
+
+	if appleAccount != nil {
+		// 既存のユーザーを取得
+		domainUser, err = u.userRepo.FindByID(ctx, appleAccount.UserID)
+		if err != nil {
+			u.logger.ErrorContext(ctx, "failed to find user", "user_id", appleAccount.UserID, "error", err)
+			return nil, nil, fmt.Errorf("failed to find user: %w", err)
+		}
+
+		// 名前は初回の認可時にしか送られないため、送られた場合のみ更新する
+		var updatedUser *model.User
+		if name != "" {
+			domainUser.Name = name
+			domainUser.UpdatedAt = now
+			updatedUser = domainUser
+		}
+
+		// Appleアカウント情報を更新（メールの非公開設定はユーザーが後から変更できる）
+		appleAccount.Email = idToken.Email
+		appleAccount.IsPrivateEmail = idToken.IsPrivateEmail
+		appleAccount.AccessToken = token.AccessToken
+		if token.RefreshToken != "" {
+			appleAccount.RefreshToken = token.RefreshToken
+		}
+		if !token.Expiry.IsZero() {
+			appleAccount.ExpiresAt = &token.Expiry
+		}
+		appleAccount.UpdatedAt = now
+
+		err = u.updateUser(ctx, updatedUser, func(ctx context.Context) error {
+			if err := u.appleAccountRepo.Update(ctx, appleAccount); err != nil {
+				return fmt.Errorf("failed to update apple account: %w", err)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+	} else {
+		// 転送用アドレスは他のプロバイダーのメールアドレスと一致しないため、実アドレスの場合のみ既存ユーザーを検索する
+		if !idToken.IsPrivateEmail {
+			domainUser, err = u.userRepo.FindByEmail(ctx, idToken.Email)
+			if err != nil && !errors.Is(err, repository.ErrUserNotFound) {
+				u.logger.ErrorContext(ctx, "failed to find user by email", "error", err)
+				return nil, nil, fmt.Errorf("failed to find user: %w", err)
+			}
+		}
+
+		if domainUser != nil {
+			// メールアドレスの一致だけでは紐付けず、既存ユーザーにアカウントの統合を確認してもらう
+			return nil, nil, u.mergeUsecase.RequestMerge(ctx, domainUser, pendingMerge("apple", idToken.Subject, idToken.Email, token))
+		}
+
+		// 新規ユーザーを作成（名前を共有しなかった場合はメールアドレスのローカル部を使う）
+		if name == "" {
+			name, _, _ = strings.Cut(idToken.Email, "@")
+		}
+		domainUser = &model.User{
+			ID:        uuid.New().String(),
+			Email:     idToken.Email,
+			Name:      name,
+			CreatedAt: now,
+			UpdatedAt: now,
+		}
+
+		// Appleアカウントはユーザーと同じトランザクションで作成する
+		appleAccount = &model.AppleAccount{
+			UserID:            domainUser.ID,
+			Provider:          "apple",
+			ProviderAccountID: idToken.Subject,
+			Email:             idToken.Email,
+			IsPrivateEmail:    idToken.IsPrivateEmail,
+			AccessToken:       token.AccessToken,
+			RefreshToken:      token.RefreshToken,
+			CreatedAt:         now,
+			UpdatedAt:         now,
+		}
+		if !token.Expiry.IsZero() {
+			appleAccount.ExpiresAt = &token.Expiry
+		}
+
+		err = u.createUser(ctx, domainUser, pendingMerge("apple", idToken.Subject, idToken.Email, token), func(ctx context.Context) error {
+			if err := u.appleAccountRepo.Create(ctx, appleAccount); err != nil {
+				return fmt.Errorf("failed to create apple account: %w", err)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, nil, err
 		}
 
-		u.logger.InfoContext(ctx, "github account created successfully", "account_id", githubAccount.ID)
+		u.logger.InfoContext(ctx, "user created successfully", "user_id", domainUser.ID, "private_email", idToken.IsPrivateEmail, "provider", "apple")
 	}
 
 	return domainUser, token, nil
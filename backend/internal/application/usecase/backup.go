@@ -0,0 +1,378 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sikigasa/github-task-controller/backend/internal/domain/model"
+	"github.com/sikigasa/github-task-controller/backend/internal/domain/repository"
+	"github.com/sikigasa/github-task-controller/backend/internal/infrastructure/backup"
+	"github.com/sikigasa/github-task-controller/backend/internal/infrastructure/storage"
+)
+
+const (
+	// backupPrefix はバックアップを保存するキーのプレフィックス
+	backupPrefix = "backups/"
+	// backupTimeLayout はバックアップ名に含める作成日時（UTC）の形式
+	backupTimeLayout = "20060102-150405.000"
+	// backupExtension はバックアップのファイルの拡張子（gzipで圧縮したJSON Lines）
+	backupExtension = ".jsonl.gz"
+)
+
+// BackupUsecase はデータベースの論理バックアップ・復元に関するユースケース
+// バックアップはstorageにbackup-{作成日時}-{契機}.jsonl.gzの名前で保存する
+type BackupUsecase struct {
+	backupRepo     repository.BackupRepository
+	storage        storage.Storage
+	retention      int
+	restoreEnabled bool
+	// restoring は復元の同時実行を防ぐ
+	restoring sync.Mutex
+	logger    *slog.Logger
+}
+
+// NewBackupUsecase は新しいBackupUsecaseを作成する
+// retentionは残す定期バックアップの世代数、restoreEnabledがfalseの場合は復元を受け付けない
+func NewBackupUsecase(
+	backupRepo repository.BackupRepository,
+	storage storage.Storage,
+	retention int,
+	restoreEnabled bool,
+	logger *slog.Logger,
+) *BackupUsecase {
+	return &BackupUsecase{
+		backupRepo:     backupRepo,
+		storage:        storage,
+		retention:      retention,
+		restoreEnabled: restoreEnabled,
+		logger:         logger,
+	}
+}
+
+// CreateBackup はすべてのテーブルのバックアップを作成して保存する
+func (u *BackupUsecase) CreateBackup(ctx context.Context) (*model.Backup, error) {
+	return u.create(ctx, model.BackupTriggerManual)
+}
+
+// CreateScheduledBackup は定期バックアップを作成し、保持する世代数を超えた古い定期バックアップを削除する
+func (u *BackupUsecase) CreateScheduledBackup(ctx context.Context) error {
+	b, err := u.create(ctx, model.BackupTriggerScheduled)
+	if err != nil {
+		return err
+	}
+	u.logger.InfoContext(ctx, "scheduled backup created", "backup", b.Name, "size", b.Size)
+
+	return u.pruneScheduled(ctx)
+}
+
+// create はバックアップを作成して保存する
+// アーカイブは書き出しながら保存先に流し込み、書き出しに失敗した場合は保存しない
+func (u *BackupUsecase) create(ctx context.Context, trigger model.BackupTrigger) (*model.Backup, error) {
+	now := time.Now().UTC()
+	name := backupName(now, trigger)
+
+	pr, pw := io.Pipe()
+	counter := &countingWriter{w: pw}
+	rowsCh := make(chan map[string]int, 1)
+	go func() {
+		rows, err := u.writeArchive(ctx, counter, now, trigger)
+		pw.CloseWithError(err)
+		rowsCh <- rows
+	}()
+
+	err := u.storage.Put(ctx, backupPrefix+name, pr)
+	pr.Close()
+	rows := <-rowsCh
+	if err != nil {
+		u.logger.ErrorContext(ctx, "failed to create backup", "error", err, "backup", name)
+		return nil, fmt.Errorf("failed to create backup: %w", err)
+	}
+
+	return &model.Backup{Name: name, Trigger: trigger, Size: counter.n, CreatedAt: now, Rows: rows}, nil
+}
+
+// writeArchive はすべてのテーブルの行をアーカイブとしてwに書き出す
+func (u *BackupUsecase) writeArchive(ctx context.Context, w io.Writer, createdAt time.Time, trigger model.BackupTrigger) (map[string]int, error) {
+	aw, err := backup.NewWriter(w, createdAt, string(trigger), u.backupRepo.Tables())
+	if err != nil {
+		return nil, err
+	}
+	if err := u.backupRepo.Dump(ctx, aw.WriteRow); err != nil {
+		return nil, fmt.Errorf("failed to dump tables: %w", err)
+	}
+	if err := aw.Close(); err != nil {
+		return nil, err
+	}
+	return aw.Rows(), nil
+}
+
+// pruneScheduled は新しいものからretention世代を残して定期バックアップを削除する
+func (u *BackupUsecase) pruneScheduled(ctx context.Context) error {
+	backups, err := u.ListBackups(ctx)
+	if err != nil {
+		return err
+	}
+
+	kept := 0
+	for _, b := range backups {
+		if b.Trigger != model.BackupTriggerScheduled {
+			continue
+		}
+		kept++
+		if kept <= u.retention {
+			continue
+		}
+		if err := u.storage.Delete(ctx, backupPrefix+b.Name); err != nil && !errors.Is(err, model.ErrNotFound) {
+			return fmt.Errorf("failed to delete expired backup: %w", err)
+		}
+		u.logger.InfoContext(ctx, "expired backup deleted", "backup", b.Name)
+	}
+	return nil
+}
+
+// ListBackups は保存したバックアップを新しい順に一覧する
+func (u *BackupUsecase) ListBackups(ctx context.Context) ([]*model.Backup, error) {
+	objects, err := u.storage.List(ctx, backupPrefix)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list backups: %w", err)
+	}
+
+	backups := make([]*model.Backup, 0, len(objects))
+	for _, obj := range objects {
+		name := strings.TrimPrefix(obj.Key, backupPrefix)
+		createdAt, trigger, err := parseBackupName(name)
+		if err != nil {
+			continue
+		}
+		backups = append(backups, &model.Backup{Name: name, Trigger: trigger, Size: obj.Size, CreatedAt: createdAt})
+	}
+	slices.SortFunc(backups, func(a, b *model.Backup) int {
+		return b.CreatedAt.Compare(a.CreatedAt)
+	})
+	return backups, nil
+}
+
+// OpenBackup はダウンロードのためにバックアップを開く（呼び出し側で閉じる）
+func (u *BackupUsecase) OpenBackup(ctx context.Context, name string) (io.ReadCloser, error) {
+	if _, _, err := parseBackupName(name); err != nil {
+		return nil, err
+	}
+	return u.storage.Get(ctx, backupPrefix+name)
+}
+
+// BackupDownloadURL はバックアップを保存先から直接ダウンロードできる署名付きURLを発行する
+// 保存先が署名付きURLを発行できない場合は空文字を返す（OpenBackupで読み出す）
+func (u *BackupUsecase) BackupDownloadURL(ctx context.Context, name string) (string, error) {
+	if _, _, err := parseBackupName(name); err != nil {
+		return "", err
+	}
+	url, err := u.storage.SignedURL(ctx, backupPrefix+name, storage.Download{FileName: name, ContentType: "application/gzip"})
+	if errors.Is(err, storage.ErrSignedURLUnsupported) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to sign backup url: %w", err)
+	}
+	return url, nil
+}
+
+// DeleteBackup はバックアップを削除する
+func (u *BackupUsecase) DeleteBackup(ctx context.Context, name string) error {
+	if _, _, err := parseBackupName(name); err != nil {
+		return err
+	}
+	return u.storage.Delete(ctx, backupPrefix+name)
+}
+
+// UploadBackup はアップロードされたアーカイブを保存する
+// 保存後に最後まで読み込んで検証し、不正な場合は削除してErrInvalidInputを返す
+func (u *BackupUsecase) UploadBackup(ctx context.Context, r io.Reader) (*model.Backup, error) {
+	now := time.Now().UTC()
+	name := backupName(now, model.BackupTriggerUpload)
+	key := backupPrefix + name
+
+	counter := &countingReader{r: r}
+	if err := u.storage.Put(ctx, key, counter); err != nil {
+		return nil, fmt.Errorf("failed to save uploaded backup: %w", err)
+	}
+
+	rows, err := u.verify(ctx, key)
+	if err != nil {
+		if err := u.storage.Delete(ctx, key); err != nil {
+			u.logger.ErrorContext(ctx, "failed to delete invalid backup", "error", err, "backup", name)
+		}
+		return nil, err
+	}
+
+	return &model.Backup{Name: name, Trigger: model.BackupTriggerUpload, Size: counter.n, CreatedAt: now, Rows: rows}, nil
+}
+
+// verify は保存したアーカイブを最後まで読み込んで形式と行数を検証する
+func (u *BackupUsecase) verify(ctx context.Context, key string) (map[string]int, error) {
+	f, err := u.storage.Get(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	ar, err := u.openArchive(f)
+	if err != nil {
+		return nil, err
+	}
+	defer ar.Close()
+
+	rows := make(map[string]int)
+	for {
+		table, _, err := ar.Next()
+		if errors.Is(err, io.EOF) {
+			return rows, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		rows[table]++
+	}
+}
+
+// openArchive はアーカイブを開き、復元できないテーブルを含まないことを検証する
+func (u *BackupUsecase) openArchive(r io.Reader) (*backup.Reader, error) {
+	ar, err := backup.NewReader(r)
+	if err != nil {
+		return nil, err
+	}
+	known := u.backupRepo.Tables()
+	for _, t := range ar.Header().Tables {
+		if !slices.Contains(known, t) {
+			ar.Close()
+			return nil, fmt.Errorf("backup contains unknown table %q: %w", t, model.ErrInvalidInput)
+		}
+	}
+	return ar, nil
+}
+
+// RestoreBackup はバックアップの内容でデータベースを置き換える
+// BACKUP_RESTORE_ENABLEDが無効の場合はErrForbidden、確認の名前が一致しない場合はErrInvalidInputを返す
+// 復元の直前の状態はpre_restoreのバックアップとして保存し、復元に失敗した場合はロールバックする
+// セッションもバックアップ時点の内容に戻るため、復元後は再ログインが必要になる場合がある
+func (u *BackupUsecase) RestoreBackup(ctx context.Context, name string, req *model.RestoreBackupRequest) (*model.RestoreResult, error) {
+	if !u.restoreEnabled {
+		return nil, fmt.Errorf("restore is disabled: %w", model.ErrForbidden)
+	}
+	if _, _, err := parseBackupName(name); err != nil {
+		return nil, err
+	}
+	if req.Confirm != name {
+		return nil, fmt.Errorf("confirm must match the backup name: %w", model.ErrInvalidInput)
+	}
+	if !u.restoring.TryLock() {
+		return nil, fmt.Errorf("another restore is in progress: %w", model.ErrConflict)
+	}
+	defer u.restoring.Unlock()
+
+	f, err := u.storage.Get(ctx, backupPrefix+name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	// データを消す前に形式を検証する
+	ar, err := u.openArchive(f)
+	if err != nil {
+		return nil, err
+	}
+	defer ar.Close()
+
+	pre, err := u.create(ctx, model.BackupTriggerPreRestore)
+	if err != nil {
+		return nil, fmt.Errorf("failed to back up before restore: %w", err)
+	}
+
+	rows := make(map[string]int)
+	err = u.backupRepo.Restore(ctx, func(insert func(table string, row json.RawMessage) error) error {
+		for {
+			table, row, err := ar.Next()
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			if err := insert(table, row); err != nil {
+				return err
+			}
+			rows[table]++
+		}
+	})
+	if err != nil {
+		u.logger.ErrorContext(ctx, "failed to restore backup", "error", err, "backup", name)
+		return nil, fmt.Errorf("failed to restore backup: %w", err)
+	}
+
+	u.logger.WarnContext(ctx, "database restored from backup", "backup", name, "pre_restore_backup", pre.Name)
+	return &model.RestoreResult{Backup: name, PreRestoreBackup: pre.Name, Rows: rows}, nil
+}
+
+// backupName はバックアップの名前を組み立てる
+func backupName(createdAt time.Time, trigger model.BackupTrigger) string {
+	return "backup-" + createdAt.Format(backupTimeLayout) + "-" + string(trigger) + backupExtension
+}
+
+// parseBackupName はバックアップの名前から作成日時と契機を取り出す
+func parseBackupName(name string) (time.Time, model.BackupTrigger, error) {
+	invalid := fmt.Errorf("invalid backup name %q: %w", name, model.ErrInvalidInput)
+
+	rest, ok := strings.CutPrefix(name, "backup-")
+	if !ok {
+		return time.Time{}, "", invalid
+	}
+	rest, ok = strings.CutSuffix(rest, backupExtension)
+	if !ok {
+		return time.Time{}, "", invalid
+	}
+	i := strings.LastIndex(rest, "-")
+	if i < 0 {
+		return time.Time{}, "", invalid
+	}
+
+	createdAt, err := time.Parse(backupTimeLayout, rest[:i])
+	if err != nil {
+		return time.Time{}, "", invalid
+	}
+	trigger := model.BackupTrigger(rest[i+1:])
+	if !trigger.IsValid() {
+		return time.Time{}, "", invalid
+	}
+	return createdAt, trigger, nil
+}
+
+// countingWriter は書き込んだバイト数を数える
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// countingReader は読み込んだバイト数を数える
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
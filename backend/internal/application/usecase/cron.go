@@ -0,0 +1,254 @@
+package usecase
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sikigasa/github-task-controller/backend/internal/domain/model"
+)
+
+// cronSearchYears は次の実行時刻を探す範囲（これを超えても見つからない式は実行されないものとして拒否する）
+const cronSearchYears = 5
+
+// cronDescriptors は@で始まる定義済みの式
+var cronDescriptors = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// cronField はcronの1つのフィールドの範囲と名前
+type cronField struct {
+	name     string
+	min, max int
+	names    []string
+}
+
+var (
+	cronMinute  = cronField{name: "minute", min: 0, max: 59}
+	cronHour    = cronField{name: "hour", min: 0, max: 23}
+	cronDay     = cronField{name: "day of month", min: 1, max: 31}
+	cronMonth   = cronField{name: "month", min: 1, max: 12, names: []string{"jan", "feb", "mar", "apr", "may", "jun", "jul", "aug", "sep", "oct", "nov", "dec"}}
+	cronWeekday = cronField{name: "day of week", min: 0, max: 7, names: []string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"}}
+)
+
+// cronAllHours はすべての時に一致する時のビット集合
+const cronAllHours = 1<<24 - 1
+
+// cronSchedule はcron式（分 時 日 月 曜日）か@every <間隔>で表したジョブの実行時刻
+type cronSchedule struct {
+	// every は@everyの場合の間隔（0の場合はcron式）
+	every time.Duration
+	// minute・hour・day・month・weekday は各フィールドで一致する値のビット集合
+	minute, hour, day, month, weekday uint64
+	// dayAny・weekdayAny は日・曜日が*の場合にtrue（両方が指定された場合はどちらかに一致すればよい）
+	dayAny, weekdayAny bool
+	loc                *time.Location
+}
+
+// parseCron はcron式を解析する（時刻はlocで解釈する）
+// 5つのフィールド（分 時 日 月 曜日）の*・数値・範囲（1-5）・間隔（*/15）・リスト（1,15）・月と曜日の英語の略称、
+// @daily等の定義済みの式、@every <間隔>（例: @every 15m）に対応する
+func parseCron(spec string, loc *time.Location) (*cronSchedule, error) {
+	spec = strings.TrimSpace(spec)
+	if interval, ok := strings.CutPrefix(spec, "@every "); ok {
+		every, err := time.ParseDuration(strings.TrimSpace(interval))
+		if err != nil || every < time.Second {
+			return nil, fmt.Errorf("invalid schedule %q (interval must be at least 1s): %w", spec, model.ErrInvalidInput)
+		}
+		return &cronSchedule{every: every, loc: loc}, nil
+	}
+	if expanded, ok := cronDescriptors[strings.ToLower(spec)]; ok {
+		spec = expanded
+	}
+
+	fields := strings.Fields(spec)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("invalid schedule %q (expected 5 fields: minute hour day month weekday): %w", spec, model.ErrInvalidInput)
+	}
+
+	s := &cronSchedule{loc: loc, dayAny: fields[2] == "*", weekdayAny: fields[4] == "*"}
+	var err error
+	for i, target := range []struct {
+		field cronField
+		bits  *uint64
+	}{
+		{cronMinute, &s.minute},
+		{cronHour, &s.hour},
+		{cronDay, &s.day},
+		{cronMonth, &s.month},
+		{cronWeekday, &s.weekday},
+	} {
+		if *target.bits, err = target.field.parse(fields[i]); err != nil {
+			return nil, fmt.Errorf("invalid schedule %q: %w", spec, err)
+		}
+	}
+	// 曜日の7は日曜日として扱う
+	if s.weekday&(1<<7) != 0 {
+		s.weekday |= 1
+	}
+
+	if s.Next(time.Now()).IsZero() {
+		return nil, fmt.Errorf("invalid schedule %q (never runs): %w", spec, model.ErrInvalidInput)
+	}
+	return s, nil
+}
+
+// parse はフィールドを一致する値のビット集合にする
+func (f cronField) parse(expr string) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(expr, ",") {
+		rangeExpr, stepExpr, hasStep := strings.Cut(part, "/")
+		step := 1
+		if hasStep {
+			n, err := strconv.Atoi(stepExpr)
+			if err != nil || n < 1 {
+				return 0, fmt.Errorf("invalid %s step %q: %w", f.name, part, model.ErrInvalidInput)
+			}
+			step = n
+		}
+
+		lo, hi := f.min, f.max
+		if rangeExpr != "*" {
+			loExpr, hiExpr, isRange := strings.Cut(rangeExpr, "-")
+			var err error
+			if lo, err = f.value(loExpr); err != nil {
+				return 0, err
+			}
+			hi = lo
+			if isRange {
+				if hi, err = f.value(hiExpr); err != nil {
+					return 0, err
+				}
+			} else if hasStep {
+				// 5/15のように開始だけを指定した場合は最大値まで
+				hi = f.max
+			}
+			if lo > hi {
+				return 0, fmt.Errorf("invalid %s range %q: %w", f.name, part, model.ErrInvalidInput)
+			}
+		}
+
+		for v := lo; v <= hi; v += step {
+			bits |= 1 << v
+		}
+	}
+	return bits, nil
+}
+
+// value は数値か名前（月・曜日の英語の略称）を値にする
+func (f cronField) value(expr string) (int, error) {
+	for i, name := range f.names {
+		if strings.EqualFold(expr, name) {
+			return f.min + i, nil
+		}
+	}
+	v, err := strconv.Atoi(expr)
+	if err != nil || v < f.min || v > f.max {
+		return 0, fmt.Errorf("invalid %s %q (must be between %d and %d): %w", f.name, expr, f.min, f.max, model.ErrInvalidInput)
+	}
+	return v, nil
+}
+
+// Next はafterより後の最初の実行時刻を返す（cronSearchYears以内に見つからない場合はゼロ値）
+// 夏時間の開始で飛ばした時刻に実行する場合は飛ばした直後に実行し、
+// 夏時間の終了で繰り返す時刻は1回目のみ実行する
+// 時が*の場合は飛ばした時刻の分を補わず、繰り返しの間も通常どおり実行する
+func (s *cronSchedule) Next(after time.Time) time.Time {
+	if s.every > 0 {
+		return after.Add(s.every)
+	}
+
+	t := after.In(s.loc).Truncate(time.Minute).Add(time.Minute)
+	limit := t.AddDate(cronSearchYears, 0, 0)
+	for t.Before(limit) {
+		switch {
+		case s.month&(1<<int(t.Month())) == 0:
+			t = s.date(t.Year(), t.Month()+1, 1, 0)
+			if s.skipped(t, 0) {
+				return t
+			}
+		case !s.matchDay(t):
+			t = s.date(t.Year(), t.Month(), t.Day()+1, 0)
+			if s.skipped(t, 0) {
+				return t
+			}
+		case s.hour&(1<<t.Hour()) == 0:
+			hour := t.Hour() + 1
+			t = s.date(t.Year(), t.Month(), t.Day(), hour)
+			if s.skipped(t, hour%24) {
+				return t
+			}
+		case s.minute&(1<<t.Minute()) == 0:
+			hour := t.Hour() + 1
+			t = t.Add(time.Minute)
+			if t.Minute() == 0 && s.skipped(t, hour%24) {
+				return t
+			}
+		case s.hour != cronAllHours && repeatedWallClock(t):
+			t = t.Add(time.Minute)
+		default:
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+// date はlocでの指定した日時を返す
+// 夏時間の開始で存在しない時刻の場合、time.Dateは前にずれた時刻を返すため、飛ばした直後の時刻にする
+func (s *cronSchedule) date(year int, month time.Month, day, hour int) time.Time {
+	t := time.Date(year, month, day, hour, 0, 0, 0, s.loc)
+	want := time.Date(year, month, day, hour, 0, 0, 0, time.UTC)
+	got := time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), 0, 0, time.UTC)
+	if got.Before(want) {
+		t = t.Add(want.Sub(got))
+	}
+	return t
+}
+
+// skipped はdateで求めたtが夏時間の開始でwantHour時から後ろにずれた場合に、
+// 飛ばした時刻にこのスケジュールの実行時刻があったかを返す（時が*の場合は常にfalse）
+func (s *cronSchedule) skipped(t time.Time, wantHour int) bool {
+	if s.hour == cronAllHours || t.Hour() == wantHour || s.month&(1<<int(t.Month())) == 0 || !s.matchDay(t) {
+		return false
+	}
+	for h := wantHour; h != t.Hour(); h = (h + 1) % 24 {
+		if s.hour&(1<<h) != 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// repeatedWallClock は夏時間の終了により、tと同じ時刻（年月日時分）がtより前にもあったかを返す
+func repeatedWallClock(t time.Time) bool {
+	_, offset := t.Zone()
+	_, before := t.Add(-24 * time.Hour).Zone()
+	if before <= offset {
+		return false
+	}
+	earlier := t.Add(-time.Duration(before-offset) * time.Second)
+	return earlier.Day() == t.Day() && earlier.Hour() == t.Hour() && earlier.Minute() == t.Minute()
+}
+
+// matchDay は日と曜日の条件に一致するかを返す（両方を指定した場合はどちらかに一致すればよい）
+func (s *cronSchedule) matchDay(t time.Time) bool {
+	day := s.day&(1<<t.Day()) != 0
+	weekday := s.weekday&(1<<int(t.Weekday())) != 0
+	switch {
+	case s.dayAny && s.weekdayAny:
+		return true
+	case s.dayAny:
+		return weekday
+	case s.weekdayAny:
+		return day
+	default:
+		return day || weekday
+	}
+}
@@ -0,0 +1,193 @@
+package usecase
+
+import (
+	"errors"
+	"testing"
+	"time"
+	_ "time/tzdata"
+
+	"github.com/sikigasa/github-task-controller/backend/internal/domain/model"
+)
+
+func loadLocation(t *testing.T, name string) *time.Location {
+	t.Helper()
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		t.Fatalf("LoadLocation(%s) error = %v", name, err)
+	}
+	return loc
+}
+
+func TestParseCronInvalid(t *testing.T) {
+	tests := []struct {
+		name string
+		spec string
+	}{
+		{name: "empty", spec: ""},
+		{name: "too few fields", spec: "* * * *"},
+		{name: "too many fields", spec: "* * * * * *"},
+		{name: "minute out of range", spec: "60 * * * *"},
+		{name: "hour out of range", spec: "0 24 * * *"},
+		{name: "day zero", spec: "0 0 0 * *"},
+		{name: "day out of range", spec: "0 0 32 * *"},
+		{name: "month zero", spec: "0 0 1 0 *"},
+		{name: "month out of range", spec: "0 0 1 13 *"},
+		{name: "weekday out of range", spec: "0 0 * * 8"},
+		{name: "negative value", spec: "-1 * * * *"},
+		{name: "zero step", spec: "*/0 * * * *"},
+		{name: "non-numeric step", spec: "*/x * * * *"},
+		{name: "reversed range", spec: "30-10 * * * *"},
+		{name: "open range", spec: "10- * * * *"},
+		{name: "empty list entry", spec: "1,,2 * * * *"},
+		{name: "unknown name", spec: "0 0 * foo *"},
+		{name: "weekday name in month", spec: "0 0 * mon *"},
+		{name: "unknown descriptor", spec: "@fortnightly"},
+		{name: "interval below one second", spec: "@every 500ms"},
+		{name: "invalid interval", spec: "@every soon"},
+		{name: "negative interval", spec: "@every -1m"},
+		{name: "never runs in february", spec: "0 0 30 2 *"},
+		{name: "never runs in 30-day months", spec: "0 0 31 4,6,9,11 *"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := parseCron(tt.spec, time.UTC)
+			if !errors.Is(err, model.ErrInvalidInput) {
+				t.Errorf("parseCron(%q) = %v, %v, want ErrInvalidInput", tt.spec, s, err)
+			}
+		})
+	}
+}
+
+func TestCronScheduleNext(t *testing.T) {
+	tests := []struct {
+		name  string
+		spec  string
+		after time.Time
+		want  time.Time
+	}{
+		// フィールドの範囲・間隔・リスト
+		{name: "every minute", spec: "* * * * *", after: time.Date(2026, 10, 16, 10, 7, 30, 0, time.UTC), want: time.Date(2026, 10, 16, 10, 8, 0, 0, time.UTC)},
+		{name: "every minute on the minute", spec: "* * * * *", after: time.Date(2026, 10, 16, 10, 7, 0, 0, time.UTC), want: time.Date(2026, 10, 16, 10, 8, 0, 0, time.UTC)},
+		{name: "step", spec: "*/15 * * * *", after: time.Date(2026, 10, 16, 10, 7, 0, 0, time.UTC), want: time.Date(2026, 10, 16, 10, 15, 0, 0, time.UTC)},
+		{name: "step to next hour", spec: "*/15 * * * *", after: time.Date(2026, 10, 16, 10, 45, 0, 0, time.UTC), want: time.Date(2026, 10, 16, 11, 0, 0, 0, time.UTC)},
+		{name: "step from start", spec: "5/20 * * * *", after: time.Date(2026, 10, 16, 10, 26, 0, 0, time.UTC), want: time.Date(2026, 10, 16, 10, 45, 0, 0, time.UTC)},
+		{name: "step from start wraps", spec: "5/20 * * * *", after: time.Date(2026, 10, 16, 10, 45, 0, 0, time.UTC), want: time.Date(2026, 10, 16, 11, 5, 0, 0, time.UTC)},
+		{name: "range", spec: "10-12 * * * *", after: time.Date(2026, 10, 16, 10, 12, 0, 0, time.UTC), want: time.Date(2026, 10, 16, 11, 10, 0, 0, time.UTC)},
+		{name: "range with step", spec: "0 9-17/4 * * *", after: time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC), want: time.Date(2026, 10, 16, 13, 0, 0, 0, time.UTC)},
+		{name: "range with step to next day", spec: "0 9-17/4 * * *", after: time.Date(2026, 10, 16, 17, 0, 0, 0, time.UTC), want: time.Date(2026, 10, 17, 9, 0, 0, 0, time.UTC)},
+		{name: "list", spec: "0,30 8,20 * * *", after: time.Date(2026, 10, 16, 8, 30, 0, 0, time.UTC), want: time.Date(2026, 10, 16, 20, 0, 0, 0, time.UTC)},
+		{name: "maximum values", spec: "59 23 * * *", after: time.Date(2026, 10, 16, 23, 59, 0, 0, time.UTC), want: time.Date(2026, 10, 17, 23, 59, 0, 0, time.UTC)},
+		{name: "month names", spec: "0 12 1 jan,JUL *", after: time.Date(2026, 7, 1, 12, 0, 0, 0, time.UTC), want: time.Date(2027, 1, 1, 12, 0, 0, 0, time.UTC)},
+		// 2026-10-16は金曜日
+		{name: "weekday range", spec: "0 9 * * mon-fri", after: time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC), want: time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC)},
+		{name: "weekday 0 is sunday", spec: "0 0 * * 0", after: time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC), want: time.Date(2026, 10, 18, 0, 0, 0, 0, time.UTC)},
+		{name: "weekday 7 is sunday", spec: "0 0 * * 7", after: time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC), want: time.Date(2026, 10, 18, 0, 0, 0, 0, time.UTC)},
+		{name: "weekday range to 7", spec: "0 0 * * 6-7", after: time.Date(2026, 10, 17, 0, 0, 0, 0, time.UTC), want: time.Date(2026, 10, 18, 0, 0, 0, 0, time.UTC)},
+		// 日と曜日の両方を指定した場合はどちらかに一致すればよい
+		{name: "day or weekday matches weekday", spec: "0 0 13 * fri", after: time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC), want: time.Date(2026, 10, 23, 0, 0, 0, 0, time.UTC)},
+		{name: "day or weekday matches day", spec: "0 0 1 * mon", after: time.Date(2026, 10, 27, 0, 0, 0, 0, time.UTC), want: time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC)},
+		{name: "day only", spec: "0 0 13 * *", after: time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC), want: time.Date(2026, 11, 13, 0, 0, 0, 0, time.UTC)},
+		{name: "weekday only", spec: "0 0 * * fri", after: time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC), want: time.Date(2026, 10, 23, 0, 0, 0, 0, time.UTC)},
+		// 月末の繰り上がり
+		{name: "day 31 skips 30-day months", spec: "0 0 31 * *", after: time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC), want: time.Date(2026, 5, 31, 0, 0, 0, 0, time.UTC)},
+		{name: "day 30 skips february", spec: "0 0 30 * *", after: time.Date(2026, 1, 30, 0, 0, 0, 0, time.UTC), want: time.Date(2026, 3, 30, 0, 0, 0, 0, time.UTC)},
+		{name: "february 29 waits for leap year", spec: "0 0 29 2 *", after: time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC), want: time.Date(2028, 2, 29, 0, 0, 0, 0, time.UTC)},
+		{name: "end of year", spec: "59 23 31 12 *", after: time.Date(2026, 12, 31, 23, 59, 0, 0, time.UTC), want: time.Date(2027, 12, 31, 23, 59, 0, 0, time.UTC)},
+		{name: "next month", spec: "0 0 1 * *", after: time.Date(2026, 12, 31, 12, 0, 0, 0, time.UTC), want: time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC)},
+		// 定義済みの式と@every
+		{name: "hourly", spec: "@hourly", after: time.Date(2026, 10, 16, 10, 7, 0, 0, time.UTC), want: time.Date(2026, 10, 16, 11, 0, 0, 0, time.UTC)},
+		{name: "daily", spec: "@daily", after: time.Date(2026, 10, 16, 10, 7, 0, 0, time.UTC), want: time.Date(2026, 10, 17, 0, 0, 0, 0, time.UTC)},
+		{name: "every", spec: "@every 90m", after: time.Date(2026, 10, 16, 10, 7, 30, 0, time.UTC), want: time.Date(2026, 10, 16, 11, 37, 30, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := parseCron(tt.spec, time.UTC)
+			if err != nil {
+				t.Fatalf("parseCron(%q) error = %v", tt.spec, err)
+			}
+			if got := s.Next(tt.after); !got.Equal(tt.want) {
+				t.Errorf("Next(%v) = %v, want %v", tt.after, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCronScheduleNextTimeZone(t *testing.T) {
+	tokyo := loadLocation(t, "Asia/Tokyo")
+	newYork := loadLocation(t, "America/New_York")
+	santiago := loadLocation(t, "America/Santiago")
+	est := time.FixedZone("EST", -5*60*60)
+	edt := time.FixedZone("EDT", -4*60*60)
+	tests := []struct {
+		name  string
+		spec  string
+		loc   *time.Location
+		after time.Time
+		want  time.Time
+	}{
+		// 時刻はスケジュールのタイムゾーンで解釈する
+		{name: "time zone", spec: "0 9 * * *", loc: tokyo, after: time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC), want: time.Date(2026, 10, 17, 9, 0, 0, 0, tokyo)},
+		// 2026-03-08 02:00（EST）に03:00（EDT）へ進む
+		{name: "spring forward runs after the gap", spec: "30 2 * * *", loc: newYork, after: time.Date(2026, 3, 8, 0, 0, 0, 0, est), want: time.Date(2026, 3, 8, 3, 0, 0, 0, edt)},
+		{name: "spring forward at the start of the gap", spec: "0 2 * * *", loc: newYork, after: time.Date(2026, 3, 7, 2, 0, 0, 0, est), want: time.Date(2026, 3, 8, 3, 0, 0, 0, edt)},
+		{name: "spring forward next day", spec: "30 2 * * *", loc: newYork, after: time.Date(2026, 3, 8, 3, 0, 0, 0, edt), want: time.Date(2026, 3, 9, 2, 30, 0, 0, edt)},
+		{name: "spring forward outside the gap", spec: "30 3 * * *", loc: newYork, after: time.Date(2026, 3, 8, 0, 0, 0, 0, est), want: time.Date(2026, 3, 8, 3, 30, 0, 0, edt)},
+		{name: "spring forward hourly", spec: "15 * * * *", loc: newYork, after: time.Date(2026, 3, 8, 1, 15, 0, 0, est), want: time.Date(2026, 3, 8, 3, 15, 0, 0, edt)},
+		{name: "spring forward hour list", spec: "0 1,2,4 * * *", loc: newYork, after: time.Date(2026, 3, 8, 1, 0, 0, 0, est), want: time.Date(2026, 3, 8, 3, 0, 0, 0, edt)},
+		// 2026-11-01 02:00（EDT）に01:00（EST）へ戻る
+		{name: "fall back first occurrence", spec: "30 1 * * *", loc: newYork, after: time.Date(2026, 11, 1, 0, 0, 0, 0, edt), want: time.Date(2026, 11, 1, 1, 30, 0, 0, edt)},
+		{name: "fall back runs once", spec: "30 1 * * *", loc: newYork, after: time.Date(2026, 11, 1, 1, 30, 0, 0, edt), want: time.Date(2026, 11, 2, 1, 30, 0, 0, est)},
+		{name: "fall back after the repeat", spec: "0 2 * * *", loc: newYork, after: time.Date(2026, 11, 1, 1, 0, 0, 0, edt), want: time.Date(2026, 11, 1, 2, 0, 0, 0, est)},
+		{name: "fall back hourly runs during the repeat", spec: "*/30 * * * *", loc: newYork, after: time.Date(2026, 11, 1, 1, 30, 0, 0, edt), want: time.Date(2026, 11, 1, 1, 0, 0, 0, est)},
+		{name: "fall back hourly after the repeat", spec: "*/30 * * * *", loc: newYork, after: time.Date(2026, 11, 1, 1, 30, 0, 0, est), want: time.Date(2026, 11, 1, 2, 0, 0, 0, est)},
+		// サンティアゴは2026-09-06 00:00に01:00へ進む（日付の変わり目がない）
+		{name: "midnight gap", spec: "0 0 * * *", loc: santiago, after: time.Date(2026, 9, 5, 12, 0, 0, 0, santiago), want: time.Date(2026, 9, 6, 1, 0, 0, 0, santiago)},
+		{name: "midnight gap day only", spec: "0 0 6 9 *", loc: santiago, after: time.Date(2026, 9, 1, 0, 0, 0, 0, santiago), want: time.Date(2026, 9, 6, 1, 0, 0, 0, santiago)},
+		{name: "midnight gap hourly", spec: "15 * * * *", loc: santiago, after: time.Date(2026, 9, 5, 23, 15, 0, 0, santiago), want: time.Date(2026, 9, 6, 1, 15, 0, 0, santiago)},
+		{name: "midnight gap later hour", spec: "0 9 * * *", loc: santiago, after: time.Date(2026, 9, 5, 12, 0, 0, 0, santiago), want: time.Date(2026, 9, 6, 9, 0, 0, 0, santiago)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := parseCron(tt.spec, tt.loc)
+			if err != nil {
+				t.Fatalf("parseCron(%q) error = %v", tt.spec, err)
+			}
+			got := s.Next(tt.after)
+			if !got.Equal(tt.want) {
+				t.Errorf("Next(%v) = %v, want %v", tt.after, got.In(tt.loc), tt.want.In(tt.loc))
+			}
+		})
+	}
+}
+
+// TestCronScheduleNextAdvances は夏時間の切り替えをまたいでも実行時刻が進み続けることを確認する
+func TestCronScheduleNextAdvances(t *testing.T) {
+	newYork := loadLocation(t, "America/New_York")
+	specs := []string{"* * * * *", "*/7 * * * *", "0 * * * *", "30 1 * * *", "0 2 * * *", "30 2 * * *", "0 0,2,23 * * *", "0 0 * * *"}
+	for _, spec := range specs {
+		t.Run(spec, func(t *testing.T) {
+			s, err := parseCron(spec, newYork)
+			if err != nil {
+				t.Fatalf("parseCron(%q) error = %v", spec, err)
+			}
+			for _, start := range []time.Time{
+				time.Date(2026, 3, 7, 0, 0, 0, 0, newYork),
+				time.Date(2026, 10, 31, 0, 0, 0, 0, newYork),
+			} {
+				end := start.Add(72 * time.Hour)
+				runs := map[int64]bool{}
+				for t1 := start; t1.Before(end); {
+					next := s.Next(t1)
+					if !next.After(t1) {
+						t.Fatalf("Next(%v) = %v, want a time after it", t1, next)
+					}
+					if runs[next.Unix()] {
+						t.Fatalf("Next(%v) = %v, want no duplicate runs", t1, next)
+					}
+					runs[next.Unix()] = true
+					t1 = next
+				}
+			}
+		})
+	}
+}
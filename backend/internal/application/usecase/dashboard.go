@@ -0,0 +1,112 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/sikigasa/github-task-controller/backend/internal/domain/model"
+	"github.com/sikigasa/github-task-controller/backend/internal/domain/repository"
+)
+
+// DashboardUsecase はホーム画面のダッシュボードに関するユースケース
+type DashboardUsecase struct {
+	projectRepo     repository.ProjectRepository
+	taskRepo        repository.TaskRepository
+	statusEventRepo repository.TaskStatusEventRepository
+	settingsRepo    repository.SettingsRepository
+	projectUsecase  *ProjectUsecase
+	githubUsecase   *GithubUsecase
+	logger          *slog.Logger
+}
+
+// NewDashboardUsecase は新しいDashboardUsecaseを作成する
+func NewDashboardUsecase(
+	projectRepo repository.ProjectRepository,
+	taskRepo repository.TaskRepository,
+	statusEventRepo repository.TaskStatusEventRepository,
+	settingsRepo repository.SettingsRepository,
+	projectUsecase *ProjectUsecase,
+	githubUsecase *GithubUsecase,
+	logger *slog.Logger,
+) *DashboardUsecase {
+	return &DashboardUsecase{
+		projectRepo:     projectRepo,
+		taskRepo:        taskRepo,
+		statusEventRepo: statusEventRepo,
+		settingsRepo:    settingsRepo,
+		projectUsecase:  projectUsecase,
+		githubUsecase:   githubUsecase,
+		logger:          logger,
+	}
+}
+
+// Dashboard はホーム画面に必要な情報をまとめたもの
+type Dashboard struct {
+	Projects       []*model.ProjectDetail     `json:"projects"`
+	DueTasks       *model.DueTasks            `json:"due_tasks"`
+	RecentActivity []*model.DashboardActivity `json:"recent_activity"`
+	Github         *GithubConnectionStatus    `json:"github"`
+}
+
+// GetDashboard はユーザーのダッシュボードを取得する
+// プロジェクト数に関わらずクエリ数が一定になるよう、関連データは全プロジェクト分をまとめて取得する
+func (u *DashboardUsecase) GetDashboard(ctx context.Context, userID string) (*Dashboard, error) {
+	settings, err := findSettings(ctx, u.settingsRepo, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	projects, err := u.projectRepo.FindByUserID(ctx, userID, model.ListOptions{})
+	if err != nil {
+		u.logger.ErrorContext(ctx, "failed to load projects for dashboard", "error", err, "user_id", userID)
+		return nil, fmt.Errorf("failed to load projects for dashboard: %w", err)
+	}
+
+	details, err := u.projectUsecase.ExpandProjects(ctx, userID, projects, model.ProjectExpand{Stats: true, Github: true})
+	if err != nil {
+		return nil, err
+	}
+
+	projectIDs := make([]string, 0, len(projects))
+	for _, p := range projects {
+		projectIDs = append(projectIDs, p.ID)
+	}
+
+	// 今日の終わりまでに期限が来る未完了タスクを取得し、今日と期限切れに分ける
+	now := time.Now()
+	tomorrowStart := model.StartOfDay(now, settings.Location()).AddDate(0, 0, 1)
+	dueTasks, err := u.taskRepo.FindDueByProjectIDs(ctx, projectIDs, tomorrowStart)
+	if err != nil {
+		u.logger.ErrorContext(ctx, "failed to load due tasks for dashboard", "error", err, "user_id", userID)
+		return nil, fmt.Errorf("failed to load due tasks for dashboard: %w", err)
+	}
+
+	events, err := u.statusEventRepo.FindRecentByProjectIDs(ctx, projectIDs, model.DashboardActivityLimit)
+	if err != nil {
+		u.logger.ErrorContext(ctx, "failed to load recent activity for dashboard", "error", err, "user_id", userID)
+		return nil, fmt.Errorf("failed to load recent activity for dashboard: %w", err)
+	}
+	taskIDs := make([]string, 0, len(events))
+	for _, e := range events {
+		taskIDs = append(taskIDs, e.TaskID)
+	}
+	activityTasks, err := u.taskRepo.FindByIDs(ctx, taskIDs)
+	if err != nil {
+		u.logger.ErrorContext(ctx, "failed to load activity tasks for dashboard", "error", err, "user_id", userID)
+		return nil, fmt.Errorf("failed to load activity tasks for dashboard: %w", err)
+	}
+
+	githubStatus, err := u.githubUsecase.GetConnectionStatus(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Dashboard{
+		Projects:       details,
+		DueTasks:       model.NewDueTasks(now, settings.Location(), dueTasks),
+		RecentActivity: model.NewDashboardActivities(events, activityTasks, projects),
+		Github:         githubStatus,
+	}, nil
+}
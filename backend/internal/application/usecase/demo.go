@@ -0,0 +1,156 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sikigasa/github-task-controller/backend/internal/domain/model"
+	"github.com/sikigasa/github-task-controller/backend/internal/domain/repository"
+)
+
+// DemoUsecase はデモモード（サインインせずに試す）のゲストユーザーに関するユースケース
+// ゲストユーザーにはサンプルのプロジェクトを用意し、有効期限を過ぎたらデータごと削除する
+type DemoUsecase struct {
+	userRepo         repository.UserRepository
+	guestUserRepo    repository.GuestUserRepository
+	projectUsecase   *ProjectUsecase
+	taskUsecase      *TaskUsecase
+	milestoneUsecase *MilestoneUsecase
+	guestTTL         time.Duration
+	logger           *slog.Logger
+}
+
+// NewDemoUsecase は新しいDemoUsecaseを作成する
+// guestTTLはゲストユーザーを作成してから削除するまでの期間
+func NewDemoUsecase(
+	userRepo repository.UserRepository,
+	guestUserRepo repository.GuestUserRepository,
+	projectUsecase *ProjectUsecase,
+	taskUsecase *TaskUsecase,
+	milestoneUsecase *MilestoneUsecase,
+	guestTTL time.Duration,
+	logger *slog.Logger,
+) *DemoUsecase {
+	return &DemoUsecase{
+		userRepo:         userRepo,
+		guestUserRepo:    guestUserRepo,
+		projectUsecase:   projectUsecase,
+		taskUsecase:      taskUsecase,
+		milestoneUsecase: milestoneUsecase,
+		guestTTL:         guestTTL,
+		logger:           logger,
+	}
+}
+
+// CreateGuest はサンプルのプロジェクトを用意したゲストユーザーを作成する
+func (u *DemoUsecase) CreateGuest(ctx context.Context) (*model.User, error) {
+	now := time.Now()
+	id := uuid.New().String()
+	user := &model.User{
+		ID:        id,
+		Email:     "guest-" + id + "@" + model.GuestEmailDomain,
+		Name:      "ゲスト",
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := u.userRepo.Create(ctx, user); err != nil {
+		return nil, fmt.Errorf("failed to create guest user: %w", err)
+	}
+
+	// 削除漏れを防ぐため、サンプルデータより先に有効期限を記録する
+	guest := &model.GuestUser{UserID: id, ExpiresAt: now.Add(u.guestTTL), CreatedAt: now}
+	if err := u.guestUserRepo.Create(ctx, guest); err != nil {
+		if delErr := u.userRepo.Delete(ctx, id); delErr != nil {
+			u.logger.ErrorContext(ctx, "failed to clean up guest user", "error", delErr, "user_id", id)
+		}
+		return nil, fmt.Errorf("failed to create guest user: %w", err)
+	}
+
+	if err := u.seed(ctx, id, now); err != nil {
+		// サンプルデータが欠けても試用はできるため、ログインは続行する
+		u.logger.ErrorContext(ctx, "failed to seed guest sample data", "error", err, "user_id", id)
+	}
+
+	u.logger.InfoContext(ctx, "guest user created", "user_id", id, "expires_at", guest.ExpiresAt)
+	return user, nil
+}
+
+// demoTask はサンプルのタスクの定義（日付はゲストの作成日からの相対日数）
+type demoTask struct {
+	title     string
+	status    model.TaskStatus
+	priority  model.TaskPriority
+	startDay  int
+	endDay    int
+	estimate  float64
+	milestone bool
+}
+
+// demoTasks はゲストユーザーに用意するサンプルのタスク
+var demoTasks = []demoTask{
+	{"要件を整理する", model.TaskStatusDone, model.TaskPriorityHigh, -14, -10, 3, true},
+	{"画面のワイヤーフレームを作成する", model.TaskStatusDone, model.TaskPriorityMedium, -10, -6, 5, true},
+	{"APIを実装する", model.TaskStatusInProgress, model.TaskPriorityHigh, -5, 3, 8, true},
+	{"画面を実装する", model.TaskStatusInProgress, model.TaskPriorityMedium, -2, 6, 8, true},
+	{"結合テストを行う", model.TaskStatusTodo, model.TaskPriorityMedium, 5, 9, 5, true},
+	{"リリース手順をまとめる", model.TaskStatusTodo, model.TaskPriorityLow, 8, 10, 2, false},
+	{"ドキュメントを更新する", model.TaskStatusTodo, model.TaskPriorityLow, 10, 14, 3, false},
+}
+
+// seed はゲストユーザーにサンプルのプロジェクト・マイルストーン・タスクを作成する
+func (u *DemoUsecase) seed(ctx context.Context, userID string, now time.Time) error {
+	project, err := u.projectUsecase.CreateProject(ctx, userID, "サンプルプロジェクト",
+		"デモ用のプロジェクトです。タスクの追加やステータスの変更を自由に試せます。", model.EstimateUnitPoints)
+	if err != nil {
+		return err
+	}
+
+	today := now.Truncate(24 * time.Hour)
+	dueDate := today.AddDate(0, 0, 10)
+	milestone, err := u.milestoneUsecase.CreateMilestone(ctx, userID, project.ID, &model.CreateMilestoneRequest{
+		Title:   "v1.0 リリース",
+		DueDate: &dueDate,
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, t := range demoTasks {
+		status := t.status
+		start := today.AddDate(0, 0, t.startDay)
+		end := today.AddDate(0, 0, t.endDay)
+		estimate := t.estimate
+		priority := t.priority
+		req := &model.CreateTaskRequest{
+			ProjectID: project.ID,
+			Title:     t.title,
+			Status:    &status,
+			Priority:  &priority,
+			StartDate: &start,
+			EndDate:   &end,
+			Estimate:  &estimate,
+		}
+		if t.milestone {
+			req.MilestoneID = &milestone.ID
+		}
+		if _, err := u.taskUsecase.createTask(ctx, req); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// PurgeExpired は有効期限を過ぎたゲストユーザーをデータごと削除する
+func (u *DemoUsecase) PurgeExpired(ctx context.Context, now time.Time) error {
+	deleted, err := u.guestUserRepo.DeleteExpired(ctx, now)
+	if err != nil {
+		return fmt.Errorf("failed to purge guest users: %w", err)
+	}
+	if deleted > 0 {
+		u.logger.InfoContext(ctx, "expired guest users purged", "count", deleted)
+	}
+	return nil
+}
@@ -0,0 +1,130 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/sikigasa/github-task-controller/backend/internal/domain/model"
+	"github.com/sikigasa/github-task-controller/backend/internal/domain/repository"
+	"github.com/sikigasa/github-task-controller/backend/internal/infrastructure/mail"
+)
+
+// DigestUsecase は週次ダイジェストメールに関するユースケース
+type DigestUsecase struct {
+	settingsRepo    repository.SettingsRepository
+	userRepo        repository.UserRepository
+	projectRepo     repository.ProjectRepository
+	taskRepo        repository.TaskRepository
+	statusEventRepo repository.TaskStatusEventRepository
+	sender          mail.Sender
+	sendHour        int
+	logger          *slog.Logger
+}
+
+// NewDigestUsecase は新しいDigestUsecaseを作成する
+// sendHourは週の開始日のうち配信を始める時刻（ユーザーのタイムゾーンでの時）
+func NewDigestUsecase(
+	settingsRepo repository.SettingsRepository,
+	userRepo repository.UserRepository,
+	projectRepo repository.ProjectRepository,
+	taskRepo repository.TaskRepository,
+	statusEventRepo repository.TaskStatusEventRepository,
+	sender mail.Sender,
+	sendHour int,
+	logger *slog.Logger,
+) *DigestUsecase {
+	return &DigestUsecase{
+		settingsRepo:    settingsRepo,
+		userRepo:        userRepo,
+		projectRepo:     projectRepo,
+		taskRepo:        taskRepo,
+		statusEventRepo: statusEventRepo,
+		sender:          sender,
+		sendHour:        sendHour,
+		logger:          logger,
+	}
+}
+
+// BuildDigest はnowまでの直近1週間のダイジェストを作成する
+func (u *DigestUsecase) BuildDigest(ctx context.Context, userID string, now time.Time) (*model.WeeklyDigest, error) {
+	since := now.AddDate(0, 0, -7)
+
+	projects, err := u.projectRepo.FindByUserID(ctx, userID, model.ListOptions{})
+	if err != nil {
+		u.logger.ErrorContext(ctx, "failed to load projects for digest", "error", err, "user_id", userID)
+		return nil, fmt.Errorf("failed to load projects for digest: %w", err)
+	}
+
+	projectIDs := make([]string, 0, len(projects))
+	for _, p := range projects {
+		projectIDs = append(projectIDs, p.ID)
+	}
+
+	tasks, err := u.taskRepo.FindByProjectIDs(ctx, projectIDs)
+	if err != nil {
+		u.logger.ErrorContext(ctx, "failed to load tasks for digest", "error", err, "user_id", userID)
+		return nil, fmt.Errorf("failed to load tasks for digest: %w", err)
+	}
+
+	events, err := u.statusEventRepo.FindByProjectIDsSince(ctx, projectIDs, since)
+	if err != nil {
+		u.logger.ErrorContext(ctx, "failed to load status events for digest", "error", err, "user_id", userID)
+		return nil, fmt.Errorf("failed to load status events for digest: %w", err)
+	}
+
+	return model.NewWeeklyDigest(userID, since, now, projects, tasks, events), nil
+}
+
+// SendDueDigests は配信を希望するユーザーのうち、今週分が未配信かつ配信時刻を過ぎたユーザーへダイジェストを送信する
+// 配信時刻はユーザーのタイムゾーンで週の開始日のsendHour時とする。ユーザーごとの失敗はログに記録して処理を続ける
+func (u *DigestUsecase) SendDueDigests(ctx context.Context, now time.Time) error {
+	subscribers, err := u.settingsRepo.FindDigestSubscribers(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to find digest subscribers: %w", err)
+	}
+
+	for _, settings := range subscribers {
+		weekStart := model.NewIterations(now, settings.Location(), settings.WeekStartDay, 1, 1)[0].Start
+		if now.Before(weekStart.Add(time.Duration(u.sendHour) * time.Hour)) {
+			continue
+		}
+		if settings.LastDigestSentAt != nil && !settings.LastDigestSentAt.Before(weekStart) {
+			continue
+		}
+
+		if err := u.sendDigest(ctx, settings, now); err != nil {
+			u.logger.ErrorContext(ctx, "failed to send weekly digest", "error", err, "user_id", settings.UserID)
+		}
+	}
+
+	return nil
+}
+
+// sendDigest は1ユーザーへダイジェストを送信し、配信日時を記録する（内容がない場合は送信せずに記録のみ行う）
+func (u *DigestUsecase) sendDigest(ctx context.Context, settings *model.Settings, now time.Time) error {
+	user, err := u.userRepo.FindByID(ctx, settings.UserID)
+	if err != nil {
+		return fmt.Errorf("failed to find user: %w", err)
+	}
+
+	digest, err := u.BuildDigest(ctx, settings.UserID, now)
+	if err != nil {
+		return err
+	}
+
+	if !digest.IsEmpty() {
+		subject, body := digest.Render(settings.Location())
+		if err := u.sender.Send(ctx, mail.Message{To: user.Email, Subject: subject, Body: body}); err != nil {
+			return fmt.Errorf("failed to send digest mail: %w", err)
+		}
+		u.logger.InfoContext(ctx, "weekly digest sent", "user_id", settings.UserID)
+	}
+
+	if err := u.settingsRepo.MarkDigestSent(ctx, settings.UserID, now); err != nil {
+		return fmt.Errorf("failed to mark digest sent: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,94 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"sync"
+
+	"github.com/sikigasa/github-task-controller/backend/internal/domain/model"
+)
+
+// broadcastBuffer は接続ごとに溜めておけるイベントの件数（超えた分は受信が遅い接続には届けない）
+const broadcastBuffer = 32
+
+// EventBroadcaster はドメインイベントをプロジェクトごとの接続（WebSocket等）にリアルタイムに配信する
+// 接続していない間のイベントは保持しないため、再接続したクライアントはAPIで最新の状態を取得し直す
+type EventBroadcaster struct {
+	mu        sync.Mutex
+	listeners map[string]map[chan *model.OutboxEvent]struct{}
+	logger    *slog.Logger
+}
+
+// NewEventBroadcaster は新しいEventBroadcasterを作成する
+func NewEventBroadcaster(logger *slog.Logger) *EventBroadcaster {
+	return &EventBroadcaster{
+		listeners: make(map[string]map[chan *model.OutboxEvent]struct{}),
+		logger:    logger,
+	}
+}
+
+// Listen はプロジェクトのイベントを受け取るチャネルを返す
+// 呼び出し側はプロジェクトを参照できることを確認してから呼び出し、接続を終えたらcancelを呼び出す
+func (b *EventBroadcaster) Listen(projectID string) (<-chan *model.OutboxEvent, func()) {
+	ch := make(chan *model.OutboxEvent, broadcastBuffer)
+
+	b.mu.Lock()
+	if b.listeners[projectID] == nil {
+		b.listeners[projectID] = make(map[chan *model.OutboxEvent]struct{})
+	}
+	b.listeners[projectID][ch] = struct{}{}
+	b.mu.Unlock()
+
+	cancel := func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		delete(b.listeners[projectID], ch)
+		if len(b.listeners[projectID]) == 0 {
+			delete(b.listeners, projectID)
+		}
+	}
+	return ch, cancel
+}
+
+// Broadcast はイベントをプロジェクトの接続に配信するイベントバスの購読者
+// 受信が遅い接続を待たないため失敗することはない（バッファがいっぱいの接続には届けない）
+func (b *EventBroadcaster) Broadcast(ctx context.Context, event *model.OutboxEvent) error {
+	projectID := eventProjectID(event)
+	if projectID == "" {
+		return nil
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for ch := range b.listeners[projectID] {
+		select {
+		case ch <- event:
+		default:
+			b.logger.WarnContext(ctx, "event dropped for slow listener", "event_id", event.ID, "project_id", projectID)
+		}
+	}
+	return nil
+}
+
+// eventProjectID はイベントが属するプロジェクトのIDを返す（判別できない場合は空）
+func eventProjectID(event *model.OutboxEvent) string {
+	if event.AggregateType == model.AggregateProject {
+		return event.AggregateID
+	}
+
+	// タスクのイベントはペイロードのproject_id（task.status_changedはtask.project_id）を使う
+	var payload struct {
+		ProjectID string `json:"project_id"`
+		Task      *struct {
+			ProjectID string `json:"project_id"`
+		} `json:"task"`
+	}
+	if err := json.Unmarshal(event.Payload, &payload); err != nil {
+		return ""
+	}
+	if payload.Task != nil {
+		return payload.Task.ProjectID
+	}
+	return payload.ProjectID
+}
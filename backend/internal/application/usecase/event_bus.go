@@ -0,0 +1,38 @@
+package usecase
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/sikigasa/github-task-controller/backend/internal/domain/model"
+)
+
+// EventSubscriber はイベントバスに発行されたドメインイベントを受け取る関数
+// 配信は少なくとも1回行われる（いずれかの購読者が失敗するとすべての購読者に再配信される）ため、
+// イベントのIDで重複を除くか、何度受け取っても結果が変わらないように実装する
+type EventSubscriber func(ctx context.Context, event *model.OutboxEvent) error
+
+// EventBus はユースケースがドメインイベントを発行し、登録した購読者に届けるイベントバス
+// 監査ログ・リアルタイム配信・GitHub同期などの関心事は購読者として実装し、CRUDのユースケースからは切り離す
+type EventBus interface {
+	// Publish はイベントを発行する
+	// 変更と同じTransactor.WithinTxのトランザクション内で呼び出し、コミットされた場合だけ購読者に届く
+	Publish(ctx context.Context, eventType, aggregateType, aggregateID string, data any) error
+	// Subscribe はeventTypesのイベント（省略した場合はすべてのイベント）を受け取る購読者を登録する（配信の開始前に呼び出す）
+	Subscribe(name string, subscriber EventSubscriber, eventTypes ...string)
+}
+
+// NewAuditLogSubscriber はドメインイベントを監査ログとして記録する購読者を作成する
+func NewAuditLogSubscriber(logger *slog.Logger) EventSubscriber {
+	return func(ctx context.Context, event *model.OutboxEvent) error {
+		logger.InfoContext(ctx, "audit",
+			"event_id", event.ID,
+			"event_type", event.Type,
+			"aggregate_type", event.AggregateType,
+			"aggregate_id", event.AggregateID,
+			"sequence", event.Sequence,
+			"occurred_at", event.OccurredAt,
+		)
+		return nil
+	}
+}
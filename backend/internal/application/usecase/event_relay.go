@@ -0,0 +1,54 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/sikigasa/github-task-controller/backend/internal/domain/model"
+	"github.com/sikigasa/github-task-controller/backend/internal/infrastructure/broker"
+)
+
+// relayedEvent はメッセージブローカーに送るドメインイベント（アウトボックスの配信状況は含めない）
+type relayedEvent struct {
+	ID            string          `json:"id"`
+	Sequence      int64           `json:"sequence"`
+	Type          string          `json:"type"`
+	AggregateType string          `json:"aggregate_type"`
+	AggregateID   string          `json:"aggregate_id"`
+	Payload       json.RawMessage `json:"payload"`
+	OccurredAt    time.Time       `json:"occurred_at"`
+}
+
+// NewEventRelaySubscriber はドメインイベントをメッセージブローカーに中継する購読者を作成する
+// アウトボックスは同じ集約のイベントを前のイベントの配信が終わるまで取得しないため、集約ごとの順序で送信される
+// 送信に失敗した場合は再試行されるため、受信側はイベントのIDで重複を除く
+// （デッドレターになったイベントは送信されず、同じ集約の後続のイベントの送信が再開される）
+func NewEventRelaySubscriber(publisher broker.Publisher) EventSubscriber {
+	return func(ctx context.Context, event *model.OutboxEvent) error {
+		body, err := json.Marshal(relayedEvent{
+			ID:            event.ID,
+			Sequence:      event.Sequence,
+			Type:          event.Type,
+			AggregateType: event.AggregateType,
+			AggregateID:   event.AggregateID,
+			Payload:       event.Payload,
+			OccurredAt:    event.OccurredAt,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to marshal relayed event: %w", err)
+		}
+
+		err = publisher.Publish(ctx, broker.Message{
+			ID:   event.ID,
+			Type: event.Type,
+			Key:  event.AggregateType + ":" + event.AggregateID,
+			Body: body,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to relay event: %w", err)
+		}
+		return nil
+	}
+}
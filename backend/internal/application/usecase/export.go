@@ -0,0 +1,329 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sikigasa/github-task-controller/backend/internal/domain/model"
+	"github.com/sikigasa/github-task-controller/backend/internal/domain/repository"
+	"github.com/sikigasa/github-task-controller/backend/internal/infrastructure/export"
+	"github.com/sikigasa/github-task-controller/backend/internal/infrastructure/mail"
+	"github.com/sikigasa/github-task-controller/backend/internal/infrastructure/storage"
+)
+
+const (
+	// exportQueueSize は処理待ちのエクスポートを保持するキューの長さ
+	exportQueueSize = 100
+	// exportPrefix はエクスポートしたファイルを保存するキーのプレフィックス
+	exportPrefix = "exports/"
+	// exportPruneInterval は保存期間を過ぎたエクスポートを削除する間隔
+	exportPruneInterval = time.Hour
+)
+
+// ExportUsecase はレポートのエクスポートに関するユースケース
+// エクスポートはキューに積んでRunのワーカーが非同期に生成し、完了したらメールでダウンロードリンクを通知する
+// 生成したファイルはStorageに保存し、保存期間を過ぎたものはRunのワーカーが削除する
+type ExportUsecase struct {
+	exportRepo    repository.ReportExportRepository
+	userRepo      repository.UserRepository
+	reportUsecase *ReportUsecase
+	sender        mail.Sender
+	storage       storage.Storage
+	retention     time.Duration
+	baseURL       string
+	queue         chan string
+	logger        *slog.Logger
+}
+
+// NewExportUsecase は新しいExportUsecaseを作成する
+// retentionは完了したエクスポートを残す期間（0の場合は削除しない）、baseURLはダウンロードリンクの組み立てに使うAPIの公開URL
+func NewExportUsecase(
+	exportRepo repository.ReportExportRepository,
+	userRepo repository.UserRepository,
+	reportUsecase *ReportUsecase,
+	sender mail.Sender,
+	storage storage.Storage,
+	retention time.Duration,
+	baseURL string,
+	logger *slog.Logger,
+) *ExportUsecase {
+	return &ExportUsecase{
+		exportRepo:    exportRepo,
+		userRepo:      userRepo,
+		reportUsecase: reportUsecase,
+		sender:        sender,
+		storage:       storage,
+		retention:     retention,
+		baseURL:       baseURL,
+		queue:         make(chan string, exportQueueSize),
+		logger:        logger,
+	}
+}
+
+// RequestExport はエクスポートを受け付けてキューに積む（生成は非同期に行う）
+func (u *ExportUsecase) RequestExport(ctx context.Context, userID string, req *model.CreateReportExportRequest) (*model.ReportExport, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	if req.Report.RequiresProject() {
+		if _, err := u.reportUsecase.authorizeProject(ctx, userID, req.ProjectID); err != nil {
+			return nil, err
+		}
+	}
+
+	params := req.ExportParams.WithDefaults(req.Report)
+	if err := validateExportParams(params); err != nil {
+		return nil, err
+	}
+
+	exp := &model.ReportExport{
+		ID:        uuid.New().String(),
+		UserID:    userID,
+		Report:    req.Report,
+		Format:    req.Format,
+		Params:    params,
+		Status:    model.ExportStatusPending,
+		CreatedAt: time.Now(),
+	}
+	if err := u.exportRepo.Create(ctx, exp); err != nil {
+		return nil, fmt.Errorf("failed to create report export: %w", err)
+	}
+
+	// キューが満杯の場合は未処理のまま残し、次回起動時に処理する
+	select {
+	case u.queue <- exp.ID:
+	default:
+		u.logger.WarnContext(ctx, "export queue is full", "export_id", exp.ID)
+	}
+
+	return exp, nil
+}
+
+// validateExportParams は集計パラメータが各レポートAPIと同じ範囲内であることを検証する
+func validateExportParams(p model.ExportParams) error {
+	switch {
+	case p.IterationWeeks < 1 || p.IterationWeeks > MaxIterationWeeks:
+		return fmt.Errorf("iteration_weeks must be between 1 and %d: %w", MaxIterationWeeks, model.ErrInvalidInput)
+	case p.Iterations < 1 || p.Iterations > MaxIterations:
+		return fmt.Errorf("iterations must be between 1 and %d: %w", MaxIterations, model.ErrInvalidInput)
+	case p.Days < 1 || p.Days > MaxCumulativeFlowDays:
+		return fmt.Errorf("days must be between 1 and %d: %w", MaxCumulativeFlowDays, model.ErrInvalidInput)
+	case p.DueSoonDays < 0 || p.DueSoonDays > MaxDueSoonDays:
+		return fmt.Errorf("due_soon_days must be between 0 and %d: %w", MaxDueSoonDays, model.ErrInvalidInput)
+	case p.StuckDays < 1 || p.StuckDays > MaxStuckDays:
+		return fmt.Errorf("stuck_days must be between 1 and %d: %w", MaxStuckDays, model.ErrInvalidInput)
+	}
+	return nil
+}
+
+// GetExport はエクスポートの処理状況を取得する
+func (u *ExportUsecase) GetExport(ctx context.Context, userID, id string) (*model.ReportExport, error) {
+	exp, err := u.exportRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find report export: %w", err)
+	}
+	if exp.UserID != userID {
+		return nil, model.ErrForbidden
+	}
+	return exp, nil
+}
+
+// ExportFile はダウンロードするエクスポートのファイル（URLとContentのどちらか一方を設定する）
+type ExportFile struct {
+	// URL は保存先から直接ダウンロードできる署名付きURL
+	URL string
+	// Content はAPIから返すファイルの内容（呼び出し側で閉じる）
+	Content io.ReadCloser
+}
+
+// Download は完了したエクスポートとダウンロードするファイルを取得する（未完了の場合はErrConflict）
+// 保存先が署名付きURLを発行できる場合はURLを、それ以外はファイルの内容を返す
+func (u *ExportUsecase) Download(ctx context.Context, userID, id string) (*model.ReportExport, *ExportFile, error) {
+	exp, err := u.GetExport(ctx, userID, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	if exp.Status != model.ExportStatusCompleted {
+		return nil, nil, fmt.Errorf("report export is %s: %w", exp.Status, model.ErrConflict)
+	}
+
+	// Storageに保存する前に完了したエクスポートはデータベースに内容がある
+	content, err := u.exportRepo.FindContent(ctx, id)
+	if err == nil {
+		return exp, &ExportFile{Content: io.NopCloser(bytes.NewReader(content))}, nil
+	}
+	if !errors.Is(err, model.ErrNotFound) {
+		return nil, nil, fmt.Errorf("failed to find report export content: %w", err)
+	}
+
+	key := exportPrefix + id
+	url, err := u.storage.SignedURL(ctx, key, storage.Download{FileName: exp.FileName, ContentType: exp.ContentType})
+	if err == nil {
+		return exp, &ExportFile{URL: url}, nil
+	}
+	if !errors.Is(err, storage.ErrSignedURLUnsupported) {
+		return nil, nil, fmt.Errorf("failed to sign report export url: %w", err)
+	}
+
+	f, err := u.storage.Get(ctx, key)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to open report export: %w", err)
+	}
+	return exp, &ExportFile{Content: f}, nil
+}
+
+// Run はctxがキャンセルされるまでキューのエクスポートを順に生成する
+// 起動時には前回処理されずに残ったエクスポートも処理する
+func (u *ExportUsecase) Run(ctx context.Context) {
+	pending, err := u.exportRepo.FindPendingIDs(ctx)
+	if err != nil {
+		u.logger.ErrorContext(ctx, "failed to load pending report exports", "error", err)
+	}
+	for _, id := range pending {
+		u.process(ctx, id)
+	}
+
+	ticker := time.NewTicker(exportPruneInterval)
+	defer ticker.Stop()
+	u.prune(ctx)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case id := <-u.queue:
+			u.process(ctx, id)
+		case <-ticker.C:
+			u.prune(ctx)
+		}
+	}
+}
+
+// prune は保存期間を過ぎたエクスポートとファイルを削除する（retentionが0の場合は何もしない）
+func (u *ExportUsecase) prune(ctx context.Context) {
+	if u.retention <= 0 {
+		return
+	}
+	before := time.Now().Add(-u.retention)
+
+	deleted, err := u.exportRepo.DeleteFinishedBefore(ctx, before)
+	if err != nil {
+		u.logger.ErrorContext(ctx, "failed to delete expired report exports", "error", err)
+		return
+	}
+	// ファイルはエクスポートの完了時に保存するため、同じ期限で削除する
+	files, err := storage.DeleteBefore(ctx, u.storage, exportPrefix, before)
+	if err != nil {
+		u.logger.ErrorContext(ctx, "failed to delete expired report export files", "error", err)
+	}
+	if deleted > 0 || files > 0 {
+		u.logger.InfoContext(ctx, "expired report exports deleted", "exports", deleted, "files", files)
+	}
+}
+
+// process はエクスポートを生成して保存し、結果をユーザーに通知する
+func (u *ExportUsecase) process(ctx context.Context, id string) {
+	exp, err := u.exportRepo.FindByID(ctx, id)
+	if err != nil {
+		u.logger.ErrorContext(ctx, "failed to load report export", "error", err, "export_id", id)
+		return
+	}
+	if exp.Status != model.ExportStatusPending {
+		return
+	}
+
+	table, err := u.buildTable(ctx, exp)
+	var content []byte
+	var contentType string
+	if err == nil {
+		content, contentType, err = export.Render(exp.Format, table)
+	}
+	if err == nil {
+		err = u.storage.Put(ctx, exportPrefix+id, bytes.NewReader(content))
+	}
+
+	now := time.Now()
+	if err != nil {
+		u.logger.ErrorContext(ctx, "failed to generate report export", "error", err, "export_id", id)
+		if err := u.exportRepo.Fail(ctx, id, err.Error(), now); err != nil {
+			u.logger.ErrorContext(ctx, "failed to mark report export failed", "error", err, "export_id", id)
+		}
+		u.notify(ctx, exp, fmt.Sprintf("レポート（%s）のエクスポートに失敗しました。時間をおいて再度お試しください。\n", exp.Report))
+		return
+	}
+
+	fileName := fmt.Sprintf("%s-%s.%s", exp.Report, now.Format("20060102-150405"), exp.Format)
+	if err := u.exportRepo.Complete(ctx, id, fileName, contentType, now); err != nil {
+		u.logger.ErrorContext(ctx, "failed to save report export", "error", err, "export_id", id)
+		return
+	}
+
+	u.logger.InfoContext(ctx, "report export completed", "export_id", id, "format", exp.Format)
+	u.notify(ctx, exp, fmt.Sprintf("レポート（%s）のエクスポートが完了しました。以下のリンクからダウンロードできます（ログインが必要です）。\n\n%s/api/v1/reports/exports/%s/download\n",
+		exp.Report, u.baseURL, id))
+}
+
+// buildTable はエクスポートの種類に応じてレポートを集計し、表形式にする
+func (u *ExportUsecase) buildTable(ctx context.Context, exp *model.ReportExport) (model.ReportTable, error) {
+	p := exp.Params
+	switch exp.Report {
+	case model.ExportReportVelocity:
+		report, err := u.reportUsecase.GetVelocity(ctx, exp.UserID, p.ProjectID, p.IterationWeeks, p.Iterations)
+		if err != nil {
+			return model.ReportTable{}, err
+		}
+		return report.Table(), nil
+	case model.ExportReportCFD:
+		cfd, err := u.reportUsecase.GetCumulativeFlow(ctx, exp.UserID, p.ProjectID, p.Days)
+		if err != nil {
+			return model.ReportTable{}, err
+		}
+		return cfd.Table(), nil
+	case model.ExportReportOverdue:
+		report, err := u.reportUsecase.GetOverdueReport(ctx, exp.UserID, p.DueSoonDays)
+		if err != nil {
+			return model.ReportTable{}, err
+		}
+		return report.Table(), nil
+	case model.ExportReportStats:
+		stats, err := u.reportUsecase.GetUserStats(ctx, exp.UserID, p.Days)
+		if err != nil {
+			return model.ReportTable{}, err
+		}
+		return stats.Table(), nil
+	case model.ExportReportFlowTime:
+		report, err := u.reportUsecase.GetFlowTime(ctx, exp.UserID, p.ProjectID, p.Days)
+		if err != nil {
+			return model.ReportTable{}, err
+		}
+		return report.Table(), nil
+	case model.ExportReportTimeInStatus:
+		report, err := u.reportUsecase.GetTimeInStatus(ctx, exp.UserID, p.ProjectID, p.StuckDays)
+		if err != nil {
+			return model.ReportTable{}, err
+		}
+		return report.Table(), nil
+	default:
+		return model.ReportTable{}, fmt.Errorf("unknown report %q: %w", exp.Report, model.ErrInvalidInput)
+	}
+}
+
+// notify はエクスポートの結果をユーザーにメールで通知する（失敗はログに記録するのみ）
+func (u *ExportUsecase) notify(ctx context.Context, exp *model.ReportExport, body string) {
+	user, err := u.userRepo.FindByID(ctx, exp.UserID)
+	if err != nil {
+		u.logger.ErrorContext(ctx, "failed to find user for export notification", "error", err, "user_id", exp.UserID)
+		return
+	}
+
+	msg := mail.Message{To: user.Email, Subject: "レポートのエクスポート", Body: body}
+	if err := u.sender.Send(ctx, msg); err != nil {
+		u.logger.ErrorContext(ctx, "failed to send export notification", "error", err, "export_id", exp.ID)
+	}
+}
@@ -2,80 +2,166 @@ package usecase
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
+	"time"
 
+	"github.com/sikigasa/github-task-controller/backend/internal/domain/model"
 	"github.com/sikigasa/github-task-controller/backend/internal/domain/repository"
+	"github.com/sikigasa/github-task-controller/backend/internal/infrastructure/auth"
 	"github.com/sikigasa/github-task-controller/backend/internal/infrastructure/github"
 )
 
+// githubSyncLockWait は他のインスタンスが同じプロジェクトを同期している場合に終了を待つ時間
+const githubSyncLockWait = 30 * time.Second
+
+// githubTokenRefreshMargin はOAuthのアクセストークンの期限切れより前にリフレッシュする余裕
+// GitHubへの一連のリクエストの途中で期限切れにならないよう、期限の少し前からリフレッシュする
+const githubTokenRefreshMargin = 5 * time.Minute
+
+// githubTokenRefreshLockWait は他のインスタンスが同じユーザーのトークンをリフレッシュしている場合に終了を待つ時間
+const githubTokenRefreshLockWait = 10 * time.Second
+
 // GithubUsecase はGitHub連携のユースケース
 type GithubUsecase struct {
-	githubAccountRepo repository.GithubAccountRepository
-	projectRepo       repository.ProjectRepository
-	taskRepo          repository.TaskRepository
-	githubService     *github.ProjectService
-	logger            *slog.Logger
+	githubAccountRepo   repository.GithubAccountRepository
+	projectRepo         repository.ProjectRepository
+	taskRepo            repository.TaskRepository
+	taskUsecase         *TaskUsecase
+	taskPullRequestRepo repository.TaskPullRequestRepository
+	taskCommitRepo      repository.TaskCommitRepository
+	fieldMappingRepo    repository.GithubFieldMappingRepository
+	milestoneRepo       repository.MilestoneRepository
+	settingsRepo        repository.SettingsRepository
+	syncOperationRepo   repository.GithubSyncOperationRepository
+	syncUsageRepo       repository.GithubSyncUsageRepository
+	taskSyncRepo        repository.GithubTaskSyncRepository
+	events              EventBus
+	tx                  repository.Transactor
+	locker              repository.Locker
+	githubService       *github.ProjectService
+	// oauthConfig は期限付きのOAuthのトークンをリフレッシュするために使う（nilの場合はリフレッシュしない）
+	oauthConfig    *auth.OAuthConfig
+	branchTemplate string
+	// syncQuotaPerHour はユーザーごとの1時間あたりのGitHubとの同期の上限（0の場合は上限なし）
+	syncQuotaPerHour int
+	logger           *slog.Logger
 }
 
 // NewGithubUsecase は新しいGithubUsecaseを作成する
+// syncQuotaPerHourはユーザーごとの1時間あたりのGitHubとの同期の上限（0の場合は上限なし）
 func NewGithubUsecase(
 	githubAccountRepo repository.GithubAccountRepository,
 	projectRepo repository.ProjectRepository,
 	taskRepo repository.TaskRepository,
+	taskUsecase *TaskUsecase,
+	taskPullRequestRepo repository.TaskPullRequestRepository,
+	taskCommitRepo repository.TaskCommitRepository,
+	fieldMappingRepo repository.GithubFieldMappingRepository,
+	milestoneRepo repository.MilestoneRepository,
+	settingsRepo repository.SettingsRepository,
+	syncOperationRepo repository.GithubSyncOperationRepository,
+	syncUsageRepo repository.GithubSyncUsageRepository,
+	taskSyncRepo repository.GithubTaskSyncRepository,
+	events EventBus,
+	tx repository.Transactor,
+	locker repository.Locker,
 	githubService *github.ProjectService,
+	oauthConfig *auth.OAuthConfig,
+	branchTemplate string,
+	syncQuotaPerHour int,
 	logger *slog.Logger,
 ) *GithubUsecase {
 	return &GithubUsecase{
-		githubAccountRepo: githubAccountRepo,
-		projectRepo:       projectRepo,
-		taskRepo:          taskRepo,
-		githubService:     githubService,
-		logger:            logger,
+		githubAccountRepo:   githubAccountRepo,
+		projectRepo:         projectRepo,
+		taskRepo:            taskRepo,
+		taskUsecase:         taskUsecase,
+		taskPullRequestRepo: taskPullRequestRepo,
+		taskCommitRepo:      taskCommitRepo,
+		fieldMappingRepo:    fieldMappingRepo,
+		milestoneRepo:       milestoneRepo,
+		settingsRepo:        settingsRepo,
+		syncOperationRepo:   syncOperationRepo,
+		syncUsageRepo:       syncUsageRepo,
+		taskSyncRepo:        taskSyncRepo,
+		events:              events,
+		tx:                  tx,
+		locker:              locker,
+		githubService:       githubService,
+		oauthConfig:         oauthConfig,
+		branchTemplate:      branchTemplate,
+		syncQuotaPerHour:    syncQuotaPerHour,
+		logger:              logger,
 	}
 }
 
+// lockProjectSync はプロジェクトのGitHubとの同期を全インスタンスで1つに限定するロックを取得する
+// 他のインスタンスの同期がgithubSyncLockWaitまでに終わらない場合はErrConflictを返す
+func (u *GithubUsecase) lockProjectSync(ctx context.Context, projectID string) (context.Context, func(), error) {
+	return acquireLock(ctx, u.locker, "github_sync:project:"+projectID, githubSyncLockWait)
+}
+
 // GithubConnectionStatus はGitHub連携状態を表す
 type GithubConnectionStatus struct {
 	IsConnected bool   `json:"is_connected"`
 	HasPAT      bool   `json:"has_pat"`
 	Username    string `json:"username,omitempty"`
+	// NeedsReauth はGitHubがトークンを拒否したため、再認証が必要かを表す
+	NeedsReauth      bool                     `json:"needs_reauth"`
+	ReauthReason     model.GithubReauthReason `json:"reauth_reason,omitempty"`
+	ReauthURL        string                   `json:"reauth_url,omitempty"`
+	ReauthRequiredAt *time.Time               `json:"reauth_required_at,omitempty"`
+	// PendingOperations はGitHubの障害のために保留し、接続が戻るのを待っている変更の件数
+	PendingOperations int `json:"pending_operations"`
 }
 
 // GetConnectionStatus はユーザーのGitHub連携状態を取得する
 func (u *GithubUsecase) GetConnectionStatus(ctx context.Context, userID string) (*GithubConnectionStatus, error) {
 	account, err := u.githubAccountRepo.FindByUserID(ctx, userID)
-	if err != nil {
-		return nil, fmt.Errorf("failed to find github account: %w", err)
-	}
-
-	if account == nil {
+	if errors.Is(err, repository.ErrAccountNotFound) {
 		return &GithubConnectionStatus{
 			IsConnected: false,
 			HasPAT:      false,
 		}, nil
 	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find github account: %w", err)
+	}
 
-	return &GithubConnectionStatus{
-		IsConnected: true,
-		HasPAT:      account.HasPAT(),
-		Username:    account.ProviderAccountID,
-	}, nil
+	pending, err := u.syncOperationRepo.CountByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count pending github operations: %w", err)
+	}
+
+	status := &GithubConnectionStatus{
+		IsConnected:       true,
+		HasPAT:            account.HasPAT(),
+		Username:          account.ProviderAccountID,
+		PendingOperations: pending,
+	}
+	if account.NeedsReauth() {
+		status.NeedsReauth = true
+		status.ReauthReason = account.ReauthReason
+		status.ReauthURL = account.ReauthReason.ReauthPath()
+		status.ReauthRequiredAt = account.ReauthRequiredAt
+	}
+	return status, nil
 }
 
-// SavePAT はPATを保存する（簡易実装：本番では暗号化必須）
+// SavePAT はPATを保存する（GithubAccountRepositoryが暗号化して保存する）
 func (u *GithubUsecase) SavePAT(ctx context.Context, userID, pat string) error {
 	account, err := u.githubAccountRepo.FindByUserID(ctx, userID)
+	if errors.Is(err, repository.ErrAccountNotFound) {
+		return fmt.Errorf("github account not found, please login with GitHub first: %w", err)
+	}
 	if err != nil {
 		return fmt.Errorf("failed to find github account: %w", err)
 	}
 
-	if account == nil {
-		return fmt.Errorf("github account not found, please login with GitHub first")
-	}
-
-	// TODO: 本番環境では暗号化する
 	account.PATEncrypted = &pat
+	account.ClearReauth(model.GithubReauthReasonPAT)
 
 	if err := u.githubAccountRepo.Update(ctx, account); err != nil {
 		return fmt.Errorf("failed to update github account: %w", err)
@@ -92,11 +178,9 @@ func (u *GithubUsecase) DeletePAT(ctx context.Context, userID string) error {
 		return fmt.Errorf("failed to find github account: %w", err)
 	}
 
-	if account == nil {
-		return fmt.Errorf("github account not found")
-	}
-
 	account.PATEncrypted = nil
+	// 拒否されたPATを削除した場合はOAuthのトークンで試せるようにする
+	account.ClearReauth(model.GithubReauthReasonPAT)
 
 	if err := u.githubAccountRepo.Update(ctx, account); err != nil {
 		return fmt.Errorf("failed to update github account: %w", err)
@@ -107,14 +191,15 @@ func (u *GithubUsecase) DeletePAT(ctx context.Context, userID string) error {
 }
 
 // GetToken はユーザーのGitHubトークンを取得する（PAT優先、なければOAuthトークン）
+// 使うトークンがGitHubに拒否されて再認証が必要な場合は、GitHubに問い合わせずにGithubReauthErrorを返す
 func (u *GithubUsecase) GetToken(ctx context.Context, userID string) (string, error) {
 	account, err := u.githubAccountRepo.FindByUserID(ctx, userID)
 	if err != nil {
 		return "", fmt.Errorf("failed to find github account: %w", err)
 	}
 
-	if account == nil {
-		return "", fmt.Errorf("github account not found")
+	if account.NeedsReauth() && account.ReauthReason == account.TokenKind() {
+		return "", &model.GithubReauthError{Reason: account.ReauthReason}
 	}
 
 	// PAT優先
@@ -122,45 +207,203 @@ func (u *GithubUsecase) GetToken(ctx context.Context, userID string) (string, er
 		return *account.PATEncrypted, nil
 	}
 
-	// OAuthトークン
+	// OAuthトークン（期限付きのトークンは期限切れの前にリフレッシュする）
 	if account.AccessToken != "" {
+		if u.oauthConfig != nil && account.NeedsTokenRefresh(time.Now(), githubTokenRefreshMargin) {
+			return u.refreshOAuthToken(ctx, account)
+		}
 		return account.AccessToken, nil
 	}
 
-	return "", fmt.Errorf("no valid token found")
+	return "", fmt.Errorf("no valid token found: %w", model.ErrNotFound)
 }
 
-// ListGithubProjects はユーザーのGitHub Projectsを取得する
-func (u *GithubUsecase) ListGithubProjects(ctx context.Context, userID string) ([]github.Project, error) {
+// refreshOAuthToken はOAuthのアクセストークンをリフレッシュトークンで取得し直して保存し、新しいアクセストークンを返す
+// GitHubのリフレッシュトークンは1回しか使えないため、リフレッシュを全インスタンスでユーザーごとに1つに限定し、
+// ロックを待つ間に他のインスタンスがリフレッシュした場合はそのトークンを使う
+// リフレッシュトークンが拒否された場合は再認証が必要な状態にし、GitHubに接続できない場合は期限内であれば今のトークンを使う
+func (u *GithubUsecase) refreshOAuthToken(ctx context.Context, account *model.GithubAccount) (string, error) {
+	lockCtx, unlock, err := acquireLock(ctx, u.locker, "github_token:user:"+account.UserID, githubTokenRefreshLockWait)
+	if err != nil {
+		if time.Now().Before(*account.ExpiresAt) {
+			u.logger.WarnContext(ctx, "using github token without refresh", "reason", err, "user_id", account.UserID)
+			return account.AccessToken, nil
+		}
+		return "", err
+	}
+	defer unlock()
+
+	account, err = u.githubAccountRepo.FindByUserID(lockCtx, account.UserID)
+	if err != nil {
+		return "", fmt.Errorf("failed to find github account: %w", err)
+	}
+	if !account.NeedsTokenRefresh(time.Now(), githubTokenRefreshMargin) {
+		return account.AccessToken, nil
+	}
+
+	token, err := u.oauthConfig.RefreshGithubToken(lockCtx, account.RefreshToken)
+	switch {
+	case errors.Is(err, auth.ErrRefreshTokenRejected):
+		if err := u.githubAccountRepo.MarkReauthRequired(lockCtx, account.UserID, model.GithubReauthReasonOAuth, time.Now()); err != nil {
+			u.logger.ErrorContext(ctx, "failed to record github re-authentication", "error", err, "user_id", account.UserID)
+		}
+		return "", &model.GithubReauthError{Reason: model.GithubReauthReasonOAuth}
+	case err != nil:
+		if time.Now().Before(*account.ExpiresAt) {
+			u.logger.WarnContext(ctx, "using github token without refresh", "reason", err, "user_id", account.UserID)
+			return account.AccessToken, nil
+		}
+		return "", fmt.Errorf("failed to refresh github token: %v: %w", err, github.ErrUnavailable)
+	}
+
+	var expiresAt *time.Time
+	if !token.Expiry.IsZero() {
+		expiresAt = &token.Expiry
+	}
+	if err := u.githubAccountRepo.UpdateOAuthToken(lockCtx, account.UserID, token.AccessToken, token.RefreshToken, expiresAt); err != nil {
+		return "", fmt.Errorf("failed to save refreshed github token: %w", err)
+	}
+	return token.AccessToken, nil
+}
+
+// HandleUnauthorized はGitHubがユーザーのトークンを拒否した（401）場合に、再認証が必要な状態として記録する
+// github.ClientのUnauthorizedHandlerとして登録し、返したGithubReauthErrorを呼び出し元まで伝える
+func (u *GithubUsecase) HandleUnauthorized(ctx context.Context, userID string) error {
+	account, err := u.githubAccountRepo.FindByUserID(ctx, userID)
+	if errors.Is(err, repository.ErrAccountNotFound) {
+		return model.ErrGithubReauthRequired
+	}
+	if err != nil {
+		u.logger.ErrorContext(ctx, "failed to find github account for re-authentication", "error", err, "user_id", userID)
+		return model.ErrGithubReauthRequired
+	}
+
+	// 拒否されたのは直前のGetTokenで選んだトークン（PAT優先）とみなす
+	reason := account.TokenKind()
+	if err := u.githubAccountRepo.MarkReauthRequired(ctx, userID, reason, time.Now()); err != nil {
+		// 記録できなくても、呼び出し元には再認証が必要なことを返す
+		u.logger.ErrorContext(ctx, "failed to record github re-authentication", "error", err, "user_id", userID)
+	}
+	return &model.GithubReauthError{Reason: reason}
+}
+
+// ListGithubProjects は連携先に選べるGitHub Projectsを取得する
+// ownerを指定した場合はそのユーザー・OrganizationのProjectsを、
+// 省略した場合はユーザー自身と所属するOrganizationのProjectsを取得する
+func (u *GithubUsecase) ListGithubProjects(ctx context.Context, userID, owner string) ([]github.Project, error) {
 	token, err := u.GetToken(ctx, userID)
 	if err != nil {
 		return nil, err
 	}
+	ctx = github.WithUser(ctx, userID)
+
+	if owner != "" {
+		ownerType, err := u.githubService.GetOwnerType(ctx, token, owner)
+		if errors.Is(err, github.ErrNotFound) {
+			return nil, fmt.Errorf("github owner %s not found: %w", owner, model.ErrNotFound)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to get github owner type: %w", err)
+		}
+		projects, err := u.githubService.GetOwnerProjects(ctx, token, github.ProjectOwner{Login: owner, Type: ownerType})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get github projects: %w", err)
+		}
+		return projects, nil
+	}
 
 	projects, err := u.githubService.GetUserProjects(ctx, token)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get github projects: %w", err)
 	}
 
+	// OrganizationのProjectsは取得できたものだけを返す（OAuth Appのアクセスを制限しているOrganization等は除く）
+	organizations, err := u.githubService.GetViewerOrganizations(ctx, token)
+	if err != nil {
+		if errors.Is(err, model.ErrGithubReauthRequired) {
+			return nil, err
+		}
+		u.logger.WarnContext(ctx, "failed to list github organizations", "error", err)
+		return projects, nil
+	}
+	for _, login := range organizations {
+		orgProjects, err := u.githubService.GetOwnerProjects(ctx, token, github.ProjectOwner{Login: login, Type: github.OwnerTypeOrg})
+		if err != nil {
+			if errors.Is(err, model.ErrGithubReauthRequired) {
+				return nil, err
+			}
+			u.logger.WarnContext(ctx, "failed to get github organization projects", "error", err, "organization", login)
+			continue
+		}
+		projects = append(projects, orgProjects...)
+	}
+
 	return projects, nil
 }
 
 // LinkProjectToGithub はプロジェクトをGitHub Projectに連携する
-func (u *GithubUsecase) LinkProjectToGithub(ctx context.Context, userID, projectID, githubOwner, githubRepo string, githubProjectNumber int) error {
+// githubOwnerが空の場合は設定のデフォルトownerを使用する
+// repoProjectがtrueの場合はリポジトリに紐づくProjectとして連携し、GitHub上に存在することを確認する
+// それ以外はownerがユーザーかOrganizationかをGitHubで確認し、所有者の種類として保存する
+func (u *GithubUsecase) LinkProjectToGithub(ctx context.Context, userID, projectID, githubOwner, githubRepo string, githubProjectNumber int, repoProject bool) error {
 	project, err := u.projectRepo.FindByID(ctx, projectID)
 	if err != nil {
 		return fmt.Errorf("failed to find project: %w", err)
 	}
 
 	if project.UserID != userID {
-		return fmt.Errorf("unauthorized")
+		return model.ErrForbidden
 	}
 
+	if githubOwner == "" {
+		settings, err := findSettings(ctx, u.settingsRepo, userID)
+		if err != nil {
+			return err
+		}
+		if settings.DefaultGithubOwner == nil {
+			return fmt.Errorf("github owner is required when no default owner is set: %w", model.ErrInvalidInput)
+		}
+		githubOwner = *settings.DefaultGithubOwner
+	}
+
+	if repoProject && githubRepo == "" {
+		return fmt.Errorf("github repo is required for a repository project: %w", model.ErrInvalidInput)
+	}
+
+	token, err := u.GetToken(ctx, userID)
+	if err != nil {
+		return err
+	}
+	ctx = github.WithUser(ctx, userID)
+
+	ownerType := model.GithubOwnerRepo
+	if repoProject {
+		owner := github.ProjectOwner{Login: githubOwner, Repo: githubRepo, Type: github.OwnerTypeRepo}
+		if _, err := u.githubService.GetProjectID(ctx, token, owner, githubProjectNumber); err != nil {
+			if errors.Is(err, model.ErrGithubReauthRequired) {
+				return err
+			}
+			return fmt.Errorf("repository project not found: %v: %w", err, model.ErrInvalidInput)
+		}
+	} else {
+		t, err := u.githubService.GetOwnerType(ctx, token, githubOwner)
+		if errors.Is(err, github.ErrNotFound) {
+			return fmt.Errorf("github owner %s not found: %w", githubOwner, model.ErrInvalidInput)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to get github owner type: %w", err)
+		}
+		ownerType = model.GithubOwnerType(t)
+	}
+
+	before := *project
 	project.GithubOwner = &githubOwner
 	project.GithubRepo = &githubRepo
 	project.GithubProjectNumber = &githubProjectNumber
+	project.GithubRepoProject = repoProject
+	project.GithubOwnerType = &ownerType
 
-	if err := u.projectRepo.Update(ctx, project); err != nil {
+	if err := saveProject(ctx, u.tx, u.projectRepo, u.events, u.taskUsecase.activity, project, &before, model.EventProjectLinked); err != nil {
 		return fmt.Errorf("failed to update project: %w", err)
 	}
 
@@ -176,14 +419,17 @@ func (u *GithubUsecase) UnlinkProjectFromGithub(ctx context.Context, userID, pro
 	}
 
 	if project.UserID != userID {
-		return fmt.Errorf("unauthorized")
+		return model.ErrForbidden
 	}
 
+	before := *project
 	project.GithubOwner = nil
 	project.GithubRepo = nil
 	project.GithubProjectNumber = nil
+	project.GithubRepoProject = false
+	project.GithubOwnerType = nil
 
-	if err := u.projectRepo.Update(ctx, project); err != nil {
+	if err := saveProject(ctx, u.tx, u.projectRepo, u.events, u.taskUsecase.activity, project, &before, model.EventProjectUnlinked); err != nil {
 		return fmt.Errorf("failed to update project: %w", err)
 	}
 
@@ -204,36 +450,316 @@ func (u *GithubUsecase) SyncTaskToGithub(ctx context.Context, userID, taskID str
 	}
 
 	if project.UserID != userID {
-		return fmt.Errorf("unauthorized")
+		return model.ErrForbidden
 	}
 
 	if !project.IsGithubLinked() {
-		return fmt.Errorf("project is not linked to github")
+		return fmt.Errorf("project is not linked to github: %w", model.ErrConflict)
+	}
+	if err := u.consumeSyncQuota(ctx, userID); err != nil {
+		return err
+	}
+
+	ctx, unlock, err := u.lockProjectSync(ctx, project.ID)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	// ロックを待つ間に他のインスタンスがItemを追加した場合に重複して追加しないよう、タスクを読み直す
+	task, err = u.taskRepo.FindByID(ctx, taskID)
+	if err != nil {
+		return fmt.Errorf("failed to find task: %w", err)
 	}
 
 	token, err := u.GetToken(ctx, userID)
 	if err != nil {
 		return err
 	}
+	ctx = github.WithUser(ctx, userID)
 
 	// GitHub Project IDを取得
-	projectGithubID, err := u.githubService.GetProjectID(ctx, token, *project.GithubOwner, *project.GithubProjectNumber)
+	projectGithubID, err := u.githubService.GetProjectID(ctx, token, githubProjectOwner(project), *project.GithubProjectNumber)
 	if err != nil {
 		return fmt.Errorf("failed to get github project id: %w", err)
 	}
 
-	// Draft Issueとして追加
-	item, err := u.githubService.AddDraftIssueToProject(ctx, token, projectGithubID, task.Title, task.Description)
+	return u.pushTask(ctx, token, project, projectGithubID, task)
+}
+
+// pushTask はタスクの内容をGitHub ProjectのItemに反映し、タスクとItemを一致させた時点を記録する
+// Itemがない場合はプロジェクトのgithub_item_typeに従ってDraft IssueかIssueとして追加する
+// Draft Issueのタイトル・本文はタスクに合わせ、Issueのタイトル・本文はgithub_item_typeがissueの場合だけ合わせる（それ以外はGitHub側で管理する）
+// 本文はタスクの説明の後にサブタスクのチェックリストを付ける
+func (u *GithubUsecase) pushTask(ctx context.Context, token string, project *model.Project, projectGithubID string, task *model.Task) error {
+	subtasks, err := u.taskRepo.FindByParentID(ctx, task.ID)
+	if err != nil {
+		return fmt.Errorf("failed to find subtasks: %w", err)
+	}
+	body := renderItemBody(task.Description, subtasks)
+
+	linked := task.GithubItemID != nil && !task.IsGithubOrphaned()
+	itemID, err := u.ensureProjectItem(ctx, token, project, projectGithubID, task, body)
+	if err != nil {
+		return err
+	}
+	if linked {
+		if err := u.syncItemContent(ctx, token, project, itemID, task, body); err != nil {
+			return err
+		}
+	}
+
+	// 見積もりの同期先フィールドが設定されている場合は見積もりを同期する
+	// Itemの追加自体は完了しているため、失敗してもエラーにはしない
+	if project.GithubEstimateField != nil && task.Estimate != nil {
+		if err := u.syncEstimate(ctx, token, projectGithubID, itemID, *project.GithubEstimateField, *task.Estimate); err != nil {
+			u.logger.WarnContext(ctx, "failed to sync estimate to github", "error", err, "task_id", task.ID, "field", *project.GithubEstimateField)
+		}
+	}
+
+	// ステータス・優先度をフィールドの対応付けに従って設定する（見積もりと同様に失敗してもエラーにはしない）
+	// ただしGitHubの障害で失敗した場合は、ステータスの変更を反映し損ねないようエラーを返して再試行させる（Itemは追加済みのため重複しない）
+	mapping, err := u.fieldMapping(ctx, project.ID)
 	if err != nil {
-		return fmt.Errorf("failed to add task to github: %w", err)
+		u.logger.WarnContext(ctx, "failed to load github field mapping", "error", err, "project_id", project.ID)
+	} else if err := u.syncFieldValues(ctx, token, projectGithubID, itemID, mapping, task); err != nil {
+		if errors.Is(err, github.ErrUnavailable) {
+			return fmt.Errorf("failed to sync fields to github: %w", err)
+		}
+		u.logger.WarnContext(ctx, "failed to sync fields to github", "error", err, "task_id", task.ID)
 	}
 
-	// タスクにGitHub Item IDを保存
+	// GitHubの更新日時は次の取り込みで記録する（反映した内容と同じため取り込みでは何も変わらない）
+	u.recordTaskSync(ctx, project, task, nil)
+
+	u.logger.InfoContext(ctx, "task synced to github", "task_id", task.ID, "github_item_id", itemID)
+	return nil
+}
+
+// syncItemContent はItemがDraft Issueの場合（とプロジェクトのgithub_item_typeがissueでItemがIssueの場合）にタイトル・本文をタスクに合わせる
+// bodyはタスクの説明にサブタスクのチェックリストを付けた本文
+func (u *GithubUsecase) syncItemContent(ctx context.Context, token string, project *model.Project, itemID string, task *model.Task, body string) error {
+	content, err := u.githubService.GetItemContent(ctx, token, itemID)
+	if err != nil {
+		return fmt.Errorf("failed to get github item content: %w", err)
+	}
+	if content.Title == task.Title && content.Body == body {
+		return nil
+	}
+
+	switch {
+	case content.Type == github.ContentTypeDraftIssue:
+		if err := u.githubService.UpdateDraftIssue(ctx, token, content.ID, task.Title, body); err != nil {
+			return fmt.Errorf("failed to update github draft issue: %w", err)
+		}
+	case content.Type == github.ContentTypeIssue && project.GithubItemType == model.GithubItemIssue:
+		if err := u.githubService.UpdateIssue(ctx, token, content.ID, task.Title, body); err != nil {
+			return fmt.Errorf("failed to update github issue: %w", err)
+		}
+	}
+	return nil
+}
+
+// recordTaskSync はタスクとItemを一致させた時点を記録する（remoteUpdatedAtがnilの場合は記録済みのItemの更新日時を残す）
+// 同期自体は完了しているため、失敗してもログに記録するだけにする（次の同期で競合として更新日時の新しい方に合わせる）
+func (u *GithubUsecase) recordTaskSync(ctx context.Context, project *model.Project, task *model.Task, remoteUpdatedAt *time.Time) {
+	err := u.taskSyncRepo.Upsert(ctx, &model.GithubTaskSync{
+		TaskID:          task.ID,
+		UserID:          project.UserID,
+		ProjectID:       project.ID,
+		LocalUpdatedAt:  task.UpdatedAt,
+		RemoteUpdatedAt: remoteUpdatedAt,
+		UpdatedAt:       time.Now(),
+	})
+	if err != nil {
+		u.logger.WarnContext(ctx, "failed to record github task sync", "error", err, "task_id", task.ID)
+	}
+}
+
+// ensureProjectItem はタスクを同期するGitHub ProjectのItemのIDを返す（Itemを追加する場合の本文はbody）
+// 同期済みのItemが存在する場合はそれを使い、未同期または連携切れの場合はプロジェクトのgithub_item_typeに従って追加する
+// 同期済みのItemがGitHub上で削除されていた場合は、プロジェクトの設定に従ってタスクを連携切れにするか削除してErrConflictを返す
+func (u *GithubUsecase) ensureProjectItem(ctx context.Context, token string, project *model.Project, projectGithubID string, task *model.Task, body string) (string, error) {
+	if task.GithubItemID != nil && !task.IsGithubOrphaned() {
+		exists, err := u.githubService.ItemExists(ctx, token, *task.GithubItemID)
+		if err != nil {
+			return "", fmt.Errorf("failed to check github item: %w", err)
+		}
+		if exists {
+			return *task.GithubItemID, nil
+		}
+		if err := u.handleGithubDeletion(ctx, task); err != nil {
+			return "", err
+		}
+		return "", errGithubLinkDeleted(task)
+	}
+
+	var item *github.ProjectItem
+	var err error
+	if project.GithubItemType == model.GithubItemIssue {
+		item, err = u.addIssueItem(ctx, token, project, projectGithubID, task, body)
+	} else {
+		item, err = u.githubService.AddDraftIssueToProject(ctx, token, projectGithubID, task.Title, body)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to add task to github: %w", err)
+	}
+
+	// タスクにGitHub Item IDを保存し、連携切れの状態を解除する
 	task.GithubItemID = &item.ID
+	task.GithubSyncState = nil
 	if err := u.taskRepo.Update(ctx, task); err != nil {
-		return fmt.Errorf("failed to update task: %w", err)
+		return "", fmt.Errorf("failed to update task: %w", err)
+	}
+
+	return item.ID, nil
+}
+
+// addIssueItem はタスクのIssueをGitHub ProjectのItemとして追加する
+// タスクにIssueが紐づいている場合（Issueから取り込んだタスク等）はそのIssueを追加し、
+// 紐づいていないかGitHub上で削除されていた場合は連携先のリポジトリにIssueを作成してタスクにIssueの番号・URLを保存する
+func (u *GithubUsecase) addIssueItem(ctx context.Context, token string, project *model.Project, projectGithubID string, task *model.Task, body string) (*github.ProjectItem, error) {
+	issue, err := u.findTaskIssue(ctx, token, task)
+	if err != nil {
+		return nil, err
+	}
+
+	if issue == nil {
+		if project.GithubRepo == nil || *project.GithubRepo == "" {
+			return nil, fmt.Errorf("project has no github repository to create issues in: %w", model.ErrConflict)
+		}
+		// プロジェクトの所有者の設定のデフォルトのラベルを付ける
+		settings, err := findSettings(ctx, u.settingsRepo, project.UserID)
+		if err != nil {
+			return nil, err
+		}
+		issue, err = u.githubService.CreateIssue(ctx, token, *project.GithubOwner, *project.GithubRepo, task.Title, body, settings.DefaultLabels)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create github issue: %w", err)
+		}
+
+		// Projectへの追加に失敗した場合の再試行でIssueを重複して作成しないよう、先にタスクに保存する
+		task.GithubIssueNumber = &issue.Number
+		task.GithubIssueURL = &issue.URL
+		if err := u.taskRepo.Update(ctx, task); err != nil {
+			return nil, fmt.Errorf("failed to update task: %w", err)
+		}
+		u.logger.InfoContext(ctx, "github issue created for task", "task_id", task.ID, "github_issue_url", issue.URL)
+	}
+
+	return u.githubService.AddIssueToProject(ctx, token, projectGithubID, issue.NodeID)
+}
+
+// findTaskIssue はタスクに紐づくIssueを取得する（紐づいていないかGitHub上で削除されていた場合はnil）
+func (u *GithubUsecase) findTaskIssue(ctx context.Context, token string, task *model.Task) (*github.Issue, error) {
+	if !task.HasGithubIssue() {
+		return nil, nil
+	}
+
+	owner, repo, number, err := github.ParseIssueURL(*task.GithubIssueURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse github issue url: %v: %w", err, model.ErrConflict)
+	}
+	issue, err := u.githubService.GetIssue(ctx, token, owner, repo, number)
+	if errors.Is(err, github.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get github issue: %w", err)
+	}
+	return issue, nil
+}
+
+// githubProjectOwner はプロジェクトの連携先のGitHub Projectを検索する所有者を返す
+// 所有者の種類が未確認のプロジェクトはGithubRepoProjectから判断する（リポジトリ以外はユーザーのProject）
+func githubProjectOwner(project *model.Project) github.ProjectOwner {
+	owner := github.ProjectOwner{Login: *project.GithubOwner, Type: github.OwnerTypeUser}
+	if project.GithubOwnerType != nil {
+		owner.Type = github.OwnerType(*project.GithubOwnerType)
+	} else if project.GithubRepoProject {
+		owner.Type = github.OwnerTypeRepo
+	}
+	if owner.Type == github.OwnerTypeRepo && project.GithubRepo != nil {
+		owner.Repo = *project.GithubRepo
+	}
+	return owner
+}
+
+// syncEstimate はGitHub Projectsの数値フィールドに見積もりを設定する
+func (u *GithubUsecase) syncEstimate(ctx context.Context, token, projectGithubID, itemID, fieldName string, estimate float64) error {
+	fieldID, err := u.githubService.GetFieldID(ctx, token, projectGithubID, fieldName)
+	if err != nil {
+		return fmt.Errorf("failed to get github field id: %w", err)
+	}
+
+	if err := u.githubService.UpdateItemNumberField(ctx, token, projectGithubID, itemID, fieldID, estimate); err != nil {
+		return fmt.Errorf("failed to update github estimate field: %w", err)
 	}
 
-	u.logger.InfoContext(ctx, "task synced to github", "task_id", taskID, "github_item_id", item.ID)
 	return nil
 }
+
+// SyncMilestoneToGithub はマイルストーンを連携先リポジトリのGitHubマイルストーンに同期する
+// 同期済みの場合は既存のGitHubマイルストーンを更新する
+func (u *GithubUsecase) SyncMilestoneToGithub(ctx context.Context, userID, milestoneID string) (*model.Milestone, error) {
+	milestone, err := u.milestoneRepo.FindByID(ctx, milestoneID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find milestone: %w", err)
+	}
+
+	project, err := u.projectRepo.FindByID(ctx, milestone.ProjectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find project: %w", err)
+	}
+
+	if project.UserID != userID {
+		return nil, model.ErrForbidden
+	}
+
+	if project.GithubOwner == nil || project.GithubRepo == nil {
+		return nil, fmt.Errorf("project is not linked to a github repository: %w", model.ErrConflict)
+	}
+	if err := u.consumeSyncQuota(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	ctx, unlock, err := u.lockProjectSync(ctx, project.ID)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+	// ロックを待つ間に他のインスタンスがGitHubマイルストーンを作成した場合に備えて読み直す
+	milestone, err = u.milestoneRepo.FindByID(ctx, milestoneID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find milestone: %w", err)
+	}
+
+	token, err := u.GetToken(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	ctx = github.WithUser(ctx, userID)
+
+	input := github.MilestoneInput{
+		Title:       milestone.Title,
+		Description: milestone.Description,
+		DueOn:       milestone.DueDate,
+	}
+
+	var synced *github.Milestone
+	if milestone.GithubMilestoneNumber != nil {
+		synced, err = u.githubService.UpdateMilestone(ctx, token, *project.GithubOwner, *project.GithubRepo, *milestone.GithubMilestoneNumber, input)
+	} else {
+		synced, err = u.githubService.CreateMilestone(ctx, token, *project.GithubOwner, *project.GithubRepo, input)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to sync milestone to github: %w", err)
+	}
+
+	milestone.GithubMilestoneNumber = &synced.Number
+	if err := u.milestoneRepo.Update(ctx, milestone); err != nil {
+		return nil, fmt.Errorf("failed to update milestone: %w", err)
+	}
+
+	u.logger.InfoContext(ctx, "milestone synced to github", "milestone_id", milestoneID, "github_milestone_number", synced.Number)
+	return milestone, nil
+}
@@ -0,0 +1,116 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"unicode"
+
+	"github.com/sikigasa/github-task-controller/backend/internal/domain/model"
+	"github.com/sikigasa/github-task-controller/backend/internal/infrastructure/github"
+)
+
+// branchSlugMaxLength はブランチ名に含めるタイトル部分の最大長
+const branchSlugMaxLength = 40
+
+// CreateTaskBranch は連携先リポジトリのデフォルトブランチからタスクの作業ブランチを作成し、ブランチ名をタスクに保存する
+func (u *GithubUsecase) CreateTaskBranch(ctx context.Context, userID, taskID string) (*model.Task, error) {
+	task, project, err := u.findOwnedTask(ctx, userID, taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	if project.GithubOwner == nil || project.GithubRepo == nil {
+		return nil, fmt.Errorf("project is not linked to a github repository: %w", model.ErrConflict)
+	}
+	if err := u.consumeSyncQuota(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	ctx, unlock, err := u.lockProjectSync(ctx, project.ID)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+	// ロックを待つ間に他のインスタンスがブランチを作成した場合に備えて読み直す
+	task, err = u.taskRepo.FindByID(ctx, taskID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find task: %w", err)
+	}
+	if task.GithubBranch != nil {
+		return nil, fmt.Errorf("branch already created for task: %s: %w", *task.GithubBranch, model.ErrConflict)
+	}
+
+	token, err := u.GetToken(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	ctx = github.WithUser(ctx, userID)
+
+	name := branchName(u.branchTemplate, task)
+	branch, err := u.githubService.CreateBranchFromDefault(ctx, token, *project.GithubOwner, *project.GithubRepo, name)
+	if err != nil {
+		var apiErr *github.APIError
+		if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusUnprocessableEntity {
+			return nil, fmt.Errorf("branch already exists: %s: %w", name, model.ErrConflict)
+		}
+		return nil, fmt.Errorf("failed to create github branch: %w", err)
+	}
+
+	task.GithubBranch = &branch.Name
+	if err := u.taskRepo.Update(ctx, task); err != nil {
+		return nil, fmt.Errorf("failed to update task: %w", err)
+	}
+
+	u.logger.InfoContext(ctx, "task branch created", "task_id", taskID, "branch", branch.Name, "base", branch.Base)
+	return task, nil
+}
+
+// branchName はテンプレートのプレースホルダーを置き換えて作業ブランチ名を作成する
+// {number}はGitHub Issueの番号（未連携の場合はタスクIDの先頭8文字）、{id}はタスクIDの先頭8文字、{slug}はタイトルに置き換える
+func branchName(template string, task *model.Task) string {
+	id := task.ID
+	if len(id) > 8 {
+		id = id[:8]
+	}
+	number := id
+	if task.GithubIssueNumber != nil {
+		number = fmt.Sprintf("%d", *task.GithubIssueNumber)
+	}
+
+	name := strings.NewReplacer(
+		"{number}", number,
+		"{id}", id,
+		"{slug}", branchSlug(task.Title),
+	).Replace(template)
+	return strings.Trim(name, "-/")
+}
+
+// branchSlug はタイトルを英数字とハイフンのみの小文字に変換する
+// 英数字を含まないタイトル（日本語のみなど）の場合は"task"を返す
+func branchSlug(title string) string {
+	var b strings.Builder
+	hyphen := false
+	for _, r := range strings.ToLower(title) {
+		if r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)) {
+			if hyphen && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			b.WriteRune(r)
+			hyphen = false
+			continue
+		}
+		hyphen = true
+	}
+
+	slug := b.String()
+	if len(slug) > branchSlugMaxLength {
+		slug = strings.TrimRight(slug[:branchSlugMaxLength], "-")
+	}
+	if slug == "" {
+		return "task"
+	}
+	return slug
+}
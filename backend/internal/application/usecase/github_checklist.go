@@ -0,0 +1,69 @@
+package usecase
+
+import (
+	"strings"
+
+	"github.com/sikigasa/github-task-controller/backend/internal/domain/model"
+)
+
+const (
+	// subtaskChecklistStart・subtaskChecklistEnd はItem・Issueの本文のうちサブタスクのチェックリストの範囲を示すコメント
+	// 取り込み時にこの範囲を除いてタスクの説明にする
+	subtaskChecklistStart = "<!-- subtasks -->"
+	subtaskChecklistEnd   = "<!-- /subtasks -->"
+)
+
+// renderItemBody はタスクの説明の後にサブタスクのチェックリストを付けた、GitHubのItem・Issueの本文を返す
+// サブタスクがない場合は説明をそのまま返す。完了したサブタスクはチェック済みにする
+func renderItemBody(description string, subtasks []*model.Task) string {
+	if len(subtasks) == 0 {
+		return description
+	}
+
+	var b strings.Builder
+	if description != "" {
+		b.WriteString(description)
+		b.WriteString("\n\n")
+	}
+	b.WriteString(subtaskChecklistStart)
+	b.WriteString("\n")
+	for _, subtask := range subtasks {
+		mark := " "
+		if subtask.Status == model.TaskStatusDone {
+			mark = "x"
+		}
+		// タイトルの改行でリストが崩れないよう1行にする
+		title := strings.Join(strings.Fields(subtask.Title), " ")
+		b.WriteString("- [" + mark + "] " + title + "\n")
+	}
+	b.WriteString(subtaskChecklistEnd)
+	return b.String()
+}
+
+// stripSubtaskChecklist はItem・Issueの本文からrenderItemBodyで付けたサブタスクのチェックリストを除き、タスクの説明を返す
+// GitHub上でチェックリストを編集してもサブタスクには反映しない（次の同期でタスクの内容に戻す）
+func stripSubtaskChecklist(body string) string {
+	start := strings.Index(body, subtaskChecklistStart)
+	if start < 0 {
+		return body
+	}
+	end := strings.Index(body[start:], subtaskChecklistEnd)
+	if end < 0 {
+		return body
+	}
+	end += start + len(subtaskChecklistEnd)
+
+	// GitHubのWeb上で編集した本文は改行がCRLFになる
+	description := body[:start]
+	for _, separator := range []string{"\r\n\r\n", "\n\n"} {
+		if trimmed, ok := strings.CutSuffix(description, separator); ok {
+			description = trimmed
+			break
+		}
+	}
+	rest := strings.TrimLeft(body[end:], "\r\n")
+	if description != "" && rest != "" {
+		description += "\n\n"
+	}
+	return description + rest
+}
@@ -0,0 +1,69 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/sikigasa/github-task-controller/backend/internal/domain/model"
+	"github.com/sikigasa/github-task-controller/backend/internal/infrastructure/github"
+)
+
+// GithubItemCleanupResult は同期済みのItemの削除結果を表す
+type GithubItemCleanupResult struct {
+	// Deleted はGitHub Projectから削除したItemの数
+	Deleted int `json:"deleted"`
+	// Failed は削除に失敗したItemの数
+	Failed int `json:"failed"`
+}
+
+// DeleteProjectItems はプロジェクトのタスクに同期済みのItemをGitHub Projectから削除する
+// プロジェクトを削除する前の後始末に使う。Draft IssueのItemは削除され、IssueのItemはProjectから外れるのみでIssue自体は残る
+// Itemごとの失敗はログに記録して続行し、件数を返す
+func (u *GithubUsecase) DeleteProjectItems(ctx context.Context, userID, projectID string) (*GithubItemCleanupResult, error) {
+	project, err := u.findOwnedProject(ctx, userID, projectID)
+	if err != nil {
+		return nil, err
+	}
+	result := &GithubItemCleanupResult{}
+	if !project.IsGithubLinked() {
+		return result, nil
+	}
+
+	ctx, unlock, err := u.lockProjectSync(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	tasks, err := u.taskRepo.FindByProjectIDs(ctx, []string{projectID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to find tasks: %w", err)
+	}
+	preview := model.NewProjectDeletePreview(projectID, tasks)
+	if len(preview.GithubItems) == 0 {
+		return result, nil
+	}
+
+	token, err := u.GetToken(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	ctx = github.WithUser(ctx, userID)
+
+	projectGithubID, err := u.githubService.GetProjectID(ctx, token, githubProjectOwner(project), *project.GithubProjectNumber)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get github project id: %w", err)
+	}
+
+	for _, item := range preview.GithubItems {
+		if err := u.githubService.DeleteProjectItem(ctx, token, projectGithubID, item.GithubItemID); err != nil {
+			u.logger.WarnContext(ctx, "failed to delete github project item", "error", err, "task_id", item.TaskID, "github_item_id", item.GithubItemID)
+			result.Failed++
+			continue
+		}
+		result.Deleted++
+	}
+
+	u.logger.InfoContext(ctx, "github project items deleted", "project_id", projectID, "deleted", result.Deleted, "failed", result.Failed)
+	return result, nil
+}
@@ -0,0 +1,179 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sikigasa/github-task-controller/backend/internal/domain/model"
+	"github.com/sikigasa/github-task-controller/backend/internal/infrastructure/github"
+)
+
+var (
+	// commitIssueRefPattern はコミットメッセージ中のIssue参照（#N）に一致する
+	commitIssueRefPattern = regexp.MustCompile(`(?:^|[^\w/])#(\d+)\b`)
+	// commitTaskRefPattern はコミットメッセージ中のタスクID（UUID）に一致する
+	commitTaskRefPattern = regexp.MustCompile(`(?i)\b[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}\b`)
+)
+
+// GithubCommitSyncResult は参照コミットの同期結果を表す
+type GithubCommitSyncResult struct {
+	// Scanned は確認したコミットの数
+	Scanned int `json:"scanned"`
+	// Recorded は新たにタスクに記録したコミット参照の数
+	Recorded int `json:"recorded"`
+	// Started は最初の参照コミットで進行中にしたタスクの数
+	Started int `json:"started"`
+}
+
+// SyncProjectCommits は連携先リポジトリの最近のコミットからタスクへの参照を検出してタスクに記録する
+// コミットメッセージの#N（同じリポジトリのIssue番号）とタスクIDを参照として扱う
+// プロジェクトのgithub_commit_starts_taskが有効な場合、未着手のタスクは最初の参照コミットで進行中にする
+func (u *GithubUsecase) SyncProjectCommits(ctx context.Context, userID, projectID string) (*GithubCommitSyncResult, error) {
+	if err := validateResourceID(projectID); err != nil {
+		return nil, err
+	}
+
+	project, err := u.projectRepo.FindByID(ctx, projectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find project: %w", err)
+	}
+	if project.UserID != userID {
+		return nil, model.ErrForbidden
+	}
+	if project.GithubOwner == nil || project.GithubRepo == nil {
+		return nil, fmt.Errorf("project is not linked to a github repository: %w", model.ErrConflict)
+	}
+	if err := u.consumeSyncQuota(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	ctx, unlock, err := u.lockProjectSync(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	token, err := u.GetToken(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	ctx = github.WithUser(ctx, userID)
+
+	since, err := u.taskCommitRepo.LatestCommittedAt(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	commits, err := u.githubService.ListCommits(ctx, token, *project.GithubOwner, *project.GithubRepo, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list github commits: %w", err)
+	}
+
+	tasks, err := u.taskRepo.FindByProjectIDs(ctx, []string{projectID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to find tasks: %w", err)
+	}
+	byID := make(map[string]*model.Task, len(tasks))
+	byIssue := make(map[int]*model.Task)
+	for _, task := range tasks {
+		byID[task.ID] = task
+		if task.HasGithubIssue() {
+			// 別リポジトリのIssueの番号は#Nの参照先にならない
+			owner, repo, number, err := github.ParseIssueURL(*task.GithubIssueURL)
+			if err == nil && strings.EqualFold(owner, *project.GithubOwner) && strings.EqualFold(repo, *project.GithubRepo) {
+				byIssue[number] = task
+			}
+		}
+	}
+
+	result := &GithubCommitSyncResult{Scanned: len(commits)}
+	// 古いコミットから記録し、最初の参照コミットで進行中にする
+	for i := len(commits) - 1; i >= 0; i-- {
+		commit := &commits[i]
+		for _, task := range referencedTasks(commit.Message, byID, byIssue) {
+			recorded, err := u.taskCommitRepo.Create(ctx, &model.TaskCommit{
+				TaskID:      task.ID,
+				ProjectID:   projectID,
+				SHA:         commit.SHA,
+				Message:     commit.Summary(),
+				URL:         commit.URL,
+				Author:      commit.Author,
+				CommittedAt: commit.CommittedAt,
+				CreatedAt:   time.Now(),
+			})
+			if err != nil {
+				return result, err
+			}
+			if !recorded {
+				continue
+			}
+			result.Recorded++
+
+			if project.GithubCommitStartsTask && task.Status == model.TaskStatusTodo {
+				started, err := u.startTask(ctx, task)
+				if err != nil {
+					return result, err
+				}
+				if started {
+					result.Started++
+				}
+			}
+		}
+	}
+
+	u.logger.InfoContext(ctx, "github commits synced", "project_id", projectID,
+		"scanned", result.Scanned, "recorded", result.Recorded, "started", result.Started)
+	return result, nil
+}
+
+// ListTaskCommits はタスクを参照しているコミットを取得する
+func (u *GithubUsecase) ListTaskCommits(ctx context.Context, userID, taskID string) ([]*model.TaskCommit, error) {
+	if _, _, err := u.findOwnedTask(ctx, userID, taskID); err != nil {
+		return nil, err
+	}
+
+	return u.taskCommitRepo.FindByTaskID(ctx, taskID)
+}
+
+// startTask は未着手のタスクを進行中にする（ステータス遷移のルールで拒否された場合はfalseを返す）
+func (u *GithubUsecase) startTask(ctx context.Context, task *model.Task) (bool, error) {
+	inProgress := model.TaskStatusInProgress
+	updated, err := u.taskUsecase.patchTask(ctx, task.ID, &model.PatchTaskRequest{Status: &inProgress})
+	if err != nil {
+		if errors.Is(err, model.ErrInvalidInput) || errors.Is(err, model.ErrConflict) {
+			u.logger.WarnContext(ctx, "skipping task start for referencing commit", "error", err, "task_id", task.ID)
+			return false, nil
+		}
+		return false, err
+	}
+
+	task.Status = updated.Status
+	return true, nil
+}
+
+// referencedTasks はコミットメッセージが参照しているタスクを重複なく返す
+func referencedTasks(message string, byID map[string]*model.Task, byIssue map[int]*model.Task) []*model.Task {
+	var tasks []*model.Task
+	seen := make(map[string]bool)
+	add := func(task *model.Task) {
+		if task != nil && !seen[task.ID] {
+			seen[task.ID] = true
+			tasks = append(tasks, task)
+		}
+	}
+
+	for _, match := range commitIssueRefPattern.FindAllStringSubmatch(message, -1) {
+		if number, err := strconv.Atoi(match[1]); err == nil {
+			add(byIssue[number])
+		}
+	}
+	for _, id := range commitTaskRefPattern.FindAllString(message, -1) {
+		add(byID[strings.ToLower(id)])
+	}
+
+	return tasks
+}
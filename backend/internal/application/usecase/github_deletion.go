@@ -0,0 +1,67 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/sikigasa/github-task-controller/backend/internal/domain/model"
+)
+
+// handleDeletedLink はGitHub上で削除されたItem・Issueに連携しているタスクを処理する
+func (u *GithubUsecase) handleDeletedLink(ctx context.Context, itemID, issueURL string) error {
+	tasks, err := u.taskRepo.FindByGithubLink(ctx, itemID, issueURL)
+	if err != nil {
+		return err
+	}
+
+	for _, task := range tasks {
+		// Itemだけが削除されIssueが残っている場合は、Itemとの同期を解除するだけにする（次回の同期で追加し直す）
+		if issueURL == "" && task.HasGithubIssue() {
+			task.GithubItemID = nil
+			if err := u.taskRepo.Update(ctx, task); err != nil {
+				return fmt.Errorf("failed to unlink github item: %w", err)
+			}
+			u.logger.InfoContext(ctx, "github item of task was deleted", "task_id", task.ID, "github_item_id", itemID)
+			continue
+		}
+		if err := u.handleGithubDeletion(ctx, task); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// handleGithubDeletion は連携先がGitHub上で削除されたタスクを、プロジェクトのgithub_deletion_policyに従って
+// 連携切れ（orphaned）にするか削除する
+func (u *GithubUsecase) handleGithubDeletion(ctx context.Context, task *model.Task) error {
+	project, err := u.projectRepo.FindByID(ctx, task.ProjectID)
+	if err != nil {
+		return fmt.Errorf("failed to find project: %w", err)
+	}
+
+	if project.GithubDeletionPolicy == model.GithubDeletionDelete {
+		if err := u.taskUsecase.deleteTask(ctx, task.ID); err != nil {
+			return err
+		}
+		u.logger.InfoContext(ctx, "task deleted because its github link was deleted", "task_id", task.ID, "project_id", project.ID)
+		return nil
+	}
+
+	if task.IsGithubOrphaned() {
+		return nil
+	}
+	orphaned := model.GithubSyncOrphaned
+	task.GithubSyncState = &orphaned
+	if err := u.taskRepo.Update(ctx, task); err != nil {
+		return fmt.Errorf("failed to mark task orphaned: %w", err)
+	}
+
+	u.logger.WarnContext(ctx, "github link of task no longer exists", "task_id", task.ID, "project_id", project.ID)
+	return nil
+}
+
+// errGithubLinkDeleted は連携先が削除されたタスクへの同期の失敗を表すエラーを返す
+func errGithubLinkDeleted(task *model.Task) error {
+	return fmt.Errorf("github link of task %s no longer exists: %w", task.ID, model.ErrConflict)
+}
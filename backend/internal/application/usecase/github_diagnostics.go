@@ -0,0 +1,82 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/sikigasa/github-task-controller/backend/internal/domain/model"
+	"github.com/sikigasa/github-task-controller/backend/internal/infrastructure/github"
+)
+
+// GithubDiagnostics はGitHub連携の診断結果を表す（同期できない原因をユーザー自身が確認するため）
+type GithubDiagnostics struct {
+	// TokenType は使用しているトークンの種類（PATを優先する）
+	TokenType model.GithubReauthReason `json:"token_type"`
+	// Reachable はGitHub APIに接続してユーザー情報を取得できたか
+	Reachable bool `json:"reachable"`
+	// Error はGitHub APIに接続できなかった場合の原因
+	Error     string `json:"error,omitempty"`
+	LatencyMS *int64 `json:"latency_ms,omitempty"`
+	Login     string `json:"login,omitempty"`
+	// Scopes はトークンのOAuthスコープ（Fine-grained PATなどスコープを確認できないトークンは省略する）
+	Scopes []string `json:"scopes,omitempty"`
+	// MissingScopes はGitHub Projectとの同期に必要でトークンにないスコープ
+	MissingScopes []string              `json:"missing_scopes,omitempty"`
+	RESTQuota     *GithubQuotaDiagnosis `json:"rest_quota,omitempty"`
+	GraphQLQuota  *GithubQuotaDiagnosis `json:"graphql_quota,omitempty"`
+}
+
+// GithubQuotaDiagnosis はGitHub APIの残りの利用量を表す
+type GithubQuotaDiagnosis struct {
+	Limit     int       `json:"limit"`
+	Remaining int       `json:"remaining"`
+	Used      int       `json:"used"`
+	ResetAt   time.Time `json:"reset_at"`
+}
+
+// Diagnose はユーザーのトークンでGitHub APIに接続し、認証されたユーザー・スコープ・残りの利用量・応答時間を診断する
+// トークンが拒否された場合は再認証が必要なエラーを返し、それ以外の接続の失敗はReachableをfalseにして返す
+func (u *GithubUsecase) Diagnose(ctx context.Context, userID string) (*GithubDiagnostics, error) {
+	account, err := u.githubAccountRepo.FindByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find github account: %w", err)
+	}
+
+	token, err := u.GetToken(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	ctx = github.WithUser(ctx, userID)
+
+	result := &GithubDiagnostics{TokenType: account.TokenKind()}
+	diagnostics, err := u.githubService.Diagnose(ctx, token)
+	if err != nil {
+		if errors.Is(err, model.ErrGithubReauthRequired) {
+			return nil, err
+		}
+		u.logger.WarnContext(ctx, "github diagnostics failed", "error", err, "user_id", userID)
+		result.Error = err.Error()
+		return result, nil
+	}
+
+	latency := diagnostics.Latency.Milliseconds()
+	result.Reachable = true
+	result.LatencyMS = &latency
+	result.Login = diagnostics.Login
+	result.Scopes = diagnostics.Scopes
+	result.MissingScopes = diagnostics.MissingScopes
+	result.RESTQuota = newGithubQuotaDiagnosis(diagnostics.REST)
+	result.GraphQLQuota = newGithubQuotaDiagnosis(diagnostics.GraphQL)
+	return result, nil
+}
+
+func newGithubQuotaDiagnosis(quota github.Quota) *GithubQuotaDiagnosis {
+	return &GithubQuotaDiagnosis{
+		Limit:     quota.Limit,
+		Remaining: quota.Remaining,
+		Used:      quota.Used,
+		ResetAt:   quota.ResetAt,
+	}
+}
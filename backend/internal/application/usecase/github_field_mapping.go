@@ -0,0 +1,172 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/sikigasa/github-task-controller/backend/internal/domain/model"
+	"github.com/sikigasa/github-task-controller/backend/internal/infrastructure/github"
+)
+
+// GetFieldMapping はプロジェクトのGitHub Projectsのフィールドの対応付けを取得する（未設定の場合は既定の対応付け）
+func (u *GithubUsecase) GetFieldMapping(ctx context.Context, userID, projectID string) (*model.GithubFieldMapping, error) {
+	if _, err := u.findOwnedProject(ctx, userID, projectID); err != nil {
+		return nil, err
+	}
+	return u.fieldMapping(ctx, projectID)
+}
+
+// UpdateFieldMapping はプロジェクトのGitHub Projectsのフィールドの対応付けを設定する
+// 連携先のGitHub Projectにフィールドと選択肢が存在することを確認する
+func (u *GithubUsecase) UpdateFieldMapping(ctx context.Context, userID, projectID string, req *model.UpdateGithubFieldMappingRequest) (*model.GithubFieldMapping, error) {
+	project, err := u.findOwnedProject(ctx, userID, projectID)
+	if err != nil {
+		return nil, err
+	}
+	if !project.IsGithubLinked() {
+		return nil, fmt.Errorf("project is not linked to github: %w", model.ErrConflict)
+	}
+
+	mapping := &model.GithubFieldMapping{
+		ProjectID:       projectID,
+		StatusField:     req.StatusField,
+		StatusOptions:   req.StatusOptions,
+		PriorityField:   req.PriorityField,
+		PriorityOptions: req.PriorityOptions,
+		UpdatedAt:       time.Now(),
+	}
+	if mapping.PriorityField == nil {
+		mapping.PriorityOptions = model.GithubPriorityOptions{}
+	} else if p := mapping.PriorityOptions; p.Low == "" || p.Medium == "" || p.High == "" {
+		return nil, fmt.Errorf("priority_options are required for all priorities when priority_field is set: %w", model.ErrInvalidInput)
+	}
+
+	token, err := u.GetToken(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	ctx = github.WithUser(ctx, userID)
+
+	projectGithubID, err := u.githubService.GetProjectID(ctx, token, githubProjectOwner(project), *project.GithubProjectNumber)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get github project id: %w", err)
+	}
+
+	options := mapping.StatusOptions
+	if err := u.validateFieldOptions(ctx, token, projectGithubID, mapping.StatusField, options.Todo, options.InProgress, options.Done); err != nil {
+		return nil, err
+	}
+	if mapping.PriorityField != nil {
+		options := mapping.PriorityOptions
+		if err := u.validateFieldOptions(ctx, token, projectGithubID, *mapping.PriorityField, options.Low, options.Medium, options.High); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := u.fieldMappingRepo.Upsert(ctx, mapping); err != nil {
+		return nil, err
+	}
+
+	u.logger.InfoContext(ctx, "github field mapping updated", "project_id", projectID, "status_field", mapping.StatusField)
+	return mapping, nil
+}
+
+// DeleteFieldMapping はプロジェクトのフィールドの対応付けを削除して既定の対応付けに戻す
+func (u *GithubUsecase) DeleteFieldMapping(ctx context.Context, userID, projectID string) error {
+	if _, err := u.findOwnedProject(ctx, userID, projectID); err != nil {
+		return err
+	}
+	return u.fieldMappingRepo.Delete(ctx, projectID)
+}
+
+// findOwnedProject はユーザーが所有するプロジェクトを取得する
+func (u *GithubUsecase) findOwnedProject(ctx context.Context, userID, projectID string) (*model.Project, error) {
+	if err := validateResourceID(projectID); err != nil {
+		return nil, err
+	}
+
+	project, err := u.projectRepo.FindByID(ctx, projectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find project: %w", err)
+	}
+	if project.UserID != userID {
+		return nil, model.ErrForbidden
+	}
+
+	return project, nil
+}
+
+// fieldMapping はプロジェクトのフィールドの対応付けを取得する（未設定の場合は既定の対応付け）
+func (u *GithubUsecase) fieldMapping(ctx context.Context, projectID string) (*model.GithubFieldMapping, error) {
+	mapping, err := u.fieldMappingRepo.FindByProjectID(ctx, projectID)
+	if errors.Is(err, model.ErrNotFound) {
+		return model.DefaultGithubFieldMapping(projectID), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return mapping, nil
+}
+
+// validateFieldOptions はGitHub Projectに単一選択フィールドと選択肢が存在することを確認する
+func (u *GithubUsecase) validateFieldOptions(ctx context.Context, token, projectGithubID, fieldName string, optionNames ...string) error {
+	// 対応付けの設定の直前にGitHub上で追加された選択肢を使えるよう、キャッシュを使わずに取得する
+	u.githubService.InvalidateFields(projectGithubID)
+	field, err := u.githubService.GetSingleSelectField(ctx, token, projectGithubID, fieldName)
+	if err != nil {
+		return fmt.Errorf("%v: %w", err, model.ErrInvalidInput)
+	}
+
+	for _, name := range optionNames {
+		if _, ok := field.Option(name); !ok {
+			return fmt.Errorf("option %q not found in field %q (available: %s): %w",
+				name, fieldName, strings.Join(field.OptionNames(), ", "), model.ErrInvalidInput)
+		}
+	}
+
+	return nil
+}
+
+// syncFieldValues は対応付けに従ってItemのステータス（と設定されている場合は優先度）のフィールドを設定する
+func (u *GithubUsecase) syncFieldValues(ctx context.Context, token, projectGithubID, itemID string, mapping *model.GithubFieldMapping, task *model.Task) error {
+	if err := u.syncSingleSelect(ctx, token, projectGithubID, itemID, mapping.StatusField, mapping.StatusOption(task.Status)); err != nil {
+		return err
+	}
+	if mapping.PriorityField != nil {
+		if err := u.syncSingleSelect(ctx, token, projectGithubID, itemID, *mapping.PriorityField, mapping.PriorityOption(task.Priority)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// syncSingleSelect はItemの単一選択フィールドを名前が一致する選択肢に設定する
+// フィールド・選択肢のIDはProjectServiceのキャッシュを使い、選択肢が見つからないか更新に失敗した場合は
+// GitHub上でフィールドが編集された可能性があるため、キャッシュを削除して次の取得で取得し直す
+func (u *GithubUsecase) syncSingleSelect(ctx context.Context, token, projectGithubID, itemID, fieldName, optionName string) error {
+	field, err := u.githubService.GetSingleSelectField(ctx, token, projectGithubID, fieldName)
+	if err != nil {
+		return fmt.Errorf("failed to get github field: %w", err)
+	}
+
+	option, ok := field.Option(optionName)
+	if !ok {
+		u.githubService.InvalidateFields(projectGithubID)
+		if field, err = u.githubService.GetSingleSelectField(ctx, token, projectGithubID, fieldName); err != nil {
+			return fmt.Errorf("failed to get github field: %w", err)
+		}
+		if option, ok = field.Option(optionName); !ok {
+			return fmt.Errorf("option %q not found in github field %q", optionName, fieldName)
+		}
+	}
+
+	if err := u.githubService.UpdateItemSingleSelectField(ctx, token, projectGithubID, itemID, field.ID, option.ID); err != nil {
+		u.githubService.InvalidateFields(projectGithubID)
+		return fmt.Errorf("failed to update github field %q: %w", fieldName, err)
+	}
+
+	return nil
+}
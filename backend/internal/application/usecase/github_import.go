@@ -0,0 +1,213 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/sikigasa/github-task-controller/backend/internal/domain/model"
+	"github.com/sikigasa/github-task-controller/backend/internal/infrastructure/github"
+)
+
+// pullRequestSyncCost はIssueのPull Requestの同期1回で消費するGraphQLのポイントの見込み
+const pullRequestSyncCost = 1
+
+// GithubIssueImportResult は担当のGitHub Issueの取り込み結果を表す
+type GithubIssueImportResult struct {
+	// Created は新たに作成したタスクの数
+	Created int `json:"created"`
+	// Updated はタイトル・説明・ステータスを更新したタスクの数
+	Updated int `json:"updated"`
+	// Unchanged は変更のなかったタスク（作成しなかったクローズ済みのIssueを含む）の数
+	Unchanged int `json:"unchanged"`
+}
+
+// ImportAssignedIssues はユーザーが担当のGitHub Issueを設定の取り込み先プロジェクトのタスクとして作成・更新する
+// IssueのURLで既存のタスクを特定し、クローズされたIssueのタスクは完了に、再オープンされたIssueのタスクは再開する
+// GraphQLの残りポイントが下限を下回る場合は取り込みを見送り、次回に同じ範囲から取り込み直す
+func (u *GithubUsecase) ImportAssignedIssues(ctx context.Context, userID string) (*GithubIssueImportResult, error) {
+	settings, err := findSettings(ctx, u.settingsRepo, userID)
+	if err != nil {
+		return nil, err
+	}
+	if settings.GithubImportProjectID == nil {
+		return nil, fmt.Errorf("github import project is not set: %w", model.ErrConflict)
+	}
+
+	project, err := u.projectRepo.FindByID(ctx, *settings.GithubImportProjectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find project: %w", err)
+	}
+	if project.UserID != userID {
+		return nil, model.ErrForbidden
+	}
+	if err := u.consumeSyncQuota(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	// 同じIssueのタスクを複数のインスタンスが重複して作成しないよう、取り込み先プロジェクトの同期と排他する
+	ctx, unlock, err := u.lockProjectSync(ctx, project.ID)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	token, err := u.GetToken(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	ctx = github.WithUser(ctx, userID)
+	if err := u.githubService.CheckBudget(token, 0); err != nil {
+		return nil, fmt.Errorf("github import deferred: %v: %w", err, model.ErrRateLimited)
+	}
+
+	// 取得中に更新されたIssueを取りこぼさないよう、取得前の時刻を記録する
+	startedAt := time.Now()
+	issues, err := u.githubService.ListAssignedIssues(ctx, token, settings.LastGithubImportAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list assigned issues: %w", err)
+	}
+
+	result := &GithubIssueImportResult{}
+	// 更新日時の古い順に反映し、同じIssueの状態が最新のものになるようにする
+	for i := len(issues) - 1; i >= 0; i-- {
+		// 最終取り込み日時を更新しないため、次回は見送ったIssueから取り込み直す
+		if err := u.githubService.CheckBudget(token, pullRequestSyncCost); err != nil {
+			return result, fmt.Errorf("github import deferred: %v: %w", err, model.ErrRateLimited)
+		}
+		changed, created, err := u.importIssue(ctx, project.ID, &issues[i])
+		if err != nil {
+			return result, fmt.Errorf("failed to import issue %s: %w", issues[i].URL, err)
+		}
+		u.refreshIssuePullRequests(ctx, token, project.ID, issues[i].URL)
+		switch {
+		case created:
+			result.Created++
+		case changed:
+			result.Updated++
+		default:
+			result.Unchanged++
+		}
+	}
+
+	if err := u.settingsRepo.MarkGithubImported(ctx, userID, startedAt); err != nil {
+		return result, fmt.Errorf("failed to mark github imported: %w", err)
+	}
+
+	u.logger.InfoContext(ctx, "assigned github issues imported", "user_id", userID, "project_id", project.ID,
+		"created", result.Created, "updated", result.Updated)
+	return result, nil
+}
+
+// importIssue はIssueに対応するタスクを作成または更新する
+func (u *GithubUsecase) importIssue(ctx context.Context, projectID string, issue *github.Issue) (changed, created bool, err error) {
+	task, err := u.taskRepo.FindByGithubIssueURL(ctx, projectID, issue.URL)
+	if errors.Is(err, model.ErrNotFound) {
+		// クローズ済みのIssueはタスクとして作成しない
+		if issue.IsClosed() {
+			return false, false, nil
+		}
+		return true, true, u.createIssueTask(ctx, projectID, issue)
+	}
+	if err != nil {
+		return false, false, fmt.Errorf("failed to find task: %w", err)
+	}
+
+	changed, err = u.updateIssueTask(ctx, task, issue)
+	return changed, false, err
+}
+
+// updateIssueTask はIssueのタイトル・本文・状態（オープン・クローズ）をタスクに反映する
+// クローズされたIssueのタスクは完了に、再オープンされたIssueのタスクは再開する
+func (u *GithubUsecase) updateIssueTask(ctx context.Context, task *model.Task, issue *github.Issue) (bool, error) {
+	req := &model.PatchTaskRequest{}
+	if title := truncateRunes(issue.Title, 255); title != "" && task.Title != title {
+		req.Title = &title
+	}
+	if description := truncateRunes(stripSubtaskChecklist(issue.Body), 10000); task.Description != description {
+		req.Description = &description
+	}
+	switch {
+	case issue.IsClosed() && task.Status != model.TaskStatusDone:
+		done := model.TaskStatusDone
+		req.Status = &done
+	case !issue.IsClosed() && task.Status == model.TaskStatusDone:
+		todo := model.TaskStatusTodo
+		req.Status = &todo
+		req.Reopen = true
+	}
+	if req.Title == nil && req.Description == nil && req.Status == nil {
+		return false, nil
+	}
+
+	if _, err := u.taskUsecase.patchTask(ctx, task.ID, req); err != nil {
+		// ステータス遷移のルールで拒否された場合は、他のIssueの取り込みを続ける
+		if errors.Is(err, model.ErrInvalidInput) || errors.Is(err, model.ErrConflict) {
+			u.logger.WarnContext(ctx, "skipping github issue update", "error", err, "task_id", task.ID, "issue_url", issue.URL)
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// createIssueTask はIssueからタスクを作成してIssueを紐付ける
+// 作成と紐付けは1つのトランザクションで行い、紐付けに失敗したタスクが次の取り込みで重複して作成されないようにする
+func (u *GithubUsecase) createIssueTask(ctx context.Context, projectID string, issue *github.Issue) error {
+	return u.tx.WithinTx(ctx, func(ctx context.Context) error {
+		task, err := u.taskUsecase.createTask(ctx, &model.CreateTaskRequest{
+			ProjectID:   projectID,
+			Title:       truncateRunes(issue.Title, 255),
+			Description: truncateRunes(stripSubtaskChecklist(issue.Body), 10000),
+		})
+		if err != nil {
+			return err
+		}
+
+		task.GithubIssueNumber = &issue.Number
+		task.GithubIssueURL = &issue.URL
+		if err := u.taskRepo.Update(ctx, task); err != nil {
+			return fmt.Errorf("failed to link github issue: %w", err)
+		}
+		return nil
+	})
+}
+
+// refreshIssuePullRequests はIssueに対応するタスクのPull Requestの紐付けを更新する
+// 取り込み自体は完了しているため、失敗してもログに記録するだけにする
+func (u *GithubUsecase) refreshIssuePullRequests(ctx context.Context, token, projectID, issueURL string) {
+	task, err := u.taskRepo.FindByGithubIssueURL(ctx, projectID, issueURL)
+	if errors.Is(err, model.ErrNotFound) {
+		return
+	}
+	if err == nil && !task.IsGithubOrphaned() {
+		_, err = u.syncPullRequests(ctx, token, task)
+	}
+	if err != nil {
+		u.logger.WarnContext(ctx, "failed to refresh pull requests for github issue", "error", err, "issue_url", issueURL)
+	}
+}
+
+// ImportAllAssignedIssues は取り込み先プロジェクトを設定した全ユーザーの担当のGitHub Issueを取り込む
+// ユーザーごとの失敗や見送りはログに記録して次のユーザーの取り込みを続ける
+func (u *GithubUsecase) ImportAllAssignedIssues(ctx context.Context) error {
+	subscribers, err := u.settingsRepo.FindGithubImportSubscribers(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to find github import subscribers: %w", err)
+	}
+
+	for _, settings := range subscribers {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		_, err := u.ImportAssignedIssues(ctx, settings.UserID)
+		switch {
+		case errors.Is(err, model.ErrRateLimited):
+			u.logger.InfoContext(ctx, "github issue import deferred", "reason", err, "user_id", settings.UserID)
+		case err != nil:
+			u.logger.ErrorContext(ctx, "failed to import assigned github issues", "error", err, "user_id", settings.UserID)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,316 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/sikigasa/github-task-controller/backend/internal/domain/model"
+	"github.com/sikigasa/github-task-controller/backend/internal/infrastructure/github"
+)
+
+// GithubItemImportAction はGitHub ProjectのItemの取り込みでタスクに行う操作を表す
+type GithubItemImportAction string
+
+const (
+	// GithubItemImportCreate はItemからタスクを作成する
+	GithubItemImportCreate GithubItemImportAction = "create"
+	// GithubItemImportUpdate はItemの内容をタスクに反映する
+	GithubItemImportUpdate GithubItemImportAction = "update"
+	// GithubItemImportUnchanged はタスクがItemと一致しているため何もしない
+	GithubItemImportUnchanged GithubItemImportAction = "unchanged"
+	// GithubItemImportSkip はItemを取り込まない（理由はReasonに記録する）
+	GithubItemImportSkip GithubItemImportAction = "skip"
+)
+
+// GithubItemImport はGitHub ProjectのItem1件の取り込み結果を表す
+type GithubItemImport struct {
+	GithubItemID string                 `json:"github_item_id"`
+	Title        string                 `json:"title"`
+	Action       GithubItemImportAction `json:"action"`
+	// TaskID は作成・更新したタスクのID（ドライランで作成するタスクは未定のためnil）
+	TaskID *string `json:"task_id"`
+	// Status は取り込み後のタスクのステータス
+	Status *model.TaskStatus `json:"status,omitempty"`
+	// Reason は取り込まない理由（pull_request・no_content・empty_title・rejected・deleted）
+	Reason string `json:"reason,omitempty"`
+}
+
+// GithubProjectImportResult は連携先のGitHub ProjectのItemの取り込み結果を表す
+type GithubProjectImportResult struct {
+	// DryRun はタスクを変更せずに結果だけを返したか
+	DryRun    bool               `json:"dry_run"`
+	Created   int                `json:"created"`
+	Updated   int                `json:"updated"`
+	Unchanged int                `json:"unchanged"`
+	Skipped   int                `json:"skipped"`
+	Items     []GithubItemImport `json:"items"`
+}
+
+// add はItemの取り込み結果を集計に加える
+func (r *GithubProjectImportResult) add(item GithubItemImport) {
+	switch item.Action {
+	case GithubItemImportCreate:
+		r.Created++
+	case GithubItemImportUpdate:
+		r.Updated++
+	case GithubItemImportUnchanged:
+		r.Unchanged++
+	default:
+		r.Skipped++
+	}
+	r.Items = append(r.Items, item)
+}
+
+// ImportGithubProjectItems は連携先のGitHub ProjectのItemをすべて読み込み、プロジェクトのタスクとして作成・更新する
+// Item IDかIssueのURLが一致するタスクはItemのタイトル・本文・ステータス・優先度に合わせ、一致するタスクがないItemはタスクを作成する
+// ステータス・優先度はフィールドの対応付けで変換し、対応付けにない選択肢はタスクの作成時は既定の値にし、更新時は変更しない
+// Pull RequestのItemは取り込まない。dryRunがtrueの場合はタスクを変更せずに、取り込んだ場合の結果を返す
+func (u *GithubUsecase) ImportGithubProjectItems(ctx context.Context, userID, projectID string, dryRun bool) (*GithubProjectImportResult, error) {
+	project, err := u.findOwnedProject(ctx, userID, projectID)
+	if err != nil {
+		return nil, err
+	}
+	if !project.IsGithubLinked() {
+		return nil, fmt.Errorf("project is not linked to github: %w", model.ErrConflict)
+	}
+	if err := u.consumeSyncQuota(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	// 同じItemのタスクを双方向の同期や他のインスタンスと重複して作成しないよう、プロジェクトの同期と排他する
+	if !dryRun {
+		var unlock func()
+		ctx, unlock, err = u.lockProjectSync(ctx, project.ID)
+		if err != nil {
+			return nil, err
+		}
+		defer unlock()
+	}
+
+	token, err := u.GetToken(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	ctx = github.WithUser(ctx, userID)
+	if err := u.githubService.CheckBudget(token, 0); err != nil {
+		return nil, fmt.Errorf("github project import deferred: %v: %w", err, model.ErrRateLimited)
+	}
+
+	mapping, err := u.fieldMapping(ctx, project.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load github field mapping: %w", err)
+	}
+	priorityField := ""
+	if mapping.PriorityField != nil {
+		priorityField = *mapping.PriorityField
+	}
+
+	items, err := u.githubService.GetProjectItems(ctx, token, githubProjectOwner(project), *project.GithubProjectNumber, mapping.StatusField, priorityField)
+	if errors.Is(err, github.ErrBudgetExhausted) {
+		return nil, fmt.Errorf("github project import deferred: %v: %w", err, model.ErrRateLimited)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get github project items: %w", err)
+	}
+
+	tasks, err := u.taskRepo.FindByProjectIDs(ctx, []string{project.ID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to find tasks: %w", err)
+	}
+	tasksByItemID := make(map[string]*model.Task, len(tasks))
+	tasksByIssueURL := make(map[string]*model.Task, len(tasks))
+	for _, task := range tasks {
+		if task.GithubItemID != nil && !task.IsGithubOrphaned() {
+			tasksByItemID[*task.GithubItemID] = task
+		} else if task.HasGithubIssue() {
+			// 他のItemに同期しているタスクは、同じIssueのItemがあっても付け替えない
+			tasksByIssueURL[*task.GithubIssueURL] = task
+		}
+	}
+	// ゴミ箱のタスクのItemは、タスクを元に戻せるよう新しいタスクとして取り込まない
+	deleted, err := u.taskRepo.FindDeletedByProjectIDs(ctx, []string{project.ID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to find deleted tasks: %w", err)
+	}
+	deletedItemIDs := make(map[string]bool, len(deleted))
+	for _, task := range deleted {
+		if task.GithubItemID != nil {
+			deletedItemIDs[*task.GithubItemID] = true
+		}
+	}
+
+	result := &GithubProjectImportResult{DryRun: dryRun, Items: []GithubItemImport{}}
+	for i := range items {
+		if ctx.Err() != nil {
+			return result, ctx.Err()
+		}
+		item := &items[i]
+
+		task, ok := tasksByItemID[item.ID]
+		if !ok && item.IssueURL != nil {
+			task = tasksByIssueURL[*item.IssueURL]
+		}
+
+		var imported GithubItemImport
+		if task == nil && deletedItemIDs[item.ID] {
+			imported = GithubItemImport{GithubItemID: item.ID, Title: item.Title, Action: GithubItemImportSkip, Reason: "deleted"}
+		} else if task == nil {
+			imported, err = u.importNewItem(ctx, project, mapping, item, dryRun)
+		} else {
+			imported, err = u.importLinkedItem(ctx, project, mapping, task, item, dryRun)
+		}
+		if err != nil {
+			return result, fmt.Errorf("failed to import github item %s: %w", item.ID, err)
+		}
+		result.add(imported)
+	}
+
+	u.logger.InfoContext(ctx, "github project items imported", "project_id", project.ID, "dry_run", dryRun,
+		"created", result.Created, "updated", result.Updated, "unchanged", result.Unchanged, "skipped", result.Skipped)
+	return result, nil
+}
+
+// importNewItem は一致するタスクがないItemからタスクを作成し、Itemと一致させた時点を記録する
+func (u *GithubUsecase) importNewItem(ctx context.Context, project *model.Project, mapping *model.GithubFieldMapping, item *github.ProjectItem, dryRun bool) (GithubItemImport, error) {
+	imported := GithubItemImport{GithubItemID: item.ID, Title: item.Title}
+	if reason := skipItemReason(item); reason != "" {
+		imported.Action = GithubItemImportSkip
+		imported.Reason = reason
+		return imported, nil
+	}
+
+	req := &model.CreateTaskRequest{
+		ProjectID:   project.ID,
+		Title:       truncateRunes(item.Title, 255),
+		Description: truncateRunes(stripSubtaskChecklist(item.Body), 10000),
+	}
+	if status, ok := mapping.TaskStatusFor(item.Status); ok {
+		req.Status = &status
+	}
+	if priority, ok := mapping.TaskPriorityFor(item.Priority); ok {
+		req.Priority = &priority
+	}
+
+	imported.Action = GithubItemImportCreate
+	imported.Status = req.Status
+	if dryRun {
+		return imported, nil
+	}
+
+	// 作成とItemへの同期は1つのトランザクションで行い、同期を保存できなかったタスクが次の取り込みで重複して作成されないようにする
+	var task *model.Task
+	err := u.tx.WithinTx(ctx, func(ctx context.Context) error {
+		created, err := u.taskUsecase.createTask(ctx, req)
+		if err != nil {
+			return err
+		}
+
+		created.GithubItemID = &item.ID
+		created.GithubIssueNumber = item.IssueNumber
+		created.GithubIssueURL = item.IssueURL
+		task, err = u.linkImportedTask(ctx, project, created, item)
+		return err
+	})
+	if err != nil {
+		return imported, err
+	}
+
+	imported.TaskID = &task.ID
+	imported.Status = &task.Status
+	return imported, nil
+}
+
+// importLinkedItem はItem IDかIssueのURLが一致するタスクをItemの内容に合わせ、Itemと一致させた時点を記録する
+// IssueのURLだけが一致したタスク（Issueから取り込んだタスク等）はItemに同期したタスクにする
+func (u *GithubUsecase) importLinkedItem(ctx context.Context, project *model.Project, mapping *model.GithubFieldMapping, task *model.Task, item *github.ProjectItem, dryRun bool) (GithubItemImport, error) {
+	imported := GithubItemImport{GithubItemID: item.ID, Title: item.Title, TaskID: &task.ID, Status: &task.Status}
+	if reason := skipItemReason(item); reason != "" {
+		imported.Action = GithubItemImportSkip
+		imported.Reason = reason
+		return imported, nil
+	}
+
+	linked := task.GithubItemID != nil && *task.GithubItemID == item.ID && !task.IsGithubOrphaned()
+	req, changed := itemPatch(mapping, task, item)
+	switch {
+	case changed:
+		imported.Action = GithubItemImportUpdate
+	case !linked:
+		// 内容が一致していても、Itemに同期したタスクにするため更新として数える
+		imported.Action = GithubItemImportUpdate
+	default:
+		imported.Action = GithubItemImportUnchanged
+	}
+	if req.Status != nil {
+		if err := u.taskUsecase.policy.Validate(task.Status, *req.Status, req.Reopen); err != nil {
+			// ステータスの遷移のルールで拒否される変更は、双方向の同期と同様に取り込まない
+			imported.Action = GithubItemImportSkip
+			imported.Reason = "rejected"
+			return imported, nil
+		}
+		imported.Status = req.Status
+	}
+	if dryRun || imported.Action == GithubItemImportUnchanged {
+		return imported, nil
+	}
+
+	if changed {
+		updated, err := u.taskUsecase.patchTask(ctx, task.ID, req)
+		if errors.Is(err, model.ErrInvalidInput) || errors.Is(err, model.ErrConflict) {
+			u.logger.WarnContext(ctx, "skipping github item import", "error", err, "task_id", task.ID, "github_item_id", item.ID)
+			imported.Action = GithubItemImportSkip
+			imported.Reason = "rejected"
+			imported.Status = &task.Status
+			return imported, nil
+		}
+		if err != nil {
+			return imported, err
+		}
+		task = updated
+	}
+
+	if linked {
+		u.recordTaskSync(ctx, project, task, &item.UpdatedAt)
+		imported.Status = &task.Status
+		return imported, nil
+	}
+
+	task.GithubItemID = &item.ID
+	task.GithubSyncState = nil
+	task, err := u.linkImportedTask(ctx, project, task, item)
+	if err != nil {
+		return imported, err
+	}
+	imported.Status = &task.Status
+	return imported, nil
+}
+
+// linkImportedTask は取り込んだタスクのItemへの同期を保存し、Itemと一致させた時点を記録する
+// 記録した時点より後の変更だけをGitHubに反映するよう、保存後のタスクを読み直して記録する
+func (u *GithubUsecase) linkImportedTask(ctx context.Context, project *model.Project, task *model.Task, item *github.ProjectItem) (*model.Task, error) {
+	if err := u.taskRepo.Update(ctx, task); err != nil {
+		return nil, fmt.Errorf("failed to link github item: %w", err)
+	}
+	task, err := u.taskRepo.FindByID(ctx, task.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find task: %w", err)
+	}
+
+	u.recordTaskSync(ctx, project, task, &item.UpdatedAt)
+	return task, nil
+}
+
+// skipItemReason はタスクとして取り込まないItemの理由を返す（取り込む場合は空文字）
+func skipItemReason(item *github.ProjectItem) string {
+	switch {
+	case item.ContentType == github.ContentTypePullRequest:
+		return "pull_request"
+	case item.ContentType == "":
+		// アクセスできないリポジトリのIssue等、内容を取得できないItem
+		return "no_content"
+	case truncateRunes(item.Title, 255) == "":
+		return "empty_title"
+	}
+	return ""
+}
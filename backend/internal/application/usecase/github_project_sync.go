@@ -0,0 +1,287 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/sikigasa/github-task-controller/backend/internal/domain/model"
+	"github.com/sikigasa/github-task-controller/backend/internal/infrastructure/github"
+)
+
+// GithubProjectSyncResult はプロジェクトとGitHub Projectの双方向の同期の結果を表す
+type GithubProjectSyncResult struct {
+	// Pulled はGitHubの変更を取り込んだタスクの数
+	Pulled int `json:"pulled"`
+	// Pushed はタスクの変更をGitHubに反映したタスクの数
+	Pushed int `json:"pushed"`
+	// Conflicts は両方が変更されていたタスクの数（更新日時の新しい方に合わせ、Pulled・Pushedにも含む）
+	Conflicts int `json:"conflicts"`
+	// Unchanged は変更のなかった（ステータスの遷移のルールで取り込めなかったものを含む）タスクの数
+	Unchanged int `json:"unchanged"`
+	// Skipped はItemが削除されていたために同期できなかったタスクの数
+	Skipped int `json:"skipped"`
+}
+
+// SyncProject はプロジェクトの同期済みのタスクと連携先のGitHub ProjectのItemを双方向に同期する
+// タスクとItemを最後に一致させた時点より後に変更された側に合わせ、両方が変更されていた場合は更新日時の新しい方に合わせる
+// GitHubから取り込むのはタイトル・本文・ステータス（と優先度のフィールドを設定している場合は優先度）
+func (u *GithubUsecase) SyncProject(ctx context.Context, userID, projectID string) (*GithubProjectSyncResult, error) {
+	project, err := u.findOwnedProject(ctx, userID, projectID)
+	if err != nil {
+		return nil, err
+	}
+	if !project.IsGithubLinked() {
+		return nil, fmt.Errorf("project is not linked to github: %w", model.ErrConflict)
+	}
+	if err := u.consumeSyncQuota(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	ctx, unlock, err := u.lockProjectSync(ctx, project.ID)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	token, err := u.GetToken(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	ctx = github.WithUser(ctx, userID)
+	if err := u.githubService.CheckBudget(token, 0); err != nil {
+		return nil, fmt.Errorf("github project sync deferred: %v: %w", err, model.ErrRateLimited)
+	}
+
+	mapping, err := u.fieldMapping(ctx, project.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load github field mapping: %w", err)
+	}
+	priorityField := ""
+	if mapping.PriorityField != nil {
+		priorityField = *mapping.PriorityField
+	}
+
+	owner := githubProjectOwner(project)
+	projectGithubID, err := u.githubService.GetProjectID(ctx, token, owner, *project.GithubProjectNumber)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get github project id: %w", err)
+	}
+	items, err := u.githubService.GetProjectItems(ctx, token, owner, *project.GithubProjectNumber, mapping.StatusField, priorityField)
+	if errors.Is(err, github.ErrBudgetExhausted) {
+		// ページの途中で残りポイントが下限を下回った場合も、タスクを同期せずに次の同期に任せる
+		return nil, fmt.Errorf("github project sync deferred: %v: %w", err, model.ErrRateLimited)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get github project items: %w", err)
+	}
+	itemsByID := make(map[string]*github.ProjectItem, len(items))
+	for i := range items {
+		itemsByID[items[i].ID] = &items[i]
+	}
+
+	tasks, err := u.taskRepo.FindByProjectIDs(ctx, []string{project.ID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to find tasks: %w", err)
+	}
+	baselines, err := u.taskSyncRepo.FindByProjectID(ctx, project.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find github task syncs: %w", err)
+	}
+
+	result := &GithubProjectSyncResult{}
+	for _, task := range tasks {
+		if ctx.Err() != nil {
+			return result, ctx.Err()
+		}
+		// 未同期のタスクはGitHubに追加しない（手動の同期で追加する）
+		if task.GithubItemID == nil || task.IsGithubOrphaned() {
+			continue
+		}
+		// 取得できなかったItem（GitHub上で削除されたものを含む）は、タスクの次の同期で連携切れを判定する
+		item, ok := itemsByID[*task.GithubItemID]
+		if !ok {
+			continue
+		}
+
+		direction, conflict, changed, err := u.syncTaskItem(ctx, token, project, projectGithubID, mapping, task, item, baselines[task.ID])
+		if conflict {
+			result.Conflicts++
+		}
+		switch {
+		case errors.Is(err, model.ErrConflict):
+			// Itemが削除されていた場合はプロジェクトの設定に従って連携切れにしているため、他のタスクの同期を続ける
+			u.logger.WarnContext(ctx, "skipping github push", "error", err, "task_id", task.ID)
+			result.Skipped++
+		case err != nil:
+			return result, err
+		case changed && direction == model.GithubSyncPull:
+			result.Pulled++
+		case changed && direction == model.GithubSyncPush:
+			result.Pushed++
+		default:
+			result.Unchanged++
+		}
+	}
+
+	u.logger.InfoContext(ctx, "project synced with github", "project_id", project.ID,
+		"pulled", result.Pulled, "pushed", result.Pushed, "conflicts", result.Conflicts, "skipped", result.Skipped)
+	return result, nil
+}
+
+// ListGithubProjectItems は連携先のGitHub ProjectのItemsをcursorの次から1ページ取得する（cursorが空の場合は先頭から）
+// 次のページがある場合は、返したEndCursorをcursorに指定して続きを取得する
+func (u *GithubUsecase) ListGithubProjectItems(ctx context.Context, userID, projectID, cursor string) (*github.ProjectItemsPage, error) {
+	project, err := u.findOwnedProject(ctx, userID, projectID)
+	if err != nil {
+		return nil, err
+	}
+	if !project.IsGithubLinked() {
+		return nil, fmt.Errorf("project is not linked to github: %w", model.ErrConflict)
+	}
+
+	token, err := u.GetToken(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	ctx = github.WithUser(ctx, userID)
+
+	mapping, err := u.fieldMapping(ctx, project.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load github field mapping: %w", err)
+	}
+	priorityField := ""
+	if mapping.PriorityField != nil {
+		priorityField = *mapping.PriorityField
+	}
+
+	page, err := u.githubService.GetProjectItemsPage(ctx, token, githubProjectOwner(project), *project.GithubProjectNumber, mapping.StatusField, priorityField, cursor)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get github project items: %w", err)
+	}
+	return page, nil
+}
+
+// syncTaskItem はタスクとItemのうち基準より後に変更された側に合わせる（両方が変更されていた場合は更新日時の新しい方）
+// 合わせた向き、競合していたか、どちらかを変更したかを返す
+func (u *GithubUsecase) syncTaskItem(
+	ctx context.Context,
+	token string,
+	project *model.Project,
+	projectGithubID string,
+	mapping *model.GithubFieldMapping,
+	task *model.Task,
+	item *github.ProjectItem,
+	baseline *model.GithubTaskSync,
+) (model.GithubSyncDirection, bool, bool, error) {
+	direction, conflict := model.ResolveGithubSync(task, item.UpdatedAt, baseline)
+	if conflict {
+		u.logger.InfoContext(ctx, "github sync conflict resolved by updated_at",
+			"task_id", task.ID, "github_item_id", item.ID, "direction", direction,
+			"task_updated_at", task.UpdatedAt, "item_updated_at", item.UpdatedAt)
+	}
+
+	switch direction {
+	case model.GithubSyncPull:
+		pulled, err := u.pullItem(ctx, project, mapping, task, item)
+		if err != nil {
+			return direction, conflict, false, fmt.Errorf("failed to pull github item %s: %w", item.ID, err)
+		}
+		return direction, conflict, pulled, nil
+	case model.GithubSyncPush:
+		if err := u.pushTask(ctx, token, project, projectGithubID, task); err != nil {
+			if errors.Is(err, model.ErrConflict) {
+				return direction, conflict, false, err
+			}
+			return direction, conflict, false, fmt.Errorf("failed to push task %s: %w", task.ID, err)
+		}
+		return direction, conflict, true, nil
+	}
+	return direction, conflict, false, nil
+}
+
+// pullItem はItemのタイトル・本文・ステータス・優先度のうちタスクと異なるものをタスクに取り込み、一致させた時点を記録する
+// 対応付けにない選択肢は取り込まない。ステータスの遷移のルールで拒否された場合は取り込まずにfalseを返す
+func (u *GithubUsecase) pullItem(ctx context.Context, project *model.Project, mapping *model.GithubFieldMapping, task *model.Task, item *github.ProjectItem) (bool, error) {
+	req, pulled := itemPatch(mapping, task, item)
+	if pulled {
+		updated, err := u.taskUsecase.patchTask(ctx, task.ID, req)
+		switch {
+		case errors.Is(err, model.ErrInvalidInput), errors.Is(err, model.ErrConflict):
+			// 取り込めない変更は、次にどちらかが変更されるまで取り込み直さない
+			u.logger.WarnContext(ctx, "skipping github item pull", "error", err, "task_id", task.ID, "github_item_id", item.ID)
+			pulled = false
+		case err != nil:
+			return false, err
+		default:
+			task = updated
+		}
+	}
+
+	u.recordTaskSync(ctx, project, task, &item.UpdatedAt)
+	return pulled, nil
+}
+
+// itemPatch はItemのタイトル・本文・ステータス・優先度のうちタスクと異なるものを変更するリクエストと、変更があるかを返す
+// 対応付けにない選択肢は変更しない
+func itemPatch(mapping *model.GithubFieldMapping, task *model.Task, item *github.ProjectItem) (*model.PatchTaskRequest, bool) {
+	req := &model.PatchTaskRequest{}
+	if title := truncateRunes(item.Title, 255); title != "" && task.Title != title {
+		req.Title = &title
+	}
+	if description := truncateRunes(stripSubtaskChecklist(item.Body), 10000); task.Description != description {
+		req.Description = &description
+	}
+	if status, ok := mapping.TaskStatusFor(item.Status); ok && status != task.Status {
+		req.Status = &status
+		req.Reopen = task.Status == model.TaskStatusDone
+	}
+	if priority, ok := mapping.TaskPriorityFor(item.Priority); ok && priority != task.Priority {
+		req.Priority = &priority
+	}
+	return req, req.Title != nil || req.Description != nil || req.Status != nil || req.Priority != nil
+}
+
+// SyncAllProjects はGitHub Projectと連携した全プロジェクトを双方向に同期する
+// プロジェクトごとの失敗や見送りはログに記録して次のプロジェクトの同期を続ける
+func (u *GithubUsecase) SyncAllProjects(ctx context.Context) error {
+	projects, err := u.projectRepo.FindGithubLinked(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to find github linked projects: %w", err)
+	}
+
+	for _, project := range projects {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		_, err := u.SyncProject(ctx, project.UserID, project.ID)
+		switch {
+		case errors.Is(err, model.ErrRateLimited), errors.Is(err, errLocked), errors.Is(err, model.ErrGithubReauthRequired):
+			u.logger.InfoContext(ctx, "github project sync deferred", "reason", err, "project_id", project.ID)
+		case err != nil:
+			u.logger.ErrorContext(ctx, "failed to sync project with github", "error", err, "project_id", project.ID)
+		}
+	}
+	return nil
+}
+
+// isTaskSynced はタスクがGitHubと最後に一致させた時点から変更されていないかを返す
+// GitHubから取り込んだ変更のイベントで、同じ内容をGitHubに反映し直さないために使う
+func (u *GithubUsecase) isTaskSynced(ctx context.Context, task *model.Task) (bool, error) {
+	sync, err := u.taskSyncRepo.FindByTaskID(ctx, task.ID)
+	if errors.Is(err, model.ErrNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return !task.UpdatedAt.After(sync.LocalUpdatedAt), nil
+}
+
+// truncateRunes は文字列を先頭からlimitの文字数までに切り詰める
+func truncateRunes(s string, limit int) string {
+	if runes := []rune(s); len(runes) > limit {
+		return string(runes[:limit])
+	}
+	return s
+}
@@ -0,0 +1,152 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/sikigasa/github-task-controller/backend/internal/domain/model"
+	"github.com/sikigasa/github-task-controller/backend/internal/infrastructure/github"
+)
+
+// ListTaskPullRequests はタスクのGitHub Issueを参照しているPull Requestを取得する（最後に同期した時点の状態）
+func (u *GithubUsecase) ListTaskPullRequests(ctx context.Context, userID, taskID string) ([]*model.TaskPullRequest, error) {
+	if _, _, err := u.findOwnedTask(ctx, userID, taskID); err != nil {
+		return nil, err
+	}
+
+	pullRequests, err := u.taskPullRequestRepo.FindByTaskID(ctx, taskID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find task pull requests: %w", err)
+	}
+
+	return pullRequests, nil
+}
+
+// SyncTaskPullRequests はタスクのGitHub Issueを参照しているPull Requestを検出して紐付けを更新する
+// Pull RequestのマージなどでIssueがクローズされている場合はタスクを完了にする
+func (u *GithubUsecase) SyncTaskPullRequests(ctx context.Context, userID, taskID string) ([]*model.TaskPullRequest, error) {
+	task, _, err := u.findOwnedTask(ctx, userID, taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !task.HasGithubIssue() {
+		return nil, fmt.Errorf("task is not linked to a github issue: %w", model.ErrConflict)
+	}
+	if task.IsGithubOrphaned() {
+		return nil, errGithubLinkDeleted(task)
+	}
+	if err := u.consumeSyncQuota(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	token, err := u.GetToken(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	ctx = github.WithUser(ctx, userID)
+
+	return u.syncPullRequests(ctx, token, task)
+}
+
+// findOwnedTask はユーザーが所有するプロジェクトのタスクをプロジェクトとともに取得する
+func (u *GithubUsecase) findOwnedTask(ctx context.Context, userID, taskID string) (*model.Task, *model.Project, error) {
+	if err := validateResourceID(taskID); err != nil {
+		return nil, nil, err
+	}
+
+	task, err := u.taskRepo.FindByID(ctx, taskID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to find task: %w", err)
+	}
+
+	project, err := u.projectRepo.FindByID(ctx, task.ProjectID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to find project: %w", err)
+	}
+
+	if project.UserID != userID {
+		return nil, nil, model.ErrForbidden
+	}
+
+	return task, project, nil
+}
+
+// syncPullRequests はIssueをクローズするPull Requestと、本文のキーワード・ブランチ名でIssueを参照するPull Requestを紐付ける
+func (u *GithubUsecase) syncPullRequests(ctx context.Context, token string, task *model.Task) ([]*model.TaskPullRequest, error) {
+	owner, repo, number, err := github.ParseIssueURL(*task.GithubIssueURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse github issue url: %v: %w", err, model.ErrConflict)
+	}
+
+	links, err := u.githubService.GetIssuePullRequests(ctx, token, owner, repo, number)
+	if errors.Is(err, github.ErrNotFound) {
+		// Issueが削除されている場合は以降の同期が失敗し続けないよう、タスクを連携切れにする
+		if err := u.handleGithubDeletion(ctx, task); err != nil {
+			return nil, err
+		}
+		return nil, errGithubLinkDeleted(task)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get github pull requests: %w", err)
+	}
+
+	var pullRequests []*model.TaskPullRequest
+	seen := make(map[string]bool)
+	link := func(pr *github.PullRequest, linkType model.PullRequestLinkType) {
+		if seen[pr.URL] {
+			return
+		}
+		seen[pr.URL] = true
+		pullRequests = append(pullRequests, &model.TaskPullRequest{
+			TaskID:       task.ID,
+			Number:       pr.Number,
+			Title:        pr.Title,
+			URL:          pr.URL,
+			State:        model.PullRequestState(pr.State),
+			HeadRef:      pr.HeadRef,
+			LinkType:     linkType,
+			MergedAt:     pr.MergedAt,
+			UpdatedAt:    pr.UpdatedAt,
+			ChecksStatus: model.ParseChecksState(pr.ChecksState),
+		})
+	}
+	for i := range links.Closing {
+		link(&links.Closing[i], model.PullRequestLinkCloses)
+	}
+	// デフォルトブランチ以外へのPull RequestはGitHubがクローズ対象として認識しないため、本文も確認する
+	for i := range links.Recent {
+		pr := &links.Recent[i]
+		switch {
+		case pr.ClosesIssue(number):
+			link(pr, model.PullRequestLinkCloses)
+		case pr.BranchReferencesIssue(number):
+			link(pr, model.PullRequestLinkBranch)
+		}
+	}
+
+	if err := u.taskPullRequestRepo.ReplaceForTask(ctx, task.ID, pullRequests); err != nil {
+		return nil, fmt.Errorf("failed to save task pull requests: %w", err)
+	}
+
+	// CIが失敗したまま完了にしないよう、ボードに表示するCIの状態をタスクに保存する
+	task.GithubChecksStatus = model.AggregateChecksStatus(pullRequests)
+	if err := u.taskRepo.Update(ctx, task); err != nil {
+		return nil, fmt.Errorf("failed to update task checks status: %w", err)
+	}
+
+	// Pull RequestのマージでIssueがクローズされた場合はタスクを完了にする
+	if links.IssueState == "closed" && task.Status != model.TaskStatusDone {
+		done := model.TaskStatusDone
+		if _, err := u.taskUsecase.patchTask(ctx, task.ID, &model.PatchTaskRequest{Status: &done}); err != nil {
+			if !errors.Is(err, model.ErrInvalidInput) && !errors.Is(err, model.ErrConflict) {
+				return nil, err
+			}
+			u.logger.WarnContext(ctx, "skipping task completion for closed github issue", "error", err, "task_id", task.ID)
+		}
+	}
+
+	u.logger.InfoContext(ctx, "task pull requests synced", "task_id", task.ID, "pull_requests", len(pullRequests), "issue_state", links.IssueState)
+	return pullRequests, nil
+}
@@ -0,0 +1,250 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sikigasa/github-task-controller/backend/internal/domain/model"
+	"github.com/sikigasa/github-task-controller/backend/internal/domain/repository"
+	"github.com/sikigasa/github-task-controller/backend/internal/infrastructure/github"
+)
+
+const (
+	// githubSyncQueueBatchSize はワーカーが一度に取得する変更の件数
+	githubSyncQueueBatchSize = 20
+	// githubSyncQueueLease は取得した変更を他のワーカーが取得しないようにする期間（再実行中に停止した場合はこの後に再実行される）
+	githubSyncQueueLease = 5 * time.Minute
+	// githubSyncQueueRetryBase は障害で保留した変更を最初に再実行するまでの待ち時間（以降は失敗するごとに倍にする）
+	githubSyncQueueRetryBase = 30 * time.Second
+	// githubSyncQueueErrorMaxLen は記録するエラーメッセージの最大長
+	githubSyncQueueErrorMaxLen = 1000
+	// githubSyncQueueWorker はWorkerMonitorに記録するワーカーの名前
+	githubSyncQueueWorker = "github_sync_queue"
+)
+
+// SyncTaskOrQueue はタスクをGitHub Projectに同期し、GitHubに障害がある（5xx・接続できない）場合は変更を保留する
+// タスクに保留中の変更がある場合は、同じタスクの変更の順序を保つため同期せずに保留する
+// 保留した場合はtrueを返す（保留した変更はGithubSyncQueueが接続が戻った後に再実行する）
+func (u *GithubUsecase) SyncTaskOrQueue(ctx context.Context, userID, taskID string) (bool, error) {
+	task, err := u.taskRepo.FindByID(ctx, taskID)
+	if err != nil {
+		return false, fmt.Errorf("failed to find task: %w", err)
+	}
+
+	project, err := u.projectRepo.FindByID(ctx, task.ProjectID)
+	if err != nil {
+		return false, fmt.Errorf("failed to find project: %w", err)
+	}
+	if project.UserID != userID {
+		return false, model.ErrForbidden
+	}
+	if !project.IsGithubLinked() {
+		return false, fmt.Errorf("project is not linked to github: %w", model.ErrConflict)
+	}
+
+	pending, err := u.syncOperationRepo.ExistsByTaskID(ctx, taskID)
+	if err != nil {
+		return false, fmt.Errorf("failed to check pending github operations: %w", err)
+	}
+	if !pending {
+		err = u.SyncTaskToGithub(ctx, userID, taskID)
+		if !errors.Is(err, github.ErrUnavailable) {
+			return false, err
+		}
+		u.logger.WarnContext(ctx, "github unavailable, queueing task sync", "error", err, "task_id", taskID)
+	}
+
+	if err := u.enqueueSync(ctx, userID, task, model.GithubSyncOperationSyncTask); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// enqueueSync はタスクのGitHubへの変更を保留する
+func (u *GithubUsecase) enqueueSync(ctx context.Context, userID string, task *model.Task, operationType model.GithubSyncOperationType) error {
+	now := time.Now()
+	operation := &model.GithubSyncOperation{
+		ID:            uuid.New().String(),
+		UserID:        userID,
+		ProjectID:     task.ProjectID,
+		TaskID:        task.ID,
+		Operation:     operationType,
+		NextAttemptAt: now.Add(githubSyncQueueRetryBase),
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+	if err := u.syncOperationRepo.Enqueue(ctx, operation); err != nil {
+		return fmt.Errorf("failed to queue github operation: %w", err)
+	}
+	return nil
+}
+
+// ListPendingOperations はユーザーの保留中のGitHubへの変更を作成順に取得する
+func (u *GithubUsecase) ListPendingOperations(ctx context.Context, userID string) ([]*model.GithubSyncOperation, error) {
+	operations, err := u.syncOperationRepo.FindByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find pending github operations: %w", err)
+	}
+	return operations, nil
+}
+
+// GithubSyncQueue はGitHubの障害のために保留した変更を再実行するワーカー
+// 同じタスクの変更は作成順に1件ずつ実行し、障害が続く間は間隔を倍にしながら再実行する
+// ユーザーの変更が1件でも成功した場合は接続が戻ったとみなし、そのユーザーの残りの変更をすぐに再実行する
+type GithubSyncQueue struct {
+	githubUsecase     *GithubUsecase
+	syncOperationRepo repository.GithubSyncOperationRepository
+	pollInterval      time.Duration
+	retryMax          time.Duration
+	wake              chan struct{}
+	workers           *WorkerMonitor
+	logger            *slog.Logger
+}
+
+// NewGithubSyncQueue は新しいGithubSyncQueueを作成する
+// pollIntervalは再実行の時刻を迎えた変更を確認する間隔、retryMaxは再実行までの待ち時間の上限
+// workersにはRunのワーカーの実行状況をgithub_sync_queueとして記録する
+func NewGithubSyncQueue(
+	githubUsecase *GithubUsecase,
+	syncOperationRepo repository.GithubSyncOperationRepository,
+	pollInterval time.Duration,
+	retryMax time.Duration,
+	workers *WorkerMonitor,
+	logger *slog.Logger,
+) *GithubSyncQueue {
+	workers.Register(githubSyncQueueWorker, pollInterval)
+	return &GithubSyncQueue{
+		githubUsecase:     githubUsecase,
+		syncOperationRepo: syncOperationRepo,
+		pollInterval:      pollInterval,
+		retryMax:          retryMax,
+		wake:              make(chan struct{}, 1),
+		workers:           workers,
+		logger:            logger,
+	}
+}
+
+// Run はctxがキャンセルされるまで再実行の時刻を迎えた変更を再実行する
+func (q *GithubSyncQueue) Run(ctx context.Context) {
+	ticker := time.NewTicker(q.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		q.workers.Beat(githubSyncQueueWorker, q.replayDue(ctx))
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-q.wake:
+		}
+	}
+}
+
+// notify はワーカーに再実行できる変更があることを知らせる（既に通知済みの場合は何もしない）
+func (q *GithubSyncQueue) notify() {
+	select {
+	case q.wake <- struct{}{}:
+	default:
+	}
+}
+
+// replayDue は再実行の時刻を迎えた変更がなくなるまで取得して再実行する
+// 変更を取得できなかった場合はエラーを返す（個々の変更の失敗は再実行に回すためエラーにしない）
+func (q *GithubSyncQueue) replayDue(ctx context.Context) error {
+	for ctx.Err() == nil {
+		operations, err := q.syncOperationRepo.ClaimDue(ctx, time.Now(), githubSyncQueueLease, githubSyncQueueBatchSize)
+		if err != nil {
+			q.logger.ErrorContext(ctx, "failed to claim github sync operations", "error", err)
+			return err
+		}
+
+		recovered := make(map[string]bool)
+		for _, operation := range operations {
+			if q.replay(ctx, operation) {
+				recovered[operation.UserID] = true
+			}
+		}
+		for userID := range recovered {
+			if err := q.syncOperationRepo.RetryByUserID(ctx, userID, time.Now()); err != nil {
+				q.logger.ErrorContext(ctx, "failed to retry github sync operations", "error", err, "user_id", userID)
+				continue
+			}
+			// 同じタスクの次の変更はこのバッチでは取得されないため、次の確認を待たずに再実行する
+			q.notify()
+		}
+		if len(operations) < githubSyncQueueBatchSize {
+			return nil
+		}
+	}
+	return nil
+}
+
+// replay は変更を再実行し、結果を記録する（GitHubへの変更が成功した場合はtrueを返す）
+// GitHubの障害・同期中のロック・再認証待ち・同期の上限の場合は再実行に回し、それ以外で失敗した変更は削除する
+func (q *GithubSyncQueue) replay(ctx context.Context, operation *model.GithubSyncOperation) bool {
+	err := q.execute(ctx, operation)
+	var quotaErr *model.GithubSyncQuotaError
+	switch {
+	case err == nil:
+		q.logger.InfoContext(ctx, "queued github operation replayed", "operation_id", operation.ID, "task_id", operation.TaskID, "attempts", operation.Attempts+1)
+	case errors.As(err, &quotaErr):
+		// ユーザーの同期の上限に達した場合は上限が戻る時刻に再実行する
+		q.markFailed(ctx, operation, err, quotaErr.ResetAt)
+		return false
+	case errors.Is(err, github.ErrUnavailable), errors.Is(err, errLocked), errors.Is(err, model.ErrGithubReauthRequired):
+		q.markFailed(ctx, operation, err, time.Now().Add(q.retryDelay(operation.Attempts+1)))
+		return false
+	case errors.Is(err, model.ErrNotFound):
+		// タスク・プロジェクトが削除された場合は反映する変更がない
+		q.logger.InfoContext(ctx, "queued github operation discarded", "reason", err, "operation_id", operation.ID, "task_id", operation.TaskID)
+	default:
+		// 連携の解除など再実行しても成功しない変更は諦める（タスクは手動の同期で反映できる）
+		q.logger.WarnContext(ctx, "queued github operation dropped", "error", err, "operation_id", operation.ID, "task_id", operation.TaskID)
+	}
+
+	if err := q.syncOperationRepo.Delete(ctx, operation.ID); err != nil {
+		// 削除に失敗した場合はleaseの後に再実行される（同期は実行時点の内容を反映するため重複しても問題ない）
+		q.logger.ErrorContext(ctx, "failed to delete github sync operation", "error", err, "operation_id", operation.ID)
+	}
+	return err == nil
+}
+
+// execute は変更の種類に応じてGitHubへの変更を実行する
+func (q *GithubSyncQueue) execute(ctx context.Context, operation *model.GithubSyncOperation) error {
+	switch operation.Operation {
+	case model.GithubSyncOperationSyncTask:
+		return q.githubUsecase.SyncTaskToGithub(ctx, operation.UserID, operation.TaskID)
+	}
+	return fmt.Errorf("unknown github sync operation %q: %w", operation.Operation, model.ErrInvalidInput)
+}
+
+// markFailed は再実行の失敗を記録し、次に再実行する時刻をnextAttemptAtにする
+func (q *GithubSyncQueue) markFailed(ctx context.Context, operation *model.GithubSyncOperation, cause error, nextAttemptAt time.Time) {
+	attempts := operation.Attempts + 1
+
+	message := cause.Error()
+	if runes := []rune(message); len(runes) > githubSyncQueueErrorMaxLen {
+		message = string(runes[:githubSyncQueueErrorMaxLen])
+	}
+
+	q.logger.WarnContext(ctx, "queued github operation failed, will retry",
+		"error", cause, "operation_id", operation.ID, "task_id", operation.TaskID, "attempts", attempts, "next_attempt_at", nextAttemptAt)
+
+	if err := q.syncOperationRepo.MarkFailed(ctx, operation.ID, attempts, message, nextAttemptAt); err != nil {
+		q.logger.ErrorContext(ctx, "failed to mark github sync operation failed", "error", err, "operation_id", operation.ID)
+	}
+}
+
+// retryDelay はattempts回目の失敗の後に再実行するまでの待ち時間を返す
+func (q *GithubSyncQueue) retryDelay(attempts int) time.Duration {
+	delay := githubSyncQueueRetryBase
+	for i := 1; i < attempts && delay < q.retryMax; i++ {
+		delay *= 2
+	}
+	return min(delay, q.retryMax)
+}
@@ -0,0 +1,49 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/sikigasa/github-task-controller/backend/internal/domain/model"
+)
+
+// githubSyncQuotaWindow はGitHubとの同期の回数を数える期間（毎時0分で区切る）
+const githubSyncQuotaWindow = time.Hour
+
+// consumeSyncQuota はユーザーのGitHubとの同期の回数を1回数え、1時間あたりの上限に達している場合はGithubSyncQuotaErrorを返す
+// 共有のインスタンスで1人のユーザーがGitHub APIの利用上限を使い切らないよう、GitHubを呼び出す同期の前に呼び出す
+func (u *GithubUsecase) consumeSyncQuota(ctx context.Context, userID string) error {
+	windowStart := time.Now().Truncate(githubSyncQuotaWindow)
+	_, ok, err := u.syncUsageRepo.Consume(ctx, userID, windowStart, u.syncQuotaPerHour)
+	if err != nil {
+		return fmt.Errorf("failed to consume github sync quota: %w", err)
+	}
+	if !ok {
+		u.logger.WarnContext(ctx, "github sync quota exceeded", "user_id", userID, "limit", u.syncQuotaPerHour)
+		return &model.GithubSyncQuotaError{Limit: u.syncQuotaPerHour, ResetAt: windowStart.Add(githubSyncQuotaWindow)}
+	}
+	return nil
+}
+
+// GetSyncUsage はユーザーの現在の1時間のGitHubとの同期の回数と上限を取得する
+func (u *GithubUsecase) GetSyncUsage(ctx context.Context, userID string) (*model.GithubSyncUsage, error) {
+	windowStart := time.Now().Truncate(githubSyncQuotaWindow)
+	used, err := u.syncUsageRepo.FindCount(ctx, userID, windowStart)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find github sync usage: %w", err)
+	}
+
+	usage := &model.GithubSyncUsage{
+		Used:        used,
+		WindowStart: windowStart,
+		ResetAt:     windowStart.Add(githubSyncQuotaWindow),
+	}
+	if u.syncQuotaPerHour > 0 {
+		limit := u.syncQuotaPerHour
+		remaining := max(limit-used, 0)
+		usage.Limit = &limit
+		usage.Remaining = &remaining
+	}
+	return usage, nil
+}
@@ -0,0 +1,198 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/sikigasa/github-task-controller/backend/internal/domain/model"
+	"github.com/sikigasa/github-task-controller/backend/internal/domain/repository"
+)
+
+const (
+	// githubSyncTick は再同期の予定時刻を迎えたタスクを確認する間隔
+	githubSyncTick = time.Second
+	// githubSyncWorker はWorkerMonitorに記録するワーカーの名前
+	githubSyncWorker = "github_sync"
+)
+
+// GithubSyncScheduler はGitHub Projectに同期済みのタスクが変更された時に再同期を予約する
+// 連続した変更はdelayの間まとめてから1回だけ同期する。未同期のタスクはGitHubに追加しない（手動の同期で追加する）
+// GitHubに障害がある場合の同期は保留し、GithubSyncQueueが接続が戻った後に再実行する
+// 予約は保持しないため、再同期の前にサーバーが停止した場合は次の変更か手動の同期まで反映されない
+type GithubSyncScheduler struct {
+	githubUsecase *GithubUsecase
+	taskRepo      repository.TaskRepository
+	projectRepo   repository.ProjectRepository
+	delay         time.Duration
+	mu            sync.Mutex
+	// pending はタスクIDごとの再同期の予定時刻
+	pending map[string]time.Time
+	workers *WorkerMonitor
+	logger  *slog.Logger
+}
+
+// NewGithubSyncScheduler は新しいGithubSyncSchedulerを作成する
+// workersにはRunのワーカーの実行状況をgithub_syncとして記録する
+func NewGithubSyncScheduler(
+	githubUsecase *GithubUsecase,
+	taskRepo repository.TaskRepository,
+	projectRepo repository.ProjectRepository,
+	delay time.Duration,
+	workers *WorkerMonitor,
+	logger *slog.Logger,
+) *GithubSyncScheduler {
+	workers.Register(githubSyncWorker, githubSyncTick)
+	return &GithubSyncScheduler{
+		githubUsecase: githubUsecase,
+		taskRepo:      taskRepo,
+		projectRepo:   projectRepo,
+		delay:         delay,
+		pending:       make(map[string]time.Time),
+		workers:       workers,
+		logger:        logger,
+	}
+}
+
+// Schedule はタスクの変更のイベント（task.updated・task.status_changed）を受け取り、同期済みのタスクの再同期を予約するイベントバスの購読者
+func (s *GithubSyncScheduler) Schedule(ctx context.Context, event *model.OutboxEvent) error {
+	task, err := eventTask(event)
+	if err != nil {
+		return err
+	}
+	if task == nil || task.GithubItemID == nil || task.IsGithubOrphaned() {
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.pending[task.ID] = time.Now().Add(s.delay)
+	return nil
+}
+
+// Run はctxがキャンセルされるまで予定時刻を迎えたタスクを再同期する
+func (s *GithubSyncScheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(githubSyncTick)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.workers.Beat(githubSyncWorker, s.syncDue(ctx))
+		}
+	}
+}
+
+// syncDue は予定時刻を迎えたタスクを再同期する（失敗したタスクがあった場合はエラーを返す）
+func (s *GithubSyncScheduler) syncDue(ctx context.Context) error {
+	now := time.Now()
+	var due []string
+
+	s.mu.Lock()
+	for taskID, at := range s.pending {
+		if !at.After(now) {
+			due = append(due, taskID)
+			delete(s.pending, taskID)
+		}
+	}
+	s.mu.Unlock()
+
+	var errs []error
+	for _, taskID := range due {
+		if ctx.Err() != nil {
+			break
+		}
+		err := s.sync(ctx, taskID)
+		var quotaErr *model.GithubSyncQuotaError
+		if errors.As(err, &quotaErr) {
+			// ユーザーの同期の上限に達した場合は上限が戻る時刻に改めて同期する
+			s.logger.InfoContext(ctx, "github resync postponed", "reason", err, "task_id", taskID, "until", quotaErr.ResetAt)
+			s.mu.Lock()
+			if _, ok := s.pending[taskID]; !ok {
+				s.pending[taskID] = quotaErr.ResetAt
+			}
+			s.mu.Unlock()
+			continue
+		}
+		if errors.Is(err, errLocked) {
+			// 他のインスタンスがプロジェクトを同期中の場合は、その同期の後に改めて同期する
+			s.logger.InfoContext(ctx, "github resync postponed", "reason", err, "task_id", taskID)
+			s.mu.Lock()
+			if _, ok := s.pending[taskID]; !ok {
+				s.pending[taskID] = time.Now().Add(s.delay)
+			}
+			s.mu.Unlock()
+			continue
+		}
+		if err != nil {
+			s.logger.WarnContext(ctx, "failed to resync task to github", "error", err, "task_id", taskID)
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// sync はタスクをプロジェクトの所有者としてGitHub Projectに同期する
+// タスク・プロジェクトが削除されたか、連携が解除されたか、GitHubと一致させた後に変更されていない場合は何もしない
+func (s *GithubSyncScheduler) sync(ctx context.Context, taskID string) error {
+	task, err := s.taskRepo.FindByID(ctx, taskID)
+	if errors.Is(err, model.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to find task: %w", err)
+	}
+
+	project, err := s.projectRepo.FindByID(ctx, task.ProjectID)
+	if errors.Is(err, model.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to find project: %w", err)
+	}
+	if !project.IsGithubLinked() {
+		return nil
+	}
+	// GitHubから取り込んだ変更は、同じ内容をGitHubに反映し直さない
+	synced, err := s.githubUsecase.isTaskSynced(ctx, task)
+	if err != nil {
+		return fmt.Errorf("failed to check github task sync: %w", err)
+	}
+	if synced {
+		return nil
+	}
+
+	queued, err := s.githubUsecase.SyncTaskOrQueue(ctx, project.UserID, taskID)
+	if err != nil {
+		return err
+	}
+	if queued {
+		s.logger.InfoContext(ctx, "github resync queued until github is available", "task_id", taskID)
+	}
+	return nil
+}
+
+// eventTask はタスクのイベントのペイロードからタスクを取り出す（タスクを含まないイベントはnil）
+func eventTask(event *model.OutboxEvent) (*model.Task, error) {
+	switch event.Type {
+	case model.EventTaskCreated, model.EventTaskUpdated:
+		var task model.Task
+		if err := json.Unmarshal(event.Payload, &task); err != nil {
+			return nil, fmt.Errorf("failed to decode %s payload: %w", event.Type, err)
+		}
+		return &task, nil
+	case model.EventTaskStatusChanged:
+		var payload model.TaskStatusChangedPayload
+		if err := json.Unmarshal(event.Payload, &payload); err != nil {
+			return nil, fmt.Errorf("failed to decode %s payload: %w", event.Type, err)
+		}
+		return payload.Task, nil
+	}
+	return nil, nil
+}
@@ -0,0 +1,227 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/sikigasa/github-task-controller/backend/internal/domain/model"
+	"github.com/sikigasa/github-task-controller/backend/internal/infrastructure/github"
+)
+
+// webhookIssue はissues・issue_commentイベントのペイロードのIssue
+type webhookIssue struct {
+	Number    int       `json:"number"`
+	Title     string    `json:"title"`
+	Body      *string   `json:"body"`
+	State     string    `json:"state"`
+	HTMLURL   string    `json:"html_url"`
+	UpdatedAt time.Time `json:"updated_at"`
+	// PullRequest はPull Requestの場合のみ含まれる（Pull Requestへのコメントもissue_commentで届く）
+	PullRequest json.RawMessage `json:"pull_request"`
+}
+
+// issue はペイロードのIssueをGitHub APIのIssueと同じ形で返す
+func (i *webhookIssue) issue() *github.Issue {
+	issue := &github.Issue{
+		Number:    i.Number,
+		Title:     i.Title,
+		URL:       i.HTMLURL,
+		State:     i.State,
+		UpdatedAt: i.UpdatedAt,
+	}
+	if i.Body != nil {
+		issue.Body = *i.Body
+	}
+	return issue
+}
+
+// HandleIssueWebhook はissuesイベントを、Issueに連携しているタスクに反映する
+// 削除されたIssueは連携先の削除として処理し、編集・クローズ・再オープンされたIssueはタスクのタイトル・説明・ステータスに反映する
+func (u *GithubUsecase) HandleIssueWebhook(ctx context.Context, delivery *model.WebhookDelivery) error {
+	var payload struct {
+		Action string       `json:"action"`
+		Issue  webhookIssue `json:"issue"`
+	}
+	if err := json.Unmarshal(delivery.Payload, &payload); err != nil {
+		return fmt.Errorf("failed to decode issues payload: %w", err)
+	}
+	if payload.Issue.HTMLURL == "" {
+		return nil
+	}
+
+	switch payload.Action {
+	case "deleted":
+		return u.handleDeletedLink(ctx, "", payload.Issue.HTMLURL)
+	case "edited", "closed", "reopened":
+		return u.refreshIssueTasks(ctx, &payload.Issue)
+	}
+	return nil
+}
+
+// HandleIssueCommentWebhook はissue_commentイベントを、コメントされたIssueに連携しているタスクに反映する
+// タスクはコメントを持たないため、ペイロードに含まれるIssueの最新のタイトル・説明・状態を反映する
+func (u *GithubUsecase) HandleIssueCommentWebhook(ctx context.Context, delivery *model.WebhookDelivery) error {
+	var payload struct {
+		Action string       `json:"action"`
+		Issue  webhookIssue `json:"issue"`
+	}
+	if err := json.Unmarshal(delivery.Payload, &payload); err != nil {
+		return fmt.Errorf("failed to decode issue_comment payload: %w", err)
+	}
+	if payload.Issue.HTMLURL == "" || payload.Issue.PullRequest != nil {
+		return nil
+	}
+
+	return u.refreshIssueTasks(ctx, &payload.Issue)
+}
+
+// HandleProjectItemWebhook はprojects_v2_itemイベントを、Itemに同期しているタスクに反映する
+// 削除されたItemは連携先の削除として処理し、編集・復元・変換されたItemは双方向の同期と同じ規則でタスクと一致させる
+func (u *GithubUsecase) HandleProjectItemWebhook(ctx context.Context, delivery *model.WebhookDelivery) error {
+	var payload struct {
+		Action string `json:"action"`
+		Item   struct {
+			NodeID string `json:"node_id"`
+		} `json:"projects_v2_item"`
+	}
+	if err := json.Unmarshal(delivery.Payload, &payload); err != nil {
+		return fmt.Errorf("failed to decode projects_v2_item payload: %w", err)
+	}
+	if payload.Item.NodeID == "" {
+		return nil
+	}
+
+	switch payload.Action {
+	case "deleted":
+		return u.handleDeletedLink(ctx, payload.Item.NodeID, "")
+	case "edited", "restored", "converted":
+		tasks, err := u.taskRepo.FindByGithubLink(ctx, payload.Item.NodeID, "")
+		if err != nil {
+			return err
+		}
+		for _, task := range tasks {
+			if err := u.syncWebhookTask(ctx, task); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// refreshIssueTasks はIssueに連携しているタスクにIssueの変更を反映する
+// Itemに同期しているタスクはItemと一致させ、Issueだけに連携しているタスクはペイロードのIssueを反映する
+// ペイロードのIssueより後にタスクが更新されていた場合は、更新日時の新しいタスクを優先して反映しない（再試行で古い配信が後から届いた場合も含む）
+func (u *GithubUsecase) refreshIssueTasks(ctx context.Context, issue *webhookIssue) error {
+	tasks, err := u.taskRepo.FindByGithubLink(ctx, "", issue.HTMLURL)
+	if err != nil {
+		return err
+	}
+
+	for _, task := range tasks {
+		if task.IsGithubOrphaned() {
+			continue
+		}
+		if task.GithubItemID != nil {
+			err = u.syncWebhookTask(ctx, task)
+		} else if issue.UpdatedAt.After(task.UpdatedAt) {
+			_, err = u.updateIssueTask(ctx, task, issue.issue())
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// syncWebhookTask はWebhookで変更を知らされたタスクを、同期済みのItemと双方向の同期と同じ規則で一致させる
+// 同期の上限に達した場合や再認証が必要な場合は、再試行しても反映できないため定期の同期に任せる
+func (u *GithubUsecase) syncWebhookTask(ctx context.Context, task *model.Task) error {
+	project, err := u.projectRepo.FindByID(ctx, task.ProjectID)
+	if errors.Is(err, model.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to find project: %w", err)
+	}
+	if !project.IsGithubLinked() {
+		return nil
+	}
+
+	err = u.syncWebhookItem(ctx, project, task.ID)
+	switch {
+	case errors.Is(err, model.ErrRateLimited), errors.Is(err, model.ErrGithubReauthRequired):
+		u.logger.InfoContext(ctx, "github webhook sync deferred", "reason", err, "task_id", task.ID)
+		return nil
+	case errors.Is(err, model.ErrConflict) && !errors.Is(err, errLocked):
+		// Itemが削除されていた場合はプロジェクトの設定に従って連携切れにしている
+		u.logger.WarnContext(ctx, "skipping github webhook sync", "error", err, "task_id", task.ID)
+		return nil
+	}
+	return err
+}
+
+// syncWebhookItem はプロジェクトの同期と排他してタスクとItemを一致させる
+func (u *GithubUsecase) syncWebhookItem(ctx context.Context, project *model.Project, taskID string) error {
+	if err := u.consumeSyncQuota(ctx, project.UserID); err != nil {
+		return err
+	}
+
+	ctx, unlock, err := u.lockProjectSync(ctx, project.ID)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	// ロックを待つ間に他のインスタンスが同期した場合に備えて、タスクを読み直す
+	task, err := u.taskRepo.FindByID(ctx, taskID)
+	if errors.Is(err, model.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to find task: %w", err)
+	}
+	if task.GithubItemID == nil || task.IsGithubOrphaned() {
+		return nil
+	}
+
+	token, err := u.GetToken(ctx, project.UserID)
+	if err != nil {
+		return err
+	}
+	ctx = github.WithUser(ctx, project.UserID)
+
+	mapping, err := u.fieldMapping(ctx, project.ID)
+	if err != nil {
+		return fmt.Errorf("failed to load github field mapping: %w", err)
+	}
+	priorityField := ""
+	if mapping.PriorityField != nil {
+		priorityField = *mapping.PriorityField
+	}
+
+	item, err := u.githubService.GetProjectItem(ctx, token, *task.GithubItemID, mapping.StatusField, priorityField)
+	if errors.Is(err, github.ErrNotFound) {
+		// Itemの削除はprojects_v2_itemのdeletedイベントで処理する
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get github project item: %w", err)
+	}
+
+	baseline, err := u.taskSyncRepo.FindByTaskID(ctx, task.ID)
+	if errors.Is(err, model.ErrNotFound) {
+		baseline = nil
+	} else if err != nil {
+		return fmt.Errorf("failed to find github task sync: %w", err)
+	}
+
+	projectGithubID, err := u.githubService.GetProjectID(ctx, token, githubProjectOwner(project), *project.GithubProjectNumber)
+	if err != nil {
+		return fmt.Errorf("failed to get github project id: %w", err)
+	}
+
+	_, _, _, err = u.syncTaskItem(ctx, token, project, projectGithubID, mapping, task, item, baseline)
+	return err
+}
@@ -0,0 +1,229 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sikigasa/github-task-controller/backend/internal/domain/model"
+	"github.com/sikigasa/github-task-controller/backend/internal/domain/repository"
+)
+
+// GoalUsecase は目標（OKR）に関するユースケース
+type GoalUsecase struct {
+	goalRepo    repository.GoalRepository
+	projectRepo repository.ProjectRepository
+	taskRepo    repository.TaskRepository
+	logger      *slog.Logger
+}
+
+// NewGoalUsecase は新しいGoalUsecaseを作成する
+func NewGoalUsecase(goalRepo repository.GoalRepository, projectRepo repository.ProjectRepository, taskRepo repository.TaskRepository, logger *slog.Logger) *GoalUsecase {
+	return &GoalUsecase{
+		goalRepo:    goalRepo,
+		projectRepo: projectRepo,
+		taskRepo:    taskRepo,
+		logger:      logger,
+	}
+}
+
+// CreateGoal はプロジェクトに新しい目標を作成する
+func (u *GoalUsecase) CreateGoal(ctx context.Context, userID, projectID string, req *model.CreateGoalRequest) (*model.GoalWithProgress, error) {
+	if err := model.ValidateQuarter(req.Quarter); err != nil {
+		return nil, err
+	}
+	if err := u.authorizeProject(ctx, userID, projectID); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	goal := &model.Goal{
+		ID:          uuid.New().String(),
+		ProjectID:   projectID,
+		Title:       req.Title,
+		Description: req.Description,
+		Quarter:     req.Quarter,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+
+	if err := u.goalRepo.Create(ctx, goal); err != nil {
+		u.logger.ErrorContext(ctx, "failed to create goal", "error", err)
+		return nil, fmt.Errorf("failed to create goal: %w", err)
+	}
+
+	u.logger.InfoContext(ctx, "goal created", "goal_id", goal.ID, "project_id", projectID)
+	return &model.GoalWithProgress{Goal: goal, TaskIDs: []string{}}, nil
+}
+
+// ListGoals はプロジェクトの目標を進捗付きで取得する（quarterが空の場合は全四半期）
+func (u *GoalUsecase) ListGoals(ctx context.Context, userID, projectID, quarter string) ([]*model.GoalWithProgress, error) {
+	if quarter != "" {
+		if err := model.ValidateQuarter(quarter); err != nil {
+			return nil, err
+		}
+	}
+	if err := u.authorizeProject(ctx, userID, projectID); err != nil {
+		return nil, err
+	}
+
+	goals, err := u.goalRepo.FindByProjectID(ctx, projectID, quarter)
+	if err != nil {
+		u.logger.ErrorContext(ctx, "failed to list goals", "error", err, "project_id", projectID)
+		return nil, fmt.Errorf("failed to list goals: %w", err)
+	}
+
+	return u.attachProgress(ctx, goals)
+}
+
+// GetGoal は進捗と貢献タスクのID付きで目標を取得する
+func (u *GoalUsecase) GetGoal(ctx context.Context, userID, id string) (*model.GoalWithProgress, error) {
+	goal, err := u.findAuthorized(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return u.detail(ctx, goal)
+}
+
+// UpdateGoal は目標情報を更新する
+func (u *GoalUsecase) UpdateGoal(ctx context.Context, userID, id string, req *model.UpdateGoalRequest) (*model.GoalWithProgress, error) {
+	if err := model.ValidateQuarter(req.Quarter); err != nil {
+		return nil, err
+	}
+	goal, err := u.findAuthorized(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	goal.Title = req.Title
+	goal.Description = req.Description
+	goal.Quarter = req.Quarter
+	goal.UpdatedAt = time.Now()
+
+	if err := u.goalRepo.Update(ctx, goal); err != nil {
+		u.logger.ErrorContext(ctx, "failed to update goal", "error", err, "goal_id", id)
+		return nil, fmt.Errorf("failed to update goal: %w", err)
+	}
+
+	u.logger.InfoContext(ctx, "goal updated", "goal_id", id)
+	return u.detail(ctx, goal)
+}
+
+// DeleteGoal は目標を削除する（貢献タスク自体は削除しない）
+func (u *GoalUsecase) DeleteGoal(ctx context.Context, userID, id string) error {
+	if _, err := u.findAuthorized(ctx, userID, id); err != nil {
+		return err
+	}
+
+	if err := u.goalRepo.Delete(ctx, id); err != nil {
+		u.logger.ErrorContext(ctx, "failed to delete goal", "error", err, "goal_id", id)
+		return fmt.Errorf("failed to delete goal: %w", err)
+	}
+
+	u.logger.InfoContext(ctx, "goal deleted", "goal_id", id)
+	return nil
+}
+
+// AddTask は目標に貢献タスクを紐づける
+// 貢献タスクは目標と同じプロジェクトのタスクに限る
+func (u *GoalUsecase) AddTask(ctx context.Context, userID, goalID, taskID string) (*model.GoalWithProgress, error) {
+	goal, err := u.findAuthorized(ctx, userID, goalID)
+	if err != nil {
+		return nil, err
+	}
+
+	task, err := u.taskRepo.FindByID(ctx, taskID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find task: %w", err)
+	}
+	if task.ProjectID != goal.ProjectID {
+		return nil, fmt.Errorf("task must be in the same project as the goal: %w", model.ErrInvalidInput)
+	}
+
+	if err := u.goalRepo.AddTask(ctx, goalID, taskID); err != nil {
+		u.logger.ErrorContext(ctx, "failed to add goal task", "error", err, "goal_id", goalID)
+		return nil, fmt.Errorf("failed to add goal task: %w", err)
+	}
+
+	u.logger.InfoContext(ctx, "goal task added", "goal_id", goalID, "task_id", taskID)
+	return u.detail(ctx, goal)
+}
+
+// RemoveTask は目標から貢献タスクの紐づけを解除する
+func (u *GoalUsecase) RemoveTask(ctx context.Context, userID, goalID, taskID string) error {
+	if _, err := u.findAuthorized(ctx, userID, goalID); err != nil {
+		return err
+	}
+
+	if err := u.goalRepo.RemoveTask(ctx, goalID, taskID); err != nil {
+		u.logger.ErrorContext(ctx, "failed to remove goal task", "error", err, "goal_id", goalID)
+		return fmt.Errorf("failed to remove goal task: %w", err)
+	}
+
+	u.logger.InfoContext(ctx, "goal task removed", "goal_id", goalID, "task_id", taskID)
+	return nil
+}
+
+// authorizeProject はプロジェクトの所有者であることを確認する
+func (u *GoalUsecase) authorizeProject(ctx context.Context, userID, projectID string) error {
+	project, err := u.projectRepo.FindByID(ctx, projectID)
+	if err != nil {
+		return fmt.Errorf("failed to find project: %w", err)
+	}
+	if project.UserID != userID {
+		return model.ErrForbidden
+	}
+	return nil
+}
+
+// findAuthorized は目標を取得し、所属プロジェクトの所有者であることを確認する
+func (u *GoalUsecase) findAuthorized(ctx context.Context, userID, id string) (*model.Goal, error) {
+	goal, err := u.goalRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find goal: %w", err)
+	}
+	if err := u.authorizeProject(ctx, userID, goal.ProjectID); err != nil {
+		return nil, err
+	}
+	return goal, nil
+}
+
+// detail は目標に進捗と貢献タスクのIDを付与する
+func (u *GoalUsecase) detail(ctx context.Context, goal *model.Goal) (*model.GoalWithProgress, error) {
+	withProgress, err := u.attachProgress(ctx, []*model.Goal{goal})
+	if err != nil {
+		return nil, err
+	}
+
+	taskIDs, err := u.goalRepo.FindTaskIDs(ctx, goal.ID)
+	if err != nil {
+		u.logger.ErrorContext(ctx, "failed to get goal tasks", "error", err, "goal_id", goal.ID)
+		return nil, fmt.Errorf("failed to get goal tasks: %w", err)
+	}
+	withProgress[0].TaskIDs = taskIDs
+
+	return withProgress[0], nil
+}
+
+// attachProgress は目標の進捗をまとめて取得して付与する
+func (u *GoalUsecase) attachProgress(ctx context.Context, goals []*model.Goal) ([]*model.GoalWithProgress, error) {
+	ids := make([]string, 0, len(goals))
+	for _, g := range goals {
+		ids = append(ids, g.ID)
+	}
+
+	progress, err := u.goalRepo.ProgressByIDs(ctx, ids)
+	if err != nil {
+		u.logger.ErrorContext(ctx, "failed to get goal progress", "error", err)
+		return nil, fmt.Errorf("failed to get goal progress: %w", err)
+	}
+
+	result := make([]*model.GoalWithProgress, 0, len(goals))
+	for _, g := range goals {
+		result = append(result, &model.GoalWithProgress{Goal: g, Progress: progress[g.ID]})
+	}
+	return result, nil
+}
@@ -0,0 +1,205 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sikigasa/github-task-controller/backend/internal/domain/model"
+	"github.com/sikigasa/github-task-controller/backend/internal/domain/repository"
+	"github.com/sikigasa/github-task-controller/backend/internal/infrastructure/mail"
+)
+
+// SignupPolicy は新規登録の制限
+type SignupPolicy struct {
+	// AllowedDomains は新規登録を許可するメールアドレスのドメイン（空の場合は制限しない）
+	AllowedDomains []string
+	// InviteOnly は新規登録に有効な招待を必須にするかどうか
+	InviteOnly bool
+	// InvitationTTL は招待の有効期間
+	InvitationTTL time.Duration
+}
+
+// InvitationUsecase は新規登録の制限と招待に関するユースケース
+// 既存ユーザーのログインは制限せず、ユーザーを新規作成する場合にのみ適用する
+type InvitationUsecase struct {
+	invitationRepo repository.InvitationRepository
+	userRepo       repository.UserRepository
+	sender         mail.Sender
+	policy         SignupPolicy
+	frontendURL    string
+	logger         *slog.Logger
+}
+
+// NewInvitationUsecase は新しいInvitationUsecaseを作成する
+// frontendURLは招待メールに記載するログインページの組み立てに使う
+func NewInvitationUsecase(
+	invitationRepo repository.InvitationRepository,
+	userRepo repository.UserRepository,
+	sender mail.Sender,
+	policy SignupPolicy,
+	frontendURL string,
+	logger *slog.Logger,
+) *InvitationUsecase {
+	domains := make([]string, 0, len(policy.AllowedDomains))
+	for _, d := range policy.AllowedDomains {
+		if d = strings.ToLower(strings.TrimPrefix(strings.TrimSpace(d), "@")); d != "" {
+			domains = append(domains, d)
+		}
+	}
+	policy.AllowedDomains = domains
+
+	return &InvitationUsecase{
+		invitationRepo: invitationRepo,
+		userRepo:       userRepo,
+		sender:         sender,
+		policy:         policy,
+		frontendURL:    frontendURL,
+		logger:         logger,
+	}
+}
+
+// normalizeEmail は比較用にメールアドレスを正規化する
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+// domainAllowed はメールアドレスのドメインが新規登録を許可されているかどうかを返す
+func (u *InvitationUsecase) domainAllowed(email string) bool {
+	if len(u.policy.AllowedDomains) == 0 {
+		return true
+	}
+	at := strings.LastIndex(email, "@")
+	if at < 0 {
+		return false
+	}
+	domain := email[at+1:]
+	for _, d := range u.policy.AllowedDomains {
+		if domain == d {
+			return true
+		}
+	}
+	return false
+}
+
+// AuthorizeSignup はメールアドレスでの新規登録が許可されているかを検証する
+// 許可されていないドメインの場合はErrEmailDomainNotAllowed、招待制で有効な招待がない場合はErrInvitationRequiredを返す
+func (u *InvitationUsecase) AuthorizeSignup(ctx context.Context, email string) error {
+	email = normalizeEmail(email)
+	if !u.domainAllowed(email) {
+		u.logger.WarnContext(ctx, "signup rejected by email domain")
+		return model.ErrEmailDomainNotAllowed
+	}
+	if !u.policy.InviteOnly {
+		return nil
+	}
+
+	invitation, err := u.invitationRepo.FindByEmail(ctx, email)
+	if errors.Is(err, model.ErrNotFound) {
+		u.logger.WarnContext(ctx, "signup rejected without invitation")
+		return model.ErrInvitationRequired
+	}
+	if err != nil {
+		return fmt.Errorf("failed to find invitation: %w", err)
+	}
+	if !invitation.Pending(time.Now()) {
+		u.logger.WarnContext(ctx, "signup rejected with used or expired invitation", "invitation_id", invitation.ID)
+		return model.ErrInvitationRequired
+	}
+	return nil
+}
+
+// AcceptInvitation は新規登録したメールアドレス宛ての招待を使用済みにする（招待がない場合は何もしない）
+func (u *InvitationUsecase) AcceptInvitation(ctx context.Context, email string) error {
+	invitation, err := u.invitationRepo.FindByEmail(ctx, normalizeEmail(email))
+	if errors.Is(err, model.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to find invitation: %w", err)
+	}
+	if err := u.invitationRepo.MarkAccepted(ctx, invitation.ID, time.Now()); err != nil {
+		return fmt.Errorf("failed to accept invitation: %w", err)
+	}
+	return nil
+}
+
+// CreateInvitation はメールアドレス宛ての招待を作成して招待メールを送信する
+// 同じメールアドレスの招待がある場合は有効期限を延ばして再送する
+func (u *InvitationUsecase) CreateInvitation(ctx context.Context, userID string, req *model.CreateInvitationRequest) (*model.Invitation, error) {
+	inviter, err := u.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find user: %w", err)
+	}
+	// ゲストユーザーが招待を作れると招待制を迂回できるため禁止する
+	if inviter.IsGuest() {
+		return nil, fmt.Errorf("guest users cannot invite: %w", model.ErrForbidden)
+	}
+
+	email := normalizeEmail(req.Email)
+	if !u.domainAllowed(email) {
+		return nil, fmt.Errorf("cannot invite %s: %w", email, model.ErrEmailDomainNotAllowed)
+	}
+	if _, err := u.userRepo.FindByEmail(ctx, email); err == nil {
+		return nil, fmt.Errorf("user already exists: %w", model.ErrConflict)
+	} else if !errors.Is(err, repository.ErrUserNotFound) {
+		return nil, fmt.Errorf("failed to find user: %w", err)
+	}
+
+	now := time.Now()
+	invitation := &model.Invitation{
+		ID:        uuid.New().String(),
+		Email:     email,
+		InvitedBy: userID,
+		ExpiresAt: now.Add(u.policy.InvitationTTL),
+		CreatedAt: now,
+	}
+	if err := u.invitationRepo.Upsert(ctx, invitation); err != nil {
+		return nil, fmt.Errorf("failed to save invitation: %w", err)
+	}
+
+	msg := mail.Message{
+		To:      email,
+		Subject: "招待が届いています",
+		Body: fmt.Sprintf("%s さんから招待が届きました。以下のページからこのメールアドレスのアカウントでログインして登録してください（%s まで有効）。\n\n%s/login\n",
+			inviter.Name, invitation.ExpiresAt.Format("2006-01-02 15:04"), u.frontendURL),
+	}
+	// 送信に失敗しても招待自体は有効なため、ログに記録するのみ
+	if err := u.sender.Send(ctx, msg); err != nil {
+		u.logger.ErrorContext(ctx, "failed to send invitation", "error", err, "invitation_id", invitation.ID)
+	}
+
+	u.logger.InfoContext(ctx, "invitation created", "invitation_id", invitation.ID, "user_id", userID)
+	return invitation, nil
+}
+
+// ListInvitations はユーザーが作成した招待を一覧する
+func (u *InvitationUsecase) ListInvitations(ctx context.Context, userID string) ([]*model.Invitation, error) {
+	invitations, err := u.invitationRepo.FindByInviter(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find invitations: %w", err)
+	}
+	return invitations, nil
+}
+
+// DeleteInvitation はユーザーが作成した招待を取り消す
+func (u *InvitationUsecase) DeleteInvitation(ctx context.Context, userID, id string) error {
+	if err := validateResourceID(id); err != nil {
+		return err
+	}
+	invitation, err := u.invitationRepo.FindByID(ctx, id)
+	if err != nil {
+		return fmt.Errorf("failed to find invitation: %w", err)
+	}
+	if invitation.InvitedBy != userID {
+		return model.ErrForbidden
+	}
+	if err := u.invitationRepo.Delete(ctx, id); err != nil {
+		return fmt.Errorf("failed to delete invitation: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,41 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/sikigasa/github-task-controller/backend/internal/domain/model"
+	"github.com/sikigasa/github-task-controller/backend/internal/domain/repository"
+)
+
+// errLocked は他のインスタンス（または処理）がロックを保持していて待ちきれなかった場合のエラー
+// model.ErrConflictと合わせて返すため、呼び出し側は後で再試行できる
+var errLocked = errors.New("resource is locked by another operation")
+
+// heldLocksKey はacquireLockで取得したロックのキーをコンテキストに保持するためのキー
+type heldLocksKey struct{}
+
+// acquireLock はキーのロックをwaitまで待って取得し、ロックを保持していることを記録したコンテキストを返す
+// そのコンテキストで同じキーを再度取得する場合は何もしない（同じ処理の中で入れ子になっても自身を待たない）
+// lockerがnilの場合は排他しない（単一インスタンスでの運用）
+func acquireLock(ctx context.Context, locker repository.Locker, key string, wait time.Duration) (context.Context, func(), error) {
+	held, _ := ctx.Value(heldLocksKey{}).([]string)
+	if locker == nil || slices.Contains(held, key) {
+		return ctx, func() {}, nil
+	}
+
+	waitCtx, cancel := context.WithTimeout(ctx, wait)
+	defer cancel()
+	unlock, err := locker.Lock(waitCtx, key)
+	if err != nil {
+		if ctx.Err() == nil && errors.Is(err, context.DeadlineExceeded) {
+			return ctx, nil, fmt.Errorf("%s: %w: %w", key, errLocked, model.ErrConflict)
+		}
+		return ctx, nil, err
+	}
+
+	return context.WithValue(ctx, heldLocksKey{}, append(slices.Clip(held), key)), unlock, nil
+}
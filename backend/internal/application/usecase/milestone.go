@@ -0,0 +1,193 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sikigasa/github-task-controller/backend/internal/domain/model"
+	"github.com/sikigasa/github-task-controller/backend/internal/domain/repository"
+)
+
+// MilestoneUsecase はマイルストーンに関するユースケース
+type MilestoneUsecase struct {
+	milestoneRepo repository.MilestoneRepository
+	projectRepo   repository.ProjectRepository
+	activity      ActivityRecorder
+	tx            repository.Transactor
+	logger        *slog.Logger
+}
+
+// NewMilestoneUsecase は新しいMilestoneUsecaseを作成する
+func NewMilestoneUsecase(milestoneRepo repository.MilestoneRepository, projectRepo repository.ProjectRepository, activity ActivityRecorder, tx repository.Transactor, logger *slog.Logger) *MilestoneUsecase {
+	return &MilestoneUsecase{
+		milestoneRepo: milestoneRepo,
+		projectRepo:   projectRepo,
+		activity:      activity,
+		tx:            tx,
+		logger:        logger,
+	}
+}
+
+// CreateMilestone はプロジェクトに新しいマイルストーンを作成する
+func (u *MilestoneUsecase) CreateMilestone(ctx context.Context, userID, projectID string, req *model.CreateMilestoneRequest) (*model.MilestoneWithProgress, error) {
+	if err := u.authorizeProject(ctx, userID, projectID); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	milestone := &model.Milestone{
+		ID:          uuid.New().String(),
+		ProjectID:   projectID,
+		Title:       req.Title,
+		Description: req.Description,
+		DueDate:     req.DueDate,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+
+	err := u.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if err := u.milestoneRepo.Create(ctx, milestone); err != nil {
+			return err
+		}
+		return u.activity.Record(ctx, projectID, model.ActivityEntityMilestone, milestone.ID, model.ActivityActionCreate, nil, milestone)
+	})
+	if err != nil {
+		u.logger.ErrorContext(ctx, "failed to create milestone", "error", err)
+		return nil, fmt.Errorf("failed to create milestone: %w", err)
+	}
+
+	u.logger.InfoContext(ctx, "milestone created", "milestone_id", milestone.ID, "project_id", projectID)
+	return &model.MilestoneWithProgress{Milestone: milestone}, nil
+}
+
+// GetMilestone は進捗付きでマイルストーンを取得する
+func (u *MilestoneUsecase) GetMilestone(ctx context.Context, userID, id string) (*model.MilestoneWithProgress, error) {
+	milestone, err := u.findAuthorized(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	withProgress, err := u.attachProgress(ctx, []*model.Milestone{milestone})
+	if err != nil {
+		return nil, err
+	}
+
+	return withProgress[0], nil
+}
+
+// ListMilestones はプロジェクトの全マイルストーンを進捗付きで取得する
+func (u *MilestoneUsecase) ListMilestones(ctx context.Context, userID, projectID string) ([]*model.MilestoneWithProgress, error) {
+	if err := u.authorizeProject(ctx, userID, projectID); err != nil {
+		return nil, err
+	}
+
+	milestones, err := u.milestoneRepo.FindByProjectID(ctx, projectID)
+	if err != nil {
+		u.logger.ErrorContext(ctx, "failed to list milestones", "error", err, "project_id", projectID)
+		return nil, fmt.Errorf("failed to list milestones: %w", err)
+	}
+
+	return u.attachProgress(ctx, milestones)
+}
+
+// UpdateMilestone はマイルストーン情報を更新する
+func (u *MilestoneUsecase) UpdateMilestone(ctx context.Context, userID, id string, req *model.UpdateMilestoneRequest) (*model.MilestoneWithProgress, error) {
+	milestone, err := u.findAuthorized(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	before := *milestone
+	milestone.Title = req.Title
+	milestone.Description = req.Description
+	milestone.DueDate = req.DueDate
+	milestone.UpdatedAt = time.Now()
+
+	err = u.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if err := u.milestoneRepo.Update(ctx, milestone); err != nil {
+			return err
+		}
+		return u.activity.Record(ctx, milestone.ProjectID, model.ActivityEntityMilestone, milestone.ID, model.ActivityActionUpdate, &before, milestone)
+	})
+	if err != nil {
+		u.logger.ErrorContext(ctx, "failed to update milestone", "error", err, "milestone_id", id)
+		return nil, fmt.Errorf("failed to update milestone: %w", err)
+	}
+
+	withProgress, err := u.attachProgress(ctx, []*model.Milestone{milestone})
+	if err != nil {
+		return nil, err
+	}
+
+	u.logger.InfoContext(ctx, "milestone updated", "milestone_id", id)
+	return withProgress[0], nil
+}
+
+// DeleteMilestone はマイルストーンを削除する
+func (u *MilestoneUsecase) DeleteMilestone(ctx context.Context, userID, id string) error {
+	milestone, err := u.findAuthorized(ctx, userID, id)
+	if err != nil {
+		return err
+	}
+
+	err = u.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if err := u.milestoneRepo.Delete(ctx, id); err != nil {
+			return err
+		}
+		return u.activity.Record(ctx, milestone.ProjectID, model.ActivityEntityMilestone, milestone.ID, model.ActivityActionDelete, milestone, nil)
+	})
+	if err != nil {
+		u.logger.ErrorContext(ctx, "failed to delete milestone", "error", err, "milestone_id", id)
+		return fmt.Errorf("failed to delete milestone: %w", err)
+	}
+
+	u.logger.InfoContext(ctx, "milestone deleted", "milestone_id", id)
+	return nil
+}
+
+// authorizeProject はプロジェクトの所有者であることを確認する
+func (u *MilestoneUsecase) authorizeProject(ctx context.Context, userID, projectID string) error {
+	project, err := u.projectRepo.FindByID(ctx, projectID)
+	if err != nil {
+		return fmt.Errorf("failed to find project: %w", err)
+	}
+	if project.UserID != userID {
+		return model.ErrForbidden
+	}
+	return nil
+}
+
+// findAuthorized はマイルストーンを取得し、所属プロジェクトの所有者であることを確認する
+func (u *MilestoneUsecase) findAuthorized(ctx context.Context, userID, id string) (*model.Milestone, error) {
+	milestone, err := u.milestoneRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find milestone: %w", err)
+	}
+	if err := u.authorizeProject(ctx, userID, milestone.ProjectID); err != nil {
+		return nil, err
+	}
+	return milestone, nil
+}
+
+// attachProgress はマイルストーンの進捗をまとめて取得して付与する
+func (u *MilestoneUsecase) attachProgress(ctx context.Context, milestones []*model.Milestone) ([]*model.MilestoneWithProgress, error) {
+	ids := make([]string, 0, len(milestones))
+	for _, m := range milestones {
+		ids = append(ids, m.ID)
+	}
+
+	progress, err := u.milestoneRepo.ProgressByIDs(ctx, ids)
+	if err != nil {
+		u.logger.ErrorContext(ctx, "failed to get milestone progress", "error", err)
+		return nil, fmt.Errorf("failed to get milestone progress: %w", err)
+	}
+
+	result := make([]*model.MilestoneWithProgress, 0, len(milestones))
+	for _, m := range milestones {
+		result = append(result, &model.MilestoneWithProgress{Milestone: m, Progress: progress[m.ID]})
+	}
+	return result, nil
+}
@@ -0,0 +1,222 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sikigasa/github-task-controller/backend/internal/domain/model"
+	"github.com/sikigasa/github-task-controller/backend/internal/domain/repository"
+)
+
+const (
+	// outboxBatchSize はワーカーが一度に取得するイベントの件数
+	outboxBatchSize = 50
+	// outboxLease は取得したイベントを他のワーカーが取得しないようにする期間（配信中に停止した場合はこの後に再試行される）
+	outboxLease = 5 * time.Minute
+	// outboxRetryBase は1回目の再試行までの待ち時間（以降は失敗するごとに倍にする）
+	outboxRetryBase = 10 * time.Second
+	// outboxRetryMax は再試行までの待ち時間の上限
+	outboxRetryMax = time.Hour
+	// outboxErrorMaxLen は記録するエラーメッセージの最大長
+	outboxErrorMaxLen = 1000
+	// outboxPruneInterval は配信済みのイベントを削除する間隔
+	outboxPruneInterval = time.Hour
+	// outboxWorker はWorkerMonitorに記録するワーカーの名前
+	outboxWorker = "outbox"
+)
+
+// OutboxUsecase はアウトボックスを使ったイベントバス（EventBus）
+// Publishで変更と同じトランザクションでアウトボックスに書き込んだドメインイベントを、Runのワーカーが購読者に配信する
+// コミットされなかった変更のイベントは配信されず、コミットされた変更のイベントは配信に成功するまで再試行する
+type OutboxUsecase struct {
+	outboxRepo    repository.OutboxRepository
+	subscriptions []eventSubscription
+	maxAttempts   int
+	pollInterval  time.Duration
+	retention     time.Duration
+	workers       *WorkerMonitor
+	logger        *slog.Logger
+}
+
+// eventSubscription はイベントバスの購読者
+type eventSubscription struct {
+	name       string
+	subscriber EventSubscriber
+	// eventTypes は受け取るイベントの種類（空の場合はすべて）
+	eventTypes []string
+}
+
+// NewOutboxUsecase は新しいOutboxUsecaseを作成する
+// maxAttemptsは配信を試行する回数の上限、pollIntervalは配信待ちのイベントを確認する間隔、retentionは配信済みのイベントを残す期間
+// workersにはRunのワーカーの実行状況をoutboxとして記録する
+func NewOutboxUsecase(
+	outboxRepo repository.OutboxRepository,
+	maxAttempts int,
+	pollInterval time.Duration,
+	retention time.Duration,
+	workers *WorkerMonitor,
+	logger *slog.Logger,
+) *OutboxUsecase {
+	workers.Register(outboxWorker, pollInterval)
+	return &OutboxUsecase{
+		outboxRepo:   outboxRepo,
+		maxAttempts:  maxAttempts,
+		pollInterval: pollInterval,
+		retention:    retention,
+		workers:      workers,
+		logger:       logger,
+	}
+}
+
+// Publish はドメインイベントをアウトボックスに追加する
+func (u *OutboxUsecase) Publish(ctx context.Context, eventType, aggregateType, aggregateID string, data any) error {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s event payload: %w", eventType, err)
+	}
+
+	now := time.Now()
+	event := &model.OutboxEvent{
+		ID:            uuid.New().String(),
+		Type:          eventType,
+		AggregateType: aggregateType,
+		AggregateID:   aggregateID,
+		Payload:       payload,
+		Status:        model.OutboxEventPending,
+		NextAttemptAt: now,
+		OccurredAt:    now,
+	}
+	if err := u.outboxRepo.Append(ctx, event); err != nil {
+		return fmt.Errorf("failed to append %s event: %w", eventType, err)
+	}
+
+	return nil
+}
+
+// Subscribe は購読者を登録する（Runの開始前に呼び出す）
+func (u *OutboxUsecase) Subscribe(name string, subscriber EventSubscriber, eventTypes ...string) {
+	u.subscriptions = append(u.subscriptions, eventSubscription{
+		name:       name,
+		subscriber: subscriber,
+		eventTypes: eventTypes,
+	})
+}
+
+// Run はctxがキャンセルされるまでpollIntervalごとに配信待ちのイベントを配信する
+func (u *OutboxUsecase) Run(ctx context.Context) {
+	ticker := time.NewTicker(u.pollInterval)
+	defer ticker.Stop()
+
+	var prunedAt time.Time
+	for {
+		u.workers.Beat(outboxWorker, u.publishDue(ctx))
+
+		if time.Since(prunedAt) >= outboxPruneInterval {
+			u.prune(ctx)
+			prunedAt = time.Now()
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// publishDue は配信時刻を迎えたイベントがなくなるまで取得して配信する
+// イベントを取得できなかった場合はエラーを返す（個々のイベントの失敗は再試行に回すためエラーにしない）
+func (u *OutboxUsecase) publishDue(ctx context.Context) error {
+	for ctx.Err() == nil {
+		events, err := u.outboxRepo.ClaimDue(ctx, time.Now(), outboxLease, outboxBatchSize)
+		if err != nil {
+			// DBの一時的な障害の場合は次の確認時に再度取得する
+			u.logger.ErrorContext(ctx, "failed to claim outbox events", "error", err)
+			return err
+		}
+		// 同じ集約の後続のイベントは先のイベントの配信後に取得できるため、取得できなくなるまで繰り返す
+		if len(events) == 0 {
+			return nil
+		}
+		for _, event := range events {
+			u.publish(ctx, event)
+		}
+	}
+	return nil
+}
+
+// publish はイベントを種類が一致する購読者に配信し、結果を記録する
+// いずれかの購読者が失敗した場合は、すべての購読者に再配信する
+func (u *OutboxUsecase) publish(ctx context.Context, event *model.OutboxEvent) {
+	var errs []error
+	for _, s := range u.subscriptions {
+		if len(s.eventTypes) > 0 && !slices.Contains(s.eventTypes, event.Type) {
+			continue
+		}
+		if err := s.subscriber(ctx, event); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", s.name, err))
+		}
+	}
+	if err := errors.Join(errs...); err != nil {
+		u.markFailed(ctx, event, err)
+		return
+	}
+
+	// 購読者のいないイベントも配信済みとして記録する
+	if err := u.outboxRepo.MarkPublished(ctx, event.ID, time.Now()); err != nil {
+		// 記録に失敗した場合はleaseの後に再度配信される
+		u.logger.ErrorContext(ctx, "failed to mark outbox event published", "error", err, "event_id", event.ID)
+	}
+}
+
+// markFailed はイベントの配信の失敗を記録し、試行回数が上限に達した場合はデッドレターにする
+// デッドレターになったイベントは削除せずに残し、同じ集約の後続のイベントの配信を再開する
+func (u *OutboxUsecase) markFailed(ctx context.Context, event *model.OutboxEvent, cause error) {
+	attempts := event.Attempts + 1
+	dead := attempts >= u.maxAttempts
+	nextAttemptAt := time.Now().Add(outboxRetryDelay(attempts))
+
+	message := cause.Error()
+	if runes := []rune(message); len(runes) > outboxErrorMaxLen {
+		message = string(runes[:outboxErrorMaxLen])
+	}
+
+	if dead {
+		u.logger.ErrorContext(ctx, "outbox event moved to dead letter",
+			"error", cause, "event_id", event.ID, "event_type", event.Type, "attempts", attempts)
+	} else {
+		u.logger.WarnContext(ctx, "outbox event publish failed, will retry",
+			"error", cause, "event_id", event.ID, "event_type", event.Type, "attempts", attempts, "next_attempt_at", nextAttemptAt)
+	}
+
+	if err := u.outboxRepo.MarkFailed(ctx, event.ID, attempts, message, nextAttemptAt, dead); err != nil {
+		u.logger.ErrorContext(ctx, "failed to mark outbox event failed", "error", err, "event_id", event.ID)
+	}
+}
+
+// prune はretentionより前に配信済みになったイベントを削除する
+func (u *OutboxUsecase) prune(ctx context.Context) {
+	deleted, err := u.outboxRepo.DeletePublishedBefore(ctx, time.Now().Add(-u.retention))
+	if err != nil {
+		u.logger.ErrorContext(ctx, "failed to prune outbox events", "error", err)
+		return
+	}
+	if deleted > 0 {
+		u.logger.InfoContext(ctx, "published outbox events pruned", "count", deleted)
+	}
+}
+
+// outboxRetryDelay はattempts回目の失敗の後、次の試行までの待ち時間を返す
+func outboxRetryDelay(attempts int) time.Duration {
+	delay := outboxRetryBase
+	for i := 1; i < attempts && delay < outboxRetryMax; i++ {
+		delay *= 2
+	}
+	return min(delay, outboxRetryMax)
+}
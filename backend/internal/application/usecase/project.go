@@ -2,6 +2,7 @@ package usecase
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 	"time"
@@ -13,31 +14,63 @@ import (
 
 // ProjectUsecase はプロジェクトに関するユースケース
 type ProjectUsecase struct {
-	projectRepo repository.ProjectRepository
-	logger      *slog.Logger
+	projectRepo  repository.ProjectRepository
+	taskRepo     repository.TaskRepository
+	depRepo      repository.TaskDependencyRepository
+	settingsRepo repository.SettingsRepository
+	events       EventBus
+	activity     ActivityRecorder
+	tx           repository.Transactor
+	logger       *slog.Logger
 }
 
 // NewProjectUsecase は新しいProjectUsecaseを作成する
-func NewProjectUsecase(projectRepo repository.ProjectRepository, logger *slog.Logger) *ProjectUsecase {
+func NewProjectUsecase(projectRepo repository.ProjectRepository, taskRepo repository.TaskRepository, depRepo repository.TaskDependencyRepository, settingsRepo repository.SettingsRepository, events EventBus, activity ActivityRecorder, tx repository.Transactor, logger *slog.Logger) *ProjectUsecase {
 	return &ProjectUsecase{
-		projectRepo: projectRepo,
-		logger:      logger,
+		projectRepo:  projectRepo,
+		taskRepo:     taskRepo,
+		depRepo:      depRepo,
+		settingsRepo: settingsRepo,
+		events:       events,
+		activity:     activity,
+		tx:           tx,
+		logger:       logger,
 	}
 }
 
 // CreateProject は新しいプロジェクトを作成する
-func (u *ProjectUsecase) CreateProject(ctx context.Context, userID, title, description string) (*model.Project, error) {
+// estimateUnitが空の場合はポイントを使用する
+func (u *ProjectUsecase) CreateProject(ctx context.Context, userID, title, description string, estimateUnit model.EstimateUnit) (*model.Project, error) {
+	if estimateUnit == "" {
+		estimateUnit = model.EstimateUnitPoints
+	}
+	if !estimateUnit.IsValid() {
+		return nil, fmt.Errorf("invalid estimate unit %q: %w", estimateUnit, model.ErrInvalidInput)
+	}
+
 	now := time.Now()
 	project := &model.Project{
-		ID:          uuid.New().String(),
-		UserID:      userID,
-		Title:       title,
-		Description: description,
-		CreatedAt:   now,
-		UpdatedAt:   now,
+		ID:                   uuid.New().String(),
+		UserID:               userID,
+		Title:                title,
+		Description:          description,
+		EstimateUnit:         estimateUnit,
+		GithubDeletionPolicy: model.GithubDeletionOrphan,
+		GithubItemType:       model.GithubItemDraft,
+		CreatedAt:            now,
+		UpdatedAt:            now,
 	}
 
-	if err := u.projectRepo.Create(ctx, project); err != nil {
+	err := u.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if err := u.projectRepo.Create(ctx, project); err != nil {
+			return err
+		}
+		if err := u.events.Publish(ctx, model.EventProjectCreated, model.AggregateProject, project.ID, project); err != nil {
+			return err
+		}
+		return u.activity.Record(ctx, project.ID, model.ActivityEntityProject, project.ID, model.ActivityActionCreate, nil, project)
+	})
+	if err != nil {
 		u.logger.ErrorContext(ctx, "failed to create project", "error", err)
 		return nil, fmt.Errorf("failed to create project: %w", err)
 	}
@@ -46,20 +79,33 @@ func (u *ProjectUsecase) CreateProject(ctx context.Context, userID, title, descr
 	return project, nil
 }
 
-// GetProject はIDでプロジェクトを取得する
-func (u *ProjectUsecase) GetProject(ctx context.Context, id string) (*model.Project, error) {
+// GetProject はuserIDが所有するプロジェクトをIDで取得する
+func (u *ProjectUsecase) GetProject(ctx context.Context, userID, id string) (*model.Project, error) {
+	return u.findOwned(ctx, userID, id)
+}
+
+// findOwned はuserIDが所有するプロジェクトを取得する
+// 存在しない場合はErrNotFound、他のユーザーのプロジェクトの場合はErrForbiddenを返す
+func (u *ProjectUsecase) findOwned(ctx context.Context, userID, id string) (*model.Project, error) {
+	if err := validateResourceID(id); err != nil {
+		return nil, err
+	}
+
 	project, err := u.projectRepo.FindByID(ctx, id)
 	if err != nil {
-		u.logger.ErrorContext(ctx, "failed to get project", "error", err, "project_id", id)
-		return nil, fmt.Errorf("failed to get project: %w", err)
+		return nil, fmt.Errorf("failed to find project: %w", err)
+	}
+	if project.UserID != userID {
+		u.logger.WarnContext(ctx, "unauthorized project access attempt", "project_id", id, "project_owner", project.UserID, "user_id", userID)
+		return nil, model.ErrForbidden
 	}
 
 	return project, nil
 }
 
 // ListProjectsByUserID はユーザーIDで全プロジェクトを取得する
-func (u *ProjectUsecase) ListProjectsByUserID(ctx context.Context, userID string) ([]*model.Project, error) {
-	projects, err := u.projectRepo.FindByUserID(ctx, userID)
+func (u *ProjectUsecase) ListProjectsByUserID(ctx context.Context, userID string, opts model.ListOptions) ([]*model.Project, error) {
+	projects, err := u.projectRepo.FindByUserID(ctx, userID, opts)
 	if err != nil {
 		u.logger.ErrorContext(ctx, "failed to list projects", "error", err, "user_id", userID)
 		return nil, fmt.Errorf("failed to list projects: %w", err)
@@ -68,19 +114,26 @@ func (u *ProjectUsecase) ListProjectsByUserID(ctx context.Context, userID string
 	return projects, nil
 }
 
-// UpdateProject はプロジェクト情報を更新する
-func (u *ProjectUsecase) UpdateProject(ctx context.Context, id, title, description string) (*model.Project, error) {
-	project, err := u.projectRepo.FindByID(ctx, id)
+// UpdateProject はuserIDが所有するプロジェクトの情報を更新する
+// versionを指定し、現在のバージョンと異なる場合は現在のプロジェクトを付けたmodel.StaleVersionErrorを返す
+func (u *ProjectUsecase) UpdateProject(ctx context.Context, userID, id, title, description string, version *int) (*model.Project, error) {
+	project, err := u.findOwned(ctx, userID, id)
 	if err != nil {
-		u.logger.ErrorContext(ctx, "failed to find project", "error", err, "project_id", id)
-		return nil, fmt.Errorf("failed to find project: %w", err)
+		return nil, err
 	}
 
+	before := *project
 	project.Title = title
 	project.Description = description
 	project.UpdatedAt = time.Now()
+	if version != nil {
+		project.Version = *version
+	}
 
-	if err := u.projectRepo.Update(ctx, project); err != nil {
+	if err := saveProject(ctx, u.tx, u.projectRepo, u.events, u.activity, project, &before, model.EventProjectUpdated); err != nil {
+		if errors.Is(err, model.ErrStaleVersion) {
+			return nil, u.staleProjectError(ctx, id)
+		}
 		u.logger.ErrorContext(ctx, "failed to update project", "error", err, "project_id", id)
 		return nil, fmt.Errorf("failed to update project: %w", err)
 	}
@@ -89,9 +142,122 @@ func (u *ProjectUsecase) UpdateProject(ctx context.Context, id, title, descripti
 	return project, nil
 }
 
-// DeleteProject はプロジェクトを削除する
-func (u *ProjectUsecase) DeleteProject(ctx context.Context, id string) error {
-	if err := u.projectRepo.Delete(ctx, id); err != nil {
+// PatchProject はuserIDが所有するプロジェクトを、リクエストに含まれるフィールドのみ更新する
+// req.Versionが現在のバージョンと異なる場合は現在のプロジェクトを付けたmodel.StaleVersionErrorを返す
+func (u *ProjectUsecase) PatchProject(ctx context.Context, userID, id string, req *model.PatchProjectRequest) (*model.Project, error) {
+	project, err := u.findOwned(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	before := *project
+	if req.Title != nil {
+		project.Title = *req.Title
+	}
+	if req.Description != nil {
+		project.Description = *req.Description
+	}
+	if req.GithubOwner.Set {
+		project.GithubOwner = req.GithubOwner.Value
+	}
+	if req.GithubRepo.Set {
+		project.GithubRepo = req.GithubRepo.Value
+	}
+	if req.GithubProjectNumber.Set {
+		project.GithubProjectNumber = req.GithubProjectNumber.Value
+	}
+	if req.GithubRepoProject != nil {
+		project.GithubRepoProject = *req.GithubRepoProject
+	}
+	if req.GithubOwner.Set || req.GithubRepoProject != nil {
+		// 連携先を直接書き換えた場合は所有者の種類を確認し直していないため未確認に戻す
+		project.GithubOwnerType = nil
+	}
+	if req.EstimateUnit != nil {
+		if !req.EstimateUnit.IsValid() {
+			return nil, fmt.Errorf("invalid estimate unit %q: %w", *req.EstimateUnit, model.ErrInvalidInput)
+		}
+		project.EstimateUnit = *req.EstimateUnit
+	}
+	if req.GithubEstimateField.Set {
+		project.GithubEstimateField = req.GithubEstimateField.Value
+	}
+	if req.GithubCommitStartsTask != nil {
+		project.GithubCommitStartsTask = *req.GithubCommitStartsTask
+	}
+	if req.GithubDeletionPolicy != nil {
+		if !req.GithubDeletionPolicy.IsValid() {
+			return nil, fmt.Errorf("invalid github deletion policy %q: %w", *req.GithubDeletionPolicy, model.ErrInvalidInput)
+		}
+		project.GithubDeletionPolicy = *req.GithubDeletionPolicy
+	}
+	if req.GithubItemType != nil {
+		if !req.GithubItemType.IsValid() {
+			return nil, fmt.Errorf("invalid github item type %q: %w", *req.GithubItemType, model.ErrInvalidInput)
+		}
+		project.GithubItemType = *req.GithubItemType
+	}
+	project.UpdatedAt = time.Now()
+	if req.Version != nil {
+		project.Version = *req.Version
+	}
+
+	if err := saveProject(ctx, u.tx, u.projectRepo, u.events, u.activity, project, &before, model.EventProjectUpdated); err != nil {
+		if errors.Is(err, model.ErrStaleVersion) {
+			return nil, u.staleProjectError(ctx, id)
+		}
+		u.logger.ErrorContext(ctx, "failed to patch project", "error", err, "project_id", id)
+		return nil, fmt.Errorf("failed to patch project: %w", err)
+	}
+
+	u.logger.InfoContext(ctx, "project patched", "project_id", id)
+	return project, nil
+}
+
+// GetDeletePreview はuserIDが所有するプロジェクトを削除した場合に合わせて削除されるタスクとGitHubのItemを返す
+func (u *ProjectUsecase) GetDeletePreview(ctx context.Context, userID, id string) (*model.ProjectDeletePreview, error) {
+	if _, err := u.findOwned(ctx, userID, id); err != nil {
+		return nil, err
+	}
+
+	tasks, err := u.taskRepo.FindByProjectIDs(ctx, []string{id})
+	if err != nil {
+		u.logger.ErrorContext(ctx, "failed to load tasks for delete preview", "error", err, "project_id", id)
+		return nil, fmt.Errorf("failed to load tasks for delete preview: %w", err)
+	}
+	return model.NewProjectDeletePreview(id, tasks), nil
+}
+
+// DeleteProject はuserIDが所有するプロジェクトをタスクごとゴミ箱に移す
+// タスクがある場合はforceを指定しないとmodel.ErrProjectHasTasksを返す
+func (u *ProjectUsecase) DeleteProject(ctx context.Context, userID, id string, force bool) error {
+	err := u.tx.WithinTx(ctx, func(ctx context.Context) error {
+		project, err := u.findOwned(ctx, userID, id)
+		if err != nil {
+			return err
+		}
+		if !force {
+			tasks, err := u.taskRepo.FindByProjectIDs(ctx, []string{id})
+			if err != nil {
+				return err
+			}
+			if len(tasks) > 0 {
+				return fmt.Errorf("%d tasks: %w", len(tasks), model.ErrProjectHasTasks)
+			}
+		}
+		if err := u.projectRepo.Delete(ctx, id, time.Now()); err != nil {
+			return err
+		}
+		payload := model.ProjectDeletedPayload{ID: project.ID, UserID: project.UserID}
+		if err := u.events.Publish(ctx, model.EventProjectDeleted, model.AggregateProject, project.ID, payload); err != nil {
+			return err
+		}
+		return u.activity.Record(ctx, project.ID, model.ActivityEntityProject, project.ID, model.ActivityActionDelete, project, nil)
+	})
+	if errors.Is(err, model.ErrProjectHasTasks) || errors.Is(err, model.ErrForbidden) || errors.Is(err, model.ErrNotFound) {
+		return err
+	}
+	if err != nil {
 		u.logger.ErrorContext(ctx, "failed to delete project", "error", err, "project_id", id)
 		return fmt.Errorf("failed to delete project: %w", err)
 	}
@@ -99,3 +265,149 @@ func (u *ProjectUsecase) DeleteProject(ctx context.Context, id string) error {
 	u.logger.InfoContext(ctx, "project deleted", "project_id", id)
 	return nil
 }
+
+// RestoreProject はuserIDが所有するゴミ箱のプロジェクトを、プロジェクトと一緒にゴミ箱に移したタスクごと元に戻す
+// プロジェクトの削除より前に個別にゴミ箱に移したタスクはゴミ箱に残す
+func (u *ProjectUsecase) RestoreProject(ctx context.Context, userID, id string) (*model.Project, error) {
+	if err := validateResourceID(id); err != nil {
+		return nil, err
+	}
+
+	var project *model.Project
+	err := u.tx.WithinTx(ctx, func(ctx context.Context) error {
+		deleted, err := u.projectRepo.FindDeletedByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if deleted.UserID != userID {
+			u.logger.WarnContext(ctx, "unauthorized project access attempt", "project_id", id, "project_owner", deleted.UserID, "user_id", userID)
+			return model.ErrForbidden
+		}
+		if err := u.projectRepo.Restore(ctx, deleted); err != nil {
+			return err
+		}
+		project, err = u.projectRepo.FindByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if err := u.events.Publish(ctx, model.EventProjectRestored, model.AggregateProject, project.ID, project); err != nil {
+			return err
+		}
+		return u.activity.Record(ctx, project.ID, model.ActivityEntityProject, project.ID, model.ActivityActionRestore, nil, project)
+	})
+	if errors.Is(err, model.ErrForbidden) || errors.Is(err, model.ErrNotFound) {
+		return nil, err
+	}
+	if err != nil {
+		u.logger.ErrorContext(ctx, "failed to restore project", "error", err, "project_id", id)
+		return nil, fmt.Errorf("failed to restore project: %w", err)
+	}
+
+	u.logger.InfoContext(ctx, "project restored", "project_id", id)
+	return project, nil
+}
+
+// staleProjectError は他の更新と競合したプロジェクトの現在の状態を付けたmodel.StaleVersionErrorを返す
+func (u *ProjectUsecase) staleProjectError(ctx context.Context, id string) error {
+	current, err := u.projectRepo.FindByID(ctx, id)
+	if err != nil {
+		return fmt.Errorf("failed to find project: %w", err)
+	}
+	return &model.StaleVersionError{Current: current}
+}
+
+// saveProject はbeforeから変更したプロジェクトを更新し、eventTypeのイベントとアクティビティを同じトランザクションで書き込む
+func saveProject(ctx context.Context, tx repository.Transactor, projectRepo repository.ProjectRepository, events EventBus, activity ActivityRecorder, project, before *model.Project, eventType string) error {
+	return tx.WithinTx(ctx, func(ctx context.Context) error {
+		if err := projectRepo.Update(ctx, project); err != nil {
+			return err
+		}
+		if err := events.Publish(ctx, eventType, model.AggregateProject, project.ID, project); err != nil {
+			return err
+		}
+		return activity.Record(ctx, project.ID, model.ActivityEntityProject, project.ID, model.ActivityActionUpdate, before, project)
+	})
+}
+
+// ExpandProjects はプロジェクトに関連リソースを埋め込む
+// N+1クエリを避けるため、関連リソースは全プロジェクト分をまとめて取得する
+// 集計の期限切れはuserIDの設定のタイムゾーンでの今日より前に終了日があるものとする
+func (u *ProjectUsecase) ExpandProjects(ctx context.Context, userID string, projects []*model.Project, expand model.ProjectExpand) ([]*model.ProjectDetail, error) {
+	details := make([]*model.ProjectDetail, 0, len(projects))
+	projectIDs := make([]string, 0, len(projects))
+	for _, p := range projects {
+		d := &model.ProjectDetail{Project: p}
+		details = append(details, d)
+		projectIDs = append(projectIDs, p.ID)
+	}
+
+	if expand.Tasks {
+		tasks, err := u.taskRepo.FindByProjectIDs(ctx, projectIDs)
+		if err != nil {
+			u.logger.ErrorContext(ctx, "failed to load tasks for projects", "error", err)
+			return nil, fmt.Errorf("failed to load tasks for projects: %w", err)
+		}
+		tasksByProject := make(map[string][]*model.Task, len(projects))
+		for _, t := range tasks {
+			tasksByProject[t.ProjectID] = append(tasksByProject[t.ProjectID], t)
+		}
+		for _, d := range details {
+			projectTasks := tasksByProject[d.ID]
+			if projectTasks == nil {
+				projectTasks = []*model.Task{}
+			}
+			d.Tasks = &projectTasks
+		}
+	}
+
+	if expand.Stats {
+		settings, err := findSettings(ctx, u.settingsRepo, userID)
+		if err != nil {
+			return nil, err
+		}
+		overdueBefore := model.StartOfDay(time.Now(), settings.Location())
+		stats, err := u.taskRepo.CountByProjectIDs(ctx, projectIDs, overdueBefore)
+		if err != nil {
+			u.logger.ErrorContext(ctx, "failed to load stats for projects", "error", err)
+			return nil, fmt.Errorf("failed to load stats for projects: %w", err)
+		}
+		for _, d := range details {
+			if s, ok := stats[d.ID]; ok {
+				d.Stats = s
+			} else {
+				d.Stats = &model.ProjectStats{}
+			}
+		}
+	}
+
+	if expand.Github {
+		for _, d := range details {
+			d.Github = model.NewProjectGithub(d.Project)
+		}
+	}
+
+	return details, nil
+}
+
+// GetTimeline はuserIDが所有するプロジェクトのタイムライン（タスクの期間と依存関係）を取得する
+func (u *ProjectUsecase) GetTimeline(ctx context.Context, userID, projectID string) (*model.Timeline, error) {
+	if _, err := u.findOwned(ctx, userID, projectID); err != nil {
+		return nil, err
+	}
+
+	tasks, err := u.taskRepo.FindByProjectID(ctx, projectID, model.TaskFilter{}, model.ListOptions{
+		Sort: []model.SortKey{{Field: "start_date", Order: model.SortAsc}, {Field: "created_at", Order: model.SortAsc}},
+	})
+	if err != nil {
+		u.logger.ErrorContext(ctx, "failed to load tasks for timeline", "error", err, "project_id", projectID)
+		return nil, fmt.Errorf("failed to load tasks for timeline: %w", err)
+	}
+
+	deps, err := u.depRepo.FindByProjectID(ctx, projectID)
+	if err != nil {
+		u.logger.ErrorContext(ctx, "failed to load dependencies for timeline", "error", err, "project_id", projectID)
+		return nil, fmt.Errorf("failed to load dependencies for timeline: %w", err)
+	}
+
+	return model.NewTimeline(projectID, tasks, deps), nil
+}
@@ -0,0 +1,160 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sikigasa/github-task-controller/backend/internal/domain/model"
+	"github.com/sikigasa/github-task-controller/backend/internal/domain/repository"
+)
+
+// projectTokenTouchInterval は最終使用日時を記録し直す間隔（リクエストのたびに書き込まないため）
+const projectTokenTouchInterval = time.Minute
+
+// ProjectTokenUsecase はプロジェクト単位のAPIトークンに関するユースケース
+// トークンは発行したユーザーとして認証し、対象のプロジェクト以外のリソースへのアクセスは認証ミドルウェアで拒否する
+type ProjectTokenUsecase struct {
+	tokenRepo   repository.ProjectTokenRepository
+	projectRepo repository.ProjectRepository
+	logger      *slog.Logger
+}
+
+// NewProjectTokenUsecase は新しいProjectTokenUsecaseを作成する
+func NewProjectTokenUsecase(tokenRepo repository.ProjectTokenRepository, projectRepo repository.ProjectRepository, logger *slog.Logger) *ProjectTokenUsecase {
+	return &ProjectTokenUsecase{
+		tokenRepo:   tokenRepo,
+		projectRepo: projectRepo,
+		logger:      logger,
+	}
+}
+
+// CreateToken はプロジェクトに限定したAPIトークンを発行する
+// トークン自体は保存しないため、返したトークンは再表示できない
+func (u *ProjectTokenUsecase) CreateToken(ctx context.Context, userID, projectID string, req *model.CreateProjectTokenRequest) (*model.IssuedProjectToken, error) {
+	if err := u.authorizeProject(ctx, userID, projectID); err != nil {
+		return nil, err
+	}
+	if !req.Scope.IsValid() {
+		return nil, fmt.Errorf("invalid project token scope %q: %w", req.Scope, model.ErrInvalidInput)
+	}
+
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return nil, fmt.Errorf("failed to generate project token: %w", err)
+	}
+	plain := model.ProjectTokenPrefix + base64.RawURLEncoding.EncodeToString(b)
+
+	now := time.Now()
+	token := &model.ProjectToken{
+		ID:        uuid.New().String(),
+		ProjectID: projectID,
+		UserID:    userID,
+		Name:      req.Name,
+		Scope:     req.Scope,
+		TokenHash: hashProjectToken(plain),
+		CreatedAt: now,
+	}
+	if req.ExpiresInDays != nil {
+		expiresAt := now.AddDate(0, 0, *req.ExpiresInDays)
+		token.ExpiresAt = &expiresAt
+	}
+
+	if err := u.tokenRepo.Create(ctx, token); err != nil {
+		return nil, fmt.Errorf("failed to create project token: %w", err)
+	}
+
+	u.logger.InfoContext(ctx, "project token issued", "token_id", token.ID, "project_id", projectID, "scope", token.Scope)
+	return &model.IssuedProjectToken{ProjectToken: token, Token: plain}, nil
+}
+
+// ListTokens はプロジェクトに発行したAPIトークンを一覧する（トークン自体は含まない）
+func (u *ProjectTokenUsecase) ListTokens(ctx context.Context, userID, projectID string) ([]*model.ProjectToken, error) {
+	if err := u.authorizeProject(ctx, userID, projectID); err != nil {
+		return nil, err
+	}
+
+	tokens, err := u.tokenRepo.FindByProjectID(ctx, projectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list project tokens: %w", err)
+	}
+	return tokens, nil
+}
+
+// RevokeToken はプロジェクトのAPIトークンを失効させる
+func (u *ProjectTokenUsecase) RevokeToken(ctx context.Context, userID, projectID, id string) error {
+	if err := u.authorizeProject(ctx, userID, projectID); err != nil {
+		return err
+	}
+	if err := validateResourceID(id); err != nil {
+		return err
+	}
+
+	if err := u.tokenRepo.Delete(ctx, projectID, id); err != nil {
+		return fmt.Errorf("failed to revoke project token: %w", err)
+	}
+
+	u.logger.InfoContext(ctx, "project token revoked", "token_id", id, "project_id", projectID)
+	return nil
+}
+
+// IsProjectToken はトークンがプロジェクト単位のAPIトークンの形式かを返す
+func (u *ProjectTokenUsecase) IsProjectToken(token string) bool {
+	return strings.HasPrefix(token, model.ProjectTokenPrefix)
+}
+
+// VerifyProjectToken はプロジェクト単位のAPIトークンを検証し、発行したユーザー・対象のプロジェクト・書き込みを許可するかを返す
+// 存在しない・失効済み・期限切れのトークンはErrUnauthorized
+func (u *ProjectTokenUsecase) VerifyProjectToken(ctx context.Context, plain string) (string, string, bool, error) {
+	token, err := u.tokenRepo.FindByHash(ctx, hashProjectToken(plain))
+	if err != nil {
+		if errors.Is(err, model.ErrNotFound) {
+			return "", "", false, model.ErrUnauthorized
+		}
+		return "", "", false, fmt.Errorf("failed to find project token: %w", err)
+	}
+
+	now := time.Now()
+	if token.IsExpired(now) {
+		return "", "", false, model.ErrUnauthorized
+	}
+
+	if token.LastUsedAt == nil || now.Sub(*token.LastUsedAt) >= projectTokenTouchInterval {
+		// 最終使用日時は目安のため、記録できなくても認証は続行する
+		if err := u.tokenRepo.TouchLastUsed(ctx, token.ID, now); err != nil {
+			u.logger.WarnContext(ctx, "failed to record project token usage", "error", err, "token_id", token.ID)
+		}
+	}
+
+	return token.UserID, token.ProjectID, token.CanWrite(), nil
+}
+
+// authorizeProject はプロジェクトをuserIDが所有していることを確認する
+func (u *ProjectTokenUsecase) authorizeProject(ctx context.Context, userID, projectID string) error {
+	if err := validateResourceID(projectID); err != nil {
+		return err
+	}
+	project, err := u.projectRepo.FindByID(ctx, projectID)
+	if err != nil {
+		return fmt.Errorf("failed to find project: %w", err)
+	}
+	if project.UserID != userID {
+		return model.ErrForbidden
+	}
+
+	return nil
+}
+
+// hashProjectToken は保存・検索に使うAPIトークンのハッシュを返す
+func hashProjectToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,330 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sikigasa/github-task-controller/backend/internal/domain/model"
+	"github.com/sikigasa/github-task-controller/backend/internal/domain/repository"
+)
+
+// MaxProvisioningPageSize はプロビジョニングAPIの一覧で1ページに返す最大件数
+const MaxProvisioningPageSize = 100
+
+// ProvisioningUsecase はIdPからのユーザー・グループのプロビジョニング（SCIM）に関するユースケース
+// プロビジョニングはデプロイ全体に対する管理操作のため、ユーザー単位の権限確認は行わない
+type ProvisioningUsecase struct {
+	userRepo        repository.UserRepository
+	groupRepo       repository.GroupRepository
+	userSessionRepo repository.UserSessionRepository
+	logger          *slog.Logger
+}
+
+// NewProvisioningUsecase は新しいProvisioningUsecaseを作成する
+func NewProvisioningUsecase(
+	userRepo repository.UserRepository,
+	groupRepo repository.GroupRepository,
+	userSessionRepo repository.UserSessionRepository,
+	logger *slog.Logger,
+) *ProvisioningUsecase {
+	return &ProvisioningUsecase{
+		userRepo:        userRepo,
+		groupRepo:       groupRepo,
+		userSessionRepo: userSessionRepo,
+		logger:          logger,
+	}
+}
+
+// pageOffset は1始まりの開始位置と件数をオフセットと上限件数に変換する
+func pageOffset(startIndex, count int) (int, int) {
+	return max(startIndex, 1) - 1, min(max(count, 0), MaxProvisioningPageSize)
+}
+
+// ListUsers はユーザーを一覧し、総件数とあわせて返す（emailを指定した場合は一致するユーザーのみ）
+func (u *ProvisioningUsecase) ListUsers(ctx context.Context, email string, startIndex, count int) ([]*model.User, int, error) {
+	offset, limit := pageOffset(startIndex, count)
+	users, total, err := u.userRepo.List(ctx, email, offset, limit)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list users: %w", err)
+	}
+	return users, total, nil
+}
+
+// GetUser はユーザーを取得する
+func (u *ProvisioningUsecase) GetUser(ctx context.Context, id string) (*model.User, error) {
+	if err := validateResourceID(id); err != nil {
+		return nil, err
+	}
+	user, err := u.userRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find user: %w", err)
+	}
+	return user, nil
+}
+
+// CreateUser はユーザーを作成する（同じメールアドレスのユーザーがいる場合はErrConflict）
+// 作成したユーザーは初回のOAuth・SAMLログイン時にメールアドレスで紐付けられる
+func (u *ProvisioningUsecase) CreateUser(ctx context.Context, email, name string, active bool) (*model.User, error) {
+	if err := validateProvisionedEmail(email); err != nil {
+		return nil, err
+	}
+	if err := u.ensureEmailAvailable(ctx, email, ""); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if name == "" {
+		name, _, _ = strings.Cut(email, "@")
+	}
+	user := &model.User{
+		ID:        uuid.New().String(),
+		Email:     email,
+		Name:      name,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if !active {
+		user.DeactivatedAt = &now
+	}
+
+	if err := u.userRepo.Create(ctx, user); err != nil {
+		return nil, fmt.Errorf("failed to create user: %w", err)
+	}
+
+	u.logger.InfoContext(ctx, "user provisioned", "user_id", user.ID)
+	return user, nil
+}
+
+// UpdateUser はユーザーのメールアドレス・名前・有効状態を更新する
+// 無効化した場合はユーザーのログインセッションを削除し、以降のログインを拒否する
+func (u *ProvisioningUsecase) UpdateUser(ctx context.Context, id, email, name string, active bool) (*model.User, error) {
+	if err := validateProvisionedEmail(email); err != nil {
+		return nil, err
+	}
+
+	user, err := u.GetUser(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if email != user.Email {
+		if err := u.ensureEmailAvailable(ctx, email, id); err != nil {
+			return nil, err
+		}
+		user.Email = email
+	}
+	if name != "" {
+		user.Name = name
+	}
+
+	deactivated := user.Active() && !active
+	switch {
+	case deactivated:
+		now := time.Now()
+		user.DeactivatedAt = &now
+	case active:
+		user.DeactivatedAt = nil
+	}
+
+	if err := u.userRepo.Update(ctx, user); err != nil {
+		return nil, fmt.Errorf("failed to update user: %w", err)
+	}
+
+	if deactivated {
+		if err := u.userSessionRepo.DeleteByUserID(ctx, id); err != nil {
+			return nil, fmt.Errorf("failed to revoke user sessions: %w", err)
+		}
+		u.logger.InfoContext(ctx, "user deprovisioned", "user_id", id)
+	}
+	return user, nil
+}
+
+// DeleteUser はユーザーを削除する（ユーザーのプロジェクト・タスクもすべて削除される）
+func (u *ProvisioningUsecase) DeleteUser(ctx context.Context, id string) error {
+	if err := validateResourceID(id); err != nil {
+		return err
+	}
+	if err := u.userRepo.Delete(ctx, id); err != nil {
+		return fmt.Errorf("failed to delete user: %w", err)
+	}
+	u.logger.InfoContext(ctx, "provisioned user deleted", "user_id", id)
+	return nil
+}
+
+// validateResourceID はリソースIDがUUIDであることを検証する（UUIDでないIDのリソースは存在しない）
+func validateResourceID(id string) error {
+	if _, err := uuid.Parse(id); err != nil {
+		return fmt.Errorf("resource %s not found: %w", id, model.ErrNotFound)
+	}
+	return nil
+}
+
+// validateProvisionedEmail はプロビジョニングされたユーザー名がメールアドレスであることを検証する
+func validateProvisionedEmail(email string) error {
+	if email == "" || !strings.Contains(email, "@") {
+		return fmt.Errorf("userName must be an email address: %w", model.ErrInvalidInput)
+	}
+	return nil
+}
+
+// ensureEmailAvailable はメールアドレスがexceptID以外のユーザーに使われていないことを確認する
+func (u *ProvisioningUsecase) ensureEmailAvailable(ctx context.Context, email, exceptID string) error {
+	existing, err := u.userRepo.FindByEmail(ctx, email)
+	if err != nil && !errors.Is(err, repository.ErrUserNotFound) {
+		return fmt.Errorf("failed to find user: %w", err)
+	}
+	if existing != nil && existing.ID != exceptID {
+		return fmt.Errorf("user %s already exists: %w", email, model.ErrConflict)
+	}
+	return nil
+}
+
+// ListGroups はグループを一覧し、総件数とあわせて返す（displayNameを指定した場合は一致するグループのみ）
+func (u *ProvisioningUsecase) ListGroups(ctx context.Context, displayName string, startIndex, count int) ([]*model.Group, int, error) {
+	offset, limit := pageOffset(startIndex, count)
+	groups, total, err := u.groupRepo.List(ctx, displayName, offset, limit)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list groups: %w", err)
+	}
+	return groups, total, nil
+}
+
+// GetGroup はグループを取得する
+func (u *ProvisioningUsecase) GetGroup(ctx context.Context, id string) (*model.Group, error) {
+	if err := validateResourceID(id); err != nil {
+		return nil, err
+	}
+	group, err := u.groupRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find group: %w", err)
+	}
+	return group, nil
+}
+
+// CreateGroup はグループをメンバーとあわせて作成する（同じ表示名のグループがある場合はErrConflict）
+func (u *ProvisioningUsecase) CreateGroup(ctx context.Context, displayName, externalID string, memberIDs []string) (*model.Group, error) {
+	if err := u.ensureGroupNameAvailable(ctx, displayName, ""); err != nil {
+		return nil, err
+	}
+	if err := u.ensureUsersExist(ctx, memberIDs); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	group := &model.Group{
+		ID:          uuid.New().String(),
+		DisplayName: displayName,
+		ExternalID:  externalID,
+		MemberIDs:   compactIDs(memberIDs),
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	if err := u.groupRepo.Create(ctx, group); err != nil {
+		return nil, fmt.Errorf("failed to create group: %w", err)
+	}
+
+	u.logger.InfoContext(ctx, "group provisioned", "group_id", group.ID, "members", len(group.MemberIDs))
+	return group, nil
+}
+
+// PatchGroup はグループの表示名・外部ID・メンバーを部分更新する
+func (u *ProvisioningUsecase) PatchGroup(ctx context.Context, id string, patch model.GroupPatch) (*model.Group, error) {
+	group, err := u.GetGroup(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if patch.DisplayName != nil || patch.ExternalID != nil {
+		if patch.DisplayName != nil && *patch.DisplayName != group.DisplayName {
+			if err := u.ensureGroupNameAvailable(ctx, *patch.DisplayName, id); err != nil {
+				return nil, err
+			}
+			group.DisplayName = *patch.DisplayName
+		}
+		if patch.ExternalID != nil {
+			group.ExternalID = *patch.ExternalID
+		}
+		if err := u.groupRepo.Update(ctx, group); err != nil {
+			return nil, fmt.Errorf("failed to update group: %w", err)
+		}
+	}
+
+	if patch.Members != nil {
+		if err := u.ensureUsersExist(ctx, *patch.Members); err != nil {
+			return nil, err
+		}
+		if err := u.groupRepo.ReplaceMembers(ctx, id, compactIDs(*patch.Members)); err != nil {
+			return nil, fmt.Errorf("failed to replace group members: %w", err)
+		}
+	}
+	if len(patch.AddMemberIDs) > 0 {
+		if err := u.ensureUsersExist(ctx, patch.AddMemberIDs); err != nil {
+			return nil, err
+		}
+		if err := u.groupRepo.AddMembers(ctx, id, compactIDs(patch.AddMemberIDs)); err != nil {
+			return nil, fmt.Errorf("failed to add group members: %w", err)
+		}
+	}
+	if len(patch.RemoveMemberIDs) > 0 {
+		if err := u.groupRepo.RemoveMembers(ctx, id, compactIDs(patch.RemoveMemberIDs)); err != nil {
+			return nil, fmt.Errorf("failed to remove group members: %w", err)
+		}
+	}
+
+	return u.GetGroup(ctx, id)
+}
+
+// DeleteGroup はグループを削除する（メンバーのユーザーは削除しない）
+func (u *ProvisioningUsecase) DeleteGroup(ctx context.Context, id string) error {
+	if err := validateResourceID(id); err != nil {
+		return err
+	}
+	if err := u.groupRepo.Delete(ctx, id); err != nil {
+		return fmt.Errorf("failed to delete group: %w", err)
+	}
+	u.logger.InfoContext(ctx, "provisioned group deleted", "group_id", id)
+	return nil
+}
+
+// ensureGroupNameAvailable は表示名がexceptID以外のグループに使われていないことを確認する
+func (u *ProvisioningUsecase) ensureGroupNameAvailable(ctx context.Context, displayName, exceptID string) error {
+	if displayName == "" {
+		return fmt.Errorf("displayName is required: %w", model.ErrInvalidInput)
+	}
+	groups, _, err := u.groupRepo.List(ctx, displayName, 0, 1)
+	if err != nil {
+		return fmt.Errorf("failed to find group: %w", err)
+	}
+	if len(groups) > 0 && groups[0].ID != exceptID {
+		return fmt.Errorf("group %s already exists: %w", displayName, model.ErrConflict)
+	}
+	return nil
+}
+
+// ensureUsersExist はメンバーに指定されたユーザーがすべて存在することを確認する
+func (u *ProvisioningUsecase) ensureUsersExist(ctx context.Context, userIDs []string) error {
+	for _, id := range compactIDs(userIDs) {
+		if _, err := uuid.Parse(id); err != nil {
+			return fmt.Errorf("invalid member %s: %w", id, model.ErrInvalidInput)
+		}
+		if _, err := u.userRepo.FindByID(ctx, id); err != nil {
+			if errors.Is(err, model.ErrNotFound) {
+				return fmt.Errorf("member %s not found: %w", id, model.ErrInvalidInput)
+			}
+			return fmt.Errorf("failed to find user: %w", err)
+		}
+	}
+	return nil
+}
+
+// compactIDs は重複を除いたIDを返す
+func compactIDs(ids []string) []string {
+	out := slices.Clone(ids)
+	slices.Sort(out)
+	return slices.Compact(out)
+}
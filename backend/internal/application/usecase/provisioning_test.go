@@ -0,0 +1,173 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/sikigasa/github-task-controller/backend/internal/domain/model"
+	"github.com/sikigasa/github-task-controller/backend/internal/domain/repository"
+)
+
+const (
+	testProvisionedUserID  = "0190a6b8-0000-7000-8000-000000000011"
+	testProvisionedOtherID = "0190a6b8-0000-7000-8000-000000000012"
+)
+
+// memoryUsers はユーザーをメモリに保存するUserRepository
+type memoryUsers struct {
+	repository.UserRepository
+	users map[string]*model.User
+	// lookups はFindByIDで検索したID
+	lookups []string
+}
+
+func (m *memoryUsers) FindByID(_ context.Context, id string) (*model.User, error) {
+	m.lookups = append(m.lookups, id)
+	user, ok := m.users[id]
+	if !ok {
+		return nil, repository.ErrUserNotFound
+	}
+	found := *user
+	return &found, nil
+}
+
+func (m *memoryUsers) FindByEmail(_ context.Context, email string) (*model.User, error) {
+	for _, user := range m.users {
+		if user.Email == email {
+			found := *user
+			return &found, nil
+		}
+	}
+	return nil, repository.ErrUserNotFound
+}
+
+func (m *memoryUsers) Create(_ context.Context, user *model.User) error {
+	stored := *user
+	m.users[user.ID] = &stored
+	return nil
+}
+
+func (m *memoryUsers) Update(_ context.Context, user *model.User) error {
+	stored := *user
+	m.users[user.ID] = &stored
+	return nil
+}
+
+// revokedUserSessions はDeleteByUserIDで削除したユーザーのIDを記録する
+type revokedUserSessions struct {
+	repository.UserSessionRepository
+	userIDs []string
+}
+
+func (s *revokedUserSessions) DeleteByUserID(_ context.Context, userID string) error {
+	s.userIDs = append(s.userIDs, userID)
+	return nil
+}
+
+func newTestProvisioningUsecase() (*ProvisioningUsecase, *memoryUsers, *revokedUserSessions) {
+	users := &memoryUsers{users: map[string]*model.User{
+		testProvisionedUserID:  {ID: testProvisionedUserID, Email: "user@example.com", Name: "User"},
+		testProvisionedOtherID: {ID: testProvisionedOtherID, Email: "other@example.com", Name: "Other"},
+	}}
+	sessions := &revokedUserSessions{}
+	return NewProvisioningUsecase(users, nil, sessions, discardLogger()), users, sessions
+}
+
+func TestProvisioningUsecaseDeactivateRevokesSessions(t *testing.T) {
+	ctx := context.Background()
+	u, users, sessions := newTestProvisioningUsecase()
+
+	user, err := u.UpdateUser(ctx, testProvisionedUserID, "user@example.com", "", false)
+	if err != nil {
+		t.Fatalf("UpdateUser() error = %v", err)
+	}
+	if user.Active() || users.users[testProvisionedUserID].Active() {
+		t.Error("user is still active after deactivation")
+	}
+	if !slices.Equal(sessions.userIDs, []string{testProvisionedUserID}) {
+		t.Errorf("revoked sessions = %v, want [%s]", sessions.userIDs, testProvisionedUserID)
+	}
+
+	// 無効化済みのユーザーへの同じ更新ではセッションを削除し直さない
+	if _, err := u.UpdateUser(ctx, testProvisionedUserID, "user@example.com", "", false); err != nil {
+		t.Fatalf("UpdateUser() error = %v", err)
+	}
+	if len(sessions.userIDs) != 1 {
+		t.Errorf("revoked sessions = %v, want only the first deactivation", sessions.userIDs)
+	}
+
+	// 再び有効にした場合はログインできる状態に戻す
+	user, err = u.UpdateUser(ctx, testProvisionedUserID, "user@example.com", "", true)
+	if err != nil {
+		t.Fatalf("UpdateUser() error = %v", err)
+	}
+	if !user.Active() {
+		t.Error("user is not active after reactivation")
+	}
+}
+
+func TestProvisioningUsecaseCreateInactiveUser(t *testing.T) {
+	u, _, _ := newTestProvisioningUsecase()
+
+	user, err := u.CreateUser(context.Background(), "new@example.com", "", false)
+	if err != nil {
+		t.Fatalf("CreateUser() error = %v", err)
+	}
+	if user.Active() {
+		t.Error("user created with active=false is active")
+	}
+	if user.Name != "new" {
+		t.Errorf("Name = %q, want %q", user.Name, "new")
+	}
+}
+
+func TestProvisioningUsecaseRejectsEmailConflict(t *testing.T) {
+	ctx := context.Background()
+	u, _, _ := newTestProvisioningUsecase()
+
+	// 他のユーザーのメールアドレスへの変更・作成でアカウントを乗っ取れない
+	if _, err := u.UpdateUser(ctx, testProvisionedUserID, "other@example.com", "", true); !errors.Is(err, model.ErrConflict) {
+		t.Errorf("UpdateUser() error = %v, want ErrConflict", err)
+	}
+	if _, err := u.CreateUser(ctx, "other@example.com", "", true); !errors.Is(err, model.ErrConflict) {
+		t.Errorf("CreateUser() error = %v, want ErrConflict", err)
+	}
+	for _, email := range []string{"", "not-an-email"} {
+		if _, err := u.CreateUser(ctx, email, "", true); !errors.Is(err, model.ErrInvalidInput) {
+			t.Errorf("CreateUser(%q) error = %v, want ErrInvalidInput", email, err)
+		}
+	}
+}
+
+func TestProvisioningUsecaseRejectsInvalidResourceID(t *testing.T) {
+	ctx := context.Background()
+	u, users, _ := newTestProvisioningUsecase()
+
+	for _, id := range []string{"", "not-a-uuid", "../users", testProvisionedUserID + "' OR '1'='1"} {
+		if _, err := u.GetUser(ctx, id); !errors.Is(err, model.ErrNotFound) {
+			t.Errorf("GetUser(%q) error = %v, want ErrNotFound", id, err)
+		}
+		if err := u.DeleteUser(ctx, id); !errors.Is(err, model.ErrNotFound) {
+			t.Errorf("DeleteUser(%q) error = %v, want ErrNotFound", id, err)
+		}
+	}
+	if len(users.lookups) != 0 {
+		t.Errorf("user ids passed to repository = %v, want none", users.lookups)
+	}
+}
+
+func TestProvisioningUsecaseRejectsUnknownGroupMember(t *testing.T) {
+	u, _, _ := newTestProvisioningUsecase()
+
+	// 存在しないユーザー・UUIDでないIDはメンバーにできない
+	for _, id := range []string{"0190a6b8-0000-7000-8000-0000000000ff", "not-a-uuid"} {
+		if err := u.ensureUsersExist(context.Background(), []string{testProvisionedUserID, id}); !errors.Is(err, model.ErrInvalidInput) {
+			t.Errorf("ensureUsersExist(%q) error = %v, want ErrInvalidInput", id, err)
+		}
+	}
+	if err := u.ensureUsersExist(context.Background(), []string{testProvisionedUserID, testProvisionedOtherID, testProvisionedUserID}); err != nil {
+		t.Errorf("ensureUsersExist() error = %v", err)
+	}
+}
@@ -0,0 +1,150 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"maps"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sikigasa/github-task-controller/backend/internal/domain/model"
+	"github.com/sikigasa/github-task-controller/backend/internal/domain/repository"
+	"github.com/sikigasa/github-task-controller/backend/internal/infrastructure/push"
+)
+
+// PushUsecase はモバイル・PWAのクライアントへのプッシュ通知に関するユースケース
+// 通知は設定で種類ごとに止められ、配信サービスが無効と判断したトークンの端末は登録を削除する
+type PushUsecase struct {
+	deviceRepo   repository.PushDeviceRepository
+	settingsRepo repository.SettingsRepository
+	projectRepo  repository.ProjectRepository
+	sender       push.Sender
+	logger       *slog.Logger
+}
+
+// NewPushUsecase は新しいPushUsecaseを作成する
+func NewPushUsecase(
+	deviceRepo repository.PushDeviceRepository,
+	settingsRepo repository.SettingsRepository,
+	projectRepo repository.ProjectRepository,
+	sender push.Sender,
+	logger *slog.Logger,
+) *PushUsecase {
+	return &PushUsecase{
+		deviceRepo:   deviceRepo,
+		settingsRepo: settingsRepo,
+		projectRepo:  projectRepo,
+		sender:       sender,
+		logger:       logger,
+	}
+}
+
+// RegisterDevice はプッシュ通知を受け取る端末を登録する（登録済みのトークンの場合は名前と登録日時を更新する）
+func (u *PushUsecase) RegisterDevice(ctx context.Context, userID string, req *model.RegisterPushDeviceRequest) (*model.PushDevice, error) {
+	now := time.Now()
+	device, err := u.deviceRepo.Upsert(ctx, &model.PushDevice{
+		ID:        uuid.New().String(),
+		UserID:    userID,
+		Platform:  req.Platform,
+		Token:     req.Token,
+		Name:      req.Name,
+		CreatedAt: now,
+		UpdatedAt: now,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to register push device: %w", err)
+	}
+	return device, nil
+}
+
+// ListDevices はユーザーが登録した端末を一覧する
+func (u *PushUsecase) ListDevices(ctx context.Context, userID string) ([]*model.PushDevice, error) {
+	devices, err := u.deviceRepo.FindByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find push devices: %w", err)
+	}
+	return devices, nil
+}
+
+// DeleteDevice は端末の登録を削除する（ログアウト時にクライアントから呼び出す）
+func (u *PushUsecase) DeleteDevice(ctx context.Context, userID, id string) error {
+	if err := u.deviceRepo.Delete(ctx, userID, id); err != nil {
+		return fmt.Errorf("failed to delete push device: %w", err)
+	}
+	return nil
+}
+
+// Notify はユーザーの端末に通知を送信し、送信できた端末の数を返す
+// 設定で通知の種類を無効にしている場合は送信しない。端末ごとの送信の失敗はログに残してエラーにしない
+func (u *PushUsecase) Notify(ctx context.Context, userID string, notification *model.PushNotification) (int, error) {
+	settings, err := findSettings(ctx, u.settingsRepo, userID)
+	if err != nil {
+		return 0, err
+	}
+	if !settings.PushEnabled(notification.Kind) {
+		return 0, nil
+	}
+
+	devices, err := u.deviceRepo.FindDeliverableByUserID(ctx, userID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to find push devices: %w", err)
+	}
+
+	data := map[string]string{"kind": string(notification.Kind)}
+	maps.Copy(data, notification.Data)
+
+	sent := 0
+	for _, device := range devices {
+		err := u.sender.Send(ctx, push.Message{
+			Platform:   push.Platform(device.Platform),
+			Token:      device.Token,
+			Title:      notification.Title,
+			Body:       notification.Body,
+			Data:       data,
+			CollapseID: notification.CollapseID,
+		})
+		switch {
+		case err == nil:
+			sent++
+		case errors.Is(err, push.ErrInvalidToken):
+			u.logger.InfoContext(ctx, "push token is no longer valid, removing device", "reason", err, "device_id", device.ID, "user_id", userID)
+			if err := u.deviceRepo.Delete(ctx, userID, device.ID); err != nil && !errors.Is(err, model.ErrNotFound) {
+				u.logger.ErrorContext(ctx, "failed to remove invalid push device", "error", err, "device_id", device.ID)
+			}
+		default:
+			u.logger.WarnContext(ctx, "failed to send push notification", "error", err, "device_id", device.ID, "user_id", userID, "kind", notification.Kind)
+		}
+	}
+
+	return sent, nil
+}
+
+// NotifyStatusChanged はタスクのステータスの変更（task.status_changed）をプロジェクトの所有者に通知するイベントバスの購読者
+// 送信に失敗した通知は再送しない（再配信された場合はイベントのIDで端末の前の通知を置き換える）
+func (u *PushUsecase) NotifyStatusChanged(ctx context.Context, event *model.OutboxEvent) error {
+	var payload model.TaskStatusChangedPayload
+	if err := json.Unmarshal(event.Payload, &payload); err != nil {
+		return fmt.Errorf("failed to decode %s payload: %w", event.Type, err)
+	}
+	if payload.Task == nil {
+		return nil
+	}
+
+	project, err := u.projectRepo.FindByID(ctx, payload.Task.ProjectID)
+	if errors.Is(err, model.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to find project: %w", err)
+	}
+
+	notification := model.NewTaskStatusPushNotification(event.ID, project.Title, payload.Task, payload.Reopened)
+	if _, err := u.Notify(ctx, project.UserID, notification); err != nil {
+		u.logger.ErrorContext(ctx, "failed to notify task status change", "error", err, "event_id", event.ID, "task_id", payload.Task.ID)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,242 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/sikigasa/github-task-controller/backend/internal/domain/model"
+	"github.com/sikigasa/github-task-controller/backend/internal/domain/repository"
+	"github.com/sikigasa/github-task-controller/backend/internal/infrastructure/mail"
+)
+
+// reminderWindowSlack はリマインダーの対象を探す終了日の範囲の前後の余裕
+// ユーザーのタイムゾーンの日付の境界はUTCから最大で1日ずれるため、範囲を広めに取ってからユーザーごとに判定する
+const reminderWindowSlack = 24 * time.Hour
+
+// ReminderChannel はリマインダーの通知先
+// 通知先を追加する場合はこのインターフェースを実装し、NewReminderUsecaseに渡す
+type ReminderChannel interface {
+	// Name は送信の記録に使う通知先の名前
+	Name() model.ReminderChannel
+	// Enabled はユーザーが設定で通知先を有効にしているかを返す
+	Enabled(settings *model.Settings) bool
+	// Send はリマインダーを送信する（エラーを返した場合は次の実行で改めて送信する）
+	Send(ctx context.Context, settings *model.Settings, reminder *model.DueReminder) error
+}
+
+// ReminderUsecase は終了日が近いタスクのリマインダーに関するユースケース
+// タスクごと・通知先ごとに終了日の1回だけ送信し、終了日を変更したタスクには改めて送信する
+type ReminderUsecase struct {
+	reminderRepo repository.TaskReminderRepository
+	taskRepo     repository.TaskRepository
+	projectRepo  repository.ProjectRepository
+	settingsRepo repository.SettingsRepository
+	channels     []ReminderChannel
+	sendHour     int
+	logger       *slog.Logger
+}
+
+// NewReminderUsecase は新しいReminderUsecaseを作成する
+// sendHourはリマインダーの送信を始める時刻（ユーザーのタイムゾーンでの時）
+func NewReminderUsecase(
+	reminderRepo repository.TaskReminderRepository,
+	taskRepo repository.TaskRepository,
+	projectRepo repository.ProjectRepository,
+	settingsRepo repository.SettingsRepository,
+	channels []ReminderChannel,
+	sendHour int,
+	logger *slog.Logger,
+) *ReminderUsecase {
+	return &ReminderUsecase{
+		reminderRepo: reminderRepo,
+		taskRepo:     taskRepo,
+		projectRepo:  projectRepo,
+		settingsRepo: settingsRepo,
+		channels:     channels,
+		sendHour:     sendHour,
+		logger:       logger,
+	}
+}
+
+// SendDueReminders は終了日が近い未完了のタスクのリマインダーを、プロジェクトの所有者が有効にしている通知先に送信する
+// 終了日がユーザーのタイムゾーンで今日から設定のreminder_days_before日後までのタスクが対象で、今日のsendHour時以降に送信する
+// タスク・通知先ごとの失敗はログに記録して処理を続ける
+func (u *ReminderUsecase) SendDueReminders(ctx context.Context, now time.Time) error {
+	from := now.Add(-reminderWindowSlack)
+	before := now.AddDate(0, 0, model.MaxReminderDaysBefore+1).Add(reminderWindowSlack)
+	tasks, err := u.taskRepo.FindDueBetween(ctx, from, before)
+	if err != nil {
+		return fmt.Errorf("failed to find due tasks: %w", err)
+	}
+
+	projects := make(map[string]*model.Project)
+	settingsByUser := make(map[string]*model.Settings)
+	sent := 0
+	for _, task := range tasks {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		project, ok := projects[task.ProjectID]
+		if !ok {
+			project, err = u.projectRepo.FindByID(ctx, task.ProjectID)
+			if err != nil && !errors.Is(err, model.ErrNotFound) {
+				u.logger.ErrorContext(ctx, "failed to find project for reminder", "error", err, "project_id", task.ProjectID)
+				continue
+			}
+			projects[task.ProjectID] = project
+		}
+		if project == nil {
+			continue
+		}
+
+		settings, ok := settingsByUser[project.UserID]
+		if !ok {
+			settings, err = findSettings(ctx, u.settingsRepo, project.UserID)
+			if err != nil {
+				u.logger.ErrorContext(ctx, "failed to find settings for reminder", "error", err, "user_id", project.UserID)
+				continue
+			}
+			settingsByUser[project.UserID] = settings
+		}
+
+		loc := settings.Location()
+		if now.Before(model.StartOfDay(now, loc).Add(time.Duration(u.sendHour) * time.Hour)) {
+			continue
+		}
+		reminder, ok := model.NewDueReminder(now, loc, settings.ReminderDaysBefore, project.UserID, project.Title, task)
+		if !ok {
+			continue
+		}
+
+		sent += u.sendReminder(ctx, settings, reminder, now)
+	}
+
+	if sent > 0 {
+		u.logger.InfoContext(ctx, "task reminders sent", "count", sent)
+	}
+	return nil
+}
+
+// sendReminder はユーザーが有効にしている通知先のうち、未送信のものにリマインダーを送信し、送信した数を返す
+// 送信の前に記録し、送信に失敗した場合は記録を削除して次の実行で改めて送信する
+func (u *ReminderUsecase) sendReminder(ctx context.Context, settings *model.Settings, reminder *model.DueReminder, now time.Time) int {
+	task := reminder.Task
+	sent := 0
+	for _, channel := range u.channels {
+		if !channel.Enabled(settings) {
+			continue
+		}
+
+		claimed, err := u.reminderRepo.Claim(ctx, &model.TaskReminder{
+			TaskID:  task.ID,
+			UserID:  reminder.UserID,
+			Channel: channel.Name(),
+			DueDate: *task.EndDate,
+			SentAt:  now,
+		})
+		if err != nil {
+			u.logger.ErrorContext(ctx, "failed to record task reminder", "error", err, "task_id", task.ID, "channel", channel.Name())
+			continue
+		}
+		if !claimed {
+			continue
+		}
+
+		if err := channel.Send(ctx, settings, reminder); err != nil {
+			u.logger.WarnContext(ctx, "failed to send task reminder", "error", err, "task_id", task.ID, "user_id", reminder.UserID, "channel", channel.Name())
+			if err := u.reminderRepo.Release(ctx, task.ID, channel.Name(), *task.EndDate); err != nil {
+				u.logger.ErrorContext(ctx, "failed to release task reminder", "error", err, "task_id", task.ID, "channel", channel.Name())
+			}
+			continue
+		}
+		sent++
+	}
+	return sent
+}
+
+// emailReminderChannel はリマインダーをユーザーのメールアドレスに送信する通知先
+type emailReminderChannel struct {
+	userRepo repository.UserRepository
+	sender   mail.Sender
+}
+
+// NewEmailReminderChannel はリマインダーをメールで送信する通知先を作成する（設定のemail_remindersで有効にする）
+func NewEmailReminderChannel(userRepo repository.UserRepository, sender mail.Sender) ReminderChannel {
+	return &emailReminderChannel{userRepo: userRepo, sender: sender}
+}
+
+func (c *emailReminderChannel) Name() model.ReminderChannel {
+	return model.ReminderChannelEmail
+}
+
+func (c *emailReminderChannel) Enabled(settings *model.Settings) bool {
+	return settings.EmailReminders
+}
+
+func (c *emailReminderChannel) Send(ctx context.Context, settings *model.Settings, reminder *model.DueReminder) error {
+	user, err := c.userRepo.FindByID(ctx, reminder.UserID)
+	if err != nil {
+		return fmt.Errorf("failed to find user: %w", err)
+	}
+	// 無効化したユーザーと、配送されないメールアドレスのゲストユーザーには送信しない
+	if !user.Active() || user.IsGuest() {
+		return nil
+	}
+
+	subject, body := reminder.Render(settings.Location())
+	if err := c.sender.Send(ctx, mail.Message{To: user.Email, Subject: subject, Body: body}); err != nil {
+		return fmt.Errorf("failed to send reminder mail: %w", err)
+	}
+	return nil
+}
+
+// webhookReminderChannel はリマインダーをtask.reminderイベントとして発行し、プロジェクトのWebhookの送信先に通知する通知先
+// 送信先への署名・再試行は他のイベントと同じくWebhookEndpointUsecaseが行う
+type webhookReminderChannel struct {
+	events EventBus
+}
+
+// NewWebhookReminderChannel はリマインダーをプロジェクトのWebhookの送信先に通知する通知先を作成する（設定のwebhook_remindersで有効にする）
+func NewWebhookReminderChannel(events EventBus) ReminderChannel {
+	return &webhookReminderChannel{events: events}
+}
+
+func (c *webhookReminderChannel) Name() model.ReminderChannel {
+	return model.ReminderChannelWebhook
+}
+
+func (c *webhookReminderChannel) Enabled(settings *model.Settings) bool {
+	return settings.WebhookReminders
+}
+
+func (c *webhookReminderChannel) Send(ctx context.Context, _ *model.Settings, reminder *model.DueReminder) error {
+	return c.events.Publish(ctx, model.EventTaskReminder, model.AggregateTask, reminder.Task.ID, reminder.Payload())
+}
+
+// pushReminderChannel はリマインダーをユーザーが登録した端末にプッシュ通知する通知先
+type pushReminderChannel struct {
+	push *PushUsecase
+}
+
+// NewPushReminderChannel はリマインダーをプッシュ通知する通知先を作成する（設定のpush_remindersで無効にできる）
+func NewPushReminderChannel(push *PushUsecase) ReminderChannel {
+	return &pushReminderChannel{push: push}
+}
+
+func (c *pushReminderChannel) Name() model.ReminderChannel {
+	return model.ReminderChannelPush
+}
+
+func (c *pushReminderChannel) Enabled(settings *model.Settings) bool {
+	return settings.PushEnabled(model.PushKindReminder)
+}
+
+func (c *pushReminderChannel) Send(ctx context.Context, _ *model.Settings, reminder *model.DueReminder) error {
+	// 端末ごとの送信の失敗はNotifyがログに残すため、送信できた端末がなくても送信済みとする
+	_, err := c.push.Notify(ctx, reminder.UserID, reminder.PushNotification())
+	return err
+}
@@ -0,0 +1,319 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/sikigasa/github-task-controller/backend/internal/domain/model"
+	"github.com/sikigasa/github-task-controller/backend/internal/domain/repository"
+)
+
+const (
+	// MaxIterationWeeks は1期間の最大週数
+	MaxIterationWeeks = 4
+	// MaxIterations は集計する最大期間数
+	MaxIterations = 26
+	// MaxCumulativeFlowDays は累積フロー図で集計する最大日数
+	MaxCumulativeFlowDays = 365
+	// MaxDueSoonDays は期限間近とみなす最大日数
+	MaxDueSoonDays = 30
+	// MaxFlowTimeDays はリードタイム・サイクルタイムで集計する最大日数
+	MaxFlowTimeDays = 365
+	// MaxStuckDays は滞留とみなす閾値の最大日数
+	MaxStuckDays = 365
+	// MaxStatsDays はユーザー統計で集計する最大日数
+	MaxStatsDays = 365
+	// StatsCacheMinDays はユーザー統計の結果をキャッシュする最小の日数（これより短い期間は毎回集計する）
+	StatsCacheMinDays = 90
+	// StatsCacheTTL はユーザー統計のキャッシュの有効期間
+	StatsCacheTTL = 10 * time.Minute
+)
+
+// ReportUsecase はプロジェクトのレポートに関するユースケース
+type ReportUsecase struct {
+	projectRepo     repository.ProjectRepository
+	taskRepo        repository.TaskRepository
+	statusEventRepo repository.TaskStatusEventRepository
+	settingsRepo    repository.SettingsRepository
+	logger          *slog.Logger
+
+	statsMu    sync.Mutex
+	statsCache map[statsCacheKey]*statsCacheEntry
+}
+
+// statsCacheKey はユーザー統計のキャッシュのキー
+type statsCacheKey struct {
+	userID string
+	days   int
+}
+
+// statsCacheEntry はキャッシュされたユーザー統計
+type statsCacheEntry struct {
+	stats     *model.UserStats
+	expiresAt time.Time
+}
+
+// NewReportUsecase は新しいReportUsecaseを作成する
+func NewReportUsecase(
+	projectRepo repository.ProjectRepository,
+	taskRepo repository.TaskRepository,
+	statusEventRepo repository.TaskStatusEventRepository,
+	settingsRepo repository.SettingsRepository,
+	logger *slog.Logger,
+) *ReportUsecase {
+	return &ReportUsecase{
+		projectRepo:     projectRepo,
+		taskRepo:        taskRepo,
+		statusEventRepo: statusEventRepo,
+		settingsRepo:    settingsRepo,
+		logger:          logger,
+		statsCache:      make(map[statsCacheKey]*statsCacheEntry),
+	}
+}
+
+// GetVelocity は期間（weeks週単位）ごとの完了タスク数・見積もりをiterations期間分集計する
+// スプリントのモデルがないため、期間は設定の週の開始曜日・タイムゾーンに揃えた固定長とする
+func (u *ReportUsecase) GetVelocity(ctx context.Context, userID, projectID string, weeks, iterations int) (*model.VelocityReport, error) {
+	if weeks < 1 || weeks > MaxIterationWeeks {
+		return nil, fmt.Errorf("iteration_weeks must be between 1 and %d: %w", MaxIterationWeeks, model.ErrInvalidInput)
+	}
+	if iterations < 1 || iterations > MaxIterations {
+		return nil, fmt.Errorf("iterations must be between 1 and %d: %w", MaxIterations, model.ErrInvalidInput)
+	}
+
+	project, err := u.authorizeProject(ctx, userID, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	settings, err := findSettings(ctx, u.settingsRepo, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	doneTasks, err := u.taskRepo.FindByProjectID(ctx, projectID, model.TaskFilter{Statuses: []model.TaskStatus{model.TaskStatusDone}}, model.ListOptions{})
+	if err != nil {
+		u.logger.ErrorContext(ctx, "failed to load tasks for velocity", "error", err, "project_id", projectID)
+		return nil, fmt.Errorf("failed to load tasks for velocity: %w", err)
+	}
+
+	events, err := u.statusEventRepo.FindByProjectID(ctx, projectID)
+	if err != nil {
+		u.logger.ErrorContext(ctx, "failed to load status events for velocity", "error", err, "project_id", projectID)
+		return nil, fmt.Errorf("failed to load status events for velocity: %w", err)
+	}
+
+	now := time.Now()
+	periods := model.NewIterations(now, settings.Location(), settings.WeekStartDay, weeks, iterations)
+	return model.NewVelocityReport(project, weeks, periods, now, doneTasks, events), nil
+}
+
+// GetCumulativeFlow は今日までのdays日分の日ごとのステータス別タスク数を集計する
+// 日付の区切りは設定のタイムゾーンに従う
+func (u *ReportUsecase) GetCumulativeFlow(ctx context.Context, userID, projectID string, days int) (*model.CumulativeFlow, error) {
+	if days < 1 || days > MaxCumulativeFlowDays {
+		return nil, fmt.Errorf("days must be between 1 and %d: %w", MaxCumulativeFlowDays, model.ErrInvalidInput)
+	}
+
+	if _, err := u.authorizeProject(ctx, userID, projectID); err != nil {
+		return nil, err
+	}
+
+	settings, err := findSettings(ctx, u.settingsRepo, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	tasks, err := u.taskRepo.FindByProjectID(ctx, projectID, model.TaskFilter{}, model.ListOptions{})
+	if err != nil {
+		u.logger.ErrorContext(ctx, "failed to load tasks for cumulative flow", "error", err, "project_id", projectID)
+		return nil, fmt.Errorf("failed to load tasks for cumulative flow: %w", err)
+	}
+
+	events, err := u.statusEventRepo.FindByProjectID(ctx, projectID)
+	if err != nil {
+		u.logger.ErrorContext(ctx, "failed to load status events for cumulative flow", "error", err, "project_id", projectID)
+		return nil, fmt.Errorf("failed to load status events for cumulative flow: %w", err)
+	}
+
+	return model.NewCumulativeFlow(projectID, settings.Location(), time.Now(), days, tasks, events), nil
+}
+
+// GetFlowTime は直近days日間に完了したタスクのリードタイム・サイクルタイムの分布を集計する
+func (u *ReportUsecase) GetFlowTime(ctx context.Context, userID, projectID string, days int) (*model.FlowTimeReport, error) {
+	if days < 1 || days > MaxFlowTimeDays {
+		return nil, fmt.Errorf("days must be between 1 and %d: %w", MaxFlowTimeDays, model.ErrInvalidInput)
+	}
+
+	if _, err := u.authorizeProject(ctx, userID, projectID); err != nil {
+		return nil, err
+	}
+
+	doneTasks, err := u.taskRepo.FindByProjectID(ctx, projectID, model.TaskFilter{Statuses: []model.TaskStatus{model.TaskStatusDone}}, model.ListOptions{})
+	if err != nil {
+		u.logger.ErrorContext(ctx, "failed to load tasks for flow time", "error", err, "project_id", projectID)
+		return nil, fmt.Errorf("failed to load tasks for flow time: %w", err)
+	}
+
+	events, err := u.statusEventRepo.FindByProjectID(ctx, projectID)
+	if err != nil {
+		u.logger.ErrorContext(ctx, "failed to load status events for flow time", "error", err, "project_id", projectID)
+		return nil, fmt.Errorf("failed to load status events for flow time: %w", err)
+	}
+
+	now := time.Now()
+	return model.NewFlowTimeReport(projectID, now.AddDate(0, 0, -days), now, doneTasks, events), nil
+}
+
+// GetTimeInStatus はプロジェクトのタスクのステータスごとの滞留時間を集計する
+// 進行中のままstuckDays日以上経過したタスクを滞留タスクとして抽出する
+func (u *ReportUsecase) GetTimeInStatus(ctx context.Context, userID, projectID string, stuckDays int) (*model.TimeInStatusReport, error) {
+	if stuckDays < 1 || stuckDays > MaxStuckDays {
+		return nil, fmt.Errorf("stuck_days must be between 1 and %d: %w", MaxStuckDays, model.ErrInvalidInput)
+	}
+
+	if _, err := u.authorizeProject(ctx, userID, projectID); err != nil {
+		return nil, err
+	}
+
+	tasks, err := u.taskRepo.FindByProjectID(ctx, projectID, model.TaskFilter{}, model.ListOptions{})
+	if err != nil {
+		u.logger.ErrorContext(ctx, "failed to load tasks for time in status", "error", err, "project_id", projectID)
+		return nil, fmt.Errorf("failed to load tasks for time in status: %w", err)
+	}
+
+	events, err := u.statusEventRepo.FindByProjectID(ctx, projectID)
+	if err != nil {
+		u.logger.ErrorContext(ctx, "failed to load status events for time in status", "error", err, "project_id", projectID)
+		return nil, fmt.Errorf("failed to load status events for time in status: %w", err)
+	}
+
+	stuckAfter := time.Duration(stuckDays) * 24 * time.Hour
+	return model.NewTimeInStatusReport(projectID, time.Now(), stuckAfter, tasks, events), nil
+}
+
+// GetOverdueReport はユーザーの全プロジェクトの期限切れ・期限間近（dueSoonDays日以内）のタスクを集計する
+// 日付の境界は設定のタイムゾーンで判定する。リマインダー通知からも使用する
+func (u *ReportUsecase) GetOverdueReport(ctx context.Context, userID string, dueSoonDays int) (*model.OverdueReport, error) {
+	if dueSoonDays < 0 || dueSoonDays > MaxDueSoonDays {
+		return nil, fmt.Errorf("due_soon_days must be between 0 and %d: %w", MaxDueSoonDays, model.ErrInvalidInput)
+	}
+
+	settings, err := findSettings(ctx, u.settingsRepo, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	projects, err := u.projectRepo.FindByUserID(ctx, userID, model.ListOptions{})
+	if err != nil {
+		u.logger.ErrorContext(ctx, "failed to load projects for overdue report", "error", err, "user_id", userID)
+		return nil, fmt.Errorf("failed to load projects for overdue report: %w", err)
+	}
+
+	projectIDs := make([]string, 0, len(projects))
+	for _, p := range projects {
+		projectIDs = append(projectIDs, p.ID)
+	}
+
+	now := time.Now()
+	loc := settings.Location()
+	tasks, err := u.taskRepo.FindDueByProjectIDs(ctx, projectIDs, model.OverdueReportDueBefore(now, loc, dueSoonDays))
+	if err != nil {
+		u.logger.ErrorContext(ctx, "failed to load tasks for overdue report", "error", err, "user_id", userID)
+		return nil, fmt.Errorf("failed to load tasks for overdue report: %w", err)
+	}
+
+	return model.NewOverdueReport(now, loc, dueSoonDays, projects, tasks), nil
+}
+
+// GetUserStats はユーザーの全プロジェクトの今日までのdays日分の完了数・連続日数・完了までの時間を集計する
+// StatsCacheMinDays日以上の期間はイベントの読み込みが重いため、StatsCacheTTLの間は結果をキャッシュする
+func (u *ReportUsecase) GetUserStats(ctx context.Context, userID string, days int) (*model.UserStats, error) {
+	if days < 1 || days > MaxStatsDays {
+		return nil, fmt.Errorf("days must be between 1 and %d: %w", MaxStatsDays, model.ErrInvalidInput)
+	}
+
+	now := time.Now()
+	key := statsCacheKey{userID: userID, days: days}
+	if days >= StatsCacheMinDays {
+		if stats, ok := u.cachedStats(key, now); ok {
+			return stats, nil
+		}
+	}
+
+	settings, err := findSettings(ctx, u.settingsRepo, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	projects, err := u.projectRepo.FindByUserID(ctx, userID, model.ListOptions{})
+	if err != nil {
+		u.logger.ErrorContext(ctx, "failed to load projects for user stats", "error", err, "user_id", userID)
+		return nil, fmt.Errorf("failed to load projects for user stats: %w", err)
+	}
+
+	projectIDs := make([]string, 0, len(projects))
+	for _, p := range projects {
+		projectIDs = append(projectIDs, p.ID)
+	}
+
+	tasks, err := u.taskRepo.FindByProjectIDs(ctx, projectIDs)
+	if err != nil {
+		u.logger.ErrorContext(ctx, "failed to load tasks for user stats", "error", err, "user_id", userID)
+		return nil, fmt.Errorf("failed to load tasks for user stats: %w", err)
+	}
+
+	// 集計開始日の0時はタイムゾーンによって前後するため、1日余分に読み込む
+	events, err := u.statusEventRepo.FindByProjectIDsSince(ctx, projectIDs, now.AddDate(0, 0, -days))
+	if err != nil {
+		u.logger.ErrorContext(ctx, "failed to load status events for user stats", "error", err, "user_id", userID)
+		return nil, fmt.Errorf("failed to load status events for user stats: %w", err)
+	}
+
+	stats := model.NewUserStats(userID, now, settings.Location(), settings.WeekStartDay, days, tasks, events)
+	if days >= StatsCacheMinDays {
+		u.storeStats(key, stats, now)
+	}
+
+	return stats, nil
+}
+
+// cachedStats は有効期限内のキャッシュされたユーザー統計を返す
+func (u *ReportUsecase) cachedStats(key statsCacheKey, now time.Time) (*model.UserStats, bool) {
+	u.statsMu.Lock()
+	defer u.statsMu.Unlock()
+
+	entry, ok := u.statsCache[key]
+	if !ok || !now.Before(entry.expiresAt) {
+		return nil, false
+	}
+	return entry.stats, true
+}
+
+// storeStats はユーザー統計をキャッシュし、期限切れのエントリを削除する
+func (u *ReportUsecase) storeStats(key statsCacheKey, stats *model.UserStats, now time.Time) {
+	u.statsMu.Lock()
+	defer u.statsMu.Unlock()
+
+	for k, entry := range u.statsCache {
+		if !now.Before(entry.expiresAt) {
+			delete(u.statsCache, k)
+		}
+	}
+	u.statsCache[key] = &statsCacheEntry{stats: stats, expiresAt: now.Add(StatsCacheTTL)}
+}
+
+// authorizeProject はプロジェクトを取得し、所有者であることを確認する
+func (u *ReportUsecase) authorizeProject(ctx context.Context, userID, projectID string) (*model.Project, error) {
+	project, err := u.projectRepo.FindByID(ctx, projectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find project: %w", err)
+	}
+	if project.UserID != userID {
+		return nil, model.ErrForbidden
+	}
+	return project, nil
+}
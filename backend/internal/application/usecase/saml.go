@@ -0,0 +1,215 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sikigasa/github-task-controller/backend/internal/domain/model"
+	"github.com/sikigasa/github-task-controller/backend/internal/domain/repository"
+	"github.com/sikigasa/github-task-controller/backend/internal/infrastructure/saml"
+)
+
+// samlRequestTTL はAuthnRequestを送ってからResponseを受け付ける期間
+const samlRequestTTL = 10 * time.Minute
+
+// SAMLUsecase はSAML SSOによるログインに関するユースケース
+// IdPはデプロイごとに管理者が設定した1つのみを信頼するため、メールアドレスが一致する既存ユーザーにはそのまま紐付ける
+type SAMLUsecase struct {
+	userRepo        repository.UserRepository
+	samlAccountRepo repository.SAMLAccountRepository
+	samlRequestRepo repository.SAMLRequestRepository
+	sp              *saml.ServiceProvider
+	emailAttribute  string
+	nameAttribute   string
+	tx              repository.Transactor
+	logger          *slog.Logger
+}
+
+// NewSAMLUsecase は新しいSAMLUsecaseを作成する
+// emailAttribute・nameAttributeはメールアドレスと表示名を取り出すアサーションの属性名
+func NewSAMLUsecase(
+	userRepo repository.UserRepository,
+	samlAccountRepo repository.SAMLAccountRepository,
+	samlRequestRepo repository.SAMLRequestRepository,
+	sp *saml.ServiceProvider,
+	emailAttribute string,
+	nameAttribute string,
+	tx repository.Transactor,
+	logger *slog.Logger,
+) *SAMLUsecase {
+	return &SAMLUsecase{
+		userRepo:        userRepo,
+		samlAccountRepo: samlAccountRepo,
+		samlRequestRepo: samlRequestRepo,
+		sp:              sp,
+		emailAttribute:  emailAttribute,
+		nameAttribute:   nameAttribute,
+		tx:              tx,
+		logger:          logger,
+	}
+}
+
+// Metadata はIdPに登録するサービスプロバイダーのメタデータを返す
+func (u *SAMLUsecase) Metadata() []byte {
+	return u.sp.Metadata()
+}
+
+// LoginURL はAuthnRequestを保存し、IdPのログインURLを返す
+// IdPはクロスサイトのPOSTでACSに遷移しCookieが送られないため、AuthnRequestはRelayStateで取り出せるようにサーバー側に保存する
+func (u *SAMLUsecase) LoginURL(ctx context.Context) (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate relay state: %w", err)
+	}
+	relayState := base64.RawURLEncoding.EncodeToString(b)
+
+	now := time.Now()
+	loginURL, requestID, err := u.sp.AuthnRequestURL(relayState, now)
+	if err != nil {
+		return "", fmt.Errorf("failed to create authn request: %w", err)
+	}
+
+	request := &model.SAMLRequest{
+		RelayStateHash: hashRelayState(relayState),
+		RequestID:      requestID,
+		ExpiresAt:      now.Add(samlRequestTTL),
+		CreatedAt:      now,
+	}
+	if err := u.samlRequestRepo.Create(ctx, request); err != nil {
+		u.logger.ErrorContext(ctx, "failed to save saml request", "error", err)
+		return "", fmt.Errorf("failed to save saml request: %w", err)
+	}
+	return loginURL, nil
+}
+
+// HandleResponse はIdPから受け取ったResponseを検証し、アサーションのユーザーをログインさせる
+// relayStateはResponseとともにPOSTされたRelayStateで、ログイン開始時に保存したAuthnRequestを取り出す
+// AuthnRequestは検証の結果にかかわらず削除するため、同じResponseやRelayStateは再利用できない
+func (u *SAMLUsecase) HandleResponse(ctx context.Context, encoded, relayState string) (*model.User, error) {
+	if relayState == "" {
+		u.logger.WarnContext(ctx, "saml relay state is missing")
+		return nil, fmt.Errorf("saml relay state is missing: %w", model.ErrUnauthorized)
+	}
+	request, err := u.samlRequestRepo.Consume(ctx, hashRelayState(relayState), time.Now())
+	if errors.Is(err, model.ErrNotFound) {
+		u.logger.WarnContext(ctx, "saml request not found")
+		return nil, fmt.Errorf("saml request not found: %w", model.ErrUnauthorized)
+	}
+	if err != nil {
+		u.logger.ErrorContext(ctx, "failed to find saml request", "error", err)
+		return nil, fmt.Errorf("failed to find saml request: %w", err)
+	}
+
+	assertion, err := u.sp.ParseResponse(encoded, request.RequestID, time.Now())
+	if err != nil {
+		u.logger.WarnContext(ctx, "failed to verify saml response", "error", err)
+		return nil, fmt.Errorf("failed to verify saml response: %w", model.ErrUnauthorized)
+	}
+
+	email := assertion.Attribute(u.emailAttribute)
+	if email == "" && strings.Contains(assertion.NameID, "@") {
+		email = assertion.NameID
+	}
+	if email == "" || !strings.Contains(email, "@") {
+		u.logger.WarnContext(ctx, "email not found in saml assertion", "name_id", assertion.NameID)
+		return nil, errors.New("email is not available")
+	}
+
+	idpEntityID := u.sp.IdPEntityID()
+	now := time.Now()
+
+	// 既存のSAMLアカウントを検索
+	account, err := u.samlAccountRepo.FindByNameID(ctx, idpEntityID, assertion.NameID)
+	if err != nil && !errors.Is(err, repository.ErrAccountNotFound) {
+		u.logger.ErrorContext(ctx, "failed to find saml account", "error", err)
+		return nil, fmt.Errorf("failed to find saml account: %w", err)
+	}
+
+	if account != nil {
+		user, err := u.userRepo.FindByID(ctx, account.UserID)
+		if err != nil {
+			u.logger.ErrorContext(ctx, "failed to find user", "user_id", account.UserID, "error", err)
+			return nil, fmt.Errorf("failed to find user: %w", err)
+		}
+
+		// IdPで表示名が変更されていれば反映する
+		if name := assertion.Attribute(u.nameAttribute); name != "" && name != user.Name {
+			user.Name = name
+			user.UpdatedAt = now
+			if err := u.userRepo.Update(ctx, user); err != nil {
+				u.logger.ErrorContext(ctx, "failed to update user", "error", err)
+				return nil, fmt.Errorf("failed to update user: %w", err)
+			}
+		}
+
+		if err := u.samlAccountRepo.Touch(ctx, idpEntityID, assertion.NameID, now); err != nil {
+			u.logger.ErrorContext(ctx, "failed to update saml account", "error", err)
+			return nil, fmt.Errorf("failed to update saml account: %w", err)
+		}
+		return user, nil
+	}
+
+	// メールアドレスが一致する既存ユーザーを検索
+	user, err := u.userRepo.FindByEmail(ctx, email)
+	if err != nil && !errors.Is(err, repository.ErrUserNotFound) {
+		u.logger.ErrorContext(ctx, "failed to find user by email", "error", err)
+		return nil, fmt.Errorf("failed to find user: %w", err)
+	}
+
+	// 新規ユーザーはSAMLアカウントと同じトランザクションで作成し、アカウントのないユーザーが残らないようにする
+	newUser := user == nil
+	if newUser {
+		name := assertion.Attribute(u.nameAttribute)
+		if name == "" {
+			name, _, _ = strings.Cut(email, "@")
+		}
+		user = &model.User{
+			ID:        uuid.New().String(),
+			Email:     email,
+			Name:      name,
+			CreatedAt: now,
+			UpdatedAt: now,
+		}
+	}
+
+	account = &model.SAMLAccount{
+		UserID:      user.ID,
+		IdPEntityID: idpEntityID,
+		NameID:      assertion.NameID,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	err = u.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if newUser {
+			if err := u.userRepo.Create(ctx, user); err != nil {
+				return fmt.Errorf("failed to create user: %w", err)
+			}
+		}
+		if err := u.samlAccountRepo.Create(ctx, account); err != nil {
+			return fmt.Errorf("failed to create saml account: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		u.logger.ErrorContext(ctx, "failed to create saml account", "error", err)
+		return nil, err
+	}
+
+	u.logger.InfoContext(ctx, "saml account created successfully", "user_id", user.ID, "new_user", newUser)
+	return user, nil
+}
+
+// hashRelayState は保存・検索に使うRelayStateのハッシュを返す
+func hashRelayState(relayState string) string {
+	sum := sha256.Sum256([]byte(relayState))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,153 @@
+package usecase
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"errors"
+	"math/big"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sikigasa/github-task-controller/backend/internal/domain/model"
+	"github.com/sikigasa/github-task-controller/backend/internal/infrastructure/saml"
+)
+
+// memorySAMLRequests はAuthnRequestをメモリに保存するSAMLRequestRepository
+type memorySAMLRequests struct {
+	mu       sync.Mutex
+	requests map[string]model.SAMLRequest
+}
+
+func (m *memorySAMLRequests) Create(_ context.Context, request *model.SAMLRequest) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.requests[request.RelayStateHash] = *request
+	return nil
+}
+
+func (m *memorySAMLRequests) Consume(_ context.Context, relayStateHash string, now time.Time) (*model.SAMLRequest, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	request, ok := m.requests[relayStateHash]
+	delete(m.requests, relayStateHash)
+	if !ok || !request.ExpiresAt.After(now) {
+		return nil, model.ErrNotFound
+	}
+	return &request, nil
+}
+
+// testIdPCertificate はテスト用の自己署名証明書をBase64で返す（Responseの検証まで進まないテストで使う）
+func testIdPCertificate(t *testing.T) string {
+	t.Helper()
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "idp.example.com"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, pub, priv)
+	if err != nil {
+		t.Fatalf("failed to create certificate: %v", err)
+	}
+	return base64.StdEncoding.EncodeToString(der)
+}
+
+func newTestSAMLUsecase(t *testing.T) (*SAMLUsecase, *memorySAMLRequests) {
+	t.Helper()
+	sp, err := saml.NewServiceProvider(saml.Config{
+		EntityID:       "https://app.example.com/auth/saml/metadata",
+		ACSURL:         "https://app.example.com/auth/saml/acs",
+		IdPEntityID:    "https://idp.example.com/metadata",
+		IdPSSOURL:      "https://idp.example.com/sso",
+		IdPCertificate: testIdPCertificate(t),
+	})
+	if err != nil {
+		t.Fatalf("NewServiceProvider() error = %v", err)
+	}
+	requests := &memorySAMLRequests{requests: make(map[string]model.SAMLRequest)}
+	return NewSAMLUsecase(nil, nil, requests, sp, "email", "name", nil, discardLogger()), requests
+}
+
+// startSAMLLogin はログインを開始し、IdPのログインURLに含まれるRelayStateを返す
+func startSAMLLogin(t *testing.T, u *SAMLUsecase) string {
+	t.Helper()
+	loginURL, err := u.LoginURL(context.Background())
+	if err != nil {
+		t.Fatalf("LoginURL() error = %v", err)
+	}
+	parsed, err := url.Parse(loginURL)
+	if err != nil {
+		t.Fatalf("failed to parse login url: %v", err)
+	}
+	relayState := parsed.Query().Get("RelayState")
+	if relayState == "" {
+		t.Fatalf("login url %s has no RelayState", loginURL)
+	}
+	return relayState
+}
+
+func TestSAMLUsecaseLoginURLSavesRequest(t *testing.T) {
+	u, requests := newTestSAMLUsecase(t)
+
+	relayState := startSAMLLogin(t, u)
+
+	request, ok := requests.requests[hashRelayState(relayState)]
+	if !ok {
+		t.Fatal("saml request was not saved for the relay state")
+	}
+	if request.RequestID == "" {
+		t.Error("saved saml request has no request id")
+	}
+	if ttl := request.ExpiresAt.Sub(request.CreatedAt); ttl != samlRequestTTL {
+		t.Errorf("saml request ttl = %v, want %v", ttl, samlRequestTTL)
+	}
+	// RelayStateはハッシュのみを保存する
+	if _, ok := requests.requests[relayState]; ok {
+		t.Error("relay state was saved without hashing")
+	}
+}
+
+func TestSAMLUsecaseHandleResponseConsumesRequest(t *testing.T) {
+	ctx := context.Background()
+	u, requests := newTestSAMLUsecase(t)
+	relayState := startSAMLLogin(t, u)
+
+	// 検証に失敗した場合もAuthnRequestは削除する
+	if _, err := u.HandleResponse(ctx, "invalid", relayState); !errors.Is(err, model.ErrUnauthorized) {
+		t.Fatalf("HandleResponse() error = %v, want ErrUnauthorized", err)
+	}
+	if len(requests.requests) != 0 {
+		t.Errorf("saml requests = %v, want none", requests.requests)
+	}
+
+	if _, err := u.HandleResponse(ctx, "invalid", relayState); !errors.Is(err, model.ErrUnauthorized) {
+		t.Errorf("HandleResponse() with used relay state error = %v, want ErrUnauthorized", err)
+	}
+}
+
+func TestSAMLUsecaseHandleResponseRejectsUnknownRelayState(t *testing.T) {
+	u, requests := newTestSAMLUsecase(t)
+	relayState := startSAMLLogin(t, u)
+
+	for name, other := range map[string]string{"empty": "", "unknown": relayState + "x"} {
+		t.Run(name, func(t *testing.T) {
+			if _, err := u.HandleResponse(context.Background(), "invalid", other); !errors.Is(err, model.ErrUnauthorized) {
+				t.Errorf("HandleResponse() error = %v, want ErrUnauthorized", err)
+			}
+		})
+	}
+	// 別のRelayStateでは保存したAuthnRequestは削除されない
+	if len(requests.requests) != 1 {
+		t.Errorf("saml requests = %d, want 1", len(requests.requests))
+	}
+}
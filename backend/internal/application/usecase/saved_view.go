@@ -0,0 +1,141 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sikigasa/github-task-controller/backend/internal/domain/model"
+	"github.com/sikigasa/github-task-controller/backend/internal/domain/repository"
+)
+
+// SavedViewUsecase は保存済みビューに関するユースケース
+type SavedViewUsecase struct {
+	viewRepo    repository.SavedViewRepository
+	projectRepo repository.ProjectRepository
+	taskRepo    repository.TaskRepository
+	logger      *slog.Logger
+}
+
+// NewSavedViewUsecase は新しいSavedViewUsecaseを作成する
+func NewSavedViewUsecase(viewRepo repository.SavedViewRepository, projectRepo repository.ProjectRepository, taskRepo repository.TaskRepository, logger *slog.Logger) *SavedViewUsecase {
+	return &SavedViewUsecase{
+		viewRepo:    viewRepo,
+		projectRepo: projectRepo,
+		taskRepo:    taskRepo,
+		logger:      logger,
+	}
+}
+
+// CreateView はプロジェクトに絞り込み・並び順の組み合わせをビューとして保存する
+func (u *SavedViewUsecase) CreateView(ctx context.Context, userID, projectID string, req *model.CreateSavedViewRequest) (*model.SavedView, error) {
+	project, err := u.projectRepo.FindByID(ctx, projectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find project: %w", err)
+	}
+	if project.UserID != userID {
+		return nil, model.ErrForbidden
+	}
+
+	if err := req.Filter.Validate(); err != nil {
+		return nil, err
+	}
+
+	sort := make([]model.SortKey, 0, len(req.Sort))
+	for _, key := range req.Sort {
+		if key.Order == "" {
+			key.Order = model.SortAsc
+		}
+		sort = append(sort, key)
+	}
+
+	now := time.Now()
+	view := &model.SavedView{
+		ID:        uuid.New().String(),
+		ProjectID: projectID,
+		UserID:    userID,
+		Name:      req.Name,
+		Filter:    req.Filter,
+		Sort:      sort,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	if err := u.viewRepo.Create(ctx, view); err != nil {
+		u.logger.ErrorContext(ctx, "failed to create saved view", "error", err)
+		return nil, fmt.Errorf("failed to create saved view: %w", err)
+	}
+
+	u.logger.InfoContext(ctx, "saved view created", "view_id", view.ID, "project_id", projectID)
+	return view, nil
+}
+
+// ListViews はユーザーがプロジェクトに保存したビューを取得する
+func (u *SavedViewUsecase) ListViews(ctx context.Context, userID, projectID string) ([]*model.SavedView, error) {
+	project, err := u.projectRepo.FindByID(ctx, projectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find project: %w", err)
+	}
+	if project.UserID != userID {
+		return nil, model.ErrForbidden
+	}
+
+	views, err := u.viewRepo.FindByProjectID(ctx, projectID, userID)
+	if err != nil {
+		u.logger.ErrorContext(ctx, "failed to list saved views", "error", err, "project_id", projectID)
+		return nil, fmt.Errorf("failed to list saved views: %w", err)
+	}
+
+	return views, nil
+}
+
+// GetView はIDでビューを取得する（他のユーザーのビューは取得できない）
+func (u *SavedViewUsecase) GetView(ctx context.Context, userID, id string) (*model.SavedView, error) {
+	view, err := u.viewRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find saved view: %w", err)
+	}
+	if view.UserID != userID {
+		return nil, model.ErrForbidden
+	}
+
+	return view, nil
+}
+
+// DeleteView はビューを削除する
+func (u *SavedViewUsecase) DeleteView(ctx context.Context, userID, id string) error {
+	if _, err := u.GetView(ctx, userID, id); err != nil {
+		return err
+	}
+
+	if err := u.viewRepo.Delete(ctx, id); err != nil {
+		u.logger.ErrorContext(ctx, "failed to delete saved view", "error", err, "view_id", id)
+		return fmt.Errorf("failed to delete saved view: %w", err)
+	}
+
+	u.logger.InfoContext(ctx, "saved view deleted", "view_id", id)
+	return nil
+}
+
+// ListViewTasks はビューの絞り込み・並び順でタスクを取得する
+// optsにソートキーが指定された場合は保存済みの並び順より優先する
+func (u *SavedViewUsecase) ListViewTasks(ctx context.Context, userID, id string, opts model.ListOptions) ([]*model.Task, error) {
+	view, err := u.GetView(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(opts.Sort) == 0 {
+		opts.Sort = view.Sort
+	}
+
+	tasks, err := u.taskRepo.FindByProjectID(ctx, view.ProjectID, view.Filter, opts)
+	if err != nil {
+		u.logger.ErrorContext(ctx, "failed to list tasks for saved view", "error", err, "view_id", id)
+		return nil, fmt.Errorf("failed to list tasks for saved view: %w", err)
+	}
+
+	return tasks, nil
+}
@@ -0,0 +1,214 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sikigasa/github-task-controller/backend/internal/domain/model"
+	"github.com/sikigasa/github-task-controller/backend/internal/domain/repository"
+)
+
+// schedulerErrorMaxLen は記録するエラーメッセージの最大長
+const schedulerErrorMaxLen = 1000
+
+// ScheduledJobFunc はスケジューラーが実行するジョブ（errは実行の結果）
+type ScheduledJobFunc func(ctx context.Context) error
+
+// Scheduler は登録したジョブをcron式のスケジュールで実行する
+// ジョブごとに前回の実行が終わってから次の実行時刻を計算するため、同じジョブが重ねて実行されることはない
+// （実行中に過ぎた実行時刻は飛ばす）
+// 複数のインスタンスで動かす場合は、実行時刻ごとにロックを取得できたインスタンスだけがジョブを実行する
+type Scheduler struct {
+	mu      sync.Mutex
+	jobs    []*scheduledJob
+	loc     *time.Location
+	locker  repository.Locker
+	workers *WorkerMonitor
+	logger  *slog.Logger
+}
+
+// scheduledJob は登録したジョブと実行状況
+type scheduledJob struct {
+	name     string
+	spec     string
+	schedule *cronSchedule
+	run      ScheduledJobFunc
+
+	running      bool
+	lastRunAt    time.Time
+	lastDuration time.Duration
+	lastError    string
+	// lastSkippedAt は他のインスタンスが実行中だったため実行を見送った時刻
+	lastSkippedAt time.Time
+	nextRunAt     time.Time
+}
+
+// NewScheduler は新しいSchedulerを作成する（cron式の時刻はlocで解釈する）
+// lockerがnilの場合はインスタンス間で排他しない。workersにはジョブの実行状況をジョブの名前で記録する
+func NewScheduler(loc *time.Location, locker repository.Locker, workers *WorkerMonitor, logger *slog.Logger) *Scheduler {
+	return &Scheduler{
+		loc:     loc,
+		locker:  locker,
+		workers: workers,
+		logger:  logger,
+	}
+}
+
+// Register はジョブを登録する（Runの開始前に呼び出す）
+// specはcron式（分 時 日 月 曜日）か@daily等の定義済みの式、@every <間隔>
+func (s *Scheduler) Register(name, spec string, run ScheduledJobFunc) error {
+	schedule, err := parseCron(spec, s.loc)
+	if err != nil {
+		return fmt.Errorf("failed to register job %s: %w", name, err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if slices.ContainsFunc(s.jobs, func(j *scheduledJob) bool { return j.name == name }) {
+		return fmt.Errorf("job %s is already registered: %w", name, model.ErrConflict)
+	}
+	s.jobs = append(s.jobs, &scheduledJob{name: name, spec: spec, schedule: schedule, run: run})
+
+	// 連続する2回の実行時刻の間隔を、WorkerMonitorがstaleと判定する基準にする
+	next := schedule.Next(time.Now())
+	s.workers.Register(name, schedule.Next(next).Sub(next))
+	return nil
+}
+
+// Run はctxがキャンセルされるまで登録したジョブをスケジュールどおりに実行する
+// 実行中のジョブがある場合は終了を待ってから返る
+func (s *Scheduler) Run(ctx context.Context) {
+	s.mu.Lock()
+	jobs := slices.Clone(s.jobs)
+	s.mu.Unlock()
+
+	var wg sync.WaitGroup
+	for _, job := range jobs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.loop(ctx, job)
+		}()
+	}
+	wg.Wait()
+}
+
+// loop は次の実行時刻まで待ってジョブを実行することを繰り返す
+func (s *Scheduler) loop(ctx context.Context, job *scheduledJob) {
+	for {
+		next := job.schedule.Next(time.Now())
+		s.mu.Lock()
+		job.nextRunAt = next
+		s.mu.Unlock()
+
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		s.execute(ctx, job)
+	}
+}
+
+// execute はジョブを1回実行して結果を記録する
+// 他のインスタンスが同じジョブを実行中の場合は実行しない
+func (s *Scheduler) execute(ctx context.Context, job *scheduledJob) {
+	unlock, acquired, err := s.tryLock(ctx, job.name)
+	if err == nil && !acquired {
+		s.logger.DebugContext(ctx, "scheduled job skipped, running on another instance", "job", job.name)
+		s.workers.Beat(job.name, nil)
+		s.mu.Lock()
+		job.lastSkippedAt = time.Now()
+		s.mu.Unlock()
+		return
+	}
+
+	s.mu.Lock()
+	job.running = true
+	s.mu.Unlock()
+
+	startedAt := time.Now()
+	if err == nil {
+		err = job.run(ctx)
+		unlock()
+	}
+	duration := time.Since(startedAt)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "scheduled job failed", "error", err, "job", job.name, "duration", duration)
+	} else {
+		s.logger.DebugContext(ctx, "scheduled job completed", "job", job.name, "duration", duration)
+	}
+	s.workers.Beat(job.name, err)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	job.running = false
+	job.lastRunAt = startedAt
+	job.lastDuration = duration
+	job.lastError = ""
+	if err != nil {
+		job.lastError = err.Error()
+		if runes := []rune(job.lastError); len(runes) > schedulerErrorMaxLen {
+			job.lastError = string(runes[:schedulerErrorMaxLen])
+		}
+	}
+}
+
+// tryLock はジョブのロックを待たずに取得する（lockerがnilの場合は常に取得できる）
+func (s *Scheduler) tryLock(ctx context.Context, name string) (func(), bool, error) {
+	if s.locker == nil {
+		return func() {}, true, nil
+	}
+	unlock, acquired, err := s.locker.TryLock(ctx, "scheduler:"+name)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to lock job %s: %w", name, err)
+	}
+	return unlock, acquired, nil
+}
+
+// Jobs は登録したジョブの実行状況を名前順に返す
+func (s *Scheduler) Jobs() []model.ScheduledJob {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	jobs := make([]model.ScheduledJob, 0, len(s.jobs))
+	for _, job := range s.jobs {
+		status := model.ScheduledJob{
+			Name:     job.name,
+			Schedule: job.spec,
+			Timezone: s.loc.String(),
+			Running:  job.running,
+		}
+		if !job.lastRunAt.IsZero() {
+			lastRunAt := job.lastRunAt
+			durationMS := job.lastDuration.Milliseconds()
+			status.LastRunAt = &lastRunAt
+			status.LastDurationMS = &durationMS
+		}
+		if job.lastError != "" {
+			lastError := job.lastError
+			status.LastError = &lastError
+		}
+		if !job.lastSkippedAt.IsZero() {
+			lastSkippedAt := job.lastSkippedAt
+			status.LastSkippedAt = &lastSkippedAt
+		}
+		if !job.nextRunAt.IsZero() {
+			nextRunAt := job.nextRunAt
+			status.NextRunAt = &nextRunAt
+		}
+		jobs = append(jobs, status)
+	}
+	slices.SortFunc(jobs, func(a, b model.ScheduledJob) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	return jobs
+}
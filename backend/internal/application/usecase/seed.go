@@ -0,0 +1,375 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math/rand/v2"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sikigasa/github-task-controller/backend/internal/domain/model"
+	"github.com/sikigasa/github-task-controller/backend/internal/domain/repository"
+)
+
+// SeedUsecase はローカル開発・負荷試験用のデータを生成するユースケース
+// 作成日・ステータス履歴・Pull Request・コミットを過去の日時で作るため、ユースケースを通さずリポジトリに直接書き込む
+type SeedUsecase struct {
+	userRepo        repository.UserRepository
+	projectRepo     repository.ProjectRepository
+	milestoneRepo   repository.MilestoneRepository
+	taskRepo        repository.TaskRepository
+	statusEventRepo repository.TaskStatusEventRepository
+	pullRequestRepo repository.TaskPullRequestRepository
+	commitRepo      repository.TaskCommitRepository
+	logger          *slog.Logger
+}
+
+// NewSeedUsecase は新しいSeedUsecaseを作成する
+func NewSeedUsecase(
+	userRepo repository.UserRepository,
+	projectRepo repository.ProjectRepository,
+	milestoneRepo repository.MilestoneRepository,
+	taskRepo repository.TaskRepository,
+	statusEventRepo repository.TaskStatusEventRepository,
+	pullRequestRepo repository.TaskPullRequestRepository,
+	commitRepo repository.TaskCommitRepository,
+	logger *slog.Logger,
+) *SeedUsecase {
+	return &SeedUsecase{
+		userRepo:        userRepo,
+		projectRepo:     projectRepo,
+		milestoneRepo:   milestoneRepo,
+		taskRepo:        taskRepo,
+		statusEventRepo: statusEventRepo,
+		pullRequestRepo: pullRequestRepo,
+		commitRepo:      commitRepo,
+		logger:          logger,
+	}
+}
+
+// 生成するデータの名前の素材
+var (
+	seedUserNames    = []string{"佐藤", "鈴木", "高橋", "田中", "伊藤", "渡辺", "山本", "中村", "小林", "加藤"}
+	seedProjectNames = []string{"Webアプリ刷新", "モバイルアプリ", "社内ツール", "データ基盤", "決済機能", "管理画面"}
+	seedTaskSubjects = []string{"ログイン画面", "APIのエラー処理", "CSVエクスポート", "通知メール", "検索機能", "権限管理", "ダッシュボード", "データ移行", "CI設定", "パフォーマンス"}
+	seedTaskActions  = []string{"を実装する", "を修正する", "のテストを書く", "をリファクタリングする", "を調査する", "のドキュメントを更新する"}
+	seedEstimates    = []float64{1, 2, 3, 5, 8, 13}
+)
+
+// seedRun は1回の生成の状態
+type seedRun struct {
+	rnd    *rand.Rand
+	now    time.Time
+	days   int
+	result *model.SeedResult
+}
+
+// Seed は開発用のユーザー・プロジェクト・マイルストーン・タスクとその履歴を生成する
+// プロジェクトの半数は架空のGitHubリポジトリに連携したものとし、Issue・Pull Request・コミットの同期履歴も作る
+func (u *SeedUsecase) Seed(ctx context.Context, opts model.SeedOptions) (*model.SeedResult, error) {
+	opts = opts.WithDefaults()
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+
+	seed := opts.RandomSeed
+	if seed == 0 {
+		seed = uint64(time.Now().UnixNano())
+	}
+	run := &seedRun{
+		rnd:    rand.New(rand.NewPCG(seed, seed)),
+		now:    time.Now(),
+		days:   opts.HistoryDays,
+		result: &model.SeedResult{},
+	}
+
+	if opts.UserID != "" {
+		if _, err := u.userRepo.FindByID(ctx, opts.UserID); err != nil {
+			return nil, fmt.Errorf("failed to find user: %w", err)
+		}
+		run.result.UserIDs = []string{opts.UserID}
+	} else {
+		for i := range opts.Users {
+			userID, err := u.seedUser(ctx, run, i)
+			if err != nil {
+				return nil, err
+			}
+			run.result.UserIDs = append(run.result.UserIDs, userID)
+		}
+	}
+
+	for _, userID := range run.result.UserIDs {
+		for i := range opts.ProjectsPerUser {
+			if err := u.seedProject(ctx, run, userID, i, opts.TasksPerProject); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	u.logger.InfoContext(ctx, "seed data generated",
+		"users", len(run.result.UserIDs), "projects", run.result.Projects, "tasks", run.result.Tasks, "random_seed", seed)
+	return run.result, nil
+}
+
+// seedUser はログインできない開発用のユーザーを作成する
+func (u *SeedUsecase) seedUser(ctx context.Context, run *seedRun, index int) (string, error) {
+	id := uuid.New().String()
+	createdAt := run.pastTime(run.days)
+	user := &model.User{
+		ID:        id,
+		Email:     fmt.Sprintf("seed-%d-%s@%s", index+1, id[:8], model.SeedEmailDomain),
+		Name:      fmt.Sprintf("%s %d", seedUserNames[index%len(seedUserNames)], index+1),
+		CreatedAt: createdAt,
+		UpdatedAt: createdAt,
+	}
+	if err := u.userRepo.Create(ctx, user); err != nil {
+		return "", fmt.Errorf("failed to create seed user: %w", err)
+	}
+	return id, nil
+}
+
+// seedProject はプロジェクトとマイルストーン・タスクを作成する
+func (u *SeedUsecase) seedProject(ctx context.Context, run *seedRun, userID string, index, tasks int) error {
+	createdAt := run.now.AddDate(0, 0, -run.days)
+	// 既存ユーザーに繰り返し生成してもタイトルが重複しないよう、タイトルにIDの先頭を付ける
+	id := uuid.New().String()
+	project := &model.Project{
+		ID:                   id,
+		UserID:               userID,
+		Title:                fmt.Sprintf("%s #%d (%s)", seedProjectNames[index%len(seedProjectNames)], index+1, id[:8]),
+		Description:          "開発用に生成したプロジェクトです。",
+		EstimateUnit:         model.EstimateUnitPoints,
+		GithubDeletionPolicy: model.GithubDeletionOrphan,
+		GithubItemType:       model.GithubItemDraft,
+		CreatedAt:            createdAt,
+		UpdatedAt:            createdAt,
+	}
+	// GitHub Projectsの同期が走らないよう、リポジトリのみ連携したことにする
+	linked := index%2 == 0
+	if linked {
+		owner := "seed-example"
+		repo := fmt.Sprintf("repo-%s", project.ID[:8])
+		project.GithubOwner = &owner
+		project.GithubRepo = &repo
+	}
+	if err := u.projectRepo.Create(ctx, project); err != nil {
+		return fmt.Errorf("failed to create seed project: %w", err)
+	}
+	run.result.Projects++
+
+	milestoneIDs, err := u.seedMilestones(ctx, run, project)
+	if err != nil {
+		return err
+	}
+
+	for i := range tasks {
+		if err := u.seedTask(ctx, run, project, milestoneIDs, i, linked); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// seedMilestones は過去・直近・将来の期日のマイルストーンを作成する
+func (u *SeedUsecase) seedMilestones(ctx context.Context, run *seedRun, project *model.Project) ([]string, error) {
+	var ids []string
+	for i, offset := range []int{-run.days / 2, 7, 30} {
+		due := run.now.AddDate(0, 0, offset).Truncate(24 * time.Hour)
+		milestone := &model.Milestone{
+			ID:        uuid.New().String(),
+			ProjectID: project.ID,
+			Title:     fmt.Sprintf("v%d.0", i+1),
+			DueDate:   &due,
+			CreatedAt: project.CreatedAt,
+			UpdatedAt: project.CreatedAt,
+		}
+		if err := u.milestoneRepo.Create(ctx, milestone); err != nil {
+			return nil, fmt.Errorf("failed to create seed milestone: %w", err)
+		}
+		ids = append(ids, milestone.ID)
+		run.result.Milestones++
+	}
+	return ids, nil
+}
+
+// seedTask はタスクを作成し、作成から現在のステータスまでの履歴とGitHubの同期履歴を記録する
+// 完了4割・進行中2.5割・未着手3.5割の割合で、着手・完了の日時は作成日から現在までの間に散らす
+func (u *SeedUsecase) seedTask(ctx context.Context, run *seedRun, project *model.Project, milestoneIDs []string, index int, linked bool) error {
+	createdAt := run.pastTime(run.days)
+	status := model.TaskStatusTodo
+	switch r := run.rnd.Float64(); {
+	case r < 0.4:
+		status = model.TaskStatusDone
+	case r < 0.65:
+		status = model.TaskStatusInProgress
+	}
+
+	var startedAt, doneAt time.Time
+	if status != model.TaskStatusTodo {
+		startedAt = run.between(createdAt, createdAt.AddDate(0, 0, 7))
+	}
+	if status == model.TaskStatusDone {
+		doneAt = run.between(startedAt, startedAt.AddDate(0, 0, 14))
+	}
+
+	estimate := seedEstimates[run.rnd.IntN(len(seedEstimates))]
+	task := &model.Task{
+		ID:        uuid.New().String(),
+		ProjectID: project.ID,
+		Title: seedTaskSubjects[run.rnd.IntN(len(seedTaskSubjects))] +
+			seedTaskActions[run.rnd.IntN(len(seedTaskActions))],
+		Status:    status,
+		Priority:  model.TaskPriority(run.rnd.IntN(3)),
+		Estimate:  &estimate,
+		CreatedAt: createdAt,
+		UpdatedAt: createdAt,
+	}
+
+	// 未着手のタスクは期日が過去（期限切れ）のものも含める
+	start := createdAt.AddDate(0, 0, run.rnd.IntN(14)).Truncate(24 * time.Hour)
+	if !startedAt.IsZero() {
+		start = startedAt.Truncate(24 * time.Hour)
+	}
+	end := start.AddDate(0, 0, 1+run.rnd.IntN(10))
+	task.StartDate = &start
+	task.EndDate = &end
+	if run.rnd.IntN(4) > 0 {
+		task.MilestoneID = &milestoneIDs[run.rnd.IntN(len(milestoneIDs))]
+	}
+
+	if linked {
+		number := index + 1
+		issueURL := fmt.Sprintf("https://github.com/%s/%s/issues/%d", *project.GithubOwner, *project.GithubRepo, number)
+		task.GithubIssueNumber = &number
+		task.GithubIssueURL = &issueURL
+		if status != model.TaskStatusTodo {
+			branch := fmt.Sprintf("task/%d", number)
+			task.GithubBranch = &branch
+		}
+	}
+
+	events := []*model.TaskStatusEvent{run.statusEvent(task, nil, model.TaskStatusTodo, createdAt)}
+	if !startedAt.IsZero() {
+		events = append(events, run.statusEvent(task, &events[0].ToStatus, model.TaskStatusInProgress, startedAt))
+		task.UpdatedAt = startedAt
+	}
+	if !doneAt.IsZero() {
+		events = append(events, run.statusEvent(task, &events[1].ToStatus, model.TaskStatusDone, doneAt))
+		task.UpdatedAt = doneAt
+	}
+
+	var pullRequests []*model.TaskPullRequest
+	if linked && status != model.TaskStatusTodo {
+		pullRequests = run.pullRequests(task, project, startedAt, doneAt)
+		task.GithubChecksStatus = model.AggregateChecksStatus(pullRequests)
+	}
+
+	if err := u.taskRepo.Create(ctx, task); err != nil {
+		return fmt.Errorf("failed to create seed task: %w", err)
+	}
+	run.result.Tasks++
+
+	for _, event := range events {
+		if err := u.statusEventRepo.Create(ctx, event); err != nil {
+			return fmt.Errorf("failed to create seed status event: %w", err)
+		}
+		run.result.StatusEvents++
+	}
+
+	if len(pullRequests) > 0 {
+		if err := u.pullRequestRepo.ReplaceForTask(ctx, task.ID, pullRequests); err != nil {
+			return fmt.Errorf("failed to create seed pull requests: %w", err)
+		}
+		run.result.PullRequests += len(pullRequests)
+	}
+
+	if linked && !startedAt.IsZero() {
+		until := run.now
+		if !doneAt.IsZero() {
+			until = doneAt
+		}
+		for range 1 + run.rnd.IntN(4) {
+			commit := run.commit(task, project, run.between(startedAt, until))
+			if _, err := u.commitRepo.Create(ctx, commit); err != nil {
+				return fmt.Errorf("failed to create seed commit: %w", err)
+			}
+			run.result.Commits++
+		}
+	}
+	return nil
+}
+
+// pastTime は現在からdays日前までのランダムな日時を返す
+func (r *seedRun) pastTime(days int) time.Time {
+	return r.now.Add(-time.Duration(r.rnd.Int64N(int64(days)*int64(24*time.Hour) + 1)))
+}
+
+// between はfromからto（現在より後の場合は現在）までのランダムな日時を返す
+func (r *seedRun) between(from, to time.Time) time.Time {
+	if to.After(r.now) {
+		to = r.now
+	}
+	if !to.After(from) {
+		return from
+	}
+	return from.Add(time.Duration(r.rnd.Int64N(int64(to.Sub(from)) + 1)))
+}
+
+// statusEvent はステータス履歴を作成する
+func (r *seedRun) statusEvent(task *model.Task, from *model.TaskStatus, to model.TaskStatus, at time.Time) *model.TaskStatusEvent {
+	return &model.TaskStatusEvent{
+		ID:         uuid.New().String(),
+		TaskID:     task.ID,
+		ProjectID:  task.ProjectID,
+		FromStatus: from,
+		ToStatus:   to,
+		ChangedAt:  at,
+	}
+}
+
+// pullRequests は完了したタスクにはマージ済み、進行中のタスクには半数にオープンなPull Requestを作成する
+func (r *seedRun) pullRequests(task *model.Task, project *model.Project, startedAt, doneAt time.Time) []*model.TaskPullRequest {
+	number := *task.GithubIssueNumber + 10000
+	pr := &model.TaskPullRequest{
+		TaskID:   task.ID,
+		Number:   number,
+		Title:    task.Title,
+		URL:      fmt.Sprintf("https://github.com/%s/%s/pull/%d", *project.GithubOwner, *project.GithubRepo, number),
+		HeadRef:  *task.GithubBranch,
+		LinkType: model.PullRequestLinkCloses,
+	}
+
+	checks := []model.ChecksStatus{model.ChecksSuccess, model.ChecksSuccess, model.ChecksFailure, model.ChecksPending}
+	status := checks[r.rnd.IntN(len(checks))]
+	switch {
+	case !doneAt.IsZero():
+		success := model.ChecksSuccess
+		pr.State = model.PullRequestMerged
+		pr.MergedAt = &doneAt
+		pr.UpdatedAt = doneAt
+		pr.ChecksStatus = &success
+	case r.rnd.IntN(2) == 0:
+		pr.State = model.PullRequestOpen
+		pr.UpdatedAt = r.between(startedAt, r.now)
+		pr.ChecksStatus = &status
+	default:
+		return nil
+	}
+	return []*model.TaskPullRequest{pr}
+}
+
+// commit はタスクを参照するコミットを作成する
+func (r *seedRun) commit(task *model.Task, project *model.Project, committedAt time.Time) *model.TaskCommit {
+	sha := fmt.Sprintf("%016x%016x%08x", r.rnd.Uint64(), r.rnd.Uint64(), r.rnd.Uint32())
+	return &model.TaskCommit{
+		TaskID:      task.ID,
+		ProjectID:   project.ID,
+		SHA:         sha,
+		Message:     fmt.Sprintf("%s (#%d)", task.Title, *task.GithubIssueNumber),
+		URL:         fmt.Sprintf("https://github.com/%s/%s/commit/%s", *project.GithubOwner, *project.GithubRepo, sha),
+		Author:      "seed-bot",
+		CommittedAt: committedAt,
+		CreatedAt:   committedAt,
+	}
+}
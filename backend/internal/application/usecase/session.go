@@ -0,0 +1,122 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sikigasa/github-task-controller/backend/internal/domain/model"
+	"github.com/sikigasa/github-task-controller/backend/internal/domain/repository"
+)
+
+const (
+	// sessionTouchInterval は最終アクティビティを更新する最小間隔（リクエストごとの書き込みを避ける）
+	sessionTouchInterval = 5 * time.Minute
+	// maxUserAgentLength は記録するUser-Agentの最大長
+	maxUserAgentLength = 512
+)
+
+// SessionUsecase はログインセッションの端末情報と最終アクティビティの記録に関するユースケース
+type SessionUsecase struct {
+	sessionRepo repository.UserSessionRepository
+	logger      *slog.Logger
+}
+
+// NewSessionUsecase は新しいSessionUsecaseを作成する
+func NewSessionUsecase(sessionRepo repository.UserSessionRepository, logger *slog.Logger) *SessionUsecase {
+	return &SessionUsecase{
+		sessionRepo: sessionRepo,
+		logger:      logger,
+	}
+}
+
+// StartSession はログイン時の端末情報を記録する
+// IPアドレスはネットワーク部のみ、locationはプロキシ・CDNが付与した国コード（空の場合は記録しない）を保存する
+func (u *SessionUsecase) StartSession(ctx context.Context, userID, userAgent, ipAddress, location string, expiresAt time.Time) (*model.UserSession, error) {
+	if runes := []rune(userAgent); len(runes) > maxUserAgentLength {
+		userAgent = string(runes[:maxUserAgentLength])
+	}
+
+	now := time.Now()
+	session := &model.UserSession{
+		ID:         uuid.New().String(),
+		UserID:     userID,
+		UserAgent:  userAgent,
+		IPAddress:  model.RoughIPAddress(ipAddress),
+		CreatedAt:  now,
+		LastSeenAt: now,
+		ExpiresAt:  expiresAt,
+	}
+	if location != "" {
+		session.Location = &location
+	}
+
+	if err := u.sessionRepo.Create(ctx, session); err != nil {
+		return nil, fmt.Errorf("failed to create user session: %w", err)
+	}
+
+	u.logger.InfoContext(ctx, "user session started", "user_id", userID, "session_id", session.ID)
+	return session, nil
+}
+
+// RecordActivity はセッションの最終アクティビティを記録する（失敗はログに記録するのみ）
+func (u *SessionUsecase) RecordActivity(ctx context.Context, sessionID string) {
+	now := time.Now()
+	if err := u.sessionRepo.Touch(ctx, sessionID, now, now.Add(-sessionTouchInterval)); err != nil {
+		u.logger.WarnContext(ctx, "failed to record session activity", "error", err, "session_id", sessionID)
+	}
+}
+
+// ListSessions はユーザーの有効なセッションを一覧する（currentSessionIDのセッションにはCurrentを付ける）
+func (u *SessionUsecase) ListSessions(ctx context.Context, userID, currentSessionID string) ([]*model.UserSession, error) {
+	sessions, err := u.sessionRepo.FindActiveByUserID(ctx, userID, time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("failed to find user sessions: %w", err)
+	}
+
+	for _, s := range sessions {
+		s.Current = s.ID == currentSessionID
+	}
+	return sessions, nil
+}
+
+// EndSession はログアウト時にセッションの記録を削除する
+func (u *SessionUsecase) EndSession(ctx context.Context, sessionID string) error {
+	if err := u.sessionRepo.Delete(ctx, sessionID); err != nil {
+		return fmt.Errorf("failed to delete user session: %w", err)
+	}
+	return nil
+}
+
+// RevokeSession はユーザーのセッションを指定して失効させる（他の端末のログアウト）
+// ユーザーの有効なセッションでない場合はErrNotFoundを返す
+func (u *SessionUsecase) RevokeSession(ctx context.Context, userID, sessionID string) error {
+	sessions, err := u.sessionRepo.FindActiveByUserID(ctx, userID, time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to find user sessions: %w", err)
+	}
+	if !slices.ContainsFunc(sessions, func(s *model.UserSession) bool { return s.ID == sessionID }) {
+		return fmt.Errorf("user session %s: %w", sessionID, model.ErrNotFound)
+	}
+
+	if err := u.sessionRepo.Delete(ctx, sessionID); err != nil {
+		return fmt.Errorf("failed to delete user session: %w", err)
+	}
+
+	u.logger.InfoContext(ctx, "user session revoked", "user_id", userID, "session_id", sessionID)
+	return nil
+}
+
+// EndAllSessions はユーザーのすべてのセッションを失効させる（すべての端末からのログアウト）
+// tokenモードで発行済みのアクセストークンは有効期限まで使える
+func (u *SessionUsecase) EndAllSessions(ctx context.Context, userID string) error {
+	if err := u.sessionRepo.DeleteByUserID(ctx, userID); err != nil {
+		return fmt.Errorf("failed to delete user sessions: %w", err)
+	}
+
+	u.logger.InfoContext(ctx, "all user sessions ended", "user_id", userID)
+	return nil
+}
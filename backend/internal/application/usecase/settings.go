@@ -0,0 +1,125 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/sikigasa/github-task-controller/backend/internal/domain/model"
+	"github.com/sikigasa/github-task-controller/backend/internal/domain/repository"
+)
+
+// SettingsUsecase はユーザー（ワークスペース）設定に関するユースケース
+type SettingsUsecase struct {
+	settingsRepo repository.SettingsRepository
+	projectRepo  repository.ProjectRepository
+	logger       *slog.Logger
+}
+
+// NewSettingsUsecase は新しいSettingsUsecaseを作成する
+func NewSettingsUsecase(settingsRepo repository.SettingsRepository, projectRepo repository.ProjectRepository, logger *slog.Logger) *SettingsUsecase {
+	return &SettingsUsecase{
+		settingsRepo: settingsRepo,
+		projectRepo:  projectRepo,
+		logger:       logger,
+	}
+}
+
+// GetSettings はユーザーの設定を取得する（保存されていない場合は既定の設定を返す）
+func (u *SettingsUsecase) GetSettings(ctx context.Context, userID string) (*model.Settings, error) {
+	settings, err := findSettings(ctx, u.settingsRepo, userID)
+	if err != nil {
+		u.logger.ErrorContext(ctx, "failed to get settings", "error", err, "user_id", userID)
+		return nil, err
+	}
+
+	return settings, nil
+}
+
+// UpdateSettings はユーザーの設定を更新する
+func (u *SettingsUsecase) UpdateSettings(ctx context.Context, userID string, req *model.UpdateSettingsRequest) (*model.Settings, error) {
+	labels := req.DefaultLabels
+	if labels == nil {
+		labels = []string{}
+	}
+
+	// プッシュ通知・リマインダーの設定は省略された場合に現在の値を引き継ぐ（設定を知らない古いクライアントが無効にしないように）
+	current, err := findSettings(ctx, u.settingsRepo, userID)
+	if err != nil {
+		u.logger.ErrorContext(ctx, "failed to get settings", "error", err, "user_id", userID)
+		return nil, err
+	}
+	pushStatusChanges := current.PushStatusChanges
+	if req.PushStatusChanges != nil {
+		pushStatusChanges = *req.PushStatusChanges
+	}
+	pushReminders := current.PushReminders
+	if req.PushReminders != nil {
+		pushReminders = *req.PushReminders
+	}
+	emailReminders := current.EmailReminders
+	if req.EmailReminders != nil {
+		emailReminders = *req.EmailReminders
+	}
+	webhookReminders := current.WebhookReminders
+	if req.WebhookReminders != nil {
+		webhookReminders = *req.WebhookReminders
+	}
+	reminderDaysBefore := current.ReminderDaysBefore
+	if req.ReminderDaysBefore != nil {
+		reminderDaysBefore = *req.ReminderDaysBefore
+	}
+
+	settings := &model.Settings{
+		UserID:                userID,
+		DefaultTaskStatus:     req.DefaultTaskStatus,
+		WeekStartDay:          req.WeekStartDay,
+		Timezone:              req.Timezone,
+		DefaultGithubOwner:    req.DefaultGithubOwner,
+		DefaultLabels:         labels,
+		WeeklyDigest:          req.WeeklyDigest,
+		PushStatusChanges:     pushStatusChanges,
+		PushReminders:         pushReminders,
+		EmailReminders:        emailReminders,
+		WebhookReminders:      webhookReminders,
+		ReminderDaysBefore:    reminderDaysBefore,
+		GithubImportProjectID: req.GithubImportProjectID,
+		UpdatedAt:             time.Now(),
+	}
+	if err := settings.Validate(); err != nil {
+		return nil, err
+	}
+	// GitHub Issueの取り込み先は自分のプロジェクトのみ指定できる
+	if req.GithubImportProjectID != nil {
+		project, err := u.projectRepo.FindByID(ctx, *req.GithubImportProjectID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find github import project: %w", err)
+		}
+		if project.UserID != userID {
+			return nil, model.ErrForbidden
+		}
+	}
+
+	if err := u.settingsRepo.Upsert(ctx, settings); err != nil {
+		u.logger.ErrorContext(ctx, "failed to update settings", "error", err, "user_id", userID)
+		return nil, fmt.Errorf("failed to update settings: %w", err)
+	}
+
+	u.logger.InfoContext(ctx, "settings updated", "user_id", userID)
+	return settings, nil
+}
+
+// findSettings はユーザーの設定を取得し、保存されていない場合は既定の設定を返す
+// 設定を参照する他のユースケースからも使用する
+func findSettings(ctx context.Context, settingsRepo repository.SettingsRepository, userID string) (*model.Settings, error) {
+	settings, err := settingsRepo.FindByUserID(ctx, userID)
+	if errors.Is(err, model.ErrNotFound) {
+		return model.DefaultSettings(userID), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find settings: %w", err)
+	}
+	return settings, nil
+}
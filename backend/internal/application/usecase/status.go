@@ -0,0 +1,208 @@
+package usecase
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sikigasa/github-task-controller/backend/internal/domain/model"
+	"github.com/sikigasa/github-task-controller/backend/internal/infrastructure/github"
+)
+
+// workerGrace はジョブの実行間隔を過ぎてからstaleとみなすまでの猶予
+const workerGrace = time.Minute
+
+// databasePingTimeout はデータベースの応答を待つ時間
+const databasePingTimeout = 3 * time.Second
+
+// WorkerMonitor はバックグラウンドジョブの実行状況を記録する
+// nilの場合は記録しない
+type WorkerMonitor struct {
+	mu      sync.Mutex
+	workers map[string]*workerRecord
+}
+
+// workerRecord はジョブごとの実行状況
+type workerRecord struct {
+	interval     time.Duration
+	registeredAt time.Time
+	lastRunAt    time.Time
+	failed       bool
+}
+
+// NewWorkerMonitor は新しいWorkerMonitorを作成する
+func NewWorkerMonitor() *WorkerMonitor {
+	return &WorkerMonitor{workers: make(map[string]*workerRecord)}
+}
+
+// Register はジョブを登録する
+// intervalはジョブを実行する間隔で、2倍と猶予を過ぎても実行されない場合はstaleとみなす（0の場合は判定しない）
+func (m *WorkerMonitor) Register(name string, interval time.Duration) {
+	if m == nil {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.workers[name] = &workerRecord{interval: interval, registeredAt: time.Now()}
+}
+
+// Beat はジョブを実行したことを記録する（errは実行の結果）
+func (m *WorkerMonitor) Beat(name string, err error) {
+	if m == nil {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	record, ok := m.workers[name]
+	if !ok {
+		return
+	}
+	record.lastRunAt = time.Now()
+	record.failed = err != nil
+}
+
+// Snapshot はジョブごとの状態を名前順に返す
+func (m *WorkerMonitor) Snapshot(now time.Time) []model.WorkerStatus {
+	if m == nil {
+		return nil
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	statuses := make([]model.WorkerStatus, 0, len(m.workers))
+	for name, record := range m.workers {
+		status := model.WorkerStatus{Name: name, State: record.state(now)}
+		if !record.lastRunAt.IsZero() {
+			lastRunAt := record.lastRunAt
+			status.LastRunAt = &lastRunAt
+		}
+		statuses = append(statuses, status)
+	}
+	slices.SortFunc(statuses, func(a, b model.WorkerStatus) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	return statuses
+}
+
+// state はジョブの状態を判定する
+func (r *workerRecord) state(now time.Time) model.WorkerState {
+	last := r.lastRunAt
+	if last.IsZero() {
+		last = r.registeredAt
+	}
+	if r.interval > 0 && now.Sub(last) > 2*r.interval+workerGrace {
+		return model.WorkerStale
+	}
+	switch {
+	case r.lastRunAt.IsZero():
+		return model.WorkerStarting
+	case r.failed:
+		return model.WorkerFailing
+	default:
+		return model.WorkerOK
+	}
+}
+
+// DatabasePinger はデータベースへの接続を確認する（*sql.DBが満たす）
+type DatabasePinger interface {
+	PingContext(ctx context.Context) error
+}
+
+// StatusUsecase は認証なしで公開するデプロイの稼働状況に関するユースケース
+// データベースへの問い合わせが増えないよう、結果をcacheTTLの間使い回す
+type StatusUsecase struct {
+	db            DatabasePinger
+	githubService *github.ProjectService
+	workers       *WorkerMonitor
+	version       string
+	startedAt     time.Time
+	cacheTTL      time.Duration
+	mu            sync.Mutex
+	cached        *model.ServiceStatus
+	logger        *slog.Logger
+}
+
+// NewStatusUsecase は新しいStatusUsecaseを作成する
+// startedAtはサーバーの起動時刻
+func NewStatusUsecase(
+	db DatabasePinger,
+	githubService *github.ProjectService,
+	workers *WorkerMonitor,
+	version string,
+	startedAt time.Time,
+	cacheTTL time.Duration,
+	logger *slog.Logger,
+) *StatusUsecase {
+	return &StatusUsecase{
+		db:            db,
+		githubService: githubService,
+		workers:       workers,
+		version:       version,
+		startedAt:     startedAt,
+		cacheTTL:      cacheTTL,
+		logger:        logger,
+	}
+}
+
+// CacheTTL は稼働状況を使い回す期間を返す
+func (u *StatusUsecase) CacheTTL() time.Duration {
+	return u.cacheTTL
+}
+
+// GetStatus は稼働状況を取得する（cacheTTL以内に取得した結果があればそれを返す）
+func (u *StatusUsecase) GetStatus(ctx context.Context) *model.ServiceStatus {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	now := time.Now()
+	if u.cached != nil && now.Sub(u.cached.CheckedAt) < u.cacheTTL {
+		return u.cached
+	}
+
+	status := &model.ServiceStatus{
+		Status:        model.ServiceOK,
+		Version:       u.version,
+		StartedAt:     u.startedAt,
+		UptimeSeconds: int64(now.Sub(u.startedAt).Seconds()),
+		Database:      u.databaseStatus(ctx),
+		Workers:       u.workers.Snapshot(now),
+		CheckedAt:     now,
+	}
+
+	quota := u.githubService.QuotaSnapshot(now)
+	status.GithubAPI = model.GithubQuotaStatus{
+		TrackedTokens:   quota.TrackedTokens,
+		LowestRemaining: quota.LowestRemaining,
+		ResetAt:         quota.ResetAt,
+	}
+
+	for _, w := range status.Workers {
+		if w.State == model.WorkerFailing || w.State == model.WorkerStale {
+			status.Status = model.ServiceDegraded
+		}
+	}
+	if status.Database.Status != model.ServiceOK {
+		status.Status = model.ServiceDown
+	}
+
+	u.cached = status
+	return status
+}
+
+// databaseStatus はデータベースに接続できるかと応答時間を確認する
+func (u *StatusUsecase) databaseStatus(ctx context.Context) model.DatabaseStatus {
+	ctx, cancel := context.WithTimeout(ctx, databasePingTimeout)
+	defer cancel()
+
+	start := time.Now()
+	err := u.db.PingContext(ctx)
+	latency := float64(time.Since(start).Microseconds()) / 1000
+	if err != nil {
+		u.logger.ErrorContext(ctx, "status check failed to ping database", "error", err)
+		return model.DatabaseStatus{Status: model.ServiceDown, LatencyMS: latency}
+	}
+	return model.DatabaseStatus{Status: model.ServiceOK, LatencyMS: latency}
+}
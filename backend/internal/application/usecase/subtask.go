@@ -0,0 +1,182 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sikigasa/github-task-controller/backend/internal/domain/model"
+)
+
+// ListSubtasks はuserIDが所有するプロジェクトのタスクのサブタスクを作成順に取得する
+func (u *TaskUsecase) ListSubtasks(ctx context.Context, userID, id string) ([]*model.Task, error) {
+	if err := u.authorizeTask(ctx, userID, id); err != nil {
+		return nil, err
+	}
+
+	subtasks, err := u.taskRepo.FindByParentID(ctx, id)
+	if err != nil {
+		u.logger.ErrorContext(ctx, "failed to list subtasks", "error", err, "task_id", id)
+		return nil, fmt.Errorf("failed to list subtasks: %w", err)
+	}
+	if subtasks == nil {
+		subtasks = []*model.Task{}
+	}
+
+	return subtasks, nil
+}
+
+// validateParent は親タスクにできるタスクであることを検証し、親タスクを返す（nilは親のないタスクとして許可する）
+// 親タスクは同じプロジェクトのサブタスクでないタスクに限り、サブタスクを持つタスク（taskID）はサブタスクにできない
+// taskIDは作成するタスクの場合は空にする
+func (u *TaskUsecase) validateParent(ctx context.Context, projectID, taskID string, parentID *string) (*model.Task, error) {
+	if parentID == nil {
+		return nil, nil
+	}
+	if _, err := uuid.Parse(*parentID); err != nil {
+		return nil, fmt.Errorf("invalid parent_task_id %q: %w", *parentID, model.ErrInvalidInput)
+	}
+	if *parentID == taskID {
+		return nil, fmt.Errorf("task cannot be its own parent: %w", model.ErrInvalidInput)
+	}
+
+	parent, err := u.taskRepo.FindByID(ctx, *parentID)
+	if errors.Is(err, model.ErrNotFound) {
+		return nil, fmt.Errorf("parent task %s not found: %w", *parentID, model.ErrInvalidInput)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find parent task: %w", err)
+	}
+	if parent.ProjectID != projectID {
+		return nil, fmt.Errorf("parent task must be in the same project: %w", model.ErrInvalidInput)
+	}
+	if parent.ParentTaskID != nil {
+		return nil, fmt.Errorf("subtasks cannot have subtasks: %w", model.ErrInvalidInput)
+	}
+
+	if taskID != "" {
+		subtasks, err := u.taskRepo.FindByParentID(ctx, taskID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find subtasks: %w", err)
+		}
+		if len(subtasks) > 0 {
+			return nil, fmt.Errorf("task with subtasks cannot be a subtask: %w", model.ErrInvalidInput)
+		}
+	}
+
+	return parent, nil
+}
+
+// cascadeSubtasks はbeforeから変更したタスクをサブタスク・親タスクに連動させる
+//   - 親タスクを完了にした場合は、未完了のサブタスクも完了にする
+//   - 完了した親タスクに未完了のサブタスクを加えた（再開・移動した）場合は、親タスクを進行中に戻す
+//   - サブタスクのタイトル・ステータス・親タスクを変更した場合は、GitHubの本文のチェックリストを作り直すよう親タスクも更新する
+//
+// 連動する遷移が遷移ルールで許可されていない場合はエラーにする
+func (u *TaskUsecase) cascadeSubtasks(ctx context.Context, task, before *model.Task) error {
+	if task.Status == model.TaskStatusDone && before.Status != model.TaskStatusDone {
+		if err := u.completeSubtasks(ctx, task); err != nil {
+			return err
+		}
+	}
+
+	var parentIDs []string
+	switch {
+	case !sameTaskID(before.ParentTaskID, task.ParentTaskID):
+		if before.ParentTaskID != nil {
+			parentIDs = append(parentIDs, *before.ParentTaskID)
+		}
+		if task.ParentTaskID != nil {
+			parentIDs = append(parentIDs, *task.ParentTaskID)
+		}
+	case task.ParentTaskID != nil && (task.Title != before.Title || task.Status != before.Status):
+		parentIDs = append(parentIDs, *task.ParentTaskID)
+	}
+
+	for _, parentID := range parentIDs {
+		parent, err := u.taskRepo.FindByID(ctx, parentID)
+		if err != nil {
+			return fmt.Errorf("failed to find parent task: %w", err)
+		}
+		var subtask *model.Task
+		if task.ParentTaskID != nil && *task.ParentTaskID == parentID {
+			subtask = task
+		}
+		if err := u.updateParent(ctx, parent, subtask); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// completeSubtasks は親タスクの未完了のサブタスクを完了にする
+func (u *TaskUsecase) completeSubtasks(ctx context.Context, parent *model.Task) error {
+	subtasks, err := u.taskRepo.FindByParentID(ctx, parent.ID)
+	if err != nil {
+		return fmt.Errorf("failed to find subtasks: %w", err)
+	}
+
+	for _, subtask := range subtasks {
+		if subtask.Status == model.TaskStatusDone {
+			continue
+		}
+		before := *subtask
+		if err := u.policy.Validate(subtask.Status, model.TaskStatusDone, false); err != nil {
+			return fmt.Errorf("failed to complete subtask %s: %w", subtask.ID, err)
+		}
+
+		subtask.Status = model.TaskStatusDone
+		subtask.UpdatedAt = time.Now()
+		if err := u.writeTask(ctx, subtask, &before, false); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// updateParent はサブタスクの追加・変更・削除を親タスクに反映する（削除・移動で外した場合のsubtaskはnil）
+// 完了した親タスクにsubtaskが未完了で加わった場合は親タスクを進行中に戻し、それ以外は更新日時だけを更新する
+func (u *TaskUsecase) updateParent(ctx context.Context, parent, subtask *model.Task) error {
+	before := *parent
+	reopened := false
+	if subtask != nil && parent.Status == model.TaskStatusDone && subtask.Status != model.TaskStatusDone {
+		if err := u.policy.Validate(parent.Status, model.TaskStatusInProgress, true); err != nil {
+			return fmt.Errorf("failed to reopen parent task %s: %w", parent.ID, err)
+		}
+		parent.Status = model.TaskStatusInProgress
+		reopened = true
+	}
+
+	parent.UpdatedAt = time.Now()
+	return u.writeTask(ctx, parent, &before, reopened)
+}
+
+// detachSubtasks は削除するタスクのサブタスクを親のないタスクに戻す
+func (u *TaskUsecase) detachSubtasks(ctx context.Context, task *model.Task) error {
+	subtasks, err := u.taskRepo.FindByParentID(ctx, task.ID)
+	if err != nil {
+		return fmt.Errorf("failed to find subtasks: %w", err)
+	}
+
+	for _, subtask := range subtasks {
+		before := *subtask
+		subtask.ParentTaskID = nil
+		subtask.UpdatedAt = time.Now()
+		if err := u.writeTask(ctx, subtask, &before, false); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// sameTaskID は2つのタスクIDの参照が同じタスクを指すか（両方nilの場合も含む）を返す
+func sameTaskID(a, b *string) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
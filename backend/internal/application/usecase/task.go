@@ -2,6 +2,7 @@ package usecase
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 	"time"
@@ -9,60 +10,194 @@ import (
 	"github.com/google/uuid"
 	"github.com/sikigasa/github-task-controller/backend/internal/domain/model"
 	"github.com/sikigasa/github-task-controller/backend/internal/domain/repository"
+	"github.com/sikigasa/github-task-controller/backend/internal/infrastructure/github"
 )
 
+// MaxTaskListLimit はタスク一覧で1回に取得できる最大件数
+const MaxTaskListLimit = 500
+
 // TaskUsecase はタスクに関するユースケース
 type TaskUsecase struct {
-	taskRepo repository.TaskRepository
-	logger   *slog.Logger
+	taskRepo        repository.TaskRepository
+	projectRepo     repository.ProjectRepository
+	statusEventRepo repository.TaskStatusEventRepository
+	depRepo         repository.TaskDependencyRepository
+	relationRepo    repository.TaskRelationRepository
+	milestoneRepo   repository.MilestoneRepository
+	settingsRepo    repository.SettingsRepository
+	events          EventBus
+	activity        ActivityRecorder
+	tx              repository.Transactor
+	policy          *model.TaskTransitionPolicy
+	logger          *slog.Logger
 }
 
 // NewTaskUsecase は新しいTaskUsecaseを作成する
-func NewTaskUsecase(taskRepo repository.TaskRepository, logger *slog.Logger) *TaskUsecase {
+// policyがnilの場合はデフォルトの遷移ルールを使用する
+func NewTaskUsecase(
+	taskRepo repository.TaskRepository,
+	projectRepo repository.ProjectRepository,
+	statusEventRepo repository.TaskStatusEventRepository,
+	depRepo repository.TaskDependencyRepository,
+	relationRepo repository.TaskRelationRepository,
+	milestoneRepo repository.MilestoneRepository,
+	settingsRepo repository.SettingsRepository,
+	events EventBus,
+	activity ActivityRecorder,
+	tx repository.Transactor,
+	policy *model.TaskTransitionPolicy,
+	logger *slog.Logger,
+) *TaskUsecase {
+	if policy == nil {
+		policy = model.DefaultTaskTransitionPolicy()
+	}
 	return &TaskUsecase{
-		taskRepo: taskRepo,
-		logger:   logger,
+		taskRepo:        taskRepo,
+		projectRepo:     projectRepo,
+		statusEventRepo: statusEventRepo,
+		depRepo:         depRepo,
+		relationRepo:    relationRepo,
+		milestoneRepo:   milestoneRepo,
+		settingsRepo:    settingsRepo,
+		events:          events,
+		activity:        activity,
+		tx:              tx,
+		policy:          policy,
+		logger:          logger,
 	}
 }
 
-// CreateTask は新しいタスクを作成する
-func (u *TaskUsecase) CreateTask(ctx context.Context, projectID, title, description string, status model.TaskStatus, priority model.TaskPriority, endDate *time.Time) (*model.Task, error) {
+// CreateTask はuserIDが所有するプロジェクトに新しいタスクを作成する
+// ステータスが省略された場合はプロジェクト所有者の設定のデフォルトステータスを使用する
+// 親タスクを指定した場合はサブタスクとして作成し、完了した親タスクに未完了のサブタスクを加えた場合は親タスクを進行中に戻す
+func (u *TaskUsecase) CreateTask(ctx context.Context, userID string, req *model.CreateTaskRequest) (*model.Task, error) {
+	if err := u.authorizeProject(ctx, userID, req.ProjectID); err != nil {
+		return nil, err
+	}
+	return u.createTask(ctx, req)
+}
+
+// createTask は所有者を確認せずにタスクを作成する（Issueの取り込み等のシステムの処理で使う）
+func (u *TaskUsecase) createTask(ctx context.Context, req *model.CreateTaskRequest) (*model.Task, error) {
+	if req.Status != nil && !req.Status.IsValid() {
+		return nil, fmt.Errorf("invalid task status %d: %w", int(*req.Status), model.ErrInvalidInput)
+	}
+	priority := model.DefaultTaskPriority
+	if req.Priority != nil {
+		priority = *req.Priority
+	}
+	if err := model.ValidatePriority(priority); err != nil {
+		return nil, err
+	}
+	if err := model.ValidateEstimate(req.Estimate); err != nil {
+		return nil, err
+	}
+	if err := model.ValidateSchedule(req.StartDate, req.EndDate); err != nil {
+		return nil, err
+	}
+	if err := u.validateMilestone(ctx, req.ProjectID, req.MilestoneID); err != nil {
+		return nil, err
+	}
+	parent, err := u.validateParent(ctx, req.ProjectID, "", req.ParentTaskID)
+	if err != nil {
+		return nil, err
+	}
+
+	status, err := u.initialStatus(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
 	now := time.Now()
 	task := &model.Task{
-		ID:          uuid.New().String(),
-		ProjectID:   projectID,
-		Title:       title,
-		Description: description,
-		Status:      status,
-		Priority:    priority,
-		EndDate:     endDate,
-		CreatedAt:   now,
-		UpdatedAt:   now,
-	}
-
-	if err := u.taskRepo.Create(ctx, task); err != nil {
+		ID:           uuid.New().String(),
+		ProjectID:    req.ProjectID,
+		Title:        req.Title,
+		Description:  req.Description,
+		Status:       status,
+		Priority:     priority,
+		StartDate:    req.StartDate,
+		EndDate:      req.EndDate,
+		Estimate:     req.Estimate,
+		MilestoneID:  req.MilestoneID,
+		ParentTaskID: req.ParentTaskID,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+
+	err = u.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if err := u.taskRepo.Create(ctx, task); err != nil {
+			return err
+		}
+		if err := u.events.Publish(ctx, model.EventTaskCreated, model.AggregateTask, task.ID, task); err != nil {
+			return err
+		}
+		if err := u.activity.Record(ctx, task.ProjectID, model.ActivityEntityTask, task.ID, model.ActivityActionCreate, nil, task); err != nil {
+			return err
+		}
+		if err := u.recordTransition(ctx, task, nil, false); err != nil {
+			return err
+		}
+		if parent == nil {
+			return nil
+		}
+		return u.updateParent(ctx, parent, task)
+	})
+	if err != nil {
 		u.logger.ErrorContext(ctx, "failed to create task", "error", err)
 		return nil, fmt.Errorf("failed to create task: %w", err)
 	}
 
-	u.logger.InfoContext(ctx, "task created", "task_id", task.ID, "project_id", projectID)
+	u.logger.InfoContext(ctx, "task created", "task_id", task.ID, "project_id", task.ProjectID)
 	return task, nil
 }
 
-// GetTask はIDでタスクを取得する
-func (u *TaskUsecase) GetTask(ctx context.Context, id string) (*model.Task, error) {
-	task, err := u.taskRepo.FindByID(ctx, id)
+// initialStatus は作成するタスクのステータスを決定する
+func (u *TaskUsecase) initialStatus(ctx context.Context, req *model.CreateTaskRequest) (model.TaskStatus, error) {
+	if req.Status != nil {
+		return *req.Status, nil
+	}
+
+	project, err := u.projectRepo.FindByID(ctx, req.ProjectID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to find project: %w", err)
+	}
+	settings, err := findSettings(ctx, u.settingsRepo, project.UserID)
 	if err != nil {
-		u.logger.ErrorContext(ctx, "failed to get task", "error", err, "task_id", id)
-		return nil, fmt.Errorf("failed to get task: %w", err)
+		u.logger.ErrorContext(ctx, "failed to get settings for task creation", "error", err, "project_id", req.ProjectID)
+		return 0, err
 	}
 
-	return task, nil
+	return settings.DefaultTaskStatus, nil
+}
+
+// GetTask はuserIDが所有するプロジェクトのタスクをIDで取得する
+func (u *TaskUsecase) GetTask(ctx context.Context, userID, id string) (*model.Task, error) {
+	return u.findAuthorized(ctx, userID, id)
 }
 
-// ListTasksByProjectID はプロジェクトIDで全タスクを取得する
-func (u *TaskUsecase) ListTasksByProjectID(ctx context.Context, projectID string) ([]*model.Task, error) {
-	tasks, err := u.taskRepo.FindByProjectID(ctx, projectID)
+// ListTasksByProjectID はuserIDが所有するプロジェクトのタスクのうちfilterに一致するものを取得する
+// opts.Limitが0の場合は一致するタスクをすべて取得する
+func (u *TaskUsecase) ListTasksByProjectID(ctx context.Context, userID, projectID string, filter model.TaskFilter, opts model.ListOptions) ([]*model.Task, error) {
+	if err := filter.Validate(); err != nil {
+		return nil, err
+	}
+	if filter.MilestoneID != nil {
+		if _, err := uuid.Parse(*filter.MilestoneID); err != nil {
+			return nil, fmt.Errorf("invalid milestone_id %q: %w", *filter.MilestoneID, model.ErrInvalidInput)
+		}
+	}
+	if opts.Limit < 0 || opts.Limit > MaxTaskListLimit {
+		return nil, fmt.Errorf("limit must be between 0 and %d: %w", MaxTaskListLimit, model.ErrInvalidInput)
+	}
+	if opts.Offset < 0 {
+		return nil, fmt.Errorf("offset must not be negative: %w", model.ErrInvalidInput)
+	}
+	if err := u.authorizeProject(ctx, userID, projectID); err != nil {
+		return nil, err
+	}
+
+	tasks, err := u.taskRepo.FindByProjectID(ctx, projectID, filter, opts)
 	if err != nil {
 		u.logger.ErrorContext(ctx, "failed to list tasks", "error", err, "project_id", projectID)
 		return nil, fmt.Errorf("failed to list tasks: %w", err)
@@ -72,32 +207,499 @@ func (u *TaskUsecase) ListTasksByProjectID(ctx context.Context, projectID string
 }
 
 // UpdateTask はタスク情報を更新する
-func (u *TaskUsecase) UpdateTask(ctx context.Context, id, title, description string, status model.TaskStatus, priority model.TaskPriority, endDate *time.Time) (*model.Task, error) {
+// 完了済みタスクのステータスを戻す場合はreopenを指定する必要がある
+// req.Versionが現在のバージョンと異なる場合は現在のタスクを付けたmodel.StaleVersionErrorを返す
+func (u *TaskUsecase) UpdateTask(ctx context.Context, userID, id string, req *model.UpdateTaskRequest) (*model.Task, error) {
+	task, err := u.findAuthorized(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	before := *task
+	if err := u.policy.Validate(before.Status, req.Status, req.Reopen); err != nil {
+		return nil, err
+	}
+	if err := model.ValidatePriority(req.Priority); err != nil {
+		return nil, err
+	}
+	if err := model.ValidateEstimate(req.Estimate); err != nil {
+		return nil, err
+	}
+	if err := model.ValidateSchedule(req.StartDate, req.EndDate); err != nil {
+		return nil, err
+	}
+	if err := u.validateMilestone(ctx, task.ProjectID, req.MilestoneID); err != nil {
+		return nil, err
+	}
+
+	task.Title = req.Title
+	task.Description = req.Description
+	task.Status = req.Status
+	task.Priority = req.Priority
+	task.StartDate = req.StartDate
+	task.EndDate = req.EndDate
+	task.Estimate = req.Estimate
+	task.MilestoneID = req.MilestoneID
+	task.UpdatedAt = time.Now()
+	if req.Version != nil {
+		task.Version = *req.Version
+	}
+
+	if err := u.saveTask(ctx, task, &before, req.Reopen); err != nil {
+		if errors.Is(err, model.ErrStaleVersion) {
+			return nil, u.staleTaskError(ctx, id)
+		}
+		u.logger.ErrorContext(ctx, "failed to update task", "error", err, "task_id", id)
+		return nil, fmt.Errorf("failed to update task: %w", err)
+	}
+
+	u.logger.InfoContext(ctx, "task updated", "task_id", id)
+	return task, nil
+}
+
+// PatchTask はuserIDが所有するプロジェクトのタスクを、リクエストに含まれるフィールドのみ更新する
+// req.Versionが現在のバージョンと異なる場合は現在のタスクを付けたmodel.StaleVersionErrorを返す
+func (u *TaskUsecase) PatchTask(ctx context.Context, userID, id string, req *model.PatchTaskRequest) (*model.Task, error) {
+	if err := u.authorizeTask(ctx, userID, id); err != nil {
+		return nil, err
+	}
+	return u.patchTask(ctx, id, req)
+}
+
+// patchTask は所有者を確認せずにタスクを部分更新する（GitHubの変更の反映等のシステムの処理で使う）
+func (u *TaskUsecase) patchTask(ctx context.Context, id string, req *model.PatchTaskRequest) (*model.Task, error) {
 	task, err := u.taskRepo.FindByID(ctx, id)
 	if err != nil {
 		u.logger.ErrorContext(ctx, "failed to find task", "error", err, "task_id", id)
 		return nil, fmt.Errorf("failed to find task: %w", err)
 	}
 
-	task.Title = title
-	task.Description = description
-	task.Status = status
-	task.Priority = priority
-	task.EndDate = endDate
+	before := *task
+	if req.Status != nil {
+		if err := u.policy.Validate(before.Status, *req.Status, req.Reopen); err != nil {
+			return nil, err
+		}
+	}
+	if req.Priority != nil {
+		if err := model.ValidatePriority(*req.Priority); err != nil {
+			return nil, err
+		}
+	}
+	if err := model.ValidateEstimate(req.Estimate.Value); err != nil {
+		return nil, err
+	}
+
+	if req.Title != nil {
+		task.Title = *req.Title
+	}
+	if req.Description != nil {
+		task.Description = *req.Description
+	}
+	if req.Status != nil {
+		task.Status = *req.Status
+	}
+	if req.Priority != nil {
+		task.Priority = *req.Priority
+	}
+	if req.StartDate.Set {
+		task.StartDate = req.StartDate.Value
+	}
+	if req.EndDate.Set {
+		task.EndDate = req.EndDate.Value
+	}
+	if err := model.ValidateSchedule(task.StartDate, task.EndDate); err != nil {
+		return nil, err
+	}
+	if req.Estimate.Set {
+		task.Estimate = req.Estimate.Value
+	}
+	if req.MilestoneID.Set {
+		if err := u.validateMilestone(ctx, task.ProjectID, req.MilestoneID.Value); err != nil {
+			return nil, err
+		}
+		task.MilestoneID = req.MilestoneID.Value
+	}
+	if req.ParentTaskID.Set {
+		if _, err := u.validateParent(ctx, task.ProjectID, task.ID, req.ParentTaskID.Value); err != nil {
+			return nil, err
+		}
+		task.ParentTaskID = req.ParentTaskID.Value
+	}
 	task.UpdatedAt = time.Now()
+	if req.Version != nil {
+		task.Version = *req.Version
+	}
+
+	if err := u.saveTask(ctx, task, &before, req.Reopen); err != nil {
+		if errors.Is(err, model.ErrStaleVersion) {
+			return nil, u.staleTaskError(ctx, id)
+		}
+		u.logger.ErrorContext(ctx, "failed to patch task", "error", err, "task_id", id)
+		return nil, fmt.Errorf("failed to patch task: %w", err)
+	}
+
+	u.logger.InfoContext(ctx, "task patched", "task_id", id)
+	return task, nil
+}
 
+// saveTask はbeforeから変更したタスクを更新し、サブタスク・親タスクへの連動（cascadeSubtasksを参照）と合わせて同じトランザクションで書き込む
+func (u *TaskUsecase) saveTask(ctx context.Context, task, before *model.Task, reopened bool) error {
+	return u.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if err := u.writeTask(ctx, task, before, reopened); err != nil {
+			return err
+		}
+		return u.cascadeSubtasks(ctx, task, before)
+	})
+}
+
+// staleTaskError は他の更新と競合したタスクの現在の状態を付けたmodel.StaleVersionErrorを返す
+func (u *TaskUsecase) staleTaskError(ctx context.Context, id string) error {
+	current, err := u.taskRepo.FindByID(ctx, id)
+	if err != nil {
+		return fmt.Errorf("failed to find task: %w", err)
+	}
+	return &model.StaleVersionError{Current: current}
+}
+
+// writeTask はbeforeから変更したタスクを更新し、task.updatedのイベントとアクティビティを書き込む
+// ステータスが変わった場合はtask.status_changedのイベントとステータス遷移イベントも書き込む
+func (u *TaskUsecase) writeTask(ctx context.Context, task, before *model.Task, reopened bool) error {
 	if err := u.taskRepo.Update(ctx, task); err != nil {
-		u.logger.ErrorContext(ctx, "failed to update task", "error", err, "task_id", id)
-		return nil, fmt.Errorf("failed to update task: %w", err)
+		return err
+	}
+	if err := u.events.Publish(ctx, model.EventTaskUpdated, model.AggregateTask, task.ID, task); err != nil {
+		return err
+	}
+	if err := u.activity.Record(ctx, task.ProjectID, model.ActivityEntityTask, task.ID, model.ActivityActionUpdate, before, task); err != nil {
+		return err
+	}
+	from := before.Status
+	if from == task.Status {
+		return nil
 	}
+	payload := model.TaskStatusChangedPayload{Task: task, FromStatus: &from, Reopened: reopened}
+	if err := u.events.Publish(ctx, model.EventTaskStatusChanged, model.AggregateTask, task.ID, payload); err != nil {
+		return err
+	}
+	return u.recordTransition(ctx, task, &from, reopened)
+}
 
-	u.logger.InfoContext(ctx, "task updated", "task_id", id)
+// ListStatusEvents はuserIDが所有するプロジェクトのタスクのステータス遷移履歴を取得する
+func (u *TaskUsecase) ListStatusEvents(ctx context.Context, userID, taskID string) ([]*model.TaskStatusEvent, error) {
+	if err := u.authorizeTask(ctx, userID, taskID); err != nil {
+		return nil, err
+	}
+
+	events, err := u.statusEventRepo.FindByTaskID(ctx, taskID)
+	if err != nil {
+		u.logger.ErrorContext(ctx, "failed to list task status events", "error", err, "task_id", taskID)
+		return nil, fmt.Errorf("failed to list task status events: %w", err)
+	}
+
+	return events, nil
+}
+
+// AddDependency はタスクに依存関係（dependsOnTaskIDの完了後に開始する）を追加する
+// 依存先は同じプロジェクトのタスクに限り、循環する依存関係は追加できない
+func (u *TaskUsecase) AddDependency(ctx context.Context, userID, taskID, dependsOnTaskID string) (*model.TaskDependency, error) {
+	if taskID == dependsOnTaskID {
+		return nil, fmt.Errorf("task cannot depend on itself: %w", model.ErrInvalidInput)
+	}
+
+	task, err := u.findAuthorized(ctx, userID, taskID)
+	if err != nil {
+		return nil, err
+	}
+	dependsOn, err := u.taskRepo.FindByID(ctx, dependsOnTaskID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find dependency task: %w", err)
+	}
+	if task.ProjectID != dependsOn.ProjectID {
+		return nil, fmt.Errorf("dependency must be in the same project: %w", model.ErrInvalidInput)
+	}
+
+	deps, err := u.depRepo.FindByProjectID(ctx, task.ProjectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find dependencies: %w", err)
+	}
+	// 依存先から依存元へ到達できる場合、追加すると循環する
+	if model.HasDependencyPath(deps, dependsOnTaskID, taskID) {
+		return nil, model.ErrDependencyCycle
+	}
+
+	dep := &model.TaskDependency{
+		TaskID:          taskID,
+		DependsOnTaskID: dependsOnTaskID,
+		ProjectID:       task.ProjectID,
+		CreatedAt:       time.Now(),
+	}
+	if err := u.depRepo.Create(ctx, dep); err != nil {
+		u.logger.ErrorContext(ctx, "failed to add task dependency", "error", err, "task_id", taskID)
+		return nil, fmt.Errorf("failed to add task dependency: %w", err)
+	}
+
+	u.logger.InfoContext(ctx, "task dependency added", "task_id", taskID, "depends_on_task_id", dependsOnTaskID)
+	return dep, nil
+}
+
+// RemoveDependency はuserIDが所有するプロジェクトのタスクの依存関係を削除する
+func (u *TaskUsecase) RemoveDependency(ctx context.Context, userID, taskID, dependsOnTaskID string) error {
+	if err := u.authorizeTask(ctx, userID, taskID); err != nil {
+		return err
+	}
+
+	if err := u.depRepo.Delete(ctx, taskID, dependsOnTaskID); err != nil {
+		u.logger.ErrorContext(ctx, "failed to remove task dependency", "error", err, "task_id", taskID)
+		return fmt.Errorf("failed to remove task dependency: %w", err)
+	}
+
+	u.logger.InfoContext(ctx, "task dependency removed", "task_id", taskID, "depends_on_task_id", dependsOnTaskID)
+	return nil
+}
+
+// GetTaskDetail は関連・サブタスクを含むタスクの詳細を取得する
+// 関連は両端のタスクのいずれから取得しても、そのタスクから見た種類で含まれる
+func (u *TaskUsecase) GetTaskDetail(ctx context.Context, userID, id string) (*model.TaskDetail, error) {
+	task, err := u.GetTask(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	links, err := u.relationRepo.FindLinksByTaskID(ctx, id)
+	if err != nil {
+		u.logger.ErrorContext(ctx, "failed to get task relations", "error", err, "task_id", id)
+		return nil, fmt.Errorf("failed to get task relations: %w", err)
+	}
+	subtasks, err := u.taskRepo.FindByParentID(ctx, id)
+	if err != nil {
+		u.logger.ErrorContext(ctx, "failed to get subtasks", "error", err, "task_id", id)
+		return nil, fmt.Errorf("failed to get subtasks: %w", err)
+	}
+	if subtasks == nil {
+		subtasks = []*model.Task{}
+	}
+
+	return &model.TaskDetail{Task: task, Relations: links, Subtasks: subtasks}, nil
+}
+
+// FindTasksByGithub はGitHubのIssueまたはProjectのItemに対応する、userIDが所有するプロジェクトのタスクを検索する
+// 同じIssueを複数のプロジェクトで取り込んでいる場合はすべて返す（該当がない場合は空）
+func (u *TaskUsecase) FindTasksByGithub(ctx context.Context, userID string, lookup model.GithubTaskLookup) ([]*model.Task, error) {
+	var tasks []*model.Task
+	var err error
+	switch {
+	case lookup.IssueURL != "" || lookup.Owner != "" || lookup.Repo != "" || lookup.Number != 0:
+		owner, repo, number := lookup.Owner, lookup.Repo, lookup.Number
+		if lookup.IssueURL != "" {
+			owner, repo, number, err = github.ParseIssueURL(lookup.IssueURL)
+			if err != nil {
+				return nil, fmt.Errorf("%w: %w", err, model.ErrInvalidInput)
+			}
+		}
+		if owner == "" || repo == "" || number <= 0 {
+			return nil, fmt.Errorf("owner, repo and a positive number are required: %w", model.ErrInvalidInput)
+		}
+		tasks, err = u.taskRepo.FindByGithubIssue(ctx, number, fmt.Sprintf("https://github.com/%s/%s/issues/%d", owner, repo, number))
+	case lookup.ItemID != "":
+		tasks, err = u.taskRepo.FindByGithubLink(ctx, lookup.ItemID, "")
+	default:
+		return nil, fmt.Errorf("issue_url, owner/repo/number or item_id is required: %w", model.ErrInvalidInput)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find tasks by github: %w", err)
+	}
+
+	// 他のユーザーのプロジェクトのタスクは除く（プロジェクトごとに1回だけ所有者を確認する）
+	owned := make(map[string]bool)
+	result := []*model.Task{}
+	for _, task := range tasks {
+		ok, checked := owned[task.ProjectID]
+		if !checked {
+			project, err := u.projectRepo.FindByID(ctx, task.ProjectID)
+			if err != nil {
+				return nil, fmt.Errorf("failed to find project: %w", err)
+			}
+			ok = project.UserID == userID
+			owned[task.ProjectID] = ok
+		}
+		if ok {
+			result = append(result, task)
+		}
+	}
+
+	return result, nil
+}
+
+// AddRelation はタスクに関連を追加する
+// 関連先は別プロジェクトのタスクでもよいが、両方のプロジェクトをuserIDが所有している必要がある
+func (u *TaskUsecase) AddRelation(ctx context.Context, userID, taskID string, req *model.AddTaskRelationRequest) (*model.TaskRelation, error) {
+	relation, err := model.NewTaskRelation(taskID, req.RelatedTaskID, req.Type)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, id := range []string{taskID, req.RelatedTaskID} {
+		if err := u.authorizeTask(ctx, userID, id); err != nil {
+			return nil, err
+		}
+	}
+
+	relation.CreatedAt = time.Now()
+	if err := u.relationRepo.Create(ctx, relation); err != nil {
+		u.logger.ErrorContext(ctx, "failed to add task relation", "error", err, "task_id", taskID)
+		return nil, fmt.Errorf("failed to add task relation: %w", err)
+	}
+
+	u.logger.InfoContext(ctx, "task relation added", "task_id", taskID, "related_task_id", req.RelatedTaskID, "type", req.Type)
+	return relation, nil
+}
+
+// RemoveRelation はタスクの関連を削除する（関連のどちら側のタスクからでも削除できる）
+func (u *TaskUsecase) RemoveRelation(ctx context.Context, userID, taskID, relatedTaskID string, relationType model.TaskRelationType) error {
+	relation, err := model.NewTaskRelation(taskID, relatedTaskID, relationType)
+	if err != nil {
+		return err
+	}
+
+	if err := u.authorizeTask(ctx, userID, taskID); err != nil {
+		return err
+	}
+
+	if err := u.relationRepo.Delete(ctx, relation); err != nil {
+		u.logger.ErrorContext(ctx, "failed to remove task relation", "error", err, "task_id", taskID)
+		return fmt.Errorf("failed to remove task relation: %w", err)
+	}
+
+	u.logger.InfoContext(ctx, "task relation removed", "task_id", taskID, "related_task_id", relatedTaskID, "type", relationType)
+	return nil
+}
+
+// authorizeTask はタスクが属するプロジェクトをuserIDが所有していることを確認する
+func (u *TaskUsecase) authorizeTask(ctx context.Context, userID, taskID string) error {
+	if err := validateResourceID(taskID); err != nil {
+		return err
+	}
+
+	ownerID, err := u.taskRepo.FindOwnerID(ctx, taskID)
+	if err != nil {
+		return fmt.Errorf("failed to find task owner: %w", err)
+	}
+	if ownerID != userID {
+		return model.ErrForbidden
+	}
+
+	return nil
+}
+
+// authorizeProject はプロジェクトをuserIDが所有していることを確認する
+func (u *TaskUsecase) authorizeProject(ctx context.Context, userID, projectID string) error {
+	if err := validateResourceID(projectID); err != nil {
+		return err
+	}
+
+	project, err := u.projectRepo.FindByID(ctx, projectID)
+	if err != nil {
+		return fmt.Errorf("failed to find project: %w", err)
+	}
+	if project.UserID != userID {
+		return model.ErrForbidden
+	}
+
+	return nil
+}
+
+// findAuthorized はタスクを取得し、所属プロジェクトをuserIDが所有していることを確認する
+func (u *TaskUsecase) findAuthorized(ctx context.Context, userID, id string) (*model.Task, error) {
+	if err := u.authorizeTask(ctx, userID, id); err != nil {
+		return nil, err
+	}
+
+	task, err := u.taskRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find task: %w", err)
+	}
 	return task, nil
 }
 
-// DeleteTask はタスクを削除する
-func (u *TaskUsecase) DeleteTask(ctx context.Context, id string) error {
-	if err := u.taskRepo.Delete(ctx, id); err != nil {
+// validateMilestone はマイルストーンがタスクと同じプロジェクトに属することを検証する（nilは未割り当てとして許可する）
+func (u *TaskUsecase) validateMilestone(ctx context.Context, projectID string, milestoneID *string) error {
+	if milestoneID == nil {
+		return nil
+	}
+
+	milestone, err := u.milestoneRepo.FindByID(ctx, *milestoneID)
+	if errors.Is(err, model.ErrNotFound) {
+		return fmt.Errorf("milestone %s not found: %w", *milestoneID, model.ErrInvalidInput)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to find milestone: %w", err)
+	}
+	if milestone.ProjectID != projectID {
+		return fmt.Errorf("milestone must be in the same project: %w", model.ErrInvalidInput)
+	}
+
+	return nil
+}
+
+// recordTransition はステータス遷移イベントを記録する（遷移の通知はイベントバスのtask.status_changedで行う）
+// レポート等の遷移履歴がタスクのステータスと食い違わないよう、ステータスを書き込むトランザクションの中で呼び出す
+func (u *TaskUsecase) recordTransition(ctx context.Context, task *model.Task, from *model.TaskStatus, reopened bool) error {
+	event := &model.TaskStatusEvent{
+		ID:         uuid.New().String(),
+		TaskID:     task.ID,
+		ProjectID:  task.ProjectID,
+		FromStatus: from,
+		ToStatus:   task.Status,
+		Reopened:   reopened,
+		ChangedAt:  task.UpdatedAt,
+	}
+
+	if err := u.statusEventRepo.Create(ctx, event); err != nil {
+		return fmt.Errorf("failed to record task status event: %w", err)
+	}
+	return nil
+}
+
+// DeleteTask はuserIDが所有するプロジェクトのタスクをゴミ箱に移す
+func (u *TaskUsecase) DeleteTask(ctx context.Context, userID, id string) error {
+	if err := u.authorizeTask(ctx, userID, id); err != nil {
+		return err
+	}
+	return u.deleteTask(ctx, id)
+}
+
+// deleteTask は所有者を確認せずにタスクをゴミ箱に移す（GitHub上で削除された連携先の反映等のシステムの処理で使う）
+// サブタスクは削除せずに親のないタスクに戻す（タスクを元に戻してもサブタスクには戻らない）
+func (u *TaskUsecase) deleteTask(ctx context.Context, id string) error {
+	err := u.tx.WithinTx(ctx, func(ctx context.Context) error {
+		task, err := u.taskRepo.FindByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if err := u.detachSubtasks(ctx, task); err != nil {
+			return err
+		}
+		if err := u.taskRepo.Delete(ctx, id, time.Now()); err != nil {
+			return err
+		}
+		payload := model.TaskDeletedPayload{ID: task.ID, ProjectID: task.ProjectID}
+		if err := u.events.Publish(ctx, model.EventTaskDeleted, model.AggregateTask, task.ID, payload); err != nil {
+			return err
+		}
+		if err := u.activity.Record(ctx, task.ProjectID, model.ActivityEntityTask, task.ID, model.ActivityActionDelete, task, nil); err != nil {
+			return err
+		}
+		if task.ParentTaskID == nil {
+			return nil
+		}
+		// 親タスクのチェックリストから除く
+		parent, err := u.taskRepo.FindByID(ctx, *task.ParentTaskID)
+		if err != nil {
+			return fmt.Errorf("failed to find parent task: %w", err)
+		}
+		return u.updateParent(ctx, parent, nil)
+	})
+	if err != nil {
 		u.logger.ErrorContext(ctx, "failed to delete task", "error", err, "task_id", id)
 		return fmt.Errorf("failed to delete task: %w", err)
 	}
@@ -105,3 +707,75 @@ func (u *TaskUsecase) DeleteTask(ctx context.Context, id string) error {
 	u.logger.InfoContext(ctx, "task deleted", "task_id", id)
 	return nil
 }
+
+// RestoreTask はuserIDが所有するプロジェクトのゴミ箱のタスクを元に戻す
+// 親タスクが削除されている・サブタスクになっている場合は親のないタスクとして戻す
+// プロジェクトごとゴミ箱に移したタスクはプロジェクトを元に戻す（プロジェクトがゴミ箱にある場合はErrNotFound）
+func (u *TaskUsecase) RestoreTask(ctx context.Context, userID, id string) (*model.Task, error) {
+	if err := validateResourceID(id); err != nil {
+		return nil, err
+	}
+
+	var task *model.Task
+	err := u.tx.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		task, err = u.taskRepo.FindDeletedByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if err := u.authorizeProject(ctx, userID, task.ProjectID); err != nil {
+			return err
+		}
+
+		parent, err := u.restorableParent(ctx, task)
+		if err != nil {
+			return err
+		}
+		if parent == nil {
+			task.ParentTaskID = nil
+		}
+		task.DeletedAt = nil
+		task.UpdatedAt = time.Now()
+		if err := u.taskRepo.Restore(ctx, task); err != nil {
+			return err
+		}
+		if err := u.events.Publish(ctx, model.EventTaskRestored, model.AggregateTask, task.ID, task); err != nil {
+			return err
+		}
+		if err := u.activity.Record(ctx, task.ProjectID, model.ActivityEntityTask, task.ID, model.ActivityActionRestore, nil, task); err != nil {
+			return err
+		}
+		if parent == nil {
+			return nil
+		}
+		return u.updateParent(ctx, parent, task)
+	})
+	if errors.Is(err, model.ErrForbidden) || errors.Is(err, model.ErrNotFound) {
+		return nil, err
+	}
+	if err != nil {
+		u.logger.ErrorContext(ctx, "failed to restore task", "error", err, "task_id", id)
+		return nil, fmt.Errorf("failed to restore task: %w", err)
+	}
+
+	u.logger.InfoContext(ctx, "task restored", "task_id", id)
+	return task, nil
+}
+
+// restorableParent は元に戻すタスクの親タスクを返す（親に戻せない場合はnil）
+func (u *TaskUsecase) restorableParent(ctx context.Context, task *model.Task) (*model.Task, error) {
+	if task.ParentTaskID == nil {
+		return nil, nil
+	}
+	parent, err := u.taskRepo.FindByID(ctx, *task.ParentTaskID)
+	if errors.Is(err, model.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find parent task: %w", err)
+	}
+	if parent.ProjectID != task.ProjectID || parent.ParentTaskID != nil {
+		return nil, nil
+	}
+	return parent, nil
+}
@@ -0,0 +1,312 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sikigasa/github-task-controller/backend/internal/domain/model"
+	"github.com/sikigasa/github-task-controller/backend/internal/domain/repository"
+)
+
+const (
+	testUserID    = "3f1c2a4e-7b8d-4c2e-9a1f-0e5d6c7b8a90"
+	testProjectID = "8a7b6c5d-4e3f-4a1b-8c9d-0e1f2a3b4c5d"
+	testTaskID    = "0b1c2d3e-4f5a-4b6c-8d7e-9f0a1b2c3d4e"
+	testSubtaskID = "5e6f7a8b-9c0d-4e1f-8a2b-3c4d5e6f7a8b"
+)
+
+// memoryTxKey はmemoryTransactorのトランザクションを保持するコンテキストのキー
+type memoryTxKey struct{}
+
+// memoryTx はトランザクション内の書き込みをコミットまで保留する
+type memoryTx struct {
+	writes []func()
+}
+
+// memoryTransactor はfnがエラーを返した場合に、トランザクション内の書き込みをすべて捨てる
+type memoryTransactor struct{}
+
+func (memoryTransactor) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	tx := &memoryTx{}
+	if err := fn(context.WithValue(ctx, memoryTxKey{}, tx)); err != nil {
+		return err
+	}
+	for _, write := range tx.writes {
+		write()
+	}
+	return nil
+}
+
+// writeInTx はトランザクション内であればコミットまで書き込みを保留し、そうでなければすぐに書き込む
+func writeInTx(ctx context.Context, write func()) {
+	if tx, ok := ctx.Value(memoryTxKey{}).(*memoryTx); ok {
+		tx.writes = append(tx.writes, write)
+		return
+	}
+	write()
+}
+
+// memoryTasks はタスクをメモリに保存する（所有者はすべてtestUserID）
+type memoryTasks struct {
+	repository.TaskRepository
+	mu    sync.Mutex
+	tasks map[string]model.Task
+}
+
+func newMemoryTasks(tasks ...model.Task) *memoryTasks {
+	m := &memoryTasks{tasks: make(map[string]model.Task)}
+	for _, task := range tasks {
+		m.tasks[task.ID] = task
+	}
+	return m
+}
+
+func (m *memoryTasks) get(id string) (model.Task, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	task, ok := m.tasks[id]
+	return task, ok
+}
+
+func (m *memoryTasks) put(ctx context.Context, task *model.Task) {
+	saved := *task
+	writeInTx(ctx, func() {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		m.tasks[saved.ID] = saved
+	})
+}
+
+func (m *memoryTasks) Create(ctx context.Context, task *model.Task) error {
+	m.put(ctx, task)
+	return nil
+}
+
+func (m *memoryTasks) Update(ctx context.Context, task *model.Task) error {
+	task.Version++
+	m.put(ctx, task)
+	return nil
+}
+
+func (m *memoryTasks) FindByID(_ context.Context, id string) (*model.Task, error) {
+	task, ok := m.get(id)
+	if !ok {
+		return nil, model.ErrNotFound
+	}
+	return &task, nil
+}
+
+func (m *memoryTasks) FindOwnerID(_ context.Context, id string) (string, error) {
+	if _, ok := m.get(id); !ok {
+		return "", model.ErrNotFound
+	}
+	return testUserID, nil
+}
+
+func (m *memoryTasks) FindByParentID(_ context.Context, parentID string) ([]*model.Task, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var subtasks []*model.Task
+	for _, task := range m.tasks {
+		if task.ParentTaskID != nil && *task.ParentTaskID == parentID {
+			subtasks = append(subtasks, &task)
+		}
+	}
+	return subtasks, nil
+}
+
+// memoryStatusEvents はステータス遷移イベントをメモリに保存する
+type memoryStatusEvents struct {
+	repository.TaskStatusEventRepository
+	mu     sync.Mutex
+	events []model.TaskStatusEvent
+	// createErr はCreateで返すエラー（記録の失敗の再現に使う）
+	createErr error
+}
+
+func (m *memoryStatusEvents) Create(ctx context.Context, event *model.TaskStatusEvent) error {
+	if m.createErr != nil {
+		return m.createErr
+	}
+	saved := *event
+	writeInTx(ctx, func() {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		m.events = append(m.events, saved)
+	})
+	return nil
+}
+
+// transitions は記録されたイベントを「タスクID:遷移前->遷移後」の形式で返す
+func (m *memoryStatusEvents) transitions() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var transitions []string
+	for _, e := range m.events {
+		from := "-"
+		if e.FromStatus != nil {
+			from = e.FromStatus.String()
+		}
+		transition := e.TaskID + ":" + from + "->" + e.ToStatus.String()
+		if e.Reopened {
+			transition += " (reopened)"
+		}
+		transitions = append(transitions, transition)
+	}
+	slices.Sort(transitions)
+	return transitions
+}
+
+// discardEvents はイベントを発行しないEventBus
+type discardEvents struct{}
+
+func (discardEvents) Publish(context.Context, string, string, string, any) error { return nil }
+func (discardEvents) Subscribe(string, EventSubscriber, ...string)               {}
+
+// discardActivity はアクティビティを記録しないActivityRecorder
+type discardActivity struct{}
+
+func (discardActivity) Record(context.Context, string, model.ActivityEntity, string, model.ActivityAction, any, any) error {
+	return nil
+}
+
+func newTestTaskUsecase(tasks *memoryTasks, statusEvents *memoryStatusEvents) *TaskUsecase {
+	return NewTaskUsecase(tasks, nil, statusEvents, nil, nil, nil, nil, discardEvents{}, discardActivity{}, memoryTransactor{}, nil, discardLogger())
+}
+
+func testTask(id string, status model.TaskStatus, parentID *string) model.Task {
+	now := time.Now()
+	return model.Task{ID: id, ProjectID: testProjectID, Title: "Task " + id[:4], Status: status, ParentTaskID: parentID, CreatedAt: now, UpdatedAt: now}
+}
+
+func TestTaskUsecaseRecordsTransition(t *testing.T) {
+	parentID := testTaskID
+	statusPtr := func(s model.TaskStatus) *model.TaskStatus { return &s }
+	title := "Renamed"
+
+	tests := []struct {
+		name  string
+		tasks []model.Task
+		req   *model.PatchTaskRequest
+		want  []string
+		// wantStatus は更新後のタスクごとのステータス
+		wantStatus map[string]model.TaskStatus
+	}{
+		{
+			name:       "status change",
+			tasks:      []model.Task{testTask(testTaskID, model.TaskStatusTodo, nil)},
+			req:        &model.PatchTaskRequest{Status: statusPtr(model.TaskStatusInProgress)},
+			want:       []string{testTaskID + ":todo->in_progress"},
+			wantStatus: map[string]model.TaskStatus{testTaskID: model.TaskStatusInProgress},
+		},
+		{
+			name:       "reopen",
+			tasks:      []model.Task{testTask(testTaskID, model.TaskStatusDone, nil)},
+			req:        &model.PatchTaskRequest{Status: statusPtr(model.TaskStatusTodo), Reopen: true},
+			want:       []string{testTaskID + ":done->todo (reopened)"},
+			wantStatus: map[string]model.TaskStatus{testTaskID: model.TaskStatusTodo},
+		},
+		{
+			name:       "no status change",
+			tasks:      []model.Task{testTask(testTaskID, model.TaskStatusTodo, nil)},
+			req:        &model.PatchTaskRequest{Title: &title, Status: statusPtr(model.TaskStatusTodo)},
+			want:       nil,
+			wantStatus: map[string]model.TaskStatus{testTaskID: model.TaskStatusTodo},
+		},
+		{
+			// 親タスクと連動して完了にしたサブタスクの遷移も記録する
+			name:       "complete parent with subtask",
+			tasks:      []model.Task{testTask(testTaskID, model.TaskStatusInProgress, nil), testTask(testSubtaskID, model.TaskStatusTodo, &parentID)},
+			req:        &model.PatchTaskRequest{Status: statusPtr(model.TaskStatusDone)},
+			want:       []string{testTaskID + ":in_progress->done", testSubtaskID + ":todo->done"},
+			wantStatus: map[string]model.TaskStatus{testTaskID: model.TaskStatusDone, testSubtaskID: model.TaskStatusDone},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tasks := newMemoryTasks(tt.tasks...)
+			statusEvents := &memoryStatusEvents{}
+			u := newTestTaskUsecase(tasks, statusEvents)
+
+			if _, err := u.PatchTask(context.Background(), testUserID, testTaskID, tt.req); err != nil {
+				t.Fatalf("PatchTask() error = %v", err)
+			}
+			if got := statusEvents.transitions(); !slices.Equal(got, tt.want) {
+				t.Errorf("transitions = %v, want %v", got, tt.want)
+			}
+			for id, want := range tt.wantStatus {
+				if task, _ := tasks.get(id); task.Status != want {
+					t.Errorf("status of %s = %s, want %s", id, task.Status, want)
+				}
+			}
+		})
+	}
+}
+
+// TestTaskUsecaseRollsBackWhenTransitionFails はステータス遷移イベントを記録できない場合に、
+// タスクの変更も書き込まずにエラーを返すことを確認する
+func TestTaskUsecaseRollsBackWhenTransitionFails(t *testing.T) {
+	errRecord := errors.New("failed to insert")
+	parentID := testTaskID
+	done := model.TaskStatusDone
+	todo := model.TaskStatusTodo
+
+	tests := []struct {
+		name string
+		run  func(u *TaskUsecase) error
+		// wantStatus は失敗した後のタスクごとのステータス
+		wantStatus map[string]model.TaskStatus
+	}{
+		{
+			name: "patch",
+			run: func(u *TaskUsecase) error {
+				_, err := u.PatchTask(context.Background(), testUserID, testTaskID, &model.PatchTaskRequest{Status: &done})
+				return err
+			},
+			wantStatus: map[string]model.TaskStatus{testTaskID: model.TaskStatusInProgress, testSubtaskID: model.TaskStatusTodo},
+		},
+		{
+			name: "update",
+			run: func(u *TaskUsecase) error {
+				_, err := u.UpdateTask(context.Background(), testUserID, testTaskID, &model.UpdateTaskRequest{Title: "Task", Status: model.TaskStatusDone, Priority: model.TaskPriorityMedium})
+				return err
+			},
+			wantStatus: map[string]model.TaskStatus{testTaskID: model.TaskStatusInProgress, testSubtaskID: model.TaskStatusTodo},
+		},
+		{
+			name: "create",
+			run: func(u *TaskUsecase) error {
+				_, err := u.createTask(context.Background(), &model.CreateTaskRequest{ProjectID: testProjectID, Title: "New", Status: &todo})
+				return err
+			},
+			wantStatus: map[string]model.TaskStatus{testTaskID: model.TaskStatusInProgress},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tasks := newMemoryTasks(testTask(testTaskID, model.TaskStatusInProgress, nil), testTask(testSubtaskID, model.TaskStatusTodo, &parentID))
+			statusEvents := &memoryStatusEvents{createErr: errRecord}
+			u := newTestTaskUsecase(tasks, statusEvents)
+
+			if err := tt.run(u); !errors.Is(err, errRecord) {
+				t.Fatalf("error = %v, want %v", err, errRecord)
+			}
+			for id, want := range tt.wantStatus {
+				if task, _ := tasks.get(id); task.Status != want {
+					t.Errorf("status of %s = %s, want %s", id, task.Status, want)
+				}
+			}
+			// 作成に失敗したタスクも残らない
+			if n := len(tasks.tasks); n != 2 {
+				t.Errorf("tasks = %d, want 2", n)
+			}
+			if got := statusEvents.transitions(); got != nil {
+				t.Errorf("transitions = %v, want none", got)
+			}
+		})
+	}
+}
@@ -25,12 +25,13 @@ func NewTodoUsecase(repo repository.TodoRepository, logger *slog.Logger) *TodoUs
 	}
 }
 
-// Create は新しいTODOを作成する
-func (u *TodoUsecase) Create(ctx context.Context, req *model.CreateTodoRequest) (*model.Todo, error) {
+// Create はuserIDが所有する新しいTODOを作成する
+func (u *TodoUsecase) Create(ctx context.Context, userID string, req *model.CreateTodoRequest) (*model.Todo, error) {
 	u.logger.InfoContext(ctx, "creating new todo", "title", req.Title)
 
 	todo := &model.Todo{
 		ID:          uuid.New().String(),
+		UserID:      userID,
 		Title:       req.Title,
 		Description: req.Description,
 		Completed:   false,
@@ -47,24 +48,18 @@ func (u *TodoUsecase) Create(ctx context.Context, req *model.CreateTodoRequest)
 	return todo, nil
 }
 
-// GetByID はIDでTODOを取得する
-func (u *TodoUsecase) GetByID(ctx context.Context, id string) (*model.Todo, error) {
+// GetByID はuserIDが所有するTODOをIDで取得する
+func (u *TodoUsecase) GetByID(ctx context.Context, userID, id string) (*model.Todo, error) {
 	u.logger.InfoContext(ctx, "getting todo by id", "id", id)
 
-	todo, err := u.repo.FindByID(ctx, id)
-	if err != nil {
-		u.logger.ErrorContext(ctx, "failed to get todo", "id", id, "error", err)
-		return nil, fmt.Errorf("failed to get todo: %w", err)
-	}
-
-	return todo, nil
+	return u.findOwned(ctx, userID, id)
 }
 
-// GetAll はすべてのTODOを取得する
-func (u *TodoUsecase) GetAll(ctx context.Context) ([]*model.Todo, error) {
+// GetAll はuserIDが所有するすべてのTODOを取得する
+func (u *TodoUsecase) GetAll(ctx context.Context, userID string, opts model.ListOptions) ([]*model.Todo, error) {
 	u.logger.InfoContext(ctx, "getting all todos")
 
-	todos, err := u.repo.FindAll(ctx)
+	todos, err := u.repo.FindByUserID(ctx, userID, opts)
 	if err != nil {
 		u.logger.ErrorContext(ctx, "failed to get todos", "error", err)
 		return nil, fmt.Errorf("failed to get todos: %w", err)
@@ -74,15 +69,14 @@ func (u *TodoUsecase) GetAll(ctx context.Context) ([]*model.Todo, error) {
 	return todos, nil
 }
 
-// Update はTODOを更新する
-func (u *TodoUsecase) Update(ctx context.Context, id string, req *model.UpdateTodoRequest) (*model.Todo, error) {
+// Update はuserIDが所有するTODOを更新する
+func (u *TodoUsecase) Update(ctx context.Context, userID, id string, req *model.UpdateTodoRequest) (*model.Todo, error) {
 	u.logger.InfoContext(ctx, "updating todo", "id", id)
 
 	// 既存のTODOを取得
-	todo, err := u.repo.FindByID(ctx, id)
+	todo, err := u.findOwned(ctx, userID, id)
 	if err != nil {
-		u.logger.ErrorContext(ctx, "failed to find todo for update", "id", id, "error", err)
-		return nil, fmt.Errorf("failed to find todo: %w", err)
+		return nil, err
 	}
 
 	// リクエストに含まれるフィールドのみ更新
@@ -106,17 +100,16 @@ func (u *TodoUsecase) Update(ctx context.Context, id string, req *model.UpdateTo
 	return todo, nil
 }
 
-// Delete はTODOを削除する
-func (u *TodoUsecase) Delete(ctx context.Context, id string) error {
+// Delete はuserIDが所有するTODOをゴミ箱に移す
+func (u *TodoUsecase) Delete(ctx context.Context, userID, id string) error {
 	u.logger.InfoContext(ctx, "deleting todo", "id", id)
 
-	// 削除前に存在確認
-	if _, err := u.repo.FindByID(ctx, id); err != nil {
-		u.logger.ErrorContext(ctx, "failed to find todo for deletion", "id", id, "error", err)
-		return fmt.Errorf("failed to find todo: %w", err)
+	// 削除前に存在と所有者を確認
+	if _, err := u.findOwned(ctx, userID, id); err != nil {
+		return err
 	}
 
-	if err := u.repo.Delete(ctx, id); err != nil {
+	if err := u.repo.Delete(ctx, id, time.Now()); err != nil {
 		u.logger.ErrorContext(ctx, "failed to delete todo", "id", id, "error", err)
 		return fmt.Errorf("failed to delete todo: %w", err)
 	}
@@ -124,3 +117,49 @@ func (u *TodoUsecase) Delete(ctx context.Context, id string) error {
 	u.logger.InfoContext(ctx, "todo deleted successfully", "id", id)
 	return nil
 }
+
+// Restore はuserIDが所有するゴミ箱のTODOを元に戻す
+func (u *TodoUsecase) Restore(ctx context.Context, userID, id string) (*model.Todo, error) {
+	u.logger.InfoContext(ctx, "restoring todo", "id", id)
+
+	if err := validateResourceID(id); err != nil {
+		return nil, err
+	}
+
+	todo, err := u.repo.FindDeletedByID(ctx, id)
+	if err != nil {
+		u.logger.ErrorContext(ctx, "failed to find deleted todo", "id", id, "error", err)
+		return nil, fmt.Errorf("failed to find deleted todo: %w", err)
+	}
+	if todo.UserID != userID {
+		return nil, model.ErrForbidden
+	}
+
+	todo.DeletedAt = nil
+	todo.UpdatedAt = time.Now()
+	if err := u.repo.Restore(ctx, todo); err != nil {
+		u.logger.ErrorContext(ctx, "failed to restore todo", "id", id, "error", err)
+		return nil, fmt.Errorf("failed to restore todo: %w", err)
+	}
+
+	u.logger.InfoContext(ctx, "todo restored successfully", "id", id)
+	return todo, nil
+}
+
+// findOwned はTODOを取得し、userIDが所有していることを確認する
+func (u *TodoUsecase) findOwned(ctx context.Context, userID, id string) (*model.Todo, error) {
+	if err := validateResourceID(id); err != nil {
+		return nil, err
+	}
+
+	todo, err := u.repo.FindByID(ctx, id)
+	if err != nil {
+		u.logger.ErrorContext(ctx, "failed to find todo", "id", id, "error", err)
+		return nil, fmt.Errorf("failed to find todo: %w", err)
+	}
+	if todo.UserID != userID {
+		return nil, model.ErrForbidden
+	}
+
+	return todo, nil
+}
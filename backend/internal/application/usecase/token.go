@@ -0,0 +1,184 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sikigasa/github-task-controller/backend/internal/domain/model"
+	"github.com/sikigasa/github-task-controller/backend/internal/domain/repository"
+	"github.com/sikigasa/github-task-controller/backend/internal/infrastructure/token"
+)
+
+// TokenUsecase はSPA向けのアクセストークン・リフレッシュトークンに関するユースケース
+// リフレッシュトークンはログインセッションの記録ごとに1つの系列として発行し、再発行のたびに新しいトークンへ置き換える
+type TokenUsecase struct {
+	refreshTokenRepo repository.RefreshTokenRepository
+	sessionRepo      repository.UserSessionRepository
+	signer           *token.Signer
+	accessTokenTTL   time.Duration
+	refreshTokenTTL  time.Duration
+	logger           *slog.Logger
+}
+
+// NewTokenUsecase は新しいTokenUsecaseを作成する
+func NewTokenUsecase(
+	refreshTokenRepo repository.RefreshTokenRepository,
+	sessionRepo repository.UserSessionRepository,
+	signer *token.Signer,
+	accessTokenTTL time.Duration,
+	refreshTokenTTL time.Duration,
+	logger *slog.Logger,
+) *TokenUsecase {
+	return &TokenUsecase{
+		refreshTokenRepo: refreshTokenRepo,
+		sessionRepo:      sessionRepo,
+		signer:           signer,
+		accessTokenTTL:   accessTokenTTL,
+		refreshTokenTTL:  refreshTokenTTL,
+		logger:           logger,
+	}
+}
+
+// RefreshTokenTTL はログインしてからリフレッシュトークンで再発行できる期間を返す
+func (u *TokenUsecase) RefreshTokenTTL() time.Duration {
+	return u.refreshTokenTTL
+}
+
+// IssueRefreshToken はログインしたセッションに最初のリフレッシュトークンを発行する
+// 系列の有効期限はセッションの有効期限と同じにする（アクセストークンはRefreshで取得する）
+func (u *TokenUsecase) IssueRefreshToken(ctx context.Context, session *model.UserSession) (string, error) {
+	return u.createRefreshToken(ctx, session.UserID, session.ID, session.ExpiresAt)
+}
+
+// Refresh はリフレッシュトークンを使用済みにして、新しいアクセストークンとリフレッシュトークンを発行する
+// 使用済みのトークンが再び使われた場合は漏洩とみなし、同じセッションのトークンをすべて失効させる
+func (u *TokenUsecase) Refresh(ctx context.Context, refreshToken string) (*model.AccessToken, string, error) {
+	stored, err := u.refreshTokenRepo.FindByHash(ctx, hashRefreshToken(refreshToken))
+	if err != nil {
+		if errors.Is(err, model.ErrNotFound) {
+			return nil, "", model.ErrUnauthorized
+		}
+		return nil, "", fmt.Errorf("failed to find refresh token: %w", err)
+	}
+
+	now := time.Now()
+	if stored.UsedAt != nil {
+		u.revokeReused(ctx, stored)
+		return nil, "", model.ErrUnauthorized
+	}
+	if !now.Before(stored.ExpiresAt) {
+		return nil, "", model.ErrUnauthorized
+	}
+
+	if err := u.refreshTokenRepo.MarkUsed(ctx, stored.ID, now); err != nil {
+		if errors.Is(err, model.ErrNotFound) {
+			// 同時に使われた場合も再利用とみなす
+			u.revokeReused(ctx, stored)
+			return nil, "", model.ErrUnauthorized
+		}
+		return nil, "", fmt.Errorf("failed to mark refresh token used: %w", err)
+	}
+
+	refreshToken, err = u.createRefreshToken(ctx, stored.UserID, stored.SessionID, stored.ExpiresAt)
+	if err != nil {
+		return nil, "", err
+	}
+	accessToken, err := u.signAccessToken(stored.UserID, stored.SessionID, now)
+	if err != nil {
+		return nil, "", err
+	}
+	return accessToken, refreshToken, nil
+}
+
+// Revoke はリフレッシュトークンのセッションを終了し、同じセッションのトークンをすべて失効させる
+// 未知のトークンの場合は何もしない
+func (u *TokenUsecase) Revoke(ctx context.Context, refreshToken string) error {
+	stored, err := u.refreshTokenRepo.FindByHash(ctx, hashRefreshToken(refreshToken))
+	if err != nil {
+		if errors.Is(err, model.ErrNotFound) {
+			return nil
+		}
+		return fmt.Errorf("failed to find refresh token: %w", err)
+	}
+
+	if err := u.sessionRepo.Delete(ctx, stored.SessionID); err != nil {
+		return fmt.Errorf("failed to delete user session: %w", err)
+	}
+
+	u.logger.InfoContext(ctx, "refresh tokens revoked", "user_id", stored.UserID, "session_id", stored.SessionID)
+	return nil
+}
+
+// VerifyAccessToken はアクセストークンを検証してユーザーIDとセッションの記録IDを返す
+func (u *TokenUsecase) VerifyAccessToken(accessToken string) (string, string, error) {
+	claims, err := u.signer.Verify(accessToken, time.Now())
+	if err != nil {
+		return "", "", model.ErrUnauthorized
+	}
+	return claims.Subject, claims.SessionID, nil
+}
+
+// createRefreshToken は新しいリフレッシュトークンを生成し、ハッシュを保存する
+func (u *TokenUsecase) createRefreshToken(ctx context.Context, userID, sessionID string, expiresAt time.Time) (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate refresh token: %w", err)
+	}
+	refreshToken := base64.RawURLEncoding.EncodeToString(b)
+
+	if err := u.refreshTokenRepo.Create(ctx, &model.RefreshToken{
+		ID:        uuid.New().String(),
+		SessionID: sessionID,
+		UserID:    userID,
+		TokenHash: hashRefreshToken(refreshToken),
+		ExpiresAt: expiresAt,
+		CreatedAt: time.Now(),
+	}); err != nil {
+		return "", fmt.Errorf("failed to create refresh token: %w", err)
+	}
+
+	return refreshToken, nil
+}
+
+// signAccessToken はアクセストークンに署名する
+func (u *TokenUsecase) signAccessToken(userID, sessionID string, now time.Time) (*model.AccessToken, error) {
+	expiresAt := now.Add(u.accessTokenTTL)
+	accessToken, err := u.signer.Sign(token.Claims{
+		Subject:   userID,
+		SessionID: sessionID,
+		IssuedAt:  now.Unix(),
+		ExpiresAt: expiresAt.Unix(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign access token: %w", err)
+	}
+
+	return &model.AccessToken{
+		AccessToken: accessToken,
+		TokenType:   "Bearer",
+		ExpiresIn:   int(u.accessTokenTTL.Seconds()),
+		ExpiresAt:   expiresAt,
+	}, nil
+}
+
+// revokeReused は再利用されたリフレッシュトークンのセッションを終了する（失敗はログに記録するのみ）
+func (u *TokenUsecase) revokeReused(ctx context.Context, stored *model.RefreshToken) {
+	u.logger.WarnContext(ctx, "refresh token reuse detected", "user_id", stored.UserID, "session_id", stored.SessionID)
+	if err := u.sessionRepo.Delete(ctx, stored.SessionID); err != nil {
+		u.logger.ErrorContext(ctx, "failed to revoke reused refresh tokens", "error", err, "session_id", stored.SessionID)
+	}
+}
+
+// hashRefreshToken は保存・検索に使うリフレッシュトークンのハッシュを返す
+func hashRefreshToken(refreshToken string) string {
+	sum := sha256.Sum256([]byte(refreshToken))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,252 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sikigasa/github-task-controller/backend/internal/domain/model"
+	"github.com/sikigasa/github-task-controller/backend/internal/domain/repository"
+	"github.com/sikigasa/github-task-controller/backend/internal/infrastructure/token"
+)
+
+func discardLogger() *slog.Logger {
+	return slog.New(slog.NewTextHandler(io.Discard, nil))
+}
+
+// memoryRefreshTokens はリフレッシュトークンとログインセッションの記録をメモリに保存する
+// refresh_tokenの外部キーと同じく、セッションの記録を削除するとそのセッションのトークンも削除する
+type memoryRefreshTokens struct {
+	mu       sync.Mutex
+	tokens   map[string]*model.RefreshToken
+	sessions map[string]bool
+	deleted  []string
+	// markUsedErr はMarkUsedで返すエラー（同時に使われた場合の再現に使う）
+	markUsedErr error
+}
+
+func newMemoryRefreshTokens(sessionIDs ...string) *memoryRefreshTokens {
+	m := &memoryRefreshTokens{
+		tokens:   make(map[string]*model.RefreshToken),
+		sessions: make(map[string]bool),
+	}
+	for _, id := range sessionIDs {
+		m.sessions[id] = true
+	}
+	return m
+}
+
+func (m *memoryRefreshTokens) Create(_ context.Context, t *model.RefreshToken) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if !m.sessions[t.SessionID] {
+		return errors.New("user session does not exist")
+	}
+	stored := *t
+	m.tokens[t.ID] = &stored
+	return nil
+}
+
+func (m *memoryRefreshTokens) FindByHash(_ context.Context, tokenHash string) (*model.RefreshToken, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, t := range m.tokens {
+		if t.TokenHash == tokenHash {
+			found := *t
+			return &found, nil
+		}
+	}
+	return nil, model.ErrNotFound
+}
+
+func (m *memoryRefreshTokens) MarkUsed(_ context.Context, id string, usedAt time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.markUsedErr != nil {
+		return m.markUsedErr
+	}
+	t, ok := m.tokens[id]
+	if !ok || t.UsedAt != nil {
+		return model.ErrNotFound
+	}
+	t.UsedAt = &usedAt
+	return nil
+}
+
+// memoryUserSessions はmemoryRefreshTokensのセッションの記録を操作するUserSessionRepository
+type memoryUserSessions struct {
+	repository.UserSessionRepository
+	store *memoryRefreshTokens
+}
+
+func (s memoryUserSessions) Delete(_ context.Context, id string) error {
+	s.store.mu.Lock()
+	defer s.store.mu.Unlock()
+	delete(s.store.sessions, id)
+	for tokenID, t := range s.store.tokens {
+		if t.SessionID == id {
+			delete(s.store.tokens, tokenID)
+		}
+	}
+	s.store.deleted = append(s.store.deleted, id)
+	return nil
+}
+
+const (
+	testTokenUserID    = "user-1"
+	testTokenSessionID = "session-1"
+)
+
+func newTestTokenUsecase(t *testing.T) (*TokenUsecase, *memoryRefreshTokens, string) {
+	t.Helper()
+	store := newMemoryRefreshTokens(testTokenSessionID)
+	u := NewTokenUsecase(store, memoryUserSessions{store: store}, token.NewSigner([]byte("test-secret")), 15*time.Minute, time.Hour, discardLogger())
+
+	refreshToken, err := u.IssueRefreshToken(context.Background(), &model.UserSession{
+		ID:        testTokenSessionID,
+		UserID:    testTokenUserID,
+		ExpiresAt: time.Now().Add(time.Hour),
+	})
+	if err != nil {
+		t.Fatalf("IssueRefreshToken() error = %v", err)
+	}
+	return u, store, refreshToken
+}
+
+func TestTokenUsecaseRefreshRotatesToken(t *testing.T) {
+	ctx := context.Background()
+	u, store, first := newTestTokenUsecase(t)
+
+	accessToken, second, err := u.Refresh(ctx, first)
+	if err != nil {
+		t.Fatalf("Refresh() error = %v", err)
+	}
+	if second == "" || second == first {
+		t.Fatalf("Refresh() returned refresh token %q, want a new token", second)
+	}
+
+	userID, sessionID, err := u.VerifyAccessToken(accessToken.AccessToken)
+	if err != nil {
+		t.Fatalf("VerifyAccessToken() error = %v", err)
+	}
+	if userID != testTokenUserID || sessionID != testTokenSessionID {
+		t.Errorf("VerifyAccessToken() = (%s, %s), want (%s, %s)", userID, sessionID, testTokenUserID, testTokenSessionID)
+	}
+
+	if _, _, err := u.Refresh(ctx, second); err != nil {
+		t.Errorf("Refresh() with rotated token error = %v", err)
+	}
+	if len(store.deleted) != 0 {
+		t.Errorf("sessions deleted = %v, want none", store.deleted)
+	}
+}
+
+func TestTokenUsecaseRefreshReuseRevokesSession(t *testing.T) {
+	ctx := context.Background()
+	u, store, first := newTestTokenUsecase(t)
+
+	_, second, err := u.Refresh(ctx, first)
+	if err != nil {
+		t.Fatalf("Refresh() error = %v", err)
+	}
+
+	// 使用済みのトークンの再利用は拒否し、セッションを失効させる
+	if _, _, err := u.Refresh(ctx, first); !errors.Is(err, model.ErrUnauthorized) {
+		t.Fatalf("Refresh() with reused token error = %v, want ErrUnauthorized", err)
+	}
+	if !slices.Equal(store.deleted, []string{testTokenSessionID}) {
+		t.Errorf("sessions deleted = %v, want [%s]", store.deleted, testTokenSessionID)
+	}
+
+	// 再利用の前に正しく再発行されたトークンも使えなくなる
+	if _, _, err := u.Refresh(ctx, second); !errors.Is(err, model.ErrUnauthorized) {
+		t.Errorf("Refresh() with token of revoked session error = %v, want ErrUnauthorized", err)
+	}
+}
+
+func TestTokenUsecaseRefreshConcurrentUseRevokesSession(t *testing.T) {
+	ctx := context.Background()
+	u, store, first := newTestTokenUsecase(t)
+
+	// 検索の後に別のリクエストが先に使用済みにした場合
+	store.markUsedErr = model.ErrNotFound
+	if _, _, err := u.Refresh(ctx, first); !errors.Is(err, model.ErrUnauthorized) {
+		t.Fatalf("Refresh() error = %v, want ErrUnauthorized", err)
+	}
+	if !slices.Equal(store.deleted, []string{testTokenSessionID}) {
+		t.Errorf("sessions deleted = %v, want [%s]", store.deleted, testTokenSessionID)
+	}
+}
+
+func TestTokenUsecaseRefreshRejectsExpiredToken(t *testing.T) {
+	ctx := context.Background()
+	store := newMemoryRefreshTokens(testTokenSessionID)
+	u := NewTokenUsecase(store, memoryUserSessions{store: store}, token.NewSigner([]byte("test-secret")), 15*time.Minute, time.Hour, discardLogger())
+
+	expired, err := u.IssueRefreshToken(ctx, &model.UserSession{
+		ID:        testTokenSessionID,
+		UserID:    testTokenUserID,
+		ExpiresAt: time.Now().Add(-time.Minute),
+	})
+	if err != nil {
+		t.Fatalf("IssueRefreshToken() error = %v", err)
+	}
+
+	if _, _, err := u.Refresh(ctx, expired); !errors.Is(err, model.ErrUnauthorized) {
+		t.Fatalf("Refresh() error = %v, want ErrUnauthorized", err)
+	}
+	// 期限切れは再利用ではないためセッションは削除しない
+	if len(store.deleted) != 0 {
+		t.Errorf("sessions deleted = %v, want none", store.deleted)
+	}
+}
+
+func TestTokenUsecaseRefreshRejectsUnknownToken(t *testing.T) {
+	u, _, _ := newTestTokenUsecase(t)
+
+	if _, _, err := u.Refresh(context.Background(), "unknown-token"); !errors.Is(err, model.ErrUnauthorized) {
+		t.Errorf("Refresh() error = %v, want ErrUnauthorized", err)
+	}
+}
+
+func TestTokenUsecaseRevoke(t *testing.T) {
+	ctx := context.Background()
+	u, store, first := newTestTokenUsecase(t)
+
+	if err := u.Revoke(ctx, first); err != nil {
+		t.Fatalf("Revoke() error = %v", err)
+	}
+	if _, _, err := u.Refresh(ctx, first); !errors.Is(err, model.ErrUnauthorized) {
+		t.Errorf("Refresh() after Revoke() error = %v, want ErrUnauthorized", err)
+	}
+	if !slices.Equal(store.deleted, []string{testTokenSessionID}) {
+		t.Errorf("sessions deleted = %v, want [%s]", store.deleted, testTokenSessionID)
+	}
+
+	// 未知のトークンの失効は何もしない
+	if err := u.Revoke(ctx, "unknown-token"); err != nil {
+		t.Errorf("Revoke() with unknown token error = %v", err)
+	}
+}
+
+func TestTokenUsecaseVerifyAccessTokenRejectsInvalidToken(t *testing.T) {
+	u, _, _ := newTestTokenUsecase(t)
+	other := token.NewSigner([]byte("other-secret"))
+	forged, err := other.Sign(token.Claims{Subject: testTokenUserID, ExpiresAt: time.Now().Add(time.Hour).Unix()})
+	if err != nil {
+		t.Fatalf("Sign() error = %v", err)
+	}
+
+	for name, accessToken := range map[string]string{"forged": forged, "malformed": "not-a-token"} {
+		t.Run(name, func(t *testing.T) {
+			if _, _, err := u.VerifyAccessToken(accessToken); !errors.Is(err, model.ErrUnauthorized) {
+				t.Errorf("VerifyAccessToken() error = %v, want ErrUnauthorized", err)
+			}
+		})
+	}
+}
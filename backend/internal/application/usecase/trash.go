@@ -0,0 +1,101 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/sikigasa/github-task-controller/backend/internal/domain/model"
+	"github.com/sikigasa/github-task-controller/backend/internal/domain/repository"
+)
+
+// TrashUsecase はゴミ箱に関するユースケース
+// 削除したプロジェクト・タスク・TODOはゴミ箱に移し、保持期間を過ぎたら完全に削除する（元に戻す操作は各ユースケースが行う）
+type TrashUsecase struct {
+	projectRepo repository.ProjectRepository
+	taskRepo    repository.TaskRepository
+	todoRepo    repository.TodoRepository
+	retention   time.Duration
+	logger      *slog.Logger
+}
+
+// NewTrashUsecase は新しいTrashUsecaseを作成する
+// retentionはゴミ箱に移してから完全に削除するまでの期間
+func NewTrashUsecase(
+	projectRepo repository.ProjectRepository,
+	taskRepo repository.TaskRepository,
+	todoRepo repository.TodoRepository,
+	retention time.Duration,
+	logger *slog.Logger,
+) *TrashUsecase {
+	return &TrashUsecase{
+		projectRepo: projectRepo,
+		taskRepo:    taskRepo,
+		todoRepo:    todoRepo,
+		retention:   retention,
+		logger:      logger,
+	}
+}
+
+// List はuserIDのゴミ箱のプロジェクト・タスク・TODOを取得する
+func (u *TrashUsecase) List(ctx context.Context, userID string) (*model.Trash, error) {
+	projects, err := u.projectRepo.FindDeletedByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find deleted projects: %w", err)
+	}
+
+	// 個別にゴミ箱に移したタスクは、削除されていないプロジェクトから探す
+	liveProjects, err := u.projectRepo.FindByUserID(ctx, userID, model.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to find projects: %w", err)
+	}
+	projectIDs := make([]string, 0, len(liveProjects))
+	for _, p := range liveProjects {
+		projectIDs = append(projectIDs, p.ID)
+	}
+	tasks, err := u.taskRepo.FindDeletedByProjectIDs(ctx, projectIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find deleted tasks: %w", err)
+	}
+
+	todos, err := u.todoRepo.FindDeletedByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find deleted todos: %w", err)
+	}
+
+	trash := &model.Trash{Projects: projects, Tasks: tasks, Todos: todos}
+	if trash.Projects == nil {
+		trash.Projects = []*model.Project{}
+	}
+	if trash.Tasks == nil {
+		trash.Tasks = []*model.Task{}
+	}
+	if trash.Todos == nil {
+		trash.Todos = []*model.Todo{}
+	}
+	return trash, nil
+}
+
+// PurgeExpired はゴミ箱に移してから保持期間を過ぎたプロジェクト・タスク・TODOを完全に削除する
+func (u *TrashUsecase) PurgeExpired(ctx context.Context, now time.Time) error {
+	before := now.Add(-u.retention)
+
+	projects, err := u.projectRepo.PurgeDeleted(ctx, before)
+	if err != nil {
+		return fmt.Errorf("failed to purge deleted projects: %w", err)
+	}
+	tasks, err := u.taskRepo.PurgeDeleted(ctx, before)
+	if err != nil {
+		return fmt.Errorf("failed to purge deleted tasks: %w", err)
+	}
+	todos, err := u.todoRepo.PurgeDeleted(ctx, before)
+	if err != nil {
+		return fmt.Errorf("failed to purge deleted todos: %w", err)
+	}
+
+	if projects+tasks+todos > 0 {
+		u.logger.InfoContext(ctx, "expired trash purged", "projects", projects, "tasks", tasks, "todos", todos)
+	}
+	return nil
+}
@@ -0,0 +1,245 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/sikigasa/github-task-controller/backend/internal/domain/model"
+	"github.com/sikigasa/github-task-controller/backend/internal/domain/repository"
+)
+
+const (
+	// webhookBatchSize はワーカーが一度に取得する配信の件数
+	webhookBatchSize = 20
+	// webhookLease は取得した配信を他のワーカーが取得しないようにする期間（処理中に停止した場合はこの後に再試行される）
+	webhookLease = 5 * time.Minute
+	// webhookRetryBase は1回目の再試行までの待ち時間（以降は失敗するごとに倍にする）
+	webhookRetryBase = 30 * time.Second
+	// webhookRetryMax は再試行までの待ち時間の上限
+	webhookRetryMax = time.Hour
+	// webhookListLimit は配信一覧で返す件数の上限
+	webhookListLimit = 100
+	// webhookErrorMaxLen は記録するエラーメッセージの最大長
+	webhookErrorMaxLen = 1000
+	// webhookWorker はWorkerMonitorに記録するワーカーの名前
+	webhookWorker = "webhook"
+)
+
+// WebhookHandler はイベントの種類ごとにWebhookの配信を処理する関数
+// 失敗した配信は再試行されるため、同じ配信を複数回処理しても結果が変わらないように実装する
+type WebhookHandler func(ctx context.Context, delivery *model.WebhookDelivery) error
+
+// WebhookUsecase は受信したWebhookの配信をキューに保存し、Runのワーカーが非同期に処理するユースケース
+// 処理に失敗した配信は間隔を空けて再試行し、上限に達したものはデッドレターとして残して管理者が再処理できるようにする
+type WebhookUsecase struct {
+	deliveryRepo repository.WebhookDeliveryRepository
+	handlers     map[string]WebhookHandler
+	maxAttempts  int
+	pollInterval time.Duration
+	wake         chan struct{}
+	workers      *WorkerMonitor
+	logger       *slog.Logger
+}
+
+// NewWebhookUsecase は新しいWebhookUsecaseを作成する
+// maxAttemptsは処理を試行する回数の上限、pollIntervalは再試行待ちの配信を確認する間隔
+// workersにはRunのワーカーの実行状況をwebhookとして記録する
+func NewWebhookUsecase(
+	deliveryRepo repository.WebhookDeliveryRepository,
+	maxAttempts int,
+	pollInterval time.Duration,
+	workers *WorkerMonitor,
+	logger *slog.Logger,
+) *WebhookUsecase {
+	workers.Register(webhookWorker, pollInterval)
+	return &WebhookUsecase{
+		deliveryRepo: deliveryRepo,
+		handlers:     make(map[string]WebhookHandler),
+		maxAttempts:  maxAttempts,
+		pollInterval: pollInterval,
+		wake:         make(chan struct{}, 1),
+		workers:      workers,
+		logger:       logger,
+	}
+}
+
+// Handle はイベントの種類（X-GitHub-Event）を処理する関数を登録する（Runの開始前に呼び出す）
+func (u *WebhookUsecase) Handle(event string, handler WebhookHandler) {
+	u.handlers[event] = handler
+}
+
+// Enqueue は受信した配信をキューに保存する（処理は非同期に行う）
+// 同じ配信IDの再送は保存済みのため無視する
+func (u *WebhookUsecase) Enqueue(ctx context.Context, id, event string, payload []byte) error {
+	if id == "" || event == "" {
+		return fmt.Errorf("delivery id and event are required: %w", model.ErrInvalidInput)
+	}
+	if !json.Valid(payload) {
+		return fmt.Errorf("webhook payload is not valid JSON: %w", model.ErrInvalidInput)
+	}
+
+	now := time.Now()
+	created, err := u.deliveryRepo.Create(ctx, &model.WebhookDelivery{
+		ID:            id,
+		Event:         event,
+		Payload:       payload,
+		Status:        model.WebhookDeliveryPending,
+		NextAttemptAt: now,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to enqueue webhook delivery: %w", err)
+	}
+	if !created {
+		u.logger.InfoContext(ctx, "duplicate webhook delivery ignored", "delivery_id", id, "event", event)
+		return nil
+	}
+
+	u.notify()
+	return nil
+}
+
+// ListDeliveries は処理状況が一致する配信を新しい順に一覧する
+func (u *WebhookUsecase) ListDeliveries(ctx context.Context, status model.WebhookDeliveryStatus) ([]*model.WebhookDelivery, error) {
+	deliveries, err := u.deliveryRepo.FindByStatus(ctx, status, webhookListLimit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list webhook deliveries: %w", err)
+	}
+	return deliveries, nil
+}
+
+// GetDelivery は配信を取得する
+func (u *WebhookUsecase) GetDelivery(ctx context.Context, id string) (*model.WebhookDelivery, error) {
+	delivery, err := u.deliveryRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find webhook delivery: %w", err)
+	}
+	return delivery, nil
+}
+
+// Reprocess はデッドレターの配信を処理待ちに戻して再処理する（処理待ち・処理済みの配信はErrConflict）
+func (u *WebhookUsecase) Reprocess(ctx context.Context, id string) (*model.WebhookDelivery, error) {
+	delivery, err := u.GetDelivery(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if delivery.Status != model.WebhookDeliveryDead {
+		return nil, fmt.Errorf("webhook delivery is %s: %w", delivery.Status, model.ErrConflict)
+	}
+
+	if err := u.deliveryRepo.Requeue(ctx, id, time.Now()); err != nil {
+		return nil, fmt.Errorf("failed to requeue webhook delivery: %w", err)
+	}
+	u.logger.InfoContext(ctx, "webhook delivery requeued", "delivery_id", id, "event", delivery.Event)
+	u.notify()
+
+	return u.GetDelivery(ctx, id)
+}
+
+// Run はctxがキャンセルされるまで処理時刻を迎えた配信を処理する
+// 新しい配信を受け付けた時はすぐに、それ以外はpollIntervalごとに再試行待ちの配信を確認する
+func (u *WebhookUsecase) Run(ctx context.Context) {
+	ticker := time.NewTicker(u.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		u.workers.Beat(webhookWorker, u.processDue(ctx))
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-u.wake:
+		}
+	}
+}
+
+// notify はワーカーに新しい配信があることを知らせる（既に通知済みの場合は何もしない）
+func (u *WebhookUsecase) notify() {
+	select {
+	case u.wake <- struct{}{}:
+	default:
+	}
+}
+
+// processDue は処理時刻を迎えた配信がなくなるまで取得して処理する
+// 配信を取得できなかった場合はエラーを返す（個々の配信の失敗は再試行に回すためエラーにしない）
+func (u *WebhookUsecase) processDue(ctx context.Context) error {
+	for ctx.Err() == nil {
+		deliveries, err := u.deliveryRepo.ClaimDue(ctx, time.Now(), webhookLease, webhookBatchSize)
+		if err != nil {
+			// DBの一時的な障害の場合は次の確認時に再度取得する
+			u.logger.ErrorContext(ctx, "failed to claim webhook deliveries", "error", err)
+			return err
+		}
+		for _, delivery := range deliveries {
+			u.process(ctx, delivery)
+		}
+		if len(deliveries) < webhookBatchSize {
+			return nil
+		}
+	}
+	return nil
+}
+
+// process は配信を処理し、結果を記録する
+func (u *WebhookUsecase) process(ctx context.Context, delivery *model.WebhookDelivery) {
+	handler, ok := u.handlers[delivery.Event]
+	if !ok {
+		// 処理対象外のイベントも受信済みとして記録する
+		u.logger.DebugContext(ctx, "no handler for webhook event", "delivery_id", delivery.ID, "event", delivery.Event)
+		u.markProcessed(ctx, delivery)
+		return
+	}
+
+	if err := handler(ctx, delivery); err != nil {
+		u.markFailed(ctx, delivery, err)
+		return
+	}
+	u.markProcessed(ctx, delivery)
+}
+
+// markProcessed は配信を処理済みにする
+// 記録に失敗した場合はleaseの後に再度処理される
+func (u *WebhookUsecase) markProcessed(ctx context.Context, delivery *model.WebhookDelivery) {
+	if err := u.deliveryRepo.MarkProcessed(ctx, delivery.ID, time.Now()); err != nil {
+		u.logger.ErrorContext(ctx, "failed to mark webhook delivery processed", "error", err, "delivery_id", delivery.ID)
+	}
+}
+
+// markFailed は配信の失敗を記録し、試行回数が上限に達した場合はデッドレターにする
+func (u *WebhookUsecase) markFailed(ctx context.Context, delivery *model.WebhookDelivery, cause error) {
+	attempts := delivery.Attempts + 1
+	dead := attempts >= u.maxAttempts
+	nextAttemptAt := time.Now().Add(webhookRetryDelay(attempts))
+
+	message := cause.Error()
+	if runes := []rune(message); len(runes) > webhookErrorMaxLen {
+		message = string(runes[:webhookErrorMaxLen])
+	}
+
+	if dead {
+		u.logger.ErrorContext(ctx, "webhook delivery moved to dead letter",
+			"error", cause, "delivery_id", delivery.ID, "event", delivery.Event, "attempts", attempts)
+	} else {
+		u.logger.WarnContext(ctx, "webhook delivery failed, will retry",
+			"error", cause, "delivery_id", delivery.ID, "event", delivery.Event, "attempts", attempts, "next_attempt_at", nextAttemptAt)
+	}
+
+	if err := u.deliveryRepo.MarkFailed(ctx, delivery.ID, attempts, message, nextAttemptAt, dead); err != nil {
+		u.logger.ErrorContext(ctx, "failed to mark webhook delivery failed", "error", err, "delivery_id", delivery.ID)
+	}
+}
+
+// webhookRetryDelay はattempts回目の失敗の後、次の試行までの待ち時間を返す
+func webhookRetryDelay(attempts int) time.Duration {
+	delay := webhookRetryBase
+	for i := 1; i < attempts && delay < webhookRetryMax; i++ {
+		delay *= 2
+	}
+	return min(delay, webhookRetryMax)
+}
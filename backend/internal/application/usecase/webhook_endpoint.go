@@ -0,0 +1,484 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sikigasa/github-task-controller/backend/internal/domain/model"
+	"github.com/sikigasa/github-task-controller/backend/internal/domain/repository"
+	"github.com/sikigasa/github-task-controller/backend/internal/infrastructure/webhook"
+)
+
+const (
+	// webhookEndpointBatchSize はワーカーが一度に取得する配信の件数
+	webhookEndpointBatchSize = 20
+	// webhookEndpointLease は取得した配信を他のワーカーが取得しないようにする期間（送信中に停止した場合はこの後に再試行される）
+	webhookEndpointLease = 5 * time.Minute
+	// webhookEndpointRetryBase は1回目の再試行までの待ち時間（以降は失敗するごとに倍にする）
+	webhookEndpointRetryBase = 30 * time.Second
+	// webhookEndpointRetryMax は再試行までの待ち時間の上限
+	webhookEndpointRetryMax = 6 * time.Hour
+	// webhookEndpointListLimit は配信ログで返す件数の上限
+	webhookEndpointListLimit = 100
+	// webhookEndpointErrorMaxLen は記録するエラーメッセージの最大長
+	webhookEndpointErrorMaxLen = 1000
+	// webhookEndpointPruneInterval は古い配信ログを削除する間隔
+	webhookEndpointPruneInterval = time.Hour
+	// webhookEndpointWorker はWorkerMonitorに記録するワーカーの名前
+	webhookEndpointWorker = "webhook_endpoint"
+)
+
+// WebhookEndpointUsecase はプロジェクトのドメインイベントを外部のWebhookの送信先に通知するユースケース
+// Dispatchをイベントバスの購読者にして送信先ごとの配信をキューに保存し、Runのワーカーが署名して送信する
+// 送信に失敗した配信は間隔を空けて再試行し、連続して失敗した送信先は自動で無効にする
+type WebhookEndpointUsecase struct {
+	endpointRepo repository.WebhookEndpointRepository
+	deliveryRepo repository.WebhookEndpointDeliveryRepository
+	projectRepo  repository.ProjectRepository
+	sender       *webhook.Sender
+	maxAttempts  int
+	disableAfter int
+	pollInterval time.Duration
+	retention    time.Duration
+	wake         chan struct{}
+	workers      *WorkerMonitor
+	logger       *slog.Logger
+}
+
+// NewWebhookEndpointUsecase は新しいWebhookEndpointUsecaseを作成する
+// maxAttemptsは1件の配信を試行する回数の上限、disableAfterは送信先を無効にする連続失敗回数、
+// pollIntervalは再試行待ちの配信を確認する間隔、retentionは送信済み・失敗の配信ログを残す期間
+// workersにはRunのワーカーの実行状況をwebhook_endpointとして記録する
+func NewWebhookEndpointUsecase(
+	endpointRepo repository.WebhookEndpointRepository,
+	deliveryRepo repository.WebhookEndpointDeliveryRepository,
+	projectRepo repository.ProjectRepository,
+	sender *webhook.Sender,
+	maxAttempts int,
+	disableAfter int,
+	pollInterval time.Duration,
+	retention time.Duration,
+	workers *WorkerMonitor,
+	logger *slog.Logger,
+) *WebhookEndpointUsecase {
+	workers.Register(webhookEndpointWorker, pollInterval)
+	return &WebhookEndpointUsecase{
+		endpointRepo: endpointRepo,
+		deliveryRepo: deliveryRepo,
+		projectRepo:  projectRepo,
+		sender:       sender,
+		maxAttempts:  maxAttempts,
+		disableAfter: disableAfter,
+		pollInterval: pollInterval,
+		retention:    retention,
+		wake:         make(chan struct{}, 1),
+		workers:      workers,
+		logger:       logger,
+	}
+}
+
+// CreateEndpoint はプロジェクトにWebhookの送信先を登録する
+// 署名のsecretはこのときだけ返す
+func (u *WebhookEndpointUsecase) CreateEndpoint(ctx context.Context, userID, projectID string, req *model.CreateWebhookEndpointRequest) (*model.CreatedWebhookEndpoint, error) {
+	if err := u.authorizeProject(ctx, userID, projectID); err != nil {
+		return nil, err
+	}
+	if err := u.validateWebhookURL(req.URL); err != nil {
+		return nil, err
+	}
+	eventTypes, err := normalizeWebhookEventTypes(req.EventTypes)
+	if err != nil {
+		return nil, err
+	}
+
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return nil, fmt.Errorf("failed to generate webhook secret: %w", err)
+	}
+
+	now := time.Now()
+	endpoint := &model.WebhookEndpoint{
+		ID:         uuid.New().String(),
+		ProjectID:  projectID,
+		UserID:     userID,
+		URL:        req.URL,
+		Secret:     model.WebhookSecretPrefix + base64.RawURLEncoding.EncodeToString(b),
+		EventTypes: eventTypes,
+		Enabled:    true,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+	if err := u.endpointRepo.Create(ctx, endpoint); err != nil {
+		return nil, fmt.Errorf("failed to create webhook endpoint: %w", err)
+	}
+
+	return &model.CreatedWebhookEndpoint{WebhookEndpoint: endpoint, Secret: endpoint.Secret}, nil
+}
+
+// ListEndpoints はプロジェクトのWebhookの送信先を一覧する（secretは含まない）
+func (u *WebhookEndpointUsecase) ListEndpoints(ctx context.Context, userID, projectID string) ([]*model.WebhookEndpoint, error) {
+	if err := u.authorizeProject(ctx, userID, projectID); err != nil {
+		return nil, err
+	}
+
+	endpoints, err := u.endpointRepo.FindByProjectID(ctx, projectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list webhook endpoints: %w", err)
+	}
+	return endpoints, nil
+}
+
+// PatchEndpoint はWebhookの送信先を部分更新する
+// 有効に戻した場合は連続失敗回数を0にし、送信待ちのまま残っていた配信の送信を再開する
+func (u *WebhookEndpointUsecase) PatchEndpoint(ctx context.Context, userID, projectID, id string, req *model.PatchWebhookEndpointRequest) (*model.WebhookEndpoint, error) {
+	endpoint, err := u.findEndpoint(ctx, userID, projectID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.URL != nil {
+		if err := u.validateWebhookURL(*req.URL); err != nil {
+			return nil, err
+		}
+		endpoint.URL = *req.URL
+	}
+	if req.EventTypes != nil {
+		eventTypes, err := normalizeWebhookEventTypes(*req.EventTypes)
+		if err != nil {
+			return nil, err
+		}
+		endpoint.EventTypes = eventTypes
+	}
+	resumed := false
+	if req.Enabled != nil && *req.Enabled != endpoint.Enabled {
+		endpoint.Enabled = *req.Enabled
+		if endpoint.Enabled {
+			endpoint.ConsecutiveFailures = 0
+			endpoint.DisabledAt = nil
+			resumed = true
+		} else {
+			now := time.Now()
+			endpoint.DisabledAt = &now
+		}
+	}
+	endpoint.UpdatedAt = time.Now()
+
+	if err := u.endpointRepo.Update(ctx, endpoint); err != nil {
+		return nil, fmt.Errorf("failed to update webhook endpoint: %w", err)
+	}
+
+	if resumed {
+		u.logger.InfoContext(ctx, "webhook endpoint re-enabled", "endpoint_id", id, "project_id", projectID)
+		u.notify()
+	}
+	return endpoint, nil
+}
+
+// DeleteEndpoint はWebhookの送信先を削除する（送信待ちの配信と配信ログも削除される）
+func (u *WebhookEndpointUsecase) DeleteEndpoint(ctx context.Context, userID, projectID, id string) error {
+	if err := u.authorizeProject(ctx, userID, projectID); err != nil {
+		return err
+	}
+	if err := validateResourceID(id); err != nil {
+		return err
+	}
+
+	if err := u.endpointRepo.Delete(ctx, projectID, id); err != nil {
+		return fmt.Errorf("failed to delete webhook endpoint: %w", err)
+	}
+	return nil
+}
+
+// ListDeliveries は送信先の配信ログを新しい順に一覧する
+func (u *WebhookEndpointUsecase) ListDeliveries(ctx context.Context, userID, projectID, id string) ([]*model.WebhookEndpointDelivery, error) {
+	endpoint, err := u.findEndpoint(ctx, userID, projectID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	deliveries, err := u.deliveryRepo.FindByEndpointID(ctx, endpoint.ID, webhookEndpointListLimit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list webhook endpoint deliveries: %w", err)
+	}
+	return deliveries, nil
+}
+
+// Dispatch はドメインイベントをプロジェクトの有効な送信先ごとの配信としてキューに保存するイベントバスの購読者
+// 送信は非同期に行うため、送信先の障害でイベントバスの配信を止めない
+func (u *WebhookEndpointUsecase) Dispatch(ctx context.Context, event *model.OutboxEvent) error {
+	projectID := eventProjectID(event)
+	if projectID == "" {
+		return nil
+	}
+
+	endpoints, err := u.endpointRepo.FindEnabledByProjectID(ctx, projectID)
+	if err != nil {
+		return fmt.Errorf("failed to find webhook endpoints: %w", err)
+	}
+
+	var body []byte
+	queued := false
+	for _, endpoint := range endpoints {
+		if !endpoint.Subscribes(event.Type) {
+			continue
+		}
+		if body == nil {
+			body, err = json.Marshal(model.WebhookEventPayload{
+				ID:         event.ID,
+				Type:       event.Type,
+				OccurredAt: event.OccurredAt,
+				Data:       event.Payload,
+			})
+			if err != nil {
+				return fmt.Errorf("failed to marshal webhook payload: %w", err)
+			}
+		}
+
+		now := time.Now()
+		delivery := &model.WebhookEndpointDelivery{
+			ID:            uuid.New().String(),
+			EndpointID:    endpoint.ID,
+			UserID:        endpoint.UserID,
+			EventID:       event.ID,
+			EventType:     event.Type,
+			Payload:       body,
+			Status:        model.WebhookEndpointDeliveryPending,
+			NextAttemptAt: now,
+			CreatedAt:     now,
+			UpdatedAt:     now,
+		}
+		if err := u.deliveryRepo.Create(ctx, delivery); err != nil {
+			return fmt.Errorf("failed to enqueue webhook endpoint delivery: %w", err)
+		}
+		queued = true
+	}
+
+	if queued {
+		u.notify()
+	}
+	return nil
+}
+
+// Run はctxがキャンセルされるまで送信時刻を迎えた配信を送信する
+// 新しい配信をキューに保存した時はすぐに、それ以外はpollIntervalごとに再試行待ちの配信を確認する
+func (u *WebhookEndpointUsecase) Run(ctx context.Context) {
+	ticker := time.NewTicker(u.pollInterval)
+	defer ticker.Stop()
+
+	var prunedAt time.Time
+	for {
+		u.workers.Beat(webhookEndpointWorker, u.sendDue(ctx))
+
+		if time.Since(prunedAt) >= webhookEndpointPruneInterval {
+			u.prune(ctx)
+			prunedAt = time.Now()
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-u.wake:
+		}
+	}
+}
+
+// notify はワーカーに新しい配信があることを知らせる（既に通知済みの場合は何もしない）
+func (u *WebhookEndpointUsecase) notify() {
+	select {
+	case u.wake <- struct{}{}:
+	default:
+	}
+}
+
+// sendDue は送信時刻を迎えた配信がなくなるまで取得して送信する
+// 配信を取得できなかった場合はエラーを返す（個々の配信の失敗は再試行に回すためエラーにしない）
+func (u *WebhookEndpointUsecase) sendDue(ctx context.Context) error {
+	for ctx.Err() == nil {
+		deliveries, err := u.deliveryRepo.ClaimDue(ctx, time.Now(), webhookEndpointLease, webhookEndpointBatchSize)
+		if err != nil {
+			// DBの一時的な障害の場合は次の確認時に再度取得する
+			u.logger.ErrorContext(ctx, "failed to claim webhook endpoint deliveries", "error", err)
+			return err
+		}
+
+		endpoints := make(map[string]*model.WebhookEndpoint)
+		for _, delivery := range deliveries {
+			u.send(ctx, delivery, endpoints)
+		}
+		if len(deliveries) < webhookEndpointBatchSize {
+			return nil
+		}
+	}
+	return nil
+}
+
+// send は配信を送信先に送信し、結果を記録する
+// endpointsは同じバッチで取得した送信先のキャッシュ（途中で無効にした送信先には残りを送らない）
+func (u *WebhookEndpointUsecase) send(ctx context.Context, delivery *model.WebhookEndpointDelivery, endpoints map[string]*model.WebhookEndpoint) {
+	endpoint, ok := endpoints[delivery.EndpointID]
+	if !ok {
+		var err error
+		endpoint, err = u.endpointRepo.FindByID(ctx, delivery.EndpointID)
+		if err != nil {
+			// 送信先が削除された場合は配信も削除されているため、記録せずに終える
+			if !errors.Is(err, model.ErrNotFound) {
+				u.logger.ErrorContext(ctx, "failed to find webhook endpoint", "error", err, "delivery_id", delivery.ID)
+			}
+			return
+		}
+		endpoints[delivery.EndpointID] = endpoint
+	}
+	if !endpoint.Enabled {
+		// 無効にした送信先の配信はleaseの後も取得されず、有効に戻した時に送信する
+		return
+	}
+
+	status, err := u.sender.Send(ctx, endpoint.URL, endpoint.Secret, webhook.Message{
+		ID:    delivery.ID,
+		Event: delivery.EventType,
+		Body:  delivery.Payload,
+	})
+	if err != nil {
+		u.markFailed(ctx, delivery, endpoint, status, err)
+		return
+	}
+
+	if err := u.deliveryRepo.MarkSucceeded(ctx, delivery.ID, status, time.Now()); err != nil {
+		// 記録に失敗した場合はleaseの後に再送される（受信側はX-Webhook-Idで重複を除ける）
+		u.logger.ErrorContext(ctx, "failed to mark webhook endpoint delivery succeeded", "error", err, "delivery_id", delivery.ID)
+	}
+	if endpoint.ConsecutiveFailures > 0 {
+		if err := u.endpointRepo.RecordSuccess(ctx, endpoint.ID); err != nil {
+			u.logger.ErrorContext(ctx, "failed to reset webhook endpoint failures", "error", err, "endpoint_id", endpoint.ID)
+		}
+		endpoint.ConsecutiveFailures = 0
+	}
+}
+
+// markFailed は送信の失敗を記録し、試行回数が上限に達した配信を失敗にする
+// 送信先の連続失敗回数がdisableAfterに達した場合は送信先を無効にする
+func (u *WebhookEndpointUsecase) markFailed(ctx context.Context, delivery *model.WebhookEndpointDelivery, endpoint *model.WebhookEndpoint, status int, cause error) {
+	attempts := delivery.Attempts + 1
+	dead := attempts >= u.maxAttempts
+	nextAttemptAt := time.Now().Add(webhookEndpointRetryDelay(attempts))
+
+	message := cause.Error()
+	if runes := []rune(message); len(runes) > webhookEndpointErrorMaxLen {
+		message = string(runes[:webhookEndpointErrorMaxLen])
+	}
+	var responseStatus *int
+	if status != 0 {
+		responseStatus = &status
+	}
+
+	if dead {
+		u.logger.ErrorContext(ctx, "webhook endpoint delivery failed permanently",
+			"error", cause, "delivery_id", delivery.ID, "endpoint_id", endpoint.ID, "attempts", attempts)
+	} else {
+		u.logger.WarnContext(ctx, "webhook endpoint delivery failed, will retry",
+			"error", cause, "delivery_id", delivery.ID, "endpoint_id", endpoint.ID, "attempts", attempts, "next_attempt_at", nextAttemptAt)
+	}
+
+	if err := u.deliveryRepo.MarkFailed(ctx, delivery.ID, attempts, responseStatus, message, nextAttemptAt, dead); err != nil {
+		u.logger.ErrorContext(ctx, "failed to mark webhook endpoint delivery failed", "error", err, "delivery_id", delivery.ID)
+	}
+
+	disabled, err := u.endpointRepo.RecordFailure(ctx, endpoint.ID, u.disableAfter, time.Now())
+	if err != nil {
+		u.logger.ErrorContext(ctx, "failed to record webhook endpoint failure", "error", err, "endpoint_id", endpoint.ID)
+		return
+	}
+	endpoint.ConsecutiveFailures++
+	if disabled {
+		endpoint.Enabled = false
+		u.logger.WarnContext(ctx, "webhook endpoint disabled after repeated failures",
+			"endpoint_id", endpoint.ID, "project_id", endpoint.ProjectID, "consecutive_failures", endpoint.ConsecutiveFailures)
+	}
+}
+
+// prune はretentionより前に作成された送信済み・失敗の配信ログを削除する
+func (u *WebhookEndpointUsecase) prune(ctx context.Context) {
+	deleted, err := u.deliveryRepo.DeleteFinishedBefore(ctx, time.Now().Add(-u.retention))
+	if err != nil {
+		u.logger.ErrorContext(ctx, "failed to prune webhook endpoint deliveries", "error", err)
+		return
+	}
+	if deleted > 0 {
+		u.logger.InfoContext(ctx, "finished webhook endpoint deliveries pruned", "count", deleted)
+	}
+}
+
+// findEndpoint はプロジェクトをuserIDが所有していることを確認して送信先を取得する
+func (u *WebhookEndpointUsecase) findEndpoint(ctx context.Context, userID, projectID, id string) (*model.WebhookEndpoint, error) {
+	if err := u.authorizeProject(ctx, userID, projectID); err != nil {
+		return nil, err
+	}
+	if err := validateResourceID(id); err != nil {
+		return nil, err
+	}
+
+	endpoint, err := u.endpointRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find webhook endpoint: %w", err)
+	}
+	if endpoint.ProjectID != projectID {
+		return nil, fmt.Errorf("webhook endpoint not found: %s: %w", id, model.ErrNotFound)
+	}
+	return endpoint, nil
+}
+
+// authorizeProject はプロジェクトをuserIDが所有していることを確認する
+func (u *WebhookEndpointUsecase) authorizeProject(ctx context.Context, userID, projectID string) error {
+	if err := validateResourceID(projectID); err != nil {
+		return err
+	}
+	project, err := u.projectRepo.FindByID(ctx, projectID)
+	if err != nil {
+		return fmt.Errorf("failed to find project: %w", err)
+	}
+	if project.UserID != userID {
+		return model.ErrForbidden
+	}
+
+	return nil
+}
+
+// validateWebhookURL は送信先のURLがhttpかhttpsの絶対URLで、内部のアドレスを指していないことを検証する
+func (u *WebhookEndpointUsecase) validateWebhookURL(raw string) error {
+	if err := u.sender.CheckURL(raw); err != nil {
+		return fmt.Errorf("%w: %w", err, model.ErrInvalidInput)
+	}
+	return nil
+}
+
+// normalizeWebhookEventTypes は通知するイベントの種類を検証し、重複を除いて返す（空の場合はすべて）
+func normalizeWebhookEventTypes(eventTypes []string) ([]string, error) {
+	normalized := []string{}
+	for _, t := range eventTypes {
+		if !slices.Contains(model.WebhookEventTypes, t) {
+			return nil, fmt.Errorf("unknown webhook event type %q: %w", t, model.ErrInvalidInput)
+		}
+		if !slices.Contains(normalized, t) {
+			normalized = append(normalized, t)
+		}
+	}
+	return normalized, nil
+}
+
+// webhookEndpointRetryDelay はattempts回目の失敗の後、次の試行までの待ち時間を返す
+func webhookEndpointRetryDelay(attempts int) time.Duration {
+	delay := webhookEndpointRetryBase
+	for i := 1; i < attempts && delay < webhookEndpointRetryMax; i++ {
+		delay *= 2
+	}
+	return min(delay, webhookEndpointRetryMax)
+}
@@ -1,6 +1,9 @@
 package model
 
-import "time"
+import (
+	"fmt"
+	"time"
+)
 
 // GithubAccount はGitHubアカウント認証情報を表すドメインモデル
 type GithubAccount struct {
@@ -11,9 +14,12 @@ type GithubAccount struct {
 	AccessToken       string     `json:"access_token,omitempty"`
 	RefreshToken      string     `json:"refresh_token,omitempty"`
 	ExpiresAt         *time.Time `json:"expires_at,omitempty"`
-	PATEncrypted      *string    `json:"-"` // Personal Access Token (暗号化済み)
-	CreatedAt         time.Time  `json:"created_at"`
-	UpdatedAt         time.Time  `json:"updated_at"`
+	PATEncrypted      *string    `json:"-"` // Personal Access Token（保存時にリポジトリで暗号化し、読み取り時に復号する）
+	// ReauthRequiredAt はGitHubがトークンを拒否した（401）日時（再認証が不要な場合はnil）
+	ReauthRequiredAt *time.Time         `json:"reauth_required_at,omitempty"`
+	ReauthReason     GithubReauthReason `json:"reauth_reason,omitempty"`
+	CreatedAt        time.Time          `json:"created_at"`
+	UpdatedAt        time.Time          `json:"updated_at"`
 }
 
 // HasPAT はPATが設定されているかを返す
@@ -21,6 +27,69 @@ func (a *GithubAccount) HasPAT() bool {
 	return a.PATEncrypted != nil && *a.PATEncrypted != ""
 }
 
+// NeedsReauth は再認証が必要かを返す
+func (a *GithubAccount) NeedsReauth() bool {
+	return a.ReauthRequiredAt != nil
+}
+
+// TokenKind は現在GitHubへのリクエストに使うトークンの種類を返す（PAT優先）
+func (a *GithubAccount) TokenKind() GithubReauthReason {
+	if a.HasPAT() {
+		return GithubReauthReasonPAT
+	}
+	return GithubReauthReasonOAuth
+}
+
+// NeedsTokenRefresh はOAuthのアクセストークンがnowからmarginの間に期限切れになり、リフレッシュが必要かを返す
+// 期限のないトークン（OAuth Appのトークン）やリフレッシュトークンのないアカウントはリフレッシュしない
+func (a *GithubAccount) NeedsTokenRefresh(now time.Time, margin time.Duration) bool {
+	return a.ExpiresAt != nil && a.RefreshToken != "" && now.Add(margin).After(*a.ExpiresAt)
+}
+
+// ClearReauth はreasonのトークンを取り直した場合に再認証が必要な状態を解除する
+// 別の種類のトークンが拒否されている場合はそのままにする（例：OAuthで再ログインしてもPATは無効のまま）
+func (a *GithubAccount) ClearReauth(reason GithubReauthReason) {
+	if a.ReauthReason == reason {
+		a.ReauthRequiredAt = nil
+		a.ReauthReason = ""
+	}
+}
+
+// GithubReauthReason は再認証が必要になったトークンの種類
+type GithubReauthReason string
+
+const (
+	// GithubReauthReasonOAuth はOAuthのアクセストークンが失効・取り消された
+	GithubReauthReasonOAuth GithubReauthReason = "oauth"
+	// GithubReauthReasonPAT はPATが失効・取り消された
+	GithubReauthReasonPAT GithubReauthReason = "pat"
+)
+
+// ReauthPath は再認証の手続きを行うパス
+// OAuthはGitHubでログインし直し、PATは新しいPATを登録し直す（削除するとOAuthのトークンを使う）
+func (r GithubReauthReason) ReauthPath() string {
+	if r == GithubReauthReasonPAT {
+		return "/api/v1/github/pat"
+	}
+	return "/auth/github/login"
+}
+
+// ErrGithubReauthRequired はGitHubのトークンが失効・取り消されていて、再認証が必要な場合のエラー
+var ErrGithubReauthRequired = fmt.Errorf("github re-authentication required: %w", ErrUnauthorized)
+
+// GithubReauthError は再認証が必要なトークンの種類を持つErrGithubReauthRequired
+type GithubReauthError struct {
+	Reason GithubReauthReason
+}
+
+func (e *GithubReauthError) Error() string {
+	return fmt.Sprintf("github %s token was rejected: %v", e.Reason, ErrGithubReauthRequired)
+}
+
+func (e *GithubReauthError) Unwrap() error {
+	return ErrGithubReauthRequired
+}
+
 // GoogleAccount はGoogleアカウント認証情報を表すドメインモデル
 type GoogleAccount struct {
 	ID                string     `json:"id"`
@@ -33,3 +102,50 @@ type GoogleAccount struct {
 	CreatedAt         time.Time  `json:"created_at"`
 	UpdatedAt         time.Time  `json:"updated_at"`
 }
+
+// AppleAccount はSign in with Appleのアカウント認証情報を表すドメインモデル
+type AppleAccount struct {
+	ID                string `json:"id"`
+	UserID            string `json:"user_id"`
+	Provider          string `json:"provider"`
+	ProviderAccountID string `json:"provider_account_id"`
+	// Email はAppleから通知されたメールアドレス（非公開を選んだ場合は転送用アドレス）
+	Email string `json:"email"`
+	// IsPrivateEmail はEmailがAppleのプライベートリレー（転送用アドレス）かどうか
+	IsPrivateEmail bool       `json:"is_private_email"`
+	AccessToken    string     `json:"access_token,omitempty"`
+	RefreshToken   string     `json:"refresh_token,omitempty"`
+	ExpiresAt      *time.Time `json:"expires_at,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
+
+// MicrosoftAccount はMicrosoft Entra ID（Azure AD）のアカウント認証情報を表すドメインモデル
+type MicrosoftAccount struct {
+	ID                string     `json:"id"`
+	UserID            string     `json:"user_id"`
+	Provider          string     `json:"provider"`
+	ProviderAccountID string     `json:"provider_account_id"`
+	AccessToken       string     `json:"access_token,omitempty"`
+	RefreshToken      string     `json:"refresh_token,omitempty"`
+	ExpiresAt         *time.Time `json:"expires_at,omitempty"`
+	CreatedAt         time.Time  `json:"created_at"`
+	UpdatedAt         time.Time  `json:"updated_at"`
+}
+
+// SAMLAccount はSAML IdPのアカウント（NameID）とユーザーの紐付けを表すドメインモデル
+type SAMLAccount struct {
+	UserID      string    `json:"user_id"`
+	IdPEntityID string    `json:"idp_entity_id"`
+	NameID      string    `json:"name_id"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// SAMLRequest はIdPに送ったAuthnRequestを表すドメインモデル（RelayStateはハッシュのみを保存する）
+type SAMLRequest struct {
+	RelayStateHash string    `json:"-"`
+	RequestID      string    `json:"request_id"`
+	ExpiresAt      time.Time `json:"expires_at"`
+	CreatedAt      time.Time `json:"created_at"`
+}
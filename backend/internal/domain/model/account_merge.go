@@ -0,0 +1,45 @@
+package model
+
+import (
+	"fmt"
+	"time"
+)
+
+// ErrAccountMergeRequired は別のプロバイダーで同じメールアドレスのユーザーが登録済みで、紐付けに確認が必要な場合のエラー
+var ErrAccountMergeRequired = fmt.Errorf("account merge confirmation is required: %w", ErrConflict)
+
+// アカウント統合の確認方法
+const (
+	// AccountMergeViaEmail は既存ユーザーのメールアドレスに送った確認リンクで確認したことを表す
+	AccountMergeViaEmail = "email"
+	// AccountMergeViaSession は既存ユーザーとしてログインした状態で確認したことを表す
+	AccountMergeViaSession = "session"
+)
+
+// AccountMerge は既存ユーザーへの別プロバイダーのアカウントの紐付け（統合）の要求と、その確認の監査記録を表す
+// 確認されるまでプロバイダーのアカウントは作成せず、トークン等はここに保持する
+type AccountMerge struct {
+	ID                string `json:"id"`
+	UserID            string `json:"user_id"`
+	Provider          string `json:"provider"`
+	ProviderAccountID string `json:"provider_account_id"`
+	// ProviderEmail はプロバイダーから通知されたメールアドレス
+	ProviderEmail  string     `json:"provider_email"`
+	AccessToken    string     `json:"-"`
+	RefreshToken   string     `json:"-"`
+	TokenExpiresAt *time.Time `json:"-"`
+	// TokenHash はメールで送る確認トークンのSHA-256ハッシュ
+	TokenHash   string     `json:"-"`
+	ExpiresAt   time.Time  `json:"expires_at"`
+	ConfirmedAt *time.Time `json:"confirmed_at,omitempty"`
+	// ConfirmedVia は確認方法（AccountMergeViaEmail / AccountMergeViaSession）
+	ConfirmedVia string `json:"confirmed_via,omitempty"`
+	// ConfirmedIP は確認したクライアントのIPアドレス（ネットワーク部のみ）
+	ConfirmedIP string    `json:"confirmed_ip,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// Pending は統合が未確認かつ有効期限内かどうかを返す
+func (m *AccountMerge) Pending(now time.Time) bool {
+	return m.ConfirmedAt == nil && now.Before(m.ExpiresAt)
+}
@@ -0,0 +1,111 @@
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// ActivityAction はアクティビティログに記録する操作の種類を表す
+type ActivityAction string
+
+const (
+	// ActivityActionCreate は作成
+	ActivityActionCreate ActivityAction = "create"
+	// ActivityActionUpdate は更新（変更したフィールドがない更新は記録しない）
+	ActivityActionUpdate ActivityAction = "update"
+	// ActivityActionDelete はゴミ箱に移す・削除
+	ActivityActionDelete ActivityAction = "delete"
+	// ActivityActionRestore はゴミ箱から元に戻す
+	ActivityActionRestore ActivityAction = "restore"
+)
+
+// ActivityEntity はアクティビティログに記録する対象の種類を表す
+type ActivityEntity string
+
+const (
+	// ActivityEntityProject はプロジェクト
+	ActivityEntityProject ActivityEntity = "project"
+	// ActivityEntityTask はタスク
+	ActivityEntityTask ActivityEntity = "task"
+	// ActivityEntityMilestone はマイルストーン
+	ActivityEntityMilestone ActivityEntity = "milestone"
+)
+
+// ActivityChange はアクティビティで変更された1つのフィールドの変更前後の値を表す
+// 作成・復元ではFrom、削除ではToを省略する
+type ActivityChange struct {
+	From json.RawMessage `json:"from,omitempty"`
+	To   json.RawMessage `json:"to,omitempty"`
+}
+
+// Activity はプロジェクト内のリソースへの1回の変更の記録を表す
+type Activity struct {
+	ID        string `json:"id"`
+	ProjectID string `json:"project_id"`
+	// ActorID は変更したユーザーのID（GitHubからの反映等のシステムの処理による変更やユーザーを削除した場合はnil）
+	ActorID    *string                   `json:"actor_id"`
+	EntityType ActivityEntity            `json:"entity_type"`
+	EntityID   string                    `json:"entity_id"`
+	Action     ActivityAction            `json:"action"`
+	Changes    map[string]ActivityChange `json:"changes"`
+	OccurredAt time.Time                 `json:"occurred_at"`
+}
+
+// activityIgnoredFields は変更の差分に含めないフィールド（変更のたびに更新される・操作の種類で表されるもの）
+var activityIgnoredFields = map[string]bool{
+	"updated_at": true,
+	"deleted_at": true,
+	"version":    true,
+}
+
+// ActivityChanges はbeforeからafterへの変更をJSONのフィールド単位の差分で返す
+// 作成・復元の場合はbeforeを、削除の場合はafterをnilにする
+func ActivityChanges(before, after any) (map[string]ActivityChange, error) {
+	from, err := activityFields(before)
+	if err != nil {
+		return nil, err
+	}
+	to, err := activityFields(after)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := make(map[string]ActivityChange)
+	for key, value := range from {
+		if activityIgnoredFields[key] {
+			continue
+		}
+		if next, ok := to[key]; !ok || string(next) != string(value) {
+			changes[key] = ActivityChange{From: value, To: next}
+		}
+	}
+	for key, value := range to {
+		if activityIgnoredFields[key] {
+			continue
+		}
+		if _, ok := from[key]; !ok {
+			changes[key] = ActivityChange{To: value}
+		}
+	}
+	return changes, nil
+}
+
+// activityFields はvをJSONのトップレベルのフィールドごとに分解する（nilの場合は空）
+func activityFields(v any) (map[string]json.RawMessage, error) {
+	fields := make(map[string]json.RawMessage)
+	if v == nil {
+		return fields, nil
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal activity value: %w", err)
+	}
+	if string(data) == "null" {
+		return fields, nil
+	}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal activity value: %w", err)
+	}
+	return fields, nil
+}
@@ -0,0 +1,51 @@
+package model
+
+import "time"
+
+// BackupTrigger はバックアップを作成した契機を表す
+type BackupTrigger string
+
+const (
+	// BackupTriggerManual は管理者がAPIから作成したバックアップ
+	BackupTriggerManual BackupTrigger = "manual"
+	// BackupTriggerScheduled は定期バックアップ（BACKUP_RETENTIONの世代数を超えると古いものから削除する）
+	BackupTriggerScheduled BackupTrigger = "scheduled"
+	// BackupTriggerUpload は管理者がアップロードしたバックアップ
+	BackupTriggerUpload BackupTrigger = "upload"
+	// BackupTriggerPreRestore は復元の直前に自動で作成したバックアップ
+	BackupTriggerPreRestore BackupTrigger = "pre_restore"
+)
+
+// IsValid は定義済みの契機かどうかを返す
+func (t BackupTrigger) IsValid() bool {
+	switch t {
+	case BackupTriggerManual, BackupTriggerScheduled, BackupTriggerUpload, BackupTriggerPreRestore:
+		return true
+	}
+	return false
+}
+
+// Backup は保存したバックアップを表すドメインモデル
+type Backup struct {
+	// Name はバックアップのファイル名（API上の識別子）
+	Name      string        `json:"name"`
+	Trigger   BackupTrigger `json:"trigger"`
+	Size      int64         `json:"size"`
+	CreatedAt time.Time     `json:"created_at"`
+	// Rows はテーブルごとの行数（作成・アップロード時のみ）
+	Rows map[string]int `json:"rows,omitempty"`
+}
+
+// RestoreBackupRequest はバックアップからの復元リクエスト
+type RestoreBackupRequest struct {
+	// Confirm には誤操作を防ぐため復元するバックアップの名前をそのまま指定する
+	Confirm string `json:"confirm" validate:"required"`
+}
+
+// RestoreResult はバックアップからの復元の結果
+type RestoreResult struct {
+	Backup string `json:"backup"`
+	// PreRestoreBackup は復元の直前の状態を保存したバックアップの名前
+	PreRestoreBackup string         `json:"pre_restore_backup"`
+	Rows             map[string]int `json:"rows"`
+}
@@ -0,0 +1,64 @@
+package model
+
+import "time"
+
+// DashboardActivityLimit はダッシュボードに表示する最近のアクティビティの件数
+const DashboardActivityLimit = 20
+
+// DashboardActivity はダッシュボードに表示するタスクのステータス遷移を表す
+type DashboardActivity struct {
+	*TaskStatusEvent
+	TaskTitle    string `json:"task_title"`
+	ProjectTitle string `json:"project_title"`
+}
+
+// DueTasks は今日が期限のタスクと期限切れのタスクを表す
+type DueTasks struct {
+	Today   []*Task `json:"today"`
+	Overdue []*Task `json:"overdue"`
+}
+
+// NewDueTasks は未完了タスクを今日（locの0時から翌日0時まで）が期限のものと、それより前に期限が切れたものに分ける
+func NewDueTasks(now time.Time, loc *time.Location, tasks []*Task) *DueTasks {
+	todayStart := StartOfDay(now, loc)
+	tomorrowStart := todayStart.AddDate(0, 0, 1)
+
+	due := &DueTasks{Today: []*Task{}, Overdue: []*Task{}}
+	for _, t := range tasks {
+		if t.Status == TaskStatusDone || t.EndDate == nil || !t.EndDate.Before(tomorrowStart) {
+			continue
+		}
+		if t.EndDate.Before(todayStart) {
+			due.Overdue = append(due.Overdue, t)
+		} else {
+			due.Today = append(due.Today, t)
+		}
+	}
+	return due
+}
+
+// NewDashboardActivities はイベントにタスクとプロジェクトのタイトルを付ける（タスクが見つからないイベントは除く）
+func NewDashboardActivities(events []*TaskStatusEvent, tasks []*Task, projects []*Project) []*DashboardActivity {
+	taskTitles := make(map[string]string, len(tasks))
+	for _, t := range tasks {
+		taskTitles[t.ID] = t.Title
+	}
+	projectTitles := make(map[string]string, len(projects))
+	for _, p := range projects {
+		projectTitles[p.ID] = p.Title
+	}
+
+	activities := make([]*DashboardActivity, 0, len(events))
+	for _, e := range events {
+		title, ok := taskTitles[e.TaskID]
+		if !ok {
+			continue
+		}
+		activities = append(activities, &DashboardActivity{
+			TaskStatusEvent: e,
+			TaskTitle:       title,
+			ProjectTitle:    projectTitles[e.ProjectID],
+		})
+	}
+	return activities
+}
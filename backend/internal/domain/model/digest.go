@@ -0,0 +1,112 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// DigestUpcomingDays は週次ダイジェストで期限間近とみなす日数
+const DigestUpcomingDays = 7
+
+// DigestTask は週次ダイジェストに載せるタスクとその所属プロジェクトを表す
+type DigestTask struct {
+	ProjectTitle string `json:"project_title"`
+	*Task
+}
+
+// WeeklyDigest はユーザーの週次サマリーを表す
+type WeeklyDigest struct {
+	UserID      string        `json:"user_id"`
+	PeriodStart time.Time     `json:"period_start"`
+	PeriodEnd   time.Time     `json:"period_end"`
+	Completed   []*DigestTask `json:"completed"`
+	Upcoming    []*DigestTask `json:"upcoming"`
+	Stalled     []*DigestTask `json:"stalled"`
+}
+
+// NewWeeklyDigest はsinceからnowまでの期間のダイジェストを作成する
+// Completedは期間内に完了へ遷移し現在も完了のタスク、Upcomingは終了日がnowからDigestUpcomingDays日以内（期限切れを含む）の未完了タスク、
+// Stalledは進行中のまま期間内にステータス遷移も更新もないタスクとする
+func NewWeeklyDigest(userID string, since, now time.Time, projects []*Project, tasks []*Task, events []*TaskStatusEvent) *WeeklyDigest {
+	digest := &WeeklyDigest{
+		UserID:      userID,
+		PeriodStart: since,
+		PeriodEnd:   now,
+		Completed:   []*DigestTask{},
+		Upcoming:    []*DigestTask{},
+		Stalled:     []*DigestTask{},
+	}
+
+	titles := make(map[string]string, len(projects))
+	for _, p := range projects {
+		titles[p.ID] = p.Title
+	}
+
+	completedInPeriod := make(map[string]bool)
+	movedInPeriod := make(map[string]bool)
+	for _, e := range events {
+		if e.ChangedAt.Before(since) || e.ChangedAt.After(now) {
+			continue
+		}
+		movedInPeriod[e.TaskID] = true
+		if e.ToStatus == TaskStatusDone {
+			completedInPeriod[e.TaskID] = true
+		}
+	}
+
+	upcomingBefore := now.AddDate(0, 0, DigestUpcomingDays)
+	for _, t := range tasks {
+		item := &DigestTask{ProjectTitle: titles[t.ProjectID], Task: t}
+		switch {
+		case t.Status == TaskStatusDone:
+			if completedInPeriod[t.ID] {
+				digest.Completed = append(digest.Completed, item)
+			}
+			continue
+		case t.Status == TaskStatusInProgress && !movedInPeriod[t.ID] && t.UpdatedAt.Before(since):
+			digest.Stalled = append(digest.Stalled, item)
+		}
+		if t.EndDate != nil && t.EndDate.Before(upcomingBefore) {
+			digest.Upcoming = append(digest.Upcoming, item)
+		}
+	}
+
+	return digest
+}
+
+// IsEmpty は載せる内容がないかどうかを返す
+func (d *WeeklyDigest) IsEmpty() bool {
+	return len(d.Completed) == 0 && len(d.Upcoming) == 0 && len(d.Stalled) == 0
+}
+
+// Render はダイジェストをメールの件名と本文に整形する（日時はlocで表示する）
+func (d *WeeklyDigest) Render(loc *time.Location) (subject, body string) {
+	const dateLayout = "2006/01/02"
+
+	subject = fmt.Sprintf("週次ダイジェスト (%s - %s)",
+		d.PeriodStart.In(loc).Format(dateLayout), d.PeriodEnd.In(loc).Format(dateLayout))
+
+	var b strings.Builder
+	writeSection := func(heading string, items []*DigestTask, withDue bool) {
+		fmt.Fprintf(&b, "■ %s (%d件)\n", heading, len(items))
+		if len(items) == 0 {
+			b.WriteString("  なし\n")
+		}
+		for _, item := range items {
+			fmt.Fprintf(&b, "  - [%s] %s", item.ProjectTitle, item.Title)
+			if withDue && item.EndDate != nil {
+				fmt.Fprintf(&b, " (期限: %s)", item.EndDate.In(loc).Format(dateLayout))
+			}
+			b.WriteString("\n")
+		}
+		b.WriteString("\n")
+	}
+
+	writeSection("今週完了したタスク", d.Completed, false)
+	writeSection(fmt.Sprintf("期限切れ・%d日以内に期限のタスク", DigestUpcomingDays), d.Upcoming, true)
+	writeSection("停滞しているタスク", d.Stalled, false)
+	b.WriteString("配信を停止する場合は設定から週次ダイジェストを無効にしてください。\n")
+
+	return subject, b.String()
+}
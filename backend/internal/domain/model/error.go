@@ -1,6 +1,9 @@
 package model
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+)
 
 // ErrNotFound はリソースが見つからない場合のエラー
 var ErrNotFound = errors.New("resource not found")
@@ -17,5 +20,25 @@ var ErrInvalidInput = errors.New("invalid input")
 // ErrConflict はリソースが競合している場合のエラー
 var ErrConflict = errors.New("resource conflict")
 
+// ErrStaleVersion は更新元のバージョンが古い（他の更新と競合した）場合のErrConflict
+var ErrStaleVersion = fmt.Errorf("stale version: %w", ErrConflict)
+
+// StaleVersionError は他の更新と競合したリソースの現在の状態を表すErrStaleVersion
+type StaleVersionError struct {
+	// Current は競合したリソースの現在の状態
+	Current any
+}
+
+func (e *StaleVersionError) Error() string {
+	return ErrStaleVersion.Error()
+}
+
+func (e *StaleVersionError) Unwrap() error {
+	return ErrStaleVersion
+}
+
+// ErrRateLimited は外部APIの利用上限に近いため処理を見送った場合のエラー
+var ErrRateLimited = errors.New("rate limited")
+
 // ErrInternalServer は内部サーバーエラー
 var ErrInternalServer = errors.New("internal server error")
@@ -0,0 +1,240 @@
+package model
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+)
+
+// ExportReport はエクスポート対象のレポートの種類を表す
+type ExportReport string
+
+const (
+	ExportReportVelocity ExportReport = "velocity"
+	ExportReportCFD      ExportReport = "cfd"
+	ExportReportOverdue  ExportReport = "overdue"
+	ExportReportStats    ExportReport = "stats"
+	// ExportReportTimeInStatus はステータスごとの滞留時間
+	ExportReportTimeInStatus ExportReport = "time_in_status"
+	// ExportReportFlowTime はリードタイム・サイクルタイム
+	ExportReportFlowTime ExportReport = "flow_time"
+)
+
+// IsValid は定義済みのレポートかどうかを返す
+func (r ExportReport) IsValid() bool {
+	switch r {
+	case ExportReportVelocity, ExportReportCFD, ExportReportOverdue, ExportReportStats, ExportReportTimeInStatus, ExportReportFlowTime:
+		return true
+	}
+	return false
+}
+
+// RequiresProject はプロジェクトの指定が必要なレポートかどうかを返す
+func (r ExportReport) RequiresProject() bool {
+	switch r {
+	case ExportReportVelocity, ExportReportCFD, ExportReportTimeInStatus, ExportReportFlowTime:
+		return true
+	}
+	return false
+}
+
+// ExportFormat はエクスポートのファイル形式を表す
+type ExportFormat string
+
+const (
+	ExportFormatCSV ExportFormat = "csv"
+	ExportFormatPDF ExportFormat = "pdf"
+)
+
+// IsValid は定義済みの形式かどうかを返す
+func (f ExportFormat) IsValid() bool {
+	return f == ExportFormatCSV || f == ExportFormatPDF
+}
+
+// ExportStatus はエクスポートの処理状況を表す
+type ExportStatus string
+
+const (
+	ExportStatusPending   ExportStatus = "pending"
+	ExportStatusCompleted ExportStatus = "completed"
+	ExportStatusFailed    ExportStatus = "failed"
+)
+
+// ExportParams はレポートの集計パラメータ（未指定の項目は各レポートAPIのデフォルト値を使う）
+type ExportParams struct {
+	ProjectID      string `json:"project_id,omitempty"`
+	IterationWeeks int    `json:"iteration_weeks,omitempty"`
+	Iterations     int    `json:"iterations,omitempty"`
+	Days           int    `json:"days,omitempty"`
+	DueSoonDays    int    `json:"due_soon_days,omitempty"`
+	StuckDays      int    `json:"stuck_days,omitempty"`
+}
+
+// WithDefaults は未指定の項目にデフォルト値を設定したパラメータを返す
+func (p ExportParams) WithDefaults(report ExportReport) ExportParams {
+	if p.IterationWeeks == 0 {
+		p.IterationWeeks = 2
+	}
+	if p.Iterations == 0 {
+		p.Iterations = 6
+	}
+	if p.Days == 0 {
+		p.Days = 30
+	}
+	if p.DueSoonDays == 0 && report == ExportReportOverdue {
+		p.DueSoonDays = 3
+	}
+	if p.StuckDays == 0 {
+		p.StuckDays = 7
+	}
+	return p
+}
+
+// ReportExport はレポートのエクスポートを表すドメインモデル
+type ReportExport struct {
+	ID          string       `json:"id"`
+	UserID      string       `json:"user_id"`
+	Report      ExportReport `json:"report"`
+	Format      ExportFormat `json:"format"`
+	Params      ExportParams `json:"params"`
+	Status      ExportStatus `json:"status"`
+	FileName    string       `json:"file_name,omitempty"`
+	ContentType string       `json:"content_type,omitempty"`
+	Error       *string      `json:"error,omitempty"`
+	CreatedAt   time.Time    `json:"created_at"`
+	CompletedAt *time.Time   `json:"completed_at,omitempty"`
+}
+
+// CreateReportExportRequest はエクスポート作成リクエスト
+type CreateReportExportRequest struct {
+	Report ExportReport `json:"report" validate:"required"`
+	Format ExportFormat `json:"format" validate:"required"`
+	ExportParams
+}
+
+// Validate はレポートの種類・形式とプロジェクトの指定を検証する
+func (r *CreateReportExportRequest) Validate() error {
+	if !r.Report.IsValid() {
+		return fmt.Errorf("unknown report %q: %w", r.Report, ErrInvalidInput)
+	}
+	if !r.Format.IsValid() {
+		return fmt.Errorf("unknown format %q: %w", r.Format, ErrInvalidInput)
+	}
+	if r.Report.RequiresProject() && r.ProjectID == "" {
+		return fmt.Errorf("project_id is required for %s: %w", r.Report, ErrInvalidInput)
+	}
+	return nil
+}
+
+// ReportTable はエクスポート用にレポートを表形式にしたもの
+type ReportTable struct {
+	Title  string
+	Header []string
+	Rows   [][]string
+}
+
+// Table はベロシティを期間ごとの行にする
+func (r *VelocityReport) Table() ReportTable {
+	table := ReportTable{
+		Title: fmt.Sprintf("Velocity (%d weeks/iteration, mean %s tasks, mean %s %s)",
+			r.IterationWeeks, formatFloat(r.MeanTasks), formatFloat(r.MeanPoints), r.EstimateUnit),
+		Header: []string{"start", "end", "completed_tasks", "completed_points", "in_progress"},
+	}
+	for _, it := range r.Iterations {
+		table.Rows = append(table.Rows, []string{
+			it.Start.Format(time.DateOnly), it.End.Format(time.DateOnly),
+			strconv.Itoa(it.CompletedTasks), formatFloat(it.CompletedPoints), strconv.FormatBool(it.InProgress),
+		})
+	}
+	return table
+}
+
+// Table は累積フロー図を日ごとの行にする
+func (c *CumulativeFlow) Table() ReportTable {
+	table := ReportTable{
+		Title:  fmt.Sprintf("Cumulative flow (%s)", c.Timezone),
+		Header: []string{"date", "todo", "in_progress", "done"},
+	}
+	for _, p := range c.Points {
+		table.Rows = append(table.Rows, []string{
+			p.Date, strconv.Itoa(p.TodoCount), strconv.Itoa(p.InProgressCount), strconv.Itoa(p.DoneCount),
+		})
+	}
+	return table
+}
+
+// Table は期限切れ・期限間近のタスクを1タスク1行にする
+func (r *OverdueReport) Table() ReportTable {
+	table := ReportTable{
+		Title: fmt.Sprintf("Overdue tasks (%d overdue, %d due within %d days)",
+			r.OverdueCount, r.DueSoonCount, r.DueSoonDays),
+		Header: []string{"project", "category", "task", "status", "end_date"},
+	}
+	appendRows := func(project, category string, tasks []*Task) {
+		for _, t := range tasks {
+			table.Rows = append(table.Rows, []string{
+				project, category, t.Title, t.Status.String(), t.EndDate.Format(time.RFC3339),
+			})
+		}
+	}
+	for _, g := range r.Projects {
+		appendRows(g.ProjectTitle, "overdue", g.Overdue)
+		appendRows(g.ProjectTitle, "due_soon", g.DueSoon)
+	}
+	return table
+}
+
+// Table はユーザー統計を日ごとの行にする
+func (s *UserStats) Table() ReportTable {
+	table := ReportTable{
+		Title: fmt.Sprintf("Productivity (%d completed, current streak %d, longest streak %d, mean %s hours to complete)",
+			s.TotalCompleted, s.CurrentStreak, s.LongestStreak, formatFloat(s.AverageHoursToComplete)),
+		Header: []string{"date", "completed"},
+	}
+	for _, d := range s.Daily {
+		table.Rows = append(table.Rows, []string{d.Date, strconv.Itoa(d.Completed)})
+	}
+	return table
+}
+
+// Table はステータスごとの滞留時間を1タスク1行にする
+func (r *TimeInStatusReport) Table() ReportTable {
+	table := ReportTable{
+		Title: fmt.Sprintf("Time in status (%d stuck in progress for %s hours or more)",
+			r.StuckTaskCount, formatFloat(r.StuckAfterHours)),
+		Header: []string{"task", "status", "todo_hours", "in_progress_hours", "current_hours", "stuck"},
+	}
+	for _, t := range r.Tasks {
+		table.Rows = append(table.Rows, []string{
+			t.Title, t.Status.String(),
+			formatFloat(t.Hours[TaskStatusTodo.String()]), formatFloat(t.Hours[TaskStatusInProgress.String()]),
+			formatFloat(t.CurrentHours), strconv.FormatBool(t.Stuck),
+		})
+	}
+	return table
+}
+
+// Table はリードタイム・サイクルタイムを完了したタスクごとの行にする
+func (r *FlowTimeReport) Table() ReportTable {
+	table := ReportTable{
+		Title: fmt.Sprintf("Lead time p50 %s hours / cycle time p50 %s hours (%d tasks)",
+			formatFloat(r.LeadTime.P50Hours), formatFloat(r.CycleTime.P50Hours), len(r.Tasks)),
+		Header: []string{"task", "completed_at", "lead_hours", "cycle_hours"},
+	}
+	for _, t := range r.Tasks {
+		cycle := ""
+		if t.CycleHours != nil {
+			cycle = formatFloat(*t.CycleHours)
+		}
+		table.Rows = append(table.Rows, []string{
+			t.Title, t.CompletedAt.Format(time.RFC3339), formatFloat(t.LeadHours), cycle,
+		})
+	}
+	return table
+}
+
+// formatFloat は小数を小数点以下2桁までの文字列にする
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(math.Round(v*100)/100, 'f', -1, 64)
+}
@@ -0,0 +1,112 @@
+package model
+
+import (
+	"strings"
+	"time"
+)
+
+// DefaultGithubStatusField は対応付けを設定していないプロジェクトで使うGitHub Projectsのステータスのフィールド名
+const DefaultGithubStatusField = "Status"
+
+// GithubStatusOptions はタスクのステータスごとに対応するGitHub Projectsの単一選択フィールドの選択肢名
+type GithubStatusOptions struct {
+	Todo       string `json:"todo" validate:"required,max=255"`
+	InProgress string `json:"in_progress" validate:"required,max=255"`
+	Done       string `json:"done" validate:"required,max=255"`
+}
+
+// GithubPriorityOptions はタスクの優先度ごとに対応するGitHub Projectsの単一選択フィールドの選択肢名
+type GithubPriorityOptions struct {
+	Low    string `json:"low" validate:"max=255"`
+	Medium string `json:"medium" validate:"max=255"`
+	High   string `json:"high" validate:"max=255"`
+}
+
+// GithubFieldMapping はプロジェクトのタスクのステータス・優先度と連携先のGitHub Projectのフィールドの対応付けを表す
+// GitHubへの同期（push）とGitHubからの取り込み（pull）の両方で使用する
+type GithubFieldMapping struct {
+	ProjectID     string              `json:"project_id"`
+	StatusField   string              `json:"status_field"`
+	StatusOptions GithubStatusOptions `json:"status_options"`
+	// PriorityField は優先度を同期する単一選択フィールド名（未設定の場合は同期しない）
+	PriorityField   *string               `json:"priority_field,omitempty"`
+	PriorityOptions GithubPriorityOptions `json:"priority_options"`
+	// IsDefault は対応付けが未設定で既定値を返しているか
+	IsDefault bool      `json:"is_default"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// DefaultGithubFieldMapping はGitHub Projectsの既定のテンプレート（Status: Todo / In Progress / Done）に合わせた対応付けを返す
+func DefaultGithubFieldMapping(projectID string) *GithubFieldMapping {
+	return &GithubFieldMapping{
+		ProjectID:   projectID,
+		StatusField: DefaultGithubStatusField,
+		StatusOptions: GithubStatusOptions{
+			Todo:       "Todo",
+			InProgress: "In Progress",
+			Done:       "Done",
+		},
+		IsDefault: true,
+	}
+}
+
+// StatusOption はステータスに対応する選択肢名を返す
+func (m *GithubFieldMapping) StatusOption(status TaskStatus) string {
+	switch status {
+	case TaskStatusTodo:
+		return m.StatusOptions.Todo
+	case TaskStatusInProgress:
+		return m.StatusOptions.InProgress
+	case TaskStatusDone:
+		return m.StatusOptions.Done
+	}
+	return ""
+}
+
+// TaskStatusFor は選択肢名に対応するステータスを返す（大文字・小文字は区別しない）
+func (m *GithubFieldMapping) TaskStatusFor(option string) (TaskStatus, bool) {
+	for _, status := range []TaskStatus{TaskStatusTodo, TaskStatusInProgress, TaskStatusDone} {
+		if strings.EqualFold(m.StatusOption(status), option) {
+			return status, true
+		}
+	}
+	return 0, false
+}
+
+// PriorityOption は優先度に対応する選択肢名を返す（優先度を同期しない場合は空文字）
+func (m *GithubFieldMapping) PriorityOption(priority TaskPriority) string {
+	if m.PriorityField == nil {
+		return ""
+	}
+	switch priority {
+	case TaskPriorityLow:
+		return m.PriorityOptions.Low
+	case TaskPriorityMedium:
+		return m.PriorityOptions.Medium
+	case TaskPriorityHigh:
+		return m.PriorityOptions.High
+	}
+	return ""
+}
+
+// TaskPriorityFor は選択肢名に対応する優先度を返す（大文字・小文字は区別しない）
+func (m *GithubFieldMapping) TaskPriorityFor(option string) (TaskPriority, bool) {
+	if option == "" {
+		return 0, false
+	}
+	for _, priority := range []TaskPriority{TaskPriorityLow, TaskPriorityMedium, TaskPriorityHigh} {
+		if strings.EqualFold(m.PriorityOption(priority), option) {
+			return priority, true
+		}
+	}
+	return 0, false
+}
+
+// UpdateGithubFieldMappingRequest はフィールドの対応付けの更新リクエストを表す
+type UpdateGithubFieldMappingRequest struct {
+	StatusField   string              `json:"status_field" validate:"required,max=255"`
+	StatusOptions GithubStatusOptions `json:"status_options"`
+	// PriorityField を指定した場合はpriority_optionsのすべての優先度に選択肢名が必要
+	PriorityField   *string               `json:"priority_field,omitempty" validate:"omitempty,min=1,max=255"`
+	PriorityOptions GithubPriorityOptions `json:"priority_options"`
+}
@@ -0,0 +1,27 @@
+package model
+
+import "time"
+
+// GithubSyncOperationType はGitHubへの変更の種類を表す
+type GithubSyncOperationType string
+
+const (
+	// GithubSyncOperationSyncTask はタスクをGitHub Projectに同期する（実行時点のタスクの内容を反映する）
+	GithubSyncOperationSyncTask GithubSyncOperationType = "sync_task"
+)
+
+// GithubSyncOperation はGitHubに障害がある（5xx・接続できない）間に保留したGitHubへの変更を表す
+// 同じタスクの変更は作成順に1件ずつ再実行し、同じタスクの同じ種類の変更は1件にまとめる
+type GithubSyncOperation struct {
+	ID        string                  `json:"id"`
+	UserID    string                  `json:"-"`
+	ProjectID string                  `json:"project_id"`
+	TaskID    string                  `json:"task_id"`
+	Operation GithubSyncOperationType `json:"operation"`
+	Attempts  int                     `json:"attempts"`
+	// LastError は最後の再実行で失敗した理由（まだ再実行していない場合はnil）
+	LastError     *string   `json:"last_error,omitempty"`
+	NextAttemptAt time.Time `json:"next_attempt_at"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
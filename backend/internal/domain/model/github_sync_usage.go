@@ -0,0 +1,31 @@
+package model
+
+import (
+	"fmt"
+	"time"
+)
+
+// GithubSyncUsage はユーザーの現在の1時間（毎時0分から）のGitHubとの同期の回数を表す
+type GithubSyncUsage struct {
+	// Limit は1時間あたりの同期の上限（上限を設定していない場合はnil）
+	Limit *int `json:"limit"`
+	Used  int  `json:"used"`
+	// Remaining は上限までの残りの回数（上限を設定していない場合はnil）
+	Remaining   *int      `json:"remaining"`
+	WindowStart time.Time `json:"window_start"`
+	ResetAt     time.Time `json:"reset_at"`
+}
+
+// GithubSyncQuotaError はユーザーの1時間あたりの同期の上限に達したことを表すErrRateLimited
+type GithubSyncQuotaError struct {
+	Limit   int
+	ResetAt time.Time
+}
+
+func (e *GithubSyncQuotaError) Error() string {
+	return fmt.Sprintf("github sync quota of %d per hour exceeded until %s: %v", e.Limit, e.ResetAt.Format(time.RFC3339), ErrRateLimited)
+}
+
+func (e *GithubSyncQuotaError) Unwrap() error {
+	return ErrRateLimited
+}
@@ -0,0 +1,52 @@
+package model
+
+import "time"
+
+// GithubTaskSync はタスクとGitHub ProjectのItemを最後に一致させた時点を表す（双方向の同期の基準）
+// この時点より後に更新された側に変更があったとみなす
+type GithubTaskSync struct {
+	TaskID    string
+	UserID    string
+	ProjectID string
+	// LocalUpdatedAt は一致させた時点のタスクの更新日時
+	LocalUpdatedAt time.Time
+	// RemoteUpdatedAt は一致させた時点のItemの更新日時（GitHubの時刻、GitHubから取り込んだことがない場合はnil）
+	RemoteUpdatedAt *time.Time
+	UpdatedAt       time.Time
+}
+
+// GithubSyncDirection はタスクとItemのどちらの内容に合わせるかを表す
+type GithubSyncDirection string
+
+const (
+	// GithubSyncNone はどちらにも変更がない
+	GithubSyncNone GithubSyncDirection = "none"
+	// GithubSyncPull はItemの内容をタスクに取り込む
+	GithubSyncPull GithubSyncDirection = "pull"
+	// GithubSyncPush はタスクの内容をItemに反映する
+	GithubSyncPush GithubSyncDirection = "push"
+)
+
+// ResolveGithubSync はタスクとItemのどちらの内容に合わせるかを判定する
+// 片方だけが基準より後に更新されていればその側に合わせ、両方が更新されていれば（競合）更新日時の新しい方に合わせる
+// 基準がない（双方向の同期の導入前に同期した）タスクは競合として扱う
+func ResolveGithubSync(task *Task, remoteUpdatedAt time.Time, baseline *GithubTaskSync) (direction GithubSyncDirection, conflict bool) {
+	localChanged, remoteChanged := true, true
+	if baseline != nil {
+		localChanged = task.UpdatedAt.After(baseline.LocalUpdatedAt)
+		remoteChanged = baseline.RemoteUpdatedAt == nil || remoteUpdatedAt.After(*baseline.RemoteUpdatedAt)
+	}
+
+	switch {
+	case localChanged && remoteChanged:
+		if task.UpdatedAt.After(remoteUpdatedAt) {
+			return GithubSyncPush, true
+		}
+		return GithubSyncPull, true
+	case localChanged:
+		return GithubSyncPush, false
+	case remoteChanged:
+		return GithubSyncPull, false
+	}
+	return GithubSyncNone, false
+}
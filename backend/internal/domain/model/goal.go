@@ -0,0 +1,86 @@
+package model
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// Goal はプロジェクトの目標（OKR）を表すドメインモデル
+// タスクを貢献タスクとして紐づけ、その完了状況から進捗を集計する
+type Goal struct {
+	ID          string `json:"id"`
+	ProjectID   string `json:"project_id"`
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	// Quarter は対象の四半期（YYYY-Qn形式）
+	Quarter   string    `json:"quarter"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// ValidateQuarter は四半期がYYYY-Qn形式（nは1〜4）であることを検証する
+func ValidateQuarter(quarter string) error {
+	if len(quarter) != 7 || quarter[4:6] != "-Q" || quarter[6] < '1' || quarter[6] > '4' {
+		return fmt.Errorf("quarter %q must be in YYYY-Qn format: %w", quarter, ErrInvalidInput)
+	}
+	if _, err := strconv.Atoi(quarter[:4]); err != nil {
+		return fmt.Errorf("quarter %q must be in YYYY-Qn format: %w", quarter, ErrInvalidInput)
+	}
+	return nil
+}
+
+// GoalProgress は目標の進捗を表す
+type GoalProgress struct {
+	TaskCount     int     `json:"task_count"`
+	DoneCount     int     `json:"done_count"`
+	EstimateTotal float64 `json:"estimate_total"`
+	EstimateDone  float64 `json:"estimate_done"`
+	// Percent は完了率（0〜100）
+	// 貢献タスクに見積もりがある場合は見積もりの合計、ない場合はタスク数で計算する
+	Percent float64 `json:"percent"`
+}
+
+// NewGoalProgress はタスク数・見積もりの集計から進捗を作成する
+func NewGoalProgress(taskCount, doneCount int, estimateTotal, estimateDone float64) GoalProgress {
+	p := GoalProgress{
+		TaskCount:     taskCount,
+		DoneCount:     doneCount,
+		EstimateTotal: estimateTotal,
+		EstimateDone:  estimateDone,
+	}
+	switch {
+	case estimateTotal > 0:
+		p.Percent = estimateDone * 100 / estimateTotal
+	case taskCount > 0:
+		p.Percent = float64(doneCount) * 100 / float64(taskCount)
+	}
+	return p
+}
+
+// GoalWithProgress は進捗付きの目標を表す
+type GoalWithProgress struct {
+	*Goal
+	Progress GoalProgress `json:"progress"`
+	// TaskIDs は貢献タスクのID（詳細取得時のみ含まれる）
+	TaskIDs []string `json:"task_ids,omitempty"`
+}
+
+// CreateGoalRequest は目標作成リクエストを表す
+type CreateGoalRequest struct {
+	Title       string `json:"title" validate:"required,max=255"`
+	Description string `json:"description" validate:"max=10000"`
+	Quarter     string `json:"quarter" validate:"required"`
+}
+
+// UpdateGoalRequest は目標更新リクエストを表す
+type UpdateGoalRequest struct {
+	Title       string `json:"title" validate:"required,max=255"`
+	Description string `json:"description" validate:"max=10000"`
+	Quarter     string `json:"quarter" validate:"required"`
+}
+
+// AddGoalTaskRequest は貢献タスク追加リクエストを表す
+type AddGoalTaskRequest struct {
+	TaskID string `json:"task_id" validate:"required,uuid"`
+}
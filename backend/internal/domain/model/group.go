@@ -0,0 +1,24 @@
+package model
+
+import "time"
+
+// Group はIdPからプロビジョニング（SCIM）されたユーザーのグループを表すドメインモデル
+type Group struct {
+	ID          string `json:"id"`
+	DisplayName string `json:"display_name"`
+	// ExternalID はIdP側でのグループの識別子
+	ExternalID string    `json:"external_id,omitempty"`
+	MemberIDs  []string  `json:"member_ids"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// GroupPatch はグループの部分更新の内容（nilの項目は変更しない）
+type GroupPatch struct {
+	DisplayName *string
+	ExternalID  *string
+	// Members を指定した場合はメンバーを置き換える（AddMemberIDs・RemoveMemberIDsより先に適用する）
+	Members         *[]string
+	AddMemberIDs    []string
+	RemoveMemberIDs []string
+}
@@ -0,0 +1,33 @@
+package model
+
+import (
+	"fmt"
+	"time"
+)
+
+// ErrEmailDomainNotAllowed は登録を許可していないドメインのメールアドレスで新規登録しようとした場合のエラー
+var ErrEmailDomainNotAllowed = fmt.Errorf("email domain is not allowed to sign up: %w", ErrForbidden)
+
+// ErrInvitationRequired は招待制で招待のないメールアドレスが新規登録しようとした場合のエラー
+var ErrInvitationRequired = fmt.Errorf("an invitation is required to sign up: %w", ErrForbidden)
+
+// Invitation は招待制での新規登録の招待を表すドメインモデル
+type Invitation struct {
+	ID string `json:"id"`
+	// Email は招待したメールアドレス（小文字に正規化する）
+	Email      string     `json:"email"`
+	InvitedBy  string     `json:"invited_by"`
+	ExpiresAt  time.Time  `json:"expires_at"`
+	AcceptedAt *time.Time `json:"accepted_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// Pending は招待が未使用かつ有効期限内かどうかを返す
+func (i *Invitation) Pending(now time.Time) bool {
+	return i.AcceptedAt == nil && now.Before(i.ExpiresAt)
+}
+
+// CreateInvitationRequest は招待の作成リクエストを表す
+type CreateInvitationRequest struct {
+	Email string `json:"email" validate:"required,email,max=255"`
+}
@@ -0,0 +1,26 @@
+package model
+
+// SortOrder はソート順を表す
+type SortOrder string
+
+const (
+	SortAsc  SortOrder = "asc"
+	SortDesc SortOrder = "desc"
+)
+
+// SortKey は一覧取得時のソートキーを表す
+// FieldはAPI上のフィールド名（JSONのキー）で、各リポジトリがカラムに対応付ける
+type SortKey struct {
+	Field string    `json:"field" validate:"required"`
+	Order SortOrder `json:"order" validate:"omitempty,oneof=asc desc"`
+}
+
+// ListOptions は一覧取得時のオプションを表す
+type ListOptions struct {
+	// Sort は優先度順のソートキー（空の場合は各リポジトリのデフォルト順）
+	Sort []SortKey
+	// Limit は取得する最大件数（0の場合はすべて）
+	Limit int
+	// Offset は先頭から読み飛ばす件数
+	Offset int
+}
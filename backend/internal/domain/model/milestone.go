@@ -0,0 +1,53 @@
+package model
+
+import "time"
+
+// Milestone はプロジェクト内のマイルストーンを表すドメインモデル
+type Milestone struct {
+	ID          string     `json:"id"`
+	ProjectID   string     `json:"project_id"`
+	Title       string     `json:"title"`
+	Description string     `json:"description"`
+	DueDate     *time.Time `json:"due_date,omitempty"`
+	// GithubMilestoneNumber は同期先のGitHubリポジトリのマイルストーン番号
+	GithubMilestoneNumber *int      `json:"github_milestone_number,omitempty"`
+	CreatedAt             time.Time `json:"created_at"`
+	UpdatedAt             time.Time `json:"updated_at"`
+}
+
+// MilestoneProgress はマイルストーンの進捗を表す
+type MilestoneProgress struct {
+	TaskCount int `json:"task_count"`
+	DoneCount int `json:"done_count"`
+	// Percent は完了率（0〜100、タスクがない場合は0）
+	Percent float64 `json:"percent"`
+}
+
+// NewMilestoneProgress はタスク数と完了数から進捗を作成する
+func NewMilestoneProgress(taskCount, doneCount int) MilestoneProgress {
+	p := MilestoneProgress{TaskCount: taskCount, DoneCount: doneCount}
+	if taskCount > 0 {
+		p.Percent = float64(doneCount) * 100 / float64(taskCount)
+	}
+	return p
+}
+
+// MilestoneWithProgress は進捗付きのマイルストーンを表す
+type MilestoneWithProgress struct {
+	*Milestone
+	Progress MilestoneProgress `json:"progress"`
+}
+
+// CreateMilestoneRequest はマイルストーン作成リクエストを表す
+type CreateMilestoneRequest struct {
+	Title       string     `json:"title" validate:"required,max=255"`
+	Description string     `json:"description" validate:"max=10000"`
+	DueDate     *time.Time `json:"due_date,omitempty"`
+}
+
+// UpdateMilestoneRequest はマイルストーン更新リクエストを表す
+type UpdateMilestoneRequest struct {
+	Title       string     `json:"title" validate:"required,max=255"`
+	Description string     `json:"description" validate:"max=10000"`
+	DueDate     *time.Time `json:"due_date,omitempty"`
+}
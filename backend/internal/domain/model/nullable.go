@@ -0,0 +1,28 @@
+package model
+
+import "encoding/json"
+
+// Nullable はJSONの「未指定」「null」「値あり」を区別するための型
+// 部分更新（PATCH）で値を明示的にクリアする場合に使用する
+type Nullable[T any] struct {
+	// Set はフィールドがJSONに含まれていたかどうか
+	Set bool
+	// Value は値（nullが指定された場合はnil）
+	Value *T
+}
+
+// UnmarshalJSON はフィールドが存在する場合にのみ呼ばれ、Setをtrueにする
+func (n *Nullable[T]) UnmarshalJSON(data []byte) error {
+	n.Set = true
+	if string(data) == "null" {
+		n.Value = nil
+		return nil
+	}
+
+	var v T
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	n.Value = &v
+	return nil
+}
@@ -0,0 +1,77 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// ドメインイベントの種類（<集約>.<出来事>）
+const (
+	EventTaskCreated       = "task.created"
+	EventTaskUpdated       = "task.updated"
+	EventTaskStatusChanged = "task.status_changed"
+	EventTaskDeleted       = "task.deleted"
+	EventTaskRestored      = "task.restored"
+	EventTaskReminder      = "task.reminder"
+	EventProjectCreated    = "project.created"
+	EventProjectUpdated    = "project.updated"
+	EventProjectDeleted    = "project.deleted"
+	EventProjectRestored   = "project.restored"
+	EventProjectLinked     = "project.linked"
+	EventProjectUnlinked   = "project.unlinked"
+)
+
+// ドメインイベントの集約の種類
+const (
+	AggregateTask    = "task"
+	AggregateProject = "project"
+)
+
+// OutboxEventStatus はアウトボックスのイベントの配信状況を表す
+type OutboxEventStatus string
+
+const (
+	// OutboxEventPending は配信待ち（再試行待ちを含む）
+	OutboxEventPending OutboxEventStatus = "pending"
+	// OutboxEventPublished は配信済み
+	OutboxEventPublished OutboxEventStatus = "published"
+	// OutboxEventDead は再試行の上限に達して配信を諦めたもの（デッドレター）
+	OutboxEventDead OutboxEventStatus = "dead"
+)
+
+// OutboxEvent は変更と同じトランザクションでアウトボックスに書き込んだドメインイベントを表す
+// コミットされた変更のイベントだけが配信され、配信先はIDで重複を除けるようにする
+type OutboxEvent struct {
+	ID string `json:"id"`
+	// Sequence はイベントを書き込んだ順の通し番号
+	Sequence      int64             `json:"sequence"`
+	Type          string            `json:"type"`
+	AggregateType string            `json:"aggregate_type"`
+	AggregateID   string            `json:"aggregate_id"`
+	Payload       json.RawMessage   `json:"payload"`
+	Status        OutboxEventStatus `json:"status"`
+	Attempts      int               `json:"attempts"`
+	LastError     *string           `json:"last_error,omitempty"`
+	NextAttemptAt time.Time         `json:"next_attempt_at"`
+	OccurredAt    time.Time         `json:"occurred_at"`
+	PublishedAt   *time.Time        `json:"published_at,omitempty"`
+}
+
+// TaskStatusChangedPayload はtask.status_changedのペイロード
+type TaskStatusChangedPayload struct {
+	Task       *Task       `json:"task"`
+	FromStatus *TaskStatus `json:"from_status"`
+	Reopened   bool        `json:"reopened"`
+}
+
+// TaskDeletedPayload はtask.deletedのペイロード
+type TaskDeletedPayload struct {
+	ID        string `json:"id"`
+	ProjectID string `json:"project_id"`
+}
+
+// ProjectDeletedPayload はproject.deletedのペイロード（プロジェクトのタスクもゴミ箱に移るが、タスクごとのイベントは発生しない）
+type ProjectDeletedPayload struct {
+	ID     string `json:"id"`
+	UserID string `json:"user_id"`
+}
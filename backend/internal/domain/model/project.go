@@ -1,21 +1,221 @@
 package model
 
-import "time"
+import (
+	"fmt"
+	"time"
+)
+
+// ErrProjectHasTasks はタスクのあるプロジェクトをforceを指定せずに削除しようとした場合のエラー
+var ErrProjectHasTasks = fmt.Errorf("project has tasks, deleting requires force: %w", ErrConflict)
 
 // Project はプロジェクトを表すドメインモデル
 type Project struct {
-	ID                  string    `json:"id"`
-	UserID              string    `json:"user_id"`
-	Title               string    `json:"title"`
-	Description         string    `json:"description"`
-	GithubOwner         *string   `json:"github_owner,omitempty"`
-	GithubRepo          *string   `json:"github_repo,omitempty"`
-	GithubProjectNumber *int      `json:"github_project_number,omitempty"`
-	CreatedAt           time.Time `json:"created_at"`
-	UpdatedAt           time.Time `json:"updated_at"`
+	ID                  string  `json:"id"`
+	UserID              string  `json:"user_id"`
+	Title               string  `json:"title"`
+	Description         string  `json:"description"`
+	GithubOwner         *string `json:"github_owner,omitempty"`
+	GithubRepo          *string `json:"github_repo,omitempty"`
+	GithubProjectNumber *int    `json:"github_project_number,omitempty"`
+	// GithubRepoProject はGitHub Projectをリポジトリに紐づくProjectとして検索するか（falseの場合はユーザーのProject）
+	GithubRepoProject bool `json:"github_repo_project"`
+	// GithubOwnerType は連携時にGitHubで確認した所有者の種類（未確認の場合はnilで、GithubRepoProjectから判断する）
+	GithubOwnerType *GithubOwnerType `json:"github_owner_type,omitempty"`
+	// EstimateUnit はタスクの見積もりの単位
+	EstimateUnit EstimateUnit `json:"estimate_unit"`
+	// GithubEstimateField は見積もりを同期するGitHub Projectsの数値フィールド名（未設定の場合は同期しない）
+	GithubEstimateField *string `json:"github_estimate_field,omitempty"`
+	// GithubCommitStartsTask は未着手のタスクを最初に参照したコミットで進行中にするか
+	GithubCommitStartsTask bool `json:"github_commit_starts_task"`
+	// GithubDeletionPolicy は連携先のGitHubのItem・Issueが削除された時のタスクの扱い
+	GithubDeletionPolicy GithubDeletionPolicy `json:"github_deletion_policy"`
+	// GithubItemType はタスクをGitHub Projectに同期する時に追加するItemの種類
+	GithubItemType GithubItemType `json:"github_item_type"`
+	// Version は楽観的排他制御のバージョン（更新のたびに1ずつ増える）
+	Version   int       `json:"version"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	// DeletedAt はゴミ箱に移した日時（ゴミ箱の一覧でのみ返す）
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+}
+
+// GithubItemType は未同期のタスクをGitHub Projectに追加する時のItemの種類を表す
+type GithubItemType string
+
+const (
+	// GithubItemDraft はDraft Issueとして追加する
+	GithubItemDraft GithubItemType = "draft"
+	// GithubItemIssue は連携先のリポジトリにIssueを作成して追加する
+	GithubItemIssue GithubItemType = "issue"
+)
+
+// IsValid は定義済みの種類かどうかを返す
+func (t GithubItemType) IsValid() bool {
+	return t == GithubItemDraft || t == GithubItemIssue
+}
+
+// GithubDeletionPolicy はGitHub側で連携先が削除された時のタスクの扱いを表す
+type GithubDeletionPolicy string
+
+const (
+	// GithubDeletionOrphan はタスクを残して連携切れ（orphaned）にする
+	GithubDeletionOrphan GithubDeletionPolicy = "orphan"
+	// GithubDeletionDelete はタスクを削除する
+	GithubDeletionDelete GithubDeletionPolicy = "delete"
+)
+
+// IsValid は定義済みの扱いかどうかを返す
+func (p GithubDeletionPolicy) IsValid() bool {
+	return p == GithubDeletionOrphan || p == GithubDeletionDelete
+}
+
+// GithubOwnerType はGitHub Projectの所有者の種類を表す
+type GithubOwnerType string
+
+const (
+	// GithubOwnerUser はユーザーのProject
+	GithubOwnerUser GithubOwnerType = "user"
+	// GithubOwnerOrg はOrganizationのProject
+	GithubOwnerOrg GithubOwnerType = "org"
+	// GithubOwnerRepo はリポジトリに紐づくProject
+	GithubOwnerRepo GithubOwnerType = "repo"
+)
+
+// IsValid は定義済みの種類かどうかを返す
+func (t GithubOwnerType) IsValid() bool {
+	return t == GithubOwnerUser || t == GithubOwnerOrg || t == GithubOwnerRepo
+}
+
+// EstimateUnit はタスクの見積もりの単位を表す
+type EstimateUnit string
+
+const (
+	EstimateUnitPoints EstimateUnit = "points"
+	EstimateUnitHours  EstimateUnit = "hours"
+)
+
+// IsValid は定義済みの単位かどうかを返す
+func (u EstimateUnit) IsValid() bool {
+	return u == EstimateUnitPoints || u == EstimateUnitHours
 }
 
 // IsGithubLinked はGitHub連携が設定されているかを返す
 func (p *Project) IsGithubLinked() bool {
 	return p.GithubOwner != nil && p.GithubRepo != nil && p.GithubProjectNumber != nil
 }
+
+// PatchProjectRequest はプロジェクトの部分更新リクエストを表す
+// nilのフィールドは更新せず、GitHub連携フィールドはnullを指定するとクリアされる
+type PatchProjectRequest struct {
+	Title                  *string               `json:"title,omitempty" validate:"omitempty,min=1,max=255"`
+	Description            *string               `json:"description,omitempty" validate:"omitempty,max=10000"`
+	GithubOwner            Nullable[string]      `json:"github_owner"`
+	GithubRepo             Nullable[string]      `json:"github_repo"`
+	GithubProjectNumber    Nullable[int]         `json:"github_project_number"`
+	GithubRepoProject      *bool                 `json:"github_repo_project,omitempty"`
+	EstimateUnit           *EstimateUnit         `json:"estimate_unit,omitempty" validate:"omitempty,oneof=points hours"`
+	GithubEstimateField    Nullable[string]      `json:"github_estimate_field"`
+	GithubCommitStartsTask *bool                 `json:"github_commit_starts_task,omitempty"`
+	GithubDeletionPolicy   *GithubDeletionPolicy `json:"github_deletion_policy,omitempty" validate:"omitempty,oneof=orphan delete"`
+	GithubItemType         *GithubItemType       `json:"github_item_type,omitempty" validate:"omitempty,oneof=draft issue"`
+	// Version は更新元として取得したプロジェクトのバージョン（指定した場合は他の更新と競合するとErrStaleVersionにする）
+	Version *int `json:"version,omitempty"`
+}
+
+// ProjectDeletePreview はプロジェクトを削除した場合に合わせて削除されるタスクを表す
+type ProjectDeletePreview struct {
+	ProjectID string `json:"project_id"`
+	TaskCount int    `json:"task_count"`
+	// GithubLinkedCount はGitHub ProjectsのItemと連携しているタスク数（連携切れは含めない）
+	GithubLinkedCount int                        `json:"github_linked_count"`
+	GithubItems       []*ProjectDeleteGithubItem `json:"github_items"`
+	// RequiresForce は削除にforceの指定が必要か（タスクがある場合）
+	RequiresForce bool `json:"requires_force"`
+}
+
+// ProjectDeleteGithubItem はプロジェクトの削除で連携が失われるGitHub ProjectsのItemを表す
+type ProjectDeleteGithubItem struct {
+	TaskID         string  `json:"task_id"`
+	Title          string  `json:"title"`
+	GithubItemID   string  `json:"github_item_id"`
+	GithubIssueURL *string `json:"github_issue_url,omitempty"`
+}
+
+// NewProjectDeletePreview はプロジェクトのタスクから削除のプレビューを作成する
+func NewProjectDeletePreview(projectID string, tasks []*Task) *ProjectDeletePreview {
+	preview := &ProjectDeletePreview{
+		ProjectID:     projectID,
+		TaskCount:     len(tasks),
+		GithubItems:   []*ProjectDeleteGithubItem{},
+		RequiresForce: len(tasks) > 0,
+	}
+	for _, t := range tasks {
+		if !t.IsGithubItemLinked() {
+			continue
+		}
+		preview.GithubItems = append(preview.GithubItems, &ProjectDeleteGithubItem{
+			TaskID:         t.ID,
+			Title:          t.Title,
+			GithubItemID:   *t.GithubItemID,
+			GithubIssueURL: t.GithubIssueURL,
+		})
+	}
+	preview.GithubLinkedCount = len(preview.GithubItems)
+	return preview
+}
+
+// ProjectExpand はプロジェクト取得時に埋め込む関連リソース
+type ProjectExpand struct {
+	Tasks  bool
+	Stats  bool
+	Github bool
+}
+
+// ProjectStats はプロジェクトのタスク集計を表す
+type ProjectStats struct {
+	TaskCount       int `json:"task_count"`
+	TodoCount       int `json:"todo_count"`
+	InProgressCount int `json:"in_progress_count"`
+	DoneCount       int `json:"done_count"`
+	// OverdueCount は期限切れの未完了タスク数
+	OverdueCount int `json:"overdue_count"`
+	// GithubLinkedCount はGitHub Projectsに同期済みのタスク数
+	GithubLinkedCount int `json:"github_linked_count"`
+	// EstimateTotal は見積もりの合計、EstimateDoneは完了済みタスクの見積もりの合計
+	EstimateTotal float64 `json:"estimate_total"`
+	EstimateDone  float64 `json:"estimate_done"`
+}
+
+// ProjectGithub はプロジェクトのGitHub連携情報を表す
+type ProjectGithub struct {
+	Linked        bool             `json:"linked"`
+	Owner         *string          `json:"owner,omitempty"`
+	OwnerType     *GithubOwnerType `json:"owner_type,omitempty"`
+	Repo          *string          `json:"repo,omitempty"`
+	ProjectNumber *int             `json:"project_number,omitempty"`
+	RepositoryURL string           `json:"repository_url,omitempty"`
+}
+
+// NewProjectGithub はプロジェクトのGitHub連携フィールドから連携情報を作成する
+func NewProjectGithub(p *Project) *ProjectGithub {
+	g := &ProjectGithub{
+		Linked:        p.IsGithubLinked(),
+		Owner:         p.GithubOwner,
+		OwnerType:     p.GithubOwnerType,
+		Repo:          p.GithubRepo,
+		ProjectNumber: p.GithubProjectNumber,
+	}
+	if p.GithubOwner != nil && p.GithubRepo != nil {
+		g.RepositoryURL = "https://github.com/" + *p.GithubOwner + "/" + *p.GithubRepo
+	}
+	return g
+}
+
+// ProjectDetail は関連リソースを埋め込んだプロジェクトを表す
+// 埋め込まれていない関連リソースはJSONに含まれない（Tasksは埋め込み時に0件でも空配列を返すためポインタとする）
+type ProjectDetail struct {
+	*Project
+	Tasks  *[]*Task       `json:"tasks,omitempty"`
+	Stats  *ProjectStats  `json:"stats,omitempty"`
+	Github *ProjectGithub `json:"github,omitempty"`
+}
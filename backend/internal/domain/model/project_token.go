@@ -0,0 +1,62 @@
+package model
+
+import "time"
+
+// ProjectTokenPrefix はプロジェクト単位のAPIトークンの先頭に付ける文字列
+// Authorizationヘッダーのトークンがログインのアクセストークンかを区別するために使う
+const ProjectTokenPrefix = "gtc_pt_"
+
+// ProjectTokenScope はプロジェクト単位のAPIトークンで許可する操作の範囲を表す
+type ProjectTokenScope string
+
+const (
+	// ProjectTokenScopeRead はプロジェクトとタスクの参照のみを許可する
+	ProjectTokenScopeRead ProjectTokenScope = "read"
+	// ProjectTokenScopeWrite は参照に加えてタスクの作成・更新・削除を許可する
+	ProjectTokenScopeWrite ProjectTokenScope = "write"
+)
+
+// IsValid は定義済みの範囲かどうかを返す
+func (s ProjectTokenScope) IsValid() bool {
+	return s == ProjectTokenScopeRead || s == ProjectTokenScopeWrite
+}
+
+// ProjectToken はCIなどの外部の自動化に発行する、1つのプロジェクトに限定したAPIトークンを表す
+// トークンは発行したユーザーとして扱い、対象のプロジェクト以外のリソースには使えない
+type ProjectToken struct {
+	ID        string            `json:"id"`
+	ProjectID string            `json:"project_id"`
+	UserID    string            `json:"user_id"`
+	Name      string            `json:"name"`
+	Scope     ProjectTokenScope `json:"scope"`
+	// TokenHash はトークンのSHA-256ハッシュ（トークン自体は保存しない）
+	TokenHash string `json:"-"`
+	// ExpiresAt は有効期限（nilの場合は無期限）
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// IsExpired はnowの時点で有効期限を過ぎているかを返す
+func (t *ProjectToken) IsExpired(now time.Time) bool {
+	return t.ExpiresAt != nil && !now.Before(*t.ExpiresAt)
+}
+
+// CanWrite は書き込み（作成・更新・削除）を許可するかを返す
+func (t *ProjectToken) CanWrite() bool {
+	return t.Scope == ProjectTokenScopeWrite
+}
+
+// CreateProjectTokenRequest はプロジェクト単位のAPIトークンの発行リクエストを表す
+type CreateProjectTokenRequest struct {
+	Name  string            `json:"name" validate:"required,max=100"`
+	Scope ProjectTokenScope `json:"scope" validate:"required,oneof=read write"`
+	// ExpiresInDays は有効期間の日数（省略すると無期限）
+	ExpiresInDays *int `json:"expires_in_days,omitempty" validate:"omitempty,min=1,max=365"`
+}
+
+// IssuedProjectToken は発行したトークンを表す（トークン自体は発行時のレスポンスでのみ返す）
+type IssuedProjectToken struct {
+	*ProjectToken
+	Token string `json:"token"`
+}
@@ -0,0 +1,90 @@
+package model
+
+import (
+	"fmt"
+	"time"
+)
+
+// PushPlatform はプッシュ通知の配信サービスを表す
+type PushPlatform string
+
+const (
+	// PushPlatformFCM はFirebase Cloud Messaging（Android・Web Push）
+	PushPlatformFCM PushPlatform = "fcm"
+	// PushPlatformAPNs はApple Push Notification service（iOS）
+	PushPlatformAPNs PushPlatform = "apns"
+)
+
+// PushDevice はプッシュ通知を受け取るためにクライアントが登録した端末を表す
+// 同じトークンを別のユーザーが後から登録した場合は、後から登録したユーザーにだけ通知する
+type PushDevice struct {
+	ID       string       `json:"id"`
+	UserID   string       `json:"-"`
+	Platform PushPlatform `json:"platform"`
+	// Token はFCMの登録トークン・APNsのデバイストークン（通知の送信にだけ使い、レスポンスには含めない）
+	Token string `json:"-"`
+	// Name は端末を一覧で見分けるための名前（例: iPhone 15）
+	Name      *string   `json:"name,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// RegisterPushDeviceRequest は端末の登録リクエストを表す
+// 登録済みのトークンを再登録した場合は名前と登録日時を更新する（アプリの起動ごとに登録し直してよい）
+type RegisterPushDeviceRequest struct {
+	Platform PushPlatform `json:"platform" validate:"required,oneof=fcm apns"`
+	Token    string       `json:"token" validate:"required,max=512"`
+	Name     *string      `json:"name,omitempty" validate:"omitempty,min=1,max=100"`
+}
+
+// PushKind はプッシュ通知の種類を表す（種類ごとに設定で通知を止められる）
+type PushKind string
+
+const (
+	// PushKindStatusChanged はタスクのステータスの変更
+	PushKindStatusChanged PushKind = "task.status_changed"
+	// PushKindReminder はタスクのリマインダー
+	PushKindReminder PushKind = "task.reminder"
+)
+
+// PushNotification は端末に送る通知を表す
+type PushNotification struct {
+	Kind  PushKind
+	Title string
+	Body  string
+	// Data はクライアントが通知を開いた時の遷移先などに使う追加の値（kindは自動で付与する）
+	Data map[string]string
+	// CollapseID は同じ通知を表す識別子（再送で同じ通知が重複して表示されないよう、端末で前の通知を置き換える）
+	CollapseID string
+}
+
+// taskStatusLabels は通知に表示するステータスの名前
+var taskStatusLabels = map[TaskStatus]string{
+	TaskStatusTodo:       "未着手",
+	TaskStatusInProgress: "進行中",
+	TaskStatusDone:       "完了",
+}
+
+// NewTaskStatusPushNotification はタスクのステータスの変更の通知を作成する
+func NewTaskStatusPushNotification(eventID, projectTitle string, task *Task, reopened bool) *PushNotification {
+	label, ok := taskStatusLabels[task.Status]
+	if !ok {
+		label = task.Status.String()
+	}
+	body := fmt.Sprintf("ステータスが「%s」になりました", label)
+	if reopened {
+		body = fmt.Sprintf("タスクが再開され、ステータスが「%s」になりました", label)
+	}
+
+	return &PushNotification{
+		Kind:  PushKindStatusChanged,
+		Title: fmt.Sprintf("[%s] %s", projectTitle, task.Title),
+		Body:  body,
+		Data: map[string]string{
+			"project_id": task.ProjectID,
+			"task_id":    task.ID,
+			"status":     task.Status.String(),
+		},
+		CollapseID: eventID,
+	}
+}
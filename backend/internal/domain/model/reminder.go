@@ -0,0 +1,120 @@
+package model
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+)
+
+// ReminderChannel はリマインダーの通知先を表す
+type ReminderChannel string
+
+const (
+	// ReminderChannelEmail はユーザーのメールアドレスへのメール
+	ReminderChannelEmail ReminderChannel = "email"
+	// ReminderChannelWebhook はプロジェクトのWebhookの送信先（task.reminderイベント）
+	ReminderChannelWebhook ReminderChannel = "webhook"
+	// ReminderChannelPush はユーザーが登録した端末へのプッシュ通知
+	ReminderChannelPush ReminderChannel = "push"
+)
+
+// TaskReminder はタスクのリマインダーを通知先に送信した記録を表す
+// 同じタスク・通知先・終了日のリマインダーは1回だけ送信する（終了日を変更した場合は改めて送信する）
+type TaskReminder struct {
+	TaskID  string
+	UserID  string
+	Channel ReminderChannel
+	// DueDate は送信した時点のタスクの終了日
+	DueDate time.Time
+	SentAt  time.Time
+}
+
+// DueReminder は終了日が近いタスクのリマインダーの内容を表す
+type DueReminder struct {
+	UserID       string
+	ProjectTitle string
+	Task         *Task
+	// DaysLeft は終了日までの日数（ユーザーのタイムゾーンの日付で数え、当日が期限の場合は0）
+	DaysLeft int
+}
+
+// TaskReminderPayload はtask.reminderのペイロード
+type TaskReminderPayload struct {
+	Task     *Task `json:"task"`
+	DaysLeft int   `json:"days_left"`
+}
+
+// NewDueReminder はnowの時点でタスクのリマインダーを通知する場合に内容を返す
+// 終了日がlocでの今日からdaysBefore日後までの未完了のタスクが対象で、期限切れのタスクは対象にしない
+func NewDueReminder(now time.Time, loc *time.Location, daysBefore int, userID, projectTitle string, task *Task) (*DueReminder, bool) {
+	if task.Status == TaskStatusDone || task.EndDate == nil {
+		return nil, false
+	}
+	todayStart := StartOfDay(now, loc)
+	if task.EndDate.Before(todayStart) || !task.EndDate.Before(OverdueReportDueBefore(now, loc, daysBefore)) {
+		return nil, false
+	}
+
+	// 夏時間の切り替えで1日が24時間でない場合があるため、日数は丸めて数える
+	daysLeft := int(math.Round(StartOfDay(*task.EndDate, loc).Sub(todayStart).Hours() / 24))
+	return &DueReminder{
+		UserID:       userID,
+		ProjectTitle: projectTitle,
+		Task:         task,
+		DaysLeft:     max(daysLeft, 0),
+	}, true
+}
+
+// dueLabel は終了日までの日数を表す文言を返す
+func (r *DueReminder) dueLabel() string {
+	switch r.DaysLeft {
+	case 0:
+		return "今日が期限です"
+	case 1:
+		return "明日が期限です"
+	}
+	return fmt.Sprintf("期限まであと%d日です", r.DaysLeft)
+}
+
+// Render はリマインダーのメールの件名と本文をlocの日付で作成する
+func (r *DueReminder) Render(loc *time.Location) (subject, body string) {
+	subject = fmt.Sprintf("[%s] %s: %s", r.ProjectTitle, r.Task.Title, r.dueLabel())
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "タスク「%s」は%s。\n\n", r.Task.Title, r.dueLabel())
+	fmt.Fprintf(&b, "プロジェクト: %s\n", r.ProjectTitle)
+	fmt.Fprintf(&b, "終了日: %s\n", r.Task.EndDate.In(loc).Format("2006/01/02"))
+	label, ok := taskStatusLabels[r.Task.Status]
+	if !ok {
+		label = r.Task.Status.String()
+	}
+	fmt.Fprintf(&b, "ステータス: %s\n", label)
+	if r.Task.GithubIssueURL != nil {
+		fmt.Fprintf(&b, "GitHub: %s\n", *r.Task.GithubIssueURL)
+	}
+	b.WriteString("\nリマインダーは設定（email_reminders・reminder_days_before）で変更できます。\n")
+
+	return subject, b.String()
+}
+
+// PushNotification はリマインダーのプッシュ通知を作成する
+// 同じタスク・終了日のリマインダーは端末で前の通知を置き換える
+func (r *DueReminder) PushNotification() *PushNotification {
+	return &PushNotification{
+		Kind:  PushKindReminder,
+		Title: fmt.Sprintf("[%s] %s", r.ProjectTitle, r.Task.Title),
+		Body:  r.dueLabel(),
+		Data: map[string]string{
+			"project_id": r.Task.ProjectID,
+			"task_id":    r.Task.ID,
+			"days_left":  fmt.Sprint(r.DaysLeft),
+		},
+		CollapseID: "reminder:" + r.Task.ID,
+	}
+}
+
+// Payload はtask.reminderイベントのペイロードを返す
+func (r *DueReminder) Payload() *TaskReminderPayload {
+	return &TaskReminderPayload{Task: r.Task, DaysLeft: r.DaysLeft}
+}
@@ -0,0 +1,523 @@
+package model
+
+import (
+	"cmp"
+	"slices"
+	"time"
+)
+
+// Iteration はレポートの集計期間（Start以上End未満）を表す
+type Iteration struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+}
+
+// Contains は時刻が期間内かどうかを返す
+func (i Iteration) Contains(t time.Time) bool {
+	return !t.Before(i.Start) && t.Before(i.End)
+}
+
+// NewIterations はnowを含む週の開始日から始まる期間を現在の期間として、weeks週ごとの期間をcount個古い順に返す
+// 週の開始日はlocのタイムゾーンでweekStartの曜日の0時とする
+func NewIterations(now time.Time, loc *time.Location, weekStart time.Weekday, weeks, count int) []Iteration {
+	local := now.In(loc)
+	offset := (int(local.Weekday()) - int(weekStart) + 7) % 7
+	currentStart := time.Date(local.Year(), local.Month(), local.Day()-offset, 0, 0, 0, 0, loc)
+
+	iterations := make([]Iteration, count)
+	for i := 0; i < count; i++ {
+		start := currentStart.AddDate(0, 0, -7*weeks*(count-1-i))
+		iterations[i] = Iteration{Start: start, End: start.AddDate(0, 0, 7*weeks)}
+	}
+	return iterations
+}
+
+// VelocityIteration は1期間のベロシティを表す
+type VelocityIteration struct {
+	Iteration
+	CompletedTasks  int     `json:"completed_tasks"`
+	CompletedPoints float64 `json:"completed_points"`
+	// InProgress は現在進行中の期間かどうか（平均・中央値の計算から除外する）
+	InProgress bool `json:"in_progress"`
+}
+
+// VelocityReport は期間ごとの完了タスク数・見積もりの推移を表す
+type VelocityReport struct {
+	ProjectID      string              `json:"project_id"`
+	EstimateUnit   EstimateUnit        `json:"estimate_unit"`
+	IterationWeeks int                 `json:"iteration_weeks"`
+	Iterations     []VelocityIteration `json:"iterations"`
+	MeanTasks      float64             `json:"mean_tasks"`
+	MedianTasks    float64             `json:"median_tasks"`
+	MeanPoints     float64             `json:"mean_points"`
+	MedianPoints   float64             `json:"median_points"`
+}
+
+// NewVelocityReport はステータス遷移イベントから期間ごとのベロシティを集計する
+// 現在完了しているタスクのみを対象とし、最後に完了へ遷移した日時の期間に計上する
+func NewVelocityReport(project *Project, weeks int, iterations []Iteration, now time.Time, doneTasks []*Task, events []*TaskStatusEvent) *VelocityReport {
+	completedAt := make(map[string]time.Time, len(doneTasks))
+	for _, e := range events {
+		if e.ToStatus == TaskStatusDone {
+			completedAt[e.TaskID] = e.ChangedAt
+		}
+	}
+
+	report := &VelocityReport{
+		ProjectID:      project.ID,
+		EstimateUnit:   project.EstimateUnit,
+		IterationWeeks: weeks,
+		Iterations:     make([]VelocityIteration, len(iterations)),
+	}
+	for i, it := range iterations {
+		report.Iterations[i] = VelocityIteration{Iteration: it, InProgress: it.Contains(now)}
+	}
+
+	for _, t := range doneTasks {
+		at, ok := completedAt[t.ID]
+		if !ok {
+			continue
+		}
+		for i := range report.Iterations {
+			if report.Iterations[i].Contains(at) {
+				report.Iterations[i].CompletedTasks++
+				if t.Estimate != nil {
+					report.Iterations[i].CompletedPoints += *t.Estimate
+				}
+				break
+			}
+		}
+	}
+
+	var tasks, points []float64
+	for _, it := range report.Iterations {
+		if it.InProgress {
+			continue
+		}
+		tasks = append(tasks, float64(it.CompletedTasks))
+		points = append(points, it.CompletedPoints)
+	}
+	report.MeanTasks, report.MedianTasks = meanMedian(tasks)
+	report.MeanPoints, report.MedianPoints = meanMedian(points)
+
+	return report
+}
+
+// meanMedian は平均値と中央値を返す（空の場合は0）
+func meanMedian(values []float64) (float64, float64) {
+	if len(values) == 0 {
+		return 0, 0
+	}
+
+	var sum float64
+	for _, v := range values {
+		sum += v
+	}
+
+	sorted := slices.Clone(values)
+	slices.Sort(sorted)
+	mid := len(sorted) / 2
+	median := sorted[mid]
+	if len(sorted)%2 == 0 {
+		median = (sorted[mid-1] + sorted[mid]) / 2
+	}
+
+	return sum / float64(len(values)), median
+}
+
+// CumulativeFlowPoint は1日の終わり時点のステータスごとのタスク数を表す
+type CumulativeFlowPoint struct {
+	// Date は集計日（YYYY-MM-DD）
+	Date            string `json:"date"`
+	TodoCount       int    `json:"todo_count"`
+	InProgressCount int    `json:"in_progress_count"`
+	DoneCount       int    `json:"done_count"`
+}
+
+// CumulativeFlow は累積フロー図（CFD）のデータを表す
+type CumulativeFlow struct {
+	ProjectID string                `json:"project_id"`
+	Timezone  string                `json:"timezone"`
+	Points    []CumulativeFlowPoint `json:"points"`
+}
+
+// NewCumulativeFlow はステータス遷移イベントを再生して、todayまでのdays日分の日ごとのステータス別タスク数を集計する
+// eventsは発生順であること。イベントが記録されていないタスクは作成日時から現在のステータスだったものとみなす
+func NewCumulativeFlow(projectID string, loc *time.Location, today time.Time, days int, tasks []*Task, events []*TaskStatusEvent) *CumulativeFlow {
+	hasEvent := make(map[string]bool, len(tasks))
+	for _, e := range events {
+		hasEvent[e.TaskID] = true
+	}
+	replay := make([]*TaskStatusEvent, 0, len(events)+len(tasks))
+	for _, t := range tasks {
+		if !hasEvent[t.ID] {
+			replay = append(replay, &TaskStatusEvent{TaskID: t.ID, ToStatus: t.Status, ChangedAt: t.CreatedAt})
+		}
+	}
+	replay = append(replay, events...)
+	slices.SortStableFunc(replay, func(a, b *TaskStatusEvent) int {
+		return a.ChangedAt.Compare(b.ChangedAt)
+	})
+
+	local := today.In(loc)
+	first := time.Date(local.Year(), local.Month(), local.Day()-(days-1), 0, 0, 0, 0, loc)
+
+	cfd := &CumulativeFlow{
+		ProjectID: projectID,
+		Timezone:  loc.String(),
+		Points:    make([]CumulativeFlowPoint, 0, days),
+	}
+	statuses := make(map[string]TaskStatus, len(tasks))
+	next := 0
+	for i := 0; i < days; i++ {
+		day := first.AddDate(0, 0, i)
+		end := day.AddDate(0, 0, 1)
+		for next < len(replay) && replay[next].ChangedAt.Before(end) {
+			statuses[replay[next].TaskID] = replay[next].ToStatus
+			next++
+		}
+
+		point := CumulativeFlowPoint{Date: day.Format(time.DateOnly)}
+		for _, s := range statuses {
+			switch s {
+			case TaskStatusTodo:
+				point.TodoCount++
+			case TaskStatusInProgress:
+				point.InProgressCount++
+			case TaskStatusDone:
+				point.DoneCount++
+			}
+		}
+		cfd.Points = append(cfd.Points, point)
+	}
+
+	return cfd
+}
+
+// OverdueProjectGroup はプロジェクトごとの期限切れ・期限間近のタスクを表す
+type OverdueProjectGroup struct {
+	ProjectID    string  `json:"project_id"`
+	ProjectTitle string  `json:"project_title"`
+	Overdue      []*Task `json:"overdue"`
+	DueSoon      []*Task `json:"due_soon"`
+}
+
+// OverdueReport はユーザーの全プロジェクトの期限切れ・期限間近のタスクを表す
+type OverdueReport struct {
+	GeneratedAt  time.Time              `json:"generated_at"`
+	Timezone     string                 `json:"timezone"`
+	DueSoonDays  int                    `json:"due_soon_days"`
+	OverdueCount int                    `json:"overdue_count"`
+	DueSoonCount int                    `json:"due_soon_count"`
+	Projects     []*OverdueProjectGroup `json:"projects"`
+}
+
+// NewOverdueReport は未完了タスクを終了日がlocでの今日より前（期限切れ）と今日からdueSoonDays日後まで（期限間近、今日が期限のものを含む）に分類し、プロジェクトごとにまとめる
+// 該当タスクのないプロジェクトは含めず、プロジェクトの順序はprojectsの順に従う
+func NewOverdueReport(now time.Time, loc *time.Location, dueSoonDays int, projects []*Project, tasks []*Task) *OverdueReport {
+	report := &OverdueReport{
+		GeneratedAt: now,
+		Timezone:    loc.String(),
+		DueSoonDays: dueSoonDays,
+		Projects:    []*OverdueProjectGroup{},
+	}
+	todayStart := StartOfDay(now, loc)
+	dueSoonBefore := OverdueReportDueBefore(now, loc, dueSoonDays)
+
+	groups := make(map[string]*OverdueProjectGroup, len(projects))
+	for _, t := range tasks {
+		if t.Status == TaskStatusDone || t.EndDate == nil || !t.EndDate.Before(dueSoonBefore) {
+			continue
+		}
+		g, ok := groups[t.ProjectID]
+		if !ok {
+			g = &OverdueProjectGroup{ProjectID: t.ProjectID, Overdue: []*Task{}, DueSoon: []*Task{}}
+			groups[t.ProjectID] = g
+		}
+		if t.EndDate.Before(todayStart) {
+			g.Overdue = append(g.Overdue, t)
+			report.OverdueCount++
+		} else {
+			g.DueSoon = append(g.DueSoon, t)
+			report.DueSoonCount++
+		}
+	}
+
+	for _, p := range projects {
+		if g, ok := groups[p.ID]; ok {
+			g.ProjectTitle = p.Title
+			report.Projects = append(report.Projects, g)
+		}
+	}
+
+	return report
+}
+
+// OverdueReportDueBefore は期限切れ・期限間近のレポートに含める終了日の上限（locでの今日からdueSoonDays日後の翌日0時）を返す
+func OverdueReportDueBefore(now time.Time, loc *time.Location, dueSoonDays int) time.Time {
+	return StartOfDay(now, loc).AddDate(0, 0, dueSoonDays+1)
+}
+
+// TaskTimeInStatus は1タスクが各ステータスで過ごした時間を表す
+type TaskTimeInStatus struct {
+	TaskID string     `json:"task_id"`
+	Title  string     `json:"title"`
+	Status TaskStatus `json:"status"`
+	// Hours はステータス名ごとの滞留時間の合計（完了後の時間は含めない）
+	Hours map[string]float64 `json:"hours"`
+	// CurrentHours は現在のステータスになってからの経過時間
+	CurrentHours float64 `json:"current_hours"`
+	// Stuck は進行中のまま閾値を超えて滞留しているかどうか
+	Stuck bool `json:"stuck"`
+}
+
+// StatusPercentiles はステータスごとの滞留時間のパーセンタイルを表す
+type StatusPercentiles struct {
+	Status string  `json:"status"`
+	Count  int     `json:"count"`
+	P50    float64 `json:"p50_hours"`
+	P75    float64 `json:"p75_hours"`
+	P90    float64 `json:"p90_hours"`
+}
+
+// TimeInStatusReport はプロジェクトのタスクのステータスごとの滞留時間を表す
+type TimeInStatusReport struct {
+	ProjectID         string              `json:"project_id"`
+	GeneratedAt       time.Time           `json:"generated_at"`
+	StuckAfterHours   float64             `json:"stuck_after_hours"`
+	Percentiles       []StatusPercentiles `json:"percentiles"`
+	Tasks             []*TaskTimeInStatus `json:"tasks"`
+	StuckTasks        []*TaskTimeInStatus `json:"stuck_tasks"`
+	StuckTaskCount    int                 `json:"stuck_task_count"`
+	MeasuredTaskCount int                 `json:"measured_task_count"`
+}
+
+// NewTimeInStatusReport はステータス遷移イベントを再生して、タスクごとのステータス別の滞留時間とプロジェクト全体のパーセンタイルを集計する
+// eventsは発生順であること。作成時のイベントがないタスクは作成日時から最初の遷移元（イベントがなければ現在）のステータスだったものとみなす
+// 進行中のままstuckAfter以上経過したタスクを滞留タスクとして滞留時間の長い順に並べる
+func NewTimeInStatusReport(projectID string, now time.Time, stuckAfter time.Duration, tasks []*Task, events []*TaskStatusEvent) *TimeInStatusReport {
+	byTask := make(map[string][]*TaskStatusEvent, len(tasks))
+	for _, e := range events {
+		byTask[e.TaskID] = append(byTask[e.TaskID], e)
+	}
+
+	report := &TimeInStatusReport{
+		ProjectID:       projectID,
+		GeneratedAt:     now,
+		StuckAfterHours: stuckAfter.Hours(),
+		Percentiles:     []StatusPercentiles{},
+		Tasks:           make([]*TaskTimeInStatus, 0, len(tasks)),
+		StuckTasks:      []*TaskTimeInStatus{},
+	}
+	samples := make(map[TaskStatus][]float64)
+
+	for _, t := range tasks {
+		taskEvents := byTask[t.ID]
+		current, since := t.Status, t.CreatedAt
+		if len(taskEvents) > 0 {
+			if from := taskEvents[0].FromStatus; from != nil {
+				current = *from
+			} else {
+				current = taskEvents[0].ToStatus
+			}
+		}
+
+		durations := make(map[TaskStatus]time.Duration)
+		for _, e := range taskEvents {
+			if current != TaskStatusDone && e.ChangedAt.After(since) {
+				durations[current] += e.ChangedAt.Sub(since)
+			}
+			current, since = e.ToStatus, e.ChangedAt
+		}
+		if current != TaskStatusDone && now.After(since) {
+			durations[current] += now.Sub(since)
+		}
+
+		item := &TaskTimeInStatus{
+			TaskID:       t.ID,
+			Title:        t.Title,
+			Status:       t.Status,
+			Hours:        make(map[string]float64, len(durations)),
+			CurrentHours: now.Sub(since).Hours(),
+		}
+		for s, d := range durations {
+			item.Hours[s.String()] = d.Hours()
+			samples[s] = append(samples[s], d.Hours())
+		}
+		if t.Status == TaskStatusInProgress && now.Sub(since) >= stuckAfter {
+			item.Stuck = true
+			report.StuckTasks = append(report.StuckTasks, item)
+		}
+		if len(durations) > 0 {
+			report.MeasuredTaskCount++
+		}
+		report.Tasks = append(report.Tasks, item)
+	}
+
+	for _, s := range []TaskStatus{TaskStatusTodo, TaskStatusInProgress} {
+		values := samples[s]
+		slices.Sort(values)
+		report.Percentiles = append(report.Percentiles, StatusPercentiles{
+			Status: s.String(),
+			Count:  len(values),
+			P50:    percentile(values, 50),
+			P75:    percentile(values, 75),
+			P90:    percentile(values, 90),
+		})
+	}
+
+	slices.SortFunc(report.StuckTasks, func(a, b *TaskTimeInStatus) int {
+		return cmp.Compare(b.CurrentHours, a.CurrentHours)
+	})
+	report.StuckTaskCount = len(report.StuckTasks)
+
+	return report
+}
+
+// percentile はソート済みの値のpパーセンタイルを線形補間で返す（空の場合は0）
+func percentile(sorted []float64, p float64) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := p / 100 * float64(len(sorted)-1)
+	lower := int(rank)
+	if lower+1 >= len(sorted) {
+		return sorted[len(sorted)-1]
+	}
+	return sorted[lower] + (sorted[lower+1]-sorted[lower])*(rank-float64(lower))
+}
+
+// durationBucketBound は分布の区間のラベルと上限（時間、0は上限なし）
+type durationBucketBound struct {
+	label    string
+	maxHours float64
+}
+
+// flowTimeBuckets はリードタイム・サイクルタイムの分布の区切り
+var flowTimeBuckets = []durationBucketBound{
+	{"<1d", 24},
+	{"1-3d", 72},
+	{"3-7d", 168},
+	{"7-14d", 336},
+	{"14-30d", 720},
+	{"30d+", 0},
+}
+
+// DurationBucket は分布の1区間の件数を表す
+type DurationBucket struct {
+	Label string `json:"label"`
+	Count int    `json:"count"`
+}
+
+// DurationDistribution は所要時間の分布を表す
+type DurationDistribution struct {
+	Count     int              `json:"count"`
+	MeanHours float64          `json:"mean_hours"`
+	P50Hours  float64          `json:"p50_hours"`
+	P75Hours  float64          `json:"p75_hours"`
+	P90Hours  float64          `json:"p90_hours"`
+	MaxHours  float64          `json:"max_hours"`
+	Buckets   []DurationBucket `json:"buckets"`
+}
+
+// newDurationDistribution は所要時間（時間）の一覧から分布を作成する
+func newDurationDistribution(hours []float64) DurationDistribution {
+	sorted := slices.Clone(hours)
+	slices.Sort(sorted)
+
+	d := DurationDistribution{
+		Count:    len(sorted),
+		P50Hours: percentile(sorted, 50),
+		P75Hours: percentile(sorted, 75),
+		P90Hours: percentile(sorted, 90),
+		Buckets:  make([]DurationBucket, len(flowTimeBuckets)),
+	}
+	d.MeanHours, _ = meanMedian(sorted)
+	if len(sorted) > 0 {
+		d.MaxHours = sorted[len(sorted)-1]
+	}
+	for i, b := range flowTimeBuckets {
+		d.Buckets[i].Label = b.label
+	}
+	for _, h := range sorted {
+		i := slices.IndexFunc(flowTimeBuckets, func(b durationBucketBound) bool {
+			return b.maxHours == 0 || h < b.maxHours
+		})
+		d.Buckets[i].Count++
+	}
+	return d
+}
+
+// TaskFlowTime は完了したタスクのリードタイムとサイクルタイムを表す
+type TaskFlowTime struct {
+	TaskID      string    `json:"task_id"`
+	Title       string    `json:"title"`
+	CompletedAt time.Time `json:"completed_at"`
+	// LeadHours は作成から完了までの時間
+	LeadHours float64 `json:"lead_hours"`
+	// CycleHours は最初に進行中になってから完了までの時間（進行中を経ずに完了した場合はnil）
+	CycleHours *float64 `json:"cycle_hours,omitempty"`
+}
+
+// FlowTimeReport はプロジェクトのリードタイム・サイクルタイムの分布を表す
+type FlowTimeReport struct {
+	ProjectID string               `json:"project_id"`
+	From      time.Time            `json:"from"`
+	To        time.Time            `json:"to"`
+	LeadTime  DurationDistribution `json:"lead_time"`
+	CycleTime DurationDistribution `json:"cycle_time"`
+	Tasks     []*TaskFlowTime      `json:"tasks"`
+}
+
+// NewFlowTimeReport はfromからnowまでに完了したタスクのリードタイム（作成→完了）とサイクルタイム（開始→完了）を集計する
+// eventsは発生順であること。現在完了しているタスクのみを対象とし、最後に完了へ遷移した日時を完了日時とする
+// 開始日時は完了より前に最初に進行中へ遷移した日時とする
+func NewFlowTimeReport(projectID string, from, now time.Time, doneTasks []*Task, events []*TaskStatusEvent) *FlowTimeReport {
+	completedAt := make(map[string]time.Time, len(doneTasks))
+	startedAt := make(map[string]time.Time, len(doneTasks))
+	for _, e := range events {
+		switch e.ToStatus {
+		case TaskStatusDone:
+			completedAt[e.TaskID] = e.ChangedAt
+		case TaskStatusInProgress:
+			if _, ok := startedAt[e.TaskID]; !ok {
+				startedAt[e.TaskID] = e.ChangedAt
+			}
+		}
+	}
+
+	report := &FlowTimeReport{
+		ProjectID: projectID,
+		From:      from,
+		To:        now,
+		Tasks:     []*TaskFlowTime{},
+	}
+	var leads, cycles []float64
+	for _, t := range doneTasks {
+		done, ok := completedAt[t.ID]
+		if !ok || done.Before(from) || done.After(now) {
+			continue
+		}
+		item := &TaskFlowTime{
+			TaskID:      t.ID,
+			Title:       t.Title,
+			CompletedAt: done,
+			LeadHours:   done.Sub(t.CreatedAt).Hours(),
+		}
+		leads = append(leads, item.LeadHours)
+		if started, ok := startedAt[t.ID]; ok && started.Before(done) {
+			cycle := done.Sub(started).Hours()
+			item.CycleHours = &cycle
+			cycles = append(cycles, cycle)
+		}
+		report.Tasks = append(report.Tasks, item)
+	}
+
+	slices.SortFunc(report.Tasks, func(a, b *TaskFlowTime) int {
+		return a.CompletedAt.Compare(b.CompletedAt)
+	})
+	report.LeadTime = newDurationDistribution(leads)
+	report.CycleTime = newDurationDistribution(cycles)
+
+	return report
+}
@@ -0,0 +1,63 @@
+package model
+
+import (
+	"fmt"
+	"time"
+	"unicode/utf8"
+)
+
+// TaskFilter はタスク一覧の絞り込み条件を表す
+// 各条件はAND、Statuses・Prioritiesの要素はORで結合する（空の条件は絞り込まない）
+type TaskFilter struct {
+	Statuses    []TaskStatus   `json:"statuses,omitempty"`
+	Priorities  []TaskPriority `json:"priorities,omitempty"`
+	MilestoneID *string        `json:"milestone_id,omitempty" validate:"omitempty,uuid"`
+	// Overdue は未完了かつ終了日を過ぎたタスクのみに絞り込む
+	Overdue bool `json:"overdue,omitempty"`
+	// DueBefore は終了日がこの日時より前のタスクのみに絞り込む（終了日のないタスクは除く）
+	DueBefore *time.Time `json:"due_before,omitempty"`
+	// DueAfter は終了日がこの日時以降のタスクのみに絞り込む（終了日のないタスクは除く）
+	DueAfter *time.Time `json:"due_after,omitempty"`
+	// Query はタイトルか説明に含まれる文字列（大文字・小文字は区別しない）
+	Query string `json:"query,omitempty" validate:"max=200"`
+}
+
+// Validate は絞り込み条件のステータス・優先度が定義済みの値であり、終了日の範囲が正しいことを検証する
+func (f TaskFilter) Validate() error {
+	for _, s := range f.Statuses {
+		if !s.IsValid() {
+			return fmt.Errorf("unknown task status %d: %w", s, ErrInvalidInput)
+		}
+	}
+	for _, p := range f.Priorities {
+		if !p.IsValid() {
+			return fmt.Errorf("unknown task priority %d: %w", p, ErrInvalidInput)
+		}
+	}
+	if f.DueBefore != nil && f.DueAfter != nil && f.DueAfter.After(*f.DueBefore) {
+		return fmt.Errorf("due_after must not be after due_before: %w", ErrInvalidInput)
+	}
+	if utf8.RuneCountInString(f.Query) > 200 {
+		return fmt.Errorf("query must be at most 200 characters: %w", ErrInvalidInput)
+	}
+	return nil
+}
+
+// SavedView はプロジェクトごとにユーザーが保存したタスクの絞り込み・並び順を表すドメインモデル
+type SavedView struct {
+	ID        string     `json:"id"`
+	ProjectID string     `json:"project_id"`
+	UserID    string     `json:"user_id"`
+	Name      string     `json:"name"`
+	Filter    TaskFilter `json:"filter"`
+	Sort      []SortKey  `json:"sort"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+}
+
+// CreateSavedViewRequest はビュー保存リクエストを表す
+type CreateSavedViewRequest struct {
+	Name   string     `json:"name" validate:"required,max=100"`
+	Filter TaskFilter `json:"filter"`
+	Sort   []SortKey  `json:"sort" validate:"max=5,dive"`
+}
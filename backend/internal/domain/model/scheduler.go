@@ -0,0 +1,20 @@
+package model
+
+import "time"
+
+// ScheduledJob はスケジューラーに登録したジョブの実行状況
+type ScheduledJob struct {
+	Name string `json:"name"`
+	// Schedule はcron式（@every <間隔>等を含む）
+	Schedule string `json:"schedule"`
+	// Timezone はcron式の時刻を解釈するタイムゾーン
+	Timezone       string     `json:"timezone"`
+	Running        bool       `json:"running"`
+	LastRunAt      *time.Time `json:"last_run_at,omitempty"`
+	LastDurationMS *int64     `json:"last_duration_ms,omitempty"`
+	// LastError は直近の実行が失敗した場合のエラー
+	LastError *string `json:"last_error,omitempty"`
+	// LastSkippedAt は他のインスタンスが実行中だったため実行を見送った直近の時刻
+	LastSkippedAt *time.Time `json:"last_skipped_at,omitempty"`
+	NextRunAt     *time.Time `json:"next_run_at,omitempty"`
+}
@@ -0,0 +1,73 @@
+package model
+
+import "fmt"
+
+// SeedEmailDomain は開発用に生成したユーザーに割り当てるメールアドレスのドメイン（配送されない予約済みドメイン）
+const SeedEmailDomain = "seed.invalid"
+
+// 開発用データの生成量の上限（誤って巨大な量を生成しないため）
+const (
+	MaxSeedUsers           = 1000
+	MaxSeedProjectsPerUser = 50
+	MaxSeedTasksPerProject = 5000
+	MaxSeedHistoryDays     = 730
+)
+
+// SeedOptions は開発用データの生成量（0の項目はデフォルト値を使う）
+type SeedOptions struct {
+	// Users は生成するユーザー数（UserIDを指定した場合は無視する）
+	Users int `json:"users" validate:"min=0"`
+	// ProjectsPerUser はユーザーごとのプロジェクト数
+	ProjectsPerUser int `json:"projects_per_user" validate:"min=0"`
+	// TasksPerProject はプロジェクトごとのタスク数
+	TasksPerProject int `json:"tasks_per_project" validate:"min=0"`
+	// HistoryDays はタスクの作成日・ステータス履歴をさかのぼる日数
+	HistoryDays int `json:"history_days" validate:"min=0"`
+	// RandomSeed は乱数のシード（同じ値で同じ内容を生成する、0の場合は毎回異なる）
+	RandomSeed uint64 `json:"random_seed"`
+	// UserID を指定すると新しいユーザーを作らずにこのユーザーのプロジェクトとして生成する
+	UserID string `json:"-"`
+}
+
+// WithDefaults は未指定の項目にデフォルト値を設定したオプションを返す
+func (o SeedOptions) WithDefaults() SeedOptions {
+	if o.Users == 0 {
+		o.Users = 3
+	}
+	if o.ProjectsPerUser == 0 {
+		o.ProjectsPerUser = 2
+	}
+	if o.TasksPerProject == 0 {
+		o.TasksPerProject = 30
+	}
+	if o.HistoryDays == 0 {
+		o.HistoryDays = 60
+	}
+	return o
+}
+
+// Validate は生成量が上限以内であることを検証する
+func (o SeedOptions) Validate() error {
+	switch {
+	case o.Users < 0 || o.Users > MaxSeedUsers:
+		return fmt.Errorf("users must be between 0 and %d: %w", MaxSeedUsers, ErrInvalidInput)
+	case o.ProjectsPerUser < 0 || o.ProjectsPerUser > MaxSeedProjectsPerUser:
+		return fmt.Errorf("projects_per_user must be between 0 and %d: %w", MaxSeedProjectsPerUser, ErrInvalidInput)
+	case o.TasksPerProject < 0 || o.TasksPerProject > MaxSeedTasksPerProject:
+		return fmt.Errorf("tasks_per_project must be between 0 and %d: %w", MaxSeedTasksPerProject, ErrInvalidInput)
+	case o.HistoryDays < 0 || o.HistoryDays > MaxSeedHistoryDays:
+		return fmt.Errorf("history_days must be between 0 and %d: %w", MaxSeedHistoryDays, ErrInvalidInput)
+	}
+	return nil
+}
+
+// SeedResult は生成した開発用データの件数
+type SeedResult struct {
+	UserIDs      []string `json:"user_ids"`
+	Projects     int      `json:"projects"`
+	Milestones   int      `json:"milestones"`
+	Tasks        int      `json:"tasks"`
+	StatusEvents int      `json:"status_events"`
+	PullRequests int      `json:"pull_requests"`
+	Commits      int      `json:"commits"`
+}
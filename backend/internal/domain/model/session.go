@@ -1,6 +1,9 @@
 package model
 
-import "time"
+import (
+	"net/netip"
+	"time"
+)
 
 // Session はセッション情報を表す
 type Session struct {
@@ -10,3 +13,37 @@ type Session struct {
 	Picture   string    `json:"picture"`
 	ExpiresAt time.Time `json:"expires_at"`
 }
+
+// UserSession はログインごとに記録する端末情報と最終アクティビティを表す
+// ログイン中の端末を一覧し、見覚えのないログインに気付けるようにする
+type UserSession struct {
+	ID        string `json:"id"`
+	UserID    string `json:"user_id"`
+	UserAgent string `json:"user_agent"`
+	// IPAddress はIPアドレスのネットワーク部のみ（IPv4は/24、IPv6は/48）
+	IPAddress string `json:"ip_address"`
+	// Location はプロキシ・CDNが付与する国コード（取得できない場合はnil）
+	Location   *string   `json:"location,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+	LastSeenAt time.Time `json:"last_seen_at"`
+	ExpiresAt  time.Time `json:"expires_at"`
+	// Current はリクエスト元のセッションかどうか
+	Current bool `json:"current"`
+}
+
+// RoughIPAddress はIPアドレスをIPv4は/24、IPv6は/48のネットワークに丸める（解析できない場合は空文字）
+func RoughIPAddress(addr string) string {
+	ip, err := netip.ParseAddr(addr)
+	if err != nil {
+		return ""
+	}
+	bits := 48
+	if ip.Unmap().Is4() {
+		ip, bits = ip.Unmap(), 24
+	}
+	prefix, err := ip.Prefix(bits)
+	if err != nil {
+		return ""
+	}
+	return prefix.String()
+}
@@ -0,0 +1,123 @@
+package model
+
+import (
+	"fmt"
+	"time"
+)
+
+// DefaultTimezone は設定が保存されていない場合のタイムゾーン
+const DefaultTimezone = "Asia/Tokyo"
+
+// MaxReminderDaysBefore はリマインダーを終了日の何日前から通知できるかの上限
+const MaxReminderDaysBefore = 14
+
+// Settings はユーザー（ワークスペース）ごとの設定を表すドメインモデル
+// タスク作成時の初期値やリマインダー・GitHub同期の既定値として参照する
+type Settings struct {
+	UserID string `json:"user_id"`
+	// DefaultTaskStatus はステータス未指定でタスクを作成した場合のステータス
+	DefaultTaskStatus TaskStatus `json:"default_task_status"`
+	// WeekStartDay は週の開始曜日（0: 日曜日 〜 6: 土曜日）
+	WeekStartDay time.Weekday `json:"week_start_day"`
+	// Timezone はIANAタイムゾーン名（例: Asia/Tokyo）
+	Timezone string `json:"timezone"`
+	// DefaultGithubOwner はGitHub連携時にownerを省略した場合に使用するowner
+	DefaultGithubOwner *string `json:"default_github_owner,omitempty"`
+	// DefaultLabels はGitHub Issue作成時に付与するラベル
+	DefaultLabels []string `json:"default_labels"`
+	// WeeklyDigest は週次ダイジェストメールの配信を希望するかどうか（オプトイン）
+	WeeklyDigest bool `json:"weekly_digest"`
+	// PushStatusChanges はタスクのステータスの変更をプッシュ通知するかどうか（端末を登録したユーザーにだけ送る）
+	PushStatusChanges bool `json:"push_status_changes"`
+	// PushReminders はタスクのリマインダーをプッシュ通知するかどうか
+	PushReminders bool `json:"push_reminders"`
+	// EmailReminders はタスクのリマインダーをメールで通知するかどうか（オプトイン）
+	EmailReminders bool `json:"email_reminders"`
+	// WebhookReminders はタスクのリマインダーをプロジェクトのWebhookの送信先に通知するかどうか（オプトイン）
+	WebhookReminders bool `json:"webhook_reminders"`
+	// ReminderDaysBefore は終了日の何日前からリマインダーを通知するか（0の場合は終了日の当日）
+	ReminderDaysBefore int `json:"reminder_days_before"`
+	// LastDigestSentAt は最後に週次ダイジェストを配信した日時
+	LastDigestSentAt *time.Time `json:"last_digest_sent_at,omitempty"`
+	// GithubImportProjectID は担当のGitHub Issueをタスクとして取り込むプロジェクト（未設定の場合は取り込まない）
+	GithubImportProjectID *string `json:"github_import_project_id,omitempty"`
+	// LastGithubImportAt は最後に担当のGitHub Issueを取り込んだ日時
+	LastGithubImportAt *time.Time `json:"last_github_import_at,omitempty"`
+	UpdatedAt          time.Time  `json:"updated_at"`
+}
+
+// DefaultSettings は設定が保存されていないユーザーの既定の設定を返す
+func DefaultSettings(userID string) *Settings {
+	return &Settings{
+		UserID:             userID,
+		DefaultTaskStatus:  TaskStatusTodo,
+		WeekStartDay:       time.Monday,
+		Timezone:           DefaultTimezone,
+		DefaultLabels:      []string{},
+		PushStatusChanges:  true,
+		PushReminders:      true,
+		ReminderDaysBefore: 1,
+	}
+}
+
+// Location は設定のタイムゾーンを返す（読み込めない場合はUTC）
+func (s *Settings) Location() *time.Location {
+	loc, err := time.LoadLocation(s.Timezone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// PushEnabled は通知の種類のプッシュ通知を有効にしているかを返す
+func (s *Settings) PushEnabled(kind PushKind) bool {
+	switch kind {
+	case PushKindStatusChanged:
+		return s.PushStatusChanges
+	case PushKindReminder:
+		return s.PushReminders
+	}
+	return false
+}
+
+// StartOfDay はtをlocで見た日付の0時を返す
+// 「今日」「期限切れ」はサーバーではなくユーザーのタイムゾーンの日付の境界で判定する
+func StartOfDay(t time.Time, loc *time.Location) time.Time {
+	local := t.In(loc)
+	return time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, loc)
+}
+
+// Validate は設定値が有効であることを検証する
+func (s *Settings) Validate() error {
+	if !s.DefaultTaskStatus.IsValid() {
+		return fmt.Errorf("invalid default task status %d: %w", int(s.DefaultTaskStatus), ErrInvalidInput)
+	}
+	if s.WeekStartDay < time.Sunday || s.WeekStartDay > time.Saturday {
+		return fmt.Errorf("invalid week start day %d: %w", int(s.WeekStartDay), ErrInvalidInput)
+	}
+	if _, err := time.LoadLocation(s.Timezone); err != nil {
+		return fmt.Errorf("unknown timezone %q: %w", s.Timezone, ErrInvalidInput)
+	}
+	if s.ReminderDaysBefore < 0 || s.ReminderDaysBefore > MaxReminderDaysBefore {
+		return fmt.Errorf("invalid reminder days before %d: %w", s.ReminderDaysBefore, ErrInvalidInput)
+	}
+	return nil
+}
+
+// UpdateSettingsRequest は設定更新リクエストを表す
+type UpdateSettingsRequest struct {
+	DefaultTaskStatus  TaskStatus   `json:"default_task_status"`
+	WeekStartDay       time.Weekday `json:"week_start_day" validate:"min=0,max=6"`
+	Timezone           string       `json:"timezone" validate:"required,max=64"`
+	DefaultGithubOwner *string      `json:"default_github_owner,omitempty" validate:"omitempty,min=1,max=39"`
+	DefaultLabels      []string     `json:"default_labels" validate:"max=20,dive,min=1,max=50"`
+	WeeklyDigest       bool         `json:"weekly_digest"`
+	// PushStatusChanges・PushReminders・リマインダーの設定を省略した場合は現在の設定を変更しない
+	PushStatusChanges  *bool `json:"push_status_changes,omitempty"`
+	PushReminders      *bool `json:"push_reminders,omitempty"`
+	EmailReminders     *bool `json:"email_reminders,omitempty"`
+	WebhookReminders   *bool `json:"webhook_reminders,omitempty"`
+	ReminderDaysBefore *int  `json:"reminder_days_before,omitempty" validate:"omitempty,min=0,max=14"`
+	// GithubImportProjectID を指定すると担当のGitHub Issueを定期的にこのプロジェクトのタスクとして取り込む
+	GithubImportProjectID *string `json:"github_import_project_id,omitempty" validate:"omitempty,uuid"`
+}
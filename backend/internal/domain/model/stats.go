@@ -0,0 +1,104 @@
+package model
+
+import "time"
+
+// DailyCompletion は1日の完了タスク数を表す
+type DailyCompletion struct {
+	// Date は集計日（YYYY-MM-DD）
+	Date      string `json:"date"`
+	Completed int    `json:"completed"`
+}
+
+// WeeklyCompletion は1週間の完了タスク数を表す（集計期間の外にはみ出す日は含めない）
+type WeeklyCompletion struct {
+	Start     time.Time `json:"start"`
+	Completed int       `json:"completed"`
+}
+
+// UserStats はユーザーの生産性の統計を表す
+type UserStats struct {
+	UserID         string    `json:"user_id"`
+	Timezone       string    `json:"timezone"`
+	Days           int       `json:"days"`
+	GeneratedAt    time.Time `json:"generated_at"`
+	TotalCompleted int       `json:"total_completed"`
+	// CurrentStreak は今日（今日の完了がなければ昨日）まで連続して完了がある日数
+	CurrentStreak int `json:"current_streak"`
+	// LongestStreak は集計期間内で最も長く連続して完了があった日数
+	LongestStreak int `json:"longest_streak"`
+	// AverageHoursToComplete は作成から完了までの時間の平均（完了がない場合は0）
+	AverageHoursToComplete float64            `json:"average_hours_to_complete"`
+	MedianHoursToComplete  float64            `json:"median_hours_to_complete"`
+	Daily                  []DailyCompletion  `json:"daily"`
+	Weekly                 []WeeklyCompletion `json:"weekly"`
+}
+
+// NewUserStats は完了へのステータス遷移イベントから、nowを含むdays日分の完了数・連続日数・完了までの時間を集計する
+// 日付と週の区切りはlocのタイムゾーンとweekStartの曜日に従う。再度完了したタスクは完了ごとに数える
+func NewUserStats(userID string, now time.Time, loc *time.Location, weekStart time.Weekday, days int, tasks []*Task, events []*TaskStatusEvent) *UserStats {
+	local := now.In(loc)
+	first := time.Date(local.Year(), local.Month(), local.Day()-(days-1), 0, 0, 0, 0, loc)
+
+	stats := &UserStats{
+		UserID:      userID,
+		Timezone:    loc.String(),
+		Days:        days,
+		GeneratedAt: now,
+		Daily:       make([]DailyCompletion, days),
+		Weekly:      []WeeklyCompletion{},
+	}
+	for i := range stats.Daily {
+		stats.Daily[i].Date = first.AddDate(0, 0, i).Format(time.DateOnly)
+	}
+
+	createdAt := make(map[string]time.Time, len(tasks))
+	for _, t := range tasks {
+		createdAt[t.ID] = t.CreatedAt
+	}
+
+	var hours []float64
+	for _, e := range events {
+		if e.ToStatus != TaskStatusDone || e.ChangedAt.Before(first) || e.ChangedAt.After(now) {
+			continue
+		}
+		changed := e.ChangedAt.In(loc)
+		day := time.Date(changed.Year(), changed.Month(), changed.Day(), 0, 0, 0, 0, loc)
+		index := int(day.Sub(first).Hours()+12) / 24
+		if index < 0 || index >= days {
+			continue
+		}
+		stats.Daily[index].Completed++
+		stats.TotalCompleted++
+		if created, ok := createdAt[e.TaskID]; ok {
+			hours = append(hours, e.ChangedAt.Sub(created).Hours())
+		}
+	}
+	stats.AverageHoursToComplete, stats.MedianHoursToComplete = meanMedian(hours)
+
+	run := 0
+	for i, d := range stats.Daily {
+		day := first.AddDate(0, 0, i)
+		if i == 0 || day.Weekday() == weekStart {
+			stats.Weekly = append(stats.Weekly, WeeklyCompletion{Start: day})
+		}
+		stats.Weekly[len(stats.Weekly)-1].Completed += d.Completed
+
+		if d.Completed > 0 {
+			run++
+			stats.LongestStreak = max(stats.LongestStreak, run)
+		} else {
+			run = 0
+		}
+	}
+
+	// 今日まだ完了がない場合は昨日までの連続日数を現在の連続日数とする
+	end := days - 1
+	if stats.Daily[end].Completed == 0 {
+		end--
+	}
+	for i := end; i >= 0 && stats.Daily[i].Completed > 0; i-- {
+		stats.CurrentStreak++
+	}
+
+	return stats
+}
@@ -0,0 +1,60 @@
+package model
+
+import "time"
+
+// ServiceState はデプロイ全体・依存先の状態を表す
+type ServiceState string
+
+const (
+	ServiceOK ServiceState = "ok"
+	// ServiceDegraded はリクエストは処理できるが、バックグラウンドジョブ等に問題がある状態
+	ServiceDegraded ServiceState = "degraded"
+	// ServiceDown はデータベースに接続できずリクエストを処理できない状態
+	ServiceDown ServiceState = "down"
+)
+
+// WorkerState はバックグラウンドジョブの状態を表す
+type WorkerState string
+
+const (
+	// WorkerStarting は起動後まだ一度も実行していない状態
+	WorkerStarting WorkerState = "starting"
+	WorkerOK       WorkerState = "ok"
+	// WorkerFailing は直近の実行が失敗した状態
+	WorkerFailing WorkerState = "failing"
+	// WorkerStale は実行間隔を大きく過ぎても実行されていない状態
+	WorkerStale WorkerState = "stale"
+)
+
+// ServiceStatus は認証なしで公開するデプロイの稼働状況
+// 内部の詳細（エラーメッセージ・ユーザー・トークン）は含めない
+type ServiceStatus struct {
+	Status        ServiceState      `json:"status"`
+	Version       string            `json:"version"`
+	StartedAt     time.Time         `json:"started_at"`
+	UptimeSeconds int64             `json:"uptime_seconds"`
+	Database      DatabaseStatus    `json:"database"`
+	GithubAPI     GithubQuotaStatus `json:"github_api"`
+	Workers       []WorkerStatus    `json:"workers"`
+	CheckedAt     time.Time         `json:"checked_at"`
+}
+
+// DatabaseStatus はデータベースへの接続の状態
+type DatabaseStatus struct {
+	Status    ServiceState `json:"status"`
+	LatencyMS float64      `json:"latency_ms"`
+}
+
+// GithubQuotaStatus はGitHub GraphQL APIのレート制限の状態（把握しているトークン全体の集計）
+type GithubQuotaStatus struct {
+	TrackedTokens   int        `json:"tracked_tokens"`
+	LowestRemaining *int       `json:"lowest_remaining,omitempty"`
+	ResetAt         *time.Time `json:"reset_at,omitempty"`
+}
+
+// WorkerStatus はバックグラウンドジョブの状態
+type WorkerStatus struct {
+	Name      string      `json:"name"`
+	State     WorkerState `json:"state"`
+	LastRunAt *time.Time  `json:"last_run_at,omitempty"`
+}
@@ -1,8 +1,15 @@
 package model
 
-import "time"
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+)
 
 // TaskStatus はタスクのステータスを表す
+// JSONでは名前（"todo"・"in_progress"・"done"）で表す。移行期間のため数値（0・1・2）の入力も受け付ける
 type TaskStatus int
 
 const (
@@ -11,7 +18,52 @@ const (
 	TaskStatusDone       TaskStatus = 2
 )
 
+// taskStatusNames はステータスと名前の対応
+var taskStatusNames = map[TaskStatus]string{
+	TaskStatusTodo:       "todo",
+	TaskStatusInProgress: "in_progress",
+	TaskStatusDone:       "done",
+}
+
+// IsValid は定義済みのステータスかどうかを返す
+func (s TaskStatus) IsValid() bool {
+	_, ok := taskStatusNames[s]
+	return ok
+}
+
+// String はステータスの名前を返す
+func (s TaskStatus) String() string {
+	if name, ok := taskStatusNames[s]; ok {
+		return name
+	}
+	return fmt.Sprintf("TaskStatus(%d)", int(s))
+}
+
+// ParseTaskStatus は名前からステータスを取得する
+func ParseTaskStatus(name string) (TaskStatus, error) {
+	for s, n := range taskStatusNames {
+		if n == name {
+			return s, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown task status %q: %w", name, ErrInvalidInput)
+}
+
+// MarshalJSON はステータスを名前で出力する（未定義の値は数値のまま出力する）
+func (s TaskStatus) MarshalJSON() ([]byte, error) {
+	if name, ok := taskStatusNames[s]; ok {
+		return json.Marshal(name)
+	}
+	return []byte(strconv.Itoa(int(s))), nil
+}
+
+// UnmarshalJSON は名前または数値（非推奨）のステータスを読み込む
+func (s *TaskStatus) UnmarshalJSON(data []byte) error {
+	return unmarshalEnum(data, s, "task status", ParseTaskStatus)
+}
+
 // TaskPriority はタスクの優先度を表す
+// JSONでは名前（"low"・"medium"・"high"）で表す。移行期間のため数値（0・1・2）の入力も受け付ける
 type TaskPriority int
 
 const (
@@ -20,23 +72,218 @@ const (
 	TaskPriorityHigh   TaskPriority = 2
 )
 
+// DefaultTaskPriority は優先度を指定せずに作成したタスクの優先度
+const DefaultTaskPriority = TaskPriorityMedium
+
+// taskPriorityNames は優先度と名前の対応
+var taskPriorityNames = map[TaskPriority]string{
+	TaskPriorityLow:    "low",
+	TaskPriorityMedium: "medium",
+	TaskPriorityHigh:   "high",
+}
+
+// IsValid は定義済みの優先度かどうかを返す
+func (p TaskPriority) IsValid() bool {
+	return p >= TaskPriorityLow && p <= TaskPriorityHigh
+}
+
+// String は優先度の名前を返す
+func (p TaskPriority) String() string {
+	if name, ok := taskPriorityNames[p]; ok {
+		return name
+	}
+	return fmt.Sprintf("TaskPriority(%d)", int(p))
+}
+
+// ParseTaskPriority は名前から優先度を取得する
+func ParseTaskPriority(name string) (TaskPriority, error) {
+	for p, n := range taskPriorityNames {
+		if n == name {
+			return p, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown task priority %q: %w", name, ErrInvalidInput)
+}
+
+// MarshalJSON は優先度を名前で出力する（未定義の値は数値のまま出力する）
+func (p TaskPriority) MarshalJSON() ([]byte, error) {
+	if name, ok := taskPriorityNames[p]; ok {
+		return json.Marshal(name)
+	}
+	return []byte(strconv.Itoa(int(p))), nil
+}
+
+// UnmarshalJSON は名前または数値（非推奨）の優先度を読み込む
+func (p *TaskPriority) UnmarshalJSON(data []byte) error {
+	return unmarshalEnum(data, p, "task priority", ParseTaskPriority)
+}
+
+// ValidatePriority は優先度が定義済みの値（low・medium・high）であることを検証する
+func ValidatePriority(p TaskPriority) error {
+	if !p.IsValid() {
+		return fmt.Errorf("priority must be one of low, medium, high: %w", ErrInvalidInput)
+	}
+	return nil
+}
+
+// unmarshalEnum は名前の文字列または数値のJSONを列挙型の値としてdstに読み込む（nullの場合は何もしない）
+// 数値は移行期間の互換のために受け付け、範囲の検証は呼び出し元のIsValidに任せる
+func unmarshalEnum[T ~int](data []byte, dst *T, kind string, parse func(string) (T, error)) error {
+	data = bytes.TrimSpace(data)
+	if string(data) == "null" {
+		return nil
+	}
+	if len(data) > 0 && data[0] == '"' {
+		var name string
+		if err := json.Unmarshal(data, &name); err != nil {
+			return fmt.Errorf("invalid %s: %w", kind, ErrInvalidInput)
+		}
+		v, err := parse(name)
+		if err != nil {
+			return err
+		}
+		*dst = v
+		return nil
+	}
+	n, err := strconv.Atoi(string(data))
+	if err != nil {
+		return fmt.Errorf("invalid %s %s: %w", kind, data, ErrInvalidInput)
+	}
+	*dst = T(n)
+	return nil
+}
+
 // Task はタスクを表すドメインモデル
+// Estimateの単位はプロジェクトのEstimateUnitに従う
 type Task struct {
-	ID                string       `json:"id"`
-	ProjectID         string       `json:"project_id"`
-	Title             string       `json:"title"`
-	Description       string       `json:"description"`
-	Status            TaskStatus   `json:"status"`
-	Priority          TaskPriority `json:"priority"`
-	EndDate           *time.Time   `json:"end_date,omitempty"`
-	GithubItemID      *string      `json:"github_item_id,omitempty"`
-	GithubIssueNumber *int         `json:"github_issue_number,omitempty"`
-	GithubIssueURL    *string      `json:"github_issue_url,omitempty"`
-	CreatedAt         time.Time    `json:"created_at"`
-	UpdatedAt         time.Time    `json:"updated_at"`
+	ID          string       `json:"id"`
+	ProjectID   string       `json:"project_id"`
+	Title       string       `json:"title"`
+	Description string       `json:"description"`
+	Status      TaskStatus   `json:"status"`
+	Priority    TaskPriority `json:"priority"`
+	StartDate   *time.Time   `json:"start_date,omitempty"`
+	EndDate     *time.Time   `json:"end_date,omitempty"`
+	Estimate    *float64     `json:"estimate,omitempty"`
+	MilestoneID *string      `json:"milestone_id,omitempty"`
+	// ParentTaskID はサブタスクの場合の親タスクのID（サブタスクはサブタスクを持てない）
+	ParentTaskID      *string `json:"parent_task_id,omitempty"`
+	GithubItemID      *string `json:"github_item_id,omitempty"`
+	GithubIssueNumber *int    `json:"github_issue_number,omitempty"`
+	GithubIssueURL    *string `json:"github_issue_url,omitempty"`
+	GithubBranch      *string `json:"github_branch,omitempty"`
+	// GithubChecksStatus は紐づくPull RequestのCIの状態（Pull Requestの同期時に更新する）
+	GithubChecksStatus *ChecksStatus `json:"github_checks_status,omitempty"`
+	// GithubSyncState は連携先のGitHubのItem・Issueとの同期の状態（正常に連携している場合はnil）
+	GithubSyncState *GithubSyncState `json:"github_sync_state,omitempty"`
+	// Version は楽観的排他制御のバージョン（更新のたびに1ずつ増える）
+	Version   int       `json:"version"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	// DeletedAt はゴミ箱に移した日時（ゴミ箱の一覧でのみ返す）
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+}
+
+// GithubSyncState はタスクとGitHubの連携先との同期の状態を表す
+type GithubSyncState string
+
+const (
+	// GithubSyncOrphaned は連携先のItem・IssueがGitHub上で削除され、同期できなくなったことを表す
+	GithubSyncOrphaned GithubSyncState = "orphaned"
+)
+
+// IsGithubOrphaned はGitHub上の連携先が削除されているかを返す
+func (t *Task) IsGithubOrphaned() bool {
+	return t.GithubSyncState != nil && *t.GithubSyncState == GithubSyncOrphaned
+}
+
+// IsGithubItemLinked はGitHub ProjectsのItemと連携しているか（連携切れでないか）を返す
+func (t *Task) IsGithubItemLinked() bool {
+	return t.GithubItemID != nil && *t.GithubItemID != "" && !t.IsGithubOrphaned()
 }
 
 // HasGithubIssue はGitHub Issueが紐づいているかを返す
 func (t *Task) HasGithubIssue() bool {
 	return t.GithubIssueURL != nil && *t.GithubIssueURL != ""
 }
+
+// GithubTaskLookup はGitHubの連携先に対応するタスクを検索する条件を表す
+// IssueURL、Owner・Repo・Number、ItemIDのいずれかを指定する（複数指定した場合はこの順に優先する）
+type GithubTaskLookup struct {
+	IssueURL string
+	Owner    string
+	Repo     string
+	Number   int
+	ItemID   string
+}
+
+// MaxEstimate は見積もりの上限値
+const MaxEstimate = 10000
+
+// ValidateEstimate は見積もりが0以上MaxEstimate以下であることを検証する（nilは未設定として許可する）
+func ValidateEstimate(estimate *float64) error {
+	if estimate != nil && (*estimate < 0 || *estimate > MaxEstimate) {
+		return fmt.Errorf("estimate must be between 0 and %d: %w", MaxEstimate, ErrInvalidInput)
+	}
+	return nil
+}
+
+// ValidateSchedule は開始日が終了日より後になっていないことを検証する
+func ValidateSchedule(startDate, endDate *time.Time) error {
+	if startDate != nil && endDate != nil && startDate.After(*endDate) {
+		return fmt.Errorf("start_date must not be after end_date: %w", ErrInvalidInput)
+	}
+	return nil
+}
+
+// CreateTaskRequest はタスク作成リクエストを表す
+type CreateTaskRequest struct {
+	ProjectID   string `json:"project_id" validate:"required,uuid"`
+	Title       string `json:"title" validate:"required,max=255"`
+	Description string `json:"description" validate:"max=10000"`
+	// Status は省略するとプロジェクト所有者の設定のデフォルトステータスになる
+	Status *TaskStatus `json:"status,omitempty"`
+	// Priority は省略すると中（DefaultTaskPriority）になる
+	Priority    *TaskPriority `json:"priority,omitempty"`
+	StartDate   *time.Time    `json:"start_date,omitempty"`
+	EndDate     *time.Time    `json:"end_date,omitempty"`
+	Estimate    *float64      `json:"estimate,omitempty" validate:"omitempty,min=0,max=10000"`
+	MilestoneID *string       `json:"milestone_id,omitempty" validate:"omitempty,uuid"`
+	// ParentTaskID を指定すると同じプロジェクトのタスクのサブタスクとして作成する
+	ParentTaskID *string `json:"parent_task_id,omitempty" validate:"omitempty,uuid"`
+}
+
+// UpdateTaskRequest はタスク更新リクエストを表す
+type UpdateTaskRequest struct {
+	Title       string       `json:"title" validate:"required,max=255"`
+	Description string       `json:"description" validate:"max=10000"`
+	Status      TaskStatus   `json:"status"`
+	Priority    TaskPriority `json:"priority"`
+	StartDate   *time.Time   `json:"start_date,omitempty"`
+	EndDate     *time.Time   `json:"end_date,omitempty"`
+	Estimate    *float64     `json:"estimate,omitempty" validate:"omitempty,min=0,max=10000"`
+	MilestoneID *string      `json:"milestone_id,omitempty" validate:"omitempty,uuid"`
+	// Reopen は完了済みタスクを再開する場合に指定する
+	Reopen bool `json:"reopen,omitempty"`
+	// Version は更新元として取得したタスクのバージョン（指定した場合は他の更新と競合するとErrStaleVersionにする）
+	Version *int `json:"version,omitempty"`
+}
+
+// PatchTaskRequest はタスクの部分更新リクエストを表す
+// nilのフィールドは更新せず、start_date・end_date・estimate・milestone_id・parent_task_idはnullを指定するとクリアされる
+type PatchTaskRequest struct {
+	Title       *string             `json:"title,omitempty" validate:"omitempty,min=1,max=255"`
+	Description *string             `json:"description,omitempty" validate:"omitempty,max=10000"`
+	Status      *TaskStatus         `json:"status,omitempty"`
+	Priority    *TaskPriority       `json:"priority,omitempty"`
+	StartDate   Nullable[time.Time] `json:"start_date"`
+	EndDate     Nullable[time.Time] `json:"end_date"`
+	Estimate    Nullable[float64]   `json:"estimate"`
+	MilestoneID Nullable[string]    `json:"milestone_id"`
+	// ParentTaskID は親タスクを変更する（nullを指定すると親のないタスクに戻す）
+	ParentTaskID Nullable[string] `json:"parent_task_id"`
+	// Reopen は完了済みタスクを再開する場合に指定する
+	Reopen bool `json:"reopen,omitempty"`
+	// Version は更新元として取得したタスクのバージョン（指定した場合は他の更新と競合するとErrStaleVersionにする）
+	Version *int `json:"version,omitempty"`
+}
@@ -0,0 +1,16 @@
+package model
+
+import "time"
+
+// TaskCommit はコミットメッセージでタスクを参照しているコミットを表す
+type TaskCommit struct {
+	TaskID    string `json:"task_id"`
+	ProjectID string `json:"project_id"`
+	SHA       string `json:"sha"`
+	// Message はコミットメッセージの1行目
+	Message     string    `json:"message"`
+	URL         string    `json:"url"`
+	Author      string    `json:"author"`
+	CommittedAt time.Time `json:"committed_at"`
+	CreatedAt   time.Time `json:"created_at"`
+}
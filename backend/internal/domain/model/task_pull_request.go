@@ -0,0 +1,100 @@
+package model
+
+import "time"
+
+// PullRequestState はPull Requestの状態を表す
+type PullRequestState string
+
+const (
+	PullRequestOpen   PullRequestState = "open"
+	PullRequestClosed PullRequestState = "closed"
+	PullRequestMerged PullRequestState = "merged"
+)
+
+// PullRequestLinkType はPull RequestがタスクのIssueを参照している方法を表す
+type PullRequestLinkType string
+
+const (
+	// PullRequestLinkCloses はcloses #N などのキーワードでIssueをクローズするPull Request
+	PullRequestLinkCloses PullRequestLinkType = "closes"
+	// PullRequestLinkBranch はブランチ名にIssue番号を含むPull Request
+	PullRequestLinkBranch PullRequestLinkType = "branch"
+)
+
+// ChecksStatus はPull RequestのCI（GitHub Checks・Actions・コミットステータス）の状態を表す
+type ChecksStatus string
+
+const (
+	ChecksSuccess ChecksStatus = "success"
+	ChecksFailure ChecksStatus = "failure"
+	ChecksPending ChecksStatus = "pending"
+)
+
+// ParseChecksState はGitHubのstatusCheckRollupの状態をChecksStatusに変換する（未実行・不明な状態はnil）
+func ParseChecksState(state string) *ChecksStatus {
+	var status ChecksStatus
+	switch state {
+	case "success":
+		status = ChecksSuccess
+	case "failure", "error":
+		status = ChecksFailure
+	case "pending", "expected":
+		status = ChecksPending
+	default:
+		return nil
+	}
+	return &status
+}
+
+// TaskPullRequest はタスクのGitHub Issueを参照しているPull Requestを表す
+type TaskPullRequest struct {
+	TaskID    string              `json:"task_id"`
+	Number    int                 `json:"number"`
+	Title     string              `json:"title"`
+	URL       string              `json:"url"`
+	State     PullRequestState    `json:"state"`
+	HeadRef   string              `json:"head_ref"`
+	LinkType  PullRequestLinkType `json:"link_type"`
+	MergedAt  *time.Time          `json:"merged_at,omitempty"`
+	UpdatedAt time.Time           `json:"updated_at"`
+	// ChecksStatus はPull Requestの先頭コミットのCIの状態（未実行の場合はnil）
+	ChecksStatus *ChecksStatus `json:"checks_status,omitempty"`
+}
+
+// AggregateChecksStatus はタスクに表示するCIの状態を集計する
+// オープンなPull Requestのいずれかが失敗していればfailure、実行中があればpending、すべて成功していればsuccessとする
+// オープンなPull Requestがない場合は最後にマージされたPull Requestの状態を使い、該当がなければnilを返す
+func AggregateChecksStatus(pullRequests []*TaskPullRequest) *ChecksStatus {
+	var open []*ChecksStatus
+	var lastMerged *TaskPullRequest
+	for _, pr := range pullRequests {
+		switch pr.State {
+		case PullRequestOpen:
+			if pr.ChecksStatus != nil {
+				open = append(open, pr.ChecksStatus)
+			}
+		case PullRequestMerged:
+			if lastMerged == nil || (pr.MergedAt != nil && lastMerged.MergedAt != nil && pr.MergedAt.After(*lastMerged.MergedAt)) {
+				lastMerged = pr
+			}
+		}
+	}
+
+	if len(open) == 0 {
+		if lastMerged != nil {
+			return lastMerged.ChecksStatus
+		}
+		return nil
+	}
+
+	result := ChecksSuccess
+	for _, status := range open {
+		switch *status {
+		case ChecksFailure:
+			return status
+		case ChecksPending:
+			result = ChecksPending
+		}
+	}
+	return &result
+}
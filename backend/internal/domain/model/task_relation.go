@@ -0,0 +1,92 @@
+package model
+
+import (
+	"fmt"
+	"time"
+)
+
+// TaskRelationType はタスク間の関連の種類を表す
+type TaskRelationType string
+
+const (
+	TaskRelationRelatesTo    TaskRelationType = "relates_to"
+	TaskRelationDuplicates   TaskRelationType = "duplicates"
+	TaskRelationDuplicatedBy TaskRelationType = "duplicated_by"
+)
+
+// IsValid は定義済みの関連の種類かどうかを返す
+func (t TaskRelationType) IsValid() bool {
+	switch t {
+	case TaskRelationRelatesTo, TaskRelationDuplicates, TaskRelationDuplicatedBy:
+		return true
+	}
+	return false
+}
+
+// Inverse は関連先のタスクから見た関連の種類を返す
+func (t TaskRelationType) Inverse() TaskRelationType {
+	switch t {
+	case TaskRelationDuplicates:
+		return TaskRelationDuplicatedBy
+	case TaskRelationDuplicatedBy:
+		return TaskRelationDuplicates
+	}
+	return t
+}
+
+// TaskRelation はタスク間の関連を表す（プロジェクトをまたいで関連付けできる）
+// 保存時は正規化され、duplicated_byはduplicatesに、relates_toはIDの昇順に揃える
+type TaskRelation struct {
+	TaskID        string           `json:"task_id"`
+	RelatedTaskID string           `json:"related_task_id"`
+	Type          TaskRelationType `json:"type"`
+	CreatedAt     time.Time        `json:"created_at"`
+}
+
+// NewTaskRelation はtaskIDから見た関連を正規化したTaskRelationを作成する
+func NewTaskRelation(taskID, relatedTaskID string, relationType TaskRelationType) (*TaskRelation, error) {
+	if !relationType.IsValid() {
+		return nil, fmt.Errorf("unknown task relation type %q: %w", relationType, ErrInvalidInput)
+	}
+	if taskID == relatedTaskID {
+		return nil, fmt.Errorf("task cannot relate to itself: %w", ErrInvalidInput)
+	}
+
+	switch {
+	case relationType == TaskRelationDuplicatedBy:
+		taskID, relatedTaskID, relationType = relatedTaskID, taskID, TaskRelationDuplicates
+	case relationType == TaskRelationRelatesTo && relatedTaskID < taskID:
+		taskID, relatedTaskID = relatedTaskID, taskID
+	}
+
+	return &TaskRelation{
+		TaskID:        taskID,
+		RelatedTaskID: relatedTaskID,
+		Type:          relationType,
+	}, nil
+}
+
+// TaskRelationLink はあるタスクから見た関連先のタスクを表す
+// Typeは参照元のタスクから見た種類（関連先から作成された場合は逆向きの種類）になる
+type TaskRelationLink struct {
+	Type      TaskRelationType `json:"type"`
+	TaskID    string           `json:"task_id"`
+	ProjectID string           `json:"project_id"`
+	Title     string           `json:"title"`
+	Status    TaskStatus       `json:"status"`
+	CreatedAt time.Time        `json:"created_at"`
+}
+
+// TaskDetail は関連・サブタスクを含むタスクの詳細を表す
+type TaskDetail struct {
+	*Task
+	Relations []*TaskRelationLink `json:"relations"`
+	// Subtasks はサブタスク（作成順）
+	Subtasks []*Task `json:"subtasks"`
+}
+
+// AddTaskRelationRequest はタスク関連追加リクエストを表す
+type AddTaskRelationRequest struct {
+	RelatedTaskID string           `json:"related_task_id" validate:"required,uuid"`
+	Type          TaskRelationType `json:"type" validate:"required,oneof=relates_to duplicates duplicated_by"`
+}
@@ -0,0 +1,186 @@
+package model
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestTaskStatusMarshalJSON(t *testing.T) {
+	tests := []struct {
+		status TaskStatus
+		want   string
+	}{
+		{status: TaskStatusTodo, want: `"todo"`},
+		{status: TaskStatusInProgress, want: `"in_progress"`},
+		{status: TaskStatusDone, want: `"done"`},
+		// 未定義の値は数値のまま出力する
+		{status: TaskStatus(7), want: `7`},
+		{status: TaskStatus(-1), want: `-1`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.status.String(), func(t *testing.T) {
+			got, err := json.Marshal(tt.status)
+			if err != nil {
+				t.Fatalf("Marshal() error = %v", err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("Marshal() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTaskStatusUnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		want    TaskStatus
+		wantErr bool
+	}{
+		{name: "todo", data: `"todo"`, want: TaskStatusTodo},
+		{name: "in_progress", data: `"in_progress"`, want: TaskStatusInProgress},
+		{name: "done", data: `"done"`, want: TaskStatusDone},
+		// 移行期間のため数値も受け付ける
+		{name: "legacy todo", data: `0`, want: TaskStatusTodo},
+		{name: "legacy in_progress", data: `1`, want: TaskStatusInProgress},
+		{name: "legacy done", data: `2`, want: TaskStatusDone},
+		{name: "legacy with spaces", data: ` 2 `, want: TaskStatusDone},
+		// 数値の範囲はIsValidで検証する
+		{name: "legacy out of range", data: `7`, want: TaskStatus(7)},
+		{name: "unknown string", data: `"archived"`, wantErr: true},
+		{name: "wrong case", data: `"Done"`, wantErr: true},
+		{name: "numeric string", data: `"2"`, wantErr: true},
+		{name: "empty string", data: `""`, wantErr: true},
+		{name: "fraction", data: `1.5`, wantErr: true},
+		{name: "boolean", data: `true`, wantErr: true},
+		{name: "object", data: `{}`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got TaskStatus
+			err := json.Unmarshal([]byte(tt.data), &got)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidInput) {
+					t.Errorf("Unmarshal(%s) error = %v, want ErrInvalidInput", tt.data, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unmarshal(%s) error = %v", tt.data, err)
+			}
+			if got != tt.want {
+				t.Errorf("Unmarshal(%s) = %v, want %v", tt.data, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTaskPriorityMarshalJSON(t *testing.T) {
+	tests := []struct {
+		priority TaskPriority
+		want     string
+	}{
+		{priority: TaskPriorityLow, want: `"low"`},
+		{priority: TaskPriorityMedium, want: `"medium"`},
+		{priority: TaskPriorityHigh, want: `"high"`},
+		// 未定義の値は数値のまま出力する
+		{priority: TaskPriority(3), want: `3`},
+		{priority: TaskPriority(-1), want: `-1`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.priority.String(), func(t *testing.T) {
+			got, err := json.Marshal(tt.priority)
+			if err != nil {
+				t.Fatalf("Marshal() error = %v", err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("Marshal() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTaskPriorityUnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		want    TaskPriority
+		wantErr bool
+	}{
+		{name: "low", data: `"low"`, want: TaskPriorityLow},
+		{name: "medium", data: `"medium"`, want: TaskPriorityMedium},
+		{name: "high", data: `"high"`, want: TaskPriorityHigh},
+		// 移行期間のため数値も受け付ける
+		{name: "legacy low", data: `0`, want: TaskPriorityLow},
+		{name: "legacy medium", data: `1`, want: TaskPriorityMedium},
+		{name: "legacy high", data: `2`, want: TaskPriorityHigh},
+		// 数値の範囲はIsValidで検証する
+		{name: "legacy out of range", data: `3`, want: TaskPriority(3)},
+		{name: "unknown string", data: `"urgent"`, wantErr: true},
+		{name: "wrong case", data: `"HIGH"`, wantErr: true},
+		{name: "empty string", data: `""`, wantErr: true},
+		{name: "fraction", data: `0.5`, wantErr: true},
+		{name: "array", data: `[1]`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got TaskPriority
+			err := json.Unmarshal([]byte(tt.data), &got)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidInput) {
+					t.Errorf("Unmarshal(%s) error = %v, want ErrInvalidInput", tt.data, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unmarshal(%s) error = %v", tt.data, err)
+			}
+			if got != tt.want {
+				t.Errorf("Unmarshal(%s) = %v, want %v", tt.data, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestTaskEnumUnmarshalNull はnullを読み込んでも値を変えず、省略可能な項目はnilのままであることを確認する
+func TestTaskEnumUnmarshalNull(t *testing.T) {
+	status, priority := TaskStatusDone, TaskPriorityHigh
+	if err := json.Unmarshal([]byte(`null`), &status); err != nil || status != TaskStatusDone {
+		t.Errorf("Unmarshal(null) into TaskStatus = %v, %v, want done", status, err)
+	}
+	if err := json.Unmarshal([]byte(`null`), &priority); err != nil || priority != TaskPriorityHigh {
+		t.Errorf("Unmarshal(null) into TaskPriority = %v, %v, want high", priority, err)
+	}
+
+	var input PatchTaskRequest
+	if err := json.Unmarshal([]byte(`{"status":null,"priority":null}`), &input); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	if input.Status != nil || input.Priority != nil {
+		t.Errorf("status, priority = %v, %v, want nil", input.Status, input.Priority)
+	}
+}
+
+// TestTaskJSONRoundTrip はタスクのステータスと優先度が文字列で出力され、そのまま読み込めることを確認する
+func TestTaskJSONRoundTrip(t *testing.T) {
+	data, err := json.Marshal(Task{ID: "t1", Status: TaskStatusInProgress, Priority: TaskPriorityHigh})
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	var fields map[string]any
+	if err := json.Unmarshal(data, &fields); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	if fields["status"] != "in_progress" || fields["priority"] != "high" {
+		t.Errorf("status, priority = %v, %v, want in_progress, high", fields["status"], fields["priority"])
+	}
+
+	var got Task
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	if got.Status != TaskStatusInProgress || got.Priority != TaskPriorityHigh {
+		t.Errorf("status, priority = %v, %v, want in_progress, high", got.Status, got.Priority)
+	}
+}
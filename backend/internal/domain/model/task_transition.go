@@ -0,0 +1,117 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ErrInvalidStatusTransition は許可されていないステータス遷移の場合のエラー
+var ErrInvalidStatusTransition = fmt.Errorf("invalid status transition: %w", ErrConflict)
+
+// ErrReopenRequired は再開フラグなしで完了済みタスクのステータスを戻そうとした場合のエラー
+var ErrReopenRequired = fmt.Errorf("reopen flag is required: %w", ErrConflict)
+
+// TaskStatusEvent はタスクのステータス遷移イベントを表す
+// アクティビティログやGitHubステータス同期、各種レポートの元データとして使用する
+type TaskStatusEvent struct {
+	ID        string `json:"id"`
+	TaskID    string `json:"task_id"`
+	ProjectID string `json:"project_id"`
+	// FromStatus は遷移前のステータス（タスク作成時はnil）
+	FromStatus *TaskStatus `json:"from_status"`
+	ToStatus   TaskStatus  `json:"to_status"`
+	Reopened   bool        `json:"reopened"`
+	ChangedAt  time.Time   `json:"changed_at"`
+}
+
+// TaskTransitionPolicy はタスクのステータス遷移ルールを表す
+type TaskTransitionPolicy struct {
+	allowed map[TaskStatus]map[TaskStatus]bool
+	// reopenFrom は遷移に再開フラグが必要な遷移元ステータス
+	reopenFrom map[TaskStatus]bool
+}
+
+// DefaultTaskTransitionPolicy はデフォルトの遷移ルールを返す
+// すべてのステータス間の遷移を許可し、完了（Done）からの遷移のみ再開フラグを必須とする
+func DefaultTaskTransitionPolicy() *TaskTransitionPolicy {
+	p := &TaskTransitionPolicy{
+		allowed:    make(map[TaskStatus]map[TaskStatus]bool),
+		reopenFrom: map[TaskStatus]bool{TaskStatusDone: true},
+	}
+	for from := range taskStatusNames {
+		for to := range taskStatusNames {
+			if from != to {
+				p.allow(from, to)
+			}
+		}
+	}
+	return p
+}
+
+// ParseTaskTransitionPolicy は設定値から遷移ルールを作成する
+// transitionsは "todo:in_progress" 形式の許可する遷移の一覧、reopenFromは再開フラグが必要な遷移元ステータスの一覧
+// transitionsが空の場合はデフォルトのルールに対してreopenFromのみ適用する
+func ParseTaskTransitionPolicy(transitions, reopenFrom []string) (*TaskTransitionPolicy, error) {
+	p := DefaultTaskTransitionPolicy()
+
+	if len(transitions) > 0 {
+		p.allowed = make(map[TaskStatus]map[TaskStatus]bool)
+		for _, t := range transitions {
+			fromName, toName, ok := strings.Cut(strings.TrimSpace(t), ":")
+			if !ok {
+				return nil, fmt.Errorf("invalid transition %q (expected from:to)", t)
+			}
+			from, err := ParseTaskStatus(fromName)
+			if err != nil {
+				return nil, err
+			}
+			to, err := ParseTaskStatus(toName)
+			if err != nil {
+				return nil, err
+			}
+			p.allow(from, to)
+		}
+	}
+
+	if reopenFrom != nil {
+		p.reopenFrom = make(map[TaskStatus]bool)
+		for _, name := range reopenFrom {
+			if name = strings.TrimSpace(name); name == "" {
+				continue
+			}
+			s, err := ParseTaskStatus(name)
+			if err != nil {
+				return nil, err
+			}
+			p.reopenFrom[s] = true
+		}
+	}
+
+	return p, nil
+}
+
+func (p *TaskTransitionPolicy) allow(from, to TaskStatus) {
+	if p.allowed[from] == nil {
+		p.allowed[from] = make(map[TaskStatus]bool)
+	}
+	p.allowed[from][to] = true
+}
+
+// Validate はステータス遷移が許可されているかを検証する
+// 同じステータスへの遷移は常に許可する
+func (p *TaskTransitionPolicy) Validate(from, to TaskStatus, reopen bool) error {
+	if !to.IsValid() {
+		return fmt.Errorf("invalid task status %d: %w", int(to), ErrInvalidInput)
+	}
+	if from == to {
+		return nil
+	}
+	if !p.allowed[from][to] {
+		return fmt.Errorf("%s -> %s: %w", from, to, ErrInvalidStatusTransition)
+	}
+	if p.reopenFrom[from] && !reopen {
+		return fmt.Errorf("%s -> %s: %w", from, to, ErrReopenRequired)
+	}
+	return nil
+}
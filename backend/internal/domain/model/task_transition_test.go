@@ -0,0 +1,92 @@
+package model
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestTaskTransitionPolicyValidate(t *testing.T) {
+	// todo→in_progress→doneの順にのみ進められ、完了からtodoに戻す場合は再開フラグが必要なルール
+	custom, err := ParseTaskTransitionPolicy([]string{"todo:in_progress", " in_progress:done ", "done:todo"}, []string{"done"})
+	if err != nil {
+		t.Fatalf("ParseTaskTransitionPolicy() error = %v", err)
+	}
+	// 遷移はデフォルトのまま、再開フラグを不要にしたルール
+	noReopen, err := ParseTaskTransitionPolicy(nil, []string{})
+	if err != nil {
+		t.Fatalf("ParseTaskTransitionPolicy() error = %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		policy  *TaskTransitionPolicy
+		from    TaskStatus
+		to      TaskStatus
+		reopen  bool
+		wantErr error
+	}{
+		{name: "default todo to in_progress", policy: DefaultTaskTransitionPolicy(), from: TaskStatusTodo, to: TaskStatusInProgress},
+		{name: "default todo to done", policy: DefaultTaskTransitionPolicy(), from: TaskStatusTodo, to: TaskStatusDone},
+		{name: "default in_progress to todo", policy: DefaultTaskTransitionPolicy(), from: TaskStatusInProgress, to: TaskStatusTodo},
+		{name: "default in_progress to done", policy: DefaultTaskTransitionPolicy(), from: TaskStatusInProgress, to: TaskStatusDone},
+		{name: "default done to todo without reopen", policy: DefaultTaskTransitionPolicy(), from: TaskStatusDone, to: TaskStatusTodo, wantErr: ErrReopenRequired},
+		{name: "default done to in_progress without reopen", policy: DefaultTaskTransitionPolicy(), from: TaskStatusDone, to: TaskStatusInProgress, wantErr: ErrReopenRequired},
+		{name: "default done to todo with reopen", policy: DefaultTaskTransitionPolicy(), from: TaskStatusDone, to: TaskStatusTodo, reopen: true},
+		{name: "default reopen flag on other transitions", policy: DefaultTaskTransitionPolicy(), from: TaskStatusTodo, to: TaskStatusDone, reopen: true},
+		// 同じステータスへの遷移は常に許可する
+		{name: "default same status", policy: DefaultTaskTransitionPolicy(), from: TaskStatusDone, to: TaskStatusDone},
+		{name: "default invalid status", policy: DefaultTaskTransitionPolicy(), from: TaskStatusTodo, to: TaskStatus(3), wantErr: ErrInvalidInput},
+		{name: "default negative status", policy: DefaultTaskTransitionPolicy(), from: TaskStatusTodo, to: TaskStatus(-1), wantErr: ErrInvalidInput},
+		{name: "default invalid same status", policy: DefaultTaskTransitionPolicy(), from: TaskStatus(3), to: TaskStatus(3), wantErr: ErrInvalidInput},
+		{name: "custom allowed", policy: custom, from: TaskStatusTodo, to: TaskStatusInProgress},
+		{name: "custom allowed with reopen", policy: custom, from: TaskStatusDone, to: TaskStatusTodo, reopen: true},
+		{name: "custom skip step", policy: custom, from: TaskStatusTodo, to: TaskStatusDone, wantErr: ErrInvalidStatusTransition},
+		{name: "custom backwards", policy: custom, from: TaskStatusInProgress, to: TaskStatusTodo, wantErr: ErrInvalidStatusTransition},
+		// 許可されていない遷移は再開フラグを指定しても拒否する
+		{name: "custom not allowed with reopen", policy: custom, from: TaskStatusDone, to: TaskStatusInProgress, reopen: true, wantErr: ErrInvalidStatusTransition},
+		{name: "custom same status", policy: custom, from: TaskStatusInProgress, to: TaskStatusInProgress},
+		{name: "no reopen required", policy: noReopen, from: TaskStatusDone, to: TaskStatusTodo},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.policy.Validate(tt.from, tt.to, tt.reopen)
+			if tt.wantErr == nil {
+				if err != nil {
+					t.Errorf("Validate(%s, %s, %v) error = %v", tt.from, tt.to, tt.reopen, err)
+				}
+				return
+			}
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Validate(%s, %s, %v) error = %v, want %v", tt.from, tt.to, tt.reopen, err, tt.wantErr)
+			}
+		})
+	}
+
+	// 遷移ルールの違反は競合として扱う
+	if err := DefaultTaskTransitionPolicy().Validate(TaskStatusDone, TaskStatusTodo, false); !errors.Is(err, ErrConflict) {
+		t.Errorf("Validate() error = %v, want ErrConflict", err)
+	}
+}
+
+func TestParseTaskTransitionPolicyInvalid(t *testing.T) {
+	tests := []struct {
+		name        string
+		transitions []string
+		reopenFrom  []string
+	}{
+		{name: "missing separator", transitions: []string{"todo-done"}},
+		{name: "empty transition", transitions: []string{""}},
+		{name: "unknown from status", transitions: []string{"archived:todo"}},
+		{name: "unknown to status", transitions: []string{"todo:archived"}},
+		{name: "missing to status", transitions: []string{"todo:"}},
+		{name: "numeric status", transitions: []string{"0:1"}},
+		{name: "unknown reopen status", reopenFrom: []string{"closed"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if p, err := ParseTaskTransitionPolicy(tt.transitions, tt.reopenFrom); err == nil {
+				t.Errorf("ParseTaskTransitionPolicy(%q, %q) = %v, want error", tt.transitions, tt.reopenFrom, p)
+			}
+		})
+	}
+}
@@ -0,0 +1,128 @@
+package model
+
+import (
+	"fmt"
+	"time"
+)
+
+// ErrDependencyCycle は依存関係が循環する場合のエラー
+var ErrDependencyCycle = fmt.Errorf("task dependency cycle: %w", ErrConflict)
+
+// TaskDependency はタスク間の依存関係（TaskIDはDependsOnTaskIDの完了後に開始する）を表す
+type TaskDependency struct {
+	TaskID          string    `json:"task_id"`
+	DependsOnTaskID string    `json:"depends_on_task_id"`
+	ProjectID       string    `json:"project_id"`
+	CreatedAt       time.Time `json:"created_at"`
+}
+
+// AddTaskDependencyRequest はタスクの依存関係追加リクエストを表す
+type AddTaskDependencyRequest struct {
+	DependsOnTaskID string `json:"depends_on_task_id" validate:"required,uuid"`
+}
+
+// TimelineItem はタイムライン（ガントチャート）上のタスクを表す
+// 開始日が未設定の場合は作成日時を開始とし、終了日が未設定の場合はEndをnilとする
+type TimelineItem struct {
+	ID        string       `json:"id"`
+	Title     string       `json:"title"`
+	Status    TaskStatus   `json:"status"`
+	Priority  TaskPriority `json:"priority"`
+	Start     time.Time    `json:"start"`
+	End       *time.Time   `json:"end,omitempty"`
+	Scheduled bool         `json:"scheduled"`
+}
+
+// TimelineEdge はタイムライン上の依存関係（Fromの完了後にToを開始する）を表す
+type TimelineEdge struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+	Type string `json:"type"`
+}
+
+// TimelineEdgeFinishToStart は終了-開始型の依存関係
+const TimelineEdgeFinishToStart = "finish_to_start"
+
+// Timeline はプロジェクトのタイムラインを表す
+type Timeline struct {
+	ProjectID string         `json:"project_id"`
+	Start     *time.Time     `json:"start,omitempty"`
+	End       *time.Time     `json:"end,omitempty"`
+	Items     []TimelineItem `json:"items"`
+	Edges     []TimelineEdge `json:"edges"`
+}
+
+// NewTimeline はタスクと依存関係からタイムラインを作成する
+// 依存関係のうちタスク一覧に含まれないものは除外する
+func NewTimeline(projectID string, tasks []*Task, deps []*TaskDependency) *Timeline {
+	t := &Timeline{
+		ProjectID: projectID,
+		Items:     make([]TimelineItem, 0, len(tasks)),
+		Edges:     make([]TimelineEdge, 0, len(deps)),
+	}
+
+	ids := make(map[string]bool, len(tasks))
+	for _, task := range tasks {
+		ids[task.ID] = true
+
+		item := TimelineItem{
+			ID:        task.ID,
+			Title:     task.Title,
+			Status:    task.Status,
+			Priority:  task.Priority,
+			Start:     task.CreatedAt,
+			End:       task.EndDate,
+			Scheduled: task.StartDate != nil,
+		}
+		if task.StartDate != nil {
+			item.Start = *task.StartDate
+		}
+		t.Items = append(t.Items, item)
+
+		if t.Start == nil || item.Start.Before(*t.Start) {
+			start := item.Start
+			t.Start = &start
+		}
+		if item.End != nil && (t.End == nil || item.End.After(*t.End)) {
+			end := *item.End
+			t.End = &end
+		}
+	}
+
+	for _, dep := range deps {
+		if ids[dep.TaskID] && ids[dep.DependsOnTaskID] {
+			t.Edges = append(t.Edges, TimelineEdge{
+				From: dep.DependsOnTaskID,
+				To:   dep.TaskID,
+				Type: TimelineEdgeFinishToStart,
+			})
+		}
+	}
+
+	return t
+}
+
+// HasDependencyPath はdepsの依存関係をたどってfromからtoに到達できるかを返す
+// 依存関係の追加前に循環の検出に使用する
+func HasDependencyPath(deps []*TaskDependency, from, to string) bool {
+	next := make(map[string][]string)
+	for _, dep := range deps {
+		next[dep.TaskID] = append(next[dep.TaskID], dep.DependsOnTaskID)
+	}
+
+	visited := make(map[string]bool)
+	stack := []string{from}
+	for len(stack) > 0 {
+		id := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if id == to {
+			return true
+		}
+		if visited[id] {
+			continue
+		}
+		visited[id] = true
+		stack = append(stack, next[id]...)
+	}
+	return false
+}
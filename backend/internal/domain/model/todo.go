@@ -5,11 +5,14 @@ import "time"
 // Todo はTODOアイテムを表すドメインモデル
 type Todo struct {
 	ID          string    `json:"id"`
+	UserID      string    `json:"user_id"`
 	Title       string    `json:"title"`
 	Description string    `json:"description"`
 	Completed   bool      `json:"completed"`
 	CreatedAt   time.Time `json:"created_at"`
 	UpdatedAt   time.Time `json:"updated_at"`
+	// DeletedAt はゴミ箱に移した日時（ゴミ箱の一覧でのみ返す）
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
 }
 
 // CreateTodoRequest はTODO作成リクエストを表す
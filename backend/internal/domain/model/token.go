@@ -0,0 +1,26 @@
+package model
+
+import "time"
+
+// RefreshToken はアクセストークンの再発行に使うリフレッシュトークンを表す
+// 同じログインセッションで発行したトークンを1つの系列として扱い、使用のたびに新しいトークンへ置き換える
+type RefreshToken struct {
+	ID        string
+	SessionID string
+	UserID    string
+	// TokenHash はトークンのSHA-256ハッシュ（トークン自体は保存しない）
+	TokenHash string
+	// ExpiresAt は系列の有効期限（ログイン時に決まり、再発行しても延長しない）
+	ExpiresAt time.Time
+	// UsedAt は再発行に使われた日時（使用済みのトークンが再び使われた場合は漏洩とみなす）
+	UsedAt    *time.Time
+	CreatedAt time.Time
+}
+
+// AccessToken はSPAに返す短期間有効なアクセストークン
+type AccessToken struct {
+	AccessToken string    `json:"access_token"`
+	TokenType   string    `json:"token_type"`
+	ExpiresIn   int       `json:"expires_in"`
+	ExpiresAt   time.Time `json:"expires_at"`
+}
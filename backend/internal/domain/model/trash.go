@@ -0,0 +1,9 @@
+package model
+
+// Trash はユーザーのゴミ箱の内容を表す（それぞれ削除日時の新しい順）
+// プロジェクトと一緒にゴミ箱に移したタスクはプロジェクトに含まれるため、Tasksには個別にゴミ箱に移したタスクだけを含める
+type Trash struct {
+	Projects []*Project `json:"projects"`
+	Tasks    []*Task    `json:"tasks"`
+	Todos    []*Todo    `json:"todos"`
+}
@@ -1,18 +1,43 @@
 package model
 
-import "time"
+import (
+	"strings"
+	"time"
+)
 
 // User はユーザー情報を表すドメインモデル
 type User struct {
-	ID        string    `json:"id"`
-	Email     string    `json:"email"`
-	Name      string    `json:"name"`
-	ImageURL  string    `json:"image_url"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID       string `json:"id"`
+	Email    string `json:"email"`
+	Name     string `json:"name"`
+	ImageURL string `json:"image_url"`
+	// DeactivatedAt はプロビジョニング（SCIM）で無効化された日時（有効な場合はnil）
+	DeactivatedAt *time.Time `json:"deactivated_at,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+}
+
+// Active はユーザーが無効化されていないかどうかを返す
+func (u *User) Active() bool {
+	return u.DeactivatedAt == nil
 }
 
 // Picture はImageURLのエイリアス（後方互換性のため）
 func (u *User) Picture() string {
 	return u.ImageURL
 }
+
+// GuestEmailDomain はデモモードのゲストユーザーに割り当てるメールアドレスのドメイン（配送されない予約済みドメイン）
+const GuestEmailDomain = "guest.invalid"
+
+// IsGuest はデモモードで作成された一時的なゲストユーザーかどうかを返す
+func (u *User) IsGuest() bool {
+	return strings.HasSuffix(u.Email, "@"+GuestEmailDomain)
+}
+
+// GuestUser はデモモードのゲストユーザーの有効期限（期限を過ぎるとユーザーごと削除される）
+type GuestUser struct {
+	UserID    string    `json:"user_id"`
+	ExpiresAt time.Time `json:"expires_at"`
+	CreatedAt time.Time `json:"created_at"`
+}
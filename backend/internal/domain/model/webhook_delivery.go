@@ -0,0 +1,43 @@
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// WebhookDeliveryStatus はWebhookの配信の処理状況を表す
+type WebhookDeliveryStatus string
+
+const (
+	// WebhookDeliveryPending は処理待ち（再試行待ちを含む）
+	WebhookDeliveryPending WebhookDeliveryStatus = "pending"
+	// WebhookDeliveryProcessed は処理済み
+	WebhookDeliveryProcessed WebhookDeliveryStatus = "processed"
+	// WebhookDeliveryDead は再試行の上限に達して処理を諦めたもの（デッドレター）
+	WebhookDeliveryDead WebhookDeliveryStatus = "dead"
+)
+
+// ParseWebhookDeliveryStatus は名前から処理状況を取得する
+func ParseWebhookDeliveryStatus(name string) (WebhookDeliveryStatus, error) {
+	switch s := WebhookDeliveryStatus(name); s {
+	case WebhookDeliveryPending, WebhookDeliveryProcessed, WebhookDeliveryDead:
+		return s, nil
+	}
+	return "", fmt.Errorf("unknown webhook delivery status %q: %w", name, ErrInvalidInput)
+}
+
+// WebhookDelivery はGitHubから受信したWebhookの配信を表す
+// IDはGitHubの配信ID（X-GitHub-Delivery）で、同じ配信の再送は重複して処理しない
+type WebhookDelivery struct {
+	ID            string                `json:"id"`
+	Event         string                `json:"event"`
+	Payload       json.RawMessage       `json:"payload"`
+	Status        WebhookDeliveryStatus `json:"status"`
+	Attempts      int                   `json:"attempts"`
+	LastError     *string               `json:"last_error,omitempty"`
+	NextAttemptAt time.Time             `json:"next_attempt_at"`
+	ProcessedAt   *time.Time            `json:"processed_at,omitempty"`
+	CreatedAt     time.Time             `json:"created_at"`
+	UpdatedAt     time.Time             `json:"updated_at"`
+}
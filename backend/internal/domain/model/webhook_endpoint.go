@@ -0,0 +1,107 @@
+package model
+
+import (
+	"encoding/json"
+	"slices"
+	"time"
+)
+
+// WebhookSecretPrefix は送信先の署名の秘密（secret）の先頭に付ける文字列
+const WebhookSecretPrefix = "whsec_"
+
+// WebhookEndpoint はプロジェクトのドメインイベントを通知する外部のWebhookの送信先を表す
+// 送信に連続して失敗した送信先は自動で無効にし、有効に戻すまで送信しない
+type WebhookEndpoint struct {
+	ID        string `json:"id"`
+	ProjectID string `json:"project_id"`
+	UserID    string `json:"user_id"`
+	URL       string `json:"url"`
+	// Secret は本文の署名に使う共有の秘密（作成時のレスポンスでのみ返す）
+	Secret string `json:"-"`
+	// EventTypes は通知するイベントの種類（空の場合はすべて）
+	EventTypes          []string   `json:"event_types"`
+	Enabled             bool       `json:"enabled"`
+	ConsecutiveFailures int        `json:"consecutive_failures"`
+	DisabledAt          *time.Time `json:"disabled_at,omitempty"`
+	CreatedAt           time.Time  `json:"created_at"`
+	UpdatedAt           time.Time  `json:"updated_at"`
+}
+
+// Subscribes はイベントの種類を通知の対象にしているかを返す
+func (e *WebhookEndpoint) Subscribes(eventType string) bool {
+	return len(e.EventTypes) == 0 || slices.Contains(e.EventTypes, eventType)
+}
+
+// WebhookEventTypes は送信先に通知できるイベントの種類
+var WebhookEventTypes = []string{
+	EventTaskCreated,
+	EventTaskUpdated,
+	EventTaskStatusChanged,
+	EventTaskDeleted,
+	EventTaskRestored,
+	EventTaskReminder,
+	EventProjectUpdated,
+	EventProjectRestored,
+	EventProjectLinked,
+	EventProjectUnlinked,
+}
+
+// CreateWebhookEndpointRequest はWebhookの送信先の登録リクエストを表す
+type CreateWebhookEndpointRequest struct {
+	URL        string   `json:"url" validate:"required,url,max=2000"`
+	EventTypes []string `json:"event_types" validate:"max=20,dive,min=1,max=64"`
+}
+
+// PatchWebhookEndpointRequest はWebhookの送信先の部分更新リクエストを表す（nilのフィールドは更新しない）
+// enabledをtrueにすると、自動で無効になった送信先の連続失敗回数を0に戻して送信を再開する
+type PatchWebhookEndpointRequest struct {
+	URL        *string   `json:"url,omitempty" validate:"omitempty,url,max=2000"`
+	EventTypes *[]string `json:"event_types,omitempty" validate:"omitempty,max=20,dive,min=1,max=64"`
+	Enabled    *bool     `json:"enabled,omitempty"`
+}
+
+// CreatedWebhookEndpoint は登録した送信先を表す（secretは登録時のレスポンスでのみ返す）
+type CreatedWebhookEndpoint struct {
+	*WebhookEndpoint
+	Secret string `json:"secret"`
+}
+
+// WebhookEndpointDeliveryStatus は送信先への配信の状況を表す
+type WebhookEndpointDeliveryStatus string
+
+const (
+	// WebhookEndpointDeliveryPending は送信待ち（再試行待ちを含む）
+	WebhookEndpointDeliveryPending WebhookEndpointDeliveryStatus = "pending"
+	// WebhookEndpointDeliverySucceeded は送信先が2xxを返したもの
+	WebhookEndpointDeliverySucceeded WebhookEndpointDeliveryStatus = "succeeded"
+	// WebhookEndpointDeliveryFailed は再試行の上限に達して送信を諦めたもの
+	WebhookEndpointDeliveryFailed WebhookEndpointDeliveryStatus = "failed"
+)
+
+// WebhookEndpointDelivery は送信先へのイベントの配信を表す（送信のキューと配信ログを兼ねる）
+// 同じイベントは送信先ごとに1件だけ作り、IDを受信側の重複排除に使えるようX-Webhook-Idで送る
+type WebhookEndpointDelivery struct {
+	ID         string                        `json:"id"`
+	EndpointID string                        `json:"endpoint_id"`
+	UserID     string                        `json:"-"`
+	EventID    string                        `json:"event_id"`
+	EventType  string                        `json:"event_type"`
+	Payload    json.RawMessage               `json:"payload"`
+	Status     WebhookEndpointDeliveryStatus `json:"status"`
+	Attempts   int                           `json:"attempts"`
+	// ResponseStatus は最後の送信で送信先が返したHTTPステータス（応答がなかった場合はnil）
+	ResponseStatus *int       `json:"response_status,omitempty"`
+	LastError      *string    `json:"last_error,omitempty"`
+	NextAttemptAt  time.Time  `json:"next_attempt_at"`
+	DeliveredAt    *time.Time `json:"delivered_at,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
+
+// WebhookEventPayload は送信先に送る本文
+type WebhookEventPayload struct {
+	ID         string          `json:"id"`
+	Type       string          `json:"type"`
+	OccurredAt time.Time       `json:"occurred_at"`
+	Data       json.RawMessage `json:"data"`
+}
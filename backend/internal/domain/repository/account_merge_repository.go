@@ -0,0 +1,23 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/sikigasa/github-task-controller/backend/internal/domain/model"
+)
+
+// AccountMergeRepository はアカウント統合の要求と監査記録のリポジトリインターフェース
+type AccountMergeRepository interface {
+	// Create はアカウント統合の要求を作成する
+	Create(ctx context.Context, merge *model.AccountMerge) error
+	// FindByID はIDでアカウント統合の要求を検索する
+	FindByID(ctx context.Context, id string) (*model.AccountMerge, error)
+	// FindByTokenHash は確認トークンのハッシュでアカウント統合の要求を検索する
+	FindByTokenHash(ctx context.Context, tokenHash string) (*model.AccountMerge, error)
+	// FindByUserID はユーザーへのアカウント統合の要求を新しい順に検索する
+	FindByUserID(ctx context.Context, userID string) ([]*model.AccountMerge, error)
+	// Confirm は未確認のアカウント統合を確認済みにして、保持していたトークンを破棄する
+	// 確認済みの場合はErrConflictを返す
+	Confirm(ctx context.Context, id, via, ip string, at time.Time) error
+}
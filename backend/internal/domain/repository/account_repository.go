@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"github.com/sikigasa/github-task-controller/backend/internal/domain/model"
 )
@@ -10,12 +11,17 @@ import (
 type GithubAccountRepository interface {
 	// Create は新しいGitHubアカウント情報を作成する
 	Create(ctx context.Context, account *model.GithubAccount) error
-	// FindByProviderAccountID はプロバイダーアカウントIDで検索する
+	// FindByProviderAccountID はプロバイダーアカウントIDで検索する（存在しない場合はErrAccountNotFound）
 	FindByProviderAccountID(ctx context.Context, provider, providerAccountID string) (*model.GithubAccount, error)
-	// FindByUserID はユーザーIDで検索する
+	// FindByUserID はユーザーIDで検索する（存在しない場合はErrAccountNotFound）
 	FindByUserID(ctx context.Context, userID string) (*model.GithubAccount, error)
-	// Update はGitHubアカウント情報を更新する
+	// Update はGitHubアカウント情報を更新する（再認証が必要な状態も含む）
 	Update(ctx context.Context, account *model.GithubAccount) error
+	// UpdateOAuthToken はリフレッシュしたOAuthのトークンを保存し、OAuthのトークンの再認証が必要な状態を解除する
+	// refreshTokenが空の場合は保存済みのリフレッシュトークンを残す
+	UpdateOAuthToken(ctx context.Context, userID, accessToken, refreshToken string, expiresAt *time.Time) error
+	// MarkReauthRequired はGitHubがreasonのトークンを拒否したため、再認証が必要な状態にする
+	MarkReauthRequired(ctx context.Context, userID string, reason model.GithubReauthReason, at time.Time) error
 	// Delete はGitHubアカウント情報を削除する
 	Delete(ctx context.Context, provider, providerAccountID string) error
 }
@@ -24,12 +30,58 @@ type GithubAccountRepository interface {
 type GoogleAccountRepository interface {
 	// Create は新しいGoogleアカウント情報を作成する
 	Create(ctx context.Context, account *model.GoogleAccount) error
-	// FindByProviderAccountID はプロバイダーアカウントIDで検索する
+	// FindByProviderAccountID はプロバイダーアカウントIDで検索する（存在しない場合はErrAccountNotFound）
 	FindByProviderAccountID(ctx context.Context, provider, providerAccountID string) (*model.GoogleAccount, error)
-	// FindByUserID はユーザーIDで検索する
+	// FindByUserID はユーザーIDで検索する（存在しない場合はErrAccountNotFound）
 	FindByUserID(ctx context.Context, userID string) (*model.GoogleAccount, error)
 	// Update はGoogleアカウント情報を更新する
 	Update(ctx context.Context, account *model.GoogleAccount) error
 	// Delete はGoogleアカウント情報を削除する
 	Delete(ctx context.Context, provider, providerAccountID string) error
 }
+
+// AppleAccountRepository はAppleアカウントのリポジトリインターフェース
+type AppleAccountRepository interface {
+	// Create は新しいAppleアカウント情報を作成する
+	Create(ctx context.Context, account *model.AppleAccount) error
+	// FindByProviderAccountID はプロバイダーアカウントIDで検索する（存在しない場合はErrAccountNotFound）
+	FindByProviderAccountID(ctx context.Context, provider, providerAccountID string) (*model.AppleAccount, error)
+	// FindByUserID はユーザーIDで検索する（存在しない場合はErrAccountNotFound）
+	FindByUserID(ctx context.Context, userID string) (*model.AppleAccount, error)
+	// Update はAppleアカウント情報を更新する
+	Update(ctx context.Context, account *model.AppleAccount) error
+	// Delete はAppleアカウント情報を削除する
+	Delete(ctx context.Context, provider, providerAccountID string) error
+}
+
+// MicrosoftAccountRepository はMicrosoftアカウントのリポジトリインターフェース
+type MicrosoftAccountRepository interface {
+	// Create は新しいMicrosoftアカウント情報を作成する
+	Create(ctx context.Context, account *model.MicrosoftAccount) error
+	// FindByProviderAccountID はプロバイダーアカウントIDで検索する（存在しない場合はErrAccountNotFound）
+	FindByProviderAccountID(ctx context.Context, provider, providerAccountID string) (*model.MicrosoftAccount, error)
+	// FindByUserID はユーザーIDで検索する（存在しない場合はErrAccountNotFound）
+	FindByUserID(ctx context.Context, userID string) (*model.MicrosoftAccount, error)
+	// Update はMicrosoftアカウント情報を更新する
+	Update(ctx context.Context, account *model.MicrosoftAccount) error
+	// Delete はMicrosoftアカウント情報を削除する
+	Delete(ctx context.Context, provider, providerAccountID string) error
+}
+
+// SAMLAccountRepository はSAMLアカウントのリポジトリインターフェース
+type SAMLAccountRepository interface {
+	// Create は新しいSAMLアカウント情報を作成する
+	Create(ctx context.Context, account *model.SAMLAccount) error
+	// FindByNameID はIdPのエンティティIDとNameIDで検索する（存在しない場合はErrAccountNotFound）
+	FindByNameID(ctx context.Context, idpEntityID, nameID string) (*model.SAMLAccount, error)
+	// Touch は最終ログイン日時（updated_at）を更新する
+	Touch(ctx context.Context, idpEntityID, nameID string, at time.Time) error
+}
+
+// SAMLRequestRepository はIdPに送ったAuthnRequestのリポジトリインターフェース
+type SAMLRequestRepository interface {
+	// Create は新しいAuthnRequestを保存する（有効期限を過ぎたAuthnRequestは削除する）
+	Create(ctx context.Context, request *model.SAMLRequest) error
+	// Consume はRelayStateのハッシュでAuthnRequestを検索して削除する（存在しない・有効期限切れの場合はErrNotFound）
+	Consume(ctx context.Context, relayStateHash string, now time.Time) (*model.SAMLRequest, error)
+}
@@ -0,0 +1,15 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/sikigasa/github-task-controller/backend/internal/domain/model"
+)
+
+// ActivityRepository はプロジェクトのアクティビティログのリポジトリインターフェース
+type ActivityRepository interface {
+	// Create は新しいアクティビティを記録する
+	Create(ctx context.Context, activity *model.Activity) error
+	// FindByProjectID はプロジェクトIDでアクティビティを新しい順に検索する（opts.Limit・opts.Offsetでページングする）
+	FindByProjectID(ctx context.Context, projectID string, opts model.ListOptions) ([]*model.Activity, error)
+}
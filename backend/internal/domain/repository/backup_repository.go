@@ -0,0 +1,18 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+)
+
+// BackupRepository はデータベースの論理バックアップ・復元のリポジトリインターフェース
+// 行レベルセキュリティのテナントに関係なくすべての行を対象にする
+type BackupRepository interface {
+	// Tables はバックアップ対象のテーブルを外部キーの参照先が先になる順で返す
+	Tables() []string
+	// Dump は同じスナップショットからすべてのテーブルの行をJSONで順にfnに渡す
+	Dump(ctx context.Context, fn func(table string, row json.RawMessage) error) error
+	// Restore は1つのトランザクションですべてのテーブルを空にし、readがinsertに渡した行を書き込む
+	// readまたは書き込みが失敗した場合はロールバックして元の状態のままにする
+	Restore(ctx context.Context, read func(insert func(table string, row json.RawMessage) error) error) error
+}
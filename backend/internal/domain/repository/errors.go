@@ -0,0 +1,18 @@
+package repository
+
+import (
+	"fmt"
+
+	"github.com/sikigasa/github-task-controller/backend/internal/domain/model"
+)
+
+// ErrUserNotFound はユーザーが存在しない場合のエラー（model.ErrNotFoundとしても判定できる）
+var ErrUserNotFound = fmt.Errorf("user not found: %w", model.ErrNotFound)
+
+// ErrAccountNotFound は外部プロバイダー（Google・GitHub・Apple・Microsoft・SAML）のアカウントが存在しない場合のエラー
+// model.ErrNotFoundとしても判定できる
+var ErrAccountNotFound = fmt.Errorf("account not found: %w", model.ErrNotFound)
+
+// ErrEmailTaken はメールアドレス（大文字・小文字は区別しない）が他のユーザーに使われている場合のエラー
+// 同時に同じメールアドレスのユーザーを作成した場合も返す（model.ErrConflictとしても判定できる）
+var ErrEmailTaken = fmt.Errorf("email is already in use: %w", model.ErrConflict)
@@ -0,0 +1,17 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/sikigasa/github-task-controller/backend/internal/domain/model"
+)
+
+// GithubFieldMappingRepository はプロジェクトのGitHub Projectsのフィールドの対応付けのリポジトリインターフェース
+type GithubFieldMappingRepository interface {
+	// FindByProjectID はプロジェクトの対応付けを検索する（未設定の場合はErrNotFound）
+	FindByProjectID(ctx context.Context, projectID string) (*model.GithubFieldMapping, error)
+	// Upsert は対応付けを作成または更新する
+	Upsert(ctx context.Context, mapping *model.GithubFieldMapping) error
+	// Delete はプロジェクトの対応付けを削除する（既定の対応付けに戻す）
+	Delete(ctx context.Context, projectID string) error
+}
@@ -0,0 +1,28 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/sikigasa/github-task-controller/backend/internal/domain/model"
+)
+
+// GithubSyncOperationRepository はGitHubに障害がある間に保留したGitHubへの変更のキューのリポジトリインターフェース
+type GithubSyncOperationRepository interface {
+	// Enqueue は変更をキューに追加する（同じタスクの同じ種類の変更が既にある場合は何もしない）
+	Enqueue(ctx context.Context, operation *model.GithubSyncOperation) error
+	// ExistsByTaskID はタスクの保留中の変更があるかを返す
+	ExistsByTaskID(ctx context.Context, taskID string) (bool, error)
+	// FindByUserID はユーザーの保留中の変更を作成順に検索する
+	FindByUserID(ctx context.Context, userID string) ([]*model.GithubSyncOperation, error)
+	// CountByUserID はユーザーの保留中の変更の件数を返す
+	CountByUserID(ctx context.Context, userID string) (int, error)
+	// ClaimDue はタスクごとに最も古い変更のうち再実行の時刻を迎えたものを最大limit件取得し、lease後まで他のワーカーが取得しないようにする
+	ClaimDue(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*model.GithubSyncOperation, error)
+	// Delete は再実行を終えた変更を削除する
+	Delete(ctx context.Context, id string) error
+	// MarkFailed は再実行の失敗を記録し、次に再実行する時刻を設定する
+	MarkFailed(ctx context.Context, id string, attempts int, lastError string, nextAttemptAt time.Time) error
+	// RetryByUserID はユーザーの保留中の変更の再実行の時刻をatに早める（GitHubへの接続が戻った時に使う）
+	RetryByUserID(ctx context.Context, userID string, at time.Time) error
+}
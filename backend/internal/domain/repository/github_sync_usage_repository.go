@@ -0,0 +1,16 @@
+package repository
+
+import (
+	"context"
+	"time"
+)
+
+// GithubSyncUsageRepository はユーザーごとの1時間あたりのGitHubとの同期の回数のリポジトリインターフェース
+type GithubSyncUsageRepository interface {
+	// Consume はwindowStartからの1時間の同期の回数を1回増やし、増やした後の回数を返す
+	// 回数が既にlimitに達している場合は増やさずにfalseを返す（limitが0以下の場合は上限なし）
+	// windowStartより前の時間の回数は削除する
+	Consume(ctx context.Context, userID string, windowStart time.Time, limit int) (int, bool, error)
+	// FindCount はwindowStartからの1時間の同期の回数を返す（同期していない場合は0）
+	FindCount(ctx context.Context, userID string, windowStart time.Time) (int, error)
+}
@@ -0,0 +1,17 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/sikigasa/github-task-controller/backend/internal/domain/model"
+)
+
+// GithubTaskSyncRepository はタスクとGitHub ProjectのItemを最後に一致させた時点のリポジトリインターフェース
+type GithubTaskSyncRepository interface {
+	// FindByTaskID はタスクの同期の基準を検索する（双方向の同期をしたことがない場合はErrNotFound）
+	FindByTaskID(ctx context.Context, taskID string) (*model.GithubTaskSync, error)
+	// FindByProjectID はプロジェクトのタスクの同期の基準をタスクIDごとに返す
+	FindByProjectID(ctx context.Context, projectID string) (map[string]*model.GithubTaskSync, error)
+	// Upsert はタスクの同期の基準を作成または更新する（RemoteUpdatedAtがnilの場合は記録済みの値を残す）
+	Upsert(ctx context.Context, sync *model.GithubTaskSync) error
+}
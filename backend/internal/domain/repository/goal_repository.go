@@ -0,0 +1,29 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/sikigasa/github-task-controller/backend/internal/domain/model"
+)
+
+// GoalRepository は目標のリポジトリインターフェース
+type GoalRepository interface {
+	// Create は新しい目標を作成する
+	Create(ctx context.Context, goal *model.Goal) error
+	// FindByID はIDで目標を検索する
+	FindByID(ctx context.Context, id string) (*model.Goal, error)
+	// FindByProjectID はプロジェクトの目標を検索する（quarterが空の場合は全四半期）
+	FindByProjectID(ctx context.Context, projectID, quarter string) ([]*model.Goal, error)
+	// Update は目標情報を更新する
+	Update(ctx context.Context, goal *model.Goal) error
+	// Delete は目標を削除する
+	Delete(ctx context.Context, id string) error
+	// AddTask は貢献タスクを紐づける（既に紐づいている場合は何もしない）
+	AddTask(ctx context.Context, goalID, taskID string) error
+	// RemoveTask は貢献タスクの紐づけを解除する
+	RemoveTask(ctx context.Context, goalID, taskID string) error
+	// FindTaskIDs は目標の貢献タスクのIDを検索する
+	FindTaskIDs(ctx context.Context, goalID string) ([]string, error)
+	// ProgressByIDs は複数の目標の進捗をまとめて集計する
+	ProgressByIDs(ctx context.Context, ids []string) (map[string]model.GoalProgress, error)
+}
@@ -0,0 +1,27 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/sikigasa/github-task-controller/backend/internal/domain/model"
+)
+
+// GroupRepository はプロビジョニングされたグループのリポジトリインターフェース
+type GroupRepository interface {
+	// Create は新しいグループをメンバーとあわせて作成する
+	Create(ctx context.Context, group *model.Group) error
+	// FindByID はIDでグループをメンバーとあわせて検索する
+	FindByID(ctx context.Context, id string) (*model.Group, error)
+	// List はグループを作成日時の順に検索し、条件に一致する総件数とあわせて返す（displayNameが空の場合は全件）
+	List(ctx context.Context, displayName string, offset, limit int) ([]*model.Group, int, error)
+	// Update はグループの表示名・外部IDを更新する
+	Update(ctx context.Context, group *model.Group) error
+	// ReplaceMembers はグループのメンバーを置き換える
+	ReplaceMembers(ctx context.Context, groupID string, userIDs []string) error
+	// AddMembers はグループにメンバーを追加する（追加済みのユーザーは無視する）
+	AddMembers(ctx context.Context, groupID string, userIDs []string) error
+	// RemoveMembers はグループからメンバーを削除する
+	RemoveMembers(ctx context.Context, groupID string, userIDs []string) error
+	// Delete はグループを削除する
+	Delete(ctx context.Context, id string) error
+}
@@ -0,0 +1,16 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/sikigasa/github-task-controller/backend/internal/domain/model"
+)
+
+// GuestUserRepository はデモモードのゲストユーザーのリポジトリインターフェース
+type GuestUserRepository interface {
+	// Create はゲストユーザーの有効期限を記録する
+	Create(ctx context.Context, guest *model.GuestUser) error
+	// DeleteExpired は有効期限を過ぎたゲストユーザーをデータごと削除し、削除した件数を返す
+	DeleteExpired(ctx context.Context, now time.Time) (int64, error)
+}
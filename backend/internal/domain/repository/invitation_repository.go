@@ -0,0 +1,24 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/sikigasa/github-task-controller/backend/internal/domain/model"
+)
+
+// InvitationRepository は新規登録の招待のリポジトリインターフェース
+type InvitationRepository interface {
+	// Upsert は招待を作成する（同じメールアドレスの招待がある場合は招待者・有効期限を更新して未使用に戻す）
+	Upsert(ctx context.Context, invitation *model.Invitation) error
+	// FindByID はIDで招待を検索する
+	FindByID(ctx context.Context, id string) (*model.Invitation, error)
+	// FindByEmail はメールアドレスで招待を検索する
+	FindByEmail(ctx context.Context, email string) (*model.Invitation, error)
+	// FindByInviter はユーザーが作成した招待を新しい順に検索する
+	FindByInviter(ctx context.Context, userID string) ([]*model.Invitation, error)
+	// MarkAccepted は招待を使用済みにする
+	MarkAccepted(ctx context.Context, id string, at time.Time) error
+	// Delete は招待を削除する
+	Delete(ctx context.Context, id string) error
+}
@@ -0,0 +1,13 @@
+package repository
+
+import "context"
+
+// Locker はサーバーの複数のインスタンスの間で処理を排他するロック
+// 同じキーのロックは全インスタンスで同時に1つしか取得できない
+type Locker interface {
+	// TryLock はキーのロックを待たずに取得する（他で取得済みの場合はacquiredがfalse）
+	// 取得した場合はunlockを必ず呼び出して解放する
+	TryLock(ctx context.Context, key string) (unlock func(), acquired bool, err error)
+	// Lock はキーのロックを取得できるまで待つ（ctxが終了した場合はctxのエラー）
+	Lock(ctx context.Context, key string) (unlock func(), err error)
+}
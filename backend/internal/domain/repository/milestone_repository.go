@@ -0,0 +1,23 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/sikigasa/github-task-controller/backend/internal/domain/model"
+)
+
+// MilestoneRepository はマイルストーンのリポジトリインターフェース
+type MilestoneRepository interface {
+	// Create は新しいマイルストーンを作成する
+	Create(ctx context.Context, milestone *model.Milestone) error
+	// FindByID はIDでマイルストーンを検索する
+	FindByID(ctx context.Context, id string) (*model.Milestone, error)
+	// FindByProjectID はプロジェクトIDで全マイルストーンを期日順に検索する
+	FindByProjectID(ctx context.Context, projectID string) ([]*model.Milestone, error)
+	// Update はマイルストーン情報を更新する
+	Update(ctx context.Context, milestone *model.Milestone) error
+	// Delete はマイルストーンを削除する（割り当て済みタスクは未割り当てになる）
+	Delete(ctx context.Context, id string) error
+	// ProgressByIDs は複数マイルストーンの進捗をまとめて取得する
+	ProgressByIDs(ctx context.Context, ids []string) (map[string]model.MilestoneProgress, error)
+}
@@ -0,0 +1,23 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/sikigasa/github-task-controller/backend/internal/domain/model"
+)
+
+// OutboxRepository はドメインイベントのアウトボックスのリポジトリインターフェース
+type OutboxRepository interface {
+	// Append はイベントを追加する（変更と同じTransactor.WithinTxのトランザクション内で呼び出す）
+	Append(ctx context.Context, event *model.OutboxEvent) error
+	// ClaimDue は配信時刻を迎えた配信待ちのイベントを発生順に最大limit件取得し、lease後まで他のワーカーが取得しないようにする
+	// 同じ集約に先に発生した配信待ちのイベントがある場合は、発生順を守るためそのイベントを取得しない
+	ClaimDue(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*model.OutboxEvent, error)
+	// MarkPublished はイベントを配信済みにする
+	MarkPublished(ctx context.Context, id string, at time.Time) error
+	// MarkFailed はイベントの配信の失敗を記録する（deadがtrueの場合はデッドレターにし、falseの場合はnextAttemptAtに再試行する）
+	MarkFailed(ctx context.Context, id string, attempts int, lastError string, nextAttemptAt time.Time, dead bool) error
+	// DeletePublishedBefore はbeforeより前に配信済みになったイベントを削除し、削除した件数を返す
+	DeletePublishedBefore(ctx context.Context, before time.Time) (int64, error)
+}
@@ -2,20 +2,32 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"github.com/sikigasa/github-task-controller/backend/internal/domain/model"
 )
 
 // ProjectRepository はプロジェクトのリポジトリインターフェース
 type ProjectRepository interface {
-	// Create は新しいプロジェクトを作成する
+	// Create は新しいプロジェクトを作成する（同じユーザーに同じタイトルのプロジェクトがある場合はErrConflict）
 	Create(ctx context.Context, project *model.Project) error
 	// FindByID はIDでプロジェクトを検索する
 	FindByID(ctx context.Context, id string) (*model.Project, error)
-	// FindByUserID はユーザーIDで全プロジェクトを検索する
-	FindByUserID(ctx context.Context, userID string) ([]*model.Project, error)
-	// Update はプロジェクト情報を更新する
+	// FindByUserID はユーザーIDで全プロジェクトをoptsのソート順で検索する
+	FindByUserID(ctx context.Context, userID string, opts model.ListOptions) ([]*model.Project, error)
+	// FindGithubLinked は全ユーザーのGitHub Projectと連携したプロジェクトを作成順に検索する
+	FindGithubLinked(ctx context.Context) ([]*model.Project, error)
+	// Update はproject.Versionのプロジェクト情報を更新し、project.Versionを更新後のバージョンにする
+	// バージョンが異なる場合はErrStaleVersion、同じユーザーに同じタイトルのプロジェクトがある場合はErrConflict
 	Update(ctx context.Context, project *model.Project) error
-	// Delete はプロジェクトを削除する
-	Delete(ctx context.Context, id string) error
+	// Delete はプロジェクトとそのタスクをゴミ箱に移す（タスクにはプロジェクトと同じ削除日時を記録する）
+	Delete(ctx context.Context, id string, deletedAt time.Time) error
+	// FindDeletedByID はゴミ箱のプロジェクトを検索する（ゴミ箱にない場合はErrNotFound）
+	FindDeletedByID(ctx context.Context, id string) (*model.Project, error)
+	// FindDeletedByUserID はユーザーのゴミ箱のプロジェクトを削除日時の新しい順に検索する
+	FindDeletedByUserID(ctx context.Context, userID string) ([]*model.Project, error)
+	// Restore はゴミ箱のプロジェクトと、プロジェクトと一緒にゴミ箱に移したタスクを元に戻す
+	Restore(ctx context.Context, project *model.Project) error
+	// PurgeDeleted はbefore以前にゴミ箱に移したプロジェクトをタスクごと完全に削除し、削除した件数を返す
+	PurgeDeleted(ctx context.Context, before time.Time) (int64, error)
 }
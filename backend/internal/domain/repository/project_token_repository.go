@@ -0,0 +1,22 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/sikigasa/github-task-controller/backend/internal/domain/model"
+)
+
+// ProjectTokenRepository はプロジェクト単位のAPIトークンのリポジトリインターフェース
+type ProjectTokenRepository interface {
+	// Create は新しいトークンを保存する
+	Create(ctx context.Context, token *model.ProjectToken) error
+	// FindByHash はトークンのハッシュで検索する（存在しない場合はErrNotFound）
+	FindByHash(ctx context.Context, tokenHash string) (*model.ProjectToken, error)
+	// FindByProjectID はプロジェクトのトークンを発行順に検索する
+	FindByProjectID(ctx context.Context, projectID string) ([]*model.ProjectToken, error)
+	// Delete はプロジェクトのトークンを削除（失効）する（存在しない場合はErrNotFound）
+	Delete(ctx context.Context, projectID, id string) error
+	// TouchLastUsed はトークンの最終使用日時を記録する
+	TouchLastUsed(ctx context.Context, id string, at time.Time) error
+}
@@ -0,0 +1,19 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/sikigasa/github-task-controller/backend/internal/domain/model"
+)
+
+// PushDeviceRepository はプッシュ通知を受け取る端末のリポジトリインターフェース
+type PushDeviceRepository interface {
+	// Upsert は端末を登録する（ユーザーが同じトークンを登録済みの場合は配信サービス・名前・更新日時を更新し、登録済みの端末を返す）
+	Upsert(ctx context.Context, device *model.PushDevice) (*model.PushDevice, error)
+	// FindByUserID はユーザーの端末を登録順に検索する
+	FindByUserID(ctx context.Context, userID string) ([]*model.PushDevice, error)
+	// FindDeliverableByUserID はユーザーの端末のうち、同じトークンを後から別のユーザーが登録していないものを検索する
+	FindDeliverableByUserID(ctx context.Context, userID string) ([]*model.PushDevice, error)
+	// Delete はユーザーの端末を削除する
+	Delete(ctx context.Context, userID, id string) error
+}
@@ -0,0 +1,18 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/sikigasa/github-task-controller/backend/internal/domain/model"
+)
+
+// RefreshTokenRepository はリフレッシュトークンのリポジトリインターフェース
+type RefreshTokenRepository interface {
+	// Create は新しいリフレッシュトークンを保存する
+	Create(ctx context.Context, token *model.RefreshToken) error
+	// FindByHash はトークンのハッシュでリフレッシュトークンを検索する
+	FindByHash(ctx context.Context, tokenHash string) (*model.RefreshToken, error)
+	// MarkUsed は未使用のリフレッシュトークンを使用済みにする（使用済みの場合はErrNotFound）
+	MarkUsed(ctx context.Context, id string, usedAt time.Time) error
+}
@@ -0,0 +1,26 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/sikigasa/github-task-controller/backend/internal/domain/model"
+)
+
+// ReportExportRepository はレポートのエクスポートのリポジトリインターフェース
+type ReportExportRepository interface {
+	// Create は新しいエクスポートを保存する
+	Create(ctx context.Context, export *model.ReportExport) error
+	// FindByID はIDでエクスポートを検索する（ファイルの内容は含まない）
+	FindByID(ctx context.Context, id string) (*model.ReportExport, error)
+	// FindPendingIDs は未処理のエクスポートのIDを作成順に検索する
+	FindPendingIDs(ctx context.Context) ([]string, error)
+	// FindContent はデータベースに保存したファイルの内容を取得する（ファイルをStorageに保存する前に完了したエクスポートのみ）
+	FindContent(ctx context.Context, id string) ([]byte, error)
+	// Complete はStorageに保存したファイルの情報を記録してエクスポートを完了にする
+	Complete(ctx context.Context, id, fileName, contentType string, completedAt time.Time) error
+	// Fail はエクスポートを失敗にする
+	Fail(ctx context.Context, id, message string, completedAt time.Time) error
+	// DeleteFinishedBefore はbeforeより前に完了・失敗したエクスポートを削除して削除した件数を返す
+	DeleteFinishedBefore(ctx context.Context, before time.Time) (int64, error)
+}
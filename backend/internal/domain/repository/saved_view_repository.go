@@ -0,0 +1,19 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/sikigasa/github-task-controller/backend/internal/domain/model"
+)
+
+// SavedViewRepository は保存済みビューのリポジトリインターフェース
+type SavedViewRepository interface {
+	// Create は新しいビューを保存する
+	Create(ctx context.Context, view *model.SavedView) error
+	// FindByID はIDでビューを検索する
+	FindByID(ctx context.Context, id string) (*model.SavedView, error)
+	// FindByProjectID はユーザーがプロジェクトに保存したビューを作成順に検索する
+	FindByProjectID(ctx context.Context, projectID, userID string) ([]*model.SavedView, error)
+	// Delete はビューを削除する
+	Delete(ctx context.Context, id string) error
+}
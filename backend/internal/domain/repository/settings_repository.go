@@ -0,0 +1,24 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/sikigasa/github-task-controller/backend/internal/domain/model"
+)
+
+// SettingsRepository はユーザー設定のリポジトリインターフェース
+type SettingsRepository interface {
+	// FindByUserID はユーザーの設定を検索する（保存されていない場合はErrNotFound）
+	FindByUserID(ctx context.Context, userID string) (*model.Settings, error)
+	// Upsert は設定を作成または更新する（LastDigestSentAt・LastGithubImportAtは更新しない）
+	Upsert(ctx context.Context, settings *model.Settings) error
+	// FindDigestSubscribers は週次ダイジェストの配信を希望するユーザーの設定を検索する
+	FindDigestSubscribers(ctx context.Context) ([]*model.Settings, error)
+	// MarkDigestSent は週次ダイジェストの配信日時を記録する
+	MarkDigestSent(ctx context.Context, userID string, sentAt time.Time) error
+	// FindGithubImportSubscribers は担当のGitHub Issueの取り込み先プロジェクトを設定したユーザーの設定を検索する
+	FindGithubImportSubscribers(ctx context.Context) ([]*model.Settings, error)
+	// MarkGithubImported は担当のGitHub Issueを取り込んだ日時を記録する
+	MarkGithubImported(ctx context.Context, userID string, importedAt time.Time) error
+}
@@ -0,0 +1,18 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/sikigasa/github-task-controller/backend/internal/domain/model"
+)
+
+// TaskCommitRepository はタスクを参照しているコミットのリポジトリインターフェース
+type TaskCommitRepository interface {
+	// Create はコミットを記録する（既に記録済みの場合は何もせずfalseを返す）
+	Create(ctx context.Context, commit *model.TaskCommit) (bool, error)
+	// FindByTaskID はタスクを参照しているコミットをコミット日時の新しい順に検索する
+	FindByTaskID(ctx context.Context, taskID string) ([]*model.TaskCommit, error)
+	// LatestCommittedAt はプロジェクトで記録済みのコミットの最新のコミット日時を取得する（記録がない場合はnil）
+	LatestCommittedAt(ctx context.Context, projectID string) (*time.Time, error)
+}
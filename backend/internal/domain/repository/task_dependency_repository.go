@@ -0,0 +1,17 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/sikigasa/github-task-controller/backend/internal/domain/model"
+)
+
+// TaskDependencyRepository はタスクの依存関係のリポジトリインターフェース
+type TaskDependencyRepository interface {
+	// Create は依存関係を追加する（既に存在する場合は何もしない）
+	Create(ctx context.Context, dep *model.TaskDependency) error
+	// Delete は依存関係を削除する
+	Delete(ctx context.Context, taskID, dependsOnTaskID string) error
+	// FindByProjectID はプロジェクト内の全依存関係を検索する
+	FindByProjectID(ctx context.Context, projectID string) ([]*model.TaskDependency, error)
+}
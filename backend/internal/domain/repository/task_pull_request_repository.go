@@ -0,0 +1,15 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/sikigasa/github-task-controller/backend/internal/domain/model"
+)
+
+// TaskPullRequestRepository はタスクに紐づくPull Requestのリポジトリインターフェース
+type TaskPullRequestRepository interface {
+	// ReplaceForTask はタスクに紐づくPull Requestを置き換える
+	ReplaceForTask(ctx context.Context, taskID string, pullRequests []*model.TaskPullRequest) error
+	// FindByTaskID はタスクに紐づくPull Requestを更新日時の新しい順に検索する
+	FindByTaskID(ctx context.Context, taskID string) ([]*model.TaskPullRequest, error)
+}
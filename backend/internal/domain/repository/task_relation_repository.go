@@ -0,0 +1,17 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/sikigasa/github-task-controller/backend/internal/domain/model"
+)
+
+// TaskRelationRepository はタスク間の関連のリポジトリインターフェース
+type TaskRelationRepository interface {
+	// Create は関連を追加する（既に存在する場合は何もしない）
+	Create(ctx context.Context, relation *model.TaskRelation) error
+	// Delete は正規化済みの関連を削除する
+	Delete(ctx context.Context, relation *model.TaskRelation) error
+	// FindLinksByTaskID はタスクを両端のいずれかに含む関連を、そのタスクから見た形で検索する
+	FindLinksByTaskID(ctx context.Context, taskID string) ([]*model.TaskRelationLink, error)
+}
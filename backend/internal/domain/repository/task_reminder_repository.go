@@ -0,0 +1,17 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/sikigasa/github-task-controller/backend/internal/domain/model"
+)
+
+// TaskReminderRepository はタスクのリマインダーの送信記録のリポジトリインターフェース
+type TaskReminderRepository interface {
+	// Claim は送信するリマインダーを記録し、同じタスク・通知先・終了日のリマインダーを記録済みの場合はfalseを返す
+	// 送信の前に記録し、重複して送信しないようにする
+	Claim(ctx context.Context, reminder *model.TaskReminder) (bool, error)
+	// Release は送信に失敗したリマインダーの記録を削除する（次の実行で改めて送信する）
+	Release(ctx context.Context, taskID string, channel model.ReminderChannel, dueDate time.Time) error
+}
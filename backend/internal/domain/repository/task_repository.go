@@ -2,20 +2,51 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"github.com/sikigasa/github-task-controller/backend/internal/domain/model"
 )
 
 // TaskRepository はタスクのリポジトリインターフェース
 type TaskRepository interface {
-	// Create は新しいタスクを作成する
+	// Create は新しいタスクを作成する（同じプロジェクトに同じGitHubのItemのタスクがある場合はErrConflict）
 	Create(ctx context.Context, task *model.Task) error
 	// FindByID はIDでタスクを検索する
 	FindByID(ctx context.Context, id string) (*model.Task, error)
-	// FindByProjectID はプロジェクトIDで全タスクを検索する
-	FindByProjectID(ctx context.Context, projectID string) ([]*model.Task, error)
-	// Update はタスク情報を更新する
+	// FindByProjectID はプロジェクトIDでfilterに一致するタスクをoptsのソート順・範囲（Limit・Offset）で検索する
+	FindByProjectID(ctx context.Context, projectID string, filter model.TaskFilter, opts model.ListOptions) ([]*model.Task, error)
+	// FindByParentID は親タスクのサブタスクを作成順に検索する
+	FindByParentID(ctx context.Context, parentID string) ([]*model.Task, error)
+	// FindOwnerID はタスクが属するプロジェクトの所有者のユーザーIDを検索する
+	FindOwnerID(ctx context.Context, id string) (string, error)
+	// FindByGithubIssueURL はプロジェクト内でGitHub IssueのURLが一致するタスクを検索する
+	FindByGithubIssueURL(ctx context.Context, projectID, issueURL string) (*model.Task, error)
+	// FindByGithubLink はGitHub ProjectのItem IDまたはIssueのURLが一致するタスクをすべてのプロジェクトから検索する（空の条件は無視する）
+	FindByGithubLink(ctx context.Context, itemID, issueURL string) ([]*model.Task, error)
+	// FindByGithubIssue はIssueの番号とURL（大文字・小文字を区別しない）が一致するタスクをすべてのプロジェクトから作成順に検索する
+	FindByGithubIssue(ctx context.Context, number int, issueURL string) ([]*model.Task, error)
+	// FindByIDs は複数IDのタスクをまとめて検索する（存在しないIDは結果に含まれない）
+	FindByIDs(ctx context.Context, ids []string) ([]*model.Task, error)
+	// FindByProjectIDs は複数プロジェクトのタスクをまとめて検索する
+	FindByProjectIDs(ctx context.Context, projectIDs []string) ([]*model.Task, error)
+	// FindDueByProjectIDs は複数プロジェクトの未完了かつ終了日がbefore以前のタスクを終了日順に検索する
+	FindDueByProjectIDs(ctx context.Context, projectIDs []string, before time.Time) ([]*model.Task, error)
+	// FindDueBetween はすべてのプロジェクトの未完了かつ終了日がfrom以降before未満のタスクを終了日順に検索する
+	FindDueBetween(ctx context.Context, from, before time.Time) ([]*model.Task, error)
+	// CountByProjectIDs は複数プロジェクトのタスク集計をプロジェクトIDごとに取得する
+	// 終了日がoverdueBeforeより前の未完了タスクを期限切れとして数える
+	CountByProjectIDs(ctx context.Context, projectIDs []string, overdueBefore time.Time) (map[string]*model.ProjectStats, error)
+	// Update はtask.Versionのタスク情報を更新し、task.Versionを更新後のバージョンにする
+	// バージョンが異なる場合はErrStaleVersion、同じプロジェクトに同じGitHubのItemのタスクがある場合はErrConflict
 	Update(ctx context.Context, task *model.Task) error
-	// Delete はタスクを削除する
-	Delete(ctx context.Context, id string) error
+	// Delete はタスクをゴミ箱に移す（削除日時を記録し、ゴミ箱以外の検索の対象から外す）
+	Delete(ctx context.Context, id string, deletedAt time.Time) error
+	// FindDeletedByID はゴミ箱のタスクを検索する（ゴミ箱にない場合はErrNotFound）
+	FindDeletedByID(ctx context.Context, id string) (*model.Task, error)
+	// FindDeletedByProjectIDs は複数プロジェクトのゴミ箱のタスクを削除日時の新しい順に検索する
+	FindDeletedByProjectIDs(ctx context.Context, projectIDs []string) ([]*model.Task, error)
+	// Restore はゴミ箱のタスクを元に戻す（親タスクと更新日時はtaskの値にする）
+	Restore(ctx context.Context, task *model.Task) error
+	// PurgeDeleted はbefore以前にゴミ箱に移したタスクを完全に削除し、削除した件数を返す
+	PurgeDeleted(ctx context.Context, before time.Time) (int64, error)
 }
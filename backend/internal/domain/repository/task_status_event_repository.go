@@ -0,0 +1,22 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/sikigasa/github-task-controller/backend/internal/domain/model"
+)
+
+// TaskStatusEventRepository はタスクのステータス遷移イベントのリポジトリインターフェース
+type TaskStatusEventRepository interface {
+	// Create は新しいステータス遷移イベントを記録する
+	Create(ctx context.Context, event *model.TaskStatusEvent) error
+	// FindByTaskID はタスクIDでイベントを発生順に検索する
+	FindByTaskID(ctx context.Context, taskID string) ([]*model.TaskStatusEvent, error)
+	// FindByProjectID はプロジェクトIDでイベントを発生順に検索する
+	FindByProjectID(ctx context.Context, projectID string) ([]*model.TaskStatusEvent, error)
+	// FindRecentByProjectIDs は複数プロジェクトの直近のイベントを新しい順にlimit件検索する
+	FindRecentByProjectIDs(ctx context.Context, projectIDs []string, limit int) ([]*model.TaskStatusEvent, error)
+	// FindByProjectIDsSince は複数プロジェクトのsince以降のイベントを発生順に検索する
+	FindByProjectIDsSince(ctx context.Context, projectIDs []string, since time.Time) ([]*model.TaskStatusEvent, error)
+}
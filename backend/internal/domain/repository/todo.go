@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"github.com/sikigasa/github-task-controller/backend/internal/domain/model"
 )
@@ -14,12 +15,24 @@ type TodoRepository interface {
 	// FindByID はIDでTODOを取得する
 	FindByID(ctx context.Context, id string) (*model.Todo, error)
 
-	// FindAll はすべてのTODOを取得する
-	FindAll(ctx context.Context) ([]*model.Todo, error)
+	// FindByUserID はユーザーのすべてのTODOをoptsのソート順で取得する
+	FindByUserID(ctx context.Context, userID string, opts model.ListOptions) ([]*model.Todo, error)
 
 	// Update はTODOを更新する
 	Update(ctx context.Context, todo *model.Todo) error
 
-	// Delete はTODOを削除する
-	Delete(ctx context.Context, id string) error
+	// Delete はTODOをゴミ箱に移す
+	Delete(ctx context.Context, id string, deletedAt time.Time) error
+
+	// FindDeletedByID はゴミ箱のTODOをIDで取得する（ゴミ箱にない場合はErrNotFound）
+	FindDeletedByID(ctx context.Context, id string) (*model.Todo, error)
+
+	// FindDeletedByUserID はユーザーのゴミ箱のTODOを削除日時の新しい順に取得する
+	FindDeletedByUserID(ctx context.Context, userID string) ([]*model.Todo, error)
+
+	// Restore はゴミ箱のTODOを元に戻す
+	Restore(ctx context.Context, todo *model.Todo) error
+
+	// PurgeDeleted はbefore以前にゴミ箱に移したTODOを完全に削除し、削除した件数を返す
+	PurgeDeleted(ctx context.Context, before time.Time) (int64, error)
 }
@@ -0,0 +1,10 @@
+package repository
+
+import "context"
+
+// Transactor は複数のリポジトリの操作を1つのトランザクションで実行する
+type Transactor interface {
+	// WithinTx はfnをトランザクション内で実行し、fnがエラーを返した場合はロールバックする
+	// fnに渡されたコンテキストで呼び出したリポジトリの操作はすべて同じトランザクションで実行される
+	WithinTx(ctx context.Context, fn func(ctx context.Context) error) error
+}
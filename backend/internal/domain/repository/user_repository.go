@@ -8,13 +8,15 @@ import (
 
 // UserRepository はユーザーのリポジトリインターフェース
 type UserRepository interface {
-	// Create は新しいユーザーを作成する
+	// Create は新しいユーザーを作成する（メールアドレスが使われている場合はErrEmailTaken）
 	Create(ctx context.Context, user *model.User) error
-	// FindByID はIDでユーザーを検索する
+	// FindByID はIDでユーザーを検索する（存在しない場合はErrUserNotFound）
 	FindByID(ctx context.Context, id string) (*model.User, error)
-	// FindByEmail はメールアドレスでユーザーを検索する
+	// FindByEmail はメールアドレスでユーザーを検索する（大文字・小文字は区別しない。存在しない場合はErrUserNotFound）
 	FindByEmail(ctx context.Context, email string) (*model.User, error)
-	// Update はユーザー情報を更新する
+	// List はユーザーを作成日時の順に検索し、条件に一致する総件数とあわせて返す（emailが空の場合は全件）
+	List(ctx context.Context, email string, offset, limit int) ([]*model.User, int, error)
+	// Update はユーザー情報を更新する（メールアドレスが他のユーザーに使われている場合はErrEmailTaken）
 	Update(ctx context.Context, user *model.User) error
 	// Delete はユーザーを削除する
 	Delete(ctx context.Context, id string) error
@@ -0,0 +1,22 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/sikigasa/github-task-controller/backend/internal/domain/model"
+)
+
+// UserSessionRepository はログインセッションの記録のリポジトリインターフェース
+type UserSessionRepository interface {
+	// Create は新しいセッションを記録する
+	Create(ctx context.Context, session *model.UserSession) error
+	// FindActiveByUserID はユーザーの有効期限内のセッションを最終アクティビティの新しい順に検索する
+	FindActiveByUserID(ctx context.Context, userID string, now time.Time) ([]*model.UserSession, error)
+	// Touch は最終アクティビティがstaleBeforeより古い場合にseenAtへ更新する
+	Touch(ctx context.Context, id string, seenAt, staleBefore time.Time) error
+	// Delete はセッションの記録を削除する（リフレッシュトークンとサーバー側のCookieのセッションも無効になる）
+	Delete(ctx context.Context, id string) error
+	// DeleteByUserID はユーザーのすべてのセッションの記録を削除する（リフレッシュトークンとサーバー側のCookieのセッションも無効になる）
+	DeleteByUserID(ctx context.Context, userID string) error
+}
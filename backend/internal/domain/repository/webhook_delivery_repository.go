@@ -0,0 +1,26 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/sikigasa/github-task-controller/backend/internal/domain/model"
+)
+
+// WebhookDeliveryRepository はWebhookの配信のキューのリポジトリインターフェース
+type WebhookDeliveryRepository interface {
+	// Create は配信をキューに追加する（同じIDの配信が既にある場合は何もせずfalseを返す）
+	Create(ctx context.Context, delivery *model.WebhookDelivery) (bool, error)
+	// FindByID はIDで配信を検索する
+	FindByID(ctx context.Context, id string) (*model.WebhookDelivery, error)
+	// FindByStatus は処理状況が一致する配信を新しい順に最大limit件検索する
+	FindByStatus(ctx context.Context, status model.WebhookDeliveryStatus, limit int) ([]*model.WebhookDelivery, error)
+	// ClaimDue は処理時刻を迎えた処理待ちの配信を最大limit件取得し、lease後まで他のワーカーが取得しないようにする
+	ClaimDue(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*model.WebhookDelivery, error)
+	// MarkProcessed は配信を処理済みにする
+	MarkProcessed(ctx context.Context, id string, at time.Time) error
+	// MarkFailed は配信の処理の失敗を記録する（deadがtrueの場合はデッドレターにし、falseの場合はnextAttemptAtに再試行する）
+	MarkFailed(ctx context.Context, id string, attempts int, lastError string, nextAttemptAt time.Time, dead bool) error
+	// Requeue は配信を試行回数を0に戻して処理待ちにする
+	Requeue(ctx context.Context, id string, at time.Time) error
+}
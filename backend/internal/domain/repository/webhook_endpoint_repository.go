@@ -0,0 +1,45 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/sikigasa/github-task-controller/backend/internal/domain/model"
+)
+
+// WebhookEndpointRepository は外部のWebhookの送信先のリポジトリインターフェース
+type WebhookEndpointRepository interface {
+	// Create は送信先を作成する
+	Create(ctx context.Context, endpoint *model.WebhookEndpoint) error
+	// FindByID はIDで送信先を検索する
+	FindByID(ctx context.Context, id string) (*model.WebhookEndpoint, error)
+	// FindByProjectID はプロジェクトの送信先を作成順に検索する
+	FindByProjectID(ctx context.Context, projectID string) ([]*model.WebhookEndpoint, error)
+	// FindEnabledByProjectID はプロジェクトの有効な送信先を検索する
+	FindEnabledByProjectID(ctx context.Context, projectID string) ([]*model.WebhookEndpoint, error)
+	// Update は送信先のURL・イベントの種類・有効かどうか・連続失敗回数を更新する
+	Update(ctx context.Context, endpoint *model.WebhookEndpoint) error
+	// Delete はプロジェクトの送信先を削除する（配信ログも削除される）
+	Delete(ctx context.Context, projectID, id string) error
+	// RecordSuccess は送信の成功を記録し、連続失敗回数を0に戻す
+	RecordSuccess(ctx context.Context, id string) error
+	// RecordFailure は送信の失敗を記録し、連続失敗回数がdisableAfterに達した場合は送信先を無効にする
+	// 今回の失敗で無効にした場合はtrueを返す
+	RecordFailure(ctx context.Context, id string, disableAfter int, at time.Time) (bool, error)
+}
+
+// WebhookEndpointDeliveryRepository は送信先へのイベントの配信（送信のキューと配信ログ）のリポジトリインターフェース
+type WebhookEndpointDeliveryRepository interface {
+	// Create は配信をキューに追加する（同じ送信先・イベントの配信が既にある場合は何もしない）
+	Create(ctx context.Context, delivery *model.WebhookEndpointDelivery) error
+	// FindByEndpointID は送信先の配信を新しい順に最大limit件検索する
+	FindByEndpointID(ctx context.Context, endpointID string, limit int) ([]*model.WebhookEndpointDelivery, error)
+	// ClaimDue は有効な送信先への送信時刻を迎えた配信を最大limit件取得し、lease後まで他のワーカーが取得しないようにする
+	ClaimDue(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*model.WebhookEndpointDelivery, error)
+	// MarkSucceeded は配信を成功にする
+	MarkSucceeded(ctx context.Context, id string, responseStatus int, at time.Time) error
+	// MarkFailed は送信の失敗を記録する（deadがtrueの場合は失敗にし、falseの場合はnextAttemptAtに再試行する）
+	MarkFailed(ctx context.Context, id string, attempts int, responseStatus *int, lastError string, nextAttemptAt time.Time, dead bool) error
+	// DeleteFinishedBefore はbeforeより前に作成された送信済み・失敗の配信を削除し、削除した件数を返す
+	DeleteFinishedBefore(ctx context.Context, before time.Time) (int64, error)
+}
@@ -0,0 +1,326 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/oauth2"
+)
+
+const (
+	appleIssuer  = "https://appleid.apple.com"
+	appleKeysURL = "https://appleid.apple.com/auth/keys"
+	// appleClientSecretTTL はトークン交換ごとに生成するクライアントシークレットの有効期間（上限は6か月）
+	appleClientSecretTTL = 5 * time.Minute
+	// appleKeysCacheTTL はid_tokenの検証に使う公開鍵をキャッシュする期間
+	appleKeysCacheTTL = 24 * time.Hour
+	// applePrivateRelayDomain は「メールを非公開」を選んだユーザーに割り当てられる転送用アドレスのドメイン
+	applePrivateRelayDomain = "privaterelay.appleid.com"
+)
+
+// AppleConfig はSign in with Appleの設定
+type AppleConfig struct {
+	// ClientID はServices ID
+	ClientID string
+	TeamID   string
+	KeyID    string
+	// PrivateKey はApple Developerで発行した.p8ファイル（PEM）の内容
+	PrivateKey  string
+	RedirectURL string
+}
+
+// appleProvider はクライアントシークレットの署名鍵とid_tokenの検証用の公開鍵を保持する
+type appleProvider struct {
+	teamID     string
+	keyID      string
+	privateKey *ecdsa.PrivateKey
+
+	keysMu        sync.Mutex
+	keys          map[string]*rsa.PublicKey
+	keysFetchedAt time.Time
+}
+
+// AppleIDToken はAppleのid_tokenから取得したユーザー情報
+type AppleIDToken struct {
+	Subject        string
+	Email          string
+	EmailVerified  bool
+	IsPrivateEmail bool
+}
+
+// AppleUser はAppleが初回の認可時にのみフォームで送信するユーザー情報
+type AppleUser struct {
+	Name struct {
+		FirstName string `json:"firstName"`
+		LastName  string `json:"lastName"`
+	} `json:"name"`
+	Email string `json:"email"`
+}
+
+// FullName は姓名をつなげた表示名を返す
+func (u *AppleUser) FullName() string {
+	return strings.TrimSpace(u.Name.FirstName + " " + u.Name.LastName)
+}
+
+// EnableApple はSign in with Appleを有効にする
+// 秘密鍵は改行を\nとしてエスケープした1行の値も受け付ける
+func (o *OAuthConfig) EnableApple(cfg AppleConfig) error {
+	block, _ := pem.Decode([]byte(strings.ReplaceAll(cfg.PrivateKey, `\n`, "\n")))
+	if block == nil {
+		return errors.New("failed to decode apple private key: invalid pem")
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return fmt.Errorf("failed to parse apple private key: %w", err)
+	}
+	ecKey, ok := key.(*ecdsa.PrivateKey)
+	if !ok {
+		return errors.New("apple private key is not an ecdsa key")
+	}
+
+	o.AppleConfig = &oauth2.Config{
+		ClientID:    cfg.ClientID,
+		RedirectURL: cfg.RedirectURL,
+		Scopes:      []string{"name", "email"},
+		Endpoint: oauth2.Endpoint{
+			AuthURL:   appleIssuer + "/auth/authorize",
+			TokenURL:  appleIssuer + "/auth/token",
+			AuthStyle: oauth2.AuthStyleInParams,
+		},
+	}
+	o.apple = &appleProvider{
+		teamID:     cfg.TeamID,
+		keyID:      cfg.KeyID,
+		privateKey: ecKey,
+	}
+	return nil
+}
+
+// AppleEnabled はSign in with Appleが有効かどうかを返す
+func (o *OAuthConfig) AppleEnabled() bool {
+	return o.apple != nil
+}
+
+// exchangeApple はES256で署名したクライアントシークレットを生成して認証コードをトークンに交換する
+func (o *OAuthConfig) exchangeApple(ctx context.Context, code string) (*oauth2.Token, error) {
+	if o.apple == nil {
+		return nil, errors.New("apple sign in is not configured")
+	}
+
+	secret, err := o.apple.clientSecret(o.AppleConfig.ClientID, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	cfg := *o.AppleConfig
+	cfg.ClientSecret = secret
+	return cfg.Exchange(ctx, code)
+}
+
+// clientSecret はAppleのトークンエンドポイントに送るクライアントシークレット（ES256のJWT）を生成する
+func (p *appleProvider) clientSecret(clientID string, now time.Time) (string, error) {
+	header, err := json.Marshal(map[string]string{"alg": "ES256", "kid": p.keyID})
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal client secret header: %w", err)
+	}
+	claims, err := json.Marshal(map[string]any{
+		"iss": p.teamID,
+		"iat": now.Unix(),
+		"exp": now.Add(appleClientSecretTTL).Unix(),
+		"aud": appleIssuer,
+		"sub": clientID,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal client secret claims: %w", err)
+	}
+
+	unsigned := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(claims)
+	digest := sha256.Sum256([]byte(unsigned))
+	r, s, err := ecdsa.Sign(rand.Reader, p.privateKey, digest[:])
+	if err != nil {
+		return "", fmt.Errorf("failed to sign client secret: %w", err)
+	}
+
+	// JWSのES256署名はr・sをそれぞれ32バイトに揃えて連結する
+	signature := make([]byte, 64)
+	r.FillBytes(signature[:32])
+	s.FillBytes(signature[32:])
+	return unsigned + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
+
+// VerifyAppleIDToken はトークンレスポンスのid_tokenの署名・発行者・対象・有効期限を検証してユーザー情報を返す
+func (o *OAuthConfig) VerifyAppleIDToken(ctx context.Context, token *oauth2.Token) (*AppleIDToken, error) {
+	if o.apple == nil {
+		return nil, errors.New("apple sign in is not configured")
+	}
+	rawIDToken, ok := token.Extra("id_token").(string)
+	if !ok || rawIDToken == "" {
+		return nil, errors.New("id_token not found in apple token response")
+	}
+
+	parts := strings.Split(rawIDToken, ".")
+	if len(parts) != 3 {
+		return nil, errors.New("malformed apple id_token")
+	}
+
+	var header struct {
+		Alg string `json:"alg"`
+		Kid string `json:"kid"`
+	}
+	if err := decodeJWTSegment(parts[0], &header); err != nil {
+		return nil, fmt.Errorf("failed to decode apple id_token header: %w", err)
+	}
+	if header.Alg != "RS256" {
+		return nil, fmt.Errorf("unsupported apple id_token algorithm: %s", header.Alg)
+	}
+
+	key, err := o.apple.publicKey(ctx, header.Kid)
+	if err != nil {
+		return nil, err
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode apple id_token signature: %w", err)
+	}
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	if err := rsa.VerifyPKCS1v15(key, crypto.SHA256, digest[:], signature); err != nil {
+		return nil, fmt.Errorf("invalid apple id_token signature: %w", err)
+	}
+
+	var claims struct {
+		Issuer         string    `json:"iss"`
+		Audience       string    `json:"aud"`
+		ExpiresAt      int64     `json:"exp"`
+		Subject        string    `json:"sub"`
+		Email          string    `json:"email"`
+		EmailVerified  appleBool `json:"email_verified"`
+		IsPrivateEmail appleBool `json:"is_private_email"`
+	}
+	if err := decodeJWTSegment(parts[1], &claims); err != nil {
+		return nil, fmt.Errorf("failed to decode apple id_token claims: %w", err)
+	}
+	switch {
+	case claims.Issuer != appleIssuer:
+		return nil, fmt.Errorf("unexpected apple id_token issuer: %s", claims.Issuer)
+	case claims.Audience != o.AppleConfig.ClientID:
+		return nil, fmt.Errorf("unexpected apple id_token audience: %s", claims.Audience)
+	case time.Now().Unix() >= claims.ExpiresAt:
+		return nil, errors.New("apple id_token is expired")
+	case claims.Subject == "":
+		return nil, errors.New("apple id_token has no subject")
+	}
+
+	return &AppleIDToken{
+		Subject:        claims.Subject,
+		Email:          claims.Email,
+		EmailVerified:  bool(claims.EmailVerified),
+		IsPrivateEmail: bool(claims.IsPrivateEmail) || strings.HasSuffix(claims.Email, "@"+applePrivateRelayDomain),
+	}, nil
+}
+
+// publicKey はkidに対応するAppleの公開鍵を返す（キャッシュにない場合は取得し直す）
+func (p *appleProvider) publicKey(ctx context.Context, kid string) (*rsa.PublicKey, error) {
+	p.keysMu.Lock()
+	defer p.keysMu.Unlock()
+
+	if key, ok := p.keys[kid]; ok && time.Since(p.keysFetchedAt) < appleKeysCacheTTL {
+		return key, nil
+	}
+
+	keys, err := fetchAppleKeys(ctx)
+	if err != nil {
+		return nil, err
+	}
+	p.keys = keys
+	p.keysFetchedAt = time.Now()
+
+	key, ok := keys[kid]
+	if !ok {
+		return nil, fmt.Errorf("apple public key not found: %s", kid)
+	}
+	return key, nil
+}
+
+// fetchAppleKeys はAppleのJWKSを取得してkidごとのRSA公開鍵にする
+func fetchAppleKeys(ctx context.Context) (map[string]*rsa.PublicKey, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, appleKeysURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create apple keys request: %w", err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get apple keys: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("apple keys endpoint returned status %d", resp.StatusCode)
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	var jwks struct {
+		Keys []struct {
+			Kid string `json:"kid"`
+			Kty string `json:"kty"`
+			N   string `json:"n"`
+			E   string `json:"e"`
+		} `json:"keys"`
+	}
+	if err := json.Unmarshal(body, &jwks); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal apple keys: %w", err)
+	}
+
+	keys := make(map[string]*rsa.PublicKey, len(jwks.Keys))
+	for _, k := range jwks.Keys {
+		if k.Kty != "RSA" {
+			continue
+		}
+		n, err := base64.RawURLEncoding.DecodeString(k.N)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode apple key modulus: %w", err)
+		}
+		e, err := base64.RawURLEncoding.DecodeString(k.E)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode apple key exponent: %w", err)
+		}
+		keys[k.Kid] = &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}
+	}
+	return keys, nil
+}
+
+// decodeJWTSegment はJWTのBase64URLエンコードされたセグメントをJSONとしてデコードする
+func decodeJWTSegment(segment string, v any) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+// appleBool はAppleのid_tokenで真偽値と文字列（"true"/"false"）のどちらでも送られるクレーム
+type appleBool bool
+
+// UnmarshalJSON は真偽値と文字列の両方を受け付ける
+func (b *appleBool) UnmarshalJSON(data []byte) error {
+	s := strings.Trim(string(data), `"`)
+	*b = appleBool(s == "true")
+	return nil
+}
@@ -0,0 +1,106 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/microsoft"
+)
+
+// microsoftGraphMeURL はサインインしたユーザーのプロフィールを取得するMicrosoft GraphのAPI
+const microsoftGraphMeURL = "https://graph.microsoft.com/v1.0/me?$select=id,displayName,mail,userPrincipalName"
+
+// MicrosoftConfig はMicrosoft Entra ID（Azure AD）のOAuth設定
+type MicrosoftConfig struct {
+	ClientID     string
+	ClientSecret string
+	// Tenant はテナントIDまたはドメイン（common / organizations / consumersの場合は複数のテナントを受け付ける）
+	Tenant      string
+	RedirectURL string
+}
+
+// MicrosoftUserInfo はMicrosoft Graphから取得したユーザー情報
+type MicrosoftUserInfo struct {
+	ID                string `json:"id"`
+	DisplayName       string `json:"displayName"`
+	Mail              string `json:"mail"`
+	UserPrincipalName string `json:"userPrincipalName"`
+}
+
+// EmailAddress はメールアドレスを返す（mailが未設定の場合はユーザープリンシパル名を使う）
+func (u *MicrosoftUserInfo) EmailAddress() string {
+	if u.Mail != "" {
+		return u.Mail
+	}
+	return u.UserPrincipalName
+}
+
+// EnableMicrosoft はMicrosoft Entra IDのログインを有効にする
+func (o *OAuthConfig) EnableMicrosoft(cfg MicrosoftConfig) {
+	tenant := cfg.Tenant
+	if tenant == "" {
+		tenant = "common"
+	}
+	o.MicrosoftConfig = &oauth2.Config{
+		ClientID:     cfg.ClientID,
+		ClientSecret: cfg.ClientSecret,
+		RedirectURL:  cfg.RedirectURL,
+		Scopes:       []string{"openid", "profile", "email", "User.Read"},
+		Endpoint:     microsoft.AzureADEndpoint(tenant),
+	}
+	o.microsoftTenant = tenant
+}
+
+// MicrosoftEnabled はMicrosoftのログインが有効かどうかを返す
+func (o *OAuthConfig) MicrosoftEnabled() bool {
+	return o.MicrosoftConfig != nil
+}
+
+// MicrosoftSingleTenant は特定のテナントのユーザーのみを受け付ける設定かどうかを返す
+// 複数テナントの場合、メールアドレスは各テナントの管理者が自由に設定できるため所有の確認にならない
+func (o *OAuthConfig) MicrosoftSingleTenant() bool {
+	switch o.microsoftTenant {
+	case "", "common", "organizations", "consumers":
+		return false
+	default:
+		return true
+	}
+}
+
+// GetMicrosoftUserInfo はアクセストークンを使用してMicrosoft Graphからユーザー情報を取得する
+func (o *OAuthConfig) GetMicrosoftUserInfo(ctx context.Context, token *oauth2.Token) (*MicrosoftUserInfo, error) {
+	client := o.MicrosoftConfig.Client(ctx, token)
+
+	resp, err := client.Get(microsoftGraphMeURL)
+	if err != nil {
+		o.Logger.ErrorContext(ctx, "failed to get user info", "error", err)
+		return nil, fmt.Errorf("failed to get user info: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		o.Logger.ErrorContext(ctx, "microsoft graph returned non-200 status",
+			"status", resp.StatusCode,
+			"body", string(body))
+		return nil, fmt.Errorf("microsoft graph returned status %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		o.Logger.ErrorContext(ctx, "failed to read response body", "error", err)
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	var userInfo MicrosoftUserInfo
+	if err := json.Unmarshal(body, &userInfo); err != nil {
+		o.Logger.ErrorContext(ctx, "failed to unmarshal user info", "error", err)
+		return nil, fmt.Errorf("failed to unmarshal user info: %w", err)
+	}
+
+	return &userInfo, nil
+}
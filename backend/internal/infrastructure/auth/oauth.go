@@ -3,6 +3,7 @@ package auth
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log/slog"
@@ -17,15 +18,24 @@ import (
 type ProviderType string
 
 const (
-	ProviderGoogle ProviderType = "google"
-	ProviderGithub ProviderType = "github"
+	ProviderGoogle    ProviderType = "google"
+	ProviderGithub    ProviderType = "github"
+	ProviderApple     ProviderType = "apple"
+	ProviderMicrosoft ProviderType = "microsoft"
 )
 
 // OAuthConfig はOAuth認証の設定を保持する
 type OAuthConfig struct {
 	GoogleConfig *oauth2.Config
 	GithubConfig *oauth2.Config
-	Logger       *slog.Logger
+	// AppleConfig はEnableAppleを呼んだ場合のみ設定される
+	AppleConfig *oauth2.Config
+	// MicrosoftConfig はEnableMicrosoftを呼んだ場合のみ設定される
+	MicrosoftConfig *oauth2.Config
+	Logger          *slog.Logger
+
+	apple           *appleProvider
+	microsoftTenant string
 }
 
 // NewOAuthConfig は新しいOAuthConfigを作成する
@@ -70,6 +80,17 @@ func (o *OAuthConfig) GetAuthURL(provider ProviderType, state string) string {
 		return o.GoogleConfig.AuthCodeURL(state, oauth2.AccessTypeOffline, oauth2.ApprovalForce)
 	case ProviderGithub:
 		return o.GithubConfig.AuthCodeURL(state)
+	case ProviderApple:
+		if o.AppleConfig == nil {
+			return ""
+		}
+		// 名前・メールアドレスのスコープを要求する場合はform_postでコールバックされる
+		return o.AppleConfig.AuthCodeURL(state, oauth2.SetAuthURLParam("response_mode", "form_post"))
+	case ProviderMicrosoft:
+		if o.MicrosoftConfig == nil {
+			return ""
+		}
+		return o.MicrosoftConfig.AuthCodeURL(state)
 	default:
 		return ""
 	}
@@ -85,6 +106,13 @@ func (o *OAuthConfig) Exchange(ctx context.Context, provider ProviderType, code
 		token, err = o.GoogleConfig.Exchange(ctx, code)
 	case ProviderGithub:
 		token, err = o.GithubConfig.Exchange(ctx, code)
+	case ProviderApple:
+		token, err = o.exchangeApple(ctx, code)
+	case ProviderMicrosoft:
+		if o.MicrosoftConfig == nil {
+			return nil, fmt.Errorf("unsupported provider: %s", provider)
+		}
+		token, err = o.MicrosoftConfig.Exchange(ctx, code)
 	default:
 		return nil, fmt.Errorf("unsupported provider: %s", provider)
 	}
@@ -96,6 +124,26 @@ func (o *OAuthConfig) Exchange(ctx context.Context, provider ProviderType, code
 	return token, nil
 }
 
+// ErrRefreshTokenRejected はプロバイダーがリフレッシュトークンを拒否した（期限切れ・使用済み・取り消し）ことを表す
+var ErrRefreshTokenRejected = errors.New("refresh token rejected")
+
+// RefreshGithubToken はGitHubのリフレッシュトークンで新しいアクセストークンを取得する
+// GitHubのリフレッシュトークンは1回しか使えず、新しいリフレッシュトークンが返るため、呼び出し元で保存し直す
+func (o *OAuthConfig) RefreshGithubToken(ctx context.Context, refreshToken string) (*oauth2.Token, error) {
+	// アクセストークンを持たないトークンを渡し、必ずリフレッシュさせる
+	token, err := o.GithubConfig.TokenSource(ctx, &oauth2.Token{RefreshToken: refreshToken}).Token()
+	if err != nil {
+		var retrieveErr *oauth2.RetrieveError
+		// GitHubは拒否した場合も200でerrorを返す
+		if errors.As(err, &retrieveErr) && (retrieveErr.ErrorCode == "bad_refresh_token" || retrieveErr.ErrorCode == "invalid_grant") {
+			return nil, fmt.Errorf("github %s: %w", retrieveErr.ErrorCode, ErrRefreshTokenRejected)
+		}
+		o.Logger.ErrorContext(ctx, "failed to refresh token", "provider", ProviderGithub, "error", err)
+		return nil, fmt.Errorf("failed to refresh token: %w", err)
+	}
+	return token, nil
+}
+
 // GoogleUserInfo はGoogleから取得したユーザー情報
 type GoogleUserInfo struct {
 	ID            string `json:"id"`
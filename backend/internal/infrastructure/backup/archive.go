@@ -0,0 +1,188 @@
+package backup
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/sikigasa/github-task-controller/backend/internal/domain/model"
+)
+
+// アーカイブはgzipで圧縮したJSON Lines形式で、1行目にヘッダー、続いてテーブルの行、最終行に行数の集計を書く
+// 最終行まで読めたことで途中で切れたアーカイブを検出する
+
+const (
+	// archiveFormat はアーカイブの形式を識別する名前
+	archiveFormat = "github-task-controller-backup"
+	// archiveVersion はアーカイブの形式のバージョン
+	archiveVersion = 1
+	// maxLineSize は1行（1レコード）の最大サイズ
+	maxLineSize = 64 << 20
+)
+
+// Header はアーカイブの先頭に書くメタデータ
+type Header struct {
+	Format    string    `json:"format"`
+	Version   int       `json:"version"`
+	CreatedAt time.Time `json:"created_at"`
+	Trigger   string    `json:"trigger"`
+	Tables    []string  `json:"tables"`
+}
+
+// line はアーカイブの2行目以降の1行
+type line struct {
+	Table string          `json:"table,omitempty"`
+	Row   json.RawMessage `json:"row,omitempty"`
+	// Rows は最終行にのみ書くテーブルごとの行数
+	Rows map[string]int `json:"rows,omitempty"`
+}
+
+// Writer はアーカイブを書き出す
+type Writer struct {
+	gz   *gzip.Writer
+	buf  *bufio.Writer
+	enc  *json.Encoder
+	rows map[string]int
+}
+
+// NewWriter はヘッダーを書き出したWriterを作成する
+func NewWriter(w io.Writer, createdAt time.Time, trigger string, tables []string) (*Writer, error) {
+	gz := gzip.NewWriter(w)
+	buf := bufio.NewWriter(gz)
+	aw := &Writer{gz: gz, buf: buf, enc: json.NewEncoder(buf), rows: make(map[string]int)}
+
+	header := Header{Format: archiveFormat, Version: archiveVersion, CreatedAt: createdAt, Trigger: trigger, Tables: tables}
+	if err := aw.enc.Encode(header); err != nil {
+		return nil, fmt.Errorf("failed to write backup header: %w", err)
+	}
+	return aw, nil
+}
+
+// WriteRow はテーブルの1行を書き出す
+func (w *Writer) WriteRow(table string, row json.RawMessage) error {
+	if err := w.enc.Encode(line{Table: table, Row: row}); err != nil {
+		return fmt.Errorf("failed to write backup row: %w", err)
+	}
+	w.rows[table]++
+	return nil
+}
+
+// Rows はこれまでに書き出したテーブルごとの行数を返す
+func (w *Writer) Rows() map[string]int {
+	return w.rows
+}
+
+// Close は行数の集計を書き出してアーカイブを閉じる（下層のio.Writerは閉じない）
+func (w *Writer) Close() error {
+	if err := w.enc.Encode(line{Rows: w.rows}); err != nil {
+		return fmt.Errorf("failed to write backup trailer: %w", err)
+	}
+	if err := w.buf.Flush(); err != nil {
+		return fmt.Errorf("failed to flush backup: %w", err)
+	}
+	if err := w.gz.Close(); err != nil {
+		return fmt.Errorf("failed to close backup: %w", err)
+	}
+	return nil
+}
+
+// Reader はアーカイブを読み込む
+type Reader struct {
+	header  Header
+	gz      *gzip.Reader
+	scanner *bufio.Scanner
+	rows    map[string]int
+	tables  map[string]bool
+	done    bool
+}
+
+// NewReader はヘッダーを読み込んで形式を検証したReaderを作成する
+func NewReader(r io.Reader) (*Reader, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("backup is not a gzip archive: %w", model.ErrInvalidInput)
+	}
+	scanner := bufio.NewScanner(gz)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxLineSize)
+
+	ar := &Reader{gz: gz, scanner: scanner, rows: make(map[string]int), tables: make(map[string]bool)}
+	if !scanner.Scan() {
+		return nil, fmt.Errorf("backup header is missing: %w", model.ErrInvalidInput)
+	}
+	if err := json.Unmarshal(scanner.Bytes(), &ar.header); err != nil {
+		return nil, fmt.Errorf("backup header is malformed: %w", model.ErrInvalidInput)
+	}
+	if ar.header.Format != archiveFormat {
+		return nil, fmt.Errorf("unknown backup format %q: %w", ar.header.Format, model.ErrInvalidInput)
+	}
+	if ar.header.Version != archiveVersion {
+		return nil, fmt.Errorf("unsupported backup version %d: %w", ar.header.Version, model.ErrInvalidInput)
+	}
+	for _, t := range ar.header.Tables {
+		ar.tables[t] = true
+	}
+	return ar, nil
+}
+
+// Header はアーカイブのヘッダーを返す
+func (r *Reader) Header() Header {
+	return r.header
+}
+
+// Next は次の行を返す
+// 最終行の行数の集計と読み込んだ行数が一致した場合はio.EOFを返し、一致しない場合や最終行がない場合はErrInvalidInputを返す
+func (r *Reader) Next() (string, json.RawMessage, error) {
+	if r.done {
+		return "", nil, io.EOF
+	}
+	if !r.scanner.Scan() {
+		if err := r.scanner.Err(); err != nil {
+			return "", nil, fmt.Errorf("failed to read backup: %w", errors.Join(err, model.ErrInvalidInput))
+		}
+		return "", nil, fmt.Errorf("backup is truncated: %w", model.ErrInvalidInput)
+	}
+
+	var l line
+	if err := json.Unmarshal(r.scanner.Bytes(), &l); err != nil {
+		return "", nil, fmt.Errorf("backup row is malformed: %w", model.ErrInvalidInput)
+	}
+
+	if l.Table == "" {
+		if err := r.verify(l.Rows); err != nil {
+			return "", nil, err
+		}
+		// gzipのチェックサムまで読み切って破損がないことを確認する
+		if r.scanner.Scan() {
+			return "", nil, fmt.Errorf("backup has data after trailer: %w", model.ErrInvalidInput)
+		}
+		if err := r.scanner.Err(); err != nil {
+			return "", nil, fmt.Errorf("failed to read backup: %w", errors.Join(err, model.ErrInvalidInput))
+		}
+		r.done = true
+		return "", nil, io.EOF
+	}
+	if !r.tables[l.Table] {
+		return "", nil, fmt.Errorf("backup row for undeclared table %q: %w", l.Table, model.ErrInvalidInput)
+	}
+	r.rows[l.Table]++
+	return l.Table, l.Row, nil
+}
+
+// verify は最終行の行数の集計と読み込んだ行数を照合する
+func (r *Reader) verify(expected map[string]int) error {
+	for _, t := range r.header.Tables {
+		if r.rows[t] != expected[t] {
+			return fmt.Errorf("backup table %s has %d rows (expected %d): %w", t, r.rows[t], expected[t], model.ErrInvalidInput)
+		}
+	}
+	return nil
+}
+
+// Close はReaderを閉じる（下層のio.Readerは閉じない）
+func (r *Reader) Close() error {
+	return r.gz.Close()
+}
@@ -0,0 +1,76 @@
+package broker
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"time"
+)
+
+// Kind はメッセージブローカーの種類
+type Kind string
+
+const (
+	// KindNATS はNATS JetStream
+	KindNATS Kind = "nats"
+	// KindKafka はKafka（REST Proxy経由）
+	KindKafka Kind = "kafka"
+)
+
+// Message はブローカーに送信するメッセージを表す
+type Message struct {
+	// ID はメッセージの一意なID（再送による重複をブローカー・受信側で除くために使う）
+	ID string
+	// Type はメッセージの種類（NATSではサブジェクトの末尾に使う）
+	Type string
+	// Key は順序を保証する単位（Kafkaではパーティションのキーに使う）
+	Key string
+	// Body はJSONの本文
+	Body []byte
+}
+
+// Publisher はメッセージをブローカーに送信するインターフェース
+// Publishはブローカーがメッセージを受け付けたことを確認してから返すため、
+// 失敗した場合に再送すれば少なくとも1回は届く
+type Publisher interface {
+	Publish(ctx context.Context, msg Message) error
+	Close() error
+}
+
+// Config はメッセージブローカーの設定
+type Config struct {
+	Kind Kind
+	// URL はNATSのサーバー（nats://またはtls://）かKafka REST ProxyのURL（認証情報はURLのユーザー情報で指定する）
+	URL string
+	// Destination はNATSのサブジェクトの接頭辞かKafkaのトピック
+	Destination string
+	// Timeout は1件の送信の応答を待つ時間
+	Timeout time.Duration
+}
+
+// NewPublisher は設定に応じたPublisherを作成する（接続は最初の送信時に行う）
+func NewPublisher(cfg Config, logger *slog.Logger) (Publisher, error) {
+	u, err := url.Parse(cfg.URL)
+	if err != nil || u.Host == "" {
+		return nil, fmt.Errorf("invalid broker url: %s", cfg.URL)
+	}
+	if cfg.Destination == "" {
+		return nil, fmt.Errorf("broker destination is required")
+	}
+
+	switch cfg.Kind {
+	case KindNATS:
+		if u.Scheme != "nats" && u.Scheme != "tls" {
+			return nil, fmt.Errorf("invalid NATS url scheme: %s (must be nats or tls)", u.Scheme)
+		}
+		return newNATSPublisher(u, cfg.Destination, cfg.Timeout, logger), nil
+	case KindKafka:
+		if u.Scheme != "http" && u.Scheme != "https" {
+			return nil, fmt.Errorf("invalid Kafka REST Proxy url scheme: %s (must be http or https)", u.Scheme)
+		}
+		return newKafkaPublisher(u, cfg.Destination, cfg.Timeout), nil
+	default:
+		return nil, fmt.Errorf("unknown broker: %s", cfg.Kind)
+	}
+}
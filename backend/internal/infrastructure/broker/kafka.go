@@ -0,0 +1,124 @@
+package broker
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// kafkaContentType はREST Proxy（v2 API）にJSONのレコードを送る場合のContent-Type
+const kafkaContentType = "application/vnd.kafka.json.v2+json"
+
+// kafkaPublisher はKafka REST Proxy（Confluent REST ProxyやRedpandaのHTTP Proxy）のv2 APIでトピックにメッセージを送信する
+// キーを集約のIDにすることで、同じ集約のメッセージを同じパーティションに順番に書き込む
+type kafkaPublisher struct {
+	topicURL   string
+	username   string
+	password   string
+	httpClient *http.Client
+}
+
+// kafkaProduceRequest はREST Proxyへの送信の本文
+type kafkaProduceRequest struct {
+	Records []kafkaRecord `json:"records"`
+}
+
+type kafkaRecord struct {
+	Key   string          `json:"key"`
+	Value json.RawMessage `json:"value"`
+}
+
+// kafkaProduceResponse はREST Proxyの応答（レコードごとの書き込み結果）
+type kafkaProduceResponse struct {
+	Offsets []struct {
+		Partition int     `json:"partition"`
+		Offset    int64   `json:"offset"`
+		ErrorCode *int    `json:"error_code"`
+		Error     *string `json:"error"`
+	} `json:"offsets"`
+}
+
+// kafkaErrorResponse はREST Proxyのエラー応答
+type kafkaErrorResponse struct {
+	ErrorCode int    `json:"error_code"`
+	Message   string `json:"message"`
+}
+
+func newKafkaPublisher(u *url.URL, topic string, timeout time.Duration) *kafkaPublisher {
+	p := &kafkaPublisher{
+		httpClient: &http.Client{Timeout: timeout},
+	}
+	if u.User != nil {
+		p.username = u.User.Username()
+		p.password, _ = u.User.Password()
+	}
+	base := *u
+	base.User = nil
+	p.topicURL = base.JoinPath("topics", topic).String()
+	return p
+}
+
+// Publish はメッセージをトピックに書き込み、ブローカーが受け付けるまで待つ
+func (p *kafkaPublisher) Publish(ctx context.Context, msg Message) error {
+	body, err := json.Marshal(kafkaProduceRequest{
+		Records: []kafkaRecord{{Key: msg.Key, Value: msg.Body}},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal Kafka record: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.topicURL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create Kafka REST request: %w", err)
+	}
+	req.Header.Set("Content-Type", kafkaContentType)
+	req.Header.Set("Accept", "application/vnd.kafka.v2+json")
+	if p.username != "" {
+		req.SetBasicAuth(p.username, p.password)
+	}
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send Kafka REST request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return fmt.Errorf("failed to read Kafka REST response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		var errResp kafkaErrorResponse
+		if json.Unmarshal(respBody, &errResp) == nil && errResp.Message != "" {
+			return fmt.Errorf("kafka REST proxy returned %d: %s (code %d)", resp.StatusCode, errResp.Message, errResp.ErrorCode)
+		}
+		return fmt.Errorf("kafka REST proxy returned %d", resp.StatusCode)
+	}
+
+	var produced kafkaProduceResponse
+	if err := json.Unmarshal(respBody, &produced); err != nil {
+		return fmt.Errorf("failed to parse Kafka REST response: %w", err)
+	}
+	if len(produced.Offsets) != 1 {
+		return fmt.Errorf("kafka REST proxy returned %d offsets for 1 record", len(produced.Offsets))
+	}
+	if offset := produced.Offsets[0]; offset.ErrorCode != nil || offset.Error != nil {
+		message := ""
+		if offset.Error != nil {
+			message = *offset.Error
+		}
+		return fmt.Errorf("kafka rejected record: %s", message)
+	}
+	return nil
+}
+
+// Close は待機中のHTTPの接続を閉じる
+func (p *kafkaPublisher) Close() error {
+	p.httpClient.CloseIdleConnections()
+	return nil
+}
@@ -0,0 +1,298 @@
+package broker
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// natsDefaultPort はURLにポートがない場合のNATSのポート
+const natsDefaultPort = "4222"
+
+// natsPublisher はNATSのプロトコルでJetStreamのストリームにメッセージを送信する
+// 送信ごとにJetStreamの受領応答（PubAck）を待ち、Nats-Msg-Idヘッダーでストリームの重複排除を効かせる
+// メッセージを送信するサブジェクト（<接頭辞>.<種類>）を含むストリームはあらかじめ作成しておく
+type natsPublisher struct {
+	url           *url.URL
+	subjectPrefix string
+	timeout       time.Duration
+	logger        *slog.Logger
+
+	// mu は接続と応答の読み取りを1件ずつに直列化する
+	mu     sync.Mutex
+	conn   net.Conn
+	reader *bufio.Reader
+	inbox  string
+}
+
+// natsServerInfo はNATSサーバーが接続時に送るINFOの内容
+type natsServerInfo struct {
+	Headers     bool `json:"headers"`
+	TLSRequired bool `json:"tls_required"`
+}
+
+// natsPubAck はJetStreamの受領応答
+type natsPubAck struct {
+	Stream    string `json:"stream"`
+	Sequence  uint64 `json:"seq"`
+	Duplicate bool   `json:"duplicate"`
+	Error     *struct {
+		Code        int    `json:"code"`
+		Description string `json:"description"`
+	} `json:"error"`
+}
+
+func newNATSPublisher(u *url.URL, subjectPrefix string, timeout time.Duration, logger *slog.Logger) *natsPublisher {
+	return &natsPublisher{
+		url:           u,
+		subjectPrefix: subjectPrefix,
+		timeout:       timeout,
+		logger:        logger,
+	}
+}
+
+// Publish はメッセージを送信し、JetStreamが保存するまで待つ
+func (p *natsPublisher) Publish(ctx context.Context, msg Message) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.conn == nil {
+		if err := p.connect(ctx); err != nil {
+			p.closeConn()
+			return fmt.Errorf("failed to connect to NATS: %w", err)
+		}
+	}
+
+	ack, err := p.publish(ctx, msg)
+	if err != nil {
+		// 応答の途中で失敗した場合に次の送信の応答と取り違えないよう、接続し直す
+		p.closeConn()
+		return fmt.Errorf("failed to publish to NATS: %w", err)
+	}
+	if ack.Duplicate {
+		p.logger.DebugContext(ctx, "NATS message was a duplicate", "message_id", msg.ID, "stream", ack.Stream)
+	}
+	return nil
+}
+
+// Close は接続を閉じる
+func (p *natsPublisher) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.closeConn()
+	return nil
+}
+
+func (p *natsPublisher) closeConn() {
+	if p.conn != nil {
+		_ = p.conn.Close()
+	}
+	p.conn = nil
+	p.reader = nil
+}
+
+// connect はサーバーに接続し、受領応答を受け取る受信箱を購読する
+func (p *natsPublisher) connect(ctx context.Context) error {
+	host := p.url.Host
+	if p.url.Port() == "" {
+		host = net.JoinHostPort(p.url.Hostname(), natsDefaultPort)
+	}
+	dialer := net.Dialer{Timeout: p.timeout}
+	conn, err := dialer.DialContext(ctx, "tcp", host)
+	if err != nil {
+		return err
+	}
+	p.conn = conn
+	p.reader = bufio.NewReader(conn)
+	p.setDeadline(ctx)
+
+	// INFOは暗号化せずに送られ、TLSの場合はその後にハンドシェイクする
+	line, err := p.readLine()
+	if err != nil {
+		return fmt.Errorf("failed to read server info: %w", err)
+	}
+	infoJSON, ok := strings.CutPrefix(line, "INFO ")
+	if !ok {
+		return fmt.Errorf("unexpected greeting: %s", line)
+	}
+	var info natsServerInfo
+	if err := json.Unmarshal([]byte(infoJSON), &info); err != nil {
+		return fmt.Errorf("failed to parse server info: %w", err)
+	}
+	if !info.Headers {
+		return errors.New("server does not support headers (NATS 2.2 or later is required)")
+	}
+
+	useTLS := p.url.Scheme == "tls"
+	if info.TLSRequired && !useTLS {
+		return errors.New("server requires TLS (use a tls:// url)")
+	}
+	if useTLS {
+		tlsConn := tls.Client(conn, &tls.Config{ServerName: p.url.Hostname(), MinVersion: tls.VersionTLS12})
+		if err := tlsConn.HandshakeContext(ctx); err != nil {
+			return fmt.Errorf("failed to complete TLS handshake: %w", err)
+		}
+		p.conn = tlsConn
+		p.reader = bufio.NewReader(tlsConn)
+		p.setDeadline(ctx)
+	}
+
+	options := map[string]any{
+		"verbose":       false,
+		"pedantic":      false,
+		"tls_required":  useTLS,
+		"name":          "github-task-controller",
+		"lang":          "go",
+		"protocol":      1,
+		"headers":       true,
+		"no_responders": true,
+	}
+	// URLにパスワードがあればユーザー名とパスワード、ユーザー名だけならトークンとして認証する
+	if user := p.url.User; user != nil {
+		if password, ok := user.Password(); ok {
+			options["user"] = user.Username()
+			options["pass"] = password
+		} else {
+			options["auth_token"] = user.Username()
+		}
+	}
+	connectJSON, err := json.Marshal(options)
+	if err != nil {
+		return fmt.Errorf("failed to marshal connect options: %w", err)
+	}
+
+	inbox, err := newNATSInbox()
+	if err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintf(p.conn, "CONNECT %s\r\nPING\r\nSUB %s 1\r\n", connectJSON, inbox); err != nil {
+		return fmt.Errorf("failed to send connect: %w", err)
+	}
+	// 認証に失敗した場合はPONGの代わりに-ERRが返る
+	for {
+		line, err := p.readLine()
+		if err != nil {
+			return fmt.Errorf("failed to read connect response: %w", err)
+		}
+		if line == "PONG" {
+			break
+		}
+		if description, ok := strings.CutPrefix(line, "-ERR "); ok {
+			return fmt.Errorf("server rejected connection: %s", description)
+		}
+	}
+	p.inbox = inbox
+
+	p.logger.InfoContext(ctx, "connected to NATS", "host", host)
+	return nil
+}
+
+// publish はヘッダー付きでメッセージを送信し、受信箱に届く受領応答を読み取る
+func (p *natsPublisher) publish(ctx context.Context, msg Message) (*natsPubAck, error) {
+	p.setDeadline(ctx)
+
+	subject := p.subjectPrefix + "." + msg.Type
+	header := "NATS/1.0\r\nNats-Msg-Id: " + msg.ID + "\r\n\r\n"
+	frame := fmt.Sprintf("HPUB %s %s %d %d\r\n%s%s\r\n", subject, p.inbox, len(header), len(header)+len(msg.Body), header, msg.Body)
+	if _, err := io.WriteString(p.conn, frame); err != nil {
+		return nil, err
+	}
+
+	for {
+		line, err := p.readLine()
+		if err != nil {
+			return nil, err
+		}
+
+		verb, args, _ := strings.Cut(line, " ")
+		switch verb {
+		case "PING":
+			if _, err := io.WriteString(p.conn, "PONG\r\n"); err != nil {
+				return nil, err
+			}
+		case "-ERR":
+			return nil, fmt.Errorf("server error: %s", args)
+		case "MSG", "HMSG":
+			return p.readPubAck(verb == "HMSG", strings.Fields(args))
+		}
+		// +OK・PONG・INFO（クラスタ構成の変更の通知）は読み飛ばす
+	}
+}
+
+// readPubAck はMSG・HMSGの本文を読み取り、JetStreamの受領応答として解釈する
+// HMSGの場合、argsの末尾はヘッダーの長さと全体の長さ、MSGの場合は本文の長さ
+func (p *natsPublisher) readPubAck(hasHeader bool, args []string) (*natsPubAck, error) {
+	if len(args) < 3 {
+		return nil, fmt.Errorf("malformed message: %v", args)
+	}
+	total, err := strconv.Atoi(args[len(args)-1])
+	if err != nil {
+		return nil, fmt.Errorf("malformed message size: %w", err)
+	}
+	headerLen := 0
+	if hasHeader {
+		if headerLen, err = strconv.Atoi(args[len(args)-2]); err != nil || headerLen > total {
+			return nil, fmt.Errorf("malformed message header size: %v", args)
+		}
+	}
+
+	data := make([]byte, total+2)
+	if _, err := io.ReadFull(p.reader, data); err != nil {
+		return nil, err
+	}
+	header, body := string(data[:headerLen]), data[headerLen:total]
+
+	// ストリームがサブジェクトを含まない場合は、本文のないステータス503（No Responders）が返る
+	if status, _, _ := strings.Cut(header, "\r\n"); strings.HasPrefix(status, "NATS/1.0 503") {
+		return nil, fmt.Errorf("no JetStream stream for subject %s.>", p.subjectPrefix)
+	}
+
+	var ack natsPubAck
+	if err := json.Unmarshal(body, &ack); err != nil {
+		return nil, fmt.Errorf("failed to parse publish ack: %w", err)
+	}
+	if ack.Error != nil {
+		return nil, fmt.Errorf("JetStream rejected message: %s (code %d)", ack.Error.Description, ack.Error.Code)
+	}
+	return &ack, nil
+}
+
+// readLine はプロトコルの1行を改行を除いて読み取る
+func (p *natsPublisher) readLine() (string, error) {
+	line, err := p.reader.ReadString('\n')
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+// setDeadline はtimeoutかctxの期限の早いほうを読み書きの期限にする
+func (p *natsPublisher) setDeadline(ctx context.Context) {
+	deadline := time.Now().Add(p.timeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+	_ = p.conn.SetDeadline(deadline)
+}
+
+// newNATSInbox は受領応答を受け取る一意なサブジェクトを作成する
+func newNATSInbox() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate inbox: %w", err)
+	}
+	return "_INBOX." + hex.EncodeToString(b), nil
+}
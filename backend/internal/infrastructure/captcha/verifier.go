@@ -0,0 +1,117 @@
+package captcha
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Provider はCAPTCHAのサービス
+type Provider string
+
+const (
+	// ProviderTurnstile はCloudflare Turnstile
+	ProviderTurnstile Provider = "turnstile"
+	// ProviderHCaptcha はhCaptcha
+	ProviderHCaptcha Provider = "hcaptcha"
+)
+
+// verifyURLs はサービスごとのトークン検証エンドポイント
+var verifyURLs = map[Provider]string{
+	ProviderTurnstile: "https://challenges.cloudflare.com/turnstile/v0/siteverify",
+	ProviderHCaptcha:  "https://api.hcaptcha.com/siteverify",
+}
+
+// ErrVerificationFailed はCAPTCHAのトークンが検証に失敗した場合のエラー
+var ErrVerificationFailed = errors.New("captcha verification failed")
+
+// Verifier はCAPTCHAのトークンを検証する
+type Verifier struct {
+	provider   Provider
+	siteKey    string
+	secret     string
+	verifyURL  string
+	httpClient *http.Client
+}
+
+// NewVerifier は新しいVerifierを作成する
+func NewVerifier(provider Provider, siteKey, secret string) (*Verifier, error) {
+	verifyURL, ok := verifyURLs[provider]
+	if !ok {
+		return nil, fmt.Errorf("unknown captcha provider: %s", provider)
+	}
+	if siteKey == "" || secret == "" {
+		return nil, fmt.Errorf("captcha site key and secret are required")
+	}
+	return &Verifier{
+		provider:   provider,
+		siteKey:    siteKey,
+		secret:     secret,
+		verifyURL:  verifyURL,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+	}, nil
+}
+
+// Provider はCAPTCHAのサービスを返す
+func (v *Verifier) Provider() Provider {
+	return v.provider
+}
+
+// SiteKey はフロントエンドでウィジェットの表示に使うサイトキーを返す
+func (v *Verifier) SiteKey() string {
+	return v.siteKey
+}
+
+// Verify はウィジェットが発行したトークンをサービスに問い合わせて検証する
+// トークンが無効な場合はErrVerificationFailedを返す
+func (v *Verifier) Verify(ctx context.Context, token, remoteIP string) error {
+	if token == "" {
+		return fmt.Errorf("empty token: %w", ErrVerificationFailed)
+	}
+
+	form := url.Values{
+		"secret":   {v.secret},
+		"response": {token},
+	}
+	if remoteIP != "" {
+		form.Set("remoteip", remoteIP)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, v.verifyURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return fmt.Errorf("failed to create captcha verify request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := v.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to verify captcha: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("captcha verify endpoint returned status %d", resp.StatusCode)
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	var result struct {
+		Success    bool     `json:"success"`
+		ErrorCodes []string `json:"error-codes"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return fmt.Errorf("failed to unmarshal captcha verify response: %w", err)
+	}
+	if !result.Success {
+		return fmt.Errorf("%s: %w", strings.Join(result.ErrorCodes, ","), ErrVerificationFailed)
+	}
+	return nil
+}
@@ -0,0 +1,163 @@
+package crypto
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// KeySize はAES-256の鍵の長さ（バイト）
+const KeySize = 32
+
+// encryptedPrefix は暗号化した値の先頭に付ける形式のバージョン（enc:v1:<キーID>:<base64(nonce||暗号文)>）
+const encryptedPrefix = "enc:v1:"
+
+var (
+	// ErrUnknownKey は暗号化に使ったキーが設定にない場合のエラー（ローテーションで古いキーを外した場合等）
+	ErrUnknownKey = errors.New("unknown encryption key")
+	// ErrMalformed は暗号化した値の形式が不正か、改ざんされている場合のエラー
+	ErrMalformed = errors.New("malformed encrypted value")
+)
+
+// Key は暗号化キー
+type Key struct {
+	// ID は暗号化した値に記録するキーの識別子
+	ID     string
+	Secret []byte
+}
+
+// Cipher はトークン等の秘密の値をAES-256-GCMで暗号化・復号する
+// 暗号化には先頭のキー（プライマリキー）を使い、復号には値に記録されたキーを使うため、
+// 新しいキーを先頭に追加して古いキーを残せば、保存済みの値を復号したままキーをローテーションできる
+type Cipher struct {
+	primary string
+	aeads   map[string]cipher.AEAD
+}
+
+// NewCipher は新しいCipherを作成する（keysの先頭をプライマリキーとする）
+func NewCipher(keys []Key) (*Cipher, error) {
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("at least one encryption key is required")
+	}
+
+	c := &Cipher{
+		primary: keys[0].ID,
+		aeads:   make(map[string]cipher.AEAD, len(keys)),
+	}
+	for _, key := range keys {
+		if key.ID == "" || strings.Contains(key.ID, ":") {
+			return nil, fmt.Errorf("invalid encryption key id: %q", key.ID)
+		}
+		if _, ok := c.aeads[key.ID]; ok {
+			return nil, fmt.Errorf("duplicate encryption key id: %s", key.ID)
+		}
+		if len(key.Secret) != KeySize {
+			return nil, fmt.Errorf("encryption key %s must be %d bytes, got %d", key.ID, KeySize, len(key.Secret))
+		}
+
+		block, err := aes.NewCipher(key.Secret)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create cipher for key %s: %w", key.ID, err)
+		}
+		aead, err := cipher.NewGCM(block)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create gcm for key %s: %w", key.ID, err)
+		}
+		c.aeads[key.ID] = aead
+	}
+	return c, nil
+}
+
+// ParseKeys は「<キーID>:<base64の32バイトの鍵>」の一覧をキーに変換する（順序を保つ）
+func ParseKeys(specs []string) ([]Key, error) {
+	keys := make([]Key, 0, len(specs))
+	for _, spec := range specs {
+		spec = strings.TrimSpace(spec)
+		if spec == "" {
+			continue
+		}
+		id, encoded, ok := strings.Cut(spec, ":")
+		if !ok {
+			// 鍵を含むためエラーに値を載せない
+			return nil, fmt.Errorf("invalid encryption key: must be <id>:<base64 key>")
+		}
+		secret, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("invalid encryption key %s: %w", id, err)
+		}
+		keys = append(keys, Key{ID: id, Secret: secret})
+	}
+	return keys, nil
+}
+
+// DeriveKey は任意の長さの秘密からキーを導出する（開発環境でキーを設定しない場合に使う）
+func DeriveKey(id, secret string) Key {
+	sum := sha256.Sum256([]byte(secret))
+	return Key{ID: id, Secret: sum[:]}
+}
+
+// Encrypt は値をプライマリキーで暗号化する（空文字列は秘密を持たないためそのまま返す）
+func (c *Cipher) Encrypt(plaintext string) (string, error) {
+	if plaintext == "" {
+		return "", nil
+	}
+
+	aead := c.aeads[c.primary]
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	// キーIDを追加データとして認証し、別のキーIDに付け替えた値を復号できないようにする
+	sealed := aead.Seal(nonce, nonce, []byte(plaintext), []byte(c.primary))
+	return encryptedPrefix + c.primary + ":" + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// Decrypt は暗号化した値を復号する
+// 暗号化の導入前に保存された平文の値はそのまま返す（ReencryptNeededで検出して暗号化し直す）
+func (c *Cipher) Decrypt(value string) (string, error) {
+	if !IsEncrypted(value) {
+		return value, nil
+	}
+
+	keyID, encoded, ok := strings.Cut(strings.TrimPrefix(value, encryptedPrefix), ":")
+	if !ok {
+		return "", ErrMalformed
+	}
+	aead, ok := c.aeads[keyID]
+	if !ok {
+		return "", fmt.Errorf("%w: %s", ErrUnknownKey, keyID)
+	}
+	sealed, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil || len(sealed) < aead.NonceSize() {
+		return "", ErrMalformed
+	}
+
+	nonce, ciphertext := sealed[:aead.NonceSize()], sealed[aead.NonceSize():]
+	plaintext, err := aead.Open(nil, nonce, ciphertext, []byte(keyID))
+	if err != nil {
+		return "", ErrMalformed
+	}
+	return string(plaintext), nil
+}
+
+// ReencryptNeeded は値を暗号化し直す必要があるか（平文のままか、プライマリ以外のキーで暗号化されているか）を返す
+func (c *Cipher) ReencryptNeeded(value string) bool {
+	if value == "" {
+		return false
+	}
+	if !IsEncrypted(value) {
+		return true
+	}
+	keyID, _, _ := strings.Cut(strings.TrimPrefix(value, encryptedPrefix), ":")
+	return keyID != c.primary
+}
+
+// IsEncrypted は値がCipherで暗号化した形式かを返す
+func IsEncrypted(value string) bool {
+	return strings.HasPrefix(value, encryptedPrefix)
+}
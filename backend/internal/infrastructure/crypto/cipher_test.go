@@ -0,0 +1,197 @@
+package crypto
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func testKey(id string, fill byte) Key {
+	return Key{ID: id, Secret: bytes.Repeat([]byte{fill}, KeySize)}
+}
+
+func newTestCipher(t *testing.T, keys ...Key) *Cipher {
+	t.Helper()
+	c, err := NewCipher(keys)
+	if err != nil {
+		t.Fatalf("NewCipher() error = %v", err)
+	}
+	return c
+}
+
+func encrypt(t *testing.T, c *Cipher, plaintext string) string {
+	t.Helper()
+	value, err := c.Encrypt(plaintext)
+	if err != nil {
+		t.Fatalf("Encrypt() error = %v", err)
+	}
+	return value
+}
+
+func TestCipherRoundTrip(t *testing.T) {
+	c := newTestCipher(t, testKey("k1", 1))
+
+	first := encrypt(t, c, "ghp_token")
+	second := encrypt(t, c, "ghp_token")
+	if !strings.HasPrefix(first, "enc:v1:k1:") {
+		t.Errorf("Encrypt() = %q, want enc:v1:k1: prefix", first)
+	}
+	if strings.Contains(first, "ghp_token") {
+		t.Error("Encrypt() result contains the plaintext")
+	}
+	// 同じ値でもノンスが異なるため暗号文は一致しない
+	if first == second {
+		t.Error("Encrypt() returned the same value twice")
+	}
+
+	for _, value := range []string{first, second} {
+		got, err := c.Decrypt(value)
+		if err != nil {
+			t.Fatalf("Decrypt() error = %v", err)
+		}
+		if got != "ghp_token" {
+			t.Errorf("Decrypt() = %q, want %q", got, "ghp_token")
+		}
+	}
+
+	if value := encrypt(t, c, ""); value != "" {
+		t.Errorf("Encrypt(\"\") = %q, want empty", value)
+	}
+}
+
+func TestCipherDecryptKnownValue(t *testing.T) {
+	// Pythonのcryptography（AESGCM）で鍵0x00..0x1f・ノンス0x00..0x0b・追加データ"2026-01"で暗号化した値
+	keys, err := ParseKeys([]string{"2026-01:AAECAwQFBgcICQoLDA0ODxAREhMUFRYXGBkaGxwdHh8="})
+	if err != nil {
+		t.Fatalf("ParseKeys() error = %v", err)
+	}
+	c := newTestCipher(t, keys...)
+
+	got, err := c.Decrypt("enc:v1:2026-01:AAECAwQFBgcICQoLIGqmRKCdo3b9LfL/3oIdAzGjXlffJe93xlezm4JHWiE=")
+	if err != nil {
+		t.Fatalf("Decrypt() error = %v", err)
+	}
+	if got != "ghp_exampletoken" {
+		t.Errorf("Decrypt() = %q, want %q", got, "ghp_exampletoken")
+	}
+}
+
+func TestCipherKeyRotation(t *testing.T) {
+	oldKey, newKey := testKey("2025", 1), testKey("2026", 2)
+	before := newTestCipher(t, oldKey)
+	value := encrypt(t, before, "ghp_token")
+
+	// 新しいキーを先頭に追加しても、古いキーで暗号化した値を復号できる
+	rotating := newTestCipher(t, newKey, oldKey)
+	if got, err := rotating.Decrypt(value); err != nil || got != "ghp_token" {
+		t.Fatalf("Decrypt() with rotated keys = %q, %v, want %q", got, err, "ghp_token")
+	}
+	if !rotating.ReencryptNeeded(value) {
+		t.Error("ReencryptNeeded() for value of old key = false, want true")
+	}
+
+	reencrypted := encrypt(t, rotating, "ghp_token")
+	if !strings.HasPrefix(reencrypted, "enc:v1:2026:") {
+		t.Errorf("Encrypt() = %q, want the primary key id", reencrypted)
+	}
+	if rotating.ReencryptNeeded(reencrypted) {
+		t.Error("ReencryptNeeded() for value of primary key = true, want false")
+	}
+
+	// 古いキーを外した後は、暗号化し直した値のみ復号できる
+	after := newTestCipher(t, newKey)
+	if got, err := after.Decrypt(reencrypted); err != nil || got != "ghp_token" {
+		t.Errorf("Decrypt() after removing old key = %q, %v, want %q", got, err, "ghp_token")
+	}
+	if _, err := after.Decrypt(value); !errors.Is(err, ErrUnknownKey) {
+		t.Errorf("Decrypt() of value of removed key error = %v, want ErrUnknownKey", err)
+	}
+}
+
+func TestCipherDecryptRejectsTamperedValue(t *testing.T) {
+	k1 := testKey("k1", 1)
+	// 同じ鍵を別のキーIDで登録し、キーIDの付け替えを追加データで検出できることを確認する
+	k2 := Key{ID: "k2", Secret: k1.Secret}
+	c := newTestCipher(t, k1, k2)
+	value := encrypt(t, c, "ghp_token")
+
+	sealed, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(value, "enc:v1:k1:"))
+	if err != nil {
+		t.Fatalf("failed to decode value: %v", err)
+	}
+	flipped := bytes.Clone(sealed)
+	flipped[len(flipped)-1] ^= 1
+
+	tests := map[string]string{
+		"flipped bit":     "enc:v1:k1:" + base64.StdEncoding.EncodeToString(flipped),
+		"relabeled key":   strings.Replace(value, "enc:v1:k1:", "enc:v1:k2:", 1),
+		"truncated":       "enc:v1:k1:" + base64.StdEncoding.EncodeToString(sealed[:len(sealed)-1]),
+		"shorter than iv": "enc:v1:k1:" + base64.StdEncoding.EncodeToString(sealed[:4]),
+		"not base64":      "enc:v1:k1:!!!",
+		"missing key id":  "enc:v1:" + base64.StdEncoding.EncodeToString(sealed),
+	}
+	for name, tampered := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got, err := c.Decrypt(tampered); !errors.Is(err, ErrMalformed) {
+				t.Errorf("Decrypt() = %q, %v, want ErrMalformed", got, err)
+			}
+		})
+	}
+}
+
+func TestCipherPlaintextValue(t *testing.T) {
+	c := newTestCipher(t, testKey("k1", 1))
+
+	// 暗号化の導入前に保存された平文の値はそのまま返し、暗号化し直す対象とする
+	got, err := c.Decrypt("gho_legacy")
+	if err != nil || got != "gho_legacy" {
+		t.Errorf("Decrypt() = %q, %v, want %q", got, err, "gho_legacy")
+	}
+	if !c.ReencryptNeeded("gho_legacy") {
+		t.Error("ReencryptNeeded() for plaintext = false, want true")
+	}
+	if c.ReencryptNeeded("") {
+		t.Error("ReencryptNeeded() for empty value = true, want false")
+	}
+}
+
+func TestNewCipherRejectsInvalidKeys(t *testing.T) {
+	tests := map[string][]Key{
+		"no keys":        nil,
+		"empty id":       {testKey("", 1)},
+		"id with colon":  {testKey("k:1", 1)},
+		"duplicate id":   {testKey("k1", 1), testKey("k1", 2)},
+		"short secret":   {{ID: "k1", Secret: make([]byte, 16)}},
+		"long secret":    {{ID: "k1", Secret: make([]byte, 64)}},
+		"missing secret": {{ID: "k1"}},
+	}
+	for name, keys := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := NewCipher(keys); err == nil {
+				t.Error("NewCipher() error = nil, want error")
+			}
+		})
+	}
+}
+
+func TestParseKeys(t *testing.T) {
+	secret := base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{1}, KeySize))
+
+	keys, err := ParseKeys([]string{" 2026:" + secret, "", "2025:" + secret})
+	if err != nil {
+		t.Fatalf("ParseKeys() error = %v", err)
+	}
+	if len(keys) != 2 || keys[0].ID != "2026" || keys[1].ID != "2025" {
+		t.Errorf("ParseKeys() = %v, want keys 2026 and 2025 in order", keys)
+	}
+
+	// 鍵を含む設定の値はエラーに載せない
+	if _, err := ParseKeys([]string{secret}); err == nil || strings.Contains(err.Error(), secret) {
+		t.Errorf("ParseKeys() without id error = %v, want error without the key", err)
+	}
+	if _, err := ParseKeys([]string{"2026:not-base64!"}); err == nil {
+		t.Error("ParseKeys() with invalid base64 error = nil, want error")
+	}
+}
@@ -0,0 +1,133 @@
+package export
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"unicode/utf16"
+
+	"github.com/sikigasa/github-task-controller/backend/internal/domain/model"
+)
+
+// A4横向きのページサイズと余白（pt）
+const (
+	pdfPageWidth   = 842
+	pdfPageHeight  = 595
+	pdfMargin      = 40
+	pdfFontSize    = 9
+	pdfTitleSize   = 14
+	pdfLineHeight  = 14
+	pdfHeaderSpace = 30
+)
+
+// pdfFontName は埋め込まずに参照する日本語フォント（PDFビューアが代替フォントで表示する）
+const pdfFontName = "HeiseiKakuGo-W5"
+
+// renderPDF は表形式のレポートを1ページに収まる行数ごとに改ページしたPDFにする
+// フォントを埋め込まないため外部ライブラリを使わずに最小限のPDFを組み立てる
+func renderPDF(table model.ReportTable) []byte {
+	rowsPerPage := (pdfPageHeight - 2*pdfMargin - pdfHeaderSpace - pdfLineHeight) / pdfLineHeight
+	var pages []string
+	for start := 0; start == 0 || start < len(table.Rows); start += rowsPerPage {
+		end := min(start+rowsPerPage, len(table.Rows))
+		pages = append(pages, pdfPageContent(table, table.Rows[start:end]))
+	}
+
+	w := &pdfWriter{}
+	w.buf.WriteString("%PDF-1.4\n")
+
+	// 1: カタログ、2: ページツリー、3-5: フォント、6以降: ページとその内容
+	w.object("<< /Type /Catalog /Pages 2 0 R >>")
+	kids := make([]string, len(pages))
+	for i := range pages {
+		kids[i] = fmt.Sprintf("%d 0 R", 6+2*i)
+	}
+	w.object(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(pages)))
+	w.object(fmt.Sprintf("<< /Type /Font /Subtype /Type0 /BaseFont /%s /Encoding /UniJIS-UCS2-H /DescendantFonts [4 0 R] >>", pdfFontName))
+	w.object(fmt.Sprintf("<< /Type /Font /Subtype /CIDFontType0 /BaseFont /%s "+
+		"/CIDSystemInfo << /Registry (Adobe) /Ordering (Japan1) /Supplement 2 >> "+
+		"/FontDescriptor 5 0 R /DW 1000 /W [1 95 500] >>", pdfFontName))
+	w.object(fmt.Sprintf("<< /Type /FontDescriptor /FontName /%s /Flags 4 /FontBBox [-92 -250 1010 922] "+
+		"/ItalicAngle 0 /Ascent 752 /Descent -221 /CapHeight 737 /StemV 114 >>", pdfFontName))
+	for i, content := range pages {
+		w.object(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %d %d] "+
+			"/Resources << /Font << /F1 3 0 R >> >> /Contents %d 0 R >>", pdfPageWidth, pdfPageHeight, 7+2*i))
+		w.object(fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", len(content), content))
+	}
+
+	return w.finish()
+}
+
+// pdfPageContent は1ページ分のタイトル・ヘッダー・行を描画するコンテンツストリームを作る
+func pdfPageContent(table model.ReportTable, rows [][]string) string {
+	var b strings.Builder
+	y := pdfPageHeight - pdfMargin - pdfTitleSize
+	writeText := func(x, y, size int, text string) {
+		fmt.Fprintf(&b, "BT /F1 %d Tf %d %d Td <%s> Tj ET\n", size, x, y, pdfEncodeText(text))
+	}
+
+	writeText(pdfMargin, y, pdfTitleSize, table.Title)
+	y -= pdfHeaderSpace
+
+	columnWidth := (pdfPageWidth - 2*pdfMargin) / max(len(table.Header), 1)
+	// 全角文字の幅をフォントサイズとみなして列に収まる文字数に切り詰める
+	maxRunes := columnWidth/pdfFontSize - 1
+	writeRow := func(cells []string) {
+		for i, cell := range cells {
+			writeText(pdfMargin+i*columnWidth, y, pdfFontSize, truncateRunes(cell, maxRunes))
+		}
+		y -= pdfLineHeight
+	}
+
+	writeRow(table.Header)
+	fmt.Fprintf(&b, "%d %d m %d %d l S\n", pdfMargin, y+pdfLineHeight-3, pdfPageWidth-pdfMargin, y+pdfLineHeight-3)
+	for _, row := range rows {
+		writeRow(row)
+	}
+
+	return b.String()
+}
+
+// pdfEncodeText はUniJIS-UCS2-Hで表示するためにUTF-16BEの16進文字列にする（BMP外の文字は?にする）
+func pdfEncodeText(text string) string {
+	var b strings.Builder
+	for _, r := range text {
+		if r > 0xFFFF || utf16.IsSurrogate(r) {
+			r = '?'
+		}
+		fmt.Fprintf(&b, "%04X", r)
+	}
+	return b.String()
+}
+
+// truncateRunes は文字数がnを超える場合に切り詰めて末尾を…にする
+func truncateRunes(s string, n int) string {
+	runes := []rune(s)
+	if len(runes) <= n || n < 1 {
+		return s
+	}
+	return string(runes[:n-1]) + "…"
+}
+
+// pdfWriter はオブジェクトのオフセットを記録しながらPDFを書き出す
+type pdfWriter struct {
+	buf     bytes.Buffer
+	offsets []int
+}
+
+// object は次の番号の間接オブジェクトを書き出す
+func (w *pdfWriter) object(body string) {
+	w.offsets = append(w.offsets, w.buf.Len())
+	fmt.Fprintf(&w.buf, "%d 0 obj\n%s\nendobj\n", len(w.offsets), body)
+}
+
+// finish は相互参照表とトレーラーを書き出してPDFを完成させる
+func (w *pdfWriter) finish() []byte {
+	xref := w.buf.Len()
+	fmt.Fprintf(&w.buf, "xref\n0 %d\n0000000000 65535 f \n", len(w.offsets)+1)
+	for _, offset := range w.offsets {
+		fmt.Fprintf(&w.buf, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&w.buf, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(w.offsets)+1, xref)
+	return w.buf.Bytes()
+}
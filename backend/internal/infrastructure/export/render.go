@@ -0,0 +1,38 @@
+package export
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+
+	"github.com/sikigasa/github-task-controller/backend/internal/domain/model"
+)
+
+// Render は表形式のレポートを指定の形式のファイルにし、内容とContent-Typeを返す
+func Render(format model.ExportFormat, table model.ReportTable) ([]byte, string, error) {
+	switch format {
+	case model.ExportFormatCSV:
+		content, err := renderCSV(table)
+		return content, "text/csv; charset=utf-8", err
+	case model.ExportFormatPDF:
+		return renderPDF(table), "application/pdf", nil
+	default:
+		return nil, "", fmt.Errorf("unknown format %q: %w", format, model.ErrInvalidInput)
+	}
+}
+
+// renderCSV はヘッダー行とデータ行をCSVにする（Excelで文字化けしないようBOMを付ける）
+func renderCSV(table model.ReportTable) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteString("\uFEFF")
+
+	w := csv.NewWriter(&buf)
+	if err := w.Write(table.Header); err != nil {
+		return nil, fmt.Errorf("failed to write csv header: %w", err)
+	}
+	if err := w.WriteAll(table.Rows); err != nil {
+		return nil, fmt.Errorf("failed to write csv rows: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
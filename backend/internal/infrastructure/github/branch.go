@@ -0,0 +1,57 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+// Branch はリポジトリに作成したブランチを表す
+type Branch struct {
+	Name string
+	// Base は分岐元のブランチ名
+	Base string
+	SHA  string
+}
+
+// CreateBranchFromDefault はリポジトリのデフォルトブランチの先頭からブランチを作成する
+// 同名のブランチが既に存在する場合はステータス422のAPIErrorを返す
+func (s *ProjectService) CreateBranchFromDefault(ctx context.Context, token, owner, repo, name string) (*Branch, error) {
+	var repository struct {
+		DefaultBranch string `json:"default_branch"`
+	}
+	if err := s.client.RESTRequest(ctx, token, http.MethodGet, fmt.Sprintf("/repos/%s/%s", owner, repo), nil, &repository); err != nil {
+		return nil, err
+	}
+	base := repository.DefaultBranch
+	if base == "" {
+		return nil, fmt.Errorf("invalid repository response format")
+	}
+
+	var ref struct {
+		Object *struct {
+			SHA string `json:"sha"`
+		} `json:"object"`
+	}
+	if err := s.client.RESTRequest(ctx, token, http.MethodGet, fmt.Sprintf("/repos/%s/%s/git/ref/heads/%s", owner, repo, base), nil, &ref); err != nil {
+		return nil, err
+	}
+	if ref.Object == nil || ref.Object.SHA == "" {
+		return nil, fmt.Errorf("invalid ref response format")
+	}
+	sha := ref.Object.SHA
+
+	body := map[string]interface{}{
+		"ref": "refs/heads/" + name,
+		"sha": sha,
+	}
+	if err := s.client.RESTRequest(ctx, token, http.MethodPost, fmt.Sprintf("/repos/%s/%s/git/refs", owner, repo), body, nil); err != nil {
+		return nil, err
+	}
+
+	return &Branch{
+		Name: name,
+		Base: base,
+		SHA:  sha,
+	}, nil
+}
@@ -1,13 +1,15 @@
 package github
 
 import (
-	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log/slog"
 	"net/http"
+	"sync"
+	"time"
 )
 
 const (
@@ -15,116 +17,237 @@ const (
 	restAPIBase     = "https://api.github.com"
 )
 
+// APIError はGitHub REST APIが2xx以外のステータスを返したことを表す
+type APIError struct {
+	StatusCode int
+	Status     string
+}
+
+// ErrNotFound はGitHub上にリソースが存在しない（削除された・アクセスできない）ことを表す
+var ErrNotFound = errors.New("github resource not found")
+
+// ErrUnauthorized はGitHubがトークンを拒否した（失効・取り消し・期限切れ）ことを表す
+var ErrUnauthorized = errors.New("github token rejected")
+
+// ErrUnavailable はGitHubが5xxを返したか接続できなかった（一時的な障害で、時間を置けば成功しうる）ことを表す
+var ErrUnavailable = errors.New("github unavailable")
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("GitHub REST API error: %s", e.Status)
+}
+
+// Is は404・410をErrNotFound、401をErrUnauthorized、5xxをErrUnavailableとして扱う
+func (e *APIError) Is(target error) bool {
+	switch target {
+	case ErrNotFound:
+		return e.StatusCode == http.StatusNotFound || e.StatusCode == http.StatusGone
+	case ErrUnauthorized:
+		return e.StatusCode == http.StatusUnauthorized
+	case ErrUnavailable:
+		return e.StatusCode >= http.StatusInternalServerError
+	}
+	return false
+}
+
+// UnauthorizedHandler はGitHubがユーザーのトークンを拒否した場合に呼び出す処理
+// 返したエラーはErrUnauthorizedと合わせて呼び出し元に返す
+type UnauthorizedHandler func(ctx context.Context, userID string) error
+
 // Client はGitHub APIクライアント
+// GraphQLのコストと残りポイント、X-RateLimit-*ヘッダーの残りの回数をトークンごとに記録する（インスタンス内のみ）
+// 残りの回数が少ない場合はリクエストの間隔を空け、レート制限と一時的な障害の場合は待ってから再試行する
 type Client struct {
 	httpClient *http.Client
-	logger     *slog.Logger
+	// budgetFloor は一括処理を見送るGraphQLの残りポイントの下限
+	budgetFloor int
+	// maxRetries はレート制限・一時的な障害で再試行する最大回数
+	maxRetries int
+	// maxWait はレート制限で1回に待つ時間の上限（超える場合は待たずにErrRateLimitedを返す）
+	maxWait    time.Duration
+	mu         sync.Mutex
+	rateLimits map[string]RateLimit
+	// headerLimits はX-RateLimit-*ヘッダーの残りの回数（キーはトークンとX-RateLimit-Resource）
+	headerLimits map[string]RateLimit
+	usage        map[string]int
+	// onUnauthorized はトークンが拒否された場合の処理（SetUnauthorizedHandlerで設定する）
+	onUnauthorized UnauthorizedHandler
+	logger         *slog.Logger
 }
 
 // NewClient は新しいGitHub APIクライアントを作成する
-func NewClient(logger *slog.Logger) *Client {
+// maxRetriesはレート制限・一時的な障害で再試行する最大回数、maxWaitはレート制限で1回に待つ時間の上限
+func NewClient(budgetFloor, maxRetries int, maxWait time.Duration, logger *slog.Logger) *Client {
 	return &Client{
-		httpClient: &http.Client{},
-		logger:     logger,
+		httpClient:   &http.Client{},
+		budgetFloor:  budgetFloor,
+		maxRetries:   maxRetries,
+		maxWait:      maxWait,
+		rateLimits:   make(map[string]RateLimit),
+		headerLimits: make(map[string]RateLimit),
+		usage:        make(map[string]int),
+		logger:       logger,
 	}
 }
 
-// GraphQLRequest はGraphQLリクエストを実行する
-func (c *Client) GraphQLRequest(ctx context.Context, token, query string, variables map[string]interface{}) (map[string]interface{}, error) {
-	body := map[string]interface{}{
-		"query":     query,
-		"variables": variables,
-	}
+// SetUnauthorizedHandler はGitHubがトークンを拒否した場合の処理を設定する
+// リクエストを実行する前（起動時）に設定する
+func (c *Client) SetUnauthorizedHandler(h UnauthorizedHandler) {
+	c.onUnauthorized = h
+}
 
-	jsonBody, err := json.Marshal(body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to marshal request: %w", err)
+// unauthorized はWithUserで設定したユーザーのトークンが拒否されたことを通知し、その結果をerrに合わせて返す
+func (c *Client) unauthorized(ctx context.Context, err error) error {
+	userID := userFromContext(ctx)
+	if c.onUnauthorized == nil || userID == "" {
+		return err
 	}
-
-	req, err := http.NewRequestWithContext(ctx, "POST", graphQLEndpoint, bytes.NewBuffer(jsonBody))
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+	if hErr := c.onUnauthorized(ctx, userID); hErr != nil {
+		return fmt.Errorf("%w: %w", err, hErr)
 	}
+	return err
+}
 
-	req.Header.Set("Authorization", "Bearer "+token)
-	req.Header.Set("Content-Type", "application/json")
-
-	resp, err := c.httpClient.Do(req)
+// GraphQLRequest はGraphQLリクエストを実行し、レスポンスのdataをdataの指す値に解析する（dataがnilの場合は解析しない）
+// dataにはクエリに合わせた構造体を渡し、nullになりうるオブジェクトのフィールドはポインタで受ける
+func (c *Client) GraphQLRequest(ctx context.Context, token, query string, variables map[string]interface{}, data interface{}) error {
+	jsonBody, err := json.Marshal(graphQLRequest{
+		Query:     withRateLimit(query),
+		Variables: variables,
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to execute request: %w", err)
+		return fmt.Errorf("failed to marshal request: %w", err)
 	}
-	defer resp.Body.Close()
 
-	respBody, err := io.ReadAll(resp.Body)
+	// mutationは処理されたか分からない障害では再試行しない
+	idempotent := !isMutation(query)
+	resp, err := c.send(ctx, token, "graphql", idempotent, jsonBody, func(body io.Reader) (*http.Request, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, graphQLEndpoint, body)
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Authorization", "Bearer "+token)
+		req.Header.Set("Content-Type", "application/json")
+		return req, nil
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to read response: %w", err)
+		return err
 	}
+	respBody := resp.Body
 
 	if resp.StatusCode != http.StatusOK {
 		c.logger.ErrorContext(ctx, "GitHub API error", "status", resp.StatusCode, "body", string(respBody))
-		return nil, fmt.Errorf("GitHub API error: %s", resp.Status)
+		if resp.StatusCode == http.StatusUnauthorized {
+			return c.unauthorized(ctx, fmt.Errorf("GitHub API error: %s: %w", resp.Status, ErrUnauthorized))
+		}
+		if resp.StatusCode >= http.StatusInternalServerError {
+			return fmt.Errorf("GitHub API error: %s: %w", resp.Status, ErrUnavailable)
+		}
+		return fmt.Errorf("GitHub API error: %s", resp.Status)
 	}
 
-	var result map[string]interface{}
+	var result graphQLResponse
 	if err := json.Unmarshal(respBody, &result); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+		return fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+
+	if len(result.Errors) > 0 {
+		c.logger.ErrorContext(ctx, "GraphQL errors", "errors", result.Errors.String())
+		if result.Errors.hasType("NOT_FOUND") {
+			return fmt.Errorf("GraphQL errors: %s: %w", result.Errors, ErrNotFound)
+		}
+		// GraphQL APIはポイントを使い切った場合も200でRATE_LIMITEDを返す
+		if result.Errors.hasType("RATE_LIMITED") {
+			return fmt.Errorf("GraphQL errors: %s: %w", result.Errors, &rateLimitError{RetryAt: rateLimitReset(resp.Header, time.Now())})
+		}
+		return fmt.Errorf("GraphQL errors: %s", result.Errors)
 	}
 
-	if errors, ok := result["errors"]; ok {
-		c.logger.ErrorContext(ctx, "GraphQL errors", "errors", errors)
-		return nil, fmt.Errorf("GraphQL errors: %v", errors)
+	if limit, ok := parseRateLimit(result.Data); ok {
+		c.recordRateLimit(ctx, token, limit)
 	}
 
-	return result, nil
+	if data == nil || len(result.Data) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(result.Data, data); err != nil {
+		return fmt.Errorf("failed to unmarshal response data: %w", err)
+	}
+	return nil
 }
 
-// RESTRequest はREST APIリクエストを実行する
-func (c *Client) RESTRequest(ctx context.Context, token, method, path string, body interface{}) (map[string]interface{}, error) {
-	var reqBody io.Reader
-	if body != nil {
-		jsonBody, err := json.Marshal(body)
-		if err != nil {
-			return nil, fmt.Errorf("failed to marshal request: %w", err)
-		}
-		reqBody = bytes.NewBuffer(jsonBody)
+// executeError はリクエストを送信できなかった場合のエラーを返す
+// 呼び出し元のキャンセル・タイムアウト以外はGitHubに接続できなかったとしてErrUnavailableを含める
+func executeError(ctx context.Context, err error) error {
+	if ctx.Err() != nil {
+		return fmt.Errorf("failed to execute request: %w", err)
 	}
+	return fmt.Errorf("failed to execute request: %w: %w", err, ErrUnavailable)
+}
 
-	req, err := http.NewRequestWithContext(ctx, method, restAPIBase+path, reqBody)
+// RESTRequest はREST APIリクエストを実行し、レスポンスをoutの指す値に解析する（outがnilかレスポンスが空の場合は解析しない）
+// 配列を返すAPIはoutにスライスのポインタを渡す
+func (c *Client) RESTRequest(ctx context.Context, token, method, path string, body, out interface{}) error {
+	respBody, err := c.doREST(ctx, token, method, path, body)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+		return err
 	}
 
-	req.Header.Set("Authorization", "Bearer "+token)
-	req.Header.Set("Accept", "application/vnd.github+json")
-	req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
-	if body != nil {
-		req.Header.Set("Content-Type", "application/json")
+	if out == nil || len(respBody) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(respBody, out); err != nil {
+		return fmt.Errorf("failed to unmarshal response: %w", err)
 	}
+	return nil
+}
 
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("failed to execute request: %w", err)
+// doREST はREST APIリクエストを実行してレスポンスボディを返す
+func (c *Client) doREST(ctx context.Context, token, method, path string, body interface{}) ([]byte, error) {
+	respBody, _, err := c.doRESTWithHeader(ctx, token, method, path, body)
+	return respBody, err
+}
+
+// doRESTWithHeader はREST APIリクエストを実行してレスポンスボディとヘッダーを返す
+func (c *Client) doRESTWithHeader(ctx context.Context, token, method, path string, body interface{}) ([]byte, http.Header, error) {
+	var jsonBody []byte
+	if body != nil {
+		var err error
+		if jsonBody, err = json.Marshal(body); err != nil {
+			return nil, nil, fmt.Errorf("failed to marshal request: %w", err)
+		}
 	}
-	defer resp.Body.Close()
 
-	respBody, err := io.ReadAll(resp.Body)
+	// POST（Issueの作成等）は処理されたか分からない障害では再試行しない
+	idempotent := method != http.MethodPost
+	resp, err := c.send(ctx, token, "core", idempotent, jsonBody, func(reqBody io.Reader) (*http.Request, error) {
+		if body == nil {
+			reqBody = nil
+		}
+		req, err := http.NewRequestWithContext(ctx, method, restAPIBase+path, reqBody)
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Authorization", "Bearer "+token)
+		req.Header.Set("Accept", "application/vnd.github+json")
+		req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
+		if body != nil {
+			req.Header.Set("Content-Type", "application/json")
+		}
+		return req, nil
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to read response: %w", err)
+		return nil, nil, err
 	}
+	respBody := resp.Body
 
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
 		c.logger.ErrorContext(ctx, "GitHub REST API error", "status", resp.StatusCode, "body", string(respBody))
-		return nil, fmt.Errorf("GitHub REST API error: %s", resp.Status)
-	}
-
-	if len(respBody) == 0 {
-		return nil, nil
-	}
-
-	var result map[string]interface{}
-	if err := json.Unmarshal(respBody, &result); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+		apiErr := &APIError{StatusCode: resp.StatusCode, Status: resp.Status}
+		if resp.StatusCode == http.StatusUnauthorized {
+			return nil, nil, c.unauthorized(ctx, apiErr)
+		}
+		return nil, nil, apiErr
 	}
 
-	return result, nil
+	return respBody, resp.Header, nil
 }
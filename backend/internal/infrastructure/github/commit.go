@@ -0,0 +1,104 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// commitsMaxPages はコミットを取得する最大ページ数（1ページ100件）
+const commitsMaxPages = 3
+
+// Commit はリポジトリのコミットを表す
+type Commit struct {
+	SHA         string
+	Message     string
+	URL         string
+	Author      string
+	CommittedAt time.Time
+}
+
+// Summary はコミットメッセージの1行目を返す
+func (c *Commit) Summary() string {
+	summary, _, _ := strings.Cut(c.Message, "\n")
+	return summary
+}
+
+// ListCommits はリポジトリのデフォルトブランチのコミットを新しい順に取得する
+// sinceを指定した場合はそれ以降のコミットのみ取得する
+func (s *ProjectService) ListCommits(ctx context.Context, token, owner, repo string, since *time.Time) ([]Commit, error) {
+	query := url.Values{"per_page": {"100"}}
+	if since != nil {
+		query.Set("since", since.UTC().Format(time.RFC3339))
+	}
+
+	var commits []Commit
+	for page := 1; page <= commitsMaxPages; page++ {
+		query.Set("page", fmt.Sprintf("%d", page))
+		var results []commitResponse
+		if err := s.client.RESTRequest(ctx, token, http.MethodGet, fmt.Sprintf("/repos/%s/%s/commits?%s", owner, repo, query.Encode()), nil, &results); err != nil {
+			return nil, err
+		}
+
+		for i := range results {
+			commit, err := results[i].commit()
+			if err != nil {
+				s.logger.WarnContext(ctx, "skipping malformed commit", "error", err)
+				continue
+			}
+			commits = append(commits, *commit)
+		}
+
+		if len(results) < 100 {
+			break
+		}
+	}
+
+	return commits, nil
+}
+
+// commitResponse はREST APIのコミット
+type commitResponse struct {
+	SHA     string `json:"sha"`
+	HTMLURL string `json:"html_url"`
+	Commit  *struct {
+		Message string `json:"message"`
+		Author  *struct {
+			Name string `json:"name"`
+		} `json:"author"`
+		Committer *struct {
+			Date time.Time `json:"date"`
+		} `json:"committer"`
+	} `json:"commit"`
+	// Author はコミットに紐づくGitHubアカウント（紐づかない場合はnull）
+	Author *struct {
+		Login string `json:"login"`
+	} `json:"author"`
+}
+
+// commit はREST APIのレスポンスからコミットを取得する
+func (r *commitResponse) commit() (*Commit, error) {
+	if r.SHA == "" {
+		return nil, fmt.Errorf("invalid commit response format")
+	}
+
+	commit := &Commit{SHA: r.SHA, URL: r.HTMLURL}
+	if detail := r.Commit; detail != nil {
+		commit.Message = detail.Message
+		if detail.Author != nil {
+			commit.Author = detail.Author.Name
+		}
+		if detail.Committer != nil {
+			commit.CommittedAt = detail.Committer.Date
+		}
+	}
+	// GitHubアカウントに紐づくコミットはログイン名を優先する
+	if r.Author != nil && r.Author.Login != "" {
+		commit.Author = r.Author.Login
+	}
+
+	return commit, nil
+}
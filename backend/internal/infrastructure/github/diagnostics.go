@@ -0,0 +1,124 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+)
+
+// projectSyncScopes はGitHub Projectとの同期に必要なOAuthスコープ（classicのPAT・OAuthトークンの場合）
+var projectSyncScopes = []string{"repo", "project"}
+
+// Quota はREST・GraphQLそれぞれのAPIの利用量を表す
+type Quota struct {
+	Limit     int
+	Remaining int
+	Used      int
+	ResetAt   time.Time
+}
+
+// Diagnostics はトークンでGitHub APIに接続できるかと、トークンの権限・残りの利用量を表す
+type Diagnostics struct {
+	// Login はトークンで認証されたGitHubのユーザー名
+	Login string
+	// Scopes はトークンに付与されたOAuthスコープ（Fine-grained PATなどスコープを返さないトークンはnil）
+	Scopes []string
+	// MissingScopes はGitHub Projectとの同期に必要でトークンにないスコープ（Scopesがnilの場合はnil）
+	MissingScopes []string
+	REST          Quota
+	GraphQL       Quota
+	// Latency はユーザー情報の取得にかかった時間（GitHub APIへの到達性の目安）
+	Latency time.Duration
+}
+
+// Diagnose はトークンで認証されたユーザー・スコープ・APIの残りの利用量を取得する
+// /rate_limitはREST APIの利用量を消費しない
+func (c *Client) Diagnose(ctx context.Context, token string) (*Diagnostics, error) {
+	start := time.Now()
+	body, header, err := c.doRESTWithHeader(ctx, token, http.MethodGet, "/user", nil)
+	latency := time.Since(start)
+	if err != nil {
+		return nil, err
+	}
+
+	var user struct {
+		Login string `json:"login"`
+	}
+	if err := json.Unmarshal(body, &user); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal user: %w", err)
+	}
+
+	body, _, err = c.doRESTWithHeader(ctx, token, http.MethodGet, "/rate_limit", nil)
+	if err != nil {
+		return nil, err
+	}
+	var rateLimit struct {
+		Resources struct {
+			Core    rateLimitResource `json:"core"`
+			GraphQL rateLimitResource `json:"graphql"`
+		} `json:"resources"`
+	}
+	if err := json.Unmarshal(body, &rateLimit); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal rate limit: %w", err)
+	}
+
+	diagnostics := &Diagnostics{
+		Login:   user.Login,
+		REST:    rateLimit.Resources.Core.quota(),
+		GraphQL: rateLimit.Resources.GraphQL.quota(),
+		Latency: latency,
+	}
+	if values, ok := header["X-Oauth-Scopes"]; ok {
+		diagnostics.Scopes = parseScopes(strings.Join(values, ","))
+		diagnostics.MissingScopes = []string{}
+		for _, scope := range projectSyncScopes {
+			if !slices.Contains(diagnostics.Scopes, scope) {
+				diagnostics.MissingScopes = append(diagnostics.MissingScopes, scope)
+			}
+		}
+	}
+
+	// 取得した残りポイントを一括処理の見送りの判断にも使う
+	c.mu.Lock()
+	c.rateLimits[tokenKey(token)] = RateLimit{Remaining: diagnostics.GraphQL.Remaining, ResetAt: diagnostics.GraphQL.ResetAt}
+	c.mu.Unlock()
+
+	return diagnostics, nil
+}
+
+// Diagnose はトークンで認証されたユーザー・スコープ・APIの残りの利用量を取得する
+func (s *ProjectService) Diagnose(ctx context.Context, token string) (*Diagnostics, error) {
+	return s.client.Diagnose(ctx, token)
+}
+
+// rateLimitResource は/rate_limitのリソースごとの利用量
+type rateLimitResource struct {
+	Limit     int   `json:"limit"`
+	Remaining int   `json:"remaining"`
+	Used      int   `json:"used"`
+	Reset     int64 `json:"reset"`
+}
+
+func (r rateLimitResource) quota() Quota {
+	return Quota{
+		Limit:     r.Limit,
+		Remaining: r.Remaining,
+		Used:      r.Used,
+		ResetAt:   time.Unix(r.Reset, 0),
+	}
+}
+
+// parseScopes はX-OAuth-Scopesヘッダー（カンマ区切り）のスコープを返す
+func parseScopes(value string) []string {
+	scopes := []string{}
+	for _, scope := range strings.Split(value, ",") {
+		if scope = strings.TrimSpace(scope); scope != "" {
+			scopes = append(scopes, scope)
+		}
+	}
+	return scopes
+}
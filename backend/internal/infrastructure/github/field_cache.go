@@ -0,0 +1,71 @@
+package github
+
+import (
+	"sync"
+	"time"
+)
+
+// fieldCacheTTL はProjectのフィールド・選択肢のIDをキャッシュする期間
+// GitHub上でフィールドや選択肢が編集された場合も、この期間が過ぎるかInvalidateFieldsを呼べば取得し直す
+const fieldCacheTTL = 10 * time.Minute
+
+// fieldCacheKey はキャッシュするフィールドを特定する（単一選択以外のフィールドはIDだけをキャッシュする）
+type fieldCacheKey struct {
+	projectID    string
+	name         string
+	singleSelect bool
+}
+
+type fieldCacheEntry struct {
+	field     *SingleSelectField
+	expiresAt time.Time
+}
+
+// fieldCache はProjectごとのフィールド・選択肢のIDのキャッシュ
+// ステータスの変更のたびにフィールドを取得し直さないよう、ProjectServiceで共有する
+type fieldCache struct {
+	mu      sync.Mutex
+	entries map[fieldCacheKey]fieldCacheEntry
+}
+
+func newFieldCache() *fieldCache {
+	return &fieldCache{entries: make(map[fieldCacheKey]fieldCacheEntry)}
+}
+
+// get は有効期限内のキャッシュを返す
+func (c *fieldCache) get(key fieldCacheKey, now time.Time) (*SingleSelectField, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[key]
+	if !ok || now.After(entry.expiresAt) {
+		delete(c.entries, key)
+		return nil, false
+	}
+	return entry.field, true
+}
+
+func (c *fieldCache) set(key fieldCacheKey, field *SingleSelectField, now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries[key] = fieldCacheEntry{field: field, expiresAt: now.Add(fieldCacheTTL)}
+}
+
+// invalidate はProjectのキャッシュをすべて削除する
+func (c *fieldCache) invalidate(projectID string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for key := range c.entries {
+		if key.projectID == projectID {
+			delete(c.entries, key)
+		}
+	}
+}
+
+// InvalidateFields はProjectのフィールド・選択肢のIDのキャッシュを削除する
+// 選択肢が見つからない場合等、GitHub上でフィールドが編集された可能性がある時に呼ぶ
+func (s *ProjectService) InvalidateFields(projectID string) {
+	s.fields.invalidate(projectID)
+}
@@ -0,0 +1,69 @@
+package github
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// graphQLRequest はGraphQL APIのリクエストボディ
+type graphQLRequest struct {
+	Query     string                 `json:"query"`
+	Variables map[string]interface{} `json:"variables"`
+}
+
+// graphQLResponse はGraphQL APIのレスポンス（dataは呼び出し元のクエリに合わせた型で解析する）
+type graphQLResponse struct {
+	Data   json.RawMessage `json:"data"`
+	Errors graphQLErrors   `json:"errors"`
+}
+
+// graphQLError はGraphQL APIのエラー
+type graphQLError struct {
+	// Type はエラーの種類（NOT_FOUND・RATE_LIMITED・FORBIDDEN等、ない場合もある）
+	Type    string        `json:"type"`
+	Message string        `json:"message"`
+	Path    []interface{} `json:"path"`
+}
+
+// graphQLErrors はレスポンスのerrorsを表す
+type graphQLErrors []graphQLError
+
+func (e graphQLErrors) String() string {
+	messages := make([]string, 0, len(e))
+	for _, gqlErr := range e {
+		if gqlErr.Type != "" {
+			messages = append(messages, fmt.Sprintf("%s: %s", gqlErr.Type, gqlErr.Message))
+		} else {
+			messages = append(messages, gqlErr.Message)
+		}
+	}
+	return strings.Join(messages, "; ")
+}
+
+// hasType はtypeがerrorTypeのエラーが含まれるかを返す
+func (e graphQLErrors) hasType(errorType string) bool {
+	for _, gqlErr := range e {
+		if gqlErr.Type == errorType {
+			return true
+		}
+	}
+	return false
+}
+
+// nodeID はidだけを取得したノード（mutationの結果や存在の確認に使う）
+type nodeID struct {
+	ID string `json:"id"`
+}
+
+// pageInfo はコネクションのページの情報
+type pageInfo struct {
+	HasNextPage bool   `json:"hasNextPage"`
+	EndCursor   string `json:"endCursor"`
+}
+
+// connection はGraphQLのコネクション（アクセスできないノードはnullになるため、ノードはポインタで受ける）
+type connection[N any] struct {
+	Nodes    []*N     `json:"nodes"`
+	PageInfo pageInfo `json:"pageInfo"`
+}
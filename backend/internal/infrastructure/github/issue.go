@@ -0,0 +1,148 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// assignedIssuesMaxPages は担当Issueを取得する最大ページ数（1ページ100件）
+const assignedIssuesMaxPages = 10
+
+// Issue はリポジトリのIssueを表す
+type Issue struct {
+	// NodeID はGraphQL APIで使うIssueのID（Projectに追加する時に使う）
+	NodeID string
+	Number int
+	Title  string
+	Body   string
+	URL    string
+	// State はopenまたはclosed
+	State string
+	// Repository はowner/repo形式のリポジトリ名
+	Repository string
+	UpdatedAt  time.Time
+}
+
+// IsClosed はIssueがクローズされているかを返す
+func (i *Issue) IsClosed() bool {
+	return i.State == "closed"
+}
+
+// ListAssignedIssues は認証ユーザーが担当者のIssueを更新日時の新しい順に取得する（Pull Requestは含めない）
+// sinceがnilの場合はオープンなIssueのみ、指定した場合はそれ以降に更新されたクローズ済みを含むIssueを取得する
+func (s *ProjectService) ListAssignedIssues(ctx context.Context, token string, since *time.Time) ([]Issue, error) {
+	query := url.Values{
+		"filter":    {"assigned"},
+		"state":     {"open"},
+		"sort":      {"updated"},
+		"direction": {"desc"},
+		"per_page":  {"100"},
+	}
+	if since != nil {
+		query.Set("state", "all")
+		query.Set("since", since.UTC().Format(time.RFC3339))
+	}
+
+	var issues []Issue
+	for page := 1; page <= assignedIssuesMaxPages; page++ {
+		query.Set("page", fmt.Sprintf("%d", page))
+		var results []issueResponse
+		if err := s.client.RESTRequest(ctx, token, http.MethodGet, "/issues?"+query.Encode(), nil, &results); err != nil {
+			return nil, err
+		}
+
+		for i := range results {
+			// /issuesはPull Requestも返すため除外する
+			if results[i].PullRequest != nil {
+				continue
+			}
+			issue, err := results[i].issue()
+			if err != nil {
+				s.logger.WarnContext(ctx, "skipping malformed issue", "error", err)
+				continue
+			}
+			issues = append(issues, *issue)
+		}
+
+		if len(results) < 100 {
+			break
+		}
+	}
+
+	return issues, nil
+}
+
+// CreateIssue はリポジトリにIssueを作成する
+// labelsはIssueに付けるラベル（リポジトリにないラベルはGitHubが作成する。空の場合は付けない）
+func (s *ProjectService) CreateIssue(ctx context.Context, token, owner, repo, title, body string, labels []string) (*Issue, error) {
+	path := fmt.Sprintf("/repos/%s/%s/issues", owner, repo)
+	params := map[string]interface{}{
+		"title": title,
+		"body":  body,
+	}
+	if len(labels) > 0 {
+		params["labels"] = labels
+	}
+	var result issueResponse
+	err := s.client.RESTRequest(ctx, token, http.MethodPost, path, params, &result)
+	if err != nil {
+		return nil, err
+	}
+
+	return result.issue()
+}
+
+// GetIssue はリポジトリのIssueを取得する
+func (s *ProjectService) GetIssue(ctx context.Context, token, owner, repo string, number int) (*Issue, error) {
+	path := fmt.Sprintf("/repos/%s/%s/issues/%d", owner, repo, number)
+	var result issueResponse
+	if err := s.client.RESTRequest(ctx, token, http.MethodGet, path, nil, &result); err != nil {
+		return nil, err
+	}
+
+	return result.issue()
+}
+
+// issueResponse はREST APIのIssue
+type issueResponse struct {
+	NodeID     string `json:"node_id"`
+	Number     int    `json:"number"`
+	Title      string `json:"title"`
+	Body       string `json:"body"`
+	HTMLURL    string `json:"html_url"`
+	State      string `json:"state"`
+	Repository *struct {
+		FullName string `json:"full_name"`
+	} `json:"repository"`
+	UpdatedAt time.Time `json:"updated_at"`
+	// PullRequest はPull Requestの場合だけ値が入る
+	PullRequest *struct{} `json:"pull_request"`
+}
+
+// issue はREST APIのレスポンスからIssueを取得する
+func (r *issueResponse) issue() (*Issue, error) {
+	if r.Number == 0 {
+		return nil, fmt.Errorf("invalid issue response format")
+	}
+	if r.HTMLURL == "" {
+		return nil, fmt.Errorf("issue #%d has no url", r.Number)
+	}
+
+	issue := &Issue{
+		NodeID:    r.NodeID,
+		Number:    r.Number,
+		Title:     r.Title,
+		Body:      r.Body,
+		URL:       r.HTMLURL,
+		State:     r.State,
+		UpdatedAt: r.UpdatedAt,
+	}
+	if r.Repository != nil {
+		issue.Repository = r.Repository.FullName
+	}
+
+	return issue, nil
+}
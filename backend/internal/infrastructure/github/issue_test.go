@@ -0,0 +1,84 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+// roundTripFunc は関数をhttp.RoundTripperとして使う
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// newRecordingService はREST APIのリクエストの本文を記録し、作成したIssueを返すProjectServiceを作成する
+func newRecordingService(t *testing.T) (*ProjectService, *map[string]any) {
+	t.Helper()
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	client := NewClient(0, 0, time.Minute, logger)
+	var body map[string]any
+	client.httpClient.Transport = roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		if req.Method != http.MethodPost || req.URL.String() != restAPIBase+"/repos/octocat/hello/issues" {
+			t.Errorf("request = %s %s, want POST /repos/octocat/hello/issues", req.Method, req.URL)
+		}
+		if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+			t.Errorf("failed to decode request body: %v", err)
+		}
+		return &http.Response{
+			StatusCode: http.StatusCreated,
+			Status:     "201 Created",
+			Header:     http.Header{"Content-Type": []string{"application/json"}},
+			Body:       io.NopCloser(strings.NewReader(`{"node_id":"I_1","number":1,"title":"Task","html_url":"https://github.com/octocat/hello/issues/1","state":"open"}`)),
+			Request:    req,
+		}, nil
+	})
+	return NewProjectService(client, logger), &body
+}
+
+func TestCreateIssueLabels(t *testing.T) {
+	tests := []struct {
+		name   string
+		labels []string
+		want   []any
+	}{
+		{name: "default labels", labels: []string{"bug", "triage"}, want: []any{"bug", "triage"}},
+		{name: "no labels", labels: []string{}, want: nil},
+		{name: "nil labels", labels: nil, want: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, body := newRecordingService(t)
+
+			issue, err := s.CreateIssue(context.Background(), "token", "octocat", "hello", "Task", "body", tt.labels)
+			if err != nil {
+				t.Fatalf("CreateIssue() error = %v", err)
+			}
+			if issue.Number != 1 {
+				t.Errorf("issue number = %d, want 1", issue.Number)
+			}
+
+			labels, ok := (*body)["labels"].([]any)
+			if tt.want == nil {
+				// ラベルがない場合はlabelsを送らない（リポジトリの既定の動作に任せる）
+				if _, exists := (*body)["labels"]; exists {
+					t.Errorf("labels = %v, want no labels field", (*body)["labels"])
+				}
+				return
+			}
+			if !ok || !slices.Equal(labels, tt.want) {
+				t.Errorf("labels = %v, want %v", (*body)["labels"], tt.want)
+			}
+			if (*body)["title"] != "Task" || (*body)["body"] != "body" {
+				t.Errorf("title, body = %v, %v, want Task, body", (*body)["title"], (*body)["body"])
+			}
+		})
+	}
+}
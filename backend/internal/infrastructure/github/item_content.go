@@ -0,0 +1,157 @@
+package github
+
+import (
+	"context"
+	"fmt"
+)
+
+const (
+	// ContentTypeDraftIssue はProjectの中だけにあるDraft Issue
+	ContentTypeDraftIssue = "DraftIssue"
+	// ContentTypeIssue はリポジトリのIssue
+	ContentTypeIssue = "Issue"
+	// ContentTypePullRequest はリポジトリのPull Request
+	ContentTypePullRequest = "PullRequest"
+)
+
+// ItemContent はProjectのItemの内容（Draft Issue・Issue・Pull Request）を表す
+type ItemContent struct {
+	// Type は内容の種類（ContentTypeDraftIssue・ContentTypeIssue・ContentTypePullRequest、内容を取得できない場合は空）
+	Type  string
+	ID    string
+	Title string
+	Body  string
+}
+
+// GetItemContent はItemの内容を取得する（Itemが存在しない場合はErrNotFoundを返す）
+func (s *ProjectService) GetItemContent(ctx context.Context, token, itemID string) (*ItemContent, error) {
+	query := `
+		query($itemId: ID!) {
+			node(id: $itemId) {
+				... on ProjectV2Item {
+					id
+					content {
+						__typename
+						... on DraftIssue {
+							id
+							title
+							body
+						}
+						... on Issue {
+							id
+							title
+							body
+						}
+						... on PullRequest {
+							id
+							title
+							body
+						}
+					}
+				}
+			}
+		}
+	`
+
+	variables := map[string]interface{}{
+		"itemId": itemID,
+	}
+
+	node, err := s.queryItemNode(ctx, token, itemID, query, variables)
+	if err != nil {
+		return nil, err
+	}
+
+	content := &ItemContent{}
+	if c := node.Content; c != nil {
+		content.Type = c.Typename
+		content.ID = c.ID
+		content.Title = c.Title
+		content.Body = c.Body
+	}
+	return content, nil
+}
+
+// GetProjectItem はItemの内容とステータス・優先度を取得する（Itemが存在しない場合はErrNotFoundを返す）
+// StatusとPriorityはGetProjectItemsと同様にstatusField・priorityFieldの値を設定する（priorityFieldが空の場合は取得しない）
+func (s *ProjectService) GetProjectItem(ctx context.Context, token, itemID, statusField, priorityField string) (*ProjectItem, error) {
+	query := `
+		query($itemId: ID!, $statusField: String!, $priorityField: String!, $withPriority: Boolean!) {
+			node(id: $itemId) {
+				...projectItemFields
+			}
+		}
+	` + projectItemFragment
+
+	variables := map[string]interface{}{
+		"itemId":        itemID,
+		"statusField":   statusField,
+		"priorityField": priorityField,
+		"withPriority":  priorityField != "",
+	}
+
+	node, err := s.queryItemNode(ctx, token, itemID, query, variables)
+	if err != nil {
+		return nil, err
+	}
+
+	item := node.projectItem()
+	return &item, nil
+}
+
+// queryItemNode はnode(id:)でItemを取得するクエリを実行する（Itemが存在しない場合はErrNotFoundを返す）
+func (s *ProjectService) queryItemNode(ctx context.Context, token, itemID, query string, variables map[string]interface{}) (*projectItemNode, error) {
+	var data struct {
+		Node *projectItemNode `json:"node"`
+	}
+	if err := s.client.GraphQLRequest(ctx, token, query, variables, &data); err != nil {
+		return nil, err
+	}
+	// Item以外のノードのIDの場合はフラグメントに一致せず空のオブジェクトになる
+	if data.Node == nil || data.Node.ID == "" {
+		return nil, fmt.Errorf("project item %s: %w", itemID, ErrNotFound)
+	}
+	return data.Node, nil
+}
+
+// UpdateDraftIssue はDraft Issueのタイトルと本文を更新する
+func (s *ProjectService) UpdateDraftIssue(ctx context.Context, token, draftIssueID, title, body string) error {
+	query := `
+		mutation($draftIssueId: ID!, $title: String!, $body: String) {
+			updateProjectV2DraftIssue(input: {draftIssueId: $draftIssueId, title: $title, body: $body}) {
+				draftIssue {
+					id
+				}
+			}
+		}
+	`
+
+	variables := map[string]interface{}{
+		"draftIssueId": draftIssueID,
+		"title":        title,
+		"body":         body,
+	}
+
+	return s.client.GraphQLRequest(ctx, token, query, variables, nil)
+}
+
+// UpdateIssue はIssueのタイトルと本文を更新する
+func (s *ProjectService) UpdateIssue(ctx context.Context, token, issueID, title, body string) error {
+	query := `
+		mutation($issueId: ID!, $title: String!, $body: String) {
+			updateIssue(input: {id: $issueId, title: $title, body: $body}) {
+				issue {
+					id
+				}
+			}
+		}
+	`
+
+	variables := map[string]interface{}{
+		"issueId": issueID,
+		"title":   title,
+		"body":    body,
+	}
+
+	return s.client.GraphQLRequest(ctx, token, query, variables, nil)
+}
@@ -0,0 +1,82 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// Milestone はリポジトリのマイルストーンを表す
+type Milestone struct {
+	Number int
+	URL    string
+}
+
+// MilestoneInput はマイルストーン作成・更新時の入力を表す
+type MilestoneInput struct {
+	Title       string
+	Description string
+	DueOn       *time.Time
+}
+
+// CreateMilestone はリポジトリにマイルストーンを作成する
+func (s *ProjectService) CreateMilestone(ctx context.Context, token, owner, repo string, input MilestoneInput) (*Milestone, error) {
+	path := fmt.Sprintf("/repos/%s/%s/milestones", owner, repo)
+	var result milestoneResponse
+	if err := s.client.RESTRequest(ctx, token, http.MethodPost, path, milestoneBody(input), &result); err != nil {
+		return nil, err
+	}
+
+	return result.milestone()
+}
+
+// UpdateMilestone はリポジトリの既存マイルストーンを更新する
+func (s *ProjectService) UpdateMilestone(ctx context.Context, token, owner, repo string, number int, input MilestoneInput) (*Milestone, error) {
+	path := fmt.Sprintf("/repos/%s/%s/milestones/%d", owner, repo, number)
+	var result milestoneResponse
+	if err := s.client.RESTRequest(ctx, token, http.MethodPatch, path, milestoneBody(input), &result); err != nil {
+		return nil, err
+	}
+
+	return result.milestone()
+}
+
+// milestoneRequest はマイルストーンの作成・更新のリクエストボディ
+// due_onは未設定の場合nullを送り、GitHub側の期日もクリアする
+type milestoneRequest struct {
+	Title       string     `json:"title"`
+	Description string     `json:"description"`
+	DueOn       *time.Time `json:"due_on"`
+}
+
+// milestoneBody はREST APIのリクエストボディを作成する
+func milestoneBody(input MilestoneInput) milestoneRequest {
+	body := milestoneRequest{
+		Title:       input.Title,
+		Description: input.Description,
+	}
+	if input.DueOn != nil {
+		dueOn := input.DueOn.UTC().Truncate(time.Second)
+		body.DueOn = &dueOn
+	}
+	return body
+}
+
+// milestoneResponse はREST APIのマイルストーン
+type milestoneResponse struct {
+	Number  int    `json:"number"`
+	HTMLURL string `json:"html_url"`
+}
+
+// milestone はREST APIのレスポンスからマイルストーンを取得する
+func (r *milestoneResponse) milestone() (*Milestone, error) {
+	if r.Number == 0 {
+		return nil, fmt.Errorf("invalid milestone response format")
+	}
+
+	return &Milestone{
+		Number: r.Number,
+		URL:    r.HTMLURL,
+	}, nil
+}
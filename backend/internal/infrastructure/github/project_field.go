@@ -0,0 +1,118 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// FieldOption は単一選択フィールドの選択肢を表す
+type FieldOption struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// SingleSelectField はGitHub Projectの単一選択フィールドを表す
+type SingleSelectField struct {
+	ID      string        `json:"id"`
+	Name    string        `json:"name"`
+	Options []FieldOption `json:"options"`
+}
+
+// Option は名前が一致する選択肢を返す（大文字・小文字は区別しない）
+func (f *SingleSelectField) Option(name string) (FieldOption, bool) {
+	for _, option := range f.Options {
+		if strings.EqualFold(option.Name, name) {
+			return option, true
+		}
+	}
+	return FieldOption{}, false
+}
+
+// OptionNames は選択肢名の一覧を返す
+func (f *SingleSelectField) OptionNames() []string {
+	names := make([]string, 0, len(f.Options))
+	for _, option := range f.Options {
+		names = append(names, option.Name)
+	}
+	return names
+}
+
+// GetSingleSelectField はProjectの単一選択フィールドを選択肢付きで取得する（fieldCacheTTLの間キャッシュする）
+func (s *ProjectService) GetSingleSelectField(ctx context.Context, token, projectID, fieldName string) (*SingleSelectField, error) {
+	key := fieldCacheKey{projectID: projectID, name: fieldName, singleSelect: true}
+	if field, ok := s.fields.get(key, time.Now()); ok {
+		return field, nil
+	}
+
+	query := `
+		query($projectId: ID!, $name: String!) {
+			node(id: $projectId) {
+				... on ProjectV2 {
+					field(name: $name) {
+						... on ProjectV2SingleSelectField {
+							id
+							name
+							options {
+								id
+								name
+							}
+						}
+					}
+				}
+			}
+		}
+	`
+
+	variables := map[string]interface{}{
+		"projectId": projectID,
+		"name":      fieldName,
+	}
+
+	var data struct {
+		Node *struct {
+			Field *SingleSelectField `json:"field"`
+		} `json:"node"`
+	}
+	if err := s.client.GraphQLRequest(ctx, token, query, variables, &data); err != nil {
+		return nil, err
+	}
+	if data.Node == nil {
+		return nil, fmt.Errorf("project not found")
+	}
+	field := data.Node.Field
+	if field == nil {
+		return nil, fmt.Errorf("field %q not found", fieldName)
+	}
+
+	// 単一選択以外のフィールドの場合はフラグメントに一致せず空のオブジェクトになる
+	if field.ID == "" {
+		return nil, fmt.Errorf("field %q is not a single select field", fieldName)
+	}
+
+	s.fields.set(key, field, time.Now())
+	return field, nil
+}
+
+// UpdateItemSingleSelectField はItemの単一選択フィールドの値を更新する
+func (s *ProjectService) UpdateItemSingleSelectField(ctx context.Context, token, projectID, itemID, fieldID, optionID string) error {
+	query := `
+		mutation($projectId: ID!, $itemId: ID!, $fieldId: ID!, $optionId: String!) {
+			updateProjectV2ItemFieldValue(input: {projectId: $projectId, itemId: $itemId, fieldId: $fieldId, value: {singleSelectOptionId: $optionId}}) {
+				projectV2Item {
+					id
+				}
+			}
+		}
+	`
+
+	variables := map[string]interface{}{
+		"projectId": projectID,
+		"itemId":    itemID,
+		"fieldId":   fieldID,
+		"optionId":  optionID,
+	}
+
+	return s.client.GraphQLRequest(ctx, token, query, variables, nil)
+}
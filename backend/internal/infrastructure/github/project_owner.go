@@ -0,0 +1,148 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// OwnerType はGitHub Projectの所有者の種類を表す
+type OwnerType string
+
+const (
+	// OwnerTypeUser はユーザーのProject（user.projectV2）
+	OwnerTypeUser OwnerType = "user"
+	// OwnerTypeOrg はOrganizationのProject（organization.projectV2）
+	OwnerTypeOrg OwnerType = "org"
+	// OwnerTypeRepo はリポジトリに紐づくProject（repository.projectV2）
+	OwnerTypeRepo OwnerType = "repo"
+)
+
+// ProjectOwner はGitHub Projectを検索する所有者を表す
+// Repoを指定した場合はリポジトリに紐づくProject（repository.projectV2）として検索する
+// Repoを指定しない場合はTypeがOwnerTypeOrgならorganization、それ以外はuserとして検索する
+type ProjectOwner struct {
+	Login string
+	Repo  string
+	Type  OwnerType
+}
+
+// rootField はProjectを検索するGraphQLのルートフィールド名を返す
+func (o ProjectOwner) rootField() string {
+	switch {
+	case o.Repo != "":
+		return "repository"
+	case o.Type == OwnerTypeOrg:
+		return "organization"
+	}
+	return "user"
+}
+
+// projectQuery はprojectV2(number:)に対してselectionを取得するクエリを作成する
+// varsはselectionで使う追加の変数の宣言（例: "$name: String!"）
+func (o ProjectOwner) projectQuery(selection string, vars ...string) string {
+	decls := ""
+	if len(vars) > 0 {
+		decls = ", " + strings.Join(vars, ", ")
+	}
+	if o.Repo != "" {
+		return fmt.Sprintf(`
+		query($owner: String!, $repo: String!, $number: Int!%s) {
+			repository(owner: $owner, name: $repo) {
+				projectV2(number: $number) {
+					%s
+				}
+			}
+		}
+	`, decls, selection)
+	}
+	return fmt.Sprintf(`
+		query($owner: String!, $number: Int!%s) {
+			%s(login: $owner) {
+				projectV2(number: $number) {
+					%s
+				}
+			}
+		}
+	`, decls, o.rootField(), selection)
+}
+
+// projectVariables はprojectQueryの変数を作成する
+func (o ProjectOwner) projectVariables(projectNumber int) map[string]interface{} {
+	variables := map[string]interface{}{
+		"owner":  o.Login,
+		"number": projectNumber,
+	}
+	if o.Repo != "" {
+		variables["repo"] = o.Repo
+	}
+	return variables
+}
+
+// projectOwnerData はprojectQueryのレスポンスのdata（Pはselectionに合わせたprojectV2の型）
+// 所有者の種類に応じて、rootFieldのフィールドだけに値が入る
+type projectOwnerData[P any] struct {
+	User         *projectOwnerNode[P] `json:"user"`
+	Organization *projectOwnerNode[P] `json:"organization"`
+	Repository   *projectOwnerNode[P] `json:"repository"`
+}
+
+type projectOwnerNode[P any] struct {
+	ProjectV2 *P `json:"projectV2"`
+}
+
+// queryProject はprojectQueryで作成したクエリを実行し、projectV2をPの型で解析して返す
+func queryProject[P any](ctx context.Context, client *Client, token string, o ProjectOwner, query string, variables map[string]interface{}) (*P, error) {
+	var data projectOwnerData[P]
+	if err := client.GraphQLRequest(ctx, token, query, variables, &data); err != nil {
+		return nil, err
+	}
+
+	root := data.User
+	switch o.rootField() {
+	case "repository":
+		root = data.Repository
+	case "organization":
+		root = data.Organization
+	}
+	if root == nil {
+		return nil, fmt.Errorf("invalid %s format", o.rootField())
+	}
+	if root.ProjectV2 == nil {
+		return nil, fmt.Errorf("project not found")
+	}
+
+	return root.ProjectV2, nil
+}
+
+// GetOwnerType はloginのアカウントがユーザーかOrganizationかをGitHubに問い合わせる
+// アカウントが存在しない場合はErrNotFoundを返す
+func (s *ProjectService) GetOwnerType(ctx context.Context, token, login string) (OwnerType, error) {
+	query := `
+		query($login: String!) {
+			repositoryOwner(login: $login) {
+				__typename
+			}
+		}
+	`
+
+	var data struct {
+		RepositoryOwner *struct {
+			Typename string `json:"__typename"`
+		} `json:"repositoryOwner"`
+	}
+	if err := s.client.GraphQLRequest(ctx, token, query, map[string]interface{}{"login": login}, &data); err != nil {
+		return "", err
+	}
+	if data.RepositoryOwner == nil {
+		return "", fmt.Errorf("github owner %s: %w", login, ErrNotFound)
+	}
+
+	switch data.RepositoryOwner.Typename {
+	case "User":
+		return OwnerTypeUser, nil
+	case "Organization":
+		return OwnerTypeOrg, nil
+	}
+	return "", fmt.Errorf("unknown github owner type %q", data.RepositoryOwner.Typename)
+}
@@ -2,30 +2,63 @@ package github
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
+	"time"
+)
+
+const (
+	// projectItemsPageSize はProjectのItemsを1回のリクエストで取得する件数（GraphQL APIの上限）
+	projectItemsPageSize = 100
+	// projectItemsMaxPages はGetProjectItemsで取得する最大ページ数
+	projectItemsMaxPages = 50
 )
 
 // ProjectItem はGitHub ProjectのItemを表す
 type ProjectItem struct {
-	ID          string
-	Title       string
-	Body        string
-	Status      string
-	IssueNumber *int
-	IssueURL    *string
+	ID          string  `json:"id"`
+	Title       string  `json:"title"`
+	Body        string  `json:"body"`
+	Status      string  `json:"status"`
+	Priority    string  `json:"priority"`
+	IssueNumber *int    `json:"issue_number"`
+	IssueURL    *string `json:"issue_url"`
+	// PullRequestURL は内容がPull Requestの場合のURL
+	PullRequestURL *string `json:"pull_request_url"`
+	// ContentType はItemの内容の種類（DraftIssue・Issue・PullRequest、内容を取得できない場合は空）
+	ContentType string `json:"content_type"`
+	// ContentID は内容（Draft Issue・Issue・Pull Request）のノードID
+	ContentID string `json:"content_id"`
+	// UpdatedAt はItem（フィールドの値）と内容（タイトル・本文）のうち新しい方の更新日時
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// ProjectItemsPage はProjectのItemsの1ページ分を表す
+type ProjectItemsPage struct {
+	Items []ProjectItem `json:"items"`
+	// EndCursor は次のページを取得する時にafterに指定するカーソル
+	EndCursor   string `json:"end_cursor"`
+	HasNextPage bool   `json:"has_next_page"`
 }
 
 // Project はGitHub Projectを表す
 type Project struct {
-	ID     string
-	Number int
-	Title  string
+	ID     string `json:"id"`
+	Number int    `json:"number"`
+	Title  string `json:"title"`
+	// Owner・OwnerType はProjectを所有するユーザー・Organization（連携時のgithub_ownerに指定する）
+	Owner     string    `json:"owner"`
+	OwnerType OwnerType `json:"owner_type"`
 }
 
+// projectsPageSize は所有者ごとに取得するProjectの件数
+const projectsPageSize = 20
+
 // ProjectService はGitHub Projects V2のサービス
 type ProjectService struct {
 	client *Client
+	fields *fieldCache
 	logger *slog.Logger
 }
 
@@ -33,6 +66,7 @@ type ProjectService struct {
 func NewProjectService(client *Client, logger *slog.Logger) *ProjectService {
 	return &ProjectService{
 		client: client,
+		fields: newFieldCache(),
 		logger: logger,
 	}
 }
@@ -40,9 +74,10 @@ func NewProjectService(client *Client, logger *slog.Logger) *ProjectService {
 // GetUserProjects はユーザーのProjectsを取得する
 func (s *ProjectService) GetUserProjects(ctx context.Context, token string) ([]Project, error) {
 	query := `
-		query {
+		query($first: Int!) {
 			viewer {
-				projectsV2(first: 20) {
+				login
+				projectsV2(first: $first) {
 					nodes {
 						id
 						number
@@ -53,164 +88,320 @@ func (s *ProjectService) GetUserProjects(ctx context.Context, token string) ([]P
 		}
 	`
 
-	result, err := s.client.GraphQLRequest(ctx, token, query, nil)
-	if err != nil {
-		return nil, err
+	var data struct {
+		Viewer *projectsOwnerNode `json:"viewer"`
 	}
-
-	data, ok := result["data"].(map[string]interface{})
-	if !ok {
-		return nil, fmt.Errorf("invalid response format")
+	if err := s.client.GraphQLRequest(ctx, token, query, map[string]interface{}{"first": projectsPageSize}, &data); err != nil {
+		return nil, err
 	}
-
-	viewer, ok := data["viewer"].(map[string]interface{})
-	if !ok {
+	if data.Viewer == nil {
 		return nil, fmt.Errorf("invalid viewer format")
 	}
 
-	projectsV2, ok := viewer["projectsV2"].(map[string]interface{})
-	if !ok {
-		return nil, fmt.Errorf("invalid projectsV2 format")
+	return parseProjects(data.Viewer, data.Viewer.Login, OwnerTypeUser)
+}
+
+// GetOwnerProjects はユーザー・OrganizationのProjectsを取得する（リポジトリのProjectは対象外）
+// 他のユーザーのProjectは公開されているものだけ、OrganizationのProjectはトークンで閲覧できるものだけを返す
+func (s *ProjectService) GetOwnerProjects(ctx context.Context, token string, owner ProjectOwner) ([]Project, error) {
+	query := fmt.Sprintf(`
+		query($owner: String!, $first: Int!) {
+			%s(login: $owner) {
+				projectsV2(first: $first) {
+					nodes {
+						id
+						number
+						title
+					}
+				}
+			}
+		}
+	`, owner.rootField())
+
+	variables := map[string]interface{}{
+		"owner": owner.Login,
+		"first": projectsPageSize,
 	}
 
-	nodes, ok := projectsV2["nodes"].([]interface{})
-	if !ok {
-		return nil, fmt.Errorf("invalid nodes format")
+	var data struct {
+		User         *projectsOwnerNode `json:"user"`
+		Organization *projectsOwnerNode `json:"organization"`
+	}
+	if err := s.client.GraphQLRequest(ctx, token, query, variables, &data); err != nil {
+		return nil, err
 	}
 
-	var projects []Project
-	for _, node := range nodes {
-		n, ok := node.(map[string]interface{})
-		if !ok {
-			continue
-		}
-		projects = append(projects, Project{
-			ID:     n["id"].(string),
-			Number: int(n["number"].(float64)),
-			Title:  n["title"].(string),
-		})
+	root := data.User
+	if owner.rootField() == "organization" {
+		root = data.Organization
+	}
+	if root == nil {
+		return nil, fmt.Errorf("github owner %s: %w", owner.Login, ErrNotFound)
 	}
 
-	return projects, nil
+	return parseProjects(root, owner.Login, owner.Type)
 }
 
-// GetProjectItems はProjectのItemsを取得する
-func (s *ProjectService) GetProjectItems(ctx context.Context, token, owner string, projectNumber int) ([]ProjectItem, error) {
+// GetViewerOrganizations は認証ユーザーが所属するOrganizationのloginを取得する
+// read:orgスコープのないトークンでは、所属を公開しているOrganizationだけを返す
+func (s *ProjectService) GetViewerOrganizations(ctx context.Context, token string) ([]string, error) {
 	query := `
-		query($owner: String!, $number: Int!) {
-			user(login: $owner) {
-				projectV2(number: $number) {
-					items(first: 100) {
-						nodes {
-							id
-							content {
-								... on Issue {
-									title
-									body
-									number
-									url
-								}
-								... on DraftIssue {
-									title
-									body
-								}
-							}
-							fieldValueByName(name: "Status") {
-								... on ProjectV2ItemFieldSingleSelectValue {
-									name
-								}
-							}
-						}
+		query($first: Int!) {
+			viewer {
+				organizations(first: $first) {
+					nodes {
+						login
 					}
 				}
 			}
 		}
 	`
 
-	variables := map[string]interface{}{
-		"owner":  owner,
-		"number": projectNumber,
+	var data struct {
+		Viewer *struct {
+			Organizations *connection[struct {
+				Login string `json:"login"`
+			}] `json:"organizations"`
+		} `json:"viewer"`
 	}
-
-	result, err := s.client.GraphQLRequest(ctx, token, query, variables)
-	if err != nil {
+	if err := s.client.GraphQLRequest(ctx, token, query, map[string]interface{}{"first": projectsPageSize}, &data); err != nil {
 		return nil, err
 	}
-
-	// レスポンスをパース
-	items, err := s.parseProjectItems(result)
-	if err != nil {
-		return nil, err
+	if data.Viewer == nil {
+		return nil, fmt.Errorf("invalid viewer format")
 	}
-
-	return items, nil
-}
-
-func (s *ProjectService) parseProjectItems(result map[string]interface{}) ([]ProjectItem, error) {
-	data, ok := result["data"].(map[string]interface{})
-	if !ok {
-		return nil, fmt.Errorf("invalid response format")
+	if data.Viewer.Organizations == nil {
+		return nil, fmt.Errorf("invalid organizations format")
 	}
 
-	user, ok := data["user"].(map[string]interface{})
-	if !ok {
-		return nil, fmt.Errorf("invalid user format")
+	var logins []string
+	for _, node := range data.Viewer.Organizations.Nodes {
+		if node != nil && node.Login != "" {
+			logins = append(logins, node.Login)
+		}
 	}
 
-	projectV2, ok := user["projectV2"].(map[string]interface{})
-	if !ok {
-		return nil, fmt.Errorf("invalid projectV2 format")
-	}
+	return logins, nil
+}
 
-	itemsData, ok := projectV2["items"].(map[string]interface{})
-	if !ok {
-		return nil, fmt.Errorf("invalid items format")
-	}
+// projectsOwnerNode はProjectsを取得する所有者（viewer・user・organization）
+type projectsOwnerNode struct {
+	Login      string `json:"login"`
+	ProjectsV2 *connection[struct {
+		ID     string `json:"id"`
+		Number int    `json:"number"`
+		Title  string `json:"title"`
+	}] `json:"projectsV2"`
+}
 
-	nodes, ok := itemsData["nodes"].([]interface{})
-	if !ok {
-		return nil, fmt.Errorf("invalid nodes format")
+// parseProjects は所有者（viewer・user・organization）のprojectsV2からProjectsを取得する
+func parseProjects(root *projectsOwnerNode, owner string, ownerType OwnerType) ([]Project, error) {
+	if root.ProjectsV2 == nil {
+		return nil, fmt.Errorf("invalid projectsV2 format")
 	}
 
-	var items []ProjectItem
-	for _, node := range nodes {
-		n, ok := node.(map[string]interface{})
-		if !ok {
+	projects := []Project{}
+	for _, node := range root.ProjectsV2.Nodes {
+		// アクセスできないProjectはnullになる
+		if node == nil || node.ID == "" {
 			continue
 		}
+		projects = append(projects, Project{
+			ID:        node.ID,
+			Number:    node.Number,
+			Title:     node.Title,
+			Owner:     owner,
+			OwnerType: ownerType,
+		})
+	}
 
-		item := ProjectItem{
-			ID: n["id"].(string),
-		}
+	return projects, nil
+}
 
-		if content, ok := n["content"].(map[string]interface{}); ok {
-			if title, ok := content["title"].(string); ok {
-				item.Title = title
+// projectItemFragment はItemの内容とステータス・優先度のフィールドの値を取得するフラグメント
+// 使用するクエリは$statusField・$priorityField・$withPriorityを宣言する
+const projectItemFragment = `
+	fragment projectItemFields on ProjectV2Item {
+		id
+		updatedAt
+		content {
+			__typename
+			... on Issue {
+				id
+				title
+				body
+				number
+				url
+				updatedAt
 			}
-			if body, ok := content["body"].(string); ok {
-				item.Body = body
+			... on DraftIssue {
+				id
+				title
+				body
+				updatedAt
 			}
-			if number, ok := content["number"].(float64); ok {
-				num := int(number)
-				item.IssueNumber = &num
+			... on PullRequest {
+				id
+				title
+				body
+				number
+				url
+				updatedAt
 			}
-			if url, ok := content["url"].(string); ok {
-				item.IssueURL = &url
+		}
+		status: fieldValueByName(name: $statusField) {
+			... on ProjectV2ItemFieldSingleSelectValue {
+				name
+			}
+		}
+		priority: fieldValueByName(name: $priorityField) @include(if: $withPriority) {
+			... on ProjectV2ItemFieldSingleSelectValue {
+				name
 			}
 		}
+	}
+`
 
-		if fieldValue, ok := n["fieldValueByName"].(map[string]interface{}); ok {
-			if name, ok := fieldValue["name"].(string); ok {
-				item.Status = name
+// GetProjectItems はProjectのItemsをすべて取得する（projectItemsMaxPagesページまで）
+// StatusとPriorityにはそれぞれstatusField・priorityFieldの単一選択フィールドの値を設定する（priorityFieldが空の場合は取得しない）
+func (s *ProjectService) GetProjectItems(ctx context.Context, token string, owner ProjectOwner, projectNumber int, statusField, priorityField string) ([]ProjectItem, error) {
+	var items []ProjectItem
+	cursor := ""
+	for page := 1; page <= projectItemsMaxPages; page++ {
+		// 2ページ目以降は残りポイントを確認し、途中で上限に達してまとめて失敗しないようにする
+		if page > 1 {
+			if err := s.client.CheckBudget(token, 0); err != nil {
+				return nil, err
 			}
 		}
 
-		items = append(items, item)
+		result, err := s.GetProjectItemsPage(ctx, token, owner, projectNumber, statusField, priorityField, cursor)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, result.Items...)
+
+		if !result.HasNextPage {
+			return items, nil
+		}
+		cursor = result.EndCursor
 	}
 
+	s.logger.WarnContext(ctx, "github project items truncated", "project_number", projectNumber, "items", len(items))
 	return items, nil
 }
 
+// GetProjectItemsPage はProjectのItemsをafterのカーソルの次から1ページ（projectItemsPageSize件）取得する（afterが空の場合は先頭から）
+// StatusとPriorityはGetProjectItemsと同様にstatusField・priorityFieldの値を設定する
+func (s *ProjectService) GetProjectItemsPage(ctx context.Context, token string, owner ProjectOwner, projectNumber int, statusField, priorityField, after string) (*ProjectItemsPage, error) {
+	query := owner.projectQuery(fmt.Sprintf(`items(first: %d, after: $after) {
+						pageInfo {
+							hasNextPage
+							endCursor
+						}
+						nodes {
+							...projectItemFields
+						}
+					}`, projectItemsPageSize), "$after: String", "$statusField: String!", "$priorityField: String!", "$withPriority: Boolean!") + projectItemFragment
+
+	variables := owner.projectVariables(projectNumber)
+	if after != "" {
+		variables["after"] = after
+	}
+	variables["statusField"] = statusField
+	variables["priorityField"] = priorityField
+	variables["withPriority"] = priorityField != ""
+
+	projectV2, err := queryProject[struct {
+		Items *connection[projectItemNode] `json:"items"`
+	}](ctx, s.client, token, owner, query, variables)
+	if err != nil {
+		return nil, err
+	}
+	if projectV2.Items == nil {
+		return nil, fmt.Errorf("invalid items format")
+	}
+
+	page := &ProjectItemsPage{Items: []ProjectItem{}}
+	for _, node := range projectV2.Items.Nodes {
+		// 権限のないItemはnullになる
+		if node == nil || node.ID == "" {
+			continue
+		}
+		page.Items = append(page.Items, node.projectItem())
+	}
+
+	page.HasNextPage = projectV2.Items.PageInfo.HasNextPage
+	page.EndCursor = projectV2.Items.PageInfo.EndCursor
+	// カーソルが返らない場合は次のページを取得できないため、最後のページとして扱う
+	if page.EndCursor == "" {
+		page.HasNextPage = false
+	}
+
+	return page, nil
+}
+
+// projectItemNode はprojectItemFragmentで取得したItem
+type projectItemNode struct {
+	ID        string    `json:"id"`
+	UpdatedAt time.Time `json:"updatedAt"`
+	// Content はItemの内容（アクセスできないリポジトリのIssue等はnull）
+	Content  *itemContentNode       `json:"content"`
+	Status   *singleSelectValueNode `json:"status"`
+	Priority *singleSelectValueNode `json:"priority"`
+}
+
+// itemContentNode はItemの内容（DraftIssue・Issue・PullRequest）
+// 種類ごとに取得したフィールドだけに値が入る（Draft Issueにはnumber・urlがない）
+type itemContentNode struct {
+	Typename  string    `json:"__typename"`
+	ID        string    `json:"id"`
+	Title     string    `json:"title"`
+	Body      string    `json:"body"`
+	Number    *int      `json:"number"`
+	URL       *string   `json:"url"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// singleSelectValueNode は単一選択フィールドの値（単一選択以外のフィールドの場合はNameが空になる）
+type singleSelectValueNode struct {
+	Name string `json:"name"`
+}
+
+// projectItem はItemをProjectItemに変換する
+func (n *projectItemNode) projectItem() ProjectItem {
+	item := ProjectItem{
+		ID:        n.ID,
+		UpdatedAt: n.UpdatedAt,
+	}
+
+	if content := n.Content; content != nil {
+		item.ContentType = content.Typename
+		item.ContentID = content.ID
+		item.Title = content.Title
+		item.Body = content.Body
+		if content.UpdatedAt.After(item.UpdatedAt) {
+			item.UpdatedAt = content.UpdatedAt
+		}
+		switch content.Typename {
+		case ContentTypeIssue:
+			item.IssueNumber = content.Number
+			item.IssueURL = content.URL
+		case ContentTypePullRequest:
+			item.PullRequestURL = content.URL
+		}
+	}
+
+	if n.Status != nil {
+		item.Status = n.Status.Name
+	}
+	if n.Priority != nil {
+		item.Priority = n.Priority.Name
+	}
+
+	return item
+}
+
 // AddDraftIssueToProject はProjectにDraft Issueを追加する
 func (s *ProjectService) AddDraftIssueToProject(ctx context.Context, token, projectID, title, body string) (*ProjectItem, error) {
 	query := `
@@ -229,39 +420,35 @@ func (s *ProjectService) AddDraftIssueToProject(ctx context.Context, token, proj
 		"body":      body,
 	}
 
-	result, err := s.client.GraphQLRequest(ctx, token, query, variables)
-	if err != nil {
-		return nil, err
+	var data struct {
+		AddProjectV2DraftIssue *struct {
+			ProjectItem *nodeID `json:"projectItem"`
+		} `json:"addProjectV2DraftIssue"`
 	}
-
-	data, ok := result["data"].(map[string]interface{})
-	if !ok {
-		return nil, fmt.Errorf("invalid response format")
+	if err := s.client.GraphQLRequest(ctx, token, query, variables, &data); err != nil {
+		return nil, err
 	}
-
-	addResult, ok := data["addProjectV2DraftIssue"].(map[string]interface{})
-	if !ok {
+	if data.AddProjectV2DraftIssue == nil {
 		return nil, fmt.Errorf("invalid addProjectV2DraftIssue format")
 	}
-
-	projectItem, ok := addResult["projectItem"].(map[string]interface{})
-	if !ok {
+	if data.AddProjectV2DraftIssue.ProjectItem == nil || data.AddProjectV2DraftIssue.ProjectItem.ID == "" {
 		return nil, fmt.Errorf("invalid projectItem format")
 	}
 
 	return &ProjectItem{
-		ID:    projectItem["id"].(string),
-		Title: title,
-		Body:  body,
+		ID:          data.AddProjectV2DraftIssue.ProjectItem.ID,
+		Title:       title,
+		Body:        body,
+		ContentType: ContentTypeDraftIssue,
 	}, nil
 }
 
-// GetProjectID はowner/project_numberからProject IDを取得する
-func (s *ProjectService) GetProjectID(ctx context.Context, token, owner string, projectNumber int) (string, error) {
+// AddIssueToProject はProjectに既存のIssueを追加する（追加済みの場合は既存のItemを返す）
+func (s *ProjectService) AddIssueToProject(ctx context.Context, token, projectID, issueNodeID string) (*ProjectItem, error) {
 	query := `
-		query($owner: String!, $number: Int!) {
-			user(login: $owner) {
-				projectV2(number: $number) {
+		mutation($projectId: ID!, $contentId: ID!) {
+			addProjectV2ItemById(input: {projectId: $projectId, contentId: $contentId}) {
+				item {
 					id
 				}
 			}
@@ -269,31 +456,112 @@ func (s *ProjectService) GetProjectID(ctx context.Context, token, owner string,
 	`
 
 	variables := map[string]interface{}{
-		"owner":  owner,
-		"number": projectNumber,
+		"projectId": projectID,
+		"contentId": issueNodeID,
+	}
+
+	var data struct {
+		AddProjectV2ItemById *struct {
+			Item *nodeID `json:"item"`
+		} `json:"addProjectV2ItemById"`
+	}
+	if err := s.client.GraphQLRequest(ctx, token, query, variables, &data); err != nil {
+		return nil, err
+	}
+	if data.AddProjectV2ItemById == nil {
+		return nil, fmt.Errorf("invalid addProjectV2ItemById format")
+	}
+	if data.AddProjectV2ItemById.Item == nil || data.AddProjectV2ItemById.Item.ID == "" {
+		return nil, fmt.Errorf("invalid item format")
 	}
 
-	result, err := s.client.GraphQLRequest(ctx, token, query, variables)
+	return &ProjectItem{ID: data.AddProjectV2ItemById.Item.ID, ContentType: ContentTypeIssue, ContentID: issueNodeID}, nil
+}
+
+// GetProjectID はowner/project_numberからProject IDを取得する
+func (s *ProjectService) GetProjectID(ctx context.Context, token string, owner ProjectOwner, projectNumber int) (string, error) {
+	query := owner.projectQuery(`id`)
+
+	projectV2, err := queryProject[nodeID](ctx, s.client, token, owner, query, owner.projectVariables(projectNumber))
 	if err != nil {
 		return "", err
 	}
+	if projectV2.ID == "" {
+		return "", fmt.Errorf("invalid project format")
+	}
 
-	data, ok := result["data"].(map[string]interface{})
-	if !ok {
-		return "", fmt.Errorf("invalid response format")
+	return projectV2.ID, nil
+}
+
+// GetFieldID はProjectのフィールド名からフィールドIDを取得する（fieldCacheTTLの間キャッシュする）
+func (s *ProjectService) GetFieldID(ctx context.Context, token, projectID, fieldName string) (string, error) {
+	key := fieldCacheKey{projectID: projectID, name: fieldName}
+	if field, ok := s.fields.get(key, time.Now()); ok {
+		return field.ID, nil
 	}
 
-	user, ok := data["user"].(map[string]interface{})
-	if !ok {
-		return "", fmt.Errorf("invalid user format")
+	query := `
+		query($projectId: ID!, $name: String!) {
+			node(id: $projectId) {
+				... on ProjectV2 {
+					field(name: $name) {
+						... on ProjectV2FieldCommon {
+							id
+						}
+					}
+				}
+			}
+		}
+	`
+
+	variables := map[string]interface{}{
+		"projectId": projectID,
+		"name":      fieldName,
 	}
 
-	projectV2, ok := user["projectV2"].(map[string]interface{})
-	if !ok {
+	var data struct {
+		Node *struct {
+			Field *nodeID `json:"field"`
+		} `json:"node"`
+	}
+	if err := s.client.GraphQLRequest(ctx, token, query, variables, &data); err != nil {
+		return "", err
+	}
+	if data.Node == nil {
 		return "", fmt.Errorf("project not found")
 	}
+	if data.Node.Field == nil {
+		return "", fmt.Errorf("field %q not found", fieldName)
+	}
+	if data.Node.Field.ID == "" {
+		return "", fmt.Errorf("invalid field format")
+	}
 
-	return projectV2["id"].(string), nil
+	id := data.Node.Field.ID
+	s.fields.set(key, &SingleSelectField{ID: id, Name: fieldName}, time.Now())
+	return id, nil
+}
+
+// UpdateItemNumberField はItemの数値フィールドの値を更新する
+func (s *ProjectService) UpdateItemNumberField(ctx context.Context, token, projectID, itemID, fieldID string, value float64) error {
+	query := `
+		mutation($projectId: ID!, $itemId: ID!, $fieldId: ID!, $value: Float!) {
+			updateProjectV2ItemFieldValue(input: {projectId: $projectId, itemId: $itemId, fieldId: $fieldId, value: {number: $value}}) {
+				projectV2Item {
+					id
+				}
+			}
+		}
+	`
+
+	variables := map[string]interface{}{
+		"projectId": projectID,
+		"itemId":    itemID,
+		"fieldId":   fieldID,
+		"value":     value,
+	}
+
+	return s.client.GraphQLRequest(ctx, token, query, variables, nil)
 }
 
 // DeleteProjectItem はProjectからItemを削除する
@@ -311,6 +579,35 @@ func (s *ProjectService) DeleteProjectItem(ctx context.Context, token, projectID
 		"itemId":    itemID,
 	}
 
-	_, err := s.client.GraphQLRequest(ctx, token, query, variables)
-	return err
+	return s.client.GraphQLRequest(ctx, token, query, variables, nil)
+}
+
+// ItemExists はProjectのItemがGitHub上に存在するかを返す
+func (s *ProjectService) ItemExists(ctx context.Context, token, itemID string) (bool, error) {
+	query := `
+		query($itemId: ID!) {
+			node(id: $itemId) {
+				... on ProjectV2Item {
+					id
+				}
+			}
+		}
+	`
+
+	variables := map[string]interface{}{
+		"itemId": itemID,
+	}
+
+	var data struct {
+		Node *nodeID `json:"node"`
+	}
+	err := s.client.GraphQLRequest(ctx, token, query, variables, &data)
+	if errors.Is(err, ErrNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	return data.Node != nil && data.Node.ID != "", nil
 }
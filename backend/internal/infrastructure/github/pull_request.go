@@ -0,0 +1,228 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// PullRequest はリポジトリのPull Requestを表す
+type PullRequest struct {
+	Number int
+	Title  string
+	URL    string
+	// State はopen・closed・mergedのいずれか
+	State     string
+	HeadRef   string
+	Body      string
+	MergedAt  *time.Time
+	UpdatedAt time.Time
+	// ChecksState は先頭コミットのCheck・コミットステータスの集計（success・failure・error・pending・expected、未実行の場合は空）
+	ChecksState string
+}
+
+// IssuePullRequests はIssueの状態とIssueを参照している可能性のあるPull Requestを表す
+type IssuePullRequests struct {
+	// IssueState はopenまたはclosed
+	IssueState string
+	// Closing はGitHubがIssueをクローズするPull Requestとして認識しているもの
+	Closing []PullRequest
+	// Recent はリポジトリで最近更新されたPull Request（本文・ブランチ名からの参照の検出に使う）
+	Recent []PullRequest
+}
+
+// closingKeywordPattern はIssueをクローズするキーワード（closes #N など）に一致する
+var closingKeywordPattern = regexp.MustCompile(`(?i)\b(?:close[sd]?|fix(?:e[sd])?|resolve[sd]?)\s*:?\s+#(\d+)\b`)
+
+// ClosesIssue は本文にIssueをクローズするキーワード（closes #N / fixes #N / resolves #N）があるかを返す
+func (p *PullRequest) ClosesIssue(number int) bool {
+	for _, match := range closingKeywordPattern.FindAllStringSubmatch(p.Body, -1) {
+		if n, err := strconv.Atoi(match[1]); err == nil && n == number {
+			return true
+		}
+	}
+	return false
+}
+
+// BranchReferencesIssue はブランチ名がIssue番号を区切りとして含むかを返す（123-fix-login、feature/issue-123 など）
+func (p *PullRequest) BranchReferencesIssue(number int) bool {
+	n := strconv.Itoa(number)
+	for _, part := range strings.FieldsFunc(p.HeadRef, func(r rune) bool {
+		return r == '/' || r == '-' || r == '_' || r == '#'
+	}) {
+		if part == n {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseIssueURL はIssueのURL（https://github.com/owner/repo/issues/N）からowner・repo・番号を取得する
+func ParseIssueURL(issueURL string) (owner, repo string, number int, err error) {
+	u, err := url.Parse(issueURL)
+	if err != nil {
+		return "", "", 0, fmt.Errorf("failed to parse issue url: %w", err)
+	}
+
+	parts := strings.Split(strings.Trim(u.Path, "/"), "/")
+	if len(parts) != 4 || parts[2] != "issues" {
+		return "", "", 0, fmt.Errorf("invalid issue url: %s", issueURL)
+	}
+	number, err = strconv.Atoi(parts[3])
+	if err != nil {
+		return "", "", 0, fmt.Errorf("invalid issue number: %s", issueURL)
+	}
+
+	return parts[0], parts[1], number, nil
+}
+
+// GetIssuePullRequests はIssueの状態と、Issueをクローズする・最近更新されたPull Requestを取得する
+func (s *ProjectService) GetIssuePullRequests(ctx context.Context, token, owner, repo string, number int) (*IssuePullRequests, error) {
+	query := `
+		query($owner: String!, $name: String!, $number: Int!) {
+			repository(owner: $owner, name: $name) {
+				issue(number: $number) {
+					state
+					closedByPullRequestsReferences(first: 20, includeClosedPrs: true) {
+						nodes {
+							number
+							title
+							url
+							state
+							headRefName
+							body
+							mergedAt
+							updatedAt
+							commits(last: 1) {
+								nodes {
+									commit {
+										statusCheckRollup {
+											state
+										}
+									}
+								}
+							}
+						}
+					}
+				}
+				pullRequests(first: 50, orderBy: {field: UPDATED_AT, direction: DESC}) {
+					nodes {
+						number
+						title
+						url
+						state
+						headRefName
+						body
+						mergedAt
+						updatedAt
+						commits(last: 1) {
+							nodes {
+								commit {
+									statusCheckRollup {
+										state
+									}
+								}
+							}
+						}
+					}
+				}
+			}
+		}
+	`
+
+	variables := map[string]interface{}{
+		"owner":  owner,
+		"name":   repo,
+		"number": number,
+	}
+
+	var data struct {
+		Repository *struct {
+			Issue *struct {
+				State                          string                       `json:"state"`
+				ClosedByPullRequestsReferences *connection[pullRequestNode] `json:"closedByPullRequestsReferences"`
+			} `json:"issue"`
+			PullRequests *connection[pullRequestNode] `json:"pullRequests"`
+		} `json:"repository"`
+	}
+	if err := s.client.GraphQLRequest(ctx, token, query, variables, &data); err != nil {
+		return nil, err
+	}
+	if data.Repository == nil {
+		return nil, fmt.Errorf("repository not found")
+	}
+	issue := data.Repository.Issue
+	if issue == nil {
+		return nil, fmt.Errorf("issue not found")
+	}
+
+	links := &IssuePullRequests{IssueState: strings.ToLower(issue.State)}
+	if issue.ClosedByPullRequestsReferences != nil {
+		links.Closing = parsePullRequests(issue.ClosedByPullRequestsReferences)
+	}
+	if data.Repository.PullRequests != nil {
+		links.Recent = parsePullRequests(data.Repository.PullRequests)
+	}
+
+	return links, nil
+}
+
+// pullRequestNode はGetIssuePullRequestsで取得したPull Request
+type pullRequestNode struct {
+	Number      int        `json:"number"`
+	Title       string     `json:"title"`
+	URL         string     `json:"url"`
+	State       string     `json:"state"`
+	HeadRefName string     `json:"headRefName"`
+	Body        string     `json:"body"`
+	MergedAt    *time.Time `json:"mergedAt"`
+	UpdatedAt   time.Time  `json:"updatedAt"`
+	// Commits は先頭コミット（commits(last: 1)）
+	Commits *connection[struct {
+		Commit *struct {
+			StatusCheckRollup *struct {
+				State string `json:"state"`
+			} `json:"statusCheckRollup"`
+		} `json:"commit"`
+	}] `json:"commits"`
+}
+
+// parsePullRequests はGraphQLのコネクションからPull Requestを取得する
+func parsePullRequests(prs *connection[pullRequestNode]) []PullRequest {
+	var pullRequests []PullRequest
+	for _, node := range prs.Nodes {
+		if node == nil || node.Number == 0 {
+			continue
+		}
+
+		pullRequests = append(pullRequests, PullRequest{
+			Number:      node.Number,
+			Title:       node.Title,
+			URL:         node.URL,
+			State:       strings.ToLower(node.State),
+			HeadRef:     node.HeadRefName,
+			Body:        node.Body,
+			MergedAt:    node.MergedAt,
+			UpdatedAt:   node.UpdatedAt,
+			ChecksState: node.checksState(),
+		})
+	}
+
+	return pullRequests
+}
+
+// checksState はPull Requestの先頭コミットのstatusCheckRollupの状態を取得する
+func (n *pullRequestNode) checksState() string {
+	if n.Commits == nil || len(n.Commits.Nodes) == 0 {
+		return ""
+	}
+	last := n.Commits.Nodes[len(n.Commits.Nodes)-1]
+	if last == nil || last.Commit == nil || last.Commit.StatusCheckRollup == nil {
+		return ""
+	}
+	return strings.ToLower(last.Commit.StatusCheckRollup.State)
+}
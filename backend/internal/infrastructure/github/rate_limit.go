@@ -0,0 +1,159 @@
+package github
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ErrBudgetExhausted はGraphQL APIの残りポイントが下限を下回っているため、処理を見送ったことを表す
+var ErrBudgetExhausted = errors.New("github graphql budget exhausted")
+
+// rateLimitSelection はクエリに追加してコストと残りポイントを取得するフィールド
+const rateLimitSelection = "rateLimit { cost remaining resetAt }\n"
+
+// RateLimit はGraphQL APIのレート制限の状態を表す
+type RateLimit struct {
+	// Cost は直前のクエリで消費したポイント
+	Cost      int
+	Remaining int
+	ResetAt   time.Time
+}
+
+type userContextKey struct{}
+
+// WithUser はGitHub APIの利用量をユーザーごとに記録するため、ctxにユーザーIDを設定する
+func WithUser(ctx context.Context, userID string) context.Context {
+	return context.WithValue(ctx, userContextKey{}, userID)
+}
+
+// userFromContext はWithUserで設定したユーザーIDを取得する
+func userFromContext(ctx context.Context) string {
+	userID, _ := ctx.Value(userContextKey{}).(string)
+	return userID
+}
+
+// tokenKey はトークンごとのレート制限を記録するキー（トークン自体は保持しない）
+func tokenKey(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:8])
+}
+
+// isMutation はクエリがmutationかを返す
+func isMutation(query string) bool {
+	return strings.HasPrefix(strings.TrimSpace(query), "mutation")
+}
+
+// withRateLimit はクエリのルートにrateLimitを追加する（mutationはrateLimitを取得できないためそのまま返す）
+func withRateLimit(query string) string {
+	if isMutation(query) {
+		return query
+	}
+	i := strings.LastIndex(query, "}")
+	if i < 0 {
+		return query
+	}
+	return query[:i] + rateLimitSelection + query[i:]
+}
+
+// rateLimitData はwithRateLimitで追加したdata.rateLimit
+type rateLimitData struct {
+	RateLimit *struct {
+		Cost      int       `json:"cost"`
+		Remaining int       `json:"remaining"`
+		ResetAt   time.Time `json:"resetAt"`
+	} `json:"rateLimit"`
+}
+
+// parseRateLimit はレスポンスのdata.rateLimitを取得する
+func parseRateLimit(data json.RawMessage) (RateLimit, bool) {
+	var result rateLimitData
+	if len(data) == 0 || json.Unmarshal(data, &result) != nil || result.RateLimit == nil {
+		return RateLimit{}, false
+	}
+	return RateLimit{
+		Cost:      result.RateLimit.Cost,
+		Remaining: result.RateLimit.Remaining,
+		ResetAt:   result.RateLimit.ResetAt,
+	}, true
+}
+
+// recordRateLimit はトークンの残りポイントとユーザーごとの消費量を記録してログに出力する
+func (c *Client) recordRateLimit(ctx context.Context, token string, limit RateLimit) {
+	userID := userFromContext(ctx)
+
+	c.mu.Lock()
+	c.rateLimits[tokenKey(token)] = limit
+	c.usage[userID] += limit.Cost
+	total := c.usage[userID]
+	c.mu.Unlock()
+
+	c.logger.InfoContext(ctx, "github graphql cost",
+		"user_id", userID, "cost", limit.Cost, "remaining", limit.Remaining, "reset_at", limit.ResetAt, "user_total_cost", total)
+	if limit.Remaining < c.budgetFloor {
+		c.logger.WarnContext(ctx, "github graphql budget below floor",
+			"user_id", userID, "remaining", limit.Remaining, "floor", c.budgetFloor, "reset_at", limit.ResetAt)
+	}
+}
+
+// CheckBudget はexpectedCostポイントを消費しても残りポイントが下限を下回らないかを確認する
+// 直近のレスポンスで残りポイントが分からない場合やリセット時刻を過ぎている場合は許可する
+func (c *Client) CheckBudget(token string, expectedCost int) error {
+	c.mu.Lock()
+	limit, ok := c.rateLimits[tokenKey(token)]
+	c.mu.Unlock()
+
+	if !ok || time.Now().After(limit.ResetAt) {
+		return nil
+	}
+	if limit.Remaining-expectedCost < c.budgetFloor {
+		return fmt.Errorf("remaining %d is below floor %d until %s: %w",
+			limit.Remaining, c.budgetFloor, limit.ResetAt.Format(time.RFC3339), ErrBudgetExhausted)
+	}
+	return nil
+}
+
+// CheckBudget はexpectedCostポイントを消費しても残りポイントが下限を下回らないかを確認する
+func (s *ProjectService) CheckBudget(token string, expectedCost int) error {
+	return s.client.CheckBudget(token, expectedCost)
+}
+
+// QuotaSnapshot はGraphQL APIのレート制限の状態をトークン全体で集計したもの（トークン・ユーザーは含まない）
+type QuotaSnapshot struct {
+	// TrackedTokens はリセット前の残りポイントを把握しているトークンの数
+	TrackedTokens int
+	// LowestRemaining はトークンの中で最も少ない残りポイント（把握しているトークンがない場合はnil）
+	LowestRemaining *int
+	// ResetAt はLowestRemainingのトークンのリセット時刻
+	ResetAt *time.Time
+}
+
+// QuotaSnapshot は直近のレスポンスで把握したレート制限の状態を集計する（リセット時刻を過ぎたものは除く）
+func (c *Client) QuotaSnapshot(now time.Time) QuotaSnapshot {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var snapshot QuotaSnapshot
+	for _, limit := range c.rateLimits {
+		if now.After(limit.ResetAt) {
+			continue
+		}
+		snapshot.TrackedTokens++
+		if snapshot.LowestRemaining == nil || limit.Remaining < *snapshot.LowestRemaining {
+			remaining, resetAt := limit.Remaining, limit.ResetAt
+			snapshot.LowestRemaining = &remaining
+			snapshot.ResetAt = &resetAt
+		}
+	}
+	return snapshot
+}
+
+// QuotaSnapshot は直近のレスポンスで把握したレート制限の状態を集計する
+func (s *ProjectService) QuotaSnapshot(now time.Time) QuotaSnapshot {
+	return s.client.QuotaSnapshot(now)
+}
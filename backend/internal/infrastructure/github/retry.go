@@ -0,0 +1,226 @@
+package github
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	// retryBaseDelay は一時的な障害（502・503・504）の最初の再試行までの待ち時間（再試行のたびに倍にする）
+	retryBaseDelay = time.Second
+	// secondaryRateLimitDelay はRetry-Afterのない二次レート制限の再試行までの最短の待ち時間（GitHubの推奨は1分）
+	secondaryRateLimitDelay = time.Minute
+	// rateLimitLowWater は残りの回数がこれを下回ると、リセットまでの時間を残りの回数で均してリクエストの間隔を空ける
+	rateLimitLowWater = 50
+	// rateLimitPaceMax は残りの回数が少ない場合に空けるリクエストの間隔の上限
+	rateLimitPaceMax = 5 * time.Second
+)
+
+// ErrRateLimited はGitHubのレート制限（一次・二次）のため、待てる時間内にリクエストを実行できなかったことを表す
+// 時間を置けば成功するため、ErrUnavailableとしても扱う（保留した同期はリセット後に再実行する）
+var ErrRateLimited = errors.New("github rate limit exceeded")
+
+// rateLimitError はレート制限でリクエストを実行できなかったことと、再試行できる時刻を表す
+type rateLimitError struct {
+	RetryAt time.Time
+}
+
+func (e *rateLimitError) Error() string {
+	return fmt.Sprintf("%v until %s", ErrRateLimited, e.RetryAt.Format(time.RFC3339))
+}
+
+// Is はErrRateLimitedとErrUnavailableとして扱う
+func (e *rateLimitError) Is(target error) bool {
+	return target == ErrRateLimited || target == ErrUnavailable
+}
+
+// response は再試行を終えたレスポンス（ボディは読み取り済み）
+type response struct {
+	StatusCode int
+	Status     string
+	Header     http.Header
+	Body       []byte
+}
+
+// send はリクエストを送信し、レート制限と一時的な障害の場合は待ってから再試行する
+// 残りの回数を使い切っている場合はリセットまで、少ない場合は間隔を空けてから送信し、待ち時間がmaxWaitを超える場合は送信せずにrateLimitErrorを返す
+// 二次レート制限はRetry-Afterの後に再試行する。502・503・504と接続の失敗はidempotentなリクエストだけ、間隔を倍にしながら再試行する
+// （mutation等は処理されたか分からないため、重複して実行しないよう再試行しない）
+func (c *Client) send(ctx context.Context, token, resource string, idempotent bool, body []byte, newRequest func(body io.Reader) (*http.Request, error)) (*response, error) {
+	for attempt := 0; ; attempt++ {
+		if err := c.waitForRateLimit(ctx, token, resource); err != nil {
+			return nil, err
+		}
+
+		req, err := newRequest(bytes.NewReader(body))
+		if err != nil {
+			return nil, fmt.Errorf("failed to create request: %w", err)
+		}
+		resp, err := c.httpClient.Do(req)
+		if err != nil {
+			err = executeError(ctx, err)
+			if !idempotent || attempt >= c.maxRetries || !errors.Is(err, ErrUnavailable) {
+				return nil, err
+			}
+			if err := c.sleepRetry(ctx, backoffDelay(attempt), "error", err); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		respBody, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read response: %w", err)
+		}
+		c.recordHeaderRateLimit(token, resp.Header)
+
+		delay, retryable := retryDelay(resp, respBody, idempotent, attempt, time.Now())
+		if !retryable || attempt >= c.maxRetries {
+			if isRateLimited(resp, respBody) {
+				return nil, &rateLimitError{RetryAt: time.Now().Add(delay)}
+			}
+			return &response{StatusCode: resp.StatusCode, Status: resp.Status, Header: resp.Header, Body: respBody}, nil
+		}
+		if delay > c.maxWait {
+			return nil, &rateLimitError{RetryAt: time.Now().Add(delay)}
+		}
+		if err := c.sleepRetry(ctx, delay, "status", resp.StatusCode); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// sleepRetry は再試行までdelayだけ待つ（ctxが終了した場合はそのエラー）
+func (c *Client) sleepRetry(ctx context.Context, delay time.Duration, reason string, value any) error {
+	c.logger.WarnContext(ctx, "retrying github request", reason, value, "delay", delay)
+	return sleep(ctx, delay)
+}
+
+// retryDelay はレスポンスを再試行するかと、再試行までの待ち時間を返す
+// 一次レート制限はリセットまで、二次レート制限はRetry-After（ない場合はsecondaryRateLimitDelay）だけ待つ
+func retryDelay(resp *http.Response, body []byte, idempotent bool, attempt int, now time.Time) (time.Duration, bool) {
+	if isRateLimited(resp, body) {
+		if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil {
+			return time.Duration(seconds) * time.Second, true
+		}
+		if resp.Header.Get("X-RateLimit-Remaining") == "0" {
+			return max(rateLimitReset(resp.Header, now).Sub(now), 0), true
+		}
+		return max(secondaryRateLimitDelay, backoffDelay(attempt)), true
+	}
+
+	switch resp.StatusCode {
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return backoffDelay(attempt), idempotent
+	}
+	return 0, false
+}
+
+// isRateLimited はレスポンスがレート制限（一次・二次）による拒否かを返す
+// GitHubは403か429を返し、一次はX-RateLimit-Remainingが0、二次はRetry-Afterかメッセージで判別する
+func isRateLimited(resp *http.Response, body []byte) bool {
+	if resp.StatusCode != http.StatusForbidden && resp.StatusCode != http.StatusTooManyRequests {
+		return false
+	}
+	return resp.StatusCode == http.StatusTooManyRequests ||
+		resp.Header.Get("Retry-After") != "" ||
+		resp.Header.Get("X-RateLimit-Remaining") == "0" ||
+		bytes.Contains(bytes.ToLower(body), []byte("secondary rate limit"))
+}
+
+// rateLimitReset はX-RateLimit-Resetヘッダーのリセット時刻を返す（ない場合はsecondaryRateLimitDelayの後）
+func rateLimitReset(header http.Header, now time.Time) time.Time {
+	if reset, err := strconv.ParseInt(header.Get("X-RateLimit-Reset"), 10, 64); err == nil {
+		return time.Unix(reset, 0)
+	}
+	return now.Add(secondaryRateLimitDelay)
+}
+
+// backoffDelay はattempt回目（0始まり）の再試行までの待ち時間を返す
+func backoffDelay(attempt int) time.Duration {
+	return retryBaseDelay << min(attempt, 10)
+}
+
+// sleep はdだけ待つ（ctxが終了した場合はそのエラー）
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// recordHeaderRateLimit はX-RateLimit-*ヘッダーからトークンの種類（core・graphql等）ごとの残りの回数を記録する
+func (c *Client) recordHeaderRateLimit(token string, header http.Header) {
+	resource := header.Get("X-RateLimit-Resource")
+	remaining, err := strconv.Atoi(header.Get("X-RateLimit-Remaining"))
+	if resource == "" || err != nil {
+		return
+	}
+	reset, err := strconv.ParseInt(header.Get("X-RateLimit-Reset"), 10, 64)
+	if err != nil {
+		return
+	}
+
+	c.mu.Lock()
+	c.headerLimits[tokenKey(token)+":"+resource] = RateLimit{Remaining: remaining, ResetAt: time.Unix(reset, 0)}
+	c.mu.Unlock()
+}
+
+// waitForRateLimit はトークンのresourceの残りの回数に応じて送信を待ち、送信する1回分を残りの回数から差し引く
+// 待ち時間がmaxWaitを超える場合は待たずにrateLimitErrorを返す
+func (c *Client) waitForRateLimit(ctx context.Context, token, resource string) error {
+	key := tokenKey(token) + ":" + resource
+	now := time.Now()
+
+	c.mu.Lock()
+	limit, ok := c.headerLimits[key]
+	delay := time.Duration(0)
+	if ok {
+		delay = rateLimitDelay(limit, now)
+		if delay <= c.maxWait && limit.Remaining > 0 {
+			// 応答を待つ間の他のリクエストも間隔を空けるよう、送信する分を先に差し引く
+			limit.Remaining--
+			c.headerLimits[key] = limit
+		}
+	}
+	c.mu.Unlock()
+
+	if delay > c.maxWait {
+		return &rateLimitError{RetryAt: now.Add(delay)}
+	}
+	if delay > 0 {
+		c.logger.InfoContext(ctx, "delaying github request near rate limit",
+			"resource", resource, "remaining", limit.Remaining, "reset_at", limit.ResetAt, "delay", delay)
+	}
+	return sleep(ctx, delay)
+}
+
+// rateLimitDelay は残りの回数から次のリクエストまでに待つ時間を返す
+// 使い切っている場合はリセットまで、少ない場合はリセットまでの時間を残りの回数で均した間隔（rateLimitPaceMaxまで）を返す
+func rateLimitDelay(limit RateLimit, now time.Time) time.Duration {
+	if !now.Before(limit.ResetAt) {
+		return 0
+	}
+	untilReset := limit.ResetAt.Sub(now)
+	switch {
+	case limit.Remaining <= 0:
+		return untilReset
+	case limit.Remaining < rateLimitLowWater:
+		return min(untilReset/time.Duration(limit.Remaining), rateLimitPaceMax)
+	}
+	return 0
+}
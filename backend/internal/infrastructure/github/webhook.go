@@ -0,0 +1,28 @@
+package github
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+)
+
+// SignatureHeader はGitHubがWebhookの本文の署名を送るヘッダー
+const SignatureHeader = "X-Hub-Signature-256"
+
+// VerifySignature はWebhookの本文の署名（X-Hub-Signature-256の"sha256=<16進>"）がsecretで計算したHMAC-SHA256と一致するかを返す
+// secretが空の場合は誰でも署名を計算できるため、常に一致しないものとする
+func VerifySignature(secret string, body []byte, signature string) bool {
+	hexDigest, ok := strings.CutPrefix(signature, "sha256=")
+	if !ok || secret == "" {
+		return false
+	}
+	got, err := hex.DecodeString(hexDigest)
+	if err != nil {
+		return false
+	}
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hmac.Equal(got, mac.Sum(nil))
+}
@@ -0,0 +1,54 @@
+package github
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+)
+
+// GitHubのドキュメント（Validating webhook deliveries）のテストの値
+const (
+	testWebhookSecret    = "It's a Secret to Everybody"
+	testWebhookPayload   = "Hello, World!"
+	testWebhookSignature = "sha256=757107ea0eb2509fc211221cce984b8a37570b6d7586c22c46f4379c8b043e17"
+)
+
+func sign(secret, payload string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(payload))
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func TestVerifySignature(t *testing.T) {
+	if !VerifySignature(testWebhookSecret, []byte(testWebhookPayload), testWebhookSignature) {
+		t.Error("VerifySignature() = false, want true")
+	}
+}
+
+func TestVerifySignatureRejectsInvalidSignature(t *testing.T) {
+	tests := []struct {
+		name      string
+		secret    string
+		payload   string
+		signature string
+	}{
+		{name: "tampered payload", secret: testWebhookSecret, payload: "Hello, World?", signature: testWebhookSignature},
+		{name: "other secret", secret: "other secret", payload: testWebhookPayload, signature: testWebhookSignature},
+		{name: "missing signature", secret: testWebhookSecret, payload: testWebhookPayload, signature: ""},
+		{name: "sha1 prefix", secret: testWebhookSecret, payload: testWebhookPayload, signature: "sha1=757107ea0eb2509fc211221cce984b8a37570b6d7586c22c46f4379c8b043e17"},
+		{name: "without prefix", secret: testWebhookSecret, payload: testWebhookPayload, signature: testWebhookSignature[len("sha256="):]},
+		{name: "truncated", secret: testWebhookSecret, payload: testWebhookPayload, signature: testWebhookSignature[:len(testWebhookSignature)-2]},
+		{name: "prefix only", secret: testWebhookSecret, payload: testWebhookPayload, signature: "sha256="},
+		{name: "not hex", secret: testWebhookSecret, payload: testWebhookPayload, signature: "sha256=zz7107ea0eb2509fc211221cce984b8a37570b6d7586c22c46f4379c8b043e17"},
+		// 空の秘密で計算した署名は誰でも作れる
+		{name: "empty secret", secret: "", payload: testWebhookPayload, signature: sign("", testWebhookPayload)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if VerifySignature(tt.secret, []byte(tt.payload), tt.signature) {
+				t.Error("VerifySignature() = true, want false")
+			}
+		})
+	}
+}
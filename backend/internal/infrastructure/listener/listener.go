@@ -0,0 +1,115 @@
+package listener
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"net"
+	"os"
+	"strconv"
+)
+
+// systemdListenFDStart はsystemdのソケットアクティベーションで渡される最初のファイルディスクリプタ番号
+const systemdListenFDStart = 3
+
+// Config はリスナーの設定
+type Config struct {
+	// Port はTCPで待ち受けるポート番号
+	Port string
+	// SocketPath はUnixドメインソケットのパス（指定時はTCPの代わりに使用する）
+	SocketPath string
+	// SocketMode はUnixドメインソケットのファイルパーミッション
+	SocketMode fs.FileMode
+}
+
+// New は設定に応じたリスナーを作成する
+// 優先順位: systemdソケットアクティベーション > Unixドメインソケット > TCP
+func New(cfg Config, logger *slog.Logger) (net.Listener, error) {
+	l, err := systemdListener()
+	if err != nil {
+		return nil, err
+	}
+	if l != nil {
+		logger.Info("using systemd socket activation", "addr", l.Addr().String())
+		return l, nil
+	}
+
+	if cfg.SocketPath != "" {
+		return unixListener(cfg.SocketPath, cfg.SocketMode, logger)
+	}
+
+	l, err = net.Listen("tcp", ":"+cfg.Port)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen on port %s: %w", cfg.Port, err)
+	}
+	logger.Info("listening on tcp", "port", cfg.Port)
+	return l, nil
+}
+
+// systemdListener はsystemdから引き継いだソケットのリスナーを返す
+// ソケットアクティベーションで起動されていない場合はnilを返す
+func systemdListener() (net.Listener, error) {
+	pid, err := strconv.Atoi(os.Getenv("LISTEN_PID"))
+	if err != nil || pid != os.Getpid() {
+		return nil, nil
+	}
+
+	fds, err := strconv.Atoi(os.Getenv("LISTEN_FDS"))
+	if err != nil || fds < 1 {
+		return nil, nil
+	}
+
+	// 子プロセスに引き継がれないよう環境変数を削除する
+	_ = os.Unsetenv("LISTEN_PID")
+	_ = os.Unsetenv("LISTEN_FDS")
+	_ = os.Unsetenv("LISTEN_FDNAMES")
+
+	// 最初のソケットのみ使用する
+	f := os.NewFile(uintptr(systemdListenFDStart), "LISTEN_FD_3")
+	if f == nil {
+		return nil, errors.New("invalid systemd listen fd")
+	}
+	defer f.Close()
+
+	l, err := net.FileListener(f)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create listener from systemd fd: %w", err)
+	}
+	return l, nil
+}
+
+// unixListener はUnixドメインソケットのリスナーを作成する
+func unixListener(path string, mode fs.FileMode, logger *slog.Logger) (net.Listener, error) {
+	// 前回の異常終了で残ったソケットファイルを削除する
+	if info, err := os.Stat(path); err == nil {
+		if info.Mode()&fs.ModeSocket == 0 {
+			return nil, fmt.Errorf("refusing to remove non-socket file: %s", path)
+		}
+		if err := os.Remove(path); err != nil {
+			return nil, fmt.Errorf("failed to remove stale socket: %w", err)
+		}
+	}
+
+	l, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen on unix socket %s: %w", path, err)
+	}
+
+	if err := os.Chmod(path, mode); err != nil {
+		l.Close()
+		return nil, fmt.Errorf("failed to chmod unix socket: %w", err)
+	}
+
+	logger.Info("listening on unix socket", "path", path, "mode", mode.String())
+	return l, nil
+}
+
+// ParseFileMode は8進数文字列のパーミッション（例: "0660"）をパースする
+func ParseFileMode(s string) (fs.FileMode, error) {
+	mode, err := strconv.ParseUint(s, 8, 32)
+	if err != nil {
+		return 0, fmt.Errorf("invalid file mode %q: %w", s, err)
+	}
+	return fs.FileMode(mode), nil
+}
@@ -0,0 +1,94 @@
+package mail
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"mime"
+	"net"
+	"net/smtp"
+	"strings"
+)
+
+// Message は送信するメールを表す
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Sender はメールを送信するインターフェース
+type Sender interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+// Config はSMTPサーバーの設定
+type Config struct {
+	Host     string
+	Port     string
+	Username string
+	Password string
+	From     string
+}
+
+// NewSender は設定に応じたSenderを作成する
+// Hostが未設定の場合は送信せずにログへ出力するSenderを返す（開発環境用）
+func NewSender(cfg Config, logger *slog.Logger) Sender {
+	if cfg.Host == "" {
+		logger.Info("SMTP_HOST is not set, emails will be logged instead of sent")
+		return &logSender{logger: logger}
+	}
+	return &smtpSender{cfg: cfg, logger: logger}
+}
+
+type smtpSender struct {
+	cfg    Config
+	logger *slog.Logger
+}
+
+// Send はSMTPでメールを送信する（本文はUTF-8のプレーンテキスト）
+func (s *smtpSender) Send(ctx context.Context, msg Message) error {
+	var auth smtp.Auth
+	if s.cfg.Username != "" {
+		auth = smtp.PlainAuth("", s.cfg.Username, s.cfg.Password, s.cfg.Host)
+	}
+
+	header := strings.Join([]string{
+		"From: " + s.cfg.From,
+		"To: " + msg.To,
+		"Subject: " + encodeHeader(msg.Subject),
+		"MIME-Version: 1.0",
+		"Content-Type: text/plain; charset=UTF-8",
+		"Content-Transfer-Encoding: 8bit",
+	}, "\r\n")
+	body := header + "\r\n\r\n" + strings.ReplaceAll(msg.Body, "\n", "\r\n")
+
+	addr := net.JoinHostPort(s.cfg.Host, s.cfg.Port)
+	if err := smtp.SendMail(addr, auth, s.cfg.From, []string{msg.To}, []byte(body)); err != nil {
+		s.logger.ErrorContext(ctx, "failed to send email", "error", err, "to", msg.To)
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+
+	s.logger.InfoContext(ctx, "email sent", "to", msg.To, "subject", msg.Subject)
+	return nil
+}
+
+// encodeHeader は非ASCII文字を含むヘッダー値をMIMEエンコードする
+func encodeHeader(value string) string {
+	for _, r := range value {
+		if r > 127 {
+			return mime.BEncoding.Encode("UTF-8", value)
+		}
+	}
+	return value
+}
+
+type logSender struct {
+	logger *slog.Logger
+}
+
+// Send はメールを送信せずにログへ出力する
+func (s *logSender) Send(ctx context.Context, msg Message) error {
+	s.logger.InfoContext(ctx, "email (not sent)", "to", msg.To, "subject", msg.Subject, "body", msg.Body)
+	return nil
+}
@@ -0,0 +1,175 @@
+package persistence
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/sikigasa/github-task-controller/backend/internal/domain/model"
+	"github.com/sikigasa/github-task-controller/backend/internal/domain/repository"
+	"github.com/sikigasa/github-task-controller/backend/internal/infrastructure/crypto"
+)
+
+// accountMergeColumns はアカウント統合の検索時に取得するカラム（scanAccountMergeの引数順と一致させる）
+const accountMergeColumns = `id, user_id, provider, provider_account_id, provider_email, access_token, refresh_token,
+	token_expires_at, token_hash, expires_at, confirmed_at, confirmed_via, confirmed_ip, created_at`
+
+type accountMergeRepository struct {
+	db     *tenantDB
+	cipher *crypto.Cipher
+	logger *slog.Logger
+}
+
+// NewAccountMergeRepository は新しいAccountMergeRepositoryを作成する
+// 確認待ちの間保持する統合元のトークンはcipherで暗号化して保存する
+func NewAccountMergeRepository(db *sql.DB, cipher *crypto.Cipher, logger *slog.Logger) repository.AccountMergeRepository {
+	return &accountMergeRepository{
+		db:     newTenantDB(db),
+		cipher: cipher,
+		logger: logger,
+	}
+}
+
+// scanAccountMerge はaccountMergeColumnsの順に読み取ったアカウント統合を返す（トークンは暗号化されたまま）
+func scanAccountMerge(row rowScanner) (*model.AccountMerge, error) {
+	var merge model.AccountMerge
+	var tokenExpiresAt, confirmedAt sql.NullTime
+	var confirmedVia, confirmedIP sql.NullString
+	if err := row.Scan(
+		&merge.ID, &merge.UserID, &merge.Provider, &merge.ProviderAccountID, &merge.ProviderEmail,
+		&merge.AccessToken, &merge.RefreshToken, &tokenExpiresAt, &merge.TokenHash, &merge.ExpiresAt,
+		&confirmedAt, &confirmedVia, &confirmedIP, &merge.CreatedAt,
+	); err != nil {
+		return nil, err
+	}
+	if tokenExpiresAt.Valid {
+		merge.TokenExpiresAt = &tokenExpiresAt.Time
+	}
+	if confirmedAt.Valid {
+		merge.ConfirmedAt = &confirmedAt.Time
+	}
+	merge.ConfirmedVia = confirmedVia.String
+	merge.ConfirmedIP = confirmedIP.String
+	return &merge, nil
+}
+
+func (r *accountMergeRepository) Create(ctx context.Context, merge *model.AccountMerge) error {
+	query := `
+		INSERT INTO account_merge (id, user_id, provider, provider_account_id, provider_email,
+			access_token, refresh_token, token_expires_at, token_hash, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+	`
+
+	accessToken, refreshToken, err := encryptTokens(r.cipher, merge.AccessToken, merge.RefreshToken)
+	if err != nil {
+		return fmt.Errorf("failed to create account merge: %w", err)
+	}
+
+	_, err = r.db.ExecContext(ctx, query,
+		merge.ID, merge.UserID, merge.Provider, merge.ProviderAccountID, merge.ProviderEmail,
+		accessToken, refreshToken, merge.TokenExpiresAt, merge.TokenHash, merge.ExpiresAt, merge.CreatedAt,
+	)
+	if err != nil {
+		r.logger.ErrorContext(ctx, "failed to create account merge", "error", err)
+		return fmt.Errorf("failed to create account merge: %w", err)
+	}
+
+	r.logger.InfoContext(ctx, "account merge requested", "merge_id", merge.ID, "user_id", merge.UserID, "provider", merge.Provider)
+	return nil
+}
+
+func (r *accountMergeRepository) FindByID(ctx context.Context, id string) (*model.AccountMerge, error) {
+	query := `SELECT ` + accountMergeColumns + ` FROM account_merge WHERE id = $1`
+
+	merge, err := scanAccountMerge(r.db.QueryRowContext(ctx, query, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("account merge not found: %s: %w", id, model.ErrNotFound)
+	}
+	if err != nil {
+		r.logger.ErrorContext(ctx, "failed to find account merge by id", "error", err, "id", id)
+		return nil, fmt.Errorf("failed to find account merge by id: %w", err)
+	}
+	if err := decryptTokens(r.cipher, &merge.AccessToken, &merge.RefreshToken); err != nil {
+		r.logger.ErrorContext(ctx, "failed to decrypt account merge", "error", err, "merge_id", merge.ID)
+		return nil, fmt.Errorf("failed to find account merge: %w", err)
+	}
+
+	return merge, nil
+}
+
+func (r *accountMergeRepository) FindByTokenHash(ctx context.Context, tokenHash string) (*model.AccountMerge, error) {
+	query := `SELECT ` + accountMergeColumns + ` FROM account_merge WHERE token_hash = $1`
+
+	merge, err := scanAccountMerge(r.db.QueryRowContext(ctx, query, tokenHash))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("account merge not found: %w", model.ErrNotFound)
+	}
+	if err != nil {
+		r.logger.ErrorContext(ctx, "failed to find account merge by token", "error", err)
+		return nil, fmt.Errorf("failed to find account merge by token: %w", err)
+	}
+	if err := decryptTokens(r.cipher, &merge.AccessToken, &merge.RefreshToken); err != nil {
+		r.logger.ErrorContext(ctx, "failed to decrypt account merge", "error", err, "merge_id", merge.ID)
+		return nil, fmt.Errorf("failed to find account merge: %w", err)
+	}
+
+	return merge, nil
+}
+
+func (r *accountMergeRepository) FindByUserID(ctx context.Context, userID string) ([]*model.AccountMerge, error) {
+	query := `SELECT ` + accountMergeColumns + ` FROM account_merge WHERE user_id = $1 ORDER BY created_at DESC`
+
+	rows, err := r.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		r.logger.ErrorContext(ctx, "failed to find account merges", "error", err, "user_id", userID)
+		return nil, fmt.Errorf("failed to find account merges: %w", err)
+	}
+	defer rows.Close()
+
+	merges := []*model.AccountMerge{}
+	for rows.Next() {
+		merge, err := scanAccountMerge(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan account merge: %w", err)
+		}
+		if err := decryptTokens(r.cipher, &merge.AccessToken, &merge.RefreshToken); err != nil {
+			r.logger.ErrorContext(ctx, "failed to decrypt account merge", "error", err, "merge_id", merge.ID)
+			return nil, fmt.Errorf("failed to find account merges: %w", err)
+		}
+		merges = append(merges, merge)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate account merges: %w", err)
+	}
+
+	return merges, nil
+}
+
+func (r *accountMergeRepository) Confirm(ctx context.Context, id, via, ip string, at time.Time) error {
+	query := `
+		UPDATE account_merge
+		SET confirmed_at = $1, confirmed_via = $2, confirmed_ip = $3,
+			access_token = '', refresh_token = '', token_expires_at = NULL
+		WHERE id = $4 AND confirmed_at IS NULL
+	`
+
+	result, err := r.db.ExecContext(ctx, query, at, via, ip, id)
+	if err != nil {
+		r.logger.ErrorContext(ctx, "failed to confirm account merge", "error", err, "merge_id", id)
+		return fmt.Errorf("failed to confirm account merge: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("account merge already confirmed: %s: %w", id, model.ErrConflict)
+	}
+
+	r.logger.InfoContext(ctx, "account merge confirmed", "merge_id", id, "via", via)
+	return nil
+}
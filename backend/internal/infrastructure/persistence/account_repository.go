@@ -3,23 +3,28 @@ package persistence
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"log/slog"
 	"time"
 
 	"github.com/sikigasa/github-task-controller/backend/internal/domain/model"
 	"github.com/sikigasa/github-task-controller/backend/internal/domain/repository"
+	"github.com/sikigasa/github-task-controller/backend/internal/infrastructure/crypto"
 )
 
 type googleAccountRepository struct {
-	db     *sql.DB
+	db     *tenantDB
+	cipher *crypto.Cipher
 	logger *slog.Logger
 }
 
 // NewGoogleAccountRepository は新しいGoogleAccountRepositoryを作成する
-func NewGoogleAccountRepository(db *sql.DB, logger *slog.Logger) repository.GoogleAccountRepository {
+// トークンはcipherで暗号化して保存する
+func NewGoogleAccountRepository(db *sql.DB, cipher *crypto.Cipher, logger *slog.Logger) repository.GoogleAccountRepository {
 	return &googleAccountRepository{
-		db:     db,
+		db:     newTenantDB(db),
+		cipher: cipher,
 		logger: logger,
 	}
 }
@@ -37,9 +42,14 @@ func (r *googleAccountRepository) Create(ctx context.Context, account *model.Goo
 		expiresAt = &ts
 	}
 
-	_, err := r.db.ExecContext(ctx, query,
+	accessToken, refreshToken, err := encryptTokens(r.cipher, account.AccessToken, account.RefreshToken)
+	if err != nil {
+		return fmt.Errorf("failed to create google account: %w", err)
+	}
+
+	_, err = r.db.ExecContext(ctx, query,
 		account.UserID, account.Provider, account.ProviderAccountID,
-		account.AccessToken, account.RefreshToken, expiresAt,
+		accessToken, refreshToken, expiresAt,
 		account.CreatedAt, account.UpdatedAt,
 	)
 	if err != nil {
@@ -65,8 +75,8 @@ func (r *googleAccountRepository) FindByProviderAccountID(ctx context.Context, p
 		&account.AccessToken, &account.RefreshToken, &expiresAt,
 		&account.CreatedAt, &account.UpdatedAt,
 	)
-	if err == sql.ErrNoRows {
-		return nil, fmt.Errorf("google account not found: %s", providerAccountID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("google %w: %s", repository.ErrAccountNotFound, providerAccountID)
 	}
 	if err != nil {
 		r.logger.ErrorContext(ctx, "failed to find google account", "error", err)
@@ -77,6 +87,10 @@ func (r *googleAccountRepository) FindByProviderAccountID(ctx context.Context, p
 		t := time.Unix(expiresAt.Int64, 0)
 		account.ExpiresAt = &t
 	}
+	if err := decryptTokens(r.cipher, &account.AccessToken, &account.RefreshToken); err != nil {
+		r.logger.ErrorContext(ctx, "failed to decrypt google account", "error", err)
+		return nil, fmt.Errorf("failed to find google account: %w", err)
+	}
 
 	return &account, nil
 }
@@ -95,8 +109,8 @@ func (r *googleAccountRepository) FindByUserID(ctx context.Context, userID strin
 		&account.AccessToken, &account.RefreshToken, &expiresAt,
 		&account.CreatedAt, &account.UpdatedAt,
 	)
-	if err == sql.ErrNoRows {
-		return nil, fmt.Errorf("google account not found for user: %s", userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("google %w (user %s)", repository.ErrAccountNotFound, userID)
 	}
 	if err != nil {
 		r.logger.ErrorContext(ctx, "failed to find google account by user_id", "error", err)
@@ -107,6 +121,10 @@ func (r *googleAccountRepository) FindByUserID(ctx context.Context, userID strin
 		t := time.Unix(expiresAt.Int64, 0)
 		account.ExpiresAt = &t
 	}
+	if err := decryptTokens(r.cipher, &account.AccessToken, &account.RefreshToken); err != nil {
+		r.logger.ErrorContext(ctx, "failed to decrypt google account", "error", err)
+		return nil, fmt.Errorf("failed to find google account: %w", err)
+	}
 
 	return &account, nil
 }
@@ -124,8 +142,13 @@ func (r *googleAccountRepository) Update(ctx context.Context, account *model.Goo
 		expiresAt = &ts
 	}
 
+	accessToken, refreshToken, err := encryptTokens(r.cipher, account.AccessToken, account.RefreshToken)
+	if err != nil {
+		return fmt.Errorf("failed to update google account: %w", err)
+	}
+
 	result, err := r.db.ExecContext(ctx, query,
-		account.AccessToken, account.RefreshToken, expiresAt, time.Now(),
+		accessToken, refreshToken, expiresAt, time.Now(),
 		account.Provider, account.ProviderAccountID,
 	)
 	if err != nil {
@@ -138,7 +161,7 @@ func (r *googleAccountRepository) Update(ctx context.Context, account *model.Goo
 		return fmt.Errorf("failed to get rows affected: %w", err)
 	}
 	if rowsAffected == 0 {
-		return fmt.Errorf("google account not found")
+		return fmt.Errorf("google %w", repository.ErrAccountNotFound)
 	}
 
 	r.logger.InfoContext(ctx, "google account updated")
@@ -159,7 +182,7 @@ func (r *googleAccountRepository) Delete(ctx context.Context, provider, provider
 		return fmt.Errorf("failed to get rows affected: %w", err)
 	}
 	if rowsAffected == 0 {
-		return fmt.Errorf("google account not found")
+		return fmt.Errorf("google %w", repository.ErrAccountNotFound)
 	}
 
 	r.logger.InfoContext(ctx, "google account deleted")
@@ -167,14 +190,17 @@ func (r *googleAccountRepository) Delete(ctx context.Context, provider, provider
 }
 
 type githubAccountRepository struct {
-	db     *sql.DB
+	db     *tenantDB
+	cipher *crypto.Cipher
 	logger *slog.Logger
 }
 
 // NewGithubAccountRepository は新しいGithubAccountRepositoryを作成する
-func NewGithubAccountRepository(db *sql.DB, logger *slog.Logger) repository.GithubAccountRepository {
+// トークンはcipherで暗号化して保存する
+func NewGithubAccountRepository(db *sql.DB, cipher *crypto.Cipher, logger *slog.Logger) repository.GithubAccountRepository {
 	return &githubAccountRepository{
-		db:     db,
+		db:     newTenantDB(db),
+		cipher: cipher,
 		logger: logger,
 	}
 }
@@ -191,9 +217,14 @@ func (r *githubAccountRepository) Create(ctx context.Context, account *model.Git
 		expiresAt = &ts
 	}
 
-	_, err := r.db.ExecContext(ctx, query,
+	accessToken, refreshToken, pat, err := r.encrypt(account)
+	if err != nil {
+		return fmt.Errorf("failed to create github account: %w", err)
+	}
+
+	_, err = r.db.ExecContext(ctx, query,
 		account.UserID, account.Provider, account.ProviderAccountID,
-		account.AccessToken, account.RefreshToken, expiresAt, account.PATEncrypted,
+		accessToken, refreshToken, expiresAt, pat,
 		account.CreatedAt, account.UpdatedAt,
 	)
 	if err != nil {
@@ -207,7 +238,7 @@ func (r *githubAccountRepository) Create(ctx context.Context, account *model.Git
 
 func (r *githubAccountRepository) FindByProviderAccountID(ctx context.Context, provider, providerAccountID string) (*model.GithubAccount, error) {
 	query := `
-		SELECT user_id, provider, provider_account_id, access_token, refresh_token, expires_at, pat_encrypted, created_at, updated_at
+		SELECT user_id, provider, provider_account_id, access_token, refresh_token, expires_at, pat_encrypted, reauth_required_at, reauth_reason, created_at, updated_at
 		FROM github_account
 		WHERE provider = $1 AND provider_account_id = $2
 	`
@@ -215,13 +246,15 @@ func (r *githubAccountRepository) FindByProviderAccountID(ctx context.Context, p
 	var account model.GithubAccount
 	var expiresAt sql.NullInt64
 	var patEncrypted sql.NullString
+	var reauthReason sql.NullString
 	err := r.db.QueryRowContext(ctx, query, provider, providerAccountID).Scan(
 		&account.UserID, &account.Provider, &account.ProviderAccountID,
 		&account.AccessToken, &account.RefreshToken, &expiresAt, &patEncrypted,
+		&account.ReauthRequiredAt, &reauthReason,
 		&account.CreatedAt, &account.UpdatedAt,
 	)
-	if err == sql.ErrNoRows {
-		return nil, fmt.Errorf("github account not found: %s", providerAccountID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("github %w: %s", repository.ErrAccountNotFound, providerAccountID)
 	}
 	if err != nil {
 		r.logger.ErrorContext(ctx, "failed to find github account", "error", err)
@@ -235,13 +268,18 @@ func (r *githubAccountRepository) FindByProviderAccountID(ctx context.Context, p
 	if patEncrypted.Valid {
 		account.PATEncrypted = &patEncrypted.String
 	}
+	account.ReauthReason = model.GithubReauthReason(reauthReason.String)
+	if err := r.decrypt(&account); err != nil {
+		r.logger.ErrorContext(ctx, "failed to decrypt github account", "error", err)
+		return nil, fmt.Errorf("failed to find github account: %w", err)
+	}
 
 	return &account, nil
 }
 
 func (r *githubAccountRepository) FindByUserID(ctx context.Context, userID string) (*model.GithubAccount, error) {
 	query := `
-		SELECT user_id, provider, provider_account_id, access_token, refresh_token, expires_at, pat_encrypted, created_at, updated_at
+		SELECT user_id, provider, provider_account_id, access_token, refresh_token, expires_at, pat_encrypted, reauth_required_at, reauth_reason, created_at, updated_at
 		FROM github_account
 		WHERE user_id = $1
 	`
@@ -249,13 +287,15 @@ func (r *githubAccountRepository) FindByUserID(ctx context.Context, userID strin
 	var account model.GithubAccount
 	var expiresAt sql.NullInt64
 	var patEncrypted sql.NullString
+	var reauthReason sql.NullString
 	err := r.db.QueryRowContext(ctx, query, userID).Scan(
 		&account.UserID, &account.Provider, &account.ProviderAccountID,
 		&account.AccessToken, &account.RefreshToken, &expiresAt, &patEncrypted,
+		&account.ReauthRequiredAt, &reauthReason,
 		&account.CreatedAt, &account.UpdatedAt,
 	)
-	if err == sql.ErrNoRows {
-		return nil, nil // アカウントが存在しない場合はnilを返す
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("github %w (user %s)", repository.ErrAccountNotFound, userID)
 	}
 	if err != nil {
 		r.logger.ErrorContext(ctx, "failed to find github account by user_id", "error", err)
@@ -269,6 +309,11 @@ func (r *githubAccountRepository) FindByUserID(ctx context.Context, userID strin
 	if patEncrypted.Valid {
 		account.PATEncrypted = &patEncrypted.String
 	}
+	account.ReauthReason = model.GithubReauthReason(reauthReason.String)
+	if err := r.decrypt(&account); err != nil {
+		r.logger.ErrorContext(ctx, "failed to decrypt github account", "error", err)
+		return nil, fmt.Errorf("failed to find github account: %w", err)
+	}
 
 	return &account, nil
 }
@@ -276,8 +321,9 @@ func (r *githubAccountRepository) FindByUserID(ctx context.Context, userID strin
 func (r *githubAccountRepository) Update(ctx context.Context, account *model.GithubAccount) error {
 	query := `
 		UPDATE github_account
-		SET access_token = $1, refresh_token = $2, expires_at = $3, pat_encrypted = $4, updated_at = $5
-		WHERE provider = $6 AND provider_account_id = $7
+		SET access_token = $1, refresh_token = $2, expires_at = $3, pat_encrypted = $4,
+			reauth_required_at = $5, reauth_reason = $6, updated_at = $7
+		WHERE provider = $8 AND provider_account_id = $9
 	`
 
 	var expiresAt *int64
@@ -286,8 +332,14 @@ func (r *githubAccountRepository) Update(ctx context.Context, account *model.Git
 		expiresAt = &ts
 	}
 
+	accessToken, refreshToken, pat, err := r.encrypt(account)
+	if err != nil {
+		return fmt.Errorf("failed to update github account: %w", err)
+	}
+
 	result, err := r.db.ExecContext(ctx, query,
-		account.AccessToken, account.RefreshToken, expiresAt, account.PATEncrypted, time.Now(),
+		accessToken, refreshToken, expiresAt, pat,
+		account.ReauthRequiredAt, sql.NullString{String: string(account.ReauthReason), Valid: account.ReauthReason != ""}, time.Now(),
 		account.Provider, account.ProviderAccountID,
 	)
 	if err != nil {
@@ -300,13 +352,74 @@ func (r *githubAccountRepository) Update(ctx context.Context, account *model.Git
 		return fmt.Errorf("failed to get rows affected: %w", err)
 	}
 	if rowsAffected == 0 {
-		return fmt.Errorf("github account not found")
+		return fmt.Errorf("github %w", repository.ErrAccountNotFound)
 	}
 
 	r.logger.InfoContext(ctx, "github account updated")
 	return nil
 }
 
+func (r *githubAccountRepository) UpdateOAuthToken(ctx context.Context, userID, accessToken, refreshToken string, expiresAt *time.Time) error {
+	query := `
+		UPDATE github_account
+		SET access_token = $1, refresh_token = COALESCE(NULLIF($2, ''), refresh_token), expires_at = $3,
+			reauth_required_at = CASE WHEN reauth_reason = 'oauth' THEN NULL ELSE reauth_required_at END,
+			reauth_reason = CASE WHEN reauth_reason = 'oauth' THEN NULL ELSE reauth_reason END,
+			updated_at = $4
+		WHERE user_id = $5
+	`
+
+	var expiresAtUnix *int64
+	if expiresAt != nil {
+		ts := expiresAt.Unix()
+		expiresAtUnix = &ts
+	}
+
+	encryptedAccess, encryptedRefresh, err := encryptTokens(r.cipher, accessToken, refreshToken)
+	if err != nil {
+		return fmt.Errorf("failed to update github oauth token: %w", err)
+	}
+
+	// GitHubのリフレッシュトークンは1回しか使えないため、呼び出し元のトランザクションがロールバックされても
+	// 新しいトークンを失わないよう、トランザクションの外で実行する
+	result, err := r.db.db.ExecContext(ctx, query, encryptedAccess, encryptedRefresh, expiresAtUnix, time.Now(), userID)
+	if err != nil {
+		r.logger.ErrorContext(ctx, "failed to update github oauth token", "error", err)
+		return fmt.Errorf("failed to update github oauth token: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("github %w", repository.ErrAccountNotFound)
+	}
+
+	r.logger.InfoContext(ctx, "github oauth token refreshed", "user_id", userID)
+	return nil
+}
+
+func (r *githubAccountRepository) MarkReauthRequired(ctx context.Context, userID string, reason model.GithubReauthReason, at time.Time) error {
+	// 最初に拒否された日時を残すため、既に同じ理由で記録済みの場合は日時を更新しない
+	query := `
+		UPDATE github_account
+		SET reauth_required_at = CASE WHEN reauth_reason = $2 THEN COALESCE(reauth_required_at, $1) ELSE $1 END,
+			reauth_reason = $2, updated_at = $1
+		WHERE user_id = $3
+	`
+
+	// GitHubの呼び出しが失敗して呼び出し元のトランザクションがロールバックされても記録を残すため、
+	// トランザクションの外で実行する
+	if _, err := r.db.db.ExecContext(ctx, query, at, string(reason), userID); err != nil {
+		r.logger.ErrorContext(ctx, "failed to mark github account as requiring re-authentication", "error", err)
+		return fmt.Errorf("failed to mark github account as requiring re-authentication: %w", err)
+	}
+
+	r.logger.WarnContext(ctx, "github account requires re-authentication", "user_id", userID, "reason", reason)
+	return nil
+}
+
 func (r *githubAccountRepository) Delete(ctx context.Context, provider, providerAccountID string) error {
 	query := `DELETE FROM github_account WHERE provider = $1 AND provider_account_id = $2`
 
@@ -321,9 +434,500 @@ func (r *githubAccountRepository) Delete(ctx context.Context, provider, provider
 		return fmt.Errorf("failed to get rows affected: %w", err)
 	}
 	if rowsAffected == 0 {
-		return fmt.Errorf("github account not found")
+		return fmt.Errorf("github %w", repository.ErrAccountNotFound)
 	}
 
 	r.logger.InfoContext(ctx, "github account deleted")
 	return nil
 }
+
+// encrypt はアカウントのトークンとPATを暗号化した値を返す（PATが未設定の場合はnil）
+func (r *githubAccountRepository) encrypt(account *model.GithubAccount) (string, string, *string, error) {
+	accessToken, refreshToken, err := encryptTokens(r.cipher, account.AccessToken, account.RefreshToken)
+	if err != nil {
+		return "", "", nil, err
+	}
+	if account.PATEncrypted == nil {
+		return accessToken, refreshToken, nil, nil
+	}
+	pat, err := r.cipher.Encrypt(*account.PATEncrypted)
+	if err != nil {
+		return "", "", nil, fmt.Errorf("failed to encrypt pat: %w", err)
+	}
+	return accessToken, refreshToken, &pat, nil
+}
+
+// decrypt は読み取ったアカウントのトークンとPATを復号して置き換える
+func (r *githubAccountRepository) decrypt(account *model.GithubAccount) error {
+	if err := decryptTokens(r.cipher, &account.AccessToken, &account.RefreshToken); err != nil {
+		return err
+	}
+	if account.PATEncrypted == nil {
+		return nil
+	}
+	pat, err := r.cipher.Decrypt(*account.PATEncrypted)
+	if err != nil {
+		return fmt.Errorf("failed to decrypt pat: %w", err)
+	}
+	account.PATEncrypted = &pat
+	return nil
+}
+
+type appleAccountRepository struct {
+	db     *tenantDB
+	cipher *crypto.Cipher
+	logger *slog.Logger
+}
+
+// NewAppleAccountRepository は新しいAppleAccountRepositoryを作成する
+// トークンはcipherで暗号化して保存する
+func NewAppleAccountRepository(db *sql.DB, cipher *crypto.Cipher, logger *slog.Logger) repository.AppleAccountRepository {
+	return &appleAccountRepository{
+		db:     newTenantDB(db),
+		cipher: cipher,
+		logger: logger,
+	}
+}
+
+const appleAccountColumns = `user_id, provider, provider_account_id, email, is_private_email, access_token, refresh_token, expires_at, created_at, updated_at`
+
+// scanAppleAccount はappleAccountColumnsの順に読み取ったAppleアカウントを返す
+func scanAppleAccount(row rowScanner) (*model.AppleAccount, error) {
+	var account model.AppleAccount
+	var expiresAt sql.NullInt64
+	if err := row.Scan(
+		&account.UserID, &account.Provider, &account.ProviderAccountID,
+		&account.Email, &account.IsPrivateEmail,
+		&account.AccessToken, &account.RefreshToken, &expiresAt,
+		&account.CreatedAt, &account.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+
+	if expiresAt.Valid {
+		t := time.Unix(expiresAt.Int64, 0)
+		account.ExpiresAt = &t
+	}
+	return &account, nil
+}
+
+func (r *appleAccountRepository) Create(ctx context.Context, account *model.AppleAccount) error {
+	query := `
+		INSERT INTO apple_account (` + appleAccountColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+	`
+
+	var expiresAt *int64
+	if account.ExpiresAt != nil {
+		ts := account.ExpiresAt.Unix()
+		expiresAt = &ts
+	}
+
+	accessToken, refreshToken, err := encryptTokens(r.cipher, account.AccessToken, account.RefreshToken)
+	if err != nil {
+		return fmt.Errorf("failed to create apple account: %w", err)
+	}
+
+	_, err = r.db.ExecContext(ctx, query,
+		account.UserID, account.Provider, account.ProviderAccountID,
+		account.Email, account.IsPrivateEmail,
+		accessToken, refreshToken, expiresAt,
+		account.CreatedAt, account.UpdatedAt,
+	)
+	if err != nil {
+		r.logger.ErrorContext(ctx, "failed to create apple account", "error", err)
+		return fmt.Errorf("failed to create apple account: %w", err)
+	}
+
+	r.logger.InfoContext(ctx, "apple account created", "user_id", account.UserID)
+	return nil
+}
+
+func (r *appleAccountRepository) FindByProviderAccountID(ctx context.Context, provider, providerAccountID string) (*model.AppleAccount, error) {
+	query := `SELECT ` + appleAccountColumns + ` FROM apple_account WHERE provider = $1 AND provider_account_id = $2`
+
+	account, err := scanAppleAccount(r.db.QueryRowContext(ctx, query, provider, providerAccountID))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("apple %w: %s", repository.ErrAccountNotFound, providerAccountID)
+	}
+	if err != nil {
+		r.logger.ErrorContext(ctx, "failed to find apple account", "error", err)
+		return nil, fmt.Errorf("failed to find apple account: %w", err)
+	}
+	if err := decryptTokens(r.cipher, &account.AccessToken, &account.RefreshToken); err != nil {
+		r.logger.ErrorContext(ctx, "failed to decrypt apple account", "error", err)
+		return nil, fmt.Errorf("failed to find apple account: %w", err)
+	}
+
+	return account, nil
+}
+
+func (r *appleAccountRepository) FindByUserID(ctx context.Context, userID string) (*model.AppleAccount, error) {
+	query := `SELECT ` + appleAccountColumns + ` FROM apple_account WHERE user_id = $1`
+
+	account, err := scanAppleAccount(r.db.QueryRowContext(ctx, query, userID))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("apple %w (user %s)", repository.ErrAccountNotFound, userID)
+	}
+	if err != nil {
+		r.logger.ErrorContext(ctx, "failed to find apple account by user_id", "error", err)
+		return nil, fmt.Errorf("failed to find apple account: %w", err)
+	}
+	if err := decryptTokens(r.cipher, &account.AccessToken, &account.RefreshToken); err != nil {
+		r.logger.ErrorContext(ctx, "failed to decrypt apple account", "error", err)
+		return nil, fmt.Errorf("failed to find apple account: %w", err)
+	}
+
+	return account, nil
+}
+
+func (r *appleAccountRepository) Update(ctx context.Context, account *model.AppleAccount) error {
+	query := `
+		UPDATE apple_account
+		SET email = $1, is_private_email = $2, access_token = $3, refresh_token = $4, expires_at = $5, updated_at = $6
+		WHERE provider = $7 AND provider_account_id = $8
+	`
+
+	var expiresAt *int64
+	if account.ExpiresAt != nil {
+		ts := account.ExpiresAt.Unix()
+		expiresAt = &ts
+	}
+
+	accessToken, refreshToken, err := encryptTokens(r.cipher, account.AccessToken, account.RefreshToken)
+	if err != nil {
+		return fmt.Errorf("failed to update apple account: %w", err)
+	}
+
+	result, err := r.db.ExecContext(ctx, query,
+		account.Email, account.IsPrivateEmail,
+		accessToken, refreshToken, expiresAt, time.Now(),
+		account.Provider, account.ProviderAccountID,
+	)
+	if err != nil {
+		r.logger.ErrorContext(ctx, "failed to update apple account", "error", err)
+		return fmt.Errorf("failed to update apple account: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("apple %w", repository.ErrAccountNotFound)
+	}
+
+	r.logger.InfoContext(ctx, "apple account updated")
+	return nil
+}
+
+func (r *appleAccountRepository) Delete(ctx context.Context, provider, providerAccountID string) error {
+	query := `DELETE FROM apple_account WHERE provider = $1 AND provider_account_id = $2`
+
+	result, err := r.db.ExecContext(ctx, query, provider, providerAccountID)
+	if err != nil {
+		r.logger.ErrorContext(ctx, "failed to delete apple account", "error", err)
+		return fmt.Errorf("failed to delete apple account: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("apple %w", repository.ErrAccountNotFound)
+	}
+
+	r.logger.InfoContext(ctx, "apple account deleted")
+	return nil
+}
+
+type microsoftAccountRepository struct {
+	db     *tenantDB
+	cipher *crypto.Cipher
+	logger *slog.Logger
+}
+
+// NewMicrosoftAccountRepository は新しいMicrosoftAccountRepositoryを作成する
+// トークンはcipherで暗号化して保存する
+func NewMicrosoftAccountRepository(db *sql.DB, cipher *crypto.Cipher, logger *slog.Logger) repository.MicrosoftAccountRepository {
+	return &microsoftAccountRepository{
+		db:     newTenantDB(db),
+		cipher: cipher,
+		logger: logger,
+	}
+}
+
+const microsoftAccountColumns = `user_id, provider, provider_account_id, access_token, refresh_token, expires_at, created_at, updated_at`
+
+// scanMicrosoftAccount はmicrosoftAccountColumnsの順に読み取ったMicrosoftアカウントを返す
+func scanMicrosoftAccount(row rowScanner) (*model.MicrosoftAccount, error) {
+	var account model.MicrosoftAccount
+	var expiresAt sql.NullInt64
+	if err := row.Scan(
+		&account.UserID, &account.Provider, &account.ProviderAccountID,
+		&account.AccessToken, &account.RefreshToken, &expiresAt,
+		&account.CreatedAt, &account.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+
+	if expiresAt.Valid {
+		t := time.Unix(expiresAt.Int64, 0)
+		account.ExpiresAt = &t
+	}
+	return &account, nil
+}
+
+func (r *microsoftAccountRepository) Create(ctx context.Context, account *model.MicrosoftAccount) error {
+	query := `
+		INSERT INTO microsoft_account (` + microsoftAccountColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`
+
+	var expiresAt *int64
+	if account.ExpiresAt != nil {
+		ts := account.ExpiresAt.Unix()
+		expiresAt = &ts
+	}
+
+	accessToken, refreshToken, err := encryptTokens(r.cipher, account.AccessToken, account.RefreshToken)
+	if err != nil {
+		return fmt.Errorf("failed to create microsoft account: %w", err)
+	}
+
+	_, err = r.db.ExecContext(ctx, query,
+		account.UserID, account.Provider, account.ProviderAccountID,
+		accessToken, refreshToken, expiresAt,
+		account.CreatedAt, account.UpdatedAt,
+	)
+	if err != nil {
+		r.logger.ErrorContext(ctx, "failed to create microsoft account", "error", err)
+		return fmt.Errorf("failed to create microsoft account: %w", err)
+	}
+
+	r.logger.InfoContext(ctx, "microsoft account created", "user_id", account.UserID)
+	return nil
+}
+
+func (r *microsoftAccountRepository) FindByProviderAccountID(ctx context.Context, provider, providerAccountID string) (*model.MicrosoftAccount, error) {
+	query := `SELECT ` + microsoftAccountColumns + ` FROM microsoft_account WHERE provider = $1 AND provider_account_id = $2`
+
+	account, err := scanMicrosoftAccount(r.db.QueryRowContext(ctx, query, provider, providerAccountID))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("microsoft %w: %s", repository.ErrAccountNotFound, providerAccountID)
+	}
+	if err != nil {
+		r.logger.ErrorContext(ctx, "failed to find microsoft account", "error", err)
+		return nil, fmt.Errorf("failed to find microsoft account: %w", err)
+	}
+	if err := decryptTokens(r.cipher, &account.AccessToken, &account.RefreshToken); err != nil {
+		r.logger.ErrorContext(ctx, "failed to decrypt microsoft account", "error", err)
+		return nil, fmt.Errorf("failed to find microsoft account: %w", err)
+	}
+
+	return account, nil
+}
+
+func (r *microsoftAccountRepository) FindByUserID(ctx context.Context, userID string) (*model.MicrosoftAccount, error) {
+	query := `SELECT ` + microsoftAccountColumns + ` FROM microsoft_account WHERE user_id = $1`
+
+	account, err := scanMicrosoftAccount(r.db.QueryRowContext(ctx, query, userID))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("microsoft %w (user %s)", repository.ErrAccountNotFound, userID)
+	}
+	if err != nil {
+		r.logger.ErrorContext(ctx, "failed to find microsoft account by user_id", "error", err)
+		return nil, fmt.Errorf("failed to find microsoft account: %w", err)
+	}
+	if err := decryptTokens(r.cipher, &account.AccessToken, &account.RefreshToken); err != nil {
+		r.logger.ErrorContext(ctx, "failed to decrypt microsoft account", "error", err)
+		return nil, fmt.Errorf("failed to find microsoft account: %w", err)
+	}
+
+	return account, nil
+}
+
+func (r *microsoftAccountRepository) Update(ctx context.Context, account *model.MicrosoftAccount) error {
+	query := `
+		UPDATE microsoft_account
+		SET access_token = $1, refresh_token = $2, expires_at = $3, updated_at = $4
+		WHERE provider = $5 AND provider_account_id = $6
+	`
+
+	var expiresAt *int64
+	if account.ExpiresAt != nil {
+		ts := account.ExpiresAt.Unix()
+		expiresAt = &ts
+	}
+
+	accessToken, refreshToken, err := encryptTokens(r.cipher, account.AccessToken, account.RefreshToken)
+	if err != nil {
+		return fmt.Errorf("failed to update microsoft account: %w", err)
+	}
+
+	result, err := r.db.ExecContext(ctx, query,
+		accessToken, refreshToken, expiresAt, time.Now(),
+		account.Provider, account.ProviderAccountID,
+	)
+	if err != nil {
+		r.logger.ErrorContext(ctx, "failed to update microsoft account", "error", err)
+		return fmt.Errorf("failed to update microsoft account: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("microsoft %w", repository.ErrAccountNotFound)
+	}
+
+	r.logger.InfoContext(ctx, "microsoft account updated")
+	return nil
+}
+
+func (r *microsoftAccountRepository) Delete(ctx context.Context, provider, providerAccountID string) error {
+	query := `DELETE FROM microsoft_account WHERE provider = $1 AND provider_account_id = $2`
+
+	result, err := r.db.ExecContext(ctx, query, provider, providerAccountID)
+	if err != nil {
+		r.logger.ErrorContext(ctx, "failed to delete microsoft account", "error", err)
+		return fmt.Errorf("failed to delete microsoft account: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("microsoft %w", repository.ErrAccountNotFound)
+	}
+
+	r.logger.InfoContext(ctx, "microsoft account deleted")
+	return nil
+}
+
+type samlAccountRepository struct {
+	db     *tenantDB
+	logger *slog.Logger
+}
+
+// NewSAMLAccountRepository は新しいSAMLAccountRepositoryを作成する
+func NewSAMLAccountRepository(db *sql.DB, logger *slog.Logger) repository.SAMLAccountRepository {
+	return &samlAccountRepository{
+		db:     newTenantDB(db),
+		logger: logger,
+	}
+}
+
+func (r *samlAccountRepository) Create(ctx context.Context, account *model.SAMLAccount) error {
+	query := `
+		INSERT INTO saml_account (user_id, idp_entity_id, name_id, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5)
+	`
+
+	_, err := r.db.ExecContext(ctx, query,
+		account.UserID, account.IdPEntityID, account.NameID, account.CreatedAt, account.UpdatedAt,
+	)
+	if err != nil {
+		r.logger.ErrorContext(ctx, "failed to create saml account", "error", err)
+		return fmt.Errorf("failed to create saml account: %w", err)
+	}
+
+	r.logger.InfoContext(ctx, "saml account created", "user_id", account.UserID)
+	return nil
+}
+
+func (r *samlAccountRepository) FindByNameID(ctx context.Context, idpEntityID, nameID string) (*model.SAMLAccount, error) {
+	query := `
+		SELECT user_id, idp_entity_id, name_id, created_at, updated_at
+		FROM saml_account WHERE idp_entity_id = $1 AND name_id = $2
+	`
+
+	var account model.SAMLAccount
+	err := r.db.QueryRowContext(ctx, query, idpEntityID, nameID).Scan(
+		&account.UserID, &account.IdPEntityID, &account.NameID, &account.CreatedAt, &account.UpdatedAt,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("saml %w: %s", repository.ErrAccountNotFound, nameID)
+	}
+	if err != nil {
+		r.logger.ErrorContext(ctx, "failed to find saml account", "error", err)
+		return nil, fmt.Errorf("failed to find saml account: %w", err)
+	}
+
+	return &account, nil
+}
+
+func (r *samlAccountRepository) Touch(ctx context.Context, idpEntityID, nameID string, at time.Time) error {
+	query := `UPDATE saml_account SET updated_at = $1 WHERE idp_entity_id = $2 AND name_id = $3`
+
+	result, err := r.db.ExecContext(ctx, query, at, idpEntityID, nameID)
+	if err != nil {
+		r.logger.ErrorContext(ctx, "failed to update saml account", "error", err)
+		return fmt.Errorf("failed to update saml account: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("saml %w", repository.ErrAccountNotFound)
+	}
+	return nil
+}
+
+type samlRequestRepository struct {
+	db     *tenantDB
+	logger *slog.Logger
+}
+
+// NewSAMLRequestRepository は新しいSAMLRequestRepositoryを作成する
+func NewSAMLRequestRepository(db *sql.DB, logger *slog.Logger) repository.SAMLRequestRepository {
+	return &samlRequestRepository{
+		db:     newTenantDB(db),
+		logger: logger,
+	}
+}
+
+func (r *samlRequestRepository) Create(ctx context.Context, request *model.SAMLRequest) error {
+	// IdPでログインを中断したAuthnRequestが残り続けないよう、保存のたびに有効期限切れのものを削除する
+	query := `
+		WITH expired AS (DELETE FROM saml_request WHERE expires_at <= $4)
+		INSERT INTO saml_request (relay_state_hash, request_id, expires_at, created_at)
+		VALUES ($1, $2, $3, $4)
+	`
+
+	_, err := r.db.ExecContext(ctx, query, request.RelayStateHash, request.RequestID, request.ExpiresAt, request.CreatedAt)
+	if err != nil {
+		r.logger.ErrorContext(ctx, "failed to create saml request", "error", err)
+		return fmt.Errorf("failed to create saml request: %w", err)
+	}
+	return nil
+}
+
+func (r *samlRequestRepository) Consume(ctx context.Context, relayStateHash string, now time.Time) (*model.SAMLRequest, error) {
+	query := `
+		DELETE FROM saml_request WHERE relay_state_hash = $1
+		RETURNING relay_state_hash, request_id, expires_at, created_at
+	`
+
+	var request model.SAMLRequest
+	err := r.db.QueryRowContext(ctx, query, relayStateHash).Scan(
+		&request.RelayStateHash, &request.RequestID, &request.ExpiresAt, &request.CreatedAt,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, model.ErrNotFound
+	}
+	if err != nil {
+		r.logger.ErrorContext(ctx, "failed to consume saml request", "error", err)
+		return nil, fmt.Errorf("failed to consume saml request: %w", err)
+	}
+	if !request.ExpiresAt.After(now) {
+		return nil, fmt.Errorf("saml request expired: %w", model.ErrNotFound)
+	}
+
+	return &request, nil
+}
@@ -0,0 +1,109 @@
+package persistence
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+
+	"github.com/sikigasa/github-task-controller/backend/internal/domain/model"
+	"github.com/sikigasa/github-task-controller/backend/internal/domain/repository"
+)
+
+// activityColumns はアクティビティ検索時に取得するカラム（scanActivityの引数順と一致させる）
+const activityColumns = `id, project_id, actor_id, entity_type, entity_id, action, changes, occurred_at`
+
+type activityRepository struct {
+	db     *tenantDB
+	logger *slog.Logger
+}
+
+// NewActivityRepository は新しいActivityRepositoryを作成する
+func NewActivityRepository(db *sql.DB, logger *slog.Logger) repository.ActivityRepository {
+	return &activityRepository{
+		db:     newTenantDB(db),
+		logger: logger,
+	}
+}
+
+func (r *activityRepository) Create(ctx context.Context, activity *model.Activity) error {
+	changes, err := json.Marshal(activity.Changes)
+	if err != nil {
+		return fmt.Errorf("failed to marshal activity changes: %w", err)
+	}
+
+	query := `
+		INSERT INTO activity (` + activityColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`
+
+	_, err = r.db.ExecContext(ctx, query,
+		activity.ID, activity.ProjectID, activity.ActorID,
+		activity.EntityType, activity.EntityID, activity.Action,
+		changes, activity.OccurredAt,
+	)
+	if err != nil {
+		r.logger.ErrorContext(ctx, "failed to create activity", "error", err, "project_id", activity.ProjectID, "entity_id", activity.EntityID)
+		return fmt.Errorf("failed to create activity: %w", err)
+	}
+
+	return nil
+}
+
+func (r *activityRepository) FindByProjectID(ctx context.Context, projectID string, opts model.ListOptions) ([]*model.Activity, error) {
+	query := `
+		SELECT ` + activityColumns + `
+		FROM activity
+		WHERE project_id = $1
+		ORDER BY occurred_at DESC, id DESC
+	` + paginationClause(opts)
+
+	rows, err := r.db.QueryContext(ctx, query, projectID)
+	if err != nil {
+		r.logger.ErrorContext(ctx, "failed to find activities", "error", err, "project_id", projectID)
+		return nil, fmt.Errorf("failed to find activities: %w", err)
+	}
+	defer rows.Close()
+
+	var activities []*model.Activity
+	for rows.Next() {
+		activity, err := scanActivity(rows)
+		if err != nil {
+			r.logger.ErrorContext(ctx, "failed to scan activity", "error", err)
+			return nil, fmt.Errorf("failed to scan activity: %w", err)
+		}
+		activities = append(activities, activity)
+	}
+
+	if err := rows.Err(); err != nil {
+		r.logger.ErrorContext(ctx, "error iterating activities", "error", err)
+		return nil, fmt.Errorf("error iterating activities: %w", err)
+	}
+
+	return activities, nil
+}
+
+// scanActivity はactivityColumnsの順で1行をスキャンする
+func scanActivity(row rowScanner) (*model.Activity, error) {
+	var activity model.Activity
+	var actorID sql.NullString
+	var changes []byte
+	err := row.Scan(
+		&activity.ID, &activity.ProjectID, &actorID,
+		&activity.EntityType, &activity.EntityID, &activity.Action,
+		&changes, &activity.OccurredAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if actorID.Valid {
+		activity.ActorID = &actorID.String
+	}
+	if err := json.Unmarshal(changes, &activity.Changes); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal activity changes: %w", err)
+	}
+
+	return &activity, nil
+}
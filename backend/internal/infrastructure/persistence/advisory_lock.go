@@ -0,0 +1,88 @@
+package persistence
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/sikigasa/github-task-controller/backend/internal/domain/repository"
+)
+
+const (
+	// advisoryLockPollInterval はLockでロックの解放を確認する間隔
+	advisoryLockPollInterval = 250 * time.Millisecond
+	// advisoryUnlockTimeout はロックの解放を待つ時間
+	advisoryUnlockTimeout = 5 * time.Second
+)
+
+type advisoryLocker struct {
+	db     *sql.DB
+	logger *slog.Logger
+}
+
+// NewAdvisoryLocker はPostgreSQLのアドバイザリーロックを使う新しいLockerを作成する
+// ロックはセッション単位のため、取得している間は接続を1つ占有する
+func NewAdvisoryLocker(db *sql.DB, logger *slog.Logger) repository.Locker {
+	return &advisoryLocker{
+		db:     db,
+		logger: logger,
+	}
+}
+
+func (l *advisoryLocker) TryLock(ctx context.Context, key string) (func(), bool, error) {
+	// アドバイザリーロックは接続（セッション）に結び付くため、専用の接続で取得して解放まで保持する
+	conn, err := l.db.Conn(ctx)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to get connection for lock %s: %w", key, err)
+	}
+
+	var acquired bool
+	// キーの文字列は64bitのハッシュにしてロックのIDにする
+	if err := conn.QueryRowContext(ctx, `SELECT pg_try_advisory_lock(hashtextextended($1, 0))`, key).Scan(&acquired); err != nil {
+		conn.Close()
+		return nil, false, fmt.Errorf("failed to acquire lock %s: %w", key, err)
+	}
+	if !acquired {
+		conn.Close()
+		return nil, false, nil
+	}
+
+	return func() { l.unlock(conn, key) }, true, nil
+}
+
+func (l *advisoryLocker) Lock(ctx context.Context, key string) (func(), error) {
+	// ブロックするpg_advisory_lockは待つ間も接続を占有するため、接続を返してから再試行する
+	ticker := time.NewTicker(advisoryLockPollInterval)
+	defer ticker.Stop()
+	for {
+		unlock, acquired, err := l.TryLock(ctx, key)
+		if err != nil {
+			return nil, err
+		}
+		if acquired {
+			return unlock, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// unlock はロックを解放して接続をプールに返す
+// 解放に失敗した場合はロックが残らないように接続を破棄する（セッションの終了でロックも解放される）
+func (l *advisoryLocker) unlock(conn *sql.Conn, key string) {
+	ctx, cancel := context.WithTimeout(context.Background(), advisoryUnlockTimeout)
+	defer cancel()
+
+	if _, err := conn.ExecContext(ctx, `SELECT pg_advisory_unlock(hashtextextended($1, 0))`, key); err != nil {
+		l.logger.WarnContext(ctx, "failed to release advisory lock, discarding connection", "error", err, "key", key)
+		_ = conn.Raw(func(any) error { return driver.ErrBadConn })
+	}
+	conn.Close()
+}
@@ -0,0 +1,111 @@
+package persistence
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/sikigasa/github-task-controller/backend/internal/infrastructure/session"
+)
+
+type authSessionRepository struct {
+	db     *tenantDB
+	logger *slog.Logger
+}
+
+// NewAuthSessionRepository はCookieのセッションをauth_sessionテーブルに保存するsession.Backendを作成する
+func NewAuthSessionRepository(db *sql.DB, logger *slog.Logger) session.Backend {
+	return &authSessionRepository{
+		db:     newTenantDB(db),
+		logger: logger,
+	}
+}
+
+func (r *authSessionRepository) Find(ctx context.Context, id string, now time.Time) (*session.Record, error) {
+	query := `
+		SELECT id, user_id, user_session_id, data, expires_at
+		FROM auth_session
+		WHERE id = $1 AND expires_at > $2
+	`
+
+	var record session.Record
+	var userID, userSessionID sql.NullString
+	var data []byte
+	err := r.db.QueryRowContext(ctx, query, id, now).Scan(&record.ID, &userID, &userSessionID, &data, &record.ExpiresAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, session.ErrInvalidSession
+	}
+	if err != nil {
+		r.logger.ErrorContext(ctx, "failed to find auth session", "error", err)
+		return nil, fmt.Errorf("failed to find auth session: %w", err)
+	}
+
+	if err := json.Unmarshal(data, &record.Values); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal auth session data: %w", err)
+	}
+	record.UserID = userID.String
+	record.UserSessionID = userSessionID.String
+	return &record, nil
+}
+
+func (r *authSessionRepository) Save(ctx context.Context, record *session.Record) error {
+	data, err := json.Marshal(record.Values)
+	if err != nil {
+		return fmt.Errorf("failed to marshal auth session data: %w", err)
+	}
+
+	query := `
+		INSERT INTO auth_session (id, user_id, user_session_id, data, expires_at, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $6)
+		ON CONFLICT (id) DO UPDATE SET
+			user_id = EXCLUDED.user_id,
+			user_session_id = EXCLUDED.user_session_id,
+			data = EXCLUDED.data,
+			expires_at = EXCLUDED.expires_at,
+			updated_at = EXCLUDED.updated_at
+	`
+
+	_, err = r.db.ExecContext(ctx, query,
+		record.ID,
+		sql.NullString{String: record.UserID, Valid: record.UserID != ""},
+		sql.NullString{String: record.UserSessionID, Valid: record.UserSessionID != ""},
+		data, record.ExpiresAt, time.Now(),
+	)
+	if err != nil {
+		r.logger.ErrorContext(ctx, "failed to save auth session", "error", err, "user_id", record.UserID)
+		return fmt.Errorf("failed to save auth session: %w", err)
+	}
+
+	return nil
+}
+
+func (r *authSessionRepository) Delete(ctx context.Context, id string) error {
+	query := `DELETE FROM auth_session WHERE id = $1`
+
+	if _, err := r.db.ExecContext(ctx, query, id); err != nil {
+		r.logger.ErrorContext(ctx, "failed to delete auth session", "error", err)
+		return fmt.Errorf("failed to delete auth session: %w", err)
+	}
+
+	return nil
+}
+
+func (r *authSessionRepository) DeleteExpired(ctx context.Context, now time.Time) (int64, error) {
+	query := `DELETE FROM auth_session WHERE expires_at <= $1`
+
+	result, err := r.db.ExecContext(ctx, query, now)
+	if err != nil {
+		r.logger.ErrorContext(ctx, "failed to delete expired auth sessions", "error", err)
+		return 0, fmt.Errorf("failed to delete expired auth sessions: %w", err)
+	}
+
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	return deleted, nil
+}
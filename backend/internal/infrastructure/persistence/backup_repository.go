@@ -0,0 +1,189 @@
+package persistence
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/sikigasa/github-task-controller/backend/internal/domain/model"
+	"github.com/sikigasa/github-task-controller/backend/internal/domain/repository"
+)
+
+// backupTables はバックアップ対象のテーブル（外部キーの参照先が先になる順）
+// テーブルを追加した場合はここにも追加する
+// outbox_eventは配信済みのイベントを復元後に再配信しないよう対象外とする
+// github_sync_usageは1時間ごとの同期の回数のため対象外とする
+// auth_sessionは復元で失効させたセッションが再び有効にならないよう対象外とする
+var backupTables = []string{
+	"users",
+	"github_account",
+	"google_account",
+	"apple_account",
+	"microsoft_account",
+	"saml_account",
+	"scim_group",
+	"scim_group_member",
+	"guest_user",
+	"invitation",
+	"account_merge",
+	"user_session",
+	"refresh_token",
+	"push_device",
+	"project",
+	"user_settings",
+	"milestone",
+	"task",
+	"task_status_event",
+	"task_dependency",
+	"task_relation",
+	"task_reminder",
+	"activity",
+	"goal",
+	"goal_task",
+	"saved_view",
+	"project_token",
+	"webhook_endpoint",
+	"webhook_endpoint_delivery",
+	"todos",
+	"report_export",
+	"task_pull_request",
+	"task_commit",
+	"github_field_mapping",
+	"github_sync_operation",
+	"github_task_sync",
+	"webhook_delivery",
+}
+
+// restoreBatchSize は復元時に1回のINSERTで書き込む行数
+const restoreBatchSize = 500
+
+type backupRepository struct {
+	// db はテナントを設定しない接続プール（すべての行を対象にするため、トランザクションごとに行レベルセキュリティの制限を外す）
+	db     *sql.DB
+	logger *slog.Logger
+}
+
+// NewBackupRepository は新しいBackupRepositoryを作成する
+func NewBackupRepository(db *sql.DB, logger *slog.Logger) repository.BackupRepository {
+	return &backupRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+func (r *backupRepository) Tables() []string {
+	return append([]string(nil), backupTables...)
+}
+
+func (r *backupRepository) Dump(ctx context.Context, fn func(table string, row json.RawMessage) error) error {
+	// テーブル間で整合したスナップショットにするため1つの読み取り専用トランザクションで読む
+	tx, err := r.db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+	if err := (tenant{bypass: true}).apply(ctx, tx); err != nil {
+		return err
+	}
+
+	for _, table := range backupTables {
+		if err := r.dumpTable(ctx, tx, table, fn); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// dumpTable は1つのテーブルの行をJSONで順にfnに渡す
+func (r *backupRepository) dumpTable(ctx context.Context, tx *sql.Tx, table string, fn func(table string, row json.RawMessage) error) error {
+	rows, err := tx.QueryContext(ctx, `SELECT row_to_json(t) FROM `+table+` t`)
+	if err != nil {
+		r.logger.ErrorContext(ctx, "failed to dump table", "error", err, "table", table)
+		return fmt.Errorf("failed to dump %s: %w", table, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var row []byte
+		if err := rows.Scan(&row); err != nil {
+			return fmt.Errorf("failed to scan %s: %w", table, err)
+		}
+		if err := fn(table, row); err != nil {
+			return err
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to dump %s: %w", table, err)
+	}
+	return nil
+}
+
+func (r *backupRepository) Restore(ctx context.Context, read func(insert func(table string, row json.RawMessage) error) error) error {
+	known := make(map[string]bool, len(backupTables))
+	for _, t := range backupTables {
+		known[t] = true
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+	if err := (tenant{bypass: true}).apply(ctx, tx); err != nil {
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, `TRUNCATE `+strings.Join(backupTables, ", ")+` CASCADE`); err != nil {
+		r.logger.ErrorContext(ctx, "failed to truncate tables for restore", "error", err)
+		return fmt.Errorf("failed to truncate tables: %w", err)
+	}
+
+	// 同じテーブルの行をまとめてjson_populate_recordsetで書き込む
+	var table string
+	var batch []json.RawMessage
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		payload, err := json.Marshal(batch)
+		if err != nil {
+			return fmt.Errorf("failed to encode %s rows: %w", table, err)
+		}
+		query := `INSERT INTO ` + table + ` SELECT * FROM json_populate_recordset(NULL::` + table + `, $1::json)`
+		if _, err := tx.ExecContext(ctx, query, payload); err != nil {
+			r.logger.ErrorContext(ctx, "failed to restore rows", "error", err, "table", table)
+			return fmt.Errorf("failed to restore %s: %w", table, err)
+		}
+		batch = batch[:0]
+		return nil
+	}
+
+	insert := func(t string, row json.RawMessage) error {
+		if !known[t] {
+			return fmt.Errorf("unknown table %q: %w", t, model.ErrInvalidInput)
+		}
+		if t != table || len(batch) >= restoreBatchSize {
+			if err := flush(); err != nil {
+				return err
+			}
+			table = t
+		}
+		batch = append(batch, row)
+		return nil
+	}
+
+	if err := read(insert); err != nil {
+		return err
+	}
+	if err := flush(); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit restore: %w", err)
+	}
+	return nil
+}
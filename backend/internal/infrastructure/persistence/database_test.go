@@ -0,0 +1,24 @@
+package persistence
+
+import "testing"
+
+func TestDBConfigWithRowLevelSecurityBypass(t *testing.T) {
+	cfg := DBConfig{Host: "localhost", Options: map[string]string{"options": "-c role=app", "connect_timeout": "10"}}
+
+	got := cfg.WithRowLevelSecurityBypass()
+	if want := "-c role=app -c app.rls_bypass=on"; got.Options["options"] != want {
+		t.Errorf("options = %q, want %q", got.Options["options"], want)
+	}
+	if got.Options["connect_timeout"] != "10" {
+		t.Errorf("connect_timeout = %q, want %q", got.Options["connect_timeout"], "10")
+	}
+	// 元の接続設定は変更しない
+	if cfg.Options["options"] != "-c role=app" {
+		t.Errorf("original options = %q, want %q", cfg.Options["options"], "-c role=app")
+	}
+
+	empty := DBConfig{Host: "localhost"}.WithRowLevelSecurityBypass()
+	if want := "-c app.rls_bypass=on"; empty.Options["options"] != want {
+		t.Errorf("options without existing options = %q, want %q", empty.Options["options"], want)
+	}
+}
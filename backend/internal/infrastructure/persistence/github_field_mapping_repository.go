@@ -0,0 +1,96 @@
+package persistence
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/sikigasa/github-task-controller/backend/internal/domain/model"
+	"github.com/sikigasa/github-task-controller/backend/internal/domain/repository"
+)
+
+type githubFieldMappingRepository struct {
+	db     *tenantDB
+	logger *slog.Logger
+}
+
+// NewGithubFieldMappingRepository は新しいGithubFieldMappingRepositoryを作成する
+func NewGithubFieldMappingRepository(db *sql.DB, logger *slog.Logger) repository.GithubFieldMappingRepository {
+	return &githubFieldMappingRepository{
+		db:     newTenantDB(db),
+		logger: logger,
+	}
+}
+
+func (r *githubFieldMappingRepository) FindByProjectID(ctx context.Context, projectID string) (*model.GithubFieldMapping, error) {
+	query := `
+		SELECT project_id, status_field, status_todo, status_in_progress, status_done,
+			priority_field, priority_low, priority_medium, priority_high, updated_at
+		FROM github_field_mapping
+		WHERE project_id = $1
+	`
+
+	var mapping model.GithubFieldMapping
+	var priorityField sql.NullString
+	err := r.db.QueryRowContext(ctx, query, projectID).Scan(
+		&mapping.ProjectID, &mapping.StatusField,
+		&mapping.StatusOptions.Todo, &mapping.StatusOptions.InProgress, &mapping.StatusOptions.Done,
+		&priorityField, &mapping.PriorityOptions.Low, &mapping.PriorityOptions.Medium, &mapping.PriorityOptions.High,
+		&mapping.UpdatedAt,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, model.ErrNotFound
+	}
+	if err != nil {
+		r.logger.ErrorContext(ctx, "failed to find github field mapping", "error", err, "project_id", projectID)
+		return nil, fmt.Errorf("failed to find github field mapping: %w", err)
+	}
+
+	if priorityField.Valid {
+		mapping.PriorityField = &priorityField.String
+	}
+
+	return &mapping, nil
+}
+
+func (r *githubFieldMappingRepository) Upsert(ctx context.Context, mapping *model.GithubFieldMapping) error {
+	query := `
+		INSERT INTO github_field_mapping (project_id, status_field, status_todo, status_in_progress, status_done,
+			priority_field, priority_low, priority_medium, priority_high, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		ON CONFLICT (project_id) DO UPDATE SET
+			status_field = EXCLUDED.status_field,
+			status_todo = EXCLUDED.status_todo,
+			status_in_progress = EXCLUDED.status_in_progress,
+			status_done = EXCLUDED.status_done,
+			priority_field = EXCLUDED.priority_field,
+			priority_low = EXCLUDED.priority_low,
+			priority_medium = EXCLUDED.priority_medium,
+			priority_high = EXCLUDED.priority_high,
+			updated_at = EXCLUDED.updated_at
+	`
+
+	_, err := r.db.ExecContext(ctx, query,
+		mapping.ProjectID, mapping.StatusField,
+		mapping.StatusOptions.Todo, mapping.StatusOptions.InProgress, mapping.StatusOptions.Done,
+		mapping.PriorityField, mapping.PriorityOptions.Low, mapping.PriorityOptions.Medium, mapping.PriorityOptions.High,
+		mapping.UpdatedAt,
+	)
+	if err != nil {
+		r.logger.ErrorContext(ctx, "failed to upsert github field mapping", "error", err, "project_id", mapping.ProjectID)
+		return fmt.Errorf("failed to upsert github field mapping: %w", err)
+	}
+
+	return nil
+}
+
+func (r *githubFieldMappingRepository) Delete(ctx context.Context, projectID string) error {
+	if _, err := r.db.ExecContext(ctx, `DELETE FROM github_field_mapping WHERE project_id = $1`, projectID); err != nil {
+		r.logger.ErrorContext(ctx, "failed to delete github field mapping", "error", err, "project_id", projectID)
+		return fmt.Errorf("failed to delete github field mapping: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,197 @@
+package persistence
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/sikigasa/github-task-controller/backend/internal/domain/model"
+	"github.com/sikigasa/github-task-controller/backend/internal/domain/repository"
+)
+
+// githubSyncOperationColumns は保留中の変更の検索時に取得するカラム（scanGithubSyncOperationの引数順と一致させる）
+const githubSyncOperationColumns = `id, user_id, project_id, task_id, operation, attempts, last_error, next_attempt_at, created_at, updated_at`
+
+type githubSyncOperationRepository struct {
+	db     *tenantDB
+	logger *slog.Logger
+}
+
+// NewGithubSyncOperationRepository は新しいGithubSyncOperationRepositoryを作成する
+func NewGithubSyncOperationRepository(db *sql.DB, logger *slog.Logger) repository.GithubSyncOperationRepository {
+	return &githubSyncOperationRepository{
+		db:     newTenantDB(db),
+		logger: logger,
+	}
+}
+
+func (r *githubSyncOperationRepository) Enqueue(ctx context.Context, operation *model.GithubSyncOperation) error {
+	// 同期は実行時点のタスクの内容を反映するため、保留中の同じ変更があれば新しく追加しない
+	query := `
+		INSERT INTO github_sync_operation (id, user_id, project_id, task_id, operation, attempts, next_attempt_at, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		ON CONFLICT (task_id, operation) DO NOTHING
+	`
+
+	_, err := r.db.ExecContext(ctx, query,
+		operation.ID, operation.UserID, operation.ProjectID, operation.TaskID, operation.Operation,
+		operation.Attempts, operation.NextAttemptAt, operation.CreatedAt, operation.UpdatedAt,
+	)
+	if err != nil {
+		r.logger.ErrorContext(ctx, "failed to enqueue github sync operation", "error", err, "task_id", operation.TaskID)
+		return fmt.Errorf("failed to enqueue github sync operation: %w", err)
+	}
+
+	return nil
+}
+
+func (r *githubSyncOperationRepository) ExistsByTaskID(ctx context.Context, taskID string) (bool, error) {
+	query := `SELECT EXISTS (SELECT 1 FROM github_sync_operation WHERE task_id = $1)`
+
+	var exists bool
+	if err := r.db.QueryRowContext(ctx, query, taskID).Scan(&exists); err != nil {
+		r.logger.ErrorContext(ctx, "failed to check github sync operations", "error", err, "task_id", taskID)
+		return false, fmt.Errorf("failed to check github sync operations: %w", err)
+	}
+
+	return exists, nil
+}
+
+func (r *githubSyncOperationRepository) FindByUserID(ctx context.Context, userID string) ([]*model.GithubSyncOperation, error) {
+	query := `
+		SELECT ` + githubSyncOperationColumns + `
+		FROM github_sync_operation
+		WHERE user_id = $1
+		ORDER BY created_at ASC, id ASC
+	`
+
+	return r.query(ctx, query, userID)
+}
+
+func (r *githubSyncOperationRepository) CountByUserID(ctx context.Context, userID string) (int, error) {
+	query := `SELECT COUNT(*) FROM github_sync_operation WHERE user_id = $1`
+
+	var count int
+	if err := r.db.QueryRowContext(ctx, query, userID).Scan(&count); err != nil {
+		r.logger.ErrorContext(ctx, "failed to count github sync operations", "error", err, "user_id", userID)
+		return 0, fmt.Errorf("failed to count github sync operations: %w", err)
+	}
+
+	return count, nil
+}
+
+func (r *githubSyncOperationRepository) ClaimDue(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*model.GithubSyncOperation, error) {
+	// 同じタスクの変更を作成順に実行するため、より古い変更が残っているものは取得しない
+	// 複数のインスタンスで同じ変更を実行しないよう、取得と同時に次の再実行の時刻をlease後にずらす
+	query := `
+		UPDATE github_sync_operation
+		SET next_attempt_at = $2, updated_at = $1
+		WHERE id IN (
+			SELECT o.id FROM github_sync_operation o
+			WHERE o.next_attempt_at <= $1
+				AND NOT EXISTS (
+					SELECT 1 FROM github_sync_operation p
+					WHERE p.task_id = o.task_id AND (p.created_at, p.id) < (o.created_at, o.id)
+				)
+			ORDER BY o.created_at
+			LIMIT $3
+			FOR UPDATE OF o SKIP LOCKED
+		)
+		RETURNING ` + githubSyncOperationColumns
+
+	return r.query(ctx, query, now, now.Add(lease), limit)
+}
+
+func (r *githubSyncOperationRepository) Delete(ctx context.Context, id string) error {
+	query := `DELETE FROM github_sync_operation WHERE id = $1`
+
+	if _, err := r.db.ExecContext(ctx, query, id); err != nil {
+		r.logger.ErrorContext(ctx, "failed to delete github sync operation", "error", err, "operation_id", id)
+		return fmt.Errorf("failed to delete github sync operation: %w", err)
+	}
+
+	return nil
+}
+
+func (r *githubSyncOperationRepository) MarkFailed(ctx context.Context, id string, attempts int, lastError string, nextAttemptAt time.Time) error {
+	query := `
+		UPDATE github_sync_operation
+		SET attempts = $2, last_error = $3, next_attempt_at = $4, updated_at = NOW()
+		WHERE id = $1
+	`
+
+	result, err := r.db.ExecContext(ctx, query, id, attempts, lastError, nextAttemptAt)
+	if err != nil {
+		r.logger.ErrorContext(ctx, "failed to mark github sync operation failed", "error", err, "operation_id", id)
+		return fmt.Errorf("failed to mark github sync operation failed: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("github sync operation not found: %s: %w", id, model.ErrNotFound)
+	}
+
+	return nil
+}
+
+func (r *githubSyncOperationRepository) RetryByUserID(ctx context.Context, userID string, at time.Time) error {
+	query := `UPDATE github_sync_operation SET next_attempt_at = $2 WHERE user_id = $1 AND next_attempt_at > $2`
+
+	if _, err := r.db.ExecContext(ctx, query, userID, at); err != nil {
+		r.logger.ErrorContext(ctx, "failed to retry github sync operations", "error", err, "user_id", userID)
+		return fmt.Errorf("failed to retry github sync operations: %w", err)
+	}
+
+	return nil
+}
+
+// query は保留中の変更を検索するクエリを実行する
+func (r *githubSyncOperationRepository) query(ctx context.Context, query string, args ...any) ([]*model.GithubSyncOperation, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		r.logger.ErrorContext(ctx, "failed to find github sync operations", "error", err)
+		return nil, fmt.Errorf("failed to find github sync operations: %w", err)
+	}
+	defer rows.Close()
+
+	operations := []*model.GithubSyncOperation{}
+	for rows.Next() {
+		operation, err := scanGithubSyncOperation(rows)
+		if err != nil {
+			r.logger.ErrorContext(ctx, "failed to scan github sync operation", "error", err)
+			return nil, fmt.Errorf("failed to scan github sync operation: %w", err)
+		}
+		operations = append(operations, operation)
+	}
+
+	if err := rows.Err(); err != nil {
+		r.logger.ErrorContext(ctx, "error iterating github sync operations", "error", err)
+		return nil, fmt.Errorf("error iterating github sync operations: %w", err)
+	}
+
+	return operations, nil
+}
+
+// scanGithubSyncOperation はgithubSyncOperationColumnsの順で1行をスキャンする
+func scanGithubSyncOperation(row rowScanner) (*model.GithubSyncOperation, error) {
+	var operation model.GithubSyncOperation
+	var lastError sql.NullString
+	err := row.Scan(
+		&operation.ID, &operation.UserID, &operation.ProjectID, &operation.TaskID, &operation.Operation,
+		&operation.Attempts, &lastError, &operation.NextAttemptAt, &operation.CreatedAt, &operation.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if lastError.Valid {
+		operation.LastError = &lastError.String
+	}
+
+	return &operation, nil
+}
@@ -0,0 +1,68 @@
+package persistence
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/sikigasa/github-task-controller/backend/internal/domain/repository"
+)
+
+type githubSyncUsageRepository struct {
+	db     *tenantDB
+	logger *slog.Logger
+}
+
+// NewGithubSyncUsageRepository は新しいGithubSyncUsageRepositoryを作成する
+func NewGithubSyncUsageRepository(db *sql.DB, logger *slog.Logger) repository.GithubSyncUsageRepository {
+	return &githubSyncUsageRepository{
+		db:     newTenantDB(db),
+		logger: logger,
+	}
+}
+
+func (r *githubSyncUsageRepository) Consume(ctx context.Context, userID string, windowStart time.Time, limit int) (int, bool, error) {
+	// 上限の判定と加算を1つの文で行い、複数のインスタンスが同時に同期しても上限を超えないようにする
+	query := `
+		WITH purged AS (
+			DELETE FROM github_sync_usage WHERE user_id = $1 AND window_start < $2
+		)
+		INSERT INTO github_sync_usage (user_id, window_start, count)
+		VALUES ($1, $2, 1)
+		ON CONFLICT (user_id, window_start) DO UPDATE
+		SET count = github_sync_usage.count + 1
+		WHERE $3 <= 0 OR github_sync_usage.count < $3
+		RETURNING count
+	`
+
+	var count int
+	err := r.db.QueryRowContext(ctx, query, userID, windowStart, limit).Scan(&count)
+	if errors.Is(err, sql.ErrNoRows) {
+		return limit, false, nil
+	}
+	if err != nil {
+		r.logger.ErrorContext(ctx, "failed to consume github sync quota", "error", err, "user_id", userID)
+		return 0, false, fmt.Errorf("failed to consume github sync quota: %w", err)
+	}
+
+	return count, true, nil
+}
+
+func (r *githubSyncUsageRepository) FindCount(ctx context.Context, userID string, windowStart time.Time) (int, error) {
+	query := `SELECT count FROM github_sync_usage WHERE user_id = $1 AND window_start = $2`
+
+	var count int
+	err := r.db.QueryRowContext(ctx, query, userID, windowStart).Scan(&count)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, nil
+	}
+	if err != nil {
+		r.logger.ErrorContext(ctx, "failed to find github sync usage", "error", err, "user_id", userID)
+		return 0, fmt.Errorf("failed to find github sync usage: %w", err)
+	}
+
+	return count, nil
+}
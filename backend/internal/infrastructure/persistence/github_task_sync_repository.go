@@ -0,0 +1,103 @@
+package persistence
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/sikigasa/github-task-controller/backend/internal/domain/model"
+	"github.com/sikigasa/github-task-controller/backend/internal/domain/repository"
+)
+
+// githubTaskSyncColumns は同期の基準の検索時に取得するカラム（scanGithubTaskSyncの引数順と一致させる）
+const githubTaskSyncColumns = `task_id, user_id, project_id, local_updated_at, remote_updated_at, updated_at`
+
+type githubTaskSyncRepository struct {
+	db     *tenantDB
+	logger *slog.Logger
+}
+
+// NewGithubTaskSyncRepository は新しいGithubTaskSyncRepositoryを作成する
+func NewGithubTaskSyncRepository(db *sql.DB, logger *slog.Logger) repository.GithubTaskSyncRepository {
+	return &githubTaskSyncRepository{
+		db:     newTenantDB(db),
+		logger: logger,
+	}
+}
+
+func (r *githubTaskSyncRepository) FindByTaskID(ctx context.Context, taskID string) (*model.GithubTaskSync, error) {
+	query := `SELECT ` + githubTaskSyncColumns + ` FROM github_task_sync WHERE task_id = $1`
+
+	sync, err := scanGithubTaskSync(r.db.QueryRowContext(ctx, query, taskID))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("github task sync not found: %s: %w", taskID, model.ErrNotFound)
+	}
+	if err != nil {
+		r.logger.ErrorContext(ctx, "failed to find github task sync", "error", err, "task_id", taskID)
+		return nil, fmt.Errorf("failed to find github task sync: %w", err)
+	}
+
+	return sync, nil
+}
+
+func (r *githubTaskSyncRepository) FindByProjectID(ctx context.Context, projectID string) (map[string]*model.GithubTaskSync, error) {
+	query := `SELECT ` + githubTaskSyncColumns + ` FROM github_task_sync WHERE project_id = $1`
+
+	rows, err := r.db.QueryContext(ctx, query, projectID)
+	if err != nil {
+		r.logger.ErrorContext(ctx, "failed to find github task syncs", "error", err, "project_id", projectID)
+		return nil, fmt.Errorf("failed to find github task syncs: %w", err)
+	}
+	defer rows.Close()
+
+	syncs := make(map[string]*model.GithubTaskSync)
+	for rows.Next() {
+		sync, err := scanGithubTaskSync(rows)
+		if err != nil {
+			r.logger.ErrorContext(ctx, "failed to scan github task sync", "error", err)
+			return nil, fmt.Errorf("failed to scan github task sync: %w", err)
+		}
+		syncs[sync.TaskID] = sync
+	}
+
+	if err := rows.Err(); err != nil {
+		r.logger.ErrorContext(ctx, "error iterating github task syncs", "error", err)
+		return nil, fmt.Errorf("error iterating github task syncs: %w", err)
+	}
+
+	return syncs, nil
+}
+
+func (r *githubTaskSyncRepository) Upsert(ctx context.Context, sync *model.GithubTaskSync) error {
+	query := `
+		INSERT INTO github_task_sync (task_id, user_id, project_id, local_updated_at, remote_updated_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (task_id) DO UPDATE SET
+			project_id = EXCLUDED.project_id,
+			local_updated_at = EXCLUDED.local_updated_at,
+			remote_updated_at = COALESCE(EXCLUDED.remote_updated_at, github_task_sync.remote_updated_at),
+			updated_at = EXCLUDED.updated_at
+	`
+
+	_, err := r.db.ExecContext(ctx, query,
+		sync.TaskID, sync.UserID, sync.ProjectID, sync.LocalUpdatedAt, sync.RemoteUpdatedAt, sync.UpdatedAt,
+	)
+	if err != nil {
+		r.logger.ErrorContext(ctx, "failed to upsert github task sync", "error", err, "task_id", sync.TaskID)
+		return fmt.Errorf("failed to upsert github task sync: %w", err)
+	}
+
+	return nil
+}
+
+// scanGithubTaskSync はgithubTaskSyncColumnsの順で1行をスキャンする
+func scanGithubTaskSync(row rowScanner) (*model.GithubTaskSync, error) {
+	var sync model.GithubTaskSync
+	err := row.Scan(&sync.TaskID, &sync.UserID, &sync.ProjectID, &sync.LocalUpdatedAt, &sync.RemoteUpdatedAt, &sync.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &sync, nil
+}
@@ -0,0 +1,268 @@
+package persistence
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/sikigasa/github-task-controller/backend/internal/domain/model"
+	"github.com/sikigasa/github-task-controller/backend/internal/domain/repository"
+)
+
+// goalColumns は目標検索時に取得するカラム（scanGoalの引数順と一致させる）
+const goalColumns = `id, project_id, title, description, quarter, created_at, updated_at`
+
+type goalRepository struct {
+	db     *tenantDB
+	logger *slog.Logger
+}
+
+// NewGoalRepository は新しいGoalRepositoryを作成する
+func NewGoalRepository(db *sql.DB, logger *slog.Logger) repository.GoalRepository {
+	return &goalRepository{
+		db:     newTenantDB(db),
+		logger: logger,
+	}
+}
+
+func (r *goalRepository) Create(ctx context.Context, goal *model.Goal) error {
+	query := `
+		INSERT INTO goal (id, project_id, title, description, quarter, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`
+
+	_, err := r.db.ExecContext(ctx, query,
+		goal.ID, goal.ProjectID, goal.Title, goal.Description, goal.Quarter,
+		goal.CreatedAt, goal.UpdatedAt,
+	)
+	if err != nil {
+		r.logger.ErrorContext(ctx, "failed to create goal", "error", err)
+		return fmt.Errorf("failed to create goal: %w", err)
+	}
+
+	r.logger.InfoContext(ctx, "goal created", "goal_id", goal.ID)
+	return nil
+}
+
+func (r *goalRepository) FindByID(ctx context.Context, id string) (*model.Goal, error) {
+	query := `
+		SELECT ` + goalColumns + `
+		FROM goal
+		WHERE id = $1
+	`
+
+	goal, err := scanGoal(r.db.QueryRowContext(ctx, query, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, model.ErrNotFound
+	}
+	if err != nil {
+		r.logger.ErrorContext(ctx, "failed to find goal by id", "error", err, "id", id)
+		return nil, fmt.Errorf("failed to find goal by id: %w", err)
+	}
+
+	return goal, nil
+}
+
+func (r *goalRepository) FindByProjectID(ctx context.Context, projectID, quarter string) ([]*model.Goal, error) {
+	query := `
+		SELECT ` + goalColumns + `
+		FROM goal
+		WHERE project_id = $1 AND ($2 = '' OR quarter = $2)
+		ORDER BY quarter ASC, created_at ASC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, projectID, quarter)
+	if err != nil {
+		r.logger.ErrorContext(ctx, "failed to find goals by project_id", "error", err, "project_id", projectID)
+		return nil, fmt.Errorf("failed to find goals by project_id: %w", err)
+	}
+	defer rows.Close()
+
+	var goals []*model.Goal
+	for rows.Next() {
+		goal, err := scanGoal(rows)
+		if err != nil {
+			r.logger.ErrorContext(ctx, "failed to scan goal", "error", err)
+			return nil, fmt.Errorf("failed to scan goal: %w", err)
+		}
+		goals = append(goals, goal)
+	}
+
+	if err := rows.Err(); err != nil {
+		r.logger.ErrorContext(ctx, "error iterating goals", "error", err)
+		return nil, fmt.Errorf("error iterating goals: %w", err)
+	}
+
+	return goals, nil
+}
+
+func (r *goalRepository) Update(ctx context.Context, goal *model.Goal) error {
+	query := `
+		UPDATE goal
+		SET title = $1, description = $2, quarter = $3, updated_at = $4
+		WHERE id = $5
+	`
+
+	result, err := r.db.ExecContext(ctx, query, goal.Title, goal.Description, goal.Quarter, time.Now(), goal.ID)
+	if err != nil {
+		r.logger.ErrorContext(ctx, "failed to update goal", "error", err, "goal_id", goal.ID)
+		return fmt.Errorf("failed to update goal: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return model.ErrNotFound
+	}
+
+	r.logger.InfoContext(ctx, "goal updated", "goal_id", goal.ID)
+	return nil
+}
+
+func (r *goalRepository) Delete(ctx context.Context, id string) error {
+	query := `DELETE FROM goal WHERE id = $1`
+
+	result, err := r.db.ExecContext(ctx, query, id)
+	if err != nil {
+		r.logger.ErrorContext(ctx, "failed to delete goal", "error", err, "goal_id", id)
+		return fmt.Errorf("failed to delete goal: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return model.ErrNotFound
+	}
+
+	r.logger.InfoContext(ctx, "goal deleted", "goal_id", id)
+	return nil
+}
+
+func (r *goalRepository) AddTask(ctx context.Context, goalID, taskID string) error {
+	query := `
+		INSERT INTO goal_task (goal_id, task_id, created_at)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (goal_id, task_id) DO NOTHING
+	`
+
+	if _, err := r.db.ExecContext(ctx, query, goalID, taskID, time.Now()); err != nil {
+		r.logger.ErrorContext(ctx, "failed to add goal task", "error", err, "goal_id", goalID)
+		return fmt.Errorf("failed to add goal task: %w", err)
+	}
+
+	return nil
+}
+
+func (r *goalRepository) RemoveTask(ctx context.Context, goalID, taskID string) error {
+	query := `DELETE FROM goal_task WHERE goal_id = $1 AND task_id = $2`
+
+	result, err := r.db.ExecContext(ctx, query, goalID, taskID)
+	if err != nil {
+		r.logger.ErrorContext(ctx, "failed to remove goal task", "error", err, "goal_id", goalID)
+		return fmt.Errorf("failed to remove goal task: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return model.ErrNotFound
+	}
+
+	return nil
+}
+
+func (r *goalRepository) FindTaskIDs(ctx context.Context, goalID string) ([]string, error) {
+	query := `SELECT task_id FROM goal_task WHERE goal_id = $1 ORDER BY created_at ASC`
+
+	rows, err := r.db.QueryContext(ctx, query, goalID)
+	if err != nil {
+		r.logger.ErrorContext(ctx, "failed to find goal tasks", "error", err, "goal_id", goalID)
+		return nil, fmt.Errorf("failed to find goal tasks: %w", err)
+	}
+	defer rows.Close()
+
+	taskIDs := []string{}
+	for rows.Next() {
+		var taskID string
+		if err := rows.Scan(&taskID); err != nil {
+			r.logger.ErrorContext(ctx, "failed to scan goal task", "error", err)
+			return nil, fmt.Errorf("failed to scan goal task: %w", err)
+		}
+		taskIDs = append(taskIDs, taskID)
+	}
+
+	if err := rows.Err(); err != nil {
+		r.logger.ErrorContext(ctx, "error iterating goal tasks", "error", err)
+		return nil, fmt.Errorf("error iterating goal tasks: %w", err)
+	}
+
+	return taskIDs, nil
+}
+
+func (r *goalRepository) ProgressByIDs(ctx context.Context, ids []string) (map[string]model.GoalProgress, error) {
+	progress := make(map[string]model.GoalProgress, len(ids))
+	if len(ids) == 0 {
+		return progress, nil
+	}
+
+	query := `
+		SELECT gt.goal_id,
+			COUNT(*),
+			COUNT(*) FILTER (WHERE t.status = $2),
+			COALESCE(SUM(t.estimate), 0),
+			COALESCE(SUM(t.estimate) FILTER (WHERE t.status = $2), 0)
+		FROM goal_task gt
+		JOIN task t ON t.id = gt.task_id
+		WHERE gt.goal_id = ANY($1) AND t.deleted_at IS NULL
+		GROUP BY gt.goal_id
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, pq.Array(ids), model.TaskStatusDone)
+	if err != nil {
+		r.logger.ErrorContext(ctx, "failed to count goal tasks", "error", err)
+		return nil, fmt.Errorf("failed to count goal tasks: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id string
+		var taskCount, doneCount int
+		var estimateTotal, estimateDone float64
+		if err := rows.Scan(&id, &taskCount, &doneCount, &estimateTotal, &estimateDone); err != nil {
+			r.logger.ErrorContext(ctx, "failed to scan goal progress", "error", err)
+			return nil, fmt.Errorf("failed to scan goal progress: %w", err)
+		}
+		progress[id] = model.NewGoalProgress(taskCount, doneCount, estimateTotal, estimateDone)
+	}
+
+	if err := rows.Err(); err != nil {
+		r.logger.ErrorContext(ctx, "error iterating goal progress", "error", err)
+		return nil, fmt.Errorf("error iterating goal progress: %w", err)
+	}
+
+	return progress, nil
+}
+
+// scanGoal はgoalColumnsの順で1行をスキャンする
+func scanGoal(row rowScanner) (*model.Goal, error) {
+	var goal model.Goal
+	err := row.Scan(
+		&goal.ID, &goal.ProjectID, &goal.Title, &goal.Description, &goal.Quarter,
+		&goal.CreatedAt, &goal.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &goal, nil
+}
@@ -0,0 +1,231 @@
+package persistence
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/sikigasa/github-task-controller/backend/internal/domain/model"
+	"github.com/sikigasa/github-task-controller/backend/internal/domain/repository"
+)
+
+// groupColumns はグループ検索時に取得するカラム（scanGroupの引数順と一致させる）
+// メンバーはscim_group_memberを集約して取得する
+const groupColumns = `g.id, g.display_name, COALESCE(g.external_id, ''),
+	ARRAY(SELECT m.user_id::text FROM scim_group_member m WHERE m.group_id = g.id ORDER BY m.user_id),
+	g.created_at, g.updated_at`
+
+type groupRepository struct {
+	db     *tenantDB
+	logger *slog.Logger
+}
+
+// NewGroupRepository は新しいGroupRepositoryを作成する
+func NewGroupRepository(db *sql.DB, logger *slog.Logger) repository.GroupRepository {
+	return &groupRepository{
+		db:     newTenantDB(db),
+		logger: logger,
+	}
+}
+
+// scanGroup はgroupColumnsの順に読み取ったグループを返す
+func scanGroup(row rowScanner) (*model.Group, error) {
+	var group model.Group
+	if err := row.Scan(
+		&group.ID, &group.DisplayName, &group.ExternalID,
+		pq.Array(&group.MemberIDs), &group.CreatedAt, &group.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+	if group.MemberIDs == nil {
+		group.MemberIDs = []string{}
+	}
+	return &group, nil
+}
+
+func (r *groupRepository) Create(ctx context.Context, group *model.Group) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback() // コミット済みの場合は何もしない
+
+	query := `
+		INSERT INTO scim_group (id, display_name, external_id, created_at, updated_at)
+		VALUES ($1, $2, NULLIF($3, ''), $4, $5)
+	`
+	if _, err := tx.ExecContext(ctx, query,
+		group.ID, group.DisplayName, group.ExternalID, group.CreatedAt, group.UpdatedAt,
+	); err != nil {
+		r.logger.ErrorContext(ctx, "failed to create group", "error", err)
+		return fmt.Errorf("failed to create group: %w", err)
+	}
+	if err := addGroupMembers(ctx, tx, group.ID, group.MemberIDs); err != nil {
+		r.logger.ErrorContext(ctx, "failed to add group members", "error", err)
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	r.logger.InfoContext(ctx, "group created", "group_id", group.ID)
+	return nil
+}
+
+func (r *groupRepository) FindByID(ctx context.Context, id string) (*model.Group, error) {
+	query := `SELECT ` + groupColumns + ` FROM scim_group g WHERE g.id = $1`
+
+	group, err := scanGroup(r.db.QueryRowContext(ctx, query, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("group not found: %s: %w", id, model.ErrNotFound)
+	}
+	if err != nil {
+		r.logger.ErrorContext(ctx, "failed to find group by id", "error", err, "id", id)
+		return nil, fmt.Errorf("failed to find group by id: %w", err)
+	}
+
+	return group, nil
+}
+
+func (r *groupRepository) List(ctx context.Context, displayName string, offset, limit int) ([]*model.Group, int, error) {
+	// displayNameが空の場合は全件を対象にする
+	where := `WHERE $1 = '' OR g.display_name = $1`
+
+	var total int
+	if err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM scim_group g `+where, displayName).Scan(&total); err != nil {
+		r.logger.ErrorContext(ctx, "failed to count groups", "error", err)
+		return nil, 0, fmt.Errorf("failed to count groups: %w", err)
+	}
+
+	query := `SELECT ` + groupColumns + ` FROM scim_group g ` + where + ` ORDER BY g.created_at, g.id OFFSET $2 LIMIT $3`
+	rows, err := r.db.QueryContext(ctx, query, displayName, offset, limit)
+	if err != nil {
+		r.logger.ErrorContext(ctx, "failed to list groups", "error", err)
+		return nil, 0, fmt.Errorf("failed to list groups: %w", err)
+	}
+	defer rows.Close()
+
+	groups := []*model.Group{}
+	for rows.Next() {
+		group, err := scanGroup(rows)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to scan group: %w", err)
+		}
+		groups = append(groups, group)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("failed to iterate groups: %w", err)
+	}
+
+	return groups, total, nil
+}
+
+func (r *groupRepository) Update(ctx context.Context, group *model.Group) error {
+	query := `
+		UPDATE scim_group
+		SET display_name = $1, external_id = NULLIF($2, ''), updated_at = $3
+		WHERE id = $4
+	`
+
+	result, err := r.db.ExecContext(ctx, query, group.DisplayName, group.ExternalID, time.Now(), group.ID)
+	if err != nil {
+		r.logger.ErrorContext(ctx, "failed to update group", "error", err, "group_id", group.ID)
+		return fmt.Errorf("failed to update group: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("group not found: %s: %w", group.ID, model.ErrNotFound)
+	}
+
+	r.logger.InfoContext(ctx, "group updated", "group_id", group.ID)
+	return nil
+}
+
+func (r *groupRepository) ReplaceMembers(ctx context.Context, groupID string, userIDs []string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback() // コミット済みの場合は何もしない
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM scim_group_member WHERE group_id = $1`, groupID); err != nil {
+		r.logger.ErrorContext(ctx, "failed to clear group members", "error", err, "group_id", groupID)
+		return fmt.Errorf("failed to clear group members: %w", err)
+	}
+	if err := addGroupMembers(ctx, tx, groupID, userIDs); err != nil {
+		r.logger.ErrorContext(ctx, "failed to add group members", "error", err, "group_id", groupID)
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+func (r *groupRepository) AddMembers(ctx context.Context, groupID string, userIDs []string) error {
+	if err := addGroupMembers(ctx, r.db, groupID, userIDs); err != nil {
+		r.logger.ErrorContext(ctx, "failed to add group members", "error", err, "group_id", groupID)
+		return err
+	}
+	return nil
+}
+
+func (r *groupRepository) RemoveMembers(ctx context.Context, groupID string, userIDs []string) error {
+	query := `DELETE FROM scim_group_member WHERE group_id = $1 AND user_id = ANY($2::uuid[])`
+
+	if _, err := r.db.ExecContext(ctx, query, groupID, pq.Array(userIDs)); err != nil {
+		r.logger.ErrorContext(ctx, "failed to remove group members", "error", err, "group_id", groupID)
+		return fmt.Errorf("failed to remove group members: %w", err)
+	}
+	return nil
+}
+
+func (r *groupRepository) Delete(ctx context.Context, id string) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM scim_group WHERE id = $1`, id)
+	if err != nil {
+		r.logger.ErrorContext(ctx, "failed to delete group", "error", err, "group_id", id)
+		return fmt.Errorf("failed to delete group: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("group not found: %s: %w", id, model.ErrNotFound)
+	}
+
+	r.logger.InfoContext(ctx, "group deleted", "group_id", id)
+	return nil
+}
+
+// execer は*sql.DBと*sql.Txに共通するクエリ実行のインターフェース
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+}
+
+// addGroupMembers はグループにメンバーを追加する（追加済みのユーザーは無視する）
+func addGroupMembers(ctx context.Context, db execer, groupID string, userIDs []string) error {
+	if len(userIDs) == 0 {
+		return nil
+	}
+	query := `
+		INSERT INTO scim_group_member (group_id, user_id)
+		SELECT $1, unnest($2::uuid[])
+		ON CONFLICT DO NOTHING
+	`
+	if _, err := db.ExecContext(ctx, query, groupID, pq.Array(userIDs)); err != nil {
+		return fmt.Errorf("failed to add group members: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,53 @@
+package persistence
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/sikigasa/github-task-controller/backend/internal/domain/model"
+	"github.com/sikigasa/github-task-controller/backend/internal/domain/repository"
+)
+
+type guestUserRepository struct {
+	db     *tenantDB
+	logger *slog.Logger
+}
+
+// NewGuestUserRepository は新しいGuestUserRepositoryを作成する
+func NewGuestUserRepository(db *sql.DB, logger *slog.Logger) repository.GuestUserRepository {
+	return &guestUserRepository{
+		db:     newTenantDB(db),
+		logger: logger,
+	}
+}
+
+func (r *guestUserRepository) Create(ctx context.Context, guest *model.GuestUser) error {
+	query := `INSERT INTO guest_user (user_id, expires_at, created_at) VALUES ($1, $2, $3)`
+
+	if _, err := r.db.ExecContext(ctx, query, guest.UserID, guest.ExpiresAt, guest.CreatedAt); err != nil {
+		r.logger.ErrorContext(ctx, "failed to create guest user", "error", err)
+		return fmt.Errorf("failed to create guest user: %w", err)
+	}
+
+	return nil
+}
+
+func (r *guestUserRepository) DeleteExpired(ctx context.Context, now time.Time) (int64, error) {
+	// usersを削除するとプロジェクト・タスク・guest_userも連鎖して削除される
+	query := `DELETE FROM users WHERE id IN (SELECT user_id FROM guest_user WHERE expires_at <= $1)`
+
+	result, err := r.db.ExecContext(ctx, query, now)
+	if err != nil {
+		r.logger.ErrorContext(ctx, "failed to delete expired guest users", "error", err)
+		return 0, fmt.Errorf("failed to delete expired guest users: %w", err)
+	}
+
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	return deleted, nil
+}
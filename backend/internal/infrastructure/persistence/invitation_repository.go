@@ -0,0 +1,152 @@
+package persistence
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/sikigasa/github-task-controller/backend/internal/domain/model"
+	"github.com/sikigasa/github-task-controller/backend/internal/domain/repository"
+)
+
+// invitationColumns は招待検索時に取得するカラム（scanInvitationの引数順と一致させる）
+const invitationColumns = `id, email, invited_by, expires_at, accepted_at, created_at`
+
+type invitationRepository struct {
+	db     *tenantDB
+	logger *slog.Logger
+}
+
+// NewInvitationRepository は新しいInvitationRepositoryを作成する
+func NewInvitationRepository(db *sql.DB, logger *slog.Logger) repository.InvitationRepository {
+	return &invitationRepository{
+		db:     newTenantDB(db),
+		logger: logger,
+	}
+}
+
+// scanInvitation はinvitationColumnsの順に読み取った招待を返す
+func scanInvitation(row rowScanner) (*model.Invitation, error) {
+	var invitation model.Invitation
+	var acceptedAt sql.NullTime
+	if err := row.Scan(
+		&invitation.ID, &invitation.Email, &invitation.InvitedBy,
+		&invitation.ExpiresAt, &acceptedAt, &invitation.CreatedAt,
+	); err != nil {
+		return nil, err
+	}
+	if acceptedAt.Valid {
+		invitation.AcceptedAt = &acceptedAt.Time
+	}
+	return &invitation, nil
+}
+
+func (r *invitationRepository) Upsert(ctx context.Context, invitation *model.Invitation) error {
+	query := `
+		INSERT INTO invitation (id, email, invited_by, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (email) DO UPDATE
+		SET invited_by = EXCLUDED.invited_by, expires_at = EXCLUDED.expires_at,
+			accepted_at = NULL, created_at = EXCLUDED.created_at
+		RETURNING id
+	`
+
+	err := r.db.QueryRowContext(ctx, query,
+		invitation.ID, invitation.Email, invitation.InvitedBy, invitation.ExpiresAt, invitation.CreatedAt,
+	).Scan(&invitation.ID)
+	if err != nil {
+		r.logger.ErrorContext(ctx, "failed to upsert invitation", "error", err)
+		return fmt.Errorf("failed to upsert invitation: %w", err)
+	}
+
+	r.logger.InfoContext(ctx, "invitation saved", "invitation_id", invitation.ID)
+	return nil
+}
+
+func (r *invitationRepository) FindByID(ctx context.Context, id string) (*model.Invitation, error) {
+	query := `SELECT ` + invitationColumns + ` FROM invitation WHERE id = $1`
+
+	invitation, err := scanInvitation(r.db.QueryRowContext(ctx, query, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("invitation not found: %s: %w", id, model.ErrNotFound)
+	}
+	if err != nil {
+		r.logger.ErrorContext(ctx, "failed to find invitation by id", "error", err, "id", id)
+		return nil, fmt.Errorf("failed to find invitation by id: %w", err)
+	}
+
+	return invitation, nil
+}
+
+func (r *invitationRepository) FindByEmail(ctx context.Context, email string) (*model.Invitation, error) {
+	query := `SELECT ` + invitationColumns + ` FROM invitation WHERE email = $1`
+
+	invitation, err := scanInvitation(r.db.QueryRowContext(ctx, query, email))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("invitation not found: %s: %w", email, model.ErrNotFound)
+	}
+	if err != nil {
+		r.logger.ErrorContext(ctx, "failed to find invitation by email", "error", err)
+		return nil, fmt.Errorf("failed to find invitation by email: %w", err)
+	}
+
+	return invitation, nil
+}
+
+func (r *invitationRepository) FindByInviter(ctx context.Context, userID string) ([]*model.Invitation, error) {
+	query := `SELECT ` + invitationColumns + ` FROM invitation WHERE invited_by = $1 ORDER BY created_at DESC`
+
+	rows, err := r.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		r.logger.ErrorContext(ctx, "failed to find invitations by inviter", "error", err, "user_id", userID)
+		return nil, fmt.Errorf("failed to find invitations by inviter: %w", err)
+	}
+	defer rows.Close()
+
+	invitations := []*model.Invitation{}
+	for rows.Next() {
+		invitation, err := scanInvitation(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan invitation: %w", err)
+		}
+		invitations = append(invitations, invitation)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate invitations: %w", err)
+	}
+
+	return invitations, nil
+}
+
+func (r *invitationRepository) MarkAccepted(ctx context.Context, id string, at time.Time) error {
+	query := `UPDATE invitation SET accepted_at = $1 WHERE id = $2 AND accepted_at IS NULL`
+
+	if _, err := r.db.ExecContext(ctx, query, at, id); err != nil {
+		r.logger.ErrorContext(ctx, "failed to mark invitation accepted", "error", err, "invitation_id", id)
+		return fmt.Errorf("failed to mark invitation accepted: %w", err)
+	}
+
+	return nil
+}
+
+func (r *invitationRepository) Delete(ctx context.Context, id string) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM invitation WHERE id = $1`, id)
+	if err != nil {
+		r.logger.ErrorContext(ctx, "failed to delete invitation", "error", err, "invitation_id", id)
+		return fmt.Errorf("failed to delete invitation: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("invitation not found: %s: %w", id, model.ErrNotFound)
+	}
+
+	r.logger.InfoContext(ctx, "invitation deleted", "invitation_id", id)
+	return nil
+}
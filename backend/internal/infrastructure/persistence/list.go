@@ -0,0 +1,52 @@
+package persistence
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/sikigasa/github-task-controller/backend/internal/domain/model"
+)
+
+// orderByClause はソートキーからORDER BY句を組み立てる
+// columnsはAPI上のフィールド名とカラム名の対応表で、対応表にないキーは無視する（SQLインジェクション対策）
+// 結果の順序を安定させるため、末尾に主キーを追加する
+func orderByClause(sort []model.SortKey, columns map[string]string, defaultClause string) string {
+	parts := make([]string, 0, len(sort)+1)
+	for _, key := range sort {
+		column, ok := columns[key.Field]
+		if !ok {
+			continue
+		}
+		direction := "ASC"
+		if key.Order == model.SortDesc {
+			direction = "DESC"
+		}
+		parts = append(parts, column+" "+direction)
+	}
+
+	if len(parts) == 0 {
+		return "ORDER BY " + defaultClause
+	}
+	return "ORDER BY " + strings.Join(append(parts, "id ASC"), ", ")
+}
+
+// paginationClause はLIMIT句・OFFSET句を組み立てる（Limitが0の場合はすべて取得する）
+// 数値はintのため、プレースホルダを使わずに埋め込む
+func paginationClause(opts model.ListOptions) string {
+	clause := ""
+	if opts.Limit > 0 {
+		clause += " LIMIT " + strconv.Itoa(opts.Limit)
+	}
+	if opts.Offset > 0 {
+		clause += " OFFSET " + strconv.Itoa(opts.Offset)
+	}
+	return clause
+}
+
+// likeEscaper はLIKEのパターンで特別な意味を持つ文字をエスケープする（エスケープ文字は既定の\）
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// escapeLike は文字列をLIKEのパターン中でそのまま一致させるためにエスケープする
+func escapeLike(s string) string {
+	return likeEscaper.Replace(s)
+}
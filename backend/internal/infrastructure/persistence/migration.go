@@ -0,0 +1,228 @@
+package persistence
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"strings"
+
+	"github.com/golang-migrate/migrate/v4"
+	"github.com/golang-migrate/migrate/v4/database/postgres"
+	"github.com/golang-migrate/migrate/v4/source"
+	"github.com/golang-migrate/migrate/v4/source/iofs"
+)
+
+// legacyBaselineVersion は廃止したInitSchemaが作成していたスキーマに相当するマイグレーションのバージョン
+// schema_migrationsがなくテーブルだけが存在するデータベースはこのバージョンから続けて適用する
+const legacyBaselineVersion = 3
+
+// Migrator はマイグレーションファイル（backend/db/migrations）でデータベーススキーマを更新する
+type Migrator struct {
+	migrate *migrate.Migrate
+	source  source.Driver
+	db      *sql.DB
+	conn    *sql.Conn
+	logger  *slog.Logger
+}
+
+// NewMigrator はmigrationsのマイグレーションファイルを適用するMigratorを作成する
+// 専用の接続を1つ確保するため、使い終わったらCloseを呼び出す（dbは閉じない）
+func NewMigrator(ctx context.Context, db *sql.DB, migrations fs.FS, logger *slog.Logger) (*Migrator, error) {
+	src, err := iofs.New(migrations, ".")
+	if err != nil {
+		return nil, fmt.Errorf("failed to open migrations: %w", err)
+	}
+
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		_ = src.Close()
+		return nil, fmt.Errorf("failed to get connection for migrations: %w", err)
+	}
+
+	// 行レベルセキュリティを使う場合もすべての行を移行できるよう、この接続では制限を外す（Closeで元に戻す）
+	if _, err := conn.ExecContext(ctx, `SELECT set_config('`+bypassSetting+`', 'on', false)`); err != nil {
+		_ = conn.Close()
+		_ = src.Close()
+		return nil, fmt.Errorf("failed to bypass row level security for migrations: %w", err)
+	}
+
+	driver, err := postgres.WithConnection(ctx, conn, &postgres.Config{})
+	if err != nil {
+		_ = conn.Close()
+		_ = src.Close()
+		return nil, fmt.Errorf("failed to create migration driver: %w", err)
+	}
+
+	m, err := migrate.NewWithInstance("iofs", src, "postgres", driver)
+	if err != nil {
+		_ = driver.Close()
+		_ = src.Close()
+		return nil, fmt.Errorf("failed to create migrator: %w", err)
+	}
+	m.Log = migrateLogger{logger: logger}
+
+	return &Migrator{
+		migrate: m,
+		source:  src,
+		db:      db,
+		conn:    conn,
+		logger:  logger,
+	}, nil
+}
+
+// Close はマイグレーションファイルと専用の接続を閉じる
+// 接続プールに戻す前に行レベルセキュリティの設定を元に戻し、戻せない場合は接続を破棄する
+func (m *Migrator) Close() error {
+	discarded := false
+	if _, err := m.conn.ExecContext(context.Background(), `RESET `+bypassSetting); err != nil {
+		m.logger.Error("failed to reset row level security bypass, discarding connection", "error", err)
+		_ = m.conn.Raw(func(any) error { return driver.ErrBadConn })
+		discarded = true
+	}
+
+	srcErr, dbErr := m.migrate.Close()
+	if discarded && errors.Is(dbErr, sql.ErrConnDone) {
+		dbErr = nil
+	}
+	if srcErr != nil || dbErr != nil {
+		return fmt.Errorf("failed to close migrator: %w", errors.Join(srcErr, dbErr))
+	}
+	return nil
+}
+
+// Up は未適用のマイグレーションをすべて適用する
+// InitSchemaで作成したデータベースはlegacyBaselineVersionまで移行してから適用する
+func (m *Migrator) Up(ctx context.Context) error {
+	if err := m.baseline(ctx); err != nil {
+		return err
+	}
+	if err := m.migrate.Up(); err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		m.logger.ErrorContext(ctx, "failed to apply migrations", "error", err)
+		return fmt.Errorf("failed to apply migrations: %w", err)
+	}
+
+	version, _, err := m.Version()
+	if err != nil {
+		return err
+	}
+	m.logger.InfoContext(ctx, "database schema is up to date", "version", version)
+	return nil
+}
+
+// Steps はnが正の場合はn件のマイグレーションを適用し、負の場合は-n件を取り消す
+func (m *Migrator) Steps(ctx context.Context, n int) error {
+	if n == 0 {
+		return nil
+	}
+	if n > 0 {
+		if err := m.baseline(ctx); err != nil {
+			return err
+		}
+	}
+	if err := m.migrate.Steps(n); err != nil {
+		m.logger.ErrorContext(ctx, "failed to migrate", "error", err, "steps", n)
+		return fmt.Errorf("failed to migrate %d steps: %w", n, err)
+	}
+	return nil
+}
+
+// Force はマイグレーションを実行せずに適用済みのバージョンをversionとして記録し、失敗による不整合（dirty）の状態を解除する
+func (m *Migrator) Force(version int) error {
+	if err := m.migrate.Force(version); err != nil {
+		return fmt.Errorf("failed to force migration version %d: %w", version, err)
+	}
+	return nil
+}
+
+// Version は適用済みのバージョンと、前回のマイグレーションが失敗して不整合（dirty）の状態かどうかを返す
+// マイグレーションを1件も適用していない場合は0を返す
+func (m *Migrator) Version() (uint, bool, error) {
+	version, dirty, err := m.migrate.Version()
+	if errors.Is(err, migrate.ErrNilVersion) {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to get migration version: %w", err)
+	}
+	return version, dirty, nil
+}
+
+// Latest はマイグレーションファイルの最新のバージョンを返す
+func (m *Migrator) Latest() (uint, error) {
+	version, err := m.source.First()
+	if err != nil {
+		return 0, fmt.Errorf("failed to read migrations: %w", err)
+	}
+	for {
+		next, err := m.source.Next(version)
+		if errors.Is(err, fs.ErrNotExist) {
+			return version, nil
+		}
+		if err != nil {
+			return 0, fmt.Errorf("failed to read migrations: %w", err)
+		}
+		version = next
+	}
+}
+
+// Check はスキーマが最新のマイグレーションまで適用済みでなければエラーを返す（スキーマは変更しない）
+func (m *Migrator) Check() error {
+	version, dirty, err := m.Version()
+	if err != nil {
+		return err
+	}
+	if dirty {
+		return fmt.Errorf("migration version %d is dirty: fix the schema and run migrate force", version)
+	}
+	latest, err := m.Latest()
+	if err != nil {
+		return err
+	}
+	if version != latest {
+		return fmt.Errorf("database schema is at version %d but migrations are at %d: run migrate up", version, latest)
+	}
+	return nil
+}
+
+// baseline はマイグレーションの記録がなく既存のテーブルがある（InitSchemaで作成した）場合に
+// legacyBaselineVersionまでのマイグレーションを適用する
+// InitSchemaはuuid_generate_v7を作成していなかったため、適用済みとして記録するだけでは以降のマイグレーションが失敗する
+// 000001〜000003は既存のテーブル・カラムがあれば何もしないため、InitSchemaのスキーマにもそのまま適用できる
+func (m *Migrator) baseline(ctx context.Context) error {
+	_, _, err := m.migrate.Version()
+	if !errors.Is(err, migrate.ErrNilVersion) {
+		return nil
+	}
+
+	var exists bool
+	if err := m.db.QueryRowContext(ctx, `SELECT to_regclass('project') IS NOT NULL`).Scan(&exists); err != nil {
+		return fmt.Errorf("failed to check existing schema: %w", err)
+	}
+	if !exists {
+		return nil
+	}
+
+	m.logger.WarnContext(ctx, "existing schema without migration history, migrating to baseline", "version", legacyBaselineVersion)
+	if err := m.migrate.Migrate(legacyBaselineVersion); err != nil {
+		m.logger.ErrorContext(ctx, "failed to migrate existing schema to baseline", "error", err)
+		return fmt.Errorf("failed to migrate existing schema to baseline version %d: %w", legacyBaselineVersion, err)
+	}
+	return nil
+}
+
+// migrateLogger はgolang-migrateのログをslogに出力する
+type migrateLogger struct {
+	logger *slog.Logger
+}
+
+func (l migrateLogger) Printf(format string, v ...any) {
+	l.logger.Info("migration", "message", strings.TrimSpace(fmt.Sprintf(format, v...)))
+}
+
+func (l migrateLogger) Verbose() bool {
+	return false
+}
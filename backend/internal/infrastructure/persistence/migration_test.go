@@ -0,0 +1,224 @@
+package persistence
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"io"
+	"log/slog"
+	"net/url"
+	"os"
+	"testing"
+
+	"github.com/sikigasa/github-task-controller/backend/db/migrations"
+)
+
+// releasedInitSchema は廃止したInitSchemaが公開時点で作成していたスキーマ
+const releasedInitSchema = `
+	CREATE TABLE IF NOT EXISTS users (
+		id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+		email VARCHAR(255) UNIQUE NOT NULL,
+		name VARCHAR(255),
+		image_url TEXT,
+		created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+		updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+	);
+
+	CREATE TABLE IF NOT EXISTS github_account (
+		user_id uuid NOT NULL,
+		provider VARCHAR NOT NULL,
+		provider_account_id VARCHAR NOT NULL,
+		access_token VARCHAR,
+		refresh_token VARCHAR,
+		expires_at BIGINT,
+		created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+		updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+		CONSTRAINT github_account_pk PRIMARY KEY (provider, provider_account_id),
+		CONSTRAINT github_account_user_fk
+			FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+	);
+
+	CREATE TABLE IF NOT EXISTS google_account (
+		user_id uuid NOT NULL,
+		provider VARCHAR NOT NULL,
+		provider_account_id VARCHAR NOT NULL,
+		access_token VARCHAR,
+		refresh_token VARCHAR,
+		expires_at BIGINT,
+		created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+		updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+		CONSTRAINT google_account_pk PRIMARY KEY (provider, provider_account_id),
+		CONSTRAINT google_account_user_fk
+			FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+	);
+
+	CREATE TABLE IF NOT EXISTS project (
+		id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+		user_id uuid NOT NULL,
+		title VARCHAR NOT NULL,
+		description TEXT,
+		created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+		updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+		CONSTRAINT project_user_fk
+			FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+	);
+
+	CREATE TABLE IF NOT EXISTS task (
+		id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+		project_id uuid NOT NULL,
+		title VARCHAR(255) NOT NULL,
+		description TEXT,
+		status INT NOT NULL,
+		end_date TIMESTAMP,
+		created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+		updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+		CONSTRAINT task_project_fk
+			FOREIGN KEY (project_id) REFERENCES project(id) ON DELETE CASCADE
+	);
+
+	CREATE INDEX IF NOT EXISTS idx_users_email ON users(email);
+	CREATE INDEX IF NOT EXISTS idx_github_account_user_id ON github_account(user_id);
+	CREATE INDEX IF NOT EXISTS idx_google_account_user_id ON google_account(user_id);
+	CREATE INDEX IF NOT EXISTS idx_project_user_id ON project(user_id);
+	CREATE INDEX IF NOT EXISTS idx_task_project_id ON task(project_id);
+	CREATE INDEX IF NOT EXISTS idx_task_status ON task(status);
+
+	ALTER TABLE task ADD COLUMN IF NOT EXISTS priority INT NOT NULL DEFAULT 0;
+
+	ALTER TABLE github_account ADD COLUMN IF NOT EXISTS pat_encrypted VARCHAR;
+	ALTER TABLE project ADD COLUMN IF NOT EXISTS github_owner VARCHAR;
+	ALTER TABLE project ADD COLUMN IF NOT EXISTS github_repo VARCHAR;
+	ALTER TABLE project ADD COLUMN IF NOT EXISTS github_project_number INT;
+	ALTER TABLE task ADD COLUMN IF NOT EXISTS github_item_id VARCHAR;
+	ALTER TABLE task ADD COLUMN IF NOT EXISTS github_issue_number INT;
+	ALTER TABLE task ADD COLUMN IF NOT EXISTS github_issue_url VARCHAR;
+`
+
+// openTestDB はTEST_DATABASE_URLのデータベースに一時的なスキーマを作成し、そのスキーマを使う接続と接続文字列を返す
+// TEST_DATABASE_URLが設定されていない場合はテストをスキップする
+func openTestDB(t *testing.T) (*sql.DB, string) {
+	t.Helper()
+	databaseURL := os.Getenv("TEST_DATABASE_URL")
+	if databaseURL == "" {
+		t.Skip("TEST_DATABASE_URL is not set")
+	}
+
+	admin, err := sql.Open("postgres", databaseURL)
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	t.Cleanup(func() { admin.Close() })
+
+	suffix := make([]byte, 6)
+	if _, err := rand.Read(suffix); err != nil {
+		t.Fatalf("failed to generate schema name: %v", err)
+	}
+	schema := "test_" + hex.EncodeToString(suffix)
+	if _, err := admin.Exec(`CREATE SCHEMA ` + schema); err != nil {
+		t.Fatalf("failed to create schema: %v", err)
+	}
+	t.Cleanup(func() {
+		if _, err := admin.Exec(`DROP SCHEMA ` + schema + ` CASCADE`); err != nil {
+			t.Errorf("failed to drop schema: %v", err)
+		}
+	})
+
+	// 拡張の関数（gen_random_bytes等）はpublicから参照する
+	u, err := url.Parse(databaseURL)
+	if err != nil {
+		t.Fatalf("failed to parse TEST_DATABASE_URL: %v", err)
+	}
+	query := u.Query()
+	query.Set("search_path", schema+",public")
+	u.RawQuery = query.Encode()
+
+	db, err := sql.Open("postgres", u.String())
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db, u.String()
+}
+
+func newTestMigrator(t *testing.T, db *sql.DB) *Migrator {
+	t.Helper()
+	m, err := NewMigrator(context.Background(), db, migrations.FS(""), slog.New(slog.NewTextHandler(io.Discard, nil)))
+	if err != nil {
+		t.Fatalf("NewMigrator() error = %v", err)
+	}
+	t.Cleanup(func() { m.Close() })
+	return m
+}
+
+func TestMigratorUpFromReleasedInitSchema(t *testing.T) {
+	db, _ := openTestDB(t)
+	ctx := context.Background()
+
+	if _, err := db.ExecContext(ctx, releasedInitSchema); err != nil {
+		t.Fatalf("failed to create released schema: %v", err)
+	}
+	var taskID string
+	err := db.QueryRowContext(ctx, `
+		WITH u AS (INSERT INTO users (email) VALUES ('user@example.com') RETURNING id),
+		p AS (INSERT INTO project (user_id, title) SELECT id, 'Project' FROM u RETURNING id)
+		INSERT INTO task (project_id, title, status, priority) SELECT id, 'Task', 0, 2 FROM p RETURNING id`).Scan(&taskID)
+	if err != nil {
+		t.Fatalf("failed to insert existing data: %v", err)
+	}
+
+	m := newTestMigrator(t, db)
+	if err := m.Up(ctx); err != nil {
+		t.Fatalf("Up() error = %v", err)
+	}
+	if err := m.Check(); err != nil {
+		t.Errorf("Check() after Up() error = %v", err)
+	}
+
+	// 既存のデータを保持し、以降のマイグレーションで追加したテーブル・関数を使える
+	var title string
+	var priority int
+	if err := db.QueryRowContext(ctx, `SELECT title, priority FROM task WHERE id = $1`, taskID).Scan(&title, &priority); err != nil {
+		t.Fatalf("failed to read existing task: %v", err)
+	}
+	if title != "Task" || priority != 2 {
+		t.Errorf("task = %q, %d, want %q, %d", title, priority, "Task", 2)
+	}
+	var exists bool
+	if err := db.QueryRowContext(ctx, `SELECT to_regproc('uuid_generate_v7') IS NOT NULL AND to_regclass('task_status_event') IS NOT NULL`).Scan(&exists); err != nil {
+		t.Fatalf("failed to check schema: %v", err)
+	}
+	if !exists {
+		t.Error("uuid_generate_v7 or task_status_event does not exist after Up()")
+	}
+}
+
+func TestMigratorUpFromEmptyDatabase(t *testing.T) {
+	db, _ := openTestDB(t)
+	ctx := context.Background()
+
+	m := newTestMigrator(t, db)
+	if err := m.Up(ctx); err != nil {
+		t.Fatalf("Up() error = %v", err)
+	}
+	if err := m.Check(); err != nil {
+		t.Errorf("Check() after Up() error = %v", err)
+	}
+
+	// タイムゾーンなしで作成した日時の列も、最新のバージョンではすべてタイムゾーン付きになっている
+	var columns int
+	err := db.QueryRowContext(ctx, `
+		SELECT count(*) FROM information_schema.columns
+		WHERE table_schema = current_schema() AND data_type = 'timestamp without time zone'`).Scan(&columns)
+	if err != nil {
+		t.Fatalf("failed to check column types: %v", err)
+	}
+	if columns != 0 {
+		t.Errorf("timestamp without time zone columns = %d, want 0", columns)
+	}
+
+	// 最新のバージョンで再度実行しても何もしない
+	if err := m.Up(ctx); err != nil {
+		t.Errorf("second Up() error = %v", err)
+	}
+}
@@ -0,0 +1,214 @@
+package persistence
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/sikigasa/github-task-controller/backend/internal/domain/model"
+	"github.com/sikigasa/github-task-controller/backend/internal/domain/repository"
+)
+
+// milestoneColumns はマイルストーン検索時に取得するカラム（scanMilestoneの引数順と一致させる）
+const milestoneColumns = `id, project_id, title, description, due_date, github_milestone_number, created_at, updated_at`
+
+type milestoneRepository struct {
+	db     *tenantDB
+	logger *slog.Logger
+}
+
+// NewMilestoneRepository は新しいMilestoneRepositoryを作成する
+func NewMilestoneRepository(db *sql.DB, logger *slog.Logger) repository.MilestoneRepository {
+	return &milestoneRepository{
+		db:     newTenantDB(db),
+		logger: logger,
+	}
+}
+
+func (r *milestoneRepository) Create(ctx context.Context, milestone *model.Milestone) error {
+	query := `
+		INSERT INTO milestone (id, project_id, title, description, due_date, github_milestone_number, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`
+
+	_, err := r.db.ExecContext(ctx, query,
+		milestone.ID, milestone.ProjectID, milestone.Title, milestone.Description,
+		milestone.DueDate, milestone.GithubMilestoneNumber,
+		milestone.CreatedAt, milestone.UpdatedAt,
+	)
+	if err != nil {
+		r.logger.ErrorContext(ctx, "failed to create milestone", "error", err)
+		return fmt.Errorf("failed to create milestone: %w", err)
+	}
+
+	r.logger.InfoContext(ctx, "milestone created", "milestone_id", milestone.ID)
+	return nil
+}
+
+func (r *milestoneRepository) FindByID(ctx context.Context, id string) (*model.Milestone, error) {
+	query := `
+		SELECT ` + milestoneColumns + `
+		FROM milestone
+		WHERE id = $1
+	`
+
+	milestone, err := scanMilestone(r.db.QueryRowContext(ctx, query, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, model.ErrNotFound
+	}
+	if err != nil {
+		r.logger.ErrorContext(ctx, "failed to find milestone by id", "error", err, "id", id)
+		return nil, fmt.Errorf("failed to find milestone by id: %w", err)
+	}
+
+	return milestone, nil
+}
+
+func (r *milestoneRepository) FindByProjectID(ctx context.Context, projectID string) ([]*model.Milestone, error) {
+	query := `
+		SELECT ` + milestoneColumns + `
+		FROM milestone
+		WHERE project_id = $1
+		ORDER BY due_date ASC NULLS LAST, created_at ASC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, projectID)
+	if err != nil {
+		r.logger.ErrorContext(ctx, "failed to find milestones by project_id", "error", err, "project_id", projectID)
+		return nil, fmt.Errorf("failed to find milestones by project_id: %w", err)
+	}
+	defer rows.Close()
+
+	var milestones []*model.Milestone
+	for rows.Next() {
+		milestone, err := scanMilestone(rows)
+		if err != nil {
+			r.logger.ErrorContext(ctx, "failed to scan milestone", "error", err)
+			return nil, fmt.Errorf("failed to scan milestone: %w", err)
+		}
+		milestones = append(milestones, milestone)
+	}
+
+	if err := rows.Err(); err != nil {
+		r.logger.ErrorContext(ctx, "error iterating milestones", "error", err)
+		return nil, fmt.Errorf("error iterating milestones: %w", err)
+	}
+
+	return milestones, nil
+}
+
+func (r *milestoneRepository) Update(ctx context.Context, milestone *model.Milestone) error {
+	query := `
+		UPDATE milestone
+		SET title = $1, description = $2, due_date = $3, github_milestone_number = $4, updated_at = $5
+		WHERE id = $6
+	`
+
+	result, err := r.db.ExecContext(ctx, query,
+		milestone.Title, milestone.Description, milestone.DueDate, milestone.GithubMilestoneNumber,
+		time.Now(), milestone.ID,
+	)
+	if err != nil {
+		r.logger.ErrorContext(ctx, "failed to update milestone", "error", err, "milestone_id", milestone.ID)
+		return fmt.Errorf("failed to update milestone: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return model.ErrNotFound
+	}
+
+	r.logger.InfoContext(ctx, "milestone updated", "milestone_id", milestone.ID)
+	return nil
+}
+
+func (r *milestoneRepository) Delete(ctx context.Context, id string) error {
+	query := `DELETE FROM milestone WHERE id = $1`
+
+	result, err := r.db.ExecContext(ctx, query, id)
+	if err != nil {
+		r.logger.ErrorContext(ctx, "failed to delete milestone", "error", err, "milestone_id", id)
+		return fmt.Errorf("failed to delete milestone: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return model.ErrNotFound
+	}
+
+	r.logger.InfoContext(ctx, "milestone deleted", "milestone_id", id)
+	return nil
+}
+
+func (r *milestoneRepository) ProgressByIDs(ctx context.Context, ids []string) (map[string]model.MilestoneProgress, error) {
+	progress := make(map[string]model.MilestoneProgress, len(ids))
+	if len(ids) == 0 {
+		return progress, nil
+	}
+
+	query := `
+		SELECT milestone_id, COUNT(*), COUNT(*) FILTER (WHERE status = $2)
+		FROM task
+		WHERE milestone_id = ANY($1) AND deleted_at IS NULL
+		GROUP BY milestone_id
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, pq.Array(ids), model.TaskStatusDone)
+	if err != nil {
+		r.logger.ErrorContext(ctx, "failed to count milestone tasks", "error", err)
+		return nil, fmt.Errorf("failed to count milestone tasks: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id string
+		var taskCount, doneCount int
+		if err := rows.Scan(&id, &taskCount, &doneCount); err != nil {
+			r.logger.ErrorContext(ctx, "failed to scan milestone progress", "error", err)
+			return nil, fmt.Errorf("failed to scan milestone progress: %w", err)
+		}
+		progress[id] = model.NewMilestoneProgress(taskCount, doneCount)
+	}
+
+	if err := rows.Err(); err != nil {
+		r.logger.ErrorContext(ctx, "error iterating milestone progress", "error", err)
+		return nil, fmt.Errorf("error iterating milestone progress: %w", err)
+	}
+
+	return progress, nil
+}
+
+// scanMilestone はmilestoneColumnsの順で1行をスキャンする
+func scanMilestone(row rowScanner) (*model.Milestone, error) {
+	var milestone model.Milestone
+	var dueDate sql.NullTime
+	var githubMilestoneNumber sql.NullInt32
+	err := row.Scan(
+		&milestone.ID, &milestone.ProjectID, &milestone.Title, &milestone.Description,
+		&dueDate, &githubMilestoneNumber,
+		&milestone.CreatedAt, &milestone.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if dueDate.Valid {
+		milestone.DueDate = &dueDate.Time
+	}
+	if githubMilestoneNumber.Valid {
+		num := int(githubMilestoneNumber.Int32)
+		milestone.GithubMilestoneNumber = &num
+	}
+
+	return &milestone, nil
+}
@@ -0,0 +1,183 @@
+package persistence
+
+import (
+	"cmp"
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"slices"
+	"time"
+
+	"github.com/sikigasa/github-task-controller/backend/internal/domain/model"
+	"github.com/sikigasa/github-task-controller/backend/internal/domain/repository"
+)
+
+// outboxEventColumns はアウトボックスのイベントの検索時に取得するカラム（scanOutboxEventの引数順と一致させる）
+const outboxEventColumns = `id, seq, event_type, aggregate_type, aggregate_id, payload, status, attempts, last_error, next_attempt_at, occurred_at, published_at`
+
+type outboxRepository struct {
+	db     *tenantDB
+	logger *slog.Logger
+}
+
+// NewOutboxRepository は新しいOutboxRepositoryを作成する
+func NewOutboxRepository(db *sql.DB, logger *slog.Logger) repository.OutboxRepository {
+	return &outboxRepository{
+		db:     newTenantDB(db),
+		logger: logger,
+	}
+}
+
+func (r *outboxRepository) Append(ctx context.Context, event *model.OutboxEvent) error {
+	query := `
+		INSERT INTO outbox_event (id, event_type, aggregate_type, aggregate_id, payload, status, next_attempt_at, occurred_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING seq
+	`
+
+	err := r.db.QueryRowContext(ctx, query,
+		event.ID, event.Type, event.AggregateType, event.AggregateID, []byte(event.Payload),
+		event.Status, event.NextAttemptAt, event.OccurredAt,
+	).Scan(&event.Sequence)
+	if err != nil {
+		r.logger.ErrorContext(ctx, "failed to append outbox event", "error", err, "event_type", event.Type, "aggregate_id", event.AggregateID)
+		return fmt.Errorf("failed to append outbox event: %w", err)
+	}
+
+	return nil
+}
+
+func (r *outboxRepository) ClaimDue(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*model.OutboxEvent, error) {
+	// 複数のインスタンスで同じイベントを配信しないよう、取得と同時に次の配信時刻をlease後にずらす
+	// 同じ集約の先のイベントが配信待ち（配信中・再試行待ちを含む）の間は後のイベントを取得しない
+	query := `
+		UPDATE outbox_event
+		SET next_attempt_at = $2
+		WHERE id IN (
+			SELECT e.id FROM outbox_event e
+			WHERE e.status = 'pending' AND e.next_attempt_at <= $1
+			  AND NOT EXISTS (
+				SELECT 1 FROM outbox_event p
+				WHERE p.aggregate_type = e.aggregate_type AND p.aggregate_id = e.aggregate_id
+				  AND p.status = 'pending' AND p.seq < e.seq
+			  )
+			ORDER BY e.seq
+			LIMIT $3
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING ` + outboxEventColumns
+
+	rows, err := r.db.QueryContext(ctx, query, now, now.Add(lease), limit)
+	if err != nil {
+		r.logger.ErrorContext(ctx, "failed to claim outbox events", "error", err)
+		return nil, fmt.Errorf("failed to claim outbox events: %w", err)
+	}
+	defer rows.Close()
+
+	events := []*model.OutboxEvent{}
+	for rows.Next() {
+		event, err := scanOutboxEvent(rows)
+		if err != nil {
+			r.logger.ErrorContext(ctx, "failed to scan outbox event", "error", err)
+			return nil, fmt.Errorf("failed to scan outbox event: %w", err)
+		}
+		events = append(events, event)
+	}
+
+	if err := rows.Err(); err != nil {
+		r.logger.ErrorContext(ctx, "error iterating outbox events", "error", err)
+		return nil, fmt.Errorf("error iterating outbox events: %w", err)
+	}
+
+	// RETURNINGの順序は保証されないため発生順に並べ直す
+	slices.SortFunc(events, func(a, b *model.OutboxEvent) int {
+		return cmp.Compare(a.Sequence, b.Sequence)
+	})
+	return events, nil
+}
+
+func (r *outboxRepository) MarkPublished(ctx context.Context, id string, at time.Time) error {
+	query := `
+		UPDATE outbox_event
+		SET status = 'published', attempts = attempts + 1, last_error = NULL, published_at = $2
+		WHERE id = $1
+	`
+
+	return r.exec(ctx, "mark outbox event published", query, id, at)
+}
+
+func (r *outboxRepository) MarkFailed(ctx context.Context, id string, attempts int, lastError string, nextAttemptAt time.Time, dead bool) error {
+	status := model.OutboxEventPending
+	if dead {
+		status = model.OutboxEventDead
+	}
+	query := `
+		UPDATE outbox_event
+		SET status = $2, attempts = $3, last_error = $4, next_attempt_at = $5
+		WHERE id = $1
+	`
+
+	return r.exec(ctx, "mark outbox event failed", query, id, status, attempts, lastError, nextAttemptAt)
+}
+
+func (r *outboxRepository) DeletePublishedBefore(ctx context.Context, before time.Time) (int64, error) {
+	query := `DELETE FROM outbox_event WHERE status = 'published' AND published_at < $1`
+
+	result, err := r.db.ExecContext(ctx, query, before)
+	if err != nil {
+		r.logger.ErrorContext(ctx, "failed to delete published outbox events", "error", err)
+		return 0, fmt.Errorf("failed to delete published outbox events: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return rowsAffected, nil
+}
+
+// exec は1件のイベントを更新するクエリを実行する（該当がない場合はErrNotFound）
+func (r *outboxRepository) exec(ctx context.Context, action, query string, args ...any) error {
+	result, err := r.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		r.logger.ErrorContext(ctx, "failed to "+action, "error", err, "event_id", args[0])
+		return fmt.Errorf("failed to %s: %w", action, err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("outbox event not found: %v: %w", args[0], model.ErrNotFound)
+	}
+
+	return nil
+}
+
+// scanOutboxEvent はoutboxEventColumnsの順で1行をスキャンする
+func scanOutboxEvent(row rowScanner) (*model.OutboxEvent, error) {
+	var event model.OutboxEvent
+	var payload []byte
+	var lastError sql.NullString
+	var publishedAt sql.NullTime
+	err := row.Scan(
+		&event.ID, &event.Sequence, &event.Type, &event.AggregateType, &event.AggregateID, &payload,
+		&event.Status, &event.Attempts, &lastError, &event.NextAttemptAt, &event.OccurredAt, &publishedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	event.Payload = payload
+	if lastError.Valid {
+		event.LastError = &lastError.String
+	}
+	if publishedAt.Valid {
+		event.PublishedAt = &publishedAt.Time
+	}
+
+	return &event, nil
+}
@@ -3,6 +3,7 @@ package persistence
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"log/slog"
 	"time"
@@ -11,30 +12,38 @@ import (
 	"github.com/sikigasa/github-task-controller/backend/internal/domain/repository"
 )
 
+// projectColumns はプロジェクト検索時に取得するカラム（scanProjectの引数順と一致させる）
+const projectColumns = `id, user_id, title, description, github_owner, github_repo, github_project_number, github_repo_project, github_owner_type, estimate_unit, github_estimate_field, github_commit_starts_task, github_deletion_policy, github_item_type, version, created_at, updated_at, deleted_at`
+
 type projectRepository struct {
-	db     *sql.DB
+	db     *tenantDB
 	logger *slog.Logger
 }
 
 // NewProjectRepository は新しいProjectRepositoryを作成する
 func NewProjectRepository(db *sql.DB, logger *slog.Logger) repository.ProjectRepository {
 	return &projectRepository{
-		db:     db,
+		db:     newTenantDB(db),
 		logger: logger,
 	}
 }
 
 func (r *projectRepository) Create(ctx context.Context, project *model.Project) error {
 	query := `
-		INSERT INTO project (id, user_id, title, description, github_owner, github_repo, github_project_number, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		INSERT INTO project (id, user_id, title, description, github_owner, github_repo, github_project_number, github_repo_project, github_owner_type, estimate_unit, github_estimate_field, github_commit_starts_task, github_deletion_policy, github_item_type, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16)
+		RETURNING version
 	`
 
-	_, err := r.db.ExecContext(ctx, query,
+	err := r.db.QueryRowContext(ctx, query,
 		project.ID, project.UserID, project.Title, project.Description,
-		project.GithubOwner, project.GithubRepo, project.GithubProjectNumber,
+		project.GithubOwner, project.GithubRepo, project.GithubProjectNumber, project.GithubRepoProject, project.GithubOwnerType,
+		project.EstimateUnit, project.GithubEstimateField, project.GithubCommitStartsTask, project.GithubDeletionPolicy, project.GithubItemType,
 		project.CreatedAt, project.UpdatedAt,
-	)
+	).Scan(&project.Version)
+	if isUniqueViolation(err, "project_user_title_unique") {
+		return fmt.Errorf("project %q already exists: %w", project.Title, model.ErrConflict)
+	}
 	if err != nil {
 		r.logger.ErrorContext(ctx, "failed to create project", "error", err)
 		return fmt.Errorf("failed to create project: %w", err)
@@ -46,48 +55,36 @@ func (r *projectRepository) Create(ctx context.Context, project *model.Project)
 
 func (r *projectRepository) FindByID(ctx context.Context, id string) (*model.Project, error) {
 	query := `
-		SELECT id, user_id, title, description, github_owner, github_repo, github_project_number, created_at, updated_at
+		SELECT ` + projectColumns + `
 		FROM project
-		WHERE id = $1
+		WHERE id = $1 AND deleted_at IS NULL
 	`
 
-	var project model.Project
-	var githubOwner, githubRepo sql.NullString
-	var githubProjectNumber sql.NullInt32
-	err := r.db.QueryRowContext(ctx, query, id).Scan(
-		&project.ID, &project.UserID, &project.Title, &project.Description,
-		&githubOwner, &githubRepo, &githubProjectNumber,
-		&project.CreatedAt, &project.UpdatedAt,
-	)
-	if err == sql.ErrNoRows {
-		return nil, fmt.Errorf("project not found: %s", id)
+	project, err := scanProject(r.db.QueryRowContext(ctx, query, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, model.ErrNotFound
 	}
 	if err != nil {
 		r.logger.ErrorContext(ctx, "failed to find project by id", "error", err, "id", id)
 		return nil, fmt.Errorf("failed to find project by id: %w", err)
 	}
 
-	if githubOwner.Valid {
-		project.GithubOwner = &githubOwner.String
-	}
-	if githubRepo.Valid {
-		project.GithubRepo = &githubRepo.String
-	}
-	if githubProjectNumber.Valid {
-		num := int(githubProjectNumber.Int32)
-		project.GithubProjectNumber = &num
-	}
+	return project, nil
+}
 
-	return &project, nil
+// projectSortColumns はプロジェクト一覧でソートに使用できるフィールドとカラムの対応
+var projectSortColumns = map[string]string{
+	"title":      "title",
+	"created_at": "created_at",
+	"updated_at": "updated_at",
 }
 
-func (r *projectRepository) FindByUserID(ctx context.Context, userID string) ([]*model.Project, error) {
+func (r *projectRepository) FindByUserID(ctx context.Context, userID string, opts model.ListOptions) ([]*model.Project, error) {
 	query := `
-		SELECT id, user_id, title, description, github_owner, github_repo, github_project_number, created_at, updated_at
+		SELECT ` + projectColumns + `
 		FROM project
-		WHERE user_id = $1
-		ORDER BY created_at DESC
-	`
+		WHERE user_id = $1 AND deleted_at IS NULL
+	` + orderByClause(opts.Sort, projectSortColumns, "created_at DESC")
 
 	rows, err := r.db.QueryContext(ctx, query, userID)
 	if err != nil {
@@ -98,29 +95,45 @@ func (r *projectRepository) FindByUserID(ctx context.Context, userID string) ([]
 
 	var projects []*model.Project
 	for rows.Next() {
-		var project model.Project
-		var githubOwner, githubRepo sql.NullString
-		var githubProjectNumber sql.NullInt32
-		err := rows.Scan(
-			&project.ID, &project.UserID, &project.Title, &project.Description,
-			&githubOwner, &githubRepo, &githubProjectNumber,
-			&project.CreatedAt, &project.UpdatedAt,
-		)
+		project, err := scanProject(rows)
 		if err != nil {
 			r.logger.ErrorContext(ctx, "failed to scan project", "error", err)
 			return nil, fmt.Errorf("failed to scan project: %w", err)
 		}
-		if githubOwner.Valid {
-			project.GithubOwner = &githubOwner.String
-		}
-		if githubRepo.Valid {
-			project.GithubRepo = &githubRepo.String
-		}
-		if githubProjectNumber.Valid {
-			num := int(githubProjectNumber.Int32)
-			project.GithubProjectNumber = &num
+		projects = append(projects, project)
+	}
+
+	if err = rows.Err(); err != nil {
+		r.logger.ErrorContext(ctx, "error iterating projects", "error", err)
+		return nil, fmt.Errorf("error iterating projects: %w", err)
+	}
+
+	return projects, nil
+}
+
+func (r *projectRepository) FindGithubLinked(ctx context.Context) ([]*model.Project, error) {
+	query := `
+		SELECT ` + projectColumns + `
+		FROM project
+		WHERE github_owner IS NOT NULL AND github_repo IS NOT NULL AND github_project_number IS NOT NULL AND deleted_at IS NULL
+		ORDER BY created_at ASC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		r.logger.ErrorContext(ctx, "failed to find github linked projects", "error", err)
+		return nil, fmt.Errorf("failed to find github linked projects: %w", err)
+	}
+	defer rows.Close()
+
+	var projects []*model.Project
+	for rows.Next() {
+		project, err := scanProject(rows)
+		if err != nil {
+			r.logger.ErrorContext(ctx, "failed to scan project", "error", err)
+			return nil, fmt.Errorf("failed to scan project: %w", err)
 		}
-		projects = append(projects, &project)
+		projects = append(projects, project)
 	}
 
 	if err = rows.Err(); err != nil {
@@ -134,39 +147,138 @@ func (r *projectRepository) FindByUserID(ctx context.Context, userID string) ([]
 func (r *projectRepository) Update(ctx context.Context, project *model.Project) error {
 	query := `
 		UPDATE project
-		SET title = $1, description = $2, github_owner = $3, github_repo = $4, github_project_number = $5, updated_at = $6
-		WHERE id = $7
+		SET title = $1, description = $2, github_owner = $3, github_repo = $4, github_project_number = $5, github_repo_project = $6,
+			github_owner_type = $7, estimate_unit = $8, github_estimate_field = $9, github_commit_starts_task = $10, github_deletion_policy = $11,
+			github_item_type = $12, updated_at = $13, version = version + 1
+		WHERE id = $14 AND deleted_at IS NULL AND version = $15
+		RETURNING version
 	`
 
-	result, err := r.db.ExecContext(ctx, query,
+	err := r.db.QueryRowContext(ctx, query,
 		project.Title, project.Description,
-		project.GithubOwner, project.GithubRepo, project.GithubProjectNumber,
-		time.Now(), project.ID,
-	)
+		project.GithubOwner, project.GithubRepo, project.GithubProjectNumber, project.GithubRepoProject, project.GithubOwnerType,
+		project.EstimateUnit, project.GithubEstimateField, project.GithubCommitStartsTask, project.GithubDeletionPolicy, project.GithubItemType,
+		time.Now(), project.ID, project.Version,
+	).Scan(&project.Version)
+	if isUniqueViolation(err, "project_user_title_unique") {
+		return fmt.Errorf("project %q already exists: %w", project.Title, model.ErrConflict)
+	}
+	if errors.Is(err, sql.ErrNoRows) {
+		return r.staleOrNotFound(ctx, project.ID, project.Version)
+	}
 	if err != nil {
 		r.logger.ErrorContext(ctx, "failed to update project", "error", err, "project_id", project.ID)
 		return fmt.Errorf("failed to update project: %w", err)
 	}
 
+	r.logger.InfoContext(ctx, "project updated", "project_id", project.ID)
+	return nil
+}
+
+// staleOrNotFound は更新できなかったプロジェクトが他の更新と競合した（ゴミ箱以外にある）場合はErrStaleVersion、それ以外はErrNotFoundを返す
+func (r *projectRepository) staleOrNotFound(ctx context.Context, id string, version int) error {
+	var exists bool
+	query := `SELECT EXISTS (SELECT 1 FROM project WHERE id = $1 AND deleted_at IS NULL)`
+	if err := r.db.QueryRowContext(ctx, query, id).Scan(&exists); err != nil {
+		r.logger.ErrorContext(ctx, "failed to check project existence", "error", err, "project_id", id)
+		return fmt.Errorf("failed to check project existence: %w", err)
+	}
+	if !exists {
+		return model.ErrNotFound
+	}
+	return fmt.Errorf("project %s is not at version %d: %w", id, version, model.ErrStaleVersion)
+}
+
+func (r *projectRepository) Delete(ctx context.Context, id string, deletedAt time.Time) error {
+	query := `UPDATE project SET deleted_at = $1 WHERE id = $2 AND deleted_at IS NULL`
+
+	result, err := r.db.ExecContext(ctx, query, deletedAt, id)
+	if err != nil {
+		r.logger.ErrorContext(ctx, "failed to delete project", "error", err, "project_id", id)
+		return fmt.Errorf("failed to delete project: %w", err)
+	}
+
 	rowsAffected, err := result.RowsAffected()
 	if err != nil {
 		return fmt.Errorf("failed to get rows affected: %w", err)
 	}
 	if rowsAffected == 0 {
-		return fmt.Errorf("project not found: %s", project.ID)
+		return model.ErrNotFound
 	}
 
-	r.logger.InfoContext(ctx, "project updated", "project_id", project.ID)
+	// 元に戻す時にプロジェクトと一緒に戻せるよう、タスクにはプロジェクトと同じ削除日時を記録する
+	taskQuery := `UPDATE task SET deleted_at = $1 WHERE project_id = $2 AND deleted_at IS NULL`
+	if _, err := r.db.ExecContext(ctx, taskQuery, deletedAt, id); err != nil {
+		r.logger.ErrorContext(ctx, "failed to delete project tasks", "error", err, "project_id", id)
+		return fmt.Errorf("failed to delete project tasks: %w", err)
+	}
+
+	r.logger.InfoContext(ctx, "project moved to trash", "project_id", id)
 	return nil
 }
 
-func (r *projectRepository) Delete(ctx context.Context, id string) error {
-	query := `DELETE FROM project WHERE id = $1`
+func (r *projectRepository) FindDeletedByID(ctx context.Context, id string) (*model.Project, error) {
+	query := `
+		SELECT ` + projectColumns + `
+		FROM project
+		WHERE id = $1 AND deleted_at IS NOT NULL
+	`
+
+	project, err := scanProject(r.db.QueryRowContext(ctx, query, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, model.ErrNotFound
+	}
+	if err != nil {
+		r.logger.ErrorContext(ctx, "failed to find deleted project by id", "error", err, "id", id)
+		return nil, fmt.Errorf("failed to find deleted project by id: %w", err)
+	}
+
+	return project, nil
+}
+
+func (r *projectRepository) FindDeletedByUserID(ctx context.Context, userID string) ([]*model.Project, error) {
+	query := `
+		SELECT ` + projectColumns + `
+		FROM project
+		WHERE user_id = $1 AND deleted_at IS NOT NULL
+		ORDER BY deleted_at DESC, id ASC
+	`
 
-	result, err := r.db.ExecContext(ctx, query, id)
+	rows, err := r.db.QueryContext(ctx, query, userID)
 	if err != nil {
-		r.logger.ErrorContext(ctx, "failed to delete project", "error", err, "project_id", id)
-		return fmt.Errorf("failed to delete project: %w", err)
+		r.logger.ErrorContext(ctx, "failed to find deleted projects by user_id", "error", err, "user_id", userID)
+		return nil, fmt.Errorf("failed to find deleted projects by user_id: %w", err)
+	}
+	defer rows.Close()
+
+	var projects []*model.Project
+	for rows.Next() {
+		project, err := scanProject(rows)
+		if err != nil {
+			r.logger.ErrorContext(ctx, "failed to scan project", "error", err)
+			return nil, fmt.Errorf("failed to scan project: %w", err)
+		}
+		projects = append(projects, project)
+	}
+
+	if err = rows.Err(); err != nil {
+		r.logger.ErrorContext(ctx, "error iterating projects", "error", err)
+		return nil, fmt.Errorf("error iterating projects: %w", err)
+	}
+
+	return projects, nil
+}
+
+func (r *projectRepository) Restore(ctx context.Context, project *model.Project) error {
+	if project.DeletedAt == nil {
+		return model.ErrNotFound
+	}
+
+	query := `UPDATE project SET deleted_at = NULL, updated_at = $1, version = version + 1 WHERE id = $2 AND deleted_at IS NOT NULL`
+	result, err := r.db.ExecContext(ctx, query, time.Now(), project.ID)
+	if err != nil {
+		r.logger.ErrorContext(ctx, "failed to restore project", "error", err, "project_id", project.ID)
+		return fmt.Errorf("failed to restore project: %w", err)
 	}
 
 	rowsAffected, err := result.RowsAffected()
@@ -174,9 +286,70 @@ func (r *projectRepository) Delete(ctx context.Context, id string) error {
 		return fmt.Errorf("failed to get rows affected: %w", err)
 	}
 	if rowsAffected == 0 {
-		return fmt.Errorf("project not found: %s", id)
+		return model.ErrNotFound
 	}
 
-	r.logger.InfoContext(ctx, "project deleted", "project_id", id)
+	// 個別にゴミ箱に移したタスクはゴミ箱に残す
+	taskQuery := `UPDATE task SET deleted_at = NULL, version = version + 1 WHERE project_id = $1 AND deleted_at = $2`
+	if _, err := r.db.ExecContext(ctx, taskQuery, project.ID, *project.DeletedAt); err != nil {
+		r.logger.ErrorContext(ctx, "failed to restore project tasks", "error", err, "project_id", project.ID)
+		return fmt.Errorf("failed to restore project tasks: %w", err)
+	}
+
+	r.logger.InfoContext(ctx, "project restored", "project_id", project.ID)
 	return nil
 }
+
+func (r *projectRepository) PurgeDeleted(ctx context.Context, before time.Time) (int64, error) {
+	// タスクは外部キーのON DELETE CASCADEで一緒に削除される
+	query := `DELETE FROM project WHERE deleted_at < $1`
+
+	result, err := r.db.ExecContext(ctx, query, before)
+	if err != nil {
+		r.logger.ErrorContext(ctx, "failed to purge deleted projects", "error", err)
+		return 0, fmt.Errorf("failed to purge deleted projects: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return rowsAffected, nil
+}
+
+// scanProject はprojectColumnsの順で1行をスキャンする
+func scanProject(row rowScanner) (*model.Project, error) {
+	var project model.Project
+	var githubOwner, githubRepo, githubEstimateField sql.NullString
+	var githubProjectNumber sql.NullInt32
+	var deletedAt sql.NullTime
+	err := row.Scan(
+		&project.ID, &project.UserID, &project.Title, &project.Description,
+		&githubOwner, &githubRepo, &githubProjectNumber, &project.GithubRepoProject, &project.GithubOwnerType,
+		&project.EstimateUnit, &githubEstimateField, &project.GithubCommitStartsTask, &project.GithubDeletionPolicy, &project.GithubItemType,
+		&project.Version, &project.CreatedAt, &project.UpdatedAt, &deletedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if githubOwner.Valid {
+		project.GithubOwner = &githubOwner.String
+	}
+	if githubRepo.Valid {
+		project.GithubRepo = &githubRepo.String
+	}
+	if githubProjectNumber.Valid {
+		num := int(githubProjectNumber.Int32)
+		project.GithubProjectNumber = &num
+	}
+	if githubEstimateField.Valid {
+		project.GithubEstimateField = &githubEstimateField.String
+	}
+	if deletedAt.Valid {
+		project.DeletedAt = &deletedAt.Time
+	}
+
+	return &project, nil
+}
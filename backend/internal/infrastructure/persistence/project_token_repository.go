@@ -0,0 +1,146 @@
+package persistence
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/sikigasa/github-task-controller/backend/internal/domain/model"
+	"github.com/sikigasa/github-task-controller/backend/internal/domain/repository"
+)
+
+// projectTokenColumns はプロジェクトのAPIトークン検索時に取得するカラム（scanProjectTokenの引数順と一致させる）
+const projectTokenColumns = `id, project_id, user_id, name, scope, token_hash, expires_at, last_used_at, created_at`
+
+type projectTokenRepository struct {
+	db     *tenantDB
+	logger *slog.Logger
+}
+
+// NewProjectTokenRepository は新しいProjectTokenRepositoryを作成する
+func NewProjectTokenRepository(db *sql.DB, logger *slog.Logger) repository.ProjectTokenRepository {
+	return &projectTokenRepository{
+		db:     newTenantDB(db),
+		logger: logger,
+	}
+}
+
+func (r *projectTokenRepository) Create(ctx context.Context, token *model.ProjectToken) error {
+	query := `
+		INSERT INTO project_token (id, project_id, user_id, name, scope, token_hash, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`
+
+	_, err := r.db.ExecContext(ctx, query,
+		token.ID, token.ProjectID, token.UserID, token.Name, token.Scope, token.TokenHash,
+		token.ExpiresAt, token.CreatedAt,
+	)
+	if err != nil {
+		r.logger.ErrorContext(ctx, "failed to create project token", "error", err, "project_id", token.ProjectID)
+		return fmt.Errorf("failed to create project token: %w", err)
+	}
+
+	r.logger.InfoContext(ctx, "project token created", "token_id", token.ID, "project_id", token.ProjectID)
+	return nil
+}
+
+func (r *projectTokenRepository) FindByHash(ctx context.Context, tokenHash string) (*model.ProjectToken, error) {
+	query := `
+		SELECT ` + projectTokenColumns + `
+		FROM project_token
+		WHERE token_hash = $1
+	`
+
+	token, err := scanProjectToken(r.db.QueryRowContext(ctx, query, tokenHash))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, model.ErrNotFound
+	}
+	if err != nil {
+		r.logger.ErrorContext(ctx, "failed to find project token", "error", err)
+		return nil, fmt.Errorf("failed to find project token: %w", err)
+	}
+
+	return token, nil
+}
+
+func (r *projectTokenRepository) FindByProjectID(ctx context.Context, projectID string) ([]*model.ProjectToken, error) {
+	query := `
+		SELECT ` + projectTokenColumns + `
+		FROM project_token
+		WHERE project_id = $1
+		ORDER BY created_at ASC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, projectID)
+	if err != nil {
+		r.logger.ErrorContext(ctx, "failed to find project tokens by project_id", "error", err, "project_id", projectID)
+		return nil, fmt.Errorf("failed to find project tokens by project_id: %w", err)
+	}
+	defer rows.Close()
+
+	tokens := []*model.ProjectToken{}
+	for rows.Next() {
+		token, err := scanProjectToken(rows)
+		if err != nil {
+			r.logger.ErrorContext(ctx, "failed to scan project token", "error", err)
+			return nil, fmt.Errorf("failed to scan project token: %w", err)
+		}
+		tokens = append(tokens, token)
+	}
+
+	if err := rows.Err(); err != nil {
+		r.logger.ErrorContext(ctx, "error iterating project tokens", "error", err)
+		return nil, fmt.Errorf("error iterating project tokens: %w", err)
+	}
+
+	return tokens, nil
+}
+
+func (r *projectTokenRepository) Delete(ctx context.Context, projectID, id string) error {
+	query := `DELETE FROM project_token WHERE id = $1 AND project_id = $2`
+
+	result, err := r.db.ExecContext(ctx, query, id, projectID)
+	if err != nil {
+		r.logger.ErrorContext(ctx, "failed to delete project token", "error", err, "token_id", id)
+		return fmt.Errorf("failed to delete project token: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return model.ErrNotFound
+	}
+
+	r.logger.InfoContext(ctx, "project token deleted", "token_id", id, "project_id", projectID)
+	return nil
+}
+
+func (r *projectTokenRepository) TouchLastUsed(ctx context.Context, id string, at time.Time) error {
+	query := `UPDATE project_token SET last_used_at = $1 WHERE id = $2`
+
+	if _, err := r.db.ExecContext(ctx, query, at, id); err != nil {
+		r.logger.ErrorContext(ctx, "failed to touch project token", "error", err, "token_id", id)
+		return fmt.Errorf("failed to touch project token: %w", err)
+	}
+
+	return nil
+}
+
+// scanProjectToken はprojectTokenColumnsの順で1行をスキャンする
+func scanProjectToken(row rowScanner) (*model.ProjectToken, error) {
+	var token model.ProjectToken
+	err := row.Scan(
+		&token.ID, &token.ProjectID, &token.UserID, &token.Name, &token.Scope, &token.TokenHash,
+		&token.ExpiresAt, &token.LastUsedAt, &token.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &token, nil
+}
@@ -0,0 +1,138 @@
+package persistence
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+
+	"github.com/sikigasa/github-task-controller/backend/internal/domain/model"
+	"github.com/sikigasa/github-task-controller/backend/internal/domain/repository"
+)
+
+// pushDeviceColumns は端末の検索時に取得するカラム（scanPushDeviceの引数順と一致させる）
+const pushDeviceColumns = `id, user_id, platform, token, name, created_at, updated_at`
+
+type pushDeviceRepository struct {
+	db     *tenantDB
+	logger *slog.Logger
+}
+
+// NewPushDeviceRepository は新しいPushDeviceRepositoryを作成する
+func NewPushDeviceRepository(db *sql.DB, logger *slog.Logger) repository.PushDeviceRepository {
+	return &pushDeviceRepository{
+		db:     newTenantDB(db),
+		logger: logger,
+	}
+}
+
+func (r *pushDeviceRepository) Upsert(ctx context.Context, device *model.PushDevice) (*model.PushDevice, error) {
+	// 再登録ではIDと作成日時を変えず、updated_atを後から登録したユーザーの判定に使う
+	query := `
+		INSERT INTO push_device (id, user_id, platform, token, name, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (user_id, token) DO UPDATE SET
+			platform = EXCLUDED.platform,
+			name = EXCLUDED.name,
+			updated_at = EXCLUDED.updated_at
+		RETURNING ` + pushDeviceColumns
+
+	saved, err := scanPushDevice(r.db.QueryRowContext(ctx, query,
+		device.ID, device.UserID, device.Platform, device.Token, device.Name, device.CreatedAt, device.UpdatedAt,
+	))
+	if err != nil {
+		r.logger.ErrorContext(ctx, "failed to upsert push device", "error", err, "user_id", device.UserID)
+		return nil, fmt.Errorf("failed to upsert push device: %w", err)
+	}
+
+	r.logger.InfoContext(ctx, "push device registered", "device_id", saved.ID, "user_id", saved.UserID, "platform", saved.Platform)
+	return saved, nil
+}
+
+func (r *pushDeviceRepository) FindByUserID(ctx context.Context, userID string) ([]*model.PushDevice, error) {
+	query := `
+		SELECT ` + pushDeviceColumns + `
+		FROM push_device
+		WHERE user_id = $1
+		ORDER BY created_at ASC, id ASC
+	`
+
+	return r.query(ctx, query, userID)
+}
+
+func (r *pushDeviceRepository) FindDeliverableByUserID(ctx context.Context, userID string) ([]*model.PushDevice, error) {
+	// ログアウトせずに別のアカウントでログインし直した端末に前のユーザーの通知を送らないよう、
+	// 同じトークンを後から登録したユーザーがいる端末は除く（テナントを設定しないワーカーから呼び出す前提）
+	query := `
+		SELECT ` + pushDeviceColumns + `
+		FROM push_device d
+		WHERE d.user_id = $1
+			AND NOT EXISTS (
+				SELECT 1 FROM push_device o
+				WHERE o.token = d.token AND o.user_id <> d.user_id AND o.updated_at > d.updated_at
+			)
+		ORDER BY d.created_at ASC, d.id ASC
+	`
+
+	return r.query(ctx, query, userID)
+}
+
+func (r *pushDeviceRepository) Delete(ctx context.Context, userID, id string) error {
+	query := `DELETE FROM push_device WHERE id = $1 AND user_id = $2`
+
+	result, err := r.db.ExecContext(ctx, query, id, userID)
+	if err != nil {
+		r.logger.ErrorContext(ctx, "failed to delete push device", "error", err, "device_id", id)
+		return fmt.Errorf("failed to delete push device: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("push device not found: %s: %w", id, model.ErrNotFound)
+	}
+
+	r.logger.InfoContext(ctx, "push device deleted", "device_id", id, "user_id", userID)
+	return nil
+}
+
+// query は端末を検索するクエリを実行する
+func (r *pushDeviceRepository) query(ctx context.Context, query string, args ...any) ([]*model.PushDevice, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		r.logger.ErrorContext(ctx, "failed to find push devices", "error", err)
+		return nil, fmt.Errorf("failed to find push devices: %w", err)
+	}
+	defer rows.Close()
+
+	devices := []*model.PushDevice{}
+	for rows.Next() {
+		device, err := scanPushDevice(rows)
+		if err != nil {
+			r.logger.ErrorContext(ctx, "failed to scan push device", "error", err)
+			return nil, fmt.Errorf("failed to scan push device: %w", err)
+		}
+		devices = append(devices, device)
+	}
+
+	if err := rows.Err(); err != nil {
+		r.logger.ErrorContext(ctx, "error iterating push devices", "error", err)
+		return nil, fmt.Errorf("error iterating push devices: %w", err)
+	}
+
+	return devices, nil
+}
+
+// scanPushDevice はpushDeviceColumnsの順で1行をスキャンする
+func scanPushDevice(row rowScanner) (*model.PushDevice, error) {
+	var device model.PushDevice
+	err := row.Scan(
+		&device.ID, &device.UserID, &device.Platform, &device.Token, &device.Name, &device.CreatedAt, &device.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &device, nil
+}
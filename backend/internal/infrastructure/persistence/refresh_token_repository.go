@@ -0,0 +1,90 @@
+package persistence
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/sikigasa/github-task-controller/backend/internal/domain/model"
+	"github.com/sikigasa/github-task-controller/backend/internal/domain/repository"
+)
+
+type refreshTokenRepository struct {
+	db     *tenantDB
+	logger *slog.Logger
+}
+
+// NewRefreshTokenRepository は新しいRefreshTokenRepositoryを作成する
+func NewRefreshTokenRepository(db *sql.DB, logger *slog.Logger) repository.RefreshTokenRepository {
+	return &refreshTokenRepository{
+		db:     newTenantDB(db),
+		logger: logger,
+	}
+}
+
+func (r *refreshTokenRepository) Create(ctx context.Context, token *model.RefreshToken) error {
+	query := `
+		INSERT INTO refresh_token (id, session_id, user_id, token_hash, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`
+
+	_, err := r.db.ExecContext(ctx, query,
+		token.ID, token.SessionID, token.UserID, token.TokenHash, token.ExpiresAt, token.CreatedAt,
+	)
+	if err != nil {
+		r.logger.ErrorContext(ctx, "failed to create refresh token", "error", err, "session_id", token.SessionID)
+		return fmt.Errorf("failed to create refresh token: %w", err)
+	}
+
+	return nil
+}
+
+func (r *refreshTokenRepository) FindByHash(ctx context.Context, tokenHash string) (*model.RefreshToken, error) {
+	query := `
+		SELECT id, session_id, user_id, token_hash, expires_at, used_at, created_at
+		FROM refresh_token
+		WHERE token_hash = $1
+	`
+
+	var t model.RefreshToken
+	var usedAt sql.NullTime
+	err := r.db.QueryRowContext(ctx, query, tokenHash).Scan(
+		&t.ID, &t.SessionID, &t.UserID, &t.TokenHash, &t.ExpiresAt, &usedAt, &t.CreatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, model.ErrNotFound
+		}
+		r.logger.ErrorContext(ctx, "failed to find refresh token", "error", err)
+		return nil, fmt.Errorf("failed to find refresh token: %w", err)
+	}
+	if usedAt.Valid {
+		t.UsedAt = &usedAt.Time
+	}
+
+	return &t, nil
+}
+
+func (r *refreshTokenRepository) MarkUsed(ctx context.Context, id string, usedAt time.Time) error {
+	query := `UPDATE refresh_token SET used_at = $1 WHERE id = $2 AND used_at IS NULL`
+
+	result, err := r.db.ExecContext(ctx, query, usedAt, id)
+	if err != nil {
+		r.logger.ErrorContext(ctx, "failed to mark refresh token used", "error", err, "refresh_token_id", id)
+		return fmt.Errorf("failed to mark refresh token used: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		r.logger.ErrorContext(ctx, "failed to get rows affected", "error", err)
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return model.ErrNotFound
+	}
+
+	return nil
+}
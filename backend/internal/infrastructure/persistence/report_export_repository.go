@@ -0,0 +1,207 @@
+package persistence
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/sikigasa/github-task-controller/backend/internal/domain/model"
+	"github.com/sikigasa/github-task-controller/backend/internal/domain/repository"
+)
+
+// reportExportColumns はエクスポート検索時に取得するカラム（scanReportExportの引数順と一致させる）
+const reportExportColumns = `id, user_id, report, format, params, status, file_name, content_type, error, created_at, completed_at`
+
+type reportExportRepository struct {
+	db     *tenantDB
+	logger *slog.Logger
+}
+
+// NewReportExportRepository は新しいReportExportRepositoryを作成する
+func NewReportExportRepository(db *sql.DB, logger *slog.Logger) repository.ReportExportRepository {
+	return &reportExportRepository{
+		db:     newTenantDB(db),
+		logger: logger,
+	}
+}
+
+func (r *reportExportRepository) Create(ctx context.Context, export *model.ReportExport) error {
+	params, err := json.Marshal(export.Params)
+	if err != nil {
+		return fmt.Errorf("failed to marshal export params: %w", err)
+	}
+
+	query := `
+		INSERT INTO report_export (id, user_id, report, format, params, status, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`
+
+	_, err = r.db.ExecContext(ctx, query,
+		export.ID, export.UserID, export.Report, export.Format,
+		params, export.Status, export.CreatedAt,
+	)
+	if err != nil {
+		r.logger.ErrorContext(ctx, "failed to create report export", "error", err)
+		return fmt.Errorf("failed to create report export: %w", err)
+	}
+
+	r.logger.InfoContext(ctx, "report export created", "export_id", export.ID)
+	return nil
+}
+
+func (r *reportExportRepository) FindByID(ctx context.Context, id string) (*model.ReportExport, error) {
+	query := `
+		SELECT ` + reportExportColumns + `
+		FROM report_export
+		WHERE id = $1
+	`
+
+	export, err := scanReportExport(r.db.QueryRowContext(ctx, query, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, model.ErrNotFound
+	}
+	if err != nil {
+		r.logger.ErrorContext(ctx, "failed to find report export by id", "error", err, "id", id)
+		return nil, fmt.Errorf("failed to find report export by id: %w", err)
+	}
+
+	return export, nil
+}
+
+func (r *reportExportRepository) FindPendingIDs(ctx context.Context) ([]string, error) {
+	query := `
+		SELECT id
+		FROM report_export
+		WHERE status = $1
+		ORDER BY created_at ASC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, model.ExportStatusPending)
+	if err != nil {
+		r.logger.ErrorContext(ctx, "failed to find pending report exports", "error", err)
+		return nil, fmt.Errorf("failed to find pending report exports: %w", err)
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			r.logger.ErrorContext(ctx, "failed to scan report export id", "error", err)
+			return nil, fmt.Errorf("failed to scan report export id: %w", err)
+		}
+		ids = append(ids, id)
+	}
+
+	if err := rows.Err(); err != nil {
+		r.logger.ErrorContext(ctx, "error iterating pending report exports", "error", err)
+		return nil, fmt.Errorf("error iterating pending report exports: %w", err)
+	}
+
+	return ids, nil
+}
+
+func (r *reportExportRepository) FindContent(ctx context.Context, id string) ([]byte, error) {
+	query := `SELECT content FROM report_export WHERE id = $1 AND content IS NOT NULL`
+
+	var content []byte
+	err := r.db.QueryRowContext(ctx, query, id).Scan(&content)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, model.ErrNotFound
+	}
+	if err != nil {
+		r.logger.ErrorContext(ctx, "failed to find report export content", "error", err, "id", id)
+		return nil, fmt.Errorf("failed to find report export content: %w", err)
+	}
+
+	return content, nil
+}
+
+func (r *reportExportRepository) Complete(ctx context.Context, id, fileName, contentType string, completedAt time.Time) error {
+	query := `
+		UPDATE report_export
+		SET status = $1, file_name = $2, content_type = $3, completed_at = $4
+		WHERE id = $5
+	`
+
+	return r.update(ctx, query, model.ExportStatusCompleted, fileName, contentType, completedAt, id)
+}
+
+func (r *reportExportRepository) Fail(ctx context.Context, id, message string, completedAt time.Time) error {
+	query := `
+		UPDATE report_export
+		SET status = $1, error = $2, completed_at = $3
+		WHERE id = $4
+	`
+
+	return r.update(ctx, query, model.ExportStatusFailed, message, completedAt, id)
+}
+
+func (r *reportExportRepository) DeleteFinishedBefore(ctx context.Context, before time.Time) (int64, error) {
+	query := `DELETE FROM report_export WHERE status <> $1 AND completed_at < $2`
+
+	result, err := r.db.ExecContext(ctx, query, model.ExportStatusPending, before)
+	if err != nil {
+		r.logger.ErrorContext(ctx, "failed to delete finished report exports", "error", err)
+		return 0, fmt.Errorf("failed to delete finished report exports: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return rowsAffected, nil
+}
+
+// update はエクスポートの状態を更新する
+func (r *reportExportRepository) update(ctx context.Context, query string, args ...any) error {
+	result, err := r.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		r.logger.ErrorContext(ctx, "failed to update report export", "error", err)
+		return fmt.Errorf("failed to update report export: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return model.ErrNotFound
+	}
+
+	return nil
+}
+
+// scanReportExport はreportExportColumnsの順で1行をスキャンする
+func scanReportExport(row rowScanner) (*model.ReportExport, error) {
+	var export model.ReportExport
+	var params []byte
+	var fileName, contentType, exportError sql.NullString
+	var completedAt sql.NullTime
+	err := row.Scan(
+		&export.ID, &export.UserID, &export.Report, &export.Format, &params, &export.Status,
+		&fileName, &contentType, &exportError, &export.CreatedAt, &completedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(params, &export.Params); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal export params: %w", err)
+	}
+	export.FileName = fileName.String
+	export.ContentType = contentType.String
+	if exportError.Valid {
+		export.Error = &exportError.String
+	}
+	if completedAt.Valid {
+		export.CompletedAt = &completedAt.Time
+	}
+
+	return &export, nil
+}
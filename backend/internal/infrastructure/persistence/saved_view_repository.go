@@ -0,0 +1,154 @@
+package persistence
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/sikigasa/github-task-controller/backend/internal/domain/model"
+	"github.com/sikigasa/github-task-controller/backend/internal/domain/repository"
+)
+
+// savedViewColumns は保存済みビュー検索時に取得するカラム（scanSavedViewの引数順と一致させる）
+const savedViewColumns = `id, project_id, user_id, name, filter, sort_keys, created_at, updated_at`
+
+type savedViewRepository struct {
+	db     *tenantDB
+	logger *slog.Logger
+}
+
+// NewSavedViewRepository は新しいSavedViewRepositoryを作成する
+func NewSavedViewRepository(db *sql.DB, logger *slog.Logger) repository.SavedViewRepository {
+	return &savedViewRepository{
+		db:     newTenantDB(db),
+		logger: logger,
+	}
+}
+
+func (r *savedViewRepository) Create(ctx context.Context, view *model.SavedView) error {
+	filter, err := json.Marshal(view.Filter)
+	if err != nil {
+		return fmt.Errorf("failed to marshal view filter: %w", err)
+	}
+	sortKeys, err := json.Marshal(view.Sort)
+	if err != nil {
+		return fmt.Errorf("failed to marshal view sort: %w", err)
+	}
+
+	query := `
+		INSERT INTO saved_view (id, project_id, user_id, name, filter, sort_keys, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`
+
+	_, err = r.db.ExecContext(ctx, query,
+		view.ID, view.ProjectID, view.UserID, view.Name,
+		filter, sortKeys,
+		view.CreatedAt, view.UpdatedAt,
+	)
+	if err != nil {
+		r.logger.ErrorContext(ctx, "failed to create saved view", "error", err)
+		return fmt.Errorf("failed to create saved view: %w", err)
+	}
+
+	r.logger.InfoContext(ctx, "saved view created", "view_id", view.ID)
+	return nil
+}
+
+func (r *savedViewRepository) FindByID(ctx context.Context, id string) (*model.SavedView, error) {
+	query := `
+		SELECT ` + savedViewColumns + `
+		FROM saved_view
+		WHERE id = $1
+	`
+
+	view, err := scanSavedView(r.db.QueryRowContext(ctx, query, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, model.ErrNotFound
+	}
+	if err != nil {
+		r.logger.ErrorContext(ctx, "failed to find saved view by id", "error", err, "id", id)
+		return nil, fmt.Errorf("failed to find saved view by id: %w", err)
+	}
+
+	return view, nil
+}
+
+func (r *savedViewRepository) FindByProjectID(ctx context.Context, projectID, userID string) ([]*model.SavedView, error) {
+	query := `
+		SELECT ` + savedViewColumns + `
+		FROM saved_view
+		WHERE project_id = $1 AND user_id = $2
+		ORDER BY created_at ASC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, projectID, userID)
+	if err != nil {
+		r.logger.ErrorContext(ctx, "failed to find saved views by project_id", "error", err, "project_id", projectID)
+		return nil, fmt.Errorf("failed to find saved views by project_id: %w", err)
+	}
+	defer rows.Close()
+
+	var views []*model.SavedView
+	for rows.Next() {
+		view, err := scanSavedView(rows)
+		if err != nil {
+			r.logger.ErrorContext(ctx, "failed to scan saved view", "error", err)
+			return nil, fmt.Errorf("failed to scan saved view: %w", err)
+		}
+		views = append(views, view)
+	}
+
+	if err := rows.Err(); err != nil {
+		r.logger.ErrorContext(ctx, "error iterating saved views", "error", err)
+		return nil, fmt.Errorf("error iterating saved views: %w", err)
+	}
+
+	return views, nil
+}
+
+func (r *savedViewRepository) Delete(ctx context.Context, id string) error {
+	query := `DELETE FROM saved_view WHERE id = $1`
+
+	result, err := r.db.ExecContext(ctx, query, id)
+	if err != nil {
+		r.logger.ErrorContext(ctx, "failed to delete saved view", "error", err, "view_id", id)
+		return fmt.Errorf("failed to delete saved view: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return model.ErrNotFound
+	}
+
+	r.logger.InfoContext(ctx, "saved view deleted", "view_id", id)
+	return nil
+}
+
+// scanSavedView はsavedViewColumnsの順で1行をスキャンする
+func scanSavedView(row rowScanner) (*model.SavedView, error) {
+	var view model.SavedView
+	var filter, sortKeys []byte
+	err := row.Scan(
+		&view.ID, &view.ProjectID, &view.UserID, &view.Name,
+		&filter, &sortKeys,
+		&view.CreatedAt, &view.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(filter, &view.Filter); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal view filter: %w", err)
+	}
+	if err := json.Unmarshal(sortKeys, &view.Sort); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal view sort: %w", err)
+	}
+
+	return &view, nil
+}
@@ -0,0 +1,145 @@
+package persistence
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/sikigasa/github-task-controller/backend/internal/infrastructure/crypto"
+)
+
+// secretTable は暗号化して保存するカラムを持つテーブル
+type secretTable struct {
+	name string
+	// keys は行を特定するカラム（主キー）
+	keys    []string
+	secrets []string
+}
+
+// secretTables は外部サービスのトークンを保存するテーブルとそのカラム
+var secretTables = []secretTable{
+	{name: "google_account", keys: []string{"provider", "provider_account_id"}, secrets: []string{"access_token", "refresh_token"}},
+	{name: "github_account", keys: []string{"provider", "provider_account_id"}, secrets: []string{"access_token", "refresh_token", "pat_encrypted"}},
+	{name: "apple_account", keys: []string{"provider", "provider_account_id"}, secrets: []string{"access_token", "refresh_token"}},
+	{name: "microsoft_account", keys: []string{"provider", "provider_account_id"}, secrets: []string{"access_token", "refresh_token"}},
+	{name: "account_merge", keys: []string{"id"}, secrets: []string{"access_token", "refresh_token"}},
+}
+
+// encryptTokens はアクセストークンとリフレッシュトークンを暗号化した値を返す
+func encryptTokens(c *crypto.Cipher, accessToken, refreshToken string) (string, string, error) {
+	encryptedAccess, err := c.Encrypt(accessToken)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to encrypt access token: %w", err)
+	}
+	encryptedRefresh, err := c.Encrypt(refreshToken)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to encrypt refresh token: %w", err)
+	}
+	return encryptedAccess, encryptedRefresh, nil
+}
+
+// decryptTokens はアクセストークンとリフレッシュトークンを復号して置き換える
+func decryptTokens(c *crypto.Cipher, accessToken, refreshToken *string) error {
+	var err error
+	if *accessToken, err = c.Decrypt(*accessToken); err != nil {
+		return fmt.Errorf("failed to decrypt access token: %w", err)
+	}
+	if *refreshToken, err = c.Decrypt(*refreshToken); err != nil {
+		return fmt.Errorf("failed to decrypt refresh token: %w", err)
+	}
+	return nil
+}
+
+// ReencryptSecrets はトークンを保存するテーブルのうち、平文のまま（暗号化の導入前に保存された）か
+// プライマリ以外のキーで暗号化された値をプライマリキーで暗号化し直す
+// 起動時に実行し、キーのローテーション後に古いキーを設定から外せるようにする
+// 複数のインスタンスが同時に実行しても、読み取った時点から変更された行は書き換えない
+func ReencryptSecrets(ctx context.Context, db *sql.DB, c *crypto.Cipher, logger *slog.Logger) error {
+	for _, table := range secretTables {
+		count, err := reencryptTable(ctx, db, c, table)
+		if err != nil {
+			return fmt.Errorf("failed to re-encrypt %s: %w", table.name, err)
+		}
+		if count > 0 {
+			logger.InfoContext(ctx, "secrets re-encrypted", "table", table.name, "rows", count)
+		}
+	}
+	return nil
+}
+
+// reencryptTable は1つのテーブルの値を暗号化し直し、書き換えた行数を返す
+func reencryptTable(ctx context.Context, db *sql.DB, c *crypto.Cipher, table secretTable) (int, error) {
+	columns := append(append([]string{}, table.keys...), table.secrets...)
+	rows, err := db.QueryContext(ctx, `SELECT `+strings.Join(columns, ", ")+` FROM `+table.name)
+	if err != nil {
+		return 0, err
+	}
+
+	var pending [][]sql.NullString
+	for rows.Next() {
+		values := make([]sql.NullString, len(columns))
+		dest := make([]any, len(columns))
+		for i := range values {
+			dest[i] = &values[i]
+		}
+		if err := rows.Scan(dest...); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		for _, value := range values[len(table.keys):] {
+			if value.Valid && c.ReencryptNeeded(value.String) {
+				pending = append(pending, values)
+				break
+			}
+		}
+	}
+	if err := rows.Err(); err != nil {
+		rows.Close()
+		return 0, err
+	}
+	rows.Close()
+
+	// SET句の後にWHERE句で主キーと読み取った時点の値を照合する
+	var set, where []string
+	for i, column := range table.secrets {
+		set = append(set, fmt.Sprintf("%s = $%d", column, i+1))
+	}
+	for i, column := range columns {
+		where = append(where, fmt.Sprintf("%s IS NOT DISTINCT FROM $%d", column, len(table.secrets)+i+1))
+	}
+	query := `UPDATE ` + table.name + ` SET ` + strings.Join(set, ", ") + ` WHERE ` + strings.Join(where, " AND ")
+
+	count := 0
+	for _, values := range pending {
+		args := make([]any, 0, len(table.secrets)+len(columns))
+		for _, value := range values[len(table.keys):] {
+			if !value.Valid || !c.ReencryptNeeded(value.String) {
+				args = append(args, value)
+				continue
+			}
+			plaintext, err := c.Decrypt(value.String)
+			if err != nil {
+				return count, err
+			}
+			encrypted, err := c.Encrypt(plaintext)
+			if err != nil {
+				return count, err
+			}
+			args = append(args, encrypted)
+		}
+		for _, value := range values {
+			args = append(args, value)
+		}
+
+		result, err := db.ExecContext(ctx, query, args...)
+		if err != nil {
+			return count, err
+		}
+		if n, err := result.RowsAffected(); err == nil && n > 0 {
+			count++
+		}
+	}
+	return count, nil
+}
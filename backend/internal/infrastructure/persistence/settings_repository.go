@@ -0,0 +1,226 @@
+package persistence
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/sikigasa/github-task-controller/backend/internal/domain/model"
+	"github.com/sikigasa/github-task-controller/backend/internal/domain/repository"
+)
+
+// settingsColumns は設定検索時に取得するカラム（scanSettingsの引数順と一致させる）
+const settingsColumns = `user_id, default_task_status, week_start_day, timezone, default_github_owner, default_labels, weekly_digest, push_status_changes, push_reminders,
+	email_reminders, webhook_reminders, reminder_days_before, last_digest_sent_at, github_import_project_id, last_github_import_at, updated_at`
+
+type settingsRepository struct {
+	db     *tenantDB
+	logger *slog.Logger
+}
+
+// NewSettingsRepository は新しいSettingsRepositoryを作成する
+func NewSettingsRepository(db *sql.DB, logger *slog.Logger) repository.SettingsRepository {
+	return &settingsRepository{
+		db:     newTenantDB(db),
+		logger: logger,
+	}
+}
+
+func (r *settingsRepository) FindByUserID(ctx context.Context, userID string) (*model.Settings, error) {
+	query := `
+		SELECT ` + settingsColumns + `
+		FROM user_settings
+		WHERE user_id = $1
+	`
+
+	settings, err := scanSettings(r.db.QueryRowContext(ctx, query, userID))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, model.ErrNotFound
+	}
+	if err != nil {
+		r.logger.ErrorContext(ctx, "failed to find settings by user_id", "error", err, "user_id", userID)
+		return nil, fmt.Errorf("failed to find settings by user_id: %w", err)
+	}
+
+	return settings, nil
+}
+
+func (r *settingsRepository) FindDigestSubscribers(ctx context.Context) ([]*model.Settings, error) {
+	query := `
+		SELECT ` + settingsColumns + `
+		FROM user_settings
+		WHERE weekly_digest
+	`
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		r.logger.ErrorContext(ctx, "failed to find digest subscribers", "error", err)
+		return nil, fmt.Errorf("failed to find digest subscribers: %w", err)
+	}
+	defer rows.Close()
+
+	var subscribers []*model.Settings
+	for rows.Next() {
+		settings, err := scanSettings(rows)
+		if err != nil {
+			r.logger.ErrorContext(ctx, "failed to scan settings", "error", err)
+			return nil, fmt.Errorf("failed to scan settings: %w", err)
+		}
+		subscribers = append(subscribers, settings)
+	}
+
+	if err := rows.Err(); err != nil {
+		r.logger.ErrorContext(ctx, "error iterating digest subscribers", "error", err)
+		return nil, fmt.Errorf("error iterating digest subscribers: %w", err)
+	}
+
+	return subscribers, nil
+}
+
+func (r *settingsRepository) MarkDigestSent(ctx context.Context, userID string, sentAt time.Time) error {
+	query := `UPDATE user_settings SET last_digest_sent_at = $1 WHERE user_id = $2`
+
+	result, err := r.db.ExecContext(ctx, query, sentAt, userID)
+	if err != nil {
+		r.logger.ErrorContext(ctx, "failed to mark digest sent", "error", err, "user_id", userID)
+		return fmt.Errorf("failed to mark digest sent: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return model.ErrNotFound
+	}
+
+	return nil
+}
+
+func (r *settingsRepository) FindGithubImportSubscribers(ctx context.Context) ([]*model.Settings, error) {
+	query := `
+		SELECT ` + settingsColumns + `
+		FROM user_settings
+		WHERE github_import_project_id IS NOT NULL
+	`
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		r.logger.ErrorContext(ctx, "failed to find github import subscribers", "error", err)
+		return nil, fmt.Errorf("failed to find github import subscribers: %w", err)
+	}
+	defer rows.Close()
+
+	var subscribers []*model.Settings
+	for rows.Next() {
+		settings, err := scanSettings(rows)
+		if err != nil {
+			r.logger.ErrorContext(ctx, "failed to scan settings", "error", err)
+			return nil, fmt.Errorf("failed to scan settings: %w", err)
+		}
+		subscribers = append(subscribers, settings)
+	}
+
+	if err := rows.Err(); err != nil {
+		r.logger.ErrorContext(ctx, "error iterating github import subscribers", "error", err)
+		return nil, fmt.Errorf("error iterating github import subscribers: %w", err)
+	}
+
+	return subscribers, nil
+}
+
+func (r *settingsRepository) MarkGithubImported(ctx context.Context, userID string, importedAt time.Time) error {
+	query := `UPDATE user_settings SET last_github_import_at = $1 WHERE user_id = $2`
+
+	result, err := r.db.ExecContext(ctx, query, importedAt, userID)
+	if err != nil {
+		r.logger.ErrorContext(ctx, "failed to mark github imported", "error", err, "user_id", userID)
+		return fmt.Errorf("failed to mark github imported: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return model.ErrNotFound
+	}
+
+	return nil
+}
+
+func (r *settingsRepository) Upsert(ctx context.Context, settings *model.Settings) error {
+	query := `
+		INSERT INTO user_settings (user_id, default_task_status, week_start_day, timezone, default_github_owner, default_labels, weekly_digest,
+			push_status_changes, push_reminders, email_reminders, webhook_reminders, reminder_days_before, github_import_project_id, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
+		ON CONFLICT (user_id) DO UPDATE SET
+			default_task_status = EXCLUDED.default_task_status,
+			week_start_day = EXCLUDED.week_start_day,
+			timezone = EXCLUDED.timezone,
+			default_github_owner = EXCLUDED.default_github_owner,
+			default_labels = EXCLUDED.default_labels,
+			weekly_digest = EXCLUDED.weekly_digest,
+			push_status_changes = EXCLUDED.push_status_changes,
+			push_reminders = EXCLUDED.push_reminders,
+			email_reminders = EXCLUDED.email_reminders,
+			webhook_reminders = EXCLUDED.webhook_reminders,
+			reminder_days_before = EXCLUDED.reminder_days_before,
+			github_import_project_id = EXCLUDED.github_import_project_id,
+			updated_at = EXCLUDED.updated_at
+	`
+
+	_, err := r.db.ExecContext(ctx, query,
+		settings.UserID, settings.DefaultTaskStatus, settings.WeekStartDay, settings.Timezone,
+		settings.DefaultGithubOwner, pq.Array(settings.DefaultLabels), settings.WeeklyDigest,
+		settings.PushStatusChanges, settings.PushReminders, settings.EmailReminders, settings.WebhookReminders, settings.ReminderDaysBefore,
+		settings.GithubImportProjectID, settings.UpdatedAt,
+	)
+	if err != nil {
+		r.logger.ErrorContext(ctx, "failed to upsert settings", "error", err, "user_id", settings.UserID)
+		return fmt.Errorf("failed to upsert settings: %w", err)
+	}
+
+	r.logger.InfoContext(ctx, "settings saved", "user_id", settings.UserID)
+	return nil
+}
+
+// scanSettings はsettingsColumnsの順で1行をスキャンする
+func scanSettings(row rowScanner) (*model.Settings, error) {
+	var s model.Settings
+	var defaultGithubOwner sql.NullString
+	var defaultLabels pq.StringArray
+	var lastDigestSentAt, lastGithubImportAt sql.NullTime
+	var githubImportProjectID sql.NullString
+	err := row.Scan(
+		&s.UserID, &s.DefaultTaskStatus, &s.WeekStartDay, &s.Timezone,
+		&defaultGithubOwner, &defaultLabels, &s.WeeklyDigest, &s.PushStatusChanges, &s.PushReminders,
+		&s.EmailReminders, &s.WebhookReminders, &s.ReminderDaysBefore, &lastDigestSentAt, &githubImportProjectID, &lastGithubImportAt, &s.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if defaultGithubOwner.Valid {
+		s.DefaultGithubOwner = &defaultGithubOwner.String
+	}
+	s.DefaultLabels = []string(defaultLabels)
+	if s.DefaultLabels == nil {
+		s.DefaultLabels = []string{}
+	}
+	if lastDigestSentAt.Valid {
+		s.LastDigestSentAt = &lastDigestSentAt.Time
+	}
+	if githubImportProjectID.Valid {
+		s.GithubImportProjectID = &githubImportProjectID.String
+	}
+	if lastGithubImportAt.Valid {
+		s.LastGithubImportAt = &lastGithubImportAt.Time
+	}
+
+	return &s, nil
+}
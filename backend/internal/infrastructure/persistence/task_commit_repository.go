@@ -0,0 +1,96 @@
+package persistence
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/sikigasa/github-task-controller/backend/internal/domain/model"
+	"github.com/sikigasa/github-task-controller/backend/internal/domain/repository"
+)
+
+type taskCommitRepository struct {
+	db     *tenantDB
+	logger *slog.Logger
+}
+
+// NewTaskCommitRepository は新しいTaskCommitRepositoryを作成する
+func NewTaskCommitRepository(db *sql.DB, logger *slog.Logger) repository.TaskCommitRepository {
+	return &taskCommitRepository{
+		db:     newTenantDB(db),
+		logger: logger,
+	}
+}
+
+func (r *taskCommitRepository) Create(ctx context.Context, commit *model.TaskCommit) (bool, error) {
+	query := `
+		INSERT INTO task_commit (task_id, project_id, sha, message, url, author, committed_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		ON CONFLICT (task_id, sha) DO NOTHING
+	`
+
+	result, err := r.db.ExecContext(ctx, query,
+		commit.TaskID, commit.ProjectID, commit.SHA, commit.Message, commit.URL, commit.Author, commit.CommittedAt, commit.CreatedAt,
+	)
+	if err != nil {
+		r.logger.ErrorContext(ctx, "failed to create task commit", "error", err, "task_id", commit.TaskID, "sha", commit.SHA)
+		return false, fmt.Errorf("failed to create task commit: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return rowsAffected > 0, nil
+}
+
+func (r *taskCommitRepository) FindByTaskID(ctx context.Context, taskID string) ([]*model.TaskCommit, error) {
+	query := `
+		SELECT task_id, project_id, sha, message, url, author, committed_at, created_at
+		FROM task_commit
+		WHERE task_id = $1
+		ORDER BY committed_at DESC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, taskID)
+	if err != nil {
+		r.logger.ErrorContext(ctx, "failed to find task commits", "error", err, "task_id", taskID)
+		return nil, fmt.Errorf("failed to find task commits: %w", err)
+	}
+	defer rows.Close()
+
+	commits := []*model.TaskCommit{}
+	for rows.Next() {
+		var c model.TaskCommit
+		if err := rows.Scan(&c.TaskID, &c.ProjectID, &c.SHA, &c.Message, &c.URL, &c.Author, &c.CommittedAt, &c.CreatedAt); err != nil {
+			r.logger.ErrorContext(ctx, "failed to scan task commit", "error", err)
+			return nil, fmt.Errorf("failed to scan task commit: %w", err)
+		}
+		commits = append(commits, &c)
+	}
+
+	if err := rows.Err(); err != nil {
+		r.logger.ErrorContext(ctx, "error iterating task commits", "error", err)
+		return nil, fmt.Errorf("error iterating task commits: %w", err)
+	}
+
+	return commits, nil
+}
+
+func (r *taskCommitRepository) LatestCommittedAt(ctx context.Context, projectID string) (*time.Time, error) {
+	query := `SELECT MAX(committed_at) FROM task_commit WHERE project_id = $1`
+
+	var latest sql.NullTime
+	if err := r.db.QueryRowContext(ctx, query, projectID).Scan(&latest); err != nil {
+		r.logger.ErrorContext(ctx, "failed to find latest task commit", "error", err, "project_id", projectID)
+		return nil, fmt.Errorf("failed to find latest task commit: %w", err)
+	}
+
+	if !latest.Valid {
+		return nil, nil
+	}
+	return &latest.Time, nil
+}
@@ -0,0 +1,92 @@
+package persistence
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+
+	"github.com/sikigasa/github-task-controller/backend/internal/domain/model"
+	"github.com/sikigasa/github-task-controller/backend/internal/domain/repository"
+)
+
+type taskDependencyRepository struct {
+	db     *tenantDB
+	logger *slog.Logger
+}
+
+// NewTaskDependencyRepository は新しいTaskDependencyRepositoryを作成する
+func NewTaskDependencyRepository(db *sql.DB, logger *slog.Logger) repository.TaskDependencyRepository {
+	return &taskDependencyRepository{
+		db:     newTenantDB(db),
+		logger: logger,
+	}
+}
+
+func (r *taskDependencyRepository) Create(ctx context.Context, dep *model.TaskDependency) error {
+	query := `
+		INSERT INTO task_dependency (task_id, depends_on_task_id, project_id, created_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (task_id, depends_on_task_id) DO NOTHING
+	`
+
+	_, err := r.db.ExecContext(ctx, query, dep.TaskID, dep.DependsOnTaskID, dep.ProjectID, dep.CreatedAt)
+	if err != nil {
+		r.logger.ErrorContext(ctx, "failed to create task dependency", "error", err, "task_id", dep.TaskID)
+		return fmt.Errorf("failed to create task dependency: %w", err)
+	}
+
+	return nil
+}
+
+func (r *taskDependencyRepository) Delete(ctx context.Context, taskID, dependsOnTaskID string) error {
+	query := `DELETE FROM task_dependency WHERE task_id = $1 AND depends_on_task_id = $2`
+
+	result, err := r.db.ExecContext(ctx, query, taskID, dependsOnTaskID)
+	if err != nil {
+		r.logger.ErrorContext(ctx, "failed to delete task dependency", "error", err, "task_id", taskID)
+		return fmt.Errorf("failed to delete task dependency: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return model.ErrNotFound
+	}
+
+	return nil
+}
+
+func (r *taskDependencyRepository) FindByProjectID(ctx context.Context, projectID string) ([]*model.TaskDependency, error) {
+	query := `
+		SELECT task_id, depends_on_task_id, project_id, created_at
+		FROM task_dependency
+		WHERE project_id = $1
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, projectID)
+	if err != nil {
+		r.logger.ErrorContext(ctx, "failed to find task dependencies", "error", err, "project_id", projectID)
+		return nil, fmt.Errorf("failed to find task dependencies: %w", err)
+	}
+	defer rows.Close()
+
+	var deps []*model.TaskDependency
+	for rows.Next() {
+		var dep model.TaskDependency
+		if err := rows.Scan(&dep.TaskID, &dep.DependsOnTaskID, &dep.ProjectID, &dep.CreatedAt); err != nil {
+			r.logger.ErrorContext(ctx, "failed to scan task dependency", "error", err)
+			return nil, fmt.Errorf("failed to scan task dependency: %w", err)
+		}
+		deps = append(deps, &dep)
+	}
+
+	if err := rows.Err(); err != nil {
+		r.logger.ErrorContext(ctx, "error iterating task dependencies", "error", err)
+		return nil, fmt.Errorf("error iterating task dependencies: %w", err)
+	}
+
+	return deps, nil
+}
@@ -0,0 +1,90 @@
+package persistence
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+
+	"github.com/sikigasa/github-task-controller/backend/internal/domain/model"
+	"github.com/sikigasa/github-task-controller/backend/internal/domain/repository"
+)
+
+type taskPullRequestRepository struct {
+	db     *tenantDB
+	logger *slog.Logger
+}
+
+// NewTaskPullRequestRepository は新しいTaskPullRequestRepositoryを作成する
+func NewTaskPullRequestRepository(db *sql.DB, logger *slog.Logger) repository.TaskPullRequestRepository {
+	return &taskPullRequestRepository{
+		db:     newTenantDB(db),
+		logger: logger,
+	}
+}
+
+func (r *taskPullRequestRepository) ReplaceForTask(ctx context.Context, taskID string, pullRequests []*model.TaskPullRequest) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback() // コミット済みの場合は何もしない
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM task_pull_request WHERE task_id = $1`, taskID); err != nil {
+		r.logger.ErrorContext(ctx, "failed to delete task pull requests", "error", err, "task_id", taskID)
+		return fmt.Errorf("failed to delete task pull requests: %w", err)
+	}
+
+	query := `
+		INSERT INTO task_pull_request (task_id, number, title, url, state, head_ref, link_type, merged_at, updated_at, checks_status)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		ON CONFLICT (task_id, url) DO NOTHING
+	`
+	for _, pr := range pullRequests {
+		if _, err := tx.ExecContext(ctx, query,
+			taskID, pr.Number, pr.Title, pr.URL, pr.State, pr.HeadRef, pr.LinkType, pr.MergedAt, pr.UpdatedAt, pr.ChecksStatus,
+		); err != nil {
+			r.logger.ErrorContext(ctx, "failed to create task pull request", "error", err, "task_id", taskID, "url", pr.URL)
+			return fmt.Errorf("failed to create task pull request: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+func (r *taskPullRequestRepository) FindByTaskID(ctx context.Context, taskID string) ([]*model.TaskPullRequest, error) {
+	query := `
+		SELECT task_id, number, title, url, state, head_ref, link_type, merged_at, updated_at, checks_status
+		FROM task_pull_request
+		WHERE task_id = $1
+		ORDER BY updated_at DESC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, taskID)
+	if err != nil {
+		r.logger.ErrorContext(ctx, "failed to find task pull requests", "error", err, "task_id", taskID)
+		return nil, fmt.Errorf("failed to find task pull requests: %w", err)
+	}
+	defer rows.Close()
+
+	pullRequests := []*model.TaskPullRequest{}
+	for rows.Next() {
+		var pr model.TaskPullRequest
+		if err := rows.Scan(&pr.TaskID, &pr.Number, &pr.Title, &pr.URL, &pr.State, &pr.HeadRef, &pr.LinkType, &pr.MergedAt, &pr.UpdatedAt, &pr.ChecksStatus); err != nil {
+			r.logger.ErrorContext(ctx, "failed to scan task pull request", "error", err)
+			return nil, fmt.Errorf("failed to scan task pull request: %w", err)
+		}
+		pullRequests = append(pullRequests, &pr)
+	}
+
+	if err := rows.Err(); err != nil {
+		r.logger.ErrorContext(ctx, "error iterating task pull requests", "error", err)
+		return nil, fmt.Errorf("error iterating task pull requests: %w", err)
+	}
+
+	return pullRequests, nil
+}
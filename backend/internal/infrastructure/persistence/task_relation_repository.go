@@ -0,0 +1,99 @@
+package persistence
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+
+	"github.com/sikigasa/github-task-controller/backend/internal/domain/model"
+	"github.com/sikigasa/github-task-controller/backend/internal/domain/repository"
+)
+
+type taskRelationRepository struct {
+	db     *tenantDB
+	logger *slog.Logger
+}
+
+// NewTaskRelationRepository は新しいTaskRelationRepositoryを作成する
+func NewTaskRelationRepository(db *sql.DB, logger *slog.Logger) repository.TaskRelationRepository {
+	return &taskRelationRepository{
+		db:     newTenantDB(db),
+		logger: logger,
+	}
+}
+
+func (r *taskRelationRepository) Create(ctx context.Context, relation *model.TaskRelation) error {
+	query := `
+		INSERT INTO task_relation (task_id, related_task_id, type, created_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (task_id, related_task_id, type) DO NOTHING
+	`
+
+	_, err := r.db.ExecContext(ctx, query, relation.TaskID, relation.RelatedTaskID, relation.Type, relation.CreatedAt)
+	if err != nil {
+		r.logger.ErrorContext(ctx, "failed to create task relation", "error", err, "task_id", relation.TaskID)
+		return fmt.Errorf("failed to create task relation: %w", err)
+	}
+
+	return nil
+}
+
+func (r *taskRelationRepository) Delete(ctx context.Context, relation *model.TaskRelation) error {
+	query := `DELETE FROM task_relation WHERE task_id = $1 AND related_task_id = $2 AND type = $3`
+
+	result, err := r.db.ExecContext(ctx, query, relation.TaskID, relation.RelatedTaskID, relation.Type)
+	if err != nil {
+		r.logger.ErrorContext(ctx, "failed to delete task relation", "error", err, "task_id", relation.TaskID)
+		return fmt.Errorf("failed to delete task relation: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return model.ErrNotFound
+	}
+
+	return nil
+}
+
+func (r *taskRelationRepository) FindLinksByTaskID(ctx context.Context, taskID string) ([]*model.TaskRelationLink, error) {
+	query := `
+		SELECT r.type, r.task_id = $1, t.id, t.project_id, t.title, t.status, r.created_at
+		FROM task_relation r
+		JOIN task t ON t.id = CASE WHEN r.task_id = $1 THEN r.related_task_id ELSE r.task_id END
+		WHERE (r.task_id = $1 OR r.related_task_id = $1) AND t.deleted_at IS NULL
+		ORDER BY r.created_at ASC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, taskID)
+	if err != nil {
+		r.logger.ErrorContext(ctx, "failed to find task relations", "error", err, "task_id", taskID)
+		return nil, fmt.Errorf("failed to find task relations: %w", err)
+	}
+	defer rows.Close()
+
+	links := []*model.TaskRelationLink{}
+	for rows.Next() {
+		var link model.TaskRelationLink
+		var outgoing bool
+		if err := rows.Scan(&link.Type, &outgoing, &link.TaskID, &link.ProjectID, &link.Title, &link.Status, &link.CreatedAt); err != nil {
+			r.logger.ErrorContext(ctx, "failed to scan task relation", "error", err)
+			return nil, fmt.Errorf("failed to scan task relation: %w", err)
+		}
+		// 関連先から作成された関連は逆向きの種類で返す
+		if !outgoing {
+			link.Type = link.Type.Inverse()
+		}
+		links = append(links, &link)
+	}
+
+	if err := rows.Err(); err != nil {
+		r.logger.ErrorContext(ctx, "error iterating task relations", "error", err)
+		return nil, fmt.Errorf("error iterating task relations: %w", err)
+	}
+
+	return links, nil
+}
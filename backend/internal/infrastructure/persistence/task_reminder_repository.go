@@ -0,0 +1,57 @@
+package persistence
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/sikigasa/github-task-controller/backend/internal/domain/model"
+	"github.com/sikigasa/github-task-controller/backend/internal/domain/repository"
+)
+
+type taskReminderRepository struct {
+	db     *tenantDB
+	logger *slog.Logger
+}
+
+// NewTaskReminderRepository は新しいTaskReminderRepositoryを作成する
+func NewTaskReminderRepository(db *sql.DB, logger *slog.Logger) repository.TaskReminderRepository {
+	return &taskReminderRepository{
+		db:     newTenantDB(db),
+		logger: logger,
+	}
+}
+
+func (r *taskReminderRepository) Claim(ctx context.Context, reminder *model.TaskReminder) (bool, error) {
+	query := `
+		INSERT INTO task_reminder (task_id, channel, due_date, user_id, sent_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (task_id, channel, due_date) DO NOTHING
+	`
+
+	result, err := r.db.ExecContext(ctx, query, reminder.TaskID, reminder.Channel, reminder.DueDate, reminder.UserID, reminder.SentAt)
+	if err != nil {
+		r.logger.ErrorContext(ctx, "failed to claim task reminder", "error", err, "task_id", reminder.TaskID, "channel", reminder.Channel)
+		return false, fmt.Errorf("failed to claim task reminder: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return rowsAffected > 0, nil
+}
+
+func (r *taskReminderRepository) Release(ctx context.Context, taskID string, channel model.ReminderChannel, dueDate time.Time) error {
+	query := `DELETE FROM task_reminder WHERE task_id = $1 AND channel = $2 AND due_date = $3`
+
+	if _, err := r.db.ExecContext(ctx, query, taskID, channel, dueDate); err != nil {
+		r.logger.ErrorContext(ctx, "failed to release task reminder", "error", err, "task_id", taskID, "channel", channel)
+		return fmt.Errorf("failed to release task reminder: %w", err)
+	}
+
+	return nil
+}
@@ -3,39 +3,49 @@ package persistence
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"log/slog"
+	"strings"
 	"time"
 
+	"github.com/lib/pq"
 	"github.com/sikigasa/github-task-controller/backend/internal/domain/model"
 	"github.com/sikigasa/github-task-controller/backend/internal/domain/repository"
 )
 
+// taskColumns はタスク検索時に取得するカラム（scanTaskの引数順と一致させる）
+const taskColumns = `id, project_id, title, description, status, priority, start_date, end_date, estimate, milestone_id, parent_task_id, github_item_id, github_issue_number, github_issue_url, github_branch, github_checks_status, github_sync_state, version, created_at, updated_at, deleted_at`
+
 type taskRepository struct {
-	db     *sql.DB
+	db     *tenantDB
 	logger *slog.Logger
 }
 
 // NewTaskRepository は新しいTaskRepositoryを作成する
 func NewTaskRepository(db *sql.DB, logger *slog.Logger) repository.TaskRepository {
 	return &taskRepository{
-		db:     db,
+		db:     newTenantDB(db),
 		logger: logger,
 	}
 }
 
 func (r *taskRepository) Create(ctx context.Context, task *model.Task) error {
 	query := `
-		INSERT INTO task (id, project_id, title, description, status, priority, end_date, github_item_id, github_issue_number, github_issue_url, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+		INSERT INTO task (id, project_id, title, description, status, priority, start_date, end_date, estimate, milestone_id, parent_task_id, github_item_id, github_issue_number, github_issue_url, github_branch, github_checks_status, github_sync_state, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19)
+		RETURNING version
 	`
 
-	_, err := r.db.ExecContext(ctx, query,
+	err := r.db.QueryRowContext(ctx, query,
 		task.ID, task.ProjectID, task.Title, task.Description,
-		task.Status, task.Priority, task.EndDate,
-		task.GithubItemID, task.GithubIssueNumber, task.GithubIssueURL,
+		task.Status, task.Priority, task.StartDate, task.EndDate, task.Estimate, task.MilestoneID, task.ParentTaskID,
+		task.GithubItemID, task.GithubIssueNumber, task.GithubIssueURL, task.GithubBranch, task.GithubChecksStatus, task.GithubSyncState,
 		task.CreatedAt, task.UpdatedAt,
-	)
+	).Scan(&task.Version)
+	if isUniqueViolation(err, "task_project_github_item_unique") {
+		return fmt.Errorf("github item is already linked to another task in project %s: %w", task.ProjectID, model.ErrConflict)
+	}
 	if err != nil {
 		r.logger.ErrorContext(ctx, "failed to create task", "error", err)
 		return fmt.Errorf("failed to create task: %w", err)
@@ -47,96 +57,343 @@ func (r *taskRepository) Create(ctx context.Context, task *model.Task) error {
 
 func (r *taskRepository) FindByID(ctx context.Context, id string) (*model.Task, error) {
 	query := `
-		SELECT id, project_id, title, description, status, priority, end_date, github_item_id, github_issue_number, github_issue_url, created_at, updated_at
+		SELECT ` + taskColumns + `
 		FROM task
-		WHERE id = $1
+		WHERE id = $1 AND deleted_at IS NULL
 	`
 
-	var task model.Task
-	var endDate sql.NullTime
-	var githubItemID, githubIssueURL sql.NullString
-	var githubIssueNumber sql.NullInt32
-	err := r.db.QueryRowContext(ctx, query, id).Scan(
-		&task.ID, &task.ProjectID, &task.Title, &task.Description,
-		&task.Status, &task.Priority, &endDate,
-		&githubItemID, &githubIssueNumber, &githubIssueURL,
-		&task.CreatedAt, &task.UpdatedAt,
-	)
-	if err == sql.ErrNoRows {
-		return nil, fmt.Errorf("task not found: %s", id)
+	task, err := scanTask(r.db.QueryRowContext(ctx, query, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, model.ErrNotFound
 	}
 	if err != nil {
 		r.logger.ErrorContext(ctx, "failed to find task by id", "error", err, "id", id)
 		return nil, fmt.Errorf("failed to find task by id: %w", err)
 	}
 
-	if endDate.Valid {
-		task.EndDate = &endDate.Time
+	return task, nil
+}
+
+func (r *taskRepository) FindOwnerID(ctx context.Context, id string) (string, error) {
+	query := `
+		SELECT p.user_id
+		FROM task t
+		JOIN project p ON p.id = t.project_id
+		WHERE t.id = $1 AND t.deleted_at IS NULL AND p.deleted_at IS NULL
+	`
+
+	var ownerID string
+	err := r.db.QueryRowContext(ctx, query, id).Scan(&ownerID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", model.ErrNotFound
 	}
-	if githubItemID.Valid {
-		task.GithubItemID = &githubItemID.String
+	if err != nil {
+		r.logger.ErrorContext(ctx, "failed to find task owner", "error", err, "id", id)
+		return "", fmt.Errorf("failed to find task owner: %w", err)
 	}
-	if githubIssueNumber.Valid {
-		num := int(githubIssueNumber.Int32)
-		task.GithubIssueNumber = &num
+
+	return ownerID, nil
+}
+
+func (r *taskRepository) FindByGithubIssueURL(ctx context.Context, projectID, issueURL string) (*model.Task, error) {
+	query := `
+		SELECT ` + taskColumns + `
+		FROM task
+		WHERE project_id = $1 AND github_issue_url = $2 AND deleted_at IS NULL
+		ORDER BY created_at
+		LIMIT 1
+	`
+
+	task, err := scanTask(r.db.QueryRowContext(ctx, query, projectID, issueURL))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, model.ErrNotFound
 	}
-	if githubIssueURL.Valid {
-		task.GithubIssueURL = &githubIssueURL.String
+	if err != nil {
+		r.logger.ErrorContext(ctx, "failed to find task by github issue url", "error", err, "project_id", projectID)
+		return nil, fmt.Errorf("failed to find task by github issue url: %w", err)
 	}
 
-	return &task, nil
+	return task, nil
 }
 
-func (r *taskRepository) FindByProjectID(ctx context.Context, projectID string) ([]*model.Task, error) {
+func (r *taskRepository) FindByGithubLink(ctx context.Context, itemID, issueURL string) ([]*model.Task, error) {
 	query := `
-		SELECT id, project_id, title, description, status, priority, end_date, github_item_id, github_issue_number, github_issue_url, created_at, updated_at
+		SELECT ` + taskColumns + `
 		FROM task
-		WHERE project_id = $1
-		ORDER BY created_at DESC
+		WHERE (($1 <> '' AND github_item_id = $1) OR ($2 <> '' AND github_issue_url = $2)) AND deleted_at IS NULL
 	`
 
-	rows, err := r.db.QueryContext(ctx, query, projectID)
+	rows, err := r.db.QueryContext(ctx, query, itemID, issueURL)
+	if err != nil {
+		r.logger.ErrorContext(ctx, "failed to find tasks by github link", "error", err)
+		return nil, fmt.Errorf("failed to find tasks by github link: %w", err)
+	}
+	defer rows.Close()
+
+	return r.scanTasks(ctx, rows)
+}
+
+func (r *taskRepository) FindByGithubIssue(ctx context.Context, number int, issueURL string) ([]*model.Task, error) {
+	// 番号のインデックスで絞り込んでから、別のリポジトリの同じ番号のIssueをURLで除く
+	query := `
+		SELECT ` + taskColumns + `
+		FROM task
+		WHERE github_issue_number = $1 AND lower(github_issue_url) = lower($2) AND deleted_at IS NULL
+		ORDER BY created_at ASC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, number, issueURL)
+	if err != nil {
+		r.logger.ErrorContext(ctx, "failed to find tasks by github issue", "error", err, "issue_number", number)
+		return nil, fmt.Errorf("failed to find tasks by github issue: %w", err)
+	}
+	defer rows.Close()
+
+	return r.scanTasks(ctx, rows)
+}
+
+// taskSortColumns はタスク一覧でソートに使用できるフィールドとカラムの対応
+var taskSortColumns = map[string]string{
+	"title":        "title",
+	"status":       "status",
+	"priority":     "priority",
+	"start_date":   "start_date",
+	"end_date":     "end_date",
+	"estimate":     "estimate",
+	"milestone_id": "milestone_id",
+	"created_at":   "created_at",
+	"updated_at":   "updated_at",
+}
+
+func (r *taskRepository) FindByProjectID(ctx context.Context, projectID string, filter model.TaskFilter, opts model.ListOptions) ([]*model.Task, error) {
+	where, args := taskFilterClause(projectID, filter)
+	query := `
+		SELECT ` + taskColumns + `
+		FROM task
+		WHERE ` + where + `
+	` + orderByClause(opts.Sort, taskSortColumns, "created_at DESC, id ASC") + paginationClause(opts)
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
 	if err != nil {
 		r.logger.ErrorContext(ctx, "failed to find tasks by project_id", "error", err, "project_id", projectID)
 		return nil, fmt.Errorf("failed to find tasks by project_id: %w", err)
 	}
 	defer rows.Close()
 
+	return r.scanTasks(ctx, rows)
+}
+
+// taskFilterClause は絞り込み条件からWHERE句とプレースホルダの引数を組み立てる
+func taskFilterClause(projectID string, filter model.TaskFilter) (string, []any) {
+	conditions := []string{"project_id = $1", "deleted_at IS NULL"}
+	args := []any{projectID}
+	addArg := func(v any) string {
+		args = append(args, v)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	if len(filter.Statuses) > 0 {
+		statuses := make([]int64, 0, len(filter.Statuses))
+		for _, s := range filter.Statuses {
+			statuses = append(statuses, int64(s))
+		}
+		conditions = append(conditions, "status = ANY("+addArg(pq.Array(statuses))+")")
+	}
+	if len(filter.Priorities) > 0 {
+		priorities := make([]int64, 0, len(filter.Priorities))
+		for _, p := range filter.Priorities {
+			priorities = append(priorities, int64(p))
+		}
+		conditions = append(conditions, "priority = ANY("+addArg(pq.Array(priorities))+")")
+	}
+	if filter.MilestoneID != nil {
+		conditions = append(conditions, "milestone_id = "+addArg(*filter.MilestoneID))
+	}
+	if filter.Overdue {
+		conditions = append(conditions, "status <> "+addArg(model.TaskStatusDone)+" AND end_date < CURRENT_TIMESTAMP")
+	}
+	if filter.DueBefore != nil {
+		conditions = append(conditions, "end_date < "+addArg(*filter.DueBefore))
+	}
+	if filter.DueAfter != nil {
+		conditions = append(conditions, "end_date >= "+addArg(*filter.DueAfter))
+	}
+	if filter.Query != "" {
+		pattern := addArg("%" + escapeLike(filter.Query) + "%")
+		conditions = append(conditions, "(title ILIKE "+pattern+" OR description ILIKE "+pattern+")")
+	}
+
+	return strings.Join(conditions, " AND "), args
+}
+
+// FindByParentID は親タスクのサブタスクを作成順に検索する
+func (r *taskRepository) FindByParentID(ctx context.Context, parentID string) ([]*model.Task, error) {
+	query := `
+		SELECT ` + taskColumns + `
+		FROM task
+		WHERE parent_task_id = $1 AND deleted_at IS NULL
+		ORDER BY created_at ASC, id ASC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, parentID)
+	if err != nil {
+		r.logger.ErrorContext(ctx, "failed to find subtasks", "error", err, "parent_task_id", parentID)
+		return nil, fmt.Errorf("failed to find subtasks: %w", err)
+	}
+	defer rows.Close()
+
+	return r.scanTasks(ctx, rows)
+}
+
+// FindByIDs は複数IDのタスクを1回のクエリでまとめて取得する
+func (r *taskRepository) FindByIDs(ctx context.Context, ids []string) ([]*model.Task, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	query := `
+		SELECT ` + taskColumns + `
+		FROM task
+		WHERE id = ANY($1) AND deleted_at IS NULL
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, pq.Array(ids))
+	if err != nil {
+		r.logger.ErrorContext(ctx, "failed to find tasks by ids", "error", err, "task_count", len(ids))
+		return nil, fmt.Errorf("failed to find tasks by ids: %w", err)
+	}
+	defer rows.Close()
+
+	return r.scanTasks(ctx, rows)
+}
+
+// FindByProjectIDs は複数プロジェクトのタスクを1回のクエリでまとめて取得する
+func (r *taskRepository) FindByProjectIDs(ctx context.Context, projectIDs []string) ([]*model.Task, error) {
+	if len(projectIDs) == 0 {
+		return nil, nil
+	}
+
+	query := `
+		SELECT ` + taskColumns + `
+		FROM task
+		WHERE project_id = ANY($1) AND deleted_at IS NULL
+		ORDER BY created_at DESC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, pq.Array(projectIDs))
+	if err != nil {
+		r.logger.ErrorContext(ctx, "failed to find tasks by project_ids", "error", err, "project_count", len(projectIDs))
+		return nil, fmt.Errorf("failed to find tasks by project_ids: %w", err)
+	}
+	defer rows.Close()
+
+	return r.scanTasks(ctx, rows)
+}
+
+// FindDueByProjectIDs は複数プロジェクトの期限切れ・期限間近のタスクを1回のクエリでまとめて取得する
+func (r *taskRepository) FindDueByProjectIDs(ctx context.Context, projectIDs []string, before time.Time) ([]*model.Task, error) {
+	if len(projectIDs) == 0 {
+		return nil, nil
+	}
+
+	query := `
+		SELECT ` + taskColumns + `
+		FROM task
+		WHERE project_id = ANY($1) AND status <> $2 AND end_date < $3 AND deleted_at IS NULL
+		ORDER BY end_date ASC, id ASC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, pq.Array(projectIDs), model.TaskStatusDone, before)
+	if err != nil {
+		r.logger.ErrorContext(ctx, "failed to find due tasks by project_ids", "error", err, "project_count", len(projectIDs))
+		return nil, fmt.Errorf("failed to find due tasks by project_ids: %w", err)
+	}
+	defer rows.Close()
+
+	return r.scanTasks(ctx, rows)
+}
+
+// FindDueBetween はリマインダーの対象になる終了日が近いタスクをすべてのプロジェクトから取得する
+func (r *taskRepository) FindDueBetween(ctx context.Context, from, before time.Time) ([]*model.Task, error) {
+	query := `
+		SELECT ` + taskColumns + `
+		FROM task
+		WHERE status <> $1 AND end_date >= $2 AND end_date < $3 AND deleted_at IS NULL
+		ORDER BY end_date ASC, id ASC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, model.TaskStatusDone, from, before)
+	if err != nil {
+		r.logger.ErrorContext(ctx, "failed to find due tasks", "error", err)
+		return nil, fmt.Errorf("failed to find due tasks: %w", err)
+	}
+	defer rows.Close()
+
+	return r.scanTasks(ctx, rows)
+}
+
+// CountByProjectIDs は複数プロジェクトのタスク集計を1回のクエリでまとめて取得する
+// タスクが存在しないプロジェクトは結果に含まれない
+func (r *taskRepository) CountByProjectIDs(ctx context.Context, projectIDs []string, overdueBefore time.Time) (map[string]*model.ProjectStats, error) {
+	stats := make(map[string]*model.ProjectStats)
+	if len(projectIDs) == 0 {
+		return stats, nil
+	}
+
+	query := `
+		SELECT project_id,
+			COUNT(*),
+			COUNT(*) FILTER (WHERE status = $2),
+			COUNT(*) FILTER (WHERE status = $3),
+			COUNT(*) FILTER (WHERE status = $4),
+			COUNT(*) FILTER (WHERE status <> $4 AND end_date < $5),
+			COUNT(*) FILTER (WHERE github_item_id IS NOT NULL),
+			COALESCE(SUM(estimate), 0),
+			COALESCE(SUM(estimate) FILTER (WHERE status = $4), 0)
+		FROM task
+		WHERE project_id = ANY($1) AND deleted_at IS NULL
+		GROUP BY project_id
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, pq.Array(projectIDs),
+		model.TaskStatusTodo, model.TaskStatusInProgress, model.TaskStatusDone, overdueBefore)
+	if err != nil {
+		r.logger.ErrorContext(ctx, "failed to count tasks by project_ids", "error", err, "project_count", len(projectIDs))
+		return nil, fmt.Errorf("failed to count tasks by project_ids: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var projectID string
+		var s model.ProjectStats
+		if err := rows.Scan(&projectID, &s.TaskCount, &s.TodoCount, &s.InProgressCount, &s.DoneCount, &s.OverdueCount, &s.GithubLinkedCount, &s.EstimateTotal, &s.EstimateDone); err != nil {
+			r.logger.ErrorContext(ctx, "failed to scan task counts", "error", err)
+			return nil, fmt.Errorf("failed to scan task counts: %w", err)
+		}
+		stats[projectID] = &s
+	}
+
+	if err := rows.Err(); err != nil {
+		r.logger.ErrorContext(ctx, "error iterating task counts", "error", err)
+		return nil, fmt.Errorf("error iterating task counts: %w", err)
+	}
+
+	return stats, nil
+}
+
+// scanTasks はタスク検索結果の行をスキャンする
+func (r *taskRepository) scanTasks(ctx context.Context, rows *tenantRows) ([]*model.Task, error) {
 	var tasks []*model.Task
 	for rows.Next() {
-		var task model.Task
-		var endDate sql.NullTime
-		var githubItemID, githubIssueURL sql.NullString
-		var githubIssueNumber sql.NullInt32
-		err := rows.Scan(
-			&task.ID, &task.ProjectID, &task.Title, &task.Description,
-			&task.Status, &task.Priority, &endDate,
-			&githubItemID, &githubIssueNumber, &githubIssueURL,
-			&task.CreatedAt, &task.UpdatedAt,
-		)
+		task, err := scanTask(rows)
 		if err != nil {
 			r.logger.ErrorContext(ctx, "failed to scan task", "error", err)
 			return nil, fmt.Errorf("failed to scan task: %w", err)
 		}
-
-		if endDate.Valid {
-			task.EndDate = &endDate.Time
-		}
-		if githubItemID.Valid {
-			task.GithubItemID = &githubItemID.String
-		}
-		if githubIssueNumber.Valid {
-			num := int(githubIssueNumber.Int32)
-			task.GithubIssueNumber = &num
-		}
-		if githubIssueURL.Valid {
-			task.GithubIssueURL = &githubIssueURL.String
-		}
-
-		tasks = append(tasks, &task)
+		tasks = append(tasks, task)
 	}
 
-	if err = rows.Err(); err != nil {
+	if err := rows.Err(); err != nil {
 		r.logger.ErrorContext(ctx, "error iterating tasks", "error", err)
 		return nil, fmt.Errorf("error iterating tasks: %w", err)
 	}
@@ -147,49 +404,210 @@ func (r *taskRepository) FindByProjectID(ctx context.Context, projectID string)
 func (r *taskRepository) Update(ctx context.Context, task *model.Task) error {
 	query := `
 		UPDATE task
-		SET title = $1, description = $2, status = $3, priority = $4, end_date = $5, github_item_id = $6, github_issue_number = $7, github_issue_url = $8, updated_at = $9
-		WHERE id = $10
+		SET title = $1, description = $2, status = $3, priority = $4, start_date = $5, end_date = $6, estimate = $7, milestone_id = $8, parent_task_id = $9,
+			github_item_id = $10, github_issue_number = $11, github_issue_url = $12, github_branch = $13, github_checks_status = $14, github_sync_state = $15, updated_at = $16,
+			version = version + 1
+		WHERE id = $17 AND deleted_at IS NULL AND version = $18
+		RETURNING version
 	`
 
-	result, err := r.db.ExecContext(ctx, query,
-		task.Title, task.Description, task.Status, task.Priority, task.EndDate,
-		task.GithubItemID, task.GithubIssueNumber, task.GithubIssueURL,
-		time.Now(), task.ID,
-	)
+	err := r.db.QueryRowContext(ctx, query,
+		task.Title, task.Description, task.Status, task.Priority, task.StartDate, task.EndDate, task.Estimate, task.MilestoneID, task.ParentTaskID,
+		task.GithubItemID, task.GithubIssueNumber, task.GithubIssueURL, task.GithubBranch, task.GithubChecksStatus, task.GithubSyncState,
+		time.Now(), task.ID, task.Version,
+	).Scan(&task.Version)
+	if isUniqueViolation(err, "task_project_github_item_unique") {
+		return fmt.Errorf("github item is already linked to another task in project %s: %w", task.ProjectID, model.ErrConflict)
+	}
+	if errors.Is(err, sql.ErrNoRows) {
+		return r.staleOrNotFound(ctx, task.ID, task.Version)
+	}
 	if err != nil {
 		r.logger.ErrorContext(ctx, "failed to update task", "error", err, "task_id", task.ID)
 		return fmt.Errorf("failed to update task: %w", err)
 	}
 
+	r.logger.InfoContext(ctx, "task updated", "task_id", task.ID)
+	return nil
+}
+
+// staleOrNotFound は更新できなかったタスクが他の更新と競合した（ゴミ箱以外にある）場合はErrStaleVersion、それ以外はErrNotFoundを返す
+func (r *taskRepository) staleOrNotFound(ctx context.Context, id string, version int) error {
+	var exists bool
+	query := `SELECT EXISTS (SELECT 1 FROM task WHERE id = $1 AND deleted_at IS NULL)`
+	if err := r.db.QueryRowContext(ctx, query, id).Scan(&exists); err != nil {
+		r.logger.ErrorContext(ctx, "failed to check task existence", "error", err, "task_id", id)
+		return fmt.Errorf("failed to check task existence: %w", err)
+	}
+	if !exists {
+		return model.ErrNotFound
+	}
+	return fmt.Errorf("task %s is not at version %d: %w", id, version, model.ErrStaleVersion)
+}
+
+func (r *taskRepository) Delete(ctx context.Context, id string, deletedAt time.Time) error {
+	query := `UPDATE task SET deleted_at = $1 WHERE id = $2 AND deleted_at IS NULL`
+
+	result, err := r.db.ExecContext(ctx, query, deletedAt, id)
+	if err != nil {
+		r.logger.ErrorContext(ctx, "failed to delete task", "error", err, "task_id", id)
+		return fmt.Errorf("failed to delete task: %w", err)
+	}
+
 	rowsAffected, err := result.RowsAffected()
 	if err != nil {
 		return fmt.Errorf("failed to get rows affected: %w", err)
 	}
 	if rowsAffected == 0 {
-		return fmt.Errorf("task not found: %s", task.ID)
+		return model.ErrNotFound
 	}
 
-	r.logger.InfoContext(ctx, "task updated", "task_id", task.ID)
+	r.logger.InfoContext(ctx, "task moved to trash", "task_id", id)
 	return nil
 }
 
-func (r *taskRepository) Delete(ctx context.Context, id string) error {
-	query := `DELETE FROM task WHERE id = $1`
+func (r *taskRepository) FindDeletedByID(ctx context.Context, id string) (*model.Task, error) {
+	query := `
+		SELECT ` + taskColumns + `
+		FROM task
+		WHERE id = $1 AND deleted_at IS NOT NULL
+	`
 
-	result, err := r.db.ExecContext(ctx, query, id)
+	task, err := scanTask(r.db.QueryRowContext(ctx, query, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, model.ErrNotFound
+	}
 	if err != nil {
-		r.logger.ErrorContext(ctx, "failed to delete task", "error", err, "task_id", id)
-		return fmt.Errorf("failed to delete task: %w", err)
+		r.logger.ErrorContext(ctx, "failed to find deleted task by id", "error", err, "id", id)
+		return nil, fmt.Errorf("failed to find deleted task by id: %w", err)
 	}
 
-	rowsAffected, err := result.RowsAffected()
+	return task, nil
+}
+
+// FindDeletedByProjectIDs は複数プロジェクトのゴミ箱のタスクを削除日時の新しい順に取得する
+// プロジェクトと一緒に削除したタスクはプロジェクトのゴミ箱に含まれるため、削除されていないプロジェクトのIDを渡す
+func (r *taskRepository) FindDeletedByProjectIDs(ctx context.Context, projectIDs []string) ([]*model.Task, error) {
+	if len(projectIDs) == 0 {
+		return nil, nil
+	}
+
+	query := `
+		SELECT ` + taskColumns + `
+		FROM task
+		WHERE project_id = ANY($1) AND deleted_at IS NOT NULL
+		ORDER BY deleted_at DESC, id ASC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, pq.Array(projectIDs))
 	if err != nil {
-		return fmt.Errorf("failed to get rows affected: %w", err)
+		r.logger.ErrorContext(ctx, "failed to find deleted tasks by project_ids", "error", err, "project_count", len(projectIDs))
+		return nil, fmt.Errorf("failed to find deleted tasks by project_ids: %w", err)
 	}
-	if rowsAffected == 0 {
-		return fmt.Errorf("task not found: %s", id)
+	defer rows.Close()
+
+	return r.scanTasks(ctx, rows)
+}
+
+func (r *taskRepository) Restore(ctx context.Context, task *model.Task) error {
+	query := `
+		UPDATE task SET deleted_at = NULL, parent_task_id = $1, updated_at = $2, version = version + 1
+		WHERE id = $3 AND deleted_at IS NOT NULL
+		RETURNING version
+	`
+
+	err := r.db.QueryRowContext(ctx, query, task.ParentTaskID, task.UpdatedAt, task.ID).Scan(&task.Version)
+	if errors.Is(err, sql.ErrNoRows) {
+		return model.ErrNotFound
+	}
+	if err != nil {
+		r.logger.ErrorContext(ctx, "failed to restore task", "error", err, "task_id", task.ID)
+		return fmt.Errorf("failed to restore task: %w", err)
 	}
 
-	r.logger.InfoContext(ctx, "task deleted", "task_id", id)
+	r.logger.InfoContext(ctx, "task restored", "task_id", task.ID)
 	return nil
 }
+
+// PurgeDeleted はbefore以前にゴミ箱に移したタスクを完全に削除し、削除した件数を返す
+// プロジェクトと一緒に削除したタスクはプロジェクトの完全な削除で消えるため対象にしない
+func (r *taskRepository) PurgeDeleted(ctx context.Context, before time.Time) (int64, error) {
+	query := `
+		DELETE FROM task t
+		WHERE t.deleted_at < $1
+			AND NOT EXISTS (SELECT 1 FROM project p WHERE p.id = t.project_id AND p.deleted_at = t.deleted_at)
+	`
+
+	result, err := r.db.ExecContext(ctx, query, before)
+	if err != nil {
+		r.logger.ErrorContext(ctx, "failed to purge deleted tasks", "error", err)
+		return 0, fmt.Errorf("failed to purge deleted tasks: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return rowsAffected, nil
+}
+
+// scanTask はtaskColumnsの順で1行をスキャンする
+func scanTask(row rowScanner) (*model.Task, error) {
+	var task model.Task
+	var startDate, endDate, deletedAt sql.NullTime
+	var estimate sql.NullFloat64
+	var milestoneID, parentTaskID, githubItemID, githubIssueURL, githubBranch, githubChecksStatus, githubSyncState sql.NullString
+	var githubIssueNumber sql.NullInt32
+	err := row.Scan(
+		&task.ID, &task.ProjectID, &task.Title, &task.Description,
+		&task.Status, &task.Priority, &startDate, &endDate, &estimate, &milestoneID, &parentTaskID,
+		&githubItemID, &githubIssueNumber, &githubIssueURL, &githubBranch, &githubChecksStatus, &githubSyncState,
+		&task.Version, &task.CreatedAt, &task.UpdatedAt, &deletedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if startDate.Valid {
+		task.StartDate = &startDate.Time
+	}
+	if endDate.Valid {
+		task.EndDate = &endDate.Time
+	}
+	if estimate.Valid {
+		task.Estimate = &estimate.Float64
+	}
+	if milestoneID.Valid {
+		task.MilestoneID = &milestoneID.String
+	}
+	if parentTaskID.Valid {
+		task.ParentTaskID = &parentTaskID.String
+	}
+	if githubItemID.Valid {
+		task.GithubItemID = &githubItemID.String
+	}
+	if githubIssueNumber.Valid {
+		num := int(githubIssueNumber.Int32)
+		task.GithubIssueNumber = &num
+	}
+	if githubIssueURL.Valid {
+		task.GithubIssueURL = &githubIssueURL.String
+	}
+	if githubBranch.Valid {
+		task.GithubBranch = &githubBranch.String
+	}
+	if githubChecksStatus.Valid {
+		status := model.ChecksStatus(githubChecksStatus.String)
+		task.GithubChecksStatus = &status
+	}
+	if githubSyncState.Valid {
+		state := model.GithubSyncState(githubSyncState.String)
+		task.GithubSyncState = &state
+	}
+	if deletedAt.Valid {
+		task.DeletedAt = &deletedAt.Time
+	}
+
+	return &task, nil
+}
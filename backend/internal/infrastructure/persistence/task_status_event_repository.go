@@ -0,0 +1,132 @@
+package persistence
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/sikigasa/github-task-controller/backend/internal/domain/model"
+	"github.com/sikigasa/github-task-controller/backend/internal/domain/repository"
+)
+
+type taskStatusEventRepository struct {
+	db     *tenantDB
+	logger *slog.Logger
+}
+
+// NewTaskStatusEventRepository は新しいTaskStatusEventRepositoryを作成する
+func NewTaskStatusEventRepository(db *sql.DB, logger *slog.Logger) repository.TaskStatusEventRepository {
+	return &taskStatusEventRepository{
+		db:     newTenantDB(db),
+		logger: logger,
+	}
+}
+
+func (r *taskStatusEventRepository) Create(ctx context.Context, event *model.TaskStatusEvent) error {
+	query := `
+		INSERT INTO task_status_event (id, task_id, project_id, from_status, to_status, reopened, changed_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`
+
+	_, err := r.db.ExecContext(ctx, query,
+		event.ID, event.TaskID, event.ProjectID,
+		event.FromStatus, event.ToStatus, event.Reopened, event.ChangedAt,
+	)
+	if err != nil {
+		r.logger.ErrorContext(ctx, "failed to create task status event", "error", err, "task_id", event.TaskID)
+		return fmt.Errorf("failed to create task status event: %w", err)
+	}
+
+	return nil
+}
+
+func (r *taskStatusEventRepository) FindByTaskID(ctx context.Context, taskID string) ([]*model.TaskStatusEvent, error) {
+	query := `
+		SELECT id, task_id, project_id, from_status, to_status, reopened, changed_at
+		FROM task_status_event
+		WHERE task_id = $1
+		ORDER BY changed_at ASC
+	`
+
+	return r.query(ctx, query, taskID)
+}
+
+func (r *taskStatusEventRepository) FindByProjectID(ctx context.Context, projectID string) ([]*model.TaskStatusEvent, error) {
+	query := `
+		SELECT id, task_id, project_id, from_status, to_status, reopened, changed_at
+		FROM task_status_event
+		WHERE project_id = $1
+		ORDER BY changed_at ASC
+	`
+
+	return r.query(ctx, query, projectID)
+}
+
+func (r *taskStatusEventRepository) FindByProjectIDsSince(ctx context.Context, projectIDs []string, since time.Time) ([]*model.TaskStatusEvent, error) {
+	if len(projectIDs) == 0 {
+		return nil, nil
+	}
+
+	query := `
+		SELECT id, task_id, project_id, from_status, to_status, reopened, changed_at
+		FROM task_status_event
+		WHERE project_id = ANY($1) AND changed_at >= $2
+		ORDER BY changed_at ASC
+	`
+
+	return r.query(ctx, query, pq.Array(projectIDs), since)
+}
+
+func (r *taskStatusEventRepository) FindRecentByProjectIDs(ctx context.Context, projectIDs []string, limit int) ([]*model.TaskStatusEvent, error) {
+	if len(projectIDs) == 0 {
+		return nil, nil
+	}
+
+	query := `
+		SELECT id, task_id, project_id, from_status, to_status, reopened, changed_at
+		FROM task_status_event
+		WHERE project_id = ANY($1)
+		ORDER BY changed_at DESC
+		LIMIT $2
+	`
+
+	return r.query(ctx, query, pq.Array(projectIDs), limit)
+}
+
+// query はイベント検索クエリを実行して結果をスキャンする
+func (r *taskStatusEventRepository) query(ctx context.Context, query string, args ...any) ([]*model.TaskStatusEvent, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		r.logger.ErrorContext(ctx, "failed to find task status events", "error", err)
+		return nil, fmt.Errorf("failed to find task status events: %w", err)
+	}
+	defer rows.Close()
+
+	var events []*model.TaskStatusEvent
+	for rows.Next() {
+		var event model.TaskStatusEvent
+		var fromStatus sql.NullInt32
+		if err := rows.Scan(
+			&event.ID, &event.TaskID, &event.ProjectID,
+			&fromStatus, &event.ToStatus, &event.Reopened, &event.ChangedAt,
+		); err != nil {
+			r.logger.ErrorContext(ctx, "failed to scan task status event", "error", err)
+			return nil, fmt.Errorf("failed to scan task status event: %w", err)
+		}
+		if fromStatus.Valid {
+			s := model.TaskStatus(fromStatus.Int32)
+			event.FromStatus = &s
+		}
+		events = append(events, &event)
+	}
+
+	if err := rows.Err(); err != nil {
+		r.logger.ErrorContext(ctx, "error iterating task status events", "error", err)
+		return nil, fmt.Errorf("error iterating task status events: %w", err)
+	}
+
+	return events, nil
+}
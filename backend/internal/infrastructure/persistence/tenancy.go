@@ -0,0 +1,258 @@
+package persistence
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+// tenantSetting は行レベルセキュリティのポリシーが参照する、リクエストのユーザーIDを保持する設定名
+const tenantSetting = "app.user_id"
+
+// bypassSetting は行レベルセキュリティの制限を外す設定名（"on"の場合のみ制限を外す）
+// tenantSettingとbypassSettingのどちらも設定していない接続では、ポリシーによりすべての行が拒否される
+const bypassSetting = "app.rls_bypass"
+
+// tenantKey はTenancy.Scope・BypassTenancyで設定したテナントをコンテキストに保持するためのキー
+type tenantKey struct{}
+
+// tenant はクエリを実行するテナント
+type tenant struct {
+	userID string
+	// bypass は行レベルセキュリティの制限を外すか（userIDが空でもbypassでなければすべての行が拒否される）
+	bypass bool
+}
+
+// apply はトランザクションの間だけテナントを設定する（コミット・ロールバックで元に戻る）
+func (t tenant) apply(ctx context.Context, tx *sql.Tx) error {
+	bypass := "off"
+	if t.bypass {
+		bypass = "on"
+	}
+	query := `SELECT set_config('` + tenantSetting + `', $1, true), set_config('` + bypassSetting + `', $2, true)`
+	if _, err := tx.ExecContext(ctx, query, t.userID, bypass); err != nil {
+		return fmt.Errorf("failed to set tenant: %w", err)
+	}
+	return nil
+}
+
+// tenantDB はリポジトリのクエリを実行する
+// コンテキストにTransactor.WithinTxのトランザクションがある場合はそのトランザクションで実行する
+// テナントがある場合は、クエリごとにトランザクションを開始してテナントを設定してから実行する
+// どちらもない場合（行レベルセキュリティを使わない場合）は接続プールで実行する
+type tenantDB struct {
+	db *sql.DB
+}
+
+// newTenantDB は新しいtenantDBを作成する
+func newTenantDB(db *sql.DB) *tenantDB {
+	return &tenantDB{db: db}
+}
+
+func (d *tenantDB) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	if tx, ok := ctx.Value(txKey{}).(*sql.Tx); ok {
+		return tx.ExecContext(ctx, query, args...)
+	}
+	if _, ok := ctx.Value(tenantKey{}).(tenant); !ok {
+		return d.db.ExecContext(ctx, query, args...)
+	}
+
+	tx, err := d.begin(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	result, err := tx.ExecContext(ctx, query, args...)
+	if err != nil {
+		_ = tx.Rollback()
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return result, nil
+}
+
+func (d *tenantDB) QueryContext(ctx context.Context, query string, args ...any) (*tenantRows, error) {
+	var tx *sql.Tx
+	var rows *sql.Rows
+	var err error
+	if current, ok := ctx.Value(txKey{}).(*sql.Tx); ok {
+		rows, err = current.QueryContext(ctx, query, args...)
+	} else if _, ok := ctx.Value(tenantKey{}).(tenant); ok {
+		if tx, err = d.begin(ctx, nil); err != nil {
+			return nil, err
+		}
+		if rows, err = tx.QueryContext(ctx, query, args...); err != nil {
+			_ = tx.Rollback()
+		}
+	} else {
+		rows, err = d.db.QueryContext(ctx, query, args...)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &tenantRows{Rows: rows, tx: tx}, nil
+}
+
+func (d *tenantDB) QueryRowContext(ctx context.Context, query string, args ...any) *tenantRow {
+	if tx, ok := ctx.Value(txKey{}).(*sql.Tx); ok {
+		return &tenantRow{Row: tx.QueryRowContext(ctx, query, args...)}
+	}
+	if _, ok := ctx.Value(tenantKey{}).(tenant); !ok {
+		return &tenantRow{Row: d.db.QueryRowContext(ctx, query, args...)}
+	}
+
+	tx, err := d.begin(ctx, nil)
+	if err != nil {
+		return &tenantRow{err: err}
+	}
+	return &tenantRow{Row: tx.QueryRowContext(ctx, query, args...), tx: tx}
+}
+
+// BeginTx はリポジトリ内で完結するトランザクションを開始する
+// Transactor.WithinTxのトランザクション内では入れ子にできないためエラーを返す
+func (d *tenantDB) BeginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error) {
+	if _, ok := ctx.Value(txKey{}).(*sql.Tx); ok {
+		return nil, errNestedTx
+	}
+	return d.begin(ctx, opts)
+}
+
+// begin はトランザクションを開始し、コンテキストにテナントがある場合はそのトランザクションに設定する
+func (d *tenantDB) begin(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error) {
+	tx, err := d.db.BeginTx(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	if t, ok := ctx.Value(tenantKey{}).(tenant); ok {
+		if err := t.apply(ctx, tx); err != nil {
+			_ = tx.Rollback()
+			return nil, err
+		}
+	}
+	return tx, nil
+}
+
+// tenantRows はtenantDB.QueryContextの結果
+// テナントを設定するために開始したトランザクションは、結果を読み終えるか閉じた時点で終了する
+type tenantRows struct {
+	*sql.Rows
+	tx  *sql.Tx
+	err error
+}
+
+func (r *tenantRows) Next() bool {
+	if r.Rows.Next() {
+		return true
+	}
+	r.end()
+	return false
+}
+
+// Err は結果の読み取りのエラーか、トランザクションのコミットのエラーを返す
+func (r *tenantRows) Err() error {
+	if err := r.Rows.Err(); err != nil {
+		return err
+	}
+	return r.err
+}
+
+func (r *tenantRows) Close() error {
+	err := r.Rows.Close()
+	r.end()
+	if err != nil {
+		return err
+	}
+	return r.err
+}
+
+// end は結果を閉じてトランザクションを終了する（読み取りに失敗した場合はロールバックする）
+func (r *tenantRows) end() {
+	if r.tx == nil {
+		return
+	}
+	tx := r.tx
+	r.tx = nil
+
+	_ = r.Rows.Close()
+	if r.Rows.Err() != nil {
+		_ = tx.Rollback()
+		return
+	}
+	if err := tx.Commit(); err != nil {
+		r.err = fmt.Errorf("failed to commit transaction: %w", err)
+	}
+}
+
+// tenantRow はtenantDB.QueryRowContextの結果
+// テナントを設定するために開始したトランザクションはScanで終了する
+type tenantRow struct {
+	*sql.Row
+	tx  *sql.Tx
+	err error
+}
+
+func (r *tenantRow) Scan(dest ...any) error {
+	if r.err != nil {
+		return r.err
+	}
+	err := r.Row.Scan(dest...)
+	if r.tx == nil {
+		return err
+	}
+	tx := r.tx
+	r.tx = nil
+
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		_ = tx.Rollback()
+		return err
+	}
+	if commitErr := tx.Commit(); commitErr != nil {
+		return fmt.Errorf("failed to commit transaction: %w", commitErr)
+	}
+	return err
+}
+
+func (r *tenantRow) Err() error {
+	if r.err != nil {
+		return r.err
+	}
+	return r.Row.Err()
+}
+
+// Tenancy は行レベルセキュリティのテナント（認証したユーザー）をコンテキストに設定する
+// テナントはリポジトリが開始するトランザクションごとにset_config(..., true)で設定するため、
+// リクエストの間は接続を保持せず、トランザクションの終了後に接続へテナントが残ることもない
+type Tenancy struct{}
+
+// NewTenancy は新しいTenancyを作成する
+func NewTenancy() *Tenancy {
+	return &Tenancy{}
+}
+
+// Scope はuserIDをテナントとして設定したコンテキストを返す
+func (t *Tenancy) Scope(ctx context.Context, userID string) context.Context {
+	return context.WithValue(ctx, tenantKey{}, tenant{userID: userID})
+}
+
+// BypassTenancy は行レベルセキュリティの制限を外してクエリを実行するコンテキストを返す
+// 行レベルセキュリティを使う場合に、バックグラウンドジョブと認証前のリクエストのコンテキストに設定する
+// （Tenancy.Scopeでテナントを設定したコンテキストではテナントが優先される）
+func BypassTenancy(ctx context.Context) context.Context {
+	return context.WithValue(ctx, tenantKey{}, tenant{bypass: true})
+}
+
+// CheckRowLevelSecurity は接続しているロールに行レベルセキュリティが適用されることを確認する
+// スーパーユーザーとBYPASSRLSを持つロールにはポリシーが適用されない
+func CheckRowLevelSecurity(ctx context.Context, db *sql.DB) error {
+	var bypass bool
+	err := db.QueryRowContext(ctx, `SELECT rolsuper OR rolbypassrls FROM pg_roles WHERE rolname = current_user`).Scan(&bypass)
+	if err != nil {
+		return fmt.Errorf("failed to check database role: %w", err)
+	}
+	if bypass {
+		return fmt.Errorf("database role bypasses row level security (use a role without SUPERUSER and BYPASSRLS)")
+	}
+	return nil
+}
@@ -0,0 +1,193 @@
+package persistence
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"io"
+	"log/slog"
+	"testing"
+)
+
+// openRowLevelSecurityDB はマイグレーションを適用した一時的なスキーマに、行レベルセキュリティが適用されるロールで接続する
+// 返す接続設定はそのロールで接続する設定
+func openRowLevelSecurityDB(t *testing.T) (*sql.DB, DBConfig) {
+	t.Helper()
+	admin, dsn := openTestDB(t)
+	ctx := context.Background()
+
+	m := newTestMigrator(t, admin)
+	if err := m.Up(ctx); err != nil {
+		t.Fatalf("Up() error = %v", err)
+	}
+
+	suffix := make([]byte, 6)
+	if _, err := rand.Read(suffix); err != nil {
+		t.Fatalf("failed to generate role name: %v", err)
+	}
+	role := "test_rls_" + hex.EncodeToString(suffix)
+	var schema string
+	if err := admin.QueryRowContext(ctx, `SELECT current_schema()`).Scan(&schema); err != nil {
+		t.Fatalf("failed to read schema: %v", err)
+	}
+	for _, stmt := range []string{
+		`CREATE ROLE ` + role + ` NOLOGIN NOSUPERUSER NOBYPASSRLS`,
+		`GRANT ` + role + ` TO CURRENT_USER`,
+		`GRANT USAGE ON SCHEMA ` + schema + ` TO ` + role,
+		`GRANT ALL ON ALL TABLES IN SCHEMA ` + schema + ` TO ` + role,
+		`GRANT ALL ON ALL SEQUENCES IN SCHEMA ` + schema + ` TO ` + role,
+	} {
+		if _, err := admin.ExecContext(ctx, stmt); err != nil {
+			t.Fatalf("failed to set up role: %v", err)
+		}
+	}
+	t.Cleanup(func() {
+		for _, stmt := range []string{`DROP OWNED BY ` + role, `DROP ROLE ` + role} {
+			if _, err := admin.Exec(stmt); err != nil {
+				t.Errorf("failed to drop role: %v", err)
+			}
+		}
+	})
+
+	cfg, err := ParseDatabaseURL(dsn)
+	if err != nil {
+		t.Fatalf("ParseDatabaseURL() error = %v", err)
+	}
+	cfg.Options["options"] = "-c role=" + role
+
+	db := openConfig(t, *cfg)
+	if err := CheckRowLevelSecurity(ctx, db); err != nil {
+		t.Fatalf("CheckRowLevelSecurity() error = %v", err)
+	}
+	return db, *cfg
+}
+
+func openConfig(t *testing.T, cfg DBConfig) *sql.DB {
+	t.Helper()
+	db, err := sql.Open("postgres", cfg.DSN())
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	// テナントの設定が接続に残っていないことを確認するため、すべてのクエリを同じ接続で実行する
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func countProjects(t *testing.T, ctx context.Context, db *tenantDB) int {
+	t.Helper()
+	var n int
+	if err := db.QueryRowContext(ctx, `SELECT count(*) FROM project`).Scan(&n); err != nil {
+		t.Fatalf("failed to count projects: %v", err)
+	}
+	return n
+}
+
+func TestTenancyRowLevelSecurity(t *testing.T) {
+	sqlDB, _ := openRowLevelSecurityDB(t)
+	db := newTenantDB(sqlDB)
+	tenancy := NewTenancy()
+	bypass := BypassTenancy(context.Background())
+
+	var user1, user2, project2 string
+	err := db.QueryRowContext(bypass, `
+		WITH u AS (INSERT INTO users (email) VALUES ('user1@example.com'), ('user2@example.com') RETURNING id, email),
+		p AS (INSERT INTO project (user_id, title) SELECT id, email FROM u RETURNING id, user_id)
+		SELECT (SELECT id FROM u WHERE email = 'user1@example.com'), u.id, p.id
+		FROM u JOIN p ON p.user_id = u.id WHERE u.email = 'user2@example.com'`).Scan(&user1, &user2, &project2)
+	if err != nil {
+		t.Fatalf("failed to insert projects: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		ctx  context.Context
+		want int
+	}{
+		// テナントも制限の解除も設定していない接続ではすべての行が拒否される
+		{name: "no tenant", ctx: context.Background(), want: 0},
+		{name: "bypass", ctx: bypass, want: 2},
+		{name: "scope", ctx: tenancy.Scope(context.Background(), user1), want: 1},
+		// テナントを設定したコンテキストでは制限の解除よりテナントが優先される
+		{name: "scope over bypass", ctx: tenancy.Scope(bypass, user1), want: 1},
+		// ユーザーIDが空でも制限は外れない
+		{name: "empty user", ctx: tenancy.Scope(context.Background(), ""), want: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := countProjects(t, tt.ctx, db); got != tt.want {
+				t.Errorf("projects = %d, want %d", got, tt.want)
+			}
+		})
+	}
+
+	scoped := tenancy.Scope(context.Background(), user1)
+	rows, err := db.QueryContext(scoped, `SELECT user_id FROM project`)
+	if err != nil {
+		t.Fatalf("QueryContext() error = %v", err)
+	}
+	var owners []string
+	for rows.Next() {
+		var owner string
+		if err := rows.Scan(&owner); err != nil {
+			t.Fatalf("failed to scan project: %v", err)
+		}
+		owners = append(owners, owner)
+	}
+	if err := rows.Err(); err != nil {
+		t.Fatalf("rows.Err() error = %v", err)
+	}
+	if len(owners) != 1 || owners[0] != user1 {
+		t.Errorf("project owners = %v, want [%s]", owners, user1)
+	}
+
+	// 他のユーザーのプロジェクトは更新できない
+	result, err := db.ExecContext(scoped, `UPDATE project SET title = 'changed' WHERE id = $1`, project2)
+	if err != nil {
+		t.Fatalf("ExecContext() error = %v", err)
+	}
+	if affected, _ := result.RowsAffected(); affected != 0 {
+		t.Errorf("updated projects of other user = %d, want 0", affected)
+	}
+
+	// Transactor.WithinTxのトランザクションにもテナントが設定される
+	transactor := NewTransactor(sqlDB, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	err = transactor.WithinTx(scoped, func(ctx context.Context) error {
+		if got := countProjects(t, ctx, db); got != 1 {
+			t.Errorf("projects in WithinTx = %d, want 1", got)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("WithinTx() error = %v", err)
+	}
+
+	// トランザクションの終了後に接続へテナントが残っていない
+	var userID, bypassSet string
+	err = sqlDB.QueryRow(`SELECT COALESCE(current_setting('`+tenantSetting+`', true), ''), COALESCE(current_setting('`+bypassSetting+`', true), '')`).Scan(&userID, &bypassSet)
+	if err != nil {
+		t.Fatalf("failed to read settings: %v", err)
+	}
+	if userID != "" || bypassSet != "" {
+		t.Errorf("settings after transactions = %q, %q, want empty", userID, bypassSet)
+	}
+	if got := countProjects(t, context.Background(), db); got != 0 {
+		t.Errorf("projects after transactions = %d, want 0", got)
+	}
+}
+
+func TestRowLevelSecurityBypassConnection(t *testing.T) {
+	_, cfg := openRowLevelSecurityDB(t)
+	db := newTenantDB(openConfig(t, cfg.WithRowLevelSecurityBypass()))
+
+	if _, err := db.ExecContext(context.Background(), `
+		WITH u AS (INSERT INTO users (email) VALUES ('user@example.com') RETURNING id)
+		INSERT INTO project (user_id, title) SELECT id, 'Project' FROM u`); err != nil {
+		t.Fatalf("failed to insert project: %v", err)
+	}
+	// 行レベルセキュリティを使わない場合は、コンテキストにテナントがなくてもすべての行を参照できる
+	if got := countProjects(t, context.Background(), db); got != 1 {
+		t.Errorf("projects = %d, want 1", got)
+	}
+}
@@ -6,21 +6,25 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"time"
 
 	"github.com/sikigasa/github-task-controller/backend/internal/domain/model"
 	"github.com/sikigasa/github-task-controller/backend/internal/domain/repository"
 )
 
+// todoColumns はTODO検索時に取得するカラム（scanTodoの引数順と一致させる）
+const todoColumns = `id, COALESCE(user_id::text, ''), title, description, completed, created_at, updated_at, deleted_at`
+
 // TodoRepositoryImpl はTodoRepositoryの実装
 type TodoRepositoryImpl struct {
-	db     *sql.DB
+	db     *tenantDB
 	logger *slog.Logger
 }
 
 // NewTodoRepository は新しいTodoRepositoryImplを作成する
 func NewTodoRepository(db *sql.DB, logger *slog.Logger) repository.TodoRepository {
 	return &TodoRepositoryImpl{
-		db:     db,
+		db:     newTenantDB(db),
 		logger: logger,
 	}
 }
@@ -28,12 +32,13 @@ func NewTodoRepository(db *sql.DB, logger *slog.Logger) repository.TodoRepositor
 // Create は新しいTODOを作成する
 func (r *TodoRepositoryImpl) Create(ctx context.Context, todo *model.Todo) error {
 	query := `
-		INSERT INTO todos (id, title, description, completed, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6)
+		INSERT INTO todos (id, user_id, title, description, completed, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
 	`
 
 	_, err := r.db.ExecContext(ctx, query,
 		todo.ID,
+		todo.UserID,
 		todo.Title,
 		todo.Description,
 		todo.Completed,
@@ -51,20 +56,12 @@ func (r *TodoRepositoryImpl) Create(ctx context.Context, todo *model.Todo) error
 // FindByID はIDでTODOを取得する
 func (r *TodoRepositoryImpl) FindByID(ctx context.Context, id string) (*model.Todo, error) {
 	query := `
-		SELECT id, title, description, completed, created_at, updated_at
+		SELECT ` + todoColumns + `
 		FROM todos
-		WHERE id = $1
+		WHERE id = $1 AND deleted_at IS NULL
 	`
 
-	var todo model.Todo
-	err := r.db.QueryRowContext(ctx, query, id).Scan(
-		&todo.ID,
-		&todo.Title,
-		&todo.Description,
-		&todo.Completed,
-		&todo.CreatedAt,
-		&todo.UpdatedAt,
-	)
+	todo, err := scanTodo(r.db.QueryRowContext(ctx, query, id))
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, model.ErrNotFound
@@ -73,47 +70,33 @@ func (r *TodoRepositoryImpl) FindByID(ctx context.Context, id string) (*model.To
 		return nil, fmt.Errorf("failed to query todo: %w", err)
 	}
 
-	return &todo, nil
+	return todo, nil
 }
 
-// FindAll はすべてのTODOを取得する
-func (r *TodoRepositoryImpl) FindAll(ctx context.Context) ([]*model.Todo, error) {
+// todoSortColumns はTODO一覧でソートに使用できるフィールドとカラムの対応
+var todoSortColumns = map[string]string{
+	"title":      "title",
+	"completed":  "completed",
+	"created_at": "created_at",
+	"updated_at": "updated_at",
+}
+
+// FindByUserID はユーザーのすべてのTODOを取得する
+func (r *TodoRepositoryImpl) FindByUserID(ctx context.Context, userID string, opts model.ListOptions) ([]*model.Todo, error) {
 	query := `
-		SELECT id, title, description, completed, created_at, updated_at
+		SELECT ` + todoColumns + `
 		FROM todos
-		ORDER BY created_at DESC
-	`
+		WHERE user_id = $1 AND deleted_at IS NULL
+	` + orderByClause(opts.Sort, todoSortColumns, "created_at DESC")
 
-	rows, err := r.db.QueryContext(ctx, query)
+	rows, err := r.db.QueryContext(ctx, query, userID)
 	if err != nil {
 		r.logger.ErrorContext(ctx, "failed to query todos", "error", err)
 		return nil, fmt.Errorf("failed to query todos: %w", err)
 	}
 	defer rows.Close()
 
-	var todos []*model.Todo
-	for rows.Next() {
-		var todo model.Todo
-		if err := rows.Scan(
-			&todo.ID,
-			&todo.Title,
-			&todo.Description,
-			&todo.Completed,
-			&todo.CreatedAt,
-			&todo.UpdatedAt,
-		); err != nil {
-			r.logger.ErrorContext(ctx, "failed to scan todo", "error", err)
-			return nil, fmt.Errorf("failed to scan todo: %w", err)
-		}
-		todos = append(todos, &todo)
-	}
-
-	if err := rows.Err(); err != nil {
-		r.logger.ErrorContext(ctx, "rows error", "error", err)
-		return nil, fmt.Errorf("rows error: %w", err)
-	}
-
-	return todos, nil
+	return r.scanTodos(ctx, rows)
 }
 
 // Update はTODOを更新する
@@ -121,7 +104,7 @@ func (r *TodoRepositoryImpl) Update(ctx context.Context, todo *model.Todo) error
 	query := `
 		UPDATE todos
 		SET title = $2, description = $3, completed = $4, updated_at = $5
-		WHERE id = $1
+		WHERE id = $1 AND deleted_at IS NULL
 	`
 
 	result, err := r.db.ExecContext(ctx, query,
@@ -149,11 +132,11 @@ func (r *TodoRepositoryImpl) Update(ctx context.Context, todo *model.Todo) error
 	return nil
 }
 
-// Delete はTODOを削除する
-func (r *TodoRepositoryImpl) Delete(ctx context.Context, id string) error {
-	query := `DELETE FROM todos WHERE id = $1`
+// Delete はTODOをゴミ箱に移す
+func (r *TodoRepositoryImpl) Delete(ctx context.Context, id string, deletedAt time.Time) error {
+	query := `UPDATE todos SET deleted_at = $2 WHERE id = $1 AND deleted_at IS NULL`
 
-	result, err := r.db.ExecContext(ctx, query, id)
+	result, err := r.db.ExecContext(ctx, query, id, deletedAt)
 	if err != nil {
 		r.logger.ErrorContext(ctx, "failed to delete todo", "id", id, "error", err)
 		return fmt.Errorf("failed to delete todo: %w", err)
@@ -171,3 +154,129 @@ func (r *TodoRepositoryImpl) Delete(ctx context.Context, id string) error {
 
 	return nil
 }
+
+// FindDeletedByID はゴミ箱のTODOをIDで取得する
+func (r *TodoRepositoryImpl) FindDeletedByID(ctx context.Context, id string) (*model.Todo, error) {
+	query := `
+		SELECT ` + todoColumns + `
+		FROM todos
+		WHERE id = $1 AND deleted_at IS NOT NULL
+	`
+
+	todo, err := scanTodo(r.db.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, model.ErrNotFound
+		}
+		r.logger.ErrorContext(ctx, "failed to query deleted todo", "id", id, "error", err)
+		return nil, fmt.Errorf("failed to query deleted todo: %w", err)
+	}
+
+	return todo, nil
+}
+
+// FindDeletedByUserID はユーザーのゴミ箱のTODOを削除日時の新しい順に取得する
+func (r *TodoRepositoryImpl) FindDeletedByUserID(ctx context.Context, userID string) ([]*model.Todo, error) {
+	query := `
+		SELECT ` + todoColumns + `
+		FROM todos
+		WHERE user_id = $1 AND deleted_at IS NOT NULL
+		ORDER BY deleted_at DESC, id ASC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		r.logger.ErrorContext(ctx, "failed to query deleted todos", "error", err)
+		return nil, fmt.Errorf("failed to query deleted todos: %w", err)
+	}
+	defer rows.Close()
+
+	return r.scanTodos(ctx, rows)
+}
+
+// Restore はゴミ箱のTODOを元に戻す
+func (r *TodoRepositoryImpl) Restore(ctx context.Context, todo *model.Todo) error {
+	query := `UPDATE todos SET deleted_at = NULL, updated_at = $2 WHERE id = $1 AND deleted_at IS NOT NULL`
+
+	result, err := r.db.ExecContext(ctx, query, todo.ID, todo.UpdatedAt)
+	if err != nil {
+		r.logger.ErrorContext(ctx, "failed to restore todo", "id", todo.ID, "error", err)
+		return fmt.Errorf("failed to restore todo: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		r.logger.ErrorContext(ctx, "failed to get rows affected", "error", err)
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return model.ErrNotFound
+	}
+
+	return nil
+}
+
+// PurgeDeleted はbefore以前にゴミ箱に移したTODOを完全に削除する
+func (r *TodoRepositoryImpl) PurgeDeleted(ctx context.Context, before time.Time) (int64, error) {
+	query := `DELETE FROM todos WHERE deleted_at < $1`
+
+	result, err := r.db.ExecContext(ctx, query, before)
+	if err != nil {
+		r.logger.ErrorContext(ctx, "failed to purge deleted todos", "error", err)
+		return 0, fmt.Errorf("failed to purge deleted todos: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		r.logger.ErrorContext(ctx, "failed to get rows affected", "error", err)
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return rowsAffected, nil
+}
+
+// scanTodos はTODO検索結果の行をスキャンする
+func (r *TodoRepositoryImpl) scanTodos(ctx context.Context, rows *tenantRows) ([]*model.Todo, error) {
+	var todos []*model.Todo
+	for rows.Next() {
+		todo, err := scanTodo(rows)
+		if err != nil {
+			r.logger.ErrorContext(ctx, "failed to scan todo", "error", err)
+			return nil, fmt.Errorf("failed to scan todo: %w", err)
+		}
+		todos = append(todos, todo)
+	}
+
+	if err := rows.Err(); err != nil {
+		r.logger.ErrorContext(ctx, "rows error", "error", err)
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+
+	return todos, nil
+}
+
+// scanTodo はtodoColumnsの順で1行をスキャンする
+func scanTodo(row rowScanner) (*model.Todo, error) {
+	var todo model.Todo
+	var deletedAt sql.NullTime
+	err := row.Scan(
+		&todo.ID,
+		&todo.UserID,
+		&todo.Title,
+		&todo.Description,
+		&todo.Completed,
+		&todo.CreatedAt,
+		&todo.UpdatedAt,
+		&deletedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if deletedAt.Valid {
+		todo.DeletedAt = &deletedAt.Time
+	}
+
+	return &todo, nil
+}
@@ -0,0 +1,55 @@
+package persistence
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/sikigasa/github-task-controller/backend/internal/domain/repository"
+)
+
+// txKey はTransactor.WithinTxのトランザクションをコンテキストに保持するためのキー
+type txKey struct{}
+
+// errNestedTx はWithinTxのトランザクション内でリポジトリが独自のトランザクションを開始しようとした場合のエラー
+var errNestedTx = errors.New("cannot begin a transaction inside WithinTx")
+
+type transactor struct {
+	db     *tenantDB
+	logger *slog.Logger
+}
+
+// NewTransactor は新しいTransactorを作成する
+func NewTransactor(db *sql.DB, logger *slog.Logger) repository.Transactor {
+	return &transactor{
+		db:     newTenantDB(db),
+		logger: logger,
+	}
+}
+
+func (t *transactor) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	// 既にトランザクション内の場合はそのトランザクションに含める
+	if _, ok := ctx.Value(txKey{}).(*sql.Tx); ok {
+		return fn(ctx)
+	}
+
+	// コンテキストにテナントがある場合はトランザクションの間だけ設定するため、行レベルセキュリティも適用される
+	tx, err := t.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback() // コミット済みの場合は何もしない
+
+	if err := fn(context.WithValue(ctx, txKey{}, tx)); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		t.logger.ErrorContext(ctx, "failed to commit transaction", "error", err)
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
@@ -3,6 +3,7 @@ package persistence
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"log/slog"
 	"time"
@@ -12,14 +13,14 @@ import (
 )
 
 type userRepository struct {
-	db     *sql.DB
+	db     *tenantDB
 	logger *slog.Logger
 }
 
 // NewUserRepository は新しいUserRepositoryを作成する
 func NewUserRepository(db *sql.DB, logger *slog.Logger) repository.UserRepository {
 	return &userRepository{
-		db:     db,
+		db:     newTenantDB(db),
 		logger: logger,
 	}
 }
@@ -34,6 +35,9 @@ func (r *userRepository) Create(ctx context.Context, user *model.User) error {
 		user.ID, user.Email, user.Name, user.ImageURL,
 		user.CreatedAt, user.UpdatedAt,
 	)
+	if isEmailViolation(err) {
+		return fmt.Errorf("%w: %s", repository.ErrEmailTaken, user.Email)
+	}
 	if err != nil {
 		r.logger.ErrorContext(ctx, "failed to create user", "error", err)
 		return fmt.Errorf("failed to create user: %w", err)
@@ -43,62 +47,107 @@ func (r *userRepository) Create(ctx context.Context, user *model.User) error {
 	return nil
 }
 
-func (r *userRepository) FindByID(ctx context.Context, id string) (*model.User, error) {
-	query := `
-		SELECT id, email, name, image_url, created_at, updated_at
-		FROM users
-		WHERE id = $1
-	`
+// isEmailViolation はerrがメールアドレスの一意制約（完全一致・大文字小文字を区別しない一致）の違反かどうかを返す
+func isEmailViolation(err error) bool {
+	return isUniqueViolation(err, "users_email_key") || isUniqueViolation(err, "users_email_lower_unique")
+}
+
+// userColumns はusersテーブルから読み取る列（scanUserの順序と一致させる）
+const userColumns = `id, email, name, image_url, deactivated_at, created_at, updated_at`
 
+// scanUser はuserColumnsの順に読み取ったユーザーを返す
+func scanUser(row rowScanner) (*model.User, error) {
 	var user model.User
-	err := r.db.QueryRowContext(ctx, query, id).Scan(
+	var deactivatedAt sql.NullTime
+	if err := row.Scan(
 		&user.ID, &user.Email, &user.Name, &user.ImageURL,
-		&user.CreatedAt, &user.UpdatedAt,
-	)
-	if err == sql.ErrNoRows {
-		return nil, fmt.Errorf("user not found: %s", id)
+		&deactivatedAt, &user.CreatedAt, &user.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+	if deactivatedAt.Valid {
+		user.DeactivatedAt = &deactivatedAt.Time
+	}
+	return &user, nil
+}
+
+func (r *userRepository) FindByID(ctx context.Context, id string) (*model.User, error) {
+	query := `SELECT ` + userColumns + ` FROM users WHERE id = $1`
+
+	user, err := scanUser(r.db.QueryRowContext(ctx, query, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("%w: %s", repository.ErrUserNotFound, id)
 	}
 	if err != nil {
 		r.logger.ErrorContext(ctx, "failed to find user by id", "error", err, "id", id)
 		return nil, fmt.Errorf("failed to find user by id: %w", err)
 	}
 
-	return &user, nil
+	return user, nil
 }
 
 func (r *userRepository) FindByEmail(ctx context.Context, email string) (*model.User, error) {
-	query := `
-		SELECT id, email, name, image_url, created_at, updated_at
-		FROM users
-		WHERE email = $1
-	`
+	// 一意制約を付ける前の大文字・小文字違いの重複が残っている場合は、先に作成されたユーザーを返す
+	query := `SELECT ` + userColumns + ` FROM users WHERE lower(email) = lower($1) ORDER BY created_at, id LIMIT 1`
 
-	var user model.User
-	err := r.db.QueryRowContext(ctx, query, email).Scan(
-		&user.ID, &user.Email, &user.Name, &user.ImageURL,
-		&user.CreatedAt, &user.UpdatedAt,
-	)
-	if err == sql.ErrNoRows {
-		return nil, fmt.Errorf("user not found: %s", email)
+	user, err := scanUser(r.db.QueryRowContext(ctx, query, email))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("%w: %s", repository.ErrUserNotFound, email)
 	}
 	if err != nil {
 		r.logger.ErrorContext(ctx, "failed to find user by email", "error", err, "email", email)
 		return nil, fmt.Errorf("failed to find user by email: %w", err)
 	}
 
-	return &user, nil
+	return user, nil
+}
+
+func (r *userRepository) List(ctx context.Context, email string, offset, limit int) ([]*model.User, int, error) {
+	// emailが空の場合は全件を対象にする
+	where := `WHERE $1 = '' OR email = $1`
+
+	var total int
+	if err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM users `+where, email).Scan(&total); err != nil {
+		r.logger.ErrorContext(ctx, "failed to count users", "error", err)
+		return nil, 0, fmt.Errorf("failed to count users: %w", err)
+	}
+
+	query := `SELECT ` + userColumns + ` FROM users ` + where + ` ORDER BY created_at, id OFFSET $2 LIMIT $3`
+	rows, err := r.db.QueryContext(ctx, query, email, offset, limit)
+	if err != nil {
+		r.logger.ErrorContext(ctx, "failed to list users", "error", err)
+		return nil, 0, fmt.Errorf("failed to list users: %w", err)
+	}
+	defer rows.Close()
+
+	users := []*model.User{}
+	for rows.Next() {
+		user, err := scanUser(rows)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to scan user: %w", err)
+		}
+		users = append(users, user)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("failed to iterate users: %w", err)
+	}
+
+	return users, total, nil
 }
 
 func (r *userRepository) Update(ctx context.Context, user *model.User) error {
 	query := `
 		UPDATE users
-		SET email = $1, name = $2, image_url = $3, updated_at = $4
-		WHERE id = $5
+		SET email = $1, name = $2, image_url = $3, deactivated_at = $4, updated_at = $5
+		WHERE id = $6
 	`
 
 	result, err := r.db.ExecContext(ctx, query,
-		user.Email, user.Name, user.ImageURL, time.Now(), user.ID,
+		user.Email, user.Name, user.ImageURL, user.DeactivatedAt, time.Now(), user.ID,
 	)
+	if isEmailViolation(err) {
+		return fmt.Errorf("%w: %s", repository.ErrEmailTaken, user.Email)
+	}
 	if err != nil {
 		r.logger.ErrorContext(ctx, "failed to update user", "error", err, "user_id", user.ID)
 		return fmt.Errorf("failed to update user: %w", err)
@@ -109,7 +158,7 @@ func (r *userRepository) Update(ctx context.Context, user *model.User) error {
 		return fmt.Errorf("failed to get rows affected: %w", err)
 	}
 	if rowsAffected == 0 {
-		return fmt.Errorf("user not found: %s", user.ID)
+		return fmt.Errorf("%w: %s", repository.ErrUserNotFound, user.ID)
 	}
 
 	r.logger.InfoContext(ctx, "user updated", "user_id", user.ID)
@@ -130,7 +179,7 @@ func (r *userRepository) Delete(ctx context.Context, id string) error {
 		return fmt.Errorf("failed to get rows affected: %w", err)
 	}
 	if rowsAffected == 0 {
-		return fmt.Errorf("user not found: %s", id)
+		return fmt.Errorf("%w: %s", repository.ErrUserNotFound, id)
 	}
 
 	r.logger.InfoContext(ctx, "user deleted", "user_id", id)
@@ -0,0 +1,120 @@
+package persistence
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/sikigasa/github-task-controller/backend/internal/domain/model"
+	"github.com/sikigasa/github-task-controller/backend/internal/domain/repository"
+)
+
+type userSessionRepository struct {
+	db     *tenantDB
+	logger *slog.Logger
+}
+
+// NewUserSessionRepository は新しいUserSessionRepositoryを作成する
+func NewUserSessionRepository(db *sql.DB, logger *slog.Logger) repository.UserSessionRepository {
+	return &userSessionRepository{
+		db:     newTenantDB(db),
+		logger: logger,
+	}
+}
+
+func (r *userSessionRepository) Create(ctx context.Context, session *model.UserSession) error {
+	query := `
+		INSERT INTO user_session (id, user_id, user_agent, ip_address, location, created_at, last_seen_at, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`
+
+	_, err := r.db.ExecContext(ctx, query,
+		session.ID, session.UserID, session.UserAgent, session.IPAddress, session.Location,
+		session.CreatedAt, session.LastSeenAt, session.ExpiresAt,
+	)
+	if err != nil {
+		r.logger.ErrorContext(ctx, "failed to create user session", "error", err, "user_id", session.UserID)
+		return fmt.Errorf("failed to create user session: %w", err)
+	}
+
+	return nil
+}
+
+func (r *userSessionRepository) FindActiveByUserID(ctx context.Context, userID string, now time.Time) ([]*model.UserSession, error) {
+	query := `
+		SELECT id, user_id, user_agent, ip_address, location, created_at, last_seen_at, expires_at
+		FROM user_session
+		WHERE user_id = $1 AND expires_at > $2
+		ORDER BY last_seen_at DESC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, userID, now)
+	if err != nil {
+		r.logger.ErrorContext(ctx, "failed to find user sessions", "error", err, "user_id", userID)
+		return nil, fmt.Errorf("failed to find user sessions: %w", err)
+	}
+	defer rows.Close()
+
+	sessions := []*model.UserSession{}
+	for rows.Next() {
+		var s model.UserSession
+		var location sql.NullString
+		if err := rows.Scan(
+			&s.ID, &s.UserID, &s.UserAgent, &s.IPAddress, &location,
+			&s.CreatedAt, &s.LastSeenAt, &s.ExpiresAt,
+		); err != nil {
+			r.logger.ErrorContext(ctx, "failed to scan user session", "error", err)
+			return nil, fmt.Errorf("failed to scan user session: %w", err)
+		}
+		if location.Valid {
+			s.Location = &location.String
+		}
+		sessions = append(sessions, &s)
+	}
+
+	if err := rows.Err(); err != nil {
+		r.logger.ErrorContext(ctx, "error iterating user sessions", "error", err)
+		return nil, fmt.Errorf("error iterating user sessions: %w", err)
+	}
+
+	return sessions, nil
+}
+
+func (r *userSessionRepository) Touch(ctx context.Context, id string, seenAt, staleBefore time.Time) error {
+	query := `UPDATE user_session SET last_seen_at = $1 WHERE id = $2 AND last_seen_at < $3`
+
+	if _, err := r.db.ExecContext(ctx, query, seenAt, id, staleBefore); err != nil {
+		r.logger.ErrorContext(ctx, "failed to touch user session", "error", err, "session_id", id)
+		return fmt.Errorf("failed to touch user session: %w", err)
+	}
+
+	return nil
+}
+
+func (r *userSessionRepository) Delete(ctx context.Context, id string) error {
+	query := `DELETE FROM user_session WHERE id = $1`
+
+	if _, err := r.db.ExecContext(ctx, query, id); err != nil {
+		r.logger.ErrorContext(ctx, "failed to delete user session", "error", err, "session_id", id)
+		return fmt.Errorf("failed to delete user session: %w", err)
+	}
+
+	return nil
+}
+
+func (r *userSessionRepository) DeleteByUserID(ctx context.Context, userID string) error {
+	// ログインセッションの記録に紐付かないサーバー側のCookieのセッションも合わせて削除する
+	query := `
+		WITH auth AS (DELETE FROM auth_session WHERE user_id = $1)
+		DELETE FROM user_session WHERE user_id = $1
+	`
+
+	if _, err := r.db.ExecContext(ctx, query, userID); err != nil {
+		r.logger.ErrorContext(ctx, "failed to delete user sessions", "error", err, "user_id", userID)
+		return fmt.Errorf("failed to delete user sessions: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,202 @@
+package persistence
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/sikigasa/github-task-controller/backend/internal/domain/model"
+	"github.com/sikigasa/github-task-controller/backend/internal/domain/repository"
+)
+
+// webhookDeliveryColumns はWebhookの配信の検索時に取得するカラム（scanWebhookDeliveryの引数順と一致させる）
+const webhookDeliveryColumns = `id, event, payload, status, attempts, last_error, next_attempt_at, processed_at, created_at, updated_at`
+
+type webhookDeliveryRepository struct {
+	db     *tenantDB
+	logger *slog.Logger
+}
+
+// NewWebhookDeliveryRepository は新しいWebhookDeliveryRepositoryを作成する
+func NewWebhookDeliveryRepository(db *sql.DB, logger *slog.Logger) repository.WebhookDeliveryRepository {
+	return &webhookDeliveryRepository{
+		db:     newTenantDB(db),
+		logger: logger,
+	}
+}
+
+func (r *webhookDeliveryRepository) Create(ctx context.Context, delivery *model.WebhookDelivery) (bool, error) {
+	query := `
+		INSERT INTO webhook_delivery (id, event, payload, status, attempts, next_attempt_at, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		ON CONFLICT (id) DO NOTHING
+	`
+
+	result, err := r.db.ExecContext(ctx, query,
+		delivery.ID, delivery.Event, []byte(delivery.Payload), delivery.Status, delivery.Attempts,
+		delivery.NextAttemptAt, delivery.CreatedAt, delivery.UpdatedAt,
+	)
+	if err != nil {
+		r.logger.ErrorContext(ctx, "failed to create webhook delivery", "error", err, "delivery_id", delivery.ID)
+		return false, fmt.Errorf("failed to create webhook delivery: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return rowsAffected > 0, nil
+}
+
+func (r *webhookDeliveryRepository) FindByID(ctx context.Context, id string) (*model.WebhookDelivery, error) {
+	query := `SELECT ` + webhookDeliveryColumns + ` FROM webhook_delivery WHERE id = $1`
+
+	delivery, err := scanWebhookDelivery(r.db.QueryRowContext(ctx, query, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("webhook delivery not found: %s: %w", id, model.ErrNotFound)
+	}
+	if err != nil {
+		r.logger.ErrorContext(ctx, "failed to find webhook delivery", "error", err, "delivery_id", id)
+		return nil, fmt.Errorf("failed to find webhook delivery: %w", err)
+	}
+
+	return delivery, nil
+}
+
+func (r *webhookDeliveryRepository) FindByStatus(ctx context.Context, status model.WebhookDeliveryStatus, limit int) ([]*model.WebhookDelivery, error) {
+	query := `
+		SELECT ` + webhookDeliveryColumns + `
+		FROM webhook_delivery
+		WHERE status = $1
+		ORDER BY created_at DESC
+		LIMIT $2
+	`
+
+	return r.query(ctx, query, status, limit)
+}
+
+func (r *webhookDeliveryRepository) ClaimDue(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*model.WebhookDelivery, error) {
+	// 複数のインスタンスで同じ配信を処理しないよう、取得と同時に次の処理時刻をlease後にずらす
+	query := `
+		UPDATE webhook_delivery
+		SET next_attempt_at = $2, updated_at = $1
+		WHERE id IN (
+			SELECT id FROM webhook_delivery
+			WHERE status = 'pending' AND next_attempt_at <= $1
+			ORDER BY next_attempt_at
+			LIMIT $3
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING ` + webhookDeliveryColumns
+
+	return r.query(ctx, query, now, now.Add(lease), limit)
+}
+
+func (r *webhookDeliveryRepository) MarkProcessed(ctx context.Context, id string, at time.Time) error {
+	query := `
+		UPDATE webhook_delivery
+		SET status = 'processed', attempts = attempts + 1, last_error = NULL, processed_at = $2, updated_at = $2
+		WHERE id = $1
+	`
+
+	return r.exec(ctx, "mark webhook delivery processed", query, id, at)
+}
+
+func (r *webhookDeliveryRepository) MarkFailed(ctx context.Context, id string, attempts int, lastError string, nextAttemptAt time.Time, dead bool) error {
+	status := model.WebhookDeliveryPending
+	if dead {
+		status = model.WebhookDeliveryDead
+	}
+	query := `
+		UPDATE webhook_delivery
+		SET status = $2, attempts = $3, last_error = $4, next_attempt_at = $5, updated_at = NOW()
+		WHERE id = $1
+	`
+
+	return r.exec(ctx, "mark webhook delivery failed", query, id, status, attempts, lastError, nextAttemptAt)
+}
+
+func (r *webhookDeliveryRepository) Requeue(ctx context.Context, id string, at time.Time) error {
+	query := `
+		UPDATE webhook_delivery
+		SET status = 'pending', attempts = 0, next_attempt_at = $2, processed_at = NULL, updated_at = $2
+		WHERE id = $1
+	`
+
+	return r.exec(ctx, "requeue webhook delivery", query, id, at)
+}
+
+// query は配信を検索するクエリを実行する
+func (r *webhookDeliveryRepository) query(ctx context.Context, query string, args ...any) ([]*model.WebhookDelivery, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		r.logger.ErrorContext(ctx, "failed to find webhook deliveries", "error", err)
+		return nil, fmt.Errorf("failed to find webhook deliveries: %w", err)
+	}
+	defer rows.Close()
+
+	deliveries := []*model.WebhookDelivery{}
+	for rows.Next() {
+		delivery, err := scanWebhookDelivery(rows)
+		if err != nil {
+			r.logger.ErrorContext(ctx, "failed to scan webhook delivery", "error", err)
+			return nil, fmt.Errorf("failed to scan webhook delivery: %w", err)
+		}
+		deliveries = append(deliveries, delivery)
+	}
+
+	if err := rows.Err(); err != nil {
+		r.logger.ErrorContext(ctx, "error iterating webhook deliveries", "error", err)
+		return nil, fmt.Errorf("error iterating webhook deliveries: %w", err)
+	}
+
+	return deliveries, nil
+}
+
+// exec は1件の配信を更新するクエリを実行する（該当がない場合はErrNotFound）
+func (r *webhookDeliveryRepository) exec(ctx context.Context, action, query string, args ...any) error {
+	result, err := r.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		r.logger.ErrorContext(ctx, "failed to "+action, "error", err, "delivery_id", args[0])
+		return fmt.Errorf("failed to %s: %w", action, err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("webhook delivery not found: %v: %w", args[0], model.ErrNotFound)
+	}
+
+	return nil
+}
+
+// scanWebhookDelivery はwebhookDeliveryColumnsの順で1行をスキャンする
+func scanWebhookDelivery(row rowScanner) (*model.WebhookDelivery, error) {
+	var delivery model.WebhookDelivery
+	var payload []byte
+	var lastError sql.NullString
+	var processedAt sql.NullTime
+	err := row.Scan(
+		&delivery.ID, &delivery.Event, &payload, &delivery.Status, &delivery.Attempts,
+		&lastError, &delivery.NextAttemptAt, &processedAt, &delivery.CreatedAt, &delivery.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	delivery.Payload = payload
+	if lastError.Valid {
+		delivery.LastError = &lastError.String
+	}
+	if processedAt.Valid {
+		delivery.ProcessedAt = &processedAt.Time
+	}
+
+	return &delivery, nil
+}
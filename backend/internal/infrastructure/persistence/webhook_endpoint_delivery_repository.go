@@ -0,0 +1,193 @@
+package persistence
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/sikigasa/github-task-controller/backend/internal/domain/model"
+	"github.com/sikigasa/github-task-controller/backend/internal/domain/repository"
+)
+
+// webhookEndpointDeliveryColumns は送信先への配信の検索時に取得するカラム（scanWebhookEndpointDeliveryの引数順と一致させる）
+const webhookEndpointDeliveryColumns = `id, endpoint_id, user_id, event_id, event_type, payload, status, attempts, response_status, last_error, next_attempt_at, delivered_at, created_at, updated_at`
+
+type webhookEndpointDeliveryRepository struct {
+	db     *tenantDB
+	logger *slog.Logger
+}
+
+// NewWebhookEndpointDeliveryRepository は新しいWebhookEndpointDeliveryRepositoryを作成する
+func NewWebhookEndpointDeliveryRepository(db *sql.DB, logger *slog.Logger) repository.WebhookEndpointDeliveryRepository {
+	return &webhookEndpointDeliveryRepository{
+		db:     newTenantDB(db),
+		logger: logger,
+	}
+}
+
+func (r *webhookEndpointDeliveryRepository) Create(ctx context.Context, delivery *model.WebhookEndpointDelivery) error {
+	// アウトボックスから同じイベントが再配信された場合は作成済みの配信を残す
+	query := `
+		INSERT INTO webhook_endpoint_delivery (id, endpoint_id, user_id, event_id, event_type, payload, status, attempts, next_attempt_at, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		ON CONFLICT (endpoint_id, event_id) DO NOTHING
+	`
+
+	_, err := r.db.ExecContext(ctx, query,
+		delivery.ID, delivery.EndpointID, delivery.UserID, delivery.EventID, delivery.EventType, []byte(delivery.Payload),
+		delivery.Status, delivery.Attempts, delivery.NextAttemptAt, delivery.CreatedAt, delivery.UpdatedAt,
+	)
+	if err != nil {
+		r.logger.ErrorContext(ctx, "failed to create webhook endpoint delivery", "error", err, "endpoint_id", delivery.EndpointID, "event_id", delivery.EventID)
+		return fmt.Errorf("failed to create webhook endpoint delivery: %w", err)
+	}
+
+	return nil
+}
+
+func (r *webhookEndpointDeliveryRepository) FindByEndpointID(ctx context.Context, endpointID string, limit int) ([]*model.WebhookEndpointDelivery, error) {
+	query := `
+		SELECT ` + webhookEndpointDeliveryColumns + `
+		FROM webhook_endpoint_delivery
+		WHERE endpoint_id = $1
+		ORDER BY created_at DESC
+		LIMIT $2
+	`
+
+	return r.query(ctx, query, endpointID, limit)
+}
+
+func (r *webhookEndpointDeliveryRepository) ClaimDue(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*model.WebhookEndpointDelivery, error) {
+	// 複数のインスタンスで同じ配信を送信しないよう、取得と同時に次の送信時刻をlease後にずらす
+	// 無効な送信先の配信は送信待ちのまま残し、有効に戻した時に送信する
+	query := `
+		UPDATE webhook_endpoint_delivery
+		SET next_attempt_at = $2, updated_at = $1
+		WHERE id IN (
+			SELECT d.id FROM webhook_endpoint_delivery d
+			JOIN webhook_endpoint e ON e.id = d.endpoint_id
+			WHERE d.status = 'pending' AND d.next_attempt_at <= $1 AND e.enabled
+			ORDER BY d.next_attempt_at
+			LIMIT $3
+			FOR UPDATE OF d SKIP LOCKED
+		)
+		RETURNING ` + webhookEndpointDeliveryColumns
+
+	return r.query(ctx, query, now, now.Add(lease), limit)
+}
+
+func (r *webhookEndpointDeliveryRepository) MarkSucceeded(ctx context.Context, id string, responseStatus int, at time.Time) error {
+	query := `
+		UPDATE webhook_endpoint_delivery
+		SET status = 'succeeded', attempts = attempts + 1, response_status = $2, last_error = NULL, delivered_at = $3, updated_at = $3
+		WHERE id = $1
+	`
+
+	return r.exec(ctx, "mark webhook endpoint delivery succeeded", query, id, responseStatus, at)
+}
+
+func (r *webhookEndpointDeliveryRepository) MarkFailed(ctx context.Context, id string, attempts int, responseStatus *int, lastError string, nextAttemptAt time.Time, dead bool) error {
+	status := model.WebhookEndpointDeliveryPending
+	if dead {
+		status = model.WebhookEndpointDeliveryFailed
+	}
+	query := `
+		UPDATE webhook_endpoint_delivery
+		SET status = $2, attempts = $3, response_status = $4, last_error = $5, next_attempt_at = $6, updated_at = NOW()
+		WHERE id = $1
+	`
+
+	return r.exec(ctx, "mark webhook endpoint delivery failed", query, id, status, attempts, responseStatus, lastError, nextAttemptAt)
+}
+
+func (r *webhookEndpointDeliveryRepository) DeleteFinishedBefore(ctx context.Context, before time.Time) (int64, error) {
+	query := `DELETE FROM webhook_endpoint_delivery WHERE status IN ('succeeded', 'failed') AND created_at < $1`
+
+	result, err := r.db.ExecContext(ctx, query, before)
+	if err != nil {
+		r.logger.ErrorContext(ctx, "failed to delete finished webhook endpoint deliveries", "error", err)
+		return 0, fmt.Errorf("failed to delete finished webhook endpoint deliveries: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return rowsAffected, nil
+}
+
+// query は配信を検索するクエリを実行する
+func (r *webhookEndpointDeliveryRepository) query(ctx context.Context, query string, args ...any) ([]*model.WebhookEndpointDelivery, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		r.logger.ErrorContext(ctx, "failed to find webhook endpoint deliveries", "error", err)
+		return nil, fmt.Errorf("failed to find webhook endpoint deliveries: %w", err)
+	}
+	defer rows.Close()
+
+	deliveries := []*model.WebhookEndpointDelivery{}
+	for rows.Next() {
+		delivery, err := scanWebhookEndpointDelivery(rows)
+		if err != nil {
+			r.logger.ErrorContext(ctx, "failed to scan webhook endpoint delivery", "error", err)
+			return nil, fmt.Errorf("failed to scan webhook endpoint delivery: %w", err)
+		}
+		deliveries = append(deliveries, delivery)
+	}
+
+	if err := rows.Err(); err != nil {
+		r.logger.ErrorContext(ctx, "error iterating webhook endpoint deliveries", "error", err)
+		return nil, fmt.Errorf("error iterating webhook endpoint deliveries: %w", err)
+	}
+
+	return deliveries, nil
+}
+
+// exec は1件の配信を更新するクエリを実行する（該当がない場合はErrNotFound）
+func (r *webhookEndpointDeliveryRepository) exec(ctx context.Context, action, query string, args ...any) error {
+	result, err := r.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		r.logger.ErrorContext(ctx, "failed to "+action, "error", err, "delivery_id", args[0])
+		return fmt.Errorf("failed to %s: %w", action, err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("webhook endpoint delivery not found: %v: %w", args[0], model.ErrNotFound)
+	}
+
+	return nil
+}
+
+// scanWebhookEndpointDelivery はwebhookEndpointDeliveryColumnsの順で1行をスキャンする
+func scanWebhookEndpointDelivery(row rowScanner) (*model.WebhookEndpointDelivery, error) {
+	var delivery model.WebhookEndpointDelivery
+	var payload []byte
+	var responseStatus sql.NullInt64
+	var lastError sql.NullString
+	err := row.Scan(
+		&delivery.ID, &delivery.EndpointID, &delivery.UserID, &delivery.EventID, &delivery.EventType, &payload,
+		&delivery.Status, &delivery.Attempts, &responseStatus, &lastError, &delivery.NextAttemptAt,
+		&delivery.DeliveredAt, &delivery.CreatedAt, &delivery.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	delivery.Payload = payload
+	if responseStatus.Valid {
+		status := int(responseStatus.Int64)
+		delivery.ResponseStatus = &status
+	}
+	if lastError.Valid {
+		delivery.LastError = &lastError.String
+	}
+
+	return &delivery, nil
+}
@@ -0,0 +1,216 @@
+package persistence
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/sikigasa/github-task-controller/backend/internal/domain/model"
+	"github.com/sikigasa/github-task-controller/backend/internal/domain/repository"
+)
+
+// webhookEndpointColumns はWebhookの送信先の検索時に取得するカラム（scanWebhookEndpointの引数順と一致させる）
+const webhookEndpointColumns = `id, project_id, user_id, url, secret, event_types, enabled, consecutive_failures, disabled_at, created_at, updated_at`
+
+type webhookEndpointRepository struct {
+	db     *tenantDB
+	logger *slog.Logger
+}
+
+// NewWebhookEndpointRepository は新しいWebhookEndpointRepositoryを作成する
+func NewWebhookEndpointRepository(db *sql.DB, logger *slog.Logger) repository.WebhookEndpointRepository {
+	return &webhookEndpointRepository{
+		db:     newTenantDB(db),
+		logger: logger,
+	}
+}
+
+func (r *webhookEndpointRepository) Create(ctx context.Context, endpoint *model.WebhookEndpoint) error {
+	query := `
+		INSERT INTO webhook_endpoint (id, project_id, user_id, url, secret, event_types, enabled, consecutive_failures, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+	`
+
+	_, err := r.db.ExecContext(ctx, query,
+		endpoint.ID, endpoint.ProjectID, endpoint.UserID, endpoint.URL, endpoint.Secret, pq.Array(endpoint.EventTypes),
+		endpoint.Enabled, endpoint.ConsecutiveFailures, endpoint.CreatedAt, endpoint.UpdatedAt,
+	)
+	if err != nil {
+		r.logger.ErrorContext(ctx, "failed to create webhook endpoint", "error", err, "project_id", endpoint.ProjectID)
+		return fmt.Errorf("failed to create webhook endpoint: %w", err)
+	}
+
+	r.logger.InfoContext(ctx, "webhook endpoint created", "endpoint_id", endpoint.ID, "project_id", endpoint.ProjectID)
+	return nil
+}
+
+func (r *webhookEndpointRepository) FindByID(ctx context.Context, id string) (*model.WebhookEndpoint, error) {
+	query := `SELECT ` + webhookEndpointColumns + ` FROM webhook_endpoint WHERE id = $1`
+
+	endpoint, err := scanWebhookEndpoint(r.db.QueryRowContext(ctx, query, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("webhook endpoint not found: %s: %w", id, model.ErrNotFound)
+	}
+	if err != nil {
+		r.logger.ErrorContext(ctx, "failed to find webhook endpoint", "error", err, "endpoint_id", id)
+		return nil, fmt.Errorf("failed to find webhook endpoint: %w", err)
+	}
+
+	return endpoint, nil
+}
+
+func (r *webhookEndpointRepository) FindByProjectID(ctx context.Context, projectID string) ([]*model.WebhookEndpoint, error) {
+	query := `
+		SELECT ` + webhookEndpointColumns + `
+		FROM webhook_endpoint
+		WHERE project_id = $1
+		ORDER BY created_at ASC
+	`
+
+	return r.query(ctx, query, projectID)
+}
+
+func (r *webhookEndpointRepository) FindEnabledByProjectID(ctx context.Context, projectID string) ([]*model.WebhookEndpoint, error) {
+	query := `
+		SELECT ` + webhookEndpointColumns + `
+		FROM webhook_endpoint
+		WHERE project_id = $1 AND enabled
+		ORDER BY created_at ASC
+	`
+
+	return r.query(ctx, query, projectID)
+}
+
+func (r *webhookEndpointRepository) Update(ctx context.Context, endpoint *model.WebhookEndpoint) error {
+	query := `
+		UPDATE webhook_endpoint
+		SET url = $1, event_types = $2, enabled = $3, consecutive_failures = $4, disabled_at = $5, updated_at = $6
+		WHERE id = $7 AND project_id = $8
+	`
+
+	result, err := r.db.ExecContext(ctx, query,
+		endpoint.URL, pq.Array(endpoint.EventTypes), endpoint.Enabled, endpoint.ConsecutiveFailures,
+		endpoint.DisabledAt, endpoint.UpdatedAt, endpoint.ID, endpoint.ProjectID,
+	)
+	if err != nil {
+		r.logger.ErrorContext(ctx, "failed to update webhook endpoint", "error", err, "endpoint_id", endpoint.ID)
+		return fmt.Errorf("failed to update webhook endpoint: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("webhook endpoint not found: %s: %w", endpoint.ID, model.ErrNotFound)
+	}
+
+	return nil
+}
+
+func (r *webhookEndpointRepository) Delete(ctx context.Context, projectID, id string) error {
+	query := `DELETE FROM webhook_endpoint WHERE id = $1 AND project_id = $2`
+
+	result, err := r.db.ExecContext(ctx, query, id, projectID)
+	if err != nil {
+		r.logger.ErrorContext(ctx, "failed to delete webhook endpoint", "error", err, "endpoint_id", id)
+		return fmt.Errorf("failed to delete webhook endpoint: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("webhook endpoint not found: %s: %w", id, model.ErrNotFound)
+	}
+
+	r.logger.InfoContext(ctx, "webhook endpoint deleted", "endpoint_id", id, "project_id", projectID)
+	return nil
+}
+
+func (r *webhookEndpointRepository) RecordSuccess(ctx context.Context, id string) error {
+	query := `UPDATE webhook_endpoint SET consecutive_failures = 0 WHERE id = $1 AND consecutive_failures > 0`
+
+	if _, err := r.db.ExecContext(ctx, query, id); err != nil {
+		r.logger.ErrorContext(ctx, "failed to record webhook endpoint success", "error", err, "endpoint_id", id)
+		return fmt.Errorf("failed to record webhook endpoint success: %w", err)
+	}
+
+	return nil
+}
+
+func (r *webhookEndpointRepository) RecordFailure(ctx context.Context, id string, disableAfter int, at time.Time) (bool, error) {
+	// 連続失敗回数の加算と無効化を1つの文で行い、複数のワーカーが同時に失敗しても無効化を1回だけ報告する
+	query := `
+		UPDATE webhook_endpoint
+		SET consecutive_failures = consecutive_failures + 1,
+			enabled = enabled AND consecutive_failures + 1 < $2,
+			disabled_at = CASE WHEN enabled AND consecutive_failures + 1 >= $2 THEN $3 ELSE disabled_at END,
+			updated_at = CASE WHEN enabled AND consecutive_failures + 1 >= $2 THEN $3 ELSE updated_at END
+		WHERE id = $1
+		RETURNING disabled_at IS NOT DISTINCT FROM $3
+	`
+
+	var disabled bool
+	if err := r.db.QueryRowContext(ctx, query, id, disableAfter, at).Scan(&disabled); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return false, fmt.Errorf("webhook endpoint not found: %s: %w", id, model.ErrNotFound)
+		}
+		r.logger.ErrorContext(ctx, "failed to record webhook endpoint failure", "error", err, "endpoint_id", id)
+		return false, fmt.Errorf("failed to record webhook endpoint failure: %w", err)
+	}
+
+	return disabled, nil
+}
+
+// query は送信先を検索するクエリを実行する
+func (r *webhookEndpointRepository) query(ctx context.Context, query string, args ...any) ([]*model.WebhookEndpoint, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		r.logger.ErrorContext(ctx, "failed to find webhook endpoints", "error", err)
+		return nil, fmt.Errorf("failed to find webhook endpoints: %w", err)
+	}
+	defer rows.Close()
+
+	endpoints := []*model.WebhookEndpoint{}
+	for rows.Next() {
+		endpoint, err := scanWebhookEndpoint(rows)
+		if err != nil {
+			r.logger.ErrorContext(ctx, "failed to scan webhook endpoint", "error", err)
+			return nil, fmt.Errorf("failed to scan webhook endpoint: %w", err)
+		}
+		endpoints = append(endpoints, endpoint)
+	}
+
+	if err := rows.Err(); err != nil {
+		r.logger.ErrorContext(ctx, "error iterating webhook endpoints", "error", err)
+		return nil, fmt.Errorf("error iterating webhook endpoints: %w", err)
+	}
+
+	return endpoints, nil
+}
+
+// scanWebhookEndpoint はwebhookEndpointColumnsの順で1行をスキャンする
+func scanWebhookEndpoint(row rowScanner) (*model.WebhookEndpoint, error) {
+	var endpoint model.WebhookEndpoint
+	var eventTypes pq.StringArray
+	err := row.Scan(
+		&endpoint.ID, &endpoint.ProjectID, &endpoint.UserID, &endpoint.URL, &endpoint.Secret, &eventTypes,
+		&endpoint.Enabled, &endpoint.ConsecutiveFailures, &endpoint.DisabledAt, &endpoint.CreatedAt, &endpoint.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	endpoint.EventTypes = []string(eventTypes)
+	if endpoint.EventTypes == nil {
+		endpoint.EventTypes = []string{}
+	}
+
+	return &endpoint, nil
+}
@@ -0,0 +1,180 @@
+package push
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// apnsProductionURL・apnsSandboxURL はAPNsのエンドポイント（HTTP/2で接続する）
+	apnsProductionURL = "https://api.push.apple.com"
+	apnsSandboxURL    = "https://api.sandbox.push.apple.com"
+	// apnsTokenTTL は認証トークン（ES256のJWT）を再利用する期間
+	// APNsはトークンを1時間まで受け付け、20分より短い間隔での再発行を拒否するためその間で更新する
+	apnsTokenTTL = 50 * time.Minute
+)
+
+// apnsSender はAPNsのトークンベースの認証（.p8の認証キー）で通知を送信する
+type apnsSender struct {
+	baseURL    string
+	teamID     string
+	keyID      string
+	topic      string
+	privateKey *ecdsa.PrivateKey
+	httpClient *http.Client
+
+	// mu は認証トークンの再利用・更新を保護する
+	mu        sync.Mutex
+	token     string
+	tokenTime time.Time
+}
+
+// apnsErrorResponse はAPNsのエラー応答
+type apnsErrorResponse struct {
+	Reason string `json:"reason"`
+}
+
+func newAPNsSender(cfg APNsConfig, timeout time.Duration) (*apnsSender, error) {
+	block, _ := pem.Decode([]byte(strings.ReplaceAll(cfg.PrivateKey, `\n`, "\n")))
+	if block == nil {
+		return nil, errors.New("failed to decode APNs private key: invalid pem")
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse APNs private key: %w", err)
+	}
+	ecKey, ok := key.(*ecdsa.PrivateKey)
+	if !ok {
+		return nil, errors.New("APNs private key is not an ecdsa key")
+	}
+
+	baseURL := apnsSandboxURL
+	if cfg.Production {
+		baseURL = apnsProductionURL
+	}
+	return &apnsSender{
+		baseURL:    baseURL,
+		teamID:     cfg.TeamID,
+		keyID:      cfg.KeyID,
+		topic:      cfg.Topic,
+		privateKey: ecKey,
+		// 既定のTransportはTLSのALPNでHTTP/2に切り替える
+		httpClient: &http.Client{Timeout: timeout},
+	}, nil
+}
+
+// Send は通知をAPNsに送信する（トークンが無効になった場合はErrInvalidTokenを返す）
+func (s *apnsSender) Send(ctx context.Context, msg Message) error {
+	payload := map[string]any{
+		"aps": map[string]any{
+			"alert": map[string]string{"title": msg.Title, "body": msg.Body},
+			"sound": "default",
+		},
+	}
+	for key, value := range msg.Data {
+		if key != "aps" {
+			payload[key] = value
+		}
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal APNs payload: %w", err)
+	}
+
+	authToken, err := s.authToken(time.Now())
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.baseURL+"/3/device/"+url.PathEscape(msg.Token), bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create APNs request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "bearer "+authToken)
+	req.Header.Set("apns-topic", s.topic)
+	req.Header.Set("apns-push-type", "alert")
+	req.Header.Set("apns-priority", "10")
+	if msg.CollapseID != "" {
+		req.Header.Set("apns-collapse-id", msg.CollapseID)
+	}
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send APNs notification: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusOK {
+		_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, errorBodyLimit))
+		return nil
+	}
+
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, errorBodyLimit))
+	var errResp apnsErrorResponse
+	_ = json.Unmarshal(respBody, &errResp)
+	switch {
+	case resp.StatusCode == http.StatusGone,
+		errResp.Reason == "BadDeviceToken", errResp.Reason == "DeviceTokenNotForTopic", errResp.Reason == "Unregistered":
+		return fmt.Errorf("APNs rejected token (%s): %w", errResp.Reason, ErrInvalidToken)
+	case errResp.Reason == "ExpiredProviderToken":
+		// 次の送信で認証トークンを発行し直す
+		s.mu.Lock()
+		s.token = ""
+		s.mu.Unlock()
+	}
+	return fmt.Errorf("APNs returned %d: %s", resp.StatusCode, errResp.Reason)
+}
+
+// authToken はAPNsの認証トークン（ES256のJWT）を返す（apnsTokenTTLの間は同じトークンを再利用する）
+func (s *apnsSender) authToken(now time.Time) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.token != "" && now.Sub(s.tokenTime) < apnsTokenTTL {
+		return s.token, nil
+	}
+
+	header, err := json.Marshal(map[string]string{"alg": "ES256", "kid": s.keyID})
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal APNs token header: %w", err)
+	}
+	claims, err := json.Marshal(map[string]any{
+		"iss": s.teamID,
+		"iat": now.Unix(),
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal APNs token claims: %w", err)
+	}
+
+	unsigned := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(claims)
+	digest := sha256.Sum256([]byte(unsigned))
+	r, sig, err := ecdsa.Sign(rand.Reader, s.privateKey, digest[:])
+	if err != nil {
+		return "", fmt.Errorf("failed to sign APNs token: %w", err)
+	}
+
+	// JWSのES256署名はr・sをそれぞれ32バイトに揃えて連結する
+	signature := make([]byte, 64)
+	r.FillBytes(signature[:32])
+	sig.FillBytes(signature[32:])
+
+	s.token = unsigned + "." + base64.RawURLEncoding.EncodeToString(signature)
+	s.tokenTime = now
+	return s.token, nil
+}
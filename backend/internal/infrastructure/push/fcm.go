@@ -0,0 +1,166 @@
+package push
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/jwt"
+)
+
+const (
+	// fcmScope はFCMのHTTP v1 APIで通知を送信するためのOAuthスコープ
+	fcmScope = "https://www.googleapis.com/auth/firebase.messaging"
+	// fcmDefaultTokenURL はサービスアカウントの鍵にtoken_uriがない場合のトークンエンドポイント
+	fcmDefaultTokenURL = "https://oauth2.googleapis.com/token"
+	// fcmSendURL は通知を送信するエンドポイント（%sはFirebaseのプロジェクトID）
+	fcmSendURL = "https://fcm.googleapis.com/v1/projects/%s/messages:send"
+	// errorBodyLimit はエラーの応答から読み取る本文の上限
+	errorBodyLimit = 64 << 10
+)
+
+// fcmServiceAccount はFirebaseのサービスアカウントの鍵（JSON）のうち認証に使う項目
+type fcmServiceAccount struct {
+	ProjectID    string `json:"project_id"`
+	PrivateKeyID string `json:"private_key_id"`
+	PrivateKey   string `json:"private_key"`
+	ClientEmail  string `json:"client_email"`
+	TokenURI     string `json:"token_uri"`
+}
+
+// fcmSender はFCMのHTTP v1 APIで通知を送信する
+// アクセストークンはサービスアカウントの鍵で署名したJWTと交換し、期限まで再利用する
+type fcmSender struct {
+	sendURL     string
+	tokenSource oauth2.TokenSource
+	httpClient  *http.Client
+}
+
+// fcmRequest はmessages:sendの本文
+type fcmRequest struct {
+	Message fcmMessage `json:"message"`
+}
+
+type fcmMessage struct {
+	Token        string            `json:"token"`
+	Notification fcmNotification   `json:"notification"`
+	Data         map[string]string `json:"data,omitempty"`
+	Android      *fcmAndroidConfig `json:"android,omitempty"`
+	APNs         *fcmAPNsConfig    `json:"apns,omitempty"`
+}
+
+type fcmNotification struct {
+	Title string `json:"title"`
+	Body  string `json:"body"`
+}
+
+type fcmAndroidConfig struct {
+	CollapseKey string `json:"collapse_key"`
+}
+
+type fcmAPNsConfig struct {
+	Headers map[string]string `json:"headers"`
+}
+
+// fcmErrorResponse はFCMのエラー応答
+type fcmErrorResponse struct {
+	Error struct {
+		Status  string `json:"status"`
+		Message string `json:"message"`
+		Details []struct {
+			ErrorCode string `json:"errorCode"`
+		} `json:"details"`
+	} `json:"error"`
+}
+
+func newFCMSender(credentials string, timeout time.Duration) (*fcmSender, error) {
+	var account fcmServiceAccount
+	if err := json.Unmarshal([]byte(credentials), &account); err != nil {
+		return nil, fmt.Errorf("failed to parse FCM credentials: %w", err)
+	}
+	if account.ProjectID == "" || account.ClientEmail == "" || account.PrivateKey == "" {
+		return nil, errors.New("FCM credentials must contain project_id, client_email and private_key")
+	}
+	tokenURL := account.TokenURI
+	if tokenURL == "" {
+		tokenURL = fcmDefaultTokenURL
+	}
+
+	httpClient := &http.Client{Timeout: timeout}
+	cfg := &jwt.Config{
+		Email:        account.ClientEmail,
+		PrivateKey:   []byte(account.PrivateKey),
+		PrivateKeyID: account.PrivateKeyID,
+		Scopes:       []string{fcmScope},
+		TokenURL:     tokenURL,
+	}
+	// トークンの取得にも同じタイムアウトのクライアントを使う
+	tokenCtx := context.WithValue(context.Background(), oauth2.HTTPClient, httpClient)
+
+	return &fcmSender{
+		sendURL:     fmt.Sprintf(fcmSendURL, account.ProjectID),
+		tokenSource: cfg.TokenSource(tokenCtx),
+		httpClient:  httpClient,
+	}, nil
+}
+
+// Send は通知をFCMに送信する（トークンが無効になった場合はErrInvalidTokenを返す）
+func (s *fcmSender) Send(ctx context.Context, msg Message) error {
+	message := fcmMessage{
+		Token:        msg.Token,
+		Notification: fcmNotification{Title: msg.Title, Body: msg.Body},
+		Data:         msg.Data,
+	}
+	if msg.CollapseID != "" {
+		message.Android = &fcmAndroidConfig{CollapseKey: msg.CollapseID}
+		message.APNs = &fcmAPNsConfig{Headers: map[string]string{"apns-collapse-id": msg.CollapseID}}
+	}
+	body, err := json.Marshal(fcmRequest{Message: message})
+	if err != nil {
+		return fmt.Errorf("failed to marshal FCM message: %w", err)
+	}
+
+	token, err := s.tokenSource.Token()
+	if err != nil {
+		return fmt.Errorf("failed to get FCM access token: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.sendURL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create FCM request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	token.SetAuthHeader(req)
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send FCM message: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusOK {
+		_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, errorBodyLimit))
+		return nil
+	}
+
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, errorBodyLimit))
+	var errResp fcmErrorResponse
+	_ = json.Unmarshal(respBody, &errResp)
+	for _, detail := range errResp.Error.Details {
+		// UNREGISTERED: アプリのアンインストールなどでトークンが無効になった
+		// SENDER_ID_MISMATCH: 別のFirebaseプロジェクトで発行されたトークン
+		if detail.ErrorCode == "UNREGISTERED" || detail.ErrorCode == "SENDER_ID_MISMATCH" {
+			return fmt.Errorf("FCM rejected token (%s): %w", detail.ErrorCode, ErrInvalidToken)
+		}
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		return fmt.Errorf("FCM rejected token (%s): %w", errResp.Error.Status, ErrInvalidToken)
+	}
+	return fmt.Errorf("FCM returned %d: %s %s", resp.StatusCode, errResp.Error.Status, errResp.Error.Message)
+}
@@ -0,0 +1,122 @@
+package push
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+)
+
+// Platform はプッシュ通知の配信サービス
+type Platform string
+
+const (
+	// PlatformFCM はFirebase Cloud Messaging（Android・Web Push）
+	PlatformFCM Platform = "fcm"
+	// PlatformAPNs はApple Push Notification service（iOS）
+	PlatformAPNs Platform = "apns"
+)
+
+// ErrInvalidToken は配信サービスがトークンを無効（アプリのアンインストール・トークンの更新など）と判断したことを表す
+// このエラーを返した端末には以降も届かないため、登録を削除する
+var ErrInvalidToken = errors.New("push token is no longer valid")
+
+// Message は端末に送る通知を表す
+type Message struct {
+	Platform Platform
+	// Token はFCMの登録トークン・APNsのデバイストークン
+	Token string
+	Title string
+	Body  string
+	// Data はクライアントに渡す追加の値
+	Data map[string]string
+	// CollapseID は同じ通知を表す識別子（端末では同じ識別子の前の通知を置き換える）
+	CollapseID string
+}
+
+// Sender はプッシュ通知を送信するインターフェース
+type Sender interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+// Config はプッシュ通知の配信サービスの設定
+type Config struct {
+	// FCMCredentials はFirebaseのサービスアカウントの鍵（JSON）の内容（空の場合はFCMに送信しない）
+	FCMCredentials string
+	APNs           APNsConfig
+	// Timeout は1件の送信の応答を待つ時間
+	Timeout time.Duration
+}
+
+// APNsConfig はAPNsの認証キー（.p8）の設定（TeamIDが空の場合はAPNsに送信しない）
+type APNsConfig struct {
+	TeamID string
+	KeyID  string
+	// PrivateKey は.p8ファイル（PEM）の内容
+	PrivateKey string
+	// Topic はiOSアプリのバンドルID
+	Topic string
+	// Production を無効にすると開発用（sandbox）のAPNsに送信する
+	Production bool
+}
+
+// NewSender は設定に応じたSenderを作成する
+// 設定していない配信サービスの端末への通知は送信せずにログへ出力する（開発環境用）
+func NewSender(cfg Config, logger *slog.Logger) (Sender, error) {
+	s := &platformSender{
+		senders: make(map[Platform]Sender),
+		log:     &logSender{logger: logger},
+	}
+
+	if cfg.FCMCredentials != "" {
+		fcm, err := newFCMSender(cfg.FCMCredentials, cfg.Timeout)
+		if err != nil {
+			return nil, err
+		}
+		s.senders[PlatformFCM] = fcm
+	}
+	if cfg.APNs.TeamID != "" {
+		apns, err := newAPNsSender(cfg.APNs, cfg.Timeout)
+		if err != nil {
+			return nil, err
+		}
+		s.senders[PlatformAPNs] = apns
+	}
+
+	if len(s.senders) == 0 {
+		logger.Info("PUSH_FCM_CREDENTIALS and PUSH_APNS_TEAM_ID are not set, push notifications will be logged instead of sent")
+	}
+	return s, nil
+}
+
+// platformSender は端末の配信サービスに応じて送信先を切り替える
+type platformSender struct {
+	senders map[Platform]Sender
+	log     Sender
+}
+
+// Send は端末の配信サービスで通知を送信する
+func (s *platformSender) Send(ctx context.Context, msg Message) error {
+	switch msg.Platform {
+	case PlatformFCM, PlatformAPNs:
+	default:
+		return fmt.Errorf("unknown push platform: %s", msg.Platform)
+	}
+
+	sender, ok := s.senders[msg.Platform]
+	if !ok {
+		return s.log.Send(ctx, msg)
+	}
+	return sender.Send(ctx, msg)
+}
+
+type logSender struct {
+	logger *slog.Logger
+}
+
+// Send は通知を送信せずにログへ出力する
+func (s *logSender) Send(ctx context.Context, msg Message) error {
+	s.logger.InfoContext(ctx, "push notification (not sent)", "platform", msg.Platform, "title", msg.Title, "body", msg.Body, "data", msg.Data)
+	return nil
+}
@@ -0,0 +1,111 @@
+package saml
+
+import (
+	"crypto"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/subtle"
+	"crypto/x509"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+const (
+	nsDSig       = "http://www.w3.org/2000/09/xmldsig#"
+	algExcC14N   = "http://www.w3.org/2001/10/xml-exc-c14n#"
+	algEnveloped = "http://www.w3.org/2000/09/xmldsig#enveloped-signature"
+	algRSASHA256 = "http://www.w3.org/2001/04/xmldsig-more#rsa-sha256"
+	algSHA256    = "http://www.w3.org/2001/04/xmlenc#sha256"
+)
+
+// errNotSigned は要素にエンベロープ署名がない場合のエラー
+var errNotSigned = errors.New("element is not signed")
+
+// verifySignature は要素のエンベロープ署名（排他的XML正規化・RSA-SHA256）をIdPの証明書で検証する
+// 署名の参照先が要素自身のIDであることも確認し、署名済みの別要素を差し込む攻撃を防ぐ
+func verifySignature(el *node, cert *x509.Certificate) error {
+	sig := el.element(nsDSig, "Signature")
+	if sig == nil {
+		return errNotSigned
+	}
+	signedInfo := sig.element(nsDSig, "SignedInfo")
+	if signedInfo == nil {
+		return errors.New("signed info not found")
+	}
+
+	c14nMethod := signedInfo.element(nsDSig, "CanonicalizationMethod")
+	if c14nMethod.attr("Algorithm") != algExcC14N {
+		return fmt.Errorf("unsupported canonicalization method: %s", c14nMethod.attr("Algorithm"))
+	}
+	if alg := signedInfo.element(nsDSig, "SignatureMethod").attr("Algorithm"); alg != algRSASHA256 {
+		return fmt.Errorf("unsupported signature method: %s", alg)
+	}
+
+	refs := signedInfo.elements(nsDSig, "Reference")
+	if len(refs) != 1 {
+		return errors.New("signature must have exactly one reference")
+	}
+	ref := refs[0]
+	id := el.attr("ID")
+	if id == "" || ref.attr("URI") != "#"+id {
+		return errors.New("signature does not reference the signed element")
+	}
+
+	// 変換はエンベロープ署名の除去と排他的XML正規化のみを受け付ける
+	var enveloped bool
+	var prefixes []string
+	for _, t := range ref.element(nsDSig, "Transforms").elements(nsDSig, "Transform") {
+		switch t.attr("Algorithm") {
+		case algEnveloped:
+			enveloped = true
+		case algExcC14N:
+			prefixes = inclusivePrefixes(t)
+		default:
+			return fmt.Errorf("unsupported transform: %s", t.attr("Algorithm"))
+		}
+	}
+	if !enveloped {
+		return errors.New("signature is not enveloped")
+	}
+	if alg := ref.element(nsDSig, "DigestMethod").attr("Algorithm"); alg != algSHA256 {
+		return fmt.Errorf("unsupported digest method: %s", alg)
+	}
+
+	expected, err := decodeBase64(ref.element(nsDSig, "DigestValue").text())
+	if err != nil {
+		return fmt.Errorf("failed to decode digest value: %w", err)
+	}
+	digest := sha256.Sum256(canonicalize(el, sig, prefixes))
+	if subtle.ConstantTimeCompare(digest[:], expected) != 1 {
+		return errors.New("digest mismatch")
+	}
+
+	signature, err := decodeBase64(sig.element(nsDSig, "SignatureValue").text())
+	if err != nil {
+		return fmt.Errorf("failed to decode signature value: %w", err)
+	}
+	pub, ok := cert.PublicKey.(*rsa.PublicKey)
+	if !ok {
+		return errors.New("idp certificate is not an rsa key")
+	}
+	signedDigest := sha256.Sum256(canonicalize(signedInfo, nil, inclusivePrefixes(c14nMethod)))
+	if err := rsa.VerifyPKCS1v15(pub, crypto.SHA256, signedDigest[:], signature); err != nil {
+		return fmt.Errorf("invalid signature: %w", err)
+	}
+	return nil
+}
+
+// inclusivePrefixes は排他的XML正規化のInclusiveNamespacesで指定された接頭辞を返す
+func inclusivePrefixes(transform *node) []string {
+	if inc := transform.element(algExcC14N, "InclusiveNamespaces"); inc != nil {
+		return strings.Fields(inc.attr("PrefixList"))
+	}
+	return nil
+}
+
+// decodeBase64 は改行などの空白を含むBase64をデコードする
+func decodeBase64(s string) ([]byte, error) {
+	return base64.StdEncoding.DecodeString(strings.Join(strings.Fields(s), ""))
+}
@@ -0,0 +1,338 @@
+// Package saml はSAML 2.0のサービスプロバイダー（SP）としてIdPのシングルサインオンを扱う
+// HTTP-RedirectバインディングでAuthnRequestを送り、HTTP-POSTバインディングで署名付きのResponseを受け取る
+package saml
+
+import (
+	"bytes"
+	"compress/flate"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+)
+
+const (
+	nsProtocol  = "urn:oasis:names:tc:SAML:2.0:protocol"
+	nsAssertion = "urn:oasis:names:tc:SAML:2.0:assertion"
+
+	bindingRedirect = "urn:oasis:names:tc:SAML:2.0:bindings:HTTP-Redirect"
+	bindingPOST     = "urn:oasis:names:tc:SAML:2.0:bindings:HTTP-POST"
+	statusSuccess   = "urn:oasis:names:tc:SAML:2.0:status:Success"
+	methodBearer    = "urn:oasis:names:tc:SAML:2.0:cm:bearer"
+
+	// clockSkew はIdPとの時刻のずれとして許容する幅
+	clockSkew = 3 * time.Minute
+)
+
+// ErrInvalidResponse はIdPのResponseが不正な場合のエラー
+var ErrInvalidResponse = errors.New("invalid saml response")
+
+// Config はサービスプロバイダーとIdPの設定（デプロイごとに1つのIdPを設定する）
+type Config struct {
+	// EntityID はこのサービスプロバイダーのエンティティID
+	EntityID string
+	// ACSURL はIdPがResponseをPOSTするAssertion Consumer ServiceのURL
+	ACSURL      string
+	IdPEntityID string
+	IdPSSOURL   string
+	// IdPCertificate はIdPの署名用証明書（PEM、またはヘッダーのないBase64）
+	IdPCertificate string
+}
+
+// ServiceProvider はSAMLのサービスプロバイダー
+type ServiceProvider struct {
+	entityID    string
+	acsURL      string
+	idpEntityID string
+	idpSSOURL   string
+	idpCert     *x509.Certificate
+}
+
+// Assertion はIdPが署名したアサーションから取り出したユーザー情報
+type Assertion struct {
+	NameID       string
+	NameIDFormat string
+	Attributes   map[string][]string
+}
+
+// Attribute は属性の最初の値を返す
+func (a *Assertion) Attribute(name string) string {
+	if values := a.Attributes[name]; len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+// NewServiceProvider は新しいServiceProviderを作成する
+func NewServiceProvider(cfg Config) (*ServiceProvider, error) {
+	if cfg.EntityID == "" || cfg.ACSURL == "" || cfg.IdPEntityID == "" || cfg.IdPSSOURL == "" {
+		return nil, errors.New("saml entity id, acs url, idp entity id and idp sso url are required")
+	}
+	cert, err := parseCertificate(cfg.IdPCertificate)
+	if err != nil {
+		return nil, err
+	}
+	return &ServiceProvider{
+		entityID:    cfg.EntityID,
+		acsURL:      cfg.ACSURL,
+		idpEntityID: cfg.IdPEntityID,
+		idpSSOURL:   cfg.IdPSSOURL,
+		idpCert:     cert,
+	}, nil
+}
+
+// IdPEntityID はIdPのエンティティIDを返す
+func (sp *ServiceProvider) IdPEntityID() string {
+	return sp.idpEntityID
+}
+
+// parseCertificate はPEM、またはヘッダーのないBase64の証明書を読み込む
+func parseCertificate(s string) (*x509.Certificate, error) {
+	s = strings.ReplaceAll(s, `\n`, "\n")
+	der := []byte(nil)
+	if block, _ := pem.Decode([]byte(s)); block != nil {
+		der = block.Bytes
+	} else {
+		decoded, err := decodeBase64(s)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode idp certificate: %w", err)
+		}
+		der = decoded
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse idp certificate: %w", err)
+	}
+	return cert, nil
+}
+
+// Metadata はIdPに登録するサービスプロバイダーのメタデータを返す
+func (sp *ServiceProvider) Metadata() []byte {
+	var b bytes.Buffer
+	b.WriteString(xml.Header)
+	fmt.Fprintf(&b, `<md:EntityDescriptor xmlns:md="urn:oasis:names:tc:SAML:2.0:metadata" entityID="%s">`, escapeAttr(sp.entityID))
+	fmt.Fprintf(&b, `<md:SPSSODescriptor AuthnRequestsSigned="false" WantAssertionsSigned="true" protocolSupportEnumeration="%s">`, nsProtocol)
+	b.WriteString(`<md:NameIDFormat>urn:oasis:names:tc:SAML:1.1:nameid-format:emailAddress</md:NameIDFormat>`)
+	b.WriteString(`<md:NameIDFormat>urn:oasis:names:tc:SAML:2.0:nameid-format:persistent</md:NameIDFormat>`)
+	fmt.Fprintf(&b, `<md:AssertionConsumerService Binding="%s" Location="%s" index="0" isDefault="true"/>`, bindingPOST, escapeAttr(sp.acsURL))
+	b.WriteString(`</md:SPSSODescriptor></md:EntityDescriptor>`)
+	return b.Bytes()
+}
+
+// AuthnRequestURL はHTTP-RedirectバインディングでIdPに送るAuthnRequestのURLとリクエストIDを返す
+// リクエストIDはResponseのInResponseToの検証に使うため、呼び出し側でRelayStateと対応付けて保存する
+func (sp *ServiceProvider) AuthnRequestURL(relayState string, now time.Time) (string, string, error) {
+	b := make([]byte, 20)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", fmt.Errorf("failed to generate request id: %w", err)
+	}
+	// IDはNCNameのため数字で始めない
+	requestID := "_" + hex.EncodeToString(b)
+
+	request := fmt.Sprintf(`<samlp:AuthnRequest xmlns:samlp="%s" xmlns:saml="%s" ID="%s" Version="2.0" IssueInstant="%s" Destination="%s" AssertionConsumerServiceURL="%s" ProtocolBinding="%s">`+
+		`<saml:Issuer>%s</saml:Issuer><samlp:NameIDPolicy AllowCreate="true"/></samlp:AuthnRequest>`,
+		nsProtocol, nsAssertion, requestID, now.UTC().Format(time.RFC3339), escapeAttr(sp.idpSSOURL), escapeAttr(sp.acsURL), bindingPOST,
+		escapeText(sp.entityID))
+
+	var buf bytes.Buffer
+	w, err := flate.NewWriter(&buf, flate.DefaultCompression)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to create deflate writer: %w", err)
+	}
+	if _, err := w.Write([]byte(request)); err != nil {
+		return "", "", fmt.Errorf("failed to deflate authn request: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return "", "", fmt.Errorf("failed to deflate authn request: %w", err)
+	}
+
+	u, err := url.Parse(sp.idpSSOURL)
+	if err != nil {
+		return "", "", fmt.Errorf("invalid idp sso url: %w", err)
+	}
+	q := u.Query()
+	q.Set("SAMLRequest", base64.StdEncoding.EncodeToString(buf.Bytes()))
+	if relayState != "" {
+		q.Set("RelayState", relayState)
+	}
+	u.RawQuery = q.Encode()
+	return u.String(), requestID, nil
+}
+
+// ParseResponse はHTTP-POSTバインディングで受け取ったResponseを検証してアサーションを返す
+// 署名（ResponseまたはAssertion）、宛先、発行者、有効期間、対象、InResponseToをすべて確認する
+func (sp *ServiceProvider) ParseResponse(encoded, requestID string, now time.Time) (*Assertion, error) {
+	data, err := decodeBase64(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode saml response: %w: %w", err, ErrInvalidResponse)
+	}
+	root, err := parseXML(data)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", err, ErrInvalidResponse)
+	}
+	assertion, err := sp.validate(root, requestID, now)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", err, ErrInvalidResponse)
+	}
+	return assertion, nil
+}
+
+// validate はResponseの要素を検証してアサーションを取り出す
+func (sp *ServiceProvider) validate(root *node, requestID string, now time.Time) (*Assertion, error) {
+	if !root.is(nsProtocol, "Response") {
+		return nil, errors.New("root element is not a response")
+	}
+
+	// 同じIDの要素が複数あると署名の参照先を取り違えるため拒否する
+	ids := map[string]bool{}
+	var duplicated bool
+	root.walk(func(n *node) {
+		if id := n.attr("ID"); id != "" {
+			duplicated = duplicated || ids[id]
+			ids[id] = true
+		}
+	})
+	if duplicated {
+		return nil, errors.New("duplicated element id")
+	}
+
+	if dest := root.attr("Destination"); dest != "" && dest != sp.acsURL {
+		return nil, fmt.Errorf("unexpected destination: %s", dest)
+	}
+	if requestID == "" || root.attr("InResponseTo") != requestID {
+		return nil, errors.New("response does not match the authn request")
+	}
+	if status := root.element(nsProtocol, "Status").element(nsProtocol, "StatusCode").attr("Value"); status != statusSuccess {
+		return nil, fmt.Errorf("idp returned status %s", status)
+	}
+	if root.element(nsAssertion, "EncryptedAssertion") != nil {
+		return nil, errors.New("encrypted assertions are not supported")
+	}
+	assertions := root.elements(nsAssertion, "Assertion")
+	if len(assertions) != 1 {
+		return nil, errors.New("response must contain exactly one assertion")
+	}
+	assertion := assertions[0]
+
+	// ResponseとAssertionのどちらかに有効な署名が必要（署名がある場合はすべて有効であること）
+	signed := false
+	for _, el := range []*node{root, assertion} {
+		err := verifySignature(el, sp.idpCert)
+		if errors.Is(err, errNotSigned) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		signed = true
+	}
+	if !signed {
+		return nil, errors.New("response is not signed")
+	}
+
+	if issuer := assertion.element(nsAssertion, "Issuer").text(); issuer != sp.idpEntityID {
+		return nil, fmt.Errorf("unexpected issuer: %s", issuer)
+	}
+	if err := sp.validateConditions(assertion.element(nsAssertion, "Conditions"), now); err != nil {
+		return nil, err
+	}
+
+	subject := assertion.element(nsAssertion, "Subject")
+	if err := sp.validateSubjectConfirmation(subject, requestID, now); err != nil {
+		return nil, err
+	}
+	nameID := subject.element(nsAssertion, "NameID")
+	if nameID.text() == "" {
+		return nil, errors.New("name id not found")
+	}
+
+	result := &Assertion{
+		NameID:       nameID.text(),
+		NameIDFormat: nameID.attr("Format"),
+		Attributes:   map[string][]string{},
+	}
+	for _, statement := range assertion.elements(nsAssertion, "AttributeStatement") {
+		for _, attr := range statement.elements(nsAssertion, "Attribute") {
+			for _, v := range attr.elements(nsAssertion, "AttributeValue") {
+				result.Attributes[attr.attr("Name")] = append(result.Attributes[attr.attr("Name")], v.text())
+			}
+		}
+	}
+	return result, nil
+}
+
+// validateConditions はアサーションの有効期間と対象（Audience）を検証する
+func (sp *ServiceProvider) validateConditions(conditions *node, now time.Time) error {
+	if conditions == nil {
+		return errors.New("conditions not found")
+	}
+	if err := validateWindow(conditions.attr("NotBefore"), conditions.attr("NotOnOrAfter"), now); err != nil {
+		return err
+	}
+
+	var audiences []string
+	for _, r := range conditions.elements(nsAssertion, "AudienceRestriction") {
+		for _, a := range r.elements(nsAssertion, "Audience") {
+			audiences = append(audiences, a.text())
+		}
+	}
+	if !slices.Contains(audiences, sp.entityID) {
+		return errors.New("assertion is not intended for this service provider")
+	}
+	return nil
+}
+
+// validateSubjectConfirmation はbearerのSubjectConfirmationの宛先・有効期限・InResponseToを検証する
+func (sp *ServiceProvider) validateSubjectConfirmation(subject *node, requestID string, now time.Time) error {
+	for _, c := range subject.elements(nsAssertion, "SubjectConfirmation") {
+		if c.attr("Method") != methodBearer {
+			continue
+		}
+		data := c.element(nsAssertion, "SubjectConfirmationData")
+		if data.attr("Recipient") != sp.acsURL {
+			continue
+		}
+		if id := data.attr("InResponseTo"); id != "" && id != requestID {
+			continue
+		}
+		if data.attr("NotOnOrAfter") == "" {
+			continue
+		}
+		if err := validateWindow(data.attr("NotBefore"), data.attr("NotOnOrAfter"), now); err != nil {
+			continue
+		}
+		return nil
+	}
+	return errors.New("no valid bearer subject confirmation")
+}
+
+// validateWindow は現在時刻がNotBefore以降かつNotOnOrAfterより前であることを検証する（時刻のずれを許容する）
+func validateWindow(notBefore, notOnOrAfter string, now time.Time) error {
+	if notBefore != "" {
+		t, err := time.Parse(time.RFC3339Nano, notBefore)
+		if err != nil {
+			return fmt.Errorf("invalid NotBefore: %w", err)
+		}
+		if now.Add(clockSkew).Before(t) {
+			return errors.New("assertion is not yet valid")
+		}
+	}
+	if notOnOrAfter != "" {
+		t, err := time.Parse(time.RFC3339Nano, notOnOrAfter)
+		if err != nil {
+			return fmt.Errorf("invalid NotOnOrAfter: %w", err)
+		}
+		if !now.Add(-clockSkew).Before(t) {
+			return errors.New("assertion is expired")
+		}
+	}
+	return nil
+}
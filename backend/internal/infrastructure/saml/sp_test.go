@@ -0,0 +1,336 @@
+package saml
+
+import (
+	"encoding/base64"
+	"errors"
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+// テストデータのResponseはtestdata/generate.shでidp.keyにより署名している
+const (
+	testEntityID    = "https://app.example.com/auth/saml/metadata"
+	testACSURL      = "https://app.example.com/auth/saml/acs"
+	testIdPEntityID = "https://idp.example.com/metadata"
+	testRequestID   = "_0123456789abcdef"
+
+	// oktaAssertionID はtestdata/responses/okta.xmlの署名済みアサーションのID
+	oktaAssertionID = "id1000000000000000002"
+)
+
+var testResponseNow = time.Date(2026, 1, 2, 3, 1, 0, 0, time.UTC)
+
+func newTestServiceProvider(t *testing.T, mutate func(*Config)) *ServiceProvider {
+	t.Helper()
+	cfg := Config{
+		EntityID:       testEntityID,
+		ACSURL:         testACSURL,
+		IdPEntityID:    testIdPEntityID,
+		IdPSSOURL:      "https://idp.example.com/sso",
+		IdPCertificate: string(readTestdata(t, "idp.crt")),
+	}
+	if mutate != nil {
+		mutate(&cfg)
+	}
+	sp, err := NewServiceProvider(cfg)
+	if err != nil {
+		t.Fatalf("NewServiceProvider() error = %v", err)
+	}
+	return sp
+}
+
+func readResponse(t *testing.T, name string) string {
+	t.Helper()
+	return string(readTestdata(t, "responses/"+name+".xml"))
+}
+
+func encodeResponse(doc string) string {
+	return base64.StdEncoding.EncodeToString([]byte(doc))
+}
+
+var signaturePattern = regexp.MustCompile(`(?s)<ds:Signature .*?</ds:Signature>`)
+
+// oktaParts はokta.xmlを署名済みアサーションとその前後に分ける
+func oktaParts(t *testing.T) (before, assertion, after string) {
+	t.Helper()
+	doc := readResponse(t, "okta")
+	start := strings.Index(doc, "<saml2:Assertion ")
+	end := strings.Index(doc, "</saml2:Assertion>") + len("</saml2:Assertion>")
+	if start < 0 || end < start {
+		t.Fatal("assertion not found in okta.xml")
+	}
+	return doc[:start], doc[start:end], doc[end:]
+}
+
+// evilAssertion は署名済みアサーションのNameIDと属性を書き換え、署名を除いた偽のアサーションを返す
+func evilAssertion(signed, id string) string {
+	evil := signaturePattern.ReplaceAllString(signed, "")
+	evil = strings.ReplaceAll(evil, "user@example.com", "admin@example.com")
+	return strings.Replace(evil, `ID="`+oktaAssertionID+`"`, `ID="`+id+`"`, 1)
+}
+
+func TestServiceProviderParseResponse(t *testing.T) {
+	tests := []struct {
+		name       string
+		response   string
+		nameID     string
+		attributes map[string][]string
+	}{
+		{
+			name:     "assertion signed",
+			response: "okta",
+			nameID:   "user@example.com",
+			attributes: map[string][]string{
+				"email": {"user@example.com"},
+				"name":  {"Test User"},
+			},
+		},
+		{
+			name:     "response and assertion signed",
+			response: "adfs",
+			nameID:   "k3v9Q2xYp0wZ8a1b",
+			attributes: map[string][]string{
+				"http://schemas.xmlsoap.org/ws/2005/05/identity/claims/emailaddress": {"user@example.com"},
+				"http://schemas.xmlsoap.org/ws/2005/05/identity/claims/name":         {"Test & User"},
+			},
+		},
+		{
+			name:     "response signed",
+			response: "shibboleth",
+			nameID:   "user@example.com",
+			attributes: map[string][]string{
+				"urn:oid:0.9.2342.19200300.100.1.3": {"user@example.com"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sp := newTestServiceProvider(t, nil)
+
+			assertion, err := sp.ParseResponse(encodeResponse(readResponse(t, tt.response)), testRequestID, testResponseNow)
+			if err != nil {
+				t.Fatalf("ParseResponse() error = %v", err)
+			}
+			if assertion.NameID != tt.nameID {
+				t.Errorf("NameID = %q, want %q", assertion.NameID, tt.nameID)
+			}
+			if !maps.EqualFunc(assertion.Attributes, tt.attributes, slices.Equal) {
+				t.Errorf("Attributes = %v, want %v", assertion.Attributes, tt.attributes)
+			}
+		})
+	}
+}
+
+func TestServiceProviderParseResponseRejectsForgedResponse(t *testing.T) {
+	before, signed, after := oktaParts(t)
+	okta := before + signed + after
+	shibboleth := readResponse(t, "shibboleth")
+	extensions := func(content string) string {
+		return strings.Replace(before, "<saml2p:Status>", "<saml2p:Extensions>"+content+"</saml2p:Extensions><saml2p:Status>", 1)
+	}
+
+	tests := []struct {
+		name     string
+		response string
+		wantErr  string
+	}{
+		{
+			name:     "unsigned assertion",
+			response: signaturePattern.ReplaceAllString(okta, ""),
+			wantErr:  "response is not signed",
+		},
+		{
+			name:     "tampered name id",
+			response: strings.Replace(okta, ">user@example.com</saml2:NameID>", ">admin@example.com</saml2:NameID>", 1),
+			wantErr:  "digest mismatch",
+		},
+		{
+			name:     "tampered signed response",
+			response: strings.Replace(shibboleth, `Address="192.0.2.1"`, `Address="192.0.2.2"`, 1),
+			wantErr:  "digest mismatch",
+		},
+		{
+			name:     "tampered signature value",
+			response: strings.Replace(okta, "<ds:SignatureValue>", "<ds:SignatureValue>AAAA", 1),
+			wantErr:  "invalid signature",
+		},
+		{
+			// 署名済みのアサーションをExtensionsに移し、偽のアサーションを置く
+			name:     "wrapped in extensions",
+			response: extensions(signed) + evilAssertion(signed, "evil") + after,
+			wantErr:  "response is not signed",
+		},
+		{
+			// 偽のアサーションに署名済みのアサーションと同じIDを付ける
+			name:     "wrapped with duplicated id",
+			response: extensions(signed) + evilAssertion(signed, oktaAssertionID) + after,
+			wantErr:  "duplicated element id",
+		},
+		{
+			// 署名済みのアサーションを偽のアサーションの子要素に入れる
+			name:     "wrapped in assertion",
+			response: before + strings.Replace(evilAssertion(signed, "evil"), "</saml2:Assertion>", "<saml2:Advice>"+signed+"</saml2:Advice></saml2:Assertion>", 1) + after,
+			wantErr:  "response is not signed",
+		},
+		{
+			// 署名済みのアサーションの署名を偽のアサーションに写す
+			name:     "copied signature",
+			response: before + strings.Replace(evilAssertion(signed, "evil"), "</saml2:Issuer>", "</saml2:Issuer>"+signaturePattern.FindString(signed), 1) + after,
+			wantErr:  "signature does not reference the signed element",
+		},
+		{
+			name:     "additional assertion",
+			response: before + signed + evilAssertion(signed, "evil") + after,
+			wantErr:  "response must contain exactly one assertion",
+		},
+		{
+			// 署名済みのResponseをExtensionsに入れた偽のResponseで包む
+			name: "wrapped signed response",
+			response: `<samlp:Response xmlns:samlp="urn:oasis:names:tc:SAML:2.0:protocol" ID="evil-response" InResponseTo="` + testRequestID + `">` +
+				`<samlp:Extensions>` + strings.TrimPrefix(shibboleth, `<?xml version="1.0" encoding="UTF-8"?>`) + `</samlp:Extensions>` +
+				`<samlp:Status><samlp:StatusCode Value="urn:oasis:names:tc:SAML:2.0:status:Success"/></samlp:Status>` +
+				evilAssertion(signed, "evil") + `</samlp:Response>`,
+			wantErr: "response is not signed",
+		},
+		{
+			name:     "doctype",
+			response: `<!DOCTYPE r [<!ENTITY e "user@example.com">]>` + okta,
+			wantErr:  "xml directives are not allowed",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sp := newTestServiceProvider(t, nil)
+
+			_, err := sp.ParseResponse(encodeResponse(tt.response), testRequestID, testResponseNow)
+			if !errors.Is(err, ErrInvalidResponse) {
+				t.Fatalf("ParseResponse() error = %v, want ErrInvalidResponse", err)
+			}
+			if !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("ParseResponse() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestServiceProviderParseResponseCommentInjection(t *testing.T) {
+	sp := newTestServiceProvider(t, nil)
+
+	// 正規化ではコメントを除くため、署名を壊さずにNameIDの途中にコメントを挿入できる
+	// コメントの前の文字データだけを読み取ると別のユーザー（victim@example.com）としてログインできてしまう
+	doc := strings.ReplaceAll(readResponse(t, "comment"), "victim@example.com.evil.test<", "victim@example.com<!---->.evil.test<")
+	if !strings.Contains(doc, "<!---->") {
+		t.Fatal("comment was not injected")
+	}
+
+	assertion, err := sp.ParseResponse(encodeResponse(doc), testRequestID, testResponseNow)
+	if err != nil {
+		t.Fatalf("ParseResponse() error = %v", err)
+	}
+	if assertion.NameID != "victim@example.com.evil.test" {
+		t.Errorf("NameID = %q, want %q", assertion.NameID, "victim@example.com.evil.test")
+	}
+	if email := assertion.Attribute("email"); email != "victim@example.com.evil.test" {
+		t.Errorf("email = %q, want %q", email, "victim@example.com.evil.test")
+	}
+}
+
+func TestServiceProviderParseResponseRejectsInvalidContext(t *testing.T) {
+	okta := encodeResponse(readResponse(t, "okta"))
+
+	tests := []struct {
+		name      string
+		mutate    func(*Config)
+		requestID string
+		now       time.Time
+		wantErr   string
+	}{
+		{
+			name:      "other request",
+			requestID: "_fedcba9876543210",
+			now:       testResponseNow,
+			wantErr:   "response does not match the authn request",
+		},
+		{
+			name:      "missing request",
+			requestID: "",
+			now:       testResponseNow,
+			wantErr:   "response does not match the authn request",
+		},
+		{
+			name:      "expired",
+			requestID: testRequestID,
+			now:       time.Date(2026, 1, 2, 3, 8, 0, 0, time.UTC),
+			wantErr:   "assertion is expired",
+		},
+		{
+			name:      "not yet valid",
+			requestID: testRequestID,
+			now:       time.Date(2026, 1, 2, 2, 51, 0, 0, time.UTC),
+			wantErr:   "assertion is not yet valid",
+		},
+		{
+			name:      "other audience",
+			mutate:    func(cfg *Config) { cfg.EntityID = "https://other.example.com/auth/saml/metadata" },
+			requestID: testRequestID,
+			now:       testResponseNow,
+			wantErr:   "assertion is not intended for this service provider",
+		},
+		{
+			name:      "other destination",
+			mutate:    func(cfg *Config) { cfg.ACSURL = "https://other.example.com/auth/saml/acs" },
+			requestID: testRequestID,
+			now:       testResponseNow,
+			wantErr:   "unexpected destination",
+		},
+		{
+			name:      "other idp",
+			mutate:    func(cfg *Config) { cfg.IdPEntityID = "https://other-idp.example.com/metadata" },
+			requestID: testRequestID,
+			now:       testResponseNow,
+			wantErr:   "unexpected issuer",
+		},
+		{
+			name: "other certificate",
+			mutate: func(cfg *Config) {
+				cfg.IdPCertificate = string(readTestdata(t, "other.crt"))
+			},
+			requestID: testRequestID,
+			now:       testResponseNow,
+			wantErr:   "invalid signature",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sp := newTestServiceProvider(t, tt.mutate)
+
+			_, err := sp.ParseResponse(okta, tt.requestID, tt.now)
+			if !errors.Is(err, ErrInvalidResponse) {
+				t.Fatalf("ParseResponse() error = %v, want ErrInvalidResponse", err)
+			}
+			if !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("ParseResponse() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestServiceProviderParseResponseRejectsMalformedInput(t *testing.T) {
+	sp := newTestServiceProvider(t, nil)
+
+	for name, encoded := range map[string]string{
+		"not base64": "not base64!",
+		"not xml":    encodeResponse("not xml"),
+		"empty":      "",
+	} {
+		t.Run(name, func(t *testing.T) {
+			if _, err := sp.ParseResponse(encoded, testRequestID, testResponseNow); !errors.Is(err, ErrInvalidResponse) {
+				t.Errorf("ParseResponse() error = %v, want ErrInvalidResponse", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,71 @@
+/*
+ * c14n はlibxml2でXML文書の部分木を排他的XML正規化（コメントなし）して標準出力に書き出す
+ * SAMLのテストデータの署名と、canonicalizeのテストの期待値の作成に使う
+ *
+ *   c14n FILE XPATH [-enveloped] [-prefixes "a b"]
+ *
+ * XPATHで選んだ最初の要素を頂点とし、-envelopedを指定するとその直下のds:Signatureを除く
+ */
+#include <stdio.h>
+#include <string.h>
+#include <libxml/parser.h>
+#include <libxml/c14n.h>
+#include <libxml/xpath.h>
+#include <libxml/xpathInternals.h>
+
+static xmlNodePtr apex;
+static xmlNodePtr excluded;
+
+static int visible(void *data, xmlNodePtr node, xmlNodePtr parent) {
+	xmlNodePtr n = node->type == XML_NAMESPACE_DECL ? parent : node;
+	for (; n != NULL; n = n->parent) {
+		if (n == excluded) return 0;
+		if (n == apex) return 1;
+	}
+	return 0;
+}
+
+int main(int argc, char **argv) {
+	if (argc < 3) {
+		fprintf(stderr, "usage: %s FILE XPATH [-enveloped] [-prefixes LIST]\n", argv[0]);
+		return 2;
+	}
+	int enveloped = 0;
+	char *prefixes = NULL;
+	for (int i = 3; i < argc; i++) {
+		if (strcmp(argv[i], "-enveloped") == 0) enveloped = 1;
+		else if (strcmp(argv[i], "-prefixes") == 0 && i + 1 < argc) prefixes = argv[++i];
+	}
+
+	xmlDocPtr doc = xmlReadFile(argv[1], NULL, 0);
+	if (doc == NULL) return 1;
+	xmlXPathContextPtr ctx = xmlXPathNewContext(doc);
+	xmlXPathRegisterNs(ctx, BAD_CAST "ds", BAD_CAST "http://www.w3.org/2000/09/xmldsig#");
+	xmlXPathObjectPtr res = xmlXPathEvalExpression(BAD_CAST argv[2], ctx);
+	if (res == NULL || res->nodesetval == NULL || res->nodesetval->nodeNr == 0) {
+		fprintf(stderr, "no element matches %s\n", argv[2]);
+		return 1;
+	}
+	apex = res->nodesetval->nodeTab[0];
+	if (enveloped) {
+		for (xmlNodePtr c = apex->children; c != NULL; c = c->next) {
+			if (c->type == XML_ELEMENT_NODE && c->ns != NULL &&
+				strcmp((char *)c->name, "Signature") == 0 &&
+				strcmp((char *)c->ns->href, "http://www.w3.org/2000/09/xmldsig#") == 0) {
+				excluded = c;
+				break;
+			}
+		}
+	}
+
+	xmlChar *list[64] = {0};
+	if (prefixes != NULL) {
+		int n = 0;
+		for (char *p = strtok(prefixes, " "); p != NULL && n < 63; p = strtok(NULL, " ")) list[n++] = BAD_CAST p;
+	}
+
+	xmlOutputBufferPtr buf = xmlAllocOutputBuffer(NULL);
+	if (xmlC14NExecute(doc, visible, NULL, XML_C14N_EXCLUSIVE_1_0, prefixes != NULL ? list : NULL, 0, buf) < 0) return 1;
+	fwrite(xmlOutputBufferGetContent(buf), 1, xmlOutputBufferGetSize(buf), stdout);
+	return 0;
+}
@@ -0,0 +1,59 @@
+#!/bin/sh
+# generate.sh はSAMLのテストデータを作成する
+#
+# canonicalizeの期待値（c14n/*.c14n）と、templatesのResponseにidp.keyで署名したresponses/*.xmlを書き出す
+# 正規化はlibxml2（c14n.c）、署名はopensslで行い、テスト対象の実装には依存しない
+# idp.key・idp.crtはテスト専用の鍵と証明書、other.crtは署名に使わない別の証明書（証明書の不一致の検証用）
+# 必要なもの: cc、libxml2（xml2-config）、openssl
+set -eu
+cd "$(dirname "$0")"
+
+tmp=$(mktemp -d)
+trap 'rm -rf "$tmp"' EXIT
+cc -o "$tmp/c14n" c14n.c $(xml2-config --cflags --libs) -Wl,-rpath,"$(xml2-config --prefix)/lib"
+c14n="$tmp/c14n"
+
+# canonicalizeの期待値（ID="apex"の要素を頂点とする）
+for name in namespaces attributes enveloped; do
+	"$c14n" "c14n/$name.xml" '//*[@ID="apex"]' -enveloped >"c14n/$name.c14n"
+done
+"$c14n" c14n/inclusive.xml '//*[@ID="apex"]' >c14n/inclusive.c14n
+"$c14n" c14n/inclusive.xml '//*[@ID="apex"]' -prefixes "xs xsi" >c14n/inclusive-prefixes.c14n
+
+# sign FILE ID PLACEHOLDER [PREFIXES] はIDの要素のエンベロープ署名のダイジェストと署名値を埋める
+sign() {
+	file=$1 id=$2 placeholder=$3 prefixes=${4:-}
+	if [ -n "$prefixes" ]; then
+		digest=$("$c14n" "$file" "//*[@ID=\"$id\"]" -enveloped -prefixes "$prefixes" | openssl dgst -sha256 -binary | openssl base64 -A)
+	else
+		digest=$("$c14n" "$file" "//*[@ID=\"$id\"]" -enveloped | openssl dgst -sha256 -binary | openssl base64 -A)
+	fi
+	sed -i "s|${placeholder}_DIGEST|$digest|" "$file"
+	signature=$("$c14n" "$file" "//*[@ID=\"$id\"]/ds:Signature/ds:SignedInfo" | openssl dgst -sha256 -sign idp.key | openssl base64 -A)
+	sed -i "s|${placeholder}_SIGNATURE|$signature|" "$file"
+}
+
+# render TEMPLATE OUTPUT NAME_ID はテンプレートのNameIDと証明書を埋める
+render() {
+	cert=$(grep -v -- '-----' idp.crt | tr -d '\n')
+	sed -e "s|NAME_ID|$3|g" -e "s|CERTIFICATE|$cert|g" "templates/$1.xml" >"responses/$2.xml"
+}
+
+mkdir -p responses
+
+# Oktaの形式（アサーションのみ署名）
+render okta okta user@example.com
+sign responses/okta.xml id1000000000000000002 ASSERTION xs
+
+# AD FSの形式（デフォルト名前空間、ResponseとAssertionの両方に署名）
+render adfs adfs k3v9Q2xYp0wZ8a1b
+sign responses/adfs.xml _5d7a1c2e-0000-4000-8000-000000000002 ASSERTION
+sign responses/adfs.xml _5d7a1c2e-0000-4000-8000-000000000001 RESPONSE
+
+# Shibbolethの形式（Responseのみ署名）
+render shibboleth shibboleth user@example.com
+sign responses/shibboleth.xml _7f3e0000000000000000000000000001 RESPONSE xs
+
+# コメントの挿入の検証用（テストでNameIDにコメントを挿入する）
+render okta comment victim@example.com.evil.test
+sign responses/comment.xml id1000000000000000002 ASSERTION xs
@@ -0,0 +1,291 @@
+package saml
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+const nsXML = "http://www.w3.org/XML/1998/namespace"
+
+// node は署名検証のために名前空間の接頭辞を保持したまま読み込んだXMLの要素
+type node struct {
+	prefix   string
+	local    string
+	attrs    []xml.Attr // Name.Spaceは接頭辞（名前空間宣言はxmlns）
+	children []any      // *node、文字データ（string）または処理命令（procInst）
+	parent   *node
+}
+
+// procInst は要素内の処理命令（正規化の出力に含まれるため保持する）
+type procInst struct {
+	target string
+	inst   string
+}
+
+// parseXML はXMLを要素の木として読み込む（DTDを含む文書は拒否する）
+func parseXML(data []byte) (*node, error) {
+	dec := xml.NewDecoder(bytes.NewReader(data))
+	var root, cur *node
+	for {
+		tok, err := dec.RawToken()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse xml: %w", err)
+		}
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			n := &node{prefix: t.Name.Space, local: t.Name.Local, attrs: t.Copy().Attr, parent: cur}
+			if cur == nil {
+				if root != nil {
+					return nil, errors.New("multiple root elements")
+				}
+				root = n
+			} else {
+				cur.children = append(cur.children, n)
+			}
+			cur = n
+		case xml.EndElement:
+			if cur == nil || cur.prefix != t.Name.Space || cur.local != t.Name.Local {
+				return nil, errors.New("mismatched end element")
+			}
+			cur = cur.parent
+		case xml.CharData:
+			if cur != nil {
+				cur.children = append(cur.children, string(t))
+			}
+		case xml.ProcInst:
+			if cur != nil {
+				cur.children = append(cur.children, procInst{target: t.Target, inst: string(t.Inst)})
+			}
+		case xml.Directive:
+			return nil, errors.New("xml directives are not allowed")
+		}
+	}
+	if root == nil || cur != nil {
+		return nil, errors.New("incomplete xml document")
+	}
+	return root, nil
+}
+
+// lookupNS は接頭辞に対応する名前空間URIを祖先の宣言から探す（""はデフォルト名前空間）
+func (n *node) lookupNS(prefix string) (string, bool) {
+	if prefix == "xml" {
+		return nsXML, true
+	}
+	for e := n; e != nil; e = e.parent {
+		for _, a := range e.attrs {
+			if (prefix == "" && a.Name.Space == "" && a.Name.Local == "xmlns") ||
+				(prefix != "" && a.Name.Space == "xmlns" && a.Name.Local == prefix) {
+				return a.Value, true
+			}
+		}
+	}
+	return "", false
+}
+
+// is は要素の名前空間URIとローカル名が一致するかどうかを返す
+func (n *node) is(ns, local string) bool {
+	uri, _ := n.lookupNS(n.prefix)
+	return n.local == local && uri == ns
+}
+
+// elements は指定した名前の子要素を返す
+func (n *node) elements(ns, local string) []*node {
+	if n == nil {
+		return nil
+	}
+	var out []*node
+	for _, c := range n.children {
+		if e, ok := c.(*node); ok && e.is(ns, local) {
+			out = append(out, e)
+		}
+	}
+	return out
+}
+
+// element は指定した名前の最初の子要素を返す（ない場合はnil）
+func (n *node) element(ns, local string) *node {
+	if es := n.elements(ns, local); len(es) > 0 {
+		return es[0]
+	}
+	return nil
+}
+
+// attr は接頭辞のない属性の値を返す
+func (n *node) attr(local string) string {
+	if n == nil {
+		return ""
+	}
+	for _, a := range n.attrs {
+		if a.Name.Space == "" && a.Name.Local == local {
+			return a.Value
+		}
+	}
+	return ""
+}
+
+// text は直下の文字データを連結して前後の空白を除いた値を返す
+func (n *node) text() string {
+	if n == nil {
+		return ""
+	}
+	var b strings.Builder
+	for _, c := range n.children {
+		if s, ok := c.(string); ok {
+			b.WriteString(s)
+		}
+	}
+	return strings.TrimSpace(b.String())
+}
+
+// walk は自身と子孫の要素を順に訪れる
+func (n *node) walk(fn func(*node)) {
+	fn(n)
+	for _, c := range n.children {
+		if e, ok := c.(*node); ok {
+			e.walk(fn)
+		}
+	}
+}
+
+// isNamespaceDecl は属性が名前空間宣言かどうかを返す
+func isNamespaceDecl(a xml.Attr) bool {
+	return a.Name.Space == "xmlns" || (a.Name.Space == "" && a.Name.Local == "xmlns")
+}
+
+// canonicalize は要素を排他的XML正規化（コメントなし）した結果を返す
+// excludeの要素（エンベロープ署名のSignature）は出力しない。inclusiveは常に出力する名前空間の接頭辞
+func canonicalize(n, exclude *node, inclusive []string) []byte {
+	var b bytes.Buffer
+	writeCanonical(&b, n, exclude, map[string]string{}, inclusive)
+	return b.Bytes()
+}
+
+// writeCanonical は要素を正規化して書き出す
+// renderedは出力済みの祖先で宣言した名前空間（接頭辞からURI）
+func writeCanonical(b *bytes.Buffer, n, exclude *node, rendered map[string]string, inclusive []string) {
+	// 要素と属性で使われている名前空間だけを宣言する
+	used := map[string]bool{n.prefix: true}
+	for _, a := range n.attrs {
+		if !isNamespaceDecl(a) && a.Name.Space != "" {
+			used[a.Name.Space] = true
+		}
+	}
+	for _, p := range inclusive {
+		if p == "#default" {
+			p = ""
+		}
+		if _, ok := n.lookupNS(p); ok {
+			used[p] = true
+		}
+	}
+
+	type nsDecl struct{ prefix, uri string }
+	var decls []nsDecl
+	for p := range used {
+		if p == "xml" {
+			continue
+		}
+		uri, _ := n.lookupNS(p)
+		prev, ok := rendered[p]
+		if (ok && prev == uri) || (!ok && p == "" && uri == "") {
+			continue
+		}
+		decls = append(decls, nsDecl{p, uri})
+	}
+	sort.Slice(decls, func(i, j int) bool { return decls[i].prefix < decls[j].prefix })
+
+	type attr struct{ uri, name, value string }
+	var attrs []attr
+	for _, a := range n.attrs {
+		if isNamespaceDecl(a) {
+			continue
+		}
+		name, uri := a.Name.Local, ""
+		if a.Name.Space != "" {
+			name = a.Name.Space + ":" + a.Name.Local
+			uri, _ = n.lookupNS(a.Name.Space)
+		}
+		attrs = append(attrs, attr{uri, name, a.Value})
+	}
+	sort.Slice(attrs, func(i, j int) bool {
+		if attrs[i].uri != attrs[j].uri {
+			return attrs[i].uri < attrs[j].uri
+		}
+		return localName(attrs[i].name) < localName(attrs[j].name)
+	})
+
+	qname := n.local
+	if n.prefix != "" {
+		qname = n.prefix + ":" + n.local
+	}
+	b.WriteString("<" + qname)
+	childRendered := rendered
+	if len(decls) > 0 {
+		childRendered = make(map[string]string, len(rendered)+len(decls))
+		for k, v := range rendered {
+			childRendered[k] = v
+		}
+	}
+	for _, d := range decls {
+		if d.prefix == "" {
+			b.WriteString(` xmlns="` + escapeAttr(d.uri) + `"`)
+		} else {
+			b.WriteString(" xmlns:" + d.prefix + `="` + escapeAttr(d.uri) + `"`)
+		}
+		childRendered[d.prefix] = d.uri
+	}
+	for _, a := range attrs {
+		b.WriteString(" " + a.name + `="` + escapeAttr(a.value) + `"`)
+	}
+	b.WriteString(">")
+
+	for _, c := range n.children {
+		switch v := c.(type) {
+		case string:
+			b.WriteString(escapeText(v))
+		case *node:
+			if v != exclude {
+				writeCanonical(b, v, exclude, childRendered, inclusive)
+			}
+		case procInst:
+			b.WriteString("<?" + v.target)
+			if v.inst != "" {
+				b.WriteString(" " + v.inst)
+			}
+			b.WriteString("?>")
+		}
+	}
+	b.WriteString("</" + qname + ">")
+}
+
+// localName は修飾名からローカル名を返す
+func localName(qname string) string {
+	if _, local, ok := strings.Cut(qname, ":"); ok {
+		return local
+	}
+	return qname
+}
+
+var (
+	textEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;", "\r", "&#xD;")
+	attrEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", `"`, "&quot;", "\t", "&#x9;", "\n", "&#xA;", "\r", "&#xD;")
+)
+
+// escapeText は正規化の規則で文字データをエスケープする
+func escapeText(s string) string {
+	return textEscaper.Replace(s)
+}
+
+// escapeAttr は正規化の規則で属性値をエスケープする
+func escapeAttr(s string) string {
+	return attrEscaper.Replace(s)
+}
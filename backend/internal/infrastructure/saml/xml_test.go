@@ -0,0 +1,88 @@
+package saml
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// findByID はID属性が一致する要素を返す
+func findByID(root *node, id string) *node {
+	var found *node
+	root.walk(func(n *node) {
+		if found == nil && n.attr("ID") == id {
+			found = n
+		}
+	})
+	return found
+}
+
+func readTestdata(t *testing.T, name string) []byte {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatalf("failed to read testdata: %v", err)
+	}
+	return data
+}
+
+// TestCanonicalize はtestdata/c14nの文書のID="apex"の要素を正規化し、libxml2の排他的XML正規化の結果と比較する
+// 期待値はtestdata/generate.shで作成している
+func TestCanonicalize(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		enveloped bool
+		prefixes  []string
+	}{
+		{name: "namespaces", input: "namespaces", enveloped: true},
+		{name: "attributes", input: "attributes", enveloped: true},
+		{name: "enveloped", input: "enveloped", enveloped: true},
+		{name: "inclusive", input: "inclusive"},
+		{name: "inclusive-prefixes", input: "inclusive", prefixes: []string{"xs", "xsi"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root, err := parseXML(readTestdata(t, "c14n/"+tt.input+".xml"))
+			if err != nil {
+				t.Fatalf("parseXML() error = %v", err)
+			}
+			apex := findByID(root, "apex")
+			if apex == nil {
+				t.Fatal("apex element not found")
+			}
+			var exclude *node
+			if tt.enveloped {
+				exclude = apex.element(nsDSig, "Signature")
+			}
+
+			got := string(canonicalize(apex, exclude, tt.prefixes))
+			want := string(readTestdata(t, "c14n/"+tt.name+".c14n"))
+			if got != want {
+				t.Errorf("canonicalize() mismatch\ngot:\n%s\nwant:\n%s", got, want)
+			}
+		})
+	}
+}
+
+func TestParseXMLRejectsDirectives(t *testing.T) {
+	doc := `<!DOCTYPE r [<!ENTITY x "y">]><r>&x;</r>`
+	if _, err := parseXML([]byte(doc)); err == nil {
+		t.Error("parseXML() with doctype error = nil, want error")
+	}
+}
+
+func TestNodeTextJoinsCommentSplitText(t *testing.T) {
+	root, err := parseXML([]byte(`<r>user@example.com<!---->.evil.test</r>`))
+	if err != nil {
+		t.Fatalf("parseXML() error = %v", err)
+	}
+	// コメントで分割された文字データも正規化と同じく連結した値を返す
+	if got := root.text(); got != "user@example.com.evil.test" {
+		t.Errorf("text() = %q, want %q", got, "user@example.com.evil.test")
+	}
+	if strings.Contains(string(canonicalize(root, nil, nil)), "<!--") {
+		t.Error("canonicalize() kept the comment")
+	}
+}
@@ -0,0 +1,190 @@
+package session
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+const (
+	// tokenLength はCookieに保存するトークンの長さ
+	tokenLength = 43
+	// defaultMaxAge はOptions.MaxAgeが0の場合にサーバー側で保持する期間（秒）
+	defaultMaxAge = 60 * 60 * 24 * 7
+	// valueKeyUserID・valueKeySessionID はログイン時にhandlerが設定するセッションの値のキー
+	valueKeyUserID    = "user_id"
+	valueKeySessionID = "session_id"
+)
+
+// Record はサーバー側に保存する1つのセッション
+type Record struct {
+	// ID はCookieのトークンのSHA-256（16進数）
+	ID string
+	// UserID はログインしたユーザーのID（ログイン前は空）
+	UserID string
+	// UserSessionID はログインセッションの記録ID（記録に失敗した場合は空）
+	UserSessionID string
+	Values        map[string]any
+	ExpiresAt     time.Time
+}
+
+// Backend はServerStoreがセッションを保存する先
+type Backend interface {
+	// Find は有効期限内のセッションを検索する（見つからない場合はErrInvalidSession）
+	Find(ctx context.Context, id string, now time.Time) (*Record, error)
+	// Save はセッションを作成・更新する
+	Save(ctx context.Context, record *Record) error
+	// Delete はセッションを削除する
+	Delete(ctx context.Context, id string) error
+	// DeleteExpired は有効期限を過ぎたセッションを削除し、削除した件数を返す
+	DeleteExpired(ctx context.Context, now time.Time) (int64, error)
+}
+
+// ServerStore はセッションの値をサーバー側（Backend）に保存し、Cookieにはランダムなトークンのみを保存するセッションストア
+// サーバー側のセッションを削除すればCookieを持つ端末をログアウトさせられる
+type ServerStore struct {
+	backend Backend
+	logger  *slog.Logger
+	Secure  bool // 本番環境ではtrueに設定
+}
+
+// NewServerStore は新しいServerStoreを作成する
+func NewServerStore(backend Backend, logger *slog.Logger) *ServerStore {
+	return &ServerStore{
+		backend: backend,
+		logger:  logger,
+	}
+}
+
+// Get はリクエストのCookieのトークンに対応するセッションを取得する
+// Cookieがない・セッションが失効している場合は新しいセッションを返す
+// 保存先からの取得に失敗した場合も新しいセッションをエラーと合わせて返す
+func (s *ServerStore) Get(r *http.Request, name string) (*Session, error) {
+	sess := s.newSession()
+
+	cookie, err := r.Cookie(name)
+	if err != nil || cookie.Value == "" {
+		return sess, nil
+	}
+
+	record, err := s.backend.Find(r.Context(), hashToken(cookie.Value), time.Now())
+	if errors.Is(err, ErrInvalidSession) {
+		return sess, nil
+	}
+	if err != nil {
+		return sess, fmt.Errorf("failed to find session: %w", err)
+	}
+
+	if record.Values != nil {
+		sess.Values = record.Values
+	}
+	sess.token = cookie.Value
+	sess.userID = record.UserID
+	return sess, nil
+}
+
+// Save はセッションをサーバー側に保存し、トークンをCookieに保存する
+// ログイン・ログアウトでユーザーが変わる場合はセッション固定攻撃を防ぐためトークンを発行し直す
+func (s *ServerStore) Save(w http.ResponseWriter, r *http.Request, name string, session *Session) error {
+	ctx := r.Context()
+	userID, _ := session.GetString(valueKeyUserID)
+
+	if session.token == "" || userID != session.userID {
+		if session.token != "" {
+			if err := s.backend.Delete(ctx, hashToken(session.token)); err != nil {
+				return fmt.Errorf("failed to delete previous session: %w", err)
+			}
+		}
+		token, err := GenerateRandomString(tokenLength)
+		if err != nil {
+			return fmt.Errorf("failed to generate session token: %w", err)
+		}
+		session.token = token
+	}
+
+	maxAge := session.Options.MaxAge
+	if maxAge <= 0 {
+		maxAge = defaultMaxAge
+	}
+	userSessionID, _ := session.GetString(valueKeySessionID)
+	record := &Record{
+		ID:            hashToken(session.token),
+		UserID:        userID,
+		UserSessionID: userSessionID,
+		Values:        session.Values,
+		ExpiresAt:     time.Now().Add(time.Duration(maxAge) * time.Second),
+	}
+	if err := s.backend.Save(ctx, record); err != nil {
+		return fmt.Errorf("failed to save session: %w", err)
+	}
+	session.userID = userID
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     name,
+		Value:    session.token,
+		Path:     session.Options.Path,
+		MaxAge:   session.Options.MaxAge,
+		HttpOnly: session.Options.HttpOnly,
+		Secure:   session.Options.Secure,
+		SameSite: session.Options.SameSite,
+	})
+	return nil
+}
+
+// Delete はサーバー側のセッションとCookieを削除する（サーバー側の削除に失敗した場合はログに記録するのみ）
+func (s *ServerStore) Delete(w http.ResponseWriter, r *http.Request, name string) {
+	if cookie, err := r.Cookie(name); err == nil && cookie.Value != "" {
+		if err := s.backend.Delete(r.Context(), hashToken(cookie.Value)); err != nil {
+			s.logger.ErrorContext(r.Context(), "failed to delete session", "error", err)
+		}
+	}
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     name,
+		Value:    "",
+		Path:     "/",
+		MaxAge:   -1,
+		HttpOnly: true,
+	})
+}
+
+// PurgeExpired は有効期限を過ぎたセッションをサーバー側から削除する
+func (s *ServerStore) PurgeExpired(ctx context.Context, now time.Time) error {
+	deleted, err := s.backend.DeleteExpired(ctx, now)
+	if err != nil {
+		return fmt.Errorf("failed to purge expired sessions: %w", err)
+	}
+	if deleted > 0 {
+		s.logger.InfoContext(ctx, "purged expired sessions", "count", deleted)
+	}
+	return nil
+}
+
+// newSession はCookieのオプションを設定した空のセッションを作成する
+func (s *ServerStore) newSession() *Session {
+	sameSite := http.SameSiteLaxMode
+	if s.Secure {
+		sameSite = http.SameSiteNoneMode
+	}
+	return &Session{
+		Values: make(map[string]any),
+		Options: &Options{
+			Path:     "/",
+			MaxAge:   defaultMaxAge,
+			HttpOnly: true,
+			Secure:   s.Secure,
+			SameSite: sameSite,
+		},
+	}
+}
+
+// hashToken はトークンから保存先のIDを求める（保存先が漏洩してもCookieを復元できないようにする）
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -19,13 +19,18 @@ var ErrInvalidSession = errors.New("invalid session")
 type Store interface {
 	Get(r *http.Request, name string) (*Session, error)
 	Save(w http.ResponseWriter, r *http.Request, name string, session *Session) error
-	Delete(w http.ResponseWriter, name string)
+	Delete(w http.ResponseWriter, r *http.Request, name string)
 }
 
 // Session はセッションデータを保持する
 type Session struct {
 	Values  map[string]any
 	Options *Options
+
+	// token はServerStoreでCookieに保存するトークン（新しいセッションの場合は空）
+	token string
+	// userID はServerStoreで読み込んだ時点のユーザーID（ログインでの変化の検知に使う）
+	userID string
 }
 
 // Options はCookieのオプション
@@ -113,7 +118,7 @@ func (s *CookieStore) Save(w http.ResponseWriter, r *http.Request, name string,
 }
 
 // Delete はセッションCookieを削除する
-func (s *CookieStore) Delete(w http.ResponseWriter, name string) {
+func (s *CookieStore) Delete(w http.ResponseWriter, r *http.Request, name string) {
 	http.SetCookie(w, &http.Cookie{
 		Name:     name,
 		Value:    "",
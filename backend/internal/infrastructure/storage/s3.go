@@ -0,0 +1,285 @@
+package storage
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/sikigasa/github-task-controller/backend/internal/domain/model"
+)
+
+const (
+	// gcsEndpoint はGCSのS3互換（XML）APIのエンドポイント
+	gcsEndpoint = "https://storage.googleapis.com"
+	// maxSignedURLTTL は署名付きURLの有効期限の上限（SigV4の上限）
+	maxSignedURLTTL = 7 * 24 * time.Hour
+)
+
+// S3Config はS3互換のバケットの設定
+type S3Config struct {
+	Bucket string
+	// Endpoint はAPIのURL（空の場合はAWSのリージョンのエンドポイント、MinIO等では指定する）
+	Endpoint string
+	Region   string
+	// AccessKeyID・SecretAccessKey はアクセスキー（GCSの場合はHMACキー）
+	AccessKeyID     string
+	SecretAccessKey string
+	// PathStyle を有効にするとバケットをホスト名ではなくパスに含める（MinIO等）
+	PathStyle bool
+	// SignedURLTTL は署名付きURLの有効期限（0の場合は発行しない）
+	SignedURLTTL time.Duration
+}
+
+// S3 はS3互換のAPIでバケットにファイルを保存するStorage（GCSはHMACキーでXML APIを使う）
+type S3 struct {
+	endpoint     *url.URL
+	bucket       string
+	pathStyle    bool
+	signedURLTTL time.Duration
+	signer       *sigV4Signer
+	httpClient   *http.Client
+}
+
+// s3ListResult はListObjectsV2の応答
+type s3ListResult struct {
+	IsTruncated           bool   `xml:"IsTruncated"`
+	NextContinuationToken string `xml:"NextContinuationToken"`
+	Contents              []struct {
+		Key          string    `xml:"Key"`
+		Size         int64     `xml:"Size"`
+		LastModified time.Time `xml:"LastModified"`
+	} `xml:"Contents"`
+}
+
+// s3Error はエラー応答
+type s3Error struct {
+	Code    string `xml:"Code"`
+	Message string `xml:"Message"`
+}
+
+// NewS3 は新しいS3を作成する
+func NewS3(cfg S3Config) (*S3, error) {
+	if cfg.Bucket == "" {
+		return nil, fmt.Errorf("storage bucket is required")
+	}
+	if cfg.AccessKeyID == "" || cfg.SecretAccessKey == "" {
+		return nil, fmt.Errorf("storage access key is required")
+	}
+	if cfg.Region == "" {
+		cfg.Region = "us-east-1"
+	}
+	if cfg.Endpoint == "" {
+		cfg.Endpoint = "https://s3." + cfg.Region + ".amazonaws.com"
+	}
+	endpoint, err := url.Parse(cfg.Endpoint)
+	if err != nil || endpoint.Host == "" {
+		return nil, fmt.Errorf("invalid storage endpoint: %s", cfg.Endpoint)
+	}
+	if cfg.SignedURLTTL > maxSignedURLTTL {
+		return nil, fmt.Errorf("signed url ttl must not exceed %s", maxSignedURLTTL)
+	}
+
+	return &S3{
+		endpoint:     endpoint,
+		bucket:       cfg.Bucket,
+		pathStyle:    cfg.PathStyle,
+		signedURLTTL: cfg.SignedURLTTL,
+		signer: &sigV4Signer{
+			accessKeyID:     cfg.AccessKeyID,
+			secretAccessKey: cfg.SecretAccessKey,
+			region:          cfg.Region,
+		},
+		httpClient: &http.Client{Timeout: 10 * time.Minute},
+	}, nil
+}
+
+// NewGCS はGCSのバケットに保存するS3を作成する（EndpointとRegionはGCSの値を使う）
+func NewGCS(cfg S3Config) (*S3, error) {
+	cfg.Endpoint = gcsEndpoint
+	cfg.Region = "auto"
+	cfg.PathStyle = true
+	return NewS3(cfg)
+}
+
+// objectURL はキーのURLを返す（空のキーはバケットを指すため拒否する）
+func (s *S3) objectURL(key string) (*url.URL, error) {
+	if err := validateKey(key); err != nil {
+		return nil, err
+	}
+	return s.url(key), nil
+}
+
+// bucketURL はバケットのURLを返す
+func (s *S3) bucketURL() *url.URL {
+	return s.url("")
+}
+
+func (s *S3) url(key string) *url.URL {
+	u := *s.endpoint
+	p := strings.TrimSuffix(u.Path, "/") + "/"
+	if s.pathStyle {
+		p += s.bucket + "/"
+	} else {
+		u.Host = s.bucket + "." + u.Host
+	}
+	u.Path = p + key
+	// 署名したパスとリクエストのパスのエンコードを一致させる
+	u.RawPath = awsURIEncode(u.Path, false)
+	return &u
+}
+
+// do は署名したリクエストを送信し、成功しなかった場合はエラーを返す（404はmodel.ErrNotFound）
+func (s *S3) do(ctx context.Context, method string, u *url.URL, body io.Reader, size int64, payloadHash string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create storage request: %w", err)
+	}
+	req.ContentLength = size
+	s.signer.sign(req, payloadHash, time.Now())
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send storage request: %w", err)
+	}
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return resp, nil
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("storage object not found: %s: %w", u.Path, model.ErrNotFound)
+	}
+	var e s3Error
+	data, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+	if xml.Unmarshal(data, &e) == nil && e.Code != "" {
+		return nil, fmt.Errorf("storage returned %d: %s: %s", resp.StatusCode, e.Code, e.Message)
+	}
+	return nil, fmt.Errorf("storage returned %d", resp.StatusCode)
+}
+
+// Put はrの内容を一時ファイルに書き出してから送信する（署名に本文のハッシュと長さが必要なため）
+// 1回のリクエストで送信するため、5GBを超えるファイルは保存できない
+func (s *S3) Put(ctx context.Context, key string, r io.Reader) error {
+	u, err := s.objectURL(key)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp("", "storage-upload-*")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	hash := sha256.New()
+	size, err := io.Copy(io.MultiWriter(tmp, hash), r)
+	if err != nil {
+		return fmt.Errorf("failed to write %s: %w", key, err)
+	}
+	if _, err := tmp.Seek(0, io.SeekStart); err != nil {
+		return fmt.Errorf("failed to write %s: %w", key, err)
+	}
+
+	resp, err := s.do(ctx, http.MethodPut, u, tmp, size, hex.EncodeToString(hash.Sum(nil)))
+	if err != nil {
+		return fmt.Errorf("failed to save %s: %w", key, err)
+	}
+	resp.Body.Close()
+	return nil
+}
+
+func (s *S3) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	u, err := s.objectURL(key)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := s.do(ctx, http.MethodGet, u, nil, 0, emptyPayloadHash)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", key, err)
+	}
+	return resp.Body, nil
+}
+
+// Delete はキーを削除する（S3のDELETEは存在しないキーでも成功するため、先に存在を確認する）
+func (s *S3) Delete(ctx context.Context, key string) error {
+	u, err := s.objectURL(key)
+	if err != nil {
+		return err
+	}
+	resp, err := s.do(ctx, http.MethodHead, u, nil, 0, emptyPayloadHash)
+	if err != nil {
+		return fmt.Errorf("failed to delete %s: %w", key, err)
+	}
+	resp.Body.Close()
+
+	resp, err = s.do(ctx, http.MethodDelete, u, nil, 0, emptyPayloadHash)
+	if err != nil {
+		return fmt.Errorf("failed to delete %s: %w", key, err)
+	}
+	resp.Body.Close()
+	return nil
+}
+
+func (s *S3) List(ctx context.Context, prefix string) ([]Object, error) {
+	var objects []Object
+	token := ""
+	for {
+		u := s.bucketURL()
+		query := url.Values{"list-type": {"2"}, "prefix": {prefix}}
+		if token != "" {
+			query.Set("continuation-token", token)
+		}
+		u.RawQuery = canonicalQuery(query)
+
+		resp, err := s.do(ctx, http.MethodGet, u, nil, 0, emptyPayloadHash)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list storage objects: %w", err)
+		}
+		var result s3ListResult
+		err = xml.NewDecoder(resp.Body).Decode(&result)
+		resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse storage object list: %w", err)
+		}
+
+		for _, c := range result.Contents {
+			objects = append(objects, Object{Key: c.Key, Size: c.Size, ModifiedAt: c.LastModified})
+		}
+		if !result.IsTruncated || result.NextContinuationToken == "" {
+			return objects, nil
+		}
+		token = result.NextContinuationToken
+	}
+}
+
+// SignedURL はSignedURLTTLの間だけGETできる署名付きURLを発行する
+func (s *S3) SignedURL(ctx context.Context, key string, download Download) (string, error) {
+	if s.signedURLTTL <= 0 {
+		return "", ErrSignedURLUnsupported
+	}
+	u, err := s.objectURL(key)
+	if err != nil {
+		return "", err
+	}
+
+	query := url.Values{}
+	if download.FileName != "" {
+		query.Set("response-content-disposition", mime.FormatMediaType("attachment", map[string]string{"filename": download.FileName}))
+	}
+	if download.ContentType != "" {
+		query.Set("response-content-type", download.ContentType)
+	}
+	u.RawQuery = query.Encode()
+
+	return s.signer.presign(u, s.signedURLTTL, time.Now()), nil
+}
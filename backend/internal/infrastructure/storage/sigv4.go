@@ -0,0 +1,159 @@
+package storage
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// sigV4Algorithm はAWS Signature Version 4の署名方式（GCSもHMACキーで同じ方式を受け付ける）
+	sigV4Algorithm = "AWS4-HMAC-SHA256"
+	// sigV4TimeFormat はx-amz-dateの形式
+	sigV4TimeFormat = "20060102T150405Z"
+	// unsignedPayload は本文のハッシュを署名に含めない場合の値（署名付きURLで使う）
+	unsignedPayload = "UNSIGNED-PAYLOAD"
+	// emptyPayloadHash は本文が空の場合のSHA-256
+	emptyPayloadHash = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+)
+
+// sigV4Signer はS3互換のAPIへのリクエストにAWS Signature Version 4で署名する
+type sigV4Signer struct {
+	accessKeyID     string
+	secretAccessKey string
+	region          string
+}
+
+// sign はリクエストのヘッダーに署名を付ける（payloadHashは本文のSHA-256の16進数）
+func (s *sigV4Signer) sign(req *http.Request, payloadHash string, now time.Time) {
+	amzDate := now.UTC().Format(sigV4TimeFormat)
+	req.Header.Set("x-amz-date", amzDate)
+	req.Header.Set("x-amz-content-sha256", payloadHash)
+
+	headers := map[string]string{
+		"host":                 req.URL.Host,
+		"x-amz-date":           amzDate,
+		"x-amz-content-sha256": payloadHash,
+	}
+	signedHeaders, canonicalHeaders := canonicalizeHeaders(headers)
+	scope := s.scope(now)
+	signature := s.signature(now, scope, req.Method, req.URL, canonicalQuery(req.URL.Query()), canonicalHeaders, signedHeaders, payloadHash)
+
+	req.Header.Set("Authorization", sigV4Algorithm+
+		" Credential="+s.accessKeyID+"/"+scope+
+		", SignedHeaders="+signedHeaders+
+		", Signature="+signature)
+}
+
+// presign はu（GET）にexpiryの間だけ有効な署名をクエリパラメータとして付けたURLを返す
+func (s *sigV4Signer) presign(u *url.URL, expiry time.Duration, now time.Time) string {
+	scope := s.scope(now)
+	query := u.Query()
+	query.Set("X-Amz-Algorithm", sigV4Algorithm)
+	query.Set("X-Amz-Credential", s.accessKeyID+"/"+scope)
+	query.Set("X-Amz-Date", now.UTC().Format(sigV4TimeFormat))
+	query.Set("X-Amz-Expires", strconv.Itoa(int(expiry.Seconds())))
+	query.Set("X-Amz-SignedHeaders", "host")
+
+	signedHeaders, canonicalHeaders := canonicalizeHeaders(map[string]string{"host": u.Host})
+	canonical := canonicalQuery(query)
+	signature := s.signature(now, scope, http.MethodGet, u, canonical, canonicalHeaders, signedHeaders, unsignedPayload)
+
+	signed := *u
+	signed.RawQuery = canonical + "&X-Amz-Signature=" + signature
+	return signed.String()
+}
+
+// scope は署名の対象範囲（日付/リージョン/サービス/aws4_request）
+func (s *sigV4Signer) scope(now time.Time) string {
+	return now.UTC().Format("20060102") + "/" + s.region + "/s3/aws4_request"
+}
+
+// signature は正規化したリクエストから署名を計算する
+func (s *sigV4Signer) signature(now time.Time, scope, method string, u *url.URL, query, canonicalHeaders, signedHeaders, payloadHash string) string {
+	canonicalRequest := strings.Join([]string{
+		method,
+		awsURIEncode(u.Path, false),
+		query,
+		canonicalHeaders,
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+	requestHash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := strings.Join([]string{
+		sigV4Algorithm,
+		now.UTC().Format(sigV4TimeFormat),
+		scope,
+		hex.EncodeToString(requestHash[:]),
+	}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+s.secretAccessKey), now.UTC().Format("20060102"))
+	key = hmacSHA256(key, s.region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	return hex.EncodeToString(hmacSHA256(key, stringToSign))
+}
+
+// canonicalizeHeaders は署名するヘッダーの名前の一覧と正規化したヘッダーを返す（名前は小文字）
+func canonicalizeHeaders(headers map[string]string) (signed, canonical string) {
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	var b strings.Builder
+	for _, name := range names {
+		b.WriteString(name + ":" + strings.TrimSpace(headers[name]) + "\n")
+	}
+	return strings.Join(names, ";"), b.String()
+}
+
+// canonicalQuery はクエリパラメータをキーの順に並べてエンコードする
+func canonicalQuery(query url.Values) string {
+	keys := make([]string, 0, len(query))
+	for key := range query {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+
+	var parts []string
+	for _, key := range keys {
+		values := slices.Clone(query[key])
+		slices.Sort(values)
+		for _, value := range values {
+			parts = append(parts, awsURIEncode(key, true)+"="+awsURIEncode(value, true))
+		}
+	}
+	return strings.Join(parts, "&")
+}
+
+// awsURIEncode は非予約文字（A-Z a-z 0-9 - _ . ~）以外をパーセントエンコードする
+// encodeSlashがfalseの場合はパスの区切りの"/"をそのまま残す
+func awsURIEncode(s string, encodeSlash bool) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case 'A' <= c && c <= 'Z', 'a' <= c && c <= 'z', '0' <= c && c <= '9', c == '-', c == '_', c == '.', c == '~':
+			b.WriteByte(c)
+		case c == '/' && !encodeSlash:
+			b.WriteByte(c)
+		default:
+			b.WriteString("%" + strings.ToUpper(hex.EncodeToString([]byte{c})))
+		}
+	}
+	return b.String()
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,106 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"time"
+
+	"github.com/sikigasa/github-task-controller/backend/internal/domain/model"
+)
+
+// ErrSignedURLUnsupported は署名付きURLを発行できない保存先（ローカル・有効期限が未設定）の場合のエラー
+// 呼び出し側はGetで読み出してAPIから返す
+var ErrSignedURLUnsupported = errors.New("signed url is not supported by this storage")
+
+// Object は保存したファイルの情報
+type Object struct {
+	Key        string
+	Size       int64
+	ModifiedAt time.Time
+}
+
+// Download は署名付きURLからダウンロードする際のレスポンスの指定
+type Download struct {
+	// FileName はダウンロード時のファイル名（Content-Disposition）
+	FileName string
+	// ContentType はレスポンスのContent-Type（空の場合は保存先の既定値）
+	ContentType string
+}
+
+// Storage はファイルの保存先のインターフェース
+// キーは"/"区切りのパス（例: backups/backup-20260101-000000.000-manual.jsonl.gz）
+type Storage interface {
+	// Put はrの内容をキーに保存する（同じキーがある場合は上書きする）
+	Put(ctx context.Context, key string, r io.Reader) error
+	// Get はキーの内容を読み出す（存在しない場合はmodel.ErrNotFound）
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	// Delete はキーを削除する（存在しない場合はmodel.ErrNotFound）
+	Delete(ctx context.Context, key string) error
+	// List はprefixで始まるキーを一覧する
+	List(ctx context.Context, prefix string) ([]Object, error)
+	// SignedURL はキーを認証なしで一定時間ダウンロードできるURLを発行する（対応しない場合はErrSignedURLUnsupported）
+	SignedURL(ctx context.Context, key string, download Download) (string, error)
+}
+
+// Config はファイルの保存先の設定
+type Config struct {
+	// Backend は保存先の種類（local・s3・gcs）
+	Backend string
+	// Dir はlocalの場合に保存するディレクトリ
+	Dir string
+	// S3 はs3・gcsの場合のバケットの設定
+	S3 S3Config
+}
+
+// New は設定に応じたStorageを作成する
+func New(cfg Config) (Storage, error) {
+	var s Storage
+	var err error
+	switch cfg.Backend {
+	case "local":
+		s, err = NewLocal(cfg.Dir)
+	case "s3":
+		s, err = NewS3(cfg.S3)
+	case "gcs":
+		s, err = NewGCS(cfg.S3)
+	default:
+		return nil, fmt.Errorf("unknown storage backend: %s", cfg.Backend)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// validateKey は正規化されていないキーや"."・".."を含むキーを拒否する
+func validateKey(key string) error {
+	cleaned := path.Clean("/" + key)
+	if key == "" || cleaned == "/" || cleaned != "/"+key {
+		return fmt.Errorf("invalid storage key %q: %w", key, model.ErrInvalidInput)
+	}
+	return nil
+}
+
+// DeleteBefore はprefixで始まるキーのうち、beforeより前に保存したものを削除して削除した件数を返す
+// 一覧の後に別の処理が削除したキーは無視する
+func DeleteBefore(ctx context.Context, s Storage, prefix string, before time.Time) (int, error) {
+	objects, err := s.List(ctx, prefix)
+	if err != nil {
+		return 0, err
+	}
+
+	deleted := 0
+	for _, obj := range objects {
+		if !obj.ModifiedAt.Before(before) {
+			continue
+		}
+		if err := s.Delete(ctx, obj.Key); err != nil && !errors.Is(err, model.ErrNotFound) {
+			return deleted, err
+		}
+		deleted++
+	}
+	return deleted, nil
+}
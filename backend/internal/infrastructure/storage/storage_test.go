@@ -0,0 +1,110 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sikigasa/github-task-controller/backend/internal/domain/model"
+)
+
+// 保存先の外を指すキーや正規化されていないキー
+var invalidKeys = []string{
+	"",
+	".",
+	"..",
+	"../x",
+	"a/../../x",
+	"a/../b",
+	"/abs",
+	"/etc/passwd",
+	"a//b",
+	"a/./b",
+	"a/",
+	"./a",
+}
+
+func TestValidateKey(t *testing.T) {
+	for _, key := range invalidKeys {
+		if err := validateKey(key); !errors.Is(err, model.ErrInvalidInput) {
+			t.Errorf("validateKey(%q) error = %v, want ErrInvalidInput", key, err)
+		}
+	}
+
+	for _, key := range []string{"backups/2026-01-01.json", "a", "a/b/c", "a..b", "..a"} {
+		if err := validateKey(key); err != nil {
+			t.Errorf("validateKey(%q) error = %v, want nil", key, err)
+		}
+	}
+}
+
+func TestLocalRejectsKeyOutsideDirectory(t *testing.T) {
+	root := t.TempDir()
+	s, err := NewLocal(filepath.Join(root, "storage"))
+	if err != nil {
+		t.Fatalf("NewLocal() error = %v", err)
+	}
+	ctx := context.Background()
+
+	for _, key := range invalidKeys {
+		if err := s.Put(ctx, key, strings.NewReader("data")); !errors.Is(err, model.ErrInvalidInput) {
+			t.Errorf("Put(%q) error = %v, want ErrInvalidInput", key, err)
+		}
+		if _, err := s.Get(ctx, key); !errors.Is(err, model.ErrInvalidInput) {
+			t.Errorf("Get(%q) error = %v, want ErrInvalidInput", key, err)
+		}
+		if err := s.Delete(ctx, key); !errors.Is(err, model.ErrInvalidInput) {
+			t.Errorf("Delete(%q) error = %v, want ErrInvalidInput", key, err)
+		}
+	}
+
+	// 保存先の外には何も書き込まれていない
+	entries, err := os.ReadDir(root)
+	if err != nil {
+		t.Fatalf("failed to read directory: %v", err)
+	}
+	if len(entries) != 1 || entries[0].Name() != "storage" {
+		t.Errorf("entries = %v, want only the storage directory", entries)
+	}
+}
+
+func TestLocalPutGet(t *testing.T) {
+	s, err := NewLocal(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewLocal() error = %v", err)
+	}
+	ctx := context.Background()
+
+	if err := s.Put(ctx, "backups/a.json", strings.NewReader("data")); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+	r, err := s.Get(ctx, "backups/a.json")
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	defer r.Close()
+	got, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatalf("failed to read object: %v", err)
+	}
+	if string(got) != "data" {
+		t.Errorf("Get() = %q, want %q", got, "data")
+	}
+}
+
+func TestS3RejectsKeyOutsideBucket(t *testing.T) {
+	s, err := NewS3(S3Config{Bucket: "examplebucket", AccessKeyID: "key", SecretAccessKey: "secret"})
+	if err != nil {
+		t.Fatalf("NewS3() error = %v", err)
+	}
+
+	for _, key := range invalidKeys {
+		if _, err := s.objectURL(key); !errors.Is(err, model.ErrInvalidInput) {
+			t.Errorf("objectURL(%q) error = %v, want ErrInvalidInput", key, err)
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package token
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ErrInvalidToken はアクセストークンの形式・署名・有効期限が不正な場合のエラー
+var ErrInvalidToken = errors.New("invalid access token")
+
+// jwtHeader はHS256で署名したJWTのヘッダー（固定値）
+var jwtHeader = base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"HS256","typ":"JWT"}`))
+
+// Claims はアクセストークンに含める情報
+type Claims struct {
+	// Subject はユーザーID
+	Subject string `json:"sub"`
+	// SessionID はトークンを発行したログインセッションの記録ID
+	SessionID string `json:"sid"`
+	IssuedAt  int64  `json:"iat"`
+	ExpiresAt int64  `json:"exp"`
+}
+
+// Signer はHS256で署名したJWT形式のアクセストークンを発行・検証する
+type Signer struct {
+	secret []byte
+}
+
+// NewSigner は新しいSignerを作成する
+func NewSigner(secret []byte) *Signer {
+	return &Signer{secret: secret}
+}
+
+// Sign はクレームに署名したアクセストークンを返す
+func (s *Signer) Sign(claims Claims) (string, error) {
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal claims: %w", err)
+	}
+
+	unsigned := jwtHeader + "." + base64.RawURLEncoding.EncodeToString(payload)
+	return unsigned + "." + s.sign(unsigned), nil
+}
+
+// Verify は署名と有効期限を検証してクレームを返す
+func (s *Signer) Verify(token string, now time.Time) (*Claims, error) {
+	header, rest, ok := strings.Cut(token, ".")
+	if !ok || header != jwtHeader {
+		return nil, ErrInvalidToken
+	}
+	payload, signature, ok := strings.Cut(rest, ".")
+	if !ok {
+		return nil, ErrInvalidToken
+	}
+
+	if !hmac.Equal([]byte(signature), []byte(s.sign(header+"."+payload))) {
+		return nil, ErrInvalidToken
+	}
+
+	data, err := base64.RawURLEncoding.DecodeString(payload)
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+	var claims Claims
+	if err := json.Unmarshal(data, &claims); err != nil {
+		return nil, ErrInvalidToken
+	}
+	if claims.Subject == "" || now.Unix() >= claims.ExpiresAt {
+		return nil, ErrInvalidToken
+	}
+
+	return &claims, nil
+}
+
+// sign はHMAC-SHA256の署名をBase64URLエンコードして返す
+func (s *Signer) sign(value string) string {
+	h := hmac.New(sha256.New, s.secret)
+	h.Write([]byte(value))
+	return base64.RawURLEncoding.EncodeToString(h.Sum(nil))
+}
@@ -0,0 +1,147 @@
+package token
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+var testNow = time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+
+func testClaims() Claims {
+	return Claims{
+		Subject:   "user-1",
+		SessionID: "session-1",
+		IssuedAt:  testNow.Unix(),
+		ExpiresAt: testNow.Add(15 * time.Minute).Unix(),
+	}
+}
+
+func signTestToken(t *testing.T, s *Signer, claims Claims) string {
+	t.Helper()
+	token, err := s.Sign(claims)
+	if err != nil {
+		t.Fatalf("Sign() error = %v", err)
+	}
+	return token
+}
+
+// hs256 はテスト用に任意のヘッダー・ペイロードのトークンをsecretで署名する
+func hs256(secret []byte, header, payload string) string {
+	unsigned := base64.RawURLEncoding.EncodeToString([]byte(header)) + "." + base64.RawURLEncoding.EncodeToString([]byte(payload))
+	h := hmac.New(sha256.New, secret)
+	h.Write([]byte(unsigned))
+	return unsigned + "." + base64.RawURLEncoding.EncodeToString(h.Sum(nil))
+}
+
+func TestSignerVerify(t *testing.T) {
+	s := NewSigner([]byte("test-secret"))
+	token := signTestToken(t, s, testClaims())
+
+	claims, err := s.Verify(token, testNow)
+	if err != nil {
+		t.Fatalf("Verify() error = %v", err)
+	}
+	if *claims != testClaims() {
+		t.Errorf("Verify() = %+v, want %+v", *claims, testClaims())
+	}
+}
+
+func TestSignerVerifyRejectsTamperedToken(t *testing.T) {
+	s := NewSigner([]byte("test-secret"))
+	token := signTestToken(t, s, testClaims())
+	parts := strings.Split(token, ".")
+
+	otherClaims := testClaims()
+	otherClaims.Subject = "user-2"
+	otherPayload := strings.Split(signTestToken(t, s, otherClaims), ".")[1]
+
+	// 署名の最後の文字を別の文字に置き換える
+	last := parts[2][len(parts[2])-1]
+	replacement := "A"
+	if last == 'A' {
+		replacement = "B"
+	}
+
+	tests := map[string]string{
+		"signature":         parts[0] + "." + parts[1] + "." + parts[2][:len(parts[2])-1] + replacement,
+		"payload":           parts[0] + "." + otherPayload + "." + parts[2],
+		"missing signature": parts[0] + "." + parts[1] + ".",
+		"two segments":      parts[0] + "." + parts[1],
+		"extra segment":     token + "." + parts[2],
+		"other secret":      signTestToken(t, NewSigner([]byte("other-secret")), testClaims()),
+		"empty":             "",
+	}
+	for name, tampered := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := s.Verify(tampered, testNow); !errors.Is(err, ErrInvalidToken) {
+				t.Errorf("Verify() error = %v, want ErrInvalidToken", err)
+			}
+		})
+	}
+}
+
+func TestSignerVerifyRejectsExpiredToken(t *testing.T) {
+	s := NewSigner([]byte("test-secret"))
+	claims := testClaims()
+	token := signTestToken(t, s, claims)
+
+	for name, now := range map[string]time.Time{
+		"at expiry":    time.Unix(claims.ExpiresAt, 0),
+		"after expiry": time.Unix(claims.ExpiresAt, 0).Add(time.Second),
+	} {
+		t.Run(name, func(t *testing.T) {
+			if _, err := s.Verify(token, now); !errors.Is(err, ErrInvalidToken) {
+				t.Errorf("Verify() error = %v, want ErrInvalidToken", err)
+			}
+		})
+	}
+
+	if _, err := s.Verify(token, time.Unix(claims.ExpiresAt, 0).Add(-time.Second)); err != nil {
+		t.Errorf("Verify() just before expiry error = %v", err)
+	}
+}
+
+func TestSignerVerifyEnforcesAlgorithm(t *testing.T) {
+	secret := []byte("test-secret")
+	s := NewSigner(secret)
+	payload := `{"sub":"user-1","sid":"session-1","iat":1767323045,"exp":1767323945}`
+
+	noneHeader := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"none","typ":"JWT"}`))
+	noneToken := noneHeader + "." + base64.RawURLEncoding.EncodeToString([]byte(payload)) + "."
+
+	tests := map[string]string{
+		"alg none":          noneToken,
+		"alg HS512":         hs256(secret, `{"alg":"HS512","typ":"JWT"}`, payload),
+		"alg RS256":         hs256(secret, `{"alg":"RS256","typ":"JWT"}`, payload),
+		"lower case alg":    hs256(secret, `{"alg":"hs256","typ":"JWT"}`, payload),
+		"missing alg":       hs256(secret, `{"typ":"JWT"}`, payload),
+		"reordered members": hs256(secret, `{"typ":"JWT","alg":"HS256"}`, payload),
+	}
+	for name, token := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := s.Verify(token, testNow); !errors.Is(err, ErrInvalidToken) {
+				t.Errorf("Verify() error = %v, want ErrInvalidToken", err)
+			}
+		})
+	}
+
+	// 同じペイロードでも発行するヘッダーで署名した場合は受け付ける
+	if _, err := s.Verify(hs256(secret, `{"alg":"HS256","typ":"JWT"}`, payload), testNow); err != nil {
+		t.Errorf("Verify() with HS256 header error = %v", err)
+	}
+}
+
+func TestSignerVerifyRejectsMissingSubject(t *testing.T) {
+	s := NewSigner([]byte("test-secret"))
+	claims := testClaims()
+	claims.Subject = ""
+
+	if _, err := s.Verify(signTestToken(t, s, claims), testNow); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("Verify() error = %v, want ErrInvalidToken", err)
+	}
+}
@@ -0,0 +1,184 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// responseDrainLimit は接続を再利用するために読み捨てる応答の本文の上限
+const responseDrainLimit = 64 << 10
+
+// Message は送信先に送るWebhookを表す
+type Message struct {
+	// ID は配信のID（X-Webhook-Id、再送でも変わらないため受信側で重複を除ける）
+	ID string
+	// Event はイベントの種類（X-Webhook-Event）
+	Event string
+	// Body はJSONの本文
+	Body []byte
+}
+
+// StatusError は送信先が2xx以外のステータスを返したことを表す
+type StatusError struct {
+	StatusCode int
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("webhook endpoint returned %d", e.StatusCode)
+}
+
+// ErrBlockedAddress は送信先がループバック・プライベート・リンクローカル等の内部のアドレスであることを表す
+var ErrBlockedAddress = errors.New("webhook endpoint address is not allowed")
+
+// blockedPrefixes はnetip.Addrのメソッドで判定できない、送信を許可しない範囲
+var blockedPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),     // このネットワーク
+	netip.MustParsePrefix("100.64.0.0/10"), // キャリアグレードNATの共有アドレス
+	netip.MustParsePrefix("255.255.255.255/32"),
+}
+
+// Sender は本文に署名してWebhookを送信する
+type Sender struct {
+	httpClient           *http.Client
+	allowPrivateNetworks bool
+}
+
+// NewSender は新しいSenderを作成する
+// timeoutは1件の送信の応答を待つ時間。リダイレクトは追わずに失敗として扱う
+// allowPrivateNetworksがfalseの場合は、名前解決した後の接続先のアドレスを検証し、内部のアドレスには接続しない
+// （登録後に名前解決の結果を内部のアドレスに変える場合も接続時に拒否する）
+func NewSender(timeout time.Duration, allowPrivateNetworks bool) *Sender {
+	dialer := &net.Dialer{Timeout: timeout}
+	if !allowPrivateNetworks {
+		dialer.Control = func(_, address string, _ syscall.RawConn) error {
+			return checkDialAddress(address)
+		}
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	// プロキシを経由すると接続先のアドレスを検証できないため、環境変数のプロキシは使わない
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+
+	return &Sender{
+		httpClient: &http.Client{
+			Transport: transport,
+			Timeout:   timeout,
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+		allowPrivateNetworks: allowPrivateNetworks,
+	}
+}
+
+// CheckURL は送信先のURLがhttpかhttpsの絶対URLであることを検証する
+// 内部のアドレスを許可しない場合は、ホストがlocalhostや内部のIPアドレスのURLも拒否する
+// （ホスト名の場合のアドレスは送信時に接続先で検証する）
+func (s *Sender) CheckURL(raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" || (u.Scheme != "https" && u.Scheme != "http") {
+		return errors.New("webhook url must be an absolute http(s) url")
+	}
+	if s.allowPrivateNetworks {
+		return nil
+	}
+
+	host := strings.ToLower(strings.TrimSuffix(u.Hostname(), "."))
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return ErrBlockedAddress
+	}
+	if ip, err := netip.ParseAddr(host); err == nil && blockedAddress(ip) {
+		return ErrBlockedAddress
+	}
+	return nil
+}
+
+// Send はメッセージをurlにPOSTし、送信先が返したステータスを返す
+// 2xx以外のステータスは*StatusError、応答がなかった場合はステータス0とエラーを返す
+func (s *Sender) Send(ctx context.Context, url, secret string, msg Message) (int, error) {
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(msg.Body))
+	if err != nil {
+		return 0, fmt.Errorf("failed to create webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "github-task-controller-webhook")
+	req.Header.Set("X-Webhook-Id", msg.ID)
+	req.Header.Set("X-Webhook-Event", msg.Event)
+	req.Header.Set("X-Webhook-Timestamp", timestamp)
+	req.Header.Set("X-Webhook-Signature", "sha256="+Sign(secret, timestamp, msg.Body))
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		// 配信ログに残るため、名前解決した内部のアドレスはエラーに含めない
+		if errors.Is(err, ErrBlockedAddress) {
+			return 0, fmt.Errorf("failed to send webhook: %w", ErrBlockedAddress)
+		}
+		return 0, fmt.Errorf("failed to send webhook: %w", err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, responseDrainLimit))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, &StatusError{StatusCode: resp.StatusCode}
+	}
+	return resp.StatusCode, nil
+}
+
+// Sign は"<timestamp>.<body>"のHMAC-SHA256を16進で返す
+// タイムスタンプを署名に含めるため、受信側は古いタイムスタンプの再送（リプレイ）を拒否できる
+func Sign(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// checkDialAddress は名前解決した後の接続先（IPアドレス:ポート）が内部のアドレスでないことを検証する
+func checkDialAddress(address string) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return fmt.Errorf("invalid webhook endpoint address: %w", err)
+	}
+	ip, err := netip.ParseAddr(host)
+	if err != nil {
+		return fmt.Errorf("invalid webhook endpoint address: %w", err)
+	}
+	if blockedAddress(ip) {
+		return ErrBlockedAddress
+	}
+	return nil
+}
+
+// blockedAddress はipがループバック・プライベート・リンクローカル・未指定・マルチキャスト等の内部のアドレスかどうかを返す
+// IPv4射影アドレス（::ffff:127.0.0.1等）はIPv4のアドレスとして判定する
+func blockedAddress(ip netip.Addr) bool {
+	ip = ip.Unmap().WithZone("")
+	if ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast() {
+		return true
+	}
+	for _, prefix := range blockedPrefixes {
+		if prefix.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,176 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestBlockedAddress(t *testing.T) {
+	tests := []struct {
+		addr string
+		want bool
+	}{
+		{addr: "127.0.0.1", want: true},
+		{addr: "127.255.255.254", want: true},
+		{addr: "::1", want: true},
+		{addr: "10.0.0.1", want: true},
+		{addr: "172.16.0.1", want: true},
+		{addr: "172.31.255.255", want: true},
+		{addr: "192.168.1.1", want: true},
+		{addr: "169.254.169.254", want: true},
+		{addr: "fe80::1", want: true},
+		{addr: "fe80::1%eth0", want: true},
+		{addr: "fc00::1", want: true},
+		{addr: "fd12:3456::1", want: true},
+		{addr: "0.0.0.0", want: true},
+		{addr: "0.1.2.3", want: true},
+		{addr: "::", want: true},
+		{addr: "100.64.0.1", want: true},
+		{addr: "224.0.0.1", want: true},
+		{addr: "ff02::1", want: true},
+		{addr: "255.255.255.255", want: true},
+		{addr: "::ffff:127.0.0.1", want: true},
+		{addr: "::ffff:169.254.169.254", want: true},
+		{addr: "8.8.8.8", want: false},
+		{addr: "1.1.1.1", want: false},
+		{addr: "172.32.0.1", want: false},
+		{addr: "100.128.0.1", want: false},
+		{addr: "2001:4860:4860::8888", want: false},
+		{addr: "::ffff:8.8.8.8", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.addr, func(t *testing.T) {
+			if got := blockedAddress(netip.MustParseAddr(tt.addr)); got != tt.want {
+				t.Errorf("blockedAddress(%s) = %v, want %v", tt.addr, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSenderCheckURL(t *testing.T) {
+	tests := []struct {
+		url     string
+		wantErr bool
+	}{
+		{url: "https://example.com/hooks", wantErr: false},
+		{url: "http://example.com:8080/hooks", wantErr: false},
+		{url: "https://8.8.8.8/hooks", wantErr: false},
+		{url: "ftp://example.com/hooks", wantErr: true},
+		{url: "/hooks", wantErr: true},
+		{url: "https://", wantErr: true},
+		{url: "http://localhost:8080/hooks", wantErr: true},
+		{url: "http://LOCALHOST./hooks", wantErr: true},
+		{url: "http://app.localhost/hooks", wantErr: true},
+		{url: "http://127.0.0.1/hooks", wantErr: true},
+		{url: "http://10.0.0.1/hooks", wantErr: true},
+		{url: "http://169.254.169.254/latest/meta-data/", wantErr: true},
+		{url: "http://[::1]:8080/hooks", wantErr: true},
+		{url: "http://[::ffff:127.0.0.1]/hooks", wantErr: true},
+		{url: "http://0.0.0.0:8080/hooks", wantErr: true},
+	}
+	s := NewSender(time.Second, false)
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			if err := s.CheckURL(tt.url); (err != nil) != tt.wantErr {
+				t.Errorf("CheckURL(%q) error = %v, wantErr %v", tt.url, err, tt.wantErr)
+			}
+		})
+	}
+
+	// 内部のアドレスを許可した場合もhttp(s)の絶対URLのみ受け付ける
+	allowed := NewSender(time.Second, true)
+	if err := allowed.CheckURL("http://127.0.0.1:8080/hooks"); err != nil {
+		t.Errorf("CheckURL() with private networks allowed error = %v", err)
+	}
+	if err := allowed.CheckURL("ftp://127.0.0.1/hooks"); err == nil {
+		t.Error("CheckURL() of ftp url with private networks allowed error = nil, want error")
+	}
+}
+
+// countingServer はリクエストの回数を数えるテスト用のサーバーを起動する
+func countingServer(t *testing.T, handler http.HandlerFunc) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	var count atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		count.Add(1)
+		handler(w, r)
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &count
+}
+
+func TestSenderRejectsPrivateAddress(t *testing.T) {
+	srv, count := countingServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+	port := srv.URL[strings.LastIndex(srv.URL, ":")+1:]
+
+	// 登録時の検証を通るホスト名でも、名前解決した接続先のアドレスで拒否する
+	urls := []string{srv.URL, "http://localhost:" + port + "/hooks"}
+	s := NewSender(time.Second, false)
+	for _, url := range urls {
+		status, err := s.Send(context.Background(), url, "secret", Message{ID: "d1", Event: "task.created", Body: []byte(`{}`)})
+		if !errors.Is(err, ErrBlockedAddress) {
+			t.Errorf("Send(%s) error = %v, want ErrBlockedAddress", url, err)
+		}
+		if status != 0 {
+			t.Errorf("Send(%s) status = %d, want 0", url, status)
+		}
+		// 配信ログに名前解決したアドレスを残さない
+		if err != nil && strings.Contains(err.Error(), "127.0.0.1") {
+			t.Errorf("Send(%s) error = %v, want error without the resolved address", url, err)
+		}
+	}
+	if n := count.Load(); n != 0 {
+		t.Errorf("requests to private address = %d, want 0", n)
+	}
+}
+
+func TestSenderDoesNotFollowRedirect(t *testing.T) {
+	internal, internalCount := countingServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+	redirector, _ := countingServer(t, func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, internal.URL, http.StatusTemporaryRedirect)
+	})
+
+	// テスト用のサーバーはループバックのため、内部のアドレスを許可して送信先に届くようにする
+	s := NewSender(time.Second, true)
+	status, err := s.Send(context.Background(), redirector.URL, "secret", Message{ID: "d1", Event: "task.created", Body: []byte(`{}`)})
+	var statusErr *StatusError
+	if !errors.As(err, &statusErr) || status != http.StatusTemporaryRedirect {
+		t.Errorf("Send() = %d, %v, want %d and StatusError", status, err, http.StatusTemporaryRedirect)
+	}
+	if n := internalCount.Load(); n != 0 {
+		t.Errorf("requests to redirect target = %d, want 0", n)
+	}
+}
+
+func TestSenderSignsRequest(t *testing.T) {
+	var signature, timestamp string
+	srv, count := countingServer(t, func(w http.ResponseWriter, r *http.Request) {
+		signature = r.Header.Get("X-Webhook-Signature")
+		timestamp = r.Header.Get("X-Webhook-Timestamp")
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	s := NewSender(time.Second, true)
+	body := []byte(`{"event":"task.created"}`)
+	status, err := s.Send(context.Background(), srv.URL, "secret", Message{ID: "d1", Event: "task.created", Body: body})
+	if err != nil || status != http.StatusNoContent {
+		t.Fatalf("Send() = %d, %v, want %d", status, err, http.StatusNoContent)
+	}
+	if n := count.Load(); n != 1 {
+		t.Errorf("requests = %d, want 1", n)
+	}
+	if want := "sha256=" + Sign("secret", timestamp, body); signature != want {
+		t.Errorf("X-Webhook-Signature = %s, want %s", signature, want)
+	}
+}
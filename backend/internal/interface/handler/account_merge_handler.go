@@ -0,0 +1,70 @@
+package handler
+
+import (
+	"log/slog"
+	"net/http"
+	"net/url"
+
+	"github.com/sikigasa/github-task-controller/backend/internal/application/usecase"
+	"github.com/sikigasa/github-task-controller/backend/internal/interface/middleware"
+)
+
+// AccountMergeHandler はアカウント統合のHTTPハンドラー
+type AccountMergeHandler struct {
+	usecase     *usecase.AccountMergeUsecase
+	frontendURL string
+	logger      *slog.Logger
+}
+
+// NewAccountMergeHandler は新しいAccountMergeHandlerを作成する
+func NewAccountMergeHandler(usecase *usecase.AccountMergeUsecase, frontendURL string, logger *slog.Logger) *AccountMergeHandler {
+	return &AccountMergeHandler{
+		usecase:     usecase,
+		frontendURL: frontendURL,
+		logger:      logger,
+	}
+}
+
+// ConfirmByEmail はメールで送った確認リンクからアカウント統合を確認し、ログインページにリダイレクトする
+// 統合後はどちらのプロバイダーでもログインできる
+func (h *AccountMergeHandler) ConfirmByEmail(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	merge, err := h.usecase.ConfirmByToken(ctx, r.URL.Query().Get("token"), middleware.ClientIP(r))
+	if err != nil {
+		h.logger.WarnContext(ctx, "failed to confirm account merge", "error", err)
+		http.Redirect(w, r, h.frontendURL+"/login?error=account_merge_failed", http.StatusSeeOther)
+		return
+	}
+
+	http.Redirect(w, r, h.frontendURL+"/login?account_merged="+url.QueryEscape(merge.Provider), http.StatusSeeOther)
+}
+
+// List はログインユーザーへのアカウント統合の要求と確認の記録を一覧する
+func (h *AccountMergeHandler) List(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID, _ := middleware.GetUserIDFromContext(ctx)
+
+	merges, err := h.usecase.ListMerges(ctx, userID)
+	if err != nil {
+		respondDomainError(w, r, h.logger, err, "account_merge.list_failed")
+		return
+	}
+
+	respondJSON(w, h.logger, http.StatusOK, merges)
+}
+
+// Confirm はログイン中のユーザーへのアカウント統合の要求を確認する
+func (h *AccountMergeHandler) Confirm(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID, _ := middleware.GetUserIDFromContext(ctx)
+	id := r.PathValue("id")
+
+	merge, err := h.usecase.ConfirmInSession(ctx, userID, id, middleware.ClientIP(r))
+	if err != nil {
+		respondDomainError(w, r, h.logger, err, "account_merge.confirm_failed")
+		return
+	}
+
+	respondJSON(w, h.logger, http.StatusOK, merge)
+}
@@ -0,0 +1,48 @@
+package handler
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/sikigasa/github-task-controller/backend/internal/application/usecase"
+	"github.com/sikigasa/github-task-controller/backend/internal/domain/model"
+	"github.com/sikigasa/github-task-controller/backend/internal/interface/middleware"
+)
+
+// ActivityHandler はアクティビティログのHTTPハンドラー
+type ActivityHandler struct {
+	usecase *usecase.ActivityUsecase
+	logger  *slog.Logger
+}
+
+// NewActivityHandler は新しいActivityHandlerを作成する
+func NewActivityHandler(usecase *usecase.ActivityUsecase, logger *slog.Logger) *ActivityHandler {
+	return &ActivityHandler{
+		usecase: usecase,
+		logger:  logger,
+	}
+}
+
+// ListByProjectID はプロジェクトのアクティビティを新しい順に取得する（limit・offsetでページングする）
+func (h *ActivityHandler) ListByProjectID(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID, _ := middleware.GetUserIDFromContext(ctx)
+	projectID := r.PathValue("id")
+
+	var opts model.ListOptions
+	var ok bool
+	if opts.Limit, ok = parseIntQuery(w, r, h.logger, "limit", usecase.DefaultActivityListLimit); !ok {
+		return
+	}
+	if opts.Offset, ok = parseIntQuery(w, r, h.logger, "offset", 0); !ok {
+		return
+	}
+
+	activities, err := h.usecase.List(ctx, userID, projectID, opts)
+	if err != nil {
+		respondDomainError(w, r, h.logger, err, "activity.list_failed")
+		return
+	}
+
+	respondJSON(w, h.logger, http.StatusOK, activities)
+}
@@ -2,14 +2,18 @@ package handler
 
 import (
 	"encoding/json"
+	"errors"
 	"log/slog"
 	"net/http"
 	"net/url"
+	"strings"
 	"time"
 
 	"github.com/sikigasa/github-task-controller/backend/internal/application/usecase"
 	"github.com/sikigasa/github-task-controller/backend/internal/domain/model"
+	"github.com/sikigasa/github-task-controller/backend/internal/infrastructure/auth"
 	"github.com/sikigasa/github-task-controller/backend/internal/infrastructure/session"
+	"github.com/sikigasa/github-task-controller/backend/internal/interface/middleware"
 )
 
 const (
@@ -19,37 +23,73 @@ const (
 	sessionKeyName      = "name"
 	sessionKeyPicture   = "picture"
 	sessionKeyExpiresAt = "expires_at"
+	sessionKeySessionID = "session_id"
 	oauthStateKey       = "oauth_state"
+	refreshTokenCookie  = "refresh_token"
 	sessionMaxAge       = 60 * 60 * 24 * 7 // 7日間
 )
 
 // AuthHandler は認証に関するHTTPリクエストを処理する
 type AuthHandler struct {
-	authUsecase  *usecase.AuthUsecase
-	sessionStore *session.CookieStore
-	frontendURL  string
-	logger       *slog.Logger
+	authUsecase    *usecase.AuthUsecase
+	sessionUsecase *usecase.SessionUsecase
+	tokenUsecase   *usecase.TokenUsecase
+	samlUsecase    *usecase.SAMLUsecase
+	demoUsecase    *usecase.DemoUsecase
+	sessionStore   session.Store
+	frontendURL    string
+	logger         *slog.Logger
 }
 
 // NewAuthHandler は新しいAuthHandlerを作成する
+// tokenUsecaseを指定した場合はログイン時にCookieのセッションの代わりにリフレッシュトークンを発行する
+// samlUsecaseはSAML SSOを設定していない場合、demoUsecaseはデモモードが無効の場合はnil
 func NewAuthHandler(
 	authUsecase *usecase.AuthUsecase,
-	sessionStore *session.CookieStore,
+	sessionUsecase *usecase.SessionUsecase,
+	tokenUsecase *usecase.TokenUsecase,
+	samlUsecase *usecase.SAMLUsecase,
+	demoUsecase *usecase.DemoUsecase,
+	sessionStore session.Store,
 	frontendURL string,
 	logger *slog.Logger,
 ) *AuthHandler {
 	return &AuthHandler{
-		authUsecase:  authUsecase,
-		sessionStore: sessionStore,
-		frontendURL:  frontendURL,
-		logger:       logger,
+		authUsecase:    authUsecase,
+		sessionUsecase: sessionUsecase,
+		tokenUsecase:   tokenUsecase,
+		samlUsecase:    samlUsecase,
+		demoUsecase:    demoUsecase,
+		sessionStore:   sessionStore,
+		frontendURL:    frontendURL,
+		logger:         logger,
 	}
 }
 
 // Login はGoogle OAuth認証を開始する
 func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
+	h.startOAuthLogin(w, r, "google")
+}
+
+// LoginGithub はGitHub OAuth認証を開始する
+func (h *AuthHandler) LoginGithub(w http.ResponseWriter, r *http.Request) {
+	h.startOAuthLogin(w, r, "github")
+}
+
+// LoginMicrosoft はMicrosoft Entra ID（Azure AD）のOAuth認証を開始する
+func (h *AuthHandler) LoginMicrosoft(w http.ResponseWriter, r *http.Request) {
+	if !h.authUsecase.MicrosoftEnabled() {
+		h.logger.WarnContext(r.Context(), "microsoft login is not configured")
+		http.Redirect(w, r, h.frontendURL+"/login?error=provider_disabled", http.StatusTemporaryRedirect)
+		return
+	}
+	h.startOAuthLogin(w, r, "microsoft")
+}
+
+// startOAuthLogin は状態トークンをセッションに保存してプロバイダーの認証URLにリダイレクトする
+func (h *AuthHandler) startOAuthLogin(w http.ResponseWriter, r *http.Request, provider string) {
 	ctx := r.Context()
-	h.logger.InfoContext(ctx, "starting google oauth login")
+	h.logger.InfoContext(ctx, "starting oauth login", "provider", provider)
 
 	// 状態トークンを生成
 	state, err := h.authUsecase.GenerateStateToken()
@@ -68,15 +108,21 @@ func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Google認証URLにリダイレクト
-	authURL := h.authUsecase.GetAuthURL("google", state)
+	// プロバイダーの認証URLにリダイレクト
+	authURL := h.authUsecase.GetAuthURL(provider, state)
 	http.Redirect(w, r, authURL, http.StatusTemporaryRedirect)
 }
 
-// LoginGithub はGitHub OAuth認証を開始する
-func (h *AuthHandler) LoginGithub(w http.ResponseWriter, r *http.Request) {
+// LoginApple はSign in with Appleの認証を開始する
+func (h *AuthHandler) LoginApple(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
-	h.logger.InfoContext(ctx, "starting github oauth login")
+	h.logger.InfoContext(ctx, "starting apple oauth login")
+
+	if !h.authUsecase.AppleEnabled() {
+		h.logger.WarnContext(ctx, "apple sign in is not configured")
+		http.Redirect(w, r, h.frontendURL+"/login?error=provider_disabled", http.StatusTemporaryRedirect)
+		return
+	}
 
 	// 状態トークンを生成
 	state, err := h.authUsecase.GenerateStateToken()
@@ -87,6 +133,7 @@ func (h *AuthHandler) LoginGithub(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// セッションに状態を保存
+	// AppleはクロスサイトのPOSTでコールバックするため、SameSite=None（COOKIE_SECURE=true）のCookieである必要がある
 	sess, _ := h.sessionStore.Get(r, sessionName)
 	sess.Set(oauthStateKey, state)
 	if err := h.sessionStore.Save(w, r, sessionName, sess); err != nil {
@@ -95,83 +142,202 @@ func (h *AuthHandler) LoginGithub(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// GitHub認証URLにリダイレクト
-	authURL := h.authUsecase.GetAuthURL("github", state)
+	// Apple認証URLにリダイレクト
+	authURL := h.authUsecase.GetAuthURL("apple", state)
 	http.Redirect(w, r, authURL, http.StatusTemporaryRedirect)
 }
 
-// Callback はGoogle OAuth認証のコールバックを処理する
-func (h *AuthHandler) Callback(w http.ResponseWriter, r *http.Request) {
+// CallbackApple はSign in with Appleのコールバック（form_post）を処理する
+func (h *AuthHandler) CallbackApple(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
-	h.logger.InfoContext(ctx, "handling google oauth callback")
+	h.logger.InfoContext(ctx, "handling apple oauth callback")
+
+	if err := r.ParseForm(); err != nil {
+		h.logger.WarnContext(ctx, "failed to parse apple callback form", "error", err)
+		http.Redirect(w, r, h.frontendURL+"/login?error=invalid_request", http.StatusSeeOther)
+		return
+	}
+
+	// ユーザーが認可をキャンセルした場合
+	if errCode := r.PostFormValue("error"); errCode != "" {
+		h.logger.InfoContext(ctx, "apple authorization failed", "error", errCode)
+		http.Redirect(w, r, h.frontendURL+"/login?error=auth_failed&detail="+url.QueryEscape(errCode), http.StatusSeeOther)
+		return
+	}
 
 	// セッションから状態を取得
 	sess, _ := h.sessionStore.Get(r, sessionName)
 	savedState, ok := sess.GetString(oauthStateKey)
 	if !ok || savedState == "" {
 		h.logger.WarnContext(ctx, "state not found in session")
-		http.Redirect(w, r, h.frontendURL+"/login?error=invalid_state", http.StatusTemporaryRedirect)
+		http.Redirect(w, r, h.frontendURL+"/login?error=invalid_state", http.StatusSeeOther)
 		return
 	}
 
 	// 状態を検証
-	state := r.URL.Query().Get("state")
+	state := r.PostFormValue("state")
 	if state != savedState {
 		h.logger.WarnContext(ctx, "state mismatch", "expected", savedState, "got", state)
-		http.Redirect(w, r, h.frontendURL+"/login?error=invalid_state", http.StatusTemporaryRedirect)
+		http.Redirect(w, r, h.frontendURL+"/login?error=invalid_state", http.StatusSeeOther)
 		return
 	}
 
 	// 認証コードを取得
-	code := r.URL.Query().Get("code")
+	code := r.PostFormValue("code")
 	if code == "" {
-		h.logger.WarnContext(ctx, "code not found in query")
-		http.Redirect(w, r, h.frontendURL+"/login?error=no_code", http.StatusTemporaryRedirect)
+		h.logger.WarnContext(ctx, "code not found in form")
+		http.Redirect(w, r, h.frontendURL+"/login?error=no_code", http.StatusSeeOther)
 		return
 	}
 
+	// 名前は初回の認可時にのみuserフィールドで送られる
+	var appleUser *auth.AppleUser
+	if raw := r.PostFormValue("user"); raw != "" {
+		appleUser = &auth.AppleUser{}
+		if err := json.Unmarshal([]byte(raw), appleUser); err != nil {
+			h.logger.WarnContext(ctx, "failed to parse apple user", "error", err)
+			appleUser = nil
+		}
+	}
+
 	// コールバックを処理してユーザー情報を取得
-	user, _, err := h.authUsecase.HandleCallback(ctx, "google", code)
+	user, _, err := h.authUsecase.HandleAppleCallback(ctx, code, appleUser)
 	if err != nil {
-		h.logger.ErrorContext(ctx, "failed to handle google callback", "error", err)
-		http.Redirect(w, r, h.frontendURL+"/login?error=auth_failed&detail="+url.QueryEscape(err.Error()), http.StatusTemporaryRedirect)
+		h.logger.ErrorContext(ctx, "failed to handle apple callback", "error", err)
+		http.Redirect(w, r, h.callbackErrorURL(err), http.StatusSeeOther)
 		return
 	}
 
-	// セッションにユーザー情報を保存
-	sessionInfo := h.authUsecase.CreateSession(user, time.Duration(sessionMaxAge)*time.Second)
-	sess.Set(sessionKeyUserID, sessionInfo.UserID)
-	sess.Set(sessionKeyEmail, sessionInfo.Email)
-	sess.Set(sessionKeyName, sessionInfo.Name)
-	sess.Set(sessionKeyPicture, sessionInfo.Picture)
-	sess.Set(sessionKeyExpiresAt, sessionInfo.ExpiresAt.Unix())
-	sess.Delete(oauthStateKey)
+	h.completeLogin(w, r, sess, user)
+}
 
-	sess.Options.MaxAge = sessionMaxAge
-	sess.Options.HttpOnly = true
-	sess.Options.Secure = isHTTPS(r)
-	if isHTTPS(r) {
-		sess.Options.SameSite = http.SameSiteNoneMode
-	} else {
-		sess.Options.SameSite = http.SameSiteLaxMode
+// callbackErrorURL はOAuthコールバックの失敗時のリダイレクト先を返す
+// 新規登録の制限・アカウント統合の確認待ちはフロントエンドが案内を出し分けられるよう専用のエラーコードにする
+func (h *AuthHandler) callbackErrorURL(err error) string {
+	switch {
+	case errors.Is(err, model.ErrEmailDomainNotAllowed):
+		return h.frontendURL + "/login?error=signup_domain_not_allowed"
+	case errors.Is(err, model.ErrInvitationRequired):
+		return h.frontendURL + "/login?error=invitation_required"
+	case errors.Is(err, model.ErrAccountMergeRequired):
+		return h.frontendURL + "/login?error=account_merge_required"
+	default:
+		return h.frontendURL + "/login?error=auth_failed&detail=" + url.QueryEscape(err.Error())
 	}
+}
 
-	if err := h.sessionStore.Save(w, r, sessionName, sess); err != nil {
-		h.logger.ErrorContext(ctx, "failed to save session", "error", err)
-		http.Redirect(w, r, h.frontendURL+"/login?error=session_failed", http.StatusTemporaryRedirect)
+// SAMLMetadata はIdPに登録するサービスプロバイダーのメタデータを返す
+func (h *AuthHandler) SAMLMetadata(w http.ResponseWriter, r *http.Request) {
+	if h.samlUsecase == nil {
+		http.NotFound(w, r)
 		return
 	}
 
-	h.logger.InfoContext(ctx, "user logged in successfully", "user_id", user.ID)
+	w.Header().Set("Content-Type", "application/samlmetadata+xml")
+	if _, err := w.Write(h.samlUsecase.Metadata()); err != nil {
+		h.logger.ErrorContext(r.Context(), "failed to write saml metadata", "error", err)
+	}
+}
 
-	// フロントエンドにリダイレクト
-	http.Redirect(w, r, h.frontendURL, http.StatusTemporaryRedirect)
+// LoginSAML はIdPにAuthnRequestを送ってSAML SSOを開始する
+func (h *AuthHandler) LoginSAML(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	h.logger.InfoContext(ctx, "starting saml login")
+
+	if h.samlUsecase == nil {
+		h.logger.WarnContext(ctx, "saml sso is not configured")
+		http.Redirect(w, r, h.frontendURL+"/login?error=provider_disabled", http.StatusTemporaryRedirect)
+		return
+	}
+
+	// AuthnRequestはRelayStateと対応付けてサーバー側に保存する（ACSへのPOSTにはCookieが送られないため）
+	loginURL, err := h.samlUsecase.LoginURL(ctx)
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to create saml authn request", "error", err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+
+	http.Redirect(w, r, loginURL, http.StatusTemporaryRedirect)
+}
+
+// SAMLACS はIdPからPOSTされたResponseを検証してログインさせる（Assertion Consumer Service）
+func (h *AuthHandler) SAMLACS(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	h.logger.InfoContext(ctx, "handling saml response")
+
+	if h.samlUsecase == nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	if err := r.ParseForm(); err != nil {
+		h.logger.WarnContext(ctx, "failed to parse saml response form", "error", err)
+		http.Redirect(w, r, h.frontendURL+"/login?error=invalid_request", http.StatusSeeOther)
+		return
+	}
+
+	// RelayStateのないResponse（IdP起点のログイン）は受け付けない
+	relayState := r.PostFormValue("RelayState")
+	if relayState == "" {
+		h.logger.WarnContext(ctx, "saml relay state not found")
+		http.Redirect(w, r, h.frontendURL+"/login?error=invalid_state", http.StatusSeeOther)
+		return
+	}
+
+	user, err := h.samlUsecase.HandleResponse(ctx, r.PostFormValue("SAMLResponse"), relayState)
+	if err != nil {
+		// 検証に失敗した理由はログにのみ残し、リダイレクト先には含めない
+		h.logger.ErrorContext(ctx, "failed to handle saml response", "error", err)
+		http.Redirect(w, r, h.frontendURL+"/login?error=auth_failed", http.StatusSeeOther)
+		return
+	}
+
+	sess, _ := h.sessionStore.Get(r, sessionName)
+	h.completeLogin(w, r, sess, user)
+}
+
+// LoginDemo はサンプルのプロジェクトを用意したゲストユーザーを作成してログインさせる（デモモードのみ）
+func (h *AuthHandler) LoginDemo(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	h.logger.InfoContext(ctx, "starting demo login")
+
+	if h.demoUsecase == nil {
+		h.logger.WarnContext(ctx, "demo mode is not enabled")
+		http.Redirect(w, r, h.frontendURL+"/login?error=provider_disabled", http.StatusSeeOther)
+		return
+	}
+
+	user, err := h.demoUsecase.CreateGuest(ctx)
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to create guest user", "error", err)
+		http.Redirect(w, r, h.frontendURL+"/login?error=auth_failed", http.StatusSeeOther)
+		return
+	}
+
+	sess, _ := h.sessionStore.Get(r, sessionName)
+	h.completeLogin(w, r, sess, user)
+}
+
+// Callback はGoogle OAuth認証のコールバックを処理する
+func (h *AuthHandler) Callback(w http.ResponseWriter, r *http.Request) {
+	h.handleOAuthCallback(w, r, "google")
 }
 
 // CallbackGithub はGitHub OAuth認証のコールバックを処理する
 func (h *AuthHandler) CallbackGithub(w http.ResponseWriter, r *http.Request) {
+	h.handleOAuthCallback(w, r, "github")
+}
+
+// CallbackMicrosoft はMicrosoft Entra IDのOAuth認証のコールバックを処理する
+func (h *AuthHandler) CallbackMicrosoft(w http.ResponseWriter, r *http.Request) {
+	h.handleOAuthCallback(w, r, "microsoft")
+}
+
+// handleOAuthCallback は状態トークンを検証し、認証コードでユーザーを特定してログインさせる
+func (h *AuthHandler) handleOAuthCallback(w http.ResponseWriter, r *http.Request, provider string) {
 	ctx := r.Context()
-	h.logger.InfoContext(ctx, "handling github oauth callback")
+	h.logger.InfoContext(ctx, "handling oauth callback", "provider", provider)
 
 	// セッションから状態を取得
 	sess, _ := h.sessionStore.Get(r, sessionName)
@@ -199,10 +365,28 @@ func (h *AuthHandler) CallbackGithub(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// コールバックを処理してユーザー情報を取得
-	user, _, err := h.authUsecase.HandleCallback(ctx, "github", code)
+	user, _, err := h.authUsecase.HandleCallback(ctx, provider, code)
 	if err != nil {
-		h.logger.ErrorContext(ctx, "failed to handle callback", "error", err)
-		http.Redirect(w, r, h.frontendURL+"/login?error=auth_failed&detail="+url.QueryEscape(err.Error()), http.StatusTemporaryRedirect)
+		h.logger.ErrorContext(ctx, "failed to handle callback", "provider", provider, "error", err)
+		http.Redirect(w, r, h.callbackErrorURL(err), http.StatusTemporaryRedirect)
+		return
+	}
+
+	h.completeLogin(w, r, sess, user)
+}
+
+// completeLogin はOAuth認証に成功したユーザーをログインさせてフロントエンドにリダイレクトする
+// POSTで送られるコールバック（Apple）からもGETで遷移させるため303でリダイレクトする
+func (h *AuthHandler) completeLogin(w http.ResponseWriter, r *http.Request, sess *session.Session, user *model.User) {
+	ctx := r.Context()
+	// プロビジョニング（SCIM）で無効化されたユーザーはログインさせない
+	if !user.Active() {
+		h.logger.WarnContext(ctx, "deactivated user tried to log in", "user_id", user.ID)
+		http.Redirect(w, r, h.frontendURL+"/login?error=account_disabled", http.StatusSeeOther)
+		return
+	}
+	if h.tokenUsecase != nil {
+		h.completeTokenLogin(w, r, user)
 		return
 	}
 
@@ -214,6 +398,7 @@ func (h *AuthHandler) CallbackGithub(w http.ResponseWriter, r *http.Request) {
 	sess.Set(sessionKeyPicture, sessionInfo.Picture)
 	sess.Set(sessionKeyExpiresAt, sessionInfo.ExpiresAt.Unix())
 	sess.Delete(oauthStateKey)
+	h.startSession(r, sess, sessionInfo)
 
 	sess.Options.MaxAge = sessionMaxAge
 	sess.Options.HttpOnly = true
@@ -226,14 +411,14 @@ func (h *AuthHandler) CallbackGithub(w http.ResponseWriter, r *http.Request) {
 
 	if err := h.sessionStore.Save(w, r, sessionName, sess); err != nil {
 		h.logger.ErrorContext(ctx, "failed to save session", "error", err)
-		http.Redirect(w, r, h.frontendURL+"/login?error=session_failed", http.StatusTemporaryRedirect)
+		http.Redirect(w, r, h.frontendURL+"/login?error=session_failed", http.StatusSeeOther)
 		return
 	}
 
 	h.logger.InfoContext(ctx, "user logged in successfully", "user_id", user.ID)
 
 	// フロントエンドにリダイレクト
-	http.Redirect(w, r, h.frontendURL, http.StatusTemporaryRedirect)
+	http.Redirect(w, r, h.frontendURL, http.StatusSeeOther)
 }
 
 // Logout はログアウト処理を行う
@@ -241,8 +426,25 @@ func (h *AuthHandler) Logout(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	h.logger.InfoContext(ctx, "logging out user")
 
-	// セッションを削除
-	h.sessionStore.Delete(w, sessionName)
+	// リフレッシュトークンを失効させる
+	if h.tokenUsecase != nil {
+		if cookie, err := r.Cookie(refreshTokenCookie); err == nil && cookie.Value != "" {
+			if err := h.tokenUsecase.Revoke(ctx, cookie.Value); err != nil {
+				h.logger.ErrorContext(ctx, "failed to revoke refresh token", "error", err)
+			}
+		}
+		h.setRefreshTokenCookie(w, r, "", -1)
+	}
+
+	// ログインセッションの記録とセッションを削除
+	if sess, err := h.sessionStore.Get(r, sessionName); err == nil {
+		if sessionID, ok := sess.GetString(sessionKeySessionID); ok && sessionID != "" {
+			if err := h.sessionUsecase.EndSession(ctx, sessionID); err != nil {
+				h.logger.ErrorContext(ctx, "failed to end user session", "error", err, "session_id", sessionID)
+			}
+		}
+	}
+	h.sessionStore.Delete(w, r, sessionName)
 
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
@@ -251,32 +453,42 @@ func (h *AuthHandler) Logout(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// LogoutAll はユーザーのすべてのセッションを失効させ、すべての端末からログアウトする
+// 認証はRequireAuthミドルウェアで行う
+func (h *AuthHandler) LogoutAll(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID, _ := middleware.GetUserIDFromContext(ctx)
+
+	if err := h.sessionUsecase.EndAllSessions(ctx, userID); err != nil {
+		respondDomainError(w, r, h.logger, err, "session.logout_all_failed")
+		return
+	}
+
+	if h.tokenUsecase != nil {
+		h.setRefreshTokenCookie(w, r, "", -1)
+	}
+	h.sessionStore.Delete(w, r, sessionName)
+
+	h.logger.InfoContext(ctx, "user logged out of all sessions", "user_id", userID)
+	w.WriteHeader(http.StatusNoContent)
+}
+
 // Me は現在ログイン中のユーザー情報を返す
+// 認証はOptionalAuthミドルウェアで行い、未認証の場合は401を返す
 func (h *AuthHandler) Me(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
-	// セッションからユーザー情報を取得
-	sess, _ := h.sessionStore.Get(r, sessionName)
-	userID, ok := sess.GetString(sessionKeyUserID)
+	userID, ok := middleware.GetUserIDFromContext(ctx)
 	if !ok || userID == "" {
 		h.logger.InfoContext(ctx, "user not authenticated")
-		http.Error(w, "Unauthorized", http.StatusUnauthorized)
-		return
-	}
-
-	// セッション有効期限を確認
-	if sess.IsExpired(sessionKeyExpiresAt) {
-		h.logger.InfoContext(ctx, "session expired", "user_id", userID)
-		h.sessionStore.Delete(w, sessionName)
-		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		respondDomainError(w, r, h.logger, model.ErrUnauthorized, "")
 		return
 	}
 
 	// ユーザー情報を取得
 	user, err := h.authUsecase.GetUserByID(ctx, userID)
 	if err != nil {
-		h.logger.ErrorContext(ctx, "failed to get user", "user_id", userID, "error", err)
-		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		respondDomainError(w, r, h.logger, err, "user.get_failed")
 		return
 	}
 
@@ -287,6 +499,7 @@ func (h *AuthHandler) Me(w http.ResponseWriter, r *http.Request) {
 		"email":   user.Email,
 		"name":    user.Name,
 		"picture": user.ImageURL,
+		"guest":   user.IsGuest(),
 	}); err != nil {
 		h.logger.ErrorContext(ctx, "failed to encode response", "error", err)
 	}
@@ -322,6 +535,101 @@ func (h *AuthHandler) GetSessionFromRequest(r *http.Request) (*model.Session, er
 	}, nil
 }
 
+// Refresh はリフレッシュトークンのCookieを新しいものに置き換え、アクセストークンを返す（tokenモードのみ）
+func (h *AuthHandler) Refresh(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	if h.tokenUsecase == nil {
+		respondDomainError(w, r, h.logger, model.ErrNotFound, "")
+		return
+	}
+
+	cookie, err := r.Cookie(refreshTokenCookie)
+	if err != nil || cookie.Value == "" {
+		respondDomainError(w, r, h.logger, model.ErrUnauthorized, "")
+		return
+	}
+
+	accessToken, refreshToken, err := h.tokenUsecase.Refresh(ctx, cookie.Value)
+	if err != nil {
+		if errors.Is(err, model.ErrUnauthorized) {
+			h.setRefreshTokenCookie(w, r, "", -1)
+		}
+		respondDomainError(w, r, h.logger, err, "auth.refresh_failed")
+		return
+	}
+
+	h.setRefreshTokenCookie(w, r, refreshToken, int(h.tokenUsecase.RefreshTokenTTL().Seconds()))
+	respondJSON(w, h.logger, http.StatusOK, accessToken)
+}
+
+// completeTokenLogin はログインセッションを記録してリフレッシュトークンをCookieに保存し、フロントエンドにリダイレクトする
+// フロントエンドはPOST /auth/refreshでアクセストークンを取得する
+func (h *AuthHandler) completeTokenLogin(w http.ResponseWriter, r *http.Request, user *model.User) {
+	ctx := r.Context()
+
+	ttl := h.tokenUsecase.RefreshTokenTTL()
+	record, err := h.sessionUsecase.StartSession(ctx, user.ID, r.UserAgent(), middleware.ClientIP(r), requestCountry(r), time.Now().Add(ttl))
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to start user session", "error", err, "user_id", user.ID)
+		http.Redirect(w, r, h.frontendURL+"/login?error=session_failed", http.StatusSeeOther)
+		return
+	}
+
+	refreshToken, err := h.tokenUsecase.IssueRefreshToken(ctx, record)
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to issue refresh token", "error", err, "user_id", user.ID)
+		http.Redirect(w, r, h.frontendURL+"/login?error=session_failed", http.StatusSeeOther)
+		return
+	}
+
+	// OAuthの状態を保存していたセッションCookieは不要になる
+	h.sessionStore.Delete(w, r, sessionName)
+	h.setRefreshTokenCookie(w, r, refreshToken, int(ttl.Seconds()))
+
+	h.logger.InfoContext(ctx, "user logged in successfully", "user_id", user.ID)
+	http.Redirect(w, r, h.frontendURL, http.StatusSeeOther)
+}
+
+// setRefreshTokenCookie はリフレッシュトークンを/auth配下にのみ送信されるHttpOnlyのCookieに保存する（maxAgeが負の場合は削除する）
+func (h *AuthHandler) setRefreshTokenCookie(w http.ResponseWriter, r *http.Request, value string, maxAge int) {
+	sameSite := http.SameSiteLaxMode
+	if isHTTPS(r) {
+		sameSite = http.SameSiteNoneMode
+	}
+	http.SetCookie(w, &http.Cookie{
+		Name:     refreshTokenCookie,
+		Value:    value,
+		Path:     "/auth",
+		MaxAge:   maxAge,
+		HttpOnly: true,
+		Secure:   isHTTPS(r),
+		SameSite: sameSite,
+	})
+}
+
+// startSession はログインした端末の情報を記録し、記録IDをセッションに保存する
+// 記録に失敗してもログイン自体は続行する
+func (h *AuthHandler) startSession(r *http.Request, sess *session.Session, sessionInfo *model.Session) {
+	ctx := r.Context()
+	record, err := h.sessionUsecase.StartSession(ctx, sessionInfo.UserID, r.UserAgent(), middleware.ClientIP(r), requestCountry(r), sessionInfo.ExpiresAt)
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to start user session", "error", err, "user_id", sessionInfo.UserID)
+		sess.Delete(sessionKeySessionID)
+		return
+	}
+	sess.Set(sessionKeySessionID, record.ID)
+}
+
+// requestCountry はCDN・プロキシが付与した国コードのヘッダーからおおよその位置を返す（不明な場合は空文字）
+func requestCountry(r *http.Request) string {
+	for _, header := range []string{"CF-IPCountry", "CloudFront-Viewer-Country", "X-Vercel-IP-Country"} {
+		if country := strings.ToUpper(strings.TrimSpace(r.Header.Get(header))); len(country) == 2 && country != "XX" {
+			return country
+		}
+	}
+	return ""
+}
+
 // isHTTPS はリクエストがHTTPS経由かどうかを判定する
 // プロキシ（Railway等）の場合はX-Forwarded-Protoヘッダーも確認する
 func isHTTPS(r *http.Request) bool {
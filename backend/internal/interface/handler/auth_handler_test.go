@@ -0,0 +1,32 @@
+package handler
+
+import (
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/sikigasa/github-task-controller/backend/internal/domain/model"
+)
+
+func TestAuthHandlerCompleteLoginRejectsDeactivatedUser(t *testing.T) {
+	// 無効化されたユーザーはセッション・トークンを発行する前に拒否するため、ユースケースは使われない
+	h := NewAuthHandler(nil, nil, nil, nil, nil, nil, "https://app.example.com", slog.New(slog.NewTextHandler(io.Discard, nil)))
+	deactivatedAt := time.Now()
+	user := &model.User{ID: "user-1", Email: "user@example.com", DeactivatedAt: &deactivatedAt}
+
+	rec := httptest.NewRecorder()
+	h.completeLogin(rec, httptest.NewRequest(http.MethodPost, "/auth/saml/acs", nil), nil, user)
+
+	if rec.Code != http.StatusSeeOther {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusSeeOther)
+	}
+	if got, want := rec.Header().Get("Location"), "https://app.example.com/login?error=account_disabled"; got != want {
+		t.Errorf("Location = %q, want %q", got, want)
+	}
+	if cookies := rec.Result().Cookies(); len(cookies) != 0 {
+		t.Errorf("cookies = %v, want none", cookies)
+	}
+}
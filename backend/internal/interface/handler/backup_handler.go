@@ -0,0 +1,124 @@
+package handler
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+
+	"github.com/sikigasa/github-task-controller/backend/internal/application/usecase"
+	"github.com/sikigasa/github-task-controller/backend/internal/domain/model"
+)
+
+// maxBackupUploadSize はアップロードできるバックアップの最大サイズ
+const maxBackupUploadSize = 1 << 30
+
+// BackupHandler は管理者向けのデータベースのバックアップ・復元のHTTPハンドラー
+type BackupHandler struct {
+	usecase *usecase.BackupUsecase
+	logger  *slog.Logger
+}
+
+// NewBackupHandler は新しいBackupHandlerを作成する
+func NewBackupHandler(usecase *usecase.BackupUsecase, logger *slog.Logger) *BackupHandler {
+	return &BackupHandler{
+		usecase: usecase,
+		logger:  logger,
+	}
+}
+
+// List は保存したバックアップを新しい順に一覧する
+func (h *BackupHandler) List(w http.ResponseWriter, r *http.Request) {
+	backups, err := h.usecase.ListBackups(r.Context())
+	if err != nil {
+		respondDomainError(w, r, h.logger, err, "backup.list_failed")
+		return
+	}
+
+	respondJSON(w, h.logger, http.StatusOK, backups)
+}
+
+// Create はバックアップを作成する（完了まで待って結果を返す）
+func (h *BackupHandler) Create(w http.ResponseWriter, r *http.Request) {
+	b, err := h.usecase.CreateBackup(r.Context())
+	if err != nil {
+		respondDomainError(w, r, h.logger, err, "backup.create_failed")
+		return
+	}
+
+	respondJSON(w, h.logger, http.StatusCreated, b)
+}
+
+// Upload はリクエストボディのアーカイブをバックアップとして保存する
+func (h *BackupHandler) Upload(w http.ResponseWriter, r *http.Request) {
+	body := http.MaxBytesReader(w, r.Body, maxBackupUploadSize)
+
+	b, err := h.usecase.UploadBackup(r.Context(), body)
+	if err != nil {
+		respondDomainError(w, r, h.logger, err, "backup.upload_failed")
+		return
+	}
+
+	respondJSON(w, h.logger, http.StatusCreated, b)
+}
+
+// Download はバックアップのアーカイブをダウンロードする
+// 保存先が署名付きURLを発行できる場合はそのURLにリダイレクトする
+func (h *BackupHandler) Download(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	name := r.PathValue("name")
+
+	url, err := h.usecase.BackupDownloadURL(ctx, name)
+	if err != nil {
+		respondDomainError(w, r, h.logger, err, "backup.download_failed")
+		return
+	}
+	if url != "" {
+		http.Redirect(w, r, url, http.StatusFound)
+		return
+	}
+
+	f, err := h.usecase.OpenBackup(ctx, name)
+	if err != nil {
+		respondDomainError(w, r, h.logger, err, "backup.download_failed")
+		return
+	}
+	defer f.Close()
+
+	w.Header().Set("Content-Type", "application/gzip")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", name))
+	w.WriteHeader(http.StatusOK)
+	if _, err := io.Copy(w, f); err != nil {
+		h.logger.ErrorContext(ctx, "failed to write backup", "error", err, "backup", name)
+	}
+}
+
+// Restore はバックアップの内容でデータベースを置き換える
+// BACKUP_RESTORE_ENABLEDが無効の場合は403、confirmがバックアップ名と一致しない場合は400
+func (h *BackupHandler) Restore(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	name := r.PathValue("name")
+
+	var req model.RestoreBackupRequest
+	if !decodeAndValidate(w, r, h.logger, &req) {
+		return
+	}
+
+	result, err := h.usecase.RestoreBackup(ctx, name, &req)
+	if err != nil {
+		respondDomainError(w, r, h.logger, err, "backup.restore_failed")
+		return
+	}
+
+	respondJSON(w, h.logger, http.StatusOK, result)
+}
+
+// Delete はバックアップを削除する
+func (h *BackupHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	if err := h.usecase.DeleteBackup(r.Context(), r.PathValue("name")); err != nil {
+		respondDomainError(w, r, h.logger, err, "backup.delete_failed")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
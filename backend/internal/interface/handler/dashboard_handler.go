@@ -0,0 +1,37 @@
+package handler
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/sikigasa/github-task-controller/backend/internal/application/usecase"
+	"github.com/sikigasa/github-task-controller/backend/internal/interface/middleware"
+)
+
+// DashboardHandler はダッシュボードのHTTPハンドラー
+type DashboardHandler struct {
+	usecase *usecase.DashboardUsecase
+	logger  *slog.Logger
+}
+
+// NewDashboardHandler は新しいDashboardHandlerを作成する
+func NewDashboardHandler(usecase *usecase.DashboardUsecase, logger *slog.Logger) *DashboardHandler {
+	return &DashboardHandler{
+		usecase: usecase,
+		logger:  logger,
+	}
+}
+
+// Get はホーム画面に必要なプロジェクト一覧（集計付き）、今日・期限切れのタスク、最近のアクティビティ、GitHub連携状態をまとめて取得する
+func (h *DashboardHandler) Get(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID, _ := middleware.GetUserIDFromContext(ctx)
+
+	dashboard, err := h.usecase.GetDashboard(ctx, userID)
+	if err != nil {
+		respondDomainError(w, r, h.logger, err, "dashboard.get_failed")
+		return
+	}
+
+	respondJSON(w, h.logger, http.StatusOK, dashboard)
+}
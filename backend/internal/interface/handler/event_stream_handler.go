@@ -0,0 +1,104 @@
+package handler
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/sikigasa/github-task-controller/backend/internal/application/usecase"
+	"github.com/sikigasa/github-task-controller/backend/internal/domain/model"
+	"github.com/sikigasa/github-task-controller/backend/internal/interface/middleware"
+	"golang.org/x/net/websocket"
+)
+
+// eventStreamWriteTimeout はイベントを1件送信するまでの待ち時間（超えた接続は切断する）
+const eventStreamWriteTimeout = 10 * time.Second
+
+// EventStreamHandler はプロジェクトのドメインイベントをWebSocketで配信するHTTPハンドラー
+type EventStreamHandler struct {
+	projectUsecase *usecase.ProjectUsecase
+	broadcaster    *usecase.EventBroadcaster
+	allowedOrigins []string
+	logger         *slog.Logger
+}
+
+// NewEventStreamHandler は新しいEventStreamHandlerを作成する
+// allowedOriginsはCORSで許可するオリジンで、ブラウザからの接続はこのオリジンのみ受け付ける
+func NewEventStreamHandler(projectUsecase *usecase.ProjectUsecase, broadcaster *usecase.EventBroadcaster, allowedOrigins []string, logger *slog.Logger) *EventStreamHandler {
+	return &EventStreamHandler{
+		projectUsecase: projectUsecase,
+		broadcaster:    broadcaster,
+		allowedOrigins: allowedOrigins,
+		logger:         logger,
+	}
+}
+
+// Stream はプロジェクトのタスク・プロジェクトの変更をWebSocketでJSONとして配信する
+// RequireAuthStreamで認証するため、ここでプロジェクトの所有者であることを確認する
+func (h *EventStreamHandler) Stream(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID, _ := middleware.GetUserIDFromContext(ctx)
+	projectID := r.PathValue("id")
+
+	if _, err := h.projectUsecase.GetProject(ctx, userID, projectID); err != nil {
+		respondDomainError(w, r, h.logger, err, "project.events_failed")
+		return
+	}
+
+	events, cancel := h.broadcaster.Listen(projectID)
+	defer cancel()
+
+	server := websocket.Server{
+		Handshake: h.checkOrigin,
+		Handler: func(ws *websocket.Conn) {
+			h.stream(ws, events)
+		},
+	}
+	server.ServeHTTP(w, r)
+}
+
+// stream はクライアントが切断するまでイベントを送信する
+func (h *EventStreamHandler) stream(ws *websocket.Conn, events <-chan *model.OutboxEvent) {
+	defer ws.Close()
+	ctx := ws.Request().Context()
+
+	// HTTPサーバーのタイムアウトで設定された期限を解除する
+	if err := ws.SetDeadline(time.Time{}); err != nil {
+		h.logger.WarnContext(ctx, "failed to clear websocket deadline", "error", err)
+	}
+
+	// クライアントからのメッセージは使わないが、切断を検知するために読み続ける
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+		_, _ = io.Copy(io.Discard, ws)
+	}()
+
+	for {
+		select {
+		case <-closed:
+			return
+		case event := <-events:
+			if err := ws.SetWriteDeadline(time.Now().Add(eventStreamWriteTimeout)); err != nil {
+				return
+			}
+			if err := websocket.JSON.Send(ws, event); err != nil {
+				h.logger.InfoContext(ctx, "websocket closed while sending event", "error", err, "event_id", event.ID)
+				return
+			}
+		}
+	}
+}
+
+// checkOrigin はブラウザからの接続のオリジンがCORSで許可したものかを確認する
+// Originヘッダーのないブラウザ以外のクライアントは許可する
+func (h *EventStreamHandler) checkOrigin(_ *websocket.Config, r *http.Request) error {
+	origin := r.Header.Get("Origin")
+	if origin == "" || slices.Contains(h.allowedOrigins, "*") || slices.Contains(h.allowedOrigins, origin) {
+		return nil
+	}
+	return fmt.Errorf("websocket origin not allowed: %s", origin)
+}
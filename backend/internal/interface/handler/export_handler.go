@@ -0,0 +1,87 @@
+package handler
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+
+	"github.com/sikigasa/github-task-controller/backend/internal/application/usecase"
+	"github.com/sikigasa/github-task-controller/backend/internal/domain/model"
+	"github.com/sikigasa/github-task-controller/backend/internal/interface/middleware"
+)
+
+// ExportHandler はレポートのエクスポートのHTTPハンドラー
+type ExportHandler struct {
+	usecase *usecase.ExportUsecase
+	logger  *slog.Logger
+}
+
+// NewExportHandler は新しいExportHandlerを作成する
+func NewExportHandler(usecase *usecase.ExportUsecase, logger *slog.Logger) *ExportHandler {
+	return &ExportHandler{
+		usecase: usecase,
+		logger:  logger,
+	}
+}
+
+// Create はレポートのエクスポートを受け付ける
+// 生成は非同期に行い、完了するとメールでダウンロードリンクを通知する
+func (h *ExportHandler) Create(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID, _ := middleware.GetUserIDFromContext(ctx)
+
+	var req model.CreateReportExportRequest
+	if !decodeAndValidate(w, r, h.logger, &req) {
+		return
+	}
+
+	exp, err := h.usecase.RequestExport(ctx, userID, &req)
+	if err != nil {
+		respondDomainError(w, r, h.logger, err, "export.create_failed")
+		return
+	}
+
+	respondJSON(w, h.logger, http.StatusAccepted, exp)
+}
+
+// Get はエクスポートの処理状況を取得する
+func (h *ExportHandler) Get(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID, _ := middleware.GetUserIDFromContext(ctx)
+	id := r.PathValue("id")
+
+	exp, err := h.usecase.GetExport(ctx, userID, id)
+	if err != nil {
+		respondDomainError(w, r, h.logger, err, "export.get_failed")
+		return
+	}
+
+	respondJSON(w, h.logger, http.StatusOK, exp)
+}
+
+// Download はエクスポートしたファイルをダウンロードする（未完了の場合は409）
+// 保存先が署名付きURLを発行できる場合はそのURLにリダイレクトする
+func (h *ExportHandler) Download(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID, _ := middleware.GetUserIDFromContext(ctx)
+	id := r.PathValue("id")
+
+	exp, file, err := h.usecase.Download(ctx, userID, id)
+	if err != nil {
+		respondDomainError(w, r, h.logger, err, "export.download_failed")
+		return
+	}
+	if file.URL != "" {
+		http.Redirect(w, r, file.URL, http.StatusFound)
+		return
+	}
+	defer file.Content.Close()
+
+	w.Header().Set("Content-Type", exp.ContentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", exp.FileName))
+	w.WriteHeader(http.StatusOK)
+	if _, err := io.Copy(w, file.Content); err != nil {
+		h.logger.ErrorContext(ctx, "failed to write export content", "error", err, "export_id", id)
+	}
+}
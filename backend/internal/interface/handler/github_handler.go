@@ -1,11 +1,11 @@
 package handler
 
 import (
-	"encoding/json"
 	"log/slog"
 	"net/http"
 
 	"github.com/sikigasa/github-task-controller/backend/internal/application/usecase"
+	"github.com/sikigasa/github-task-controller/backend/internal/domain/model"
 	"github.com/sikigasa/github-task-controller/backend/internal/interface/middleware"
 )
 
@@ -30,20 +30,44 @@ func (h *GithubHandler) GetConnectionStatus(w http.ResponseWriter, r *http.Reque
 
 	status, err := h.usecase.GetConnectionStatus(ctx, userID)
 	if err != nil {
-		h.logger.ErrorContext(ctx, "failed to get connection status", "error", err)
-		http.Error(w, "Failed to get connection status", http.StatusInternalServerError)
+		respondDomainError(w, r, h.logger, err, "github.status_failed")
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	if err := json.NewEncoder(w).Encode(status); err != nil {
-		h.logger.ErrorContext(ctx, "failed to encode response", "error", err)
+	respondJSON(w, h.logger, http.StatusOK, status)
+}
+
+// Diagnose はGitHub連携を診断する（認証されたユーザー・トークンの種類とスコープ・残りの利用量・応答時間）
+func (h *GithubHandler) Diagnose(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID, _ := middleware.GetUserIDFromContext(ctx)
+
+	diagnostics, err := h.usecase.Diagnose(ctx, userID)
+	if err != nil {
+		respondDomainError(w, r, h.logger, err, "github.diagnostics_failed")
+		return
 	}
+
+	respondJSON(w, h.logger, http.StatusOK, diagnostics)
+}
+
+// GetSyncUsage は現在の1時間のGitHubとの同期の回数と上限を取得する
+func (h *GithubHandler) GetSyncUsage(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID, _ := middleware.GetUserIDFromContext(ctx)
+
+	usage, err := h.usecase.GetSyncUsage(ctx, userID)
+	if err != nil {
+		respondDomainError(w, r, h.logger, err, "github.sync_usage_failed")
+		return
+	}
+
+	respondJSON(w, h.logger, http.StatusOK, usage)
 }
 
 // SavePATRequest はPAT保存リクエスト
 type SavePATRequest struct {
-	PAT string `json:"pat"`
+	PAT string `json:"pat" validate:"required"`
 }
 
 // SavePAT はPATを保存する
@@ -52,20 +76,12 @@ func (h *GithubHandler) SavePAT(w http.ResponseWriter, r *http.Request) {
 	userID, _ := middleware.GetUserIDFromContext(ctx)
 
 	var req SavePATRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		h.logger.ErrorContext(ctx, "failed to decode request", "error", err)
-		http.Error(w, "Invalid request body", http.StatusBadRequest)
-		return
-	}
-
-	if req.PAT == "" {
-		http.Error(w, "PAT is required", http.StatusBadRequest)
+	if !decodeAndValidate(w, r, h.logger, &req) {
 		return
 	}
 
 	if err := h.usecase.SavePAT(ctx, userID, req.PAT); err != nil {
-		h.logger.ErrorContext(ctx, "failed to save PAT", "error", err)
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		respondDomainError(w, r, h.logger, err, "github.pat_save_failed")
 		return
 	}
 
@@ -78,37 +94,36 @@ func (h *GithubHandler) DeletePAT(w http.ResponseWriter, r *http.Request) {
 	userID, _ := middleware.GetUserIDFromContext(ctx)
 
 	if err := h.usecase.DeletePAT(ctx, userID); err != nil {
-		h.logger.ErrorContext(ctx, "failed to delete PAT", "error", err)
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		respondDomainError(w, r, h.logger, err, "github.pat_delete_failed")
 		return
 	}
 
 	w.WriteHeader(http.StatusNoContent)
 }
 
-// ListGithubProjects はユーザーのGitHub Projectsを取得する
+// ListGithubProjects は連携先に選べるGitHub Projectsを取得する
+// ?owner=でユーザー・Organizationを指定した場合はそのProjectsだけを返す
 func (h *GithubHandler) ListGithubProjects(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	userID, _ := middleware.GetUserIDFromContext(ctx)
 
-	projects, err := h.usecase.ListGithubProjects(ctx, userID)
+	projects, err := h.usecase.ListGithubProjects(ctx, userID, r.URL.Query().Get("owner"))
 	if err != nil {
-		h.logger.ErrorContext(ctx, "failed to list github projects", "error", err)
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		respondDomainError(w, r, h.logger, err, "github.projects_failed")
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	if err := json.NewEncoder(w).Encode(projects); err != nil {
-		h.logger.ErrorContext(ctx, "failed to encode response", "error", err)
-	}
+	respondJSON(w, h.logger, http.StatusOK, projects)
 }
 
 // LinkProjectRequest はプロジェクト連携リクエスト
 type LinkProjectRequest struct {
+	// GithubOwner は省略すると設定のデフォルトownerを使用する
 	GithubOwner         string `json:"github_owner"`
 	GithubRepo          string `json:"github_repo"`
-	GithubProjectNumber int    `json:"github_project_number"`
+	GithubProjectNumber int    `json:"github_project_number" validate:"required,min=1"`
+	// RepoProject はgithub_repoに紐づくProjectとして連携するか
+	RepoProject bool `json:"repo_project"`
 }
 
 // LinkProject はプロジェクトをGitHub Projectに連携する
@@ -118,20 +133,12 @@ func (h *GithubHandler) LinkProject(w http.ResponseWriter, r *http.Request) {
 	projectID := r.PathValue("id")
 
 	var req LinkProjectRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		h.logger.ErrorContext(ctx, "failed to decode request", "error", err)
-		http.Error(w, "Invalid request body", http.StatusBadRequest)
+	if !decodeAndValidate(w, r, h.logger, &req) {
 		return
 	}
 
-	if req.GithubOwner == "" || req.GithubProjectNumber == 0 {
-		http.Error(w, "github_owner and github_project_number are required", http.StatusBadRequest)
-		return
-	}
-
-	if err := h.usecase.LinkProjectToGithub(ctx, userID, projectID, req.GithubOwner, req.GithubRepo, req.GithubProjectNumber); err != nil {
-		h.logger.ErrorContext(ctx, "failed to link project", "error", err)
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+	if err := h.usecase.LinkProjectToGithub(ctx, userID, projectID, req.GithubOwner, req.GithubRepo, req.GithubProjectNumber, req.RepoProject); err != nil {
+		respondDomainError(w, r, h.logger, err, "project.link_failed")
 		return
 	}
 
@@ -145,8 +152,53 @@ func (h *GithubHandler) UnlinkProject(w http.ResponseWriter, r *http.Request) {
 	projectID := r.PathValue("id")
 
 	if err := h.usecase.UnlinkProjectFromGithub(ctx, userID, projectID); err != nil {
-		h.logger.ErrorContext(ctx, "failed to unlink project", "error", err)
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		respondDomainError(w, r, h.logger, err, "project.unlink_failed")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetFieldMapping はプロジェクトのタスクのステータス・優先度とGitHub Projectsのフィールドの対応付けを取得する
+func (h *GithubHandler) GetFieldMapping(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID, _ := middleware.GetUserIDFromContext(ctx)
+
+	mapping, err := h.usecase.GetFieldMapping(ctx, userID, r.PathValue("id"))
+	if err != nil {
+		respondDomainError(w, r, h.logger, err, "github.field_mapping_get_failed")
+		return
+	}
+
+	respondJSON(w, h.logger, http.StatusOK, mapping)
+}
+
+// UpdateFieldMapping はプロジェクトのフィールドの対応付けを設定する
+func (h *GithubHandler) UpdateFieldMapping(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID, _ := middleware.GetUserIDFromContext(ctx)
+
+	var req model.UpdateGithubFieldMappingRequest
+	if !decodeAndValidate(w, r, h.logger, &req) {
+		return
+	}
+
+	mapping, err := h.usecase.UpdateFieldMapping(ctx, userID, r.PathValue("id"), &req)
+	if err != nil {
+		respondDomainError(w, r, h.logger, err, "github.field_mapping_update_failed")
+		return
+	}
+
+	respondJSON(w, h.logger, http.StatusOK, mapping)
+}
+
+// DeleteFieldMapping はプロジェクトのフィールドの対応付けを既定に戻す
+func (h *GithubHandler) DeleteFieldMapping(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID, _ := middleware.GetUserIDFromContext(ctx)
+
+	if err := h.usecase.DeleteFieldMapping(ctx, userID, r.PathValue("id")); err != nil {
+		respondDomainError(w, r, h.logger, err, "github.field_mapping_delete_failed")
 		return
 	}
 
@@ -154,16 +206,189 @@ func (h *GithubHandler) UnlinkProject(w http.ResponseWriter, r *http.Request) {
 }
 
 // SyncTaskToGithub はタスクをGitHub Projectに同期する
+// GitHubに障害があり同期を保留した場合は202を返す（接続が戻った後に自動で同期する）
 func (h *GithubHandler) SyncTaskToGithub(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	userID, _ := middleware.GetUserIDFromContext(ctx)
 	taskID := r.PathValue("id")
 
-	if err := h.usecase.SyncTaskToGithub(ctx, userID, taskID); err != nil {
-		h.logger.ErrorContext(ctx, "failed to sync task", "error", err)
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+	queued, err := h.usecase.SyncTaskOrQueue(ctx, userID, taskID)
+	if err != nil {
+		respondDomainError(w, r, h.logger, err, "task.sync_failed")
+		return
+	}
+	if queued {
+		respondJSON(w, h.logger, http.StatusAccepted, map[string]bool{"queued": true})
 		return
 	}
 
 	w.WriteHeader(http.StatusNoContent)
 }
+
+// ListPendingOperations はGitHubの障害のために保留しているGitHubへの変更を取得する
+func (h *GithubHandler) ListPendingOperations(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID, _ := middleware.GetUserIDFromContext(ctx)
+
+	operations, err := h.usecase.ListPendingOperations(ctx, userID)
+	if err != nil {
+		respondDomainError(w, r, h.logger, err, "github.pending_operations_failed")
+		return
+	}
+
+	respondJSON(w, h.logger, http.StatusOK, operations)
+}
+
+// SyncMilestoneToGithub はマイルストーンを連携先リポジトリのGitHubマイルストーンに同期する
+func (h *GithubHandler) SyncMilestoneToGithub(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID, _ := middleware.GetUserIDFromContext(ctx)
+	milestoneID := r.PathValue("id")
+
+	milestone, err := h.usecase.SyncMilestoneToGithub(ctx, userID, milestoneID)
+	if err != nil {
+		respondDomainError(w, r, h.logger, err, "github.milestone_sync_failed")
+		return
+	}
+
+	respondJSON(w, h.logger, http.StatusOK, milestone)
+}
+
+// CreateTaskBranch は連携先リポジトリにタスクの作業ブランチを作成する
+func (h *GithubHandler) CreateTaskBranch(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID, _ := middleware.GetUserIDFromContext(ctx)
+	taskID := r.PathValue("id")
+
+	task, err := h.usecase.CreateTaskBranch(ctx, userID, taskID)
+	if err != nil {
+		respondDomainError(w, r, h.logger, err, "github.branch_create_failed")
+		return
+	}
+
+	respondJSON(w, h.logger, http.StatusCreated, task)
+}
+
+// ListTaskCommits はタスクを参照しているコミットを取得する
+func (h *GithubHandler) ListTaskCommits(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID, _ := middleware.GetUserIDFromContext(ctx)
+	taskID := r.PathValue("id")
+
+	commits, err := h.usecase.ListTaskCommits(ctx, userID, taskID)
+	if err != nil {
+		respondDomainError(w, r, h.logger, err, "github.commits_failed")
+		return
+	}
+
+	respondJSON(w, h.logger, http.StatusOK, commits)
+}
+
+// SyncProjectCommits は連携先リポジトリの最近のコミットからタスクへの参照を検出して記録する
+func (h *GithubHandler) SyncProjectCommits(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID, _ := middleware.GetUserIDFromContext(ctx)
+	projectID := r.PathValue("id")
+
+	result, err := h.usecase.SyncProjectCommits(ctx, userID, projectID)
+	if err != nil {
+		respondDomainError(w, r, h.logger, err, "github.commits_sync_failed")
+		return
+	}
+
+	respondJSON(w, h.logger, http.StatusOK, result)
+}
+
+// SyncProject はプロジェクトの同期済みのタスクと連携先のGitHub ProjectのItemを双方向に同期する
+func (h *GithubHandler) SyncProject(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID, _ := middleware.GetUserIDFromContext(ctx)
+	projectID := r.PathValue("id")
+
+	result, err := h.usecase.SyncProject(ctx, userID, projectID)
+	if err != nil {
+		respondDomainError(w, r, h.logger, err, "github.project_sync_failed")
+		return
+	}
+
+	respondJSON(w, h.logger, http.StatusOK, result)
+}
+
+// ListGithubProjectItems は連携先のGitHub ProjectのItemsを1ページ取得する
+// 次のページはレスポンスのend_cursorをcursorに指定して取得する
+func (h *GithubHandler) ListGithubProjectItems(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID, _ := middleware.GetUserIDFromContext(ctx)
+
+	page, err := h.usecase.ListGithubProjectItems(ctx, userID, r.PathValue("id"), r.URL.Query().Get("cursor"))
+	if err != nil {
+		respondDomainError(w, r, h.logger, err, "github.project_items_failed")
+		return
+	}
+
+	respondJSON(w, h.logger, http.StatusOK, page)
+}
+
+// ListTaskPullRequests はタスクのGitHub Issueを参照しているPull Requestを取得する
+func (h *GithubHandler) ListTaskPullRequests(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID, _ := middleware.GetUserIDFromContext(ctx)
+	taskID := r.PathValue("id")
+
+	pullRequests, err := h.usecase.ListTaskPullRequests(ctx, userID, taskID)
+	if err != nil {
+		respondDomainError(w, r, h.logger, err, "github.pull_requests_failed")
+		return
+	}
+
+	respondJSON(w, h.logger, http.StatusOK, pullRequests)
+}
+
+// SyncTaskPullRequests はタスクのGitHub Issueを参照しているPull Requestを検出して紐付ける
+func (h *GithubHandler) SyncTaskPullRequests(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID, _ := middleware.GetUserIDFromContext(ctx)
+	taskID := r.PathValue("id")
+
+	pullRequests, err := h.usecase.SyncTaskPullRequests(ctx, userID, taskID)
+	if err != nil {
+		respondDomainError(w, r, h.logger, err, "github.pull_requests_sync_failed")
+		return
+	}
+
+	respondJSON(w, h.logger, http.StatusOK, pullRequests)
+}
+
+// ImportProjectItems は連携先のGitHub ProjectのItemをプロジェクトのタスクとして取り込む
+// ?dry_run=trueの場合はタスクを変更せずに、取り込んだ場合の結果を返す
+func (h *GithubHandler) ImportProjectItems(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID, _ := middleware.GetUserIDFromContext(ctx)
+
+	dryRun, ok := parseBoolQuery(w, r, h.logger, "dry_run")
+	if !ok {
+		return
+	}
+
+	result, err := h.usecase.ImportGithubProjectItems(ctx, userID, r.PathValue("id"), dryRun)
+	if err != nil {
+		respondDomainError(w, r, h.logger, err, "github.project_import_failed")
+		return
+	}
+
+	respondJSON(w, h.logger, http.StatusOK, result)
+}
+
+// ImportAssignedIssues は担当のGitHub Issueを設定の取り込み先プロジェクトのタスクとして取り込む
+func (h *GithubHandler) ImportAssignedIssues(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID, _ := middleware.GetUserIDFromContext(ctx)
+
+	result, err := h.usecase.ImportAssignedIssues(ctx, userID)
+	if err != nil {
+		respondDomainError(w, r, h.logger, err, "github.issue_import_failed")
+		return
+	}
+
+	respondJSON(w, h.logger, http.StatusOK, result)
+}
@@ -0,0 +1,57 @@
+package handler
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+
+	"github.com/sikigasa/github-task-controller/backend/internal/application/usecase"
+	"github.com/sikigasa/github-task-controller/backend/internal/domain/model"
+	"github.com/sikigasa/github-task-controller/backend/internal/infrastructure/github"
+)
+
+// githubWebhookMaxBodyBytes は受信するWebhookの本文の上限（GitHubが送る本文の上限に合わせる）
+const githubWebhookMaxBodyBytes = 25 << 20
+
+// GithubWebhookHandler はGitHubからのWebhookを受信するHTTPハンドラー
+type GithubWebhookHandler struct {
+	usecase *usecase.WebhookUsecase
+	secret  string
+	logger  *slog.Logger
+}
+
+// NewGithubWebhookHandler は新しいGithubWebhookHandlerを作成する
+// secretはGitHubのWebhookに設定した署名の秘密
+func NewGithubWebhookHandler(usecase *usecase.WebhookUsecase, secret string, logger *slog.Logger) *GithubWebhookHandler {
+	return &GithubWebhookHandler{
+		usecase: usecase,
+		secret:  secret,
+		logger:  logger,
+	}
+}
+
+// Receive はX-Hub-Signature-256の署名を検証した配信をキューに保存し、202を返す（処理は非同期に行う）
+// 同じ配信ID（X-GitHub-Delivery）の再送は保存済みのため重複して処理しない
+func (h *GithubWebhookHandler) Receive(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	deliveryID := r.Header.Get("X-GitHub-Delivery")
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, githubWebhookMaxBodyBytes))
+	if err != nil {
+		respondDomainError(w, r, h.logger, fmt.Errorf("failed to read webhook body: %v: %w", err, model.ErrInvalidInput), "webhook.receive_failed")
+		return
+	}
+	if !github.VerifySignature(h.secret, body, r.Header.Get(github.SignatureHeader)) {
+		h.logger.WarnContext(ctx, "github webhook signature mismatch", "delivery_id", deliveryID)
+		respondDomainError(w, r, h.logger, model.ErrUnauthorized, "")
+		return
+	}
+
+	if err := h.usecase.Enqueue(ctx, deliveryID, r.Header.Get("X-GitHub-Event"), body); err != nil {
+		respondDomainError(w, r, h.logger, err, "webhook.receive_failed")
+		return
+	}
+
+	w.WriteHeader(http.StatusAccepted)
+}
@@ -0,0 +1,116 @@
+package handler
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sikigasa/github-task-controller/backend/internal/application/usecase"
+	"github.com/sikigasa/github-task-controller/backend/internal/domain/model"
+	"github.com/sikigasa/github-task-controller/backend/internal/domain/repository"
+	"github.com/sikigasa/github-task-controller/backend/internal/infrastructure/github"
+)
+
+const (
+	testGithubWebhookSecret = "test-webhook-secret"
+	testGithubWebhookBody   = `{"action":"edited","issue":{"number":1}}`
+)
+
+// recordedDeliveries はCreateで保存した配信を記録するWebhookDeliveryRepository
+type recordedDeliveries struct {
+	repository.WebhookDeliveryRepository
+	deliveries []*model.WebhookDelivery
+}
+
+func (r *recordedDeliveries) Create(_ context.Context, delivery *model.WebhookDelivery) (bool, error) {
+	for _, d := range r.deliveries {
+		if d.ID == delivery.ID {
+			return false, nil
+		}
+	}
+	r.deliveries = append(r.deliveries, delivery)
+	return true, nil
+}
+
+func signGithubWebhook(secret, body string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(body))
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func newTestGithubWebhookHandler() (*GithubWebhookHandler, *recordedDeliveries) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	deliveries := &recordedDeliveries{}
+	webhookUsecase := usecase.NewWebhookUsecase(deliveries, 3, time.Minute, nil, logger)
+	return NewGithubWebhookHandler(webhookUsecase, testGithubWebhookSecret, logger), deliveries
+}
+
+func receiveGithubWebhook(h *GithubWebhookHandler, deliveryID, body, signature string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/webhooks/github", strings.NewReader(body))
+	req.Header.Set("X-GitHub-Delivery", deliveryID)
+	req.Header.Set("X-GitHub-Event", "issues")
+	if signature != "" {
+		req.Header.Set(github.SignatureHeader, signature)
+	}
+	rec := httptest.NewRecorder()
+	h.Receive(rec, req)
+	return rec
+}
+
+func TestGithubWebhookHandlerReceive(t *testing.T) {
+	h, deliveries := newTestGithubWebhookHandler()
+	signature := signGithubWebhook(testGithubWebhookSecret, testGithubWebhookBody)
+
+	rec := receiveGithubWebhook(h, "delivery-1", testGithubWebhookBody, signature)
+	if rec.Code != http.StatusAccepted {
+		t.Fatalf("status = %d, want %d, body = %s", rec.Code, http.StatusAccepted, rec.Body.String())
+	}
+	if len(deliveries.deliveries) != 1 || string(deliveries.deliveries[0].Payload) != testGithubWebhookBody {
+		t.Fatalf("deliveries = %v, want the received payload", deliveries.deliveries)
+	}
+
+	// 同じ配信IDの再送は受け付けるが、重複して保存しない
+	rec = receiveGithubWebhook(h, "delivery-1", testGithubWebhookBody, signature)
+	if rec.Code != http.StatusAccepted {
+		t.Errorf("redelivery status = %d, want %d", rec.Code, http.StatusAccepted)
+	}
+	if len(deliveries.deliveries) != 1 {
+		t.Errorf("deliveries = %d, want 1", len(deliveries.deliveries))
+	}
+}
+
+func TestGithubWebhookHandlerRejectsInvalidSignature(t *testing.T) {
+	tests := map[string]struct {
+		body      string
+		signature string
+	}{
+		"missing signature": {body: testGithubWebhookBody},
+		"other secret":      {body: testGithubWebhookBody, signature: signGithubWebhook("other-secret", testGithubWebhookBody)},
+		"tampered body": {
+			body:      strings.Replace(testGithubWebhookBody, `"number":1`, `"number":2`, 1),
+			signature: signGithubWebhook(testGithubWebhookSecret, testGithubWebhookBody),
+		},
+		"empty secret": {body: testGithubWebhookBody, signature: signGithubWebhook("", testGithubWebhookBody)},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			h, deliveries := newTestGithubWebhookHandler()
+
+			rec := receiveGithubWebhook(h, "delivery-1", tt.body, tt.signature)
+			if rec.Code != http.StatusUnauthorized {
+				t.Errorf("status = %d, want %d", rec.Code, http.StatusUnauthorized)
+			}
+			if len(deliveries.deliveries) != 0 {
+				t.Errorf("deliveries = %v, want none", deliveries.deliveries)
+			}
+		})
+	}
+}
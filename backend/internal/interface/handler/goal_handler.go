@@ -0,0 +1,142 @@
+package handler
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/sikigasa/github-task-controller/backend/internal/application/usecase"
+	"github.com/sikigasa/github-task-controller/backend/internal/domain/model"
+	"github.com/sikigasa/github-task-controller/backend/internal/interface/middleware"
+)
+
+// GoalHandler は目標（OKR）のHTTPハンドラー
+type GoalHandler struct {
+	usecase *usecase.GoalUsecase
+	logger  *slog.Logger
+}
+
+// NewGoalHandler は新しいGoalHandlerを作成する
+func NewGoalHandler(usecase *usecase.GoalUsecase, logger *slog.Logger) *GoalHandler {
+	return &GoalHandler{
+		usecase: usecase,
+		logger:  logger,
+	}
+}
+
+// Create はプロジェクトに新しい目標を作成する
+func (h *GoalHandler) Create(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID, _ := middleware.GetUserIDFromContext(ctx)
+	projectID := r.PathValue("id")
+
+	var req model.CreateGoalRequest
+	if !decodeAndValidate(w, r, h.logger, &req) {
+		return
+	}
+
+	goal, err := h.usecase.CreateGoal(ctx, userID, projectID, &req)
+	if err != nil {
+		respondDomainError(w, r, h.logger, err, "goal.create_failed")
+		return
+	}
+
+	respondJSON(w, h.logger, http.StatusCreated, goal)
+}
+
+// ListByProjectID はプロジェクトの目標を進捗付きで取得する
+// quarterクエリ（YYYY-Qn）を指定するとその四半期の目標のみを返す
+func (h *GoalHandler) ListByProjectID(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID, _ := middleware.GetUserIDFromContext(ctx)
+	projectID := r.PathValue("id")
+
+	goals, err := h.usecase.ListGoals(ctx, userID, projectID, r.URL.Query().Get("quarter"))
+	if err != nil {
+		respondDomainError(w, r, h.logger, err, "goal.list_failed")
+		return
+	}
+
+	respondJSON(w, h.logger, http.StatusOK, goals)
+}
+
+// Get はIDで目標を取得する
+func (h *GoalHandler) Get(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID, _ := middleware.GetUserIDFromContext(ctx)
+	id := r.PathValue("id")
+
+	goal, err := h.usecase.GetGoal(ctx, userID, id)
+	if err != nil {
+		respondDomainError(w, r, h.logger, err, "goal.get_failed")
+		return
+	}
+
+	respondJSON(w, h.logger, http.StatusOK, goal)
+}
+
+// Update は目標情報を更新する
+func (h *GoalHandler) Update(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID, _ := middleware.GetUserIDFromContext(ctx)
+	id := r.PathValue("id")
+
+	var req model.UpdateGoalRequest
+	if !decodeAndValidate(w, r, h.logger, &req) {
+		return
+	}
+
+	goal, err := h.usecase.UpdateGoal(ctx, userID, id, &req)
+	if err != nil {
+		respondDomainError(w, r, h.logger, err, "goal.update_failed")
+		return
+	}
+
+	respondJSON(w, h.logger, http.StatusOK, goal)
+}
+
+// Delete は目標を削除する
+func (h *GoalHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID, _ := middleware.GetUserIDFromContext(ctx)
+	id := r.PathValue("id")
+
+	if err := h.usecase.DeleteGoal(ctx, userID, id); err != nil {
+		respondDomainError(w, r, h.logger, err, "goal.delete_failed")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// AddTask は目標に貢献タスクを紐づける
+func (h *GoalHandler) AddTask(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID, _ := middleware.GetUserIDFromContext(ctx)
+	id := r.PathValue("id")
+
+	var req model.AddGoalTaskRequest
+	if !decodeAndValidate(w, r, h.logger, &req) {
+		return
+	}
+
+	goal, err := h.usecase.AddTask(ctx, userID, id, req.TaskID)
+	if err != nil {
+		respondDomainError(w, r, h.logger, err, "goal.task_add_failed")
+		return
+	}
+
+	respondJSON(w, h.logger, http.StatusOK, goal)
+}
+
+// RemoveTask は目標から貢献タスクの紐づけを解除する
+func (h *GoalHandler) RemoveTask(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID, _ := middleware.GetUserIDFromContext(ctx)
+
+	if err := h.usecase.RemoveTask(ctx, userID, r.PathValue("id"), r.PathValue("taskId")); err != nil {
+		respondDomainError(w, r, h.logger, err, "goal.task_remove_failed")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
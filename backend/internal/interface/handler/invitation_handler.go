@@ -0,0 +1,71 @@
+package handler
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/sikigasa/github-task-controller/backend/internal/application/usecase"
+	"github.com/sikigasa/github-task-controller/backend/internal/domain/model"
+	"github.com/sikigasa/github-task-controller/backend/internal/interface/middleware"
+)
+
+// InvitationHandler は新規登録の招待のHTTPハンドラー
+type InvitationHandler struct {
+	usecase *usecase.InvitationUsecase
+	logger  *slog.Logger
+}
+
+// NewInvitationHandler は新しいInvitationHandlerを作成する
+func NewInvitationHandler(usecase *usecase.InvitationUsecase, logger *slog.Logger) *InvitationHandler {
+	return &InvitationHandler{
+		usecase: usecase,
+		logger:  logger,
+	}
+}
+
+// Create はメールアドレス宛ての招待を作成して招待メールを送信する
+func (h *InvitationHandler) Create(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID, _ := middleware.GetUserIDFromContext(ctx)
+
+	var req model.CreateInvitationRequest
+	if !decodeAndValidate(w, r, h.logger, &req) {
+		return
+	}
+
+	invitation, err := h.usecase.CreateInvitation(ctx, userID, &req)
+	if err != nil {
+		respondDomainError(w, r, h.logger, err, "invitation.create_failed")
+		return
+	}
+
+	respondJSON(w, h.logger, http.StatusCreated, invitation)
+}
+
+// List はログインユーザーが作成した招待を一覧する
+func (h *InvitationHandler) List(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID, _ := middleware.GetUserIDFromContext(ctx)
+
+	invitations, err := h.usecase.ListInvitations(ctx, userID)
+	if err != nil {
+		respondDomainError(w, r, h.logger, err, "invitation.list_failed")
+		return
+	}
+
+	respondJSON(w, h.logger, http.StatusOK, invitations)
+}
+
+// Delete は招待を取り消す
+func (h *InvitationHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID, _ := middleware.GetUserIDFromContext(ctx)
+	id := r.PathValue("id")
+
+	if err := h.usecase.DeleteInvitation(ctx, userID, id); err != nil {
+		respondDomainError(w, r, h.logger, err, "invitation.delete_failed")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,27 @@
+package handler
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/sikigasa/github-task-controller/backend/internal/application/usecase"
+)
+
+// JobHandler は管理者向けの定期実行するジョブの確認のHTTPハンドラー
+type JobHandler struct {
+	scheduler *usecase.Scheduler
+	logger    *slog.Logger
+}
+
+// NewJobHandler は新しいJobHandlerを作成する
+func NewJobHandler(scheduler *usecase.Scheduler, logger *slog.Logger) *JobHandler {
+	return &JobHandler{
+		scheduler: scheduler,
+		logger:    logger,
+	}
+}
+
+// List はジョブごとのスケジュールと前回・次回の実行時刻を一覧する
+func (h *JobHandler) List(w http.ResponseWriter, r *http.Request) {
+	respondJSON(w, h.logger, http.StatusOK, h.scheduler.Jobs())
+}
@@ -0,0 +1,257 @@
+package handler
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/sikigasa/github-task-controller/backend/internal/domain/model"
+	"github.com/sikigasa/github-task-controller/backend/internal/interface/i18n"
+)
+
+// listQuerySpec は一覧エンドポイントごとに許可するソートキー・取得フィールドの一覧
+type listQuerySpec struct {
+	sortable []string
+	fields   []string
+}
+
+// listQuery は一覧エンドポイントの共通クエリパラメータの解析結果
+type listQuery struct {
+	options model.ListOptions
+	// fields はレスポンスに含めるフィールド（空の場合はすべて）
+	fields []string
+}
+
+// parseListQuery は一覧エンドポイントの共通クエリパラメータを解析する
+//
+//   - sort: ソートキーをカンマ区切りで指定する（例: sort=priority,created_at）
+//   - order: asc/descをsortと同じ順にカンマ区切りで指定する（省略した位置はasc）
+//   - fields: レスポンスに含めるフィールドをカンマ区切りで指定する（idは常に含まれる）
+//
+// 同じパラメータを繰り返した場合（sort=title&sort=created_at）はカンマ区切りで指定した場合と同じように扱う
+// 同じソートキーを複数回指定した場合はエラーにする
+// 許可されていない値の場合はフィールド単位のエラーを含む400を書き込み、falseを返す
+func parseListQuery(w http.ResponseWriter, r *http.Request, logger *slog.Logger, spec listQuerySpec) (listQuery, bool) {
+	ctx := r.Context()
+	query := r.URL.Query()
+	var result listQuery
+	var fieldErrors []FieldError
+
+	sortFields := splitQueryList(query["sort"])
+	orders := splitQueryList(query["order"])
+	if len(orders) > len(sortFields) {
+		fieldErrors = append(fieldErrors, FieldError{Field: "order", Message: i18n.T(ctx, "query.order_without_sort")})
+	}
+	for i, field := range sortFields {
+		if !slices.Contains(spec.sortable, field) {
+			fieldErrors = append(fieldErrors, FieldError{Field: "sort", Message: i18n.T(ctx, "query.invalid_sort", field, strings.Join(spec.sortable, ", "))})
+			continue
+		}
+		if slices.Contains(sortFields[:i], field) {
+			fieldErrors = append(fieldErrors, FieldError{Field: "sort", Message: i18n.T(ctx, "query.duplicate_sort", field)})
+			continue
+		}
+		order := model.SortAsc
+		if i < len(orders) {
+			switch model.SortOrder(strings.ToLower(orders[i])) {
+			case model.SortAsc:
+			case model.SortDesc:
+				order = model.SortDesc
+			default:
+				fieldErrors = append(fieldErrors, FieldError{Field: "order", Message: i18n.T(ctx, "query.invalid_order", orders[i])})
+				continue
+			}
+		}
+		result.options.Sort = append(result.options.Sort, model.SortKey{Field: field, Order: order})
+	}
+
+	for _, field := range splitQueryList(query["fields"]) {
+		if !slices.Contains(spec.fields, field) {
+			fieldErrors = append(fieldErrors, FieldError{Field: "fields", Message: i18n.T(ctx, "query.invalid_field", field, strings.Join(spec.fields, ", "))})
+			continue
+		}
+		if !slices.Contains(result.fields, field) {
+			result.fields = append(result.fields, field)
+		}
+	}
+
+	if len(fieldErrors) > 0 {
+		respondProblem(w, r, logger, ProblemDetail{
+			Type:   "about:blank",
+			Title:  "Invalid Input",
+			Status: http.StatusBadRequest,
+			Detail: i18n.T(ctx, "error.invalid_input"),
+			Errors: fieldErrors,
+		})
+		return listQuery{}, false
+	}
+
+	return result, true
+}
+
+// parseExpand はexpandクエリパラメータ（カンマ区切り）を解析する
+// 許可されていない値の場合はフィールド単位のエラーを含む400を書き込み、falseを返す
+func parseExpand(w http.ResponseWriter, r *http.Request, logger *slog.Logger, allowed []string) ([]string, bool) {
+	ctx := r.Context()
+	var expand []string
+	var fieldErrors []FieldError
+	for _, name := range splitQueryList(r.URL.Query()["expand"]) {
+		if !slices.Contains(allowed, name) {
+			fieldErrors = append(fieldErrors, FieldError{Field: "expand", Message: i18n.T(ctx, "query.invalid_expand", name, strings.Join(allowed, ", "))})
+			continue
+		}
+		expand = append(expand, name)
+	}
+
+	if len(fieldErrors) > 0 {
+		respondProblem(w, r, logger, ProblemDetail{
+			Type:   "about:blank",
+			Title:  "Invalid Input",
+			Status: http.StatusBadRequest,
+			Detail: i18n.T(ctx, "error.invalid_input"),
+			Errors: fieldErrors,
+		})
+		return nil, false
+	}
+
+	return expand, true
+}
+
+// parseIntQuery は整数のクエリパラメータを解析する（省略時はdefaultValue）
+// 整数でない場合（intの範囲外を含む）や複数回指定した場合はフィールド単位のエラーを含む400を書き込み、falseを返す
+func parseIntQuery(w http.ResponseWriter, r *http.Request, logger *slog.Logger, name string, defaultValue int) (int, bool) {
+	value, ok := singleQueryValue(w, r, logger, name)
+	if !ok {
+		return 0, false
+	}
+	if value == "" {
+		return defaultValue, true
+	}
+
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		ctx := r.Context()
+		respondProblem(w, r, logger, ProblemDetail{
+			Type:   "about:blank",
+			Title:  "Invalid Input",
+			Status: http.StatusBadRequest,
+			Detail: i18n.T(ctx, "error.invalid_input"),
+			Errors: []FieldError{{Field: name, Message: i18n.T(ctx, "query.invalid_integer", value)}},
+		})
+		return 0, false
+	}
+
+	return n, true
+}
+
+// parseBoolQuery は真偽値のクエリパラメータを解析する（省略時はfalse）
+// 真偽値でない場合や複数回指定した場合はフィールド単位のエラーを含む400を書き込み、falseを返す
+func parseBoolQuery(w http.ResponseWriter, r *http.Request, logger *slog.Logger, name string) (value, ok bool) {
+	raw, ok := singleQueryValue(w, r, logger, name)
+	if !ok {
+		return false, false
+	}
+	if raw == "" {
+		return false, true
+	}
+
+	b, err := strconv.ParseBool(raw)
+	if err != nil {
+		ctx := r.Context()
+		respondProblem(w, r, logger, ProblemDetail{
+			Type:   "about:blank",
+			Title:  "Invalid Input",
+			Status: http.StatusBadRequest,
+			Detail: i18n.T(ctx, "error.invalid_input"),
+			Errors: []FieldError{{Field: name, Message: i18n.T(ctx, "query.invalid_boolean", raw)}},
+		})
+		return false, false
+	}
+
+	return b, true
+}
+
+// singleQueryValue は1回だけ指定できるクエリパラメータの値を返す（省略時は空文字列）
+// 複数回指定した場合はどの値を使うかが曖昧なため、フィールド単位のエラーを含む400を書き込み、falseを返す
+func singleQueryValue(w http.ResponseWriter, r *http.Request, logger *slog.Logger, name string) (string, bool) {
+	values := r.URL.Query()[name]
+	if len(values) > 1 {
+		ctx := r.Context()
+		respondProblem(w, r, logger, ProblemDetail{
+			Type:   "about:blank",
+			Title:  "Invalid Input",
+			Status: http.StatusBadRequest,
+			Detail: i18n.T(ctx, "error.invalid_input"),
+			Errors: []FieldError{{Field: name, Message: i18n.T(ctx, "query.repeated", name)}},
+		})
+		return "", false
+	}
+	if len(values) == 0 {
+		return "", true
+	}
+	return values[0], true
+}
+
+// splitQueryList はカンマ区切りのクエリパラメータを分割する（空要素は除外する）
+// 同じパラメータを繰り返した場合の値（url.Valuesの値）はすべてつなげる
+func splitQueryList(values []string) []string {
+	var items []string
+	for _, value := range values {
+		for _, v := range strings.Split(value, ",") {
+			if v = strings.TrimSpace(v); v != "" {
+				items = append(items, v)
+			}
+		}
+	}
+	return items
+}
+
+// respondList は一覧をJSON形式で返す
+// fieldsが指定されている場合は各要素を指定フィールド（とid）のみに絞り込む
+func respondList[T any](w http.ResponseWriter, r *http.Request, logger *slog.Logger, items []T, fields []string) {
+	if len(fields) == 0 {
+		respondJSON(w, logger, http.StatusOK, items)
+		return
+	}
+
+	selected, err := selectFields(items, fields)
+	if err != nil {
+		logger.ErrorContext(r.Context(), "failed to select fields", "error", err)
+		respondError(w, r, logger, http.StatusInternalServerError, "Internal Server Error", "error.unexpected")
+		return
+	}
+
+	respondJSON(w, logger, http.StatusOK, selected)
+}
+
+// selectFields は各要素のJSON表現から指定フィールドとidのみを残す
+func selectFields[T any](items []T, fields []string) ([]map[string]json.RawMessage, error) {
+	data, err := json.Marshal(items)
+	if err != nil {
+		return nil, err
+	}
+
+	var objects []map[string]json.RawMessage
+	if err := json.Unmarshal(data, &objects); err != nil {
+		return nil, err
+	}
+
+	selected := make([]map[string]json.RawMessage, 0, len(objects))
+	for _, obj := range objects {
+		m := make(map[string]json.RawMessage, len(fields)+1)
+		if id, ok := obj["id"]; ok {
+			m["id"] = id
+		}
+		for _, field := range fields {
+			if v, ok := obj[field]; ok {
+				m[field] = v
+			}
+		}
+		selected = append(selected, m)
+	}
+
+	return selected, nil
+}
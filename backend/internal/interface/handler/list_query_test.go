@@ -0,0 +1,186 @@
+package handler
+
+import (
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	"github.com/sikigasa/github-task-controller/backend/internal/domain/model"
+)
+
+// problemFields は400のレスポンスに含まれるフィールド単位のエラーのフィールド名を返す
+func problemFields(t *testing.T, rec *httptest.ResponseRecorder) []string {
+	t.Helper()
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusBadRequest)
+	}
+	var problem ProblemDetail
+	if err := json.NewDecoder(rec.Body).Decode(&problem); err != nil {
+		t.Fatalf("failed to decode problem: %v", err)
+	}
+	fields := make([]string, 0, len(problem.Errors))
+	for _, e := range problem.Errors {
+		fields = append(fields, e.Field)
+	}
+	return fields
+}
+
+func TestParseListQuery(t *testing.T) {
+	spec := listQuerySpec{
+		sortable: []string{"title", "priority", "created_at"},
+		fields:   []string{"title", "status", "priority"},
+	}
+	tests := []struct {
+		name       string
+		query      string
+		wantSort   []model.SortKey
+		wantFields []string
+		// wantErrors はエラーになる場合のフィールド単位のエラーのフィールド名
+		wantErrors []string
+	}{
+		{name: "empty", query: ""},
+		{name: "single sort", query: "sort=title", wantSort: []model.SortKey{{Field: "title", Order: model.SortAsc}}},
+		{
+			name:     "sort with orders",
+			query:    "sort=priority,created_at&order=desc,asc",
+			wantSort: []model.SortKey{{Field: "priority", Order: model.SortDesc}, {Field: "created_at", Order: model.SortAsc}},
+		},
+		{
+			name:     "omitted orders are asc",
+			query:    "sort=priority,created_at&order=desc",
+			wantSort: []model.SortKey{{Field: "priority", Order: model.SortDesc}, {Field: "created_at", Order: model.SortAsc}},
+		},
+		{name: "upper case order", query: "sort=title&order=DESC", wantSort: []model.SortKey{{Field: "title", Order: model.SortDesc}}},
+		{
+			name:     "spaces and empty entries",
+			query:    "sort=,+title+,,priority&order=+desc,",
+			wantSort: []model.SortKey{{Field: "title", Order: model.SortDesc}, {Field: "priority", Order: model.SortAsc}},
+		},
+		{name: "fields", query: "fields=title,status", wantFields: []string{"title", "status"}},
+		// 同じパラメータを繰り返した場合はカンマ区切りと同じ
+		{
+			name:     "repeated sort",
+			query:    "sort=priority&sort=title&order=desc&order=asc",
+			wantSort: []model.SortKey{{Field: "priority", Order: model.SortDesc}, {Field: "title", Order: model.SortAsc}},
+		},
+		{name: "repeated fields", query: "fields=title&fields=status", wantFields: []string{"title", "status"}},
+		{name: "duplicate fields", query: "fields=title,title&fields=title", wantFields: []string{"title"}},
+		{name: "duplicate sort", query: "sort=title,title", wantErrors: []string{"sort"}},
+		{name: "duplicate repeated sort", query: "sort=title&sort=title", wantErrors: []string{"sort"}},
+		{name: "unknown sort", query: "sort=description", wantErrors: []string{"sort"}},
+		{name: "case sensitive sort", query: "sort=Title", wantErrors: []string{"sort"}},
+		{name: "invalid order", query: "sort=title&order=up", wantErrors: []string{"order"}},
+		{name: "order without sort", query: "order=desc", wantErrors: []string{"order"}},
+		{name: "more orders than sort", query: "sort=title&order=asc,desc", wantErrors: []string{"order"}},
+		{name: "unknown field", query: "fields=title,secret", wantErrors: []string{"fields"}},
+		{name: "all errors", query: "sort=description&order=asc,up&fields=secret", wantErrors: []string{"order", "sort", "fields"}},
+	}
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			got, ok := parseListQuery(rec, httptest.NewRequest(http.MethodGet, "/api/v1/tasks?"+tt.query, nil), logger, spec)
+
+			if tt.wantErrors != nil {
+				if ok {
+					t.Fatalf("parseListQuery(%q) = %+v, want error", tt.query, got)
+				}
+				if fields := problemFields(t, rec); !slices.Equal(fields, tt.wantErrors) {
+					t.Errorf("error fields = %v, want %v", fields, tt.wantErrors)
+				}
+				return
+			}
+			if !ok {
+				t.Fatalf("parseListQuery(%q) failed: %d %s", tt.query, rec.Code, rec.Body)
+			}
+			if !slices.Equal(got.options.Sort, tt.wantSort) {
+				t.Errorf("sort = %v, want %v", got.options.Sort, tt.wantSort)
+			}
+			if !slices.Equal(got.fields, tt.wantFields) {
+				t.Errorf("fields = %v, want %v", got.fields, tt.wantFields)
+			}
+		})
+	}
+}
+
+func TestParseIntQuery(t *testing.T) {
+	tests := []struct {
+		name    string
+		query   string
+		want    int
+		wantErr bool
+	}{
+		{name: "omitted", query: "", want: 50},
+		{name: "empty", query: "limit=", want: 50},
+		{name: "zero", query: "limit=0", want: 0},
+		{name: "positive", query: "limit=10", want: 10},
+		// 範囲の検証は各ユースケースで行う
+		{name: "negative", query: "limit=-1", want: -1},
+		{name: "not a number", query: "limit=ten", wantErr: true},
+		{name: "fraction", query: "limit=1.5", wantErr: true},
+		{name: "spaces", query: "limit=+10+", wantErr: true},
+		{name: "out of int range", query: "limit=99999999999999999999", wantErr: true},
+		{name: "repeated", query: "limit=1&limit=2", wantErr: true},
+		{name: "repeated same value", query: "limit=1&limit=1", wantErr: true},
+	}
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			got, ok := parseIntQuery(rec, httptest.NewRequest(http.MethodGet, "/api/v1/tasks?"+tt.query, nil), logger, "limit", 50)
+
+			if tt.wantErr {
+				if ok {
+					t.Fatalf("parseIntQuery(%q) = %d, want error", tt.query, got)
+				}
+				if fields := problemFields(t, rec); !slices.Equal(fields, []string{"limit"}) {
+					t.Errorf("error fields = %v, want [limit]", fields)
+				}
+				return
+			}
+			if !ok || got != tt.want {
+				t.Errorf("parseIntQuery(%q) = %d, %v, want %d", tt.query, got, ok, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseBoolQuery(t *testing.T) {
+	tests := []struct {
+		name    string
+		query   string
+		want    bool
+		wantErr bool
+	}{
+		{name: "omitted", query: "", want: false},
+		{name: "true", query: "force=true", want: true},
+		{name: "one", query: "force=1", want: true},
+		{name: "false", query: "force=false", want: false},
+		{name: "not a boolean", query: "force=yes", wantErr: true},
+		{name: "repeated", query: "force=true&force=false", wantErr: true},
+	}
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			got, ok := parseBoolQuery(rec, httptest.NewRequest(http.MethodDelete, "/api/v1/projects/1?"+tt.query, nil), logger, "force")
+
+			if tt.wantErr {
+				if ok {
+					t.Fatalf("parseBoolQuery(%q) = %v, want error", tt.query, got)
+				}
+				if fields := problemFields(t, rec); !slices.Equal(fields, []string{"force"}) {
+					t.Errorf("error fields = %v, want [force]", fields)
+				}
+				return
+			}
+			if !ok || got != tt.want {
+				t.Errorf("parseBoolQuery(%q) = %v, %v, want %v", tt.query, got, ok, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,109 @@
+package handler
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/sikigasa/github-task-controller/backend/internal/application/usecase"
+	"github.com/sikigasa/github-task-controller/backend/internal/domain/model"
+	"github.com/sikigasa/github-task-controller/backend/internal/interface/middleware"
+)
+
+// MilestoneHandler はマイルストーンのHTTPハンドラー
+type MilestoneHandler struct {
+	usecase *usecase.MilestoneUsecase
+	logger  *slog.Logger
+}
+
+// NewMilestoneHandler は新しいMilestoneHandlerを作成する
+func NewMilestoneHandler(usecase *usecase.MilestoneUsecase, logger *slog.Logger) *MilestoneHandler {
+	return &MilestoneHandler{
+		usecase: usecase,
+		logger:  logger,
+	}
+}
+
+// Create はプロジェクトに新しいマイルストーンを作成する
+func (h *MilestoneHandler) Create(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID, _ := middleware.GetUserIDFromContext(ctx)
+	projectID := r.PathValue("id")
+
+	var req model.CreateMilestoneRequest
+	if !decodeAndValidate(w, r, h.logger, &req) {
+		return
+	}
+
+	milestone, err := h.usecase.CreateMilestone(ctx, userID, projectID, &req)
+	if err != nil {
+		respondDomainError(w, r, h.logger, err, "milestone.create_failed")
+		return
+	}
+
+	respondJSON(w, h.logger, http.StatusCreated, milestone)
+}
+
+// ListByProjectID はプロジェクトの全マイルストーンを進捗付きで取得する
+func (h *MilestoneHandler) ListByProjectID(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID, _ := middleware.GetUserIDFromContext(ctx)
+	projectID := r.PathValue("id")
+
+	milestones, err := h.usecase.ListMilestones(ctx, userID, projectID)
+	if err != nil {
+		respondDomainError(w, r, h.logger, err, "milestone.list_failed")
+		return
+	}
+
+	respondJSON(w, h.logger, http.StatusOK, milestones)
+}
+
+// Get はIDでマイルストーンを進捗付きで取得する
+func (h *MilestoneHandler) Get(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID, _ := middleware.GetUserIDFromContext(ctx)
+	id := r.PathValue("id")
+
+	milestone, err := h.usecase.GetMilestone(ctx, userID, id)
+	if err != nil {
+		respondDomainError(w, r, h.logger, err, "milestone.get_failed")
+		return
+	}
+
+	respondJSON(w, h.logger, http.StatusOK, milestone)
+}
+
+// Update はマイルストーン情報を更新する
+func (h *MilestoneHandler) Update(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID, _ := middleware.GetUserIDFromContext(ctx)
+	id := r.PathValue("id")
+
+	var req model.UpdateMilestoneRequest
+	if !decodeAndValidate(w, r, h.logger, &req) {
+		return
+	}
+
+	milestone, err := h.usecase.UpdateMilestone(ctx, userID, id, &req)
+	if err != nil {
+		respondDomainError(w, r, h.logger, err, "milestone.update_failed")
+		return
+	}
+
+	respondJSON(w, h.logger, http.StatusOK, milestone)
+}
+
+// Delete はマイルストーンを削除する
+// 割り当てられていたタスクはマイルストーン未割り当てになる
+func (h *MilestoneHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID, _ := middleware.GetUserIDFromContext(ctx)
+	id := r.PathValue("id")
+
+	if err := h.usecase.DeleteMilestone(ctx, userID, id); err != nil {
+		respondDomainError(w, r, h.logger, err, "milestone.delete_failed")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -1,69 +1,86 @@
 package handler
 
 import (
-	"encoding/json"
+	"errors"
 	"log/slog"
 	"net/http"
+	"slices"
 
 	"github.com/sikigasa/github-task-controller/backend/internal/application/usecase"
+	"github.com/sikigasa/github-task-controller/backend/internal/domain/model"
 	"github.com/sikigasa/github-task-controller/backend/internal/interface/middleware"
 )
 
 // ProjectHandler はプロジェクトのHTTPハンドラー
 type ProjectHandler struct {
-	usecase *usecase.ProjectUsecase
-	logger  *slog.Logger
+	usecase       *usecase.ProjectUsecase
+	githubUsecase *usecase.GithubUsecase
+	logger        *slog.Logger
 }
 
 // NewProjectHandler は新しいProjectHandlerを作成する
-func NewProjectHandler(usecase *usecase.ProjectUsecase, logger *slog.Logger) *ProjectHandler {
+func NewProjectHandler(usecase *usecase.ProjectUsecase, githubUsecase *usecase.GithubUsecase, logger *slog.Logger) *ProjectHandler {
 	return &ProjectHandler{
-		usecase: usecase,
-		logger:  logger,
+		usecase:       usecase,
+		githubUsecase: githubUsecase,
+		logger:        logger,
 	}
 }
 
-// CreateProjectRequest はプロジェクト作成リクエスト
+// projectListQuerySpec はプロジェクト一覧で許可するソートキー・取得フィールド
+var projectListQuerySpec = listQuerySpec{
+	sortable: []string{"title", "created_at", "updated_at"},
+	fields: []string{
+		"user_id", "title", "description", "github_owner", "github_repo", "github_project_number", "github_repo_project", "github_owner_type",
+		"estimate_unit", "github_estimate_field", "github_commit_starts_task", "github_deletion_policy", "github_item_type", "created_at", "updated_at", "tasks", "stats", "github",
+	},
+}
+
+// projectExpandable はプロジェクト取得時にexpandで埋め込める関連リソース
+var projectExpandable = []string{"tasks", "stats", "github"}
+
+// toProjectExpand はexpandの指定をProjectExpandに変換する
+func toProjectExpand(expand []string) model.ProjectExpand {
+	return model.ProjectExpand{
+		Tasks:  slices.Contains(expand, "tasks"),
+		Stats:  slices.Contains(expand, "stats"),
+		Github: slices.Contains(expand, "github"),
+	}
+}
+
+// CreateProjectRequest はプロジェクト作成リクエスト（所有者は認証されたユーザー）
 type CreateProjectRequest struct {
-	UserID      string `json:"user_id"`
-	Title       string `json:"title"`
-	Description string `json:"description"`
+	Title       string `json:"title" validate:"required,max=255"`
+	Description string `json:"description" validate:"max=10000"`
+	// EstimateUnit は見積もりの単位（省略時はpoints）
+	EstimateUnit string `json:"estimate_unit" validate:"omitempty,oneof=points hours"`
 }
 
 // UpdateProjectRequest はプロジェクト更新リクエスト
 type UpdateProjectRequest struct {
-	Title       string `json:"title"`
-	Description string `json:"description"`
+	Title       string `json:"title" validate:"required,max=255"`
+	Description string `json:"description" validate:"max=10000"`
+	// Version は更新元として取得したプロジェクトのバージョン（指定した場合は他の更新と競合すると409にする）
+	Version *int `json:"version,omitempty"`
 }
 
 // Create は新しいプロジェクトを作成する
 func (h *ProjectHandler) Create(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
+	userID, _ := middleware.GetUserIDFromContext(ctx)
 
 	var req CreateProjectRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		h.logger.ErrorContext(ctx, "failed to decode request", "error", err)
-		http.Error(w, "Invalid request body", http.StatusBadRequest)
-		return
-	}
-
-	if req.UserID == "" || req.Title == "" {
-		http.Error(w, "user_id and title are required", http.StatusBadRequest)
+	if !decodeAndValidate(w, r, h.logger, &req) {
 		return
 	}
 
-	project, err := h.usecase.CreateProject(ctx, req.UserID, req.Title, req.Description)
+	project, err := h.usecase.CreateProject(ctx, userID, req.Title, req.Description, model.EstimateUnit(req.EstimateUnit))
 	if err != nil {
-		h.logger.ErrorContext(ctx, "failed to create project", "error", err)
-		http.Error(w, "Failed to create project", http.StatusInternalServerError)
+		respondDomainError(w, r, h.logger, err, "project.create_failed")
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusCreated)
-	if err := json.NewEncoder(w).Encode(project); err != nil {
-		h.logger.ErrorContext(ctx, "failed to encode response", "error", err)
-	}
+	respondJSON(w, h.logger, http.StatusCreated, project)
 }
 
 // Get はIDでプロジェクトを取得する
@@ -71,55 +88,79 @@ func (h *ProjectHandler) Get(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	id := r.PathValue("id")
 
-	// 認証されたユーザーIDを取得
-	authenticatedUserID, ok := middleware.GetUserIDFromContext(ctx)
+	userID, _ := middleware.GetUserIDFromContext(ctx)
+
+	project, err := h.usecase.GetProject(ctx, userID, id)
+	if err != nil {
+		respondDomainError(w, r, h.logger, err, "project.get_failed")
+		return
+	}
+
+	expand, ok := parseExpand(w, r, h.logger, projectExpandable)
 	if !ok {
-		h.logger.ErrorContext(ctx, "user not authenticated")
-		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	if len(expand) == 0 {
+		respondJSON(w, h.logger, http.StatusOK, project)
 		return
 	}
 
-	project, err := h.usecase.GetProject(ctx, id)
+	details, err := h.usecase.ExpandProjects(ctx, userID, []*model.Project{project}, toProjectExpand(expand))
 	if err != nil {
-		h.logger.ErrorContext(ctx, "failed to get project", "error", err, "id", id)
-		http.Error(w, "Project not found", http.StatusNotFound)
+		respondDomainError(w, r, h.logger, err, "project.get_failed")
 		return
 	}
 
-	// プロジェクトの所有者を確認
-	if project.UserID != authenticatedUserID {
-		h.logger.WarnContext(ctx, "unauthorized access attempt", "project_id", id, "project_owner", project.UserID, "authenticated_user", authenticatedUserID)
-		http.Error(w, "Forbidden", http.StatusForbidden)
+	respondJSON(w, h.logger, http.StatusOK, details[0])
+}
+
+// Timeline はプロジェクトのタイムライン（ガントチャート用データ）を取得する
+func (h *ProjectHandler) Timeline(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id := r.PathValue("id")
+
+	userID, _ := middleware.GetUserIDFromContext(ctx)
+
+	timeline, err := h.usecase.GetTimeline(ctx, userID, id)
+	if err != nil {
+		respondDomainError(w, r, h.logger, err, "project.timeline_failed")
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	if err := json.NewEncoder(w).Encode(project); err != nil {
-		h.logger.ErrorContext(ctx, "failed to encode response", "error", err)
-	}
+	respondJSON(w, h.logger, http.StatusOK, timeline)
 }
 
-// ListByUserID はユーザーIDで全プロジェクトを取得する
+// ListByUserID は認証されたユーザーの全プロジェクトを取得する
 func (h *ProjectHandler) ListByUserID(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
-	userID := r.URL.Query().Get("user_id")
+	userID, _ := middleware.GetUserIDFromContext(ctx)
 
-	if userID == "" {
-		http.Error(w, "user_id is required", http.StatusBadRequest)
+	q, ok := parseListQuery(w, r, h.logger, projectListQuerySpec)
+	if !ok {
+		return
+	}
+	expand, ok := parseExpand(w, r, h.logger, projectExpandable)
+	if !ok {
 		return
 	}
 
-	projects, err := h.usecase.ListProjectsByUserID(ctx, userID)
+	projects, err := h.usecase.ListProjectsByUserID(ctx, userID, q.options)
 	if err != nil {
-		h.logger.ErrorContext(ctx, "failed to list projects", "error", err, "user_id", userID)
-		http.Error(w, "Failed to list projects", http.StatusInternalServerError)
+		respondDomainError(w, r, h.logger, err, "project.list_failed")
+		return
+	}
+	if len(expand) == 0 {
+		respondList(w, r, h.logger, projects, q.fields)
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	if err := json.NewEncoder(w).Encode(projects); err != nil {
-		h.logger.ErrorContext(ctx, "failed to encode response", "error", err)
+	details, err := h.usecase.ExpandProjects(ctx, userID, projects, toProjectExpand(expand))
+	if err != nil {
+		respondDomainError(w, r, h.logger, err, "project.list_failed")
+		return
 	}
+
+	respondList(w, r, h.logger, details, q.fields)
 }
 
 // Update はプロジェクト情報を更新する
@@ -127,85 +168,117 @@ func (h *ProjectHandler) Update(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	id := r.PathValue("id")
 
-	// 認証されたユーザーIDを取得
-	authenticatedUserID, ok := middleware.GetUserIDFromContext(ctx)
-	if !ok {
-		h.logger.ErrorContext(ctx, "user not authenticated")
-		http.Error(w, "Unauthorized", http.StatusUnauthorized)
-		return
-	}
+	userID, _ := middleware.GetUserIDFromContext(ctx)
 
 	var req UpdateProjectRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		h.logger.ErrorContext(ctx, "failed to decode request", "error", err)
-		http.Error(w, "Invalid request body", http.StatusBadRequest)
+	if !decodeAndValidate(w, r, h.logger, &req) {
 		return
 	}
 
-	if req.Title == "" {
-		http.Error(w, "title is required", http.StatusBadRequest)
+	project, err := h.usecase.UpdateProject(ctx, userID, id, req.Title, req.Description, req.Version)
+	if err != nil {
+		respondDomainError(w, r, h.logger, err, "project.update_failed")
 		return
 	}
 
-	// プロジェクトを取得して所有者を確認
-	existingProject, err := h.usecase.GetProject(ctx, id)
-	if err != nil {
-		h.logger.ErrorContext(ctx, "failed to get project", "error", err, "id", id)
-		http.Error(w, "Project not found", http.StatusNotFound)
+	respondJSON(w, h.logger, http.StatusOK, project)
+}
+
+// Patch はリクエストに含まれるフィールドのみプロジェクトを更新する
+func (h *ProjectHandler) Patch(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id := r.PathValue("id")
+
+	userID, _ := middleware.GetUserIDFromContext(ctx)
+
+	var req model.PatchProjectRequest
+	if !decodeAndValidate(w, r, h.logger, &req) {
 		return
 	}
 
-	if existingProject.UserID != authenticatedUserID {
-		h.logger.WarnContext(ctx, "unauthorized update attempt", "project_id", id, "project_owner", existingProject.UserID, "authenticated_user", authenticatedUserID)
-		http.Error(w, "Forbidden", http.StatusForbidden)
+	project, err := h.usecase.PatchProject(ctx, userID, id, &req)
+	if err != nil {
+		respondDomainError(w, r, h.logger, err, "project.update_failed")
 		return
 	}
 
-	project, err := h.usecase.UpdateProject(ctx, id, req.Title, req.Description)
+	respondJSON(w, h.logger, http.StatusOK, project)
+}
+
+// DeletePreview はプロジェクトを削除した場合に合わせて削除されるタスクとGitHubのItemを返す
+func (h *ProjectHandler) DeletePreview(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id := r.PathValue("id")
+
+	userID, _ := middleware.GetUserIDFromContext(ctx)
+
+	preview, err := h.usecase.GetDeletePreview(ctx, userID, id)
 	if err != nil {
-		h.logger.ErrorContext(ctx, "failed to update project", "error", err, "id", id)
-		http.Error(w, "Failed to update project", http.StatusInternalServerError)
+		respondDomainError(w, r, h.logger, err, "project.delete_preview_failed")
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	if err := json.NewEncoder(w).Encode(project); err != nil {
-		h.logger.ErrorContext(ctx, "failed to encode response", "error", err)
-	}
+	respondJSON(w, h.logger, http.StatusOK, preview)
 }
 
-// Delete はプロジェクトを削除する
+// Delete はプロジェクトをタスクごとゴミ箱に移す
+// タスクがある場合はforce=trueが必要で、cleanup_github=trueの場合は先に同期済みのGitHub ProjectsのItemを削除する
 func (h *ProjectHandler) Delete(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	id := r.PathValue("id")
 
-	// 認証されたユーザーIDを取得
-	authenticatedUserID, ok := middleware.GetUserIDFromContext(ctx)
+	userID, _ := middleware.GetUserIDFromContext(ctx)
+
+	force, ok := parseBoolQuery(w, r, h.logger, "force")
 	if !ok {
-		h.logger.ErrorContext(ctx, "user not authenticated")
-		http.Error(w, "Unauthorized", http.StatusUnauthorized)
 		return
 	}
-
-	// プロジェクトを取得して所有者を確認
-	project, err := h.usecase.GetProject(ctx, id)
-	if err != nil {
-		h.logger.ErrorContext(ctx, "failed to get project", "error", err, "id", id)
-		http.Error(w, "Project not found", http.StatusNotFound)
+	cleanupGithub, ok := parseBoolQuery(w, r, h.logger, "cleanup_github")
+	if !ok {
 		return
 	}
 
-	if project.UserID != authenticatedUserID {
-		h.logger.WarnContext(ctx, "unauthorized delete attempt", "project_id", id, "project_owner", project.UserID, "authenticated_user", authenticatedUserID)
-		http.Error(w, "Forbidden", http.StatusForbidden)
-		return
+	if cleanupGithub {
+		// タスクがあるのにforceがない場合は削除しないため、GitHubのItemも消さない
+		preview, err := h.usecase.GetDeletePreview(ctx, userID, id)
+		if err != nil {
+			respondDomainError(w, r, h.logger, err, "project.delete_preview_failed")
+			return
+		}
+		if preview.RequiresForce && !force {
+			respondError(w, r, h.logger, http.StatusConflict, "Conflict", "project.delete_has_tasks")
+			return
+		}
+		if _, err := h.githubUsecase.DeleteProjectItems(ctx, userID, id); err != nil {
+			respondDomainError(w, r, h.logger, err, "project.github_cleanup_failed")
+			return
+		}
 	}
 
-	if err := h.usecase.DeleteProject(ctx, id); err != nil {
-		h.logger.ErrorContext(ctx, "failed to delete project", "error", err, "id", id)
-		http.Error(w, "Failed to delete project", http.StatusInternalServerError)
+	if err := h.usecase.DeleteProject(ctx, userID, id, force); err != nil {
+		if errors.Is(err, model.ErrProjectHasTasks) {
+			respondError(w, r, h.logger, http.StatusConflict, "Conflict", "project.delete_has_tasks")
+			return
+		}
+		respondDomainError(w, r, h.logger, err, "project.delete_failed")
 		return
 	}
 
 	w.WriteHeader(http.StatusNoContent)
 }
+
+// Restore はゴミ箱のプロジェクトを、プロジェクトと一緒にゴミ箱に移したタスクごと元に戻す
+func (h *ProjectHandler) Restore(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id := r.PathValue("id")
+
+	userID, _ := middleware.GetUserIDFromContext(ctx)
+
+	project, err := h.usecase.RestoreProject(ctx, userID, id)
+	if err != nil {
+		respondDomainError(w, r, h.logger, err, "project.restore_failed")
+		return
+	}
+
+	respondJSON(w, h.logger, http.StatusOK, project)
+}
@@ -0,0 +1,114 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+
+	"github.com/sikigasa/github-task-controller/backend/internal/application/usecase"
+	"github.com/sikigasa/github-task-controller/backend/internal/domain/model"
+	"github.com/sikigasa/github-task-controller/backend/internal/interface/middleware"
+)
+
+// projectTokenMaxPeekBytes はAPIトークンの対象の確認のために読むタスク作成のリクエストボディの上限
+const projectTokenMaxPeekBytes = 1 << 20
+
+// ProjectTokenHandler はプロジェクト単位のAPIトークンのHTTPハンドラー
+type ProjectTokenHandler struct {
+	usecase *usecase.ProjectTokenUsecase
+	logger  *slog.Logger
+}
+
+// NewProjectTokenHandler は新しいProjectTokenHandlerを作成する
+func NewProjectTokenHandler(usecase *usecase.ProjectTokenUsecase, logger *slog.Logger) *ProjectTokenHandler {
+	return &ProjectTokenHandler{
+		usecase: usecase,
+		logger:  logger,
+	}
+}
+
+// Create はプロジェクトにAPIトークンを発行する（トークンはこのレスポンスでのみ返す）
+func (h *ProjectTokenHandler) Create(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID, _ := middleware.GetUserIDFromContext(ctx)
+
+	var req model.CreateProjectTokenRequest
+	if !decodeAndValidate(w, r, h.logger, &req) {
+		return
+	}
+
+	token, err := h.usecase.CreateToken(ctx, userID, r.PathValue("id"), &req)
+	if err != nil {
+		respondDomainError(w, r, h.logger, err, "project_token.create_failed")
+		return
+	}
+
+	respondJSON(w, h.logger, http.StatusCreated, token)
+}
+
+// List はプロジェクトに発行したAPIトークンを一覧する
+func (h *ProjectTokenHandler) List(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID, _ := middleware.GetUserIDFromContext(ctx)
+
+	tokens, err := h.usecase.ListTokens(ctx, userID, r.PathValue("id"))
+	if err != nil {
+		respondDomainError(w, r, h.logger, err, "project_token.list_failed")
+		return
+	}
+
+	respondJSON(w, h.logger, http.StatusOK, tokens)
+}
+
+// Delete はプロジェクトのAPIトークンを失効させる
+func (h *ProjectTokenHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID, _ := middleware.GetUserIDFromContext(ctx)
+
+	if err := h.usecase.RevokeToken(ctx, userID, r.PathValue("id"), r.PathValue("tokenId")); err != nil {
+		respondDomainError(w, r, h.logger, err, "project_token.delete_failed")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ProjectIDFromPath はパスの{id}をリクエストの対象のプロジェクトとする（/api/v1/projects/{id}/...）
+func ProjectIDFromPath(r *http.Request) (string, error) {
+	return r.PathValue("id"), nil
+}
+
+// ProjectIDFromQuery はクエリのproject_idをリクエストの対象のプロジェクトとする
+func ProjectIDFromQuery(r *http.Request) (string, error) {
+	return r.URL.Query().Get("project_id"), nil
+}
+
+// TaskProjectID はパスの{id}のタスクが属するプロジェクトをリクエストの対象とする（/api/v1/tasks/{id}/...）
+func (h *TaskHandler) TaskProjectID(r *http.Request) (string, error) {
+	userID, _ := middleware.GetUserIDFromContext(r.Context())
+	task, err := h.usecase.GetTask(r.Context(), userID, r.PathValue("id"))
+	if err != nil {
+		return "", err
+	}
+	return task.ProjectID, nil
+}
+
+// CreateTaskProjectID はタスク作成のリクエストボディのproject_idをリクエストの対象とする
+// ボディは読んだ後にハンドラーで読めるよう元に戻す
+func (h *TaskHandler) CreateTaskProjectID(r *http.Request) (string, error) {
+	body, err := io.ReadAll(io.LimitReader(r.Body, projectTokenMaxPeekBytes))
+	if err != nil {
+		return "", err
+	}
+	r.Body = io.NopCloser(io.MultiReader(bytes.NewReader(body), r.Body))
+
+	var req struct {
+		ProjectID string `json:"project_id"`
+	}
+	if err := json.Unmarshal(body, &req); err != nil {
+		return "", err
+	}
+	return req.ProjectID, nil
+}
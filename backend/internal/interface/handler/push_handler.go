@@ -0,0 +1,70 @@
+package handler
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/sikigasa/github-task-controller/backend/internal/application/usecase"
+	"github.com/sikigasa/github-task-controller/backend/internal/domain/model"
+	"github.com/sikigasa/github-task-controller/backend/internal/interface/middleware"
+)
+
+// PushHandler はプッシュ通知を受け取る端末の登録のHTTPハンドラー
+type PushHandler struct {
+	usecase *usecase.PushUsecase
+	logger  *slog.Logger
+}
+
+// NewPushHandler は新しいPushHandlerを作成する
+func NewPushHandler(usecase *usecase.PushUsecase, logger *slog.Logger) *PushHandler {
+	return &PushHandler{
+		usecase: usecase,
+		logger:  logger,
+	}
+}
+
+// RegisterDevice はプッシュ通知を受け取る端末を登録する（登録済みのトークンの場合は登録済みの端末を返す）
+func (h *PushHandler) RegisterDevice(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID, _ := middleware.GetUserIDFromContext(ctx)
+
+	var req model.RegisterPushDeviceRequest
+	if !decodeAndValidate(w, r, h.logger, &req) {
+		return
+	}
+
+	device, err := h.usecase.RegisterDevice(ctx, userID, &req)
+	if err != nil {
+		respondDomainError(w, r, h.logger, err, "push.register_failed")
+		return
+	}
+
+	respondJSON(w, h.logger, http.StatusOK, device)
+}
+
+// ListDevices はユーザーが登録した端末を一覧する
+func (h *PushHandler) ListDevices(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID, _ := middleware.GetUserIDFromContext(ctx)
+
+	devices, err := h.usecase.ListDevices(ctx, userID)
+	if err != nil {
+		respondDomainError(w, r, h.logger, err, "push.list_failed")
+		return
+	}
+
+	respondJSON(w, h.logger, http.StatusOK, devices)
+}
+
+// DeleteDevice は端末の登録を削除する
+func (h *PushHandler) DeleteDevice(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID, _ := middleware.GetUserIDFromContext(ctx)
+
+	if err := h.usecase.DeleteDevice(ctx, userID, r.PathValue("id")); err != nil {
+		respondDomainError(w, r, h.logger, err, "push.delete_failed")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
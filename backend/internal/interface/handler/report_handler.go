@@ -0,0 +1,158 @@
+package handler
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/sikigasa/github-task-controller/backend/internal/application/usecase"
+	"github.com/sikigasa/github-task-controller/backend/internal/interface/middleware"
+)
+
+// ReportHandler はレポートのHTTPハンドラー
+type ReportHandler struct {
+	usecase *usecase.ReportUsecase
+	logger  *slog.Logger
+}
+
+// NewReportHandler は新しいReportHandlerを作成する
+func NewReportHandler(usecase *usecase.ReportUsecase, logger *slog.Logger) *ReportHandler {
+	return &ReportHandler{
+		usecase: usecase,
+		logger:  logger,
+	}
+}
+
+// Velocity はプロジェクトの期間ごとのベロシティを取得する
+//
+//   - iteration_weeks: 1期間の週数（デフォルト2）
+//   - iterations: 集計する期間数（デフォルト6、現在進行中の期間を含む）
+func (h *ReportHandler) Velocity(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID, _ := middleware.GetUserIDFromContext(ctx)
+	projectID := r.PathValue("id")
+
+	weeks, ok := parseIntQuery(w, r, h.logger, "iteration_weeks", 2)
+	if !ok {
+		return
+	}
+	iterations, ok := parseIntQuery(w, r, h.logger, "iterations", 6)
+	if !ok {
+		return
+	}
+
+	report, err := h.usecase.GetVelocity(ctx, userID, projectID, weeks, iterations)
+	if err != nil {
+		respondDomainError(w, r, h.logger, err, "report.velocity_failed")
+		return
+	}
+
+	respondJSON(w, h.logger, http.StatusOK, report)
+}
+
+// Overdue はログイン中のユーザーの全プロジェクトの期限切れ・期限間近のタスクをプロジェクトごとに取得する
+//
+//   - due_soon_days: 期限間近とみなす日数（デフォルト3）
+func (h *ReportHandler) Overdue(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID, _ := middleware.GetUserIDFromContext(ctx)
+
+	dueSoonDays, ok := parseIntQuery(w, r, h.logger, "due_soon_days", 3)
+	if !ok {
+		return
+	}
+
+	report, err := h.usecase.GetOverdueReport(ctx, userID, dueSoonDays)
+	if err != nil {
+		respondDomainError(w, r, h.logger, err, "report.overdue_failed")
+		return
+	}
+
+	respondJSON(w, h.logger, http.StatusOK, report)
+}
+
+// CumulativeFlow はプロジェクトの累積フロー図（CFD）のデータを取得する
+//
+//   - days: 今日を含めて集計する日数（デフォルト30）
+func (h *ReportHandler) CumulativeFlow(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID, _ := middleware.GetUserIDFromContext(ctx)
+	projectID := r.PathValue("id")
+
+	days, ok := parseIntQuery(w, r, h.logger, "days", 30)
+	if !ok {
+		return
+	}
+
+	cfd, err := h.usecase.GetCumulativeFlow(ctx, userID, projectID, days)
+	if err != nil {
+		respondDomainError(w, r, h.logger, err, "report.cfd_failed")
+		return
+	}
+
+	respondJSON(w, h.logger, http.StatusOK, cfd)
+}
+
+// FlowTime はプロジェクトのリードタイム（作成→完了）とサイクルタイム（開始→完了）の分布を取得する
+//
+//   - days: 集計対象とする完了日の期間（デフォルト90）
+func (h *ReportHandler) FlowTime(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID, _ := middleware.GetUserIDFromContext(ctx)
+	projectID := r.PathValue("id")
+
+	days, ok := parseIntQuery(w, r, h.logger, "days", 90)
+	if !ok {
+		return
+	}
+
+	report, err := h.usecase.GetFlowTime(ctx, userID, projectID, days)
+	if err != nil {
+		respondDomainError(w, r, h.logger, err, "report.flow_time_failed")
+		return
+	}
+
+	respondJSON(w, h.logger, http.StatusOK, report)
+}
+
+// TimeInStatus はプロジェクトのタスクのステータスごとの滞留時間を取得する
+//
+//   - stuck_days: 進行中のまま滞留しているとみなす日数（デフォルト7）
+func (h *ReportHandler) TimeInStatus(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID, _ := middleware.GetUserIDFromContext(ctx)
+	projectID := r.PathValue("id")
+
+	stuckDays, ok := parseIntQuery(w, r, h.logger, "stuck_days", 7)
+	if !ok {
+		return
+	}
+
+	report, err := h.usecase.GetTimeInStatus(ctx, userID, projectID, stuckDays)
+	if err != nil {
+		respondDomainError(w, r, h.logger, err, "report.time_in_status_failed")
+		return
+	}
+
+	respondJSON(w, h.logger, http.StatusOK, report)
+}
+
+// UserStats はログイン中のユーザーの生産性の統計を取得する
+//
+//   - days: 今日を含めて集計する日数（デフォルト30）
+func (h *ReportHandler) UserStats(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID, _ := middleware.GetUserIDFromContext(ctx)
+
+	days, ok := parseIntQuery(w, r, h.logger, "days", 30)
+	if !ok {
+		return
+	}
+
+	stats, err := h.usecase.GetUserStats(ctx, userID, days)
+	if err != nil {
+		respondDomainError(w, r, h.logger, err, "report.stats_failed")
+		return
+	}
+
+	respondJSON(w, h.logger, http.StatusOK, stats)
+}
@@ -0,0 +1,186 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/sikigasa/github-task-controller/backend/internal/domain/model"
+	"github.com/sikigasa/github-task-controller/backend/internal/infrastructure/github"
+	"github.com/sikigasa/github-task-controller/backend/internal/interface/i18n"
+)
+
+// ProblemDetail はRFC 9457に準拠したエラーレスポンス
+type ProblemDetail struct {
+	Type     string `json:"type"`
+	Title    string `json:"title"`
+	Status   int    `json:"status"`
+	Detail   string `json:"detail,omitempty"`
+	Instance string `json:"instance,omitempty"`
+	// Errors はフィールド単位のバリデーションエラー（RFC 9457の拡張メンバー）
+	Errors []FieldError `json:"errors,omitempty"`
+	// ReauthReason・ReauthURL はGitHubの再認証が必要な場合の種類と手続きのパス（RFC 9457の拡張メンバー）
+	ReauthReason string `json:"reauth_reason,omitempty"`
+	ReauthURL    string `json:"reauth_url,omitempty"`
+	// QuotaLimit・QuotaResetAt はGitHubとの同期の上限に達した場合の1時間あたりの上限と上限が戻る時刻（RFC 9457の拡張メンバー）
+	QuotaLimit   int        `json:"quota_limit,omitempty"`
+	QuotaResetAt *time.Time `json:"quota_reset_at,omitempty"`
+	// Current は更新元のバージョンが古い場合のリソースの現在の状態（RFC 9457の拡張メンバー）
+	Current any `json:"current,omitempty"`
+}
+
+// FieldError はフィールド単位のバリデーションエラー
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// respondJSON はJSON形式でレスポンスを返す
+func respondJSON(w http.ResponseWriter, logger *slog.Logger, status int, data any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(data); err != nil {
+		logger.Error("failed to encode response", "error", err)
+	}
+}
+
+// respondError はRFC 9457形式のエラーレスポンスを返す
+// detailKeyはメッセージカタログのキーで、リクエストの言語に翻訳して返す
+func respondError(w http.ResponseWriter, r *http.Request, logger *slog.Logger, status int, title string, detailKey string) {
+	respondProblem(w, r, logger, ProblemDetail{
+		Type:   "about:blank",
+		Title:  title,
+		Status: status,
+		Detail: i18n.T(r.Context(), detailKey),
+	})
+}
+
+// respondProblem はProblemDetailをレスポンスとして返す
+func respondProblem(w http.ResponseWriter, r *http.Request, logger *slog.Logger, problem ProblemDetail) {
+	if problem.Instance == "" {
+		problem.Instance = r.URL.Path
+	}
+
+	// ログレベルを適切に設定
+	ctx := r.Context()
+	switch {
+	case problem.Status >= 500:
+		logger.ErrorContext(ctx, "server error", "status", problem.Status, "title", problem.Title, "detail", problem.Detail, "path", r.URL.Path)
+	case problem.Status == 401 || problem.Status == 403 || problem.Status == 409 || problem.Status == 429:
+		logger.WarnContext(ctx, "client error requiring attention", "status", problem.Status, "title", problem.Title, "path", r.URL.Path)
+	default:
+		logger.InfoContext(ctx, "client error", "status", problem.Status, "title", problem.Title, "path", r.URL.Path)
+	}
+
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(problem.Status)
+	if err := json.NewEncoder(w).Encode(problem); err != nil {
+		logger.ErrorContext(ctx, "failed to encode response", "error", err)
+	}
+}
+
+// domainErrorResponse はドメインエラーに対応するHTTPステータス・タイトル・詳細（メッセージキー）
+type domainErrorResponse struct {
+	status    int
+	title     string
+	detailKey string
+}
+
+// domainErrorResponses はドメインエラーとHTTPレスポンスの対応表
+// ドメインエラーからHTTPステータスへの変換はこの表に集約する
+var domainErrorResponses = []struct {
+	err error
+	res domainErrorResponse
+}{
+	{model.ErrNotFound, domainErrorResponse{http.StatusNotFound, "Not Found", "error.not_found"}},
+	{model.ErrUnauthorized, domainErrorResponse{http.StatusUnauthorized, "Unauthorized", "error.unauthorized"}},
+	{model.ErrForbidden, domainErrorResponse{http.StatusForbidden, "Forbidden", "error.forbidden"}},
+	{model.ErrInvalidInput, domainErrorResponse{http.StatusBadRequest, "Invalid Input", "error.invalid_input"}},
+	{model.ErrConflict, domainErrorResponse{http.StatusConflict, "Conflict", "error.conflict"}},
+	{model.ErrRateLimited, domainErrorResponse{http.StatusTooManyRequests, "Too Many Requests", "error.too_many_requests"}},
+	// GitHubのレート制限のリセットを待てなかった場合
+	{github.ErrRateLimited, domainErrorResponse{http.StatusTooManyRequests, "Too Many Requests", "github.rate_limited"}},
+}
+
+// respondDomainError はドメインエラーを対応するRFC 9457形式のレスポンスに変換して返す
+// 対応表にないエラーは500として扱い、detailKeyのメッセージを利用者向けに返す
+func respondDomainError(w http.ResponseWriter, r *http.Request, logger *slog.Logger, err error, detailKey string) {
+	// GitHubの再認証はセッションの401と区別できるよう、手続きのパスを付けて返す
+	if errors.Is(err, model.ErrGithubReauthRequired) {
+		respondGithubReauth(w, r, logger, err)
+		return
+	}
+	// GitHubとの同期の上限は、再試行できる時刻と上限を付けて返す
+	var quotaErr *model.GithubSyncQuotaError
+	if errors.As(err, &quotaErr) {
+		respondGithubSyncQuota(w, r, logger, quotaErr)
+		return
+	}
+	// 他の更新と競合した場合は、リソースの現在の状態を付けて返す
+	var staleErr *model.StaleVersionError
+	if errors.As(err, &staleErr) {
+		respondStaleVersion(w, r, logger, staleErr)
+		return
+	}
+
+	for _, m := range domainErrorResponses {
+		if errors.Is(err, m.err) {
+			respondError(w, r, logger, m.res.status, m.res.title, m.res.detailKey)
+			return
+		}
+	}
+
+	logger.ErrorContext(r.Context(), "unhandled error", "error", err, "path", r.URL.Path)
+	respondError(w, r, logger, http.StatusInternalServerError, "Internal Server Error", detailKey)
+}
+
+// respondGithubReauth はGitHubの再認証が必要なことを401で返す
+// 拒否されたトークンの種類が分からない場合はOAuthで再ログインする手続きを返す
+func respondGithubReauth(w http.ResponseWriter, r *http.Request, logger *slog.Logger, err error) {
+	reason := model.GithubReauthReasonOAuth
+	var reauthErr *model.GithubReauthError
+	if errors.As(err, &reauthErr) {
+		reason = reauthErr.Reason
+	}
+
+	respondProblem(w, r, logger, ProblemDetail{
+		Type:         "about:blank",
+		Title:        "Unauthorized",
+		Status:       http.StatusUnauthorized,
+		Detail:       i18n.T(r.Context(), "github.reauth_required."+string(reason)),
+		ReauthReason: string(reason),
+		ReauthURL:    reason.ReauthPath(),
+	})
+}
+
+// respondStaleVersion は更新元のバージョンが古く他の更新と競合したことを、リソースの現在の状態を付けて409で返す
+func respondStaleVersion(w http.ResponseWriter, r *http.Request, logger *slog.Logger, staleErr *model.StaleVersionError) {
+	respondProblem(w, r, logger, ProblemDetail{
+		Type:    "about:blank",
+		Title:   "Conflict",
+		Status:  http.StatusConflict,
+		Detail:  i18n.T(r.Context(), "error.stale_version"),
+		Current: staleErr.Current,
+	})
+}
+
+// respondGithubSyncQuota はユーザーのGitHubとの同期の上限に達したことを429で返す
+// Retry-Afterには上限が戻るまでの秒数を設定する
+func respondGithubSyncQuota(w http.ResponseWriter, r *http.Request, logger *slog.Logger, quotaErr *model.GithubSyncQuotaError) {
+	retryAfter := max(int(math.Ceil(time.Until(quotaErr.ResetAt).Seconds())), 1)
+	w.Header().Set("Retry-After", strconv.Itoa(retryAfter))
+
+	resetAt := quotaErr.ResetAt
+	respondProblem(w, r, logger, ProblemDetail{
+		Type:         "about:blank",
+		Title:        "Too Many Requests",
+		Status:       http.StatusTooManyRequests,
+		Detail:       i18n.T(r.Context(), "github.sync_quota_exceeded"),
+		QuotaLimit:   quotaErr.Limit,
+		QuotaResetAt: &resetAt,
+	})
+}
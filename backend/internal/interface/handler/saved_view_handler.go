@@ -0,0 +1,130 @@
+package handler
+
+import (
+	"log/slog"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/sikigasa/github-task-controller/backend/internal/application/usecase"
+	"github.com/sikigasa/github-task-controller/backend/internal/domain/model"
+	"github.com/sikigasa/github-task-controller/backend/internal/interface/i18n"
+	"github.com/sikigasa/github-task-controller/backend/internal/interface/middleware"
+)
+
+// SavedViewHandler は保存済みビューのHTTPハンドラー
+type SavedViewHandler struct {
+	usecase *usecase.SavedViewUsecase
+	logger  *slog.Logger
+}
+
+// NewSavedViewHandler は新しいSavedViewHandlerを作成する
+func NewSavedViewHandler(usecase *usecase.SavedViewUsecase, logger *slog.Logger) *SavedViewHandler {
+	return &SavedViewHandler{
+		usecase: usecase,
+		logger:  logger,
+	}
+}
+
+// Create はプロジェクトにビューを保存する
+func (h *SavedViewHandler) Create(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID, _ := middleware.GetUserIDFromContext(ctx)
+	projectID := r.PathValue("id")
+
+	var req model.CreateSavedViewRequest
+	if !decodeAndValidate(w, r, h.logger, &req) {
+		return
+	}
+
+	// ソートキーはタスク一覧のsortクエリと同じフィールドのみ許可する
+	var fieldErrors []FieldError
+	for _, key := range req.Sort {
+		if !slices.Contains(taskListQuerySpec.sortable, key.Field) {
+			fieldErrors = append(fieldErrors, FieldError{Field: "sort", Message: i18n.T(ctx, "query.invalid_sort", key.Field, strings.Join(taskListQuerySpec.sortable, ", "))})
+		}
+	}
+	if len(fieldErrors) > 0 {
+		respondProblem(w, r, h.logger, ProblemDetail{
+			Type:   "about:blank",
+			Title:  "Invalid Input",
+			Status: http.StatusBadRequest,
+			Detail: i18n.T(ctx, "error.invalid_input"),
+			Errors: fieldErrors,
+		})
+		return
+	}
+
+	view, err := h.usecase.CreateView(ctx, userID, projectID, &req)
+	if err != nil {
+		respondDomainError(w, r, h.logger, err, "view.create_failed")
+		return
+	}
+
+	respondJSON(w, h.logger, http.StatusCreated, view)
+}
+
+// ListByProjectID はユーザーがプロジェクトに保存したビューを取得する
+func (h *SavedViewHandler) ListByProjectID(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID, _ := middleware.GetUserIDFromContext(ctx)
+	projectID := r.PathValue("id")
+
+	views, err := h.usecase.ListViews(ctx, userID, projectID)
+	if err != nil {
+		respondDomainError(w, r, h.logger, err, "view.list_failed")
+		return
+	}
+
+	respondJSON(w, h.logger, http.StatusOK, views)
+}
+
+// Get はIDでビューを取得する
+func (h *SavedViewHandler) Get(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID, _ := middleware.GetUserIDFromContext(ctx)
+	id := r.PathValue("id")
+
+	view, err := h.usecase.GetView(ctx, userID, id)
+	if err != nil {
+		respondDomainError(w, r, h.logger, err, "view.get_failed")
+		return
+	}
+
+	respondJSON(w, h.logger, http.StatusOK, view)
+}
+
+// ListTasks はビューの絞り込み・並び順でタスクを取得する
+// sort・order・fieldsはタスク一覧と同様に指定でき、sortは保存済みの並び順より優先する
+func (h *SavedViewHandler) ListTasks(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID, _ := middleware.GetUserIDFromContext(ctx)
+	id := r.PathValue("id")
+
+	q, ok := parseListQuery(w, r, h.logger, taskListQuerySpec)
+	if !ok {
+		return
+	}
+
+	tasks, err := h.usecase.ListViewTasks(ctx, userID, id, q.options)
+	if err != nil {
+		respondDomainError(w, r, h.logger, err, "view.tasks_failed")
+		return
+	}
+
+	respondList(w, r, h.logger, tasks, q.fields)
+}
+
+// Delete はビューを削除する
+func (h *SavedViewHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID, _ := middleware.GetUserIDFromContext(ctx)
+	id := r.PathValue("id")
+
+	if err := h.usecase.DeleteView(ctx, userID, id); err != nil {
+		respondDomainError(w, r, h.logger, err, "view.delete_failed")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
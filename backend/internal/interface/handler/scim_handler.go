@@ -0,0 +1,346 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/sikigasa/github-task-controller/backend/internal/application/usecase"
+	"github.com/sikigasa/github-task-controller/backend/internal/domain/model"
+)
+
+// scimMaxBodyBytes はSCIMリクエストボディの最大サイズ
+const scimMaxBodyBytes = 1 << 20
+
+// SCIMHandler はIdPからのプロビジョニング（SCIM 2.0）のHTTPハンドラー
+// レスポンスはRFC 9457ではなくSCIMの形式（application/scim+json）で返す
+type SCIMHandler struct {
+	usecase *usecase.ProvisioningUsecase
+	baseURL string
+	logger  *slog.Logger
+}
+
+// NewSCIMHandler は新しいSCIMHandlerを作成する
+// baseURLはリソースのlocationに使うSCIMエンドポイントの公開URL（例: https://api.example.com/scim/v2）
+func NewSCIMHandler(usecase *usecase.ProvisioningUsecase, baseURL string, logger *slog.Logger) *SCIMHandler {
+	return &SCIMHandler{
+		usecase: usecase,
+		baseURL: baseURL,
+		logger:  logger,
+	}
+}
+
+// ServiceProviderConfig は対応しているSCIMの機能を返す
+func (h *SCIMHandler) ServiceProviderConfig(w http.ResponseWriter, r *http.Request) {
+	h.respond(w, http.StatusOK, map[string]any{
+		"schemas":        []string{scimSchemaSPConfig},
+		"patch":          map[string]bool{"supported": true},
+		"bulk":           map[string]any{"supported": false, "maxOperations": 0, "maxPayloadSize": 0},
+		"filter":         map[string]any{"supported": true, "maxResults": usecase.MaxProvisioningPageSize},
+		"changePassword": map[string]bool{"supported": false},
+		"sort":           map[string]bool{"supported": false},
+		"etag":           map[string]bool{"supported": false},
+		"authenticationSchemes": []map[string]any{{
+			"type":        "oauthbearertoken",
+			"name":        "Bearer Token",
+			"description": "Authorization: Bearer <SCIM_TOKEN>",
+		}},
+	})
+}
+
+// ResourceTypes は提供しているリソースの種類を返す
+func (h *SCIMHandler) ResourceTypes(w http.ResponseWriter, r *http.Request) {
+	resourceTypes := []map[string]any{
+		{"schemas": []string{scimSchemaResourceType}, "id": "User", "name": "User", "endpoint": "/Users", "schema": scimSchemaUser},
+		{"schemas": []string{scimSchemaResourceType}, "id": "Group", "name": "Group", "endpoint": "/Groups", "schema": scimSchemaGroup},
+	}
+	h.respondList(w, resourceTypes, len(resourceTypes), len(resourceTypes), 1)
+}
+
+// ListUsers はユーザーを一覧する（userName eq "..." のフィルターに対応）
+func (h *SCIMHandler) ListUsers(w http.ResponseWriter, r *http.Request) {
+	email, err := parseSCIMFilter(r.URL.Query().Get("filter"), "userName")
+	if err != nil {
+		h.respondError(w, r, http.StatusBadRequest, "invalidFilter", err.Error())
+		return
+	}
+	startIndex, count, ok := h.parsePaging(w, r)
+	if !ok {
+		return
+	}
+
+	users, total, err := h.usecase.ListUsers(r.Context(), email, startIndex, count)
+	if err != nil {
+		h.respondDomainError(w, r, err)
+		return
+	}
+
+	resources := make([]scimUser, 0, len(users))
+	for _, user := range users {
+		resources = append(resources, newSCIMUser(user, h.baseURL))
+	}
+	h.respondList(w, resources, len(resources), total, startIndex)
+}
+
+// GetUser はユーザーを取得する
+func (h *SCIMHandler) GetUser(w http.ResponseWriter, r *http.Request) {
+	user, err := h.usecase.GetUser(r.Context(), r.PathValue("id"))
+	if err != nil {
+		h.respondDomainError(w, r, err)
+		return
+	}
+	h.respond(w, http.StatusOK, newSCIMUser(user, h.baseURL))
+}
+
+// CreateUser はユーザーを作成する
+func (h *SCIMHandler) CreateUser(w http.ResponseWriter, r *http.Request) {
+	var req scimUser
+	if !h.decode(w, r, &req) {
+		return
+	}
+
+	active := req.Active == nil || *req.Active
+	user, err := h.usecase.CreateUser(r.Context(), req.email(), req.displayName(), active)
+	if err != nil {
+		h.respondDomainError(w, r, err)
+		return
+	}
+	h.respond(w, http.StatusCreated, newSCIMUser(user, h.baseURL))
+}
+
+// ReplaceUser はユーザーを置き換える（PUT）
+func (h *SCIMHandler) ReplaceUser(w http.ResponseWriter, r *http.Request) {
+	var req scimUser
+	if !h.decode(w, r, &req) {
+		return
+	}
+
+	active := req.Active == nil || *req.Active
+	user, err := h.usecase.UpdateUser(r.Context(), r.PathValue("id"), req.email(), req.displayName(), active)
+	if err != nil {
+		h.respondDomainError(w, r, err)
+		return
+	}
+	h.respond(w, http.StatusOK, newSCIMUser(user, h.baseURL))
+}
+
+// PatchUser はユーザーを部分更新する（無効化はactiveをfalseにする操作で行われる）
+func (h *SCIMHandler) PatchUser(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	var req scimPatchRequest
+	if !h.decode(w, r, &req) {
+		return
+	}
+
+	user, err := h.usecase.GetUser(ctx, r.PathValue("id"))
+	if err != nil {
+		h.respondDomainError(w, r, err)
+		return
+	}
+	patch, err := applySCIMUserPatch(user, &req)
+	if err != nil {
+		h.respondError(w, r, http.StatusBadRequest, "invalidValue", err.Error())
+		return
+	}
+
+	user, err = h.usecase.UpdateUser(ctx, user.ID, patch.email, patch.name, patch.active)
+	if err != nil {
+		h.respondDomainError(w, r, err)
+		return
+	}
+	h.respond(w, http.StatusOK, newSCIMUser(user, h.baseURL))
+}
+
+// DeleteUser はユーザーを削除する
+func (h *SCIMHandler) DeleteUser(w http.ResponseWriter, r *http.Request) {
+	if err := h.usecase.DeleteUser(r.Context(), r.PathValue("id")); err != nil {
+		h.respondDomainError(w, r, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ListGroups はグループを一覧する（displayName eq "..." のフィルターに対応）
+func (h *SCIMHandler) ListGroups(w http.ResponseWriter, r *http.Request) {
+	displayName, err := parseSCIMFilter(r.URL.Query().Get("filter"), "displayName")
+	if err != nil {
+		h.respondError(w, r, http.StatusBadRequest, "invalidFilter", err.Error())
+		return
+	}
+	startIndex, count, ok := h.parsePaging(w, r)
+	if !ok {
+		return
+	}
+
+	groups, total, err := h.usecase.ListGroups(r.Context(), displayName, startIndex, count)
+	if err != nil {
+		h.respondDomainError(w, r, err)
+		return
+	}
+
+	resources := make([]scimGroup, 0, len(groups))
+	for _, group := range groups {
+		resources = append(resources, newSCIMGroup(group, h.baseURL))
+	}
+	h.respondList(w, resources, len(resources), total, startIndex)
+}
+
+// GetGroup はグループを取得する
+func (h *SCIMHandler) GetGroup(w http.ResponseWriter, r *http.Request) {
+	group, err := h.usecase.GetGroup(r.Context(), r.PathValue("id"))
+	if err != nil {
+		h.respondDomainError(w, r, err)
+		return
+	}
+	h.respond(w, http.StatusOK, newSCIMGroup(group, h.baseURL))
+}
+
+// CreateGroup はグループを作成する
+func (h *SCIMHandler) CreateGroup(w http.ResponseWriter, r *http.Request) {
+	var req scimGroup
+	if !h.decode(w, r, &req) {
+		return
+	}
+
+	memberIDs := make([]string, 0, len(req.Members))
+	for _, m := range req.Members {
+		memberIDs = append(memberIDs, m.Value)
+	}
+	group, err := h.usecase.CreateGroup(r.Context(), req.DisplayName, req.ExternalID, memberIDs)
+	if err != nil {
+		h.respondDomainError(w, r, err)
+		return
+	}
+	h.respond(w, http.StatusCreated, newSCIMGroup(group, h.baseURL))
+}
+
+// ReplaceGroup はグループを置き換える（PUT）
+func (h *SCIMHandler) ReplaceGroup(w http.ResponseWriter, r *http.Request) {
+	var req scimGroup
+	if !h.decode(w, r, &req) {
+		return
+	}
+
+	memberIDs := make([]string, 0, len(req.Members))
+	for _, m := range req.Members {
+		memberIDs = append(memberIDs, m.Value)
+	}
+	group, err := h.usecase.PatchGroup(r.Context(), r.PathValue("id"), model.GroupPatch{
+		DisplayName: &req.DisplayName,
+		ExternalID:  &req.ExternalID,
+		Members:     &memberIDs,
+	})
+	if err != nil {
+		h.respondDomainError(w, r, err)
+		return
+	}
+	h.respond(w, http.StatusOK, newSCIMGroup(group, h.baseURL))
+}
+
+// PatchGroup はグループの表示名・メンバーを部分更新する
+func (h *SCIMHandler) PatchGroup(w http.ResponseWriter, r *http.Request) {
+	var req scimPatchRequest
+	if !h.decode(w, r, &req) {
+		return
+	}
+
+	patch, err := parseSCIMGroupPatch(&req)
+	if err != nil {
+		h.respondError(w, r, http.StatusBadRequest, "invalidValue", err.Error())
+		return
+	}
+	group, err := h.usecase.PatchGroup(r.Context(), r.PathValue("id"), patch)
+	if err != nil {
+		h.respondDomainError(w, r, err)
+		return
+	}
+	h.respond(w, http.StatusOK, newSCIMGroup(group, h.baseURL))
+}
+
+// DeleteGroup はグループを削除する
+func (h *SCIMHandler) DeleteGroup(w http.ResponseWriter, r *http.Request) {
+	if err := h.usecase.DeleteGroup(r.Context(), r.PathValue("id")); err != nil {
+		h.respondDomainError(w, r, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// parsePaging はstartIndex（1始まり）とcountのクエリパラメータを解析する
+func (h *SCIMHandler) parsePaging(w http.ResponseWriter, r *http.Request) (int, int, bool) {
+	startIndex, count := 1, usecase.MaxProvisioningPageSize
+	for name, dst := range map[string]*int{"startIndex": &startIndex, "count": &count} {
+		raw := r.URL.Query().Get(name)
+		if raw == "" {
+			continue
+		}
+		v, err := strconv.Atoi(raw)
+		if err != nil {
+			h.respondError(w, r, http.StatusBadRequest, "invalidValue", name+" must be an integer")
+			return 0, 0, false
+		}
+		*dst = v
+	}
+	return max(startIndex, 1), count, true
+}
+
+// decode はリクエストボディをデコードする（失敗した場合はエラーレスポンスを書き込む）
+func (h *SCIMHandler) decode(w http.ResponseWriter, r *http.Request, dst any) bool {
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, scimMaxBodyBytes)).Decode(dst); err != nil {
+		h.respondError(w, r, http.StatusBadRequest, "invalidSyntax", "invalid request body")
+		return false
+	}
+	return true
+}
+
+// respond はSCIMのレスポンスを返す
+func (h *SCIMHandler) respond(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/scim+json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		h.logger.Error("failed to encode scim response", "error", err)
+	}
+}
+
+// respondList はSCIMの一覧レスポンスを返す
+func (h *SCIMHandler) respondList(w http.ResponseWriter, resources any, items, total, startIndex int) {
+	h.respond(w, http.StatusOK, scimListResponse{
+		Schemas:      []string{scimSchemaListResponse},
+		TotalResults: total,
+		StartIndex:   startIndex,
+		ItemsPerPage: items,
+		Resources:    resources,
+	})
+}
+
+// respondError はSCIMのエラーレスポンスを返す
+func (h *SCIMHandler) respondError(w http.ResponseWriter, r *http.Request, status int, scimType, detail string) {
+	if status >= 500 {
+		h.logger.ErrorContext(r.Context(), "scim server error", "status", status, "detail", detail, "path", r.URL.Path)
+	} else {
+		h.logger.InfoContext(r.Context(), "scim client error", "status", status, "scim_type", scimType, "detail", detail, "path", r.URL.Path)
+	}
+	h.respond(w, status, scimErrorResponse{
+		Schemas:  []string{scimSchemaError},
+		Status:   strconv.Itoa(status),
+		ScimType: scimType,
+		Detail:   detail,
+	})
+}
+
+// respondDomainError はドメインエラーをSCIMのエラーレスポンスに変換して返す
+func (h *SCIMHandler) respondDomainError(w http.ResponseWriter, r *http.Request, err error) {
+	switch {
+	case errors.Is(err, model.ErrNotFound):
+		h.respondError(w, r, http.StatusNotFound, "", "resource not found")
+	case errors.Is(err, model.ErrConflict):
+		h.respondError(w, r, http.StatusConflict, "uniqueness", err.Error())
+	case errors.Is(err, model.ErrInvalidInput):
+		h.respondError(w, r, http.StatusBadRequest, "invalidValue", err.Error())
+	default:
+		h.logger.ErrorContext(r.Context(), "unhandled scim error", "error", err, "path", r.URL.Path)
+		h.respondError(w, r, http.StatusInternalServerError, "", "internal server error")
+	}
+}
@@ -0,0 +1,389 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sikigasa/github-task-controller/backend/internal/domain/model"
+)
+
+// SCIM 2.0（RFC 7643/7644）のスキーマURN
+const (
+	scimSchemaUser         = "urn:ietf:params:scim:schemas:core:2.0:User"
+	scimSchemaGroup        = "urn:ietf:params:scim:schemas:core:2.0:Group"
+	scimSchemaListResponse = "urn:ietf:params:scim:api:messages:2.0:ListResponse"
+	scimSchemaPatchOp      = "urn:ietf:params:scim:api:messages:2.0:PatchOp"
+	scimSchemaError        = "urn:ietf:params:scim:api:messages:2.0:Error"
+	scimSchemaSPConfig     = "urn:ietf:params:scim:schemas:core:2.0:ServiceProviderConfig"
+	scimSchemaResourceType = "urn:ietf:params:scim:schemas:core:2.0:ResourceType"
+)
+
+// scimMeta はSCIMリソースのメタ情報
+type scimMeta struct {
+	ResourceType string    `json:"resourceType"`
+	Created      time.Time `json:"created"`
+	LastModified time.Time `json:"lastModified"`
+	Location     string    `json:"location"`
+}
+
+// scimName はSCIMユーザーの氏名
+type scimName struct {
+	Formatted  string `json:"formatted,omitempty"`
+	GivenName  string `json:"givenName,omitempty"`
+	FamilyName string `json:"familyName,omitempty"`
+}
+
+// scimEmail はSCIMユーザーのメールアドレス
+type scimEmail struct {
+	Value   string `json:"value"`
+	Type    string `json:"type,omitempty"`
+	Primary bool   `json:"primary,omitempty"`
+}
+
+// scimUser はSCIMのUserリソース
+type scimUser struct {
+	Schemas     []string    `json:"schemas"`
+	ID          string      `json:"id,omitempty"`
+	UserName    string      `json:"userName"`
+	Name        *scimName   `json:"name,omitempty"`
+	DisplayName string      `json:"displayName,omitempty"`
+	Emails      []scimEmail `json:"emails,omitempty"`
+	Active      *bool       `json:"active,omitempty"`
+	Meta        *scimMeta   `json:"meta,omitempty"`
+}
+
+// scimMember はSCIMグループのメンバー
+type scimMember struct {
+	Value string `json:"value"`
+	Ref   string `json:"$ref,omitempty"`
+}
+
+// scimGroup はSCIMのGroupリソース
+type scimGroup struct {
+	Schemas     []string     `json:"schemas"`
+	ID          string       `json:"id,omitempty"`
+	DisplayName string       `json:"displayName"`
+	ExternalID  string       `json:"externalId,omitempty"`
+	Members     []scimMember `json:"members"`
+	Meta        *scimMeta    `json:"meta,omitempty"`
+}
+
+// scimListResponse はSCIMの一覧レスポンス
+type scimListResponse struct {
+	Schemas      []string `json:"schemas"`
+	TotalResults int      `json:"totalResults"`
+	StartIndex   int      `json:"startIndex"`
+	ItemsPerPage int      `json:"itemsPerPage"`
+	Resources    any      `json:"Resources"`
+}
+
+// scimPatchRequest はSCIMのPATCHリクエスト
+type scimPatchRequest struct {
+	Schemas    []string `json:"schemas"`
+	Operations []struct {
+		Op    string          `json:"op"`
+		Path  string          `json:"path"`
+		Value json.RawMessage `json:"value"`
+	} `json:"Operations"`
+}
+
+// scimErrorResponse はSCIMのエラーレスポンス
+type scimErrorResponse struct {
+	Schemas  []string `json:"schemas"`
+	Status   string   `json:"status"`
+	ScimType string   `json:"scimType,omitempty"`
+	Detail   string   `json:"detail,omitempty"`
+}
+
+// newSCIMUser はユーザーをSCIMのUserリソースに変換する
+func newSCIMUser(user *model.User, baseURL string) scimUser {
+	active := user.Active()
+	return scimUser{
+		Schemas:     []string{scimSchemaUser},
+		ID:          user.ID,
+		UserName:    user.Email,
+		Name:        &scimName{Formatted: user.Name},
+		DisplayName: user.Name,
+		Emails:      []scimEmail{{Value: user.Email, Type: "work", Primary: true}},
+		Active:      &active,
+		Meta: &scimMeta{
+			ResourceType: "User",
+			Created:      user.CreatedAt,
+			LastModified: user.UpdatedAt,
+			Location:     baseURL + "/Users/" + user.ID,
+		},
+	}
+}
+
+// newSCIMGroup はグループをSCIMのGroupリソースに変換する
+func newSCIMGroup(group *model.Group, baseURL string) scimGroup {
+	members := make([]scimMember, 0, len(group.MemberIDs))
+	for _, id := range group.MemberIDs {
+		members = append(members, scimMember{Value: id, Ref: baseURL + "/Users/" + id})
+	}
+	return scimGroup{
+		Schemas:     []string{scimSchemaGroup},
+		ID:          group.ID,
+		DisplayName: group.DisplayName,
+		ExternalID:  group.ExternalID,
+		Members:     members,
+		Meta: &scimMeta{
+			ResourceType: "Group",
+			Created:      group.CreatedAt,
+			LastModified: group.UpdatedAt,
+			Location:     baseURL + "/Groups/" + group.ID,
+		},
+	}
+}
+
+// email はユーザー名（メールアドレス）を返す（ユーザー名がメールアドレスでない場合は主たるメールアドレス）
+func (u *scimUser) email() string {
+	if strings.Contains(u.UserName, "@") {
+		return u.UserName
+	}
+	for _, e := range u.Emails {
+		if e.Primary {
+			return e.Value
+		}
+	}
+	if len(u.Emails) > 0 {
+		return u.Emails[0].Value
+	}
+	return u.UserName
+}
+
+// displayName は表示名を返す（表示名がない場合は氏名から組み立てる）
+func (u *scimUser) displayName() string {
+	if u.DisplayName != "" {
+		return u.DisplayName
+	}
+	if u.Name == nil {
+		return ""
+	}
+	if u.Name.Formatted != "" {
+		return u.Name.Formatted
+	}
+	return strings.TrimSpace(u.Name.GivenName + " " + u.Name.FamilyName)
+}
+
+// scimFilterPattern は対応するフィルター（属性 eq "値"）の形式
+var scimFilterPattern = regexp.MustCompile(`(?i)^\s*([a-z][\w.]*)\s+eq\s+("(?:[^"\\]|\\.)*")\s*$`)
+
+// parseSCIMFilter は「属性 eq "値"」形式のフィルターを解析し、値を返す
+// 属性はattributeのみ受け付ける（大文字と小文字は区別しない）
+func parseSCIMFilter(filter, attribute string) (string, error) {
+	if filter == "" {
+		return "", nil
+	}
+	m := scimFilterPattern.FindStringSubmatch(filter)
+	if m == nil || !strings.EqualFold(m[1], attribute) {
+		return "", fmt.Errorf("unsupported filter: %s (only %s eq \"value\" is supported)", filter, attribute)
+	}
+	value, err := strconv.Unquote(m[2])
+	if err != nil {
+		return "", fmt.Errorf("invalid filter value: %w", err)
+	}
+	return value, nil
+}
+
+// scimBool はSCIMの真偽値を読み取る（"True"/"False"の文字列で送るIdPにも対応する）
+func scimBool(raw json.RawMessage) (bool, error) {
+	var b bool
+	if err := json.Unmarshal(raw, &b); err == nil {
+		return b, nil
+	}
+	var s string
+	if err := json.Unmarshal(raw, &s); err != nil {
+		return false, fmt.Errorf("invalid boolean: %s", raw)
+	}
+	return strconv.ParseBool(strings.ToLower(s))
+}
+
+// scimString はSCIMの文字列を読み取る
+func scimString(raw json.RawMessage) (string, error) {
+	var s string
+	if err := json.Unmarshal(raw, &s); err != nil {
+		return "", fmt.Errorf("invalid string: %s", raw)
+	}
+	return s, nil
+}
+
+// scimMemberIDs はメンバーの配列（[{"value": "..."}]）からユーザーIDを取り出す
+func scimMemberIDs(raw json.RawMessage) ([]string, error) {
+	if len(raw) == 0 || string(raw) == "null" {
+		return []string{}, nil
+	}
+	var members []scimMember
+	if err := json.Unmarshal(raw, &members); err != nil {
+		return nil, fmt.Errorf("invalid members: %w", err)
+	}
+	ids := make([]string, 0, len(members))
+	for _, m := range members {
+		ids = append(ids, m.Value)
+	}
+	return ids, nil
+}
+
+// scimUserPatch はユーザーへのPATCHを適用した結果
+type scimUserPatch struct {
+	email  string
+	name   string
+	active bool
+}
+
+// applySCIMUserPatch はユーザーにPATCHの操作を適用する
+// 対応していない属性（役職・部署など）への操作はIdPの同期を止めないよう無視する
+func applySCIMUserPatch(user *model.User, req *scimPatchRequest) (scimUserPatch, error) {
+	result := scimUserPatch{email: user.Email, active: user.Active()}
+	var name scimUser
+
+	apply := func(path string, value json.RawMessage) error {
+		var err error
+		switch strings.ToLower(path) {
+		case "active":
+			result.active, err = scimBool(value)
+		case "username":
+			result.email, err = scimString(value)
+		case "displayname":
+			name.DisplayName, err = scimString(value)
+		case "name.formatted":
+			name.ensureName().Formatted, err = scimString(value)
+		case "name.givenname":
+			name.ensureName().GivenName, err = scimString(value)
+		case "name.familyname":
+			name.ensureName().FamilyName, err = scimString(value)
+		case "name":
+			err = json.Unmarshal(value, name.ensureName())
+		}
+		return err
+	}
+
+	for _, op := range req.Operations {
+		switch strings.ToLower(op.Op) {
+		case "add", "replace":
+		case "remove":
+			continue
+		default:
+			return scimUserPatch{}, fmt.Errorf("unsupported patch op: %s", op.Op)
+		}
+
+		// パスを省略した場合は値のオブジェクトの各属性に適用する
+		if op.Path == "" {
+			var attrs map[string]json.RawMessage
+			if err := json.Unmarshal(op.Value, &attrs); err != nil {
+				return scimUserPatch{}, fmt.Errorf("invalid patch value: %w", err)
+			}
+			for path, value := range attrs {
+				if err := apply(path, value); err != nil {
+					return scimUserPatch{}, err
+				}
+			}
+			continue
+		}
+		if err := apply(op.Path, op.Value); err != nil {
+			return scimUserPatch{}, err
+		}
+	}
+
+	result.name = name.displayName()
+	return result, nil
+}
+
+// ensureName は氏名がなければ作成して返す
+func (u *scimUser) ensureName() *scimName {
+	if u.Name == nil {
+		u.Name = &scimName{}
+	}
+	return u.Name
+}
+
+// scimMemberFilterPattern はメンバーを指定するパス（members[value eq "..."]）の形式
+var scimMemberFilterPattern = regexp.MustCompile(`(?i)^members\[\s*value\s+eq\s+("(?:[^"\\]|\\.)*")\s*\]$`)
+
+// parseSCIMGroupPatch はグループへのPATCHの操作をドメインの部分更新に変換する
+func parseSCIMGroupPatch(req *scimPatchRequest) (model.GroupPatch, error) {
+	var patch model.GroupPatch
+
+	apply := func(op, path string, value json.RawMessage) error {
+		switch strings.ToLower(path) {
+		case "displayname":
+			s, err := scimString(value)
+			if err != nil {
+				return err
+			}
+			patch.DisplayName = &s
+		case "externalid":
+			s, err := scimString(value)
+			if err != nil {
+				return err
+			}
+			patch.ExternalID = &s
+		case "members":
+			ids, err := scimMemberIDs(value)
+			if err != nil {
+				return err
+			}
+			switch op {
+			case "add":
+				patch.AddMemberIDs = append(patch.AddMemberIDs, ids...)
+			case "replace":
+				patch.Members = &ids
+				patch.AddMemberIDs, patch.RemoveMemberIDs = nil, nil
+			}
+		}
+		return nil
+	}
+
+	for _, operation := range req.Operations {
+		op := strings.ToLower(operation.Op)
+		switch op {
+		case "add", "replace":
+			if operation.Path == "" {
+				var attrs map[string]json.RawMessage
+				if err := json.Unmarshal(operation.Value, &attrs); err != nil {
+					return model.GroupPatch{}, fmt.Errorf("invalid patch value: %w", err)
+				}
+				for path, value := range attrs {
+					if err := apply(op, path, value); err != nil {
+						return model.GroupPatch{}, err
+					}
+				}
+				continue
+			}
+			if err := apply(op, operation.Path, operation.Value); err != nil {
+				return model.GroupPatch{}, err
+			}
+		case "remove":
+			// members[value eq "id"] または members（値に削除するメンバーを指定、省略時は全員）
+			if m := scimMemberFilterPattern.FindStringSubmatch(operation.Path); m != nil {
+				id, err := strconv.Unquote(m[1])
+				if err != nil {
+					return model.GroupPatch{}, fmt.Errorf("invalid member filter: %w", err)
+				}
+				patch.RemoveMemberIDs = append(patch.RemoveMemberIDs, id)
+				continue
+			}
+			if !strings.EqualFold(operation.Path, "members") {
+				return model.GroupPatch{}, fmt.Errorf("unsupported remove path: %s", operation.Path)
+			}
+			ids, err := scimMemberIDs(operation.Value)
+			if err != nil {
+				return model.GroupPatch{}, err
+			}
+			if len(ids) == 0 {
+				empty := []string{}
+				patch.Members = &empty
+				patch.AddMemberIDs, patch.RemoveMemberIDs = nil, nil
+				continue
+			}
+			patch.RemoveMemberIDs = append(patch.RemoveMemberIDs, ids...)
+		default:
+			return model.GroupPatch{}, fmt.Errorf("unsupported patch op: %s", operation.Op)
+		}
+	}
+	return patch, nil
+}
@@ -0,0 +1,109 @@
+package handler
+
+import (
+	"encoding/json"
+	"slices"
+	"testing"
+
+	"github.com/sikigasa/github-task-controller/backend/internal/domain/model"
+)
+
+func decodeSCIMPatch(t *testing.T, body string) *scimPatchRequest {
+	t.Helper()
+	var req scimPatchRequest
+	if err := json.Unmarshal([]byte(body), &req); err != nil {
+		t.Fatalf("failed to decode patch request: %v", err)
+	}
+	return &req
+}
+
+func TestApplySCIMUserPatchDeactivate(t *testing.T) {
+	// IdPごとに異なる無効化のリクエストの形式
+	tests := map[string]string{
+		"path and boolean":    `{"Operations":[{"op":"replace","path":"active","value":false}]}`,
+		"value object":        `{"Operations":[{"op":"replace","value":{"active":false}}]}`,
+		"capitalized string":  `{"Operations":[{"op":"Replace","path":"active","value":"False"}]}`,
+		"add with value":      `{"Operations":[{"op":"Add","value":{"active":"false"}}]}`,
+		"with unknown fields": `{"Operations":[{"op":"replace","path":"title","value":"Engineer"},{"op":"replace","path":"active","value":false}]}`,
+	}
+	for name, body := range tests {
+		t.Run(name, func(t *testing.T) {
+			user := &model.User{ID: "user-1", Email: "user@example.com", Name: "User"}
+
+			result, err := applySCIMUserPatch(user, decodeSCIMPatch(t, body))
+			if err != nil {
+				t.Fatalf("applySCIMUserPatch() error = %v", err)
+			}
+			if result.active {
+				t.Error("active = true, want false")
+			}
+			if result.email != user.Email {
+				t.Errorf("email = %q, want %q", result.email, user.Email)
+			}
+		})
+	}
+}
+
+func TestApplySCIMUserPatchRejectsInvalidValue(t *testing.T) {
+	tests := map[string]string{
+		"unsupported op":  `{"Operations":[{"op":"move","path":"active","value":false}]}`,
+		"invalid boolean": `{"Operations":[{"op":"replace","path":"active","value":"no"}]}`,
+		"invalid value":   `{"Operations":[{"op":"replace","value":"active"}]}`,
+		"invalid email":   `{"Operations":[{"op":"replace","path":"userName","value":1}]}`,
+	}
+	for name, body := range tests {
+		t.Run(name, func(t *testing.T) {
+			user := &model.User{ID: "user-1", Email: "user@example.com"}
+			if _, err := applySCIMUserPatch(user, decodeSCIMPatch(t, body)); err == nil {
+				t.Error("applySCIMUserPatch() error = nil, want error")
+			}
+		})
+	}
+}
+
+func TestParseSCIMGroupPatchMembers(t *testing.T) {
+	body := `{"Operations":[
+		{"op":"add","path":"members","value":[{"value":"user-1"},{"value":"user-2"}]},
+		{"op":"remove","path":"members[value eq \"user-3\"]"},
+		{"op":"Remove","path":"members","value":[{"value":"user-4"}]}
+	]}`
+
+	patch, err := parseSCIMGroupPatch(decodeSCIMPatch(t, body))
+	if err != nil {
+		t.Fatalf("parseSCIMGroupPatch() error = %v", err)
+	}
+	if patch.Members != nil {
+		t.Errorf("Members = %v, want nil", *patch.Members)
+	}
+	if !slices.Equal(patch.AddMemberIDs, []string{"user-1", "user-2"}) {
+		t.Errorf("AddMemberIDs = %v, want [user-1 user-2]", patch.AddMemberIDs)
+	}
+	if !slices.Equal(patch.RemoveMemberIDs, []string{"user-3", "user-4"}) {
+		t.Errorf("RemoveMemberIDs = %v, want [user-3 user-4]", patch.RemoveMemberIDs)
+	}
+}
+
+func TestParseSCIMGroupPatchRemoveAllMembers(t *testing.T) {
+	patch, err := parseSCIMGroupPatch(decodeSCIMPatch(t, `{"Operations":[{"op":"remove","path":"members"}]}`))
+	if err != nil {
+		t.Fatalf("parseSCIMGroupPatch() error = %v", err)
+	}
+	if patch.Members == nil || len(*patch.Members) != 0 {
+		t.Errorf("Members = %v, want empty", patch.Members)
+	}
+}
+
+func TestParseSCIMGroupPatchRejectsUnsupportedPath(t *testing.T) {
+	tests := map[string]string{
+		"remove display name": `{"Operations":[{"op":"remove","path":"displayName"}]}`,
+		"unterminated filter": `{"Operations":[{"op":"remove","path":"members[value eq \"user-1]"}]}`,
+		"unsupported op":      `{"Operations":[{"op":"copy","path":"members"}]}`,
+	}
+	for name, body := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := parseSCIMGroupPatch(decodeSCIMPatch(t, body)); err == nil {
+				t.Error("parseSCIMGroupPatch() error = nil, want error")
+			}
+		})
+	}
+}
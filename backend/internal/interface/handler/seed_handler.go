@@ -0,0 +1,44 @@
+package handler
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/sikigasa/github-task-controller/backend/internal/application/usecase"
+	"github.com/sikigasa/github-task-controller/backend/internal/domain/model"
+	"github.com/sikigasa/github-task-controller/backend/internal/interface/middleware"
+)
+
+// SeedHandler は開発環境（APP_ENV=dev）のみ有効な開発用データの生成のHTTPハンドラー
+type SeedHandler struct {
+	usecase *usecase.SeedUsecase
+	logger  *slog.Logger
+}
+
+// NewSeedHandler は新しいSeedHandlerを作成する
+func NewSeedHandler(usecase *usecase.SeedUsecase, logger *slog.Logger) *SeedHandler {
+	return &SeedHandler{
+		usecase: usecase,
+		logger:  logger,
+	}
+}
+
+// Seed はログイン中のユーザーのプロジェクトとして開発用データを生成する（usersは無視する）
+func (h *SeedHandler) Seed(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID, _ := middleware.GetUserIDFromContext(ctx)
+
+	var opts model.SeedOptions
+	if !decodeAndValidate(w, r, h.logger, &opts) {
+		return
+	}
+	opts.UserID = userID
+
+	result, err := h.usecase.Seed(ctx, opts)
+	if err != nil {
+		respondDomainError(w, r, h.logger, err, "seed.failed")
+		return
+	}
+
+	respondJSON(w, h.logger, http.StatusCreated, result)
+}
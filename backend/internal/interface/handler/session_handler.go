@@ -0,0 +1,51 @@
+package handler
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/sikigasa/github-task-controller/backend/internal/application/usecase"
+	"github.com/sikigasa/github-task-controller/backend/internal/interface/middleware"
+)
+
+// SessionHandler はログインセッションのHTTPハンドラー
+type SessionHandler struct {
+	usecase *usecase.SessionUsecase
+	logger  *slog.Logger
+}
+
+// NewSessionHandler は新しいSessionHandlerを作成する
+func NewSessionHandler(usecase *usecase.SessionUsecase, logger *slog.Logger) *SessionHandler {
+	return &SessionHandler{
+		usecase: usecase,
+		logger:  logger,
+	}
+}
+
+// List はログイン中のセッションを端末情報・最終アクティビティ付きで一覧する
+func (h *SessionHandler) List(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID, _ := middleware.GetUserIDFromContext(ctx)
+	sessionID, _ := middleware.GetSessionIDFromContext(ctx)
+
+	sessions, err := h.usecase.ListSessions(ctx, userID, sessionID)
+	if err != nil {
+		respondDomainError(w, r, h.logger, err, "session.list_failed")
+		return
+	}
+
+	respondJSON(w, h.logger, http.StatusOK, sessions)
+}
+
+// Revoke は指定したセッションを失効させ、その端末をログアウトさせる
+func (h *SessionHandler) Revoke(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID, _ := middleware.GetUserIDFromContext(ctx)
+
+	if err := h.usecase.RevokeSession(ctx, userID, r.PathValue("id")); err != nil {
+		respondDomainError(w, r, h.logger, err, "session.revoke_failed")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,57 @@
+package handler
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/sikigasa/github-task-controller/backend/internal/application/usecase"
+	"github.com/sikigasa/github-task-controller/backend/internal/domain/model"
+	"github.com/sikigasa/github-task-controller/backend/internal/interface/middleware"
+)
+
+// SettingsHandler はユーザー（ワークスペース）設定のHTTPハンドラー
+type SettingsHandler struct {
+	usecase *usecase.SettingsUsecase
+	logger  *slog.Logger
+}
+
+// NewSettingsHandler は新しいSettingsHandlerを作成する
+func NewSettingsHandler(usecase *usecase.SettingsUsecase, logger *slog.Logger) *SettingsHandler {
+	return &SettingsHandler{
+		usecase: usecase,
+		logger:  logger,
+	}
+}
+
+// Get はログイン中のユーザーの設定を取得する
+func (h *SettingsHandler) Get(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID, _ := middleware.GetUserIDFromContext(ctx)
+
+	settings, err := h.usecase.GetSettings(ctx, userID)
+	if err != nil {
+		respondDomainError(w, r, h.logger, err, "settings.get_failed")
+		return
+	}
+
+	respondJSON(w, h.logger, http.StatusOK, settings)
+}
+
+// Update はログイン中のユーザーの設定を更新する
+func (h *SettingsHandler) Update(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID, _ := middleware.GetUserIDFromContext(ctx)
+
+	var req model.UpdateSettingsRequest
+	if !decodeAndValidate(w, r, h.logger, &req) {
+		return
+	}
+
+	settings, err := h.usecase.UpdateSettings(ctx, userID, &req)
+	if err != nil {
+		respondDomainError(w, r, h.logger, err, "settings.update_failed")
+		return
+	}
+
+	respondJSON(w, h.logger, http.StatusOK, settings)
+}
@@ -0,0 +1,83 @@
+package handler
+
+import (
+	"fmt"
+	"html/template"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/sikigasa/github-task-controller/backend/internal/application/usecase"
+	"github.com/sikigasa/github-task-controller/backend/internal/domain/model"
+)
+
+// statusPage はブラウザで/statusを開いた場合に表示するページ
+var statusPage = template.Must(template.New("status").Parse(`<!DOCTYPE html>
+<html lang="ja">
+<head>
+<meta charset="utf-8">
+<title>Status: {{.Status}}</title>
+<style>
+body { font-family: sans-serif; margin: 2rem; }
+table { border-collapse: collapse; }
+td, th { border: 1px solid #ccc; padding: 0.25rem 0.75rem; text-align: left; }
+.ok { color: #1a7f37; } .degraded, .starting { color: #9a6700; } .down, .failing, .stale { color: #cf222e; }
+</style>
+</head>
+<body>
+<h1 class="{{.Status}}">{{.Status}}</h1>
+<table>
+<tr><th>Version</th><td>{{.Version}}</td></tr>
+<tr><th>Started at</th><td>{{.StartedAt.Format "2006-01-02 15:04:05 MST"}}</td></tr>
+<tr><th>Uptime</th><td>{{.UptimeSeconds}}s</td></tr>
+<tr><th>Database</th><td class="{{.Database.Status}}">{{.Database.Status}} ({{printf "%.2f" .Database.LatencyMS}} ms)</td></tr>
+<tr><th>GitHub API</th><td>{{if .GithubAPI.LowestRemaining}}lowest remaining {{.GithubAPI.LowestRemaining}} points ({{.GithubAPI.TrackedTokens}} tokens){{else}}no recent usage{{end}}</td></tr>
+</table>
+<h2>Workers</h2>
+<table>
+<tr><th>Name</th><th>State</th><th>Last run</th></tr>
+{{range .Workers}}<tr><td>{{.Name}}</td><td class="{{.State}}">{{.State}}</td><td>{{if .LastRunAt}}{{.LastRunAt.Format "2006-01-02 15:04:05 MST"}}{{end}}</td></tr>
+{{end}}</table>
+<p>Checked at {{.CheckedAt.Format "2006-01-02 15:04:05 MST"}}</p>
+</body>
+</html>
+`))
+
+// StatusHandler は認証なしで公開するデプロイの稼働状況のHTTPハンドラー
+type StatusHandler struct {
+	usecase *usecase.StatusUsecase
+	logger  *slog.Logger
+}
+
+// NewStatusHandler は新しいStatusHandlerを作成する
+func NewStatusHandler(usecase *usecase.StatusUsecase, logger *slog.Logger) *StatusHandler {
+	return &StatusHandler{
+		usecase: usecase,
+		logger:  logger,
+	}
+}
+
+// Get は稼働状況を返す（AcceptにHTMLを含む場合はページ、それ以外はJSON）
+// データベースに接続できない場合は503を返す
+func (h *StatusHandler) Get(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	status := h.usecase.GetStatus(ctx)
+
+	code := http.StatusOK
+	if status.Status == model.ServiceDown {
+		code = http.StatusServiceUnavailable
+	}
+	w.Header().Set("Cache-Control", fmt.Sprintf("public, max-age=%d", int(h.usecase.CacheTTL().Seconds())))
+	w.Header().Add("Vary", "Accept")
+
+	if !strings.Contains(r.Header.Get("Accept"), "text/html") {
+		respondJSON(w, h.logger, code, status)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(code)
+	if err := statusPage.Execute(w, status); err != nil {
+		h.logger.ErrorContext(ctx, "failed to render status page", "error", err)
+	}
+}
@@ -1,13 +1,15 @@
 package handler
 
 import (
-	"encoding/json"
 	"log/slog"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/sikigasa/github-task-controller/backend/internal/application/usecase"
 	"github.com/sikigasa/github-task-controller/backend/internal/domain/model"
+	"github.com/sikigasa/github-task-controller/backend/internal/interface/i18n"
+	"github.com/sikigasa/github-task-controller/backend/internal/interface/middleware"
 )
 
 // TaskHandler はタスクのHTTPハンドラー
@@ -24,136 +26,353 @@ func NewTaskHandler(usecase *usecase.TaskUsecase, logger *slog.Logger) *TaskHand
 	}
 }
 
-// CreateTaskRequest はタスク作成リクエスト
-type CreateTaskRequest struct {
-	ProjectID   string     `json:"project_id"`
-	Title       string     `json:"title"`
-	Description string     `json:"description"`
-	Status      int        `json:"status"`
-	Priority    int        `json:"priority"`
-	EndDate     *time.Time `json:"end_date,omitempty"`
-}
-
-// UpdateTaskRequest はタスク更新リクエスト
-type UpdateTaskRequest struct {
-	Title       string     `json:"title"`
-	Description string     `json:"description"`
-	Status      int        `json:"status"`
-	Priority    int        `json:"priority"`
-	EndDate     *time.Time `json:"end_date,omitempty"`
+// taskListQuerySpec はタスク一覧で許可するソートキー・取得フィールド
+var taskListQuerySpec = listQuerySpec{
+	sortable: []string{"title", "status", "priority", "start_date", "end_date", "estimate", "milestone_id", "created_at", "updated_at"},
+	fields: []string{
+		"project_id", "title", "description", "status", "priority", "start_date", "end_date", "estimate", "milestone_id", "parent_task_id",
+		"github_item_id", "github_issue_number", "github_issue_url", "github_branch", "github_checks_status", "github_sync_state", "created_at", "updated_at",
+	},
 }
 
 // Create は新しいタスクを作成する
 func (h *TaskHandler) Create(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
+	userID, _ := middleware.GetUserIDFromContext(ctx)
 
-	var req CreateTaskRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		h.logger.ErrorContext(ctx, "failed to decode request", "error", err)
-		http.Error(w, "Invalid request body", http.StatusBadRequest)
+	var req model.CreateTaskRequest
+	if !decodeAndValidate(w, r, h.logger, &req) {
 		return
 	}
 
-	if req.ProjectID == "" || req.Title == "" {
-		http.Error(w, "project_id and title are required", http.StatusBadRequest)
+	task, err := h.usecase.CreateTask(ctx, userID, &req)
+	if err != nil {
+		respondDomainError(w, r, h.logger, err, "task.create_failed")
 		return
 	}
 
-	task, err := h.usecase.CreateTask(ctx, req.ProjectID, req.Title, req.Description, model.TaskStatus(req.Status), model.TaskPriority(req.Priority), req.EndDate)
+	respondJSON(w, h.logger, http.StatusCreated, task)
+}
+
+// Get はIDでタスクを取得する
+func (h *TaskHandler) Get(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID, _ := middleware.GetUserIDFromContext(ctx)
+	id := r.PathValue("id")
+
+	task, err := h.usecase.GetTaskDetail(ctx, userID, id)
 	if err != nil {
-		h.logger.ErrorContext(ctx, "failed to create task", "error", err)
-		http.Error(w, "Failed to create task", http.StatusInternalServerError)
+		respondDomainError(w, r, h.logger, err, "task.get_failed")
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusCreated)
-	if err := json.NewEncoder(w).Encode(task); err != nil {
-		h.logger.ErrorContext(ctx, "failed to encode response", "error", err)
+	respondJSON(w, h.logger, http.StatusOK, task)
+}
+
+// FindByGithub はGitHubのIssue（issue_urlまたはowner・repo・number）かProjectのItem（item_id）に対応するタスクを検索する
+func (h *TaskHandler) FindByGithub(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID, _ := middleware.GetUserIDFromContext(ctx)
+	query := r.URL.Query()
+
+	number, ok := parseIntQuery(w, r, h.logger, "number", 0)
+	if !ok {
+		return
 	}
+
+	tasks, err := h.usecase.FindTasksByGithub(ctx, userID, model.GithubTaskLookup{
+		IssueURL: query.Get("issue_url"),
+		Owner:    query.Get("owner"),
+		Repo:     query.Get("repo"),
+		Number:   number,
+		ItemID:   query.Get("item_id"),
+	})
+	if err != nil {
+		respondDomainError(w, r, h.logger, err, "task.list_failed")
+		return
+	}
+
+	respondJSON(w, h.logger, http.StatusOK, tasks)
 }
 
-// Get はIDでタスクを取得する
-func (h *TaskHandler) Get(w http.ResponseWriter, r *http.Request) {
+// ListStatusEvents はタスクのステータス遷移履歴を取得する
+func (h *TaskHandler) ListStatusEvents(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
+	userID, _ := middleware.GetUserIDFromContext(ctx)
 	id := r.PathValue("id")
 
-	task, err := h.usecase.GetTask(ctx, id)
+	events, err := h.usecase.ListStatusEvents(ctx, userID, id)
 	if err != nil {
-		h.logger.ErrorContext(ctx, "failed to get task", "error", err, "id", id)
-		http.Error(w, "Task not found", http.StatusNotFound)
+		respondDomainError(w, r, h.logger, err, "task.status_events_failed")
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	if err := json.NewEncoder(w).Encode(task); err != nil {
-		h.logger.ErrorContext(ctx, "failed to encode response", "error", err)
+	respondJSON(w, h.logger, http.StatusOK, events)
+}
+
+// ListSubtasks はタスクのサブタスクを作成順に取得する
+func (h *TaskHandler) ListSubtasks(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID, _ := middleware.GetUserIDFromContext(ctx)
+	id := r.PathValue("id")
+
+	subtasks, err := h.usecase.ListSubtasks(ctx, userID, id)
+	if err != nil {
+		respondDomainError(w, r, h.logger, err, "task.subtasks_failed")
+		return
 	}
+
+	respondJSON(w, h.logger, http.StatusOK, subtasks)
+}
+
+// AddDependency はタスクに依存関係を追加する
+func (h *TaskHandler) AddDependency(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID, _ := middleware.GetUserIDFromContext(ctx)
+	id := r.PathValue("id")
+
+	var req model.AddTaskDependencyRequest
+	if !decodeAndValidate(w, r, h.logger, &req) {
+		return
+	}
+
+	dep, err := h.usecase.AddDependency(ctx, userID, id, req.DependsOnTaskID)
+	if err != nil {
+		respondDomainError(w, r, h.logger, err, "task.dependency_add_failed")
+		return
+	}
+
+	respondJSON(w, h.logger, http.StatusCreated, dep)
+}
+
+// RemoveDependency はタスクの依存関係を削除する
+func (h *TaskHandler) RemoveDependency(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID, _ := middleware.GetUserIDFromContext(ctx)
+
+	if err := h.usecase.RemoveDependency(ctx, userID, r.PathValue("id"), r.PathValue("dependsOnId")); err != nil {
+		respondDomainError(w, r, h.logger, err, "task.dependency_remove_failed")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
 }
 
-// ListByProjectID はプロジェクトIDで全タスクを取得する
+// AddRelation はタスクに関連（relates_to・duplicates・duplicated_by）を追加する
+func (h *TaskHandler) AddRelation(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID, _ := middleware.GetUserIDFromContext(ctx)
+	id := r.PathValue("id")
+
+	var req model.AddTaskRelationRequest
+	if !decodeAndValidate(w, r, h.logger, &req) {
+		return
+	}
+
+	relation, err := h.usecase.AddRelation(ctx, userID, id, &req)
+	if err != nil {
+		respondDomainError(w, r, h.logger, err, "task.relation_add_failed")
+		return
+	}
+
+	respondJSON(w, h.logger, http.StatusCreated, relation)
+}
+
+// RemoveRelation はタスクの関連を削除する
+func (h *TaskHandler) RemoveRelation(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID, _ := middleware.GetUserIDFromContext(ctx)
+
+	relationType := model.TaskRelationType(r.PathValue("type"))
+	if err := h.usecase.RemoveRelation(ctx, userID, r.PathValue("id"), r.PathValue("relatedId"), relationType); err != nil {
+		respondDomainError(w, r, h.logger, err, "task.relation_remove_failed")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ListByProjectID はプロジェクトIDでタスクを取得する
+//
+//   - status・priority: 名前をカンマ区切りで指定し、いずれかに一致するタスクに絞り込む
+//   - milestone_id: マイルストーンのタスクに絞り込む
+//   - overdue: trueの場合は未完了かつ終了日を過ぎたタスクに絞り込む
+//   - due_before・due_after: 終了日がdue_beforeより前・due_after以降のタスクに絞り込む（RFC3339かYYYY-MM-DD）
+//   - q: タイトルか説明に含まれる文字列で絞り込む
+//   - limit・offset: 取得する最大件数（省略時はすべて）と読み飛ばす件数
+//   - sort・order・fields: parseListQueryを参照
 func (h *TaskHandler) ListByProjectID(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
+	userID, _ := middleware.GetUserIDFromContext(ctx)
 	projectID := r.URL.Query().Get("project_id")
 
 	if projectID == "" {
-		http.Error(w, "project_id is required", http.StatusBadRequest)
+		respondError(w, r, h.logger, http.StatusBadRequest, "Invalid Request", "request.project_id_required")
+		return
+	}
+
+	q, ok := parseListQuery(w, r, h.logger, taskListQuerySpec)
+	if !ok {
+		return
+	}
+	filter, ok := parseTaskFilterQuery(w, r, h.logger)
+	if !ok {
+		return
+	}
+	if q.options.Limit, ok = parseIntQuery(w, r, h.logger, "limit", 0); !ok {
+		return
+	}
+	if q.options.Offset, ok = parseIntQuery(w, r, h.logger, "offset", 0); !ok {
 		return
 	}
 
-	tasks, err := h.usecase.ListTasksByProjectID(ctx, projectID)
+	tasks, err := h.usecase.ListTasksByProjectID(ctx, userID, projectID, filter, q.options)
 	if err != nil {
-		h.logger.ErrorContext(ctx, "failed to list tasks", "error", err, "project_id", projectID)
-		http.Error(w, "Failed to list tasks", http.StatusInternalServerError)
+		respondDomainError(w, r, h.logger, err, "task.list_failed")
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	if err := json.NewEncoder(w).Encode(tasks); err != nil {
-		h.logger.ErrorContext(ctx, "failed to encode response", "error", err)
+	respondList(w, r, h.logger, tasks, q.fields)
+}
+
+// parseTaskFilterQuery はタスク一覧の絞り込み条件のクエリパラメータを解析する
+// 不正な値の場合はフィールド単位のエラーを含む400を書き込み、falseを返す
+func parseTaskFilterQuery(w http.ResponseWriter, r *http.Request, logger *slog.Logger) (model.TaskFilter, bool) {
+	ctx := r.Context()
+	query := r.URL.Query()
+	filter := model.TaskFilter{Query: strings.TrimSpace(query.Get("q"))}
+	var fieldErrors []FieldError
+
+	for _, name := range splitQueryList(query["status"]) {
+		status, err := model.ParseTaskStatus(name)
+		if err != nil {
+			fieldErrors = append(fieldErrors, FieldError{Field: "status", Message: i18n.T(ctx, "query.invalid_status", name)})
+			continue
+		}
+		filter.Statuses = append(filter.Statuses, status)
+	}
+	for _, name := range splitQueryList(query["priority"]) {
+		priority, err := model.ParseTaskPriority(name)
+		if err != nil {
+			fieldErrors = append(fieldErrors, FieldError{Field: "priority", Message: i18n.T(ctx, "query.invalid_priority", name)})
+			continue
+		}
+		filter.Priorities = append(filter.Priorities, priority)
+	}
+	if milestoneID := query.Get("milestone_id"); milestoneID != "" {
+		filter.MilestoneID = &milestoneID
+	}
+	for _, field := range []struct {
+		name string
+		dest **time.Time
+	}{{"due_before", &filter.DueBefore}, {"due_after", &filter.DueAfter}} {
+		value := query.Get(field.name)
+		if value == "" {
+			continue
+		}
+		t, ok := parseDateQuery(value)
+		if !ok {
+			fieldErrors = append(fieldErrors, FieldError{Field: field.name, Message: i18n.T(ctx, "query.invalid_date", value)})
+			continue
+		}
+		*field.dest = &t
+	}
+
+	if len(fieldErrors) > 0 {
+		respondProblem(w, r, logger, ProblemDetail{
+			Type:   "about:blank",
+			Title:  "Invalid Input",
+			Status: http.StatusBadRequest,
+			Detail: i18n.T(ctx, "error.invalid_input"),
+			Errors: fieldErrors,
+		})
+		return model.TaskFilter{}, false
+	}
+
+	overdue, ok := parseBoolQuery(w, r, logger, "overdue")
+	if !ok {
+		return model.TaskFilter{}, false
+	}
+	filter.Overdue = overdue
+	return filter, true
+}
+
+// parseDateQuery はRFC3339の日時かYYYY-MM-DDの日付（UTCの0時とする）を解析する
+func parseDateQuery(value string) (time.Time, bool) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, true
+	}
+	if t, err := time.Parse(time.DateOnly, value); err == nil {
+		return t, true
 	}
+	return time.Time{}, false
 }
 
 // Update はタスク情報を更新する
 func (h *TaskHandler) Update(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
+	userID, _ := middleware.GetUserIDFromContext(ctx)
 	id := r.PathValue("id")
 
-	var req UpdateTaskRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		h.logger.ErrorContext(ctx, "failed to decode request", "error", err)
-		http.Error(w, "Invalid request body", http.StatusBadRequest)
+	var req model.UpdateTaskRequest
+	if !decodeAndValidate(w, r, h.logger, &req) {
 		return
 	}
 
-	if req.Title == "" {
-		http.Error(w, "title is required", http.StatusBadRequest)
+	task, err := h.usecase.UpdateTask(ctx, userID, id, &req)
+	if err != nil {
+		respondDomainError(w, r, h.logger, err, "task.update_failed")
 		return
 	}
 
-	task, err := h.usecase.UpdateTask(ctx, id, req.Title, req.Description, model.TaskStatus(req.Status), model.TaskPriority(req.Priority), req.EndDate)
-	if err != nil {
-		h.logger.ErrorContext(ctx, "failed to update task", "error", err, "id", id)
-		http.Error(w, "Failed to update task", http.StatusInternalServerError)
+	respondJSON(w, h.logger, http.StatusOK, task)
+}
+
+// Patch はリクエストに含まれるフィールドのみタスクを更新する
+func (h *TaskHandler) Patch(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID, _ := middleware.GetUserIDFromContext(ctx)
+	id := r.PathValue("id")
+
+	var req model.PatchTaskRequest
+	if !decodeAndValidate(w, r, h.logger, &req) {
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	if err := json.NewEncoder(w).Encode(task); err != nil {
-		h.logger.ErrorContext(ctx, "failed to encode response", "error", err)
+	task, err := h.usecase.PatchTask(ctx, userID, id, &req)
+	if err != nil {
+		respondDomainError(w, r, h.logger, err, "task.update_failed")
+		return
 	}
+
+	respondJSON(w, h.logger, http.StatusOK, task)
 }
 
-// Delete はタスクを削除する
+// Delete はタスクをゴミ箱に移す
 func (h *TaskHandler) Delete(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
+	userID, _ := middleware.GetUserIDFromContext(ctx)
 	id := r.PathValue("id")
 
-	if err := h.usecase.DeleteTask(ctx, id); err != nil {
-		h.logger.ErrorContext(ctx, "failed to delete task", "error", err, "id", id)
-		http.Error(w, "Failed to delete task", http.StatusInternalServerError)
+	if err := h.usecase.DeleteTask(ctx, userID, id); err != nil {
+		respondDomainError(w, r, h.logger, err, "task.delete_failed")
 		return
 	}
 
 	w.WriteHeader(http.StatusNoContent)
 }
+
+// Restore はゴミ箱のタスクを元に戻す
+func (h *TaskHandler) Restore(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID, _ := middleware.GetUserIDFromContext(ctx)
+	id := r.PathValue("id")
+
+	task, err := h.usecase.RestoreTask(ctx, userID, id)
+	if err != nil {
+		respondDomainError(w, r, h.logger, err, "task.restore_failed")
+		return
+	}
+
+	respondJSON(w, h.logger, http.StatusOK, task)
+}
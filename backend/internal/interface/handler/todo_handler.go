@@ -1,13 +1,12 @@
 package handler
 
 import (
-	"encoding/json"
-	"errors"
 	"log/slog"
 	"net/http"
 
 	"github.com/sikigasa/github-task-controller/backend/internal/application/usecase"
 	"github.com/sikigasa/github-task-controller/backend/internal/domain/model"
+	"github.com/sikigasa/github-task-controller/backend/internal/interface/middleware"
 )
 
 // TodoHandler はTODOに関するHTTPリクエストを処理する
@@ -16,6 +15,12 @@ type TodoHandler struct {
 	logger  *slog.Logger
 }
 
+// todoListQuerySpec はTODO一覧で許可するソートキー・取得フィールド
+var todoListQuerySpec = listQuerySpec{
+	sortable: []string{"title", "completed", "created_at", "updated_at"},
+	fields:   []string{"user_id", "title", "description", "completed", "created_at", "updated_at"},
+}
+
 // NewTodoHandler は新しいTodoHandlerを作成する
 func NewTodoHandler(usecase *usecase.TodoUsecase, logger *slog.Logger) *TodoHandler {
 	return &TodoHandler{
@@ -24,177 +29,124 @@ func NewTodoHandler(usecase *usecase.TodoUsecase, logger *slog.Logger) *TodoHand
 	}
 }
 
-// ProblemDetail はRFC 9457に準拠したエラーレスポンス
-type ProblemDetail struct {
-	Type     string `json:"type"`
-	Title    string `json:"title"`
-	Status   int    `json:"status"`
-	Detail   string `json:"detail,omitempty"`
-	Instance string `json:"instance,omitempty"`
-}
-
-// respondJSON はJSON形式でレスポンスを返す
-func (h *TodoHandler) respondJSON(w http.ResponseWriter, status int, data any) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(status)
-	if err := json.NewEncoder(w).Encode(data); err != nil {
-		h.logger.Error("failed to encode response", "error", err)
-	}
-}
-
-// respondError はRFC 9457形式のエラーレスポンスを返す
-func (h *TodoHandler) respondError(w http.ResponseWriter, r *http.Request, status int, title string, detail string) {
-	problem := ProblemDetail{
-		Type:     "about:blank",
-		Title:    title,
-		Status:   status,
-		Detail:   detail,
-		Instance: r.URL.Path,
-	}
-
-	// ログレベルを適切に設定
-	switch {
-	case status >= 500:
-		h.logger.Error("server error", "status", status, "title", title, "detail", detail, "path", r.URL.Path)
-	case status == 401 || status == 403 || status == 409 || status == 429:
-		h.logger.Warn("client error requiring attention", "status", status, "title", title, "path", r.URL.Path)
-	default:
-		h.logger.Info("client error", "status", status, "title", title, "path", r.URL.Path)
-	}
-
-	h.respondJSON(w, status, problem)
-}
-
 // Create はTODOを作成する
 func (h *TodoHandler) Create(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
+	userID, _ := middleware.GetUserIDFromContext(ctx)
 
 	var req model.CreateTodoRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		h.respondError(w, r, http.StatusBadRequest, "Invalid Request", "リクエストボディが不正です")
-		return
-	}
-
-	// バリデーション
-	if req.Title == "" {
-		h.respondError(w, r, http.StatusBadRequest, "Invalid Input", "タイトルは必須です")
-		return
-	}
-	if len(req.Title) > 200 {
-		h.respondError(w, r, http.StatusBadRequest, "Invalid Input", "タイトルは200文字以内にしてください")
-		return
-	}
-	if len(req.Description) > 1000 {
-		h.respondError(w, r, http.StatusBadRequest, "Invalid Input", "説明は1000文字以内にしてください")
+	if !decodeAndValidate(w, r, h.logger, &req) {
 		return
 	}
 
-	todo, err := h.usecase.Create(ctx, &req)
+	todo, err := h.usecase.Create(ctx, userID, &req)
 	if err != nil {
-		h.respondError(w, r, http.StatusInternalServerError, "Internal Server Error", "TODOの作成に失敗しました")
+		respondDomainError(w, r, h.logger, err, "todo.create_failed")
 		return
 	}
 
-	h.respondJSON(w, http.StatusCreated, todo)
+	respondJSON(w, h.logger, http.StatusCreated, todo)
 }
 
 // Get はTODOを取得する
 func (h *TodoHandler) Get(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
+	userID, _ := middleware.GetUserIDFromContext(ctx)
 	id := r.PathValue("id")
 
 	if id == "" {
-		h.respondError(w, r, http.StatusBadRequest, "Invalid Request", "IDが指定されていません")
+		respondError(w, r, h.logger, http.StatusBadRequest, "Invalid Request", "request.id_required")
 		return
 	}
 
-	todo, err := h.usecase.GetByID(ctx, id)
+	todo, err := h.usecase.GetByID(ctx, userID, id)
 	if err != nil {
-		if errors.Is(err, model.ErrNotFound) {
-			h.respondError(w, r, http.StatusNotFound, "Not Found", "指定されたTODOが見つかりません")
-			return
-		}
-		h.respondError(w, r, http.StatusInternalServerError, "Internal Server Error", "TODOの取得に失敗しました")
+		respondDomainError(w, r, h.logger, err, "todo.get_failed")
 		return
 	}
 
-	h.respondJSON(w, http.StatusOK, todo)
+	respondJSON(w, h.logger, http.StatusOK, todo)
 }
 
-// List はすべてのTODOを取得する
+// List はセッションのユーザーのすべてのTODOを取得する
 func (h *TodoHandler) List(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
+	userID, _ := middleware.GetUserIDFromContext(ctx)
 
-	todos, err := h.usecase.GetAll(ctx)
+	q, ok := parseListQuery(w, r, h.logger, todoListQuerySpec)
+	if !ok {
+		return
+	}
+
+	todos, err := h.usecase.GetAll(ctx, userID, q.options)
 	if err != nil {
-		h.respondError(w, r, http.StatusInternalServerError, "Internal Server Error", "TODOリストの取得に失敗しました")
+		respondDomainError(w, r, h.logger, err, "todo.list_failed")
 		return
 	}
 
-	h.respondJSON(w, http.StatusOK, todos)
+	respondList(w, r, h.logger, todos, q.fields)
 }
 
 // Update はTODOを更新する
 func (h *TodoHandler) Update(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
+	userID, _ := middleware.GetUserIDFromContext(ctx)
 	id := r.PathValue("id")
 
 	if id == "" {
-		h.respondError(w, r, http.StatusBadRequest, "Invalid Request", "IDが指定されていません")
+		respondError(w, r, h.logger, http.StatusBadRequest, "Invalid Request", "request.id_required")
 		return
 	}
 
 	var req model.UpdateTodoRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		h.respondError(w, r, http.StatusBadRequest, "Invalid Request", "リクエストボディが不正です")
+	if !decodeAndValidate(w, r, h.logger, &req) {
 		return
 	}
 
-	// バリデーション
-	if req.Title != nil && *req.Title == "" {
-		h.respondError(w, r, http.StatusBadRequest, "Invalid Input", "タイトルは空にできません")
-		return
-	}
-	if req.Title != nil && len(*req.Title) > 200 {
-		h.respondError(w, r, http.StatusBadRequest, "Invalid Input", "タイトルは200文字以内にしてください")
+	todo, err := h.usecase.Update(ctx, userID, id, &req)
+	if err != nil {
+		respondDomainError(w, r, h.logger, err, "todo.update_failed")
 		return
 	}
-	if req.Description != nil && len(*req.Description) > 1000 {
-		h.respondError(w, r, http.StatusBadRequest, "Invalid Input", "説明は1000文字以内にしてください")
+
+	respondJSON(w, h.logger, http.StatusOK, todo)
+}
+
+// Delete はTODOを削除する
+func (h *TodoHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID, _ := middleware.GetUserIDFromContext(ctx)
+	id := r.PathValue("id")
+
+	if id == "" {
+		respondError(w, r, h.logger, http.StatusBadRequest, "Invalid Request", "request.id_required")
 		return
 	}
 
-	todo, err := h.usecase.Update(ctx, id, &req)
-	if err != nil {
-		if errors.Is(err, model.ErrNotFound) {
-			h.respondError(w, r, http.StatusNotFound, "Not Found", "指定されたTODOが見つかりません")
-			return
-		}
-		h.respondError(w, r, http.StatusInternalServerError, "Internal Server Error", "TODOの更新に失敗しました")
+	if err := h.usecase.Delete(ctx, userID, id); err != nil {
+		respondDomainError(w, r, h.logger, err, "todo.delete_failed")
 		return
 	}
 
-	h.respondJSON(w, http.StatusOK, todo)
+	w.WriteHeader(http.StatusNoContent)
 }
 
-// Delete はTODOを削除する
-func (h *TodoHandler) Delete(w http.ResponseWriter, r *http.Request) {
+// Restore はゴミ箱のTODOを元に戻す
+func (h *TodoHandler) Restore(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
+	userID, _ := middleware.GetUserIDFromContext(ctx)
 	id := r.PathValue("id")
 
 	if id == "" {
-		h.respondError(w, r, http.StatusBadRequest, "Invalid Request", "IDが指定されていません")
+		respondError(w, r, h.logger, http.StatusBadRequest, "Invalid Request", "request.id_required")
 		return
 	}
 
-	if err := h.usecase.Delete(ctx, id); err != nil {
-		if errors.Is(err, model.ErrNotFound) {
-			h.respondError(w, r, http.StatusNotFound, "Not Found", "指定されたTODOが見つかりません")
-			return
-		}
-		h.respondError(w, r, http.StatusInternalServerError, "Internal Server Error", "TODOの削除に失敗しました")
+	todo, err := h.usecase.Restore(ctx, userID, id)
+	if err != nil {
+		respondDomainError(w, r, h.logger, err, "todo.restore_failed")
 		return
 	}
 
-	w.WriteHeader(http.StatusNoContent)
+	respondJSON(w, h.logger, http.StatusOK, todo)
 }
@@ -0,0 +1,37 @@
+package handler
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/sikigasa/github-task-controller/backend/internal/application/usecase"
+	"github.com/sikigasa/github-task-controller/backend/internal/interface/middleware"
+)
+
+// TrashHandler はゴミ箱のHTTPハンドラー
+type TrashHandler struct {
+	usecase *usecase.TrashUsecase
+	logger  *slog.Logger
+}
+
+// NewTrashHandler は新しいTrashHandlerを作成する
+func NewTrashHandler(usecase *usecase.TrashUsecase, logger *slog.Logger) *TrashHandler {
+	return &TrashHandler{
+		usecase: usecase,
+		logger:  logger,
+	}
+}
+
+// List はゴミ箱のプロジェクト・タスク・TODOを取得する
+func (h *TrashHandler) List(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID, _ := middleware.GetUserIDFromContext(ctx)
+
+	trash, err := h.usecase.List(ctx, userID)
+	if err != nil {
+		respondDomainError(w, r, h.logger, err, "trash.list_failed")
+		return
+	}
+
+	respondJSON(w, h.logger, http.StatusOK, trash)
+}
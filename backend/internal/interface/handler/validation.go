@@ -0,0 +1,107 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/sikigasa/github-task-controller/backend/internal/interface/i18n"
+)
+
+// validate はstructタグ（validate:"..."）に基づくバリデーター
+var validate = newValidator()
+
+// newValidator はJSONタグ名でフィールドを報告するバリデーターを作成する
+func newValidator() *validator.Validate {
+	v := validator.New(validator.WithRequiredStructEnabled())
+	v.RegisterTagNameFunc(func(f reflect.StructField) string {
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name == "-" {
+			return ""
+		}
+		if name == "" {
+			return f.Name
+		}
+		return name
+	})
+	return v
+}
+
+// decodeAndValidate はリクエストボディをJSONデコードし、structタグでバリデーションする
+// 失敗した場合はRFC 9457形式のエラーレスポンスを書き込み、falseを返す
+func decodeAndValidate(w http.ResponseWriter, r *http.Request, logger *slog.Logger, dst any) bool {
+	if err := json.NewDecoder(r.Body).Decode(dst); err != nil {
+		logger.InfoContext(r.Context(), "failed to decode request", "error", err)
+		respondError(w, r, logger, http.StatusBadRequest, "Invalid Request", "request.invalid_body")
+		return false
+	}
+
+	return validateRequest(w, r, logger, dst)
+}
+
+// validateRequest はstructタグでバリデーションする
+// 失敗した場合はフィールド単位のエラーを含むレスポンスを書き込み、falseを返す
+func validateRequest(w http.ResponseWriter, r *http.Request, logger *slog.Logger, req any) bool {
+	err := validate.Struct(req)
+	if err == nil {
+		return true
+	}
+
+	var validationErrors validator.ValidationErrors
+	if !errors.As(err, &validationErrors) {
+		logger.ErrorContext(r.Context(), "failed to validate request", "error", err)
+		respondError(w, r, logger, http.StatusInternalServerError, "Internal Server Error", "request.validation_failed")
+		return false
+	}
+
+	fieldErrors := make([]FieldError, 0, len(validationErrors))
+	for _, fe := range validationErrors {
+		fieldErrors = append(fieldErrors, FieldError{
+			Field:   fe.Field(),
+			Message: validationMessage(r.Context(), fe),
+		})
+	}
+
+	respondProblem(w, r, logger, ProblemDetail{
+		Type:   "about:blank",
+		Title:  "Invalid Input",
+		Status: http.StatusBadRequest,
+		Detail: i18n.T(r.Context(), "error.invalid_input"),
+		Errors: fieldErrors,
+	})
+	return false
+}
+
+// validationMessage はバリデーションエラーをリクエストの言語で利用者向けのメッセージに変換する
+func validationMessage(ctx context.Context, fe validator.FieldError) string {
+	isString := fe.Kind() == reflect.String
+	switch fe.Tag() {
+	case "required":
+		return i18n.T(ctx, "validation.required")
+	case "min":
+		if isString {
+			return i18n.T(ctx, "validation.min_length", fe.Param())
+		}
+		return i18n.T(ctx, "validation.min", fe.Param())
+	case "max":
+		if isString {
+			return i18n.T(ctx, "validation.max_length", fe.Param())
+		}
+		return i18n.T(ctx, "validation.max", fe.Param())
+	case "oneof":
+		return i18n.T(ctx, "validation.oneof", fe.Param())
+	case "uuid":
+		return i18n.T(ctx, "validation.uuid")
+	case "url", "http_url":
+		return i18n.T(ctx, "validation.url")
+	case "email":
+		return i18n.T(ctx, "validation.email")
+	default:
+		return i18n.T(ctx, "validation.invalid", fe.Tag())
+	}
+}
@@ -0,0 +1,72 @@
+package handler
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/sikigasa/github-task-controller/backend/internal/application/usecase"
+	"github.com/sikigasa/github-task-controller/backend/internal/domain/model"
+)
+
+// WebhookDeliveryHandler は管理者向けのWebhookの配信の確認・再処理のHTTPハンドラー
+type WebhookDeliveryHandler struct {
+	usecase *usecase.WebhookUsecase
+	logger  *slog.Logger
+}
+
+// NewWebhookDeliveryHandler は新しいWebhookDeliveryHandlerを作成する
+func NewWebhookDeliveryHandler(usecase *usecase.WebhookUsecase, logger *slog.Logger) *WebhookDeliveryHandler {
+	return &WebhookDeliveryHandler{
+		usecase: usecase,
+		logger:  logger,
+	}
+}
+
+// List は配信を処理状況（?status=、省略時はdead）で絞り込んで一覧する
+func (h *WebhookDeliveryHandler) List(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	status := model.WebhookDeliveryDead
+	if s := r.URL.Query().Get("status"); s != "" {
+		parsed, err := model.ParseWebhookDeliveryStatus(s)
+		if err != nil {
+			respondDomainError(w, r, h.logger, err, "webhook.list_failed")
+			return
+		}
+		status = parsed
+	}
+
+	deliveries, err := h.usecase.ListDeliveries(ctx, status)
+	if err != nil {
+		respondDomainError(w, r, h.logger, err, "webhook.list_failed")
+		return
+	}
+
+	respondJSON(w, h.logger, http.StatusOK, deliveries)
+}
+
+// Get は配信をペイロードと最後のエラー付きで取得する
+func (h *WebhookDeliveryHandler) Get(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	delivery, err := h.usecase.GetDelivery(ctx, r.PathValue("id"))
+	if err != nil {
+		respondDomainError(w, r, h.logger, err, "webhook.get_failed")
+		return
+	}
+
+	respondJSON(w, h.logger, http.StatusOK, delivery)
+}
+
+// Reprocess はデッドレターの配信を処理待ちに戻す
+func (h *WebhookDeliveryHandler) Reprocess(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	delivery, err := h.usecase.Reprocess(ctx, r.PathValue("id"))
+	if err != nil {
+		respondDomainError(w, r, h.logger, err, "webhook.reprocess_failed")
+		return
+	}
+
+	respondJSON(w, h.logger, http.StatusAccepted, delivery)
+}
@@ -0,0 +1,103 @@
+package handler
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/sikigasa/github-task-controller/backend/internal/application/usecase"
+	"github.com/sikigasa/github-task-controller/backend/internal/domain/model"
+	"github.com/sikigasa/github-task-controller/backend/internal/interface/middleware"
+)
+
+// WebhookEndpointHandler はプロジェクトのイベントを通知する外部のWebhookの送信先のHTTPハンドラー
+type WebhookEndpointHandler struct {
+	usecase *usecase.WebhookEndpointUsecase
+	logger  *slog.Logger
+}
+
+// NewWebhookEndpointHandler は新しいWebhookEndpointHandlerを作成する
+func NewWebhookEndpointHandler(usecase *usecase.WebhookEndpointUsecase, logger *slog.Logger) *WebhookEndpointHandler {
+	return &WebhookEndpointHandler{
+		usecase: usecase,
+		logger:  logger,
+	}
+}
+
+// Create はプロジェクトにWebhookの送信先を登録する（署名のsecretはこのレスポンスでのみ返す）
+func (h *WebhookEndpointHandler) Create(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID, _ := middleware.GetUserIDFromContext(ctx)
+
+	var req model.CreateWebhookEndpointRequest
+	if !decodeAndValidate(w, r, h.logger, &req) {
+		return
+	}
+
+	endpoint, err := h.usecase.CreateEndpoint(ctx, userID, r.PathValue("id"), &req)
+	if err != nil {
+		respondDomainError(w, r, h.logger, err, "webhook_endpoint.create_failed")
+		return
+	}
+
+	respondJSON(w, h.logger, http.StatusCreated, endpoint)
+}
+
+// List はプロジェクトのWebhookの送信先を一覧する
+func (h *WebhookEndpointHandler) List(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID, _ := middleware.GetUserIDFromContext(ctx)
+
+	endpoints, err := h.usecase.ListEndpoints(ctx, userID, r.PathValue("id"))
+	if err != nil {
+		respondDomainError(w, r, h.logger, err, "webhook_endpoint.list_failed")
+		return
+	}
+
+	respondJSON(w, h.logger, http.StatusOK, endpoints)
+}
+
+// Patch はWebhookの送信先を部分更新する（enabledをtrueにすると自動で無効になった送信先を再開する）
+func (h *WebhookEndpointHandler) Patch(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID, _ := middleware.GetUserIDFromContext(ctx)
+
+	var req model.PatchWebhookEndpointRequest
+	if !decodeAndValidate(w, r, h.logger, &req) {
+		return
+	}
+
+	endpoint, err := h.usecase.PatchEndpoint(ctx, userID, r.PathValue("id"), r.PathValue("webhookId"), &req)
+	if err != nil {
+		respondDomainError(w, r, h.logger, err, "webhook_endpoint.update_failed")
+		return
+	}
+
+	respondJSON(w, h.logger, http.StatusOK, endpoint)
+}
+
+// Delete はWebhookの送信先を削除する
+func (h *WebhookEndpointHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID, _ := middleware.GetUserIDFromContext(ctx)
+
+	if err := h.usecase.DeleteEndpoint(ctx, userID, r.PathValue("id"), r.PathValue("webhookId")); err != nil {
+		respondDomainError(w, r, h.logger, err, "webhook_endpoint.delete_failed")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ListDeliveries はWebhookの送信先の配信ログを新しい順に一覧する
+func (h *WebhookEndpointHandler) ListDeliveries(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID, _ := middleware.GetUserIDFromContext(ctx)
+
+	deliveries, err := h.usecase.ListDeliveries(ctx, userID, r.PathValue("id"), r.PathValue("webhookId"))
+	if err != nil {
+		respondDomainError(w, r, h.logger, err, "webhook_endpoint.deliveries_failed")
+		return
+	}
+
+	respondJSON(w, h.logger, http.StatusOK, deliveries)
+}
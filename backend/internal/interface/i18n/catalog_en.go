@@ -0,0 +1,187 @@
+package i18n
+
+// catalogEn は英語のメッセージカタログ
+var catalogEn = map[string]string{
+	"error.not_found":         "The requested resource was not found",
+	"error.unauthorized":      "Authentication is required",
+	"error.forbidden":         "You do not have permission to access this resource",
+	"error.invalid_input":     "The input contains errors",
+	"error.conflict":          "The request conflicts with the current state of the resource",
+	"error.stale_version":     "The resource was updated by another request. Apply your changes to the current version and retry",
+	"error.unexpected":        "An unexpected error occurred",
+	"error.too_many_requests": "Too many requests. Please try again later",
+
+	"request.invalid_body":        "The request body is invalid",
+	"request.validation_failed":   "Failed to validate the request",
+	"request.id_required":         "No ID was specified",
+	"request.project_id_required": "project_id is required",
+
+	"query.invalid_sort":       "%s cannot be used for sorting (allowed: %s)",
+	"query.invalid_order":      "%s is not a valid order (use asc or desc)",
+	"query.order_without_sort": "order has more entries than sort",
+	"query.duplicate_sort":     "%s is specified more than once in sort",
+	"query.invalid_field":      "%s cannot be selected (allowed: %s)",
+	"query.invalid_expand":     "%s cannot be expanded (allowed: %s)",
+	"query.invalid_integer":    "%s is not an integer",
+	"query.invalid_boolean":    "%s is not a boolean (use true or false)",
+	"query.repeated":           "%s must be specified only once",
+	"query.invalid_status":     "%s is not a valid status (use todo, in_progress or done)",
+	"query.invalid_priority":   "%s is not a valid priority (use low, medium or high)",
+	"query.invalid_date":       "%s is not a valid date (use RFC3339 or YYYY-MM-DD)",
+
+	"validation.required":   "is required",
+	"validation.min_length": "must be at least %s characters",
+	"validation.min":        "must be at least %s",
+	"validation.max_length": "must be at most %s characters",
+	"validation.max":        "must be at most %s",
+	"validation.oneof":      "must be one of: %s",
+	"validation.uuid":       "must be a valid UUID",
+	"validation.url":        "must be a valid URL",
+	"validation.email":      "must be a valid email address",
+	"validation.invalid":    "is invalid (%s)",
+
+	"user.get_failed": "Failed to get user information",
+
+	"todo.list_failed":    "Failed to get the todo list",
+	"todo.get_failed":     "Failed to get the todo",
+	"todo.create_failed":  "Failed to create the todo",
+	"todo.update_failed":  "Failed to update the todo",
+	"todo.delete_failed":  "Failed to delete the todo",
+	"todo.restore_failed": "Failed to restore the todo",
+
+	"project.list_failed":           "Failed to get the project list",
+	"project.get_failed":            "Failed to get the project",
+	"project.create_failed":         "Failed to create the project",
+	"project.update_failed":         "Failed to update the project",
+	"project.delete_failed":         "Failed to delete the project",
+	"project.restore_failed":        "Failed to restore the project",
+	"project.delete_has_tasks":      "Specify force=true to delete a project that has tasks (see delete-preview for the affected tasks)",
+	"project.delete_preview_failed": "Failed to get the project delete preview",
+	"project.github_cleanup_failed": "Failed to delete the GitHub Projects items",
+	"project.link_failed":           "Failed to link the project",
+	"project.unlink_failed":         "Failed to unlink the project",
+	"project.timeline_failed":       "Failed to get the timeline",
+	"project.events_failed":         "Failed to start streaming project events",
+
+	"task.list_failed":              "Failed to get the task list",
+	"task.get_failed":               "Failed to get the task",
+	"task.create_failed":            "Failed to create the task",
+	"task.update_failed":            "Failed to update the task",
+	"task.delete_failed":            "Failed to delete the task",
+	"task.restore_failed":           "Failed to restore the task",
+	"task.sync_failed":              "Failed to sync the task",
+	"task.status_events_failed":     "Failed to get the status history",
+	"task.subtasks_failed":          "Failed to get the subtasks",
+	"task.dependency_add_failed":    "Failed to add the dependency",
+	"task.dependency_remove_failed": "Failed to remove the dependency",
+	"task.relation_add_failed":      "Failed to add the relation",
+	"task.relation_remove_failed":   "Failed to remove the relation",
+
+	"milestone.list_failed":   "Failed to get the milestone list",
+	"milestone.get_failed":    "Failed to get the milestone",
+	"milestone.create_failed": "Failed to create the milestone",
+	"milestone.update_failed": "Failed to update the milestone",
+	"milestone.delete_failed": "Failed to delete the milestone",
+
+	"view.list_failed":   "Failed to get the view list",
+	"view.get_failed":    "Failed to get the view",
+	"view.create_failed": "Failed to save the view",
+	"view.delete_failed": "Failed to delete the view",
+	"view.tasks_failed":  "Failed to get the tasks for the view",
+
+	"project_token.create_failed": "Failed to issue the API token",
+	"project_token.list_failed":   "Failed to get the API token list",
+	"project_token.delete_failed": "Failed to revoke the API token",
+
+	"webhook_endpoint.create_failed":     "Failed to register the webhook endpoint",
+	"webhook_endpoint.list_failed":       "Failed to get the webhook endpoint list",
+	"webhook_endpoint.update_failed":     "Failed to update the webhook endpoint",
+	"webhook_endpoint.delete_failed":     "Failed to delete the webhook endpoint",
+	"webhook_endpoint.deliveries_failed": "Failed to get the webhook delivery log",
+
+	"goal.list_failed":        "Failed to get the goal list",
+	"goal.get_failed":         "Failed to get the goal",
+	"goal.create_failed":      "Failed to create the goal",
+	"goal.update_failed":      "Failed to update the goal",
+	"goal.delete_failed":      "Failed to delete the goal",
+	"goal.task_add_failed":    "Failed to add the contributing task",
+	"goal.task_remove_failed": "Failed to remove the contributing task",
+
+	"settings.get_failed":    "Failed to get the settings",
+	"settings.update_failed": "Failed to update the settings",
+
+	"push.register_failed": "Failed to register the device",
+	"push.list_failed":     "Failed to get the device list",
+	"push.delete_failed":   "Failed to unregister the device",
+
+	"dashboard.get_failed": "Failed to get the dashboard",
+
+	"trash.list_failed":    "Failed to get the trash",
+	"activity.list_failed": "Failed to get the activity",
+
+	"auth.refresh_failed":            "Failed to refresh the access token",
+	"auth.project_token_not_allowed": "This endpoint cannot be used with a project API token",
+	"auth.project_token_read_only":   "A read-only API token cannot make changes",
+	"auth.project_token_forbidden":   "A project API token can only access its own project",
+
+	"session.list_failed":       "Failed to list login sessions",
+	"session.revoke_failed":     "Failed to revoke the login session",
+	"session.logout_all_failed": "Failed to log out of all devices",
+
+	"account_merge.list_failed":    "Failed to list account merges",
+	"account_merge.confirm_failed": "Failed to merge the accounts",
+
+	"invitation.create_failed": "Failed to create the invitation",
+	"invitation.list_failed":   "Failed to list invitations",
+	"invitation.delete_failed": "Failed to revoke the invitation",
+
+	"report.velocity_failed":       "Failed to aggregate the velocity",
+	"report.cfd_failed":            "Failed to aggregate the cumulative flow",
+	"report.overdue_failed":        "Failed to aggregate the overdue tasks",
+	"report.stats_failed":          "Failed to aggregate the statistics",
+	"report.time_in_status_failed": "Failed to aggregate the time in status",
+	"report.flow_time_failed":      "Failed to aggregate the lead and cycle time",
+
+	"export.create_failed":   "Failed to accept the export",
+	"export.get_failed":      "Failed to get the export",
+	"export.download_failed": "Failed to download the export",
+
+	"github.status_failed":               "Failed to get the GitHub connection status",
+	"github.diagnostics_failed":          "Failed to diagnose the GitHub connection",
+	"github.pending_operations_failed":   "Failed to get pending GitHub operations",
+	"github.sync_usage_failed":           "Failed to get GitHub sync usage",
+	"github.sync_quota_exceeded":         "You have reached the hourly limit of GitHub syncs. Please try again after the limit resets",
+	"github.reauth_required.oauth":       "Your GitHub token is no longer valid. Please sign in with GitHub again",
+	"github.reauth_required.pat":         "Your GitHub personal access token is no longer valid. Please register a new one",
+	"github.rate_limited":                "The GitHub API rate limit has been reached. Please try again later",
+	"github.projects_failed":             "Failed to get GitHub Projects",
+	"github.project_items_failed":        "Failed to get GitHub Project items",
+	"github.project_import_failed":       "Failed to import GitHub Project items",
+	"github.pat_save_failed":             "Failed to save the personal access token",
+	"github.pat_delete_failed":           "Failed to delete the personal access token",
+	"github.milestone_sync_failed":       "Failed to sync the milestone",
+	"github.field_mapping_get_failed":    "Failed to get field mapping",
+	"github.field_mapping_update_failed": "Failed to update field mapping",
+	"github.field_mapping_delete_failed": "Failed to delete field mapping",
+	"github.issue_import_failed":         "Failed to import GitHub issues",
+	"github.branch_create_failed":        "Failed to create the working branch",
+	"github.commits_failed":              "Failed to get referencing commits",
+	"github.commits_sync_failed":         "Failed to sync referencing commits",
+	"github.pull_requests_failed":        "Failed to get pull requests",
+	"github.pull_requests_sync_failed":   "Failed to sync pull requests",
+	"github.project_sync_failed":         "Failed to sync with the GitHub Project",
+
+	"webhook.list_failed":      "Failed to list webhook deliveries",
+	"webhook.get_failed":       "Failed to get webhook delivery",
+	"webhook.reprocess_failed": "Failed to reprocess webhook delivery",
+	"webhook.receive_failed":   "Failed to receive the webhook",
+
+	"backup.list_failed":     "Failed to list backups",
+	"backup.create_failed":   "Failed to create backup",
+	"backup.upload_failed":   "Failed to upload backup",
+	"backup.download_failed": "Failed to download backup",
+	"backup.restore_failed":  "Failed to restore from backup",
+	"backup.delete_failed":   "Failed to delete backup",
+
+	"seed.failed": "Failed to generate seed data",
+}
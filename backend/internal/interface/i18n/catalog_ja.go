@@ -0,0 +1,187 @@
+package i18n
+
+// catalogJa は日本語のメッセージカタログ
+var catalogJa = map[string]string{
+	"error.not_found":         "指定されたリソースが見つかりません",
+	"error.unauthorized":      "認証が必要です",
+	"error.forbidden":         "このリソースへのアクセス権限がありません",
+	"error.invalid_input":     "入力内容に誤りがあります",
+	"error.conflict":          "リソースの現在の状態と競合しています",
+	"error.stale_version":     "他の更新でリソースが変更されています。現在の内容に変更を反映してやり直してください",
+	"error.unexpected":        "予期しないエラーが発生しました",
+	"error.too_many_requests": "リクエストが多すぎます。しばらくしてから再度お試しください",
+
+	"request.invalid_body":        "リクエストボディが不正です",
+	"request.validation_failed":   "リクエストの検証に失敗しました",
+	"request.id_required":         "IDが指定されていません",
+	"request.project_id_required": "project_idは必須です",
+
+	"query.invalid_sort":       "%s はソートに使用できません（使用可能: %s）",
+	"query.invalid_order":      "%s は不正な並び順です（asc または desc を指定してください）",
+	"query.order_without_sort": "order の指定数が sort を超えています",
+	"query.duplicate_sort":     "%s がソートに複数回指定されています",
+	"query.invalid_field":      "%s は取得できないフィールドです（使用可能: %s）",
+	"query.invalid_expand":     "%s は展開できません（使用可能: %s）",
+	"query.invalid_integer":    "%s は整数ではありません",
+	"query.invalid_boolean":    "%s は真偽値ではありません（true または false を指定してください）",
+	"query.repeated":           "%s は1回だけ指定してください",
+	"query.invalid_status":     "%s は不正なステータスです（todo・in_progress・done を指定してください）",
+	"query.invalid_priority":   "%s は不正な優先度です（low・medium・high を指定してください）",
+	"query.invalid_date":       "%s は不正な日付です（RFC3339 または YYYY-MM-DD で指定してください）",
+
+	"validation.required":   "必須です",
+	"validation.min_length": "%s文字以上にしてください",
+	"validation.min":        "%s以上にしてください",
+	"validation.max_length": "%s文字以内にしてください",
+	"validation.max":        "%s以下にしてください",
+	"validation.oneof":      "%s のいずれかを指定してください",
+	"validation.uuid":       "UUID形式で指定してください",
+	"validation.url":        "URL形式で指定してください",
+	"validation.email":      "メールアドレスの形式で指定してください",
+	"validation.invalid":    "不正な値です（%s）",
+
+	"user.get_failed": "ユーザー情報の取得に失敗しました",
+
+	"todo.list_failed":    "TODOリストの取得に失敗しました",
+	"todo.get_failed":     "TODOの取得に失敗しました",
+	"todo.create_failed":  "TODOの作成に失敗しました",
+	"todo.update_failed":  "TODOの更新に失敗しました",
+	"todo.delete_failed":  "TODOの削除に失敗しました",
+	"todo.restore_failed": "TODOの復元に失敗しました",
+
+	"project.list_failed":           "プロジェクト一覧の取得に失敗しました",
+	"project.get_failed":            "プロジェクトの取得に失敗しました",
+	"project.create_failed":         "プロジェクトの作成に失敗しました",
+	"project.update_failed":         "プロジェクトの更新に失敗しました",
+	"project.delete_failed":         "プロジェクトの削除に失敗しました",
+	"project.restore_failed":        "プロジェクトの復元に失敗しました",
+	"project.delete_has_tasks":      "タスクのあるプロジェクトを削除するには force=true を指定してください（削除されるタスクは delete-preview で確認できます）",
+	"project.delete_preview_failed": "プロジェクトの削除のプレビューの取得に失敗しました",
+	"project.github_cleanup_failed": "GitHub ProjectsのItemの削除に失敗しました",
+	"project.link_failed":           "プロジェクトの連携に失敗しました",
+	"project.unlink_failed":         "プロジェクトの連携解除に失敗しました",
+	"project.timeline_failed":       "タイムラインの取得に失敗しました",
+	"project.events_failed":         "プロジェクトの変更の配信を開始できませんでした",
+
+	"task.list_failed":              "タスク一覧の取得に失敗しました",
+	"task.get_failed":               "タスクの取得に失敗しました",
+	"task.create_failed":            "タスクの作成に失敗しました",
+	"task.update_failed":            "タスクの更新に失敗しました",
+	"task.delete_failed":            "タスクの削除に失敗しました",
+	"task.restore_failed":           "タスクの復元に失敗しました",
+	"task.sync_failed":              "タスクの同期に失敗しました",
+	"task.status_events_failed":     "ステータス履歴の取得に失敗しました",
+	"task.subtasks_failed":          "サブタスクの取得に失敗しました",
+	"task.dependency_add_failed":    "依存関係の追加に失敗しました",
+	"task.dependency_remove_failed": "依存関係の削除に失敗しました",
+	"task.relation_add_failed":      "関連の追加に失敗しました",
+	"task.relation_remove_failed":   "関連の削除に失敗しました",
+
+	"milestone.list_failed":   "マイルストーン一覧の取得に失敗しました",
+	"milestone.get_failed":    "マイルストーンの取得に失敗しました",
+	"milestone.create_failed": "マイルストーンの作成に失敗しました",
+	"milestone.update_failed": "マイルストーンの更新に失敗しました",
+	"milestone.delete_failed": "マイルストーンの削除に失敗しました",
+
+	"view.list_failed":   "ビュー一覧の取得に失敗しました",
+	"view.get_failed":    "ビューの取得に失敗しました",
+	"view.create_failed": "ビューの保存に失敗しました",
+	"view.delete_failed": "ビューの削除に失敗しました",
+	"view.tasks_failed":  "ビューのタスク取得に失敗しました",
+
+	"project_token.create_failed": "APIトークンの発行に失敗しました",
+	"project_token.list_failed":   "APIトークン一覧の取得に失敗しました",
+	"project_token.delete_failed": "APIトークンの失効に失敗しました",
+
+	"webhook_endpoint.create_failed":     "Webhookの送信先の登録に失敗しました",
+	"webhook_endpoint.list_failed":       "Webhookの送信先一覧の取得に失敗しました",
+	"webhook_endpoint.update_failed":     "Webhookの送信先の更新に失敗しました",
+	"webhook_endpoint.delete_failed":     "Webhookの送信先の削除に失敗しました",
+	"webhook_endpoint.deliveries_failed": "Webhookの配信ログの取得に失敗しました",
+
+	"goal.list_failed":        "目標一覧の取得に失敗しました",
+	"goal.get_failed":         "目標の取得に失敗しました",
+	"goal.create_failed":      "目標の作成に失敗しました",
+	"goal.update_failed":      "目標の更新に失敗しました",
+	"goal.delete_failed":      "目標の削除に失敗しました",
+	"goal.task_add_failed":    "貢献タスクの追加に失敗しました",
+	"goal.task_remove_failed": "貢献タスクの解除に失敗しました",
+
+	"settings.get_failed":    "設定の取得に失敗しました",
+	"settings.update_failed": "設定の更新に失敗しました",
+
+	"push.register_failed": "端末の登録に失敗しました",
+	"push.list_failed":     "端末一覧の取得に失敗しました",
+	"push.delete_failed":   "端末の登録の削除に失敗しました",
+
+	"dashboard.get_failed": "ダッシュボードの取得に失敗しました",
+
+	"trash.list_failed":    "ゴミ箱の取得に失敗しました",
+	"activity.list_failed": "アクティビティの取得に失敗しました",
+
+	"auth.refresh_failed":            "アクセストークンの再発行に失敗しました",
+	"auth.project_token_not_allowed": "このエンドポイントはプロジェクトのAPIトークンでは利用できません",
+	"auth.project_token_read_only":   "読み取り専用のAPIトークンでは変更できません",
+	"auth.project_token_forbidden":   "APIトークンの対象のプロジェクト以外は操作できません",
+
+	"session.list_failed":       "ログインセッションの取得に失敗しました",
+	"session.revoke_failed":     "ログインセッションの削除に失敗しました",
+	"session.logout_all_failed": "すべての端末からのログアウトに失敗しました",
+
+	"account_merge.list_failed":    "アカウント統合の履歴の取得に失敗しました",
+	"account_merge.confirm_failed": "アカウントの統合に失敗しました",
+
+	"invitation.create_failed": "招待の作成に失敗しました",
+	"invitation.list_failed":   "招待一覧の取得に失敗しました",
+	"invitation.delete_failed": "招待の取り消しに失敗しました",
+
+	"report.velocity_failed":       "ベロシティの集計に失敗しました",
+	"report.cfd_failed":            "累積フロー図の集計に失敗しました",
+	"report.overdue_failed":        "期限切れタスクの集計に失敗しました",
+	"report.stats_failed":          "統計の集計に失敗しました",
+	"report.time_in_status_failed": "滞留時間の集計に失敗しました",
+	"report.flow_time_failed":      "リードタイム・サイクルタイムの集計に失敗しました",
+
+	"export.create_failed":   "エクスポートの受付に失敗しました",
+	"export.get_failed":      "エクスポートの取得に失敗しました",
+	"export.download_failed": "エクスポートのダウンロードに失敗しました",
+
+	"github.status_failed":               "GitHub連携状態の取得に失敗しました",
+	"github.diagnostics_failed":          "GitHub連携の診断に失敗しました",
+	"github.pending_operations_failed":   "保留中のGitHubへの変更の取得に失敗しました",
+	"github.sync_usage_failed":           "GitHubとの同期の利用状況の取得に失敗しました",
+	"github.sync_quota_exceeded":         "1時間あたりのGitHubとの同期の上限に達しました。上限が戻った後に再度お試しください",
+	"github.reauth_required.oauth":       "GitHubのトークンが無効になりました。GitHubでログインし直してください",
+	"github.reauth_required.pat":         "GitHubのPATが無効になりました。新しいPATを登録し直してください",
+	"github.rate_limited":                "GitHub APIのレート制限に達しました。しばらくしてから再度お試しください",
+	"github.projects_failed":             "GitHub Projectsの取得に失敗しました",
+	"github.project_items_failed":        "GitHub ProjectのItemsの取得に失敗しました",
+	"github.project_import_failed":       "GitHub ProjectのItemsの取り込みに失敗しました",
+	"github.pat_save_failed":             "PATの保存に失敗しました",
+	"github.pat_delete_failed":           "PATの削除に失敗しました",
+	"github.milestone_sync_failed":       "マイルストーンの同期に失敗しました",
+	"github.field_mapping_get_failed":    "フィールドの対応付けの取得に失敗しました",
+	"github.field_mapping_update_failed": "フィールドの対応付けの設定に失敗しました",
+	"github.field_mapping_delete_failed": "フィールドの対応付けの削除に失敗しました",
+	"github.issue_import_failed":         "GitHub Issueの取り込みに失敗しました",
+	"github.branch_create_failed":        "作業ブランチの作成に失敗しました",
+	"github.commits_failed":              "参照コミットの取得に失敗しました",
+	"github.commits_sync_failed":         "参照コミットの同期に失敗しました",
+	"github.pull_requests_failed":        "Pull Requestの取得に失敗しました",
+	"github.pull_requests_sync_failed":   "Pull Requestの同期に失敗しました",
+	"github.project_sync_failed":         "GitHub Projectとの同期に失敗しました",
+
+	"webhook.list_failed":      "Webhookの配信一覧の取得に失敗しました",
+	"webhook.get_failed":       "Webhookの配信の取得に失敗しました",
+	"webhook.reprocess_failed": "Webhookの配信の再処理に失敗しました",
+	"webhook.receive_failed":   "Webhookの受信に失敗しました",
+
+	"backup.list_failed":     "バックアップ一覧の取得に失敗しました",
+	"backup.create_failed":   "バックアップの作成に失敗しました",
+	"backup.upload_failed":   "バックアップのアップロードに失敗しました",
+	"backup.download_failed": "バックアップのダウンロードに失敗しました",
+	"backup.restore_failed":  "バックアップからの復元に失敗しました",
+	"backup.delete_failed":   "バックアップの削除に失敗しました",
+
+	"seed.failed": "開発用データの生成に失敗しました",
+}
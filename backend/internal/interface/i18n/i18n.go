@@ -0,0 +1,116 @@
+// Package i18n はエラーメッセージ等の利用者向け文言の多言語化を提供する
+package i18n
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Lang は言語タグ（ISO 639-1）を表す
+type Lang string
+
+const (
+	Japanese Lang = "ja"
+	English  Lang = "en"
+
+	// Default は対応言語が判定できない場合に使用する言語
+	Default = Japanese
+)
+
+// catalogs は言語ごとのメッセージカタログ
+var catalogs = map[Lang]map[string]string{
+	Japanese: catalogJa,
+	English:  catalogEn,
+}
+
+// Supported は対応している言語かどうかを返す
+func Supported(lang Lang) bool {
+	_, ok := catalogs[lang]
+	return ok
+}
+
+// Parse は言語タグ（例: "en-US"）を対応言語に変換する
+func Parse(tag string) (Lang, bool) {
+	base, _, _ := strings.Cut(strings.TrimSpace(tag), "-")
+	lang := Lang(strings.ToLower(base))
+	if !Supported(lang) {
+		return "", false
+	}
+	return lang, true
+}
+
+// Negotiate はAccept-Languageヘッダーから最も優先度の高い対応言語を選択する
+// 対応言語が含まれない場合はDefaultを返す
+func Negotiate(acceptLanguage string) Lang {
+	type candidate struct {
+		lang Lang
+		q    float64
+	}
+
+	var candidates []candidate
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		tag, params, _ := strings.Cut(part, ";")
+		q := 1.0
+		if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				continue
+			}
+			q = parsed
+		}
+		if q <= 0 {
+			continue
+		}
+		if lang, ok := Parse(tag); ok {
+			candidates = append(candidates, candidate{lang: lang, q: q})
+		}
+	}
+
+	if len(candidates) == 0 {
+		return Default
+	}
+	// 同じ優先度の場合はヘッダーに記載された順を維持する
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].q > candidates[j].q
+	})
+	return candidates[0].lang
+}
+
+type contextKey struct{}
+
+// WithLang は言語をコンテキストに設定する
+func WithLang(ctx context.Context, lang Lang) context.Context {
+	return context.WithValue(ctx, contextKey{}, lang)
+}
+
+// FromContext はコンテキストから言語を取得する（未設定の場合はDefault）
+func FromContext(ctx context.Context) Lang {
+	if lang, ok := ctx.Value(contextKey{}).(Lang); ok {
+		return lang
+	}
+	return Default
+}
+
+// Message は指定言語でメッセージを返す
+// 指定言語にメッセージがない場合はDefaultの言語、それもない場合はキーをそのまま返す
+func Message(lang Lang, key string, args ...any) string {
+	format, ok := catalogs[lang][key]
+	if !ok {
+		format, ok = catalogs[Default][key]
+	}
+	if !ok {
+		format = key
+	}
+	if len(args) == 0 {
+		return format
+	}
+	return fmt.Sprintf(format, args...)
+}
+
+// T はコンテキストの言語でメッセージを返す
+func T(ctx context.Context, key string, args ...any) string {
+	return Message(FromContext(ctx), key, args...)
+}
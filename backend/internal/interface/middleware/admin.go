@@ -0,0 +1,37 @@
+package middleware
+
+import (
+	"log/slog"
+	"net/http"
+)
+
+// AdminMiddleware は運用者向けのエンドポイントを設定で指定した管理者ユーザーに限定する
+type AdminMiddleware struct {
+	userIDs map[string]struct{}
+	logger  *slog.Logger
+}
+
+// NewAdminMiddleware は新しいAdminMiddlewareを作成する
+func NewAdminMiddleware(userIDs []string, logger *slog.Logger) *AdminMiddleware {
+	ids := make(map[string]struct{}, len(userIDs))
+	for _, id := range userIDs {
+		ids[id] = struct{}{}
+	}
+	return &AdminMiddleware{
+		userIDs: ids,
+		logger:  logger,
+	}
+}
+
+// RequireAdmin は認証済みのユーザーが管理者の場合のみ次のハンドラーを実行する（RequireAuthの内側で使用する）
+func (m *AdminMiddleware) RequireAdmin(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		userID, _ := GetUserIDFromContext(r.Context())
+		if _, ok := m.userIDs[userID]; !ok || userID == "" {
+			m.logger.WarnContext(r.Context(), "admin access denied", "user_id", userID, "path", r.URL.Path)
+			WriteProblem(w, r, m.logger, http.StatusForbidden, "Forbidden", "error.forbidden")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"log/slog"
 	"net/http"
+	"strings"
 
 	"github.com/sikigasa/github-task-controller/backend/internal/infrastructure/session"
 )
@@ -16,33 +17,116 @@ const (
 	UserIDKey ContextKey = "user_id"
 	// SessionKey はコンテキストからセッション情報を取得するためのキー
 	SessionKey ContextKey = "session"
+	// SessionIDKey はコンテキストからログインセッションの記録IDを取得するためのキー
+	SessionIDKey ContextKey = "session_id"
 )
 
 const (
 	sessionName         = "auth-session"
 	sessionKeyUserID    = "user_id"
 	sessionKeyExpiresAt = "expires_at"
+	sessionKeySessionID = "session_id"
 )
 
+// SessionActivityRecorder はログインセッションの最終アクティビティを記録する
+type SessionActivityRecorder interface {
+	RecordActivity(ctx context.Context, sessionID string)
+}
+
+// AccessTokenVerifier はAuthorizationヘッダーのアクセストークンを検証してユーザーIDとセッションの記録IDを返す
+type AccessTokenVerifier interface {
+	VerifyAccessToken(accessToken string) (string, string, error)
+}
+
+// ProjectTokenVerifier はプロジェクト単位のAPIトークンを検証する
+type ProjectTokenVerifier interface {
+	// IsProjectToken はトークンがプロジェクト単位のAPIトークンの形式かを返す
+	IsProjectToken(token string) bool
+	// VerifyProjectToken はトークンを検証し、発行したユーザー・対象のプロジェクト・書き込みを許可するかを返す
+	VerifyProjectToken(ctx context.Context, token string) (userID, projectID string, write bool, err error)
+}
+
+// ProjectResolver はリクエストが操作するプロジェクトのIDを返す
+// プロジェクト単位のAPIトークンで認証したリクエストが、トークンの対象のプロジェクトだけを操作することの確認に使う
+type ProjectResolver func(r *http.Request) (projectID string, err error)
+
+// TenantScoper は認証したユーザーをデータベースの行レベルセキュリティのテナントとして設定する
+type TenantScoper interface {
+	Scope(ctx context.Context, userID string) context.Context
+}
+
 // AuthMiddleware は認証ミドルウェア
 type AuthMiddleware struct {
-	sessionStore *session.CookieStore
-	logger       *slog.Logger
+	sessionStore  session.Store
+	tokens        AccessTokenVerifier
+	projectTokens ProjectTokenVerifier
+	recorder      SessionActivityRecorder
+	tenancy       TenantScoper
+	logger        *slog.Logger
 }
 
 // NewAuthMiddleware は新しいAuthMiddlewareを作成する
-func NewAuthMiddleware(sessionStore *session.CookieStore, logger *slog.Logger) *AuthMiddleware {
+// tokensを指定した場合はCookieのセッションの代わりにアクセストークン（Authorization: Bearer）で認証する
+// projectTokensはプロジェクト単位のAPIトークンを検証する（RequireAuthOrProjectTokenのエンドポイントでのみ受け付ける）
+// tenancyは行レベルセキュリティを使わない場合はnil
+func NewAuthMiddleware(sessionStore session.Store, tokens AccessTokenVerifier, projectTokens ProjectTokenVerifier, recorder SessionActivityRecorder, tenancy TenantScoper, logger *slog.Logger) *AuthMiddleware {
 	return &AuthMiddleware{
-		sessionStore: sessionStore,
-		logger:       logger,
+		sessionStore:  sessionStore,
+		tokens:        tokens,
+		projectTokens: projectTokens,
+		recorder:      recorder,
+		tenancy:       tenancy,
+		logger:        logger,
 	}
 }
 
 // RequireAuth は認証が必要なエンドポイント用のミドルウェア
+// プロジェクト単位のAPIトークンは受け付けない（403）
 func (m *AuthMiddleware) RequireAuth(next http.Handler) http.Handler {
+	return m.requireAuth(next, m.tenancy, nil)
+}
+
+// RequireAuthOrProjectToken は認証が必要で、プロジェクト単位のAPIトークンも受け付けるエンドポイント用のミドルウェア
+// APIトークンの場合はresolveで求めたリクエストの対象のプロジェクトがトークンの対象と一致する場合のみ許可し、
+// 書き込み（GET・HEAD以外）はwriteのトークンのみ許可する
+func (m *AuthMiddleware) RequireAuthOrProjectToken(resolve ProjectResolver, next http.Handler) http.Handler {
+	return m.requireAuth(next, m.tenancy, resolve)
+}
+
+// RequireAuthStream はWebSocket等の長時間の接続を受け付ける、認証が必要なエンドポイント用のミドルウェア
+// プロジェクト単位のAPIトークンは受け付けないため、ハンドラーで参照するリソースの所有者を確認すること
+// （テナントはクエリごとのトランザクションで設定するため、接続の間データベースの接続を占有しない）
+func (m *AuthMiddleware) RequireAuthStream(next http.Handler) http.Handler {
+	return m.requireAuth(next, m.tenancy, nil)
+}
+
+// requireAuth は認証を確認し、tenancyがnilでない場合はユーザーをテナントとして設定してから次のハンドラーを実行する
+// resolveがnilの場合はプロジェクト単位のAPIトークンを受け付けない
+func (m *AuthMiddleware) requireAuth(next http.Handler, tenancy TenantScoper, resolve ProjectResolver) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		ctx := r.Context()
 
+		// プロジェクト単位のAPIトークンで認証する場合（ログインのアクセストークンより先に判定する）
+		if token, ok := m.projectBearer(r); ok {
+			m.serveProjectToken(w, r, next, tenancy, resolve, token)
+			return
+		}
+
+		// アクセストークンで認証する場合
+		if m.tokens != nil {
+			userID, sessionID, ok := m.verifyBearer(r)
+			if !ok {
+				m.logger.InfoContext(ctx, "invalid or missing access token")
+				http.Error(w, "Unauthorized", http.StatusUnauthorized)
+				return
+			}
+			ctx = context.WithValue(ctx, UserIDKey, userID)
+			ctx = context.WithValue(ctx, SessionIDKey, sessionID)
+			m.recorder.RecordActivity(ctx, sessionID)
+			m.serveAs(w, r.WithContext(ctx), next, tenancy, userID)
+			return
+		}
+
 		// セッションからユーザー情報を取得
 		sess, err := m.sessionStore.Get(r, sessionName)
 		if err != nil {
@@ -61,7 +145,7 @@ func (m *AuthMiddleware) RequireAuth(next http.Handler) http.Handler {
 		// セッション有効期限を確認
 		if sess.IsExpired(sessionKeyExpiresAt) {
 			m.logger.InfoContext(ctx, "session expired", "user_id", userID)
-			m.sessionStore.Delete(w, sessionName)
+			m.sessionStore.Delete(w, r, sessionName)
 			http.Error(w, "Unauthorized", http.StatusUnauthorized)
 			return
 		}
@@ -70,10 +154,16 @@ func (m *AuthMiddleware) RequireAuth(next http.Handler) http.Handler {
 		ctx = context.WithValue(ctx, UserIDKey, userID)
 		ctx = context.WithValue(ctx, SessionKey, sess.Values)
 
+		// ログインセッションの最終アクティビティを記録（記録導入前のセッションにはIDがない）
+		if sessionID, ok := sess.GetString(sessionKeySessionID); ok && sessionID != "" {
+			ctx = context.WithValue(ctx, SessionIDKey, sessionID)
+			m.recorder.RecordActivity(ctx, sessionID)
+		}
+
 		m.logger.InfoContext(ctx, "user authenticated", "user_id", userID)
 
 		// 次のハンドラーを実行
-		next.ServeHTTP(w, r.WithContext(ctx))
+		m.serveAs(w, r.WithContext(ctx), next, tenancy, userID)
 	})
 }
 
@@ -83,6 +173,18 @@ func (m *AuthMiddleware) OptionalAuth(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		ctx := r.Context()
 
+		// アクセストークンで認証する場合（トークンが不正でも続行）
+		if m.tokens != nil {
+			if userID, sessionID, ok := m.verifyBearer(r); ok {
+				ctx = context.WithValue(ctx, UserIDKey, userID)
+				ctx = context.WithValue(ctx, SessionIDKey, sessionID)
+				m.serveAs(w, r.WithContext(ctx), next, m.tenancy, userID)
+				return
+			}
+			next.ServeHTTP(w, r.WithContext(ctx))
+			return
+		}
+
 		// セッションからユーザー情報を取得
 		sess, err := m.sessionStore.Get(r, sessionName)
 		if err != nil {
@@ -110,12 +212,89 @@ func (m *AuthMiddleware) OptionalAuth(next http.Handler) http.Handler {
 		ctx = context.WithValue(ctx, SessionKey, sess.Values)
 
 		// 次のハンドラーを実行
-		next.ServeHTTP(w, r.WithContext(ctx))
+		m.serveAs(w, r.WithContext(ctx), next, m.tenancy, userID)
 	})
 }
 
+// serveAs は行レベルセキュリティを使う場合はユーザーをテナントとして設定してから次のハンドラーを実行する
+func (m *AuthMiddleware) serveAs(w http.ResponseWriter, r *http.Request, next http.Handler, tenancy TenantScoper, userID string) {
+	if tenancy == nil {
+		next.ServeHTTP(w, r)
+		return
+	}
+
+	next.ServeHTTP(w, r.WithContext(tenancy.Scope(r.Context(), userID)))
+}
+
+// serveProjectToken はプロジェクト単位のAPIトークンを検証し、トークンの対象のプロジェクトへのリクエストの場合のみ次のハンドラーを実行する
+func (m *AuthMiddleware) serveProjectToken(w http.ResponseWriter, r *http.Request, next http.Handler, tenancy TenantScoper, resolve ProjectResolver, token string) {
+	ctx := r.Context()
+	if resolve == nil {
+		m.logger.InfoContext(ctx, "project token is not allowed for this endpoint", "path", r.URL.Path)
+		WriteProblem(w, r, m.logger, http.StatusForbidden, "Forbidden", "auth.project_token_not_allowed")
+		return
+	}
+
+	userID, projectID, write, err := m.projectTokens.VerifyProjectToken(ctx, token)
+	if err != nil {
+		m.logger.InfoContext(ctx, "invalid project token", "error", err)
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	if !write && r.Method != http.MethodGet && r.Method != http.MethodHead {
+		m.logger.InfoContext(ctx, "read-only project token used for write", "user_id", userID, "project_id", projectID, "method", r.Method)
+		WriteProblem(w, r, m.logger, http.StatusForbidden, "Forbidden", "auth.project_token_read_only")
+		return
+	}
+
+	// 対象のプロジェクトの確認はテナントを設定してから行う（タスク等からプロジェクトを求める場合に行レベルセキュリティを適用する）
+	scoped := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		target, err := resolve(r)
+		if err != nil || target != projectID {
+			m.logger.InfoContext(r.Context(), "project token used outside its project", "error", err, "user_id", userID, "project_id", projectID, "path", r.URL.Path)
+			WriteProblem(w, r, m.logger, http.StatusForbidden, "Forbidden", "auth.project_token_forbidden")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+
+	m.logger.InfoContext(ctx, "user authenticated by project token", "user_id", userID, "project_id", projectID)
+	m.serveAs(w, r.WithContext(context.WithValue(ctx, UserIDKey, userID)), scoped, tenancy, userID)
+}
+
+// projectBearer はAuthorizationヘッダーのBearerトークンがプロジェクト単位のAPIトークンの場合にそのトークンを返す
+func (m *AuthMiddleware) projectBearer(r *http.Request) (string, bool) {
+	if m.projectTokens == nil {
+		return "", false
+	}
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok || !m.projectTokens.IsProjectToken(token) {
+		return "", false
+	}
+	return token, true
+}
+
+// verifyBearer はAuthorizationヘッダーのBearerトークンを検証する
+func (m *AuthMiddleware) verifyBearer(r *http.Request) (string, string, bool) {
+	accessToken, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok || accessToken == "" {
+		return "", "", false
+	}
+	userID, sessionID, err := m.tokens.VerifyAccessToken(accessToken)
+	if err != nil {
+		return "", "", false
+	}
+	return userID, sessionID, true
+}
+
 // GetUserIDFromContext はコンテキストからユーザーIDを取得する
 func GetUserIDFromContext(ctx context.Context) (string, bool) {
 	userID, ok := ctx.Value(UserIDKey).(string)
 	return userID, ok
 }
+
+// GetSessionIDFromContext はコンテキストからログインセッションの記録IDを取得する
+func GetSessionIDFromContext(ctx context.Context) (string, bool) {
+	sessionID, ok := ctx.Value(SessionIDKey).(string)
+	return sessionID, ok
+}
@@ -0,0 +1,116 @@
+package middleware
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/sikigasa/github-task-controller/backend/internal/infrastructure/captcha"
+)
+
+// captchaTokenParam はCAPTCHAのトークンを受け取るパラメータ名（クエリまたはフォーム）
+const captchaTokenParam = "captcha_token"
+
+// ChallengeMiddleware はログイン開始エンドポイントへの不審なアクセスにCAPTCHAを要求するミドルウェア
+// クライアントIPごとの試行回数がウィンドウ内でしきい値を超えた場合、CAPTCHAを通過するまでOAuthのリダイレクトを行わない
+type ChallengeMiddleware struct {
+	verifier    *captcha.Verifier
+	threshold   int
+	window      time.Duration
+	frontendURL string
+	mu          sync.Mutex
+	clients     map[string]*rateLimitEntry
+	logger      *slog.Logger
+}
+
+// NewChallengeMiddleware は新しいChallengeMiddlewareを作成する
+// thresholdはウィンドウ内でCAPTCHAなしに許可するログイン開始の回数
+func NewChallengeMiddleware(verifier *captcha.Verifier, threshold int, window time.Duration, frontendURL string, logger *slog.Logger) *ChallengeMiddleware {
+	return &ChallengeMiddleware{
+		verifier:    verifier,
+		threshold:   threshold,
+		window:      window,
+		frontendURL: frontendURL,
+		clients:     make(map[string]*rateLimitEntry),
+		logger:      logger,
+	}
+}
+
+// Require はしきい値を超えたクライアントにCAPTCHAのトークンを要求するミドルウェア
+// トークンがない・無効な場合はフロントエンドのログインページにサイトキー付きでリダイレクトする
+func (m *ChallengeMiddleware) Require(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		key := ClientIP(r)
+
+		if !m.tripped(key, time.Now()) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		token := r.FormValue(captchaTokenParam)
+		if token == "" {
+			m.logger.WarnContext(ctx, "captcha required", "client_ip", key, "path", r.URL.Path)
+			m.redirect(w, r, "captcha_required")
+			return
+		}
+		if err := m.verifier.Verify(ctx, token, key); err != nil {
+			if errors.Is(err, captcha.ErrVerificationFailed) {
+				m.logger.WarnContext(ctx, "captcha verification failed", "client_ip", key, "error", err)
+			} else {
+				m.logger.ErrorContext(ctx, "failed to verify captcha", "client_ip", key, "error", err)
+			}
+			m.redirect(w, r, "captcha_failed")
+			return
+		}
+
+		// CAPTCHAを通過したクライアントは試行回数を数え直す
+		m.reset(key)
+		next.ServeHTTP(w, r)
+	})
+}
+
+// tripped は試行を記録し、ウィンドウ内の試行回数がしきい値を超えたかどうかを返す
+func (m *ChallengeMiddleware) tripped(key string, now time.Time) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	entry, ok := m.clients[key]
+	if !ok || now.After(entry.resetAt) {
+		// 期限切れエントリが溜まらないよう、新規ウィンドウ開始時に掃除する
+		if len(m.clients) > 10000 {
+			for k, e := range m.clients {
+				if now.After(e.resetAt) {
+					delete(m.clients, k)
+				}
+			}
+		}
+		m.clients[key] = &rateLimitEntry{count: 1, resetAt: now.Add(m.window)}
+		return m.threshold < 1
+	}
+
+	entry.count++
+	return entry.count > m.threshold
+}
+
+// reset はクライアントの試行回数を破棄する
+func (m *ChallengeMiddleware) reset(key string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.clients, key)
+}
+
+// redirect はCAPTCHAの表示に必要な情報を付けてフロントエンドのログインページにリダイレクトする
+func (m *ChallengeMiddleware) redirect(w http.ResponseWriter, r *http.Request, code string) {
+	query := url.Values{
+		"error":            {code},
+		"captcha_provider": {string(m.verifier.Provider())},
+		"captcha_site_key": {m.verifier.SiteKey()},
+		// フロントエンドはCAPTCHA通過後にこのパスへcaptcha_tokenを付けて再度アクセスする
+		"retry": {r.URL.Path},
+	}
+	http.Redirect(w, r, m.frontendURL+"/login?"+query.Encode(), http.StatusSeeOther)
+}
@@ -0,0 +1,79 @@
+package middleware
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+)
+
+// clientIPKey はClientIPMiddlewareで決定したクライアントIPをコンテキストに保持するためのキー
+type clientIPKey struct{}
+
+// ClientIPMiddleware はリクエスト元のIPアドレスを決定してコンテキストに設定するミドルウェア
+// X-Forwarded-Forはクライアントが自由に付けられるため、信頼するプロキシが追加した値のみ使う
+type ClientIPMiddleware struct {
+	trustedHops int
+}
+
+// NewClientIPMiddleware は新しいClientIPMiddlewareを作成する
+// trustedHopsは前段にある信頼するリバースプロキシの数（0の場合はX-Forwarded-Forを使わない）
+func NewClientIPMiddleware(trustedHops int) *ClientIPMiddleware {
+	return &ClientIPMiddleware{trustedHops: trustedHops}
+}
+
+// Resolve はクライアントIPをコンテキストに設定するミドルウェア
+func (m *ClientIPMiddleware) Resolve(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := context.WithValue(r.Context(), clientIPKey{}, m.clientIP(r))
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// clientIP はリクエスト元のIPアドレスを返す
+// 信頼するプロキシはそれぞれ接続元のアドレスをX-Forwarded-Forの末尾に追加するため、
+// 末尾からtrustedHops番目が最も外側のプロキシに接続したクライアントのアドレスになる
+func (m *ClientIPMiddleware) clientIP(r *http.Request) string {
+	remote := remoteIP(r)
+	if m.trustedHops <= 0 {
+		return remote
+	}
+
+	// プロキシが別の行として追加する場合もあるため、すべてのヘッダーの値を順に連結する
+	var addrs []string
+	for _, value := range r.Header.Values("X-Forwarded-For") {
+		for _, addr := range strings.Split(value, ",") {
+			addrs = append(addrs, strings.TrimSpace(addr))
+		}
+	}
+	if len(addrs) == 0 {
+		return remote
+	}
+
+	// 値がtrustedHopsより少ない場合はすべて信頼するプロキシが追加した値のため、先頭を使う
+	i := max(len(addrs)-m.trustedHops, 0)
+	ip, err := netip.ParseAddr(addrs[i])
+	if err != nil {
+		return remote
+	}
+	return ip.Unmap().String()
+}
+
+// ClientIP はリクエスト元のIPアドレスを返す
+// ClientIPMiddlewareで決定した値を使い、ない場合は接続元のアドレスを返す
+func ClientIP(r *http.Request) string {
+	if ip, ok := r.Context().Value(clientIPKey{}).(string); ok {
+		return ip
+	}
+	return remoteIP(r)
+}
+
+// remoteIP は接続元のアドレスからポートを除いたものを返す
+func remoteIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
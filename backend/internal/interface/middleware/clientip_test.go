@@ -0,0 +1,102 @@
+package middleware
+
+import (
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// resolveClientIP はClientIPMiddlewareを通したリクエストのClientIPを返す
+func resolveClientIP(m *ClientIPMiddleware, remoteAddr string, forwarded ...string) string {
+	var got string
+	h := m.Resolve(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = ClientIP(r)
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/auth/login", nil)
+	req.RemoteAddr = remoteAddr
+	for _, value := range forwarded {
+		req.Header.Add("X-Forwarded-For", value)
+	}
+	h.ServeHTTP(httptest.NewRecorder(), req)
+	return got
+}
+
+func TestClientIPMiddleware(t *testing.T) {
+	tests := []struct {
+		name       string
+		hops       int
+		remoteAddr string
+		forwarded  []string
+		want       string
+	}{
+		{name: "no proxy", hops: 0, remoteAddr: "203.0.113.10:51234", want: "203.0.113.10"},
+		{name: "no proxy ignores header", hops: 0, remoteAddr: "203.0.113.10:51234", forwarded: []string{"198.51.100.1"}, want: "203.0.113.10"},
+		{name: "ipv6 remote", hops: 0, remoteAddr: "[2001:db8::1]:443", want: "2001:db8::1"},
+		{name: "one proxy", hops: 1, remoteAddr: "10.0.0.2:8080", forwarded: []string{"203.0.113.10"}, want: "203.0.113.10"},
+		{name: "one proxy with spoofed entries", hops: 1, remoteAddr: "10.0.0.2:8080", forwarded: []string{"198.51.100.1, 198.51.100.2, 203.0.113.10"}, want: "203.0.113.10"},
+		{name: "one proxy with separate header lines", hops: 1, remoteAddr: "10.0.0.2:8080", forwarded: []string{"198.51.100.1", "203.0.113.10"}, want: "203.0.113.10"},
+		{name: "two proxies", hops: 2, remoteAddr: "10.0.0.3:8080", forwarded: []string{"198.51.100.1, 203.0.113.10, 10.0.0.2"}, want: "203.0.113.10"},
+		{name: "fewer entries than proxies", hops: 2, remoteAddr: "10.0.0.3:8080", forwarded: []string{"203.0.113.10"}, want: "203.0.113.10"},
+		{name: "ipv4-mapped address", hops: 1, remoteAddr: "10.0.0.2:8080", forwarded: []string{"::ffff:203.0.113.10"}, want: "203.0.113.10"},
+		{name: "invalid entry", hops: 1, remoteAddr: "10.0.0.2:8080", forwarded: []string{"198.51.100.1, unknown"}, want: "10.0.0.2"},
+		{name: "proxy without header", hops: 1, remoteAddr: "10.0.0.2:8080", want: "10.0.0.2"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := resolveClientIP(NewClientIPMiddleware(tt.hops), tt.remoteAddr, tt.forwarded...); got != tt.want {
+				t.Errorf("ClientIP() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestClientIPIgnoresSpoofedHeader はクライアントがX-Forwarded-Forを変えてもレート制限のキーが変わらないことを確認する
+func TestClientIPIgnoresSpoofedHeader(t *testing.T) {
+	tests := []struct {
+		name       string
+		hops       int
+		remoteAddr string
+		// forwarded はプロキシが追加する値（クライアントが付けた値の後ろに連結する）
+		forwarded string
+	}{
+		{name: "no proxy", hops: 0, remoteAddr: "203.0.113.10:51234"},
+		{name: "one proxy", hops: 1, remoteAddr: "10.0.0.2:8080", forwarded: "203.0.113.10"},
+		{name: "two proxies", hops: 2, remoteAddr: "10.0.0.3:8080", forwarded: "203.0.113.10, 10.0.0.2"},
+	}
+	spoofed := []string{"", "198.51.100.1", "198.51.100.2, 198.51.100.3", "garbage", "2001:db8::dead"}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewClientIPMiddleware(tt.hops)
+			limiter := NewRateLimitMiddleware(1, time.Minute, slog.New(slog.NewTextHandler(io.Discard, nil)))
+			now := time.Now()
+
+			keys := map[string]bool{}
+			for i, value := range spoofed {
+				var header []string
+				switch {
+				case value != "" && tt.forwarded != "":
+					header = []string{value + ", " + tt.forwarded}
+				case value != "":
+					header = []string{value}
+				case tt.forwarded != "":
+					header = []string{tt.forwarded}
+				}
+				key := resolveClientIP(m, tt.remoteAddr, header...)
+				keys[key] = true
+
+				// 2回目以降は同じクライアントとして数えられ、上限を超える
+				if allowed := limiter.allow(key, now); allowed != (i == 0) {
+					t.Errorf("allow() with X-Forwarded-For %q = %v, want %v", header, allowed, i == 0)
+				}
+			}
+			if len(keys) != 1 {
+				t.Errorf("client ip keys = %v, want a single key", keys)
+			}
+		})
+	}
+}
@@ -0,0 +1,34 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/sikigasa/github-task-controller/backend/internal/interface/i18n"
+)
+
+// LangCookieName は利用者が選択した表示言語を保持するCookie名
+const LangCookieName = "lang"
+
+// Locale はリクエストの表示言語を判定してコンテキストに設定するミドルウェア
+// 利用者の選択（langクエリ・Cookie）を優先し、なければAccept-Languageヘッダーから判定する
+func Locale(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lang := detectLang(r)
+		w.Header().Add("Vary", "Accept-Language")
+		w.Header().Set("Content-Language", string(lang))
+		next.ServeHTTP(w, r.WithContext(i18n.WithLang(r.Context(), lang)))
+	})
+}
+
+// detectLang はリクエストから表示言語を判定する
+func detectLang(r *http.Request) i18n.Lang {
+	if lang, ok := i18n.Parse(r.URL.Query().Get("lang")); ok {
+		return lang
+	}
+	if cookie, err := r.Cookie(LangCookieName); err == nil {
+		if lang, ok := i18n.Parse(cookie.Value); ok {
+			return lang
+		}
+	}
+	return i18n.Negotiate(r.Header.Get("Accept-Language"))
+}
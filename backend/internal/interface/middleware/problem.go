@@ -0,0 +1,26 @@
+package middleware
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+
+	"github.com/sikigasa/github-task-controller/backend/internal/interface/i18n"
+)
+
+// WriteProblem はハンドラー外（ミドルウェア）からRFC 9457形式のエラーレスポンスを返す
+// detailKeyはメッセージカタログのキーで、リクエストの言語に翻訳して返す
+func WriteProblem(w http.ResponseWriter, r *http.Request, logger *slog.Logger, status int, title, detailKey string) {
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(status)
+	problem := map[string]any{
+		"type":     "about:blank",
+		"title":    title,
+		"status":   status,
+		"detail":   i18n.T(r.Context(), detailKey),
+		"instance": r.URL.Path,
+	}
+	if err := json.NewEncoder(w).Encode(problem); err != nil {
+		logger.ErrorContext(r.Context(), "failed to write error response", "error", err)
+	}
+}
@@ -0,0 +1,39 @@
+package middleware
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"log/slog"
+	"net/http"
+	"strings"
+)
+
+// ProvisioningAuthMiddleware はIdPからのプロビジョニング（SCIM）リクエストを専用のBearerトークンで認証する
+type ProvisioningAuthMiddleware struct {
+	tokenHash [sha256.Size]byte
+	logger    *slog.Logger
+}
+
+// NewProvisioningAuthMiddleware は新しいProvisioningAuthMiddlewareを作成する
+func NewProvisioningAuthMiddleware(token string, logger *slog.Logger) *ProvisioningAuthMiddleware {
+	return &ProvisioningAuthMiddleware{
+		tokenHash: sha256.Sum256([]byte(token)),
+		logger:    logger,
+	}
+}
+
+// RequireToken はAuthorizationヘッダーのBearerトークンがプロビジョニング用のトークンと一致する場合のみ次のハンドラーを実行する
+func (m *ProvisioningAuthMiddleware) RequireToken(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		// 長さの違いから推測されないよう、ハッシュ同士を一定時間で比較する
+		hash := sha256.Sum256([]byte(token))
+		if !ok || token == "" || subtle.ConstantTimeCompare(hash[:], m.tokenHash[:]) != 1 {
+			m.logger.WarnContext(r.Context(), "invalid provisioning token", "path", r.URL.Path)
+			w.Header().Set("WWW-Authenticate", `Bearer realm="scim"`)
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
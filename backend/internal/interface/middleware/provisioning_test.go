@@ -0,0 +1,75 @@
+package middleware
+
+import (
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+const testProvisioningToken = "0123456789abcdef0123456789abcdef"
+
+func serveProvisioning(m *ProvisioningAuthMiddleware, authorization string) (*httptest.ResponseRecorder, bool) {
+	called := false
+	h := m.RequireToken(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/scim/v2/Users", nil)
+	if authorization != "" {
+		req.Header.Set("Authorization", authorization)
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec, called
+}
+
+func TestProvisioningAuthMiddlewareRequireToken(t *testing.T) {
+	m := NewProvisioningAuthMiddleware(testProvisioningToken, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	rec, called := serveProvisioning(m, "Bearer "+testProvisioningToken)
+	if !called || rec.Code != http.StatusNoContent {
+		t.Errorf("valid token: called = %v, status = %d, want handler to be called", called, rec.Code)
+	}
+}
+
+func TestProvisioningAuthMiddlewareRejectsInvalidToken(t *testing.T) {
+	m := NewProvisioningAuthMiddleware(testProvisioningToken, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	tests := map[string]string{
+		"missing header": "",
+		"empty token":    "Bearer ",
+		"wrong token":    "Bearer fedcba9876543210fedcba9876543210",
+		"token prefix":   "Bearer " + testProvisioningToken[:len(testProvisioningToken)-1],
+		"token suffix":   "Bearer " + testProvisioningToken + "0",
+		"basic scheme":   "Basic " + testProvisioningToken,
+		"without scheme": testProvisioningToken,
+	}
+	for name, authorization := range tests {
+		t.Run(name, func(t *testing.T) {
+			rec, called := serveProvisioning(m, authorization)
+			if called {
+				t.Error("handler was called with an invalid token")
+			}
+			if rec.Code != http.StatusUnauthorized {
+				t.Errorf("status = %d, want %d", rec.Code, http.StatusUnauthorized)
+			}
+			if got := rec.Header().Get("WWW-Authenticate"); got != `Bearer realm="scim"` {
+				t.Errorf("WWW-Authenticate = %q, want %q", got, `Bearer realm="scim"`)
+			}
+		})
+	}
+}
+
+func TestProvisioningAuthMiddlewareRejectsEmptyConfiguredToken(t *testing.T) {
+	// トークンが空の設定でも、空のBearerトークンで認証されない
+	m := NewProvisioningAuthMiddleware("", slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	for _, authorization := range []string{"", "Bearer ", "Bearer"} {
+		if _, called := serveProvisioning(m, authorization); called {
+			t.Errorf("handler was called with authorization %q", authorization)
+		}
+	}
+}
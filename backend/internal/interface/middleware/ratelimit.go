@@ -0,0 +1,81 @@
+package middleware
+
+import (
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// RateLimitMiddleware はクライアントIPごとに固定ウィンドウでリクエスト数を制限するミドルウェア
+type RateLimitMiddleware struct {
+	limit   int
+	window  time.Duration
+	mu      sync.Mutex
+	clients map[string]*rateLimitEntry
+	logger  *slog.Logger
+}
+
+// rateLimitEntry はクライアントごとのカウンタ
+type rateLimitEntry struct {
+	count   int
+	resetAt time.Time
+}
+
+// NewRateLimitMiddleware は新しいRateLimitMiddlewareを作成する
+// limitが0以下の場合は制限を行わない
+func NewRateLimitMiddleware(limit int, window time.Duration, logger *slog.Logger) *RateLimitMiddleware {
+	return &RateLimitMiddleware{
+		limit:   limit,
+		window:  window,
+		clients: make(map[string]*rateLimitEntry),
+		logger:  logger,
+	}
+}
+
+// Limit はリクエスト数が上限を超えた場合に429を返すミドルウェア
+func (m *RateLimitMiddleware) Limit(next http.Handler) http.Handler {
+	if m.limit <= 0 {
+		return next
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		key := ClientIP(r)
+
+		if !m.allow(key, time.Now()) {
+			m.logger.WarnContext(ctx, "rate limit exceeded", "client_ip", key, "path", r.URL.Path)
+			w.Header().Set("Retry-After", "60")
+			WriteProblem(w, r, m.logger, http.StatusTooManyRequests, "Too Many Requests", "error.too_many_requests")
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// allow はリクエストを許可するかどうかを判定する
+func (m *RateLimitMiddleware) allow(key string, now time.Time) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	entry, ok := m.clients[key]
+	if !ok || now.After(entry.resetAt) {
+		// 期限切れエントリが溜まらないよう、新規ウィンドウ開始時に掃除する
+		if len(m.clients) > 10000 {
+			for k, e := range m.clients {
+				if now.After(e.resetAt) {
+					delete(m.clients, k)
+				}
+			}
+		}
+		m.clients[key] = &rateLimitEntry{count: 1, resetAt: now.Add(m.window)}
+		return true
+	}
+
+	if entry.count >= m.limit {
+		return false
+	}
+	entry.count++
+	return true
+}
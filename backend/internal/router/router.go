@@ -1,8 +1,11 @@
 package router
 
 import (
+	"bufio"
+	"fmt"
 	"io/fs"
 	"log/slog"
+	"net"
 	"net/http"
 	"os"
 	"path/filepath"
@@ -16,27 +19,89 @@ import (
 
 // Router はアプリケーションのルーティングを管理する
 type Router struct {
-	mux            *http.ServeMux
-	todoHandler    *handler.TodoHandler
-	projectHandler *handler.ProjectHandler
-	taskHandler    *handler.TaskHandler
-	authHandler    *handler.AuthHandler
-	githubHandler  *handler.GithubHandler
-	authMiddleware *middleware.AuthMiddleware
-	logger         *slog.Logger
-	staticDir      string
-	frontendURL    string
+	mux               *http.ServeMux
+	todoHandler       *handler.TodoHandler
+	projectHandler    *handler.ProjectHandler
+	taskHandler       *handler.TaskHandler
+	milestoneHandler  *handler.MilestoneHandler
+	viewHandler       *handler.SavedViewHandler
+	tokenHandler      *handler.ProjectTokenHandler
+	endpointHandler   *handler.WebhookEndpointHandler
+	goalHandler       *handler.GoalHandler
+	settingsHandler   *handler.SettingsHandler
+	pushHandler       *handler.PushHandler
+	reportHandler     *handler.ReportHandler
+	exportHandler     *handler.ExportHandler
+	dashboardHandler  *handler.DashboardHandler
+	trashHandler      *handler.TrashHandler
+	activityHandler   *handler.ActivityHandler
+	sessionHandler    *handler.SessionHandler
+	invitationHandler *handler.InvitationHandler
+	mergeHandler      *handler.AccountMergeHandler
+	authHandler       *handler.AuthHandler
+	githubHandler     *handler.GithubHandler
+	scimHandler       *handler.SCIMHandler
+	webhookHandler    *handler.WebhookDeliveryHandler
+	githubHookHandler *handler.GithubWebhookHandler
+	backupHandler     *handler.BackupHandler
+	jobHandler        *handler.JobHandler
+	seedHandler       *handler.SeedHandler
+	statusHandler     *handler.StatusHandler
+	eventHandler      *handler.EventStreamHandler
+	authMiddleware    *middleware.AuthMiddleware
+	provisioningAuth  *middleware.ProvisioningAuthMiddleware
+	adminAuth         *middleware.AdminMiddleware
+	rateLimiter       *middleware.RateLimitMiddleware
+	statusLimiter     *middleware.RateLimitMiddleware
+	authChallenge     *middleware.ChallengeMiddleware
+	clientIP          *middleware.ClientIPMiddleware
+	logger            *slog.Logger
+	staticDir         string
+	allowedOrigins    []string
 }
 
 // NewRouter は新しいRouterを作成する
+// scimHandler・provisioningAuthはSCIMプロビジョニングを設定していない場合はnil
+// authChallengeはCAPTCHAを設定していない場合はnil
+// githubHookHandlerはGITHUB_WEBHOOK_SECRETを設定していない場合はnil
+// seedHandlerは開発環境（APP_ENV=dev）以外ではnil
 func NewRouter(
 	todoHandler *handler.TodoHandler,
 	projectHandler *handler.ProjectHandler,
 	taskHandler *handler.TaskHandler,
+	milestoneHandler *handler.MilestoneHandler,
+	viewHandler *handler.SavedViewHandler,
+	tokenHandler *handler.ProjectTokenHandler,
+	endpointHandler *handler.WebhookEndpointHandler,
+	goalHandler *handler.GoalHandler,
+	settingsHandler *handler.SettingsHandler,
+	pushHandler *handler.PushHandler,
+	reportHandler *handler.ReportHandler,
+	exportHandler *handler.ExportHandler,
+	dashboardHandler *handler.DashboardHandler,
+	trashHandler *handler.TrashHandler,
+	activityHandler *handler.ActivityHandler,
+	sessionHandler *handler.SessionHandler,
+	invitationHandler *handler.InvitationHandler,
+	mergeHandler *handler.AccountMergeHandler,
 	authHandler *handler.AuthHandler,
 	githubHandler *handler.GithubHandler,
+	scimHandler *handler.SCIMHandler,
+	webhookHandler *handler.WebhookDeliveryHandler,
+	githubHookHandler *handler.GithubWebhookHandler,
+	backupHandler *handler.BackupHandler,
+	jobHandler *handler.JobHandler,
+	seedHandler *handler.SeedHandler,
+	statusHandler *handler.StatusHandler,
+	eventHandler *handler.EventStreamHandler,
 	authMiddleware *middleware.AuthMiddleware,
-	frontendURL string,
+	provisioningAuth *middleware.ProvisioningAuthMiddleware,
+	adminAuth *middleware.AdminMiddleware,
+	rateLimiter *middleware.RateLimitMiddleware,
+	statusLimiter *middleware.RateLimitMiddleware,
+	authChallenge *middleware.ChallengeMiddleware,
+	clientIP *middleware.ClientIPMiddleware,
+	allowedOrigins []string,
 	logger *slog.Logger,
 ) *Router {
 	// 静的ファイルディレクトリ（環境変数で設定可能）
@@ -46,16 +111,45 @@ func NewRouter(
 	}
 
 	return &Router{
-		mux:            http.NewServeMux(),
-		todoHandler:    todoHandler,
-		projectHandler: projectHandler,
-		taskHandler:    taskHandler,
-		authHandler:    authHandler,
-		githubHandler:  githubHandler,
-		authMiddleware: authMiddleware,
-		logger:         logger,
-		staticDir:      staticDir,
-		frontendURL:    frontendURL,
+		mux:               http.NewServeMux(),
+		todoHandler:       todoHandler,
+		projectHandler:    projectHandler,
+		taskHandler:       taskHandler,
+		milestoneHandler:  milestoneHandler,
+		viewHandler:       viewHandler,
+		tokenHandler:      tokenHandler,
+		endpointHandler:   endpointHandler,
+		goalHandler:       goalHandler,
+		settingsHandler:   settingsHandler,
+		pushHandler:       pushHandler,
+		reportHandler:     reportHandler,
+		exportHandler:     exportHandler,
+		dashboardHandler:  dashboardHandler,
+		trashHandler:      trashHandler,
+		activityHandler:   activityHandler,
+		sessionHandler:    sessionHandler,
+		invitationHandler: invitationHandler,
+		mergeHandler:      mergeHandler,
+		authHandler:       authHandler,
+		githubHandler:     githubHandler,
+		scimHandler:       scimHandler,
+		webhookHandler:    webhookHandler,
+		githubHookHandler: githubHookHandler,
+		backupHandler:     backupHandler,
+		jobHandler:        jobHandler,
+		seedHandler:       seedHandler,
+		statusHandler:     statusHandler,
+		eventHandler:      eventHandler,
+		authMiddleware:    authMiddleware,
+		provisioningAuth:  provisioningAuth,
+		adminAuth:         adminAuth,
+		rateLimiter:       rateLimiter,
+		statusLimiter:     statusLimiter,
+		authChallenge:     authChallenge,
+		clientIP:          clientIP,
+		logger:            logger,
+		staticDir:         staticDir,
+		allowedOrigins:    allowedOrigins,
 	}
 }
 
@@ -63,17 +157,44 @@ func NewRouter(
 func (r *Router) Setup() http.Handler {
 	// ヘルスチェック
 	r.mux.HandleFunc("GET /health", r.healthCheck)
+	// 稼働状況（認証不要、キャッシュ可能、全体とは別にIPごとの回数を制限する）
+	r.mux.Handle("GET /status", r.statusLimiter.Limit(http.HandlerFunc(r.statusHandler.Get)))
 
 	// 認証エンドポイント（認証不要）
+	// ログイン開始はクライアントごとの試行回数が多い場合にCAPTCHAを要求する
+	login := func(pattern string, h http.HandlerFunc) {
+		if r.authChallenge == nil {
+			r.mux.Handle(pattern, h)
+			return
+		}
+		r.mux.Handle(pattern, r.authChallenge.Require(h))
+	}
 	// Google OAuth
-	r.mux.HandleFunc("GET /auth/google/login", r.authHandler.Login)
+	login("GET /auth/google/login", r.authHandler.Login)
 	r.mux.HandleFunc("GET /auth/google/callback", r.authHandler.Callback)
 	// GitHub OAuth
-	r.mux.HandleFunc("GET /auth/github/login", r.authHandler.LoginGithub)
+	login("GET /auth/github/login", r.authHandler.LoginGithub)
 	r.mux.HandleFunc("GET /auth/github/callback", r.authHandler.CallbackGithub)
+	// Microsoft Entra ID
+	login("GET /auth/microsoft/login", r.authHandler.LoginMicrosoft)
+	r.mux.HandleFunc("GET /auth/microsoft/callback", r.authHandler.CallbackMicrosoft)
+	// Sign in with Apple（コールバックはform_post）
+	login("GET /auth/apple/login", r.authHandler.LoginApple)
+	r.mux.HandleFunc("POST /auth/apple/callback", r.authHandler.CallbackApple)
+	// SAML SSO（ACSはIdPからのHTTP-POST）
+	r.mux.HandleFunc("GET /auth/saml/metadata", r.authHandler.SAMLMetadata)
+	login("GET /auth/saml/login", r.authHandler.LoginSAML)
+	r.mux.HandleFunc("POST /auth/saml/acs", r.authHandler.SAMLACS)
+	// アカウント統合の確認リンク
+	r.mux.HandleFunc("GET /auth/merge/confirm", r.mergeHandler.ConfirmByEmail)
+	// デモモードのゲストログイン
+	login("POST /auth/demo", r.authHandler.LoginDemo)
 	// 共通
 	r.mux.HandleFunc("POST /auth/logout", r.authHandler.Logout)
-	r.mux.HandleFunc("GET /auth/me", r.authHandler.Me)
+	r.mux.HandleFunc("POST /auth/refresh", r.authHandler.Refresh)
+	r.mux.Handle("GET /auth/me", r.authMiddleware.OptionalAuth(http.HandlerFunc(r.authHandler.Me)))
+	r.mux.Handle("DELETE /auth/sessions", r.authMiddleware.RequireAuth(http.HandlerFunc(r.authHandler.LogoutAll)))
+	r.mux.Handle("DELETE /auth/sessions/{id}", r.authMiddleware.RequireAuth(http.HandlerFunc(r.sessionHandler.Revoke)))
 
 	// 認証が必要なAPIエンドポイント
 	// TODOエンドポイント
@@ -82,52 +203,204 @@ func (r *Router) Setup() http.Handler {
 	r.mux.Handle("GET /api/v1/todos/{id}", r.authMiddleware.RequireAuth(http.HandlerFunc(r.todoHandler.Get)))
 	r.mux.Handle("PUT /api/v1/todos/{id}", r.authMiddleware.RequireAuth(http.HandlerFunc(r.todoHandler.Update)))
 	r.mux.Handle("DELETE /api/v1/todos/{id}", r.authMiddleware.RequireAuth(http.HandlerFunc(r.todoHandler.Delete)))
+	r.mux.Handle("POST /api/v1/todos/{id}/restore", r.authMiddleware.RequireAuth(http.HandlerFunc(r.todoHandler.Restore)))
 
 	// プロジェクトエンドポイント
 	r.mux.Handle("POST /api/v1/projects", r.authMiddleware.RequireAuth(http.HandlerFunc(r.projectHandler.Create)))
 	r.mux.Handle("GET /api/v1/projects", r.authMiddleware.RequireAuth(http.HandlerFunc(r.projectHandler.ListByUserID)))
-	r.mux.Handle("GET /api/v1/projects/{id}", r.authMiddleware.RequireAuth(http.HandlerFunc(r.projectHandler.Get)))
+	r.mux.Handle("GET /api/v1/projects/{id}", r.authMiddleware.RequireAuthOrProjectToken(handler.ProjectIDFromPath, http.HandlerFunc(r.projectHandler.Get)))
 	r.mux.Handle("PUT /api/v1/projects/{id}", r.authMiddleware.RequireAuth(http.HandlerFunc(r.projectHandler.Update)))
+	r.mux.Handle("PATCH /api/v1/projects/{id}", r.authMiddleware.RequireAuth(http.HandlerFunc(r.projectHandler.Patch)))
 	r.mux.Handle("DELETE /api/v1/projects/{id}", r.authMiddleware.RequireAuth(http.HandlerFunc(r.projectHandler.Delete)))
-
-	// タスクエンドポイント
-	r.mux.Handle("POST /api/v1/tasks", r.authMiddleware.RequireAuth(http.HandlerFunc(r.taskHandler.Create)))
-	r.mux.Handle("GET /api/v1/tasks", r.authMiddleware.RequireAuth(http.HandlerFunc(r.taskHandler.ListByProjectID)))
-	r.mux.Handle("GET /api/v1/tasks/{id}", r.authMiddleware.RequireAuth(http.HandlerFunc(r.taskHandler.Get)))
-	r.mux.Handle("PUT /api/v1/tasks/{id}", r.authMiddleware.RequireAuth(http.HandlerFunc(r.taskHandler.Update)))
-	r.mux.Handle("DELETE /api/v1/tasks/{id}", r.authMiddleware.RequireAuth(http.HandlerFunc(r.taskHandler.Delete)))
+	r.mux.Handle("POST /api/v1/projects/{id}/restore", r.authMiddleware.RequireAuth(http.HandlerFunc(r.projectHandler.Restore)))
+	r.mux.Handle("GET /api/v1/projects/{id}/delete-preview", r.authMiddleware.RequireAuth(http.HandlerFunc(r.projectHandler.DeletePreview)))
+	r.mux.Handle("GET /api/v1/projects/{id}/timeline", r.authMiddleware.RequireAuth(http.HandlerFunc(r.projectHandler.Timeline)))
+	// プロジェクトの変更のリアルタイム配信（WebSocket）
+	r.mux.Handle("GET /api/v1/projects/{id}/events", r.authMiddleware.RequireAuthStream(http.HandlerFunc(r.eventHandler.Stream)))
+
+	// プロジェクト単位のAPIトークン（トークンの管理はログインしたユーザーのみ）
+	r.mux.Handle("POST /api/v1/projects/{id}/tokens", r.authMiddleware.RequireAuth(http.HandlerFunc(r.tokenHandler.Create)))
+	r.mux.Handle("GET /api/v1/projects/{id}/tokens", r.authMiddleware.RequireAuth(http.HandlerFunc(r.tokenHandler.List)))
+	r.mux.Handle("DELETE /api/v1/projects/{id}/tokens/{tokenId}", r.authMiddleware.RequireAuth(http.HandlerFunc(r.tokenHandler.Delete)))
+	r.mux.Handle("POST /api/v1/projects/{id}/webhooks", r.authMiddleware.RequireAuth(http.HandlerFunc(r.endpointHandler.Create)))
+	r.mux.Handle("GET /api/v1/projects/{id}/webhooks", r.authMiddleware.RequireAuth(http.HandlerFunc(r.endpointHandler.List)))
+	r.mux.Handle("PATCH /api/v1/projects/{id}/webhooks/{webhookId}", r.authMiddleware.RequireAuth(http.HandlerFunc(r.endpointHandler.Patch)))
+	r.mux.Handle("DELETE /api/v1/projects/{id}/webhooks/{webhookId}", r.authMiddleware.RequireAuth(http.HandlerFunc(r.endpointHandler.Delete)))
+	r.mux.Handle("GET /api/v1/projects/{id}/webhooks/{webhookId}/deliveries", r.authMiddleware.RequireAuth(http.HandlerFunc(r.endpointHandler.ListDeliveries)))
+
+	// タスクエンドポイント（プロジェクト単位のAPIトークンでも操作できる）
+	r.mux.Handle("POST /api/v1/tasks", r.authMiddleware.RequireAuthOrProjectToken(r.taskHandler.CreateTaskProjectID, http.HandlerFunc(r.taskHandler.Create)))
+	r.mux.Handle("GET /api/v1/tasks", r.authMiddleware.RequireAuthOrProjectToken(handler.ProjectIDFromQuery, http.HandlerFunc(r.taskHandler.ListByProjectID)))
+	r.mux.Handle("GET /api/v1/tasks/by-github", r.authMiddleware.RequireAuth(http.HandlerFunc(r.taskHandler.FindByGithub)))
+	r.mux.Handle("GET /api/v1/tasks/{id}", r.authMiddleware.RequireAuthOrProjectToken(r.taskHandler.TaskProjectID, http.HandlerFunc(r.taskHandler.Get)))
+	r.mux.Handle("PUT /api/v1/tasks/{id}", r.authMiddleware.RequireAuthOrProjectToken(r.taskHandler.TaskProjectID, http.HandlerFunc(r.taskHandler.Update)))
+	r.mux.Handle("PATCH /api/v1/tasks/{id}", r.authMiddleware.RequireAuthOrProjectToken(r.taskHandler.TaskProjectID, http.HandlerFunc(r.taskHandler.Patch)))
+	r.mux.Handle("DELETE /api/v1/tasks/{id}", r.authMiddleware.RequireAuthOrProjectToken(r.taskHandler.TaskProjectID, http.HandlerFunc(r.taskHandler.Delete)))
+	r.mux.Handle("POST /api/v1/tasks/{id}/restore", r.authMiddleware.RequireAuth(http.HandlerFunc(r.taskHandler.Restore)))
+	r.mux.Handle("GET /api/v1/tasks/{id}/status-events", r.authMiddleware.RequireAuthOrProjectToken(r.taskHandler.TaskProjectID, http.HandlerFunc(r.taskHandler.ListStatusEvents)))
+	r.mux.Handle("GET /api/v1/tasks/{id}/subtasks", r.authMiddleware.RequireAuthOrProjectToken(r.taskHandler.TaskProjectID, http.HandlerFunc(r.taskHandler.ListSubtasks)))
+	r.mux.Handle("POST /api/v1/tasks/{id}/dependencies", r.authMiddleware.RequireAuth(http.HandlerFunc(r.taskHandler.AddDependency)))
+	r.mux.Handle("DELETE /api/v1/tasks/{id}/dependencies/{dependsOnId}", r.authMiddleware.RequireAuth(http.HandlerFunc(r.taskHandler.RemoveDependency)))
+	r.mux.Handle("POST /api/v1/tasks/{id}/relations", r.authMiddleware.RequireAuth(http.HandlerFunc(r.taskHandler.AddRelation)))
+	r.mux.Handle("DELETE /api/v1/tasks/{id}/relations/{type}/{relatedId}", r.authMiddleware.RequireAuth(http.HandlerFunc(r.taskHandler.RemoveRelation)))
+
+	// マイルストーンエンドポイント
+	r.mux.Handle("POST /api/v1/projects/{id}/milestones", r.authMiddleware.RequireAuth(http.HandlerFunc(r.milestoneHandler.Create)))
+	r.mux.Handle("GET /api/v1/projects/{id}/milestones", r.authMiddleware.RequireAuthOrProjectToken(handler.ProjectIDFromPath, http.HandlerFunc(r.milestoneHandler.ListByProjectID)))
+	r.mux.Handle("GET /api/v1/milestones/{id}", r.authMiddleware.RequireAuth(http.HandlerFunc(r.milestoneHandler.Get)))
+	r.mux.Handle("PUT /api/v1/milestones/{id}", r.authMiddleware.RequireAuth(http.HandlerFunc(r.milestoneHandler.Update)))
+	r.mux.Handle("DELETE /api/v1/milestones/{id}", r.authMiddleware.RequireAuth(http.HandlerFunc(r.milestoneHandler.Delete)))
+
+	// アクティビティログ
+	r.mux.Handle("GET /api/v1/projects/{id}/activity", r.authMiddleware.RequireAuth(http.HandlerFunc(r.activityHandler.ListByProjectID)))
+
+	// 保存済みビューエンドポイント
+	r.mux.Handle("POST /api/v1/projects/{id}/views", r.authMiddleware.RequireAuth(http.HandlerFunc(r.viewHandler.Create)))
+	r.mux.Handle("GET /api/v1/projects/{id}/views", r.authMiddleware.RequireAuth(http.HandlerFunc(r.viewHandler.ListByProjectID)))
+	r.mux.Handle("GET /api/v1/views/{id}", r.authMiddleware.RequireAuth(http.HandlerFunc(r.viewHandler.Get)))
+	r.mux.Handle("DELETE /api/v1/views/{id}", r.authMiddleware.RequireAuth(http.HandlerFunc(r.viewHandler.Delete)))
+	r.mux.Handle("GET /api/v1/views/{id}/tasks", r.authMiddleware.RequireAuth(http.HandlerFunc(r.viewHandler.ListTasks)))
+
+	// 目標（OKR）エンドポイント
+	r.mux.Handle("POST /api/v1/projects/{id}/goals", r.authMiddleware.RequireAuth(http.HandlerFunc(r.goalHandler.Create)))
+	r.mux.Handle("GET /api/v1/projects/{id}/goals", r.authMiddleware.RequireAuth(http.HandlerFunc(r.goalHandler.ListByProjectID)))
+	r.mux.Handle("GET /api/v1/goals/{id}", r.authMiddleware.RequireAuth(http.HandlerFunc(r.goalHandler.Get)))
+	r.mux.Handle("PUT /api/v1/goals/{id}", r.authMiddleware.RequireAuth(http.HandlerFunc(r.goalHandler.Update)))
+	r.mux.Handle("DELETE /api/v1/goals/{id}", r.authMiddleware.RequireAuth(http.HandlerFunc(r.goalHandler.Delete)))
+	r.mux.Handle("POST /api/v1/goals/{id}/tasks", r.authMiddleware.RequireAuth(http.HandlerFunc(r.goalHandler.AddTask)))
+	r.mux.Handle("DELETE /api/v1/goals/{id}/tasks/{taskId}", r.authMiddleware.RequireAuth(http.HandlerFunc(r.goalHandler.RemoveTask)))
+
+	// レポートエンドポイント
+	r.mux.Handle("GET /api/v1/projects/{id}/reports/velocity", r.authMiddleware.RequireAuth(http.HandlerFunc(r.reportHandler.Velocity)))
+	r.mux.Handle("GET /api/v1/projects/{id}/reports/cfd", r.authMiddleware.RequireAuth(http.HandlerFunc(r.reportHandler.CumulativeFlow)))
+	r.mux.Handle("GET /api/v1/projects/{id}/reports/flow-time", r.authMiddleware.RequireAuth(http.HandlerFunc(r.reportHandler.FlowTime)))
+	r.mux.Handle("GET /api/v1/projects/{id}/reports/time-in-status", r.authMiddleware.RequireAuth(http.HandlerFunc(r.reportHandler.TimeInStatus)))
+	r.mux.Handle("GET /api/v1/reports/overdue", r.authMiddleware.RequireAuth(http.HandlerFunc(r.reportHandler.Overdue)))
+	r.mux.Handle("GET /api/v1/users/me/stats", r.authMiddleware.RequireAuth(http.HandlerFunc(r.reportHandler.UserStats)))
+	r.mux.Handle("POST /api/v1/reports/exports", r.authMiddleware.RequireAuth(http.HandlerFunc(r.exportHandler.Create)))
+	r.mux.Handle("GET /api/v1/reports/exports/{id}", r.authMiddleware.RequireAuth(http.HandlerFunc(r.exportHandler.Get)))
+	r.mux.Handle("GET /api/v1/reports/exports/{id}/download", r.authMiddleware.RequireAuth(http.HandlerFunc(r.exportHandler.Download)))
+
+	// ダッシュボードエンドポイント
+	r.mux.Handle("GET /api/v1/dashboard", r.authMiddleware.RequireAuth(http.HandlerFunc(r.dashboardHandler.Get)))
+
+	// ゴミ箱エンドポイント
+	r.mux.Handle("GET /api/v1/trash", r.authMiddleware.RequireAuth(http.HandlerFunc(r.trashHandler.List)))
+
+	// ログインセッションエンドポイント
+	r.mux.Handle("GET /api/v1/sessions", r.authMiddleware.RequireAuth(http.HandlerFunc(r.sessionHandler.List)))
+
+	// アカウント統合エンドポイント
+	r.mux.Handle("GET /api/v1/account/merges", r.authMiddleware.RequireAuth(http.HandlerFunc(r.mergeHandler.List)))
+	r.mux.Handle("POST /api/v1/account/merges/{id}/confirm", r.authMiddleware.RequireAuth(http.HandlerFunc(r.mergeHandler.Confirm)))
+
+	// 招待エンドポイント
+	r.mux.Handle("GET /api/v1/invitations", r.authMiddleware.RequireAuth(http.HandlerFunc(r.invitationHandler.List)))
+	r.mux.Handle("POST /api/v1/invitations", r.authMiddleware.RequireAuth(http.HandlerFunc(r.invitationHandler.Create)))
+	r.mux.Handle("DELETE /api/v1/invitations/{id}", r.authMiddleware.RequireAuth(http.HandlerFunc(r.invitationHandler.Delete)))
+
+	// 設定エンドポイント
+	r.mux.Handle("GET /api/v1/settings", r.authMiddleware.RequireAuth(http.HandlerFunc(r.settingsHandler.Get)))
+	r.mux.Handle("PUT /api/v1/settings", r.authMiddleware.RequireAuth(http.HandlerFunc(r.settingsHandler.Update)))
+
+	// プッシュ通知を受け取る端末の登録
+	r.mux.Handle("POST /api/v1/push/devices", r.authMiddleware.RequireAuth(http.HandlerFunc(r.pushHandler.RegisterDevice)))
+	r.mux.Handle("GET /api/v1/push/devices", r.authMiddleware.RequireAuth(http.HandlerFunc(r.pushHandler.ListDevices)))
+	r.mux.Handle("DELETE /api/v1/push/devices/{id}", r.authMiddleware.RequireAuth(http.HandlerFunc(r.pushHandler.DeleteDevice)))
 
 	// GitHub連携エンドポイント
 	r.mux.Handle("GET /api/v1/github/status", r.authMiddleware.RequireAuth(http.HandlerFunc(r.githubHandler.GetConnectionStatus)))
+	r.mux.Handle("GET /api/v1/github/diagnostics", r.authMiddleware.RequireAuth(http.HandlerFunc(r.githubHandler.Diagnose)))
+	r.mux.Handle("GET /api/v1/github/pending-operations", r.authMiddleware.RequireAuth(http.HandlerFunc(r.githubHandler.ListPendingOperations)))
+	r.mux.Handle("GET /api/v1/github/usage", r.authMiddleware.RequireAuth(http.HandlerFunc(r.githubHandler.GetSyncUsage)))
 	r.mux.Handle("POST /api/v1/github/pat", r.authMiddleware.RequireAuth(http.HandlerFunc(r.githubHandler.SavePAT)))
 	r.mux.Handle("DELETE /api/v1/github/pat", r.authMiddleware.RequireAuth(http.HandlerFunc(r.githubHandler.DeletePAT)))
 	r.mux.Handle("GET /api/v1/github/projects", r.authMiddleware.RequireAuth(http.HandlerFunc(r.githubHandler.ListGithubProjects)))
 	r.mux.Handle("POST /api/v1/projects/{id}/github/link", r.authMiddleware.RequireAuth(http.HandlerFunc(r.githubHandler.LinkProject)))
 	r.mux.Handle("DELETE /api/v1/projects/{id}/github/link", r.authMiddleware.RequireAuth(http.HandlerFunc(r.githubHandler.UnlinkProject)))
+	r.mux.Handle("GET /api/v1/projects/{id}/github/field-mapping", r.authMiddleware.RequireAuth(http.HandlerFunc(r.githubHandler.GetFieldMapping)))
+	r.mux.Handle("PUT /api/v1/projects/{id}/github/field-mapping", r.authMiddleware.RequireAuth(http.HandlerFunc(r.githubHandler.UpdateFieldMapping)))
+	r.mux.Handle("DELETE /api/v1/projects/{id}/github/field-mapping", r.authMiddleware.RequireAuth(http.HandlerFunc(r.githubHandler.DeleteFieldMapping)))
+	r.mux.Handle("GET /api/v1/projects/{id}/github/items", r.authMiddleware.RequireAuth(http.HandlerFunc(r.githubHandler.ListGithubProjectItems)))
+	r.mux.Handle("POST /api/v1/projects/{id}/github/sync", r.authMiddleware.RequireAuth(http.HandlerFunc(r.githubHandler.SyncProject)))
+	r.mux.Handle("POST /api/v1/projects/{id}/github/import", r.authMiddleware.RequireAuth(http.HandlerFunc(r.githubHandler.ImportProjectItems)))
+	r.mux.Handle("POST /api/v1/projects/{id}/github/commits/sync", r.authMiddleware.RequireAuth(http.HandlerFunc(r.githubHandler.SyncProjectCommits)))
 	r.mux.Handle("POST /api/v1/tasks/{id}/github/sync", r.authMiddleware.RequireAuth(http.HandlerFunc(r.githubHandler.SyncTaskToGithub)))
+	r.mux.Handle("POST /api/v1/tasks/{id}/github/branch", r.authMiddleware.RequireAuth(http.HandlerFunc(r.githubHandler.CreateTaskBranch)))
+	r.mux.Handle("GET /api/v1/tasks/{id}/github/commits", r.authMiddleware.RequireAuth(http.HandlerFunc(r.githubHandler.ListTaskCommits)))
+	r.mux.Handle("GET /api/v1/tasks/{id}/github/pull-requests", r.authMiddleware.RequireAuth(http.HandlerFunc(r.githubHandler.ListTaskPullRequests)))
+	r.mux.Handle("POST /api/v1/tasks/{id}/github/pull-requests/sync", r.authMiddleware.RequireAuth(http.HandlerFunc(r.githubHandler.SyncTaskPullRequests)))
+	r.mux.Handle("POST /api/v1/milestones/{id}/github/sync", r.authMiddleware.RequireAuth(http.HandlerFunc(r.githubHandler.SyncMilestoneToGithub)))
+	r.mux.Handle("POST /api/v1/github/issues/import", r.authMiddleware.RequireAuth(http.HandlerFunc(r.githubHandler.ImportAssignedIssues)))
+
+	// 管理者向けエンドポイント（ADMIN_USER_IDSのユーザーのみ）
+	admin := func(pattern string, h http.HandlerFunc) {
+		r.mux.Handle(pattern, r.authMiddleware.RequireAuth(r.adminAuth.RequireAdmin(h)))
+	}
+	admin("GET /api/v1/admin/webhook-deliveries", r.webhookHandler.List)
+	admin("GET /api/v1/admin/webhook-deliveries/{id}", r.webhookHandler.Get)
+	admin("POST /api/v1/admin/webhook-deliveries/{id}/reprocess", r.webhookHandler.Reprocess)
+	admin("GET /api/v1/admin/backups", r.backupHandler.List)
+	admin("POST /api/v1/admin/backups", r.backupHandler.Create)
+	admin("POST /api/v1/admin/backups/upload", r.backupHandler.Upload)
+	admin("GET /api/v1/admin/backups/{name}", r.backupHandler.Download)
+	admin("DELETE /api/v1/admin/backups/{name}", r.backupHandler.Delete)
+	admin("POST /api/v1/admin/backups/{name}/restore", r.backupHandler.Restore)
+	admin("GET /api/v1/admin/jobs", r.jobHandler.List)
+
+	// 開発用データの生成エンドポイント（開発環境のみ）
+	if r.seedHandler != nil {
+		r.mux.Handle("POST /api/v1/dev/seed", r.authMiddleware.RequireAuth(http.HandlerFunc(r.seedHandler.Seed)))
+	}
+
+	// GitHubのWebhookの受信（認証の代わりにX-Hub-Signature-256の署名を検証する）
+	if r.githubHookHandler != nil {
+		r.mux.HandleFunc("POST /webhooks/github", r.githubHookHandler.Receive)
+	}
+
+	// SCIMプロビジョニングエンドポイント（プロビジョニング用のトークンで認証）
+	if r.scimHandler != nil {
+		scim := func(pattern string, h http.HandlerFunc) {
+			r.mux.Handle(pattern, r.provisioningAuth.RequireToken(h))
+		}
+		scim("GET /scim/v2/ServiceProviderConfig", r.scimHandler.ServiceProviderConfig)
+		scim("GET /scim/v2/ResourceTypes", r.scimHandler.ResourceTypes)
+		scim("GET /scim/v2/Users", r.scimHandler.ListUsers)
+		scim("POST /scim/v2/Users", r.scimHandler.CreateUser)
+		scim("GET /scim/v2/Users/{id}", r.scimHandler.GetUser)
+		scim("PUT /scim/v2/Users/{id}", r.scimHandler.ReplaceUser)
+		scim("PATCH /scim/v2/Users/{id}", r.scimHandler.PatchUser)
+		scim("DELETE /scim/v2/Users/{id}", r.scimHandler.DeleteUser)
+		scim("GET /scim/v2/Groups", r.scimHandler.ListGroups)
+		scim("POST /scim/v2/Groups", r.scimHandler.CreateGroup)
+		scim("GET /scim/v2/Groups/{id}", r.scimHandler.GetGroup)
+		scim("PUT /scim/v2/Groups/{id}", r.scimHandler.ReplaceGroup)
+		scim("PATCH /scim/v2/Groups/{id}", r.scimHandler.PatchGroup)
+		scim("DELETE /scim/v2/Groups/{id}", r.scimHandler.DeleteGroup)
+	}
 
 	// SPA静的ファイル配信（本番環境用）
 	r.mux.HandleFunc("/", r.spaHandler)
 
 	// CORS設定
 	// credentials: 'include' を使用する場合、AllowedOrigins に "*" は使用不可
-	allowedOrigins := []string{"http://localhost:5173", "http://127.0.0.1:5173"}
-	if r.frontendURL != "" && r.frontendURL != "http://localhost:5173" {
-		allowedOrigins = append(allowedOrigins, r.frontendURL)
-	}
+	// 許可するオリジンは環境プロファイル（APP_ENV）から決定される
 	c := cors.New(cors.Options{
-		AllowedOrigins:   allowedOrigins,
-		AllowedMethods:   []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
-		AllowedHeaders:   []string{"Content-Type", "Authorization", "Cookie"},
-		ExposedHeaders:   []string{"Content-Length", "Set-Cookie"},
+		AllowedOrigins:   r.allowedOrigins,
+		AllowedMethods:   []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
+		AllowedHeaders:   []string{"Content-Type", "Authorization", "Cookie", "Accept-Language"},
+		ExposedHeaders:   []string{"Content-Length", "Set-Cookie", "Content-Language"},
 		AllowCredentials: true,
 		MaxAge:           300,
 	})
 
 	// ミドルウェアを適用
 	var h http.Handler = r.mux
+	h = r.rateLimiter.Limit(h)
 	h = r.loggingMiddleware(h)
 	h = r.recoveryMiddleware(h)
+	h = middleware.Locale(h)
+	h = r.clientIP.Resolve(h)
 
 	return c.Handler(h)
 }
@@ -173,11 +446,7 @@ func (r *Router) recoveryMiddleware(next http.Handler) http.Handler {
 					"path", req.URL.Path,
 				)
 
-				w.Header().Set("Content-Type", "application/json")
-				w.WriteHeader(http.StatusInternalServerError)
-				if _, writeErr := w.Write([]byte(`{"type":"about:blank","title":"Internal Server Error","status":500,"detail":"予期しないエラーが発生しました"}`)); writeErr != nil {
-					r.logger.ErrorContext(req.Context(), "failed to write error response", "error", writeErr)
-				}
+				middleware.WriteProblem(w, req, r.logger, http.StatusInternalServerError, "Internal Server Error", "error.unexpected")
 			}
 		}()
 
@@ -197,6 +466,16 @@ func (rw *responseWriter) WriteHeader(code int) {
 	rw.ResponseWriter.WriteHeader(code)
 }
 
+// Hijack はWebSocketで接続を引き継ぐため、ラップしたResponseWriterの接続を返す
+func (rw *responseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hijacker, ok := rw.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, fmt.Errorf("response writer does not support hijacking")
+	}
+	rw.statusCode = http.StatusSwitchingProtocols
+	return hijacker.Hijack()
+}
+
 // spaHandler はSPA用の静的ファイル配信とfallbackを処理する
 func (r *Router) spaHandler(w http.ResponseWriter, req *http.Request) {
 	// 静的ファイルディレクトリが存在しない場合は404
@@ -0,0 +1,207 @@
+package router
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/sikigasa/github-task-controller/backend/internal/application/usecase"
+	"github.com/sikigasa/github-task-controller/backend/internal/domain/model"
+	"github.com/sikigasa/github-task-controller/backend/internal/domain/repository"
+	"github.com/sikigasa/github-task-controller/backend/internal/interface/handler"
+	"github.com/sikigasa/github-task-controller/backend/internal/interface/middleware"
+)
+
+const (
+	testUserID      = "0190a6b8-0000-7000-8000-000000000001"
+	testAccessToken = "test-access-token"
+	testProjectID   = "0190a6b8-0000-7000-8000-0000000000a1"
+	testTaskID      = "0190a6b8-0000-7000-8000-0000000000b1"
+	testDependsOnID = "0190a6b8-0000-7000-8000-0000000000b2"
+)
+
+// stubAccessTokens はtestAccessTokenのみをtestUserIDのアクセストークンとして受け付ける
+type stubAccessTokens struct{}
+
+func (stubAccessTokens) VerifyAccessToken(accessToken string) (string, string, error) {
+	if accessToken != testAccessToken {
+		return "", "", errors.New("invalid access token")
+	}
+	return testUserID, "", nil
+}
+
+type stubActivityRecorder struct{}
+
+func (stubActivityRecorder) RecordActivity(context.Context, string) {}
+
+// fakeProjectRepo はFindByIDで渡されたIDを記録し、testUserIDのプロジェクトとして返す
+// 埋め込んだインターフェースはnilのため、その他のメソッドを呼び出すとpanicする
+type fakeProjectRepo struct {
+	repository.ProjectRepository
+	ids []string
+}
+
+func (r *fakeProjectRepo) FindByID(_ context.Context, id string) (*model.Project, error) {
+	r.ids = append(r.ids, id)
+	return &model.Project{ID: id, UserID: testUserID}, nil
+}
+
+// fakeTaskRepo はタスクの検索で渡されたIDを記録し、testProjectIDのタスクとして返す
+type fakeTaskRepo struct {
+	repository.TaskRepository
+	ids []string
+}
+
+func (r *fakeTaskRepo) FindOwnerID(_ context.Context, id string) (string, error) {
+	r.ids = append(r.ids, id)
+	return testUserID, nil
+}
+
+func (r *fakeTaskRepo) FindByID(_ context.Context, id string) (*model.Task, error) {
+	r.ids = append(r.ids, id)
+	return &model.Task{ID: id, ProjectID: testProjectID}, nil
+}
+
+func (r *fakeTaskRepo) FindByParentID(_ context.Context, parentID string) ([]*model.Task, error) {
+	r.ids = append(r.ids, parentID)
+	return nil, nil
+}
+
+type fakeTaskRelationRepo struct {
+	repository.TaskRelationRepository
+}
+
+func (fakeTaskRelationRepo) FindLinksByTaskID(context.Context, string) ([]*model.TaskRelationLink, error) {
+	return nil, nil
+}
+
+// fakeTaskDependencyRepo はDeleteで渡されたIDの組を記録する
+type fakeTaskDependencyRepo struct {
+	repository.TaskDependencyRepository
+	deleted [][2]string
+}
+
+func (r *fakeTaskDependencyRepo) Delete(_ context.Context, taskID, dependsOnTaskID string) error {
+	r.deleted = append(r.deleted, [2]string{taskID, dependsOnTaskID})
+	return nil
+}
+
+type routerFixture struct {
+	handler  http.Handler
+	projects *fakeProjectRepo
+	tasks    *fakeTaskRepo
+	deps     *fakeTaskDependencyRepo
+}
+
+// newRouterFixture はプロジェクト・タスクのハンドラーのみを実際のユースケースとフェイクのリポジトリで組み立てたルーターを作成する
+func newRouterFixture(t *testing.T) *routerFixture {
+	t.Helper()
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	t.Setenv("STATIC_DIR", t.TempDir())
+
+	f := &routerFixture{
+		projects: &fakeProjectRepo{},
+		tasks:    &fakeTaskRepo{},
+		deps:     &fakeTaskDependencyRepo{},
+	}
+	projectUsecase := usecase.NewProjectUsecase(f.projects, f.tasks, f.deps, nil, nil, nil, nil, logger)
+	taskUsecase := usecase.NewTaskUsecase(f.tasks, f.projects, nil, f.deps, fakeTaskRelationRepo{}, nil, nil, nil, nil, nil, nil, logger)
+	authMiddleware := middleware.NewAuthMiddleware(nil, stubAccessTokens{}, nil, stubActivityRecorder{}, nil, logger)
+	noLimit := middleware.NewRateLimitMiddleware(0, time.Minute, logger)
+
+	r := NewRouter(
+		nil,
+		handler.NewProjectHandler(projectUsecase, nil, logger),
+		handler.NewTaskHandler(taskUsecase, logger),
+		nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil,
+		authMiddleware,
+		nil,
+		middleware.NewAdminMiddleware(nil, logger),
+		noLimit,
+		noLimit,
+		nil,
+		middleware.NewClientIPMiddleware(0),
+		nil,
+		logger,
+	)
+	f.handler = r.Setup()
+	return f
+}
+
+func (f *routerFixture) serve(t *testing.T, method, path string) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest(method, path, nil)
+	req.Header.Set("Authorization", "Bearer "+testAccessToken)
+	rec := httptest.NewRecorder()
+	f.handler.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestRouterProjectPathValue(t *testing.T) {
+	f := newRouterFixture(t)
+
+	rec := f.serve(t, http.MethodGet, "/api/v1/projects/"+testProjectID)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("GET /api/v1/projects/{id}: status = %d, body = %s", rec.Code, rec.Body.String())
+	}
+	if len(f.projects.ids) == 0 || slices.ContainsFunc(f.projects.ids, func(id string) bool { return id != testProjectID }) {
+		t.Errorf("project ids passed to repository = %v, want only %s", f.projects.ids, testProjectID)
+	}
+}
+
+func TestRouterTaskPathValue(t *testing.T) {
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		status int
+	}{
+		{name: "get", method: http.MethodGet, path: "/api/v1/tasks/" + testTaskID, status: http.StatusOK},
+		{name: "subtasks", method: http.MethodGet, path: "/api/v1/tasks/" + testTaskID + "/subtasks", status: http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newRouterFixture(t)
+
+			rec := f.serve(t, tt.method, tt.path)
+			if rec.Code != tt.status {
+				t.Fatalf("%s %s: status = %d, want %d, body = %s", tt.method, tt.path, rec.Code, tt.status, rec.Body.String())
+			}
+			if len(f.tasks.ids) == 0 || slices.ContainsFunc(f.tasks.ids, func(id string) bool { return id != testTaskID }) {
+				t.Errorf("task ids passed to repository = %v, want only %s", f.tasks.ids, testTaskID)
+			}
+		})
+	}
+}
+
+func TestRouterTaskDependencyPathValues(t *testing.T) {
+	f := newRouterFixture(t)
+
+	rec := f.serve(t, http.MethodDelete, "/api/v1/tasks/"+testTaskID+"/dependencies/"+testDependsOnID)
+	if rec.Code != http.StatusNoContent {
+		t.Fatalf("DELETE /api/v1/tasks/{id}/dependencies/{dependsOnId}: status = %d, body = %s", rec.Code, rec.Body.String())
+	}
+	want := [][2]string{{testTaskID, testDependsOnID}}
+	if !slices.Equal(f.deps.deleted, want) {
+		t.Errorf("deleted dependencies = %v, want %v", f.deps.deleted, want)
+	}
+}
+
+func TestRouterUnknownPathValueIsNotFound(t *testing.T) {
+	f := newRouterFixture(t)
+
+	// UUIDでないIDはリポジトリを呼び出さずに404を返す
+	rec := f.serve(t, http.MethodGet, "/api/v1/projects/not-a-uuid")
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("GET /api/v1/projects/not-a-uuid: status = %d, want %d", rec.Code, http.StatusNotFound)
+	}
+	if len(f.projects.ids) != 0 {
+		t.Errorf("project ids passed to repository = %v, want none", f.projects.ids)
+	}
+}
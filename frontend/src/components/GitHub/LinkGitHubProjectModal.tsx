@@ -21,11 +21,12 @@ export function LinkGitHubProjectModal({ projectId, isOpen, onClose, onLinked }:
     }
   }, [isOpen]);
 
-  const fetchGithubProjects = async () => {
+  const fetchGithubProjects = async (projectOwner?: string) => {
     setIsLoading(true);
     setError(null);
+    setSelectedProject(null);
     try {
-      const projects = await githubApi.listProjects();
+      const projects = await githubApi.listProjects(projectOwner);
       setGithubProjects(projects || []);
     } catch (err) {
       setError("GitHub Projectsの取得に失敗しました。PATが設定されているか確認してください。");
@@ -66,14 +67,26 @@ export function LinkGitHubProjectModal({ projectId, isOpen, onClose, onLinked }:
 
         <div className="space-y-4">
           <div>
-            <label className="block text-sm font-medium mb-1">GitHubユーザー名</label>
-            <input
-              type="text"
-              value={owner}
-              onChange={(e) => setOwner(e.target.value)}
-              placeholder="your-username"
-              className="w-full px-3 py-2 border rounded dark:bg-gray-700 dark:border-gray-600"
-            />
+            <label className="block text-sm font-medium mb-1">GitHubユーザー名・Organization名</label>
+            <div className="flex gap-2">
+              <input
+                type="text"
+                value={owner}
+                onChange={(e) => setOwner(e.target.value)}
+                placeholder="your-username"
+                className="w-full px-3 py-2 border rounded dark:bg-gray-700 dark:border-gray-600"
+              />
+              <button
+                onClick={() => fetchGithubProjects(owner.trim() || undefined)}
+                disabled={isLoading}
+                className="px-3 py-2 text-sm border rounded hover:bg-gray-100 disabled:opacity-50 dark:border-gray-600 dark:hover:bg-gray-700"
+              >
+                検索
+              </button>
+            </div>
+            <p className="mt-1 text-xs text-gray-500">
+              一覧にないOrganizationのProjectは、Organization名を入力して検索してください。
+            </p>
           </div>
 
           <div>
@@ -90,13 +103,16 @@ export function LinkGitHubProjectModal({ projectId, isOpen, onClose, onLinked }:
                 onChange={(e) => {
                   const proj = githubProjects.find(p => p.id === e.target.value);
                   setSelectedProject(proj || null);
+                  if (proj) {
+                    setOwner(proj.owner);
+                  }
                 }}
                 className="w-full px-3 py-2 border rounded dark:bg-gray-700 dark:border-gray-600"
               >
                 <option value="">選択してください</option>
                 {githubProjects.map((proj) => (
                   <option key={proj.id} value={proj.id}>
-                    #{proj.number} - {proj.title}
+                    {proj.owner} #{proj.number} - {proj.title}
                   </option>
                 ))}
               </select>
@@ -45,7 +45,7 @@ export function ProjectProvider({ children }: { children: ReactNode }) {
     setIsLoading(true);
     setError(null);
     try {
-      const data = await projectApi.list(user.id);
+      const data = await projectApi.list();
       setProjects(data.map(mapApiProject));
     } catch (err) {
       setError("Failed to fetch projects");
@@ -70,7 +70,6 @@ export function ProjectProvider({ children }: { children: ReactNode }) {
 
       try {
         const created = await projectApi.create({
-          user_id: user.id,
           title: data.title,
           description: data.description,
         });
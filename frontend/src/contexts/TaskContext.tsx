@@ -7,34 +7,34 @@ import {
   useEffect,
   type ReactNode,
 } from 'react';
-import { taskApi, type Task as ApiTask } from '@/lib/api';
+import { taskApi, type Task as ApiTask, type ApiTaskStatus, type ApiTaskPriority } from '@/lib/api';
 import { useProjects } from './ProjectContext';
 import type { Task, TaskStatus, TaskFormData, Priority } from '@/types';
 
 // APIのステータス値とフロントエンドのステータス文字列のマッピング
-const statusToNumber: Record<TaskStatus, number> = {
-  'To Do': 0,
-  'In Progress': 1,
-  Done: 2,
+const statusToApi: Record<TaskStatus, ApiTaskStatus> = {
+  'To Do': 'todo',
+  'In Progress': 'in_progress',
+  Done: 'done',
 };
 
-const numberToStatus: Record<number, TaskStatus> = {
-  0: 'To Do',
-  1: 'In Progress',
-  2: 'Done',
+const apiToStatus: Record<ApiTaskStatus, TaskStatus> = {
+  todo: 'To Do',
+  in_progress: 'In Progress',
+  done: 'Done',
 };
 
 // APIのpriority値とフロントエンドのpriority文字列のマッピング
-const priorityToNumber: Record<Priority, number> = {
-  Low: 0,
-  Medium: 1,
-  High: 2,
+const priorityToApi: Record<Priority, ApiTaskPriority> = {
+  Low: 'low',
+  Medium: 'medium',
+  High: 'high',
 };
 
-const numberToPriority: Record<number, Priority> = {
-  0: 'Low',
-  1: 'Medium',
-  2: 'High',
+const apiToPriority: Record<ApiTaskPriority, Priority> = {
+  low: 'Low',
+  medium: 'Medium',
+  high: 'High',
 };
 
 // APIレスポンスをフロントエンド用に変換
@@ -42,8 +42,8 @@ const convertApiTask = (apiTask: ApiTask, projectName: string): Task => ({
   id: apiTask.id,
   title: apiTask.title,
   description: apiTask.description || '',
-  status: numberToStatus[apiTask.status] || 'To Do',
-  priority: numberToPriority[apiTask.priority] || 'Medium',
+  status: apiToStatus[apiTask.status] || 'To Do',
+  priority: apiToPriority[apiTask.priority] || 'Medium',
   project: projectName,
   assignee: 'Me',
   due: apiTask.end_date ? apiTask.end_date.split('T')[0] : '',
@@ -121,8 +121,8 @@ export function TaskProvider({ children }: { children: ReactNode }) {
         project_id: projectId,
         title: data.title,
         description: data.description,
-        status: statusToNumber[status],
-        priority: priorityToNumber[data.priority],
+        status: statusToApi[status],
+        priority: priorityToApi[data.priority],
         end_date: data.due || undefined,
       });
 
@@ -140,9 +140,10 @@ export function TaskProvider({ children }: { children: ReactNode }) {
       await taskApi.update(id, {
         title: task.title,
         description: task.description || '',
-        status: statusToNumber[status],
-        priority: priorityToNumber[task.priority],
+        status: statusToApi[status],
+        priority: priorityToApi[task.priority],
         end_date: task.due || undefined,
+        reopen: task.status === "Done" && status !== "Done",
       });
 
       setTasks((prev) => prev.map((t) => (t.id === id ? { ...t, status } : t)));
@@ -164,9 +165,10 @@ export function TaskProvider({ children }: { children: ReactNode }) {
       await taskApi.update(id, {
         title: newTitle,
         description: newDescription || '',
-        status: statusToNumber[newStatus],
-        priority: priorityToNumber[newPriority],
+        status: statusToApi[newStatus],
+        priority: priorityToApi[newPriority],
         end_date: newDue || undefined,
+        reopen: task.status === "Done" && newStatus !== "Done",
       });
 
       setTasks((prev) => prev.map((t) => (t.id === id ? { ...t, ...updates } : t)));
@@ -26,7 +26,6 @@ export interface Project {
 }
 
 export interface CreateProjectRequest {
-  user_id: string;
   title: string;
   description: string;
 }
@@ -74,13 +73,16 @@ export const authApi = {
   },
 };
 
+export type ApiTaskStatus = "todo" | "in_progress" | "done";
+export type ApiTaskPriority = "low" | "medium" | "high";
+
 export interface Task {
   id: string;
   project_id: string;
   title: string;
   description: string;
-  status: number; // 0: To Do, 1: In Progress, 2: Done
-  priority: number; // 0: Low, 1: Medium, 2: High
+  status: ApiTaskStatus;
+  priority: ApiTaskPriority;
   end_date?: string;
   created_at: string;
   updated_at: string;
@@ -90,17 +92,19 @@ export interface CreateTaskRequest {
   project_id: string;
   title: string;
   description: string;
-  status: number;
-  priority: number;
+  status: ApiTaskStatus;
+  priority: ApiTaskPriority;
   end_date?: string;
 }
 
 export interface UpdateTaskRequest {
   title: string;
   description: string;
-  status: number;
-  priority: number;
+  status: ApiTaskStatus;
+  priority: ApiTaskPriority;
   end_date?: string;
+  // 完了済みタスクを未完了に戻す場合に指定する
+  reopen?: boolean;
 }
 
 export const taskApi = {
@@ -171,14 +175,11 @@ export const taskApi = {
 };
 
 export const projectApi = {
-  list: async (userId: string): Promise<Project[]> => {
-    const response = await fetch(
-      `${API_BASE_URL}/api/v1/projects?user_id=${userId}`,
-      {
-        method: "GET",
-        credentials: "include",
-      }
-    );
+  list: async (): Promise<Project[]> => {
+    const response = await fetch(`${API_BASE_URL}/api/v1/projects`, {
+      method: "GET",
+      credentials: "include",
+    });
     if (!response.ok) {
       throw new Error("Failed to fetch projects");
     }
@@ -244,6 +245,8 @@ export interface GithubProject {
   id: string;
   number: number;
   title: string;
+  owner: string;
+  owner_type: "user" | "org";
 }
 
 export interface LinkProjectRequest {
@@ -286,8 +289,10 @@ export const githubApi = {
     }
   },
 
-  listProjects: async (): Promise<GithubProject[]> => {
-    const response = await fetch(`${API_BASE_URL}/api/v1/github/projects`, {
+  // ownerを省略するとユーザー自身と所属するOrganizationのProjectsを返す
+  listProjects: async (owner?: string): Promise<GithubProject[]> => {
+    const query = owner ? `?owner=${encodeURIComponent(owner)}` : "";
+    const response = await fetch(`${API_BASE_URL}/api/v1/github/projects${query}`, {
       method: "GET",
       credentials: "include",
     });